@@ -5,6 +5,7 @@ package yoloai
 
 import (
 	"context"
+	"io"
 	"time"
 
 	"github.com/kstenerud/yoloai/internal/orchestrator"
@@ -65,6 +66,15 @@ func (a *Agent) TerminalLog(tailLines int) (string, error) {
 	return orchestrator.ReadAgentLog(a.engine.Layout(), a.name, tailLines)
 }
 
+// TerminalRecording opens the agent's terminal recording: the TerminalLog
+// stream with its timing, in asciicast v2 format (what `yoloai replay` and
+// asciinema play). The caller closes it. A sandbox with no recording returns
+// (nil, nil). This is a host-filesystem read and does not require a running
+// backend.
+func (a *Agent) TerminalRecording() (io.ReadCloser, error) {
+	return orchestrator.OpenAgentRecording(a.engine.Layout(), a.name)
+}
+
 // LogEvent is one structured-log line surfaced by Logs: the verbatim JSONL byte
 // slice (Raw) plus the two fields the library parsed to order and filter the
 // stream (Time, Level). Raw is the canonical payload — yoloAI does not decompose
@@ -85,7 +95,8 @@ type LogEvent struct {
 
 // AgentLogsOptions selects and filters the LogEvents that Logs emits.
 type AgentLogsOptions struct {
-	// Sources limits the streamed sources; empty (nil) means all of them.
+	// Sources limits the streamed sources; empty (nil) means the four on-disk
+	// sources. LogSourceRuntime is included only when named here.
 	Sources []LogSource
 	// MinLevel drops events below this level ("debug" < "info" < "warn" <
 	// "error"). Empty means no level filter. An unknown value returns a
@@ -117,10 +128,11 @@ func (o AgentLogsOptions) toInternal() orchestrator.LogStreamOptions {
 // stream ends, so a plain range over it terminates cleanly.
 //
 // This is a host-filesystem read: no backend connection is required, matching
-// TerminalLog. Cancel ctx to stop a Follow stream early. A missing sandbox returns
+// TerminalLog — unless Sources names LogSourceRuntime, which opens the backend
+// best-effort and is empty on backends that keep no runtime log. Cancel ctx to stop a Follow stream early. A missing sandbox returns
 // ErrSandboxNotFound; an invalid MinLevel returns a *UsageError.
 func (a *Agent) Logs(ctx context.Context, opts AgentLogsOptions) (<-chan LogEvent, error) {
-	frames, err := orchestrator.StreamLogs(ctx, a.engine.Layout(), a.name, opts.toInternal())
+	frames, err := a.engine.StreamLogs(ctx, a.name, opts.toInternal())
 	if err != nil {
 		return nil, err
 	}
@@ -166,8 +178,10 @@ func (a *Agent) CaptureTerminal(ctx context.Context, scrollback int) (TerminalSn
 	return TerminalSnapshot{Plain: plain, ANSI: ansi}, err
 }
 
-// SendInput appends text to the running sandbox's tmux session as if the user
-// typed it. Returns ErrContainerNotRunning when the sandbox is stopped.
+// SendInput submits text to the running agent as a follow-up prompt, pasted
+// into its tmux session and submitted with the agent's own key sequence, as
+// if the user typed it and pressed Enter. Returns ErrContainerNotRunning when
+// the sandbox is stopped and a *UsageError when the agent runs headless.
 func (a *Agent) SendInput(ctx context.Context, text string) error {
 	return a.engine.SendInput(ctx, a.name, text)
 }
@@ -180,13 +194,79 @@ func (a *Agent) ContainerLogs(ctx context.Context, tailLines int) string {
 	return a.engine.ContainerLogs(ctx, a.name, tailLines)
 }
 
+// AgentUpgradeOptions configures Agent.Upgrade.
+type AgentUpgradeOptions struct {
+	// Version is the npm version or dist-tag to install; "" means "latest".
+	Version string
+}
+
+// Upgrade reinstalls the agent CLI at opts.Version inside the running sandbox
+// and relaunches it, continuing its conversation when the agent supports
+// resuming. The old and new versions are recorded in the sandbox's agent.json.
+// The install lives in the container, so a recreate reverts to the image's
+// version. Returns a *UsageError when the sandbox is stopped, the agent isn't
+// npm-installed, or the backend runs the host's agent.
+func (a *Agent) Upgrade(ctx context.Context, opts AgentUpgradeOptions) (*AgentUpgradeResult, error) {
+	return a.engine.UpgradeAgent(ctx, orchestrator.UpgradeOptions{Name: a.name, Version: opts.Version})
+}
+
+// AgentCleanStateOptions configures Agent.CleanState.
+type AgentCleanStateOptions struct {
+	// MaxSize trims the agent's prunable state, oldest first, until its state
+	// directory is at most this many bytes. 0 removes all of it.
+	MaxSize int64
+	// DryRun reports what would be removed without removing it.
+	DryRun bool
+}
+
+// CleanState deletes what the agent lists as prunable in its state directory
+// — session transcripts, caches, logs — to reclaim space. Removing the
+// transcripts means a resume starts a fresh conversation. Returns a
+// *UsageError when the agent is running or paused.
+func (a *Agent) CleanState(ctx context.Context, opts AgentCleanStateOptions) (*AgentCleanStateResult, error) {
+	return a.engine.CleanAgentState(ctx, orchestrator.CleanStateOptions{Name: a.name, MaxSize: opts.MaxSize, DryRun: opts.DryRun})
+}
+
 // Attach connects the supplied IOStreams to the sandbox's tmux session.
 // Blocks until the user detaches (Ctrl-B d) or the agent exits. The sandbox
 // must be running (Active/Idle/Done/Failed); for stopped sandboxes call Start
 // first. io.TTY=true is required; non-TTY attach returns a *UsageError.
 func (a *Agent) Attach(ctx context.Context, io IOStreams) error {
+	return a.AttachWith(ctx, io, AgentAttachOptions{})
+}
+
+// AttachReadOnly is Attach for a watcher: the session is shown but keystrokes
+// don't reach the agent, so it can't interleave with another attached terminal.
+func (a *Agent) AttachReadOnly(ctx context.Context, io IOStreams) error {
+	return a.AttachWith(ctx, io, AgentAttachOptions{ReadOnly: true})
+}
+
+// ShellWindow is the session window holding a plain shell in the workdir,
+// beside the agent's. Attaching to it reopens it if its shell was exited, and
+// leaves the agent's window current again on detach.
+const ShellWindow = orchestrator.ShellWindow
+
+// AgentAttachOptions configures Agent.AttachWith.
+type AgentAttachOptions struct {
+	// ReadOnly attaches as a watcher, as AttachReadOnly does.
+	ReadOnly bool
+	// Window shows one window of the session: a split-role sandbox's role
+	// (SandboxCreateOptions.Roles), ShellWindow, or "vscode-tunnel". "" = the
+	// current window. A window the sandbox doesn't have is a *UsageError.
+	Window string
+}
+
+// AttachWith is Attach with options.
+func (a *Agent) AttachWith(ctx context.Context, io IOStreams, opts AgentAttachOptions) error {
 	if !io.TTY {
 		return yoerrors.NewUsageError("attach requires TTY=true")
 	}
-	return execExitError(a.engine.Attach(ctx, a.name, io))
+	return execExitError(a.engine.Attach(ctx, a.name, orchestrator.AttachOptions{ReadOnly: opts.ReadOnly, Window: opts.Window}, io))
+}
+
+// AttachedClients lists the terminals attached to the agent's session right
+// now; empty when nobody is attached or the sandbox isn't running. Check it
+// before Attach to avoid two terminals typing into the same agent.
+func (a *Agent) AttachedClients(ctx context.Context) ([]AttachedClient, error) {
+	return a.engine.AttachedClients(ctx, a.name)
 }
@@ -0,0 +1,55 @@
+// ABOUTME: Artifacts is the host-side handle for a sandbox's outbox/ — the
+// ABOUTME: files the agent hands back — listing and collecting them to the host.
+package yoloai
+
+import (
+	"context"
+	"time"
+
+	"github.com/kstenerud/yoloai/internal/orchestrator"
+)
+
+// Artifacts is a name-scoped handle for a sandbox's artifact outbox
+// (~/.yoloai/sandboxes/<name>/outbox, mounted at /yoloai/outbox). The agent is
+// told to leave non-code deliverables there — reports, generated assets — so
+// they reach the user without passing through diff/apply. Like Files, it is
+// pure host filesystem work and needs no running backend.
+type Artifacts struct {
+	engine *orchestrator.Engine
+	name   string
+}
+
+// Artifact is one file in the outbox.
+type Artifact struct {
+	Path    string    // relative to the outbox, slash-separated
+	Size    int64     // bytes
+	ModTime time.Time // last modification
+}
+
+// Path returns the host path of the outbox. The directory may not exist for
+// sandboxes created before the outbox was introduced until they next start.
+func (a *Artifacts) Path() string {
+	return orchestrator.OutboxDir(a.engine.Layout(), a.name)
+}
+
+// List returns every regular file in the outbox, sorted by path. Symlinks are
+// skipped. An empty or missing outbox is not an error.
+func (a *Artifacts) List() ([]Artifact, error) {
+	items, err := a.engine.ListArtifacts(a.name)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]Artifact, len(items))
+	for i, it := range items {
+		out[i] = Artifact{Path: it.Path, Size: it.Size, ModTime: it.ModTime}
+	}
+	return out, nil
+}
+
+// Collect copies the artifacts matching patterns (all of them when patterns is
+// empty) into destDir, keeping their outbox-relative paths. A pattern matches
+// an artifact's relative path or its base name. Without force, an existing
+// destination file is an error. Returns the relative paths copied.
+func (a *Artifacts) Collect(ctx context.Context, destDir string, patterns []string, force bool) ([]string, error) {
+	return a.engine.CollectArtifacts(ctx, a.name, destDir, patterns, force)
+}
@@ -123,6 +123,7 @@ func NewClient(ctx context.Context, opts ClientCreateOptions) (*Client, error) {
 
 	layout := config.NewLayoutFor(opts.DataDir, opts.HomeDir).WithPrincipal(principal).WithEnv(opts.Env)
 	layout.SecretsStagingDir = opts.SecretsStagingDir
+	layout.SystemConfigDir = opts.SystemConfigDir
 
 	logger := opts.Logger
 	if logger == nil {
@@ -208,7 +209,7 @@ func (c *Client) Sandbox(name string) (*Sandbox, error) {
 // System returns the admin sub-handle for system-level operations.
 // Always non-nil; never errors. See System for the surface.
 func (c *Client) System() *System {
-	return &System{layout: c.layout}
+	return &System{layout: c.layout, logger: c.engine.Logger()}
 }
 
 // ListSandboxes returns info for all sandboxes.
@@ -117,4 +117,12 @@ type ClientCreateOptions struct {
 	// principal's Client at that principal's own tmpfs so plaintext
 	// credentials never share a staging root across principals.
 	SecretsStagingDir string
+
+	// SystemConfigDir is the machine-wide config directory a platform team
+	// manages. Its config.yaml (and any org config `config pull` cached from
+	// org_config_url) is layered beneath the user's config under DataDir, and
+	// its profiles/ are usable alongside the user's. Optional; empty ("")
+	// means no system layer. The CLI passes /etc/yoloai, or
+	// $YOLOAI_SYSTEM_CONFIG_DIR when set.
+	SystemConfigDir string
 }
@@ -5,6 +5,7 @@
 package copyflow
 
 import (
+	"cmp"
 	"context"
 	"errors"
 	"fmt"
@@ -12,7 +13,6 @@ import (
 	"os/exec"
 	"path/filepath"
 	"strings"
-	"time"
 
 	"github.com/kstenerud/yoloai/internal/config"
 	"github.com/kstenerud/yoloai/internal/fileutil"
@@ -35,13 +35,30 @@ type AppliedCommit struct {
 type ApplyResult struct {
 	// Dir is the host directory that was patched.
 	Dir string
-	// Stat is the human-readable diff stat summary (net-diff / NoCommit applies).
+	// Stat is the human-readable diff stat summary (net-diff / NoCommit
+	// applies). Empty when SelectHunks applied only part of the patch.
 	Stat string
 	// Commits are the commits replayed, in order (series applies); empty for a
 	// NoCommit/net-diff apply. On a DryRun preview, HostSHA is empty.
 	Commits []AppliedCommit
 	// UncommittedApplied is true when uncommitted edits were also applied as unstaged changes.
 	UncommittedApplied bool
+	// Clone describes the fresh clone the changes landed in; nil for an apply
+	// to the original host directory.
+	Clone *FreshClone
+	// PushedBranch is the origin branch the landed commits were pushed to; ""
+	// when the apply didn't push.
+	PushedBranch string
+	// Branch is the new host branch the changes were committed to; "" for an
+	// apply to the host's working tree.
+	Branch string
+	// Rejects are the .rej files a Reject apply left beside the files whose
+	// changes didn't all fit, as absolute paths; the baseline was not
+	// advanced. Empty when everything applied.
+	Rejects []string
+	// ChangelogFragment is the changelog fragment written into Dir for the
+	// change, as an absolute path; "" when none was asked for.
+	ChangelogFragment string
 }
 
 // ApplyAllOptions configures ApplyAll.
@@ -50,6 +67,33 @@ type ApplyAllOptions struct {
 	Paths              []string // optional path filter; when non-empty the baseline is NOT advanced
 	DryRun             bool     // generate + validate but do not apply or advance baseline
 	DirHostPath        string   // "" selects Dirs[0] (workdir)
+	// Provenance, when set, stamps a provenance header into each file the
+	// patch creates and keeps edits to already-stamped files applying.
+	Provenance *Provenance
+	// FreshClone, when set, clones the source repo's origin into this
+	// directory at the baseline and applies there, leaving the original host
+	// directory and the baseline untouched. Not combinable with DryRun.
+	FreshClone string
+	// Branch, when set, creates this branch in the host repository and lands
+	// the changes there as one commit, through a throwaway worktree; the
+	// user's checkout is left alone. Not combinable with DryRun or FreshClone.
+	Branch string
+	// Target, when set, applies into this existing directory — another clone
+	// or worktree — instead of the original host directory, which is left
+	// alone along with the baseline. Not combinable with FreshClone or Branch.
+	Target string
+	// SelectHunks, when set, is handed the generated patch split into files
+	// and hunks, and returns the subset (possibly edited) to apply — the hook
+	// behind an interactive apply. It runs with the sandbox lock held. The
+	// baseline advances only when the selection is the whole patch unchanged.
+	// Not combinable with DryRun.
+	SelectHunks func([]PatchFile) ([]PatchFile, error)
+	// Reject, when the patch doesn't apply cleanly, applies the hunks that
+	// do and leaves the rest in .rej files beside their targets (see
+	// ApplyResult.Rejects) instead of failing with *ApplyConflictError. The
+	// baseline then stays put until the caller advances it. Not combinable
+	// with DryRun or Branch.
+	Reject bool
 }
 
 // ApplyAll applies the sandbox's pending workdir changes back to the original
@@ -82,6 +126,28 @@ func ApplyAll(ctx context.Context, layout config.Layout, rt runtime.Backend, nam
 		// :rw is live, so it never funnels through this squash apply path.
 		return nil, nil
 	}
+	if opts.FreshClone != "" && opts.DryRun {
+		return nil, yoerrors.NewUsageError("a fresh-clone apply can't be a dry run")
+	}
+	if err := checkBranchOptions(opts.Branch, opts.DryRun, opts.FreshClone, ""); err != nil {
+		return nil, err
+	}
+	if opts.SelectHunks != nil && opts.DryRun {
+		return nil, yoerrors.NewUsageError("a hunk-selecting apply can't be a dry run")
+	}
+	if opts.Reject && (opts.DryRun || opts.Branch != "") {
+		return nil, yoerrors.NewUsageError("an apply that leaves .rej files can't be a dry run or go to a branch")
+	}
+	target, err := resolveApplyTarget(dir, opts.Target, opts.FreshClone, opts.Branch)
+	if err != nil {
+		return nil, err
+	}
+	hostGit := git.NewHost(layout)
+	if opts.FreshClone == "" && target == "" {
+		if err := CheckSourceIdentity(ctx, hostGit, name, dir); err != nil {
+			return nil, err
+		}
+	}
 
 	patchBytes, stat, err := GeneratePatch(ctx, layout, rt, name, opts.DirHostPath, opts.Paths, opts.IncludeUncommitted)
 	if err != nil {
@@ -90,30 +156,128 @@ func ApplyAll(ctx context.Context, layout config.Layout, rt runtime.Backend, nam
 	if len(strings.TrimSpace(string(patchBytes))) == 0 {
 		return nil, nil
 	}
+	partial := false
+	if opts.SelectHunks != nil {
+		selected, err := selectHunks(patchBytes, opts.SelectHunks)
+		if err != nil {
+			return nil, err
+		}
+		if len(strings.TrimSpace(string(selected))) == 0 {
+			return nil, nil
+		}
+		// A partial selection leaves the declined hunks pending, so the
+		// baseline mustn't move past them; its stat no longer matches either.
+		partial = string(selected) != string(patchBytes)
+		if partial {
+			stat = ""
+		}
+		patchBytes = selected
+	}
 
 	hostPath := dir.HostPath
+	if target != "" {
+		hostPath = target
+	}
+	var clone *FreshClone
+	if opts.FreshClone != "" {
+		if clone, err = prepareFreshClone(ctx, hostGit, dir, opts.FreshClone); err != nil {
+			return nil, err
+		}
+		hostPath = clone.Dir
+	}
+	var wt *branchWorktree
+	keepBranch := false // set once the changes are committed on it
+	if opts.Branch != "" {
+		if wt, err = prepareBranchWorktree(ctx, hostGit, dir, opts.Branch); err != nil {
+			return nil, err
+		}
+		defer func() { wt.close(ctx, keepBranch) }()
+		hostPath = wt.dir
+	}
+	if opts.Provenance != nil {
+		patchBytes = newStamper(*opts.Provenance, hostPath).stamp(patchBytes)
+	}
 	isGit := git.IsGitRepo(hostPath)
-	hostGit := git.NewHost(layout)
-	if err := hostGit.CheckPatch(ctx, patchBytes, hostPath, isGit); err != nil {
-		return nil, err
+	checkErr := hostGit.CheckPatch(ctx, patchBytes, hostPath, isGit)
+	if checkErr != nil && !opts.Reject {
+		return nil, &ApplyConflictError{Dir: hostPath, Err: checkErr}
 	}
 	if opts.DryRun {
 		return &ApplyResult{Dir: hostPath, Stat: stat}, nil
 	}
 
-	if err := hostGit.ApplyPatch(ctx, patchBytes, hostPath, isGit); err != nil {
+	if checkErr != nil {
+		rejects, err := applyWithRejects(ctx, hostGit, patchBytes, hostPath, isGit)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", hostPath, err)
+		}
+		if len(rejects) > 0 {
+			return &ApplyResult{Dir: hostPath, Stat: stat, Clone: clone, Rejects: rejects}, nil
+		}
+		// Everything fit after all: the target changed since the check.
+	} else if err := hostGit.ApplyPatch(ctx, patchBytes, hostPath, isGit); err != nil {
 		return nil, fmt.Errorf("%s: %w", hostPath, err)
 	}
+	if wt != nil {
+		if err := wt.commitRemaining(ctx, "Apply changes from sandbox "+name); err != nil {
+			return nil, fmt.Errorf("commit to branch %s: %w", opts.Branch, err)
+		}
+		keepBranch = true
+		hostPath = dir.HostPath
+	}
 
 	// Path-filtered applies don't advance the baseline (the remaining
-	// unapplied paths still diff against it).
-	if len(opts.Paths) == 0 {
+	// unapplied paths still diff against it), and neither do a partial hunk
+	// selection, a fresh-clone apply or one to another target — the original
+	// host directory hasn't received all of the changes.
+	if len(opts.Paths) == 0 && !partial && clone == nil && target == "" {
 		if err := AdvanceBaseline(ctx, layout, rt, name, opts.DirHostPath); err != nil {
 			return nil, fmt.Errorf("advance baseline: %w", err)
 		}
 	}
 
-	return &ApplyResult{Dir: hostPath, Stat: stat}, nil
+	return &ApplyResult{Dir: hostPath, Stat: stat, Clone: clone, Branch: opts.Branch}, nil
+}
+
+// selectHunks runs the caller's hunk selection over patch and reassembles the
+// result.
+func selectHunks(patch []byte, sel func([]PatchFile) ([]PatchFile, error)) ([]byte, error) {
+	files, err := sel(ParsePatch(patch))
+	if err != nil {
+		return nil, err
+	}
+	return AssemblePatch(files)
+}
+
+// CheckSourceIdentity verifies that dir's host path is still the directory the
+// sandbox copied from, so apply never lands a patch on the wrong tree. It fails
+// with *UsageError when the path no longer exists (moved or deleted) or, for a
+// source recorded as a git repo, when the repo there no longer contains the
+// recorded root commit (a different repository now sits at that path). A
+// changed origin URL alone is not a mismatch — remotes get renamed and moved.
+// Sandboxes created before identity was recorded skip the repo check. The
+// message points at --patches, which exports the work without a target.
+func CheckSourceIdentity(ctx context.Context, hostGit *git.Git, name string, dir *store.DirEnvironment) error {
+	if _, err := os.Stat(dir.HostPath); err != nil {
+		return yoerrors.NewUsageError(
+			"original directory %s is gone (moved or deleted since sandbox %q was created); "+
+				"export the changes instead with: yoloai apply %s --patches <dir>",
+			dir.HostPath, name, name)
+	}
+	if dir.SourceRootSHA == "" {
+		return nil
+	}
+	if !git.IsGitRepo(dir.HostPath) || !hostGit.HasCommit(ctx, dir.HostPath, dir.SourceRootSHA) {
+		was := "the repository it was copied from"
+		if dir.SourceRemote != "" {
+			was = "the repository it was copied from (" + dir.SourceRemote + ")"
+		}
+		return yoerrors.NewUsageError(
+			"%s no longer holds %s — it was replaced or re-initialized since sandbox %q was created; "+
+				"export the changes instead with: yoloai apply %s --patches <dir>",
+			dir.HostPath, was, name, name)
+	}
+	return nil
 }
 
 // ApplySeriesOptions configures ApplySeries.
@@ -123,6 +287,24 @@ type ApplySeriesOptions struct {
 	Paths              []string // optional path filter; when non-empty the baseline is NOT advanced
 	DryRun             bool     // list the commits that would apply, without applying
 	DirHostPath        string   // "" selects Dirs[0] (workdir)
+	// Provenance, when set, stamps a provenance header into each file the
+	// series (or the uncommitted edits) creates and keeps edits to
+	// already-stamped files applying.
+	Provenance *Provenance
+	// FreshClone, as for ApplyAllOptions.
+	FreshClone string
+	// Branch, when set, creates this branch in the host repository and
+	// replays the series there (uncommitted edits, if included, follow as one
+	// more commit), through a throwaway worktree; the user's checkout is left
+	// alone. Not combinable with DryRun, FreshClone or PushBranch.
+	Branch string
+	// PushBranch, when set, pushes the target's HEAD to this branch of its
+	// origin once the series has landed, with the host's git credentials. The
+	// way a sandbox created from a remote repository (--repo) hands its work
+	// back; only committed changes travel. Ignored on DryRun.
+	PushBranch string
+	// Target, as for ApplyAllOptions; it must be a git repository.
+	Target string
 }
 
 // ApplySeries replays the sandbox's beyond-baseline commits onto the host
@@ -130,6 +312,11 @@ type ApplySeriesOptions struct {
 // commit's message/author — the normal apply flow (D26). With opts.Refs it
 // replays only that subset (selective apply) and advances the baseline across
 // the contiguous applied prefix; otherwise it replays all and advances to HEAD.
+// With opts.FreshClone the series lands in a new clone of the source's origin
+// (see prepareFreshClone) and the baseline stays put, as it does with
+// opts.Target, which lands it in another existing checkout. With opts.Branch it
+// lands on a new branch of the host repository (see prepareBranchWorktree);
+// the work has then reached the host, so the baseline advances as usual.
 //
 // Return contract (comply-or-complain, D27):
 //   - (nil, nil): nothing to apply (no beyond-baseline commits). Uncommitted-only
@@ -159,12 +346,28 @@ func ApplySeries(ctx context.Context, layout config.Layout, rt runtime.Backend,
 	if dir == nil || dir.Mode != "copy" {
 		return nil, nil
 	}
-
-	hostPath := dir.HostPath
-	if !git.IsGitRepo(hostPath) {
-		return nil, yoerrors.NewUsageError(
-			"cannot replay a commit series onto %s: not a git repository — apply with NoCommit to land the net changes instead",
-			hostPath)
+	if opts.FreshClone != "" && opts.DryRun {
+		return nil, yoerrors.NewUsageError("a fresh-clone apply can't be a dry run")
+	}
+	if err := checkBranchOptions(opts.Branch, opts.DryRun, opts.FreshClone, opts.PushBranch); err != nil {
+		return nil, err
+	}
+	if opts.Target, err = resolveApplyTarget(dir, opts.Target, opts.FreshClone, opts.Branch); err != nil {
+		return nil, err
+	}
+	hostGit := git.NewHost(layout)
+	if opts.FreshClone == "" {
+		if opts.Target == "" {
+			if err := CheckSourceIdentity(ctx, hostGit, name, dir); err != nil {
+				return nil, err
+			}
+		}
+		// (A Branch apply refuses a non-git target itself, in its own terms.)
+		if into := cmp.Or(opts.Target, dir.HostPath); !git.IsGitRepo(into) && opts.Branch == "" {
+			return nil, yoerrors.NewUsageError(
+				"cannot replay a commit series onto %s: not a git repository — apply with NoCommit to land the net changes instead",
+				into)
+		}
 	}
 
 	commits, err := resolveSeriesCommits(ctx, layout, rt, name, opts.DirHostPath, opts.Refs)
@@ -176,7 +379,7 @@ func ApplySeries(ctx context.Context, layout config.Layout, rt runtime.Backend,
 	}
 
 	if opts.DryRun {
-		return seriesResult(hostPath, commits, nil), nil
+		return seriesResult(cmp.Or(opts.Target, dir.HostPath), commits, nil), nil
 	}
 
 	patchDir, files, err := generateSeriesPatch(ctx, layout, rt, name, commits, opts)
@@ -189,24 +392,91 @@ func ApplySeries(ctx context.Context, layout config.Layout, rt runtime.Backend,
 		return nil, nil
 	}
 
-	hostGit := git.NewHost(layout)
+	hostPath := cmp.Or(opts.Target, dir.HostPath)
+	var clone *FreshClone
+	if opts.FreshClone != "" {
+		if clone, err = prepareFreshClone(ctx, hostGit, dir, opts.FreshClone); err != nil {
+			return nil, err
+		}
+		hostPath = clone.Dir
+	}
+	var wt *branchWorktree
+	keepBranch := false // set once the commits land on it
+	if opts.Branch != "" {
+		if wt, err = prepareBranchWorktree(ctx, hostGit, dir, opts.Branch); err != nil {
+			return nil, err
+		}
+		defer func() { wt.close(ctx, keepBranch) }()
+		hostPath = wt.dir
+	}
+	var st *stamper
+	if opts.Provenance != nil {
+		st = newStamper(*opts.Provenance, hostPath)
+		if err := stampPatchFiles(st, patchDir, files); err != nil {
+			return nil, err
+		}
+	}
+
 	shaMap, amErr := hostGit.ApplyFormatPatch(ctx, patchDir, files, hostPath)
 	if amErr != nil && shaMap == nil {
 		// git am failed outright — nothing applied.
 		return nil, amErr
 	}
 
-	return finishSeriesApply(ctx, layout, rt, name, hostPath, opts, hostGit, seriesResult(hostPath, commits, shaMap), amErr)
+	result := seriesResult(hostPath, commits, shaMap)
+	result.Clone = clone
+	if wt != nil {
+		// The branch stays even if a follow-on step fails: the commits are on it.
+		keepBranch = true
+		return finishBranchApply(ctx, layout, rt, name, wt, opts, hostGit, st, result, amErr)
+	}
+	result, err = finishSeriesApply(ctx, layout, rt, name, hostPath, opts, hostGit, st, result, amErr)
+	return pushSeries(ctx, hostGit, hostPath, opts.PushBranch, result, err)
+}
+
+// finishBranchApply is finishSeriesApply for a series replayed onto a new
+// branch: any uncommitted edits it applies are committed on top, since the
+// worktree holding them is about to go, and the result names the host
+// repository and the branch rather than the worktree.
+func finishBranchApply(ctx context.Context, layout config.Layout, rt runtime.Backend, name string, wt *branchWorktree, opts ApplySeriesOptions, hostGit *git.Git, st *stamper, result *ApplyResult, amErr error) (*ApplyResult, error) {
+	result, err := finishSeriesApply(ctx, layout, rt, name, wt.dir, opts, hostGit, st, result, amErr)
+	if result.UncommittedApplied {
+		if cerr := wt.commitRemaining(ctx, "Uncommitted changes from sandbox "+name); cerr != nil {
+			result.UncommittedApplied = false
+			err = errors.Join(err, fmt.Errorf("commit uncommitted changes to branch %s: %w", opts.Branch, cerr))
+		}
+	}
+	result.Dir = wt.repo
+	result.Branch = opts.Branch
+	return result, err
+}
+
+// pushSeries pushes hostPath's HEAD to branch on its origin after a series
+// apply. The commits landed whatever err (a follow-on issue) says, so they are
+// pushed regardless, and a push failure joins err under the same (*ApplyResult,
+// error) contract: the result still lists what landed locally.
+func pushSeries(ctx context.Context, hostGit *git.Git, hostPath, branch string, result *ApplyResult, err error) (*ApplyResult, error) {
+	if branch == "" || result == nil {
+		return result, err
+	}
+	if perr := hostGit.RunCmd(ctx, hostPath, "push", "--quiet", "origin", "HEAD:refs/heads/"+branch); perr != nil {
+		return result, errors.Join(err, fmt.Errorf("push to branch %s: %w", branch, perr))
+	}
+	result.PushedBranch = branch
+	return result, err
 }
 
 // finishSeriesApply advances the baseline (unless path-filtered), surfaces a git
 // am stash error (commits already landed), and applies uncommitted changes when
 // requested. amErr is the non-nil-but-non-fatal error from ApplyFormatPatch (a
-// stash it couldn't reapply); the commits in result did land.
-func finishSeriesApply(ctx context.Context, layout config.Layout, rt runtime.Backend, name, hostPath string, opts ApplySeriesOptions, hostGit *git.Git, result *ApplyResult, amErr error) (*ApplyResult, error) {
+// stash it couldn't reapply); the commits in result did land. st is the
+// series' provenance stamper, nil when provenance is off.
+func finishSeriesApply(ctx context.Context, layout config.Layout, rt runtime.Backend, name, hostPath string, opts ApplySeriesOptions, hostGit *git.Git, st *stamper, result *ApplyResult, amErr error) (*ApplyResult, error) {
 	// Advance the baseline past the applied commits (skip for path-filtered
-	// applies — the remaining paths still diff against it).
-	if len(opts.Paths) == 0 {
+	// applies — the remaining paths still diff against it — and for a fresh
+	// clone or another target, which leave the original host directory
+	// without them).
+	if len(opts.Paths) == 0 && result.Clone == nil && opts.Target == "" {
 		if err := advanceSeriesBaseline(ctx, layout, rt, name, opts.DirHostPath, opts.Refs, result.Commits); err != nil {
 			return result, fmt.Errorf("advance baseline: %w", err)
 		}
@@ -217,7 +487,7 @@ func finishSeriesApply(ctx context.Context, layout config.Layout, rt runtime.Bac
 		return result, amErr
 	}
 	if opts.IncludeUncommitted {
-		applied, err := applySeriesUncommitted(ctx, layout, rt, name, opts.DirHostPath, hostPath, hostGit, opts.Paths)
+		applied, err := applySeriesUncommitted(ctx, layout, rt, name, opts.DirHostPath, hostPath, hostGit, opts.Paths, st)
 		if err != nil {
 			return result, err
 		}
@@ -244,7 +514,7 @@ func seriesResult(hostPath string, commits []CommitInfo, shaMap map[string]strin
 // applySeriesUncommitted applies the agent's uncommitted edits as unstaged changes
 // after the commit series has landed. Errors are wrapped to make clear the
 // commits already applied (the caller surfaces them as a warning, not a hard failure).
-func applySeriesUncommitted(ctx context.Context, layout config.Layout, rt runtime.Backend, name string, dirHostPath string, hostPath string, hostGit *git.Git, paths []string) (bool, error) {
+func applySeriesUncommitted(ctx context.Context, layout config.Layout, rt runtime.Backend, name string, dirHostPath string, hostPath string, hostGit *git.Git, paths []string, st *stamper) (bool, error) {
 	uncommittedPatch, _, err := GenerateUncommittedDiff(ctx, layout, rt, name, dirHostPath, paths)
 	if err != nil {
 		return false, fmt.Errorf("generate uncommitted diff (commits already applied): %w", err)
@@ -252,6 +522,9 @@ func applySeriesUncommitted(ctx context.Context, layout config.Layout, rt runtim
 	if len(uncommittedPatch) == 0 {
 		return false, nil
 	}
+	if st != nil {
+		uncommittedPatch = st.stamp(uncommittedPatch)
+	}
 	if err := hostGit.ApplyPatch(ctx, uncommittedPatch, hostPath, true); err != nil {
 		return false, fmt.Errorf("apply uncommitted changes (commits already applied): %w", err)
 	}
@@ -333,7 +606,7 @@ func GeneratePatch(ctx context.Context, layout config.Layout, rt runtime.Backend
 	// includes them.
 	endpoint := "HEAD"
 	if includeUncommitted {
-		if addErr := gitAddRetry(ctx, g, workDir); addErr != nil {
+		if addErr := g.StageUntracked(ctx, workDir, paths...); addErr != nil {
 			return nil, "", fmt.Errorf("git add: %w", addErr)
 		}
 		endpoint = ""
@@ -420,7 +693,7 @@ func HasUncommittedChanges(ctx context.Context, layout config.Layout, rt runtime
 	}
 
 	// Stage untracked files — retry on index.lock contention from agent activity.
-	if err = gitAddRetry(ctx, g, workDir); err != nil {
+	if err = g.StageUntracked(ctx, workDir); err != nil {
 		return false, fmt.Errorf("git add: %w", err)
 	}
 
@@ -684,8 +957,7 @@ func GenerateUncommittedDiff(ctx context.Context, layout config.Layout, rt runti
 	}
 
 	// Stage untracked files
-	_, err = g.Run(ctx, workDir, "add", "-A")
-	if err != nil {
+	if err = g.StageUntracked(ctx, workDir, paths...); err != nil {
 		return nil, "", fmt.Errorf("git add: %w", err)
 	}
 
@@ -719,22 +991,3 @@ func GenerateUncommittedDiff(ctx context.Context, layout config.Layout, rt runti
 
 	return []byte(patchOut), strings.TrimRight(statOut, "\n"), nil
 }
-
-// gitAddRetry runs `git add -A` via the runtime, retrying on index.lock
-// contention. The agent may hold the lock briefly for status bar updates
-// while sharing the work dir via a bind mount.
-func gitAddRetry(ctx context.Context, g *git.Git, workDir string) error {
-	var err error
-	for range 5 {
-		_, err = g.Run(ctx, workDir, "add", "-A")
-		if err == nil || !git.IsIndexLocked(err) {
-			return err
-		}
-		select {
-		case <-ctx.Done():
-			return ctx.Err()
-		case <-time.After(100 * time.Millisecond):
-		}
-	}
-	return err
-}
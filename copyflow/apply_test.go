@@ -1,5 +1,5 @@
 // ABOUTME: Unit tests for patch generation, baseline advancement, format-patch, selective apply,
-// ABOUTME: uncommitted diff, and commit ref resolution in copyflow.
+// ABOUTME: uncommitted diff, commit ref resolution, and the source-identity check in copyflow.
 
 package copyflow
 
@@ -1525,3 +1525,39 @@ func TestApplySeries_DryRunDoesNotApply(t *testing.T) {
 	require.NoError(t, err)
 	assert.Len(t, remaining, 3)
 }
+
+// CheckSourceIdentity tests
+
+func TestCheckSourceIdentity(t *testing.T) {
+	g := git.NewTestHostWithEnv(testEnv())
+	src := t.TempDir()
+	initGitRepo(t, src)
+	writeTestFile(t, src, "a.txt", "a")
+	gitAdd(t, src, ".")
+	gitCommit(t, src, "first")
+	root, err := g.RootCommit(context.Background(), src)
+	require.NoError(t, err)
+
+	dir := &store.DirEnvironment{HostPath: src, Mode: store.DirModeCopy, SourceRootSHA: root, SourceRemote: "https://example.com/repo.git"}
+	assert.NoError(t, CheckSourceIdentity(context.Background(), g, "box", dir), "same repo passes")
+
+	legacy := &store.DirEnvironment{HostPath: src, Mode: store.DirModeCopy}
+	assert.NoError(t, CheckSourceIdentity(context.Background(), g, "box", legacy), "no recorded identity skips the repo check")
+
+	require.NoError(t, os.RemoveAll(src))
+	require.NoError(t, os.MkdirAll(src, 0o750))
+	initGitRepo(t, src)
+	writeTestFile(t, src, "a.txt", "different")
+	gitAdd(t, src, ".")
+	gitCommit(t, src, "unrelated")
+	err = CheckSourceIdentity(context.Background(), g, "box", dir)
+	var usageErr *yoerrors.UsageError
+	require.ErrorAs(t, err, &usageErr)
+	assert.Contains(t, err.Error(), "https://example.com/repo.git")
+	assert.Contains(t, err.Error(), "--patches")
+
+	gone := &store.DirEnvironment{HostPath: filepath.Join(t.TempDir(), "moved"), Mode: store.DirModeCopy}
+	err = CheckSourceIdentity(context.Background(), g, "box", gone)
+	require.ErrorAs(t, err, &usageErr)
+	assert.Contains(t, err.Error(), "is gone")
+}
@@ -0,0 +1,101 @@
+// ABOUTME: Branch apply target: a throwaway worktree of the host repo on a new
+// ABOUTME: branch, so apply lands there without touching the user's checkout.
+
+package copyflow
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/kstenerud/yoloai/internal/git"
+	"github.com/kstenerud/yoloai/store"
+	"github.com/kstenerud/yoloai/yoerrors"
+)
+
+// branchWorktree is a temporary worktree of the host repository checked out on
+// a newly created branch: the target of an apply with a Branch option.
+type branchWorktree struct {
+	hostGit *git.Git
+	repo    string // the host repository
+	branch  string
+	tmp     string // parent of dir, removed on close
+	dir     string // the worktree
+}
+
+// checkBranchOptions rejects the apply options a Branch can't be combined
+// with: a dry run has no branch to create, and a fresh clone or a push to
+// origin are other destinations altogether.
+func checkBranchOptions(branch string, dryRun bool, freshClone, pushBranch string) error {
+	switch {
+	case branch == "":
+		return nil
+	case dryRun:
+		return yoerrors.NewUsageError("an apply to a new branch can't be a dry run")
+	case freshClone != "":
+		return yoerrors.NewUsageError("apply to a new branch or to a fresh clone, not both")
+	case pushBranch != "":
+		return yoerrors.NewUsageError("apply to a new branch or push to a branch of origin, not both")
+	}
+	return nil
+}
+
+// prepareBranchWorktree creates branch in dir's host repository, at the
+// sandbox baseline when the repository has it and at HEAD otherwise, and
+// checks it out in a temporary worktree. The branch must not exist yet. The
+// user's checkout (its branch, index and working tree) is never touched.
+func prepareBranchWorktree(ctx context.Context, hostGit *git.Git, dir *store.DirEnvironment, branch string) (*branchWorktree, error) {
+	if !git.IsGitRepo(dir.HostPath) {
+		return nil, yoerrors.NewUsageError("cannot apply to a branch of %s: not a git repository", dir.HostPath)
+	}
+	if err := hostGit.RunCmd(ctx, dir.HostPath, "check-ref-format", "--branch", branch); err != nil {
+		return nil, yoerrors.NewUsageError("invalid branch name %q", branch)
+	}
+	if hostGit.HasCommit(ctx, dir.HostPath, "refs/heads/"+branch) {
+		return nil, yoerrors.NewUsageError("branch %s already exists in %s; pick another name or delete it first", branch, dir.HostPath)
+	}
+	base := dir.BaselineSHA
+	if base == "" || !hostGit.HasCommit(ctx, dir.HostPath, base) {
+		var err error
+		if base, err = hostGit.HeadSHA(ctx, dir.HostPath); err != nil {
+			return nil, err
+		}
+	}
+
+	tmp, err := os.MkdirTemp("", "yoloai-branch-")
+	if err != nil {
+		return nil, fmt.Errorf("create branch worktree: %w", err)
+	}
+	wt := &branchWorktree{hostGit: hostGit, repo: dir.HostPath, branch: branch, tmp: tmp, dir: filepath.Join(tmp, "worktree")}
+	if err := hostGit.RunCmd(ctx, dir.HostPath, "worktree", "add", "--quiet", "-b", branch, wt.dir, base); err != nil {
+		_ = os.RemoveAll(tmp) //nolint:errcheck // best-effort cleanup
+		return nil, fmt.Errorf("create branch %s: %w", branch, err)
+	}
+	return wt, nil
+}
+
+// commitRemaining commits whatever the apply left uncommitted in the worktree
+// (a net diff, or uncommitted edits after a series) as one commit, so it
+// travels with the branch instead of vanishing with the worktree.
+func (wt *branchWorktree) commitRemaining(ctx context.Context, message string) error {
+	if err := wt.hostGit.RunCmd(ctx, wt.dir, "add", "-A"); err != nil {
+		return err
+	}
+	if _, err := wt.hostGit.Run(ctx, wt.dir, "diff", "--cached", "--quiet"); err == nil {
+		return nil // nothing staged
+	}
+	return wt.hostGit.RunCmd(ctx, wt.dir, "commit", "--quiet", "-m", message)
+}
+
+// close removes the worktree. Unless keep, the branch goes too, so a failed
+// apply leaves nothing behind and can be retried under the same name.
+func (wt *branchWorktree) close(ctx context.Context, keep bool) {
+	ctx = context.WithoutCancel(ctx)
+	_ = wt.hostGit.RunCmd(ctx, wt.repo, "worktree", "remove", "--force", wt.dir) //nolint:errcheck // best-effort cleanup
+	_ = os.RemoveAll(wt.tmp)                                                     //nolint:errcheck // best-effort cleanup
+	_ = wt.hostGit.RunCmd(ctx, wt.repo, "worktree", "prune")                     //nolint:errcheck // best-effort cleanup
+	if !keep {
+		_ = wt.hostGit.RunCmd(ctx, wt.repo, "branch", "--quiet", "-D", wt.branch) //nolint:errcheck // best-effort cleanup
+	}
+}
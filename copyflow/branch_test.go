@@ -0,0 +1,139 @@
+// ABOUTME: Tests for applying to a new branch: the changes land on the branch
+// ABOUTME: while the host checkout, its current branch and working tree stay put.
+
+package copyflow
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kstenerud/yoloai/internal/config"
+	"github.com/kstenerud/yoloai/internal/git"
+	"github.com/kstenerud/yoloai/yoerrors"
+)
+
+// branchSandbox is pushBranchSandbox that also returns the sandbox's work
+// copy and a layout whose git finds the test identity.
+func branchSandbox(t *testing.T, name string) (tmpDir, host, workDir string, layout config.Layout) {
+	t.Helper()
+	tmpDir = t.TempDir()
+	t.Setenv("HOME", tmpDir)
+	_, host, _ = setupOrigin(t, tmpDir)
+	workDir = createCopySandboxWithCommits(t, tmpDir, name, host, []struct {
+		subject  string
+		filename string
+		content  string
+	}{
+		{"add A", "a.txt", "a\n"},
+		{"add B", "b.txt", "b\n"},
+	})
+	writeTestFile(t, tmpDir, ".gitconfig", "[user]\n\tname = Test\n\temail = test@example.com\n")
+	layout = testLayout(tmpDir).WithEnv(map[string]string{"HOME": tmpDir, "PATH": os.Getenv("PATH")})
+	return tmpDir, host, workDir, layout
+}
+
+func gitOut(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	out, err := git.NewTestHostWithEnv(testEnv()).Run(context.Background(), dir, args...)
+	require.NoError(t, err)
+	return strings.TrimSpace(out)
+}
+
+func TestApplySeries_Branch(t *testing.T) {
+	name := "series-branch"
+	tmpDir, host, workDir, layout := branchSandbox(t, name)
+	writeTestFile(t, workDir, "wip.txt", "wip\n")
+	hostHead := gitHEAD(t, host)
+	hostBranch := gitOut(t, host, "branch", "--show-current")
+
+	result, err := ApplySeries(context.Background(), layout, hostGitRuntime(), name, ApplySeriesOptions{Branch: "yoloai/" + name, IncludeUncommitted: true})
+	require.NoError(t, err)
+	require.NotNil(t, result)
+	assert.Equal(t, "yoloai/"+name, result.Branch)
+	assert.Equal(t, host, result.Dir)
+	require.Len(t, result.Commits, 2)
+	assert.True(t, result.UncommittedApplied)
+
+	assert.Equal(t, "Uncommitted changes from sandbox series-branch\nadd B\nadd A\nlater",
+		gitOut(t, host, "log", "--format=%s", "-4", "yoloai/"+name), "the uncommitted edits follow the series as one commit")
+	assert.Equal(t, result.Commits[1].HostSHA, gitOut(t, host, "rev-parse", "yoloai/"+name+"~1"))
+
+	assert.Equal(t, hostHead, gitHEAD(t, host), "the host checkout is left alone")
+	assert.Equal(t, hostBranch, gitOut(t, host, "branch", "--show-current"))
+	assert.NoFileExists(t, filepath.Join(host, "a.txt"))
+	assert.NoFileExists(t, filepath.Join(host, "wip.txt"))
+	assert.Empty(t, gitOut(t, host, "status", "--porcelain"))
+	assert.Equal(t, 1, strings.Count(gitOut(t, host, "worktree", "list"), "\n")+1, "the temporary worktree is gone")
+
+	remaining, err := ListCommitsBeyondBaseline(context.Background(), testLayout(tmpDir), hostGitRuntime(), name, "")
+	require.NoError(t, err)
+	assert.Empty(t, remaining, "the baseline advances as for any apply")
+}
+
+func TestApplyAll_Branch(t *testing.T) {
+	name := "all-branch"
+	_, host, _, layout := branchSandbox(t, name)
+	hostHead := gitHEAD(t, host)
+
+	result, err := ApplyAll(context.Background(), layout, hostGitRuntime(), name, ApplyAllOptions{Branch: "review"})
+	require.NoError(t, err)
+	require.NotNil(t, result)
+	assert.Equal(t, "review", result.Branch)
+	assert.Equal(t, host, result.Dir)
+	assert.Equal(t, "Apply changes from sandbox all-branch", gitOut(t, host, "log", "-1", "--format=%s", "review"))
+	assert.Equal(t, "a.txt\nb.txt", gitOut(t, host, "diff", "--name-only", hostHead, "review"))
+	assert.Equal(t, hostHead, gitHEAD(t, host))
+	assert.NoFileExists(t, filepath.Join(host, "a.txt"))
+}
+
+func TestApplySeries_BranchRefusals(t *testing.T) {
+	name := "branch-refuse"
+	_, host, _, layout := branchSandbox(t, name)
+	gitOut(t, host, "branch", "taken")
+	var usage *yoerrors.UsageError
+
+	_, err := ApplySeries(context.Background(), layout, hostGitRuntime(), name, ApplySeriesOptions{Branch: "taken"})
+	assert.ErrorAs(t, err, &usage, "an existing branch is never overwritten")
+	_, err = ApplySeries(context.Background(), layout, hostGitRuntime(), name, ApplySeriesOptions{Branch: "bad..name"})
+	assert.ErrorAs(t, err, &usage)
+	_, err = ApplySeries(context.Background(), layout, hostGitRuntime(), name, ApplySeriesOptions{Branch: "b", DryRun: true})
+	assert.ErrorAs(t, err, &usage)
+	_, err = ApplySeries(context.Background(), layout, hostGitRuntime(), name, ApplySeriesOptions{Branch: "b", PushBranch: "p"})
+	assert.ErrorAs(t, err, &usage)
+
+	assert.Equal(t, "taken", gitOut(t, host, "branch", "--list", "taken", "b", "--format=%(refname:short)"))
+}
+
+// TestApplySeries_BranchRemovedOnFailure: when the series doesn't apply, the
+// half-made branch is deleted so the apply can be retried under the same name.
+func TestApplySeries_BranchRemovedOnFailure(t *testing.T) {
+	name := "branch-conflict"
+	tmpDir := t.TempDir()
+	t.Setenv("HOME", tmpDir)
+	_, host, _ := setupOrigin(t, tmpDir)
+	createCopySandboxWithCommits(t, tmpDir, name, host, []struct {
+		subject  string
+		filename string
+		content  string
+	}{
+		{"edit later", "later.txt", "agent\n"},
+	})
+	writeTestFile(t, tmpDir, ".gitconfig", "[user]\n\tname = Test\n\temail = test@example.com\n")
+	layout := testLayout(tmpDir).WithEnv(map[string]string{"HOME": tmpDir, "PATH": os.Getenv("PATH")})
+	// The host's later.txt differs from the copy the agent edited, so git am
+	// can't apply the commit.
+	writeTestFile(t, host, "later.txt", "host\n")
+	gitAdd(t, host, ".")
+	gitCommit(t, host, "host edit")
+
+	_, err := ApplySeries(context.Background(), layout, hostGitRuntime(), name, ApplySeriesOptions{Branch: "retry-me"})
+	require.Error(t, err)
+	assert.Empty(t, gitOut(t, host, "branch", "--list", "retry-me"))
+	assert.Equal(t, 1, strings.Count(gitOut(t, host, "worktree", "list"), "\n")+1)
+}
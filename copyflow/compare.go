@@ -0,0 +1,219 @@
+// ABOUTME: Compare diffs one sandbox's :copy work copy against another's — the
+// ABOUTME: `yoloai compare diff` flow for sandboxes given the same task.
+
+package copyflow
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/kstenerud/yoloai/internal/config"
+	"github.com/kstenerud/yoloai/internal/fileutil"
+	"github.com/kstenerud/yoloai/internal/git"
+	"github.com/kstenerud/yoloai/runtime"
+	"github.com/kstenerud/yoloai/store"
+	"github.com/kstenerud/yoloai/yoerrors"
+)
+
+// CompareSide names one work copy in a comparison.
+type CompareSide struct {
+	Name        string // sandbox name; also the path prefix in the diff
+	DirHostPath string // "" selects Dirs[0] (workdir)
+}
+
+// CompareOptions configures Compare.
+type CompareOptions struct {
+	A, B CompareSide
+	Stat bool // true for --stat summary only
+}
+
+// compareFile is one file as it stands in a work copy.
+type compareFile struct {
+	path string
+	mode string // git index mode: 100644, 100755 or 120000
+	data string
+}
+
+// Compare returns the diff from A's live work copy to B's — "" when they hold
+// the same files. Only the files either agent touched are read: anything
+// neither changed is still at the shared baseline. Each side is read through
+// its own sandbox-scoped git (in confinement where the backend runs it there)
+// and the two file sets are diffed in a host temp repo yoloai owns, so no
+// agent-controlled git config is consulted on the host.
+func Compare(ctx context.Context, layout config.Layout, rt runtime.Backend, opts CompareOptions) (string, error) {
+	a, err := readChangedSide(ctx, layout, rt, opts.A)
+	if err != nil {
+		return "", err
+	}
+	b, err := readChangedSide(ctx, layout, rt, opts.B)
+	if err != nil {
+		return "", err
+	}
+	paths := unionPaths(a.changed, b.changed)
+	if len(paths) == 0 {
+		return "", nil
+	}
+	aFiles, err := a.files(ctx, paths)
+	if err != nil {
+		return "", err
+	}
+	bFiles, err := b.files(ctx, paths)
+	if err != nil {
+		return "", err
+	}
+	return diffFileSets(ctx, layout, opts, aFiles, bFiles)
+}
+
+// compareReader reads one side's work copy.
+type compareReader struct {
+	name    string
+	g       *git.Git
+	workDir string
+	changed []string
+}
+
+func readChangedSide(ctx context.Context, layout config.Layout, rt runtime.Backend, side CompareSide) (*compareReader, error) {
+	workDir, baselineSHA, mode, err := loadDiffContext(layout, side.Name, side.DirHostPath)
+	if err != nil {
+		return nil, err
+	}
+	if mode != store.DirModeCopy {
+		return nil, yoerrors.NewUsageError("%s: only :copy work copies can be compared (workdir is :%s)", side.Name, mode)
+	}
+	g := git.NewSandbox(layout, rt, side.Name)
+	if err := g.StageUntracked(ctx, workDir); err != nil {
+		return nil, err
+	}
+	out, err := g.Run(ctx, workDir, "diff", "--name-only", "--no-renames", "-z", baselineSHA)
+	if err != nil {
+		return nil, fmt.Errorf("%s: list changed files: %w", side.Name, err)
+	}
+	return &compareReader{name: side.Name, g: g, workDir: workDir, changed: splitNUL(out)}, nil
+}
+
+// files returns the staged content of paths; a path missing from the index
+// (deleted in this copy) is simply absent from the result.
+func (r *compareReader) files(ctx context.Context, paths []string) ([]compareFile, error) {
+	out, err := r.g.Run(ctx, r.workDir, append([]string{"ls-files", "-s", "-z", "--"}, paths...)...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: list files: %w", r.name, err)
+	}
+	var files []compareFile
+	for _, entry := range splitNUL(out) {
+		// "<mode> <sha> <stage>\t<path>"
+		meta, path, ok := strings.Cut(entry, "\t")
+		fields := strings.Fields(meta)
+		if !ok || len(fields) != 3 || fields[0] == "160000" {
+			continue // malformed, or a submodule: no blob to read
+		}
+		if !filepath.IsLocal(path) {
+			return nil, fmt.Errorf("%s: refusing path outside the work copy: %q", r.name, path)
+		}
+		data, err := r.g.Run(ctx, r.workDir, "cat-file", "blob", fields[1])
+		if err != nil {
+			return nil, fmt.Errorf("%s: read %s: %w", r.name, path, err)
+		}
+		files = append(files, compareFile{path: path, mode: fields[0], data: data})
+	}
+	return files, nil
+}
+
+// diffFileSets stages each side's files into a scratch repo as a tree and
+// diffs the two trees, prefixing paths with the sandbox names.
+func diffFileSets(ctx context.Context, layout config.Layout, opts CompareOptions, aFiles, bFiles []compareFile) (string, error) {
+	if err := fileutil.MkdirAll(layout.TempDir(), 0o700); err != nil {
+		return "", fmt.Errorf("create temp root: %w", err)
+	}
+	tmpDir, err := os.MkdirTemp(layout.TempDir(), "yoloai-compare-*")
+	if err != nil {
+		return "", fmt.Errorf("create temp dir: %w", err)
+	}
+	defer os.RemoveAll(tmpDir) //nolint:errcheck // best-effort cleanup
+
+	g := git.NewHost(layout)
+	if err := g.RunCmd(ctx, tmpDir, "init", "-q"); err != nil {
+		return "", err
+	}
+	treeA, err := writeTree(ctx, g, tmpDir, aFiles)
+	if err != nil {
+		return "", err
+	}
+	treeB, err := writeTree(ctx, g, tmpDir, bFiles)
+	if err != nil {
+		return "", err
+	}
+
+	args := []string{"diff", "--binary"}
+	if opts.Stat {
+		args = []string{"diff", "--stat"}
+	}
+	args = append(args, "--src-prefix="+opts.A.Name+"/", "--dst-prefix="+opts.B.Name+"/", treeA, treeB)
+	out, err := g.Run(ctx, tmpDir, args...)
+	if err != nil {
+		return "", fmt.Errorf("git diff: %w", err)
+	}
+	return strings.TrimRight(out, "\n"), nil
+}
+
+// writeTree replaces the scratch repo's files with files and returns the
+// resulting tree's SHA.
+func writeTree(ctx context.Context, g *git.Git, dir string, files []compareFile) (string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return "", err
+	}
+	for _, e := range entries {
+		if e.Name() != ".git" {
+			if err := os.RemoveAll(filepath.Join(dir, e.Name())); err != nil {
+				return "", err
+			}
+		}
+	}
+	for _, f := range files {
+		dst := filepath.Join(dir, f.path)
+		if err := fileutil.MkdirAll(filepath.Dir(dst), 0o700); err != nil {
+			return "", err
+		}
+		switch f.mode {
+		case "120000":
+			err = os.Symlink(f.data, dst)
+		case "100755":
+			err = fileutil.WriteFile(dst, []byte(f.data), 0o700)
+		default:
+			err = fileutil.WriteFile(dst, []byte(f.data), 0o600)
+		}
+		if err != nil {
+			return "", fmt.Errorf("write %s: %w", f.path, err)
+		}
+	}
+	if err := g.RunCmd(ctx, dir, "add", "-A"); err != nil {
+		return "", err
+	}
+	out, err := g.Run(ctx, dir, "write-tree")
+	if err != nil {
+		return "", fmt.Errorf("git write-tree: %w", err)
+	}
+	return strings.TrimSpace(out), nil
+}
+
+// splitNUL splits git's -z output into its non-empty entries.
+func splitNUL(s string) []string {
+	var out []string
+	for part := range strings.SplitSeq(s, "\x00") {
+		if part != "" {
+			out = append(out, part)
+		}
+	}
+	return out
+}
+
+// unionPaths merges two path lists into one sorted, duplicate-free list.
+func unionPaths(a, b []string) []string {
+	paths := slices.Concat(a, b)
+	slices.Sort(paths)
+	return slices.Compact(paths)
+}
@@ -0,0 +1,89 @@
+// ABOUTME: Unit tests for Compare: the diff between two sandboxes' work copies
+// ABOUTME: covers files either side touched, identical copies, and :rw refusal.
+
+package copyflow
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kstenerud/yoloai/yoerrors"
+)
+
+func TestCompare_DiffsFilesEitherSideTouched(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Setenv("HOME", tmpDir)
+
+	workA := createCopySandbox(t, tmpDir, "cmp-a", "/tmp/project")
+	workB := createCopySandbox(t, tmpDir, "cmp-b", "/tmp/project")
+	writeTestFile(t, workA, "file.txt", "changed by a\n")
+	writeTestFile(t, workA, "only-a.txt", "from a\n")
+	writeTestFile(t, workB, "only-b.txt", "from b\n")
+
+	out, err := Compare(context.Background(), testLayout(tmpDir), hostGitRuntime(), CompareOptions{
+		A: CompareSide{Name: "cmp-a"},
+		B: CompareSide{Name: "cmp-b"},
+	})
+	require.NoError(t, err)
+	assert.Contains(t, out, "--- cmp-a/file.txt")
+	assert.Contains(t, out, "+++ cmp-b/file.txt")
+	assert.Contains(t, out, "-changed by a")
+	assert.Contains(t, out, "+original content")
+	assert.Contains(t, out, "deleted file mode 100644")
+	assert.Contains(t, out, "-from a")
+	assert.Contains(t, out, "+from b")
+}
+
+func TestCompare_Stat(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Setenv("HOME", tmpDir)
+
+	createCopySandbox(t, tmpDir, "cmp-a", "/tmp/project")
+	workB := createCopySandbox(t, tmpDir, "cmp-b", "/tmp/project")
+	writeTestFile(t, workB, "only-b.txt", "from b\n")
+
+	out, err := Compare(context.Background(), testLayout(tmpDir), hostGitRuntime(), CompareOptions{
+		A: CompareSide{Name: "cmp-a"}, B: CompareSide{Name: "cmp-b"}, Stat: true,
+	})
+	require.NoError(t, err)
+	assert.Contains(t, out, "only-b.txt | 1 +")
+	assert.Contains(t, out, "1 file changed")
+}
+
+func TestCompare_SameChangesIsEmpty(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Setenv("HOME", tmpDir)
+
+	workA := createCopySandbox(t, tmpDir, "cmp-a", "/tmp/project")
+	workB := createCopySandbox(t, tmpDir, "cmp-b", "/tmp/project")
+	for _, dir := range []string{workA, workB} {
+		writeTestFile(t, dir, "fix.txt", "same fix\n")
+		require.NoError(t, os.Remove(filepath.Join(dir, "file.txt")))
+	}
+
+	out, err := Compare(context.Background(), testLayout(tmpDir), hostGitRuntime(), CompareOptions{
+		A: CompareSide{Name: "cmp-a"}, B: CompareSide{Name: "cmp-b"},
+	})
+	require.NoError(t, err)
+	assert.Empty(t, out)
+}
+
+func TestCompare_RWWorkdirRefused(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Setenv("HOME", tmpDir)
+
+	createCopySandbox(t, tmpDir, "cmp-a", "/tmp/project")
+	createRWSandbox(t, tmpDir, "cmp-rw", "/tmp/project")
+
+	_, err := Compare(context.Background(), testLayout(tmpDir), hostGitRuntime(), CompareOptions{
+		A: CompareSide{Name: "cmp-a"}, B: CompareSide{Name: "cmp-rw"},
+	})
+	var usage *yoerrors.UsageError
+	require.ErrorAs(t, err, &usage)
+	assert.Contains(t, err.Error(), "only :copy work copies")
+}
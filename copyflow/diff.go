@@ -96,8 +96,8 @@ func GenerateChanges(ctx context.Context, opts DiffOptions) ([]FileChange, error
 // they asked about.
 //
 // Mode dispatch:
-//   - :copy: stages untracked files (via opts.Runtime.GitExec), then
-//     `git diff` against baseline.
+//   - :copy: stages untracked files (via opts.Runtime.GitExec) — only
+//     under opts.Paths when given — then `git diff` against baseline.
 //   - :rw: host-side `git diff HEAD`. Non-git :rw returns "" (no
 //     diff available).
 func GenerateDiff(ctx context.Context, opts DiffOptions) (string, error) {
@@ -112,7 +112,7 @@ func GenerateDiff(ctx context.Context, opts DiffOptions) (string, error) {
 
 	default: // "copy"
 		g := git.NewSandbox(opts.Layout, opts.Runtime, opts.Name)
-		if _, err := g.Run(ctx, workDir, "add", "-A"); err != nil {
+		if err := g.StageUntracked(ctx, workDir, opts.Paths...); err != nil {
 			return "", err
 		}
 
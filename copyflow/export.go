@@ -31,6 +31,10 @@ type ExportOptions struct {
 	IncludeUncommitted bool
 	// DirHostPath selects the directory to export; "" selects Dirs[0] (workdir).
 	DirHostPath string
+	// Provenance, when set, stamps a provenance header into each file the
+	// exported patches create and keeps edits to already-stamped files
+	// applying to the original directory.
+	Provenance *Provenance
 }
 
 // ExportResult reports what Export wrote.
@@ -63,24 +67,31 @@ func Export(ctx context.Context, layout config.Layout, rt runtime.Backend, name
 		return nil, fmt.Errorf("create export directory: %w", err)
 	}
 
-	return exportCopy(ctx, layout, rt, name, opts)
+	return exportCopy(ctx, layout, rt, name, dir.HostPath, opts)
 }
 
 // exportCopy writes format-patch files (+ optional uncommitted.diff) for a
-// copy-mode sandbox.
-func exportCopy(ctx context.Context, layout config.Layout, rt runtime.Backend, name string, opts ExportOptions) (*ExportResult, error) {
+// copy-mode sandbox. hostPath is the original directory the patches target.
+func exportCopy(ctx context.Context, layout config.Layout, rt runtime.Backend, name, hostPath string, opts ExportOptions) (*ExportResult, error) {
 	patchDir, files, err := generateExportPatch(ctx, layout, rt, name, opts)
 	if err != nil {
 		return nil, err
 	}
 	defer os.RemoveAll(patchDir) //nolint:errcheck // best-effort cleanup
 
+	var st *stamper
+	if opts.Provenance != nil {
+		st = newStamper(*opts.Provenance, hostPath)
+	}
 	result := &ExportResult{Dir: opts.Dir}
 	for _, f := range files {
 		data, readErr := os.ReadFile(filepath.Join(patchDir, f)) //nolint:gosec // G304: temp patch dir we created
 		if readErr != nil {
 			return nil, fmt.Errorf("read patch %s: %w", f, readErr)
 		}
+		if st != nil {
+			data = st.stamp(data)
+		}
 		dst := filepath.Join(opts.Dir, f)
 		if writeErr := fileutil.WriteFile(dst, data, 0600); writeErr != nil {
 			return nil, fmt.Errorf("write patch %s: %w", f, writeErr)
@@ -94,6 +105,9 @@ func exportCopy(ctx context.Context, layout config.Layout, rt runtime.Backend, n
 			return nil, diffErr
 		}
 		if len(uncommitted) > 0 {
+			if st != nil {
+				uncommitted = st.stamp(uncommitted)
+			}
 			dst := filepath.Join(opts.Dir, "uncommitted.diff")
 			if writeErr := fileutil.WriteFile(dst, uncommitted, 0600); writeErr != nil {
 				return nil, fmt.Errorf("write uncommitted.diff: %w", writeErr)
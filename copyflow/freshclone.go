@@ -0,0 +1,74 @@
+// ABOUTME: Fresh-clone apply target: clones the source repo's origin into a new
+// ABOUTME: directory at the sandbox baseline so apply can land there instead.
+
+package copyflow
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/kstenerud/yoloai/internal/git"
+	"github.com/kstenerud/yoloai/store"
+	"github.com/kstenerud/yoloai/yoerrors"
+)
+
+// FreshClone describes the clone a fresh-clone apply landed its changes in.
+type FreshClone struct {
+	// Dir is the new clone.
+	Dir string
+	// Remote is the origin URL it was cloned from.
+	Remote string
+	// BaseSHA is the commit the changes were applied on top of.
+	BaseSHA string
+	// AtBaseline is true when BaseSHA is the sandbox's diff baseline. False
+	// means origin doesn't have the baseline (unpushed work, a dirty-tree
+	// baseline, or a stripped history), so the clone stayed on origin's default
+	// branch and the apply validates against that instead.
+	AtBaseline bool
+}
+
+// prepareFreshClone clones dir's origin into dest and checks out the sandbox's
+// baseline there. The origin URL is read from the host repo, falling back to
+// the one recorded at create for a source that has since moved. dest must not
+// exist or be empty; it's made absolute so the clone never lands relative to
+// the host repo.
+func prepareFreshClone(ctx context.Context, hostGit *git.Git, dir *store.DirEnvironment, dest string) (*FreshClone, error) {
+	dest, err := filepath.Abs(dest)
+	if err != nil {
+		return nil, fmt.Errorf("resolve clone dir: %w", err)
+	}
+	if entries, err := os.ReadDir(dest); err == nil && len(entries) > 0 {
+		return nil, yoerrors.NewUsageError("fresh-clone directory %s already exists and is not empty", dest)
+	}
+
+	fromDir := filepath.Dir(dest)
+	remote := ""
+	if git.IsGitRepo(dir.HostPath) {
+		fromDir = dir.HostPath
+		remote = hostGit.RemoteURL(ctx, dir.HostPath, "origin")
+	}
+	if remote == "" {
+		remote = dir.SourceRemote
+	}
+	if remote == "" {
+		return nil, yoerrors.NewUsageError("%s has no origin remote to clone; apply there directly or export with --patches", dir.HostPath)
+	}
+
+	if err := hostGit.Clone(ctx, fromDir, remote, dest); err != nil {
+		return nil, fmt.Errorf("clone %s: %w", remote, err)
+	}
+
+	fc := &FreshClone{Dir: dest, Remote: remote}
+	if dir.BaselineSHA != "" && hostGit.HasCommit(ctx, dest, dir.BaselineSHA) {
+		if err := hostGit.RunCmd(ctx, dest, "checkout", "--quiet", "--detach", dir.BaselineSHA); err != nil {
+			return nil, err
+		}
+		fc.AtBaseline = true
+	}
+	if fc.BaseSHA, err = hostGit.HeadSHA(ctx, dest); err != nil {
+		return nil, err
+	}
+	return fc, nil
+}
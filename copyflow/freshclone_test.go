@@ -0,0 +1,154 @@
+// ABOUTME: Tests for fresh-clone applies: baseline checkout and its fallback,
+// ABOUTME: origin resolution, and that the host dir and baseline stay untouched.
+
+package copyflow
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/kstenerud/yoloai/internal/git"
+	"github.com/kstenerud/yoloai/store"
+	"github.com/kstenerud/yoloai/yoerrors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupOrigin builds an upstream repo with two commits and a host checkout
+// cloned from it, returning both paths and the upstream's first commit.
+func setupOrigin(t *testing.T, tmpDir string) (upstream, host, firstSHA string) {
+	t.Helper()
+	upstream = filepath.Join(tmpDir, "upstream")
+	require.NoError(t, os.MkdirAll(upstream, 0750))
+	initGitRepo(t, upstream)
+	writeTestFile(t, upstream, "seed.txt", "seed\n")
+	gitAdd(t, upstream, ".")
+	gitCommit(t, upstream, "initial")
+	firstSHA = gitHEAD(t, upstream)
+	writeTestFile(t, upstream, "later.txt", "later\n")
+	gitAdd(t, upstream, ".")
+	gitCommit(t, upstream, "later")
+
+	host = filepath.Join(tmpDir, "host")
+	require.NoError(t, git.NewTestHostWithEnv(testEnv()).Clone(context.Background(), tmpDir, upstream, host))
+	return upstream, host, firstSHA
+}
+
+func TestPrepareFreshClone_ChecksOutBaseline(t *testing.T) {
+	tmpDir := t.TempDir()
+	upstream, host, first := setupOrigin(t, tmpDir)
+	g := git.NewTestHostWithEnv(testEnv())
+
+	fc, err := prepareFreshClone(context.Background(), g, &store.DirEnvironment{HostPath: host, BaselineSHA: first}, filepath.Join(tmpDir, "clone"))
+	require.NoError(t, err)
+
+	assert.Equal(t, upstream, fc.Remote)
+	assert.True(t, fc.AtBaseline)
+	assert.Equal(t, first, fc.BaseSHA)
+	assert.NoFileExists(t, filepath.Join(fc.Dir, "later.txt"))
+}
+
+func TestPrepareFreshClone_FallsBackToDefaultBranch(t *testing.T) {
+	tmpDir := t.TempDir()
+	upstream, host, _ := setupOrigin(t, tmpDir)
+	g := git.NewTestHostWithEnv(testEnv())
+
+	fc, err := prepareFreshClone(context.Background(), g, &store.DirEnvironment{HostPath: host, BaselineSHA: "0123456789abcdef0123456789abcdef01234567"}, filepath.Join(tmpDir, "clone"))
+	require.NoError(t, err)
+
+	assert.False(t, fc.AtBaseline)
+	assert.Equal(t, gitHEAD(t, upstream), fc.BaseSHA)
+}
+
+func TestPrepareFreshClone_UsesRecordedRemoteForMovedSource(t *testing.T) {
+	tmpDir := t.TempDir()
+	upstream, _, _ := setupOrigin(t, tmpDir)
+	g := git.NewTestHostWithEnv(testEnv())
+
+	dir := &store.DirEnvironment{HostPath: filepath.Join(tmpDir, "moved"), SourceRemote: upstream}
+	fc, err := prepareFreshClone(context.Background(), g, dir, filepath.Join(tmpDir, "clone"))
+	require.NoError(t, err)
+	assert.FileExists(t, filepath.Join(fc.Dir, "seed.txt"))
+}
+
+func TestPrepareFreshClone_Refusals(t *testing.T) {
+	tmpDir := t.TempDir()
+	_, host, _ := setupOrigin(t, tmpDir)
+	g := git.NewTestHostWithEnv(testEnv())
+	var ue *yoerrors.UsageError
+
+	_, err := prepareFreshClone(context.Background(), g, &store.DirEnvironment{HostPath: host}, host)
+	require.ErrorAs(t, err, &ue, "a non-empty destination is refused")
+
+	noRemote := filepath.Join(tmpDir, "no-remote")
+	require.NoError(t, os.MkdirAll(noRemote, 0750))
+	initGitRepo(t, noRemote)
+	_, err = prepareFreshClone(context.Background(), g, &store.DirEnvironment{HostPath: noRemote}, filepath.Join(tmpDir, "clone"))
+	require.ErrorAs(t, err, &ue, "a source without an origin is refused")
+}
+
+// TestApplySeries_FreshClone replays the series into a new clone of the host's
+// origin: the host checkout is untouched and the baseline doesn't advance.
+func TestApplySeries_FreshClone(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Setenv("HOME", tmpDir)
+
+	upstream, host, _ := setupOrigin(t, tmpDir)
+	name := "series-fresh"
+	createCopySandboxWithCommits(t, tmpDir, name, host, []struct {
+		subject  string
+		filename string
+		content  string
+	}{
+		{"add A", "a.txt", "a\n"},
+		{"add B", "b.txt", "b\n"},
+	})
+	rt := hostGitRuntime()
+	cloneDir := filepath.Join(tmpDir, "fresh")
+
+	// git am commits in the clone, which has no identity of its own; give host
+	// git a HOME whose global config supplies one.
+	writeTestFile(t, tmpDir, ".gitconfig", "[user]\n\tname = Test\n\temail = test@example.com\n")
+	layout := testLayout(tmpDir).WithEnv(map[string]string{"HOME": tmpDir, "PATH": os.Getenv("PATH")})
+
+	result, err := ApplySeries(context.Background(), layout, rt, name, ApplySeriesOptions{FreshClone: cloneDir})
+	require.NoError(t, err)
+	require.NotNil(t, result)
+	require.NotNil(t, result.Clone)
+	assert.Equal(t, cloneDir, result.Dir)
+	assert.Equal(t, upstream, result.Clone.Remote)
+	require.Len(t, result.Commits, 2)
+
+	assert.FileExists(t, filepath.Join(cloneDir, "a.txt"))
+	assert.FileExists(t, filepath.Join(cloneDir, "b.txt"))
+	assert.NoFileExists(t, filepath.Join(host, "a.txt"))
+
+	remaining, err := ListCommitsBeyondBaseline(context.Background(), testLayout(tmpDir), rt, name, "")
+	require.NoError(t, err)
+	assert.Len(t, remaining, 2, "a fresh-clone apply must not advance the baseline")
+}
+
+func TestApplyAll_FreshClone(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Setenv("HOME", tmpDir)
+
+	_, host, _ := setupOrigin(t, tmpDir)
+	name := "all-fresh"
+	workDir := createCopySandbox(t, tmpDir, name, host)
+	writeTestFile(t, workDir, "new.txt", "new\n")
+	rt := hostGitRuntime()
+	cloneDir := filepath.Join(tmpDir, "fresh")
+
+	result, err := ApplyAll(context.Background(), testLayout(tmpDir), rt, name, ApplyAllOptions{IncludeUncommitted: true, FreshClone: cloneDir})
+	require.NoError(t, err)
+	require.NotNil(t, result)
+	require.NotNil(t, result.Clone)
+	assert.FileExists(t, filepath.Join(cloneDir, "new.txt"))
+	assert.NoFileExists(t, filepath.Join(host, "new.txt"))
+
+	_, err = ApplyAll(context.Background(), testLayout(tmpDir), rt, name, ApplyAllOptions{IncludeUncommitted: true, FreshClone: filepath.Join(tmpDir, "again"), DryRun: true})
+	var ue *yoerrors.UsageError
+	require.ErrorAs(t, err, &ue, "dry run is refused")
+}
@@ -0,0 +1,163 @@
+// ABOUTME: Splits a git diff into per-file hunks and reassembles a chosen subset,
+// ABOUTME: the building blocks of an interactive (hunk-by-hunk) apply.
+
+package copyflow
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// PatchFile is one file's section of a git diff: the header (the "diff --git"
+// line through the "+++" line) and its hunks. A file with no Hunks — a binary
+// change, a pure rename or mode change, or a deletion — is offered and applied
+// as a single unit: its whole diff lives in Header.
+type PatchFile struct {
+	// Path is the file's path relative to the workdir (the b/ side; the a/
+	// side for a deletion).
+	Path string
+	// Header is the file's diff text preceding the first hunk, newline-terminated.
+	Header string
+	// Hunks are the file's "@@" hunks, in order.
+	Hunks []Hunk
+}
+
+// Hunk is one "@@" hunk of a file diff. Lines keep their leading ' ', '+',
+// '-' or '\' marker and carry no trailing newline.
+type Hunk struct {
+	// Header is the "@@ -a,b +c,d @@ ..." line. The counts are recomputed from
+	// Lines when the patch is assembled, so an edited hunk needn't fix them.
+	Header string
+	Lines  []string
+}
+
+// String renders the hunk as diff text, header line first.
+func (h Hunk) String() string {
+	var b strings.Builder
+	b.WriteString(h.Header)
+	b.WriteByte('\n')
+	for _, l := range h.Lines {
+		b.WriteString(l)
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+// ParsePatch splits git diff output into per-file sections. Text before the
+// first "diff --git" line is dropped (GeneratePatch emits none).
+func ParsePatch(patch []byte) []PatchFile {
+	var files []PatchFile
+	var cur *PatchFile
+	var hunk *Hunk
+	flushHunk := func() {
+		if cur != nil && hunk != nil {
+			cur.Hunks = append(cur.Hunks, *hunk)
+		}
+		hunk = nil
+	}
+
+	lines := strings.SplitAfter(string(patch), "\n")
+	for _, raw := range lines {
+		if raw == "" {
+			continue
+		}
+		line := strings.TrimSuffix(raw, "\n")
+		switch {
+		case strings.HasPrefix(line, "diff --git "):
+			flushHunk()
+			files = append(files, PatchFile{Path: diffGitPath(line), Header: line + "\n"})
+			cur = &files[len(files)-1]
+		case cur == nil:
+			continue
+		case strings.HasPrefix(line, "@@") && !strings.Contains(cur.Header, "\ndeleted file mode "):
+			// Every hunk content line carries a marker, so a bare "@@" always
+			// starts a new hunk. A deletion stays whole: dropping part of it
+			// would leave a patch that deletes a file it doesn't empty.
+			flushHunk()
+			hunk = &Hunk{Header: line}
+		case hunk != nil:
+			hunk.Lines = append(hunk.Lines, line)
+		default:
+			cur.Header += line + "\n"
+		}
+	}
+	flushHunk()
+	return files
+}
+
+// diffGitPath extracts the path from a "diff --git a/x b/x" line. Paths with
+// spaces are ambiguous in that line; it takes the b/ side after the last
+// " b/", which is right whenever the path doesn't itself contain " b/".
+func diffGitPath(line string) string {
+	rest := strings.TrimPrefix(line, "diff --git ")
+	if i := strings.LastIndex(rest, " b/"); i >= 0 {
+		return rest[i+len(" b/"):]
+	}
+	return strings.TrimPrefix(rest, "a/")
+}
+
+var hunkHeaderRE = regexp.MustCompile(`^@@ -(\d+)(?:,\d+)? \+(\d+)(?:,\d+)? @@(.*)$`)
+
+// recount returns h's header with the old/new line counts recomputed from its
+// lines, so hunks edited by hand still apply. Start lines are kept: git apply
+// locates a hunk by its context, not by exact offsets.
+func (h Hunk) recount() (string, error) {
+	m := hunkHeaderRE.FindStringSubmatch(h.Header)
+	if m == nil {
+		return "", fmt.Errorf("malformed hunk header %q", h.Header)
+	}
+	oldN, newN := 0, 0
+	for _, l := range h.Lines {
+		switch {
+		case l == "" || l[0] == ' ':
+			oldN++
+			newN++
+		case l[0] == '-':
+			oldN++
+		case l[0] == '+':
+			newN++
+		case l[0] == '\\':
+		default:
+			return "", fmt.Errorf("hunk line %q doesn't start with ' ', '+', '-' or '\\'", l)
+		}
+	}
+	return fmt.Sprintf("@@ -%s%s +%s%s @@%s", m[1], hunkCount(oldN), m[2], hunkCount(newN), m[3]), nil
+}
+
+// hunkCount formats a hunk header line count the way git does: omitted when
+// it's 1, so an unedited hunk reassembles byte-for-byte.
+func hunkCount(n int) string {
+	if n == 1 {
+		return ""
+	}
+	return fmt.Sprintf(",%d", n)
+}
+
+// AssemblePatch renders files back into git diff text. A file with no hunks
+// is emitted as its header alone (the whole-file change it describes); hunk
+// counts are recomputed so hand-edited hunks apply. Callers drop a file whose
+// hunks were all declined rather than passing it with Hunks emptied.
+func AssemblePatch(files []PatchFile) ([]byte, error) {
+	var b strings.Builder
+	for _, f := range files {
+		b.WriteString(f.Header)
+		for _, h := range f.Hunks {
+			header, err := h.recount()
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", f.Path, err)
+			}
+			b.WriteString(header)
+			b.WriteByte('\n')
+			for _, l := range h.Lines {
+				if l == "" {
+					// Editors strip the lone space of an empty context line.
+					l = " "
+				}
+				b.WriteString(l)
+				b.WriteByte('\n')
+			}
+		}
+	}
+	return []byte(b.String()), nil
+}
@@ -0,0 +1,138 @@
+// ABOUTME: Unit tests for splitting a git diff into hunks and reassembling a
+// ABOUTME: selection: byte-exact round trip, partial and edited hunks applying.
+
+package copyflow
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kstenerud/yoloai/internal/testutil"
+)
+
+// numberedLines returns "line 1\n" … "line n\n", with overrides by 1-based line.
+func numberedLines(n int, overrides map[int]string) string {
+	var b strings.Builder
+	for i := 1; i <= n; i++ {
+		if s, ok := overrides[i]; ok {
+			b.WriteString(s + "\n")
+			continue
+		}
+		fmt.Fprintf(&b, "line %d\n", i)
+	}
+	return b.String()
+}
+
+// hunkRepo commits a 30-line a.txt and a gone.txt, then edits a.txt in two far
+// apart places, adds new.txt and deletes gone.txt. Returns the repo and its diff.
+func hunkRepo(t *testing.T) (string, []byte) {
+	t.Helper()
+	dir := t.TempDir()
+	initGitRepo(t, dir)
+	writeTestFile(t, dir, "a.txt", numberedLines(30, nil))
+	writeTestFile(t, dir, "gone.txt", "bye\n")
+	gitAdd(t, dir, ".")
+	gitCommit(t, dir, "base")
+
+	writeTestFile(t, dir, "a.txt", numberedLines(30, map[int]string{2: "changed 2", 28: "changed 28"}))
+	writeTestFile(t, dir, "new.txt", "hello\n")
+	require.NoError(t, os.Remove(filepath.Join(dir, "gone.txt")))
+	gitAdd(t, dir, ".")
+	// RunGitOutput trims; git ends a diff with a newline.
+	return dir, []byte(testutil.RunGitOutput(t, dir, "diff", "--cached", "--binary", "HEAD") + "\n")
+}
+
+func TestParsePatch_SplitsFilesAndHunks(t *testing.T) {
+	_, patch := hunkRepo(t)
+
+	files := ParsePatch(patch)
+	require.Len(t, files, 3)
+
+	assert.Equal(t, "a.txt", files[0].Path)
+	require.Len(t, files[0].Hunks, 2)
+	assert.Contains(t, files[0].Hunks[0].Lines, "+changed 2")
+	assert.Contains(t, files[0].Hunks[1].Lines, "+changed 28")
+
+	// A deletion stays whole: its hunk is part of the header.
+	assert.Equal(t, "gone.txt", files[1].Path)
+	assert.Empty(t, files[1].Hunks)
+	assert.Contains(t, files[1].Header, "-bye")
+
+	assert.Equal(t, "new.txt", files[2].Path)
+	require.Len(t, files[2].Hunks, 1)
+}
+
+func TestAssemblePatch_RoundTripsUnchanged(t *testing.T) {
+	_, patch := hunkRepo(t)
+
+	out, err := AssemblePatch(ParsePatch(patch))
+	require.NoError(t, err)
+	assert.Equal(t, string(patch), string(out))
+}
+
+func TestAssemblePatch_PartialSelectionApplies(t *testing.T) {
+	dir, patch := hunkRepo(t)
+	testutil.RunGit(t, dir, "reset", "-q", "--hard", "HEAD")
+
+	// Keep only a.txt's second hunk.
+	files := ParsePatch(patch)
+	a := files[0]
+	a.Hunks = a.Hunks[1:]
+	out, err := AssemblePatch([]PatchFile{a})
+	require.NoError(t, err)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "sel.patch"), out, 0600))
+	testutil.RunGit(t, dir, "apply", "sel.patch")
+
+	got, err := os.ReadFile(filepath.Join(dir, "a.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, numberedLines(30, map[int]string{28: "changed 28"}), string(got))
+	assert.FileExists(t, filepath.Join(dir, "gone.txt"))
+	assert.NoFileExists(t, filepath.Join(dir, "new.txt"))
+}
+
+func TestAssemblePatch_RecountsEditedHunk(t *testing.T) {
+	dir, patch := hunkRepo(t)
+	testutil.RunGit(t, dir, "reset", "-q", "--hard", "HEAD")
+
+	// Turn the first hunk's "-line 2" into context and drop its "+" line, then
+	// add a line of our own — the counts in the header are now all wrong.
+	files := ParsePatch(patch)
+	h := files[0].Hunks[0]
+	var lines []string
+	for _, l := range h.Lines {
+		switch l {
+		case "-line 2":
+			lines = append(lines, " line 2")
+		case "+changed 2":
+			lines = append(lines, "+inserted")
+		default:
+			lines = append(lines, l)
+		}
+	}
+	h.Lines = lines
+	out, err := AssemblePatch([]PatchFile{{Path: "a.txt", Header: files[0].Header, Hunks: []Hunk{h}}})
+	require.NoError(t, err)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "sel.patch"), out, 0600))
+	testutil.RunGit(t, dir, "apply", "sel.patch")
+
+	got, err := os.ReadFile(filepath.Join(dir, "a.txt"))
+	require.NoError(t, err)
+	want := strings.Replace(numberedLines(30, nil), "line 2\n", "line 2\ninserted\n", 1)
+	assert.Equal(t, want, string(got))
+}
+
+func TestAssemblePatch_RejectsBadHunkLine(t *testing.T) {
+	_, err := AssemblePatch([]PatchFile{{Path: "x", Header: "diff --git a/x b/x\n", Hunks: []Hunk{
+		{Header: "@@ -1 +1 @@", Lines: []string{"oops"}},
+	}}})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "x:")
+}
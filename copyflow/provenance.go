@@ -0,0 +1,495 @@
+// ABOUTME: Provenance headers: rewrites a git patch so every file it creates
+// ABOUTME: opens with a comment naming the agent, model, sandbox and date, and
+// ABOUTME: shifts later hunks of stamped files so they still apply.
+
+package copyflow
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kstenerud/yoloai/internal/fileutil"
+)
+
+// ProvenanceMarker opens every provenance header, so stamped files can be found
+// (and stripped) with a plain text search.
+const ProvenanceMarker = "yoloai-provenance:"
+
+// Provenance identifies who generated a file, for the header comment stamped
+// into files the agent created.
+type Provenance struct {
+	Tool    string    // agent that wrote the file, e.g. "claude"
+	Model   string    // model the agent ran; "" when unknown
+	Sandbox string    // sandbox name
+	Date    time.Time // when the change was applied
+
+	// Skip leaves created files unstamped (`apply --no-provenance`). Headers
+	// an earlier apply put on the host are still accounted for, so edits to
+	// those files keep applying.
+	Skip bool
+}
+
+// text renders the header's comment body.
+func (p Provenance) text() string {
+	var b strings.Builder
+	b.WriteString(ProvenanceMarker + " generated by " + p.Tool)
+	if p.Model != "" {
+		b.WriteString(" (model " + p.Model + ")")
+	}
+	fmt.Fprintf(&b, " in yoloai sandbox %s on %s", p.Sandbox, p.Date.Format("2006-01-02"))
+	return b.String()
+}
+
+// commentStyle is a language's comment delimiters. end is empty for line comments.
+type commentStyle struct {
+	start, end string
+}
+
+var (
+	slashComment = commentStyle{start: "//"}
+	hashComment  = commentStyle{start: "#"}
+	dashComment  = commentStyle{start: "--"}
+	semiComment  = commentStyle{start: ";"}
+	blockComment = commentStyle{start: "/*", end: "*/"}
+	htmlComment  = commentStyle{start: "<!--", end: "-->"}
+)
+
+// commentStyles maps file extensions to their comment syntax. Formats with no
+// comment syntax (JSON, plain text) are absent and never stamped.
+var commentStyles = map[string]commentStyle{
+	".go": slashComment, ".c": slashComment, ".h": slashComment, ".cc": slashComment,
+	".cpp": slashComment, ".cxx": slashComment, ".hpp": slashComment, ".m": slashComment,
+	".mm": slashComment, ".java": slashComment, ".kt": slashComment, ".kts": slashComment,
+	".scala": slashComment, ".groovy": slashComment, ".gradle": slashComment,
+	".js": slashComment, ".jsx": slashComment, ".mjs": slashComment, ".cjs": slashComment,
+	".ts": slashComment, ".tsx": slashComment, ".swift": slashComment, ".rs": slashComment,
+	".cs": slashComment, ".fs": slashComment, ".dart": slashComment, ".zig": slashComment,
+	".proto": slashComment, ".scss": slashComment, ".less": slashComment,
+
+	".py": hashComment, ".rb": hashComment, ".sh": hashComment, ".bash": hashComment,
+	".zsh": hashComment, ".fish": hashComment, ".pl": hashComment, ".pm": hashComment,
+	".r": hashComment, ".jl": hashComment, ".ex": hashComment, ".exs": hashComment,
+	".nix": hashComment, ".ps1": hashComment, ".tf": hashComment, ".cmake": hashComment,
+	".mk": hashComment, ".yaml": hashComment, ".yml": hashComment, ".toml": hashComment,
+
+	".sql": dashComment, ".lua": dashComment, ".hs": dashComment, ".elm": dashComment,
+
+	".el": semiComment, ".clj": semiComment, ".cljs": semiComment, ".lisp": semiComment,
+	".scm": semiComment, ".ini": semiComment,
+
+	".css": blockComment,
+
+	".html": htmlComment, ".htm": htmlComment, ".xml": htmlComment, ".svg": htmlComment,
+	".vue": htmlComment, ".svelte": htmlComment, ".md": htmlComment,
+}
+
+// commentStyleNames covers extensionless files known by name.
+var commentStyleNames = map[string]commentStyle{
+	"Dockerfile": hashComment, "Containerfile": hashComment, "Makefile": hashComment,
+	"GNUmakefile": hashComment, "Rakefile": hashComment, "Gemfile": hashComment,
+	"CMakeLists.txt": hashComment,
+}
+
+// commentStyleFor returns the comment syntax for path, or false when the file
+// type has none yoloai knows.
+func commentStyleFor(path string) (commentStyle, bool) {
+	base := filepath.Base(path)
+	if s, ok := commentStyleNames[base]; ok {
+		return s, true
+	}
+	s, ok := commentStyles[strings.ToLower(filepath.Ext(base))]
+	return s, ok
+}
+
+// preambleRe matches first lines that must stay first: an interpreter line, an
+// XML declaration, an HTML doctype, or a Python/Ruby encoding declaration.
+var preambleRe = regexp.MustCompile(`^(#!|<\?xml|<!(?i:doctype)|#.*coding[:=])`)
+
+// hunkRe parses a unified-diff hunk header; a missing count means 1.
+var hunkRe = regexp.MustCompile(`^@@ -(\d+)(?:,(\d+))? \+(\d+)(?:,(\d+))? @@`)
+
+// fileHeader is a provenance header as it sits in a file: lines (with their
+// line endings, without diff prefixes) starting after the first at lines.
+type fileHeader struct {
+	at    int
+	lines []string
+}
+
+// stamper rewrites patches for provenance: it adds headers to created files
+// and shifts hunks that edit a file already carrying one, since the sandbox's
+// copy of that file never had it. It tracks headers across the patches of a
+// series, and reads the host target for files stamped by an earlier apply.
+type stamper struct {
+	prov    Provenance
+	hostDir string                // host target; "" skips the lookup
+	headers map[string]fileHeader // by path, as of the next patch
+}
+
+func newStamper(p Provenance, hostDir string) *stamper {
+	return &stamper{prov: p, hostDir: hostDir, headers: make(map[string]fileHeader)}
+}
+
+// fileSection is the per-file state while walking a patch.
+type fileSection struct {
+	oldPath, newPath string
+	created, deleted bool
+	copied           bool // "copy to": oldPath stays, newPath is added
+	resolved         bool // header lookup done for this section
+	hdr              *fileHeader
+}
+
+// stamp returns patch rewritten for provenance. Works on plain diffs and on git
+// format-patch output alike.
+func (s *stamper) stamp(patch []byte) []byte {
+	lines := strings.SplitAfter(string(patch), "\n")
+	out := make([]string, 0, len(lines))
+	var sec *fileSection
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		switch {
+		case strings.HasPrefix(line, "diff --git "):
+			s.finish(sec)
+			sec = &fileSection{}
+		case sec == nil:
+		case strings.HasPrefix(line, "new file mode "):
+			sec.created = true
+		case strings.HasPrefix(line, "deleted file mode "):
+			sec.deleted = true
+		case strings.HasPrefix(line, "rename from "), strings.HasPrefix(line, "copy from "):
+			sec.oldPath = unquotePath(line[strings.Index(line, "from ")+5:])
+		case strings.HasPrefix(line, "rename to "), strings.HasPrefix(line, "copy to "):
+			sec.newPath = unquotePath(line[strings.Index(line, "to ")+3:])
+			sec.copied = strings.HasPrefix(line, "copy ")
+		case strings.HasPrefix(line, "--- "):
+			sec.oldPath = diffSidePath(line[4:], "a/")
+		case strings.HasPrefix(line, "+++ "):
+			sec.newPath = diffSidePath(line[4:], "b/")
+		}
+		m := hunkRe.FindStringSubmatch(line)
+		if m == nil || sec == nil {
+			out = append(out, line)
+			continue
+		}
+		h := parseHunk(m)
+		end := min(i+1+h.bodyLen(lines[i+1:]), len(lines))
+		out = append(out, s.rewriteHunk(sec, h, line[len(m[0]):], lines[i+1:end])...)
+		i = end - 1
+	}
+	s.finish(sec)
+	return []byte(strings.Join(out, ""))
+}
+
+// finish records what a file section leaves behind for later patches.
+func (s *stamper) finish(sec *fileSection) {
+	if sec == nil {
+		return
+	}
+	if !sec.resolved && !sec.created && sec.oldPath != "" {
+		// A pure rename or copy has no hunks; carry any header along.
+		sec.hdr = s.lookup(sec.oldPath)
+	}
+	if sec.oldPath != "" && sec.oldPath != sec.newPath && !sec.copied {
+		delete(s.headers, sec.oldPath)
+	}
+	if sec.deleted || sec.newPath == "" {
+		return
+	}
+	if sec.hdr != nil {
+		s.headers[sec.newPath] = *sec.hdr
+	} else {
+		// Known not to carry one now (e.g. created unstamped); don't consult
+		// the host, whose copy predates this patch.
+		s.headers[sec.newPath] = fileHeader{at: -1}
+	}
+}
+
+// lookup returns the header path carries before the current patch: from an
+// earlier patch in the series, else from the host file. nil when it has none.
+func (s *stamper) lookup(path string) *fileHeader {
+	if h, ok := s.headers[path]; ok {
+		if h.at < 0 {
+			return nil
+		}
+		return &h
+	}
+	if s.hostDir == "" {
+		return nil
+	}
+	h := readFileHeader(filepath.Join(s.hostDir, filepath.FromSlash(path)))
+	if h != nil {
+		s.headers[path] = *h
+	}
+	return h
+}
+
+// hunk is a parsed hunk header.
+type hunk struct {
+	oldStart, oldCount, newStart, newCount int
+}
+
+func parseHunk(m []string) hunk {
+	count := func(s string) int {
+		if s == "" {
+			return 1
+		}
+		n, _ := strconv.Atoi(s)
+		return n
+	}
+	oldStart, _ := strconv.Atoi(m[1])
+	newStart, _ := strconv.Atoi(m[3])
+	return hunk{oldStart: oldStart, oldCount: count(m[2]), newStart: newStart, newCount: count(m[4])}
+}
+
+// bodyLen counts the lines of rest that belong to the hunk: until both sides'
+// counts are used up, plus any "\ No newline" marker that follows.
+func (h hunk) bodyLen(rest []string) int {
+	oldLeft, newLeft := h.oldCount, h.newCount
+	n := 0
+	for n < len(rest) && rest[n] != "" && (oldLeft > 0 || newLeft > 0) {
+		switch rest[n][0] {
+		case ' ':
+			oldLeft--
+			newLeft--
+		case '-':
+			oldLeft--
+		case '+':
+			newLeft--
+		}
+		n++
+	}
+	if n < len(rest) && strings.HasPrefix(rest[n], `\`) {
+		n++
+	}
+	return n
+}
+
+func (h hunk) String() string {
+	return fmt.Sprintf("@@ -%s +%s @@", hunkRange(h.oldStart, h.oldCount), hunkRange(h.newStart, h.newCount))
+}
+
+func hunkRange(start, count int) string {
+	if count == 1 {
+		return strconv.Itoa(start)
+	}
+	return strconv.Itoa(start) + "," + strconv.Itoa(count)
+}
+
+// rewriteHunk returns the hunk's header line and body, rewritten for sec.
+func (s *stamper) rewriteHunk(sec *fileSection, h hunk, trailer string, body []string) []string {
+	unchanged := append([]string{h.String() + trailer}, body...)
+
+	if sec.created {
+		sec.resolved = true
+		if h.oldCount != 0 || s.prov.Skip {
+			return unchanged
+		}
+		hdr, ok := s.newHeader(sec.newPath, body)
+		if !ok {
+			return unchanged
+		}
+		sec.hdr = &hdr
+		h.newCount += len(hdr.lines)
+		return spliceHunk(h, trailer, body, hdr.at, prefixed("+", hdr.lines))
+	}
+
+	if !sec.resolved {
+		sec.resolved = true
+		sec.hdr = s.lookup(sec.oldPath)
+	}
+	hdr := sec.hdr
+	if hdr == nil || oldSideHasMarker(body) {
+		// No header to account for, or the sandbox's copy has one too.
+		return unchanged
+	}
+	n := len(hdr.lines)
+	switch {
+	case sec.deleted:
+		h.oldCount += n
+		return spliceHunk(h, trailer, body, hdr.at, prefixed("-", hdr.lines))
+	case h.oldCount == 0:
+		// Pure insertion after line oldStart.
+		if h.oldStart > 0 && h.oldStart >= hdr.at {
+			h.oldStart += n
+			h.newStart += n
+		}
+		return append([]string{h.String() + trailer}, body...)
+	case h.oldStart > hdr.at:
+		h.oldStart += n
+		h.newStart += n
+		return append([]string{h.String() + trailer}, body...)
+	default:
+		// The hunk starts on the preamble the header follows: carry the
+		// header through as context, unless the preamble itself changes.
+		if idx := oldSideIndex(body, hdr.at); idx < 0 || body[idx-1][0] != ' ' {
+			return unchanged
+		}
+		h.oldCount += n
+		h.newCount += n
+		return spliceHunk(h, trailer, body, hdr.at, prefixed(" ", hdr.lines))
+	}
+}
+
+// spliceHunk renders h with insert placed after the first `after` old-side
+// lines of body (for a created file, after the first `after` lines).
+func spliceHunk(h hunk, trailer string, body []string, after int, insert []string) []string {
+	idx := oldSideIndex(body, after)
+	if idx < 0 {
+		idx = min(after, len(body))
+	}
+	out := make([]string, 0, len(body)+len(insert)+1)
+	out = append(out, h.String()+trailer)
+	out = append(out, body[:idx]...)
+	out = append(out, insert...)
+	return append(out, body[idx:]...)
+}
+
+// oldSideIndex returns the body index just past the first n old-side (context
+// or removed) lines, or -1 when the body has fewer. n == 0 yields 0.
+func oldSideIndex(body []string, n int) int {
+	if n == 0 {
+		return 0
+	}
+	seen := 0
+	for i, l := range body {
+		if l[0] == ' ' || l[0] == '-' {
+			seen++
+			if seen == n {
+				return i + 1
+			}
+		}
+	}
+	return -1
+}
+
+// oldSideHasMarker reports whether the hunk's pre-image already contains a
+// provenance header.
+func oldSideHasMarker(body []string) bool {
+	for _, l := range body {
+		if (l[0] == ' ' || l[0] == '-') && strings.Contains(l, ProvenanceMarker) {
+			return true
+		}
+	}
+	return false
+}
+
+// newHeader builds the header for a created file from its "+" lines, or false
+// when the file type has no comment syntax, already carries a header, or is
+// only a preamble line.
+func (s *stamper) newHeader(path string, body []string) (fileHeader, bool) {
+	style, ok := commentStyleFor(path)
+	if !ok || len(body) == 0 || !strings.HasPrefix(body[0], "+") {
+		return fileHeader{}, false
+	}
+	for _, l := range body[:min(3, len(body))] {
+		if strings.Contains(l, ProvenanceMarker) {
+			return fileHeader{}, false
+		}
+	}
+	at := 0
+	if preambleRe.MatchString(body[0][1:]) {
+		// The header goes after the preamble, which must then not be the
+		// file's last line (a "\ No newline" marker would follow it).
+		if len(body) < 2 || !strings.HasPrefix(body[1], "+") {
+			return fileHeader{}, false
+		}
+		at = 1
+	}
+
+	eol := "\n"
+	if strings.HasSuffix(body[0], "\r\n") {
+		eol = "\r\n"
+	}
+	comment := style.start + " " + s.prov.text()
+	if style.end != "" {
+		comment += " " + style.end
+	}
+	return fileHeader{at: at, lines: []string{comment + eol, eol}}, true
+}
+
+// readFileHeader returns the provenance header at the top of the file at path
+// (first line, or second after a preamble) with the blank line after it, or
+// nil when there is none or the file can't be read.
+func readFileHeader(path string) *fileHeader {
+	f, err := os.Open(path) //nolint:gosec // G304: file in the apply target
+	if err != nil {
+		return nil
+	}
+	defer f.Close() //nolint:errcheck // read-only
+	r := bufio.NewReader(f)
+	var top []string
+	for range 3 {
+		l, err := r.ReadString('\n')
+		if l != "" {
+			top = append(top, l)
+		}
+		if err != nil {
+			break
+		}
+	}
+	for at := 0; at < min(2, len(top)); at++ {
+		if !strings.Contains(top[at], ProvenanceMarker) {
+			continue
+		}
+		if at == 1 && !preambleRe.MatchString(top[0]) {
+			return nil
+		}
+		lines := []string{top[at]}
+		if at+1 < len(top) && strings.TrimRight(top[at+1], "\r\n") == "" {
+			lines = append(lines, top[at+1])
+		}
+		return &fileHeader{at: at, lines: lines}
+	}
+	return nil
+}
+
+// prefixed returns lines each prefixed with p (a diff line marker).
+func prefixed(p string, lines []string) []string {
+	out := make([]string, len(lines))
+	for i, l := range lines {
+		out[i] = p + l
+	}
+	return out
+}
+
+// diffSidePath extracts the path from a "--- a/<path>" or "+++ b/<path>" line
+// remainder. "/dev/null" yields "".
+func diffSidePath(s, prefix string) string {
+	p := unquotePath(s)
+	if p == "/dev/null" {
+		return ""
+	}
+	return strings.TrimPrefix(p, prefix)
+}
+
+// unquotePath trims the line ending and undoes git's C-style quoting of paths
+// with unusual characters.
+func unquotePath(s string) string {
+	p := strings.TrimRight(s, "\r\n")
+	if strings.HasPrefix(p, `"`) {
+		if unq, err := strconv.Unquote(p); err == nil {
+			return unq
+		}
+	}
+	return p
+}
+
+// stampPatchFiles rewrites each patch file in dir in place, in order, through st.
+func stampPatchFiles(st *stamper, dir string, files []string) error {
+	for _, f := range files {
+		path := filepath.Join(dir, f)
+		data, err := os.ReadFile(path) //nolint:gosec // G304: patch dir we created
+		if err != nil {
+			return fmt.Errorf("read patch %s: %w", f, err)
+		}
+		if err := fileutil.WriteFile(path, st.stamp(data), 0600); err != nil {
+			return fmt.Errorf("write patch %s: %w", f, err)
+		}
+	}
+	return nil
+}
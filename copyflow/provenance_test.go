@@ -0,0 +1,218 @@
+// ABOUTME: Tests for provenance headers: per-language comment syntax, preamble
+// ABOUTME: handling and skips, hunk shifting for stamped files, and git am.
+
+package copyflow
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kstenerud/yoloai/internal/git"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testProvenance = Provenance{
+	Tool:    "claude",
+	Model:   "opus",
+	Sandbox: "mybox",
+	Date:    time.Date(2026, 3, 4, 12, 0, 0, 0, time.UTC),
+}
+
+const testProvenanceText = "yoloai-provenance: generated by claude (model opus) in yoloai sandbox mybox on 2026-03-04"
+
+// newFilePatch builds a git diff that creates path with the given lines.
+func newFilePatch(path string, lines ...string) string {
+	var b strings.Builder
+	b.WriteString("diff --git a/" + path + " b/" + path + "\n")
+	b.WriteString("new file mode 100644\n")
+	b.WriteString("index 0000000..1111111\n")
+	b.WriteString("--- /dev/null\n")
+	b.WriteString("+++ b/" + path + "\n")
+	if len(lines) == 1 {
+		b.WriteString("@@ -0,0 +1 @@\n")
+	} else {
+		b.WriteString("@@ -0,0 +1," + strconv.Itoa(len(lines)) + " @@\n")
+	}
+	for _, l := range lines {
+		b.WriteString("+" + l + "\n")
+	}
+	return b.String()
+}
+
+func TestStamper_LineComment(t *testing.T) {
+	got := string(newStamper(testProvenance, "").stamp([]byte(newFilePatch("main.go", "package main", "", "func main() {}"))))
+
+	assert.Contains(t, got, "@@ -0,0 +1,5 @@\n+// "+testProvenanceText+"\n+\n+package main\n")
+}
+
+func TestStamper_SingleLineHunk(t *testing.T) {
+	got := string(newStamper(testProvenance, "").stamp([]byte(newFilePatch("run.py", "print('hi')"))))
+
+	assert.Contains(t, got, "@@ -0,0 +1,3 @@\n+# "+testProvenanceText+"\n+\n+print('hi')\n")
+}
+
+func TestStamper_BlockComment(t *testing.T) {
+	got := string(newStamper(testProvenance, "").stamp([]byte(newFilePatch("index.html", "<p>hi</p>"))))
+
+	assert.Contains(t, got, "+<!-- "+testProvenanceText+" -->\n")
+}
+
+func TestStamper_KnownFilename(t *testing.T) {
+	got := string(newStamper(testProvenance, "").stamp([]byte(newFilePatch("build/Dockerfile", "FROM alpine"))))
+
+	assert.Contains(t, got, "+# "+testProvenanceText+"\n")
+}
+
+func TestStamper_KeepsShebangFirst(t *testing.T) {
+	got := string(newStamper(testProvenance, "").stamp([]byte(newFilePatch("run.sh", "#!/bin/sh", "echo hi"))))
+
+	assert.Contains(t, got, "@@ -0,0 +1,4 @@\n+#!/bin/sh\n+# "+testProvenanceText+"\n+\n+echo hi\n")
+}
+
+func TestStamper_PreservesCRLF(t *testing.T) {
+	patch := strings.Replace(newFilePatch("a.ts", "x", "y"), "+x\n+y\n", "+x\r\n+y\r\n", 1)
+	got := string(newStamper(testProvenance, "").stamp([]byte(patch)))
+
+	assert.Contains(t, got, "+// "+testProvenanceText+"\r\n+\r\n+x\r\n")
+}
+
+func TestStamper_LeavesOtherFilesAlone(t *testing.T) {
+	modified := "diff --git a/old.go b/old.go\n" +
+		"index 1111111..2222222 100644\n" +
+		"--- a/old.go\n" +
+		"+++ b/old.go\n" +
+		"@@ -1 +1 @@\n" +
+		"-package old\n" +
+		"+package renamed\n"
+	cases := map[string]string{
+		"unknown extension": newFilePatch("data.json", "{}"),
+		"modified file":     modified,
+		"already stamped":   newFilePatch("a.go", "// "+testProvenanceText, "package a"),
+		"lone shebang":      newFilePatch("x.sh", "#!/bin/sh") + "\\ No newline at end of file\n",
+	}
+	for name, patch := range cases {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, patch, string(newStamper(testProvenance, "").stamp([]byte(patch))))
+		})
+	}
+}
+
+func TestStamper_OmitsUnknownModel(t *testing.T) {
+	p := testProvenance
+	p.Model = ""
+	got := string(newStamper(p, "").stamp([]byte(newFilePatch("a.go", "package a"))))
+
+	assert.Contains(t, got, "generated by claude in yoloai sandbox mybox")
+}
+
+// A file created in one commit and edited in the next must still apply once
+// the first commit's patch has grown a header.
+func TestStampPatchFiles_SeriesApplies(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Setenv("HOME", tmpDir)
+
+	createCopySandboxWithCommits(t, tmpDir, "test-prov", "/tmp/project", []struct {
+		subject  string
+		filename string
+		content  string
+	}{
+		{"add main", "main.go", "package main\n\nfunc main() {}\n"},
+		{"edit main", "main.go", "package main\n\nfunc main() { println(1) }\n"},
+	})
+
+	rt := hostGitRuntime()
+	patchDir, files, err := GenerateFormatPatch(context.Background(), testLayout(tmpDir), rt, "test-prov", "", nil)
+	require.NoError(t, err)
+	defer os.RemoveAll(patchDir) //nolint:errcheck
+	require.NoError(t, stampPatchFiles(newStamper(testProvenance, ""), patchDir, files))
+
+	targetDir := filepath.Join(tmpDir, "target-prov")
+	require.NoError(t, os.MkdirAll(targetDir, 0750))
+	initGitRepo(t, targetDir)
+	writeTestFile(t, targetDir, "file.txt", "original content\n")
+	gitAdd(t, targetDir, ".")
+	gitCommit(t, targetDir, "initial")
+
+	_, err = git.NewTestHostWithEnv(testEnv()).ApplyFormatPatch(context.Background(), patchDir, files, targetDir)
+	require.NoError(t, err)
+
+	content, err := os.ReadFile(filepath.Join(targetDir, "main.go")) //nolint:gosec
+	require.NoError(t, err)
+	assert.Equal(t, "// "+testProvenanceText+"\n\npackage main\n\nfunc main() { println(1) }\n", string(content))
+}
+
+// applyStamped stamps patch against the host directory dir and applies it
+// there with git apply, returning the resulting content of path.
+func applyStamped(t *testing.T, dir, patch, path string, p Provenance) string {
+	t.Helper()
+	stamped := newStamper(p, dir).stamp([]byte(patch))
+	require.NoError(t, git.NewTestHostWithEnv(testEnv()).ApplyPatch(context.Background(), stamped, dir, false))
+	content, err := os.ReadFile(filepath.Join(dir, path)) //nolint:gosec // G304: test file path
+	if os.IsNotExist(err) {
+		return ""
+	}
+	require.NoError(t, err)
+	return string(content)
+}
+
+// An earlier apply stamped the host file; the sandbox's copy never had the
+// header, so an edit near the top must be shifted past it.
+func TestStamper_ShiftsEditsOfHostStampedFile(t *testing.T) {
+	dir := t.TempDir()
+	hdr := "// " + testProvenanceText + "\n\n"
+	writeTestFile(t, dir, "main.go", hdr+"package main\n\nfunc main() {}\n")
+
+	patch := "diff --git a/main.go b/main.go\n" +
+		"--- a/main.go\n" +
+		"+++ b/main.go\n" +
+		"@@ -1,3 +1,3 @@\n" +
+		" package main\n" +
+		" \n" +
+		"-func main() {}\n" +
+		"+func main() { println(1) }\n"
+
+	got := applyStamped(t, dir, patch, "main.go", Provenance{Skip: true})
+	assert.Equal(t, hdr+"package main\n\nfunc main() { println(1) }\n", got)
+}
+
+func TestStamper_KeepsHeaderAfterHostPreamble(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, dir, "run.sh", "#!/bin/sh\n# "+testProvenanceText+"\n\necho a\n")
+
+	patch := "diff --git a/run.sh b/run.sh\n" +
+		"--- a/run.sh\n" +
+		"+++ b/run.sh\n" +
+		"@@ -1,2 +1,3 @@\n" +
+		" #!/bin/sh\n" +
+		"+set -e\n" +
+		" echo a\n"
+
+	got := applyStamped(t, dir, patch, "run.sh", testProvenance)
+	assert.Equal(t, "#!/bin/sh\n# "+testProvenanceText+"\n\nset -e\necho a\n", got)
+}
+
+func TestStamper_DeletesHostStampedFile(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, dir, "old.py", "# "+testProvenanceText+"\n\nprint(1)\n")
+
+	patch := "diff --git a/old.py b/old.py\n" +
+		"deleted file mode 100644\n" +
+		"--- a/old.py\n" +
+		"+++ /dev/null\n" +
+		"@@ -1 +0,0 @@\n" +
+		"-print(1)\n"
+
+	assert.Empty(t, applyStamped(t, dir, patch, "old.py", testProvenance))
+	assert.NoFileExists(t, filepath.Join(dir, "old.py"))
+}
+
+func TestStamper_SkipLeavesCreatedFilesUnstamped(t *testing.T) {
+	patch := newFilePatch("main.go", "package main")
+	assert.Equal(t, patch, string(newStamper(Provenance{Skip: true}, "").stamp([]byte(patch))))
+}
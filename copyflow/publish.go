@@ -0,0 +1,281 @@
+// ABOUTME: Publish mirrors a sandbox's commits to a scratch ref of a remote
+// ABOUTME: (refs/yoloai/<name>) without touching the host checkout.
+
+package copyflow
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/kstenerud/yoloai/internal/config"
+	"github.com/kstenerud/yoloai/internal/git"
+	"github.com/kstenerud/yoloai/runtime"
+	"github.com/kstenerud/yoloai/store"
+	"github.com/kstenerud/yoloai/yoerrors"
+)
+
+// PublishRefPrefix is the remote namespace Publish pushes to by default, one
+// ref per sandbox. Outside refs/heads, so a mirror never shows up as a branch
+// in the remote's UI or in a teammate's `git fetch` unless asked for.
+const PublishRefPrefix = "refs/yoloai/"
+
+// PublishOptions configures Publish.
+type PublishOptions struct {
+	// DirHostPath selects the directory to publish; "" selects Dirs[0] (workdir).
+	DirHostPath string
+	// Remote is where to push: a remote name of the host repository or a URL.
+	// "" is the host repository's origin, falling back to the origin recorded
+	// when the sandbox was created.
+	Remote string
+	// Ref is the ref pushed to; "" is PublishRefPrefix + the sandbox name.
+	Ref string
+}
+
+// PublishResult reports what Publish pushed.
+type PublishResult struct {
+	// Remote is the URL pushed to.
+	Remote string
+	// Ref is the remote ref that now holds the commits.
+	Ref string
+	// Commits is the number of beyond-baseline commits published.
+	Commits int
+	// SourceHead is the sandbox commit the mirror reflects.
+	SourceHead string
+	// PublishedSHA is the commit Ref points at. The commits are replayed onto
+	// the baseline, so its SHA differs from SourceHead.
+	PublishedSHA string
+}
+
+// Publish pushes the sandbox's beyond-baseline commits to opts.Ref of a remote
+// as an off-machine mirror of the agent's progress. The commits are replayed
+// (format-patch → git am, as for ApplySeries) onto the baseline in a throwaway
+// clone that shares the host repository's objects, then force-pushed: the
+// mirror always reflects the sandbox as it is now, even after a rewind. The
+// force is leased (see publishLease): only a ref this sandbox owns is
+// overwritten, never the remote's default branch or someone else's. The
+// host checkout, its branches and the baseline are left alone, and only
+// committed work travels.
+//
+// Returns (nil, nil) when there are no commits to publish, and a *UsageError
+// when the host directory isn't a git repository or no remote can be found.
+func Publish(ctx context.Context, layout config.Layout, rt runtime.Backend, name string, opts PublishOptions) (*PublishResult, error) {
+	meta, err := store.LoadEnvironment(layout.SandboxDir(name))
+	if err != nil {
+		return nil, err
+	}
+	dir := meta.Dir(opts.DirHostPath)
+	if dir == nil {
+		return nil, yoerrors.NewUsageError("directory not found in sandbox")
+	}
+	if dir.Mode != store.DirModeCopy {
+		return nil, yoerrors.NewUsageError("publish mirrors the commits of a :copy directory; %s is :%s", dir.HostPath, dir.Mode)
+	}
+
+	commits, err := ListCommitsBeyondBaseline(ctx, layout, rt, name, opts.DirHostPath)
+	if err != nil {
+		return nil, err
+	}
+	if len(commits) == 0 {
+		return nil, nil
+	}
+
+	hostGit := git.NewHost(layout)
+	remote, err := publishRemote(ctx, hostGit, dir, opts.Remote)
+	if err != nil {
+		return nil, err
+	}
+	if !git.IsGitRepo(dir.HostPath) {
+		return nil, yoerrors.NewUsageError("cannot publish from %s: not a git repository, so there is nothing to replay the commits onto; export them with apply --patches instead", dir.HostPath)
+	}
+	// Replay onto the baseline when the host has it, so the mirror is the
+	// agent's history on top of what it started from; otherwise (stripped
+	// history, or a baseline yoloai committed over uncommitted host changes)
+	// onto the host's HEAD, where an apply would land them.
+	base := dir.BaselineSHA
+	if base == "" || !hostGit.HasCommit(ctx, dir.HostPath, base) {
+		if base, err = hostGit.HeadSHA(ctx, dir.HostPath); err != nil {
+			return nil, err
+		}
+	}
+	ref := opts.Ref
+	if ref == "" {
+		ref = PublishRefPrefix + name
+	} else if !strings.HasPrefix(ref, "refs/") {
+		return nil, yoerrors.NewUsageError("publish needs a full ref name such as refs/yoloai/%s: %q", name, ref)
+	}
+
+	lease, err := publishLease(ctx, hostGit, dir, remote, ref)
+	if err != nil {
+		return nil, err
+	}
+
+	patchDir, files, err := GenerateFormatPatch(ctx, layout, rt, name, opts.DirHostPath, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(patchDir) //nolint:errcheck // best-effort cleanup
+
+	scratch, err := os.MkdirTemp("", "yoloai-publish-")
+	if err != nil {
+		return nil, fmt.Errorf("create publish clone: %w", err)
+	}
+	defer os.RemoveAll(scratch) //nolint:errcheck // best-effort cleanup
+
+	// --shared borrows the host repository's objects instead of copying them,
+	// so the clone is instant however large the history.
+	if err := hostGit.RunCmd(ctx, dir.HostPath, "clone", "--quiet", "--shared", "--no-checkout", dir.HostPath, scratch); err != nil {
+		return nil, fmt.Errorf("clone %s: %w", dir.HostPath, err)
+	}
+	if err := hostGit.RunCmd(ctx, scratch, "reset", "--quiet", "--hard", base); err != nil {
+		return nil, err
+	}
+	if _, err := hostGit.ApplyFormatPatch(ctx, patchDir, files, scratch); err != nil {
+		return nil, fmt.Errorf("replay commits: %w", err)
+	}
+	head, err := hostGit.HeadSHA(ctx, scratch)
+	if err != nil {
+		return nil, err
+	}
+	// The lease makes the push fail if the ref moved since publishLease looked.
+	if err := hostGit.RunCmd(ctx, scratch, "push", "--quiet", "--force-with-lease="+ref+":"+lease, remote, "HEAD:"+ref); err != nil {
+		return nil, fmt.Errorf("push to %s %s: %w", remote, ref, err)
+	}
+	if err := recordPublished(layout, name, dir.HostPath, remote, ref, head); err != nil {
+		return nil, err
+	}
+	return &PublishResult{
+		Remote:       remote,
+		Ref:          ref,
+		Commits:      len(commits),
+		SourceHead:   commits[len(commits)-1].SHA,
+		PublishedSHA: head,
+	}, nil
+}
+
+// Unpublish deletes opts.Ref (PublishRefPrefix + the sandbox name by default)
+// from the remote opts.Remote resolves to, as Publish would. Whether deleting
+// a ref that isn't there fails is up to the remote's git.
+func Unpublish(ctx context.Context, layout config.Layout, name string, opts PublishOptions) error {
+	meta, err := store.LoadEnvironment(layout.SandboxDir(name))
+	if err != nil {
+		return err
+	}
+	dir := meta.Dir(opts.DirHostPath)
+	if dir == nil {
+		return yoerrors.NewUsageError("directory not found in sandbox")
+	}
+	if !git.IsGitRepo(dir.HostPath) {
+		return yoerrors.NewUsageError("cannot unpublish from %s: not a git repository", dir.HostPath)
+	}
+	hostGit := git.NewHost(layout)
+	remote, err := publishRemote(ctx, hostGit, dir, opts.Remote)
+	if err != nil {
+		return err
+	}
+	ref := opts.Ref
+	if ref == "" {
+		ref = PublishRefPrefix + name
+	}
+	if err := hostGit.RunCmd(ctx, dir.HostPath, "push", "--quiet", remote, "--delete", ref); err != nil {
+		return fmt.Errorf("delete %s from %s: %w", ref, remote, err)
+	}
+	return recordPublished(layout, name, dir.HostPath, remote, ref, "")
+}
+
+// publishLease checks that Publish may overwrite ref on remote and returns
+// the commit the ref must still hold when the push lands ("" for "must not
+// exist"), for --force-with-lease. It refuses the remote's default branch,
+// a branch that exists but that this sandbox never published, and a ref that
+// moved since this sandbox last pushed it. Under refs/yoloai/, yoloai's own
+// namespace, an existing ref with no record (a mirror published before the
+// record was kept) is taken over.
+func publishLease(ctx context.Context, hostGit *git.Git, dir *store.DirEnvironment, remote, ref string) (string, error) {
+	out, err := hostGit.Run(ctx, dir.HostPath, "ls-remote", "--symref", remote, "HEAD", ref)
+	if err != nil {
+		return "", fmt.Errorf("read %s from %s: %w", ref, remote, err)
+	}
+	var current, defaultBranch string
+	for _, line := range strings.Split(out, "\n") {
+		fields := strings.Fields(line)
+		switch {
+		case len(fields) == 3 && fields[0] == "ref:" && fields[2] == "HEAD":
+			defaultBranch = fields[1]
+		case len(fields) == 2 && fields[1] == ref:
+			current = fields[0]
+		}
+	}
+	if ref == defaultBranch {
+		return "", yoerrors.NewUsageError("refusing to publish to %s, the default branch of %s; pick another branch", ref, remote)
+	}
+	recorded := dir.PublishedRefs[publishedKey(remote, ref)]
+	switch {
+	case current == "" || current == recorded:
+		return current, nil
+	case recorded != "":
+		return "", yoerrors.NewUsageError("%s of %s has changed since this sandbox last published it (someone else pushed to it); refusing to overwrite it", ref, remote)
+	case strings.HasPrefix(ref, PublishRefPrefix):
+		return current, nil
+	default:
+		return "", yoerrors.NewUsageError("%s already exists on %s and was not published from this sandbox; refusing to overwrite it, pick another branch", ref, remote)
+	}
+}
+
+// publishedKey is the DirEnvironment.PublishedRefs key of ref on remote.
+func publishedKey(remote, ref string) string {
+	return remote + " " + ref
+}
+
+// recordPublished stores sha as what this sandbox last pushed to ref on
+// remote, or forgets the ref when sha is "".
+func recordPublished(layout config.Layout, name, hostPath, remote, ref, sha string) error {
+	unlock, err := store.AcquireLock(layout, name)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	sandboxDir := layout.SandboxDir(name)
+	meta, err := store.LoadEnvironment(sandboxDir)
+	if err != nil {
+		return err
+	}
+	dir := meta.Dir(hostPath)
+	if dir == nil {
+		return fmt.Errorf("directory %q not found in sandbox %q", hostPath, name)
+	}
+	key := publishedKey(remote, ref)
+	if sha == "" {
+		delete(dir.PublishedRefs, key)
+	} else {
+		if dir.PublishedRefs == nil {
+			dir.PublishedRefs = map[string]string{}
+		}
+		dir.PublishedRefs[key] = sha
+	}
+	return store.SaveEnvironment(sandboxDir, meta)
+}
+
+// publishRemote resolves PublishOptions.Remote to a URL. A name is looked up
+// among the host repository's remotes and anything else is taken as a URL;
+// no name falls back from the host's origin to the one recorded at create.
+func publishRemote(ctx context.Context, hostGit *git.Git, dir *store.DirEnvironment, remote string) (string, error) {
+	isRepo := git.IsGitRepo(dir.HostPath)
+	if remote != "" {
+		if isRepo {
+			if url := hostGit.RemoteURL(ctx, dir.HostPath, remote); url != "" {
+				return url, nil
+			}
+		}
+		return remote, nil
+	}
+	if isRepo {
+		if url := hostGit.RemoteURL(ctx, dir.HostPath, "origin"); url != "" {
+			return url, nil
+		}
+	}
+	if dir.SourceRemote != "" {
+		return dir.SourceRemote, nil
+	}
+	return "", yoerrors.NewUsageError("%s has no origin remote to publish to; name one with --remote", dir.HostPath)
+}
@@ -0,0 +1,146 @@
+// ABOUTME: Tests for Publish and Unpublish: the commits reach refs/yoloai/<name> of the
+// ABOUTME: remote, the host stays untouched, and branches yoloai doesn't own are refused.
+
+package copyflow
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kstenerud/yoloai/internal/git"
+	"github.com/kstenerud/yoloai/yoerrors"
+)
+
+func TestPublish_MirrorsCommitsToScratchRef(t *testing.T) {
+	name := "publish-box"
+	tmpDir, upstream, host := pushBranchSandbox(t, name)
+	rt := hostGitRuntime()
+	layout := testLayout(tmpDir).WithEnv(map[string]string{"HOME": tmpDir, "PATH": os.Getenv("PATH")})
+	hostHead := gitHEAD(t, host)
+
+	result, err := Publish(context.Background(), layout, rt, name, PublishOptions{})
+	require.NoError(t, err)
+	require.NotNil(t, result)
+	assert.Equal(t, "refs/yoloai/"+name, result.Ref)
+	assert.Equal(t, upstream, result.Remote)
+	assert.Equal(t, 2, result.Commits)
+	assert.Equal(t, result.PublishedSHA+" add B", upstreamRev(t, upstream, "refs/yoloai/"+name))
+
+	assert.Equal(t, hostHead, gitHEAD(t, host), "the host checkout is left alone")
+	assert.NoFileExists(t, filepath.Join(host, "b.txt"))
+	remaining, err := ListCommitsBeyondBaseline(context.Background(), testLayout(tmpDir), rt, name, "")
+	require.NoError(t, err)
+	assert.Len(t, remaining, 2, "publishing doesn't advance the baseline")
+
+	// A second publish replaces the mirror (force push), so it works again
+	// even though the replayed commits get new SHAs.
+	again, err := Publish(context.Background(), layout, rt, name, PublishOptions{Ref: "refs/yoloai/custom"})
+	require.NoError(t, err)
+	assert.Equal(t, again.PublishedSHA+" add B", upstreamRev(t, upstream, "refs/yoloai/custom"))
+	_, err = Publish(context.Background(), layout, rt, name, PublishOptions{})
+	require.NoError(t, err)
+}
+
+func TestPublish_RemoteByURL(t *testing.T) {
+	name := "publish-url"
+	tmpDir, _, _ := pushBranchSandbox(t, name)
+	rt := hostGitRuntime()
+	layout := testLayout(tmpDir).WithEnv(map[string]string{"HOME": tmpDir, "PATH": os.Getenv("PATH")})
+
+	backup := filepath.Join(tmpDir, "backup")
+	require.NoError(t, os.MkdirAll(backup, 0o750))
+	initGitRepo(t, backup)
+
+	result, err := Publish(context.Background(), layout, rt, name, PublishOptions{Remote: backup})
+	require.NoError(t, err)
+	assert.Equal(t, backup, result.Remote)
+	assert.Equal(t, result.PublishedSHA+" add B", upstreamRev(t, backup, "refs/yoloai/"+name))
+}
+
+func TestPublish_HostNotARepo(t *testing.T) {
+	name := "publish-norepo"
+	tmpDir := t.TempDir()
+	t.Setenv("HOME", tmpDir)
+	host := filepath.Join(tmpDir, "plain")
+	require.NoError(t, os.MkdirAll(host, 0o750))
+	createCopySandboxWithCommits(t, tmpDir, name, host, []struct {
+		subject  string
+		filename string
+		content  string
+	}{
+		{"add A", "a.txt", "a\n"},
+	})
+	rt := hostGitRuntime()
+
+	var usage *yoerrors.UsageError
+	_, err := Publish(context.Background(), testLayout(tmpDir), rt, name, PublishOptions{})
+	assert.ErrorAs(t, err, &usage, "no origin to publish to")
+	_, err = Publish(context.Background(), testLayout(tmpDir), rt, name, PublishOptions{Remote: filepath.Join(tmpDir, "backup")})
+	assert.ErrorAs(t, err, &usage, "nothing to replay onto")
+}
+
+func TestPublish_NothingToPublish(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Setenv("HOME", tmpDir)
+	createCopySandbox(t, tmpDir, "publish-empty", "/tmp/project")
+
+	result, err := Publish(context.Background(), testLayout(tmpDir), hostGitRuntime(), "publish-empty", PublishOptions{})
+	require.NoError(t, err)
+	assert.Nil(t, result)
+}
+
+func TestUnpublish_DeletesRef(t *testing.T) {
+	name := "unpublish-box"
+	tmpDir, upstream, _ := pushBranchSandbox(t, name)
+	layout := testLayout(tmpDir).WithEnv(map[string]string{"HOME": tmpDir, "PATH": os.Getenv("PATH")})
+	opts := PublishOptions{Ref: "refs/heads/yoloai-ci/" + name}
+
+	_, err := Publish(context.Background(), layout, hostGitRuntime(), name, opts)
+	require.NoError(t, err)
+	require.NoError(t, Unpublish(context.Background(), layout, name, opts))
+
+	_, err = git.NewTestHostWithEnv(testEnv()).Run(context.Background(), upstream, "rev-parse", "--verify", "--quiet", opts.Ref)
+	assert.Error(t, err, "the ref is gone from the remote")
+}
+
+// Publish overwrites only a ref it owns: the remote's default branch, a branch
+// someone else created, and one pushed to since the sandbox last published
+// are refused, while republishing its own branch works.
+func TestPublish_RefusesRefsItDoesNotOwn(t *testing.T) {
+	name := "publish-owned"
+	tmpDir, upstream, _ := pushBranchSandbox(t, name)
+	rt := hostGitRuntime()
+	layout := testLayout(tmpDir).WithEnv(map[string]string{"HOME": tmpDir, "PATH": os.Getenv("PATH")})
+	g := git.NewTestHostWithEnv(testEnv())
+	out, err := g.Run(context.Background(), upstream, "symbolic-ref", "HEAD")
+	require.NoError(t, err)
+	defaultBranch := strings.TrimSpace(out)
+
+	var usage *yoerrors.UsageError
+	_, err = Publish(context.Background(), layout, rt, name, PublishOptions{Ref: defaultBranch})
+	require.ErrorAs(t, err, &usage)
+	assert.Contains(t, err.Error(), "default branch")
+
+	require.NoError(t, g.RunCmd(context.Background(), upstream, "branch", "teammate"))
+	_, err = Publish(context.Background(), layout, rt, name, PublishOptions{Ref: "refs/heads/teammate"})
+	require.ErrorAs(t, err, &usage)
+	assert.Contains(t, err.Error(), "was not published from this sandbox")
+
+	own := PublishOptions{Ref: "refs/heads/yoloai/" + name}
+	_, err = Publish(context.Background(), layout, rt, name, own)
+	require.NoError(t, err)
+	_, err = Publish(context.Background(), layout, rt, name, own)
+	require.NoError(t, err, "republishing its own branch is allowed")
+
+	// Someone else moves the branch: the next publish leaves it alone.
+	require.NoError(t, g.RunCmd(context.Background(), upstream, "update-ref", own.Ref, "HEAD"))
+	_, err = Publish(context.Background(), layout, rt, name, own)
+	require.ErrorAs(t, err, &usage)
+	assert.Contains(t, err.Error(), "has changed since this sandbox last published it")
+}
@@ -0,0 +1,80 @@
+// ABOUTME: Tests for push-branch applies: the landed series reaches a branch of
+// ABOUTME: origin, and a failed push still reports the commits that landed.
+
+package copyflow
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kstenerud/yoloai/internal/git"
+)
+
+func upstreamRev(t *testing.T, upstream, rev string) string {
+	t.Helper()
+	out, err := git.NewTestHostWithEnv(testEnv()).Run(context.Background(), upstream, "log", "-1", "--format=%H %s", rev)
+	require.NoError(t, err)
+	return strings.TrimSpace(out)
+}
+
+func pushBranchSandbox(t *testing.T, name string) (tmpDir, upstream, host string) {
+	t.Helper()
+	tmpDir = t.TempDir()
+	t.Setenv("HOME", tmpDir)
+	upstream, host, _ = setupOrigin(t, tmpDir)
+	createCopySandboxWithCommits(t, tmpDir, name, host, []struct {
+		subject  string
+		filename string
+		content  string
+	}{
+		{"add A", "a.txt", "a\n"},
+		{"add B", "b.txt", "b\n"},
+	})
+	writeTestFile(t, tmpDir, ".gitconfig", "[user]\n\tname = Test\n\temail = test@example.com\n")
+	return tmpDir, upstream, host
+}
+
+// TestApplySeries_PushBranch lands the series in the target and pushes it to a
+// new branch of origin; the baseline advances as for any full apply.
+func TestApplySeries_PushBranch(t *testing.T) {
+	name := "series-push"
+	tmpDir, upstream, host := pushBranchSandbox(t, name)
+	rt := hostGitRuntime()
+	layout := testLayout(tmpDir).WithEnv(map[string]string{"HOME": tmpDir, "PATH": os.Getenv("PATH")})
+
+	result, err := ApplySeries(context.Background(), layout, rt, name, ApplySeriesOptions{PushBranch: "agent-work"})
+	require.NoError(t, err)
+	require.NotNil(t, result)
+	assert.Equal(t, "agent-work", result.PushedBranch)
+	require.Len(t, result.Commits, 2)
+
+	assert.Equal(t, gitHEAD(t, host)+" add B", upstreamRev(t, upstream, "refs/heads/agent-work"))
+
+	remaining, err := ListCommitsBeyondBaseline(context.Background(), testLayout(tmpDir), rt, name, "")
+	require.NoError(t, err)
+	assert.Empty(t, remaining)
+}
+
+// A push that fails (here: origin is gone) still returns the commits that
+// landed locally, with the push error alongside.
+func TestApplySeries_PushBranchFailureKeepsResult(t *testing.T) {
+	name := "series-push-fail"
+	tmpDir, upstream, host := pushBranchSandbox(t, name)
+	require.NoError(t, os.RemoveAll(upstream))
+	rt := hostGitRuntime()
+	layout := testLayout(tmpDir).WithEnv(map[string]string{"HOME": tmpDir, "PATH": os.Getenv("PATH")})
+
+	result, err := ApplySeries(context.Background(), layout, rt, name, ApplySeriesOptions{PushBranch: "agent-work"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "push to branch agent-work")
+	require.NotNil(t, result)
+	assert.Empty(t, result.PushedBranch)
+	assert.Len(t, result.Commits, 2)
+	assert.FileExists(t, filepath.Join(host, "b.txt"))
+}
@@ -0,0 +1,84 @@
+// ABOUTME: Conflicted net-diff applies: ApplyConflictError, and landing the
+// ABOUTME: hunks that fit while leaving the rest in .rej files to resolve by hand.
+
+package copyflow
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/kstenerud/yoloai/internal/git"
+	"github.com/kstenerud/yoloai/yoerrors"
+)
+
+// ApplyConflictError is returned by ApplyAll when the patch doesn't apply
+// cleanly to Dir, so nothing was applied. ApplyAllOptions.Reject applies what
+// does fit instead. Match it with errors.As.
+type ApplyConflictError struct {
+	Dir string
+	Err error
+}
+
+func (e *ApplyConflictError) Error() string { return e.Err.Error() }
+
+func (e *ApplyConflictError) Unwrap() error { return e.Err }
+
+// applyWithRejects applies patch to hostPath one file at a time with
+// `git apply --reject`, and returns the .rej files left for what didn't fit,
+// as absolute paths in patch order. A file git couldn't patch at all gets its
+// whole diff as its .rej, so every unapplied change is accounted for in the
+// same way. Refuses up front if any of those .rej files already exists: a
+// leftover from an earlier conflict must not be mistaken for (or overwritten
+// by) this one.
+func applyWithRejects(ctx context.Context, hostGit *git.Git, patch []byte, hostPath string, isGit bool) ([]string, error) {
+	files := ParsePatch(patch)
+	for _, f := range files {
+		// The patch comes out of the sandbox; a path leaving the target is
+		// refused by git on apply, and must not steer the .rej written below.
+		if !filepath.IsLocal(f.Path) {
+			return nil, fmt.Errorf("patch path %q is outside %s", f.Path, hostPath)
+		}
+		rej := filepath.Join(hostPath, f.Path+".rej")
+		if _, err := os.Lstat(rej); err == nil {
+			return nil, yoerrors.NewUsageError("%s is left over from an earlier conflict: resolve it and delete it, then apply again", rej)
+		}
+	}
+
+	root, err := os.OpenRoot(hostPath)
+	if err != nil {
+		return nil, fmt.Errorf("open %s: %w", hostPath, err)
+	}
+	defer root.Close() //nolint:errcheck // read-only handle
+
+	var rejects []string
+	for _, f := range files {
+		one, err := AssemblePatch([]PatchFile{f})
+		if err != nil {
+			return rejects, err
+		}
+		applyErr := hostGit.ApplyPatchReject(ctx, one, hostPath, isGit)
+		if applyErr == nil {
+			continue
+		}
+		if ctx.Err() != nil {
+			return rejects, ctx.Err()
+		}
+		rej := f.Path + ".rej"
+		if _, err := root.Lstat(rej); errors.Is(err, fs.ErrNotExist) {
+			// Through the root, so a symlinked directory in the target can't
+			// carry the write outside it.
+			if err := root.MkdirAll(filepath.Dir(rej), 0o750); err != nil {
+				return rejects, fmt.Errorf("%s: %w (apply: %w)", rej, err, applyErr)
+			}
+			if err := root.WriteFile(rej, one, 0o644); err != nil { //nolint:gosec // G306: sits beside the user's own files
+				return rejects, fmt.Errorf("%s: %w (apply: %w)", rej, err, applyErr)
+			}
+		}
+		rejects = append(rejects, filepath.Join(hostPath, rej))
+	}
+	return rejects, nil
+}
@@ -0,0 +1,106 @@
+// ABOUTME: Tests for conflicted net-diff applies: the typed conflict error, and
+// ABOUTME: Reject landing what fits while leaving .rej files for the rest.
+
+package copyflow
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kstenerud/yoloai/yoerrors"
+)
+
+// conflictSandbox sets up a sandbox whose commit rewrites file.txt and adds
+// new.txt, applying to a host repo where file.txt was edited differently.
+func conflictSandbox(t *testing.T, name string) (tmpDir, host string) {
+	t.Helper()
+	tmpDir = t.TempDir()
+	t.Setenv("HOME", tmpDir)
+	host = filepath.Join(tmpDir, "host")
+	require.NoError(t, os.MkdirAll(host, 0750))
+	initGitRepo(t, host)
+	writeTestFile(t, host, "file.txt", "original content\n")
+	gitAdd(t, host, ".")
+	gitCommit(t, host, "initial")
+	writeTestFile(t, host, "file.txt", "user version\n")
+
+	createCopySandboxWithCommits(t, tmpDir, name, host, []struct {
+		subject  string
+		filename string
+		content  string
+	}{
+		{"agent edits", "file.txt", "agent version\n"},
+		{"agent adds", "new.txt", "new\n"},
+	})
+	return tmpDir, host
+}
+
+func TestApplyAll_ConflictError(t *testing.T) {
+	tmpDir, host := conflictSandbox(t, "conflict")
+
+	_, err := ApplyAll(context.Background(), testLayout(tmpDir), hostGitRuntime(), "conflict", ApplyAllOptions{})
+	var conflict *ApplyConflictError
+	require.ErrorAs(t, err, &conflict)
+	assert.Equal(t, host, conflict.Dir)
+	assert.NoFileExists(t, filepath.Join(host, "new.txt"), "a conflicted apply lands nothing")
+}
+
+func TestApplyAll_Reject(t *testing.T) {
+	tmpDir, host := conflictSandbox(t, "reject")
+	layout := testLayout(tmpDir)
+
+	result, err := ApplyAll(context.Background(), layout, hostGitRuntime(), "reject", ApplyAllOptions{Reject: true})
+	require.NoError(t, err)
+	require.NotNil(t, result)
+	assert.Equal(t, []string{filepath.Join(host, "file.txt.rej")}, result.Rejects)
+
+	content, err := os.ReadFile(filepath.Join(host, "new.txt")) //nolint:gosec // G304: test file path
+	require.NoError(t, err)
+	assert.Equal(t, "new\n", string(content), "the change that fits lands")
+	content, err = os.ReadFile(filepath.Join(host, "file.txt")) //nolint:gosec // G304: test file path
+	require.NoError(t, err)
+	assert.Equal(t, "user version\n", string(content), "the conflicted file is left as it was")
+	rej, err := os.ReadFile(filepath.Join(host, "file.txt.rej")) //nolint:gosec // G304: test file path
+	require.NoError(t, err)
+	assert.Contains(t, string(rej), "+agent version")
+
+	remaining, err := ListCommitsBeyondBaseline(context.Background(), layout, hostGitRuntime(), "reject", "")
+	require.NoError(t, err)
+	assert.Len(t, remaining, 2, "the baseline waits for the rejects to be resolved")
+}
+
+func TestApplyAll_RejectWholeFile(t *testing.T) {
+	tmpDir, host := conflictSandbox(t, "reject-missing")
+	require.NoError(t, os.Remove(filepath.Join(host, "file.txt")))
+
+	result, err := ApplyAll(context.Background(), testLayout(tmpDir), hostGitRuntime(), "reject-missing", ApplyAllOptions{Reject: true})
+	require.NoError(t, err)
+	require.NotNil(t, result)
+	assert.Equal(t, []string{filepath.Join(host, "file.txt.rej")}, result.Rejects)
+
+	rej, err := os.ReadFile(filepath.Join(host, "file.txt.rej")) //nolint:gosec // G304: test file path
+	require.NoError(t, err)
+	assert.Contains(t, string(rej), "diff --git a/file.txt b/file.txt", "a file git can't patch keeps its whole diff")
+	assert.FileExists(t, filepath.Join(host, "new.txt"))
+}
+
+func TestApplyAll_RejectRefusals(t *testing.T) {
+	tmpDir, host := conflictSandbox(t, "reject-refuse")
+	layout := testLayout(tmpDir)
+	var usage *yoerrors.UsageError
+
+	writeTestFile(t, host, "file.txt.rej", "from last time\n")
+	_, err := ApplyAll(context.Background(), layout, hostGitRuntime(), "reject-refuse", ApplyAllOptions{Reject: true})
+	assert.ErrorAs(t, err, &usage, "a leftover .rej is never overwritten")
+	assert.NoFileExists(t, filepath.Join(host, "new.txt"))
+
+	_, err = ApplyAll(context.Background(), layout, hostGitRuntime(), "reject-refuse", ApplyAllOptions{Reject: true, DryRun: true})
+	assert.ErrorAs(t, err, &usage)
+	_, err = ApplyAll(context.Background(), layout, hostGitRuntime(), "reject-refuse", ApplyAllOptions{Reject: true, Branch: "b"})
+	assert.ErrorAs(t, err, &usage)
+}
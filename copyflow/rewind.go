@@ -0,0 +1,85 @@
+// ABOUTME: Rewinds a sandbox work copy to one of the agent's own commits,
+// ABOUTME: discarding the commits after it and leaving the baseline in place.
+package copyflow
+
+import (
+	"context"
+	"strings"
+
+	"github.com/kstenerud/yoloai/internal/config"
+	"github.com/kstenerud/yoloai/internal/git"
+	"github.com/kstenerud/yoloai/runtime"
+	"github.com/kstenerud/yoloai/store"
+	"github.com/kstenerud/yoloai/yoerrors"
+)
+
+// RewindResult reports the outcome of RewindToCommit: the commit the work
+// copy now sits on and the commits that were discarded, oldest first.
+type RewindResult struct {
+	SHA     string
+	Subject string
+	Dropped []CommitInfo
+}
+
+// RewindToCommit hard-resets the sandbox work copy to ref, which must resolve
+// to the diff baseline or to one of the commits ListCommitsBeyondBaseline
+// reports — the agent's own unapplied commits. Anything else (an applied
+// commit, a commit off the work copy's branch, a host-only SHA) is refused
+// with a *UsageError, so this can only discard work nobody has applied yet.
+// Uncommitted edits and untracked files are discarded too; ignored files stay.
+//
+// The baseline is left where it is: the target is the baseline or a
+// descendant of it, so baseline..HEAD stays well-formed and simply lists fewer
+// commits. The caller holds the per-sandbox lock.
+func RewindToCommit(ctx context.Context, layout config.Layout, rt runtime.Backend, name, dirHostPath, ref string) (*RewindResult, error) {
+	workDir, baselineSHA, mode, err := loadDiffContext(layout, name, dirHostPath)
+	if err != nil {
+		return nil, err
+	}
+	if mode != store.DirModeCopy {
+		return nil, yoerrors.NewUsageError("resetting to a commit is only available for :copy directories")
+	}
+
+	g := git.NewSandbox(layout, rt, name)
+	out, err := g.Run(ctx, workDir, "rev-parse", "--verify", "--quiet", ref+"^{commit}")
+	if err != nil {
+		return nil, yoerrors.NewUsageError("%q is not a commit in sandbox %s", ref, name)
+	}
+	sha := strings.TrimSpace(out)
+
+	commits, err := ListCommitsBeyondBaseline(ctx, layout, rt, name, dirHostPath)
+	if err != nil {
+		return nil, err
+	}
+	keep := -1 // baseline itself: drop every commit
+	if sha != baselineSHA {
+		keep = indexOfCommit(commits, sha)
+		if keep < 0 {
+			return nil, yoerrors.NewUsageError("%.12s is not one of the sandbox's unapplied commits — see 'yoloai baseline log %s'", sha, name)
+		}
+	}
+
+	if err := g.RunCmd(ctx, workDir, "reset", "--quiet", "--hard", sha); err != nil {
+		return nil, err
+	}
+	if err := g.RunCmd(ctx, workDir, "clean", "-f", "-d", "-q"); err != nil {
+		return nil, err
+	}
+
+	result := &RewindResult{SHA: sha, Dropped: commits[keep+1:]}
+	if subj, subjErr := g.Run(ctx, workDir, "log", "--format=%s", "-1", sha); subjErr == nil {
+		result.Subject = strings.TrimSpace(subj)
+	}
+	return result, nil
+}
+
+// indexOfCommit returns the index of the commit with the given full SHA, or
+// -1 when it isn't in the list.
+func indexOfCommit(commits []CommitInfo, sha string) int {
+	for i, c := range commits {
+		if strings.EqualFold(c.SHA, sha) {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,88 @@
+// ABOUTME: Tests for rewinding a work copy to one of the agent's commits: the
+// ABOUTME: dropped tail, the untouched baseline, and refusals of other commits.
+package copyflow
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/kstenerud/yoloai/internal/testutil"
+	"github.com/kstenerud/yoloai/store"
+	"github.com/kstenerud/yoloai/yoerrors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func threeAgentCommits(t *testing.T, tmpDir, name string) string {
+	t.Helper()
+	return createCopySandboxWithCommits(t, tmpDir, name, "/tmp/project", []struct {
+		subject  string
+		filename string
+		content  string
+	}{
+		{"add A", "a.txt", "a\n"},
+		{"add B", "b.txt", "b\n"},
+		{"add C", "c.txt", "c\n"},
+	})
+}
+
+func TestRewindToCommit_DropsLaterCommits(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Setenv("HOME", tmpDir)
+	name := "rewind"
+	workDir := threeAgentCommits(t, tmpDir, name)
+	writeTestFile(t, workDir, "wip.txt", "wip\n")
+	rt := hostGitRuntime()
+
+	commits, err := ListCommitsBeyondBaseline(context.Background(), testLayout(tmpDir), rt, name, "")
+	require.NoError(t, err)
+	require.Len(t, commits, 3)
+	meta, err := store.LoadEnvironment(filepath.Join(tmpDir, ".yoloai", "sandboxes", name))
+	require.NoError(t, err)
+	baseline := meta.Workdir().BaselineSHA
+
+	res, err := RewindToCommit(context.Background(), testLayout(tmpDir), rt, name, "", commits[0].SHA[:10])
+	require.NoError(t, err)
+	assert.Equal(t, commits[0].SHA, res.SHA)
+	assert.Equal(t, "add A", res.Subject)
+	assert.Equal(t, commits[1:], res.Dropped)
+
+	assert.FileExists(t, filepath.Join(workDir, "a.txt"))
+	assert.NoFileExists(t, filepath.Join(workDir, "b.txt"))
+	assert.NoFileExists(t, filepath.Join(workDir, "wip.txt"), "uncommitted work is discarded")
+
+	remaining, err := ListCommitsBeyondBaseline(context.Background(), testLayout(tmpDir), rt, name, "")
+	require.NoError(t, err)
+	assert.Equal(t, commits[:1], remaining)
+	meta, err = store.LoadEnvironment(filepath.Join(tmpDir, ".yoloai", "sandboxes", name))
+	require.NoError(t, err)
+	assert.Equal(t, baseline, meta.Workdir().BaselineSHA, "the baseline doesn't move")
+
+	res, err = RewindToCommit(context.Background(), testLayout(tmpDir), rt, name, "", baseline)
+	require.NoError(t, err)
+	assert.Len(t, res.Dropped, 1)
+	assert.NoFileExists(t, filepath.Join(workDir, "a.txt"))
+}
+
+func TestRewindToCommit_RefusesOtherCommits(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Setenv("HOME", tmpDir)
+	name := "rewind-refuse"
+	workDir := threeAgentCommits(t, tmpDir, name)
+	rt := hostGitRuntime()
+	var ue *yoerrors.UsageError
+
+	_, err := RewindToCommit(context.Background(), testLayout(tmpDir), rt, name, "", "no-such-ref")
+	require.ErrorAs(t, err, &ue)
+
+	// A commit off the work copy's branch isn't the agent's unapplied work.
+	testutil.RunGit(t, workDir, "checkout", "-q", "-b", "side", "HEAD~1")
+	writeTestFile(t, workDir, "side.txt", "side\n")
+	gitAdd(t, workDir, ".")
+	gitCommit(t, workDir, "side")
+	side := gitHEAD(t, workDir)
+	testutil.RunGit(t, workDir, "checkout", "-q", "-")
+	_, err = RewindToCommit(context.Background(), testLayout(tmpDir), rt, name, "", side)
+	require.ErrorAs(t, err, &ue)
+}
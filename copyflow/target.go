@@ -0,0 +1,38 @@
+// ABOUTME: Apply target redirection: lands an apply in another existing
+// ABOUTME: checkout instead of the directory the sandbox copied from.
+
+package copyflow
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/kstenerud/yoloai/store"
+	"github.com/kstenerud/yoloai/yoerrors"
+)
+
+// resolveApplyTarget checks an apply's Target and returns it as an absolute
+// path, or "" when there is none or it names dir's own host path (a plain
+// apply). A target must be an existing directory, and can't be combined with
+// a fresh clone or a new branch, which pick their own.
+func resolveApplyTarget(dir *store.DirEnvironment, target, freshClone, branch string) (string, error) {
+	if target == "" {
+		return "", nil
+	}
+	if freshClone != "" || branch != "" {
+		return "", yoerrors.NewUsageError("apply to a target directory, a fresh clone or a new branch — only one")
+	}
+	abs, err := filepath.Abs(target)
+	if err != nil {
+		return "", fmt.Errorf("resolve target dir: %w", err)
+	}
+	info, err := os.Stat(abs)
+	if err != nil || !info.IsDir() {
+		return "", yoerrors.NewUsageError("apply target %s is not an existing directory", abs)
+	}
+	if abs == filepath.Clean(dir.HostPath) {
+		return "", nil
+	}
+	return abs, nil
+}
@@ -0,0 +1,112 @@
+// ABOUTME: Tests for applies redirected to another checkout with Target: the
+// ABOUTME: original host dir and baseline stay untouched, and bad targets are refused.
+
+package copyflow
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/kstenerud/yoloai/internal/git"
+	"github.com/kstenerud/yoloai/yoerrors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestApplySeries_Target replays the series into a second clone of the host's
+// origin: the host checkout is untouched and the baseline doesn't advance.
+func TestApplySeries_Target(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Setenv("HOME", tmpDir)
+
+	upstream, host, _ := setupOrigin(t, tmpDir)
+	other := filepath.Join(tmpDir, "other")
+	require.NoError(t, git.NewTestHostWithEnv(testEnv()).Clone(context.Background(), tmpDir, upstream, other))
+	name := "series-target"
+	createCopySandboxWithCommits(t, tmpDir, name, host, []struct {
+		subject  string
+		filename string
+		content  string
+	}{
+		{"add A", "a.txt", "a\n"},
+		{"add B", "b.txt", "b\n"},
+	})
+	rt := hostGitRuntime()
+
+	writeTestFile(t, tmpDir, ".gitconfig", "[user]\n\tname = Test\n\temail = test@example.com\n")
+	layout := testLayout(tmpDir).WithEnv(map[string]string{"HOME": tmpDir, "PATH": os.Getenv("PATH")})
+
+	preview, err := ApplySeries(context.Background(), layout, rt, name, ApplySeriesOptions{Target: other, DryRun: true})
+	require.NoError(t, err)
+	require.NotNil(t, preview)
+	assert.Equal(t, other, preview.Dir)
+	assert.NoFileExists(t, filepath.Join(other, "a.txt"), "a dry run applies nothing")
+
+	result, err := ApplySeries(context.Background(), layout, rt, name, ApplySeriesOptions{Target: other})
+	require.NoError(t, err)
+	require.NotNil(t, result)
+	assert.Equal(t, other, result.Dir)
+	assert.Nil(t, result.Clone)
+	require.Len(t, result.Commits, 2)
+
+	assert.FileExists(t, filepath.Join(other, "a.txt"))
+	assert.FileExists(t, filepath.Join(other, "b.txt"))
+	assert.NoFileExists(t, filepath.Join(host, "a.txt"))
+
+	remaining, err := ListCommitsBeyondBaseline(context.Background(), testLayout(tmpDir), rt, name, "")
+	require.NoError(t, err)
+	assert.Len(t, remaining, 2, "an apply to another target must not advance the baseline")
+}
+
+// TestApplyAll_Target lands the net diff in a plain directory, which a series
+// apply refuses.
+func TestApplyAll_Target(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Setenv("HOME", tmpDir)
+
+	_, host, _ := setupOrigin(t, tmpDir)
+	name := "all-target"
+	workDir := createCopySandbox(t, tmpDir, name, host)
+	writeTestFile(t, workDir, "new.txt", "new\n")
+	rt := hostGitRuntime()
+	plain := filepath.Join(tmpDir, "plain")
+	require.NoError(t, os.MkdirAll(plain, 0750))
+
+	_, err := ApplySeries(context.Background(), testLayout(tmpDir), rt, name, ApplySeriesOptions{Target: plain})
+	var ue *yoerrors.UsageError
+	require.ErrorAs(t, err, &ue, "a series can't replay onto a non-git target")
+
+	result, err := ApplyAll(context.Background(), testLayout(tmpDir), rt, name, ApplyAllOptions{IncludeUncommitted: true, Target: plain})
+	require.NoError(t, err)
+	require.NotNil(t, result)
+	assert.Equal(t, plain, result.Dir)
+	assert.FileExists(t, filepath.Join(plain, "new.txt"))
+	assert.NoFileExists(t, filepath.Join(host, "new.txt"))
+
+	again, err := ApplyAll(context.Background(), testLayout(tmpDir), rt, name, ApplyAllOptions{IncludeUncommitted: true, DryRun: true})
+	require.NoError(t, err)
+	require.NotNil(t, again, "the baseline didn't advance, so the changes are still pending")
+}
+
+func TestApply_TargetRefusals(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Setenv("HOME", tmpDir)
+
+	_, host, _ := setupOrigin(t, tmpDir)
+	name := "target-refusals"
+	workDir := createCopySandbox(t, tmpDir, name, host)
+	writeTestFile(t, workDir, "new.txt", "new\n")
+	rt := hostGitRuntime()
+
+	for desc, opts := range map[string]ApplyAllOptions{
+		"missing target":          {IncludeUncommitted: true, Target: filepath.Join(tmpDir, "missing")},
+		"target with fresh clone": {IncludeUncommitted: true, Target: host, FreshClone: filepath.Join(tmpDir, "fresh")},
+		"target with branch":      {IncludeUncommitted: true, Target: host, Branch: "b"},
+	} {
+		_, err := ApplyAll(context.Background(), testLayout(tmpDir), rt, name, opts)
+		var ue *yoerrors.UsageError
+		assert.ErrorAs(t, err, &ue, desc)
+	}
+}
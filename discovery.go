@@ -5,6 +5,7 @@ package yoloai
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/kstenerud/yoloai/internal/agent"
 	"github.com/kstenerud/yoloai/internal/orchestrator/archetype"
@@ -164,6 +165,33 @@ func (s *System) CheckBackend(ctx context.Context, name BackendType) (available
 	return true, ""
 }
 
+// FallbackBackend returns preferred if CheckBackend finds it available, and
+// otherwise the first backend in fallback that is, with a warning naming the
+// swap. When nothing in fallback is available either, preferred is returned
+// with no warning, so creating on it fails with the backend's own error.
+// Creation-time only: an existing sandbox stays on the backend recorded in
+// its environment.json.
+func (s *System) FallbackBackend(ctx context.Context, preferred BackendType, fallback []BackendType) (BackendType, string) {
+	return pickBackend(preferred, fallback, func(b BackendType) (bool, string) { return s.CheckBackend(ctx, b) })
+}
+
+// pickBackend is FallbackBackend with the availability probe passed in.
+func pickBackend(preferred BackendType, fallback []BackendType, check func(BackendType) (bool, string)) (BackendType, string) {
+	ok, note := check(preferred)
+	if ok {
+		return preferred, ""
+	}
+	for _, b := range fallback {
+		if b == preferred {
+			continue
+		}
+		if ok, _ := check(b); ok {
+			return b, fmt.Sprintf("Warning: backend %s not available (%s); falling back to %s (backend_fallback)", preferred, note, b)
+		}
+	}
+	return preferred, ""
+}
+
 // Archetypes returns the sorted list of valid environment-archetype names
 // yoloai ships (used to auto-shape a sandbox's setup). Static metadata; no host
 // state is consulted.
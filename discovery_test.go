@@ -99,3 +99,25 @@ func TestSystem_CheckBackend_Unknown(t *testing.T) {
 	assert.False(t, available, "an unregistered backend is never available")
 	assert.NotEmpty(t, note, "an unavailable backend explains why")
 }
+
+func TestPickBackend(t *testing.T) {
+	up := map[BackendType]bool{"podman": true, "seatbelt": true}
+	check := func(b BackendType) (bool, string) {
+		if up[b] {
+			return true, ""
+		}
+		return false, "daemon not running"
+	}
+
+	got, warn := pickBackend("podman", []BackendType{"seatbelt"}, check)
+	assert.Equal(t, BackendType("podman"), got, "an available preference is kept")
+	assert.Empty(t, warn)
+
+	got, warn = pickBackend("docker", []BackendType{"docker", "tart", "seatbelt", "podman"}, check)
+	assert.Equal(t, BackendType("seatbelt"), got, "the first available fallback wins")
+	assert.Equal(t, "Warning: backend docker not available (daemon not running); falling back to seatbelt (backend_fallback)", warn)
+
+	got, warn = pickBackend("docker", []BackendType{"tart"}, check)
+	assert.Equal(t, BackendType("docker"), got, "with no fallback available, the preference stands")
+	assert.Empty(t, warn)
+}
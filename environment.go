@@ -47,6 +47,33 @@ type Environment struct {
 	CapAdd             []string          `json:"cap_add,omitempty"`
 	Devices            []string          `json:"devices,omitempty"`
 	AutoCommitInterval int               `json:"auto_commit_interval,omitempty"`
+	ProvenanceHeaders  bool              `json:"provenance_headers,omitempty"`
+	Timezone           string            `json:"timezone,omitempty"`
+	Locale             string            `json:"locale,omitempty"`
+	FakeTime           string            `json:"faketime,omitempty"`
+	AgentWorkdir       string            `json:"agent_workdir,omitempty"`
+	Roles              []string          `json:"roles,omitempty"`
+	AllowPush          bool              `json:"allow_push,omitempty"`
+	// RepoURL / RepoRef are the remote repository (and ref, "" = its default
+	// branch) a sandbox created with --repo was cloned from; empty for a
+	// sandbox made from a host directory.
+	RepoURL string `json:"repo_url,omitempty"`
+	RepoRef string `json:"repo_ref,omitempty"`
+	// RepoDepth is the shallow clone's commit depth (0 = full history) and
+	// RepoSparse the directories of a sparse checkout (empty = the whole tree).
+	RepoDepth  int      `json:"repo_depth,omitempty"`
+	RepoSparse []string `json:"repo_sparse,omitempty"`
+	// WorkRoot is the --work-root override the work copies were placed under
+	// ("" = inside the sandbox dir).
+	WorkRoot string `json:"work_root,omitempty"`
+	// ExpiresAt is when the sandbox's TTL runs out (nil = never); see Expired.
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+// Expired reports whether the sandbox's TTL had run out at now. A sandbox
+// with no TTL never expires.
+func (e *Environment) Expired(now time.Time) bool {
+	return e.ExpiresAt != nil && !now.Before(*e.ExpiresAt)
 }
 
 // Workdir returns the primary directory — Dirs[0], the agent's cwd. Returns the
@@ -87,6 +114,10 @@ type DirInfo struct {
 	Mode         DirMode `json:"mode"`
 	BaselineSHA  string  `json:"baseline_sha,omitempty"`
 	InceptionSHA string  `json:"inception_sha,omitempty"`
+	// SourceRootSHA / SourceRemote identify the host repository the copy was
+	// taken from (root commit, origin URL); empty for non-git sources.
+	SourceRootSHA string `json:"source_root_sha,omitempty"`
+	SourceRemote  string `json:"source_remote,omitempty"`
 }
 
 // environmentFromStore builds the public read-model from the internal metadata.
@@ -111,6 +142,19 @@ func environmentFromStore(m *store.Environment) *Environment {
 		CapAdd:             m.CapAdd,
 		Devices:            m.Devices,
 		AutoCommitInterval: m.AutoCommitInterval,
+		ProvenanceHeaders:  m.ProvenanceHeaders,
+		Timezone:           m.Timezone,
+		Locale:             m.Locale,
+		FakeTime:           m.FakeTime,
+		AgentWorkdir:       m.AgentWorkdir,
+		Roles:              m.Roles,
+		AllowPush:          m.AllowPush,
+		RepoURL:            m.RepoURL,
+		RepoRef:            m.RepoRef,
+		RepoDepth:          m.RepoDepth,
+		RepoSparse:         m.RepoSparse,
+		WorkRoot:           m.WorkRoot,
+		ExpiresAt:          m.ExpiresAt,
 	}
 	if len(m.Dirs) > 0 {
 		env.Dirs = make([]DirInfo, len(m.Dirs))
@@ -120,8 +164,10 @@ func environmentFromStore(m *store.Environment) *Environment {
 	}
 	if m.Resources != nil {
 		env.Resources = &ProfileResources{
-			CPULimit:    m.Resources.CPUs,
-			MemoryLimit: m.Resources.Memory,
+			CPULimit:        m.Resources.CPUs,
+			MemoryLimit:     m.Resources.Memory,
+			DiskLimit:       m.Resources.Disk,
+			AgentStateLimit: m.Resources.AgentState,
 		}
 	}
 	return env
@@ -129,11 +175,13 @@ func environmentFromStore(m *store.Environment) *Environment {
 
 func dirInfoFromStore(d store.DirEnvironment) DirInfo {
 	return DirInfo{
-		HostPath:     d.HostPath,
-		MountPath:    d.MountPath,
-		Mode:         d.Mode,
-		BaselineSHA:  d.BaselineSHA,
-		InceptionSHA: d.InceptionSHA,
+		HostPath:      d.HostPath,
+		MountPath:     d.MountPath,
+		Mode:          d.Mode,
+		BaselineSHA:   d.BaselineSHA,
+		InceptionSHA:  d.InceptionSHA,
+		SourceRootSHA: d.SourceRootSHA,
+		SourceRemote:  d.SourceRemote,
 	}
 }
 
@@ -153,10 +201,22 @@ func sandboxInfoFromStatus(si *orchestrator.Info) *SandboxInfo {
 		AgentStatus:     si.AgentStatus,
 		NetHealth:       si.NetHealth,
 		NetHealthDetail: si.NetHealthDetail,
+		LastOutput:      si.LastOutput,
 		Changes:         ChangeState(si.HasChanges),
 		DiskUsageBytes:  si.DiskUsageBytes,
 		ExitCode:        si.ExitCode,
+		Result:          agentResultFromStore(si.Result),
+		ResultError:     si.ResultError,
+	}
+}
+
+// agentResultFromStore converts the stored result contract to the public
+// AgentResult. Nil-safe.
+func agentResultFromStore(r *store.AgentResult) *AgentResult {
+	if r == nil {
+		return nil
 	}
+	return &AgentResult{Status: r.Status, Summary: r.Summary, FollowUps: r.FollowUps, Files: r.Files}
 }
 
 // sandboxInfosFromStatus maps a slice of internal read-models to public
@@ -66,6 +66,11 @@ type DiskSpaceError = yoerrors.DiskSpaceError
 // macOS concurrent-VM cap); recoverable after freeing the resource.
 type ResourceLimitError = yoerrors.ResourceLimitError
 
+// SandboxQuotaError is returned by CreateSandbox when the workdir already has
+// as many sandboxes as max_sandboxes_per_workdir allows. Sandboxes lists them
+// oldest first; destroy one and retry.
+type SandboxQuotaError = yoerrors.SandboxQuotaError
+
 // SandboxLockedError indicates a write could not acquire the per-sandbox lock.
 // It carries structured fields (HolderAlive, HolderPID, LockPath) so embedders
 // can distinguish a live holder from a stale lock and branch accordingly.
@@ -28,6 +28,22 @@ const (
 	PromptModeHeadless PromptMode = "headless"
 )
 
+// UsageSource names where an agent records the tokens it has spent, in a form
+// yoloai can read back to total them.
+type UsageSource string
+
+const (
+	// UsageClaudeTranscripts reads the usage block of each assistant message
+	// in the session transcripts under StateDir's projects/.
+	UsageClaudeTranscripts UsageSource = "claude-transcripts"
+	// UsageCodexSessions reads the running token_count totals in the session
+	// rollouts under StateDir's sessions/.
+	UsageCodexSessions UsageSource = "codex-sessions"
+	// UsageAiderLog reads the "Tokens: ... Cost: ..." line aider prints after
+	// each reply, from the sandbox's agent terminal log.
+	UsageAiderLog UsageSource = "aider-log"
+)
+
 // SeedFile describes a host file to copy into the agent's state directory.
 // HostPath supports ~ for the user's home directory, expanded at runtime.
 // TargetPath is relative to the agent's StateDir.
@@ -96,6 +112,17 @@ type Definition struct {
 	ContextFile       string            // filename in StateDir for sandbox context reference (e.g., "CLAUDE.md")
 	AgentFilesExclude []string          // glob patterns to skip when copying agent_files (string form)
 
+	// PrunableState lists what in the agent's state directory may be deleted to
+	// reclaim space: paths relative to StateDir, where a trailing "/" means
+	// everything under that dir and anything else is a path.Match pattern.
+	// Session transcripts belong here even though resuming reads the latest
+	// one: trimming to a size cap removes the oldest files first.
+	PrunableState []string
+
+	// UsageSource says where the agent records its token usage, for `yoloai
+	// cost`. "" means it keeps nothing yoloai can read.
+	UsageSource UsageSource
+
 	// ResumeFlag is the agent's native conversation-resume flag, appended to the
 	// interactive command to continue the prior conversation (e.g. Claude
 	// "--continue"). "" means the agent has no native resume — the fall-to-shell
@@ -104,6 +131,12 @@ type Definition struct {
 	// the fall-to-shell wrapper.
 	ResumeFlag string
 
+	// NPMPackage is the npm package the base image installs the agent from
+	// (e.g. "@anthropic-ai/claude-code"). `yoloai upgrade` reinstalls it at a
+	// newer version inside a running sandbox. "" means the agent is not
+	// npm-installed and can't be upgraded in place.
+	NPMPackage string
+
 	// ApplySettings patches the agent's JSON config map before it is written to
 	// disk. Called with the parsed config map; mutates it in place. Nil means no
 	// patches are needed.
@@ -296,6 +329,7 @@ var agents = map[string]*Definition{
 		StateDir:       "",
 		SubmitSequence: "Enter",
 		StartupDelay:   3 * time.Second,
+		UsageSource:    UsageAiderLog,
 		Idle: IdleSupport{
 			// Hook-authoritative for idle via --notifications-command (above).
 			// Stop-only: active relies on prompt-delivery's active-before-submit,
@@ -329,6 +363,7 @@ var agents = map[string]*Definition{
 		HeadlessCmd:    `claude -p "PROMPT" --dangerously-skip-permissions`,
 		PromptMode:     PromptModeInteractive,
 		ResumeFlag:     "--continue",
+		NPMPackage:     "@anthropic-ai/claude-code",
 		APIKeyEnvVars:  []string{"ANTHROPIC_API_KEY", "CLAUDE_CODE_OAUTH_TOKEN"},
 		Broker: &BrokerConfig{ //nolint:gosec // G101 false positive: env-var NAMES + a placeholder, not real credentials
 			UpstreamURL: "https://api.anthropic.com",
@@ -385,6 +420,8 @@ var agents = map[string]*Definition{
 		NetworkAllowlist:  []string{"api.anthropic.com", "claude.ai", "platform.claude.com", "statsig.anthropic.com", "sentry.io"},
 		ContextFile:       "CLAUDE.md",
 		AgentFilesExclude: []string{"projects/", "statsig/", "todos/", ".credentials.json", "*.log"},
+		PrunableState:     []string{"projects/", "todos/", "shell-snapshots/", "file-history/", "statsig/", "debug/", "*.log"},
+		UsageSource:       UsageClaudeTranscripts,
 		ApplySettings: func(s map[string]any) {
 			s["skipDangerousModePermissionPrompt"] = true
 			// Default the terminal renderer to the classic ("default") line
@@ -429,6 +466,7 @@ var agents = map[string]*Definition{
 		InteractiveCmd: "gemini --yolo",
 		HeadlessCmd:    `gemini -p "PROMPT" --yolo`,
 		PromptMode:     PromptModeInteractive,
+		NPMPackage:     "@google/gemini-cli",
 		APIKeyEnvVars:  []string{"GEMINI_API_KEY"},
 		Broker: &BrokerConfig{ //nolint:gosec // G101 false positive: env-var NAMES + a placeholder, not real credentials
 			UpstreamURL: "https://generativelanguage.googleapis.com",
@@ -479,6 +517,7 @@ var agents = map[string]*Definition{
 		NetworkAllowlist:  []string{"generativelanguage.googleapis.com", "cloudcode-pa.googleapis.com", "oauth2.googleapis.com"},
 		ContextFile:       "GEMINI.md",
 		AgentFilesExclude: []string{"logs/", "oauth_creds.json", "gemini-credentials.json", "google_accounts.json"},
+		PrunableState:     []string{"tmp/", "logs/"},
 		ApplySettings: func(s map[string]any) {
 			// Preserve existing security settings (e.g. auth.selectedType) while
 			// disabling folder trust — the container is already sandboxed.
@@ -499,6 +538,7 @@ var agents = map[string]*Definition{
 		InteractiveCmd:  "opencode",
 		HeadlessCmd:     `opencode run "PROMPT"`,
 		PromptMode:      PromptModeHeadless,
+		NPMPackage:      "opencode-ai",
 		APIKeyEnvVars:   []string{"ANTHROPIC_API_KEY", "OPENAI_API_KEY", "GEMINI_API_KEY", "GROQ_API_KEY", "OPENROUTER_API_KEY", "XAI_API_KEY"},
 		AuthHintEnvVars: []string{"GITHUB_TOKEN", "LOCAL_ENDPOINT", "AZURE_OPENAI_ENDPOINT", "AWS_ACCESS_KEY_ID", "AWS_PROFILE", "AWS_DEFAULT_PROFILE", "AWS_REGION", "AWS_DEFAULT_REGION", "VERTEXAI_PROJECT"},
 		AuthOptional:    true,
@@ -532,6 +572,7 @@ var agents = map[string]*Definition{
 		},
 		NetworkAllowlist:  []string{"api.anthropic.com", "api.openai.com", "generativelanguage.googleapis.com", "api.github.com", "api.githubcopilot.com"},
 		AgentFilesExclude: []string{"auth.json", "sessions/"},
+		PrunableState:     []string{"sessions/", "log/"},
 	},
 	"codex": {
 		Type:        "codex",
@@ -543,6 +584,7 @@ var agents = map[string]*Definition{
 		InteractiveCmd: "codex --dangerously-bypass-approvals-and-sandbox --dangerously-bypass-hook-trust",
 		HeadlessCmd:    `codex exec --dangerously-bypass-approvals-and-sandbox --dangerously-bypass-hook-trust "PROMPT"`,
 		PromptMode:     PromptModeInteractive,
+		NPMPackage:     "@openai/codex",
 		APIKeyEnvVars:  []string{"CODEX_API_KEY", "OPENAI_API_KEY"},
 		Broker: &BrokerConfig{ //nolint:gosec // G101 false positive: env-var NAMES + a placeholder, not real credentials
 			UpstreamURL: "https://api.openai.com",
@@ -600,19 +642,27 @@ var agents = map[string]*Definition{
 			"spark":   "gpt-5.3-codex-spark",
 			"mini":    "codex-mini-latest",
 		},
-		NetworkAllowlist:  []string{"api.openai.com"},
+		// api.openai.com serves API-key auth. A ChatGPT sign-in (the seeded
+		// auth.json) talks to chatgpt.com's backend instead and refreshes its
+		// token at auth.openai.com, so both must stay reachable when isolated.
+		NetworkAllowlist:  []string{"api.openai.com", "chatgpt.com", "auth.openai.com"},
 		AgentFilesExclude: []string{"auth.json", "sessions/", "hooks.json"},
+		PrunableState:     []string{"sessions/", "log/"},
+		UsageSource:       UsageCodexSessions,
 		// Native turn-completion detection via Codex's lifecycle hooks, written to
 		// its dedicated ~/.codex/hooks.json: UserPromptSubmit/PreToolUse → active,
 		// Stop → idle. Makes Codex hook-authoritative.
 		SettingsFileName: "hooks.json",
 		ApplySettings:    injectCodexHooks,
 	},
+	// test-agent.py execs `bash` / `sh -c PROMPT` unchanged unless a scenario
+	// file is given (--scenario after `--`, or YOLOAI_TEST_SCENARIO), in which
+	// case it plays the scenario's output, edits, commits and exit code.
 	"test": {
 		Type:           "test",
-		Description:    "Bash shell for testing and development",
-		InteractiveCmd: "bash",
-		HeadlessCmd:    `sh -c "PROMPT"`,
+		Description:    "Bash shell for testing and development, or a scripted fake agent",
+		InteractiveCmd: testAgentCmd,
+		HeadlessCmd:    testAgentCmd + ` -c "PROMPT"`,
 		PromptMode:     PromptModeHeadless,
 		APIKeyEnvVars:  []string{},
 		StateDir:       "",
@@ -762,6 +812,11 @@ func init() {
 	agents["shell"] = buildShellAgent()
 }
 
+// testAgentCmd launches the built-in `test` agent's script, installed in the
+// sandbox bin dir by every backend. Uses $YOLOAI_DIR like the status commands
+// below.
+const testAgentCmd = `python3 "${YOLOAI_DIR:-/yoloai}/bin/test-agent.py"`
+
 // statusIdleCommand writes idle status to agent-status.json and appends a
 // structured JSONL entry to logs/agent-hooks.jsonl when Claude finishes a
 // response (Stop hook). Uses $YOLOAI_DIR for portability across
@@ -188,7 +188,7 @@ func TestGetAgent_Codex(t *testing.T) {
 	assert.Equal(t, "gpt-5.3-codex", def.ModelAliases["default"])
 	assert.Equal(t, "gpt-5.3-codex-spark", def.ModelAliases["spark"])
 	assert.Equal(t, "codex-mini-latest", def.ModelAliases["mini"])
-	assert.Equal(t, []string{"api.openai.com"}, def.NetworkAllowlist)
+	assert.Equal(t, []string{"api.openai.com", "chatgpt.com", "auth.openai.com"}, def.NetworkAllowlist)
 }
 
 func TestAllAgentTypes(t *testing.T) {
@@ -196,14 +196,27 @@ func TestAllAgentTypes(t *testing.T) {
 	assert.Equal(t, []string{"aider", "claude", "codex", "gemini", "idle", "opencode", "shell", "test"}, names)
 }
 
+func TestPrunableState_WellFormed(t *testing.T) {
+	for _, name := range AllAgentTypes() {
+		def := GetAgent(name)
+		if len(def.PrunableState) > 0 {
+			assert.NotEmpty(t, def.StateDir, "%s: prunable state needs a state dir", name)
+		}
+		for _, p := range def.PrunableState {
+			assert.NoError(t, validatePrunablePath(p), "%s: %q", name, p)
+		}
+	}
+	assert.Contains(t, GetAgent("claude").PrunableState, "projects/", "Claude's session transcripts")
+}
+
 func TestGetAgent_Test(t *testing.T) {
 	def := GetAgent("test")
 	require.NotNil(t, def)
 
 	assert.Equal(t, AgentType("test"), def.Type)
 	assert.NotEmpty(t, def.Description)
-	assert.Equal(t, "bash", def.InteractiveCmd)
-	assert.Contains(t, def.HeadlessCmd, "sh -c")
+	assert.Contains(t, def.InteractiveCmd, "/bin/test-agent.py")
+	assert.Equal(t, def.InteractiveCmd+` -c "PROMPT"`, def.HeadlessCmd)
 	assert.Equal(t, PromptModeHeadless, def.PromptMode)
 	assert.Empty(t, def.APIKeyEnvVars)
 	assert.NotNil(t, def.APIKeyEnvVars, "should be empty slice, not nil")
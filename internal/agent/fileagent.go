@@ -3,11 +3,15 @@
 package agent
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
+	"io"
 	"os"
+	"path"
 	"path/filepath"
 	"regexp"
+	"strings"
 	"time"
 
 	"gopkg.in/yaml.v3"
@@ -125,6 +129,15 @@ type FileAgentSpec struct {
 
 	// AgentFilesExclude lists glob patterns to skip when copying agent_files.
 	AgentFilesExclude []string `yaml:"agent_files_exclude"`
+
+	// PrunableState lists what in StateDir may be deleted to reclaim space
+	// (e.g. "sessions/", "*.log"); see Definition.PrunableState.
+	PrunableState []string `yaml:"prunable_state"`
+
+	// UsageSource names the usage record the agent keeps in a built-in
+	// agent's format (e.g. a wrapper around Claude Code); see
+	// Definition.UsageSource.
+	UsageSource string `yaml:"usage_source"`
 }
 
 // toDefinition converts a validated FileAgentSpec into a *Definition.
@@ -170,6 +183,8 @@ func (s *FileAgentSpec) toDefinition() *Definition {
 		NetworkAllowlist:  s.NetworkAllowlist,
 		ContextFile:       s.ContextFile,
 		AgentFilesExclude: s.AgentFilesExclude,
+		PrunableState:     s.PrunableState,
+		UsageSource:       UsageSource(s.UsageSource),
 	}
 }
 
@@ -195,12 +210,37 @@ func validateFileAgentSpec(s *FileAgentSpec, filePath string, builtIns map[strin
 	if builtIns[s.Type] {
 		return fmt.Errorf("file-defined agent %q: type %q is a reserved built-in name", base, s.Type)
 	}
+	switch UsageSource(s.UsageSource) {
+	case "", UsageClaudeTranscripts, UsageCodexSessions, UsageAiderLog:
+	default:
+		return fmt.Errorf("file-defined agent %q (type %q): usage_source must be %q, %q or %q, got %q",
+			base, s.Type, UsageClaudeTranscripts, UsageCodexSessions, UsageAiderLog, s.UsageSource)
+	}
+	for _, p := range s.PrunableState {
+		if err := validatePrunablePath(p); err != nil {
+			return fmt.Errorf("file-defined agent %q (type %q): prunable_state %q: %w", base, s.Type, p, err)
+		}
+	}
+	return nil
+}
+
+// validatePrunablePath checks one PrunableState entry: relative, inside the
+// state dir, and (when not a dir) a well-formed pattern.
+func validatePrunablePath(p string) error {
+	clean := path.Clean(strings.TrimSuffix(p, "/"))
+	if p == "" || path.IsAbs(p) || clean == "." || clean == ".." || strings.HasPrefix(clean, "../") {
+		return errors.New("must be a path inside state_dir")
+	}
+	if _, err := path.Match(p, ""); err != nil {
+		return fmt.Errorf("invalid pattern: %w", err)
+	}
 	return nil
 }
 
 // LoadFileAgents parses all *.yaml and *.yml files in dir and returns the
 // resulting agent Definitions. A missing dir is not an error (returns nil, nil).
-// Returns a descriptive error naming the file on any parse or validation failure.
+// Returns a descriptive error naming the file on any parse or validation
+// failure, including a key FileAgentSpec does not define.
 //
 // LoadFileAgents validates that no file agent shadows a built-in name. The
 // built-in set is frozen after package init() and requires no lock to read.
@@ -238,8 +278,12 @@ func LoadFileAgents(dir string) ([]*Definition, error) {
 		if err != nil {
 			return nil, fmt.Errorf("file-defined agent %q: %w", filepath.Base(f), err)
 		}
+		// Unknown keys are errors, as in profiles: a misspelled ready_pattern
+		// would otherwise leave the agent silently without idle detection.
 		var spec FileAgentSpec
-		if err := yaml.Unmarshal(data, &spec); err != nil {
+		dec := yaml.NewDecoder(bytes.NewReader(data))
+		dec.KnownFields(true)
+		if err := dec.Decode(&spec); err != nil && !errors.Is(err, io.EOF) {
 			return nil, fmt.Errorf("file-defined agent %q: YAML parse error: %w", filepath.Base(f), err)
 		}
 		if err := validateFileAgentSpec(&spec, f, builtIns); err != nil {
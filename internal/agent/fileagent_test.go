@@ -86,6 +86,8 @@ network_allowlist:
   - auth.mytool2.com
 context_file: AGENTS.md
 agent_files_exclude: ["auth.json", "sessions/"]
+prunable_state: ["sessions/", "*.log"]
+usage_source: codex-sessions
 `)
 	defs, err := LoadFileAgents(dir)
 	require.NoError(t, err)
@@ -116,11 +118,13 @@ agent_files_exclude: ["auth.json", "sessions/"]
 	assert.True(t, def.Idle.WchanApplicable)
 	assert.False(t, def.Idle.Hook, "Hook cannot be set via YAML; must stay false")
 	assert.Equal(t, "--model", def.ModelFlag)
+	assert.Equal(t, UsageCodexSessions, def.UsageSource)
 	assert.Equal(t, map[string]string{"fast": "mytool2-fast", "slow": "mytool2-slow"}, def.ModelAliases)
 	assert.Equal(t, map[string]string{"OLLAMA_API_BASE": "ollama/"}, def.ModelPrefixes)
 	assert.Equal(t, []string{"api.mytool2.com", "auth.mytool2.com"}, def.NetworkAllowlist)
 	assert.Equal(t, "AGENTS.md", def.ContextFile)
 	assert.Equal(t, []string{"auth.json", "sessions/"}, def.AgentFilesExclude)
+	assert.Equal(t, []string{"sessions/", "*.log"}, def.PrunableState)
 
 	// Code-only fields must stay zero/nil.
 	assert.Nil(t, def.ApplySettings, "ApplySettings must be nil for file-defined agents")
@@ -169,6 +173,20 @@ func TestLoadFileAgents_MalformedYAML(t *testing.T) {
 	assert.Contains(t, err.Error(), "bad.yaml")
 }
 
+func TestLoadFileAgents_UnknownKey(t *testing.T) {
+	dir := t.TempDir()
+	writeAgentFile(t, dir, "typo.yaml", `
+type: typo
+interactive_cmd: sometool
+idle:
+  ready_patern: "> "
+`)
+	_, err := LoadFileAgents(dir)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "typo.yaml")
+	assert.Contains(t, err.Error(), "ready_patern")
+}
+
 func TestLoadFileAgents_MissingType(t *testing.T) {
 	dir := t.TempDir()
 	writeAgentFile(t, dir, "notype.yaml", `
@@ -204,6 +222,24 @@ description: no commands
 	assert.Contains(t, err.Error(), "interactive_cmd or headless_cmd")
 }
 
+func TestLoadFileAgents_PrunableStateOutsideStateDir(t *testing.T) {
+	for _, bad := range []string{"../secrets/", "/etc/", ".", "[a-"} {
+		dir := t.TempDir()
+		writeAgentFile(t, dir, "prune.yaml", "type: prune\ninteractive_cmd: sometool\nprunable_state: [\""+bad+"\"]\n")
+		_, err := LoadFileAgents(dir)
+		require.Error(t, err, bad)
+		assert.Contains(t, err.Error(), "prunable_state", bad)
+	}
+}
+
+func TestLoadFileAgents_UnknownUsageSource(t *testing.T) {
+	dir := t.TempDir()
+	writeAgentFile(t, dir, "usage.yaml", "type: usage\ninteractive_cmd: sometool\nusage_source: billing-api\n")
+	_, err := LoadFileAgents(dir)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "usage_source")
+}
+
 func TestLoadFileAgents_InvalidPromptMode(t *testing.T) {
 	dir := t.TempDir()
 	writeAgentFile(t, dir, "badmode.yaml", `
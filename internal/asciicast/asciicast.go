@@ -0,0 +1,169 @@
+// ABOUTME: Reading and playing asciicast v2 recordings — the format of a sandbox's
+// ABOUTME: logs/agent.cast — at a chosen speed with long pauses cut short.
+package asciicast
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+// Header is the first line of an asciicast v2 file.
+type Header struct {
+	Version   int               `json:"version"`
+	Width     int               `json:"width"`
+	Height    int               `json:"height"`
+	Timestamp int64             `json:"timestamp,omitempty"`
+	Env       map[string]string `json:"env,omitempty"`
+}
+
+// Event is one recorded event: Data printed ("o") or typed ("i") Time
+// seconds after the recording started.
+type Event struct {
+	Time float64
+	Type string
+	Data string
+}
+
+// Reader reads the events of an asciicast v2 stream in order.
+type Reader struct {
+	Header Header
+	r      *bufio.Reader
+}
+
+// NewReader reads and checks the header of the recording in r.
+func NewReader(r io.Reader) (*Reader, error) {
+	br := bufio.NewReader(r)
+	line, err := readLine(br)
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, errors.New("empty recording")
+		}
+		return nil, fmt.Errorf("read recording header: %w", err)
+	}
+	var h Header
+	if err := json.Unmarshal(line, &h); err != nil {
+		return nil, fmt.Errorf("parse recording header: %w", err)
+	}
+	if h.Version != 2 {
+		return nil, fmt.Errorf("unsupported recording version %d (want 2)", h.Version)
+	}
+	return &Reader{Header: h, r: br}, nil
+}
+
+// Next returns the next event, or io.EOF after the last one. A line that is
+// not an event is skipped: the recorder appends as the agent prints, so a
+// sandbox stopped mid-write can leave a torn last line.
+func (r *Reader) Next() (Event, error) {
+	for {
+		line, err := readLine(r.r)
+		if err != nil {
+			return Event{}, err
+		}
+		var raw []json.RawMessage
+		if json.Unmarshal(line, &raw) != nil || len(raw) != 3 {
+			continue
+		}
+		var ev Event
+		if json.Unmarshal(raw[0], &ev.Time) != nil ||
+			json.Unmarshal(raw[1], &ev.Type) != nil ||
+			json.Unmarshal(raw[2], &ev.Data) != nil {
+			continue
+		}
+		return ev, nil
+	}
+}
+
+// readLine returns the next non-blank line without its newline. Lines are read
+// whole, however long: one output event can hold a full screen redraw.
+func readLine(r *bufio.Reader) ([]byte, error) {
+	for {
+		line, err := r.ReadBytes('\n')
+		if trimmed := bytes.TrimSpace(line); len(trimmed) > 0 {
+			return trimmed, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+// PlayOptions controls playback.
+type PlayOptions struct {
+	// Speed scales playback: 2 plays twice as fast. Zero or less means 1.
+	Speed float64
+	// MaxIdle caps any pause between two events (before scaling by Speed), so
+	// the hours an agent sat waiting for input don't replay in real time.
+	// Zero keeps every pause as recorded.
+	MaxIdle time.Duration
+}
+
+// Play writes the output events of the recording in r to w with their
+// recorded timing. It returns when the recording ends or ctx is cancelled.
+func Play(ctx context.Context, r io.Reader, w io.Writer, opts PlayOptions) error {
+	return play(ctx, r, w, opts, sleepCtx)
+}
+
+// play is Play with the wait injectable, so tests run without real pauses.
+func play(ctx context.Context, r io.Reader, w io.Writer, opts PlayOptions, sleep func(context.Context, time.Duration) error) error {
+	rd, err := NewReader(r)
+	if err != nil {
+		return err
+	}
+	speed := opts.Speed
+	if speed <= 0 {
+		speed = 1
+	}
+	last := 0.0
+	for {
+		ev, err := rd.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("read recording: %w", err)
+		}
+		if ev.Type != "o" {
+			continue
+		}
+		if wait := pause(ev.Time-last, opts.MaxIdle, speed); wait > 0 {
+			if err := sleep(ctx, wait); err != nil {
+				return err
+			}
+		}
+		if ev.Time > last {
+			last = ev.Time
+		}
+		if _, err := io.WriteString(w, ev.Data); err != nil {
+			return err
+		}
+	}
+}
+
+// pause is how long to wait for a gap of gapSeconds between two events.
+func pause(gapSeconds float64, maxIdle time.Duration, speed float64) time.Duration {
+	if gapSeconds <= 0 {
+		return 0
+	}
+	d := time.Duration(gapSeconds * float64(time.Second))
+	if maxIdle > 0 && d > maxIdle {
+		d = maxIdle
+	}
+	return time.Duration(float64(d) / speed)
+}
+
+func sleepCtx(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
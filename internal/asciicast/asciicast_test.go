@@ -0,0 +1,70 @@
+// ABOUTME: Tests for asciicast playback: header checks, torn lines skipped, input
+// ABOUTME: events not replayed, and pauses scaled by speed and capped by max-idle.
+package asciicast
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const cast = `{"version": 2, "width": 200, "height": 50, "timestamp": 1700000000}
+[0.5, "o", "hello "]
+[1.5, "i", "typed"]
+[2.0, "o", "wörld\r\n"]
+[602.0, "o", "later"]
+[602.1, "o", "tor`
+
+func recordSleeps(waits *[]time.Duration) func(context.Context, time.Duration) error {
+	return func(_ context.Context, d time.Duration) error {
+		*waits = append(*waits, d)
+		return nil
+	}
+}
+
+func TestPlay_WritesOutputWithTiming(t *testing.T) {
+	var out bytes.Buffer
+	var waits []time.Duration
+	err := play(context.Background(), strings.NewReader(cast), &out, PlayOptions{}, recordSleeps(&waits))
+	require.NoError(t, err)
+	assert.Equal(t, "hello wörld\r\nlater", out.String())
+	assert.Equal(t, []time.Duration{500 * time.Millisecond, 1500 * time.Millisecond, 600 * time.Second}, waits)
+}
+
+func TestPlay_SpeedAndMaxIdle(t *testing.T) {
+	var waits []time.Duration
+	opts := PlayOptions{Speed: 2, MaxIdle: time.Second}
+	err := play(context.Background(), strings.NewReader(cast), &bytes.Buffer{}, opts, recordSleeps(&waits))
+	require.NoError(t, err)
+	assert.Equal(t, []time.Duration{250 * time.Millisecond, 500 * time.Millisecond, 500 * time.Millisecond}, waits)
+}
+
+func TestPlay_Cancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err := Play(ctx, strings.NewReader(cast), &bytes.Buffer{}, PlayOptions{})
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestNewReader_RejectsBadHeader(t *testing.T) {
+	_, err := NewReader(strings.NewReader(""))
+	assert.ErrorContains(t, err, "empty recording")
+
+	_, err = NewReader(strings.NewReader(`{"version": 1}` + "\n"))
+	assert.ErrorContains(t, err, "unsupported recording version 1")
+
+	_, err = NewReader(strings.NewReader("not json\n"))
+	assert.ErrorContains(t, err, "parse recording header")
+}
+
+func TestNewReader_Header(t *testing.T) {
+	r, err := NewReader(strings.NewReader(cast))
+	require.NoError(t, err)
+	assert.Equal(t, 200, r.Header.Width)
+	assert.Equal(t, 50, r.Header.Height)
+}
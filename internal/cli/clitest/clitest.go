@@ -25,6 +25,8 @@ import (
 func Home(t *testing.T) string {
 	t.Helper()
 	home := testutil.IsolatedHome(t)
+	// Keep the machine's /etc/yoloai out of the tests too.
+	t.Setenv("YOLOAI_SYSTEM_CONFIG_DIR", "")
 	cliutil.SetRootLayout(cliutil.LayoutForDataDir(filepath.Join(home, ".yoloai")))
 	return home
 }
@@ -1,7 +1,7 @@
-package sandboxcmd
+package cliutil
 
 // ABOUTME: ANSI escape sequence and control character stripping for readable log output.
-// ABOUTME: Used by `yoloai log` to clean tmux pipe-pane capture.
+// ABOUTME: Used by `yoloai log`, bug reports and `describe` to clean tmux pipe-pane capture.
 
 import (
 	"bufio"
@@ -31,10 +31,10 @@ var ansiPattern = regexp.MustCompile(`\x1b(?:` +
 // artifacts in log display.
 var controlPattern = regexp.MustCompile(`[\x00-\x08\x0b-\x0c\x0d-\x1a\x7f]`)
 
-// stripANSI copies src to dst with ANSI escape sequences and problematic
+// StripANSI copies src to dst with ANSI escape sequences and problematic
 // control characters removed. It processes line-by-line to avoid
 // partial-match issues at buffer boundaries.
-func stripANSI(dst io.Writer, src io.Reader) error {
+func StripANSI(dst io.Writer, src io.Reader) error {
 	scanner := bufio.NewScanner(src)
 	for scanner.Scan() {
 		line := ansiPattern.ReplaceAll(scanner.Bytes(), nil)
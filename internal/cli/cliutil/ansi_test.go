@@ -1,7 +1,7 @@
-// ABOUTME: Tests for stripANSI: removes SGR color, cursor movement, OSC
+// ABOUTME: Tests for StripANSI: removes SGR color, cursor movement, OSC
 // ABOUTME: title, charset-selection, and other control sequences from
 // ABOUTME: agent terminal output while preserving plain text.
-package sandboxcmd
+package cliutil
 
 import (
 	"bytes"
@@ -125,12 +125,12 @@ func TestStripANSI(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			var buf bytes.Buffer
-			err := stripANSI(&buf, strings.NewReader(tt.input))
+			err := StripANSI(&buf, strings.NewReader(tt.input))
 			if err != nil {
-				t.Fatalf("stripANSI() error = %v", err)
+				t.Fatalf("StripANSI() error = %v", err)
 			}
 			if got := buf.String(); got != tt.want {
-				t.Errorf("stripANSI() = %q, want %q", got, tt.want)
+				t.Errorf("StripANSI() = %q, want %q", got, tt.want)
 			}
 		})
 	}
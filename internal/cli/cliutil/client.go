@@ -26,6 +26,7 @@ import (
 // The pattern is intentionally not abstracted into a generic helper because
 // each pair has domain-specific variations:
 //   - ResolveBackend: accepts a --backend flag; used by new/build/setup.
+//     ResolveCreateBackend adds the backend_fallback probe for creation.
 //   - ResolveBackendForSandbox: reads from environment.json, not a flag; used by
 //     lifecycle commands (start, stop, attach, diff, apply, destroy).
 //   - ResolveAgent: similar flag→config→default; new command only.
@@ -79,6 +80,38 @@ func ResolveBackend(cmd *cobra.Command) yoloai.BackendType {
 	return backend
 }
 
+// ResolveCreateBackend is ResolveBackend for the commands that create a
+// sandbox (new, run, batch, compare). A backend named by --backend is used as
+// given; one that came from config or auto-detection is probed, and when it's
+// unavailable the first available backend in the backend_fallback config list
+// replaces it, with a warning on stderr. The sandbox records whichever backend it is
+// created on, so later commands find it there.
+func ResolveCreateBackend(cmd *cobra.Command) yoloai.BackendType {
+	backend := ResolveBackend(cmd)
+	if b, _ := cmd.Flags().GetString("backend"); b != "" {
+		return backend
+	}
+	cfg, _ := config.LoadDefaultsConfig(Layout())
+	if cfg == nil || len(cfg.BackendFallback) == 0 {
+		return backend
+	}
+	sys, err := SystemWithEnv(BackendEnv(cmd))
+	if err != nil {
+		return backend
+	}
+	fallback := make([]yoloai.BackendType, 0, len(cfg.BackendFallback))
+	for _, b := range cfg.BackendFallback {
+		resolved, _ := yoloai.ResolveContainerSystem(yoloai.BackendType(b), "")
+		fallback = append(fallback, resolved)
+	}
+	chosen, warn := sys.FallbackBackend(cmd.Context(), backend, fallback)
+	if warn != "" {
+		fmt.Fprintln(os.Stderr, warn)
+		slog.Warn("backend unavailable; using fallback", "event", "sandbox.backend.fallback", "backend", backend, "fallback", chosen)
+	}
+	return chosen
+}
+
 // rawBackendPreference returns the user's explicit backend choice as a raw,
 // unresolved string: the --backend flag if set, else the container_backend
 // config value, else "". A container-system alias (orbstack/docker-desktop) is
@@ -141,7 +174,7 @@ func ResolveContainerBackendConfig() yoloai.BackendType {
 // Used by lifecycle commands that operate on an existing sandbox.
 func ResolveBackendForSandbox(name string) yoloai.BackendType {
 	l := Layout()
-	c, err := yoloai.NewClient(context.Background(), yoloai.ClientCreateOptions{DataDir: l.DataDir, HomeDir: l.HomeDir, Principal: string(l.Principal), Env: EdgeEnv()})
+	c, err := yoloai.NewClient(context.Background(), yoloai.ClientCreateOptions{DataDir: l.DataDir, HomeDir: l.HomeDir, Principal: string(l.Principal), SystemConfigDir: l.SystemConfigDir, Env: EdgeEnv()})
 	if err == nil {
 		defer c.Close() //nolint:errcheck // backend-less close is a no-op
 		if sb, sbErr := c.Sandbox(name); sbErr == nil {
@@ -164,23 +197,34 @@ func ResolveBackendForSandbox(name string) yoloai.BackendType {
 // Run). The Client wraps a runtime + sandbox.Engine with §12-clean Layout
 // derived from Layout(). See internal/cli/CONVENTIONS.md.
 func WithClient(cmd *cobra.Command, backend yoloai.BackendType, fn func(ctx context.Context, c *yoloai.Client) error) error {
-	ctx := cmd.Context()
+	c, err := ClientFor(cmd, backend)
+	if err != nil {
+		return err
+	}
+	defer c.Close() //nolint:errcheck // best-effort cleanup
+	return fn(cmd.Context(), c)
+}
+
+// ClientFor constructs the yoloai.Client WithClient hands to its callback, for
+// the rare command that keeps clients open across many operations (top holds
+// one per backend for its whole session). The caller must Close it.
+func ClientFor(cmd *cobra.Command, backend yoloai.BackendType) (*yoloai.Client, error) {
 	l := Layout()
-	c, err := yoloai.NewClient(ctx, yoloai.ClientCreateOptions{
-		DataDir:     l.DataDir,
-		HomeDir:     l.HomeDir,
-		Principal:   string(l.Principal),
-		BackendType: backend,
-		Logger:      slog.Default(),
-		Input:       cmd.InOrStdin(),
-		Output:      cmd.ErrOrStderr(),
-		Env:         EdgeEnv(),
+	c, err := yoloai.NewClient(cmd.Context(), yoloai.ClientCreateOptions{
+		DataDir:         l.DataDir,
+		HomeDir:         l.HomeDir,
+		Principal:       string(l.Principal),
+		SystemConfigDir: l.SystemConfigDir,
+		BackendType:     backend,
+		Logger:          slog.Default(),
+		Input:           cmd.InOrStdin(),
+		Output:          cmd.ErrOrStderr(),
+		Env:             EdgeEnv(),
 	})
 	if err != nil {
-		return fmt.Errorf("connect to runtime: %w", err)
+		return nil, fmt.Errorf("connect to runtime: %w", err)
 	}
-	defer c.Close() //nolint:errcheck // best-effort cleanup
-	return fn(ctx, c)
+	return c, nil
 }
 
 // WithSandbox folds the per-sandbox command prologue that recurs across the
@@ -244,13 +288,14 @@ func WithTrackedDir(cmd *cobra.Command, name, hostPath string, fn func(ctx conte
 func Client(cmd *cobra.Command) (*yoloai.Client, error) {
 	l := Layout()
 	return yoloai.NewClient(cmd.Context(), yoloai.ClientCreateOptions{
-		DataDir:   l.DataDir,
-		HomeDir:   l.HomeDir,
-		Principal: string(l.Principal),
-		Logger:    slog.Default(),
-		Input:     cmd.InOrStdin(),
-		Output:    cmd.ErrOrStderr(),
-		Env:       EdgeEnv(),
+		DataDir:         l.DataDir,
+		HomeDir:         l.HomeDir,
+		Principal:       string(l.Principal),
+		SystemConfigDir: l.SystemConfigDir,
+		Logger:          slog.Default(),
+		Input:           cmd.InOrStdin(),
+		Output:          cmd.ErrOrStderr(),
+		Env:             EdgeEnv(),
 	})
 }
 
@@ -280,10 +325,11 @@ func SystemWithEnv(env map[string]string) (*yoloai.System, error) {
 // root, which differs only on an un-relocated flat v0 install).
 func systemForDataDir(dataDir string, env map[string]string) (*yoloai.System, error) {
 	c, err := yoloai.NewClient(context.Background(), yoloai.ClientCreateOptions{
-		DataDir:   dataDir,
-		HomeDir:   Layout().HomeDir,
-		Principal: string(Layout().Principal),
-		Env:       env,
+		DataDir:         dataDir,
+		HomeDir:         Layout().HomeDir,
+		Principal:       string(Layout().Principal),
+		SystemConfigDir: Layout().SystemConfigDir,
+		Env:             env,
 	})
 	if err != nil {
 		return nil, err
@@ -136,6 +136,18 @@ func TestResolveBackend_ConfigAliasResolvesToDocker(t *testing.T) {
 		"container_backend: orbstack must route to the docker backend")
 }
 
+func TestResolveCreateBackend_FlagSkipsFallback(t *testing.T) {
+	dir := clitest.ConfigDir(t)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "config.yaml"), []byte("backend_fallback: [podman]\n"), 0600))
+
+	cmd := &cobra.Command{}
+	cmd.Flags().String("backend", "", "")
+	require.NoError(t, cmd.Flags().Set("backend", "does-not-exist"))
+
+	assert.Equal(t, runtime.BackendType("does-not-exist"), cliutil.ResolveCreateBackend(cmd),
+		"a backend named with --backend is never swapped for a fallback")
+}
+
 func TestBackendEnv_PinsDockerHostForAlias(t *testing.T) {
 	home := clitest.Home(t)
 	cmd := &cobra.Command{}
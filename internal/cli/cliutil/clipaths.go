@@ -44,6 +44,24 @@ func CLIExtensionsDir() string {
 	return filepath.Join(CLIDir(), "extensions")
 }
 
+// CLIDaemonLogPath returns TOP/cli/daemon.log — where `yoloai daemon`, run
+// as a launchd agent, writes its output.
+func CLIDaemonLogPath() string {
+	return filepath.Join(CLIDir(), "daemon.log")
+}
+
+// CLIEventsSocketPath returns TOP/cli/events.sock — the unix socket on which
+// `yoloai daemon run` publishes sandbox lifecycle and status events.
+func CLIEventsSocketPath() string {
+	return filepath.Join(CLIDir(), "events.sock")
+}
+
+// CLIDaemonSocketPath returns TOP/cli/daemon.sock — the unix socket on which
+// `yoloai daemon run` serves its local API (internal/daemonapi).
+func CLIDaemonSocketPath() string {
+	return filepath.Join(CLIDir(), "daemon.sock")
+}
+
 // CLIStatePath returns TOP/cli/state.yaml — the CLI app's own state file
 // (e.g. whether the first-run setup wizard has been shown). The library
 // keeps no such setup-ceremony state; recording it is the app's business.
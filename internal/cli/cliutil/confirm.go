@@ -1,48 +1,280 @@
-// ABOUTME: Context-aware interactive prompting for user confirmations.
-// ABOUTME: Provides readLine (races stdin vs context) and Confirm (y/N prompt).
+// ABOUTME: The one yes/no confirmation prompt every command uses: default-yes or -no,
+// ABOUTME: --confirm-timeout, full-word and locale answers, and a plain accessible style.
 
 package cliutil
 
 import (
-	"bufio"
 	"context"
 	"fmt"
 	"io"
+	"reflect"
 	"strings"
+	"sync"
+	"time"
 )
 
-// readLine reads a single line from input, returning early if ctx is cancelled.
-// On EOF (empty reader), returns ("", nil) so callers can treat it as a default.
-// The reading goroutine may outlive the call on cancellation; this is acceptable
-// for a CLI that is about to exit.
-func readLine(ctx context.Context, input io.Reader) (string, error) {
-	ch := make(chan string, 1)
-	go func() {
-		scanner := bufio.NewScanner(input)
-		if scanner.Scan() {
-			ch <- scanner.Text()
-		} else {
-			ch <- ""
-		}
-	}()
+// PromptSettings is how every confirmation prompt behaves. The root command
+// sets it once from the global flags and the environment (SetPromptSettings).
+type PromptSettings struct {
+	// Timeout, when positive, makes an unanswered prompt take its default
+	// after this long, so an unattended run does not wait forever.
+	Timeout time.Duration
+	// Plain spells the choices out in words instead of "[y/N]" notation,
+	// which screen readers read out as punctuation.
+	Plain bool
+	// Lang is the user's language ("de", "fr", ...), whose words for yes and
+	// no are accepted alongside the English ones.
+	Lang string
+}
+
+// promptSettings is the process-wide PromptSettings; the zero value (no
+// timeout, bracket style, English only) is what tests get.
+var promptSettings PromptSettings
+
+// SetPromptSettings records the settings every later prompt uses. Called from
+// the root command's PersistentPreRunE.
+func SetPromptSettings(s PromptSettings) {
+	promptSettings = s
+}
+
+// PromptSettingsFor builds the settings for the --confirm-timeout and
+// --plain-prompts values and the captured environment: YOLOAI_PLAIN_PROMPTS
+// turns plain mode on too, and the locale variables give the language.
+func PromptSettingsFor(timeout time.Duration, plain bool, env map[string]string) PromptSettings {
+	if v := env["YOLOAI_PLAIN_PROMPTS"]; v != "" && v != "0" && !strings.EqualFold(v, "false") {
+		plain = true
+	}
+	return PromptSettings{Timeout: timeout, Plain: plain, Lang: localeLang(env)}
+}
+
+// localeLang is the language of the user's message locale, taken the way
+// setlocale does (LC_ALL, then LC_MESSAGES, then LANG): "de_DE.UTF-8" is "de".
+// The C and POSIX locales have none.
+func localeLang(env map[string]string) string {
+	for _, key := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		v := env[key]
+		if v == "" {
+			continue
+		}
+		lang, _, _ := strings.Cut(v, "_")
+		lang, _, _ = strings.Cut(lang, ".")
+		lang, _, _ = strings.Cut(lang, "@")
+		lang = strings.ToLower(lang)
+		if lang == "c" || lang == "posix" {
+			return ""
+		}
+		return lang
+	}
+	return ""
+}
+
+// localeAnswers are the words for yes and no accepted in each language on top
+// of y/yes and n/no. A word in both lists of a language would be ambiguous, so
+// none is.
+var localeAnswers = map[string]struct{ yes, no []string }{
+	"de": {yes: []string{"j", "ja"}, no: []string{"nein"}},
+	"es": {yes: []string{"s", "si", "sí"}, no: []string{"no"}},
+	"fr": {yes: []string{"o", "oui"}, no: []string{"non"}},
+	"it": {yes: []string{"s", "si", "sì"}, no: []string{"no"}},
+	"nl": {yes: []string{"j", "ja"}, no: []string{"nee"}},
+	"pt": {yes: []string{"s", "sim"}, no: []string{"nao", "não"}},
+}
+
+// parseAnswer reads a typed answer as yes or no. ok is false for anything it
+// does not recognize, including an empty line.
+func parseAnswer(line, lang string) (yes, ok bool) {
+	answer := strings.ToLower(strings.TrimSpace(line))
+	switch answer {
+	case "y", "yes":
+		return true, true
+	case "n", "no":
+		return false, true
+	}
+	words := localeAnswers[lang]
+	for _, w := range words.yes {
+		if answer == w {
+			return true, true
+		}
+	}
+	for _, w := range words.no {
+		if answer == w {
+			return false, true
+		}
+	}
+	return false, false
+}
+
+// ConfirmOptions adjusts a single prompt.
+type ConfirmOptions struct {
+	// DefaultYes makes an empty answer, end of input, or a timeout mean yes.
+	// Reserve it for questions where going ahead is the safe choice.
+	DefaultYes bool
+}
+
+// Confirm asks question and reads a yes or no answer, defaulting to no. The
+// question is bare ("Delete profile 'x'?"); the choices and default are
+// appended in the configured style. Returns an error only if the context is
+// cancelled (e.g. Ctrl+C).
+func Confirm(ctx context.Context, question string, input io.Reader, output io.Writer) (bool, error) {
+	return Ask(ctx, question, ConfirmOptions{}, input, output)
+}
+
+// Ask is Confirm with options.
+func Ask(ctx context.Context, question string, opts ConfirmOptions, input io.Reader, output io.Writer) (bool, error) {
+	return ask(ctx, question, opts, promptSettings, input, output)
+}
+
+// ask runs the prompt under explicit settings. An answer it can't read as yes
+// or no asks again; an empty answer, end of input (a headless run), or the
+// timeout running out takes the default.
+func ask(ctx context.Context, question string, opts ConfirmOptions, s PromptSettings, input io.Reader, output io.Writer) (bool, error) {
+	src := sourceFor(input)
+	fmt.Fprint(output, promptText(question, opts.DefaultYes, s)) //nolint:errcheck // best-effort output
+	for {
+		line, open, expired, err := src.next(ctx, s.Timeout)
+		switch {
+		case err != nil:
+			return false, err
+		case expired:
+			fmt.Fprintf(output, "\nNo answer after %s, so %s.\n", s.Timeout, answerWord(opts.DefaultYes)) //nolint:errcheck // best-effort output
+			return opts.DefaultYes, nil
+		case !open || strings.TrimSpace(line) == "":
+			return opts.DefaultYes, nil
+		}
+		if yes, ok := parseAnswer(line, s.Lang); ok {
+			return yes, nil
+		}
+		fmt.Fprint(output, "Please answer yes or no: ") //nolint:errcheck // best-effort output
+	}
+}
+
+// promptText is question with its choices, default and any timeout spelled
+// out: "Delete it? [y/N] " or, plain, "Delete it? Answer yes or no (default
+// no): ".
+func promptText(question string, defaultYes bool, s PromptSettings) string {
+	if s.Plain {
+		suffix := fmt.Sprintf("default %s", answerWord(defaultYes))
+		if s.Timeout > 0 {
+			suffix = fmt.Sprintf("%s after %s", answerWord(defaultYes), s.Timeout)
+		}
+		return fmt.Sprintf("%s Answer yes or no (%s): ", question, suffix)
+	}
+	choices := "[y/N]"
+	if defaultYes {
+		choices = "[Y/n]"
+	}
+	if s.Timeout > 0 {
+		return fmt.Sprintf("%s %s (%s in %s) ", question, choices, answerWord(defaultYes), s.Timeout)
+	}
+	return fmt.Sprintf("%s %s ", question, choices)
+}
+
+func answerWord(yes bool) string {
+	if yes {
+		return "yes"
+	}
+	return "no"
+}
+
+// lineSource reads lines from one input for the prompts that use it. It reads
+// a line only when a prompt asks for one, and a byte at a time, so nothing
+// past the answer is consumed: later prompts, $EDITOR and the next command
+// see the rest of stdin untouched. A prompt that gives up (timeout, Ctrl+C)
+// leaves its read outstanding, and the line it returns answers the next
+// prompt on the same input rather than being lost.
+type lineSource struct {
+	requests chan struct{}
+	results  chan lineResult
+	pending  bool
+}
+
+type lineResult struct {
+	line string
+	open bool
+}
+
+var (
+	lineSourcesMu sync.Mutex
+	lineSources   = map[io.Reader]*lineSource{}
+)
 
+// sourceFor returns the input's lineSource, starting it on first use.
+func sourceFor(input io.Reader) *lineSource {
+	lineSourcesMu.Lock()
+	defer lineSourcesMu.Unlock()
+	comparable := reflect.TypeOf(input).Comparable()
+	if comparable {
+		if src, ok := lineSources[input]; ok {
+			return src
+		}
+	}
+	src := &lineSource{requests: make(chan struct{}), results: make(chan lineResult, 1)}
+	if comparable {
+		lineSources[input] = src
+	}
+	go src.serve(input)
+	return src
+}
+
+func (s *lineSource) serve(input io.Reader) {
+	for range s.requests {
+		line, err := readLine(input)
+		if err != nil && line == "" {
+			s.results <- lineResult{}
+			return
+		}
+		s.results <- lineResult{line: line, open: true}
+	}
+}
+
+// next waits for the next line, for at most timeout when it is positive.
+// open is false at end of input; expired is true when the timeout ran out.
+func (s *lineSource) next(ctx context.Context, timeout time.Duration) (line string, open, expired bool, err error) {
+	if err := ctx.Err(); err != nil {
+		return "", false, false, err
+	}
+	if !s.pending {
+		s.requests <- struct{}{}
+		s.pending = true
+	}
+	var deadline <-chan time.Time
+	if timeout > 0 {
+		timer := time.NewTimer(timeout)
+		defer timer.Stop()
+		deadline = timer.C
+	}
 	select {
 	case <-ctx.Done():
-		return "", ctx.Err()
-	case line := <-ch:
-		return line, nil
+		return "", false, false, ctx.Err()
+	case <-deadline:
+		return "", false, true, nil
+	case r := <-s.results:
+		s.pending = false
+		if !r.open {
+			// Input is exhausted: every later prompt takes its default.
+			s.results <- r
+			s.pending = true
+		}
+		return r.line, r.open, false, nil
 	}
 }
 
-// Confirm prints a prompt and reads y/N from the reader.
-// Returns true if the user answered "y" or "yes" (case-insensitive).
-// Returns an error if the context is cancelled (e.g. Ctrl+C).
-func Confirm(ctx context.Context, prompt string, input io.Reader, output io.Writer) (bool, error) {
-	fmt.Fprint(output, prompt) //nolint:errcheck // best-effort output
-	line, err := readLine(ctx, input)
-	if err != nil {
-		return false, err
+// readLine reads up to and including the next newline, one byte at a time,
+// and returns the line without it. A final line with no newline is returned
+// with the error that ended it.
+func readLine(input io.Reader) (string, error) {
+	var line []byte
+	b := make([]byte, 1)
+	for {
+		n, err := input.Read(b)
+		if n == 1 {
+			if b[0] == '\n' {
+				return strings.TrimSuffix(string(line), "\r"), nil
+			}
+			line = append(line, b[0])
+		}
+		if err != nil {
+			return string(line), err
+		}
 	}
-	answer := strings.TrimSpace(strings.ToLower(line))
-	return answer == "y" || answer == "yes", nil
 }
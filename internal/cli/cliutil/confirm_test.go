@@ -1,12 +1,14 @@
-// ABOUTME: Tests for the interactive y/N confirmation prompt: yes/no/
-// ABOUTME: empty/EOF input handling and context cancellation.
+// ABOUTME: Tests for the yes/no confirmation prompt: defaults, EOF, re-asking, full-word
+// ABOUTME: and locale answers, the plain style, the timeout, and context cancellation.
 package cliutil
 
 import (
 	"bytes"
 	"context"
+	"io"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -14,7 +16,7 @@ import (
 
 func TestConfirm_Yes(t *testing.T) {
 	var out bytes.Buffer
-	confirmed, err := Confirm(context.Background(), "Continue? [y/N] ", strings.NewReader("y\n"), &out)
+	confirmed, err := Confirm(context.Background(), "Continue?", strings.NewReader("y\n"), &out)
 	require.NoError(t, err)
 	assert.True(t, confirmed)
 	assert.Equal(t, "Continue? [y/N] ", out.String())
@@ -22,21 +24,21 @@ func TestConfirm_Yes(t *testing.T) {
 
 func TestConfirm_No(t *testing.T) {
 	var out bytes.Buffer
-	confirmed, err := Confirm(context.Background(), "Continue? [y/N] ", strings.NewReader("n\n"), &out)
+	confirmed, err := Confirm(context.Background(), "Continue?", strings.NewReader("n\n"), &out)
 	require.NoError(t, err)
 	assert.False(t, confirmed)
 }
 
 func TestConfirm_Empty(t *testing.T) {
 	var out bytes.Buffer
-	confirmed, err := Confirm(context.Background(), "Continue? [y/N] ", strings.NewReader("\n"), &out)
+	confirmed, err := Confirm(context.Background(), "Continue?", strings.NewReader("\n"), &out)
 	require.NoError(t, err)
 	assert.False(t, confirmed)
 }
 
 func TestConfirm_EOF(t *testing.T) {
 	var out bytes.Buffer
-	confirmed, err := Confirm(context.Background(), "Continue? [y/N] ", strings.NewReader(""), &out)
+	confirmed, err := Confirm(context.Background(), "Continue?", strings.NewReader(""), &out)
 	require.NoError(t, err)
 	assert.False(t, confirmed)
 }
@@ -46,27 +48,118 @@ func TestConfirm_CancelledContext(t *testing.T) {
 	cancel() // cancel immediately
 
 	var out bytes.Buffer
-	confirmed, err := Confirm(ctx, "Continue? [y/N] ", strings.NewReader("y\n"), &out)
+	confirmed, err := Confirm(ctx, "Continue?", strings.NewReader("y\n"), &out)
 	assert.ErrorIs(t, err, context.Canceled)
 	assert.False(t, confirmed)
 }
 
-func TestReadLine_Normal(t *testing.T) {
-	line, err := readLine(context.Background(), strings.NewReader("hello\n"))
+func TestAsk_DefaultYes(t *testing.T) {
+	var out bytes.Buffer
+	confirmed, err := Ask(context.Background(), "Continue?", ConfirmOptions{DefaultYes: true}, strings.NewReader("\n"), &out)
+	require.NoError(t, err)
+	assert.True(t, confirmed)
+	assert.Equal(t, "Continue? [Y/n] ", out.String())
+
+	confirmed, err = Ask(context.Background(), "Continue?", ConfirmOptions{DefaultYes: true}, strings.NewReader("no\n"), io.Discard)
 	require.NoError(t, err)
-	assert.Equal(t, "hello", line)
+	assert.False(t, confirmed)
 }
 
-func TestReadLine_EOF(t *testing.T) {
-	line, err := readLine(context.Background(), strings.NewReader(""))
+func TestAsk_FullWordsAnyCase(t *testing.T) {
+	for input, want := range map[string]bool{"YES\n": true, " Yes \n": true, "No\n": false, "N\n": false} {
+		confirmed, err := Confirm(context.Background(), "Continue?", strings.NewReader(input), io.Discard)
+		require.NoError(t, err, input)
+		assert.Equal(t, want, confirmed, input)
+	}
+}
+
+func TestAsk_AsksAgainOnUnknownAnswer(t *testing.T) {
+	var out bytes.Buffer
+	confirmed, err := Confirm(context.Background(), "Continue?", strings.NewReader("maybe\nyes\n"), &out)
 	require.NoError(t, err)
-	assert.Equal(t, "", line)
+	assert.True(t, confirmed)
+	assert.Equal(t, "Continue? [y/N] Please answer yes or no: ", out.String())
 }
 
-func TestReadLine_CancelledContext(t *testing.T) {
-	ctx, cancel := context.WithCancel(context.Background())
-	cancel()
+func TestAsk_LocaleWords(t *testing.T) {
+	de := PromptSettings{Lang: "de"}
+	for input, want := range map[string]bool{"ja\n": true, "j\n": true, "nein\n": false, "y\n": true} {
+		confirmed, err := ask(context.Background(), "Weiter?", ConfirmOptions{DefaultYes: !want}, de, strings.NewReader(input), io.Discard)
+		require.NoError(t, err, input)
+		assert.Equal(t, want, confirmed, input)
+	}
+	// Another language's words are not taken as an answer.
+	var out bytes.Buffer
+	confirmed, err := ask(context.Background(), "Continue?", ConfirmOptions{}, PromptSettings{Lang: "fr"}, strings.NewReader("ja\n"), &out)
+	require.NoError(t, err)
+	assert.False(t, confirmed)
+	assert.Contains(t, out.String(), "Please answer yes or no")
+}
 
-	_, err := readLine(ctx, strings.NewReader("hello\n"))
-	assert.ErrorIs(t, err, context.Canceled)
+func TestAsk_Plain(t *testing.T) {
+	var out bytes.Buffer
+	_, err := ask(context.Background(), "Delete it?", ConfirmOptions{}, PromptSettings{Plain: true}, strings.NewReader("\n"), &out)
+	require.NoError(t, err)
+	assert.Equal(t, "Delete it? Answer yes or no (default no): ", out.String())
+
+	out.Reset()
+	_, err = ask(context.Background(), "Delete it?", ConfirmOptions{DefaultYes: true},
+		PromptSettings{Plain: true, Timeout: 30 * time.Second}, strings.NewReader("\n"), &out)
+	require.NoError(t, err)
+	assert.Equal(t, "Delete it? Answer yes or no (yes after 30s): ", out.String())
+}
+
+func TestAsk_TimeoutTakesDefault(t *testing.T) {
+	in, w := io.Pipe() // never written: nobody answers
+	defer w.Close()    //nolint:errcheck // test cleanup
+
+	var out bytes.Buffer
+	s := PromptSettings{Timeout: 10 * time.Millisecond}
+	confirmed, err := ask(context.Background(), "Continue?", ConfirmOptions{DefaultYes: true}, s, in, &out)
+	require.NoError(t, err)
+	assert.True(t, confirmed)
+	assert.Equal(t, "Continue? [Y/n] (yes in 10ms) \nNo answer after 10ms, so yes.\n", out.String())
+}
+
+func TestPromptSettingsFor(t *testing.T) {
+	s := PromptSettingsFor(time.Minute, false, map[string]string{"LANG": "de_DE.UTF-8"})
+	assert.Equal(t, PromptSettings{Timeout: time.Minute, Lang: "de"}, s)
+
+	// LC_ALL wins over LANG; the C locale has no language.
+	assert.Empty(t, PromptSettingsFor(0, false, map[string]string{"LC_ALL": "C", "LANG": "fr_FR"}).Lang)
+	assert.Equal(t, "pt", PromptSettingsFor(0, false, map[string]string{"LC_MESSAGES": "pt_BR"}).Lang)
+
+	assert.True(t, PromptSettingsFor(0, false, map[string]string{"YOLOAI_PLAIN_PROMPTS": "1"}).Plain)
+	assert.False(t, PromptSettingsFor(0, false, map[string]string{"YOLOAI_PLAIN_PROMPTS": "0"}).Plain)
+	assert.True(t, PromptSettingsFor(0, true, nil).Plain)
+}
+
+func TestConfirm_SuccessivePromptsShareInput(t *testing.T) {
+	in := strings.NewReader("y\nn\nrest\n")
+	first, err := Confirm(context.Background(), "First?", in, io.Discard)
+	require.NoError(t, err)
+	second, err := Confirm(context.Background(), "Second?", in, io.Discard)
+	require.NoError(t, err)
+	assert.True(t, first)
+	assert.False(t, second)
+
+	// Nothing past the answers was consumed.
+	rest, err := io.ReadAll(in)
+	require.NoError(t, err)
+	assert.Equal(t, "rest\n", string(rest))
+}
+
+func TestAsk_LineAfterTimeoutAnswersNextPrompt(t *testing.T) {
+	in, w := io.Pipe()
+	defer w.Close() //nolint:errcheck // test cleanup
+
+	s := PromptSettings{Timeout: 10 * time.Millisecond}
+	confirmed, err := ask(context.Background(), "First?", ConfirmOptions{}, s, in, io.Discard)
+	require.NoError(t, err)
+	assert.False(t, confirmed)
+
+	go w.Write([]byte("yes\n")) //nolint:errcheck // test input
+	confirmed, err = ask(context.Background(), "Second?", ConfirmOptions{}, PromptSettings{}, in, io.Discard)
+	require.NoError(t, err)
+	assert.True(t, confirmed)
 }
@@ -0,0 +1,194 @@
+// ABOUTME: The examples registry: copy-pasteable command lines shown under
+// ABOUTME: each command's --help, and the workflows listed by `yoloai examples`.
+package cliutil
+
+import (
+	"strings"
+)
+
+// Example is one command line with a short note on what it does.
+type Example struct {
+	Cmd  string `json:"cmd"`
+	Note string `json:"note,omitempty"`
+}
+
+// Workflow is a named sequence of commands for a common task.
+type Workflow struct {
+	Name  string    `json:"name"`
+	Title string    `json:"title"`
+	Steps []Example `json:"steps"`
+}
+
+// commandExamples holds the examples shown under a command's --help, keyed by
+// the command's path below the root ("new", "sandbox allow"). The directory
+// suffixes (:copy, :rw, :force, =<mount>) are only discoverable here and in
+// 'yoloai help workdirs', so the create commands show them.
+var commandExamples = map[string][]Example{
+	"new": {
+		{"yoloai new fix-bug .", "copy the current directory (the default :copy)"},
+		{`yoloai new fix-bug . -p "fix the failing tests"`, "start with a prompt"},
+		{"yoloai new fix-bug ./app:rw", "live mount: changes land immediately"},
+		{"yoloai new fix-bug ./app=/src/app", "mount the workdir at /src/app"},
+		{"yoloai new fix-bug . -d ../lib -d ../docs:rw", "add a read-only and a writable dir"},
+		{"yoloai new fix-bug . --network-allow proxy.golang.org", "isolated network, plus one domain"},
+		{"yoloai new fix-bug . --agent codex --model o3", "pick the agent and model"},
+		{"yoloai new fix-bug . --port 3000:3000", "reach a dev server from the host"},
+		{"yoloai new fix-bug . --image my-env:v1", "start from an image saved by 'yoloai commit'"},
+		{"yoloai new fix-bug . --replace", "start over in an existing sandbox"},
+	},
+	"run": {
+		{`yoloai run lint . -p "fix every lint warning" --wait`, "block until the agent exits"},
+		{`yoloai run lint . -f task.md --rm`, "wait, then destroy the sandbox"},
+		{`yoloai run lint ./app:rw -p "bump the deps"`, "headless, on the live directory"},
+	},
+	"attach": {
+		{"yoloai attach fix-bug", "open the agent's session (detach: Ctrl-b d)"},
+		{"yoloai attach fix-bug --read-only", "watch without typing"},
+		{"yoloai attach fix-bug --resume", "restart the agent with its prompt first"},
+	},
+	"shell": {
+		{"yoloai shell fix-bug", "a shell in the workdir, beside the agent (detach: Ctrl-b d)"},
+	},
+	"port add": {
+		{"yoloai port add fix-bug 3000:3000", "reach a dev server started after creation"},
+		{"yoloai port add fix-bug 8080:80 --no-restart", "forward from the next start"},
+	},
+	"port rm": {
+		{"yoloai port rm fix-bug 3000", "stop forwarding host port 3000"},
+	},
+	"network allow": {
+		{"yoloai network allow fix-bug registry.npmjs.org", "the agent needs npm; no restart"},
+		{"yoloai network allow fix-bug pypi.org files.pythonhosted.org", "several at once"},
+	},
+	"network log": {
+		{"yoloai network log fix-bug", "where the agent tried to go today"},
+		{"yoloai network log fix-bug --blocked", "only what the allowlist refused"},
+		{"yoloai network log fix-bug --since 1h --json", "the raw events"},
+	},
+	"network refresh": {
+		{"yoloai network refresh", "every running isolated sandbox"},
+		{"yoloai network refresh fix-bug", "just one"},
+	},
+	"reset": {
+		{"yoloai reset fix-bug", "re-copy the workdir, keep the agent running"},
+		{"yoloai reset fix-bug --restart", "also restart the agent"},
+		{"yoloai reset fix-bug --abandon-unapplied", "throw away unapplied changes"},
+	},
+	"rebase": {
+		{"yoloai rebase fix-bug", "replay the agent's work onto the host's latest"},
+		{"yoloai rebase fix-bug web --json", "one dir of a multi-dir sandbox"},
+	},
+	"commit": {
+		{"yoloai commit setup --image my-env:v1", "save a hand-prepared sandbox as an image"},
+		{"yoloai new fix-bug . --image my-env:v1", "start a sandbox from it"},
+	},
+	"clone": {
+		{"yoloai clone fix-bug fix-bug-2", "copy a sandbox, work and all"},
+		{`yoloai clone fix-bug fix-bug-2 -p "try another approach"`, "with a new prompt"},
+	},
+	"clean-state": {
+		{"yoloai clean-state fix-bug --dry-run", "list what would be deleted"},
+		{"yoloai clean-state fix-bug --max-size 500m", "drop the oldest transcripts, keep the latest"},
+	},
+	"cost": {
+		{"yoloai cost", "tokens and estimated cost of every sandbox"},
+		{"yoloai cost fix-bug --json", "one sandbox, for a spreadsheet"},
+	},
+	"replay": {
+		{"yoloai replay fix-bug", "watch what the agent did, long pauses cut to 2s"},
+		{"yoloai replay fix-bug --speed 4", "the same, four times as fast"},
+	},
+	"destroy": {
+		{"yoloai destroy fix-bug", "remove a sandbox you've applied"},
+		{"yoloai destroy fix-bug lint --abandon-unapplied", "remove several, discarding work"},
+	},
+}
+
+// workflows are the common tasks listed by `yoloai examples`, in the order a
+// new user meets them.
+var workflows = []Workflow{
+	{Name: "review", Title: "Let an agent work on a copy, then review and apply", Steps: []Example{
+		{`yoloai new fix-bug . -p "fix the failing tests"`, "the agent works on a copy"},
+		{"yoloai attach fix-bug", "watch or talk to it (detach: Ctrl-b d)"},
+		{"yoloai diff fix-bug", "review what it changed"},
+		{"yoloai apply fix-bug", "bring its commits back to your repo"},
+		{"yoloai destroy fix-bug", "clean up"},
+	}},
+	{Name: "headless", Title: "Run a task to completion without attaching", Steps: []Example{
+		{`yoloai run lint . -p "fix every lint warning" --wait`, "the agent exits when done"},
+		{"yoloai diff lint --stat", "summary of the changes"},
+		{"yoloai apply lint", "keep them"},
+	}},
+	{Name: "dirs", Title: "Directory modes and mount points", Steps: []Example{
+		{"yoloai new task ./app", ":copy (default): an isolated copy, diff/apply"},
+		{"yoloai new task ./app:rw", ":rw: live mount, no diff/apply"},
+		{"yoloai new task ./app:copy-all", ":copy-all: copy gitignored files too"},
+		{"yoloai new task ./app:copy-strict", ":copy-strict: copy without git history"},
+		{"yoloai new task ./app=/src/app", "=<path>: choose the mount point"},
+		{"yoloai new task . -d ../lib -d ../shared:rw", "-d: extra dirs, read-only unless :rw"},
+		{"yoloai new task $HOME/scratch:force", ":force: mount a directory yoloai would refuse"},
+	}},
+	{Name: "network", Title: "Limit what the agent can reach", Steps: []Example{
+		{"yoloai new task . --network-isolated", "only the agent's API"},
+		{"yoloai new task . --network-allow proxy.golang.org", "isolated, plus a domain"},
+		{"yoloai sandbox task allow registry.npmjs.org", "allow another domain later"},
+		{"yoloai sandbox task allowed", "show the allowlist"},
+		{"yoloai new task . --network-none", "no network at all"},
+	}},
+	{Name: "followup", Title: "Keep a sandbox going", Steps: []Example{
+		{"yoloai wait fix-bug", "block until the agent is idle"},
+		{`yoloai send fix-bug "now add tests"`, "give it the next task"},
+		{"yoloai reset fix-bug", "re-sync after you change the original"},
+		{"yoloai stop fix-bug", "stop it, keeping its state"},
+		{"yoloai start fix-bug", "pick up where it left off"},
+	}},
+	{Name: "profiles", Title: "Reuse a setup with a profile", Steps: []Example{
+		{"yoloai profile create go-dev", "scaffold a profile"},
+		{"yoloai new task . --profile go-dev", "create a sandbox from it"},
+		{"yoloai config set agent codex", "change the default agent"},
+	}},
+}
+
+// CommandExamples returns the registered examples for the command at path,
+// formatted for cobra's Example field. Empty when none are registered.
+func CommandExamples(path string) string {
+	return FormatExamples(commandExamples[path])
+}
+
+// Workflows returns the common workflows listed by `yoloai examples`.
+func Workflows() []Workflow {
+	return workflows
+}
+
+// AllExamples returns every registered example: the per-command ones and the
+// workflow steps. Tests use it to check each one parses.
+func AllExamples() []Example {
+	var all []Example
+	for _, examples := range commandExamples {
+		all = append(all, examples...)
+	}
+	for _, w := range workflows {
+		all = append(all, w.Steps...)
+	}
+	return all
+}
+
+// FormatExamples renders examples one per line, indented by two spaces, with
+// the notes aligned in a comment column.
+func FormatExamples(examples []Example) string {
+	width := 0
+	for _, e := range examples {
+		width = max(width, len(e.Cmd))
+	}
+	var b strings.Builder
+	for i, e := range examples {
+		if i > 0 {
+			b.WriteByte('\n')
+		}
+		b.WriteString("  " + e.Cmd)
+		if e.Note != "" {
+			b.WriteString(strings.Repeat(" ", width-len(e.Cmd)+2) + "# " + e.Note)
+		}
+	}
+	return b.String()
+}
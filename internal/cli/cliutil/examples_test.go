@@ -0,0 +1,34 @@
+// ABOUTME: Tests for the examples registry: comment-column formatting and
+// ABOUTME: that the registry has what --help and `yoloai examples` show.
+package cliutil
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatExamples(t *testing.T) {
+	got := FormatExamples([]Example{
+		{Cmd: "yoloai diff mybox", Note: "full diff"},
+		{Cmd: "yoloai diff mybox --stat", Note: "summary"},
+		{Cmd: "yoloai diff mybox --log"},
+	})
+	assert.Equal(t, "  yoloai diff mybox         # full diff\n"+
+		"  yoloai diff mybox --stat  # summary\n"+
+		"  yoloai diff mybox --log", got)
+	assert.Empty(t, FormatExamples(nil))
+}
+
+func TestCommandExamples(t *testing.T) {
+	assert.Contains(t, CommandExamples("new"), ":rw")
+	assert.Contains(t, CommandExamples("new"), "--network-allow")
+	assert.Empty(t, CommandExamples("no-such-command"))
+
+	names := make(map[string]bool)
+	for _, w := range Workflows() {
+		assert.False(t, names[w.Name], "duplicate workflow %q", w.Name)
+		names[w.Name] = true
+		assert.NotEmpty(t, w.Steps, w.Name)
+	}
+}
@@ -11,7 +11,11 @@ import (
 // FormatAge returns a human-readable duration string (e.g., "2h", "3d", "5m")
 // for the time elapsed since created.
 func FormatAge(created time.Time) string {
-	d := time.Since(created)
+	return FormatDuration(time.Since(created))
+}
+
+// FormatDuration renders d in its largest whole unit (e.g., "2h", "3d", "5m").
+func FormatDuration(d time.Duration) string {
 	switch {
 	case d < time.Minute:
 		return fmt.Sprintf("%ds", int(d.Seconds()))
@@ -86,8 +86,24 @@ func Layout() config.Layout {
 // without clashing. The user's home stays bound to the real $HOME even when
 // TOP is rerooted (e.g. /var/lib/yoloai under a service install).
 func LayoutForDataDir(dataDir string) config.Layout {
+	env := processEnv()
 	l := config.NewLayoutFor(filepath.Join(dataDir, libraryNamespace), resolveHome())
-	return l.WithPrincipal(config.CLIPrincipal).WithEnv(processEnv())
+	l.SystemConfigDir = resolveSystemConfigDir(env)
+	return l.WithPrincipal(config.CLIPrincipal).WithEnv(env)
+}
+
+// defaultSystemConfigDir is where a platform team installs the machine-wide
+// config layered beneath each user's own.
+const defaultSystemConfigDir = "/etc/yoloai"
+
+// resolveSystemConfigDir returns $YOLOAI_SYSTEM_CONFIG_DIR from the edge env
+// snapshot when set — empty turning the system layer off — else
+// defaultSystemConfigDir.
+func resolveSystemConfigDir(env map[string]string) string {
+	if dir, ok := env["YOLOAI_SYSTEM_CONFIG_DIR"]; ok {
+		return dir
+	}
+	return defaultSystemConfigDir
 }
 
 // processEnv snapshots the process environment into a map for the
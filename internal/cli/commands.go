@@ -6,12 +6,14 @@ package cli
 import (
 	"github.com/kstenerud/yoloai/internal/cli/cliutil"
 	"github.com/kstenerud/yoloai/internal/cli/configcmd"
+	"github.com/kstenerud/yoloai/internal/cli/daemoncmd"
 	"github.com/kstenerud/yoloai/internal/cli/doctorcmd"
 	"github.com/kstenerud/yoloai/internal/cli/helpcmd"
 	"github.com/kstenerud/yoloai/internal/cli/lifecycle"
 	"github.com/kstenerud/yoloai/internal/cli/mcp"
 	"github.com/kstenerud/yoloai/internal/cli/profile"
 	"github.com/kstenerud/yoloai/internal/cli/sandboxcmd"
+	"github.com/kstenerud/yoloai/internal/cli/selftestcmd"
 	"github.com/kstenerud/yoloai/internal/cli/system"
 	"github.com/kstenerud/yoloai/internal/cli/versioncmd"
 	"github.com/kstenerud/yoloai/internal/cli/workflow"
@@ -33,20 +35,38 @@ func registerCommands(root *cobra.Command, version, commit, date string) {
 		lifecycle.NewNewCmd(version),
 		lifecycle.NewRunCmd(version),
 		lifecycle.NewCloneCmd(),
+		lifecycle.NewBatchCmd(version),
+		lifecycle.NewCompareCmd(version),
 		lifecycle.NewStartCmd(),
 		lifecycle.NewStopCmd(),
+		lifecycle.NewPauseCmd(),
+		lifecycle.NewUnpauseCmd(),
+		lifecycle.NewCommitCmd(),
+		lifecycle.NewUpCmd(),
 		lifecycle.NewRestartCmd(),
+		lifecycle.NewPortCmd(),
 		lifecycle.NewDestroyCmd(),
+		lifecycle.NewGCCmd(),
+		lifecycle.NewScrubCmd(),
 		lifecycle.NewResetCmd(),
+		lifecycle.NewUpgradeCmd(),
 		lifecycle.NewWaitCmd(),
 		mcp.NewCmd(),
 
 		// Workflow
 		workflow.NewAttachCmd(),
+		workflow.NewShellCmd(),
+		workflow.NewSendCmd(),
 		workflow.NewDiffCmd(),
 		workflow.NewApplyCmd(),
+		workflow.NewPublishCmd(),
+		workflow.NewPRCmd(),
 		workflow.NewBaselineCmd(),
+		workflow.NewRebaseCmd(),
 		workflow.NewFilesCmd(),
+		workflow.NewArtifactsCmd(),
+		workflow.NewDescribeCmd(),
+		workflow.NewServeCmd(),
 		xcmd.NewCmd(),
 
 		// Sandbox Tools
@@ -55,13 +75,24 @@ func registerCommands(root *cobra.Command, version, commit, date string) {
 		sandboxcmd.NewLogAliasCmd(),
 		sandboxcmd.NewExecAliasCmd(),
 		sandboxcmd.NewVscodeAliasCmd(),
+		sandboxcmd.NewNetworkCmd(),
+		sandboxcmd.NewDuCmd(),
+		sandboxcmd.NewCleanStateCmd(),
+		sandboxcmd.NewCostCmd(),
+		sandboxcmd.NewReplayCmd(),
+		sandboxcmd.NewTopCmd(),
+		sandboxcmd.NewStatuslineCmd(),
 
 		// Admin
 		system.NewCmd(version, commit, date),
 		doctorcmd.NewCmd(),
+		selftestcmd.NewCmd(),
+		daemoncmd.NewCmd(),
 		profile.NewCmd(),
 		helpcmd.NewCmd(),
+		helpcmd.NewExamplesCmd(),
 		configcmd.NewCmd(),
+		configcmd.NewSchemaCmd(),
 		versioncmd.NewCmd(version, commit, date),
 	)
 }
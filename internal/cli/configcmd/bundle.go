@@ -0,0 +1,160 @@
+package configcmd
+
+// ABOUTME: `config export` / `config import`: move the user's setup (config,
+// ABOUTME: profiles, agent definitions; never secrets) to another machine.
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/kstenerud/yoloai"
+	"github.com/kstenerud/yoloai/internal/cli/cliutil"
+	"github.com/kstenerud/yoloai/internal/fileutil"
+	"github.com/kstenerud/yoloai/yoerrors"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+func newConfigExportCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "export [file]",
+		Short: "Write a bundle of your setup for another machine",
+		Long: `Write a tar bundle of your yoloAI setup, for 'yoloai config import' on
+another machine: the global config (config.yaml), the defaults directory,
+every profile (config and Dockerfile), and your agent definitions.
+
+Secrets never go in. Credential files (.npmrc, *.pem, ...) are skipped, and
+secret values are cut out of the YAML files: a key whose name ends in KEY,
+TOKEN, SECRET or PASSWORD (ANTHROPIC_API_KEY, GH_TOKEN), or a value shaped
+like an API key.
+Everything left out is listed so you can set it up again; a ${VAR} reference
+to a host variable is kept, since the secret itself stays on the host.
+
+The bundle goes to stdout unless a file is named.`,
+		Example: `  yoloai config export > yoloai-setup.tar
+  yoloai config export yoloai-setup.tar`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: runConfigExport,
+	}
+}
+
+func runConfigExport(cmd *cobra.Command, args []string) error {
+	sys, err := cliutil.System()
+	if err != nil {
+		return err
+	}
+
+	var result *yoloai.ConfigExportResult
+	if len(args) == 0 || args[0] == "-" {
+		if cliutil.JSONEnabled(cmd) {
+			return yoerrors.NewUsageError("--json needs the bundle written to a file: yoloai config export <file> --json")
+		}
+		if f, ok := cmd.OutOrStdout().(*os.File); ok && term.IsTerminal(int(f.Fd())) { //nolint:gosec // G115: fd is a small int
+			return yoerrors.NewUsageError("refusing to write a tar bundle to the terminal: redirect it (yoloai config export > yoloai-setup.tar) or name a file")
+		}
+		if result, err = sys.Config().Export(cmd.Context(), cmd.OutOrStdout()); err != nil {
+			return err
+		}
+	} else {
+		f, err := fileutil.OpenFile(args[0], os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o600)
+		if err != nil {
+			return err
+		}
+		result, err = sys.Config().Export(cmd.Context(), f)
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	if cliutil.JSONEnabled(cmd) {
+		omitted := make([]map[string]string, 0, len(result.Omitted))
+		for _, o := range result.Omitted {
+			omitted = append(omitted, map[string]string{"path": o.Path, "key": o.Key, "reason": o.Reason})
+		}
+		return cliutil.WriteJSON(cmd.OutOrStdout(), map[string]any{
+			"file":    args[0], // --json is refused above when writing to stdout
+			"files":   cliutil.EmptyIfNil(result.Files),
+			"omitted": omitted,
+		})
+	}
+	// The bundle may be on stdout, so the report goes to stderr.
+	report := cmd.ErrOrStderr()
+	fmt.Fprintf(report, "Exported %d file(s)\n", len(result.Files)) //nolint:errcheck // best-effort output
+	printOmitted(report, result.Omitted)
+	return nil
+}
+
+// printOmitted lists the secrets an export left out.
+func printOmitted(w io.Writer, omitted []yoloai.ConfigBundleOmission) {
+	if len(omitted) == 0 {
+		return
+	}
+	fmt.Fprintln(w, "Left out, to set up again on the new machine:") //nolint:errcheck // best-effort output
+	for _, o := range omitted {
+		if o.Key != "" {
+			fmt.Fprintf(w, "  %s: %s (%s)\n", o.Path, o.Key, o.Reason) //nolint:errcheck // best-effort output
+		} else {
+			fmt.Fprintf(w, "  %s (%s)\n", o.Path, o.Reason) //nolint:errcheck // best-effort output
+		}
+	}
+}
+
+func newConfigImportCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "import <file>",
+		Short: "Restore a bundle written by 'config export'",
+		Long: `Restore a bundle written by 'yoloai config export': config, profiles and
+agent definitions. Use - to read the bundle from stdin.
+
+Nothing is written until the whole bundle has been checked. A file that
+already exists with different content stops the import, unless --force
+replaces it; files the bundle doesn't mention are left alone. Rebuild
+profile images afterwards with 'yoloai system build'.`,
+		Example: `  yoloai config import yoloai-setup.tar
+  ssh old-box yoloai config export | yoloai config import -`,
+		Args: cobra.ExactArgs(1),
+		RunE: runConfigImport,
+	}
+	cmd.Flags().Bool("force", false, "Replace files that already exist with different content")
+	return cmd
+}
+
+func runConfigImport(cmd *cobra.Command, args []string) error {
+	force, _ := cmd.Flags().GetBool("force")
+	sys, err := cliutil.System()
+	if err != nil {
+		return err
+	}
+
+	r := cmd.InOrStdin()
+	if args[0] != "-" {
+		f, err := os.Open(args[0])
+		if err != nil {
+			return err
+		}
+		defer f.Close() //nolint:errcheck // read-only
+		r = f
+	}
+
+	result, err := sys.Config().Import(cmd.Context(), r, yoloai.ConfigImportOptions{Overwrite: force})
+	if err != nil {
+		return err
+	}
+	if cliutil.JSONEnabled(cmd) {
+		return cliutil.WriteJSON(cmd.OutOrStdout(), map[string]any{
+			"written":   cliutil.EmptyIfNil(result.Written),
+			"unchanged": cliutil.EmptyIfNil(result.Unchanged),
+		})
+	}
+	out := cmd.OutOrStdout()
+	for _, p := range result.Written {
+		fmt.Fprintf(out, "Restored %s\n", p) //nolint:errcheck // best-effort output
+	}
+	_, err = fmt.Fprintf(out, "%d file(s) restored, %d already up to date\n", len(result.Written), len(result.Unchanged))
+	return err
+}
@@ -0,0 +1,42 @@
+package configcmd
+
+// ABOUTME: Tests for config export/import: a bundle written to a file restores
+// ABOUTME: into a fresh home, with the left-out secrets reported.
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/kstenerud/yoloai/internal/cli/clitest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfigExportImport(t *testing.T) {
+	dir := cliConfigDir(t)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "config.yaml"), []byte("agent: codex\nenv:\n  GH_TOKEN: ghp_secret\n"), 0o600))
+	bundle := filepath.Join(t.TempDir(), "setup.tar")
+
+	cmd := newConfigExportCmd()
+	var stderr bytes.Buffer
+	cmd.SetErr(&stderr)
+	cmd.SetArgs([]string{bundle})
+	require.NoError(t, cmd.Execute())
+	assert.Contains(t, stderr.String(), "Exported 1 file(s)")
+	assert.Contains(t, stderr.String(), "defaults/config.yaml: env.GH_TOKEN (secret value)")
+
+	home := clitest.Home(t)
+	cmd = newConfigImportCmd()
+	var stdout bytes.Buffer
+	cmd.SetOut(&stdout)
+	cmd.SetArgs([]string{bundle})
+	require.NoError(t, cmd.Execute())
+	assert.Contains(t, stdout.String(), "1 file(s) restored")
+
+	data, err := os.ReadFile(filepath.Join(home, ".yoloai", "library", "defaults", "config.yaml"))
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "agent: codex")
+	assert.NotContains(t, string(data), "ghp_secret")
+}
@@ -6,6 +6,8 @@ package configcmd
 import (
 	"errors"
 	"fmt"
+	"strings"
+	"text/tabwriter"
 
 	"github.com/kstenerud/yoloai"
 	"github.com/kstenerud/yoloai/internal/cli/cliutil"
@@ -25,35 +27,90 @@ func NewCmd() *cobra.Command {
 		newConfigGetCmd(),
 		newConfigSetCmd(),
 		newConfigResetCmd(),
+		newConfigExportCmd(),
+		newConfigImportCmd(),
+		newConfigPullCmd(),
 	)
 
 	return cmd
 }
 
 func newConfigGetCmd() *cobra.Command {
-	return &cobra.Command{
-		Use:   "get [key]",
-		Short: "Print configuration value(s)",
+	cmd := &cobra.Command{
+		Use:     "get [key]",
+		Aliases: []string{"show"},
+		Short:   "Print configuration value(s)",
 		Long: `Print configuration values.
 
 Without arguments, prints all settings with effective values (defaults + overrides).
 With a dotted key (e.g., container_backend), prints just that value.
 
 Global settings (tmux_conf, model_aliases) are stored in ~/.yoloai/config.yaml.
-Default settings are stored in ~/.yoloai/defaults/config.yaml.`,
+Default settings are stored in ~/.yoloai/defaults/config.yaml.
+
+Beneath both sit the system-wide settings a platform team manages:
+/etc/yoloai/config.yaml (or $YOLOAI_SYSTEM_CONFIG_DIR/config.yaml), and below
+that the org config 'yoloai config pull' fetches from org_config_url. Your own
+settings win; lists such as network.allow add up across the layers.
+
+--origin prints each value with where it came from: default, org:<url>,
+system:<path> or user:<path>. With a key, only that key and the keys under it.`,
+		Example: `  yoloai config get
+  yoloai config get network.allow
+  yoloai config show --origin
+  yoloai config show --origin network`,
 		Args: cobra.MaximumNArgs(1),
 		RunE: runConfigGet,
 	}
+	cmd.Flags().Bool("origin", false, "Show which config layer each value comes from")
+	return cmd
 }
 
 // runConfigGet implements the config get command body.
 func runConfigGet(cmd *cobra.Command, args []string) error {
+	if origin, _ := cmd.Flags().GetBool("origin"); origin {
+		prefix := ""
+		if len(args) > 0 {
+			prefix = args[0]
+		}
+		return configGetOrigins(cmd, prefix)
+	}
 	if len(args) == 0 {
 		return configGetAll(cmd)
 	}
 	return configGetKey(cmd, args[0])
 }
 
+// configGetOrigins prints each effective value under prefix ("" = all) with
+// the layer it came from.
+func configGetOrigins(cmd *cobra.Command, prefix string) error {
+	sys, err := cliutil.System()
+	if err != nil {
+		return err
+	}
+	origins, err := sys.Config().Origins(cmd.Context())
+	if err != nil {
+		return err
+	}
+	var shown []yoloai.ConfigOrigin
+	for _, o := range origins {
+		if prefix == "" || o.Key == prefix || strings.HasPrefix(o.Key, prefix+".") {
+			shown = append(shown, o)
+		}
+	}
+	if len(shown) == 0 {
+		return fmt.Errorf("%w: %s (run `yoloai config get` to list available keys)", yoloai.ErrConfigKeyNotFound, prefix)
+	}
+	if cliutil.JSONEnabled(cmd) {
+		return cliutil.WriteJSONList(cmd.OutOrStdout(), "settings", shown)
+	}
+	w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+	for _, o := range shown {
+		fmt.Fprintf(w, "%s: %s\t# %s\n", o.Key, o.Value, o.Origin) //nolint:errcheck
+	}
+	return w.Flush()
+}
+
 // configGetAll prints all effective configuration values.
 func configGetAll(cmd *cobra.Command) error {
 	sys, err := cliutil.System()
@@ -174,3 +231,47 @@ Default settings are stored in ~/.yoloai/defaults/config.yaml.`,
 		},
 	}
 }
+
+func newConfigPullCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "pull",
+		Short: "Fetch the org-wide config from org_config_url",
+		Long: `Fetch the org-wide config named by org_config_url and cache it, layered
+beneath the system config (/etc/yoloai/config.yaml) and your own.
+
+org_config_url is read from your ~/.yoloai/config.yaml or the system config,
+and must be an https URL. A document that fails to parse or sets an unknown
+key is rejected and the copy fetched last stays in use. With org_config_url
+unset, a cached org config is removed.
+
+'yoloai daemon' runs this on every sweep, so the org config stays current.`,
+		Example: `  yoloai config set org_config_url https://example.com/yoloai/config.yaml
+  yoloai config pull`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			sys, err := cliutil.System()
+			if err != nil {
+				return err
+			}
+			result, err := sys.Config().Pull(cmd.Context())
+			if err != nil {
+				return err
+			}
+			if cliutil.JSONEnabled(cmd) {
+				return cliutil.WriteJSON(cmd.OutOrStdout(), result)
+			}
+			out := cmd.OutOrStdout()
+			switch {
+			case result.URL == "" && result.Changed:
+				_, err = fmt.Fprintf(out, "org_config_url is not set; removed the cached org config %s\n", result.Path)
+			case result.URL == "":
+				_, err = fmt.Fprintln(out, "org_config_url is not set; nothing to pull")
+			case result.Changed:
+				_, err = fmt.Fprintf(out, "Updated the org config from %s\n", result.URL)
+			default:
+				_, err = fmt.Fprintf(out, "The org config from %s is up to date\n", result.URL)
+			}
+			return err
+		},
+	}
+}
@@ -1,14 +1,17 @@
 package configcmd
 
-// ABOUTME: Tests for the config get/set/reset CLI commands.
+// ABOUTME: Tests for the config get/set/reset and schema CLI commands.
 
 import (
 	"bytes"
+	"encoding/json"
 	"os"
 	"path/filepath"
+	"regexp"
 	"testing"
 
 	"github.com/kstenerud/yoloai/internal/cli/clitest"
+	"github.com/kstenerud/yoloai/internal/cli/cliutil"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -174,3 +177,64 @@ func TestConfigReset_UnknownKeyRejected(t *testing.T) {
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "config key not found: backend")
 }
+
+func TestConfigShow_Origin(t *testing.T) {
+	dir := cliConfigDir(t)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "config.yaml"), []byte("agent: gemini\n"), 0600))
+	sysDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(sysDir, "config.yaml"), []byte("agent: codex\nmodel: sys-model\n"), 0600))
+	l := cliutil.Layout()
+	l.SystemConfigDir = sysDir
+	cliutil.SetRootLayout(l)
+
+	cmd := NewCmd()
+	buf := new(bytes.Buffer)
+	cmd.SetOut(buf)
+	cmd.SetArgs([]string{"show", "--origin"})
+	require.NoError(t, cmd.Execute())
+
+	out := buf.String()
+	assert.Regexp(t, `(?m)^agent: gemini +# user:`+regexp.QuoteMeta(filepath.Join(dir, "config.yaml"))+`$`, out)
+	assert.Regexp(t, `(?m)^model: sys-model +# system:`+regexp.QuoteMeta(filepath.Join(sysDir, "config.yaml"))+`$`, out)
+	assert.Regexp(t, `(?m)^os: linux +# default$`, out)
+
+	cmd = NewCmd()
+	buf.Reset()
+	cmd.SetOut(buf)
+	cmd.SetArgs([]string{"show", "--origin", "resources"})
+	require.NoError(t, cmd.Execute())
+	assert.Contains(t, buf.String(), "resources.cpus: ")
+	assert.NotContains(t, buf.String(), "agent:")
+}
+
+func TestConfigPull_Unset(t *testing.T) {
+	_ = clitest.Home(t)
+
+	cmd := newConfigPullCmd()
+	buf := new(bytes.Buffer)
+	cmd.SetOut(buf)
+	cmd.SetArgs([]string{})
+	require.NoError(t, cmd.Execute())
+	assert.Equal(t, "org_config_url is not set; nothing to pull\n", buf.String())
+}
+
+func TestSchema_PrintsAndLists(t *testing.T) {
+	_ = clitest.Home(t)
+
+	cmd := NewSchemaCmd()
+	buf := new(bytes.Buffer)
+	cmd.SetOut(buf)
+	cmd.SetArgs([]string{"profile"})
+	require.NoError(t, cmd.Execute())
+	var schema map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &schema))
+	assert.Equal(t, "object", schema["type"])
+	assert.Contains(t, schema["properties"], "workdir")
+
+	cmd = NewSchemaCmd()
+	buf.Reset()
+	cmd.SetOut(buf)
+	cmd.SetArgs([]string{})
+	require.NoError(t, cmd.Execute())
+	assert.Contains(t, buf.String(), "docs/schemas/defaults.json")
+}
@@ -0,0 +1,80 @@
+package configcmd
+
+// ABOUTME: `yoloai schema` — prints the JSON Schema of a config file, for
+// ABOUTME: editors to validate and autocomplete config.yaml and profiles with.
+
+import (
+	"fmt"
+	"text/tabwriter"
+
+	"github.com/kstenerud/yoloai/internal/cli/cliutil"
+
+	"github.com/spf13/cobra"
+)
+
+// NewSchemaCmd creates the `yoloai schema` command.
+func NewSchemaCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "schema [config|defaults|profile|system]",
+		Short: "Print the JSON Schema of a config file",
+		Long: `Print the JSON Schema of a config file, for an editor to validate and
+autocomplete it with:
+
+  config     ~/.yoloai/config.yaml (global settings)
+  defaults   ~/.yoloai/defaults/config.yaml (sandbox defaults)
+  profile    ~/.yoloai/profiles/<name>/config.yaml
+  system     the system and org config, which take the keys of both
+
+With no argument, lists the schemas and the URLs they are published at.
+The schemas are generated from the structs yoloai parses each file into,
+and yoloai checks the files against them as it loads them: a wrong type,
+or an unknown key outside a profile, is an error naming its line.
+
+With the YAML language server (VS Code's YAML extension and others), put
+a comment like this at the top of a file:
+
+  # yaml-language-server: $schema=<url>`,
+		Example: cliutil.FormatExamples([]cliutil.Example{
+			{Cmd: "yoloai schema", Note: "list the schemas and their URLs"},
+			{Cmd: "yoloai schema profile > profile.schema.json", Note: "save one for an editor"},
+		}),
+		GroupID: cliutil.GroupAdmin,
+		Args:    cobra.MaximumNArgs(1),
+		ValidArgs: []string{
+			"config\tglobal config.yaml",
+			"defaults\tdefaults/config.yaml",
+			"profile\ta profile's config.yaml",
+			"system\tsystem and org config",
+		},
+		RunE: runSchema,
+	}
+}
+
+func runSchema(cmd *cobra.Command, args []string) error {
+	sys, err := cliutil.System()
+	if err != nil {
+		return err
+	}
+	admin := sys.Config()
+	out := cmd.OutOrStdout()
+
+	if len(args) == 1 {
+		data, err := admin.Schema(cmd.Context(), args[0])
+		if err != nil {
+			return err
+		}
+		_, err = out.Write(data)
+		return err
+	}
+
+	schemas := admin.Schemas()
+	if cliutil.JSONEnabled(cmd) {
+		return cliutil.WriteJSON(out, map[string]any{"schemas": schemas})
+	}
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "SCHEMA\tFILE\tURL") //nolint:errcheck // best-effort output
+	for _, s := range schemas {
+		fmt.Fprintf(w, "%s\t%s\t%s\n", s.Name, s.File, s.URL) //nolint:errcheck // best-effort output
+	}
+	return w.Flush()
+}
@@ -0,0 +1,287 @@
+// ABOUTME: `yoloai daemon` — the background sweeper that runs periodic upkeep
+// ABOUTME: (gc: TTL expiry and the retention scrub), publishes sandbox events, serves
+// ABOUTME: the local API `ls` lists through, and can notify when an agent goes quiet.
+package daemoncmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kstenerud/yoloai"
+	"github.com/kstenerud/yoloai/internal/cli/cliutil"
+	"github.com/kstenerud/yoloai/internal/daemonapi"
+	"github.com/kstenerud/yoloai/internal/events"
+	"github.com/kstenerud/yoloai/internal/fileutil"
+	"github.com/kstenerud/yoloai/internal/sysexec"
+	"github.com/kstenerud/yoloai/yoerrors"
+	"github.com/spf13/cobra"
+)
+
+// defaultInterval is how often the daemon sweeps.
+const defaultInterval = 15 * time.Minute
+
+// defaultEventsInterval is how often the daemon lists sandboxes to publish
+// events on its socket.
+const defaultEventsInterval = 5 * time.Second
+
+// sweeps are the yoloai commands the daemon runs on every tick. Each runs as
+// its own process of this binary, so a sweep picks up config changes, and one
+// that fails or hangs on a backend can't take the daemon down with it.
+var sweeps = [][]string{
+	{"gc"},
+	{"config", "pull"},
+	{"network", "refresh"},
+}
+
+// executable resolves the binary the sweeps (and an installed service) run.
+// A variable so tests can point it at a stub.
+var executable = os.Executable
+
+func NewCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "daemon",
+		Short: "Run background upkeep, or install it as a login service",
+		Long: `The yoloAI daemon runs background upkeep on a timer, so it happens
+without a terminal staying open: today that is 'yoloai gc', which destroys
+sandboxes whose TTL has run out and applies the retention_days scrub,
+'yoloai config pull', which refreshes the org config from org_config_url, and
+'yoloai network refresh', which re-resolves the allowed domains of running
+network-isolated sandboxes so their firewalls follow rotating CDN addresses.
+
+'daemon install' registers it as a per-user service that starts at login and
+restarts if it dies: a launchd agent on macOS, a systemd user unit on Linux.
+'daemon status' and 'daemon uninstall' manage it. 'daemon run' is what the
+service runs, and works in the foreground too.
+
+While it runs, the daemon also publishes sandbox lifecycle and status changes
+on a unix socket, so status-bar widgets and scripts can subscribe instead of
+polling 'yoloai ls'. 'daemon events' prints them. It answers 'yoloai ls' too,
+from backends it keeps open, which spares inspecting every sandbox anew. When a sandbox is created,
+its agent needs input, or it finishes or fails, the daemon notifies you as the
+notifications config says: notifications.webhook_url gets a JSON event and
+notifications.slack_webhook a Slack message for each, notifications.desktop a
+desktop notification for exits. With --notify-idle it also posts a desktop
+notification when an agent goes quiet.`,
+		GroupID: cliutil.GroupAdmin,
+	}
+	cmd.AddCommand(newRunCmd(), newEventsCmd(), newInstallCmd(), newStatusCmd(), newUninstallCmd())
+	return cmd
+}
+
+func newRunCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "run",
+		Short: "Run the background sweeps in the foreground until interrupted",
+		Long: `Run the background sweeps in the foreground until interrupted.
+
+Unless --once is given, the daemon also lists sandboxes every
+--events-interval and publishes what changed on the unix socket
+~/.yoloai/cli/events.sock, one JSON object per line; see 'yoloai daemon
+events'. --events-interval 0 turns the socket off. It also serves its local
+API on ~/.yoloai/cli/daemon.sock, which 'yoloai ls' lists through.
+
+--notify-idle shows a desktop notification (osascript on macOS, notify-send
+on Linux) when a running agent has printed nothing for that long (2m when no
+duration is given, as --notify-idle=10m otherwise): it is waiting for input,
+or may be stuck. Each quiet stretch is announced once, and agents that are
+already quiet when the daemon starts are not announced.`,
+		Example: `  yoloai daemon run
+  yoloai daemon run --interval 5m
+  yoloai daemon run --notify-idle
+  yoloai daemon run --once`,
+		Args: cobra.NoArgs,
+		RunE: runDaemon,
+	}
+	cmd.Flags().Duration("interval", defaultInterval, "Time between sweeps")
+	cmd.Flags().Bool("once", false, "Sweep once and exit")
+	cmd.Flags().Duration("events-interval", defaultEventsInterval, "Time between sandbox listings for the events socket (0 disables it)")
+	addNotifyIdleFlag(cmd)
+	return cmd
+}
+
+// addNotifyIdleFlag adds --notify-idle, which takes an optional duration.
+func addNotifyIdleFlag(cmd *cobra.Command) {
+	cmd.Flags().Duration("notify-idle", 0, "Notify on the desktop when an agent has printed nothing for this long (default 2m when given without a value)")
+	cmd.Flags().Lookup("notify-idle").NoOptDefVal = defaultNotifyIdle.String()
+}
+
+// notifyIdleFlag reads --notify-idle; 0 means off.
+func notifyIdleFlag(cmd *cobra.Command) (time.Duration, error) {
+	after, _ := cmd.Flags().GetDuration("notify-idle")
+	if after < 0 {
+		return 0, yoerrors.NewUsageError("--notify-idle must be positive: %s", after)
+	}
+	return after, nil
+}
+
+func runDaemon(cmd *cobra.Command, _ []string) error {
+	interval, _ := cmd.Flags().GetDuration("interval")
+	once, _ := cmd.Flags().GetBool("once")
+	eventsInterval, _ := cmd.Flags().GetDuration("events-interval")
+	if interval < time.Minute {
+		return yoerrors.NewUsageError("--interval must be at least 1m: %s", interval)
+	}
+	if eventsInterval != 0 && eventsInterval < time.Second {
+		return yoerrors.NewUsageError("--events-interval must be 0 or at least 1s: %s", eventsInterval)
+	}
+	notifyIdle, err := notifyIdleFlag(cmd)
+	if err != nil {
+		return err
+	}
+	if once && notifyIdle > 0 {
+		return yoerrors.NewUsageError("--notify-idle needs a running daemon and does nothing with --once")
+	}
+	exe, err := executable()
+	if err != nil {
+		return fmt.Errorf("resolve own executable: %w", err)
+	}
+
+	ctx, cancel := context.WithCancel(cmd.Context())
+	defer cancel()
+	out := cmd.OutOrStdout()
+	if !once {
+		fmt.Fprintf(out, "%s daemon started, sweeping every %s\n", stamp(), interval) //nolint:errcheck // best-effort output
+		// The events, notification and idle watches list on their own timers; one
+		// lister lets them share its runtimes and inspect caches. It is
+		// closed after the watches have stopped.
+		var lister *yoloai.SandboxLister
+		if sys, err := cliutil.System(); err != nil {
+			fmt.Fprintf(cmd.ErrOrStderr(), "%s sandbox watching disabled: %v\n", stamp(), err) //nolint:errcheck // best-effort output
+		} else {
+			lister = sys.NewSandboxLister()
+			defer lister.Close() //nolint:errcheck // best-effort cleanup
+		}
+		var wg sync.WaitGroup
+		defer wg.Wait()
+		defer cancel() // runs before the Wait
+		if lister != nil {
+			serveAPI(ctx, &wg, lister, out, cmd.ErrOrStderr())
+			if eventsInterval > 0 {
+				publishEvents(ctx, &wg, lister, eventsInterval, out, cmd.ErrOrStderr())
+			}
+			watchNotifications(ctx, &wg, lister, out, cmd.ErrOrStderr())
+			if notifyIdle > 0 {
+				watchIdle(ctx, &wg, lister, notifyIdle, out, cmd.ErrOrStderr())
+			}
+		}
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		failed := runSweeps(ctx, exe, out, cmd.ErrOrStderr())
+		if once {
+			if failed > 0 {
+				return fmt.Errorf("%d sweep(s) failed", failed)
+			}
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			fmt.Fprintf(out, "%s daemon stopped\n", stamp()) //nolint:errcheck // best-effort output
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// publishEvents starts serving sandbox events on the events socket, listing
+// sandboxes every interval, until ctx is cancelled. Problems are logged and
+// leave the sweeps running: the socket is a convenience, and another daemon
+// already serving it is not an error.
+func publishEvents(ctx context.Context, wg *sync.WaitGroup, lister *yoloai.SandboxLister, interval time.Duration, stdout, stderr io.Writer) {
+	path := cliutil.CLIEventsSocketPath()
+	if err := fileutil.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		fmt.Fprintf(stderr, "%s events socket disabled: %v\n", stamp(), err) //nolint:errcheck // best-effort output
+		return
+	}
+	ln, err := events.ListenSocket(path)
+	if err != nil {
+		fmt.Fprintf(stderr, "%s events socket disabled: %v\n", stamp(), err) //nolint:errcheck // best-effort output
+		return
+	}
+	fmt.Fprintf(stdout, "%s publishing events on %s\n", stamp(), path) //nolint:errcheck // best-effort output
+
+	hub := events.NewHub()
+	// A listing failure is logged once, not on every tick until it clears.
+	failing := false
+	list := func(ctx context.Context) ([]*yoloai.SandboxInfo, []yoloai.BackendType, error) {
+		infos, unavailable, err := lister.List(ctx)
+		if err == nil {
+			failing = false
+		}
+		return infos, unavailable, err
+	}
+	wg.Go(func() {
+		hub.Watch(ctx, interval, list, func(err error) {
+			if !failing {
+				fmt.Fprintf(stderr, "%s listing sandboxes for events failed: %v\n", stamp(), err) //nolint:errcheck // best-effort output
+			}
+			failing = true
+		})
+	})
+	wg.Go(func() {
+		if err := events.ServeSocket(ctx, ln, hub); err != nil {
+			fmt.Fprintf(stderr, "%s events socket: %v\n", stamp(), err) //nolint:errcheck // best-effort output
+		}
+	})
+}
+
+// serveAPI starts the daemon's local API (internal/daemonapi) on its socket
+// until ctx is cancelled. Listings go through the daemon's lister, so `yoloai
+// ls` gets them from runtimes that are already open and caching inspects.
+// Problems are logged and leave the sweeps running; `ls` lists by itself when
+// no daemon answers.
+func serveAPI(ctx context.Context, wg *sync.WaitGroup, lister *yoloai.SandboxLister, stdout, stderr io.Writer) {
+	path := cliutil.CLIDaemonSocketPath()
+	if err := fileutil.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		fmt.Fprintf(stderr, "%s API socket disabled: %v\n", stamp(), err) //nolint:errcheck // best-effort output
+		return
+	}
+	ln, err := events.ListenSocket(path)
+	if err != nil {
+		fmt.Fprintf(stderr, "%s API socket disabled: %v\n", stamp(), err) //nolint:errcheck // best-effort output
+		return
+	}
+	fmt.Fprintf(stdout, "%s serving the API on %s\n", stamp(), path) //nolint:errcheck // best-effort output
+	wg.Go(func() {
+		if err := daemonapi.Serve(ctx, ln, daemonapi.NewHandler(lister.List)); err != nil {
+			fmt.Fprintf(stderr, "%s API socket: %v\n", stamp(), err) //nolint:errcheck // best-effort output
+		}
+	})
+}
+
+// runSweeps runs every sweep once, in order, and returns how many failed. A
+// failure is logged and does not stop the others.
+func runSweeps(ctx context.Context, exe string, stdout, stderr io.Writer) int {
+	failed := 0
+	for _, sweep := range sweeps {
+		if ctx.Err() != nil {
+			return failed
+		}
+		args := append([]string{"--data-dir", cliutil.TopDir()}, sweep...)
+		// The child is yoloai itself, which curates what it passes on at its own
+		// edge, so it gets the environment this process was started with.
+		c := sysexec.CommandContext(ctx, cliutil.Layout().Env().PassthroughEnv(), exe, args...)
+		c.Stdout = stdout
+		c.Stderr = stderr
+		name := strings.Join(sweep, " ")
+		fmt.Fprintf(stdout, "%s %s\n", stamp(), name) //nolint:errcheck // best-effort output
+		if err := c.Run(); err != nil && ctx.Err() == nil {
+			failed++
+			fmt.Fprintf(stderr, "%s %s failed: %v\n", stamp(), name, err) //nolint:errcheck // best-effort output
+		}
+	}
+	return failed
+}
+
+// stamp prefixes a daemon log line, which ends up in a log file when the
+// daemon runs as a service.
+func stamp() string {
+	return time.Now().Format(time.RFC3339)
+}
@@ -0,0 +1,284 @@
+package daemoncmd
+
+// ABOUTME: Tests for the daemon: sweeps run as child processes, service files
+// ABOUTME: render and quote correctly, install/uninstall drive the manager, and
+// ABOUTME: the events and API sockets are served.
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	goruntime "runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kstenerud/yoloai"
+	"github.com/kstenerud/yoloai/internal/cli/clitest"
+	"github.com/kstenerud/yoloai/internal/cli/cliutil"
+	"github.com/kstenerud/yoloai/internal/daemonapi"
+	"github.com/kstenerud/yoloai/internal/events"
+	"github.com/kstenerud/yoloai/internal/fileutil"
+	"github.com/kstenerud/yoloai/yoerrors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubExecutable points the daemon at a script that records its arguments,
+// and returns the file they are recorded in.
+func stubExecutable(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	record := filepath.Join(dir, "args")
+	script := filepath.Join(dir, "yoloai")
+	require.NoError(t, os.WriteFile(script, []byte("#!/bin/sh\necho \"$@\" >> "+record+"\n"), 0o700)) //nolint:gosec // G306: test stub must be executable
+	old := executable
+	executable = func() (string, error) { return script, nil }
+	t.Cleanup(func() { executable = old })
+	return record
+}
+
+func TestDaemonRunOnce(t *testing.T) {
+	if goruntime.GOOS == "windows" {
+		t.Skip("shell stub")
+	}
+	clitest.Home(t)
+	record := stubExecutable(t)
+
+	cmd := newRunCmd()
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"--once"})
+	require.NoError(t, cmd.Execute())
+
+	data, err := os.ReadFile(record)
+	require.NoError(t, err)
+	prefix := "--data-dir " + cliutil.TopDir()
+	assert.Equal(t, prefix+" gc\n"+prefix+" config pull\n"+prefix+" network refresh\n", string(data))
+	assert.Contains(t, out.String(), " gc\n")
+	assert.Contains(t, out.String(), " config pull\n")
+	assert.Contains(t, out.String(), " network refresh\n")
+}
+
+func TestDaemonRun_IntervalTooShort(t *testing.T) {
+	clitest.Home(t)
+	cmd := newRunCmd()
+	cmd.SetArgs([]string{"--interval", "10s"})
+	assert.ErrorContains(t, cmd.Execute(), "at least 1m")
+}
+
+func TestHostService(t *testing.T) {
+	svc, err := hostService("darwin", "/Users/me", "/Users/me/.yoloai/cli/daemon.log")
+	require.NoError(t, err)
+	assert.Equal(t, "/Users/me/Library/LaunchAgents/com.yoloai.daemon.plist", svc.path)
+	assert.Equal(t, "/Users/me/.yoloai/cli/daemon.log", svc.logHint())
+
+	svc, err = hostService("linux", "/home/me", "ignored")
+	require.NoError(t, err)
+	assert.Equal(t, "/home/me/.config/systemd/user/yoloai-daemon.service", svc.path)
+	assert.Equal(t, "journalctl --user -u yoloai-daemon.service", svc.logHint())
+
+	_, err = hostService("windows", `C:\Users\me`, "")
+	assert.ErrorContains(t, err, "daemon run")
+}
+
+func TestLaunchdPlist(t *testing.T) {
+	plist := launchdPlist([]string{"/opt/homebrew/bin/yoloai", "daemon", "run"},
+		map[string]string{"PATH": "/opt/homebrew/bin:/usr/bin", "DOCKER_HOST": "unix:///a&b.sock"}, "/tmp/daemon.log")
+	assert.Contains(t, plist, "<string>com.yoloai.daemon</string>")
+	assert.Contains(t, plist, "\t\t<string>/opt/homebrew/bin/yoloai</string>\n\t\t<string>daemon</string>")
+	assert.Contains(t, plist, "<key>DOCKER_HOST</key>\n\t\t<string>unix:///a&amp;b.sock</string>")
+	assert.Contains(t, plist, "<key>KeepAlive</key>\n\t<true/>")
+	assert.Contains(t, plist, "<string>/tmp/daemon.log</string>")
+}
+
+func TestSystemdUnit(t *testing.T) {
+	unit := systemdUnit([]string{"/usr/local/bin/yoloai", "--data-dir", "/home/me/my data", "daemon", "run"},
+		map[string]string{"PATH": "/usr/bin:/odd%dir"})
+	assert.Contains(t, unit, `ExecStart="/usr/local/bin/yoloai" "--data-dir" "/home/me/my data" "daemon" "run"`)
+	assert.Contains(t, unit, `Environment="PATH=/usr/bin:/odd%%dir"`)
+	assert.Contains(t, unit, "Restart=on-failure")
+	assert.Contains(t, unit, "WantedBy=default.target")
+}
+
+func TestInstallUninstall_Systemd(t *testing.T) {
+	if goruntime.GOOS != "linux" {
+		t.Skip("systemd is the Linux service manager")
+	}
+	home := clitest.Home(t)
+	stubExecutable(t)
+	var calls []string
+	old := serviceCtl
+	serviceCtl = func(_ context.Context, name string, args ...string) (string, error) {
+		calls = append(calls, name+" "+strings.Join(args, " "))
+		return "active", nil
+	}
+	t.Cleanup(func() { serviceCtl = old })
+
+	cmd := newInstallCmd()
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"--interval", "5m"})
+	require.NoError(t, cmd.Execute())
+
+	unitPath := filepath.Join(home, ".config", "systemd", "user", systemdUnitName)
+	unit, err := os.ReadFile(unitPath)
+	require.NoError(t, err)
+	assert.Contains(t, string(unit), `"daemon" "run" "--interval" "5m0s"`)
+	assert.Equal(t, []string{
+		"systemctl --user daemon-reload",
+		"systemctl --user enable yoloai-daemon.service",
+		"systemctl --user restart yoloai-daemon.service",
+	}, calls)
+
+	calls = nil
+	cmd = newStatusCmd()
+	out.Reset()
+	cmd.SetOut(&out)
+	require.NoError(t, cmd.Execute())
+	assert.Contains(t, out.String(), "State: running (active)")
+
+	calls = nil
+	cmd = newUninstallCmd()
+	cmd.SetOut(&out)
+	require.NoError(t, cmd.Execute())
+	assert.NoFileExists(t, unitPath)
+	assert.Equal(t, "systemctl --user disable --now yoloai-daemon.service", calls[0])
+}
+
+func TestDaemonRun_PublishesEvents(t *testing.T) {
+	if goruntime.GOOS == "windows" {
+		t.Skip("shell stub")
+	}
+	clitest.Home(t)
+	stubExecutable(t)
+	if len(cliutil.CLIEventsSocketPath()) > 100 {
+		t.Skip("temp dir too deep for a unix socket path")
+	}
+
+	ctx, cancel := context.WithCancel(t.Context())
+	cmd := newRunCmd()
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetErr(&out)
+	cmd.SetArgs([]string{"--events-interval", "1s"})
+	done := make(chan error, 1)
+	go func() { done <- cmd.ExecuteContext(ctx) }()
+
+	require.Eventually(t, func() bool {
+		conn, err := net.Dial("unix", cliutil.CLIEventsSocketPath())
+		if err == nil {
+			conn.Close() //nolint:errcheck,gosec // probe
+		}
+		return err == nil
+	}, 10*time.Second, 20*time.Millisecond)
+	cancel()
+	require.NoError(t, <-done)
+	assert.Contains(t, out.String(), "publishing events on "+cliutil.CLIEventsSocketPath())
+}
+
+func TestDaemonRun_ServesAPI(t *testing.T) {
+	if goruntime.GOOS == "windows" {
+		t.Skip("shell stub")
+	}
+	clitest.Home(t)
+	stubExecutable(t)
+	if len(cliutil.CLIDaemonSocketPath()) > 100 {
+		t.Skip("temp dir too deep for a unix socket path")
+	}
+
+	ctx, cancel := context.WithCancel(t.Context())
+	cmd := newRunCmd()
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetErr(&out)
+	cmd.SetArgs([]string{"--events-interval", "0"})
+	done := make(chan error, 1)
+	go func() { done <- cmd.ExecuteContext(ctx) }()
+
+	client := daemonapi.NewClient(cliutil.CLIDaemonSocketPath())
+	var st daemonapi.Status
+	require.Eventually(t, func() bool {
+		var err error
+		st, err = client.Status(t.Context())
+		return err == nil
+	}, 10*time.Second, 20*time.Millisecond)
+	assert.Equal(t, os.Getpid(), st.PID)
+	cancel()
+	require.NoError(t, <-done)
+	assert.Contains(t, out.String(), "serving the API on "+cliutil.CLIDaemonSocketPath())
+	_, err := os.Stat(cliutil.CLIDaemonSocketPath())
+	assert.True(t, os.IsNotExist(err), "the socket is removed on shutdown")
+}
+
+func TestDaemonRun_EventsIntervalTooShort(t *testing.T) {
+	clitest.Home(t)
+	cmd := newRunCmd()
+	cmd.SetArgs([]string{"--events-interval", "10ms"})
+	assert.ErrorContains(t, cmd.Execute(), "at least 1s")
+}
+
+func TestDaemonEvents(t *testing.T) {
+	clitest.Home(t)
+	if len(cliutil.CLIEventsSocketPath()) > 100 {
+		t.Skip("temp dir too deep for a unix socket path")
+	}
+	cmd := newEventsCmd()
+	cmd.SetOut(&bytes.Buffer{})
+	var usage *yoerrors.UsageError
+	require.ErrorAs(t, cmd.Execute(), &usage, "no daemon is running")
+
+	require.NoError(t, fileutil.MkdirAll(cliutil.CLIDir(), 0o750))
+	ln, err := events.ListenSocket(cliutil.CLIEventsSocketPath())
+	require.NoError(t, err)
+	hub := events.NewHub()
+	hub.Update([]*yoloai.SandboxInfo{{Environment: &yoloai.Environment{Name: "one"}, Status: yoloai.StatusActive}}, nil)
+	ctx, cancel := context.WithCancel(t.Context())
+	served := make(chan error, 1)
+	go func() { served <- events.ServeSocket(ctx, ln, hub) }()
+
+	// The daemon going away ends the command with an error, after the
+	// snapshot it sent has been printed.
+	pr, pw := io.Pipe()
+	cmd = newEventsCmd()
+	cmd.SetOut(pw)
+	cmd.SilenceUsage = true
+	cmd.SetErr(io.Discard)
+	line := make(chan string, 1)
+	go func() {
+		l, _ := bufio.NewReader(pr).ReadString('\n')
+		line <- l
+		cancel()
+		io.Copy(io.Discard, pr) //nolint:errcheck,gosec // drain
+	}()
+	assert.ErrorContains(t, cmd.Execute(), "closed the events socket")
+	pw.Close() //nolint:errcheck,gosec // test
+	require.NoError(t, <-served)
+	assert.Regexp(t, `^\d\d:\d\d:\d\d one: active\n$`, <-line)
+}
+
+func TestFormatEvent(t *testing.T) {
+	at := time.Date(2026, 1, 2, 15, 4, 5, 0, time.Local)
+	ev := events.Event{Time: at, Sandbox: "s", Status: yoloai.StatusIdle, PreviousStatus: yoloai.StatusActive, Changes: "yes"}
+	for typ, want := range map[string]string{
+		events.TypeSnapshot:  "15:04:05 s: idle",
+		events.TypeCreated:   "15:04:05 s: created (idle)",
+		events.TypeStatus:    "15:04:05 s: active -> idle",
+		events.TypeChanges:   "15:04:05 s: unapplied changes: yes",
+		events.TypeDestroyed: "15:04:05 s: destroyed",
+	} {
+		ev.Type = typ
+		assert.Equal(t, want, formatEvent(ev))
+	}
+}
+
+func TestPrintEvents_JSON(t *testing.T) {
+	var out bytes.Buffer
+	line := `{"type":"created","sandbox":"s"}` + "\n"
+	require.NoError(t, printEvents(strings.NewReader(line), &out, true))
+	assert.Equal(t, line, out.String(), "JSON lines pass through as the daemon sent them")
+}
@@ -0,0 +1,103 @@
+// ABOUTME: `yoloai daemon events` — subscribe to the running daemon's events
+// ABOUTME: socket and print sandbox lifecycle and status changes as they happen.
+package daemoncmd
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+
+	"github.com/kstenerud/yoloai/internal/cli/cliutil"
+	"github.com/kstenerud/yoloai/internal/events"
+	"github.com/kstenerud/yoloai/yoerrors"
+	"github.com/spf13/cobra"
+)
+
+func newEventsCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "events",
+		Short: "Print sandbox lifecycle and status changes as they happen",
+		Long: `Subscribe to the events the running daemon publishes and print them until
+interrupted. It starts with the current state of every sandbox, then prints
+each sandbox that is created or destroyed, changes status, or gains or loses
+unapplied changes.
+
+With --json each event is printed as the daemon sends it, one JSON object per
+line with the fields time, type (snapshot, created, status, changes,
+destroyed), sandbox, status, previous_status, changes and backend. Tools can
+also read the socket directly: it is ~/.yoloai/cli/events.sock, and speaks
+the same lines.`,
+		Example: `  yoloai daemon events
+  yoloai daemon events --json | jq -r 'select(.type=="status") | .sandbox+" "+.status'
+  socat - UNIX-CONNECT:$HOME/.yoloai/cli/events.sock`,
+		Args: cobra.NoArgs,
+		RunE: runEvents,
+	}
+}
+
+func runEvents(cmd *cobra.Command, _ []string) error {
+	path := cliutil.CLIEventsSocketPath()
+	var d net.Dialer
+	conn, err := d.DialContext(cmd.Context(), "unix", path)
+	if err != nil {
+		return yoerrors.NewUsageError("no daemon is publishing events at %s: start one with 'yoloai daemon run' or 'yoloai daemon install'", path)
+	}
+	defer conn.Close()                                                //nolint:errcheck // read-only
+	stop := context.AfterFunc(cmd.Context(), func() { conn.Close() }) //nolint:errcheck,gosec // unblocks the read on interrupt
+	defer stop()
+
+	err = printEvents(conn, cmd.OutOrStdout(), cliutil.JSONEnabled(cmd))
+	if cmd.Context().Err() != nil {
+		return nil
+	}
+	if err == nil {
+		return errors.New("the daemon closed the events socket")
+	}
+	return err
+}
+
+// printEvents copies the event lines from r to w, as they are with asJSON and
+// as one readable line each otherwise, until r ends.
+func printEvents(r io.Reader, w io.Writer, asJSON bool) error {
+	sc := bufio.NewScanner(r)
+	for sc.Scan() {
+		if asJSON {
+			if _, err := fmt.Fprintf(w, "%s\n", sc.Bytes()); err != nil {
+				return err
+			}
+			continue
+		}
+		var ev events.Event
+		if err := json.Unmarshal(sc.Bytes(), &ev); err != nil {
+			return fmt.Errorf("unreadable event from the daemon: %w", err)
+		}
+		if _, err := fmt.Fprintln(w, formatEvent(ev)); err != nil {
+			return err
+		}
+	}
+	return sc.Err()
+}
+
+// formatEvent renders an event as one line for a terminal.
+func formatEvent(ev events.Event) string {
+	var what string
+	switch ev.Type {
+	case events.TypeSnapshot:
+		what = string(ev.Status)
+	case events.TypeCreated:
+		what = "created (" + string(ev.Status) + ")"
+	case events.TypeStatus:
+		what = string(ev.PreviousStatus) + " -> " + string(ev.Status)
+	case events.TypeChanges:
+		what = "unapplied changes: " + string(ev.Changes)
+	case events.TypeDestroyed:
+		what = "destroyed"
+	default:
+		what = ev.Type
+	}
+	return fmt.Sprintf("%s %s: %s", ev.Time.Local().Format("15:04:05"), ev.Sandbox, what)
+}
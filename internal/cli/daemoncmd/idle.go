@@ -0,0 +1,129 @@
+// ABOUTME: `daemon run --notify-idle`: a desktop notification when a running agent
+// ABOUTME: stops producing output, once per quiet stretch.
+package daemoncmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/kstenerud/yoloai"
+	"github.com/kstenerud/yoloai/internal/cli/cliutil"
+	"github.com/kstenerud/yoloai/internal/notify"
+)
+
+// defaultNotifyIdle is how long an agent must go without output before
+// --notify-idle, given without a value, says so.
+const defaultNotifyIdle = 2 * time.Minute
+
+// idleCheckInterval is how often the daemon lists sandboxes for --notify-idle.
+// A notification a few seconds late costs nothing, so it is well above the
+// events socket's default.
+const idleCheckInterval = 15 * time.Second
+
+// desktopNotify shows a notification. A variable so tests can record them.
+var desktopNotify = func(ctx context.Context, title, message string) error {
+	return notify.Desktop(ctx, cliutil.Layout().Env().EnvForDesktopNotify(), title, message)
+}
+
+// idleWatcher tracks which running agents have gone quiet, from the output
+// heartbeat on each listing (SandboxInfo.LastOutput), so each quiet stretch
+// is announced once.
+type idleWatcher struct {
+	after time.Duration
+	// told maps each quiet sandbox to the LastOutput of the stretch it was
+	// announced for. When output resumes the entry goes, so the next
+	// stretch is announced again.
+	told   map[string]time.Time
+	primed bool
+}
+
+// quietSandbox is one sandbox an observation found newly quiet.
+type quietSandbox struct {
+	name    string
+	message string
+}
+
+// observe takes a listing made at now and returns the running agents that
+// have now been quiet for w.after and weren't announced for this stretch yet.
+// The first listing only records what is already quiet: the daemon starting
+// up isn't news. A sandbox without a heartbeat is never reported.
+func (w *idleWatcher) observe(infos []*yoloai.SandboxInfo, now time.Time) []quietSandbox {
+	told := make(map[string]time.Time)
+	var fresh []quietSandbox
+	for _, info := range infos {
+		if info.Environment == nil || info.LastOutput.IsZero() {
+			continue
+		}
+		if info.Status != yoloai.StatusActive && info.Status != yoloai.StatusIdle {
+			continue
+		}
+		quiet := now.Sub(info.LastOutput)
+		if quiet < w.after {
+			continue
+		}
+		name := info.Environment.Name
+		told[name] = info.LastOutput
+		if !w.primed || w.told[name].Equal(info.LastOutput) {
+			continue
+		}
+		fresh = append(fresh, quietSandbox{name: name, message: idleMessage(info.Status, quiet)})
+	}
+	w.told, w.primed = told, true
+	return fresh
+}
+
+// idleMessage is the notification text for an agent quiet for that long.
+func idleMessage(status yoloai.Status, quiet time.Duration) string {
+	if status == yoloai.StatusIdle {
+		return fmt.Sprintf("Waiting for input (idle for %s)", cliutil.FormatDuration(quiet))
+	}
+	return fmt.Sprintf("No output for %s; it may be stuck", cliutil.FormatDuration(quiet))
+}
+
+// watchIdle lists sandboxes every idleCheckInterval and sends a desktop
+// notification for each agent that has gone quiet for after, until ctx is
+// cancelled. A failing listing or notifier is logged once per failing streak.
+func watchIdle(ctx context.Context, wg *sync.WaitGroup, lister *yoloai.SandboxLister, after time.Duration, stdout, stderr io.Writer) {
+	fmt.Fprintf(stdout, "%s notifying when an agent is quiet for %s\n", stamp(), after) //nolint:errcheck // best-effort output
+	w := &idleWatcher{after: after}
+	wg.Go(func() {
+		ticker := time.NewTicker(idleCheckInterval)
+		defer ticker.Stop()
+		failing := false
+		for {
+			if err := checkIdle(ctx, lister, w, stdout); err != nil && ctx.Err() == nil {
+				if !failing {
+					fmt.Fprintf(stderr, "%s idle notifications: %v\n", stamp(), err) //nolint:errcheck // best-effort output
+				}
+				failing = true
+			} else {
+				failing = false
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	})
+}
+
+// checkIdle runs one listing through w and notifies about what it found.
+func checkIdle(ctx context.Context, lister *yoloai.SandboxLister, w *idleWatcher, stdout io.Writer) error {
+	infos, _, err := lister.List(ctx)
+	if err != nil {
+		return fmt.Errorf("list sandboxes: %w", err)
+	}
+	var errs []error
+	for _, q := range w.observe(infos, time.Now()) {
+		fmt.Fprintf(stdout, "%s %s: %s\n", stamp(), q.name, q.message) //nolint:errcheck // best-effort output
+		if err := desktopNotify(ctx, "yoloai: "+q.name, q.message); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
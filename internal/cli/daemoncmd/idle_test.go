@@ -0,0 +1,92 @@
+package daemoncmd
+
+// ABOUTME: Tests for --notify-idle: each quiet stretch is announced once, the first
+// ABOUTME: listing only primes, and the flag reaches the installed service.
+
+import (
+	"bytes"
+	goruntime "runtime"
+	"testing"
+	"time"
+
+	"github.com/kstenerud/yoloai"
+	"github.com/kstenerud/yoloai/internal/cli/clitest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func quietInfo(name string, status yoloai.Status, lastOutput time.Time) *yoloai.SandboxInfo {
+	return &yoloai.SandboxInfo{Environment: &yoloai.Environment{Name: name}, Status: status, LastOutput: lastOutput}
+}
+
+func names(qs []quietSandbox) []string {
+	var out []string
+	for _, q := range qs {
+		out = append(out, q.name)
+	}
+	return out
+}
+
+func TestIdleWatcher_AnnouncesEachQuietStretchOnce(t *testing.T) {
+	now := time.Now()
+	w := &idleWatcher{after: 2 * time.Minute}
+
+	// Already quiet when the daemon starts: recorded, not announced.
+	old := quietInfo("old", yoloai.StatusIdle, now.Add(-time.Hour))
+	busy := quietInfo("busy", yoloai.StatusActive, now.Add(-10*time.Second))
+	assert.Empty(t, w.observe([]*yoloai.SandboxInfo{old, busy}, now))
+
+	// busy goes quiet: announced once.
+	now = now.Add(3 * time.Minute)
+	got := w.observe([]*yoloai.SandboxInfo{old, busy}, now)
+	require.Equal(t, []string{"busy"}, names(got))
+	assert.Equal(t, "No output for 3m; it may be stuck", got[0].message)
+	assert.Empty(t, w.observe([]*yoloai.SandboxInfo{old, busy}, now.Add(time.Minute)))
+
+	// Output resumes, then stops again: a new stretch, announced again.
+	busy.LastOutput = now
+	assert.Empty(t, w.observe([]*yoloai.SandboxInfo{old, busy}, now))
+	busy.Status = yoloai.StatusIdle
+	got = w.observe([]*yoloai.SandboxInfo{old, busy}, now.Add(5*time.Minute))
+	require.Equal(t, []string{"busy"}, names(got))
+	assert.Equal(t, "Waiting for input (idle for 5m)", got[0].message)
+}
+
+func TestIdleWatcher_IgnoresStoppedAndHeartbeatless(t *testing.T) {
+	now := time.Now()
+	w := &idleWatcher{after: time.Minute}
+	w.observe(nil, now)
+
+	stopped := quietInfo("stopped", yoloai.StatusStopped, now.Add(-time.Hour))
+	noBeat := quietInfo("nobeat", yoloai.StatusActive, time.Time{})
+	assert.Empty(t, w.observe([]*yoloai.SandboxInfo{stopped, noBeat}, now))
+}
+
+func TestDaemonRun_NotifyIdleWithOnce(t *testing.T) {
+	clitest.Home(t)
+	cmd := newRunCmd()
+	cmd.SetArgs([]string{"--once", "--notify-idle"})
+	assert.ErrorContains(t, cmd.Execute(), "does nothing with --once")
+}
+
+func TestInstall_NotifyIdle(t *testing.T) {
+	if goruntime.GOOS != "linux" {
+		t.Skip("systemd is the Linux service manager")
+	}
+	clitest.Home(t)
+	stubExecutable(t)
+
+	cmd := newInstallCmd()
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"--print", "--notify-idle"})
+	require.NoError(t, cmd.Execute())
+	assert.Contains(t, out.String(), `"run" "--interval" "15m0s" "--notify-idle=2m0s"`)
+
+	cmd = newInstallCmd()
+	out.Reset()
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"--print"})
+	require.NoError(t, cmd.Execute())
+	assert.NotContains(t, out.String(), "notify-idle", "off unless asked for")
+}
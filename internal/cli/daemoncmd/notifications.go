@@ -0,0 +1,174 @@
+// ABOUTME: The daemon's notifications: a sandbox created, an agent that needs input,
+// ABOUTME: finished or failed, sent to the desktop, a webhook and/or Slack per config.
+package daemoncmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/kstenerud/yoloai"
+	"github.com/kstenerud/yoloai/internal/cli/cliutil"
+	"github.com/kstenerud/yoloai/internal/config"
+	"github.com/kstenerud/yoloai/internal/notify"
+)
+
+// notifyCheckInterval is how often the daemon lists sandboxes to spot what
+// it notifies about.
+const notifyCheckInterval = 15 * time.Second
+
+// postWebhook delivers one event. A variable so tests can record them.
+var postWebhook = func(ctx context.Context, target string, ev notify.Event) error {
+	return notify.Webhook(ctx, nil, target, ev)
+}
+
+// postSlack delivers one event to a Slack webhook. A variable so tests can
+// record them.
+var postSlack = func(ctx context.Context, target string, ev notify.Event) error {
+	return notify.Slack(ctx, nil, target, ev)
+}
+
+// eventWatcher remembers each sandbox's status between listings, so each
+// thing worth a notification is reported once, when it happens.
+type eventWatcher struct {
+	last   map[string]yoloai.Status
+	primed bool
+}
+
+// observe takes a listing and returns an event for each sandbox that has
+// appeared since the last one, whose agent has gone from working to waiting
+// for input, or that has turned done or failed (including one created and
+// finished in between). The first listing only records: what happened before
+// the daemon started isn't news.
+func (w *eventWatcher) observe(infos []*yoloai.SandboxInfo, now time.Time) []notify.Event {
+	last := make(map[string]yoloai.Status, len(infos))
+	var events []notify.Event
+	for _, info := range infos {
+		if info.Environment == nil {
+			continue
+		}
+		name := info.Environment.Name
+		last[name] = info.Status
+		prev, seen := w.last[name]
+		if w.primed && !seen {
+			events = append(events, notify.Event{Time: now, Event: notify.EventCreated, Sandbox: name, Status: string(info.Status), Message: "Created"})
+		}
+		if info.Status == yoloai.StatusUnavailable {
+			// The backend didn't answer; keep what we knew.
+			if seen {
+				last[name] = prev
+			}
+			continue
+		}
+		if !w.primed {
+			continue
+		}
+		switch {
+		case info.Status == yoloai.StatusIdle && prev == yoloai.StatusActive:
+			events = append(events, notify.Event{Time: now, Event: notify.EventNeedsInput, Sandbox: name, Status: string(info.Status), Message: "Waiting for input"})
+		case exitedStatus(info.Status) && !exitedStatus(prev) && prev != yoloai.StatusUnavailable:
+			// A sandbox last seen on an unreachable backend may have
+			// exited long ago, so it is recorded, not reported.
+			events = append(events, exitEvent(name, info, now))
+		}
+	}
+	w.last, w.primed = last, true
+	return events
+}
+
+// exitedStatus reports whether status means the agent has exited.
+func exitedStatus(status yoloai.Status) bool {
+	return status == yoloai.StatusDone || status == yoloai.StatusFailed
+}
+
+// exitEvent describes a sandbox whose agent has just exited.
+func exitEvent(name string, info *yoloai.SandboxInfo, now time.Time) notify.Event {
+	ev := notify.Event{Time: now, Sandbox: name, Status: string(info.Status), ExitCode: info.ExitCode}
+	if info.Status == yoloai.StatusDone {
+		ev.Event, ev.Message = notify.EventFinished, "Finished"
+	} else {
+		ev.Event, ev.Message = notify.EventFailed, "Failed"
+	}
+	if info.ExitCode != nil {
+		ev.Message += fmt.Sprintf(" (exit %d)", *info.ExitCode)
+	}
+	return ev
+}
+
+// watchNotifications lists sandboxes every notifyCheckInterval and reports
+// what happened to them, as the notifications config says, until ctx is
+// cancelled. The config is read on every check, so turning notifications on
+// or off needs no restart. A failing listing or notifier is logged once per
+// failing streak.
+func watchNotifications(ctx context.Context, wg *sync.WaitGroup, lister *yoloai.SandboxLister, stdout, stderr io.Writer) {
+	w := &eventWatcher{}
+	wg.Go(func() {
+		ticker := time.NewTicker(notifyCheckInterval)
+		defer ticker.Stop()
+		failing := false
+		for {
+			if err := checkNotifications(ctx, lister, w, stdout); err != nil && ctx.Err() == nil {
+				if !failing {
+					fmt.Fprintf(stderr, "%s notifications: %v\n", stamp(), err) //nolint:errcheck // best-effort output
+				}
+				failing = true
+			} else {
+				failing = false
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	})
+}
+
+// checkNotifications runs one listing through w and sends what it found.
+func checkNotifications(ctx context.Context, lister *yoloai.SandboxLister, w *eventWatcher, stdout io.Writer) error {
+	infos, _, err := lister.List(ctx)
+	if err != nil {
+		return fmt.Errorf("list sandboxes: %w", err)
+	}
+	events := w.observe(infos, time.Now())
+	if len(events) == 0 {
+		return nil
+	}
+	gcfg, err := config.LoadGlobalConfig(cliutil.Layout())
+	if err != nil {
+		return fmt.Errorf("load notifications config: %w", err)
+	}
+	return notifyEvents(ctx, gcfg.Notifications, events, stdout)
+}
+
+// notifyEvents logs each event and sends it where cfg says. The desktop only
+// hears about exits: you made the sandbox yourself, and --notify-idle is the
+// desktop's way to learn an agent is waiting.
+func notifyEvents(ctx context.Context, cfg *config.NotificationsConfig, events []notify.Event, stdout io.Writer) error {
+	var errs []error
+	for _, ev := range events {
+		fmt.Fprintf(stdout, "%s %s: %s\n", stamp(), ev.Sandbox, ev.Message) //nolint:errcheck // best-effort output
+		if !cfg.Enabled() {
+			continue
+		}
+		if cfg.Desktop && (ev.Event == notify.EventFinished || ev.Event == notify.EventFailed) {
+			if err := desktopNotify(ctx, "yoloai: "+ev.Sandbox, ev.Message); err != nil {
+				errs = append(errs, err)
+			}
+		}
+		if cfg.WebhookURL != "" {
+			if err := postWebhook(ctx, cfg.WebhookURL, ev); err != nil {
+				errs = append(errs, err)
+			}
+		}
+		if cfg.SlackWebhook != "" {
+			if err := postSlack(ctx, cfg.SlackWebhook, ev); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,124 @@
+package daemoncmd
+
+// ABOUTME: Tests for the daemon's notifications: each event is reported once, the first
+// ABOUTME: listing only primes, and the config picks desktop, webhook and/or Slack.
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/kstenerud/yoloai"
+	"github.com/kstenerud/yoloai/internal/config"
+	"github.com/kstenerud/yoloai/internal/notify"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func statusInfo(name string, status yoloai.Status, exitCode *int) *yoloai.SandboxInfo {
+	return &yoloai.SandboxInfo{Environment: &yoloai.Environment{Name: name}, Status: status, ExitCode: exitCode}
+}
+
+func TestEventWatcher_ReportsEachExitOnce(t *testing.T) {
+	now := time.Now()
+	w := &eventWatcher{}
+	zero, two := 0, 2
+
+	// Already done when the daemon starts: recorded, not reported.
+	assert.Empty(t, w.observe([]*yoloai.SandboxInfo{
+		statusInfo("old", yoloai.StatusDone, &zero),
+		statusInfo("run", yoloai.StatusActive, nil),
+	}, now))
+
+	// run exits non-zero, and quick is created and finishes between listings.
+	got := w.observe([]*yoloai.SandboxInfo{
+		statusInfo("old", yoloai.StatusDone, &zero),
+		statusInfo("run", yoloai.StatusFailed, &two),
+		statusInfo("quick", yoloai.StatusDone, &zero),
+	}, now)
+	require.Len(t, got, 3)
+	assert.Equal(t, notify.Event{Time: now, Event: notify.EventFailed, Sandbox: "run", Status: "failed", ExitCode: &two, Message: "Failed (exit 2)"}, got[0])
+	assert.Equal(t, notify.Event{Time: now, Event: notify.EventCreated, Sandbox: "quick", Status: "done", Message: "Created"}, got[1])
+	assert.Equal(t, "quick", got[2].Sandbox)
+	assert.Equal(t, "Finished (exit 0)", got[2].Message)
+
+	// Still exited: nothing new.
+	assert.Empty(t, w.observe([]*yoloai.SandboxInfo{statusInfo("run", yoloai.StatusFailed, &two)}, now))
+}
+
+func TestEventWatcher_NeedsInputOnlyAfterWorking(t *testing.T) {
+	w := &eventWatcher{}
+	w.observe([]*yoloai.SandboxInfo{statusInfo("chat", yoloai.StatusActive, nil)}, time.Now())
+
+	got := w.observe([]*yoloai.SandboxInfo{statusInfo("chat", yoloai.StatusIdle, nil)}, time.Now())
+	require.Len(t, got, 1)
+	assert.Equal(t, notify.EventNeedsInput, got[0].Event)
+	assert.Equal(t, "Waiting for input", got[0].Message)
+
+	// Still waiting: nothing new. Back to work and waiting again: reported again.
+	assert.Empty(t, w.observe([]*yoloai.SandboxInfo{statusInfo("chat", yoloai.StatusIdle, nil)}, time.Now()))
+	w.observe([]*yoloai.SandboxInfo{statusInfo("chat", yoloai.StatusActive, nil)}, time.Now())
+	assert.Len(t, w.observe([]*yoloai.SandboxInfo{statusInfo("chat", yoloai.StatusIdle, nil)}, time.Now()), 1)
+
+	// A new sandbox that starts out idle was created, not handed back.
+	got = w.observe([]*yoloai.SandboxInfo{statusInfo("chat", yoloai.StatusIdle, nil), statusInfo("new", yoloai.StatusIdle, nil)}, time.Now())
+	require.Len(t, got, 1)
+	assert.Equal(t, notify.EventCreated, got[0].Event)
+}
+
+func TestEventWatcher_UnreachableBackendIsNotAnExit(t *testing.T) {
+	w := &eventWatcher{}
+	w.observe([]*yoloai.SandboxInfo{statusInfo("far", yoloai.StatusUnavailable, nil)}, time.Now())
+
+	// Its backend comes back and the agent turns out to have exited at some
+	// point: there is no telling when, so it isn't reported.
+	assert.Empty(t, w.observe([]*yoloai.SandboxInfo{statusInfo("far", yoloai.StatusDone, nil)}, time.Now()))
+
+	// An agent seen running keeps that status through an outage.
+	w.observe([]*yoloai.SandboxInfo{statusInfo("far", yoloai.StatusActive, nil)}, time.Now())
+	w.observe([]*yoloai.SandboxInfo{statusInfo("far", yoloai.StatusUnavailable, nil)}, time.Now())
+	assert.Len(t, w.observe([]*yoloai.SandboxInfo{statusInfo("far", yoloai.StatusDone, nil)}, time.Now()), 1)
+}
+
+func TestNotifyEvents_FollowsConfig(t *testing.T) {
+	var desktop []string
+	var hooks, slack []string
+	oldDesktop, oldHook, oldSlack := desktopNotify, postWebhook, postSlack
+	desktopNotify = func(_ context.Context, title, message string) error {
+		desktop = append(desktop, title+": "+message)
+		return nil
+	}
+	postWebhook = func(_ context.Context, target string, ev notify.Event) error {
+		hooks = append(hooks, target+" "+ev.Event)
+		return nil
+	}
+	postSlack = func(_ context.Context, target string, ev notify.Event) error {
+		slack = append(slack, target+" "+ev.Event)
+		return nil
+	}
+	t.Cleanup(func() { desktopNotify, postWebhook, postSlack = oldDesktop, oldHook, oldSlack })
+
+	exited := []notify.Event{{Event: notify.EventFinished, Sandbox: "fix-bug", Message: "Finished (exit 0)"}}
+	var out bytes.Buffer
+	require.NoError(t, notifyEvents(context.Background(), nil, exited, &out))
+	assert.Contains(t, out.String(), "fix-bug: Finished (exit 0)", "an exit is logged even with notifications off")
+	assert.Empty(t, desktop)
+	assert.Empty(t, hooks)
+	assert.Empty(t, slack)
+
+	cfg := &config.NotificationsConfig{Desktop: true, WebhookURL: "https://hooks.example.com/y"}
+	require.NoError(t, notifyEvents(context.Background(), cfg, exited, &out))
+	assert.Equal(t, []string{"yoloai: fix-bug: Finished (exit 0)"}, desktop)
+	assert.Equal(t, []string{"https://hooks.example.com/y finished"}, hooks)
+	assert.Empty(t, slack)
+
+	// Slack and the webhook get every event; the desktop only exits.
+	desktop, hooks = nil, nil
+	cfg.SlackWebhook = "https://hooks.slack.com/services/T/B/x"
+	created := []notify.Event{{Event: notify.EventCreated, Sandbox: "fix-bug", Message: "Created"}}
+	require.NoError(t, notifyEvents(context.Background(), cfg, created, &out))
+	assert.Empty(t, desktop)
+	assert.Equal(t, []string{"https://hooks.example.com/y created"}, hooks)
+	assert.Equal(t, []string{"https://hooks.slack.com/services/T/B/x created"}, slack)
+}
@@ -0,0 +1,447 @@
+// ABOUTME: `daemon install/status/uninstall` — the daemon as a per-user login
+// ABOUTME: service: a launchd agent on macOS, a systemd user unit on Linux.
+package daemoncmd
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	goruntime "runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kstenerud/yoloai/internal/cli/cliutil"
+	"github.com/kstenerud/yoloai/internal/daemonapi"
+	"github.com/kstenerud/yoloai/internal/fileutil"
+	"github.com/kstenerud/yoloai/internal/sysexec"
+	"github.com/kstenerud/yoloai/yoerrors"
+	"github.com/spf13/cobra"
+)
+
+const (
+	launchdLabel    = "com.yoloai.daemon"
+	systemdUnitName = "yoloai-daemon.service"
+)
+
+// service is the daemon's registration with the host's service manager.
+type service struct {
+	manager string // "launchd" or "systemd"
+	path    string // where the plist / unit file lives
+	logPath string // where the daemon's output goes ("" = the journal)
+}
+
+// hostService returns the service definition for this host, or a usage error
+// where there is no supported per-user service manager. logPath is where the
+// launchd agent writes its output; systemd keeps the journal instead.
+func hostService(goos, home, logPath string) (*service, error) {
+	switch goos {
+	case "darwin":
+		return &service{
+			manager: "launchd",
+			path:    filepath.Join(home, "Library", "LaunchAgents", launchdLabel+".plist"),
+			logPath: logPath,
+		}, nil
+	case "linux":
+		return &service{
+			manager: "systemd",
+			path:    filepath.Join(home, ".config", "systemd", "user", systemdUnitName),
+		}, nil
+	}
+	return nil, yoerrors.NewUsageError("daemon install supports macOS (launchd) and Linux (systemd); on %s run 'yoloai daemon run' under your own supervisor", goos)
+}
+
+// render returns the plist or unit file that runs argv with env.
+func (s *service) render(argv []string, env map[string]string) string {
+	if s.manager == "launchd" {
+		return launchdPlist(argv, env, s.logPath)
+	}
+	return systemdUnit(argv, env)
+}
+
+// launchdPlist renders a launchd agent that starts at login and is restarted
+// whenever it exits.
+func launchdPlist(argv []string, env map[string]string, logPath string) string {
+	var b strings.Builder
+	b.WriteString(`<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">
+<plist version="1.0">
+<dict>
+	<key>Label</key>
+	<string>` + launchdLabel + `</string>
+	<key>ProgramArguments</key>
+	<array>
+`)
+	for _, a := range argv {
+		b.WriteString("\t\t<string>" + html.EscapeString(a) + "</string>\n")
+	}
+	b.WriteString("\t</array>\n\t<key>EnvironmentVariables</key>\n\t<dict>\n")
+	for _, k := range sortedKeys(env) {
+		b.WriteString("\t\t<key>" + html.EscapeString(k) + "</key>\n\t\t<string>" + html.EscapeString(env[k]) + "</string>\n")
+	}
+	b.WriteString(`	</dict>
+	<key>RunAtLoad</key>
+	<true/>
+	<key>KeepAlive</key>
+	<true/>
+	<key>StandardOutPath</key>
+	<string>` + html.EscapeString(logPath) + `</string>
+	<key>StandardErrorPath</key>
+	<string>` + html.EscapeString(logPath) + `</string>
+</dict>
+</plist>
+`)
+	return b.String()
+}
+
+// systemdUnit renders a systemd user unit that starts with the user's session
+// and is restarted if it fails. Output goes to the journal.
+func systemdUnit(argv []string, env map[string]string) string {
+	quoted := make([]string, len(argv))
+	for i, a := range argv {
+		quoted[i] = systemdQuote(a)
+	}
+	var b strings.Builder
+	b.WriteString(`[Unit]
+Description=yoloAI background daemon (gc, retention)
+
+[Service]
+ExecStart=` + strings.Join(quoted, " ") + `
+Restart=on-failure
+RestartSec=30
+`)
+	for _, k := range sortedKeys(env) {
+		b.WriteString("Environment=" + systemdQuote(k+"="+env[k]) + "\n")
+	}
+	b.WriteString(`
+[Install]
+WantedBy=default.target
+`)
+	return b.String()
+}
+
+// systemdQuote double-quotes s for a unit file: backslash and quote escaped,
+// and % doubled so systemd doesn't read it as a specifier.
+func systemdQuote(s string) string {
+	s = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "%", "%%").Replace(s)
+	return `"` + s + `"`
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// serviceEnv is the environment the service is given. A service manager
+// starts it with almost none — launchd's PATH lacks /usr/local/bin and
+// /opt/homebrew/bin, where docker usually lives — so the installing shell's
+// PATH and Docker daemon settings are recorded into it.
+func serviceEnv() map[string]string {
+	env := cliutil.Layout().Env()
+	out := env.EnvForDaemonDiscovery()
+	for _, kv := range env.EnvForHostTool() {
+		if k, v, ok := strings.Cut(kv, "="); ok && k == "PATH" {
+			out[k] = v
+		}
+	}
+	return out
+}
+
+// serviceCtl runs a service-manager command (launchctl, systemctl) and
+// returns its trimmed combined output. A variable so tests can record calls.
+var serviceCtl = func(ctx context.Context, name string, args ...string) (string, error) {
+	out, err := sysexec.CommandContext(ctx, cliutil.Layout().Env().EnvForHostTool(), name, args...).CombinedOutput()
+	return strings.TrimSpace(string(out)), err
+}
+
+// ctl runs serviceCtl and folds its output into the error.
+func ctl(ctx context.Context, name string, args ...string) error {
+	out, err := serviceCtl(ctx, name, args...)
+	if err != nil {
+		if out != "" {
+			return fmt.Errorf("%s %s: %w: %s", name, strings.Join(args, " "), err, out)
+		}
+		return fmt.Errorf("%s %s: %w", name, strings.Join(args, " "), err)
+	}
+	return nil
+}
+
+func launchdTarget() string {
+	return "gui/" + strconv.Itoa(cliutil.Layout().HostUID)
+}
+
+func currentService() (*service, error) {
+	return hostService(goruntime.GOOS, cliutil.Layout().HomeDir, cliutil.CLIDaemonLogPath())
+}
+
+func newInstallCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "install",
+		Short: "Install and start the daemon as a login service",
+		Long: `Install the daemon as a per-user service that starts at login and is
+restarted if it dies, then start it: a launchd agent
+(~/Library/LaunchAgents/` + launchdLabel + `.plist) on macOS, a systemd user unit
+(~/.config/systemd/user/` + systemdUnitName + `) on Linux.
+
+The service runs this yoloai binary with this data directory, and is given
+your current PATH and Docker settings. Run install again after moving the
+binary or changing them; it replaces the old service. --print shows the file
+without installing anything.`,
+		Example: `  yoloai daemon install
+  yoloai daemon install --interval 5m
+  yoloai daemon install --notify-idle
+  yoloai daemon install --print`,
+		Args: cobra.NoArgs,
+		RunE: runInstall,
+	}
+	cmd.Flags().Duration("interval", defaultInterval, "Time between sweeps")
+	cmd.Flags().Bool("print", false, "Print the service file instead of installing it")
+	addNotifyIdleFlag(cmd)
+	return cmd
+}
+
+func runInstall(cmd *cobra.Command, _ []string) error {
+	interval, _ := cmd.Flags().GetDuration("interval")
+	printOnly, _ := cmd.Flags().GetBool("print")
+	if interval < time.Minute {
+		return yoerrors.NewUsageError("--interval must be at least 1m: %s", interval)
+	}
+	notifyIdle, err := notifyIdleFlag(cmd)
+	if err != nil {
+		return err
+	}
+	svc, err := currentService()
+	if err != nil {
+		return err
+	}
+	exe, err := serviceExecutable()
+	if err != nil {
+		return err
+	}
+	argv := []string{exe, "--data-dir", cliutil.TopDir(), "daemon", "run", "--interval", interval.String()}
+	if notifyIdle > 0 {
+		argv = append(argv, "--notify-idle="+notifyIdle.String())
+	}
+	content := svc.render(argv, serviceEnv())
+	if printOnly {
+		_, err := fmt.Fprint(cmd.OutOrStdout(), content)
+		return err
+	}
+
+	if err := fileutil.MkdirAll(filepath.Dir(svc.path), 0o755); err != nil {
+		return err
+	}
+	if svc.logPath != "" {
+		if err := fileutil.MkdirAll(filepath.Dir(svc.logPath), 0o750); err != nil {
+			return err
+		}
+	}
+	if err := fileutil.AtomicWriteFile(svc.path, []byte(content), 0o644); err != nil {
+		return fmt.Errorf("write %s: %w", svc.path, err)
+	}
+
+	ctx := cmd.Context()
+	switch svc.manager {
+	case "launchd":
+		// bootout first so a reinstall picks up the new plist; it fails when
+		// nothing was loaded, which is fine.
+		_, _ = serviceCtl(ctx, "launchctl", "bootout", launchdTarget()+"/"+launchdLabel) //nolint:errcheck // may not be loaded
+		if err := ctl(ctx, "launchctl", "bootstrap", launchdTarget(), svc.path); err != nil {
+			return err
+		}
+	case "systemd":
+		if err := ctl(ctx, "systemctl", "--user", "daemon-reload"); err != nil {
+			return err
+		}
+		if err := ctl(ctx, "systemctl", "--user", "enable", systemdUnitName); err != nil {
+			return err
+		}
+		if err := ctl(ctx, "systemctl", "--user", "restart", systemdUnitName); err != nil {
+			return err
+		}
+	}
+
+	out := cmd.OutOrStdout()
+	fmt.Fprintf(out, "Installed %s and started the daemon (every %s)\n", svc.path, interval) //nolint:errcheck // best-effort output
+	fmt.Fprintf(out, "Logs: %s\n", svc.logHint())                                            //nolint:errcheck // best-effort output
+	return nil
+}
+
+// serviceExecutable is the binary path the service runs. os.Executable gives
+// the resolved file, which under Homebrew is a versioned Cellar path that an
+// upgrade deletes; when the same binary is on PATH, that stable name is used.
+func serviceExecutable() (string, error) {
+	exe, err := executable()
+	if err != nil {
+		return "", fmt.Errorf("resolve own executable: %w", err)
+	}
+	onPath, err := exec.LookPath(filepath.Base(exe))
+	if err != nil {
+		return exe, nil
+	}
+	if abs, absErr := filepath.Abs(onPath); absErr == nil {
+		onPath = abs
+	}
+	a, errA := os.Stat(exe)
+	b, errB := os.Stat(onPath)
+	if errA == nil && errB == nil && os.SameFile(a, b) {
+		return onPath, nil
+	}
+	return exe, nil
+}
+
+// logHint says where the daemon's output can be read.
+func (s *service) logHint() string {
+	if s.logPath != "" {
+		return s.logPath
+	}
+	return "journalctl --user -u " + systemdUnitName
+}
+
+func newStatusCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "status",
+		Short: "Show whether the daemon service is installed and running",
+		Args:  cobra.NoArgs,
+		RunE:  runStatus,
+	}
+}
+
+// statusJSON is the --json shape of daemon status.
+type statusJSON struct {
+	Manager   string `json:"manager"`
+	Path      string `json:"path"`
+	Installed bool   `json:"installed"`
+	Running   bool   `json:"running"`
+	State     string `json:"state,omitempty"`
+	Logs      string `json:"logs"`
+	// API is what the daemon answering on its API socket says about itself,
+	// installed or run by hand; nil when none answers.
+	API *daemonapi.Status `json:"api,omitempty"`
+}
+
+// apiStatusTimeout bounds asking a running daemon about itself.
+const apiStatusTimeout = 2 * time.Second
+
+func runStatus(cmd *cobra.Command, _ []string) error {
+	svc, err := currentService()
+	if err != nil {
+		return err
+	}
+	st := statusJSON{Manager: svc.manager, Path: svc.path, Logs: svc.logHint()}
+	if _, err := os.Stat(svc.path); err == nil {
+		st.Installed = true
+	}
+	if st.Installed {
+		st.State, st.Running = svc.state(cmd.Context())
+	}
+	ctx, cancel := context.WithTimeout(cmd.Context(), apiStatusTimeout)
+	defer cancel()
+	if api, err := daemonapi.NewClient(cliutil.CLIDaemonSocketPath()).Status(ctx); err == nil {
+		st.API = &api
+	}
+
+	if cliutil.JSONEnabled(cmd) {
+		return cliutil.WriteJSON(cmd.OutOrStdout(), st)
+	}
+	out := cmd.OutOrStdout()
+	if !st.Installed {
+		if _, err := fmt.Fprintln(out, "Daemon not installed (run 'yoloai daemon install')"); err != nil {
+			return err
+		}
+		return printAPIStatus(out, st.API)
+	}
+	running := "not running"
+	if st.Running {
+		running = "running"
+	}
+	fmt.Fprintf(out, "Daemon installed (%s): %s\n", svc.manager, svc.path) //nolint:errcheck // best-effort output
+	fmt.Fprintf(out, "State: %s (%s)\n", running, st.State)                //nolint:errcheck // best-effort output
+	if _, err := fmt.Fprintf(out, "Logs:  %s\n", st.Logs); err != nil {
+		return err
+	}
+	return printAPIStatus(out, st.API)
+}
+
+// printAPIStatus prints the API line of daemon status: which daemon answers
+// on the API socket, if any.
+func printAPIStatus(out io.Writer, api *daemonapi.Status) error {
+	if api == nil {
+		_, err := fmt.Fprintf(out, "API:   no daemon answering on %s\n", cliutil.CLIDaemonSocketPath())
+		return err
+	}
+	_, err := fmt.Fprintf(out, "API:   %s (pid %d, version %s, up %s)\n", cliutil.CLIDaemonSocketPath(), api.PID, api.Version, cliutil.FormatDuration(time.Since(api.Started)))
+	return err
+}
+
+// state asks the service manager about the daemon: a short state word and
+// whether it is running.
+func (s *service) state(ctx context.Context) (string, bool) {
+	switch s.manager {
+	case "launchd":
+		out, err := serviceCtl(ctx, "launchctl", "print", launchdTarget()+"/"+launchdLabel)
+		if err != nil {
+			return "not loaded", false
+		}
+		for _, line := range strings.Split(out, "\n") {
+			if k, v, ok := strings.Cut(strings.TrimSpace(line), " = "); ok && k == "state" {
+				return v, v == "running"
+			}
+		}
+		return "loaded", false
+	default:
+		// is-active exits non-zero for anything but active, and still prints
+		// the state.
+		out, _ := serviceCtl(ctx, "systemctl", "--user", "is-active", systemdUnitName) //nolint:errcheck // state is in the output
+		if out == "" {
+			out = "unknown"
+		}
+		return out, out == "active"
+	}
+}
+
+func newUninstallCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "uninstall",
+		Short: "Stop the daemon and remove its login service",
+		Args:  cobra.NoArgs,
+		RunE:  runUninstall,
+	}
+}
+
+func runUninstall(cmd *cobra.Command, _ []string) error {
+	svc, err := currentService()
+	if err != nil {
+		return err
+	}
+	out := cmd.OutOrStdout()
+	if _, err := os.Stat(svc.path); os.IsNotExist(err) {
+		_, err := fmt.Fprintln(out, "Daemon not installed")
+		return err
+	}
+
+	ctx := cmd.Context()
+	switch svc.manager {
+	case "launchd":
+		_, _ = serviceCtl(ctx, "launchctl", "bootout", launchdTarget()+"/"+launchdLabel) //nolint:errcheck // may not be loaded
+	case "systemd":
+		_, _ = serviceCtl(ctx, "systemctl", "--user", "disable", "--now", systemdUnitName) //nolint:errcheck // may not be enabled
+	}
+	if err := os.Remove(svc.path); err != nil {
+		return err
+	}
+	if svc.manager == "systemd" {
+		_, _ = serviceCtl(ctx, "systemctl", "--user", "daemon-reload") //nolint:errcheck // best-effort
+	}
+	_, err = fmt.Fprintf(out, "Stopped the daemon and removed %s\n", svc.path)
+	return err
+}
@@ -12,6 +12,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/kstenerud/yoloai/internal/cli/cliutil"
 	"github.com/kstenerud/yoloai/internal/config"
 	"github.com/kstenerud/yoloai/internal/testutil"
 	"github.com/spf13/cobra"
@@ -462,3 +463,52 @@ func TestDocsFlagsExist_DetectsMissingFlag(t *testing.T) {
 			"the gate would never fail on a genuinely stale flag")
 	}
 }
+
+// TestExamplesParse keeps the examples registry (cliutil/examples.go) as
+// honest as the help topics: every example shown under --help or by
+// `yoloai examples` must name a real command and parse against its flags.
+func TestExamplesParse(t *testing.T) {
+	testutil.IsolatedHome(t)
+	rootCmd := NewRootCmd("test", "test", "test")
+
+	for _, e := range cliutil.AllExamples() {
+		args := splitExampleArgs(e.Cmd)
+		if len(args) < 2 || args[0] != "yoloai" {
+			t.Errorf("%q: not a yoloai command line", e.Cmd)
+			continue
+		}
+		cmd, rest, err := rootCmd.Find(args[1:])
+		if err != nil || cmd == rootCmd {
+			t.Errorf("%q: no such command (%v)", e.Cmd, err)
+			continue
+		}
+		if err := cmd.ParseFlags(rest); err != nil {
+			t.Errorf("%q: %v", e.Cmd, err)
+		}
+	}
+}
+
+// splitExampleArgs splits a command line on spaces, keeping double-quoted
+// strings whole — enough for the registry's examples.
+func splitExampleArgs(line string) []string {
+	var args []string
+	var cur strings.Builder
+	quoted := false
+	for _, r := range line {
+		switch {
+		case r == '"':
+			quoted = !quoted
+		case r == ' ' && !quoted:
+			if cur.Len() > 0 {
+				args = append(args, cur.String())
+				cur.Reset()
+			}
+		default:
+			cur.WriteRune(r)
+		}
+	}
+	if cur.Len() > 0 {
+		args = append(args, cur.String())
+	}
+	return args
+}
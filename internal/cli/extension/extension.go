@@ -157,9 +157,9 @@ var reservedFlags = map[string]bool{
 
 // ReservedNames are built-in command names that extensions cannot shadow.
 var ReservedNames = map[string]bool{
-	"new": true, "attach": true, "diff": true, "apply": true, "files": true,
+	"new": true, "attach": true, "diff": true, "apply": true, "files": true, "artifacts": true, "describe": true,
 	"start": true, "stop": true, "restart": true, "destroy": true, "reset": true,
-	"system": true, "sandbox": true, "ls": true, "log": true, "exec": true,
+	"system": true, "sandbox": true, "ls": true, "log": true, "logs": true, "exec": true,
 	"profile": true, "help": true, "config": true, "version": true,
 	"x": true, "ext": true, "sb": true,
 }
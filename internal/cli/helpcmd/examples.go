@@ -0,0 +1,81 @@
+package helpcmd
+
+// ABOUTME: `yoloai examples` — lists the common workflows from the examples
+// ABOUTME: registry as copy-pasteable command sequences.
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/kstenerud/yoloai/internal/cli/cliutil"
+	"github.com/kstenerud/yoloai/yoerrors"
+	"github.com/spf13/cobra"
+)
+
+// NewExamplesCmd creates the `yoloai examples` command.
+func NewExamplesCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "examples [workflow]",
+		Short: "Show example commands for common workflows",
+		Long: `Show example commands for common workflows: reviewing an agent's work,
+headless runs, directory modes, network limits, follow-ups and profiles.
+Name a workflow to show only that one.
+
+Each command also lists its own examples under --help.`,
+		Example: cliutil.FormatExamples([]cliutil.Example{
+			{Cmd: "yoloai examples", Note: "every workflow"},
+			{Cmd: "yoloai examples dirs", Note: "just the directory modes"},
+		}),
+		GroupID: cliutil.GroupAdmin,
+		Args:    cobra.MaximumNArgs(1),
+		ValidArgsFunction: func(_ *cobra.Command, args []string, _ string) ([]string, cobra.ShellCompDirective) {
+			if len(args) > 0 {
+				return nil, cobra.ShellCompDirectiveNoFileComp
+			}
+			var names []string
+			for _, w := range cliutil.Workflows() {
+				names = append(names, w.Name+"\t"+w.Title)
+			}
+			return names, cobra.ShellCompDirectiveNoFileComp
+		},
+		RunE: runExamples,
+	}
+}
+
+func runExamples(cmd *cobra.Command, args []string) error {
+	workflows := cliutil.Workflows()
+	if len(args) > 0 {
+		name := strings.ToLower(args[0])
+		var found []cliutil.Workflow
+		for _, w := range workflows {
+			if w.Name == name {
+				found = append(found, w)
+			}
+		}
+		if len(found) == 0 {
+			names := make([]string, 0, len(workflows))
+			for _, w := range workflows {
+				names = append(names, w.Name)
+			}
+			return yoerrors.NewUsageError("unknown workflow %q (one of: %s)", args[0], strings.Join(names, ", "))
+		}
+		workflows = found
+	}
+
+	if cliutil.JSONEnabled(cmd) {
+		return cliutil.WriteJSON(cmd.OutOrStdout(), map[string]any{"workflows": workflows})
+	}
+	printWorkflows(cmd.OutOrStdout(), workflows)
+	return nil
+}
+
+// printWorkflows writes each workflow as a titled block of examples.
+func printWorkflows(out io.Writer, workflows []cliutil.Workflow) {
+	for i, w := range workflows {
+		if i > 0 {
+			fmt.Fprintln(out) //nolint:errcheck // best-effort output
+		}
+		fmt.Fprintf(out, "%s  (yoloai examples %s)\n\n%s\n", w.Title, w.Name, cliutil.FormatExamples(w.Steps)) //nolint:errcheck // best-effort output
+	}
+}
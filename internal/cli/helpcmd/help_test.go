@@ -4,8 +4,11 @@
 package helpcmd
 
 import (
+	"bytes"
 	"testing"
 
+	"github.com/kstenerud/yoloai/yoerrors"
+
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -118,3 +121,18 @@ func TestLevenshtein(t *testing.T) {
 		})
 	}
 }
+
+func TestExamples_FilterByWorkflow(t *testing.T) {
+	cmd := NewExamplesCmd()
+	cmd.Flags().Bool("json", false, "")
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+
+	require.NoError(t, runExamples(cmd, []string{"DIRS"}))
+	assert.Contains(t, out.String(), "(yoloai examples dirs)")
+	assert.Contains(t, out.String(), "./app:rw")
+	assert.NotContains(t, out.String(), "--network-none", "only the named workflow")
+
+	var usage *yoerrors.UsageError
+	require.ErrorAs(t, runExamples(cmd, []string{"nope"}), &usage)
+}
@@ -0,0 +1,289 @@
+// ABOUTME: `yoloai batch create -f <spec>` — create (and start) every sandbox
+// ABOUTME: listed in a YAML spec file, concurrently through a bounded worker pool.
+package lifecycle
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/kstenerud/yoloai/internal/cli/cliutil"
+	"gopkg.in/yaml.v3"
+
+	yoloai "github.com/kstenerud/yoloai"
+	"github.com/kstenerud/yoloai/yoerrors"
+	"github.com/spf13/cobra"
+)
+
+// defaultBatchJobs bounds how many sandboxes `batch create` provisions at once
+// when --jobs is not given. Each create copies a workdir and launches a
+// container, so a small pool keeps disk and backend load reasonable.
+const defaultBatchJobs = 4
+
+func NewBatchCmd(version string) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "batch <subcommand>",
+		Short:   "Operate on many sandboxes from a spec file",
+		GroupID: cliutil.GroupLifecycle,
+	}
+	cmd.AddCommand(newBatchCreateCmd(version))
+	return cmd
+}
+
+func newBatchCreateCmd(version string) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "create -f <spec.yaml>",
+		Short: "Create and start every sandbox listed in a YAML spec",
+		Long: `Create and start every sandbox listed in a YAML spec file.
+
+The spec is a list of entries with the keys name, workdir, agent, prompt and
+profile. name is required, as is workdir unless a profile supplies one; agent
+defaults to the configured default agent. A relative workdir is resolved
+against the spec file's directory, and accepts the same :copy/:rw suffixes as
+'yoloai new':
+
+  - name: fix-login
+    workdir: ./webapp
+    prompt: Fix the login redirect loop
+  - name: docs-pass
+    workdir: ./webapp:copy
+    agent: codex
+    prompt: Proofread docs/
+
+The whole spec is validated before anything is created. Sandboxes are then
+created concurrently, at most --jobs at a time. One entry failing does not
+stop the others; failures are listed at the end and the command exits non-zero.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return runBatchCreate(cmd, version)
+		},
+	}
+
+	cmd.Flags().StringP("file", "f", "", "YAML spec file listing the sandboxes to create")
+	cmd.Flags().IntP("jobs", "j", defaultBatchJobs, "Maximum number of sandboxes to create at once")
+	cmd.Flags().Bool("no-start", false, "Create but don't start the containers")
+	cmd.Flags().Bool("allow-dirty", false, "Proceed even if a workdir has uncommitted changes (they will be visible to the agent)")
+	_ = cmd.MarkFlagRequired("file")
+
+	return cmd
+}
+
+// batchEntry is one sandbox in a `batch create` spec file.
+type batchEntry struct {
+	Name    string `yaml:"name"`
+	Workdir string `yaml:"workdir"`
+	Agent   string `yaml:"agent"`
+	Prompt  string `yaml:"prompt"`
+	Profile string `yaml:"profile"`
+}
+
+// batchResult is one entry's outcome, shared by the human and JSON renderings.
+type batchResult struct {
+	Name   string `json:"name"`
+	Action string `json:"action,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+func runBatchCreate(cmd *cobra.Command, version string) error {
+	specPath, _ := cmd.Flags().GetString("file")
+	jobs, _ := cmd.Flags().GetInt("jobs")
+	noStart, _ := cmd.Flags().GetBool("no-start")
+	allowDirty, _ := cmd.Flags().GetBool("allow-dirty")
+	if jobs < 1 {
+		return yoerrors.NewUsageError("--jobs must be at least 1")
+	}
+
+	data, err := os.ReadFile(specPath) //nolint:gosec // G304: user-named spec file
+	if err != nil {
+		return fmt.Errorf("read batch spec: %w", err)
+	}
+	entries, err := parseBatchSpec(data)
+	if err != nil {
+		return err
+	}
+	specDir, err := filepath.Abs(filepath.Dir(specPath))
+	if err != nil {
+		return fmt.Errorf("resolve batch spec directory: %w", err)
+	}
+	optsList := make([]yoloai.SandboxCreateOptions, len(entries))
+	for i, e := range entries {
+		opts, err := batchCreateOptions(e, specDir)
+		if err != nil {
+			return fmt.Errorf("%s: %w", e.Name, err)
+		}
+		opts.AllowDirtyWorkdir = allowDirty
+		optsList[i] = opts
+	}
+
+	if !cliutil.JSONEnabled(cmd) {
+		cliutil.WarnIfLowDisk(cmd.ErrOrStderr(), cliutil.Layout().SandboxesDir())
+	}
+
+	c, err := newCreateClient(cmd, version)
+	if err != nil {
+		return err
+	}
+	defer c.Close() //nolint:errcheck // best-effort cleanup
+
+	ctx := cmd.Context()
+	// First-run setup (base image build, defaults) is shared by every entry;
+	// run it once up front so the workers don't race to perform it.
+	if err := c.EnsureSetup(ctx); err != nil {
+		return err
+	}
+
+	results := runBatchPool(ctx, optsList, jobs, func(ctx context.Context, opts yoloai.SandboxCreateOptions) batchResult {
+		return createForBatch(cmd, ctx, c, opts, noStart)
+	})
+	return reportBatch(cmd, results)
+}
+
+// parseBatchSpec decodes a spec file and validates every entry before any
+// sandbox is created: a bad name, a duplicate, or an entry with neither a
+// workdir nor a profile fails the whole batch up front.
+func parseBatchSpec(data []byte) ([]batchEntry, error) {
+	var entries []batchEntry
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&entries); err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, yoerrors.NewUsageError("batch spec is empty")
+		}
+		return nil, yoerrors.NewUsageError("invalid batch spec: %s", err)
+	}
+	if len(entries) == 0 {
+		return nil, yoerrors.NewUsageError("batch spec is empty")
+	}
+	seen := make(map[string]bool, len(entries))
+	for i, e := range entries {
+		if e.Name == "" {
+			return nil, yoerrors.NewUsageError("batch spec entry %d: name is required", i+1)
+		}
+		if err := cliutil.ValidateName(e.Name); err != nil {
+			return nil, err
+		}
+		if seen[e.Name] {
+			return nil, yoerrors.NewUsageError("batch spec lists %q more than once", e.Name)
+		}
+		seen[e.Name] = true
+		if e.Workdir == "" && e.Profile == "" {
+			return nil, yoerrors.NewUsageError("batch spec entry %q: workdir is required (or set a profile)", e.Name)
+		}
+	}
+	return entries, nil
+}
+
+// batchCreateOptions builds the create options for one spec entry. A relative
+// workdir is anchored at specDir so a spec works from any current directory;
+// ~ and $VAR forms are left for ParseDirArg to expand.
+func batchCreateOptions(e batchEntry, specDir string) (yoloai.SandboxCreateOptions, error) {
+	rawWorkdir := e.Workdir
+	if rawWorkdir != "" && !filepath.IsAbs(rawWorkdir) && !strings.HasPrefix(rawWorkdir, "~") && !strings.HasPrefix(rawWorkdir, "$") {
+		rawWorkdir = filepath.Join(specDir, rawWorkdir)
+	}
+	workdirSpec, _, err := resolveNewDirSpecs(rawWorkdir, nil)
+	if err != nil {
+		return yoloai.SandboxCreateOptions{}, err
+	}
+	agentName := e.Agent
+	if agentName == "" {
+		agentName = cliutil.ResolveAgentFromConfig()
+	}
+	return yoloai.SandboxCreateOptions{
+		Name:      e.Name,
+		Workdir:   workdirSpec,
+		AgentType: yoloai.AgentType(agentName),
+		Model:     cliutil.ResolveModelFromConfig(),
+		Profile:   e.Profile,
+		Prompt:    e.Prompt,
+		// Concurrent creates would interleave their progress lines; the batch
+		// reports one line per sandbox instead.
+		Output: io.Discard,
+	}, nil
+}
+
+// runBatchPool runs fn over every entry with at most jobs in flight, returning
+// the results in spec order.
+func runBatchPool(ctx context.Context, optsList []yoloai.SandboxCreateOptions, jobs int, fn func(context.Context, yoloai.SandboxCreateOptions) batchResult) []batchResult {
+	results := make([]batchResult, len(optsList))
+	sem := make(chan struct{}, jobs)
+	var wg sync.WaitGroup
+	for i, opts := range optsList {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			if ctx.Err() != nil {
+				results[i] = batchResult{Name: opts.Name, Error: ctx.Err().Error()}
+				return
+			}
+			results[i] = fn(ctx, opts)
+		}()
+	}
+	wg.Wait()
+	return results
+}
+
+// createForBatch creates one sandbox and, unless noStart, launches its agent.
+// A failed launch rolls the sandbox back, as `new` does, so a retry of the
+// batch is not blocked by a half-made sandbox of the same name.
+func createForBatch(cmd *cobra.Command, ctx context.Context, c *yoloai.Client, opts yoloai.SandboxCreateOptions, noStart bool) batchResult {
+	slog.Info("creating sandbox", "event", "sandbox.batch.create", "sandbox", opts.Name)
+	sb, err := c.CreateSandbox(ctx, opts)
+	if err != nil {
+		return batchResult{Name: opts.Name, Error: err.Error()}
+	}
+	if noStart {
+		return batchResult{Name: opts.Name, Action: "created"}
+	}
+	res, err := sb.Start(ctx, yoloai.SandboxStartOptions{})
+	if res != nil && !cliutil.JSONEnabled(cmd) {
+		cliutil.RenderWarnings(cmd, res.Notices)
+	}
+	if err != nil {
+		rollbackFailedStart(ctx, sb)
+		return batchResult{Name: opts.Name, Error: err.Error()}
+	}
+	slog.Info("sandbox started", "event", "sandbox.batch.started", "sandbox", opts.Name)
+	return batchResult{Name: opts.Name, Action: "started"}
+}
+
+// reportBatch renders the results and returns an error if any entry failed.
+func reportBatch(cmd *cobra.Command, results []batchResult) error {
+	failed := 0
+	for _, r := range results {
+		if r.Error != "" {
+			failed++
+		}
+	}
+	if cliutil.JSONEnabled(cmd) {
+		if err := cliutil.WriteJSONList(cmd.OutOrStdout(), "sandboxes", results); err != nil {
+			return err
+		}
+	} else {
+		out := cmd.OutOrStdout()
+		for _, r := range results {
+			if r.Error != "" {
+				fmt.Fprintf(cmd.ErrOrStderr(), "Warning: create %s: %s\n", r.Name, r.Error) //nolint:errcheck // best-effort output
+				continue
+			}
+			verb := "Started"
+			if r.Action == "created" {
+				verb = "Created"
+			}
+			fmt.Fprintf(out, "%s %s\n", verb, r.Name) //nolint:errcheck // best-effort output
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("failed to create %d of %d sandbox(es)", failed, len(results))
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+// ABOUTME: Tests for `batch create`: spec parsing and up-front validation, and
+// ABOUTME: the bounded worker pool's concurrency limit and result ordering.
+
+package lifecycle
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	yoloai "github.com/kstenerud/yoloai"
+	"github.com/kstenerud/yoloai/yoerrors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseBatchSpec(t *testing.T) {
+	entries, err := parseBatchSpec([]byte(`
+- name: fix-login
+  workdir: ./webapp
+  prompt: Fix the login loop
+- name: docs
+  profile: docs-env
+  agent: codex
+`))
+	require.NoError(t, err)
+	assert.Equal(t, []batchEntry{
+		{Name: "fix-login", Workdir: "./webapp", Prompt: "Fix the login loop"},
+		{Name: "docs", Profile: "docs-env", Agent: "codex"},
+	}, entries)
+}
+
+func TestParseBatchSpec_Invalid(t *testing.T) {
+	for desc, spec := range map[string]string{
+		"empty":        ``,
+		"empty list":   `[]`,
+		"missing name": "- workdir: .\n",
+		"bad name":     "- name: ../x\n  workdir: .\n",
+		"duplicate":    "- name: a\n  workdir: .\n- name: a\n  workdir: .\n",
+		"no workdir":   "- name: a\n",
+		"unknown key":  "- name: a\n  workdir: .\n  promt: typo\n",
+		"not a list":   "name: a\n",
+	} {
+		_, err := parseBatchSpec([]byte(spec))
+		var ue *yoerrors.UsageError
+		assert.ErrorAs(t, err, &ue, desc)
+	}
+}
+
+func TestRunBatchPool_BoundsConcurrency(t *testing.T) {
+	optsList := make([]yoloai.SandboxCreateOptions, 10)
+	for i := range optsList {
+		optsList[i].Name = string(rune('a' + i))
+	}
+	var inFlight, peak atomic.Int32
+	results := runBatchPool(context.Background(), optsList, 3, func(_ context.Context, opts yoloai.SandboxCreateOptions) batchResult {
+		n := inFlight.Add(1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		time.Sleep(5 * time.Millisecond)
+		inFlight.Add(-1)
+		return batchResult{Name: opts.Name, Action: "started"}
+	})
+
+	assert.LessOrEqual(t, peak.Load(), int32(3))
+	require.Len(t, results, 10)
+	for i, r := range results {
+		assert.Equal(t, optsList[i].Name, r.Name, "results stay in spec order")
+	}
+}
@@ -118,6 +118,7 @@ func NewCloneCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:     "clone <source> <dest>",
 		Short:   "Clone a sandbox",
+		Example: cliutil.CommandExamples("clone"),
 		GroupID: cliutil.GroupLifecycle,
 		Args:    cobra.ExactArgs(2),
 		RunE:    runClone,
@@ -0,0 +1,69 @@
+// ABOUTME: `yoloai commit <name> --image <ref>` — save a sandbox's container as an image
+// ABOUTME: that `new --image` starts other sandboxes from.
+package lifecycle
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/kstenerud/yoloai/internal/cli/cliutil"
+
+	yoloai "github.com/kstenerud/yoloai"
+	"github.com/spf13/cobra"
+)
+
+func NewCommitCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "commit <name> --image <ref>",
+		Short: "Save a sandbox's environment as an image for new sandboxes",
+		Long: `Save a sandbox's container as an image, so new sandboxes can start from it.
+
+Set an environment up once by hand — install packages, log in to tools,
+warm caches — then commit it and create as many sandboxes from it as you
+like with 'yoloai new --image', without writing a Dockerfile.
+
+The image holds the container's own filesystem. The mounted directories are
+left out: the work copies, the agent's state, and the credentials yoloai
+passes in. Anything else the sandbox wrote is in it, so don't commit a
+sandbox that saved secrets into its home directory. A running sandbox is
+frozen for the moment of the commit and keeps running.
+
+Names starting with yoloai- are reserved for yoloai's own images, and
+'yoloai system prune --images' leaves committed images alone. Supported on
+docker and podman.`,
+		Example: cliutil.CommandExamples("commit"),
+		GroupID: cliutil.GroupLifecycle,
+		Args:    cobra.ArbitraryArgs,
+		RunE:    runCommitCmd,
+	}
+	cmd.Flags().String("image", "", "Image name to save as, e.g. my-env:v1 (required)")
+	_ = cmd.MarkFlagRequired("image")
+	return cmd
+}
+
+func runCommitCmd(cmd *cobra.Command, args []string) error {
+	name, _, err := cliutil.ResolveName(cmd, args)
+	if err != nil {
+		return err
+	}
+	imageRef := cliutil.FlagStr(cmd, "image")
+	defer cliutil.OpenCLIJSONLSink(name, cmd)()
+
+	return cliutil.WithSandbox(cmd, name, func(ctx context.Context, sb *yoloai.Sandbox) error {
+		if err := sb.CommitImage(ctx, imageRef); err != nil {
+			return err
+		}
+		slog.Info("sandbox committed", "event", "sandbox.committed", "sandbox", name, "image", imageRef)
+
+		if cliutil.JSONEnabled(cmd) {
+			return cliutil.WriteJSON(cmd.OutOrStdout(), map[string]string{
+				"name":   name,
+				"action": "committed",
+				"image":  imageRef,
+			})
+		}
+		_, err := fmt.Fprintf(cmd.OutOrStdout(), "Saved %s as image %s\nStart a sandbox from it: yoloai new <name> <workdir> --image %s\n", name, imageRef, imageRef)
+		return err
+	})
+}
@@ -0,0 +1,351 @@
+// ABOUTME: `yoloai compare` — race several agents on the same prompt and workdir,
+// ABOUTME: wait for them all, and show their diffstats side by side.
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"slices"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/kstenerud/yoloai/internal/cli/cliutil"
+
+	yoloai "github.com/kstenerud/yoloai"
+	"github.com/kstenerud/yoloai/yoerrors"
+	"github.com/spf13/cobra"
+)
+
+// defaultComparePrefix names compare's sandboxes when --name is not given.
+const defaultComparePrefix = "compare"
+
+func NewCompareCmd(version string) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "compare --prompt <text> --agents <a,b,...> [--workdir <dir>]",
+		Short: "Race agents on the same prompt and compare their changes",
+		Long: `Give the same prompt and workdir to several agents at once, wait for them all
+to finish, and show what each changed side by side.
+
+Each agent gets its own headless sandbox, named <prefix>-<agent> (--name sets
+the prefix, default "compare"; an agent listed twice gets -1, -2, ...). The
+sandboxes are left in place afterwards, so the usual tools work on them:
+
+  yoloai compare -p "fix the flaky login test" --agents claude,gemini,codex
+  yoloai compare diff compare-claude compare-gemini   # how the two differ
+  yoloai apply compare-claude                         # keep the winner
+  yoloai destroy compare-gemini compare-codex
+
+The workdir must be copied (:copy, the default), so every agent starts from
+the same files. One sandbox failing to start does not stop the others;
+failures are listed and the command exits non-zero.`,
+		GroupID: cliutil.GroupLifecycle,
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return runCompare(cmd, version)
+		},
+	}
+
+	cmd.Flags().StringP("prompt", "p", "", "Prompt text for every agent")
+	cmd.Flags().StringP("prompt-file", "f", "", "File containing the prompt")
+	cmd.Flags().StringSlice("agents", nil, "Agents to race, comma-separated (at least two)")
+	cmd.Flags().String("workdir", ".", "Directory every agent works on (copied into each sandbox)")
+	cmd.Flags().String("name", defaultComparePrefix, "Prefix for the sandbox names")
+	cmd.Flags().String("backend", "", "Runtime backend (see 'yoloai system backends')")
+	cmd.Flags().Duration("timeout", 0, "Stop waiting after this long and show what the agents have so far (0 = no limit)")
+	cmd.Flags().Bool("allow-dirty", false, "Proceed even if the workdir has uncommitted changes (they will be visible to the agents)")
+	_ = cmd.MarkFlagRequired("agents")
+	cmd.MarkFlagsMutuallyExclusive("prompt", "prompt-file")
+
+	cmd.AddCommand(newCompareDiffCmd())
+	return cmd
+}
+
+func newCompareDiffCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "diff <a> <b>",
+		Short: "Show how two sandboxes' work copies differ",
+		Long: `Show the diff from sandbox a's work copy to sandbox b's, with paths prefixed
+by the sandbox names. Only files either agent changed are compared, so the
+sandboxes should have started from the same workdir, as 'yoloai compare'
+sets them up. Both must be running.`,
+		Args: cobra.ExactArgs(2),
+		RunE: runCompareDiff,
+	}
+	cmd.Flags().Bool("stat", false, "Show summary (files changed, insertions, deletions)")
+	return cmd
+}
+
+// compareResult is one agent's outcome, shared by the table and JSON renderings.
+type compareResult struct {
+	Name    string          `json:"name"`
+	Agent   string          `json:"agent"`
+	Status  yoloai.Status   `json:"status,omitempty"`
+	Changes *yoloai.Changes `json:"changes,omitempty"`
+	Error   string          `json:"error,omitempty"`
+}
+
+func runCompare(cmd *cobra.Command, version string) error {
+	prompt, _ := cmd.Flags().GetString("prompt")
+	promptFile, _ := cmd.Flags().GetString("prompt-file")
+	agents, _ := cmd.Flags().GetStringSlice("agents")
+	rawWorkdir, _ := cmd.Flags().GetString("workdir")
+	prefix, _ := cmd.Flags().GetString("name")
+	timeout, _ := cmd.Flags().GetDuration("timeout")
+	allowDirty, _ := cmd.Flags().GetBool("allow-dirty")
+	if prompt == "" && promptFile == "" {
+		return yoerrors.NewUsageError("yoloai compare requires a prompt (--prompt or --prompt-file)")
+	}
+	if timeout < 0 {
+		return yoerrors.NewUsageError("--timeout must not be negative: %s", timeout)
+	}
+	names, err := compareNames(prefix, agents)
+	if err != nil {
+		return err
+	}
+	workdirSpec, _, err := resolveNewDirSpecs(rawWorkdir, nil)
+	if err != nil {
+		return err
+	}
+	if workdirSpec.Mode == yoloai.DirModeRW {
+		return yoerrors.NewUsageError("compare needs a copied workdir: with :rw every agent would edit the same files")
+	}
+
+	optsList := make([]yoloai.SandboxCreateOptions, len(agents))
+	for i, agentName := range agents {
+		optsList[i] = yoloai.SandboxCreateOptions{
+			Name:              names[i],
+			Workdir:           workdirSpec,
+			AgentType:         yoloai.AgentType(agentName),
+			Prompt:            prompt,
+			PromptFile:        promptFile,
+			Headless:          true,
+			AllowDirtyWorkdir: allowDirty,
+			// Concurrent creates would interleave their progress lines; compare
+			// reports per sandbox instead.
+			Output: io.Discard,
+		}
+	}
+
+	if !cliutil.JSONEnabled(cmd) {
+		cliutil.WarnIfLowDisk(cmd.ErrOrStderr(), cliutil.Layout().SandboxesDir())
+	}
+
+	c, err := newCreateClient(cmd, version)
+	if err != nil {
+		return err
+	}
+	defer c.Close() //nolint:errcheck // best-effort cleanup
+
+	ctx := cmd.Context()
+	// Setup is shared, as for batch create: run it once before the creates.
+	if err := c.EnsureSetup(ctx); err != nil {
+		return err
+	}
+
+	started := runBatchPool(ctx, optsList, len(optsList), func(ctx context.Context, opts yoloai.SandboxCreateOptions) batchResult {
+		return createForBatch(cmd, ctx, c, opts, false)
+	})
+	if !cliutil.JSONEnabled(cmd) {
+		fmt.Fprintf(cmd.ErrOrStderr(), "Waiting for %s...\n", strings.Join(agents, ", ")) //nolint:errcheck // best-effort output
+	}
+
+	results := make([]compareResult, len(started))
+	var wg sync.WaitGroup
+	for i, s := range started {
+		results[i] = compareResult{Name: s.Name, Agent: agents[i], Error: s.Error}
+		if s.Error != "" {
+			continue
+		}
+		wg.Go(func() { waitForCompare(ctx, c, &results[i], timeout) })
+	}
+	wg.Wait()
+	return reportCompare(cmd, results)
+}
+
+// compareNames derives a sandbox name per agent: <prefix>-<agent>, numbered
+// when an agent is listed more than once.
+func compareNames(prefix string, agents []string) ([]string, error) {
+	if len(agents) < 2 {
+		return nil, yoerrors.NewUsageError("--agents needs at least two agents to compare")
+	}
+	count := make(map[string]int, len(agents))
+	for _, a := range agents {
+		if a == "" {
+			return nil, yoerrors.NewUsageError("--agents has an empty entry")
+		}
+		count[a]++
+	}
+	seen := make(map[string]int, len(agents))
+	names := make([]string, len(agents))
+	for i, a := range agents {
+		names[i] = prefix + "-" + a
+		if count[a] > 1 {
+			seen[a]++
+			names[i] = fmt.Sprintf("%s-%d", names[i], seen[a])
+		}
+		if err := cliutil.ValidateName(names[i]); err != nil {
+			return nil, err
+		}
+	}
+	return names, nil
+}
+
+// waitForCompare waits for one agent to finish and records its status and
+// changes. A headless agent exits when done; one downgraded to interactive
+// (no usable headless credentials) finishes its turn and goes idle.
+func waitForCompare(ctx context.Context, c *yoloai.Client, r *compareResult, timeout time.Duration) {
+	sb, err := c.Sandbox(r.Name)
+	if err != nil {
+		r.Error = err.Error()
+		return
+	}
+	waitFor := yoloai.WaitForExit
+	if meta, metaErr := sb.Metadata(); metaErr == nil && !meta.Headless {
+		waitFor = yoloai.WaitForIdle
+	}
+	info, err := sb.Wait(ctx, yoloai.SandboxWaitOptions{For: waitFor, Timeout: timeout})
+	if info != nil {
+		r.Status = info.Status
+	}
+	if err != nil && !errors.Is(err, yoloai.ErrWaitTimeout) {
+		r.Error = err.Error()
+		return
+	}
+	slog.Info("compare agent finished", "event", "sandbox.compare.done", "sandbox", r.Name, "status", r.Status)
+	changes, err := sb.Workdir().Changes(ctx)
+	if err != nil {
+		r.Error = err.Error()
+		return
+	}
+	r.Changes = changes
+}
+
+// reportCompare renders the results and returns an error if any sandbox
+// could not be created, run or read.
+func reportCompare(cmd *cobra.Command, results []compareResult) error {
+	failed := 0
+	for _, r := range results {
+		if r.Error != "" {
+			failed++
+		}
+	}
+	if cliutil.JSONEnabled(cmd) {
+		if err := cliutil.WriteJSONList(cmd.OutOrStdout(), "sandboxes", results); err != nil {
+			return err
+		}
+	} else {
+		for _, r := range results {
+			if r.Error != "" {
+				fmt.Fprintf(cmd.ErrOrStderr(), "Warning: %s: %s\n", r.Name, r.Error) //nolint:errcheck // best-effort output
+			}
+		}
+		if err := writeCompareTable(cmd.OutOrStdout(), results); err != nil {
+			return err
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d agent(s) could not be compared", failed, len(results))
+	}
+	return nil
+}
+
+// writeCompareTable prints one column per sandbox: its status, a line per
+// file any agent changed, and the totals.
+func writeCompareTable(out io.Writer, results []compareResult) error {
+	w := tabwriter.NewWriter(out, 0, 0, 3, ' ', 0)
+	row := func(label string, cell func(compareResult) string) {
+		cells := []string{label}
+		for _, r := range results {
+			cells = append(cells, cell(r))
+		}
+		fmt.Fprintln(w, strings.Join(cells, "\t")) //nolint:errcheck // flushed below
+	}
+
+	row("", func(r compareResult) string { return r.Name })
+	row("status", func(r compareResult) string {
+		if r.Status == "" {
+			return "error"
+		}
+		return string(r.Status)
+	})
+	var paths []string
+	for _, r := range results {
+		if r.Changes != nil {
+			for _, f := range r.Changes.Files {
+				paths = append(paths, f.Path)
+			}
+		}
+	}
+	slices.Sort(paths)
+	for _, p := range slices.Compact(paths) {
+		row(p, func(r compareResult) string {
+			if r.Changes != nil {
+				for _, f := range r.Changes.Files {
+					if f.Path == p {
+						return formatFileChange(f)
+					}
+				}
+			}
+			return "-"
+		})
+	}
+	row("total", func(r compareResult) string {
+		if r.Changes == nil {
+			return "-"
+		}
+		files := "files"
+		if len(r.Changes.Files) == 1 {
+			files = "file"
+		}
+		return fmt.Sprintf("%d %s +%d -%d", len(r.Changes.Files), files, r.Changes.Additions, r.Changes.Deletions)
+	})
+	return w.Flush()
+}
+
+// formatFileChange renders one file's line counts as +A -D, or "binary".
+func formatFileChange(f yoloai.FileChange) string {
+	if f.Binary {
+		return "binary"
+	}
+	return fmt.Sprintf("+%d -%d", f.Additions, f.Deletions)
+}
+
+func runCompareDiff(cmd *cobra.Command, args []string) error {
+	a, b := args[0], args[1]
+	if a == b {
+		return yoerrors.NewUsageError("compare diff needs two different sandboxes")
+	}
+	stat, _ := cmd.Flags().GetBool("stat")
+	backend := cliutil.ResolveBackendForSandbox(a)
+	if other := cliutil.ResolveBackendForSandbox(b); other != backend {
+		return yoerrors.NewUsageError("%s runs on %s and %s on %s: only sandboxes on the same backend can be compared", a, backend, b, other)
+	}
+	return cliutil.WithClient(cmd, backend, func(ctx context.Context, c *yoloai.Client) error {
+		sbA, err := c.Sandbox(a)
+		if err != nil {
+			return cliutil.SandboxErrorHint(a, err)
+		}
+		sbB, err := c.Sandbox(b)
+		if err != nil {
+			return cliutil.SandboxErrorHint(b, err)
+		}
+		out, err := sbA.Workdir().Compare(ctx, sbB.Workdir(), yoloai.WorkdirCompareOptions{Stat: stat})
+		if err != nil {
+			return err
+		}
+		if cliutil.JSONEnabled(cmd) {
+			return cliutil.WriteJSON(cmd.OutOrStdout(), map[string]string{"a": a, "b": b, "diff": out})
+		}
+		if out == "" {
+			fmt.Fprintf(cmd.ErrOrStderr(), "%s and %s made the same changes\n", a, b) //nolint:errcheck // best-effort output
+			return nil
+		}
+		fmt.Fprintln(cmd.OutOrStdout(), out) //nolint:errcheck // best-effort output
+		return nil
+	})
+}
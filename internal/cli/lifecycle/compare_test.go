@@ -0,0 +1,59 @@
+// ABOUTME: Tests for `compare`: per-agent sandbox naming and validation, and the
+// ABOUTME: side-by-side diffstat table.
+
+package lifecycle
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	yoloai "github.com/kstenerud/yoloai"
+	"github.com/kstenerud/yoloai/yoerrors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompareNames(t *testing.T) {
+	names, err := compareNames("race", []string{"claude", "gemini", "claude"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"race-claude-1", "race-gemini", "race-claude-2"}, names)
+}
+
+func TestCompareNames_Invalid(t *testing.T) {
+	for desc, agents := range map[string][]string{
+		"none":        nil,
+		"just one":    {"claude"},
+		"empty entry": {"claude", ""},
+	} {
+		_, err := compareNames("compare", agents)
+		var ue *yoerrors.UsageError
+		assert.ErrorAs(t, err, &ue, desc)
+	}
+	_, err := compareNames("../x", []string{"claude", "gemini"})
+	assert.Error(t, err)
+}
+
+func TestWriteCompareTable(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, writeCompareTable(&buf, []compareResult{
+		{Name: "compare-claude", Agent: "claude", Status: yoloai.StatusDone, Changes: &yoloai.Changes{
+			Files:     []yoloai.FileChange{{Path: "b.go", Additions: 3, Deletions: 1}, {Path: "logo.png", Additions: -1, Deletions: -1, Binary: true}},
+			Additions: 3, Deletions: 1,
+		}},
+		{Name: "compare-gemini", Agent: "gemini", Status: yoloai.StatusFailed, Changes: &yoloai.Changes{
+			Files:     []yoloai.FileChange{{Path: "a.go", Additions: 2}},
+			Additions: 2,
+		}},
+		{Name: "compare-codex", Agent: "codex", Error: "create failed"},
+	}))
+
+	lines := strings.Split(strings.TrimRight(buf.String(), "\n"), "\n")
+	require.Len(t, lines, 6)
+	assert.Equal(t, []string{"compare-claude", "compare-gemini", "compare-codex"}, strings.Fields(lines[0]))
+	assert.Equal(t, []string{"status", "done", "failed", "error"}, strings.Fields(lines[1]))
+	assert.Equal(t, []string{"a.go", "-", "+2", "-0", "-"}, strings.Fields(lines[2]))
+	assert.Equal(t, []string{"b.go", "+3", "-1", "-", "-"}, strings.Fields(lines[3]))
+	assert.Equal(t, []string{"logo.png", "binary", "-", "-"}, strings.Fields(lines[4]))
+	assert.Equal(t, []string{"total", "2", "files", "+3", "-1", "1", "file", "+2", "-0", "-"}, strings.Fields(lines[5]))
+}
@@ -53,6 +53,7 @@ func NewDestroyCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:     "destroy <name>...",
 		Short:   "Stop and remove sandboxes",
+		Example: cliutil.CommandExamples("destroy"),
 		GroupID: cliutil.GroupLifecycle,
 		Args:    cobra.ArbitraryArgs,
 		RunE:    runDestroyCmd,
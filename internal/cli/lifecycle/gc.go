@@ -0,0 +1,180 @@
+// ABOUTME: `yoloai gc` — destroys sandboxes whose TTL (--ttl / the ttl config
+// ABOUTME: key) has run out, keeping any that still hold unapplied changes, and
+// ABOUTME: applies the retention_days scrub to the trash.
+package lifecycle
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/kstenerud/yoloai/internal/cli/cliutil"
+
+	yoloai "github.com/kstenerud/yoloai"
+	"github.com/spf13/cobra"
+)
+
+func NewGCCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "gc",
+		Short: "Destroy sandboxes whose TTL has expired",
+		Long: `Destroy every sandbox whose TTL has expired.
+
+A sandbox gets a TTL from 'new --ttl 4h' (or 'run --ttl'), or from the ttl
+config key. Durations are Go-style (90m, 4h) or whole days (7d). Once the TTL
+has passed, the sandbox is expired: 'yoloai sandbox <name> info' says so, and
+gc destroys it — running or not.
+
+An expired sandbox that still holds unapplied changes is kept and reported,
+so forgotten work is never thrown away by a sweep. Apply or discard it, or
+pass --abandon-unapplied to destroy it anyway.
+
+When the retention_days config key is set, gc also scrubs the prompts, logs
+and transcripts of trash entries older than that (see 'yoloai scrub').
+
+gc never prompts, so it is safe to run from cron or a login script, and
+'yoloai daemon install' runs it in the background for you.`,
+		Example: `  yoloai new fix-bug . --ttl 4h
+  yoloai gc --dry-run
+  yoloai gc`,
+		GroupID: cliutil.GroupLifecycle,
+		Args:    cobra.NoArgs,
+		RunE:    runGCCmd,
+	}
+
+	cmd.Flags().Bool("dry-run", false, "Report expired sandboxes without destroying them")
+	cmd.Flags().Bool("abandon-unapplied", false, "Destroy expired sandboxes even when they have unapplied changes")
+
+	return cmd
+}
+
+// gcResult is one expired sandbox's outcome.
+type gcResult struct {
+	Name      string    `json:"name"`
+	ExpiresAt time.Time `json:"expires_at"`
+	Action    string    `json:"action"` // destroyed, would-destroy, kept, already-gone, failed
+	Reason    string    `json:"reason,omitempty"`
+	Error     string    `json:"error,omitempty"`
+}
+
+func runGCCmd(cmd *cobra.Command, _ []string) error {
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	abandonUnapplied, _ := cmd.Flags().GetBool("abandon-unapplied")
+
+	backend, warn := yoloai.SelectContainerBackend(cmd.Context(), cliutil.ResolveContainerBackendConfig(), cliutil.Layout().Env().EnvForDaemonDiscovery())
+	if warn != "" {
+		fmt.Fprintln(os.Stderr, warn)
+	}
+
+	return cliutil.WithClient(cmd, backend, func(ctx context.Context, c *yoloai.Client) error {
+		infos, err := c.ListSandboxes(ctx)
+		if err != nil {
+			return fmt.Errorf("list sandboxes: %w", err)
+		}
+
+		var results []gcResult
+		failed := 0
+		now := time.Now()
+		for _, info := range infos {
+			env := info.Environment
+			if env == nil || !env.Expired(now) {
+				continue
+			}
+			r := gcOne(cmd, ctx, c, env.Name, dryRun, abandonUnapplied)
+			r.ExpiresAt = *env.ExpiresAt
+			if r.Action == "failed" {
+				failed++
+			}
+			results = append(results, r)
+		}
+
+		// The retention policy rides along, so one cron entry covers both.
+		scrubbed, scrubErr := scrubTrash(nil, dryRun)
+		if scrubErr != nil {
+			fmt.Fprintf(os.Stderr, "Warning: retention scrub: %v\n", scrubErr)
+		}
+
+		if cliutil.JSONEnabled(cmd) {
+			out := map[string]any{"sandboxes": cliutil.EmptyIfNil(results)}
+			if scrubbed != nil && scrubbed.RetentionDays >= 0 {
+				out["scrubbed"] = scrubEntriesJSON(scrubbed)
+			}
+			if err := cliutil.WriteJSON(cmd.OutOrStdout(), out); err != nil {
+				return err
+			}
+		} else {
+			printGCResults(cmd, results)
+			if scrubbed != nil && len(scrubbed.Entries) > 0 {
+				printScrubResult(cmd, scrubbed, dryRun)
+			}
+		}
+		if failed > 0 {
+			return fmt.Errorf("failed to destroy %d expired sandbox(es)", failed)
+		}
+		return nil
+	})
+}
+
+// gcOne decides and carries out the fate of one expired sandbox.
+func gcOne(cmd *cobra.Command, ctx context.Context, c *yoloai.Client, name string, dryRun, abandonUnapplied bool) gcResult {
+	r := gcResult{Name: name}
+	if !abandonUnapplied {
+		sb, err := c.Sandbox(name)
+		if err != nil {
+			r.Action, r.Error = "failed", err.Error()
+			return r
+		}
+		if active, reason := sb.HasActiveWork(ctx); active {
+			r.Action, r.Reason = "kept", reason
+			return r
+		}
+	}
+	if dryRun {
+		r.Action = "would-destroy"
+		return r
+	}
+
+	slog.Info("destroying expired sandbox", "event", "sandbox.gc", "sandbox", name)
+	gone, err := destroyOne(cmd, ctx, c, name, abandonUnapplied)
+	switch {
+	case err != nil:
+		r.Action, r.Error = "failed", err.Error()
+	case gone:
+		r.Action = "already-gone"
+	default:
+		slog.Info("sandbox destroyed", "event", "sandbox.destroy.complete", "sandbox", name)
+		r.Action = "destroyed"
+	}
+	return r
+}
+
+// printGCResults reports each expired sandbox's outcome (human mode).
+func printGCResults(cmd *cobra.Command, results []gcResult) {
+	out := cmd.OutOrStdout()
+	if len(results) == 0 {
+		fmt.Fprintln(out, "No expired sandboxes") //nolint:errcheck // best-effort output
+		return
+	}
+	kept := 0
+	for _, r := range results {
+		expired := "expired " + cliutil.FormatAge(r.ExpiresAt) + " ago"
+		switch r.Action {
+		case "destroyed":
+			fmt.Fprintf(out, "Destroyed %s (%s)\n", r.Name, expired) //nolint:errcheck // best-effort output
+		case "would-destroy":
+			fmt.Fprintf(out, "Would destroy %s (%s)\n", r.Name, expired) //nolint:errcheck // best-effort output
+		case "already-gone":
+			fmt.Fprintf(out, "%s: already gone\n", r.Name) //nolint:errcheck // best-effort output
+		case "kept":
+			kept++
+			fmt.Fprintf(out, "Kept %s (%s): %s\n", r.Name, expired, r.Reason) //nolint:errcheck // best-effort output
+		case "failed":
+			fmt.Fprintf(os.Stderr, "Warning: destroy %s: %s\n", r.Name, r.Error)
+		}
+	}
+	if kept > 0 {
+		fmt.Fprintln(out, "Apply or discard the kept sandboxes' changes, or rerun with --abandon-unapplied to destroy them anyway.") //nolint:errcheck // best-effort output
+	}
+}
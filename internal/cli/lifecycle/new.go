@@ -8,6 +8,9 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
 	goruntime "runtime"
 	"strconv"
 	"strings"
@@ -24,6 +27,7 @@ func NewNewCmd(version string) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:     "new [flags] <name> [workdir] [-d <dir>...] [-- <agent-args>...]",
 		Short:   "Create and start a sandbox",
+		Example: cliutil.CommandExamples("new"),
 		GroupID: cliutil.GroupLifecycle,
 		Args:    cobra.ArbitraryArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
@@ -49,7 +53,8 @@ func addCreateFlags(cmd *cobra.Command) {
 	cmd.Flags().StringP("model", "m", "", "Model name or alias")
 	cmd.Flags().String("agent", "", "Agent to use (default from config or claude)")
 	cmd.Flags().String("profile", "", "Profile to use (from ~/.yoloai/profiles/)")
-	cmd.Flags().Bool("no-profile", false, "Use base image even if config sets a default profile")
+	cmd.Flags().Bool("no-profile", false, "Use the base image even if the project's .yoloai.yaml names a profile")
+	cmd.Flags().String("image", "", "Start from an image saved by 'yoloai commit' instead of the base image (docker, podman)")
 	cmd.Flags().String("backend", "", "Runtime backend (see 'yoloai system backends')")
 	cmd.Flags().Bool("network-none", false, "Disable network access")
 	cmd.Flags().Bool("network-isolated", false, "Allow only agent API traffic (IPv4 iptables allowlist; IPv6 is not filtered)")
@@ -69,10 +74,21 @@ func addCreateFlags(cmd *cobra.Command) {
 	cmd.Flags().Bool("broker", false, "Require credential brokering: keep the agent's API key host-side (errors if the backend can't). On by default for supported backends (Linux docker)")
 	cmd.Flags().Bool("no-broker", false, "Disable credential brokering: deliver the agent's API key into the sandbox directly (sticky across restart)")
 	cmd.Flags().String("archetype", "", fmt.Sprintf("Environment archetype (%s)", strings.Join(yoloai.Archetypes(), "|")))
+	cmd.Flags().String("work-root", "", "Directory to hold this sandbox's work copies (e.g. a fast scratch disk) instead of the sandbox dir")
+	cmd.Flags().String("ttl", "", "Lifetime after which 'yoloai gc' destroys the sandbox, e.g. 4h, 7d (default from config; 0 = never)")
+	cmd.Flags().String("faketime", "", `Run the sandbox's clock through libfaketime: an offset (-3d, +2h), a frozen time ("2024-02-29 12:00:00") or a start time ("@2024-02-29 12:00:00"); "none" overrides config`)
+	cmd.Flags().StringArray("role", nil, "Split-role session: NAME=PROMPT (or NAME=@FILE) runs one agent per role in its own tmux window, against the same files (repeatable, at least two; replaces --prompt)")
+	cmd.Flags().Bool("allow-push", false, "Let the agent git push to real remotes (by default pushes from inside the sandbox fail)")
+	cmd.Flags().String("repo", "", "Clone this repository (URL[@ref]) as the workdir instead of copying a host directory; apply then pushes to a branch (--push-branch)")
+	cmd.Flags().Int("depth", 0, "With --repo: shallow-clone only the last N commits of the ref (default: full history)")
+	cmd.Flags().StringSlice("sparse", nil, "With --repo: check out only these directories (cone-mode sparse checkout over a blobless clone)")
+	cmd.Flags().String("agent-workdir", "", "Start the agent in this subdirectory of the workdir (e.g. packages/api); the whole workdir is still mounted and diffed")
+	cmd.Flags().String("context", "agent", "Where the sandbox description for the agent goes: agent (its own instruction file, outside your files), worktree (the agent's instruction file or AGENTS.md in the :copy work copy, never diffed or applied), none")
 	cmd.Flags().Bool("copy-strict", false, "For :copy dirs, strip git history instead of preserving it (fresh baseline). Use for repos with unrotated secrets in history. Per-dir :copy-all / :copy-strict suffixes still win.")
 
 	cmd.MarkFlagsMutuallyExclusive("network-none", "network-isolated")
 	cmd.MarkFlagsMutuallyExclusive("profile", "no-profile")
+	cmd.MarkFlagsMutuallyExclusive("profile", "image")
 	cmd.MarkFlagsMutuallyExclusive("broker", "no-broker")
 }
 
@@ -125,14 +141,15 @@ func newCreateClient(cmd *cobra.Command, version string) (*yoloai.Client, error)
 	}
 	l := cliutil.Layout()
 	c, err := yoloai.NewClient(cmd.Context(), yoloai.ClientCreateOptions{
-		DataDir:     l.DataDir,
-		HomeDir:     l.HomeDir,
-		Principal:   string(l.Principal),
-		BackendType: yoloai.BackendType(cliutil.ResolveBackend(cmd)),
-		Input:       cmd.InOrStdin(),
-		Output:      mgrOutput,
-		Version:     version,
-		Env:         cliutil.BackendEnv(cmd),
+		DataDir:         l.DataDir,
+		HomeDir:         l.HomeDir,
+		Principal:       string(l.Principal),
+		SystemConfigDir: l.SystemConfigDir,
+		BackendType:     cliutil.ResolveCreateBackend(cmd),
+		Input:           cmd.InOrStdin(),
+		Output:          mgrOutput,
+		Version:         version,
+		Env:             cliutil.BackendEnv(cmd),
 	})
 	if err != nil {
 		return nil, fmt.Errorf("connect to runtime: %w", err)
@@ -156,7 +173,7 @@ func parseNewCmdPositional(cmd *cobra.Command, args []string) (name, rawWorkdirA
 	if len(positional) < 1 {
 		return "", "", nil, "", yoerrors.NewUsageError("sandbox name is required")
 	}
-	if len(positional) < 2 && profileFlag == "" {
+	if len(positional) < 2 && profileFlag == "" && cliutil.FlagStr(cmd, "repo") == "" {
 		return "", "", nil, "", yoerrors.NewUsageError("workdir is required (or use --profile)\n\nUsage: yoloai new [flags] <name> <workdir> [-- <agent-args>...]\n\nExample: yoloai new %s .", positional[0])
 	}
 	if len(positional) > 2 {
@@ -222,6 +239,13 @@ func resolveCreateOptions(cmd *cobra.Command, name, rawWorkdirArg string, passth
 	broker, _ := cmd.Flags().GetBool("broker")
 	noBroker, _ := cmd.Flags().GetBool("no-broker") // mutual exclusion enforced by MarkFlagsMutuallyExclusive
 	archetypeFlag, _ := cmd.Flags().GetString("archetype")
+	allowPush, _ := cmd.Flags().GetBool("allow-push")
+	noProfile, _ := cmd.Flags().GetBool("no-profile")
+
+	workRoot, err := resolveWorkRoot(cliutil.FlagStr(cmd, "work-root"))
+	if err != nil {
+		return yoloai.SandboxCreateOptions{}, err
+	}
 
 	isolation, _, err := resolveNewIsolationOS(cmd)
 	if err != nil {
@@ -233,6 +257,25 @@ func resolveCreateOptions(cmd *cobra.Command, name, rawWorkdirArg string, passth
 		return yoloai.SandboxCreateOptions{}, err
 	}
 
+	rawRoles, _ := cmd.Flags().GetStringArray("role")
+	roles, err := parseRoleFlags(rawRoles, cliutil.Layout())
+	if err != nil {
+		return yoloai.SandboxCreateOptions{}, err
+	}
+
+	repo := cliutil.FlagStr(cmd, "repo")
+	if repo != "" && rawWorkdirArg != "" {
+		return yoloai.SandboxCreateOptions{}, yoerrors.NewUsageError("--repo clones the workdir, so drop the workdir argument (%s)", rawWorkdirArg)
+	}
+
+	repoDepth, _ := cmd.Flags().GetInt("depth")
+	repoSparse, _ := cmd.Flags().GetStringSlice("sparse")
+
+	contextMode, err := parseContextFlag(cliutil.FlagStr(cmd, "context"))
+	if err != nil {
+		return yoloai.SandboxCreateOptions{}, err
+	}
+
 	workdirSpec, auxDirSpecs, err := resolveNewDirSpecs(rawWorkdirArg, rawDirs)
 	if err != nil {
 		return yoloai.SandboxCreateOptions{}, err
@@ -261,6 +304,8 @@ func resolveCreateOptions(cmd *cobra.Command, name, rawWorkdirArg string, passth
 		AgentType:            yoloai.AgentType(agentName),
 		Model:                model,
 		Profile:              profileFlag,
+		NoProfile:            noProfile,
+		Image:                cliutil.FlagStr(cmd, "image"),
 		Prompt:               prompt,
 		PromptFile:           promptFile,
 		Network:              networkMode,
@@ -279,6 +324,16 @@ func resolveCreateOptions(cmd *cobra.Command, name, rawWorkdirArg string, passth
 		Broker:               broker,
 		NoBroker:             noBroker,
 		Archetype:            archetypeFlag,
+		WorkRoot:             workRoot,
+		TTL:                  cliutil.FlagStr(cmd, "ttl"),
+		FakeTime:             cliutil.FlagStr(cmd, "faketime"),
+		AgentWorkdir:         cliutil.FlagStr(cmd, "agent-workdir"),
+		Roles:                roles,
+		AllowPush:            allowPush,
+		Context:              contextMode,
+		Repo:                 repo,
+		RepoDepth:            repoDepth,
+		RepoSparse:           repoSparse,
 		// A dirty workdir never auto-proceeds here. executeNewCreate surfaces the
 		// warning and requires --allow-dirty to widen the scope — we never prompt
 		// to widen it, so --yes (gone from this command) can't paper over it.
@@ -286,6 +341,20 @@ func resolveCreateOptions(cmd *cobra.Command, name, rawWorkdirArg string, passth
 	}, nil
 }
 
+// parseContextFlag maps --context onto the library's ContextMode, whose
+// default (the agent's instruction file) is the zero value.
+func parseContextFlag(raw string) (yoloai.ContextMode, error) {
+	switch raw {
+	case "", "agent":
+		return yoloai.ContextModeAgent, nil
+	case "worktree":
+		return yoloai.ContextModeWorktree, nil
+	case "none":
+		return yoloai.ContextModeNone, nil
+	}
+	return "", yoerrors.NewUsageError("--context must be agent, worktree or none: %q", raw)
+}
+
 // parsePortFlags parses --port "host:container" strings into typed PortMappings
 // at the CLI boundary (Q-Y: the public surface takes []PortMapping). Protocol
 // is tcp — the only mode the backend pipeline supports today.
@@ -325,6 +394,54 @@ func parseEnvSlice(envSlice []string) (map[string]string, error) {
 	return envMap, nil
 }
 
+// parseRoleFlags parses --role "NAME=PROMPT" values. A prompt starting with @
+// names a file to read it from (~ and ${VAR} expanded, as for --prompt-file).
+// Names and the role count are validated by the library.
+func parseRoleFlags(raw []string, layout config.Layout) ([]yoloai.SandboxRole, error) {
+	if len(raw) == 0 {
+		return nil, nil
+	}
+	roles := make([]yoloai.SandboxRole, 0, len(raw))
+	for _, r := range raw {
+		name, prompt, ok := strings.Cut(r, "=")
+		if !ok {
+			return nil, yoerrors.NewUsageError("invalid --role value %q: must be NAME=PROMPT or NAME=@FILE", r)
+		}
+		if path, isFile := strings.CutPrefix(prompt, "@"); isFile {
+			expanded, err := config.ExpandPath(path, layout.HomeDir, layout.Env().EnvForConfigInterpolation())
+			if err != nil {
+				return nil, yoerrors.NewUsageError("--role %s: %s", name, err)
+			}
+			data, err := os.ReadFile(expanded) //nolint:gosec // G304: user-named prompt file
+			if err != nil {
+				return nil, yoerrors.NewUsageError("--role %s: read prompt file: %s", name, err)
+			}
+			prompt = strings.TrimSpace(string(data))
+		}
+		roles = append(roles, yoloai.SandboxRole{Name: name, Prompt: prompt})
+	}
+	return roles, nil
+}
+
+// resolveWorkRoot expands and absolutizes the --work-root flag ("" stays "").
+// The library requires an absolute path; resolving ~ and relative paths against
+// the caller's cwd is the CLI's job, as with dir arguments.
+func resolveWorkRoot(raw string) (string, error) {
+	if raw == "" {
+		return "", nil
+	}
+	layout := cliutil.Layout()
+	expanded, err := config.ExpandPath(raw, layout.HomeDir, layout.Env().EnvForConfigInterpolation())
+	if err != nil {
+		return "", yoerrors.NewUsageError("invalid --work-root: %s", err)
+	}
+	abs, err := filepath.Abs(expanded)
+	if err != nil {
+		return "", yoerrors.NewUsageError("invalid --work-root: %s", err)
+	}
+	return abs, nil
+}
+
 // applyCopyStrict applies the --copy-strict default to a spec: strip git history
 // on the copy. An explicit :copy-all (IncludeIgnored) opts out of history
 // stripping entirely, so it is left untouched; a per-dir :copy-strict suffix
@@ -371,7 +488,7 @@ func resolveNewDirSpecs(rawWorkdirArg string, rawDirs []string) (workdirSpec yol
 // otherwise it returns the refusal. It never prompts: widening the destructive
 // scope to include a dirty workdir is opt-in via --allow-dirty alone.
 func executeNewCreate(cmd *cobra.Command, ctx context.Context, c *yoloai.Client, opts yoloai.SandboxCreateOptions, attach, noStart bool) error {
-	sb, err := createSandboxWithDirtyRetry(cmd, ctx, c, opts)
+	sb, err := createSandboxWithRetry(cmd, ctx, c, opts)
 	if err != nil {
 		return err
 	}
@@ -437,28 +554,74 @@ func executeNewCreate(cmd *cobra.Command, ctx context.Context, c *yoloai.Client,
 	})
 }
 
-// createSandboxWithDirtyRetry provisions the sandbox, handling the
-// dirty-workdir refusal uniformly for `new` and `run`: on *DirtyWorkdirError it
-// prints the warning, then proceeds only when --allow-dirty was given (re-issuing
-// the create with AllowDirtyWorkdir set); otherwise it returns the refusal. It
-// never prompts — widening the destructive scope to include a dirty workdir is
-// opt-in via --allow-dirty alone.
-func createSandboxWithDirtyRetry(cmd *cobra.Command, ctx context.Context, c *yoloai.Client, opts yoloai.SandboxCreateOptions) (*yoloai.Sandbox, error) {
-	sb, err := c.CreateSandbox(ctx, opts)
-	if dirty, isDirty := errors.AsType[*yoloai.DirtyWorkdirError](err); isDirty {
-		printDirtyWarning(cmd, dirty)
-		allowDirty, _ := cmd.Flags().GetBool("allow-dirty")
-		if !allowDirty {
-			fmt.Fprintln(cmd.ErrOrStderr(), "Re-run with --allow-dirty to proceed.") //nolint:errcheck // best-effort output
-			return nil, dirty
+// createSandboxWithRetry provisions the sandbox, handling the refusals `new`
+// and `run` can recover from:
+//
+//   - *DirtyWorkdirError: it prints the warning, then proceeds only when
+//     --allow-dirty was given (re-issuing the create with AllowDirtyWorkdir
+//     set); otherwise it returns the refusal. It never prompts — widening the
+//     destructive scope to include a dirty workdir is opt-in via --allow-dirty
+//     alone.
+//   - *SandboxQuotaError: it offers to destroy the workdir's oldest sandbox
+//     and create again (see makeRoomForSandbox).
+func createSandboxWithRetry(cmd *cobra.Command, ctx context.Context, c *yoloai.Client, opts yoloai.SandboxCreateOptions) (*yoloai.Sandbox, error) {
+	for {
+		sb, err := c.CreateSandbox(ctx, opts)
+		if dirty, isDirty := errors.AsType[*yoloai.DirtyWorkdirError](err); isDirty && !opts.AllowDirtyWorkdir {
+			printDirtyWarning(cmd, dirty)
+			allowDirty, _ := cmd.Flags().GetBool("allow-dirty")
+			if !allowDirty {
+				fmt.Fprintln(cmd.ErrOrStderr(), "Re-run with --allow-dirty to proceed.") //nolint:errcheck // best-effort output
+				return nil, dirty
+			}
+			opts.AllowDirtyWorkdir = true
+			continue
 		}
-		opts.AllowDirtyWorkdir = true
-		sb, err = c.CreateSandbox(ctx, opts)
+		if quota, full := errors.AsType[*yoloai.SandboxQuotaError](err); full {
+			made, roomErr := makeRoomForSandbox(cmd, ctx, c, quota)
+			if roomErr != nil {
+				return nil, roomErr
+			}
+			if !made {
+				return nil, quota
+			}
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		return sb, nil
+	}
+}
+
+// makeRoomForSandbox asks whether to destroy the oldest of the workdir's
+// sandboxes and, if so, destroys it, reporting whether it did. The destroy
+// never abandons unapplied work: a sandbox still holding some refuses, and that
+// refusal is returned. With --json, or when nobody answers, nothing is
+// destroyed.
+func makeRoomForSandbox(cmd *cobra.Command, ctx context.Context, c *yoloai.Client, quota *yoloai.SandboxQuotaError) (bool, error) {
+	if cliutil.JSONEnabled(cmd) || len(quota.Sandboxes) == 0 {
+		return false, nil
 	}
+	out := cmd.ErrOrStderr()
+	oldest := quota.Sandboxes[0]
+	question := fmt.Sprintf("%s already has %d sandboxes (max_sandboxes_per_workdir is %d). Destroy the oldest, %s?",
+		quota.Workdir, len(quota.Sandboxes), quota.Limit, oldest)
+	confirmed, err := cliutil.Confirm(ctx, question, os.Stdin, out)
 	if err != nil {
-		return nil, err
+		return false, err
+	}
+	if !confirmed {
+		fmt.Fprintln(out, "Destroy one with 'yoloai destroy <name>', or raise max_sandboxes_per_workdir.") //nolint:errcheck // best-effort output
+		return false, nil
+	}
+	slog.Info("destroying sandbox", "event", "sandbox.destroy", "sandbox", oldest)
+	if _, err := destroyOne(cmd, ctx, c, oldest, false); err != nil {
+		return false, fmt.Errorf("destroy %s: %w", oldest, err)
 	}
-	return sb, nil
+	slog.Info("sandbox destroyed", "event", "sandbox.destroy.complete", "sandbox", oldest)
+	fmt.Fprintf(out, "Destroyed %s\n", oldest) //nolint:errcheck // best-effort output
+	return true, nil
 }
 
 // loadCreatedMeta reads a just-created sandbox's metadata through the in-scope
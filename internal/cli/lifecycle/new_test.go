@@ -4,11 +4,15 @@
 package lifecycle
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	yoloai "github.com/kstenerud/yoloai"
+	"github.com/kstenerud/yoloai/internal/config"
 	"github.com/kstenerud/yoloai/yoerrors"
 )
 
@@ -34,6 +38,7 @@ func TestParseNewCmdPositional_Errors(t *testing.T) {
 		name      string
 		args      []string
 		profile   string
+		repo      string
 		wantErr   string // "" means no error
 		wantName  string
 		wantWdArg string
@@ -43,6 +48,7 @@ func TestParseNewCmdPositional_Errors(t *testing.T) {
 		{name: "too many positionals", args: []string{"box", "wd", "extra"}, wantErr: "too many positional arguments"},
 		{name: "name + workdir ok", args: []string{"box", "."}, wantName: "box", wantWdArg: "."},
 		{name: "name only with profile ok", args: []string{"box"}, profile: "myprof", wantName: "box"},
+		{name: "name only with repo ok", args: []string{"box"}, repo: "https://example.com/org/repo", wantName: "box"},
 	}
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
@@ -51,6 +57,9 @@ func TestParseNewCmdPositional_Errors(t *testing.T) {
 			if tc.profile != "" {
 				require.NoError(t, cmd.Flags().Set("profile", tc.profile))
 			}
+			if tc.repo != "" {
+				require.NoError(t, cmd.Flags().Set("repo", tc.repo))
+			}
 			name, wdArg, _, _, err := parseNewCmdPositional(cmd, tc.args)
 			if tc.wantErr != "" {
 				assertUsageError(t, err, tc.wantErr)
@@ -72,6 +81,14 @@ func TestResolveCreateOptions_FlagConflicts(t *testing.T) {
 		_, err := resolveCreateOptions(cmd, "box", ".", nil, "")
 		assertUsageError(t, err, "--port is incompatible with --network-none")
 	})
+
+	t.Run("repo + workdir argument incompatible", func(t *testing.T) {
+		cmd := NewNewCmd("test")
+		require.NoError(t, cmd.Flags().Set("repo", "https://example.com/org/repo"))
+
+		_, err := resolveCreateOptions(cmd, "box", ".", nil, "")
+		assertUsageError(t, err, "--repo clones the workdir")
+	})
 }
 
 // TestResolveCreateOptions_RejectsInvalidNameUpFront pins the fast-feedback path:
@@ -139,3 +156,21 @@ func TestParseEnvSlice(t *testing.T) {
 		assert.Equal(t, map[string]string{"A": "1", "B": "two", "C": ""}, m)
 	})
 }
+
+func TestParseRoleFlags(t *testing.T) {
+	layout := config.NewLayout(t.TempDir())
+	promptFile := filepath.Join(t.TempDir(), "tester.md")
+	require.NoError(t, os.WriteFile(promptFile, []byte("Write the tests.\n"), 0600))
+
+	roles, err := parseRoleFlags([]string{"implementer=Build the parser", "tester=@" + promptFile}, layout)
+	require.NoError(t, err)
+	assert.Equal(t, []yoloai.SandboxRole{
+		{Name: "implementer", Prompt: "Build the parser"},
+		{Name: "tester", Prompt: "Write the tests."},
+	}, roles)
+
+	_, err = parseRoleFlags([]string{"implementer"}, layout)
+	assertUsageError(t, err, "must be NAME=PROMPT")
+	_, err = parseRoleFlags([]string{"tester=@" + filepath.Join(t.TempDir(), "missing.md")}, layout)
+	assertUsageError(t, err, "read prompt file")
+}
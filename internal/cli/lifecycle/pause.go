@@ -0,0 +1,82 @@
+// ABOUTME: `yoloai pause <name>` / `yoloai unpause <name>` — freeze a running
+// ABOUTME: sandbox in place and thaw it, keeping the agent's in-memory state.
+package lifecycle
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/kstenerud/yoloai/internal/cli/cliutil"
+
+	yoloai "github.com/kstenerud/yoloai"
+	"github.com/spf13/cobra"
+)
+
+func NewPauseCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "pause <name>",
+		Short: "Freeze a running sandbox without stopping it",
+		Long: `Freeze every process in a running sandbox without stopping it.
+
+The agent stops using CPU immediately but keeps its memory: its conversation,
+child processes and open files are all still there, and 'yoloai unpause'
+carries on from exactly where it was. Use it to quiet a noisy sandbox for a
+while; use 'yoloai stop' to free its memory too.
+
+While paused, 'yoloai list' and 'yoloai sandbox <name> info' report the
+sandbox as paused, and attach and start are refused. Stopping a paused sandbox
+thaws it first so the agent shuts down cleanly.
+
+Supported on docker, podman, containerd and seatbelt. Tart and Apple
+containers have no in-memory freeze.`,
+		GroupID: cliutil.GroupLifecycle,
+		Args:    cobra.ArbitraryArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runPauseCmd(cmd, args, true)
+		},
+	}
+}
+
+func NewUnpauseCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:     "unpause <name>",
+		Short:   "Resume a sandbox frozen by 'yoloai pause'",
+		GroupID: cliutil.GroupLifecycle,
+		Args:    cobra.ArbitraryArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runPauseCmd(cmd, args, false)
+		},
+	}
+}
+
+func runPauseCmd(cmd *cobra.Command, args []string, pause bool) error {
+	name, _, err := cliutil.ResolveName(cmd, args)
+	if err != nil {
+		return err
+	}
+	defer cliutil.OpenCLIJSONLSink(name, cmd)()
+
+	return cliutil.WithSandbox(cmd, name, func(ctx context.Context, sb *yoloai.Sandbox) error {
+		action, verb := "paused", "Paused"
+		if pause {
+			err = sb.Pause(ctx)
+		} else {
+			action, verb = "unpaused", "Unpaused"
+			err = sb.Unpause(ctx)
+		}
+		if err != nil {
+			return err
+		}
+		slog.Info("sandbox "+action, "event", "sandbox."+action, "sandbox", name)
+
+		if cliutil.JSONEnabled(cmd) {
+			return cliutil.WriteJSON(cmd.OutOrStdout(), map[string]string{
+				"name":   name,
+				"action": action,
+			})
+		}
+		_, err = fmt.Fprintf(cmd.OutOrStdout(), "%s %s\n", verb, name)
+		return err
+	})
+}
@@ -0,0 +1,138 @@
+// ABOUTME: `yoloai port add|rm <name> ...` — forward host ports into an existing
+// ABOUTME: sandbox, or stop forwarding them, by recreating its container.
+package lifecycle
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+
+	"github.com/kstenerud/yoloai/internal/cli/cliutil"
+	"github.com/kstenerud/yoloai/yoerrors"
+
+	yoloai "github.com/kstenerud/yoloai"
+	"github.com/spf13/cobra"
+)
+
+func NewPortCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "port",
+		Short: "Add or remove port forwards on an existing sandbox",
+		Long: `Add or remove port forwards on an existing sandbox.
+
+'yoloai new --port' fixes a sandbox's forwards when it is created. 'port add'
+and 'port rm' change them afterwards: the change is saved with the sandbox,
+and a running sandbox is restarted so its container is recreated with the new
+mappings. The work copy, the agent's state and its conversation carry over,
+the same as 'yoloai restart'. With --no-restart, or for a stopped sandbox, the
+change takes effect the next time it starts.
+
+A host port can forward to only one sandbox port; remove the old mapping
+before pointing it somewhere else. A sandbox created with --network-none
+can't forward ports.`,
+		GroupID: cliutil.GroupLifecycle,
+	}
+	cmd.AddCommand(newPortAddCmd(), newPortRmCmd())
+	return cmd
+}
+
+func newPortAddCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "add <name> <host:container>...",
+		Short:   "Forward host ports into the sandbox",
+		Example: cliutil.CommandExamples("port add"),
+		Args:    cobra.MinimumNArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			add, err := parsePortFlags(args[1:])
+			if err != nil {
+				return err
+			}
+			return runPortCmd(cmd, args[0], yoloai.SandboxPortsOptions{Add: add})
+		},
+	}
+	cmd.Flags().Bool("no-restart", false, "Save the change without restarting a running sandbox")
+	return cmd
+}
+
+func newPortRmCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "rm <name> <host-port|host:container>...",
+		Short:   "Stop forwarding host ports into the sandbox",
+		Example: cliutil.CommandExamples("port rm"),
+		Args:    cobra.MinimumNArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			remove, err := parseHostPorts(args[1:])
+			if err != nil {
+				return err
+			}
+			return runPortCmd(cmd, args[0], yoloai.SandboxPortsOptions{Remove: remove})
+		},
+	}
+	cmd.Flags().Bool("no-restart", false, "Save the change without restarting a running sandbox")
+	return cmd
+}
+
+func runPortCmd(cmd *cobra.Command, name string, opts yoloai.SandboxPortsOptions) error {
+	if err := cliutil.ValidateName(name); err != nil {
+		return err
+	}
+	defer cliutil.OpenCLIJSONLSink(name, cmd)()
+	opts.NoRestart, _ = cmd.Flags().GetBool("no-restart")
+
+	return cliutil.WithSandbox(cmd, name, func(ctx context.Context, sb *yoloai.Sandbox) error {
+		res, err := sb.UpdatePorts(ctx, opts)
+		if res != nil {
+			cliutil.RenderNotices(cmd, res.Notices)
+		}
+		if err != nil {
+			return err
+		}
+		slog.Info("sandbox ports updated", "event", "sandbox.ports", "sandbox", name, "added", res.Added, "removed", res.Removed, "restarted", res.Restarted)
+
+		if cliutil.JSONEnabled(cmd) {
+			return cliutil.WriteJSON(cmd.OutOrStdout(), map[string]any{
+				"name":      name,
+				"ports":     nonNilStrings(res.Ports),
+				"added":     nonNilStrings(res.Added),
+				"removed":   nonNilStrings(res.Removed),
+				"restarted": res.Restarted,
+			})
+		}
+		out := cmd.OutOrStdout()
+		if len(res.Added) == 0 && len(res.Removed) == 0 {
+			_, err = fmt.Fprintf(out, "Ports of %s unchanged\n", name)
+			return err
+		}
+		ports := "none"
+		if len(res.Ports) > 0 {
+			ports = strings.Join(res.Ports, ", ")
+		}
+		_, err = fmt.Fprintf(out, "Ports of %s: %s\n", name, ports)
+		return err
+	})
+}
+
+// parseHostPorts parses the arguments of 'port rm': a host port, or a whole
+// "host:container" mapping, of which only the host side is used.
+func parseHostPorts(args []string) ([]int, error) {
+	ports := make([]int, 0, len(args))
+	for _, a := range args {
+		host, _, _ := strings.Cut(a, ":")
+		port, err := strconv.Atoi(host)
+		if err != nil || port < 1 || port > 65535 {
+			return nil, yoerrors.NewUsageError("invalid host port %q", a)
+		}
+		ports = append(ports, port)
+	}
+	return ports, nil
+}
+
+// nonNilStrings keeps a JSON list field an array when it's empty.
+func nonNilStrings(s []string) []string {
+	if s == nil {
+		return []string{}
+	}
+	return s
+}
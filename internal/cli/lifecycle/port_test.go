@@ -0,0 +1,21 @@
+// ABOUTME: Tests for `yoloai port`: parsing the host ports 'port rm' takes,
+// ABOUTME: either bare or as the host side of a mapping.
+package lifecycle
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseHostPorts(t *testing.T) {
+	ports, err := parseHostPorts([]string{"3000", "8080:80"})
+	require.NoError(t, err)
+	assert.Equal(t, []int{3000, 8080}, ports, "a mapping's host side is used")
+
+	_, err = parseHostPorts([]string{"web"})
+	assertUsageError(t, err, "invalid host port")
+	_, err = parseHostPorts([]string{"0"})
+	assertUsageError(t, err, "invalid host port")
+}
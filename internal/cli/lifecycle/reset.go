@@ -24,6 +24,9 @@ type resetOpts struct {
 	attach           bool
 	debug            bool
 	env              []string
+	paths            []string
+	to               string
+	dirs             []string
 }
 
 func NewResetCmd() *cobra.Command {
@@ -31,6 +34,7 @@ func NewResetCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:     "reset <name>",
 		Short:   "Re-copy tracked dirs into sandbox and reset diff baseline",
+		Example: cliutil.CommandExamples("reset"),
 		GroupID: cliutil.GroupLifecycle,
 		Args:    cobra.ArbitraryArgs,
 		RunE:    func(cmd *cobra.Command, args []string) error { return runReset(cmd, args, opts) },
@@ -44,6 +48,18 @@ func NewResetCmd() *cobra.Command {
 	cmd.Flags().BoolVar(&opts.keepFiles, "keep-files", false, "Preserve files directory")
 	cmd.Flags().BoolVarP(&opts.attach, "attach", "a", false, "Auto-attach after restart (implies --restart)")
 	cmd.Flags().StringArrayVar(&opts.env, "env", nil, "Per-sandbox env var KEY=VAL applied on --restart (not persisted)")
+	cmd.Flags().StringSliceVar(&opts.paths, "paths", nil, "Restore only these workdir paths to their original contents, keeping the rest of the work (comma-separated or repeated)")
+	cmd.Flags().StringVar(&opts.to, "to", "", "Rewind the work copy to one of the agent's commits, discarding the commits after it")
+	for _, f := range []string{"restart", "clear-state", "attach", "keep-cache", "keep-files", "no-prompt", "env"} {
+		cmd.MarkFlagsMutuallyExclusive("paths", f)
+		cmd.MarkFlagsMutuallyExclusive("to", f)
+	}
+	cmd.MarkFlagsMutuallyExclusive("paths", "to")
+	cmd.Flags().StringArrayVar(&opts.dirs, "dir", nil, "Reset only this tracked :copy dir — host path, mount path, or basename (repeatable)")
+	cmd.MarkFlagsMutuallyExclusive("dir", "paths")
+	cmd.MarkFlagsMutuallyExclusive("dir", "to")
+	cmd.MarkFlagsMutuallyExclusive("dir", "keep-cache")
+	cmd.MarkFlagsMutuallyExclusive("dir", "keep-files")
 
 	return cmd
 }
@@ -74,6 +90,17 @@ func runReset(cmd *cobra.Command, args []string, opts *resetOpts) error {
 	if err != nil {
 		return err
 	}
+	dirs, err := resolveResetDirs(cmd, name, opts.dirs)
+	if err != nil {
+		return err
+	}
+
+	if len(opts.paths) > 0 {
+		return runResetPaths(cmd, name, opts.paths)
+	}
+	if opts.to != "" {
+		return runResetTo(cmd, name, opts.to, opts.abandonUnapplied)
+	}
 
 	return cliutil.WithSandbox(cmd, name, func(ctx context.Context, sb *yoloai.Sandbox) error {
 		slog.Info("resetting sandbox", "event", "sandbox.reset", "sandbox", name, "restart", opts.restart, "clear_state", opts.clearState)
@@ -85,6 +112,7 @@ func runReset(cmd *cobra.Command, args []string, opts *resetOpts) error {
 			NoPrompt:         opts.noPrompt,
 			Debug:            opts.debug,
 			Env:              envMap,
+			Dirs:             dirs,
 			// Reset overwrites every tracked work copy from the host, so it
 			// destroys unapplied work exactly as destroy does — and authorizes it
 			// the same way. Like destroy, there is no prompt to widen the scope
@@ -100,10 +128,14 @@ func runReset(cmd *cobra.Command, args []string, opts *resetOpts) error {
 		slog.Info("sandbox reset complete", "event", "sandbox.reset.complete", "sandbox", name)
 
 		if cliutil.JSONEnabled(cmd) {
-			return cliutil.WriteJSON(cmd.OutOrStdout(), map[string]string{
+			result := map[string]any{
 				"name":   name,
 				"action": "reset",
-			})
+			}
+			if len(dirs) > 0 {
+				result["dirs"] = dirs
+			}
+			return cliutil.WriteJSON(cmd.OutOrStdout(), result)
 		}
 
 		if opts.attach {
@@ -112,11 +144,98 @@ func runReset(cmd *cobra.Command, args []string, opts *resetOpts) error {
 			})
 		}
 
+		what := "Sandbox " + name
+		if len(dirs) > 0 {
+			what = fmt.Sprintf("%d dir(s) in %s", len(dirs), name)
+		}
 		if opts.restart {
-			_, err = fmt.Fprintf(cmd.OutOrStdout(), "Sandbox %s reset\nRun 'yoloai attach %s' to reconnect\n", name, name)
+			_, err = fmt.Fprintf(cmd.OutOrStdout(), "%s reset\nRun 'yoloai attach %s' to reconnect\n", what, name)
 			return err
 		}
-		_, err = fmt.Fprintf(cmd.OutOrStdout(), "Sandbox %s reset\n", name)
+		_, err = fmt.Fprintf(cmd.OutOrStdout(), "%s reset\n", what)
+		return err
+	})
+}
+
+// resolveResetDirs maps each --dir specifier to the host path of the tracked
+// directory it names, the same way diff and apply pick a directory.
+func resolveResetDirs(cmd *cobra.Command, name string, specs []string) ([]string, error) {
+	if len(specs) == 0 {
+		return nil, nil
+	}
+	env, err := cliutil.SandboxMetadata(cmd, name)
+	if err != nil {
+		return nil, err
+	}
+	dirs := make([]string, 0, len(specs))
+	for _, spec := range specs {
+		dir, err := cliutil.ResolveDirSpecifier(env, spec)
+		if err != nil {
+			return nil, yoerrors.NewUsageError("--dir: %v", err)
+		}
+		dirs = append(dirs, dir.HostPath)
+	}
+	return dirs, nil
+}
+
+// runResetPaths restores the named paths in a running sandbox, leaving the rest
+// of its work and the agent's session alone. Naming the paths is the consent,
+// so no --abandon-unapplied is needed.
+func runResetPaths(cmd *cobra.Command, name string, paths []string) error {
+	return cliutil.WithSandbox(cmd, name, func(ctx context.Context, sb *yoloai.Sandbox) error {
+		slog.Info("restoring sandbox paths", "event", "sandbox.reset.paths", "sandbox", name, "paths", paths)
+		if _, err := sb.Reset(ctx, yoloai.SandboxResetOptions{Paths: paths}); err != nil {
+			return cliutil.SandboxErrorHint(name, err)
+		}
+
+		if cliutil.JSONEnabled(cmd) {
+			return cliutil.WriteJSON(cmd.OutOrStdout(), map[string]any{
+				"name":   name,
+				"action": "reset",
+				"paths":  paths,
+			})
+		}
+		_, err := fmt.Fprintf(cmd.OutOrStdout(), "Restored %d path(s) in %s to their original contents\n", len(paths), name)
+		return err
+	})
+}
+
+// droppedCommit is the JSON form of a commit a reset --to discarded.
+type droppedCommit struct {
+	SHA     string `json:"sha"`
+	Subject string `json:"subject"`
+}
+
+// runResetTo rewinds a running sandbox's work copy to one of the agent's
+// commits. The commits after it are named by the target; uncommitted edits
+// aren't, so those still need --abandon-unapplied.
+func runResetTo(cmd *cobra.Command, name, ref string, abandonUnapplied bool) error {
+	return cliutil.WithSandbox(cmd, name, func(ctx context.Context, sb *yoloai.Sandbox) error {
+		slog.Info("rewinding sandbox", "event", "sandbox.reset.to", "sandbox", name, "ref", ref)
+		res, err := sb.Reset(ctx, yoloai.SandboxResetOptions{To: ref, AbandonUnappliedWork: abandonUnapplied})
+		if err != nil {
+			return cliutil.SandboxErrorHint(name, err)
+		}
+		rw := res.Rewind
+
+		if cliutil.JSONEnabled(cmd) {
+			dropped := make([]droppedCommit, len(rw.Dropped))
+			for i, c := range rw.Dropped {
+				dropped[i] = droppedCommit{SHA: c.SHA, Subject: c.Subject}
+			}
+			return cliutil.WriteJSON(cmd.OutOrStdout(), map[string]any{
+				"name":    name,
+				"action":  "reset",
+				"to":      rw.SHA,
+				"dropped": dropped,
+			})
+		}
+
+		out := cmd.OutOrStdout()
+		for _, c := range rw.Dropped {
+			fmt.Fprintf(out, "  dropped %.12s %s\n", c.SHA, c.Subject) //nolint:errcheck
+		}
+		_, err = fmt.Fprintf(out, "Sandbox %s reset to %.12s %s (%d commit(s) dropped)\n", name, rw.SHA, rw.Subject, len(rw.Dropped))
 		return err
 	})
 }
@@ -32,8 +32,9 @@ func rollbackFailedStart(ctx context.Context, sb *yoloai.Sandbox) {
 
 func NewRunCmd(version string) *cobra.Command {
 	cmd := &cobra.Command{
-		Use:   "run [flags] <name> <workdir> -p <prompt> [-d <dir>...] [-- <agent-args>...]",
-		Short: "Run an agent headlessly to completion",
+		Use:     "run [flags] <name> <workdir> -p <prompt> [-d <dir>...] [-- <agent-args>...]",
+		Short:   "Run an agent headlessly to completion",
+		Example: cliutil.CommandExamples("run"),
 		Long: "Create a sandbox and run the agent in its own headless mode (e.g. claude -p): " +
 			"the prompt is baked into the launch command and the task ends when the agent exits. " +
 			"A prompt and workdir are required. By default run returns once the agent is launched — " +
@@ -68,7 +69,7 @@ func runRunCmd(cmd *cobra.Command, args []string, version string) error {
 	// A workdir-less run (the agent just makes API calls) is the intended end
 	// state, but it needs the no-Dirs[0]-workdir pipeline work tracked in DF49.
 	// Until then, require a workdir like `new` does.
-	if rawWorkdirArg == "" {
+	if rawWorkdirArg == "" && cliutil.FlagStr(cmd, "repo") == "" {
 		return yoerrors.NewUsageError("workdir is required\n\nUsage: yoloai run [flags] <name> <workdir> --prompt <text>\n\nExample: yoloai run %s . --prompt \"fix the bug\"", name)
 	}
 
@@ -140,7 +141,7 @@ func parseRunCmdPositional(cmd *cobra.Command, args []string) (name, rawWorkdirA
 // inspect/diff/apply). The exit code reflects the agent: a failed agent returns a
 // non-nil error so the process exits non-zero.
 func executeRun(cmd *cobra.Command, ctx context.Context, c *yoloai.Client, opts yoloai.SandboxCreateOptions, wait, rm bool) error {
-	sb, err := createSandboxWithDirtyRetry(cmd, ctx, c, opts)
+	sb, err := createSandboxWithRetry(cmd, ctx, c, opts)
 	if err != nil {
 		return err
 	}
@@ -182,6 +183,20 @@ func executeRun(cmd *cobra.Command, ctx context.Context, c *yoloai.Client, opts
 	return waitForRunResult(cmd, ctx, sb, headless, rm)
 }
 
+// writeRunResult puts the agent's result.json on stdout — the one thing a
+// one-shot run prints there — so a script can pipe `yoloai run --wait` into a
+// JSON tool. A result.json the agent got wrong is reported on stderr.
+func writeRunResult(cmd *cobra.Command, info *yoloai.SandboxInfo) error {
+	if info.ResultError != "" {
+		fmt.Fprintf(cmd.ErrOrStderr(), "Warning: agent result ignored: %s\n", info.ResultError) //nolint:errcheck // best-effort output
+		return nil
+	}
+	if info.Result == nil {
+		return nil
+	}
+	return cliutil.WriteJSON(cmd.OutOrStdout(), info.Result)
+}
+
 // waitForRunResult blocks until the agent completes, optionally destroys the
 // sandbox (--rm), reports the outcome, and maps a failed agent to a non-zero
 // exit. A headless agent exits when done (WaitForExit); an interactive one
@@ -209,8 +224,13 @@ func waitForRunResult(cmd *cobra.Command, ctx context.Context, sb *yoloai.Sandbo
 		if err := cliutil.WriteJSON(cmd.OutOrStdout(), info); err != nil {
 			return err
 		}
-	} else if info.Status != yoloai.StatusFailed {
-		fmt.Fprintf(cmd.ErrOrStderr(), "Agent finished in sandbox %s (%s).\n", sb.Name(), info.Status) //nolint:errcheck // best-effort output
+	} else {
+		if info.Status != yoloai.StatusFailed {
+			fmt.Fprintf(cmd.ErrOrStderr(), "Agent finished in sandbox %s (%s).\n", sb.Name(), info.Status) //nolint:errcheck // best-effort output
+		}
+		if err := writeRunResult(cmd, info); err != nil {
+			return err
+		}
 	}
 
 	// The exit code reflects the agent: a failed agent makes `run` exit non-zero
@@ -0,0 +1,116 @@
+// ABOUTME: `yoloai scrub` — applies the retention policy (retention_days) to the
+// ABOUTME: prompts, logs and transcripts left in the trash, on demand.
+package lifecycle
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/kstenerud/yoloai/internal/cli/cliutil"
+
+	yoloai "github.com/kstenerud/yoloai"
+	"github.com/kstenerud/yoloai/yoerrors"
+	"github.com/spf13/cobra"
+)
+
+func NewScrubCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "scrub",
+		Short: "Remove old prompts, logs and transcripts from the trash",
+		Long: `Remove the prompts, logs and agent transcripts of sandboxes in the trash
+once they are older than the retention period.
+
+Destroying a sandbox removes everything it had. What survives is in the
+trash: sandboxes that 'system prune' quarantined and data a migration moved
+aside. Scrubbing removes their prompt.txt, logs/ (the agent's terminal output
+included) and agent-runtime/ (the agent's sessions), and leaves the work
+copies and metadata so any code in them can still be recovered.
+
+The retention period is the retention_days config key, and 'yoloai gc' applies
+it on every run. scrub applies it on demand; --days overrides it, and
+--days 0 scrubs the whole trash now.`,
+		Example: `  yoloai config set retention_days 30
+  yoloai scrub
+  yoloai scrub --days 0 --dry-run`,
+		GroupID: cliutil.GroupLifecycle,
+		Args:    cobra.NoArgs,
+		RunE:    runScrubCmd,
+	}
+
+	cmd.Flags().Int("days", 0, "Scrub trash entries older than this many days (default: retention_days)")
+	cmd.Flags().Bool("dry-run", false, "Report what would be removed without removing it")
+
+	return cmd
+}
+
+// scrubEntryJSON is the --json shape of one scrubbed trash entry.
+type scrubEntryJSON struct {
+	Path      string    `json:"path"`
+	TrashedAt time.Time `json:"trashed_at"`
+	Removed   []string  `json:"removed"`
+}
+
+func runScrubCmd(cmd *cobra.Command, _ []string) error {
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	var days *int
+	if cmd.Flags().Changed("days") {
+		n, _ := cmd.Flags().GetInt("days")
+		if n < 0 {
+			return yoerrors.NewUsageError("--days must not be negative: %d", n)
+		}
+		days = &n
+	}
+
+	result, err := scrubTrash(days, dryRun)
+	if result == nil {
+		return err
+	}
+	if cliutil.JSONEnabled(cmd) {
+		if jsonErr := cliutil.WriteJSON(cmd.OutOrStdout(), map[string]any{
+			"retention_days": result.RetentionDays,
+			"entries":        scrubEntriesJSON(result),
+		}); jsonErr != nil {
+			return jsonErr
+		}
+		return err
+	}
+	if result.RetentionDays < 0 {
+		fmt.Fprintln(cmd.OutOrStdout(), "No retention configured: set retention_days, or pass --days") //nolint:errcheck // best-effort output
+		return err
+	}
+	printScrubResult(cmd, result, dryRun)
+	return err
+}
+
+// scrubTrash runs System.Scrub; days nil uses the retention_days config key.
+func scrubTrash(days *int, dryRun bool) (*yoloai.ScrubResult, error) {
+	sys, err := cliutil.System()
+	if err != nil {
+		return nil, err
+	}
+	return sys.Scrub(yoloai.SystemScrubOptions{RetentionDays: days, DryRun: dryRun})
+}
+
+func scrubEntriesJSON(result *yoloai.ScrubResult) []scrubEntryJSON {
+	out := []scrubEntryJSON{}
+	for _, e := range result.Entries {
+		out = append(out, scrubEntryJSON{Path: e.Path, TrashedAt: e.TrashedAt, Removed: e.Removed})
+	}
+	return out
+}
+
+// printScrubResult reports each scrubbed trash entry (human mode).
+func printScrubResult(cmd *cobra.Command, result *yoloai.ScrubResult, dryRun bool) {
+	out := cmd.OutOrStdout()
+	if len(result.Entries) == 0 {
+		fmt.Fprintln(out, "Nothing to scrub") //nolint:errcheck // best-effort output
+		return
+	}
+	verb := "Scrubbed"
+	if dryRun {
+		verb = "Would scrub"
+	}
+	for _, e := range result.Entries {
+		fmt.Fprintf(out, "%s %s (trashed %s ago): %d path(s)\n", verb, e.Path, cliutil.FormatAge(e.TrashedAt), len(e.Removed)) //nolint:errcheck // best-effort output
+	}
+}
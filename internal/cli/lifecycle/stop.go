@@ -86,7 +86,7 @@ func resolveStopAll(cmd *cobra.Command, ctx context.Context, c *yoloai.Client) (
 	var names []string
 	for _, info := range infos {
 		switch info.Status {
-		case yoloai.StatusActive, yoloai.StatusIdle, yoloai.StatusDone, yoloai.StatusFailed:
+		case yoloai.StatusActive, yoloai.StatusIdle, yoloai.StatusDone, yoloai.StatusFailed, yoloai.StatusPaused:
 			names = append(names, info.Environment.Name)
 		default:
 			// StatusStopped, StatusRemoved, StatusBroken, StatusUnavailable: skip
@@ -0,0 +1,168 @@
+// ABOUTME: Cobra "up" command: brings back every sandbox whose container is
+// ABOUTME: stopped or gone (e.g. after a host reboot) in one pass, across backends.
+package lifecycle
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"sort"
+
+	"github.com/kstenerud/yoloai/internal/cli/cliutil"
+
+	yoloai "github.com/kstenerud/yoloai"
+	"github.com/spf13/cobra"
+)
+
+func NewUpCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "up [name]...",
+		Short: "Start every stopped sandbox (e.g. after a host reboot)",
+		Long: `Start every sandbox whose container is stopped or gone.
+
+After a host reboot or sleep, sandboxes survive on disk but their containers
+may be stopped or removed. 'up' finds them all, on every backend, and starts
+each one, recreating its container when needed. Work copies and agent state
+are untouched.
+
+Sandboxes that are running, or whose agent already finished (done/failed),
+are left alone. Name sandboxes to limit 'up' to them. One sandbox failing to
+start does not stop the others; failures are listed at the end.`,
+		GroupID: cliutil.GroupLifecycle,
+		Args:    cobra.ArbitraryArgs,
+		RunE:    runUpCmd,
+	}
+
+	cmd.Flags().Bool("resume", false, "Re-feed each sandbox's original prompt with a continuation preamble")
+
+	return cmd
+}
+
+// upResult is one sandbox's outcome, shared by the human and JSON renderings.
+type upResult struct {
+	Name   string `json:"name"`
+	Action string `json:"action,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+func runUpCmd(cmd *cobra.Command, args []string) error {
+	for _, name := range args {
+		if err := cliutil.ValidateName(name); err != nil {
+			return err
+		}
+	}
+	resume, _ := cmd.Flags().GetBool("resume")
+
+	sys, err := cliutil.System()
+	if err != nil {
+		return err
+	}
+	infos, unavailable, err := sys.AllSandboxesComplete(cmd.Context())
+	if err != nil {
+		return err
+	}
+	for _, b := range unavailable {
+		fmt.Fprintf(os.Stderr, "Warning: backend %s is unavailable; its sandboxes were skipped\n", b)
+	}
+
+	byBackend := upTargets(infos, args)
+	var results []upResult
+	for _, backend := range sortedBackends(byBackend) {
+		names := byBackend[backend]
+		err := cliutil.WithClient(cmd, backend, func(ctx context.Context, c *yoloai.Client) error {
+			for _, name := range names {
+				results = append(results, startForUp(ctx, cmd, c, name, resume))
+			}
+			return nil
+		})
+		if err != nil {
+			for _, name := range names {
+				results = append(results, upResult{Name: name, Error: err.Error()})
+			}
+		}
+	}
+
+	return reportUp(cmd, results)
+}
+
+// upTargets selects the sandboxes 'up' should start, grouped by backend: those
+// stopped or whose container is gone. Running and finished sandboxes are
+// skipped, as are broken and unreachable ones (start cannot help them). When
+// only is non-empty, selection is limited to those names.
+func upTargets(infos []*yoloai.SandboxInfo, only []string) map[yoloai.BackendType][]string {
+	want := make(map[string]bool, len(only))
+	for _, name := range only {
+		want[name] = true
+	}
+	out := map[yoloai.BackendType][]string{}
+	for _, info := range infos {
+		if info.Environment == nil {
+			continue
+		}
+		name := info.Environment.Name
+		if len(want) > 0 && !want[name] {
+			continue
+		}
+		switch info.Status {
+		case yoloai.StatusStopped, yoloai.StatusRemoved:
+			out[info.Environment.BackendType] = append(out[info.Environment.BackendType], name)
+		default:
+			// Active, Idle, Done, Failed, Broken, Unavailable, Suspended: skip
+		}
+	}
+	return out
+}
+
+// sortedBackends returns the map's backends in a stable order.
+func sortedBackends(m map[yoloai.BackendType][]string) []yoloai.BackendType {
+	backends := make([]yoloai.BackendType, 0, len(m))
+	for b := range m {
+		backends = append(backends, b)
+	}
+	sort.Slice(backends, func(i, j int) bool { return backends[i] < backends[j] })
+	return backends
+}
+
+// startForUp starts one sandbox, recreating its container when it is gone.
+func startForUp(ctx context.Context, cmd *cobra.Command, c *yoloai.Client, name string, resume bool) upResult {
+	slog.Info("starting sandbox", "event", "sandbox.up", "sandbox", name)
+	sb, err := c.Sandbox(name)
+	if err != nil {
+		return upResult{Name: name, Error: err.Error()}
+	}
+	res, err := sb.Start(ctx, yoloai.SandboxStartOptions{Resume: resume})
+	if res != nil && !cliutil.JSONEnabled(cmd) {
+		cliutil.RenderNotices(cmd, res.Notices)
+	}
+	if err != nil {
+		return upResult{Name: name, Error: cliutil.SandboxErrorHint(name, err).Error()}
+	}
+	slog.Info("sandbox started", "event", "sandbox.up.complete", "sandbox", name)
+	return upResult{Name: name, Action: "started"}
+}
+
+// reportUp renders the results and returns an error if any sandbox failed.
+func reportUp(cmd *cobra.Command, results []upResult) error {
+	if cliutil.JSONEnabled(cmd) {
+		return cliutil.WriteJSONList(cmd.OutOrStdout(), "started", results)
+	}
+	out := cmd.OutOrStdout()
+	if len(results) == 0 {
+		_, err := fmt.Fprintln(out, "No stopped sandboxes to start")
+		return err
+	}
+	failed := 0
+	for _, r := range results {
+		if r.Error != "" {
+			failed++
+			fmt.Fprintf(os.Stderr, "Warning: start %s: %s\n", r.Name, r.Error)
+			continue
+		}
+		fmt.Fprintf(out, "Started %s\n", r.Name) //nolint:errcheck // best-effort output
+	}
+	if failed > 0 {
+		return fmt.Errorf("failed to start %d sandbox(es)", failed)
+	}
+	return nil
+}
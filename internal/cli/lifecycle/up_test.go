@@ -0,0 +1,40 @@
+// ABOUTME: Tests for the up command's target selection: which sandboxes it
+// ABOUTME: starts, how they group by backend, and name filtering.
+
+package lifecycle
+
+import (
+	"testing"
+
+	yoloai "github.com/kstenerud/yoloai"
+	"github.com/stretchr/testify/assert"
+)
+
+func upInfo(name string, backend yoloai.BackendType, status yoloai.Status) *yoloai.SandboxInfo {
+	return &yoloai.SandboxInfo{
+		Environment: &yoloai.Environment{Name: name, BackendType: backend},
+		Status:      status,
+	}
+}
+
+func TestUpTargets(t *testing.T) {
+	infos := []*yoloai.SandboxInfo{
+		upInfo("stopped", "docker", yoloai.StatusStopped),
+		upInfo("removed", "podman", yoloai.StatusRemoved),
+		upInfo("active", "docker", yoloai.StatusActive),
+		upInfo("done", "docker", yoloai.StatusDone),
+		upInfo("broken", "docker", yoloai.StatusBroken),
+		{Status: yoloai.StatusStopped}, // no environment: skipped, not a panic
+	}
+
+	got := upTargets(infos, nil)
+	assert.Equal(t, map[yoloai.BackendType][]string{
+		"docker": {"stopped"},
+		"podman": {"removed"},
+	}, got)
+	assert.Equal(t, []yoloai.BackendType{"docker", "podman"}, sortedBackends(got))
+
+	filtered := upTargets(infos, []string{"removed", "active"})
+	assert.Equal(t, map[yoloai.BackendType][]string{"podman": {"removed"}}, filtered,
+		"named sandboxes limit the selection; running ones stay skipped")
+}
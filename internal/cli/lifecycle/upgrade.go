@@ -0,0 +1,80 @@
+// ABOUTME: `yoloai upgrade <name>` — update the agent CLI inside a running
+// ABOUTME: sandbox and relaunch it, keeping its conversation and state.
+package lifecycle
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/kstenerud/yoloai/internal/cli/cliutil"
+
+	yoloai "github.com/kstenerud/yoloai"
+	"github.com/spf13/cobra"
+)
+
+func NewUpgradeCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "upgrade <name>",
+		Short: "Upgrade the agent CLI inside a running sandbox",
+		Long: `Upgrade the agent CLI inside a running sandbox.
+
+Reinstalls the agent's npm package in the container (the latest release, or
+--version), then relaunches the agent in its session. Agents with a native
+resume flag (e.g. claude --continue) pick up their conversation; the agent's
+state directory is kept either way. The old and new versions are recorded in
+the sandbox's agent.json.
+
+The install lives in the container. Stopping and starting the sandbox, or
+'reset --restart', recreates the container from the image and reverts to the
+image's version; run 'yoloai upgrade' again after that, or rebuild the image
+with 'yoloai system build' to upgrade every new sandbox.
+
+The sandbox needs to reach the npm registry. For a network-isolated sandbox,
+allow it first: 'yoloai sandbox <name> allow registry.npmjs.org'.`,
+		GroupID: cliutil.GroupLifecycle,
+		Args:    cobra.ArbitraryArgs,
+		RunE:    runUpgrade,
+	}
+
+	cmd.Flags().String("version", "", "Agent version or npm dist-tag to install (default latest)")
+
+	return cmd
+}
+
+func runUpgrade(cmd *cobra.Command, args []string) error {
+	name, _, err := cliutil.ResolveName(cmd, args)
+	if err != nil {
+		return err
+	}
+	defer cliutil.OpenCLIJSONLSink(name, cmd)()
+	version, _ := cmd.Flags().GetString("version")
+
+	return cliutil.WithSandbox(cmd, name, func(ctx context.Context, sb *yoloai.Sandbox) error {
+		slog.Info("upgrading agent", "event", "sandbox.upgrade", "sandbox", name)
+		res, err := sb.Agent().Upgrade(ctx, yoloai.AgentUpgradeOptions{Version: version})
+		if err != nil {
+			return err
+		}
+		slog.Info("agent upgraded", "event", "sandbox.upgrade.complete", "sandbox", name, "from", res.From, "to", res.To)
+		cliutil.RenderNotices(cmd, res.Notices)
+
+		if cliutil.JSONEnabled(cmd) {
+			action := "upgraded"
+			if !res.Relaunched {
+				action = "unchanged"
+			}
+			return cliutil.WriteJSON(cmd.OutOrStdout(), map[string]string{
+				"name":   name,
+				"action": action,
+				"from":   res.From,
+				"to":     res.To,
+			})
+		}
+		if !res.Relaunched {
+			return nil
+		}
+		_, err = fmt.Fprintf(cmd.OutOrStdout(), "Run 'yoloai attach %s' to reconnect\n", name)
+		return err
+	})
+}
@@ -40,9 +40,11 @@ func newMCPServeCmd() *cobra.Command {
 The MCP server exposes sandbox operations as tools for outer agents
 (Claude Desktop, VS Code Copilot, etc.) driving a two-layer agentic workflow:
 
-  - sandbox_create / sandbox_status / sandbox_list / sandbox_destroy
+  - sandbox_create / sandbox_run / sandbox_status / sandbox_wait
+  - sandbox_list / sandbox_destroy
   - sandbox_diff / sandbox_diff_file / sandbox_log
   - sandbox_input / sandbox_reset
+  - sandbox_apply (lands approved changes on the host, like 'yoloai apply')
   - sandbox_files_list / sandbox_files_read / sandbox_files_write
 
 Add to ~/.claude.json to use with Claude Desktop:
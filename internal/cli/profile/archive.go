@@ -0,0 +1,180 @@
+package profile
+
+// ABOUTME: `profile export` / `profile import`: share one profile (config,
+// ABOUTME: Dockerfile and the files it builds from) as a .tgz; never secrets.
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/kstenerud/yoloai"
+	"github.com/kstenerud/yoloai/internal/cli/cliutil"
+	"github.com/kstenerud/yoloai/internal/config"
+	"github.com/kstenerud/yoloai/internal/fileutil"
+	"github.com/kstenerud/yoloai/yoerrors"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+func newProfileExportCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "export <name>",
+		Short: "Write a profile to a .tgz archive to share",
+		Long: `Write a profile to a gzipped tar archive, for 'yoloai profile import' on
+another machine or by a teammate: its config.yaml, its Dockerfile, and every
+other file in the profile directory (scripts or configs the Dockerfile
+COPYs). The image isn't included; it is built on first use after import.
+
+Secrets never go in, as with 'yoloai config export': credential files are
+skipped and secret values are cut out of YAML files. Everything left out is
+listed.
+
+The archive goes to stdout unless -o names a file.`,
+		Example: `  yoloai profile export go-dev -o go-dev.tgz
+  yoloai profile export go-dev > go-dev.tgz`,
+		Args: cobra.ExactArgs(1),
+		ValidArgsFunction: func(_ *cobra.Command, args []string, _ string) ([]string, cobra.ShellCompDirective) {
+			if len(args) > 0 {
+				return nil, cobra.ShellCompDirectiveNoFileComp
+			}
+			names, err := config.ListProfiles(cliutil.Layout())
+			if err != nil {
+				return nil, cobra.ShellCompDirectiveError
+			}
+			return names, cobra.ShellCompDirectiveNoFileComp
+		},
+		RunE: runProfileExport,
+	}
+	cmd.Flags().StringP("output", "o", "", "Write the archive to this file instead of stdout")
+	return cmd
+}
+
+func runProfileExport(cmd *cobra.Command, args []string) error {
+	name := args[0]
+	output, _ := cmd.Flags().GetString("output")
+	sys, err := cliutil.System()
+	if err != nil {
+		return err
+	}
+
+	var result *yoloai.ProfileExportResult
+	if output == "" || output == "-" {
+		if cliutil.JSONEnabled(cmd) {
+			return yoerrors.NewUsageError("--json needs the archive written to a file: yoloai profile export %s -o <file> --json", name)
+		}
+		if f, ok := cmd.OutOrStdout().(*os.File); ok && term.IsTerminal(int(f.Fd())) { //nolint:gosec // G115: fd is a small int
+			return yoerrors.NewUsageError("refusing to write an archive to the terminal: name a file (yoloai profile export %s -o %s.tgz) or redirect it", name, name)
+		}
+		if result, err = sys.Profiles().Export(cmd.Context(), name, cmd.OutOrStdout()); err != nil {
+			return err
+		}
+	} else {
+		f, err := fileutil.OpenFile(output, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o600)
+		if err != nil {
+			return err
+		}
+		result, err = sys.Profiles().Export(cmd.Context(), name, f)
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			os.Remove(output) //nolint:errcheck,gosec // don't leave a partial archive behind
+			return err
+		}
+	}
+
+	if cliutil.JSONEnabled(cmd) {
+		omitted := make([]map[string]string, 0, len(result.Omitted))
+		for _, o := range result.Omitted {
+			omitted = append(omitted, map[string]string{"path": o.Path, "key": o.Key, "reason": o.Reason})
+		}
+		return cliutil.WriteJSON(cmd.OutOrStdout(), map[string]any{
+			"name":    name,
+			"file":    output, // --json is refused above when writing to stdout
+			"files":   cliutil.EmptyIfNil(result.Files),
+			"omitted": omitted,
+		})
+	}
+	// The archive may be on stdout, so the report goes to stderr.
+	report := cmd.ErrOrStderr()
+	fmt.Fprintf(report, "Exported profile '%s' (%d file(s))\n", name, len(result.Files)) //nolint:errcheck // best-effort output
+	printOmitted(report, result.Omitted)
+	return nil
+}
+
+// printOmitted lists the secrets an export left out.
+func printOmitted(w io.Writer, omitted []yoloai.ConfigBundleOmission) {
+	if len(omitted) == 0 {
+		return
+	}
+	fmt.Fprintln(w, "Left out, to set up again where it is imported:") //nolint:errcheck // best-effort output
+	for _, o := range omitted {
+		if o.Key != "" {
+			fmt.Fprintf(w, "  %s: %s (%s)\n", o.Path, o.Key, o.Reason) //nolint:errcheck // best-effort output
+		} else {
+			fmt.Fprintf(w, "  %s (%s)\n", o.Path, o.Reason) //nolint:errcheck // best-effort output
+		}
+	}
+}
+
+func newProfileImportCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "import <file>",
+		Short: "Install a profile from an archive written by 'profile export'",
+		Long: `Install a profile from an archive written by 'yoloai profile export'. Use -
+to read the archive from stdin.
+
+The profile keeps the name it was exported with unless --name gives another.
+Nothing is written until the whole archive has been checked, and a profile
+that already exists under that name stops the import unless --force replaces
+it. Its image is built the first time a sandbox uses it.`,
+		Example: `  yoloai profile import go-dev.tgz
+  yoloai profile import go-dev.tgz --name go-dev-team
+  curl -fsSL https://example.com/go-dev.tgz | yoloai profile import -`,
+		Args: cobra.ExactArgs(1),
+		RunE: runProfileImport,
+	}
+	cmd.Flags().String("name", "", "Install the profile under this name")
+	cmd.Flags().Bool("force", false, "Replace an existing profile of the same name")
+	return cmd
+}
+
+func runProfileImport(cmd *cobra.Command, args []string) error {
+	name, _ := cmd.Flags().GetString("name")
+	force, _ := cmd.Flags().GetBool("force")
+	sys, err := cliutil.System()
+	if err != nil {
+		return err
+	}
+
+	r := cmd.InOrStdin()
+	if args[0] != "-" {
+		f, err := os.Open(args[0])
+		if err != nil {
+			return err
+		}
+		defer f.Close() //nolint:errcheck // read-only
+		r = f
+	}
+
+	result, err := sys.Profiles().Import(cmd.Context(), r, yoloai.ProfileImportOptions{Name: name, Overwrite: force})
+	if err != nil {
+		return err
+	}
+	if cliutil.JSONEnabled(cmd) {
+		return cliutil.WriteJSON(cmd.OutOrStdout(), map[string]any{
+			"name":     result.Name,
+			"path":     cliutil.Layout().ProfileDir(result.Name),
+			"files":    cliutil.EmptyIfNil(result.Files),
+			"replaced": result.Replaced,
+		})
+	}
+	verb := "Imported"
+	if result.Replaced {
+		verb = "Replaced"
+	}
+	_, err = fmt.Fprintf(cmd.OutOrStdout(), "%s profile '%s' at %s (%d file(s))\n", verb, result.Name, cliutil.Layout().ProfileDir(result.Name), len(result.Files))
+	return err
+}
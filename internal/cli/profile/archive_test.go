@@ -0,0 +1,51 @@
+package profile
+
+// ABOUTME: Tests for profile export/import: an archive written to a file
+// ABOUTME: installs into a fresh home, renamed on request.
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/kstenerud/yoloai/internal/cli/clitest"
+	"github.com/kstenerud/yoloai/internal/cli/cliutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProfileExportImport(t *testing.T) {
+	clitest.Home(t)
+	dir := cliutil.Layout().ProfileDir("go-dev")
+	require.NoError(t, os.MkdirAll(dir, 0o750))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "config.yaml"), []byte("agent: codex\nenv:\n  GH_TOKEN: ghp_secret\n"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "Dockerfile"), []byte("FROM yoloai-base\n"), 0o600))
+	archive := filepath.Join(t.TempDir(), "go-dev.tgz")
+
+	cmd := newProfileExportCmd()
+	var stderr bytes.Buffer
+	cmd.SetErr(&stderr)
+	cmd.SetArgs([]string{"go-dev", "-o", archive})
+	require.NoError(t, cmd.Execute())
+	assert.Contains(t, stderr.String(), "Exported profile 'go-dev' (2 file(s))")
+	assert.Contains(t, stderr.String(), "config.yaml: env.GH_TOKEN (secret value)")
+
+	clitest.Home(t)
+	cmd = newProfileImportCmd()
+	var stdout bytes.Buffer
+	cmd.SetOut(&stdout)
+	cmd.SetArgs([]string{archive, "--name", "go-team"})
+	require.NoError(t, cmd.Execute())
+	assert.Contains(t, stdout.String(), "Imported profile 'go-team'")
+
+	data, err := os.ReadFile(filepath.Join(cliutil.Layout().ProfileDir("go-team"), "config.yaml"))
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "agent: codex")
+	assert.NotContains(t, string(data), "ghp_secret")
+	assert.FileExists(t, filepath.Join(cliutil.Layout().ProfileDir("go-team"), "Dockerfile"))
+
+	cmd = newProfileImportCmd()
+	cmd.SetArgs([]string{archive, "--name", "go-team"})
+	assert.Error(t, cmd.Execute(), "an existing profile needs --force")
+}
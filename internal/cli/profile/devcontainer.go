@@ -0,0 +1,88 @@
+package profile
+
+// ABOUTME: `profile from-devcontainer`: generate a profile from a repository's
+// ABOUTME: devcontainer.json instead of writing it again by hand.
+
+import (
+	"fmt"
+
+	"github.com/kstenerud/yoloai"
+	"github.com/kstenerud/yoloai/internal/cli/cliutil"
+
+	"github.com/spf13/cobra"
+)
+
+func newProfileFromDevcontainerCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "from-devcontainer [repo]",
+		Short: "Generate a profile from a repository's devcontainer.json",
+		Long: `Generate a profile from the devcontainer.json of a repository
+(.devcontainer/devcontainer.json, or devcontainer.json at its root), so a
+project that already defines a devcontainer needn't be described twice. The
+repository defaults to the current directory; a path to a devcontainer.json
+works too.
+
+Forwarded ports, the container's environment and the --cpus, --memory and
+--cap-add run arguments go into the profile's config.yaml. Profile images
+build on yoloai-base, so the devcontainer's image isn't used as is: the
+toolchain it provides (Go, Node.js, Python, Rust or Java) and those its
+features add are installed in a Dockerfile on yoloai-base, at the versions
+asked for. Lifecycle commands aren't copied; they still run from the
+devcontainer.json when the repository is a sandbox's workdir.
+
+Whatever can't be translated is listed afterwards, for you to finish in the
+profile directory. The profile is named after the repository's directory
+unless --name gives another; an existing profile of that name is replaced
+only with --force.`,
+		Example: `  yoloai profile from-devcontainer
+  yoloai profile from-devcontainer ~/src/api --name api
+  yoloai profile from-devcontainer ~/src/api/.devcontainer/devcontainer.json --force`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: runProfileFromDevcontainer,
+	}
+	cmd.Flags().String("name", "", "Name the profile (default: the repository directory's name)")
+	cmd.Flags().Bool("force", false, "Replace an existing profile of the same name")
+	return cmd
+}
+
+func runProfileFromDevcontainer(cmd *cobra.Command, args []string) error {
+	name, _ := cmd.Flags().GetString("name")
+	force, _ := cmd.Flags().GetBool("force")
+	repo := "."
+	if len(args) > 0 {
+		repo = args[0]
+	}
+	sys, err := cliutil.System()
+	if err != nil {
+		return err
+	}
+	result, err := sys.Profiles().FromDevcontainer(cmd.Context(), repo, yoloai.ProfileFromDevcontainerOptions{Name: name, Overwrite: force})
+	if err != nil {
+		return err
+	}
+	dir := cliutil.Layout().ProfileDir(result.Name)
+	if cliutil.JSONEnabled(cmd) {
+		return cliutil.WriteJSON(cmd.OutOrStdout(), map[string]any{
+			"name":     result.Name,
+			"path":     dir,
+			"source":   result.Source,
+			"files":    cliutil.EmptyIfNil(result.Files),
+			"replaced": result.Replaced,
+			"notes":    cliutil.EmptyIfNil(result.Notes),
+		})
+	}
+	verb := "Created"
+	if result.Replaced {
+		verb = "Replaced"
+	}
+	out := cmd.OutOrStdout()
+	fmt.Fprintf(out, "%s profile '%s' at %s from %s (%d file(s))\n", verb, result.Name, dir, result.Source, len(result.Files)) //nolint:errcheck // best-effort output
+	if len(result.Notes) > 0 {
+		fmt.Fprintln(out, "Not carried over, to finish by hand:") //nolint:errcheck // best-effort output
+		for _, n := range result.Notes {
+			fmt.Fprintf(out, "  - %s\n", n) //nolint:errcheck // best-effort output
+		}
+	}
+	_, err = fmt.Fprintf(out, "Use it with: yoloai new <name> <workdir> --profile %s\n", result.Name)
+	return err
+}
@@ -1,13 +1,14 @@
 package profile
 
-// ABOUTME: `yoloai profile` command group: create, list, info, delete.
-// ABOUTME: Manages reusable environment profiles in ~/.yoloai/profiles/.
+// ABOUTME: `yoloai profile` command group: create, list, info, delete, export/import,
+// ABOUTME: add/update, from-devcontainer. Manages reusable profiles in ~/.yoloai/profiles/.
 
 import (
 	"fmt"
 	"io"
 	"os"
 	"path/filepath"
+	"slices"
 	"sort"
 	"strings"
 	"text/tabwriter"
@@ -31,6 +32,11 @@ func NewCmd() *cobra.Command {
 		newProfileListCmd(),
 		newProfileInfoCmd(),
 		newProfileDeleteCmd(),
+		newProfileExportCmd(),
+		newProfileImportCmd(),
+		newProfileAddCmd(),
+		newProfileUpdateCmd(),
+		newProfileFromDevcontainerCmd(),
 	)
 
 	return cmd
@@ -84,6 +90,7 @@ func newProfileListCmd() *cobra.Command {
 					Name          string `json:"name"`
 					HasDockerfile bool   `json:"has_dockerfile"`
 					Agent         string `json:"agent"`
+					Source        string `json:"source,omitempty"`
 				}
 				items := make([]profileListItem, 0, len(summaries))
 				for _, s := range summaries {
@@ -91,6 +98,7 @@ func newProfileListCmd() *cobra.Command {
 						Name:          s.Name,
 						HasDockerfile: s.HasDockerfile,
 						Agent:         string(s.AgentType),
+						Source:        s.Source,
 					})
 				}
 				return cliutil.WriteJSONList(cmd.OutOrStdout(), "profiles", items)
@@ -101,14 +109,24 @@ func newProfileListCmd() *cobra.Command {
 				return nil
 			}
 
+			// The SOURCE column only appears once a profile was added from git.
+			withSource := slices.ContainsFunc(summaries, func(s yoloai.ProfileSummary) bool { return s.Source != "" })
 			w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
-			fmt.Fprintln(w, "NAME\tIMAGE\tAGENT") //nolint:errcheck
+			if withSource {
+				fmt.Fprintln(w, "NAME\tIMAGE\tAGENT\tSOURCE") //nolint:errcheck
+			} else {
+				fmt.Fprintln(w, "NAME\tIMAGE\tAGENT") //nolint:errcheck
+			}
 			for _, s := range summaries {
 				image := "no"
 				if s.HasDockerfile {
 					image = "yes"
 				}
-				fmt.Fprintf(w, "%s\t%s\t%s\n", s.Name, image, s.AgentType) //nolint:errcheck
+				if withSource {
+					fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", s.Name, image, s.AgentType, s.Source) //nolint:errcheck
+				} else {
+					fmt.Fprintf(w, "%s\t%s\t%s\n", s.Name, image, s.AgentType) //nolint:errcheck
+				}
 			}
 			return w.Flush()
 		},
@@ -322,7 +340,7 @@ func printProfileInfoDirs(out io.Writer, merged *yoloai.ResolvedProfileConfig) {
 
 // printProfileInfoResources prints resources and network fields.
 func printProfileInfoResources(out io.Writer, merged *yoloai.ResolvedProfileConfig) {
-	if merged.Resources != nil && (merged.Resources.CPULimit != "" || merged.Resources.MemoryLimit != "") {
+	if merged.Resources != nil && (merged.Resources.CPULimit != "" || merged.Resources.MemoryLimit != "" || merged.Resources.DiskLimit != "" || merged.Resources.AgentStateLimit != "") {
 		var parts []string
 		if merged.Resources.CPULimit != "" {
 			parts = append(parts, merged.Resources.CPULimit+" cpus")
@@ -330,6 +348,12 @@ func printProfileInfoResources(out io.Writer, merged *yoloai.ResolvedProfileConf
 		if merged.Resources.MemoryLimit != "" {
 			parts = append(parts, merged.Resources.MemoryLimit+" memory")
 		}
+		if merged.Resources.DiskLimit != "" {
+			parts = append(parts, merged.Resources.DiskLimit+" disk")
+		}
+		if merged.Resources.AgentStateLimit != "" {
+			parts = append(parts, merged.Resources.AgentStateLimit+" agent state")
+		}
 		fmt.Fprintf(out, "Resources:   %s\n", strings.Join(parts, ", ")) //nolint:errcheck
 	}
 	if merged.Network != nil && merged.Network.Isolated {
@@ -531,6 +555,20 @@ func printResourcesDiff(out io.Writer, old, new *yoloai.ProfileResources) bool {
 			lines = append(lines, fmt.Sprintf("    ~ %-10s %s → %s", "memory:", old.MemoryLimit, new.MemoryLimit))
 		}
 	}
+	if new.DiskLimit != old.DiskLimit {
+		if old.DiskLimit == "" {
+			lines = append(lines, fmt.Sprintf("    + %-10s %s", "disk:", new.DiskLimit))
+		} else {
+			lines = append(lines, fmt.Sprintf("    ~ %-10s %s → %s", "disk:", old.DiskLimit, new.DiskLimit))
+		}
+	}
+	if new.AgentStateLimit != old.AgentStateLimit {
+		if old.AgentStateLimit == "" {
+			lines = append(lines, fmt.Sprintf("    + %-10s %s", "agent_state:", new.AgentStateLimit))
+		} else {
+			lines = append(lines, fmt.Sprintf("    ~ %-10s %s → %s", "agent_state:", old.AgentStateLimit, new.AgentStateLimit))
+		}
+	}
 
 	if len(lines) == 0 {
 		return false
@@ -611,7 +649,7 @@ func newProfileDeleteCmd() *cobra.Command {
 			}
 
 			if !cliutil.EffectiveYes(cmd) {
-				confirmed, confirmErr := cliutil.Confirm(ctx, fmt.Sprintf("Delete profile '%s'? [y/N] ", name), os.Stdin, cmd.ErrOrStderr())
+				confirmed, confirmErr := cliutil.Confirm(ctx, fmt.Sprintf("Delete profile '%s'?", name), os.Stdin, cmd.ErrOrStderr())
 				if confirmErr != nil {
 					return confirmErr
 				}
@@ -0,0 +1,187 @@
+package profile
+
+// ABOUTME: `profile add` / `profile update`: install a profile from a directory
+// ABOUTME: of a git repository, and refresh it from there later.
+
+import (
+	"fmt"
+
+	"github.com/kstenerud/yoloai"
+	"github.com/kstenerud/yoloai/internal/cli/cliutil"
+
+	"github.com/spf13/cobra"
+)
+
+func newProfileAddCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "add <repo>//<dir>",
+		Short: "Install a profile from a git repository",
+		Long: `Install a profile from a directory of a git repository, so a team can keep
+its profiles in one place. The source is the repository URL, then //, then
+the profile's directory in it; leave off //<dir> when the whole repository
+is the profile. A URL that starts with a host name is fetched over https;
+ssh (git@host:org/repo) and local paths work too. Your git does the clone,
+so private repositories work wherever your git credentials do.
+
+The profile is named after its directory unless --name gives another, and
+follows the repository's default branch unless --ref names a branch, tag or
+commit. Where it came from is recorded, so 'yoloai profile update' can pull
+in later changes. Its image is built the first time a sandbox uses it.`,
+		Example: `  yoloai profile add github.com/org/yoloai-profiles//go-dev
+  yoloai profile add github.com/org/yoloai-profiles//go-dev --ref stable --name go
+  yoloai profile add git@github.com:org/private-profiles.git//rust`,
+		Args: cobra.ExactArgs(1),
+		RunE: runProfileAdd,
+	}
+	cmd.Flags().String("name", "", "Install the profile under this name")
+	cmd.Flags().String("ref", "", "Branch, tag or commit to install (default: the default branch)")
+	cmd.Flags().Bool("force", false, "Replace an existing profile of the same name")
+	return cmd
+}
+
+func runProfileAdd(cmd *cobra.Command, args []string) error {
+	name, _ := cmd.Flags().GetString("name")
+	ref, _ := cmd.Flags().GetString("ref")
+	force, _ := cmd.Flags().GetBool("force")
+	sys, err := cliutil.System()
+	if err != nil {
+		return err
+	}
+	result, err := sys.Profiles().Add(cmd.Context(), args[0], yoloai.ProfileAddOptions{Name: name, Ref: ref, Overwrite: force})
+	if err != nil {
+		return err
+	}
+	if cliutil.JSONEnabled(cmd) {
+		return cliutil.WriteJSON(cmd.OutOrStdout(), map[string]any{
+			"name":     result.Name,
+			"path":     cliutil.Layout().ProfileDir(result.Name),
+			"source":   result.Source,
+			"commit":   result.Commit,
+			"files":    cliutil.EmptyIfNil(result.Files),
+			"replaced": result.Replaced,
+		})
+	}
+	verb := "Added"
+	if result.Replaced {
+		verb = "Replaced"
+	}
+	_, err = fmt.Fprintf(cmd.OutOrStdout(), "%s profile '%s' from %s at %s (%d file(s))\n",
+		verb, result.Name, result.Source, shortCommit(result.Commit), len(result.Files))
+	return err
+}
+
+func newProfileUpdateCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "update [name...]",
+		Short: "Refresh profiles installed with 'profile add' from their repositories",
+		Long: `Refresh profiles installed with 'yoloai profile add' from the repositories
+they came from, following the branch they were added from. With no names,
+every such profile is updated. A profile whose files you have edited is left
+alone unless --force discards the edits. A profile's image is rebuilt the
+next time a sandbox uses it.`,
+		Example: `  yoloai profile update
+  yoloai profile update go-dev
+  yoloai profile update go-dev --force`,
+		ValidArgsFunction: func(cmd *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
+			names, err := addedProfiles(cmd)
+			if err != nil {
+				return nil, cobra.ShellCompDirectiveError
+			}
+			return names, cobra.ShellCompDirectiveNoFileComp
+		},
+		RunE: runProfileUpdate,
+	}
+	cmd.Flags().Bool("force", false, "Discard local edits to the profiles' files")
+	return cmd
+}
+
+func runProfileUpdate(cmd *cobra.Command, args []string) error {
+	force, _ := cmd.Flags().GetBool("force")
+	sys, err := cliutil.System()
+	if err != nil {
+		return err
+	}
+	names := args
+	if len(names) == 0 {
+		if names, err = addedProfiles(cmd); err != nil {
+			return err
+		}
+	}
+
+	type updateJSON struct {
+		Name      string   `json:"name"`
+		Source    string   `json:"source,omitempty"`
+		OldCommit string   `json:"old_commit,omitempty"`
+		Commit    string   `json:"commit,omitempty"`
+		Updated   bool     `json:"updated"`
+		Files     []string `json:"files,omitempty"`
+		Error     string   `json:"error,omitempty"`
+	}
+	out := cmd.OutOrStdout()
+	results := make([]updateJSON, 0, len(names))
+	failed := 0
+	for _, name := range names {
+		r, err := sys.Profiles().Update(cmd.Context(), name, yoloai.ProfileUpdateOptions{Force: force})
+		if err != nil {
+			// One profile failing doesn't stop the rest; with a single name
+			// its error is the command's.
+			if len(names) == 1 {
+				return err
+			}
+			failed++
+			results = append(results, updateJSON{Name: name, Error: err.Error()})
+			if !cliutil.JSONEnabled(cmd) {
+				fmt.Fprintf(cmd.ErrOrStderr(), "Failed to update '%s': %v\n", name, err) //nolint:errcheck // best-effort output
+			}
+			continue
+		}
+		results = append(results, updateJSON{Name: r.Name, Source: r.Source, OldCommit: r.OldCommit, Commit: r.Commit, Updated: r.Updated, Files: r.Files})
+		if cliutil.JSONEnabled(cmd) {
+			continue
+		}
+		if r.Updated {
+			fmt.Fprintf(out, "Updated '%s' %s -> %s (%d file(s))\n", r.Name, shortCommit(r.OldCommit), shortCommit(r.Commit), len(r.Files)) //nolint:errcheck // best-effort output
+		} else {
+			fmt.Fprintf(out, "'%s' is up to date (%s)\n", r.Name, shortCommit(r.Commit)) //nolint:errcheck // best-effort output
+		}
+	}
+
+	if cliutil.JSONEnabled(cmd) {
+		if err := cliutil.WriteJSONList(out, "profiles", results); err != nil {
+			return err
+		}
+	} else if len(names) == 0 {
+		fmt.Fprintln(out, "No profiles were added with 'yoloai profile add'") //nolint:errcheck // best-effort output
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d profile(s) failed to update", failed)
+	}
+	return nil
+}
+
+// addedProfiles names the profiles installed with `profile add`.
+func addedProfiles(cmd *cobra.Command) ([]string, error) {
+	sys, err := cliutil.System()
+	if err != nil {
+		return nil, err
+	}
+	summaries, err := sys.Profiles().List(cmd.Context())
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, s := range summaries {
+		if s.Source != "" {
+			names = append(names, s.Name)
+		}
+	}
+	return names, nil
+}
+
+// shortCommit abbreviates a commit ID the way git's one-line formats do.
+func shortCommit(sha string) string {
+	if len(sha) > 7 {
+		return sha[:7]
+	}
+	return sha
+}
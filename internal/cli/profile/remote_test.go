@@ -0,0 +1,57 @@
+package profile
+
+// ABOUTME: Tests for profile add/update: a profile added from a local git
+// ABOUTME: repository is listed with its source and refreshed by update.
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/kstenerud/yoloai/internal/cli/clitest"
+	"github.com/kstenerud/yoloai/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProfileAddUpdate(t *testing.T) {
+	clitest.Home(t)
+	repo := t.TempDir()
+	testutil.InitGitRepo(t, repo)
+	require.NoError(t, os.Mkdir(filepath.Join(repo, "go-dev"), 0o750))
+	testutil.WriteFile(t, repo, "go-dev/config.yaml", "agent: codex\n")
+	testutil.GitAdd(t, repo, ".")
+	testutil.GitCommit(t, repo, "go-dev")
+
+	cmd := newProfileAddCmd()
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{repo + "//go-dev", "--name", "go"})
+	require.NoError(t, cmd.Execute())
+	assert.Contains(t, out.String(), "Added profile 'go' from "+repo+"//go-dev at ")
+
+	out.Reset()
+	cmd = newProfileListCmd()
+	cmd.SetOut(&out)
+	require.NoError(t, cmd.Execute())
+	assert.Regexp(t, `NAME\s+IMAGE\s+AGENT\s+SOURCE`, out.String())
+	assert.Regexp(t, `go\s+no\s+codex\s+`+repo+"//go-dev", out.String())
+
+	out.Reset()
+	cmd = newProfileUpdateCmd()
+	cmd.SetOut(&out)
+	require.NoError(t, cmd.Execute())
+	assert.Contains(t, out.String(), "'go' is up to date")
+
+	testutil.WriteFile(t, repo, "go-dev/Dockerfile", "FROM yoloai-base\n")
+	testutil.GitAdd(t, repo, ".")
+	testutil.GitCommit(t, repo, "add Dockerfile")
+	out.Reset()
+	cmd = newProfileUpdateCmd()
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"go"})
+	require.NoError(t, cmd.Execute())
+	assert.Contains(t, out.String(), "Updated 'go' ")
+	assert.Contains(t, out.String(), "(2 file(s))")
+}
@@ -18,6 +18,7 @@ import (
 	"github.com/kstenerud/yoloai/internal/cli/sandboxcmd"
 
 	"github.com/kstenerud/yoloai/internal/fileutil"
+	"github.com/kstenerud/yoloai/internal/timing"
 	"github.com/kstenerud/yoloai/yoerrors"
 	"github.com/spf13/cobra"
 )
@@ -72,7 +73,13 @@ func Execute(ctx context.Context, version, commit, date string) (exitCode int) {
 		}
 	}()
 
-	runErr = rootCmd.ExecuteContext(ctx)
+	// Every command records its slow phases; reportTimings persists them and,
+	// under --timings, prints them.
+	rec := timing.NewRecorder()
+	runErr = rootCmd.ExecuteContext(timing.WithRecorder(ctx, rec))
+	if activeCmd != nil {
+		reportTimings(activeCmd, rec)
+	}
 	if runErr == nil {
 		return 0
 	}
@@ -222,7 +229,10 @@ diff and apply what you want to keep.`,
 	rootCmd.PersistentFlags().CountP("quiet", "q", "Suppress non-essential output (-q for error only)")
 	rootCmd.PersistentFlags().Bool("json", false, "Output as JSON (machine-readable)")
 	rootCmd.PersistentFlags().Bool("debug", false, "Enable debug-level entries in cli.jsonl")
+	rootCmd.PersistentFlags().Bool("timings", false, "Report where the command's time went (image, copy, baseline, create, boot)")
 	rootCmd.PersistentFlags().String("bugreport", "", "Write bug report (safe|unsafe)")
+	rootCmd.PersistentFlags().Duration("confirm-timeout", 0, "Answer yes/no prompts with their default after this long (e.g. 30s); 0 waits")
+	rootCmd.PersistentFlags().Bool("plain-prompts", false, "Spell out yes/no prompt choices in words, for screen readers (or set YOLOAI_PLAIN_PROMPTS=1)")
 	rootCmd.PersistentFlags().String("data-dir", "", "Override the yoloai data directory (default: $HOME/.yoloai/). HTTP/MCP/daemon/test embedders pass explicit paths; see development-principles.md §12.")
 
 	// Persistent pre-run: record the process-wide rootLayout from the
@@ -233,6 +243,12 @@ diff and apply what you want to keep.`,
 	rootCmd.PersistentPreRunE = func(cmd *cobra.Command, args []string) error {
 		dataDir, _ := cmd.Flags().GetString("data-dir")
 		cliutil.SetRootLayoutFromFlag(dataDir)
+		confirmTimeout, _ := cmd.Flags().GetDuration("confirm-timeout")
+		if confirmTimeout < 0 {
+			return yoerrors.NewUsageError("--confirm-timeout: must not be negative")
+		}
+		plainPrompts, _ := cmd.Flags().GetBool("plain-prompts")
+		cliutil.SetPromptSettings(cliutil.PromptSettingsFor(confirmTimeout, plainPrompts, cliutil.EdgeEnv()))
 		// Run the read-only migration gate before any command touches the data
 		// dir: it create-freshes a genuinely new install, fails fast telling
 		// the user to run `yoloai system migrate` when the dir is out of date,
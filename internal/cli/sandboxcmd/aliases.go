@@ -23,6 +23,7 @@ func NewLsAliasCmd() *cobra.Command {
 func NewLogAliasCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:     "log <name>",
+		Aliases: []string{"logs"},
 		Short:   "Show sandbox log (shortcut for 'sandbox log')",
 		GroupID: cliutil.GroupSandboxTools,
 		Args:    cobra.ArbitraryArgs,
@@ -33,13 +34,14 @@ func NewLogAliasCmd() *cobra.Command {
 }
 
 func addLogFlags(cmd *cobra.Command) {
-	cmd.Flags().String("source", "", "comma-separated sources: cli,sandbox,monitor,hooks")
+	cmd.Flags().String("source", "", "comma-separated sources: cli,sandbox,monitor,hooks,runtime")
 	cmd.Flags().String("level", "info", "minimum log level: debug|info|warn|error")
 	cmd.Flags().String("since", "", "show entries since duration (5m) or local time (14:20:00)")
 	cmd.Flags().Bool("raw", false, "emit raw JSONL (no formatting)")
 	cmd.Flags().Bool("agent", false, "show agent output (ANSI stripped)")
 	cmd.Flags().Bool("agent-raw", false, "show raw agent terminal stream")
 	cmd.Flags().BoolP("follow", "f", false, "tail log live; auto-exits when sandbox is done")
+	cmd.Flags().String("grep", "", "only show lines matching this regular expression")
 	cmd.MarkFlagsMutuallyExclusive("agent", "agent-raw")
 	cmd.MarkFlagsMutuallyExclusive("agent", "raw")
 	cmd.MarkFlagsMutuallyExclusive("agent-raw", "raw")
@@ -31,6 +31,27 @@ func TestNetworkAdd_NewDomains(t *testing.T) {
 	assert.Contains(t, out.String(), "will take effect on next start")
 }
 
+func TestNetworkAllowCmd(t *testing.T) {
+	sandboxDir := createNetworkSandbox(t, "na-top", "isolated", []string{"existing.com"})
+
+	cmd := NewNetworkCmd()
+	out := new(bytes.Buffer)
+	cmd.SetOut(out)
+	cmd.SetArgs([]string{"allow", "na-top", "registry.npmjs.org"})
+	require.NoError(t, cmd.Execute())
+
+	np, err := netpolicycfg.Load(sandboxDir)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"existing.com", "registry.npmjs.org"}, np.Allow)
+	assert.Contains(t, out.String(), "Allowed registry.npmjs.org")
+
+	cmd = NewNetworkCmd()
+	cmd.SetOut(new(bytes.Buffer))
+	cmd.SetErr(new(bytes.Buffer))
+	cmd.SetArgs([]string{"allow", "na-top"})
+	require.Error(t, cmd.Execute(), "a domain is required")
+}
+
 func TestNetworkAdd_DeduplicateExisting(t *testing.T) {
 	createNetworkSandbox(t, "na-dedup", "isolated", []string{"already.com"})
 
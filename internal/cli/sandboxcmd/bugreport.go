@@ -140,13 +140,14 @@ func WriteSandboxSectionsForFlag(w io.Writer, name, reportType string) {
 	ctx := context.Background()
 	l := cliutil.Layout()
 	c, err := yoloai.NewClient(ctx, yoloai.ClientCreateOptions{
-		DataDir:     l.DataDir,
-		HomeDir:     l.HomeDir,
-		Principal:   string(l.Principal),
-		BackendType: yoloai.BackendType(backend),
-		Input:       os.Stdin,
-		Output:      io.Discard, // best-effort path; don't write to the in-progress bug report
-		Env:         cliutil.EdgeEnv(),
+		DataDir:         l.DataDir,
+		HomeDir:         l.HomeDir,
+		Principal:       string(l.Principal),
+		SystemConfigDir: l.SystemConfigDir,
+		BackendType:     yoloai.BackendType(backend),
+		Input:           os.Stdin,
+		Output:          io.Discard, // best-effort path; don't write to the in-progress bug report
+		Env:             cliutil.EdgeEnv(),
 	})
 	if err != nil {
 		return
@@ -417,7 +418,7 @@ func writeBugReportAgentOutput(w io.Writer, c *yoloai.Client, name string) {
 	fmt.Fprintln(w, "<summary>Agent output</summary>") //nolint:errcheck
 	fmt.Fprintln(w)                                    //nolint:errcheck
 	fmt.Fprintln(w, "```")                             //nolint:errcheck
-	_ = stripANSI(w, strings.NewReader(output))
+	_ = cliutil.StripANSI(w, strings.NewReader(output))
 	fmt.Fprintln(w, "```")        //nolint:errcheck
 	fmt.Fprintln(w)               //nolint:errcheck
 	fmt.Fprintln(w, "</details>") //nolint:errcheck
@@ -476,9 +477,9 @@ func writeBugReportTmuxCapture(w io.Writer, sb *yoloai.Sandbox) {
 	tmuxEnv := cliutil.Layout().Env().EnvForHostTool()
 	var cmd *exec.Cmd
 	if tmuxSock != "" {
-		cmd = sysexec.Command(tmuxEnv, "tmux", "-S", tmuxSock, "capture-pane", "-p", "-t", "main")
+		cmd = sysexec.Command(tmuxEnv, "tmux", "-S", tmuxSock, "capture-pane", "-p", "-t", "main:{start}")
 	} else {
-		cmd = sysexec.Command(tmuxEnv, "tmux", "capture-pane", "-p", "-t", "main")
+		cmd = sysexec.Command(tmuxEnv, "tmux", "capture-pane", "-p", "-t", "main:{start}")
 	}
 
 	out, err := cmd.Output()
@@ -0,0 +1,99 @@
+// ABOUTME: `yoloai clean-state <name>` — delete the prunable part of a stopped
+// ABOUTME: sandbox's agent state (session transcripts, caches), or trim it to a size.
+package sandboxcmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kstenerud/yoloai"
+	"github.com/kstenerud/yoloai/internal/cli/cliutil"
+	"github.com/kstenerud/yoloai/internal/config"
+	"github.com/spf13/cobra"
+)
+
+func NewCleanStateCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "clean-state <name>",
+		Short: "Reclaim space from a sandbox's agent state",
+		Long: `Delete what the sandbox's agent keeps but can do without: its session
+transcripts, caches and logs (for Claude, projects/, todos/, shell-snapshots/
+and the like). Settings, credentials and anything else the agent doesn't list
+as prunable are kept.
+
+With --max-size, the oldest files go first and the trim stops once the agent's
+state is down to that size, so the latest conversation survives. Without it,
+all of the prunable state is removed, and the agent's native resume starts a
+fresh conversation.
+
+The sandbox's agent must not be running; stop the sandbox first. To trim
+automatically, set resources.agent_state (e.g. 2g): start then trims to it
+before the agent launches.`,
+		GroupID: cliutil.GroupSandboxTools,
+		Args:    cobra.ArbitraryArgs,
+		RunE:    runCleanState,
+	}
+
+	cmd.Flags().String("max-size", "", "Trim oldest first down to this size (e.g. 500m, 2g) instead of removing everything")
+	cmd.Flags().Bool("dry-run", false, "Show what would be removed without removing it")
+
+	return cmd
+}
+
+func runCleanState(cmd *cobra.Command, args []string) error {
+	name, _, err := cliutil.ResolveName(cmd, args)
+	if err != nil {
+		return err
+	}
+	maxSizeStr, _ := cmd.Flags().GetString("max-size")
+	maxSize, err := config.ParseSize("--max-size", maxSizeStr)
+	if err != nil {
+		return err
+	}
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+	return cliutil.WithSandbox(cmd, name, func(ctx context.Context, sb *yoloai.Sandbox) error {
+		res, err := sb.Agent().CleanState(ctx, yoloai.AgentCleanStateOptions{MaxSize: maxSize, DryRun: dryRun})
+		if err != nil {
+			return err
+		}
+		cliutil.RenderNotices(cmd, res.Notices)
+
+		if cliutil.JSONEnabled(cmd) {
+			removed := res.Removed
+			if removed == nil {
+				removed = []string{}
+			}
+			return cliutil.WriteJSON(cmd.OutOrStdout(), map[string]any{
+				"name":         name,
+				"dry_run":      dryRun,
+				"removed":      removed,
+				"freed_bytes":  res.Freed,
+				"before_bytes": res.Before,
+				"after_bytes":  res.After,
+			})
+		}
+		return printCleanState(cmd, res, dryRun)
+	})
+}
+
+// printCleanState reports the trim in human form: each file under --dry-run,
+// a one-line summary otherwise.
+func printCleanState(cmd *cobra.Command, res *yoloai.AgentCleanStateResult, dryRun bool) error {
+	out := cmd.OutOrStdout()
+	if len(res.Removed) == 0 {
+		_, err := fmt.Fprintf(out, "Nothing to remove (agent state is %s)\n", cliutil.FormatSize(res.Before))
+		return err
+	}
+	if dryRun {
+		for _, rel := range res.Removed {
+			fmt.Fprintf(out, "Would remove %s\n", rel) //nolint:errcheck // best-effort output
+		}
+		_, err := fmt.Fprintf(out, "Would free %s (agent state %s → %s)\n",
+			cliutil.FormatSize(res.Freed), cliutil.FormatSize(res.Before), cliutil.FormatSize(res.After))
+		return err
+	}
+	_, err := fmt.Fprintf(out, "Removed %d file(s), freed %s (agent state %s → %s)\n", len(res.Removed),
+		cliutil.FormatSize(res.Freed), cliutil.FormatSize(res.Before), cliutil.FormatSize(res.After))
+	return err
+}
@@ -0,0 +1,176 @@
+// ABOUTME: `yoloai cost` — per-sandbox token usage and estimated cost, read from the
+// ABOUTME: agents' own records (Claude Code and Codex sessions, aider's log).
+package sandboxcmd
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/kstenerud/yoloai"
+	"github.com/kstenerud/yoloai/internal/cli/cliutil"
+	"github.com/spf13/cobra"
+)
+
+func NewCostCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "cost [name...]",
+		Short: "Show the tokens each sandbox's agent has used and what they cost",
+		Long: `Show how many tokens each sandbox's agent has used, and what they cost.
+
+The counts come from the records the agent keeps: Claude Code's and Codex's
+session files, and the usage line aider prints after each reply. Other agents
+keep none yoloai can read and show as not tracked. A stopped sandbox still
+reports what it spent, but deleting its agent state (clean-state, or the
+retention scrub) deletes the record too.
+
+For Claude Code and Codex the cost is an estimate at list prices (marked ~),
+which does not know about subscription plans or negotiated discounts; aider's
+is its own figure. Tokens spent on a model yoloai has no price for are counted
+but left out of the cost, and the model is named.`,
+		Example: `  yoloai cost
+  yoloai cost my-sandbox --json`,
+		GroupID: cliutil.GroupSandboxTools,
+		Args:    cobra.ArbitraryArgs,
+		RunE:    runCost,
+	}
+}
+
+// costJSON is the --json shape of one sandbox's usage.
+type costJSON struct {
+	Name       string   `json:"name"`
+	Agent      string   `json:"agent,omitempty"`
+	Tracked    bool     `json:"tracked"`
+	Input      int64    `json:"input_tokens"`
+	Output     int64    `json:"output_tokens"`
+	CacheWrite int64    `json:"cache_write_tokens"`
+	CacheRead  int64    `json:"cache_read_tokens"`
+	CostUSD    float64  `json:"cost_usd"`
+	Estimated  bool     `json:"estimated,omitempty"`
+	Models     []string `json:"models,omitempty"`
+	Unpriced   []string `json:"unpriced_models,omitempty"`
+	Error      string   `json:"error,omitempty"`
+}
+
+func runCost(cmd *cobra.Command, args []string) error {
+	sys, err := cliutil.System()
+	if err != nil {
+		return err
+	}
+	for _, name := range args {
+		if err := cliutil.ValidateName(name); err != nil {
+			return err
+		}
+	}
+	rows, err := sys.SandboxCosts(args...)
+	if err != nil {
+		return err
+	}
+
+	if cliutil.JSONEnabled(cmd) {
+		out := make([]costJSON, 0, len(rows))
+		for _, r := range rows {
+			out = append(out, costJSONOf(r))
+		}
+		return cliutil.WriteJSON(cmd.OutOrStdout(), out)
+	}
+	if len(rows) == 0 {
+		fmt.Fprintln(cmd.OutOrStdout(), "No sandboxes found") //nolint:errcheck // best-effort output
+		return nil
+	}
+	return printCost(cmd.OutOrStdout(), rows)
+}
+
+// costJSONOf is r's --json shape.
+func costJSONOf(r yoloai.SandboxCost) costJSON {
+	u := r.Usage
+	j := costJSON{Name: r.Name, Agent: r.Agent, Tracked: r.Supported,
+		Input: u.Input, Output: u.Output, CacheWrite: u.CacheWrite, CacheRead: u.CacheRead,
+		CostUSD: u.CostUSD, Estimated: u.Estimated, Models: u.Models, Unpriced: u.Unpriced}
+	if r.Err != nil {
+		j.Error = r.Err.Error()
+	}
+	return j
+}
+
+// printCost renders the usage table, with a total row when there is more than
+// one sandbox, and names any models left out of the cost.
+func printCost(out io.Writer, rows []yoloai.SandboxCost) error {
+	w := tabwriter.NewWriter(out, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(w, "NAME\tAGENT\tINPUT\tOUTPUT\tCACHE WRITE\tCACHE READ\tCOST") //nolint:errcheck
+	var sum yoloai.TokenUsage
+	var unpriced []string
+	for _, r := range rows {
+		switch {
+		case r.Err != nil:
+			fmt.Fprintf(w, "%s\t%s\t-\t-\t-\t-\t%s\n", r.Name, r.Agent, r.Err) //nolint:errcheck
+			continue
+		case !r.Supported:
+			fmt.Fprintf(w, "%s\t%s\t-\t-\t-\t-\tnot tracked\n", r.Name, r.Agent) //nolint:errcheck
+			continue
+		}
+		u := r.Usage
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n", r.Name, r.Agent, //nolint:errcheck
+			formatTokens(u.Input), formatTokens(u.Output), formatTokens(u.CacheWrite), formatTokens(u.CacheRead),
+			formatCost(u))
+		sum.Input += u.Input
+		sum.Output += u.Output
+		sum.CacheWrite += u.CacheWrite
+		sum.CacheRead += u.CacheRead
+		sum.CostUSD += u.CostUSD
+		sum.Estimated = sum.Estimated || u.Estimated
+		for _, m := range u.Unpriced {
+			unpriced = append(unpriced, r.Name+": "+m)
+		}
+	}
+	if len(rows) > 1 {
+		fmt.Fprintf(w, "TOTAL\t\t%s\t%s\t%s\t%s\t%s\n", //nolint:errcheck
+			formatTokens(sum.Input), formatTokens(sum.Output), formatTokens(sum.CacheWrite), formatTokens(sum.CacheRead),
+			formatCost(sum))
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	if len(unpriced) > 0 {
+		_, err := fmt.Fprintf(out, "\nNo price known, left out of the cost: %s\n", strings.Join(unpriced, ", "))
+		return err
+	}
+	return nil
+}
+
+// formatTokens renders a token count compactly: 950, 12.3k, 4.1M.
+func formatTokens(n int64) string {
+	switch {
+	case n >= 1_000_000:
+		return fmt.Sprintf("%.1fM", float64(n)/1e6)
+	case n >= 1_000:
+		return fmt.Sprintf("%.1fk", float64(n)/1e3)
+	default:
+		return fmt.Sprintf("%d", n)
+	}
+}
+
+// formatTokenUsage is the one-line summary sandbox info shows:
+// "12.3k in, 950 out, 4.1M cached, ~$1.23".
+func formatTokenUsage(u yoloai.TokenUsage) string {
+	s := fmt.Sprintf("%s in, %s out", formatTokens(u.Input), formatTokens(u.Output))
+	if cached := u.CacheWrite + u.CacheRead; cached > 0 {
+		s += fmt.Sprintf(", %s cached", formatTokens(cached))
+	}
+	s += ", " + formatCost(u)
+	if len(u.Unpriced) > 0 {
+		s += fmt.Sprintf(" (no price for %s)", strings.Join(u.Unpriced, ", "))
+	}
+	return s
+}
+
+// formatCost renders a usage's cost in dollars, "~" marking an estimate from
+// list prices.
+func formatCost(u yoloai.TokenUsage) string {
+	s := fmt.Sprintf("$%.2f", u.CostUSD)
+	if u.Estimated {
+		s = "~" + s
+	}
+	return s
+}
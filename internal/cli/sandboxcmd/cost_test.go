@@ -0,0 +1,33 @@
+package sandboxcmd
+
+// ABOUTME: Unit tests for the `cost` table: compact counts, estimates marked,
+// ABOUTME: untracked agents, totals, and models left out of the cost.
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	yoloai "github.com/kstenerud/yoloai"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrintCost(t *testing.T) {
+	var b bytes.Buffer
+	require.NoError(t, printCost(&b, []yoloai.SandboxCost{
+		{Name: "fix-bug", Agent: "claude", Supported: true, Usage: yoloai.TokenUsage{
+			Input: 12_345, Output: 950, CacheRead: 4_100_000, CostUSD: 1.234, Estimated: true, Unpriced: []string{"claude-next"},
+		}},
+		{Name: "pair", Agent: "aider", Supported: true, Usage: yoloai.TokenUsage{Input: 2_000, Output: 100, CostUSD: 0.5}},
+		{Name: "sh", Agent: "shell"},
+		{Name: "broken", Err: errors.New("permission denied")},
+	}))
+	out := b.String()
+	assert.Regexp(t, `fix-bug\s+claude\s+12.3k\s+950\s+0\s+4.1M\s+~\$1.23`, out)
+	assert.Regexp(t, `pair\s+aider\s+2.0k\s+100\s+0\s+0\s+\$0.50`, out)
+	assert.Regexp(t, `sh\s+shell\s+-\s+-\s+-\s+-\s+not tracked`, out)
+	assert.Contains(t, out, "permission denied")
+	assert.Regexp(t, `TOTAL\s+14.3k\s+1.1k\s+0\s+4.1M\s+~\$1.73`, out)
+	assert.Contains(t, out, "No price known, left out of the cost: fix-bug: claude-next")
+}
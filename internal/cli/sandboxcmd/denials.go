@@ -0,0 +1,100 @@
+// ABOUTME: `yoloai sandbox <name> denials` — list the file writes the sandbox's
+// ABOUTME: confinement refused (seatbelt), so a failing tool can be diagnosed.
+package sandboxcmd
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/kstenerud/yoloai"
+	"github.com/kstenerud/yoloai/internal/cli/cliutil"
+	"github.com/kstenerud/yoloai/yoerrors"
+	"github.com/spf13/cobra"
+)
+
+// defaultDenialsSince is how far back `denials` looks without --since.
+const defaultDenialsSince = 24 * time.Hour
+
+// deniedWriteJSON is the --json shape of one refused write.
+type deniedWriteJSON struct {
+	Time      string `json:"time,omitempty"`
+	Process   string `json:"process"`
+	Operation string `json:"operation"`
+	Path      string `json:"path"`
+}
+
+// runSandboxDenials dispatches `yoloai sandbox <name> denials [--since DUR]`.
+func runSandboxDenials(cmd *cobra.Command, name string, rest []string) error {
+	since, err := parseDenialsArgs(rest)
+	if err != nil {
+		return err
+	}
+
+	return cliutil.WithSandbox(cmd, name, func(ctx context.Context, sb *yoloai.Sandbox) error {
+		denied, supported, err := sb.DeniedWrites(ctx, time.Now().Add(-since))
+		if err != nil {
+			return err
+		}
+		if !supported {
+			return yoerrors.NewUsageError("sandbox %q: refused-write reports are only available on the seatbelt backend", name)
+		}
+		return printDenials(cmd, denied, since)
+	})
+}
+
+// parseDenialsArgs parses the dispatched args of `denials`.
+func parseDenialsArgs(rest []string) (time.Duration, error) {
+	since := defaultDenialsSince
+	for i := 0; i < len(rest); i++ {
+		switch rest[i] {
+		case "--since":
+			if i+1 >= len(rest) {
+				return 0, yoerrors.NewUsageError("--since requires a duration (e.g. 30m, 2h)")
+			}
+			i++
+			d, err := time.ParseDuration(rest[i])
+			if err != nil || d <= 0 {
+				return 0, yoerrors.NewUsageError("invalid --since %q: want a positive duration (e.g. 30m, 2h)", rest[i])
+			}
+			since = d
+		case "":
+			// ignore
+		default:
+			return 0, yoerrors.NewUsageError("unknown flag %q for denials (valid: --since)", rest[i])
+		}
+	}
+	return since, nil
+}
+
+// printDenials renders the refused writes as a table or JSON.
+func printDenials(cmd *cobra.Command, denied []yoloai.DeniedWrite, since time.Duration) error {
+	if cliutil.JSONEnabled(cmd) {
+		items := make([]deniedWriteJSON, 0, len(denied))
+		for _, d := range denied {
+			item := deniedWriteJSON{Process: d.Process, Operation: d.Operation, Path: d.Path}
+			if !d.Time.IsZero() {
+				item.Time = d.Time.UTC().Format(time.RFC3339)
+			}
+			items = append(items, item)
+		}
+		return cliutil.WriteJSONList(cmd.OutOrStdout(), "denials", items)
+	}
+
+	out := cmd.OutOrStdout()
+	if len(denied) == 0 {
+		_, err := fmt.Fprintf(out, "No refused writes in the last %s\n", since)
+		return err
+	}
+	for _, d := range denied {
+		ts := "-"
+		if !d.Time.IsZero() {
+			ts = d.Time.Local().Format("15:04:05")
+		}
+		fmt.Fprintf(out, "%s  %-16s %-20s %s\n", ts, d.Process, d.Operation, d.Path) //nolint:errcheck // best-effort output
+	}
+	fmt.Fprintln(out)                                                                                                 //nolint:errcheck // best-effort output
+	fmt.Fprintln(out, "The agent may only write its work copies and this sandbox's own directory (TMPDIR included).") //nolint:errcheck // best-effort output
+	_, err := fmt.Fprintln(out, "Point the tool at $TMPDIR, or mount the path with -d <path>:rw.")
+	return err
+}
@@ -0,0 +1,26 @@
+package sandboxcmd
+
+// ABOUTME: Unit tests for `yoloai sandbox <name> denials` argument parsing.
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDenialsArgs(t *testing.T) {
+	since, err := parseDenialsArgs(nil)
+	require.NoError(t, err)
+	assert.Equal(t, defaultDenialsSince, since)
+
+	since, err = parseDenialsArgs([]string{"--since", "30m"})
+	require.NoError(t, err)
+	assert.Equal(t, 30*time.Minute, since)
+
+	for _, bad := range [][]string{{"--since"}, {"--since", "soon"}, {"--since", "-1h"}, {"--all"}} {
+		_, err := parseDenialsArgs(bad)
+		assert.Error(t, err, "args %v", bad)
+	}
+}
@@ -0,0 +1,113 @@
+// ABOUTME: `yoloai du` — per-sandbox disk usage split into work copies, agent
+// ABOUTME: state and logs, flagged against each sandbox's resources.disk limit.
+package sandboxcmd
+
+import (
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/kstenerud/yoloai"
+	"github.com/kstenerud/yoloai/internal/cli/cliutil"
+	"github.com/spf13/cobra"
+)
+
+func NewDuCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "du [name...]",
+		Short: "Show each sandbox's disk usage",
+		Long: `Show how much disk each sandbox uses, split into its work copies, the
+agent's state (its session transcripts included), logs, and everything else.
+
+A sandbox with a resources.disk limit shows it, and one over it is marked:
+start refuses such a sandbox until space is freed. Sizes are measured on the
+host, so a VM backend's in-guest work copy is not counted.`,
+		Example: `  yoloai du
+  yoloai du my-sandbox --json`,
+		GroupID: cliutil.GroupSandboxTools,
+		Args:    cobra.ArbitraryArgs,
+		RunE:    runDu,
+	}
+}
+
+// duJSON is the --json shape of one sandbox's usage.
+type duJSON struct {
+	Name       string `json:"name"`
+	Work       int64  `json:"work_bytes"`
+	AgentState int64  `json:"agent_state_bytes"`
+	Logs       int64  `json:"logs_bytes"`
+	Other      int64  `json:"other_bytes"`
+	Total      int64  `json:"total_bytes"`
+	Limit      int64  `json:"limit_bytes,omitempty"`
+	OverLimit  bool   `json:"over_limit,omitempty"`
+	Error      string `json:"error,omitempty"`
+}
+
+func runDu(cmd *cobra.Command, args []string) error {
+	sys, err := cliutil.System()
+	if err != nil {
+		return err
+	}
+	for _, name := range args {
+		if err := cliutil.ValidateName(name); err != nil {
+			return err
+		}
+	}
+	rows, err := sys.SandboxDiskUsage(args...)
+	if err != nil {
+		return err
+	}
+
+	if cliutil.JSONEnabled(cmd) {
+		out := make([]duJSON, 0, len(rows))
+		for _, r := range rows {
+			j := duJSON{Name: r.Name, Work: r.Work, AgentState: r.AgentState, Logs: r.Logs, Other: r.Other, Total: r.Total, Limit: r.Limit}
+			j.OverLimit = r.Limit > 0 && r.Total > r.Limit
+			if r.Err != nil {
+				j.Error = r.Err.Error()
+			}
+			out = append(out, j)
+		}
+		return cliutil.WriteJSON(cmd.OutOrStdout(), out)
+	}
+	if len(rows) == 0 {
+		fmt.Fprintln(cmd.OutOrStdout(), "No sandboxes found") //nolint:errcheck // best-effort output
+		return nil
+	}
+	return printDu(cmd.OutOrStdout(), rows)
+}
+
+// printDu renders the usage table, with a total row when there is more than
+// one sandbox.
+func printDu(out io.Writer, rows []yoloai.SandboxDiskUsage) error {
+	w := tabwriter.NewWriter(out, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(w, "NAME\tWORK\tAGENT STATE\tLOGS\tOTHER\tTOTAL\tLIMIT") //nolint:errcheck
+	var sum yoloai.SandboxDiskUsage
+	for _, r := range rows {
+		if r.Err != nil {
+			fmt.Fprintf(w, "%s\t-\t-\t-\t-\t-\t%s\n", r.Name, r.Err) //nolint:errcheck
+			continue
+		}
+		limit := "-"
+		if r.Limit > 0 {
+			limit = cliutil.FormatSize(r.Limit)
+			if r.Total > r.Limit {
+				limit += " (over)"
+			}
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n", r.Name, //nolint:errcheck
+			cliutil.FormatSize(r.Work), cliutil.FormatSize(r.AgentState), cliutil.FormatSize(r.Logs),
+			cliutil.FormatSize(r.Other), cliutil.FormatSize(r.Total), limit)
+		sum.Work += r.Work
+		sum.AgentState += r.AgentState
+		sum.Logs += r.Logs
+		sum.Other += r.Other
+		sum.Total += r.Total
+	}
+	if len(rows) > 1 {
+		fmt.Fprintf(w, "TOTAL\t%s\t%s\t%s\t%s\t%s\t\n", //nolint:errcheck
+			cliutil.FormatSize(sum.Work), cliutil.FormatSize(sum.AgentState), cliutil.FormatSize(sum.Logs),
+			cliutil.FormatSize(sum.Other), cliutil.FormatSize(sum.Total))
+	}
+	return w.Flush()
+}
@@ -0,0 +1,34 @@
+package sandboxcmd
+
+// ABOUTME: Unit tests for the `du` table: per-part columns, limits, totals.
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	yoloai "github.com/kstenerud/yoloai"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrintDu(t *testing.T) {
+	var b bytes.Buffer
+	require.NoError(t, printDu(&b, []yoloai.SandboxDiskUsage{
+		{Name: "big", Work: 3 << 30, AgentState: 2 << 20, Total: 3<<30 + 2<<20, Limit: 2 << 30},
+		{Name: "small", Logs: 4 << 10, Total: 4 << 10},
+		{Name: "broken", Err: errors.New("permission denied")},
+	}))
+	out := b.String()
+	assert.Contains(t, out, "AGENT STATE")
+	assert.Contains(t, out, "2.0GB (over)")
+	assert.Contains(t, out, "permission denied")
+	assert.Regexp(t, `small\s+0B\s+0B\s+4KB\s+0B\s+4KB\s+-`, out)
+	assert.Regexp(t, `TOTAL\s+3.0GB`, out)
+}
+
+func TestPrintDu_SingleHasNoTotal(t *testing.T) {
+	var b bytes.Buffer
+	require.NoError(t, printDu(&b, []yoloai.SandboxDiskUsage{{Name: "one", Total: 10}}))
+	assert.NotContains(t, b.String(), "\nTOTAL")
+}
@@ -9,6 +9,7 @@ import (
 	"io"
 	"log/slog"
 	"strings"
+	"time"
 
 	"github.com/kstenerud/yoloai/internal/cli/cliutil"
 
@@ -27,34 +28,58 @@ func runSandboxInfo(cmd *cobra.Command, name string) error {
 		if err != nil {
 			return cliutil.SandboxErrorHint(name, err)
 		}
+		// Best-effort: a session that can't be queried just shows no clients.
+		attached, _ := sb.Agent().AttachedClients(ctx)
+
+		// Best-effort too: an unreadable usage record just shows no tokens.
+		usage, tracked, usageErr := sb.TokenUsage()
+		tracked = tracked && usageErr == nil
 
 		if cliutil.JSONEnabled(cmd) {
 			type infoJSON struct {
 				*yoloai.SandboxInfo
-				ConfigPath    string `json:"config_path"`
-				PromptPreview string `json:"prompt_preview,omitempty"`
+				ConfigPath    string                  `json:"config_path"`
+				PromptPreview string                  `json:"prompt_preview,omitempty"`
+				Attached      []yoloai.AttachedClient `json:"attached,omitempty"`
+				Tokens        *costJSON               `json:"tokens,omitempty"`
 			}
 			result := infoJSON{
 				SandboxInfo:   info,
 				ConfigPath:    sb.RuntimeConfigPath(),
 				PromptPreview: loadPromptPreview(sb),
+				Attached:      attached,
+			}
+			if tracked {
+				j := costJSONOf(yoloai.SandboxCost{Name: name, Agent: string(info.AgentType), Usage: usage, Supported: true})
+				result.Tokens = &j
 			}
 			return cliutil.WriteJSON(cmd.OutOrStdout(), result)
 		}
 
-		printSandboxInfo(cmd, sb, name, info)
+		var tokens string
+		if tracked && usage.Total() > 0 {
+			tokens = formatTokenUsage(usage)
+		}
+		printSandboxInfo(cmd, sb, name, info, attached, tokens)
 		slog.Debug("show complete", "event", "sandbox.info", "sandbox", name)
 		return nil
 	})
 }
 
-// printSandboxInfo prints sandbox info in human-readable format.
-func printSandboxInfo(cmd *cobra.Command, sb *yoloai.Sandbox, name string, info *yoloai.SandboxInfo) {
+// printSandboxInfo prints sandbox info in human-readable format. tokens is the
+// agent's token usage line; "" leaves it out.
+func printSandboxInfo(cmd *cobra.Command, sb *yoloai.Sandbox, name string, info *yoloai.SandboxInfo, attached []yoloai.AttachedClient, tokens string) {
 	w := cmd.OutOrStdout()
 	meta := info.Environment
 
-	fmt.Fprintf(w, "Name:        %s\n", meta.Name)      //nolint:errcheck
-	fmt.Fprintf(w, "Status:      %s\n", info.Status)    //nolint:errcheck
+	fmt.Fprintf(w, "Name:        %s\n", meta.Name)   //nolint:errcheck
+	fmt.Fprintf(w, "Status:      %s\n", info.Status) //nolint:errcheck
+	if activity := formatActivity(info, time.Now()); activity != "" {
+		fmt.Fprintf(w, "Activity:    %s\n", activity) //nolint:errcheck
+	}
+	if len(attached) > 0 {
+		fmt.Fprintf(w, "Attached:    %s\n", formatAttached(attached)) //nolint:errcheck
+	}
 	fmt.Fprintf(w, "Agent:       %s\n", info.AgentType) //nolint:errcheck
 
 	if info.Model != "" {
@@ -79,6 +104,74 @@ func printSandboxInfo(cmd *cobra.Command, sb *yoloai.Sandbox, name string, info
 	printSandboxDirs(w, meta)
 	printSandboxNetwork(w, info)
 	printSandboxResources(w, meta, info)
+	if tokens != "" {
+		fmt.Fprintf(w, "Tokens:      %s\n", tokens) //nolint:errcheck
+	}
+	printSandboxResult(w, info)
+}
+
+// formatActivity describes what a running agent's output heartbeat says:
+// "waiting for input, idle for 12m", "no output for 12m" once a working agent
+// has been quiet for quietAfter, otherwise "last output 5s ago". "" when the
+// sandbox isn't running an agent or has no heartbeat.
+func formatActivity(info *yoloai.SandboxInfo, now time.Time) string {
+	if info.LastOutput.IsZero() {
+		return ""
+	}
+	quiet := cliutil.FormatDuration(max(now.Sub(info.LastOutput), 0))
+	switch info.Status {
+	case yoloai.StatusIdle:
+		return "waiting for input, idle for " + quiet
+	case yoloai.StatusActive:
+		if now.Sub(info.LastOutput) >= quietAfter {
+			return "no output for " + quiet
+		}
+		return "last output " + quiet + " ago"
+	default:
+		return ""
+	}
+}
+
+// formatAttached renders the attached terminals as "/dev/pts/3, /dev/pts/5
+// (read-only)".
+func formatAttached(clients []yoloai.AttachedClient) string {
+	parts := make([]string, len(clients))
+	for i, c := range clients {
+		parts[i] = c.TTY
+		if c.ReadOnly {
+			parts[i] += " (read-only)"
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// printSandboxResult prints the agent's result.json, when it wrote one.
+func printSandboxResult(w io.Writer, info *yoloai.SandboxInfo) {
+	if info.ResultError != "" {
+		fmt.Fprintf(w, "Result:      unusable (%s)\n", info.ResultError) //nolint:errcheck
+		return
+	}
+	res := info.Result
+	if res == nil {
+		return
+	}
+	fmt.Fprintf(w, "Result:      %s\n", res.Status) //nolint:errcheck
+	if res.Summary != "" {
+		fmt.Fprintf(w, "Summary:     %s\n", strings.Join(strings.Fields(res.Summary), " ")) //nolint:errcheck
+	}
+	printResultList(w, "Follow-ups:", res.FollowUps)
+	printResultList(w, "Files:", res.Files)
+}
+
+// printResultList prints items under a label, one per line, aligned with the
+// other info fields.
+func printResultList(w io.Writer, label string, items []string) {
+	for i, item := range items {
+		if i > 0 {
+			label = ""
+		}
+		fmt.Fprintf(w, "%-12s %s\n", label, item) //nolint:errcheck
+	}
 }
 
 // printSandboxDirs prints workdir and auxiliary directory information.
@@ -95,6 +188,9 @@ func printSandboxDirs(w io.Writer, meta *yoloai.Environment) {
 			fmt.Fprintf(w, "Dir:         %s (%s)\n", d.HostPath, d.Mode) //nolint:errcheck
 		}
 	}
+	if meta.WorkRoot != "" {
+		fmt.Fprintf(w, "Work root:   %s\n", meta.WorkRoot) //nolint:errcheck
+	}
 }
 
 // printSandboxNetwork prints network mode and port information.
@@ -146,11 +242,49 @@ func printSandboxResources(w io.Writer, meta *yoloai.Environment, info *yoloai.S
 		if meta.Resources.MemoryLimit != "" {
 			parts = append(parts, meta.Resources.MemoryLimit+" memory")
 		}
+		if meta.Resources.DiskLimit != "" {
+			parts = append(parts, meta.Resources.DiskLimit+" disk")
+		}
+		if meta.Resources.AgentStateLimit != "" {
+			parts = append(parts, meta.Resources.AgentStateLimit+" agent state")
+		}
 		if len(parts) > 0 {
 			fmt.Fprintf(w, "Resources:   %s\n", strings.Join(parts, ", ")) //nolint:errcheck
 		}
 	}
 	fmt.Fprintf(w, "Created:     %s (%s)\n", meta.CreatedAt.Format("2006-01-02T15:04:05Z07:00"), cliutil.FormatAge(meta.CreatedAt)) //nolint:errcheck
+	if meta.AgentWorkdir != "" {
+		fmt.Fprintf(w, "Agent dir:   %s\n", meta.AgentWorkdir) //nolint:errcheck
+	}
+	if meta.RepoURL != "" {
+		repo := meta.RepoURL
+		if meta.RepoRef != "" {
+			repo += " @ " + meta.RepoRef
+		}
+		if meta.RepoDepth > 0 {
+			repo += fmt.Sprintf(" (depth %d)", meta.RepoDepth)
+		}
+		if len(meta.RepoSparse) > 0 {
+			repo += " (sparse: " + strings.Join(meta.RepoSparse, ", ") + ")"
+		}
+		fmt.Fprintf(w, "Repo:        %s\n", repo) //nolint:errcheck
+	}
+	if meta.AllowPush {
+		fmt.Fprintln(w, "Git push:    allowed") //nolint:errcheck
+	}
+	if len(meta.Roles) > 0 {
+		fmt.Fprintf(w, "Roles:       %s\n", strings.Join(meta.Roles, ", ")) //nolint:errcheck
+	}
+	if meta.FakeTime != "" {
+		fmt.Fprintf(w, "Faketime:    %s\n", meta.FakeTime) //nolint:errcheck
+	}
+	if meta.ExpiresAt != nil {
+		when := "in " + cliutil.FormatDuration(time.Until(*meta.ExpiresAt))
+		if meta.Expired(time.Now()) {
+			when = "expired; 'yoloai gc' destroys it"
+		}
+		fmt.Fprintf(w, "Expires:     %s (%s)\n", meta.ExpiresAt.Format("2006-01-02T15:04:05Z07:00"), when) //nolint:errcheck
+	}
 	if meta.Workdir().BaselineSHA != "" {
 		fmt.Fprintf(w, "Baseline:    %s\n", meta.Workdir().BaselineSHA) //nolint:errcheck
 	}
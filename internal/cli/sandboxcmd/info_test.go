@@ -1,12 +1,13 @@
 // ABOUTME: Tests for sandbox info rendering: prompt-preview truncation
-// ABOUTME: (rune-safe) and the network-health line (wedged/ok/unknown/
-// ABOUTME: unprobed) shown by `sandbox info`.
+// ABOUTME: (rune-safe), the network-health line (wedged/ok/unknown/unprobed)
+// ABOUTME: and the agent-activity line shown by `sandbox info`.
 package sandboxcmd
 
 import (
 	"bytes"
 	"strings"
 	"testing"
+	"time"
 
 	yoloai "github.com/kstenerud/yoloai"
 	"github.com/stretchr/testify/assert"
@@ -82,3 +83,45 @@ func TestPrintSandboxNetwork_NoNetHealthLineWhenUnprobed(t *testing.T) {
 	printSandboxNetwork(&buf, netHealthTestInfo("", ""))
 	assert.NotContains(t, buf.String(), "Net health:")
 }
+
+func TestPrintSandboxResult(t *testing.T) {
+	var buf bytes.Buffer
+	printSandboxResult(&buf, &yoloai.SandboxInfo{Result: &yoloai.AgentResult{
+		Status:    yoloai.ResultStatusPartial,
+		Summary:   "Fixed the parser.\nTests still flaky.",
+		FollowUps: []string{"stabilize tests", "update docs"},
+	}})
+	out := buf.String()
+	assert.Contains(t, out, "Result:      partial\n")
+	assert.Contains(t, out, "Summary:     Fixed the parser. Tests still flaky.\n")
+	assert.Contains(t, out, "Follow-ups:  stabilize tests\n             update docs\n")
+	assert.NotContains(t, out, "Files:")
+}
+
+func TestPrintSandboxResult_Unusable(t *testing.T) {
+	var buf bytes.Buffer
+	printSandboxResult(&buf, &yoloai.SandboxInfo{ResultError: "result.json: invalid status \"done\""})
+	assert.Contains(t, buf.String(), "Result:      unusable (")
+}
+
+func TestPrintSandboxResult_NoneWritten(t *testing.T) {
+	var buf bytes.Buffer
+	printSandboxResult(&buf, &yoloai.SandboxInfo{})
+	assert.Empty(t, buf.String())
+}
+
+func TestFormatActivity(t *testing.T) {
+	now := time.Now()
+	info := &yoloai.SandboxInfo{Status: yoloai.StatusIdle, LastOutput: now.Add(-12 * time.Minute)}
+	assert.Equal(t, "waiting for input, idle for 12m", formatActivity(info, now))
+
+	info.Status = yoloai.StatusActive
+	assert.Equal(t, "no output for 12m", formatActivity(info, now))
+	info.LastOutput = now.Add(-5 * time.Second)
+	assert.Equal(t, "last output 5s ago", formatActivity(info, now))
+
+	info.Status = yoloai.StatusStopped
+	assert.Empty(t, formatActivity(info, now), "a stopped sandbox's heartbeat is stale")
+	info.Status, info.LastOutput = yoloai.StatusActive, time.Time{}
+	assert.Empty(t, formatActivity(info, now), "no heartbeat, nothing to say")
+}
@@ -4,12 +4,16 @@ package sandboxcmd
 // ABOUTME: top-level `yoloai ls` shortcut.
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"strings"
 	"text/tabwriter"
+	"time"
 
 	"github.com/kstenerud/yoloai/internal/cli/cliutil"
+	"github.com/kstenerud/yoloai/internal/daemonapi"
 
 	yoloai "github.com/kstenerud/yoloai"
 	"github.com/spf13/cobra"
@@ -46,6 +50,29 @@ func addListFlags(cmd *cobra.Command) {
 	cmd.Flags().String("agent", "", "Show only sandboxes using this agent")
 	cmd.Flags().String("profile", "", "Show only sandboxes using this profile")
 	cmd.Flags().Bool("changes", false, "Show only sandboxes with unapplied changes")
+	cmd.Flags().Bool("no-daemon", false, "Inspect the sandboxes directly even when a daemon is running")
+}
+
+// listSandboxes lists every sandbox across backends. A running daemon (see
+// `yoloai daemon run`) answers from the runtimes it keeps open, which spares
+// re-inspecting every instance; without one, or when it can't answer, the
+// sandboxes are listed directly.
+func listSandboxes(ctx context.Context, noDaemon bool) ([]*yoloai.SandboxInfo, []yoloai.BackendType, error) {
+	if !noDaemon {
+		l, err := daemonapi.NewClient(cliutil.CLIDaemonSocketPath()).Sandboxes(ctx)
+		if err == nil {
+			slog.Debug("listed through the daemon", "event", "sandbox.list.daemon", "count", len(l.Sandboxes))
+			return l.Sandboxes, l.UnavailableBackends, nil
+		}
+		if !errors.Is(err, daemonapi.ErrNoDaemon) {
+			slog.Debug("daemon could not list; listing directly", "event", "sandbox.list.daemon", "err", err)
+		}
+	}
+	sys, err := cliutil.System()
+	if err != nil {
+		return nil, nil, err
+	}
+	return sys.AllSandboxes(ctx)
 }
 
 // filterInfos applies the given filters to a slice of sandbox infos.
@@ -123,27 +150,55 @@ func formatProfile(profile string) string {
 	return profile
 }
 
-// statusCell renders the STATUS column for one sandbox. A running sandbox
-// whose guest network is confirmed dead (the tart vmnet wedge) gets a
-// "(net-dead)" qualifier; healthy and unprobed sandboxes render the bare
-// status so normal output stays unchanged.
+// quietAfter is how long a working agent must go without printing anything
+// before the listing points it out: long enough that a model thinking between
+// tool calls doesn't trip it.
+const quietAfter = 2 * time.Minute
+
+// statusCell renders the STATUS column for one sandbox. A running agent's
+// status says how long it has been quiet, from the monitor's output heartbeat
+// (see activityNote). A running sandbox whose guest network is confirmed dead
+// (the tart vmnet wedge) gets a "(net-dead)" qualifier, and a sandbox whose
+// agent reported a result gets that result's status in brackets; other
+// sandboxes render the bare status so normal output stays unchanged.
 func statusCell(info *yoloai.SandboxInfo) string {
+	cell := string(info.Status) + activityNote(info, time.Now())
 	if info.NetHealth == "wedged" {
-		return string(info.Status) + " (net-dead)"
+		cell += " (net-dead)"
+	}
+	if info.Result != nil {
+		cell += " [" + info.Result.Status + "]"
 	}
-	return string(info.Status)
+	return cell
+}
+
+// activityNote qualifies a running agent's status with how long it has gone
+// without output: an idle agent, waiting for input, has been idle " for 12m",
+// and a working one that has printed nothing for quietAfter is ", quiet 12m"
+// (it may be stuck). "" for every other sandbox, and for one with no heartbeat.
+func activityNote(info *yoloai.SandboxInfo, now time.Time) string {
+	if info.LastOutput.IsZero() {
+		return ""
+	}
+	quiet := max(now.Sub(info.LastOutput), 0)
+	switch info.Status {
+	case yoloai.StatusIdle:
+		return " for " + cliutil.FormatDuration(quiet)
+	case yoloai.StatusActive:
+		if quiet >= quietAfter {
+			return ", quiet " + cliutil.FormatDuration(quiet)
+		}
+	default:
+	}
+	return ""
 }
 
 // runList is the shared implementation for `sandbox list` and the `ls` alias.
 func runList(cmd *cobra.Command, _ []string) error {
 	ctx := cmd.Context()
 
-	// Use multi-backend listing
-	sys, err := cliutil.System()
-	if err != nil {
-		return err
-	}
-	infos, unavailableBackends, err := sys.AllSandboxes(ctx)
+	noDaemon, _ := cmd.Flags().GetBool("no-daemon")
+	infos, unavailableBackends, err := listSandboxes(ctx, noDaemon)
 	if err != nil {
 		return err
 	}
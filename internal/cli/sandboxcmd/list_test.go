@@ -1,14 +1,22 @@
 package sandboxcmd
 
-// ABOUTME: Unit tests for list command filtering and formatting helpers.
+// ABOUTME: Unit tests for list command filtering and formatting helpers, and
+// ABOUTME: for listing through a running daemon.
 
 import (
+	"context"
+	"net"
 	"testing"
 	"time"
 
 	yoloai "github.com/kstenerud/yoloai"
 	agentpkg "github.com/kstenerud/yoloai/internal/agent"
+	"github.com/kstenerud/yoloai/internal/cli/clitest"
+	"github.com/kstenerud/yoloai/internal/cli/cliutil"
+	"github.com/kstenerud/yoloai/internal/daemonapi"
+	"github.com/kstenerud/yoloai/internal/fileutil"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func makeInfo(name string, status yoloai.Status, agent, profile, changes string) *yoloai.SandboxInfo {
@@ -211,3 +219,66 @@ func TestStatusCell_UnprobedUnqualified(t *testing.T) {
 	info := makeInfo("a", yoloai.StatusStopped, "claude", "", "no")
 	assert.Equal(t, "stopped", statusCell(info))
 }
+
+func TestStatusCell_AgentResult(t *testing.T) {
+	info := makeInfo("a", yoloai.StatusDone, "claude", "", "yes")
+	info.Result = &yoloai.AgentResult{Status: yoloai.ResultStatusPartial}
+	assert.Equal(t, "done [partial]", statusCell(info))
+}
+
+func TestStatusCell_IdleSaysForHowLong(t *testing.T) {
+	info := makeInfo("a", yoloai.StatusIdle, "claude", "", "no")
+	info.LastOutput = time.Now().Add(-12*time.Minute - time.Second)
+	assert.Equal(t, "idle for 12m", statusCell(info))
+}
+
+func TestStatusCell_QuietActive(t *testing.T) {
+	info := makeInfo("a", yoloai.StatusActive, "claude", "", "no")
+	info.LastOutput = time.Now().Add(-12*time.Minute - time.Second)
+	assert.Equal(t, "active, quiet 12m", statusCell(info))
+
+	info.LastOutput = time.Now().Add(-30 * time.Second)
+	assert.Equal(t, "active", statusCell(info), "an agent that printed recently is just active")
+}
+
+func TestStatusCell_NoHeartbeatUnqualified(t *testing.T) {
+	info := makeInfo("a", yoloai.StatusIdle, "claude", "", "no")
+	assert.Equal(t, "idle", statusCell(info))
+}
+
+func TestStatusCell_StoppedIgnoresStaleHeartbeat(t *testing.T) {
+	info := makeInfo("a", yoloai.StatusStopped, "claude", "", "no")
+	info.LastOutput = time.Now().Add(-3 * time.Hour)
+	assert.Equal(t, "stopped", statusCell(info))
+}
+
+// A running daemon answers the listing; --no-daemon goes around it.
+func TestListSandboxes_ThroughDaemon(t *testing.T) {
+	clitest.Home(t)
+	path := cliutil.CLIDaemonSocketPath()
+	if len(path) > 100 {
+		t.Skip("temp dir too deep for a unix socket path")
+	}
+	require.NoError(t, fileutil.MkdirAll(cliutil.CLIDir(), 0o750))
+	ln, err := net.Listen("unix", path)
+	require.NoError(t, err)
+	h := daemonapi.NewHandler(func(context.Context) ([]*yoloai.SandboxInfo, []yoloai.BackendType, error) {
+		return []*yoloai.SandboxInfo{makeInfo("from-daemon", yoloai.StatusActive, "claude", "", "no")}, nil, nil
+	})
+	ctx, cancel := context.WithCancel(t.Context())
+	done := make(chan error, 1)
+	go func() { done <- daemonapi.Serve(ctx, ln, h) }()
+	t.Cleanup(func() {
+		cancel()
+		require.NoError(t, <-done)
+	})
+
+	infos, _, err := listSandboxes(t.Context(), false)
+	require.NoError(t, err)
+	require.Len(t, infos, 1)
+	assert.Equal(t, "from-daemon", infos[0].Environment.Name)
+
+	infos, _, err = listSandboxes(t.Context(), true)
+	require.NoError(t, err)
+	assert.Empty(t, infos, "--no-daemon lists the (empty) data dir itself")
+}
@@ -2,12 +2,14 @@ package sandboxcmd
 
 // ABOUTME: Sandbox log display: pretty-prints the structured JSONL frames the
 // ABOUTME: library's activity stream (System.Logs) delivers, with optional
-// ABOUTME: follow, level/source/since filtering, raw passthrough, and agent output.
+// ABOUTME: follow, level/source/since/grep filtering, raw passthrough, and agent output.
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"io"
+	"regexp"
 	"strings"
 	"time"
 
@@ -24,6 +26,14 @@ var sourceLabels = map[yoloai.LogSource]string{
 	yoloai.LogSourceSandbox: "sandbox",
 	yoloai.LogSourceMonitor: "monitor",
 	yoloai.LogSourceHooks:   "hooks  ",
+	yoloai.LogSourceRuntime: "runtime",
+}
+
+// defaultLogSources is what the CLI shows without --source: the sandbox's own
+// logs plus the container/VM runtime's, in one stream.
+var defaultLogSources = []yoloai.LogSource{
+	yoloai.LogSourceCLI, yoloai.LogSourceSandbox, yoloai.LogSourceMonitor,
+	yoloai.LogSourceHooks, yoloai.LogSourceRuntime,
 }
 
 // logRecord is a parsed JSONL log entry, decomposed for pretty-printing. The
@@ -139,10 +149,10 @@ func parseSince(s string) (time.Time, error) {
 }
 
 // parseSourceFlag turns the --source value into a LogSource list. Empty means
-// all sources (nil). Unknown keys are silently dropped, matching prior behavior.
+// defaultLogSources. Unknown keys are silently dropped, matching prior behavior.
 func parseSourceFlag(sourceFlag string) []yoloai.LogSource {
 	if sourceFlag == "" {
-		return nil
+		return defaultLogSources
 	}
 	var result []yoloai.LogSource
 	for k := range strings.SplitSeq(sourceFlag, ",") {
@@ -196,8 +206,62 @@ func formatRecord(rec logRecord, width int) string {
 	return prefix + rest
 }
 
+// parseGrep compiles the --grep pattern. Empty means no filter (nil).
+func parseGrep(pattern string) (*regexp.Regexp, error) {
+	if pattern == "" {
+		return nil, nil
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, yoerrors.NewUsageError("--grep: %v", err)
+	}
+	return re, nil
+}
+
+// grepWriter passes through only the lines matching re (all of them when re is
+// nil), so --grep applies to every output mode alike.
+type grepWriter struct {
+	w       io.Writer
+	re      *regexp.Regexp
+	partial []byte
+}
+
+func (g *grepWriter) Write(p []byte) (int, error) {
+	if g.re == nil {
+		return g.w.Write(p)
+	}
+	g.partial = append(g.partial, p...)
+	for {
+		i := bytes.IndexByte(g.partial, '\n')
+		if i < 0 {
+			return len(p), nil
+		}
+		line := g.partial[:i+1]
+		g.partial = g.partial[i+1:]
+		if g.re.Match(line[:i]) {
+			if _, err := g.w.Write(line); err != nil {
+				return 0, err
+			}
+		}
+	}
+}
+
+// Flush writes a final unterminated line if it matches.
+func (g *grepWriter) Flush() error {
+	if g.re == nil || len(g.partial) == 0 {
+		return nil
+	}
+	line := g.partial
+	g.partial = nil
+	if g.re.Match(line) {
+		_, err := g.w.Write(line)
+		return err
+	}
+	return nil
+}
+
 // runLogStructured consumes the library activity stream and renders it.
-func runLogStructured(cmd *cobra.Command, name string, opts yoloai.AgentLogsOptions, rawMode bool) error {
+func runLogStructured(cmd *cobra.Command, name string, opts yoloai.AgentLogsOptions, rawMode bool, out io.Writer) error {
 	c, err := cliutil.Client(cmd)
 	if err != nil {
 		return err
@@ -213,7 +277,6 @@ func runLogStructured(cmd *cobra.Command, name string, opts yoloai.AgentLogsOpti
 	}
 
 	width := terminalWidth()
-	out := cmd.OutOrStdout()
 	printed := 0
 	for ev := range events {
 		printed++
@@ -230,13 +293,13 @@ func runLogStructured(cmd *cobra.Command, name string, opts yoloai.AgentLogsOpti
 	}
 
 	if printed == 0 && !opts.Follow {
-		fmt.Fprintln(out, "No log entries found.") //nolint:errcheck
+		fmt.Fprintln(cmd.OutOrStdout(), "No log entries found.") //nolint:errcheck
 	}
 	return nil
 }
 
 // runLogAgent shows the raw agent terminal output (logs/agent.log).
-func runLogAgent(cmd *cobra.Command, name string, rawMode bool) error {
+func runLogAgent(cmd *cobra.Command, name string, rawMode bool, out io.Writer) error {
 	c, err := cliutil.Client(cmd)
 	if err != nil {
 		return err
@@ -256,10 +319,10 @@ func runLogAgent(cmd *cobra.Command, name string, rawMode bool) error {
 	}
 
 	if rawMode {
-		_, err = io.WriteString(cmd.OutOrStdout(), output)
+		_, err = io.WriteString(out, output)
 		return err
 	}
-	return stripANSI(cmd.OutOrStdout(), strings.NewReader(output))
+	return cliutil.StripANSI(out, strings.NewReader(output))
 }
 
 // runLog is the shared implementation for `sandbox log` and the `log` alias.
@@ -276,10 +339,20 @@ func runLog(cmd *cobra.Command, args []string) error {
 	levelFlag, _ := cmd.Flags().GetString("level")
 	sinceFlag, _ := cmd.Flags().GetString("since")
 	followFlag, _ := cmd.Flags().GetBool("follow")
+	grepFlag, _ := cmd.Flags().GetString("grep")
+
+	re, err := parseGrep(grepFlag)
+	if err != nil {
+		return err
+	}
+	out := &grepWriter{w: cmd.OutOrStdout(), re: re}
 
 	// Agent output modes are mutually exclusive with structured log options.
 	if agentFlag || agentRawFlag {
-		return runLogAgent(cmd, name, agentRawFlag)
+		if err := runLogAgent(cmd, name, agentRawFlag, out); err != nil {
+			return err
+		}
+		return out.Flush()
 	}
 
 	var sinceTime time.Time
@@ -296,5 +369,8 @@ func runLog(cmd *cobra.Command, args []string) error {
 		Since:    sinceTime,
 		Follow:   followFlag,
 	}
-	return runLogStructured(cmd, name, opts, rawFlag)
+	if err := runLogStructured(cmd, name, opts, rawFlag, out); err != nil {
+		return err
+	}
+	return out.Flush()
 }
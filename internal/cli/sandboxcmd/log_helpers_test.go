@@ -1,14 +1,16 @@
 // ABOUTME: Tests for log-command internals: --since parsing (duration or
 // ABOUTME: clock time), level-code formatting, --source key parsing, JSONL
-// ABOUTME: record parsing with timestamp fallback, and line formatting.
+// ABOUTME: record parsing with timestamp fallback, line formatting, and --grep.
 package sandboxcmd
 
 import (
+	"bytes"
 	"strings"
 	"testing"
 	"time"
 
 	"github.com/kstenerud/yoloai"
+	"github.com/kstenerud/yoloai/yoerrors"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -81,9 +83,43 @@ func TestLevelCode_UnknownShort(t *testing.T) {
 
 // --- parseSourceFlag tests ---
 
-func TestParseSourceFlag_Empty_ReturnsNilMeaningAll(t *testing.T) {
-	// Empty flag yields nil; the library treats nil Sources as "all sources".
-	assert.Nil(t, parseSourceFlag(""))
+func TestParseSourceFlag_Empty_ReturnsDefaultSources(t *testing.T) {
+	// Empty flag yields every source, the runtime's log included — the library
+	// only streams LogSourceRuntime when it is named.
+	sources := parseSourceFlag("")
+	assert.Contains(t, sources, yoloai.LogSourceRuntime)
+	assert.Len(t, sources, len(sourceLabels))
+}
+
+func TestParseSourceFlag_Runtime(t *testing.T) {
+	assert.Equal(t, []yoloai.LogSource{yoloai.LogSourceRuntime}, parseSourceFlag("runtime"))
+}
+
+// --- grep tests ---
+
+func TestParseGrep_InvalidIsUsageError(t *testing.T) {
+	_, err := parseGrep("(")
+	var ue *yoerrors.UsageError
+	assert.ErrorAs(t, err, &ue)
+}
+
+func TestGrepWriter_FiltersLinesAcrossWrites(t *testing.T) {
+	re, err := parseGrep("err")
+	require.NoError(t, err)
+	var buf bytes.Buffer
+	g := &grepWriter{w: &buf, re: re}
+	_, _ = g.Write([]byte("ok one\nan er"))
+	_, _ = g.Write([]byte("ror\nok two\ntrailing error"))
+	require.NoError(t, g.Flush())
+	assert.Equal(t, "an error\ntrailing error", buf.String())
+}
+
+func TestGrepWriter_NoPatternPassesThrough(t *testing.T) {
+	var buf bytes.Buffer
+	g := &grepWriter{w: &buf}
+	_, _ = g.Write([]byte("a\nb"))
+	require.NoError(t, g.Flush())
+	assert.Equal(t, "a\nb", buf.String())
 }
 
 func TestParseSourceFlag_SingleKey(t *testing.T) {
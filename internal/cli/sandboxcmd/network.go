@@ -0,0 +1,310 @@
+// ABOUTME: `yoloai network` — network-policy commands: `network allow` widens a
+// ABOUTME: sandbox's allowlist live, `network log` shows its recorded egress,
+// ABOUTME: `network refresh` re-resolves isolated allowlists.
+package sandboxcmd
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/kstenerud/yoloai"
+	"github.com/kstenerud/yoloai/internal/cli/cliutil"
+	"github.com/kstenerud/yoloai/yoerrors"
+
+	"github.com/spf13/cobra"
+)
+
+func NewNetworkCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "network",
+		Short: "Manage network-isolated sandboxes' allowlists",
+		Long: `Manage the allowlists of network-isolated sandboxes.
+
+'yoloai sandbox <name> allowed' and 'deny' show and shrink one sandbox's list;
+'network log' shows what the agent tried to reach.`,
+		GroupID: cliutil.GroupSandboxTools,
+	}
+	cmd.AddCommand(newNetworkAllowCmd(), newNetworkLogCmd(), newNetworkRefreshCmd())
+	return cmd
+}
+
+func newNetworkAllowCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "allow <name> <domain>...",
+		Short: "Allow more domains in an isolated sandbox, live",
+		Long: `Allow more domains in a network-isolated sandbox.
+
+The domains are added to the sandbox's saved allowlist. When the sandbox is
+running, its firewall is patched in place: each domain is resolved and its
+addresses admitted, without restarting or recreating the container, so the
+agent keeps going. A stopped sandbox picks them up on its next start.
+
+Domains already allowed are skipped. The same as 'yoloai sandbox <name> allow'.`,
+		Example: cliutil.CommandExamples("network allow"),
+		Args:    cobra.MinimumNArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := cliutil.ValidateName(args[0]); err != nil {
+				return err
+			}
+			return runSandboxAllow(cmd, args[0], args[1:])
+		},
+	}
+}
+
+// defaultEgressSince is how far back `network log` looks without --since.
+const defaultEgressSince = 24 * time.Hour
+
+func newNetworkLogCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "log <name>",
+		Short: "Show where an isolated sandbox's agent tried to connect",
+		Long: `Show the HTTP(S) destinations a network-isolated sandbox's agent asked for.
+
+An isolated sandbox routes its HTTP(S) traffic through a small audit proxy
+inside the container, which records each destination and whether the
+firewall let it through. 'log' summarizes that record per destination:
+how often, with what verdict, and when last. Use it to see what the agent
+needed that the allowlist refused, then 'yoloai network allow' it.
+
+The proxy only sees programs that honour HTTP_PROXY/HTTPS_PROXY; anything
+else is still held by the firewall, just not recorded. With --json, every
+event is listed individually.`,
+		Example: cliutil.CommandExamples("network log"),
+		Args:    cobra.ExactArgs(1),
+		RunE:    runNetworkLog,
+	}
+	cmd.Flags().Duration("since", defaultEgressSince, "How far back to look")
+	cmd.Flags().Bool("blocked", false, "Show only requests the firewall refused")
+	return cmd
+}
+
+func runNetworkLog(cmd *cobra.Command, args []string) error {
+	name := args[0]
+	if err := cliutil.ValidateName(name); err != nil {
+		return err
+	}
+	since, _ := cmd.Flags().GetDuration("since")
+	if since <= 0 {
+		return yoerrors.NewUsageError("invalid --since %s: want a positive duration (e.g. 30m, 2h)", since)
+	}
+	blockedOnly, _ := cmd.Flags().GetBool("blocked")
+
+	return cliutil.WithSandbox(cmd, name, func(_ context.Context, sb *yoloai.Sandbox) error {
+		events, err := sb.Network().Egress(time.Now().Add(-since))
+		if err != nil {
+			return err
+		}
+		if blockedOnly {
+			kept := events[:0]
+			for _, e := range events {
+				if e.Verdict == yoloai.EgressBlocked {
+					kept = append(kept, e)
+				}
+			}
+			events = kept
+		}
+		if cliutil.JSONEnabled(cmd) {
+			if events == nil {
+				events = []yoloai.EgressEvent{}
+			}
+			return cliutil.WriteJSONList(cmd.OutOrStdout(), "egress", events)
+		}
+		return printEgressSummary(cmd, summarizeEgress(events), since)
+	})
+}
+
+// egressDest is one destination's tally in the `network log` summary.
+type egressDest struct {
+	Host     string
+	Port     int
+	Verdict  string
+	Count    int
+	LastSeen time.Time
+}
+
+// summarizeEgress groups events by destination and verdict, blocked first,
+// then most requested.
+func summarizeEgress(events []yoloai.EgressEvent) []egressDest {
+	type key struct {
+		host    string
+		port    int
+		verdict string
+	}
+	byKey := map[key]*egressDest{}
+	var out []*egressDest
+	for _, e := range events {
+		k := key{e.Host, e.Port, e.Verdict}
+		d := byKey[k]
+		if d == nil {
+			d = &egressDest{Host: e.Host, Port: e.Port, Verdict: e.Verdict}
+			byKey[k] = d
+			out = append(out, d)
+		}
+		d.Count++
+		if e.Time.After(d.LastSeen) {
+			d.LastSeen = e.Time
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		bi, bj := out[i].Verdict == yoloai.EgressBlocked, out[j].Verdict == yoloai.EgressBlocked
+		if bi != bj {
+			return bi
+		}
+		return out[i].Count > out[j].Count
+	})
+	dests := make([]egressDest, len(out))
+	for i, d := range out {
+		dests[i] = *d
+	}
+	return dests
+}
+
+// printEgressSummary renders the per-destination summary.
+func printEgressSummary(cmd *cobra.Command, dests []egressDest, since time.Duration) error {
+	out := cmd.OutOrStdout()
+	if len(dests) == 0 {
+		_, err := fmt.Fprintf(out, "No recorded egress in the last %s\n", since)
+		return err
+	}
+	for _, d := range dests {
+		fmt.Fprintf(out, "%-8s %5d  %s  %s:%d\n", d.Verdict, d.Count, d.LastSeen.Local().Format("Jan 02 15:04"), d.Host, d.Port) //nolint:errcheck // best-effort output
+	}
+	return nil
+}
+
+func newNetworkRefreshCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "refresh [name]...",
+		Short: "Re-resolve the allowed domains of running isolated sandboxes",
+		Long: `Re-resolve the allowed domains of running network-isolated sandboxes.
+
+An isolated sandbox's firewall admits the addresses its allowed domains had
+when it started. CDN-hosted APIs move between addresses within hours, so a
+long-lived sandbox gradually loses the endpoints it was allowed. 'refresh'
+looks every domain up again and replaces the admitted addresses with the
+answers, in one atomic swap; if no domain resolves, the current rules stay.
+
+Without names, every running isolated sandbox on every backend is refreshed.
+'yoloai daemon' runs this on each sweep, so with the daemon running there is
+nothing to do by hand.`,
+		Example: cliutil.CommandExamples("network refresh"),
+		Args:    cobra.ArbitraryArgs,
+		RunE:    runNetworkRefresh,
+	}
+}
+
+// refreshResult is one sandbox's outcome, shared by the human and JSON renderings.
+type refreshResult struct {
+	Name    string `json:"name"`
+	Domains int    `json:"domains"`
+	Error   string `json:"error,omitempty"`
+}
+
+func runNetworkRefresh(cmd *cobra.Command, args []string) error {
+	for _, name := range args {
+		if err := cliutil.ValidateName(name); err != nil {
+			return err
+		}
+	}
+	sys, err := cliutil.System()
+	if err != nil {
+		return err
+	}
+	infos, unavailable, err := sys.AllSandboxesComplete(cmd.Context())
+	if err != nil {
+		return err
+	}
+	for _, b := range unavailable {
+		fmt.Fprintf(os.Stderr, "Warning: backend %s is unavailable; its sandboxes were skipped\n", b)
+	}
+
+	byBackend := refreshTargets(infos, args)
+	backends := make([]yoloai.BackendType, 0, len(byBackend))
+	for b := range byBackend {
+		backends = append(backends, b)
+	}
+	sort.Slice(backends, func(i, j int) bool { return backends[i] < backends[j] })
+
+	var results []refreshResult
+	for _, backend := range backends {
+		names := byBackend[backend]
+		err := cliutil.WithClient(cmd, backend, func(ctx context.Context, c *yoloai.Client) error {
+			for _, name := range names {
+				results = append(results, refreshSandbox(ctx, c, name))
+			}
+			return nil
+		})
+		if err != nil {
+			for _, name := range names {
+				results = append(results, refreshResult{Name: name, Error: err.Error()})
+			}
+		}
+	}
+	return reportRefresh(cmd, results)
+}
+
+// refreshTargets selects the running network-isolated sandboxes, grouped by
+// backend. When only is non-empty, selection is limited to those names.
+func refreshTargets(infos []*yoloai.SandboxInfo, only []string) map[yoloai.BackendType][]string {
+	want := make(map[string]bool, len(only))
+	for _, name := range only {
+		want[name] = true
+	}
+	out := map[yoloai.BackendType][]string{}
+	for _, info := range infos {
+		if info.Environment == nil || info.NetworkMode != yoloai.NetworkModeIsolated {
+			continue
+		}
+		name := info.Environment.Name
+		if len(want) > 0 && !want[name] {
+			continue
+		}
+		if info.Status == yoloai.StatusActive || info.Status == yoloai.StatusIdle {
+			out[info.Environment.BackendType] = append(out[info.Environment.BackendType], name)
+		}
+	}
+	return out
+}
+
+// refreshSandbox re-resolves one sandbox's allowlist.
+func refreshSandbox(ctx context.Context, c *yoloai.Client, name string) refreshResult {
+	sb, err := c.Sandbox(name)
+	if err != nil {
+		return refreshResult{Name: name, Error: err.Error()}
+	}
+	res, err := sb.Network().Refresh(ctx)
+	if err != nil {
+		return refreshResult{Name: name, Error: err.Error()}
+	}
+	slog.Info("allowlist refreshed", "event", "sandbox.network.refresh", "sandbox", name, "domains", len(res.Domains), "live", res.Live)
+	return refreshResult{Name: name, Domains: len(res.Domains)}
+}
+
+// reportRefresh renders the results and returns an error if any sandbox failed.
+func reportRefresh(cmd *cobra.Command, results []refreshResult) error {
+	if cliutil.JSONEnabled(cmd) {
+		return cliutil.WriteJSONList(cmd.OutOrStdout(), "refreshed", results)
+	}
+	out := cmd.OutOrStdout()
+	if len(results) == 0 {
+		_, err := fmt.Fprintln(out, "No running network-isolated sandboxes to refresh")
+		return err
+	}
+	failed := 0
+	for _, r := range results {
+		if r.Error != "" {
+			failed++
+			fmt.Fprintf(os.Stderr, "Warning: refresh %s: %s\n", r.Name, r.Error)
+			continue
+		}
+		fmt.Fprintf(out, "Refreshed %s (%d domains)\n", r.Name, r.Domains) //nolint:errcheck // best-effort output
+	}
+	if failed > 0 {
+		return fmt.Errorf("failed to refresh %d sandbox(es)", failed)
+	}
+	return nil
+}
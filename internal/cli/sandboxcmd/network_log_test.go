@@ -0,0 +1,27 @@
+// ABOUTME: Tests for `yoloai network log`: the per-destination summary of
+// ABOUTME: recorded egress (blocked first, then most requested).
+package sandboxcmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kstenerud/yoloai"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSummarizeEgress(t *testing.T) {
+	t0 := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	events := []yoloai.EgressEvent{
+		{Time: t0, Host: "api.example", Port: 443, Verdict: yoloai.EgressAllowed},
+		{Time: t0.Add(time.Minute), Host: "cdn.example", Port: 443, Verdict: yoloai.EgressBlocked},
+		{Time: t0.Add(2 * time.Minute), Host: "api.example", Port: 443, Verdict: yoloai.EgressAllowed},
+		{Time: t0.Add(3 * time.Minute), Host: "pypi.org", Port: 443, Verdict: yoloai.EgressAllowed},
+	}
+	assert.Equal(t, []egressDest{
+		{Host: "cdn.example", Port: 443, Verdict: yoloai.EgressBlocked, Count: 1, LastSeen: t0.Add(time.Minute)},
+		{Host: "api.example", Port: 443, Verdict: yoloai.EgressAllowed, Count: 2, LastSeen: t0.Add(2 * time.Minute)},
+		{Host: "pypi.org", Port: 443, Verdict: yoloai.EgressAllowed, Count: 1, LastSeen: t0.Add(3 * time.Minute)},
+	}, summarizeEgress(events))
+	assert.Empty(t, summarizeEgress(nil))
+}
@@ -0,0 +1,36 @@
+// ABOUTME: Tests for `yoloai network refresh`: which sandboxes it selects
+// ABOUTME: (running and network-isolated, optionally by name).
+package sandboxcmd
+
+import (
+	"testing"
+
+	"github.com/kstenerud/yoloai"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRefreshTargets(t *testing.T) {
+	info := func(name string, backend yoloai.BackendType, mode yoloai.NetworkMode, st yoloai.Status) *yoloai.SandboxInfo {
+		return &yoloai.SandboxInfo{
+			Environment: &yoloai.Environment{Name: name, BackendType: backend},
+			NetworkMode: mode,
+			Status:      st,
+		}
+	}
+	infos := []*yoloai.SandboxInfo{
+		info("busy", "docker", yoloai.NetworkModeIsolated, yoloai.StatusActive),
+		info("quiet", "podman", yoloai.NetworkModeIsolated, yoloai.StatusIdle),
+		info("stopped", "docker", yoloai.NetworkModeIsolated, yoloai.StatusStopped),
+		info("open", "docker", "", yoloai.StatusActive),
+		info("offline", "docker", yoloai.NetworkModeNone, yoloai.StatusActive),
+		{Status: yoloai.StatusBroken},
+	}
+
+	assert.Equal(t, map[yoloai.BackendType][]string{
+		"docker": {"busy"},
+		"podman": {"quiet"},
+	}, refreshTargets(infos, nil))
+	assert.Equal(t, map[yoloai.BackendType][]string{
+		"podman": {"quiet"},
+	}, refreshTargets(infos, []string{"quiet", "stopped"}))
+}
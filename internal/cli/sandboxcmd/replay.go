@@ -0,0 +1,84 @@
+// ABOUTME: `yoloai replay <name>` — play back a sandbox's recorded agent terminal
+// ABOUTME: (logs/agent.cast) with its original timing, sped up and long pauses cut.
+package sandboxcmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/kstenerud/yoloai"
+	"github.com/kstenerud/yoloai/internal/asciicast"
+	"github.com/kstenerud/yoloai/internal/cli/cliutil"
+	"github.com/kstenerud/yoloai/yoerrors"
+	"github.com/spf13/cobra"
+)
+
+func NewReplayCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "replay <name>",
+		Short: "Play back a recording of the sandbox's agent session",
+		Long: `Play back what the sandbox's agent printed, with its original timing.
+
+Every sandbox records its agent's terminal to logs/agent.cast, an asciicast v2
+file that also plays in asciinema (asciinema play <file>). A restarted sandbox
+keeps adding to the same recording. The recording is at the sandbox terminal's
+size, 200x50; in a smaller window, lines wrap.
+
+Pauses longer than --max-idle are cut short, so the time the agent sat waiting
+for input doesn't replay in full. Press Ctrl+C to stop.`,
+		GroupID: cliutil.GroupSandboxTools,
+		Args:    cobra.ArbitraryArgs,
+		RunE:    runReplay,
+	}
+
+	cmd.Flags().Float64("speed", 1, "Playback speed (2 plays twice as fast)")
+	cmd.Flags().Duration("max-idle", 2*time.Second, "Cut pauses longer than this short (0 keeps every pause)")
+
+	return cmd
+}
+
+func runReplay(cmd *cobra.Command, args []string) error {
+	if cliutil.JSONEnabled(cmd) {
+		return cliutil.ErrJSONNotSupported("replay")
+	}
+	name, _, err := cliutil.ResolveName(cmd, args)
+	if err != nil {
+		return err
+	}
+	speed, _ := cmd.Flags().GetFloat64("speed")
+	if speed <= 0 {
+		return yoerrors.NewUsageError("--speed: must be greater than 0")
+	}
+	maxIdle, _ := cmd.Flags().GetDuration("max-idle")
+	if maxIdle < 0 {
+		return yoerrors.NewUsageError("--max-idle: must not be negative")
+	}
+
+	return cliutil.WithSandbox(cmd, name, func(ctx context.Context, sb *yoloai.Sandbox) error {
+		rec, err := sb.Agent().TerminalRecording()
+		if err != nil {
+			return err
+		}
+		if rec == nil {
+			fmt.Fprintln(cmd.OutOrStdout(), "No recording for this sandbox yet") //nolint:errcheck // best-effort output
+			return nil
+		}
+		defer rec.Close() //nolint:errcheck // read-only file
+
+		err = asciicast.Play(ctx, rec, cmd.OutOrStdout(), asciicast.PlayOptions{Speed: speed, MaxIdle: maxIdle})
+		resetTerminal(cmd.OutOrStdout())
+		if errors.Is(err, context.Canceled) {
+			return nil
+		}
+		return err
+	})
+}
+
+// resetTerminal undoes what the replayed output may have left set: colors and
+// attributes, a hidden cursor, the alternate screen.
+func resetTerminal(w io.Writer) {
+	fmt.Fprint(w, "\x1b[0m\x1b[?25h\x1b[?1049l\r\n") //nolint:errcheck // best-effort output
+}
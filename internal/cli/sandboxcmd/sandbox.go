@@ -1,7 +1,7 @@
 // ABOUTME: `yoloai sandbox` parent command with name-first dispatch.
 // ABOUTME: `list` is a real Cobra subcommand; everything else dispatched by RunE.
 // ABOUTME: Subcommands: list, info, log, exec, prompt, allow, allowed, deny,
-// ABOUTME: bugreport, vscode, unlock, terminal-snapshot.
+// ABOUTME: bugreport, vscode, unlock, terminal-snapshot, denials.
 package sandboxcmd
 
 import (
@@ -14,9 +14,9 @@ import (
 // sandboxSubcmds is the set of known sandbox subcommands dispatched by RunE.
 // "list" is excluded — it's a real Cobra subcommand.
 var sandboxSubcmds = map[string]bool{
-	"info": true, "log": true, "exec": true, "prompt": true,
+	"info": true, "log": true, "logs": true, "exec": true, "prompt": true,
 	"allow": true, "allowed": true, "deny": true, "bugreport": true,
-	"vscode": true, "unlock": true, "terminal-snapshot": true,
+	"vscode": true, "unlock": true, "terminal-snapshot": true, "denials": true,
 }
 
 func NewSandboxCmd() *cobra.Command {
@@ -52,7 +52,8 @@ Commands:
   <name> bugreport [safe|unsafe]  Write a bug report for the sandbox
   <name> vscode                   Open sandbox in VS Code (attach-to-container)
   <name> unlock                   Force-clear a stale lock file (rare)
-  <name> terminal-snapshot [--ansi]  Capture the agent's rendered tmux pane (DF3){{if gt (len .Aliases) 0}}
+  <name> terminal-snapshot [--ansi]  Capture the agent's rendered tmux pane (DF3)
+  <name> denials [--since DUR]       List file writes the sandbox refused (seatbelt){{if gt (len .Aliases) 0}}
 
 Aliases:
   {{.NameAndAliases}}{{end}}{{if .HasAvailableLocalFlags}}
@@ -92,7 +93,7 @@ func runSandboxSubcommand(cmd *cobra.Command, subcmd, name string, rest []string
 	switch subcmd {
 	case "info":
 		return runSandboxInfo(cmd, name)
-	case "log":
+	case "log", "logs":
 		// Re-inject name into args so runLog can call ResolveName internally
 		return runLog(cmd, append([]string{name}, rest...))
 	case "exec":
@@ -118,8 +119,10 @@ func runSandboxSubcommand(cmd *cobra.Command, subcmd, name string, rest []string
 		return runSandboxUnlock(cmd, name)
 	case "terminal-snapshot":
 		return runTerminalSnapshot(cmd, name, rest)
+	case "denials":
+		return runSandboxDenials(cmd, name, rest)
 	default:
-		return yoerrors.NewUsageError("unknown subcommand %q: valid subcommands are info, log, exec, prompt, allow, allowed, deny, bugreport, vscode, unlock, terminal-snapshot", subcmd)
+		return yoerrors.NewUsageError("unknown subcommand %q: valid subcommands are info, log, exec, prompt, allow, allowed, deny, bugreport, vscode, unlock, terminal-snapshot, denials", subcmd)
 	}
 }
 
@@ -141,7 +144,7 @@ func resolveSandboxDispatchArgs(args []string) (name, subcmd string, rest []stri
 		return "", "", nil, err
 	}
 	if len(args) < 2 {
-		return "", "", nil, yoerrors.NewUsageError("subcommand required: info, log, exec, prompt, allow, allowed, deny, bugreport, vscode, unlock, terminal-snapshot")
+		return "", "", nil, yoerrors.NewUsageError("subcommand required: info, log, exec, prompt, allow, allowed, deny, bugreport, vscode, unlock, terminal-snapshot, denials")
 	}
 	return args[0], args[1], args[2:], nil
 }
@@ -0,0 +1,165 @@
+// ABOUTME: `yoloai statusline` — a one-line count of sandboxes by status for a
+// ABOUTME: tmux status bar or shell prompt, served from a short-lived cache.
+package sandboxcmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/kstenerud/yoloai"
+	"github.com/kstenerud/yoloai/internal/cli/cliutil"
+	"github.com/kstenerud/yoloai/internal/fileutil"
+	"github.com/kstenerud/yoloai/yoerrors"
+	"github.com/spf13/cobra"
+)
+
+// statuslineCategories are the statuses a status line counts, in the order
+// it shows them. Stopped and removed sandboxes aren't news.
+var statuslineCategories = []struct {
+	status yoloai.Status
+	label  string
+}{
+	{yoloai.StatusActive, "running"},
+	{yoloai.StatusIdle, "idle"},
+	{yoloai.StatusDone, "done"},
+	{yoloai.StatusFailed, "failed"},
+	{yoloai.StatusPaused, "paused"},
+}
+
+// statuslineSummary is what a status line shows, and what is cached between
+// calls in TOP/cli/statusline.json.
+type statuslineSummary struct {
+	Updated time.Time      `json:"updated"`
+	Counts  map[string]int `json:"counts"`  // sandboxes per category label
+	Changes map[string]int `json:"changes"` // of those, how many have unapplied changes
+}
+
+func NewStatuslineCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "statusline",
+		Short: "Print a one-line sandbox summary for a tmux status bar or prompt",
+		Long: `Print a one-line count of sandboxes by status, such as
+
+  3 running, 1 done★, 1 failed
+
+for a tmux status bar, a starship custom module or a shell prompt. A ★ marks
+a status where some sandbox has changes not yet applied. Stopped sandboxes
+aren't counted, and with nothing to report the line is empty.
+
+Listing every sandbox takes a moment, so the summary is cached and reused
+while it is younger than --max-age; a status bar can call this every few
+seconds. --max-age 0 always looks afresh.`,
+		Example: `  yoloai statusline
+  # ~/.tmux.conf
+  set -g status-right '#(yoloai statusline) %H:%M'
+  set -g status-interval 5`,
+		GroupID: cliutil.GroupSandboxTools,
+		Args:    cobra.NoArgs,
+		RunE:    runStatusline,
+	}
+	cmd.Flags().Duration("max-age", 5*time.Second, "Reuse a cached summary younger than this")
+	cmd.Flags().Bool("ascii", false, "Mark unapplied changes with * instead of ★")
+	return cmd
+}
+
+func runStatusline(cmd *cobra.Command, _ []string) error {
+	maxAge, _ := cmd.Flags().GetDuration("max-age")
+	ascii, _ := cmd.Flags().GetBool("ascii")
+	if maxAge < 0 {
+		return yoerrors.NewUsageError("--max-age can't be negative: %s", maxAge)
+	}
+
+	path := filepath.Join(cliutil.CLIDir(), "statusline.json")
+	summary, ok := readStatuslineCache(path, maxAge)
+	if !ok {
+		sys, err := cliutil.System()
+		if err != nil {
+			return err
+		}
+		infos, _, err := sys.AllSandboxes(cmd.Context())
+		if err != nil {
+			return err
+		}
+		summary = summarizeStatusline(infos, time.Now())
+		// A cache that can't be written only costs the next call a listing.
+		if err := fileutil.MkdirAll(cliutil.CLIDir(), 0o750); err == nil {
+			fileutil.AtomicWriteJSON(path, summary, 0o600) //nolint:errcheck // best-effort cache
+		}
+	}
+
+	if cliutil.JSONEnabled(cmd) {
+		return cliutil.WriteJSON(cmd.OutOrStdout(), map[string]any{
+			"updated": summary.Updated,
+			"counts":  summary.Counts,
+			"changes": summary.Changes,
+			"text":    summary.render("★"),
+		})
+	}
+	mark := "★"
+	if ascii {
+		mark = "*"
+	}
+	if line := summary.render(mark); line != "" {
+		_, err := fmt.Fprintln(cmd.OutOrStdout(), line)
+		return err
+	}
+	return nil
+}
+
+// readStatuslineCache returns the cached summary if it is younger than
+// maxAge.
+func readStatuslineCache(path string, maxAge time.Duration) (*statuslineSummary, bool) {
+	if maxAge == 0 {
+		return nil, false
+	}
+	data, err := os.ReadFile(path) //nolint:gosec // G304: the CLI's own cache file
+	if err != nil {
+		return nil, false
+	}
+	var s statuslineSummary
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, false
+	}
+	if age := time.Since(s.Updated); age < 0 || age >= maxAge {
+		return nil, false
+	}
+	return &s, true
+}
+
+func summarizeStatusline(infos []*yoloai.SandboxInfo, now time.Time) *statuslineSummary {
+	s := &statuslineSummary{Updated: now, Counts: map[string]int{}, Changes: map[string]int{}}
+	for _, info := range infos {
+		for _, c := range statuslineCategories {
+			if info.Status != c.status {
+				continue
+			}
+			s.Counts[c.label]++
+			if info.Changes == yoloai.ChangesPresent {
+				s.Changes[c.label]++
+			}
+		}
+	}
+	return s
+}
+
+// render is the status line: "3 running, 1 done★, 1 failed", or "" when
+// nothing is counted.
+func (s *statuslineSummary) render(mark string) string {
+	var parts []string
+	for _, c := range statuslineCategories {
+		n := s.Counts[c.label]
+		if n == 0 {
+			continue
+		}
+		part := fmt.Sprintf("%d %s", n, c.label)
+		if s.Changes[c.label] > 0 {
+			part += mark
+		}
+		parts = append(parts, part)
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,69 @@
+package sandboxcmd
+
+// ABOUTME: Unit tests for `statusline`: counting by status, the changes mark,
+// ABOUTME: and serving the line from a cache younger than --max-age.
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+	"time"
+
+	yoloai "github.com/kstenerud/yoloai"
+	"github.com/kstenerud/yoloai/internal/cli/clitest"
+	"github.com/kstenerud/yoloai/internal/cli/cliutil"
+	"github.com/kstenerud/yoloai/internal/fileutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSummarizeStatusline(t *testing.T) {
+	info := func(st yoloai.Status, ch yoloai.ChangeState) *yoloai.SandboxInfo {
+		return &yoloai.SandboxInfo{Status: st, Changes: ch}
+	}
+	s := summarizeStatusline([]*yoloai.SandboxInfo{
+		info(yoloai.StatusActive, yoloai.ChangesAbsent),
+		info(yoloai.StatusActive, yoloai.ChangesPresent),
+		info(yoloai.StatusActive, yoloai.ChangesAbsent),
+		info(yoloai.StatusDone, yoloai.ChangesPresent),
+		info(yoloai.StatusFailed, yoloai.ChangesAbsent),
+		info(yoloai.StatusStopped, yoloai.ChangesPresent),
+		info(yoloai.StatusBroken, yoloai.ChangesNotApplicable),
+	}, time.Now())
+	assert.Equal(t, "3 running★, 1 done★, 1 failed", s.render("★"))
+	assert.Equal(t, "3 running*, 1 done*, 1 failed", s.render("*"))
+
+	assert.Empty(t, summarizeStatusline(nil, time.Now()).render("★"), "nothing to report is an empty line")
+}
+
+func TestReadStatuslineCache(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "statusline.json")
+	_, ok := readStatuslineCache(path, time.Minute)
+	assert.False(t, ok, "no cache yet")
+
+	require.NoError(t, fileutil.AtomicWriteJSON(path, &statuslineSummary{Updated: time.Now().Add(-10 * time.Second), Counts: map[string]int{"idle": 2}}, 0o600))
+	s, ok := readStatuslineCache(path, time.Minute)
+	require.True(t, ok)
+	assert.Equal(t, "2 idle", s.render("★"))
+	_, ok = readStatuslineCache(path, 5*time.Second)
+	assert.False(t, ok, "too old")
+	_, ok = readStatuslineCache(path, 0)
+	assert.False(t, ok, "--max-age 0 never uses the cache")
+}
+
+func TestStatuslineCmd_FromCache(t *testing.T) {
+	_ = clitest.Home(t)
+	require.NoError(t, fileutil.MkdirAll(cliutil.CLIDir(), 0o750))
+	require.NoError(t, fileutil.AtomicWriteJSON(filepath.Join(cliutil.CLIDir(), "statusline.json"), &statuslineSummary{
+		Updated: time.Now(),
+		Counts:  map[string]int{"running": 1, "done": 2},
+		Changes: map[string]int{"done": 1},
+	}, 0o600))
+
+	var out bytes.Buffer
+	cmd := NewStatuslineCmd()
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"--ascii"})
+	require.NoError(t, cmd.Execute())
+	assert.Equal(t, "1 running, 2 done*\n", out.String())
+}
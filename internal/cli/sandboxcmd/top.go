@@ -0,0 +1,427 @@
+// ABOUTME: `yoloai top` — a full-screen live view of every sandbox with its
+// ABOUTME: status, CPU/memory and changes, and keys to attach, diff, stop or destroy.
+package sandboxcmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/sys/unix"
+	"golang.org/x/term"
+
+	"github.com/kstenerud/yoloai"
+	"github.com/kstenerud/yoloai/internal/cli/cliutil"
+	"github.com/kstenerud/yoloai/yoerrors"
+	"github.com/spf13/cobra"
+)
+
+// topInputPoll bounds each wait for a key, so a resized terminal or a
+// cancelled context is noticed between refreshes.
+const topInputPoll = 250 * time.Millisecond
+
+func NewTopCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "top",
+		Short: "Watch all sandboxes live, with CPU, memory and changes",
+		Long: `Show every sandbox in a full-screen view that refreshes on its own: its
+status, backend and agent, the CPU and memory its container is using, how many
+files it has changed, and its age.
+
+Keys act on the selected sandbox:
+
+  ↑/↓ or j/k   select a sandbox
+  a or Enter   attach to it (detach with Ctrl-b d to come back)
+  d            page through its diff (q to go back)
+  s            stop it
+  x            destroy it, after a y/N confirmation
+  r            refresh now
+  q or Ctrl-C  quit
+
+CPU and memory are reported on the docker and podman backends; other backends
+show "-". top needs a terminal; use 'yoloai ls' in scripts.`,
+		Example: `  yoloai top
+  yoloai top --interval 5s`,
+		GroupID: cliutil.GroupSandboxTools,
+		Args:    cobra.NoArgs,
+		RunE:    runTop,
+	}
+	cmd.Flags().Duration("interval", 2*time.Second, "Time between refreshes")
+	return cmd
+}
+
+func runTop(cmd *cobra.Command, _ []string) error {
+	interval, _ := cmd.Flags().GetDuration("interval")
+	if interval < time.Second {
+		return yoerrors.NewUsageError("--interval must be at least 1s: %s", interval)
+	}
+	if cliutil.JSONEnabled(cmd) {
+		return yoerrors.NewUsageError("top is interactive and has no --json output; use 'yoloai ls --json'")
+	}
+	inFd := int(os.Stdin.Fd())   //nolint:gosec // G115: a file descriptor is a small non-negative int
+	outFd := int(os.Stdout.Fd()) //nolint:gosec // G115: a file descriptor is a small non-negative int
+	if !term.IsTerminal(inFd) || !term.IsTerminal(outFd) {
+		return yoerrors.NewUsageError("top needs an interactive terminal; use 'yoloai ls' instead")
+	}
+
+	sys, err := cliutil.System()
+	if err != nil {
+		return err
+	}
+	lister := sys.NewSandboxLister()
+	defer lister.Close() //nolint:errcheck // best-effort cleanup
+	s := &topSession{
+		cmd:     cmd,
+		lister:  lister,
+		clients: map[yoloai.BackendType]*yoloai.Client{},
+		model:   topModel{interval: interval},
+		inFd:    inFd,
+		outFd:   outFd,
+	}
+	defer s.closeClients()
+	return s.run(cmd.Context())
+}
+
+// topSession drives the screen: it owns the terminal, gathers each refresh,
+// and carries out what the model's keys ask for. It keeps one Client open per
+// backend, and a SandboxLister, for the whole session rather than reconnecting
+// every refresh.
+type topSession struct {
+	cmd     *cobra.Command
+	lister  *yoloai.SandboxLister
+	clients map[yoloai.BackendType]*yoloai.Client
+	model   topModel
+	inFd    int
+	outFd   int
+	state   *term.State
+}
+
+// run refreshes every interval and handles keys until the user quits or ctx
+// is cancelled.
+func (s *topSession) run(ctx context.Context) error {
+	if err := s.enter(); err != nil {
+		return err
+	}
+	defer s.leave()
+
+	s.refresh(ctx)
+	next := time.Now().Add(s.model.interval)
+	var lastW, lastH int
+	dirty := true
+	buf := make([]byte, 64)
+	for {
+		if w, h := s.size(); dirty || w != lastW || h != lastH {
+			fmt.Fprint(os.Stdout, s.model.render(w, h)) //nolint:errcheck // best-effort redraw
+			lastW, lastH, dirty = w, h, false
+		}
+
+		ready, err := waitForInput(s.inFd, min(time.Until(next), topInputPoll))
+		switch {
+		case ctx.Err() != nil:
+			return nil
+		case err != nil:
+			return err
+		case !ready:
+			if !time.Now().Before(next) {
+				s.refresh(ctx)
+				next = time.Now().Add(s.model.interval)
+				dirty = true
+			}
+			continue
+		}
+
+		n, err := os.Stdin.Read(buf)
+		if err != nil {
+			return err
+		}
+		for _, key := range parseKeys(buf[:n]) {
+			_, h := s.size()
+			act := s.model.handleKey(key, h)
+			if act.kind == topQuit {
+				return nil
+			}
+			if s.do(ctx, act) {
+				next = time.Now().Add(s.model.interval)
+			}
+		}
+		dirty = true
+	}
+}
+
+// do carries out one action, reporting whether it refreshed the table.
+func (s *topSession) do(ctx context.Context, act topAction) (refreshed bool) {
+	switch act.kind {
+	case topRefresh:
+	case topAttach:
+		s.leave()
+		err := cliutil.AttachToSandboxByName(s.cmd, act.name)
+		if err := s.enter(); err != nil {
+			s.model.message = err.Error()
+		}
+		if err != nil {
+			s.model.message = fmt.Sprintf("attach %s: %v", act.name, err)
+		}
+	case topDiff:
+		diff, err := s.diff(ctx, act.name)
+		if err != nil {
+			s.model.message = fmt.Sprintf("diff %s: %v", act.name, err)
+			return false
+		}
+		s.model.openDiff(act.name, diff)
+		return false
+	case topStop:
+		s.show(fmt.Sprintf("Stopping %s...", act.name))
+		if err := s.stop(ctx, act.name); err != nil {
+			s.model.message = fmt.Sprintf("stop %s: %v", act.name, err)
+		} else {
+			s.model.message = "Stopped " + act.name
+		}
+	case topDestroy:
+		s.show(fmt.Sprintf("Destroying %s...", act.name))
+		warnings, err := s.destroy(ctx, act.name, act.abandon)
+		var active *yoloai.ActiveWorkError
+		switch {
+		case errors.As(err, &active):
+			s.model.askAbandon(act.name, active)
+		case err != nil:
+			s.model.message = fmt.Sprintf("destroy %s: %v", act.name, err)
+		default:
+			s.model.message = "Destroyed " + act.name
+			if len(warnings) > 0 {
+				s.model.message += " (warning: " + strings.Join(warnings, "; ") + ")"
+			}
+		}
+	default:
+		return false
+	}
+	s.refresh(ctx)
+	return true
+}
+
+// show puts a message at the foot of the screen at once, for an action that
+// takes a while.
+func (s *topSession) show(msg string) {
+	s.model.message = msg
+	fmt.Fprint(os.Stdout, s.model.render(s.size())) //nolint:errcheck // best-effort redraw
+}
+
+// enter switches the terminal to raw mode on the alternate screen.
+func (s *topSession) enter() error {
+	state, err := term.MakeRaw(s.inFd)
+	if err != nil {
+		return fmt.Errorf("set terminal raw mode: %w", err)
+	}
+	s.state = state
+	fmt.Fprint(os.Stdout, topEnterScreen) //nolint:errcheck // best-effort terminal control
+	return nil
+}
+
+// leave restores the terminal enter found.
+func (s *topSession) leave() {
+	if s.state == nil {
+		return
+	}
+	fmt.Fprint(os.Stdout, topLeaveScreen) //nolint:errcheck // best-effort terminal control
+	term.Restore(s.inFd, s.state)         //nolint:errcheck // best-effort terminal restore
+	s.state = nil
+}
+
+// size is the terminal's width and height, with a fallback when it can't be read.
+func (s *topSession) size() (width, height int) {
+	w, h, err := term.GetSize(s.outFd)
+	if err != nil || w <= 0 || h <= 0 {
+		return 80, 24
+	}
+	return w, h
+}
+
+// refresh re-reads every sandbox. The per-sandbox usage and change counts are
+// gathered concurrently, since a CPU reading takes about a second each.
+func (s *topSession) refresh(ctx context.Context) {
+	infos, unavailable, err := s.lister.List(ctx)
+	if err != nil {
+		s.model.message = fmt.Sprintf("list sandboxes: %v", err)
+		return
+	}
+	s.model.unavailable = s.model.unavailable[:0]
+	for _, b := range unavailable {
+		s.model.unavailable = append(s.model.unavailable, string(b))
+	}
+
+	rows := make([]topRow, len(infos))
+	var wg sync.WaitGroup
+	for i, info := range infos {
+		rows[i] = baseTopRow(info)
+		if info.Status == yoloai.StatusBroken || info.Status == yoloai.StatusUnavailable {
+			continue
+		}
+		c, err := s.client(topBackend(info))
+		if err != nil {
+			continue
+		}
+		sb, err := c.Sandbox(info.Environment.Name)
+		if err != nil {
+			continue
+		}
+		wg.Go(func() { fillTopRow(ctx, sb, info, &rows[i]) })
+	}
+	wg.Wait()
+	s.model.setRows(rows)
+	s.model.updated = time.Now()
+}
+
+// baseTopRow formats what the listing itself says about a sandbox; the usage
+// and change columns start as "-" until fillTopRow measures them.
+func baseTopRow(info *yoloai.SandboxInfo) topRow {
+	row := topRow{
+		name:    info.Environment.Name,
+		status:  statusCell(info),
+		backend: "-",
+		agent:   "-",
+		cpu:     "-",
+		mem:     "-",
+		changes: "-",
+		age:     "-",
+	}
+	if info.Status == yoloai.StatusBroken || info.Status == yoloai.StatusUnavailable {
+		if info.Environment.BackendType != "" {
+			row.backend = string(info.Environment.BackendType)
+		}
+		return row
+	}
+	row.backend = string(topBackend(info))
+	row.agent = string(info.AgentType)
+	row.age = cliutil.FormatAge(info.Environment.CreatedAt)
+	row.changes = string(info.Changes)
+	row.unapplied = info.Changes == yoloai.ChangesPresent
+	return row
+}
+
+// fillTopRow measures a sandbox's CPU/memory (when it is running) and counts
+// its changed files (when it has any). A measurement that fails leaves "-".
+func fillTopRow(ctx context.Context, sb *yoloai.Sandbox, info *yoloai.SandboxInfo, row *topRow) {
+	var wg sync.WaitGroup
+	switch info.Status {
+	case yoloai.StatusActive, yoloai.StatusIdle, yoloai.StatusDone, yoloai.StatusFailed:
+		wg.Go(func() {
+			usage, supported, err := sb.Usage(ctx)
+			if err != nil || !supported {
+				return
+			}
+			row.cpu = fmt.Sprintf("%.1f%%", usage.CPUPercent)
+			row.mem = cliutil.FormatSize(usage.MemoryBytes)
+		})
+	}
+	if info.Changes == yoloai.ChangesPresent {
+		wg.Go(func() {
+			changes, err := sb.Workdir().Changes(ctx)
+			if err != nil {
+				return
+			}
+			row.changes = formatTopChanges(changes)
+		})
+	}
+	wg.Wait()
+}
+
+// formatTopChanges renders a change summary as "3 files +40 -2".
+func formatTopChanges(c *yoloai.Changes) string {
+	files := "files"
+	if len(c.Files) == 1 {
+		files = "file"
+	}
+	return fmt.Sprintf("%d %s +%d -%d", len(c.Files), files, c.Additions, c.Deletions)
+}
+
+// topBackend is the sandbox's backend, defaulting as `ls` does for sandboxes
+// made before the backend was recorded.
+func topBackend(info *yoloai.SandboxInfo) yoloai.BackendType {
+	if info.Environment.BackendType == "" {
+		return "docker"
+	}
+	return info.Environment.BackendType
+}
+
+// client returns the session's Client for backend, opening it on first use.
+func (s *topSession) client(backend yoloai.BackendType) (*yoloai.Client, error) {
+	if c, ok := s.clients[backend]; ok {
+		return c, nil
+	}
+	c, err := cliutil.ClientFor(s.cmd, backend)
+	if err != nil {
+		return nil, err
+	}
+	s.clients[backend] = c
+	return c, nil
+}
+
+// closeClients closes every Client the session opened.
+func (s *topSession) closeClients() {
+	for _, c := range s.clients {
+		c.Close() //nolint:errcheck // best-effort cleanup
+	}
+}
+
+// sandbox opens the named sandbox on its recorded backend.
+func (s *topSession) sandbox(name string) (*yoloai.Sandbox, error) {
+	c, err := s.client(cliutil.ResolveBackendForSandbox(name))
+	if err != nil {
+		return nil, err
+	}
+	return c.Sandbox(name)
+}
+
+// diff returns the sandbox's full working diff.
+func (s *topSession) diff(ctx context.Context, name string) (string, error) {
+	sb, err := s.sandbox(name)
+	if err != nil {
+		return "", err
+	}
+	return sb.Workdir().Diff(ctx, yoloai.WorkdirDiffOptions{})
+}
+
+// stop stops the sandbox.
+func (s *topSession) stop(ctx context.Context, name string) error {
+	sb, err := s.sandbox(name)
+	if err != nil {
+		return err
+	}
+	return sb.Stop(ctx)
+}
+
+// destroy destroys the sandbox, returning the warnings its teardown raised.
+// Without abandon, a sandbox holding unapplied work is refused with an
+// *ActiveWorkError.
+func (s *topSession) destroy(ctx context.Context, name string, abandon bool) (warnings []string, err error) {
+	sb, err := s.sandbox(name)
+	if err != nil {
+		return nil, err
+	}
+	res, err := sb.Destroy(ctx, yoloai.SandboxDestroyOptions{AbandonUnappliedWork: abandon})
+	if res != nil {
+		for _, n := range res.Notices {
+			if n.Level == yoloai.NoticeWarn {
+				warnings = append(warnings, n.Message)
+			}
+		}
+	}
+	return warnings, err
+}
+
+// waitForInput waits up to d for fd to have input, reporting whether it does.
+// An interrupted wait reports no input.
+func waitForInput(fd int, d time.Duration) (bool, error) {
+	fds := []unix.PollFd{{Fd: int32(fd), Events: unix.POLLIN}} //nolint:gosec // G115: a file descriptor is a small non-negative int
+	n, err := unix.Poll(fds, int(max(d, 0).Milliseconds()))
+	if errors.Is(err, unix.EINTR) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("wait for input: %w", err)
+	}
+	return n > 0, nil
+}
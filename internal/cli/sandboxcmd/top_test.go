@@ -0,0 +1,176 @@
+package sandboxcmd
+
+// ABOUTME: Unit tests for the `top` screen model: keys, selection, the destroy
+// ABOUTME: confirmation, the diff viewer and rendering.
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	yoloai "github.com/kstenerud/yoloai"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func topRows(names ...string) []topRow {
+	rows := make([]topRow, len(names))
+	for i, n := range names {
+		rows[i] = topRow{name: n, status: "active", backend: "docker", agent: "claude",
+			cpu: "1.5%", mem: "120MB", changes: "no", age: "5m"}
+	}
+	return rows
+}
+
+func TestParseKeys(t *testing.T) {
+	assert.Equal(t, []string{"up", "down", "pgup", "pgdn", "j", "esc", "ctrl-c", "enter", " "},
+		parseKeys([]byte("\033[A\033[B\033[5~\033[6~j\033\x03\r ")))
+	// Sequences top doesn't use are dropped whole.
+	assert.Equal(t, []string{"q"}, parseKeys([]byte("\033[1;5Cq\x7f")))
+}
+
+func TestTopModel_Selection(t *testing.T) {
+	m := &topModel{}
+	m.setRows(topRows("a", "b", "c"))
+
+	assert.Equal(t, topAction{}, m.handleKey("down", 24))
+	m.handleKey("j", 24)
+	m.handleKey("j", 24)
+	assert.Equal(t, "c", m.selectedName())
+	m.handleKey("up", 24)
+	assert.Equal(t, "b", m.selectedName())
+
+	// A refresh keeps the same sandbox selected while it is listed.
+	m.setRows(topRows("new", "a", "b", "c"))
+	assert.Equal(t, "b", m.selectedName())
+	m.setRows(topRows("a"))
+	assert.Equal(t, "a", m.selectedName())
+	m.setRows(nil)
+	assert.Empty(t, m.selectedName())
+	assert.Equal(t, topAction{}, m.handleKey("a", 24))
+}
+
+func TestTopModel_Actions(t *testing.T) {
+	m := &topModel{}
+	m.setRows(topRows("a", "b"))
+	m.handleKey("j", 24)
+
+	assert.Equal(t, topAction{kind: topAttach, name: "b"}, m.handleKey("a", 24))
+	assert.Equal(t, topAction{kind: topAttach, name: "b"}, m.handleKey("enter", 24))
+	assert.Equal(t, topAction{kind: topDiff, name: "b"}, m.handleKey("d", 24))
+	assert.Equal(t, topAction{kind: topStop, name: "b"}, m.handleKey("s", 24))
+	assert.Equal(t, topAction{kind: topRefresh}, m.handleKey("r", 24))
+	assert.Equal(t, topAction{kind: topQuit}, m.handleKey("q", 24))
+	assert.Equal(t, topAction{kind: topQuit}, m.handleKey("ctrl-c", 24))
+}
+
+func TestTopModel_DestroyConfirm(t *testing.T) {
+	m := &topModel{}
+	rows := topRows("a", "b")
+	rows[1].unapplied = true
+	m.setRows(rows)
+
+	assert.Equal(t, topAction{}, m.handleKey("x", 24))
+	require.NotNil(t, m.confirm)
+	assert.Equal(t, "Destroy a? [y/N]", m.confirm.prompt)
+	assert.Equal(t, topAction{}, m.handleKey("n", 24))
+	assert.Nil(t, m.confirm)
+	assert.Equal(t, "Destroy cancelled", m.message)
+
+	m.handleKey("j", 24)
+	m.handleKey("x", 24)
+	assert.Contains(t, m.confirm.prompt, "unapplied changes")
+	assert.Equal(t, topAction{kind: topDestroy, name: "b", abandon: true}, m.handleKey("y", 24))
+
+	// A refused destroy is re-asked, offering to abandon the work.
+	m.askAbandon("a", errors.New("sandbox a has 2 unapplied commits"))
+	assert.Equal(t, "sandbox a has 2 unapplied commits. Destroy a anyway? [y/N]", m.confirm.prompt)
+	assert.Equal(t, topAction{kind: topDestroy, name: "a", abandon: true}, m.handleKey("Y", 24))
+}
+
+func TestTopModel_DiffViewer(t *testing.T) {
+	m := &topModel{}
+	m.setRows(topRows("a"))
+	lines := make([]string, 30)
+	for i := range lines {
+		lines[i] = "+line"
+	}
+	m.openDiff("a", strings.Join(lines, "\n")+"\n")
+	require.NotNil(t, m.diff)
+	assert.Len(t, m.diff.lines, 30)
+
+	// A 10-line screen shows 9 diff lines under the title.
+	m.handleKey(" ", 10)
+	assert.Equal(t, 9, m.diff.offset)
+	m.handleKey("G", 10)
+	assert.Equal(t, 21, m.diff.offset)
+	m.handleKey("j", 10)
+	assert.Equal(t, 21, m.diff.offset, "scrolling stops at the last page")
+	m.handleKey("b", 10)
+	m.handleKey("k", 10)
+	assert.Equal(t, 11, m.diff.offset)
+	m.handleKey("g", 10)
+	assert.Equal(t, 0, m.diff.offset)
+
+	// Keys in the viewer don't reach the table.
+	assert.Equal(t, topAction{}, m.handleKey("s", 10))
+	assert.Equal(t, topAction{}, m.handleKey("q", 10))
+	assert.Nil(t, m.diff)
+
+	m.openDiff("a", "")
+	assert.Contains(t, m.render(80, 10), "no changes")
+}
+
+func TestTopModel_Render(t *testing.T) {
+	m := &topModel{unavailable: []string{"tart"}}
+	m.setRows(topRows("alpha", "beta"))
+	m.handleKey("j", 24)
+
+	out := m.render(100, 24)
+	assert.True(t, strings.HasPrefix(out, topHome))
+	assert.Contains(t, out, "2 sandboxes")
+	assert.Contains(t, out, "(unavailable: tart)")
+	assert.Regexp(t, `NAME\s+STATUS\s+BACKEND\s+AGENT\s+CPU\s+MEM\s+CHANGES\s+AGE`, out)
+	assert.Regexp(t, `alpha\s+active\s+docker\s+claude\s+1.5%\s+120MB\s+no\s+5m`, out)
+	assert.Contains(t, out, topReverse+"beta")
+	assert.Contains(t, out, topHelp)
+
+	for _, line := range strings.Split(m.render(20, 24), "\r\n") {
+		line = strings.NewReplacer(topHome, "", topClearLine, "", topClearBelow, "", topReverse, "", topReset, "").Replace(line)
+		assert.LessOrEqual(t, len([]rune(line)), 20, "line %q is wider than the screen", line)
+	}
+
+	m.handleKey("x", 24)
+	assert.Contains(t, m.render(100, 24), "Destroy beta? [y/N]")
+}
+
+func TestTopModel_RenderScrollsToSelection(t *testing.T) {
+	m := &topModel{}
+	m.setRows(topRows("s0", "s1", "s2", "s3", "s4", "s5", "s6", "s7"))
+	for range 7 {
+		m.handleKey("j", 8)
+	}
+	out := m.render(80, 8)
+	assert.Contains(t, out, "s7")
+	assert.NotContains(t, out, "s0")
+}
+
+func TestBaseTopRow(t *testing.T) {
+	info := makeInfo("one", yoloai.StatusActive, "claude", "", "yes")
+	row := baseTopRow(info)
+	assert.Equal(t, "one", row.name)
+	assert.Equal(t, "docker", row.backend)
+	assert.Equal(t, "claude", row.agent)
+	assert.Equal(t, "yes", row.changes)
+	assert.True(t, row.unapplied)
+	assert.Equal(t, "-", row.cpu)
+
+	assert.Equal(t, "-", baseTopRow(makeBrokenInfo("two")).agent)
+}
+
+func TestFormatTopChanges(t *testing.T) {
+	assert.Equal(t, "1 file +3 -0", formatTopChanges(&yoloai.Changes{
+		Files: []yoloai.FileChange{{Path: "a.go", Additions: 3}}, Additions: 3}))
+	assert.Equal(t, "2 files +3 -1", formatTopChanges(&yoloai.Changes{
+		Files: []yoloai.FileChange{{Path: "a.go"}, {Path: "b.go"}}, Additions: 3, Deletions: 1}))
+}
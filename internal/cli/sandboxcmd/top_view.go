@@ -0,0 +1,361 @@
+// ABOUTME: The screen model behind `yoloai top`: key decoding, selection and
+// ABOUTME: confirmation state, the diff viewer, and rendering to a sized screen.
+package sandboxcmd
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// Terminal control sequences top draws with. Each frame homes the cursor and
+// overwrites in place, clearing to the end of every line and then below the
+// last, so a refresh doesn't flicker the way a full clear does.
+const (
+	topHome        = "\033[H"
+	topClearLine   = "\033[K"
+	topClearBelow  = "\033[J"
+	topReverse     = "\033[7m"
+	topReset       = "\033[0m"
+	topEnterScreen = "\033[?1049h\033[?25l" // alternate screen, cursor hidden
+	topLeaveScreen = "\033[?25h\033[?1049l"
+)
+
+// topRow is one sandbox's line in the top table, already formatted.
+type topRow struct {
+	name    string
+	status  string
+	backend string
+	agent   string
+	cpu     string
+	mem     string
+	changes string
+	age     string
+	// unapplied is set when the sandbox holds changes not yet applied, so the
+	// destroy prompt can say so.
+	unapplied bool
+}
+
+// topActionKind is what a key press asks the session to do.
+type topActionKind int
+
+const (
+	topNone topActionKind = iota
+	topQuit
+	topRefresh
+	topAttach
+	topDiff
+	topStop
+	topDestroy
+)
+
+// topAction is a request from the model to the session, naming the sandbox
+// it applies to.
+type topAction struct {
+	kind    topActionKind
+	name    string
+	abandon bool // destroy even though the sandbox holds unapplied work
+}
+
+// topConfirm is a pending y/N question about destroying a sandbox.
+type topConfirm struct {
+	prompt  string
+	name    string
+	abandon bool
+}
+
+// topDiffView is the open diff viewer: the diff's lines and the first shown.
+type topDiffView struct {
+	name   string
+	lines  []string
+	offset int
+}
+
+// topModel is everything top shows, and the state its keys move through.
+type topModel struct {
+	rows        []topRow
+	unavailable []string
+	selected    int
+	interval    time.Duration
+	updated     time.Time
+	message     string
+	confirm     *topConfirm
+	diff        *topDiffView
+}
+
+// setRows replaces the table, keeping the same sandbox selected when it is
+// still listed.
+func (m *topModel) setRows(rows []topRow) {
+	name := m.selectedName()
+	m.rows = rows
+	for i, r := range rows {
+		if r.name == name {
+			m.selected = i
+			return
+		}
+	}
+	m.selected = min(m.selected, max(len(rows)-1, 0))
+}
+
+// selectedName is the selected sandbox, or "" when there are none.
+func (m *topModel) selectedName() string {
+	if m.selected < 0 || m.selected >= len(m.rows) {
+		return ""
+	}
+	return m.rows[m.selected].name
+}
+
+// askDestroy opens the destroy confirmation for the selected sandbox.
+func (m *topModel) askDestroy() {
+	row := m.rows[m.selected]
+	prompt := fmt.Sprintf("Destroy %s? [y/N]", row.name)
+	if row.unapplied {
+		prompt = fmt.Sprintf("Destroy %s? It has unapplied changes. [y/N]", row.name)
+	}
+	m.confirm = &topConfirm{prompt: prompt, name: row.name, abandon: row.unapplied}
+}
+
+// askAbandon re-asks about a destroy the library refused because the sandbox
+// holds unapplied work, this time offering to discard it.
+func (m *topModel) askAbandon(name string, reason error) {
+	m.confirm = &topConfirm{
+		prompt:  fmt.Sprintf("%v. Destroy %s anyway? [y/N]", reason, name),
+		name:    name,
+		abandon: true,
+	}
+}
+
+// openDiff shows a sandbox's diff in the viewer.
+func (m *topModel) openDiff(name, diff string) {
+	diff = strings.TrimRight(diff, "\n")
+	var lines []string
+	if diff != "" {
+		lines = strings.Split(diff, "\n")
+	}
+	m.diff = &topDiffView{name: name, lines: lines}
+}
+
+// handleKey applies one key to the model. height is the screen's, which the
+// diff viewer pages by. It returns what, if anything, the session must do.
+func (m *topModel) handleKey(key string, height int) topAction {
+	if key == "ctrl-c" {
+		return topAction{kind: topQuit}
+	}
+	if m.diff != nil {
+		m.diff.scroll(key, max(height-1, 1))
+		if key == "q" || key == "esc" {
+			m.diff = nil
+		}
+		return topAction{}
+	}
+	if c := m.confirm; c != nil {
+		m.confirm = nil
+		if key == "y" || key == "Y" {
+			return topAction{kind: topDestroy, name: c.name, abandon: c.abandon}
+		}
+		m.message = "Destroy cancelled"
+		return topAction{}
+	}
+
+	m.message = ""
+	switch key {
+	case "q", "esc":
+		return topAction{kind: topQuit}
+	case "r":
+		return topAction{kind: topRefresh}
+	case "j", "down":
+		m.selected = min(m.selected+1, max(len(m.rows)-1, 0))
+		return topAction{}
+	case "k", "up":
+		m.selected = max(m.selected-1, 0)
+		return topAction{}
+	}
+	name := m.selectedName()
+	if name == "" {
+		return topAction{}
+	}
+	switch key {
+	case "a", "enter":
+		return topAction{kind: topAttach, name: name}
+	case "d":
+		return topAction{kind: topDiff, name: name}
+	case "s":
+		return topAction{kind: topStop, name: name}
+	case "x":
+		m.askDestroy()
+	}
+	return topAction{}
+}
+
+// scroll moves the viewer for a key, a page being page lines.
+func (v *topDiffView) scroll(key string, page int) {
+	switch key {
+	case "j", "down", "enter":
+		v.offset++
+	case "k", "up":
+		v.offset--
+	case " ", "pgdn", "f":
+		v.offset += page
+	case "b", "pgup":
+		v.offset -= page
+	case "g":
+		v.offset = 0
+	case "G":
+		v.offset = len(v.lines)
+	}
+	v.offset = max(min(v.offset, len(v.lines)-page), 0)
+}
+
+// parseKeys splits what one read of the terminal returned into keys: printable
+// characters stand for themselves, and the arrow, paging, enter, escape and
+// Ctrl-C keys get names. Sequences top has no use for are dropped.
+func parseKeys(b []byte) []string {
+	var keys []string
+	for len(b) > 0 {
+		switch {
+		case bytes.HasPrefix(b, []byte("\033[A")):
+			keys, b = append(keys, "up"), b[3:]
+		case bytes.HasPrefix(b, []byte("\033[B")):
+			keys, b = append(keys, "down"), b[3:]
+		case bytes.HasPrefix(b, []byte("\033[5~")):
+			keys, b = append(keys, "pgup"), b[4:]
+		case bytes.HasPrefix(b, []byte("\033[6~")):
+			keys, b = append(keys, "pgdn"), b[4:]
+		case bytes.HasPrefix(b, []byte("\033[")):
+			// Another CSI sequence: skip to its final byte.
+			i := 2
+			for i < len(b) && (b[i] < 0x40 || b[i] > 0x7e) {
+				i++
+			}
+			b = b[min(i+1, len(b)):]
+		case b[0] == 0x1b:
+			keys, b = append(keys, "esc"), b[1:]
+		case b[0] == 0x03:
+			keys, b = append(keys, "ctrl-c"), b[1:]
+		case b[0] == '\r' || b[0] == '\n':
+			keys, b = append(keys, "enter"), b[1:]
+		case b[0] >= 0x20 && b[0] < 0x7f:
+			keys, b = append(keys, string(b[0])), b[1:]
+		default:
+			b = b[1:]
+		}
+	}
+	return keys
+}
+
+// topHelp is the key summary at the foot of the table.
+const topHelp = "↑/↓ select   a attach   d diff   s stop   x destroy   r refresh   q quit"
+
+// render draws the whole screen for a terminal of the given size.
+func (m *topModel) render(width, height int) string {
+	var lines []string
+	if m.diff != nil {
+		lines = m.diff.render(width, height)
+	} else {
+		lines = m.renderTable(width, height)
+	}
+
+	var b strings.Builder
+	b.WriteString(topHome)
+	for i, line := range lines {
+		if i > 0 {
+			b.WriteString("\r\n")
+		}
+		b.WriteString(line)
+		b.WriteString(topClearLine)
+	}
+	b.WriteString(topClearBelow)
+	return b.String()
+}
+
+// renderTable lays out the sandbox table, scrolled so the selection shows.
+func (m *topModel) renderTable(width, height int) []string {
+	noun := "sandboxes"
+	if len(m.rows) == 1 {
+		noun = "sandbox"
+	}
+	title := fmt.Sprintf("yoloai top: %d %s, every %s", len(m.rows), noun, m.interval)
+	if !m.updated.IsZero() {
+		title += ", updated " + m.updated.Format("15:04:05")
+	}
+	if len(m.unavailable) > 0 {
+		title += " (unavailable: " + strings.Join(m.unavailable, ", ") + ")"
+	}
+	lines := []string{fitWidth(title, width), ""}
+
+	var buf bytes.Buffer
+	w := tabwriter.NewWriter(&buf, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(w, "NAME\tSTATUS\tBACKEND\tAGENT\tCPU\tMEM\tCHANGES\tAGE") //nolint:errcheck
+	for _, r := range m.rows {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n", //nolint:errcheck
+			r.name, r.status, r.backend, r.agent, r.cpu, r.mem, r.changes, r.age)
+	}
+	w.Flush() //nolint:errcheck // writes to a buffer
+	table := strings.Split(strings.TrimRight(buf.String(), "\n"), "\n")
+	lines = append(lines, fitWidth(table[0], width))
+
+	// Title, blank, header, then the rows; a blank and the footer close it.
+	visible := max(height-5, 1)
+	first := max(m.selected-visible+1, 0)
+	for i, line := range table[1:] {
+		if i < first || i >= first+visible {
+			continue
+		}
+		line = fitWidth(line, width)
+		if i == m.selected {
+			line = topReverse + line + strings.Repeat(" ", max(width-len([]rune(line)), 0)) + topReset
+		}
+		lines = append(lines, line)
+	}
+	if len(m.rows) == 0 {
+		lines = append(lines, "No sandboxes found")
+	}
+
+	footer := m.message
+	if m.confirm != nil {
+		footer = m.confirm.prompt
+	}
+	if footer == "" {
+		footer = topHelp
+	}
+	return append(lines, "", fitWidth(footer, width))
+}
+
+// render lays out one screenful of the diff under a title line, coloring
+// added and removed lines as git does.
+func (v *topDiffView) render(width, height int) []string {
+	page := max(height-1, 1)
+	end := min(v.offset+page, len(v.lines))
+	title := fmt.Sprintf("yoloai diff %s: lines %d-%d of %d   j/k scroll   space/b page   q back",
+		v.name, min(v.offset+1, end), end, len(v.lines))
+	if len(v.lines) == 0 {
+		title = fmt.Sprintf("yoloai diff %s: no changes   q back", v.name)
+	}
+	lines := []string{fitWidth(title, width)}
+	for _, line := range v.lines[v.offset:end] {
+		line = fitWidth(strings.ReplaceAll(line, "\t", "    "), width)
+		switch {
+		case strings.HasPrefix(line, "+++"), strings.HasPrefix(line, "---"), strings.HasPrefix(line, "diff "):
+			line = "\033[1m" + line + topReset
+		case strings.HasPrefix(line, "+"):
+			line = "\033[32m" + line + topReset
+		case strings.HasPrefix(line, "-"):
+			line = "\033[31m" + line + topReset
+		case strings.HasPrefix(line, "@@"):
+			line = "\033[36m" + line + topReset
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// fitWidth cuts s to at most width runes.
+func fitWidth(s string, width int) string {
+	r := []rune(s)
+	if len(r) <= width {
+		return s
+	}
+	return string(r[:max(width, 0)])
+}
@@ -0,0 +1,316 @@
+// ABOUTME: `yoloai selftest` — runs a throwaway sandbox through create, done, diff,
+// ABOUTME: apply and destroy with the scripted test agent, reporting each phase.
+package selftestcmd
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/kstenerud/yoloai"
+	"github.com/kstenerud/yoloai/internal/cli/cliutil"
+	"github.com/kstenerud/yoloai/internal/fileutil"
+	"github.com/kstenerud/yoloai/internal/sysexec"
+
+	"github.com/spf13/cobra"
+)
+
+const (
+	// scenarioFile is the test agent's scenario, committed into the project so
+	// it is part of the baseline rather than the agent's diff.
+	scenarioFile = ".yoloai-selftest.json"
+	// outputFile is what the scenario writes; diff and apply look for it.
+	outputFile    = "selftest.txt"
+	outputContent = "yoloai selftest\n"
+	commitMessage = "selftest: add selftest.txt"
+)
+
+// phaseResult is one phase's outcome, in both the text and JSON output.
+type phaseResult struct {
+	Name       string `json:"name"`
+	OK         bool   `json:"ok"`
+	DurationMS int64  `json:"duration_ms"`
+	Error      string `json:"error,omitempty"`
+}
+
+// reportJSON is the --json form of a selftest run.
+type reportJSON struct {
+	Backend string        `json:"backend"`
+	Passed  bool          `json:"passed"`
+	Phases  []phaseResult `json:"phases"`
+}
+
+// phase is one named step; run returns nil on success.
+type phase struct {
+	name string
+	run  func(ctx context.Context) error
+}
+
+// NewCmd builds the top-level `yoloai selftest` command.
+func NewCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "selftest",
+		Short:   "Check that yoloai works end to end on this machine",
+		GroupID: cliutil.GroupAdmin,
+		Long: `Run a throwaway sandbox through the whole lifecycle with the scripted test
+agent, which needs no API key:
+
+  prepare  — make a temporary git project holding the agent's scenario
+  create   — create and start a sandbox on it
+  done     — wait for the agent to edit a file, commit it and exit
+  diff     — check the diff shows the agent's file
+  apply    — apply the commit to the temporary project
+  destroy  — destroy the sandbox
+
+Each phase reports pass or fail; a failure skips the phases after it, but
+the sandbox and the temporary project are always cleaned up. Use it after
+an upgrade or a backend change. Exit code 0 when every phase passed.`,
+		Args: cobra.NoArgs,
+		RunE: runSelftest,
+	}
+	cmd.Flags().String("backend", "", "Runtime backend to test (see 'yoloai system backends')")
+	cmd.Flags().Duration("timeout", 10*time.Minute, "How long to wait for the agent (the first run may build an image)")
+	return cmd
+}
+
+func runSelftest(cmd *cobra.Command, _ []string) error {
+	timeout, _ := cmd.Flags().GetDuration("timeout")
+	backend := cliutil.ResolveBackend(cmd)
+
+	projectDir, err := os.MkdirTemp("", "yoloai-selftest-")
+	if err != nil {
+		return fmt.Errorf("create temp project: %w", err)
+	}
+	defer os.RemoveAll(projectDir) //nolint:errcheck // best-effort cleanup
+
+	var results []phaseResult
+	err = cliutil.WithClient(cmd, backend, func(ctx context.Context, c *yoloai.Client) error {
+		st := &selftest{
+			client:     c,
+			projectDir: projectDir,
+			name:       sandboxName(),
+			timeout:    timeout,
+			env:        cliutil.Layout().Env().EnvForGitInvocation(),
+		}
+		results = runPhases(ctx, st.phases(), st.cleanup(), phaseReporter(cmd))
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	return report(cmd, string(backend), results)
+}
+
+// runPhases runs phases in order until one fails, then always runs cleanup
+// (as the last phase when it does something). progress sees each result as
+// it lands.
+func runPhases(ctx context.Context, phases []phase, cleanup phase, progress func(phaseResult)) []phaseResult {
+	var results []phaseResult
+	record := func(p phase) bool {
+		start := time.Now()
+		err := p.run(ctx)
+		r := phaseResult{Name: p.name, OK: err == nil, DurationMS: time.Since(start).Milliseconds()}
+		if err != nil {
+			r.Error = err.Error()
+		}
+		results = append(results, r)
+		progress(r)
+		return r.OK
+	}
+	for _, p := range phases {
+		if !record(p) {
+			break
+		}
+	}
+	// Cleanup runs under a fresh deadline-free context so an interrupted or
+	// timed-out run still destroys what it created.
+	cleanupCtx := context.WithoutCancel(ctx)
+	record(phase{name: cleanup.name, run: func(context.Context) error { return cleanup.run(cleanupCtx) }})
+	return results
+}
+
+// phaseReporter prints each phase as it finishes, unless --json is set.
+func phaseReporter(cmd *cobra.Command) func(phaseResult) {
+	if cliutil.JSONEnabled(cmd) {
+		return func(phaseResult) {}
+	}
+	w := cmd.OutOrStdout()
+	return func(r phaseResult) {
+		d := time.Duration(r.DurationMS) * time.Millisecond
+		if r.OK {
+			fmt.Fprintf(w, "  ✓  %-8s %s\n", r.Name, d.Round(100*time.Millisecond)) //nolint:errcheck
+			return
+		}
+		fmt.Fprintf(w, "  ✗  %-8s %s\n", r.Name, r.Error) //nolint:errcheck
+	}
+}
+
+// report prints the verdict and maps a failed phase to a non-zero exit.
+func report(cmd *cobra.Command, backend string, results []phaseResult) error {
+	passed := true
+	var failed string
+	for _, r := range results {
+		if !r.OK {
+			passed = false
+			if failed == "" {
+				failed = r.Name
+			}
+		}
+	}
+	if cliutil.JSONEnabled(cmd) {
+		if err := cliutil.WriteJSON(cmd.OutOrStdout(), reportJSON{Backend: backend, Passed: passed, Phases: results}); err != nil {
+			return err
+		}
+	} else if passed {
+		fmt.Fprintf(cmd.OutOrStdout(), "selftest passed (backend %s)\n", backend) //nolint:errcheck
+	}
+	if !passed {
+		return fmt.Errorf("selftest failed at %s (backend %s)", failed, backend)
+	}
+	return nil
+}
+
+// sandboxName returns a name no user sandbox is likely to have.
+func sandboxName() string {
+	b := make([]byte, 3)
+	_, _ = rand.Read(b)
+	return "selftest-" + hex.EncodeToString(b)
+}
+
+// selftest holds the state the phases share.
+type selftest struct {
+	client     *yoloai.Client
+	projectDir string
+	name       string
+	timeout    time.Duration
+	env        []string // host git's environment
+	sb         *yoloai.Sandbox
+}
+
+func (s *selftest) phases() []phase {
+	return []phase{
+		{"prepare", s.prepare},
+		{"create", s.create},
+		{"done", s.waitDone},
+		{"diff", s.diff},
+		{"apply", s.apply},
+	}
+}
+
+// cleanup is the destroy phase; with no sandbox created it has nothing to do.
+func (s *selftest) cleanup() phase {
+	return phase{"destroy", func(ctx context.Context) error {
+		if s.sb == nil {
+			return nil
+		}
+		_, err := s.sb.Destroy(ctx, yoloai.SandboxDestroyOptions{AbandonUnappliedWork: true})
+		return err
+	}}
+}
+
+// scenario is the test agent's script: write a file, commit it, exit 0.
+func scenario() ([]byte, error) {
+	return json.MarshalIndent(map[string]any{
+		"steps": []map[string]any{
+			{"say": "yoloai selftest: writing " + outputFile},
+			{"write": outputFile, "content": outputContent},
+			{"commit": commitMessage},
+		},
+		"exit": 0,
+	}, "", "  ")
+}
+
+func (s *selftest) prepare(ctx context.Context) error {
+	data, err := scenario()
+	if err != nil {
+		return err
+	}
+	if err := fileutil.WriteFile(filepath.Join(s.projectDir, scenarioFile), data, 0600); err != nil {
+		return err
+	}
+	if err := fileutil.WriteFile(filepath.Join(s.projectDir, "README.md"), []byte("yoloai selftest project\n"), 0600); err != nil {
+		return err
+	}
+	for _, args := range [][]string{
+		{"init", "-q"},
+		{"add", "-A"},
+		{"-c", "user.name=yoloai selftest", "-c", "user.email=selftest@yoloai.invalid", "commit", "-q", "-m", "selftest baseline"},
+	} {
+		if err := s.git(ctx, args...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// git runs git in the temporary project.
+func (s *selftest) git(ctx context.Context, args ...string) error {
+	c := sysexec.CommandContext(ctx, s.env, "git", append([]string{"-C", s.projectDir}, args...)...)
+	if out, err := c.CombinedOutput(); err != nil {
+		return fmt.Errorf("git: %s", strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+func (s *selftest) create(ctx context.Context) error {
+	env := map[string]string{"YOLOAI_TEST_SCENARIO": scenarioFile}
+	sb, err := s.client.CreateSandbox(ctx, yoloai.SandboxCreateOptions{
+		Name:      s.name,
+		Workdir:   yoloai.DirSpec{Path: s.projectDir, Mode: yoloai.DirModeCopy},
+		AgentType: yoloai.AgentTest,
+		NoProfile: true,
+		Prompt:    "selftest",
+		Headless:  true,
+		Env:       env,
+		Output:    io.Discard,
+	})
+	if err != nil {
+		return err
+	}
+	s.sb = sb
+	_, err = sb.Start(ctx, yoloai.SandboxStartOptions{Env: env})
+	return err
+}
+
+func (s *selftest) waitDone(ctx context.Context) error {
+	info, err := s.sb.Wait(ctx, yoloai.SandboxWaitOptions{For: yoloai.WaitForExit, Timeout: s.timeout})
+	if err != nil {
+		return err
+	}
+	if info.Status != yoloai.StatusDone {
+		return fmt.Errorf("agent ended %s, want done (see 'yoloai log %s')", info.Status, s.name)
+	}
+	return nil
+}
+
+func (s *selftest) diff(ctx context.Context) error {
+	out, err := s.sb.Workdir().Diff(ctx, yoloai.WorkdirDiffOptions{})
+	if err != nil {
+		return err
+	}
+	if !strings.Contains(out, outputFile) || !strings.Contains(out, "+"+strings.TrimSpace(outputContent)) {
+		return fmt.Errorf("diff does not show the agent's %s", outputFile)
+	}
+	return nil
+}
+
+func (s *selftest) apply(ctx context.Context) error {
+	if _, err := s.sb.Workdir().Apply(ctx, yoloai.WorkdirApplyOptions{Mode: yoloai.ApplyModeCommits}); err != nil {
+		return err
+	}
+	got, err := os.ReadFile(filepath.Join(s.projectDir, outputFile))
+	if err != nil {
+		return fmt.Errorf("applied file missing: %w", err)
+	}
+	if string(got) != outputContent {
+		return fmt.Errorf("applied %s has %q, want %q", outputFile, got, outputContent)
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+// ABOUTME: Tests selftest's phase runner (stop at the first failure, always clean
+// ABOUTME: up), its verdict, and the temporary project it prepares.
+package selftestcmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kstenerud/yoloai/internal/testutil"
+)
+
+func TestRunPhases_StopsAtFailureAndCleansUp(t *testing.T) {
+	var ran []string
+	step := func(name string, err error) phase {
+		return phase{name, func(context.Context) error { ran = append(ran, name); return err }}
+	}
+	var seen []string
+	results := runPhases(context.Background(),
+		[]phase{step("a", nil), step("b", errors.New("boom")), step("c", nil)},
+		step("destroy", nil),
+		func(r phaseResult) { seen = append(seen, r.Name) })
+
+	assert.Equal(t, []string{"a", "b", "destroy"}, ran, "c is skipped, cleanup still runs")
+	assert.Equal(t, ran, seen)
+	require.Len(t, results, 3)
+	assert.True(t, results[0].OK)
+	assert.False(t, results[1].OK)
+	assert.Equal(t, "boom", results[1].Error)
+}
+
+func TestRunPhases_CleanupSurvivesCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	var cleanupErr error
+	runPhases(ctx, nil, phase{"destroy", func(ctx context.Context) error {
+		cleanupErr = ctx.Err()
+		return nil
+	}}, func(phaseResult) {})
+	assert.NoError(t, cleanupErr, "cleanup must not inherit the cancelled context")
+}
+
+func TestReport_FailedPhaseIsAnError(t *testing.T) {
+	cmd := &cobra.Command{}
+	err := report(cmd, "docker", []phaseResult{{Name: "create", OK: true}, {Name: "done"}, {Name: "destroy", OK: true}})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed at done")
+	assert.NoError(t, report(cmd, "docker", []phaseResult{{Name: "create", OK: true}}))
+}
+
+func TestScenario_WritesAndCommits(t *testing.T) {
+	data, err := scenario()
+	require.NoError(t, err)
+	var s struct {
+		Steps []map[string]any `json:"steps"`
+		Exit  int              `json:"exit"`
+	}
+	require.NoError(t, json.Unmarshal(data, &s))
+	assert.Equal(t, 0, s.Exit)
+	assert.Contains(t, s.Steps, map[string]any{"write": outputFile, "content": outputContent})
+	assert.Contains(t, s.Steps, map[string]any{"commit": commitMessage})
+}
+
+func TestPrepare_CommitsTheScenario(t *testing.T) {
+	st := &selftest{projectDir: t.TempDir(), env: testutil.GitEnv()}
+	require.NoError(t, st.prepare(context.Background()))
+	assert.FileExists(t, filepath.Join(st.projectDir, scenarioFile))
+	assert.DirExists(t, filepath.Join(st.projectDir, ".git"))
+	require.NoError(t, st.git(context.Background(), "diff", "--quiet", "HEAD"), "the scenario is in the baseline, not the agent's diff")
+}
@@ -125,7 +125,7 @@ func resolveApproval(ctx context.Context, opts planApplyOpts, unmet []yoloai.Mig
 			"this migration would abandon uncommitted work:\n  - %s\nre-run with --abandon-stopped-overlay to authorize",
 			strings.Join(needsAbandon, "\n  - "))
 	}
-	confirmed, err := cliutil.Confirm(ctx, "Proceed with the migration? [y/N] ", opts.in, opts.errw)
+	confirmed, err := cliutil.Confirm(ctx, "Proceed with the migration?", opts.in, opts.errw)
 	if err != nil {
 		return false, err
 	}
@@ -183,10 +183,12 @@ func printActualRemoval(output io.Writer, result *yoloai.PruneResult, images, is
 			fmt.Fprintf(output, "Removed orphaned lock for %s\n", item.Name) //nolint:errcheck
 		case yoloai.PruneKindSandboxDir:
 			fmt.Fprintf(output, "Removed never-initialized sandbox %s\n", item.Name) //nolint:errcheck
+		case yoloai.PruneKindObjectStore:
+			fmt.Fprintf(output, "Removed unused git object store %s\n", item.Name) //nolint:errcheck
 		case yoloai.PruneKindStaleBase:
 			fmt.Fprintf(output, "Removed superseded base image %s\n", item.Name) //nolint:errcheck
 		default:
-			fmt.Fprintf(output, "Removed %s %s\n", item.Kind, item.Name) //nolint:errcheck
+			fmt.Fprintf(output, "Removed %s %s%s\n", item.Kind, item.Name, detailSuffix(item.Detail)) //nolint:errcheck
 		}
 	}
 	for _, t := range result.Trashed {
@@ -226,13 +228,13 @@ func confirmPrune(cmd *cobra.Command, ctx context.Context, totalItems int, image
 	var prompt string
 	switch {
 	case totalItems == 0 && images:
-		prompt = "Reclaim cache and remove base images (rebuilds yoloai-base on next 'new')? [y/N]: "
+		prompt = "Reclaim cache and remove base images (rebuilds yoloai-base on next 'new')?"
 	case images:
-		prompt = fmt.Sprintf("Remove %d resource(s), reclaim cache, and remove base images (rebuilds yoloai-base on next 'new')? [y/N]: ", totalItems)
+		prompt = fmt.Sprintf("Remove %d resource(s), reclaim cache, and remove base images (rebuilds yoloai-base on next 'new')?", totalItems)
 	case totalItems == 0:
-		prompt = "Reclaim backend cache? [y/N]: "
+		prompt = "Reclaim backend cache?"
 	default:
-		prompt = fmt.Sprintf("Remove %d resource(s) and reclaim cache? [y/N]: ", totalItems)
+		prompt = fmt.Sprintf("Remove %d resource(s) and reclaim cache?", totalItems)
 	}
 	return cliutil.Confirm(ctx, prompt, cmd.InOrStdin(), cmd.ErrOrStderr())
 }
@@ -291,7 +293,7 @@ func emptyTrash(cmd *cobra.Command, ctx context.Context, trash yoloai.TrashSumma
 
 	if !skipConfirm {
 		prompt := fmt.Sprintf(
-			"Delete %d trash item(s) (%s)? This cannot be undone. [y/N]: ",
+			"Delete %d trash item(s) (%s)? This cannot be undone.",
 			trash.Count, cliutil.HumanBytes(trash.Bytes))
 		confirmed, err := cliutil.Confirm(ctx, prompt, cmd.InOrStdin(), cmd.ErrOrStderr())
 		if err != nil {
@@ -317,6 +319,14 @@ func emptyTrash(cmd *cobra.Command, ctx context.Context, trash yoloai.TrashSumma
 	return nil
 }
 
+// detailSuffix formats an orphan's label description after its name.
+func detailSuffix(detail string) string {
+	if detail == "" {
+		return ""
+	}
+	return " (" + detail + ")"
+}
+
 // printPruneFoundItems reports what was found to prune
 // (human-readable only).
 func printPruneFoundItems(output io.Writer, items []yoloai.PruneItem, isJSON bool) {
@@ -328,7 +338,7 @@ func printPruneFoundItems(output io.Writer, items []yoloai.PruneItem, isJSON boo
 		switch item.Kind {
 		case yoloai.PruneKindTempDir:
 			temps = append(temps, item)
-		case yoloai.PruneKindLockFile, yoloai.PruneKindSandboxDir:
+		case yoloai.PruneKindLockFile, yoloai.PruneKindSandboxDir, yoloai.PruneKindObjectStore:
 			hostCruft = append(hostCruft, item)
 		case yoloai.PruneKindStaleBase:
 			staleBases = append(staleBases, item)
@@ -346,7 +356,7 @@ func printPruneFoundItems(output io.Writer, items []yoloai.PruneItem, isJSON boo
 	if len(orphans) > 0 {
 		fmt.Fprintln(output, "Orphaned resources:") //nolint:errcheck
 		for _, item := range orphans {
-			fmt.Fprintf(output, "  %s %s\n", item.Kind, item.Name) //nolint:errcheck
+			fmt.Fprintf(output, "  %s %s%s\n", item.Kind, item.Name, detailSuffix(item.Detail)) //nolint:errcheck
 		}
 		fmt.Fprintln(output) //nolint:errcheck
 	}
@@ -369,8 +379,9 @@ func printPruneFoundItems(output io.Writer, items []yoloai.PruneItem, isJSON boo
 // writePruneJSON outputs prune results as JSON.
 func writePruneJSON(cmd *cobra.Command, result *yoloai.PruneResult, dryRun bool) error {
 	type pruneItem struct {
-		Kind string `json:"kind"`
-		Name string `json:"name"`
+		Kind   string `json:"kind"`
+		Name   string `json:"name"`
+		Detail string `json:"detail,omitempty"`
 	}
 	type refusedItem struct {
 		Name   string `json:"name"`
@@ -384,7 +395,7 @@ func writePruneJSON(cmd *cobra.Command, result *yoloai.PruneResult, dryRun bool)
 	}
 	items := make([]pruneItem, 0, len(result.RemovedItems))
 	for _, item := range result.RemovedItems {
-		items = append(items, pruneItem{Kind: string(item.Kind), Name: item.Name})
+		items = append(items, pruneItem{Kind: string(item.Kind), Name: item.Name, Detail: item.Detail})
 	}
 	refused := make([]refusedItem, 0, len(result.RefusedDataBearing))
 	for _, r := range result.RefusedDataBearing {
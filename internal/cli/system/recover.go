@@ -0,0 +1,68 @@
+package system
+
+// ABOUTME: `yoloai system recover <name>` rebuilds a broken sandbox's metadata
+// ABOUTME: from its retained previous copy, or its work copies and instance labels.
+
+import (
+	"fmt"
+
+	"github.com/kstenerud/yoloai/internal/cli/cliutil"
+
+	"github.com/spf13/cobra"
+)
+
+func newSystemRecoverCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "recover <name>",
+		Short: "Rebuild the metadata of a broken sandbox",
+		Long: `Rebuild the metadata (environment.json) of a sandbox that shows as broken
+because the record is missing or unreadable.
+
+The previous copy of the record, which yoloai keeps beside it on every save, is
+restored when it still matches the sandbox's work copies. Otherwise a minimal
+record is reconstructed from them: each copied directory is found again, and
+the one the agent ran in becomes the workdir. On backends that keep instance
+labels (Docker, Podman), the sandbox's container supplies its yoloai version and
+profile and confirms the workdir.
+
+A copy whose diff baseline is not on record gets the baseline commit yoloai
+made when it copied the directory. A copy of a repo that had no uncommitted
+work got no such commit, so its current HEAD becomes the baseline and anything
+the agent already committed drops out of 'yoloai diff'; check with 'yoloai
+baseline log' and move it with 'yoloai baseline set'. --backend names the
+runtime the sandbox was created on (default: the configured one).`,
+		Args: cobra.ExactArgs(1),
+		RunE: runSystemRecover,
+	}
+	cmd.Flags().String("backend", "", "Runtime backend the sandbox was created on (see 'yoloai system backends')")
+	return cmd
+}
+
+func runSystemRecover(cmd *cobra.Command, args []string) error {
+	name := args[0]
+	sys, err := cliutil.System()
+	if err != nil {
+		return err
+	}
+	env, err := sys.RecoverSandbox(cmd.Context(), name, cliutil.ResolveBackend(cmd))
+	if err != nil {
+		return err
+	}
+	if cliutil.JSONEnabled(cmd) {
+		return cliutil.WriteJSON(cmd.OutOrStdout(), env)
+	}
+	out := cmd.OutOrStdout()
+	fmt.Fprintf(out, "Recovered sandbox %s (%d director%s)\n", name, len(env.Dirs), pluralY(len(env.Dirs))) //nolint:errcheck
+	for _, d := range env.Dirs {
+		fmt.Fprintf(out, "  %s (%s)\n", d.HostPath, d.Mode) //nolint:errcheck
+	}
+	return nil
+}
+
+// pluralY returns the "y"/"ies" suffix for a count.
+func pluralY(n int) string {
+	if n == 1 {
+		return "y"
+	}
+	return "ies"
+}
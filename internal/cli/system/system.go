@@ -32,6 +32,7 @@ func NewCmd(version, commit, date string) *cobra.Command {
 		newSystemDiskCmd(),
 		newSystemMigrateCmd(),
 		newSystemPruneCmd(),
+		newSystemRecoverCmd(),
 		newSystemSetupCmd(),
 		tart.NewCmd(cliutil.System),
 		newCompletionCmd(),
@@ -0,0 +1,105 @@
+// ABOUTME: `yoloai system tart build-image`: build, update, check, or verify the
+// ABOUTME: macOS guest base VM every Tart sandbox is cloned from, and report its build info.
+package tart
+
+import (
+	"fmt"
+	"io"
+
+	yoloai "github.com/kstenerud/yoloai"
+	"github.com/kstenerud/yoloai/yoerrors"
+	"github.com/spf13/cobra"
+)
+
+type buildImageOpts struct {
+	force  bool
+	check  bool
+	verify bool
+}
+
+func newSystemTartBuildImageCmd() *cobra.Command {
+	opts := &buildImageOpts{}
+	cmd := &cobra.Command{
+		Use:   "build-image",
+		Short: "Build or update the macOS guest base VM",
+		Long: `Build or update the macOS guest base VM (yoloai-base) that every Tart
+sandbox is cloned from. The Tart backend runs macOS guests only; there is no
+Linux guest image to build, and Linux sandboxes run on the container backends.
+
+The image is pulled from the upstream base (tart.image, or the Cirrus macOS
+base matching the host), provisioned with the agent tooling, verified, and
+only then swapped in for the old one. It is rebuilt only when missing or when
+its provision inputs changed; --force rebuilds it regardless.
+
+Each build records the yoloai version, a checksum of its provision inputs
+(the provisioning commands and the source image), the source image, and a
+timestamp, on the host and inside the image. --check compares that host-side
+record with the current inputs without touching the VM, so it catches a
+missing or stale image but not a damaged one. --verify also boots a throwaway
+clone of the image, checks the agent tooling, and compares the record inside
+it with the host's; it needs a free VM slot and takes a minute or so.
+
+Examples:
+  yoloai system tart build-image
+  yoloai system tart build-image --force
+  yoloai system tart build-image --check
+  yoloai system tart build-image --verify`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error { return runSystemTartBuildImage(cmd, opts) },
+	}
+
+	cmd.Flags().BoolVar(&opts.force, "force", false, "Rebuild even if the image is current")
+	cmd.Flags().BoolVar(&opts.check, "check", false, "Check the image's build record without building it")
+	cmd.Flags().BoolVar(&opts.verify, "verify", false, "Boot a clone of the image and verify it, without building it")
+	cmd.MarkFlagsMutuallyExclusive("force", "check", "verify")
+
+	return cmd
+}
+
+// runSystemTartBuildImage implements the `system tart build-image` command body.
+func runSystemTartBuildImage(cmd *cobra.Command, opts *buildImageOpts) error {
+	ctx := cmd.Context()
+	sys, err := pkgClient()
+	if err != nil {
+		return err
+	}
+	h := sys.TartBases()
+
+	var img yoloai.TartGuestImage
+	switch {
+	case opts.check:
+		img, err = h.GuestImage(ctx)
+	case opts.verify:
+		img, err = h.VerifyGuestImage(ctx, cmd.OutOrStdout())
+	default:
+		img, err = h.BuildGuestImage(ctx, opts.force, cmd.OutOrStdout())
+	}
+	if err != nil {
+		return err
+	}
+
+	printGuestImage(cmd.OutOrStdout(), img)
+	if img.Problem != "" {
+		return yoerrors.NewUsageError("%s\n\nRun 'yoloai system tart build-image' to rebuild it.", img.Problem)
+	}
+	return nil
+}
+
+// printGuestImage displays the guest image's source and build record.
+func printGuestImage(out io.Writer, img yoloai.TartGuestImage) {
+	fmt.Fprintln(out)                                        //nolint:errcheck
+	fmt.Fprintf(out, "Guest image:   %s\n", img.Name)        //nolint:errcheck
+	fmt.Fprintf(out, "Source image:  %s\n", img.SourceImage) //nolint:errcheck
+	if v := img.Info["yoloai_version"]; v != "" {
+		fmt.Fprintf(out, "Built by:      yoloai %s\n", v) //nolint:errcheck
+	}
+	if t := img.Info["built_at"]; t != "" {
+		fmt.Fprintf(out, "Built at:      %s\n", t) //nolint:errcheck
+	}
+	fmt.Fprintf(out, "Checksum:      %s\n", img.Checksum) //nolint:errcheck
+	status := "ok"
+	if img.Problem != "" {
+		status = img.Problem
+	}
+	fmt.Fprintf(out, "Status:        %s\n", status) //nolint:errcheck
+}
@@ -16,6 +16,7 @@ import (
 	"strings"
 
 	yoloai "github.com/kstenerud/yoloai"
+	"github.com/kstenerud/yoloai/internal/cli/cliutil"
 	"github.com/kstenerud/yoloai/yoerrors"
 	"github.com/spf13/cobra"
 )
@@ -37,8 +38,9 @@ func NewCmd(clientFn func() (*yoloai.System, error)) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:     "tart",
 		Aliases: []string{"runtime"},
-		Short:   "Manage Apple simulator runtime base images (Tart backend)",
-		Long: `Pre-create and manage Tart base VMs with iOS/tvOS/watchOS/visionOS runtimes.
+		Short:   "Manage Tart guest and simulator runtime base images",
+		Long: `Build the Tart macOS guest base VM, and pre-create and manage base VMs
+with iOS/tvOS/watchOS/visionOS runtimes.
 
 Only available on macOS with the Tart backend.`,
 		PersistentPreRunE: requireTartBackend,
@@ -48,6 +50,7 @@ Only available on macOS with the Tart backend.`,
 		newSystemTartAddCmd(),
 		newSystemTartListCmd(),
 		newSystemTartRemoveCmd(),
+		newSystemTartBuildImageCmd(),
 	)
 
 	return cmd
@@ -335,10 +338,11 @@ func runSystemTartRemove(cmd *cobra.Command, args []string, opts *runtimeRemoveO
 // confirmRuntimeRemove prompts the user to confirm deletion. Returns (true, nil) if cancelled.
 func confirmRuntimeRemove(cmd *cobra.Command, baseName string, size int64) (cancelled bool, err error) {
 	fmt.Fprintf(cmd.OutOrStdout(), "\nThis will delete runtime base '%s' (%s).\n", baseName, formatSize(size)) //nolint:errcheck
-	fmt.Fprintf(cmd.OutOrStdout(), "Continue? [y/N]: ")                                                        //nolint:errcheck
-	var response string
-	fmt.Scanln(&response) //nolint:errcheck,gosec
-	if strings.ToLower(strings.TrimSpace(response)) != "y" {
+	confirmed, err := cliutil.Confirm(cmd.Context(), "Continue?", cmd.InOrStdin(), cmd.OutOrStdout())
+	if err != nil {
+		return true, err
+	}
+	if !confirmed {
 		fmt.Fprintln(cmd.OutOrStdout(), "Cancelled.") //nolint:errcheck
 		return true, nil
 	}
@@ -0,0 +1,62 @@
+package cli
+
+// ABOUTME: --timings: prints where a command's time went and records the same
+// ABOUTME: per-phase summary in the sandbox's cli.jsonl as a timing.summary event.
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"time"
+
+	"github.com/kstenerud/yoloai/internal/cli/cliutil"
+	"github.com/kstenerud/yoloai/internal/timing"
+	"github.com/spf13/cobra"
+)
+
+// reportTimings records rec's phases in the sandbox's cli.jsonl, whether or not
+// --timings was given, so a bug report carries them, and prints them when it was.
+func reportTimings(cmd *cobra.Command, rec *timing.Recorder) {
+	total := rec.Total()
+	spans := rec.Spans()
+	if name := rec.Sandbox(); name != "" && len(spans) > 0 {
+		phases := make([]any, 0, len(spans))
+		for _, s := range spans {
+			phases = append(phases, slog.Int64(s.Phase, s.Duration.Milliseconds()))
+		}
+		closeSink := cliutil.OpenCLIJSONLSink(name, cmd)
+		slog.Info("command timings", "event", "timing.summary", "sandbox", name, "command", cmd.CommandPath(),
+			"total_ms", total.Milliseconds(), slog.Group("phases_ms", phases...))
+		closeSink()
+	}
+	if on, _ := cmd.Flags().GetBool("timings"); on {
+		writeTimings(cmd.ErrOrStderr(), cmd.CommandPath(), spans, total)
+	}
+}
+
+// writeTimings prints one line per phase, then whatever the phases don't
+// account for as "other", then the total.
+func writeTimings(w io.Writer, command string, spans []timing.Span, total time.Duration) {
+	fmt.Fprintf(w, "Timings for %s:\n", command) //nolint:errcheck // best-effort output
+	var tracked time.Duration
+	for _, s := range spans {
+		tracked += s.Duration
+		times := ""
+		if s.Count > 1 {
+			times = fmt.Sprintf("  (%d×)", s.Count)
+		}
+		fmt.Fprintf(w, "  %-9s %8s%s\n", s.Phase, formatTiming(s.Duration), times) //nolint:errcheck // best-effort output
+	}
+	if other := total - tracked; len(spans) > 0 && other > 0 {
+		fmt.Fprintf(w, "  %-9s %8s\n", "other", formatTiming(other)) //nolint:errcheck // best-effort output
+	}
+	fmt.Fprintf(w, "  %-9s %8s\n", "total", formatTiming(total)) //nolint:errcheck // best-effort output
+}
+
+// formatTiming renders d to a tenth of a second, or in milliseconds under one.
+func formatTiming(d time.Duration) string {
+	if d < time.Second {
+		return fmt.Sprintf("%dms", d.Milliseconds())
+	}
+	return fmt.Sprintf("%.1fs", d.Seconds())
+}
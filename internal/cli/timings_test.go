@@ -0,0 +1,36 @@
+package cli
+
+// ABOUTME: Tests for the --timings report: one line per phase, the untracked
+// ABOUTME: remainder as "other", and the total.
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kstenerud/yoloai/internal/timing"
+)
+
+func TestWriteTimings(t *testing.T) {
+	var buf bytes.Buffer
+	writeTimings(&buf, "yoloai new", []timing.Span{
+		{Phase: timing.PhaseImage, Duration: 300 * time.Millisecond, Count: 1},
+		{Phase: timing.PhaseCopy, Duration: 8100 * time.Millisecond, Count: 2},
+		{Phase: timing.PhaseBoot, Duration: 1600 * time.Millisecond, Count: 1},
+	}, 12*time.Second)
+
+	assert.Equal(t, "Timings for yoloai new:\n"+
+		"  image        300ms\n"+
+		"  copy          8.1s  (2×)\n"+
+		"  boot          1.6s\n"+
+		"  other         2.0s\n"+
+		"  total        12.0s\n", buf.String())
+}
+
+func TestWriteTimings_NoPhasesShowsOnlyTotal(t *testing.T) {
+	var buf bytes.Buffer
+	writeTimings(&buf, "yoloai ls", nil, 40*time.Millisecond)
+	assert.Equal(t, "Timings for yoloai ls:\n  total         40ms\n", buf.String())
+}
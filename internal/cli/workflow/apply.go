@@ -1,6 +1,7 @@
 // ABOUTME: 'apply' command entry — wires CLI flags to the chosen apply
-// ABOUTME: workflow (format-patch, no-commit, selective, export) and
-// ABOUTME: holds shared helpers (arg parsing, tag transfer, result type).
+// ABOUTME: workflow (format-patch, no-commit, selective, export, fresh clone,
+// ABOUTME: other target, push branch) and holds shared helpers (arg parsing,
+// ABOUTME: tag transfer, result type).
 package workflow
 
 import (
@@ -10,6 +11,7 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/kstenerud/yoloai/internal/cli/cliutil"
 
@@ -39,7 +41,15 @@ type applyResult struct {
 	UncommittedApplied bool   `json:"uncommitted_applied"`
 	TagsApplied        int    `json:"tags_applied"`
 	TagsSkipped        int    `json:"tags_skipped"`
-	Method             string `json:"method"` // "format-patch", "no-commit", "selective", "patches-export"
+	Method             string `json:"method"` // "format-patch", "no-commit", "selective", "patches-export", "fresh-clone", "target", "push-branch", "branch"
+	// FreshClone describes the clone a --fresh-clone apply landed in.
+	FreshClone *freshCloneResult `json:"fresh_clone,omitempty"`
+	// PushedBranch is the origin branch a --push-branch apply pushed to.
+	PushedBranch string `json:"pushed_branch,omitempty"`
+	// Branch is the new host branch a --branch apply committed to.
+	Branch string `json:"branch,omitempty"`
+	// ChangelogFragment is the fragment a --changelog apply wrote.
+	ChangelogFragment string `json:"changelog_fragment,omitempty"`
 }
 
 func NewApplyCmd() *cobra.Command {
@@ -61,14 +71,84 @@ Specific commits can be cherry-picked by providing ref arguments:
   yoloai apply mybox abc123..def456      # range
   yoloai apply mybox                     # all commits (default)
 
+Sandboxes created with provenance_headers enabled get a header comment
+(agent, model, sandbox, date) added to each file the agent created, as
+it is applied or exported. Pass --no-provenance to leave it out.
+
 Use --no-commit to land the changes as a single unstaged patch in the
 working tree instead of replaying the commits (combine with --include-uncommitted
 to include uncommitted edits too). It's also used automatically when the
-target isn't a git repository. Use --patches to export .patch files
-without applying them.
+target isn't a git repository. If that patch doesn't apply cleanly, apply
+offers to land what fits and walk you through the rest: each conflicted
+file opens in $EDITOR beside a .rej of the changes that didn't fit. Use
+--patches to export .patch files without applying them.
+
+Use --interactive (-i) to choose what lands, hunk by hunk, like
+'git add -p': each hunk of the net diff is shown and you accept, skip, or
+edit it. The accepted hunks are applied as one unstaged patch (as with
+--no-commit). The baseline only advances if you took everything as-is; otherwise a
+later 'apply -i' offers every hunk again, so skip the ones already taken.
+
+Use --branch <branch> to land the changes on a new branch of the target
+repository instead of in your working tree: the commits replay onto it
+(a --no-commit patch, and --include-uncommitted edits, become one commit
+each), through a temporary worktree, so your current branch, index and
+files are never touched. The branch starts at the sandbox baseline, or at
+HEAD if the repository doesn't have it, and must not exist yet. Review it,
+then merge it like any other branch. The baseline advances as for any apply.
+
+Use --fresh-clone <dir> to leave the original directory alone: the
+source repo's origin is cloned into <dir> (which must not exist or be
+empty), checked out at the sandbox baseline, and the changes are applied
+there. Handy when your checkout is mid-something, or to check the patch
+against a pristine tree. If origin doesn't have the baseline commit
+(unpushed work), the clone stays on origin's default branch. The
+baseline doesn't advance, so a later apply to the original still works.
+
+Use --target <dir> to apply into another checkout you already have — a
+clean clone, or a 'git worktree' of the same repository — instead of the
+original directory. The commits replay there (as one unstaged patch with
+--no-commit, or when <dir> isn't a git repository), with no prompt. As
+with --fresh-clone, the original and the baseline are left as they are.
+
+A sandbox created with 'new --repo' has no host directory of yours: use
+--push-branch <branch> to replay its commits into the checkout yoloai
+cloned and push them to that branch of the remote, with your own git
+credentials. The baseline advances, so pushing again later to the same
+branch fast-forwards it. Only commits are pushed. --push-branch also
+works with --fresh-clone, pushing from the new clone.
+
+Use --ci-check to run the repository's CI on the changes first: the
+commits are pushed to a temporary yoloai-ci/<name> branch of origin, the
+GitHub Actions runs they start are followed with the GitHub CLI (gh) and
+their results shown as they finish, and the branch is deleted again. The
+apply goes ahead once every run passes; if one fails, you're asked whether
+to apply anyway (with --yes, it stops). --ci-timeout caps the wait.
+
+Use --verify <command> to run a check first — typically the project's
+tests — inside the running sandbox, in the work copy, uncommitted edits
+included. The apply only goes ahead if the command exits zero. A verify
+key in the project's .yoloai.yaml sets the command for every apply of
+the project; --verify overrides it and --no-verify skips it. --dry-run
+and --patches don't verify.
+
+Use --changelog to also write a changelog fragment describing the change
+(the agent's summary, else the prompt) into the project's fragments
+directory: the one the changelog key of .yoloai.yaml names, or else the
+directory of the repository's towncrier configuration. The fragment is
+left untracked for you to commit with the changes. --changelog-type picks
+the towncrier type or Keep a Changelog section.
 
 Examples:
-  yoloai apply mybox --all              # apply all tracked dirs`,
+  yoloai apply mybox --all              # apply all tracked dirs
+  yoloai apply mybox -i                 # pick hunks interactively
+  yoloai apply mybox --branch yoloai/mybox      # land on a new branch
+  yoloai apply mybox --fresh-clone /tmp/check   # apply to a new clone of origin
+  yoloai apply mybox --target ../repo-review    # apply to another checkout
+  yoloai apply mybox --push-branch fix-typo     # push a --repo sandbox's commits
+  yoloai apply mybox --ci-check                 # apply once CI passes
+  yoloai apply mybox --verify "make test"       # apply once the tests pass
+  yoloai apply mybox --changelog-type bugfix    # also write a changelog fragment`,
 		GroupID: cliutil.GroupWorkflow,
 		Args:    cobra.ArbitraryArgs,
 		RunE:    runApplyCmd,
@@ -81,10 +161,49 @@ Examples:
 	cmd.Flags().Bool("dry-run", false, "Show what would be applied without applying")
 	cmd.Flags().Bool("tags", false, "Transfer git tags created by the agent")
 	cmd.Flags().Bool("all", false, "operate on all tracked directories")
+	cmd.Flags().Bool("no-provenance", false, "Don't add provenance headers to new files, even if the sandbox has provenance_headers")
+	cmd.Flags().String("fresh-clone", "", "Clone the source repo's origin into `dir` at the baseline and apply there instead")
+	cmd.Flags().String("target", "", "Apply into the existing `dir` (another clone or worktree) instead of the original directory")
+	cmd.Flags().BoolP("interactive", "i", false, "Choose hunks to apply one at a time (like git add -p); lands them unstaged")
+	cmd.Flags().String("push-branch", "", "Push the commits to `branch` of origin (sandboxes made with --repo, or with --fresh-clone)")
+	cmd.Flags().String("branch", "", "Create `branch` in the target repository and apply there, leaving the current branch and working tree untouched")
+	cmd.Flags().Bool("ci-check", false, "Push the commits to a temporary branch and wait for the repository's CI to pass before applying (needs gh)")
+	cmd.Flags().Duration("ci-timeout", 30*time.Minute, "How long --ci-check waits for CI to finish")
+	cmd.Flags().String("verify", "", "Run `command` in the sandbox against the work copy first and apply only if it exits zero (overrides the .yoloai.yaml verify key)")
+	cmd.Flags().Bool("no-verify", false, "Skip the .yoloai.yaml verify command")
+	cmd.Flags().Bool("changelog", false, "Write a changelog fragment for the change into the project's fragments directory (.yoloai.yaml changelog key, or towncrier's)")
+	cmd.Flags().String("changelog-type", "", "Fragment `type` (towncrier) or section (keep-a-changelog) for --changelog; implies --changelog")
 
 	cmd.MarkFlagsMutuallyExclusive("no-commit", "patches")
 	cmd.MarkFlagsMutuallyExclusive("no-commit", "tags")
 	cmd.MarkFlagsMutuallyExclusive("dry-run", "patches")
+	cmd.MarkFlagsMutuallyExclusive("fresh-clone", "patches")
+	cmd.MarkFlagsMutuallyExclusive("fresh-clone", "dry-run")
+	cmd.MarkFlagsMutuallyExclusive("fresh-clone", "tags")
+	cmd.MarkFlagsMutuallyExclusive("fresh-clone", "all")
+	for _, other := range []string{"patches", "dry-run", "tags", "all", "fresh-clone", "yes"} {
+		cmd.MarkFlagsMutuallyExclusive("interactive", other)
+	}
+	for _, other := range []string{"no-commit", "patches", "include-uncommitted", "tags", "all", "interactive"} {
+		cmd.MarkFlagsMutuallyExclusive("push-branch", other)
+	}
+	for _, other := range []string{"patches", "dry-run", "tags", "all", "fresh-clone", "interactive", "push-branch"} {
+		cmd.MarkFlagsMutuallyExclusive("branch", other)
+	}
+	for _, other := range []string{"patches", "tags", "all", "fresh-clone", "branch", "interactive", "push-branch"} {
+		cmd.MarkFlagsMutuallyExclusive("target", other)
+	}
+	for _, other := range []string{"patches", "dry-run", "include-uncommitted", "all", "interactive", "push-branch"} {
+		cmd.MarkFlagsMutuallyExclusive("ci-check", other)
+	}
+	for _, other := range []string{"no-verify", "patches", "dry-run"} {
+		cmd.MarkFlagsMutuallyExclusive("verify", other)
+	}
+	for _, flag := range []string{"changelog", "changelog-type"} {
+		for _, other := range []string{"patches", "branch", "push-branch"} {
+			cmd.MarkFlagsMutuallyExclusive(flag, other)
+		}
+	}
 
 	return cmd
 }
@@ -97,6 +216,15 @@ type applyFlags struct {
 	includeUncommitted bool
 	dryRun             bool
 	withTags           bool
+	freshClone         string
+	target             string
+	interactive        bool
+	pushBranch         string
+	branch             string
+	ciCheck            bool
+	ciTimeout          time.Duration
+	verify             string
+	noVerify           bool
 }
 
 func runApplyCmd(cmd *cobra.Command, args []string) error {
@@ -134,6 +262,10 @@ func runApplyCmd(cmd *cobra.Command, args []string) error {
 	// consumed a dir specifier. parseApplyArgs needs this to adjust ArgsLenAtDash.
 	argsConsumedBeforeRest := 1
 
+	if err := checkRepoApply(env, name, flags); err != nil {
+		return err
+	}
+
 	hostPath, selectedDir, rest, err := cliutil.SelectTrackedDir(env, rest)
 	if err != nil {
 		return err
@@ -164,9 +296,61 @@ func parseApplyFlags(cmd *cobra.Command) (applyFlags, error) {
 	f.includeUncommitted, _ = cmd.Flags().GetBool("include-uncommitted")
 	f.dryRun, _ = cmd.Flags().GetBool("dry-run")
 	f.withTags, _ = cmd.Flags().GetBool("tags")
+	f.freshClone, _ = cmd.Flags().GetString("fresh-clone")
+	f.target, _ = cmd.Flags().GetString("target")
+	f.interactive, _ = cmd.Flags().GetBool("interactive")
+	f.pushBranch, _ = cmd.Flags().GetString("push-branch")
+	f.branch, _ = cmd.Flags().GetString("branch")
+	f.ciCheck, _ = cmd.Flags().GetBool("ci-check")
+	f.ciTimeout, _ = cmd.Flags().GetDuration("ci-timeout")
+	f.verify, _ = cmd.Flags().GetString("verify")
+	f.noVerify, _ = cmd.Flags().GetBool("no-verify")
+	if f.interactive && cliutil.JSONEnabled(cmd) {
+		return applyFlags{}, yoerrors.NewUsageError("--interactive prompts on the terminal and can't be used with --json")
+	}
+	if f.freshClone != "" {
+		var err error
+		f.freshClone, err = cliutil.ExpandPath(f.freshClone, cliutil.Layout().HomeDir, cliutil.Layout().Env().EnvForConfigInterpolation())
+		if err != nil {
+			return applyFlags{}, fmt.Errorf("expand fresh-clone path: %w", err)
+		}
+	}
+	if f.target != "" {
+		var err error
+		f.target, err = cliutil.ExpandPath(f.target, cliutil.Layout().HomeDir, cliutil.Layout().Env().EnvForConfigInterpolation())
+		if err != nil {
+			return applyFlags{}, fmt.Errorf("expand target path: %w", err)
+		}
+	}
 	return f, nil
 }
 
+// noProvenance reports whether --no-provenance was given.
+func noProvenance(cmd *cobra.Command) bool {
+	v, _ := cmd.Flags().GetBool("no-provenance")
+	return v
+}
+
+// changelogOption returns the changelog fragment --changelog (or
+// --changelog-type) asks the apply for, or nil.
+func changelogOption(cmd *cobra.Command) *yoloai.ApplyChangelog {
+	on, _ := cmd.Flags().GetBool("changelog")
+	kind, _ := cmd.Flags().GetString("changelog-type")
+	if !on && kind == "" {
+		return nil
+	}
+	return &yoloai.ApplyChangelog{Type: kind}
+}
+
+// reportChangelogFragment says where the apply's changelog fragment went
+// (human-readable only; --json carries it as changelog_fragment).
+func reportChangelogFragment(cmd *cobra.Command, result *yoloai.ApplyResult) {
+	if result == nil || result.ChangelogFragment == "" || cliutil.JSONEnabled(cmd) {
+		return
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "Changelog fragment: %s (untracked; commit it with the changes)\n", result.ChangelogFragment) //nolint:errcheck
+}
+
 // dispatchApply validates options and routes to the correct apply workflow.
 func dispatchApply(cmd *cobra.Command, name, hostPath string, selectedDir yoloai.DirInfo, refs, paths []string, flags applyFlags) error {
 	targetDir := selectedDir.HostPath
@@ -175,6 +359,9 @@ func dispatchApply(cmd *cobra.Command, name, hostPath string, selectedDir yoloai
 	if len(refs) > 0 && flags.noCommit {
 		return yoerrors.NewUsageError("--no-commit cannot be used with commit refs — they are mutually exclusive")
 	}
+	if len(refs) > 0 && flags.interactive {
+		return yoerrors.NewUsageError("--interactive applies the net diff and cannot be used with commit refs")
+	}
 	if selectedDir.Mode == yoloai.DirModeRW {
 		return yoerrors.NewUsageError("apply is not needed for :rw directories — changes are already live")
 	}
@@ -185,6 +372,42 @@ func dispatchApply(cmd *cobra.Command, name, hostPath string, selectedDir yoloai
 		return runExport(cmd, name, hostPath, selectedDir, refs, paths, flags.patchesDir, flags.includeUncommitted)
 	}
 
+	// --verify / the project's verify key: gate everything below on the check
+	// passing in the sandbox.
+	if err := maybeVerify(cmd, name, hostPath, flags); err != nil {
+		return err
+	}
+
+	// --ci-check: gate everything below on the repository's CI passing.
+	if flags.ciCheck {
+		if len(refs) > 0 || len(paths) > 0 {
+			return yoerrors.NewUsageError("--ci-check tests all of the sandbox's commits and cannot be used with commit refs or paths")
+		}
+		if err := runCICheck(cmd, name, hostPath, flags.yes, flags.ciTimeout); err != nil {
+			return err
+		}
+	}
+
+	// --push-branch: replay into a yoloai-owned checkout and push a branch.
+	if flags.pushBranch != "" {
+		return applyPushBranch(cmd, name, hostPath, refs, paths, flags)
+	}
+
+	// --fresh-clone: apply into a new clone of origin, leaving targetDir alone.
+	if flags.freshClone != "" {
+		return applyFreshClone(cmd, name, hostPath, refs, paths, flags)
+	}
+
+	// --target: apply into another existing checkout, leaving targetDir alone.
+	if flags.target != "" {
+		return applyToTarget(cmd, name, hostPath, refs, paths, flags)
+	}
+
+	// --branch: commit onto a new branch of targetDir's repo, leaving its checkout alone.
+	if flags.branch != "" {
+		return applyBranch(cmd, name, hostPath, refs, paths, flags)
+	}
+
 	slog.Info("applying changes", "event", "sandbox.apply", "sandbox", name)
 
 	if !cliutil.JSONEnabled(cmd) {
@@ -201,6 +424,11 @@ func dispatchApply(cmd *cobra.Command, name, hostPath string, selectedDir yoloai
 		return applySelectedCommits(cmd, name, hostPath, targetDir, refs, paths, flags.yes, flags.dryRun, flags.withTags)
 	}
 
+	// --interactive: land the hunks the user picks as one unstaged patch.
+	if flags.interactive {
+		return applyInteractive(cmd, name, hostPath, paths, flags.includeUncommitted)
+	}
+
 	// --no-commit: land one unstaged patch (commits only unless --include-uncommitted).
 	if flags.noCommit {
 		return applyNoCommit(cmd, name, hostPath, targetDir, paths, flags.yes, flags.dryRun, flags.includeUncommitted)
@@ -337,6 +565,9 @@ func applyAll(cmd *cobra.Command, name string, flags applyFlags) error {
 	if err != nil {
 		return err
 	}
+	if env.RepoURL != "" {
+		return yoerrors.NewUsageError("sandbox %q was cloned from %s: push its commits with: yoloai apply %s --push-branch <branch>", name, env.RepoURL, name)
+	}
 	tracked := env.TrackedDirs()
 	if len(tracked) == 0 {
 		fmt.Fprintln(cmd.OutOrStdout(), "No tracked directories") //nolint:errcheck
@@ -344,7 +575,7 @@ func applyAll(cmd *cobra.Command, name string, flags applyFlags) error {
 	}
 
 	if !flags.yes && !flags.dryRun {
-		confirmed, promptErr := cliutil.Confirm(cmd.Context(), fmt.Sprintf("Apply changes to all %d tracked directories? [y/N] ", len(tracked)), os.Stdin, cmd.ErrOrStderr())
+		confirmed, promptErr := cliutil.Confirm(cmd.Context(), fmt.Sprintf("Apply changes to all %d tracked directories?", len(tracked)), os.Stdin, cmd.ErrOrStderr())
 		if promptErr != nil {
 			return promptErr
 		}
@@ -385,12 +616,19 @@ func applyOneDir(cmd *cobra.Command, name string, d yoloai.DirInfo, flags applyF
 		}
 	}
 
+	if err := maybeVerify(cmd, name, d.HostPath, flags); err != nil {
+		return err
+	}
+
 	return cliutil.WithTrackedDir(cmd, name, d.HostPath, func(ctx context.Context, wd *yoloai.Workdir) error {
-		_, applyErr := wd.Apply(ctx, yoloai.WorkdirApplyOptions{
+		result, applyErr := wd.Apply(ctx, yoloai.WorkdirApplyOptions{
 			Mode:               mode,
 			IncludeUncommitted: flags.includeUncommitted,
 			DryRun:             flags.dryRun,
+			NoProvenance:       noProvenance(cmd),
+			Changelog:          changelogOption(cmd),
 		})
+		reportChangelogFragment(cmd, result)
 		return applyErr
 	})
 }
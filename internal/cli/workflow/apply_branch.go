@@ -0,0 +1,85 @@
+// ABOUTME: --branch apply workflow — commits the changes onto a new branch of
+// ABOUTME: the target repository, leaving its current branch and working tree alone.
+
+package workflow
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/kstenerud/yoloai/internal/cli/cliutil"
+
+	"github.com/kstenerud/yoloai"
+	"github.com/spf13/cobra"
+)
+
+// applyBranch lands the changes on a new branch of the target repository. The
+// user's checkout isn't touched, so there's no confirmation prompt. Commits
+// replay as a series unless --no-commit; a sandbox with only uncommitted edits
+// (and --include-uncommitted) lands them as one commit, as the default flow
+// lands them as one patch.
+func applyBranch(cmd *cobra.Command, name, hostPath string, refs, paths []string, flags applyFlags) error {
+	mode := yoloai.ApplyModeCommits
+	if flags.noCommit {
+		mode = yoloai.ApplyModeNoCommit
+	}
+
+	slog.Info("applying changes to a new branch", "event", "sandbox.apply.branch", "sandbox", name, "branch", flags.branch)
+
+	var result *yoloai.ApplyResult
+	applyErr := cliutil.WithTrackedDir(cmd, name, hostPath, func(ctx context.Context, wd *yoloai.Workdir) error {
+		opts := yoloai.WorkdirApplyOptions{
+			Mode: mode, Refs: refs, IncludeUncommitted: flags.includeUncommitted, Paths: paths,
+			NoProvenance: noProvenance(cmd), Branch: flags.branch,
+		}
+		var e error
+		result, e = wd.Apply(ctx, opts)
+		if result == nil && e == nil && mode == yoloai.ApplyModeCommits && len(refs) == 0 && flags.includeUncommitted {
+			opts.Mode = yoloai.ApplyModeNoCommit
+			result, e = wd.Apply(ctx, opts)
+		}
+		return e
+	})
+	// As in runApplyCommits: a result alongside an error means the changes
+	// landed but a follow-on step didn't.
+	if result == nil {
+		if applyErr != nil {
+			return applyErr
+		}
+		if cliutil.JSONEnabled(cmd) {
+			return cliutil.WriteJSON(cmd.OutOrStdout(), applyResult{Method: "branch"})
+		}
+		_, err := fmt.Fprintln(cmd.OutOrStdout(), "No changes to apply — no branch was created")
+		return err
+	}
+
+	if cliutil.JSONEnabled(cmd) {
+		if err := cliutil.WriteJSON(cmd.OutOrStdout(), applyResult{
+			Target:             result.Dir,
+			CommitsApplied:     len(result.Commits),
+			UncommittedApplied: result.UncommittedApplied || len(result.Commits) == 0,
+			Method:             "branch",
+			Branch:             result.Branch,
+		}); err != nil {
+			return err
+		}
+		return applyErr
+	}
+
+	out := cmd.OutOrStdout()
+	if len(result.Commits) > 0 {
+		for _, c := range result.Commits {
+			fmt.Fprintf(out, "  %.12s %s\n", c.SourceSHA, c.Subject) //nolint:errcheck
+		}
+		fmt.Fprintf(out, "%d commit(s) applied to branch %s of %s\n", len(result.Commits), result.Branch, result.Dir) //nolint:errcheck
+		if result.UncommittedApplied {
+			fmt.Fprintln(out, "Uncommitted changes committed on top") //nolint:errcheck
+		}
+	} else {
+		fmt.Fprintln(out, result.Stat)                                                        //nolint:errcheck
+		fmt.Fprintf(out, "Changes committed to branch %s of %s\n", result.Branch, result.Dir) //nolint:errcheck
+	}
+	fmt.Fprintf(out, "Your current branch and working tree are unchanged. Review with: git log -p HEAD..%s\n", result.Branch) //nolint:errcheck
+	return applyErr
+}
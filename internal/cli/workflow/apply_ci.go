@@ -0,0 +1,207 @@
+// ABOUTME: apply --ci-check — pushes the sandbox's commits to a scratch branch,
+// ABOUTME: waits for the repository's GitHub Actions runs on it, then gates the apply.
+package workflow
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"time"
+
+	"github.com/kstenerud/yoloai/internal/cli/cliutil"
+
+	yoloai "github.com/kstenerud/yoloai"
+	"github.com/kstenerud/yoloai/yoerrors"
+	"github.com/spf13/cobra"
+)
+
+// ciBranchPrefix namespaces the scratch branch CI runs on, apart from the
+// yoloai/<name> branch 'yoloai pr' pushes.
+const ciBranchPrefix = "yoloai-ci/"
+
+const (
+	// ciPollInterval is how often the runs are polled.
+	ciPollInterval = 15 * time.Second
+	// ciStartGrace is how long to wait for the push to start any run before
+	// concluding the repository has no CI for it.
+	ciStartGrace = 2 * time.Minute
+)
+
+// ciRun is one GitHub Actions run, as `gh run list --json` reports it.
+type ciRun struct {
+	Name       string `json:"name"`
+	Status     string `json:"status"`     // queued, in_progress, completed, ...
+	Conclusion string `json:"conclusion"` // success, failure, cancelled, skipped, ... once completed
+	URL        string `json:"url"`
+}
+
+// state is what the run is doing, or how it ended.
+func (r ciRun) state() string {
+	if r.Status == "completed" {
+		return r.Conclusion
+	}
+	return r.Status
+}
+
+// passed reports whether a completed run counts as a pass.
+func (r ciRun) passed() bool {
+	switch r.Conclusion {
+	case "success", "skipped", "neutral":
+		return true
+	}
+	return false
+}
+
+// runCICheck is the gate behind apply --ci-check. It pushes the sandbox's
+// commits to yoloai-ci/<name> of origin, prints the CI runs on them as they
+// progress, and deletes the branch again. It returns nil when CI passed, or
+// failed and the user chose to apply anyway; the apply then runs as usual.
+func runCICheck(cmd *cobra.Command, name, hostPath string, yes bool, timeout time.Duration) error {
+	if _, err := exec.LookPath("gh"); err != nil {
+		return yoerrors.NewUsageError("--ci-check needs the GitHub CLI (gh): install it from https://cli.github.com and run 'gh auth login'")
+	}
+	out := cmd.OutOrStdout()
+	if cliutil.JSONEnabled(cmd) {
+		out = cmd.ErrOrStderr()
+	}
+	branch := ciBranchPrefix + name
+	pub := yoloai.WorkdirPublishOptions{Ref: "refs/heads/" + branch}
+
+	var result *yoloai.PublishResult
+	err := cliutil.WithTrackedDir(cmd, name, hostPath, func(ctx context.Context, wd *yoloai.Workdir) error {
+		var e error
+		result, e = wd.Publish(ctx, pub)
+		return e
+	})
+	if err != nil {
+		return err
+	}
+	if result == nil {
+		return yoerrors.NewUsageError("sandbox %q has no commits for CI to check; ask the agent to commit its work first", name)
+	}
+	defer func() {
+		// A fresh context: the branch should go even when the wait was interrupted.
+		ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		defer cancel()
+		cleanupErr := cliutil.WithTrackedDir(cmd, name, hostPath, func(_ context.Context, wd *yoloai.Workdir) error {
+			return wd.Unpublish(ctx, pub)
+		})
+		if cleanupErr != nil {
+			fmt.Fprintf(cmd.ErrOrStderr(), "Warning: could not delete CI branch %s: %v\n", branch, cleanupErr) //nolint:errcheck
+		}
+	}()
+	fmt.Fprintf(out, "Pushed %d commit(s) to %s of %s; waiting for CI...\n", result.Commits, branch, result.Remote) //nolint:errcheck
+
+	// gh acts with the user's own login (see runPR).
+	env := cliutil.Layout().Env().EnvForGitHubCLI()
+	w := &ciWaiter{
+		list: func(ctx context.Context) ([]ciRun, error) {
+			raw, err := runGH(ctx, env, "run", "list", "--repo", result.Remote, "--commit", result.PublishedSHA,
+				"--json", "name,status,conclusion,url", "--limit", "100")
+			if err != nil {
+				return nil, err
+			}
+			var runs []ciRun
+			if err := json.Unmarshal([]byte(raw), &runs); err != nil {
+				return nil, fmt.Errorf("parse gh run list: %w", err)
+			}
+			return runs, nil
+		},
+		out:      out,
+		interval: ciPollInterval,
+		grace:    ciStartGrace,
+		timeout:  timeout,
+	}
+	runs, err := w.wait(cmd.Context())
+	if err != nil {
+		return err
+	}
+
+	var failed []ciRun
+	for _, r := range runs {
+		if !r.passed() {
+			failed = append(failed, r)
+		}
+	}
+	if len(failed) == 0 {
+		fmt.Fprintf(out, "CI passed (%d run(s)).\n\n", len(runs)) //nolint:errcheck
+		return nil
+	}
+	fmt.Fprintf(out, "CI failed:\n") //nolint:errcheck
+	for _, r := range failed {
+		fmt.Fprintf(out, "  %s: %s  %s\n", r.Name, r.state(), r.URL) //nolint:errcheck
+	}
+	if yes || cliutil.JSONEnabled(cmd) {
+		return fmt.Errorf("CI failed on %d of %d run(s); nothing applied", len(failed), len(runs))
+	}
+	confirmed, err := cliutil.Confirm(cmd.Context(), "Apply anyway?", os.Stdin, cmd.ErrOrStderr())
+	if err != nil {
+		return err
+	}
+	if !confirmed {
+		return fmt.Errorf("CI failed on %d of %d run(s); nothing applied", len(failed), len(runs))
+	}
+	return nil
+}
+
+// ciWaiter polls the CI runs for a pushed commit until all of them finish,
+// printing each run's state when it changes.
+type ciWaiter struct {
+	list     func(ctx context.Context) ([]ciRun, error)
+	out      io.Writer
+	interval time.Duration
+	grace    time.Duration // for the first run to appear
+	timeout  time.Duration // for all of them to finish
+
+	shown map[string]string // run URL -> last printed state
+}
+
+// wait returns the runs once every one has completed. It fails when no run
+// has started within the grace period, or some are still going at the timeout.
+func (w *ciWaiter) wait(ctx context.Context) ([]ciRun, error) {
+	start := time.Now()
+	for {
+		runs, err := w.list(ctx)
+		if err != nil {
+			return nil, err
+		}
+		w.report(runs)
+		done := len(runs) > 0
+		for _, r := range runs {
+			done = done && r.Status == "completed"
+		}
+		if done {
+			return runs, nil
+		}
+		waited := time.Since(start)
+		if len(runs) == 0 && waited >= w.grace {
+			return nil, yoerrors.NewUsageError("no CI run started within %s: --ci-check waits for GitHub Actions workflows that run on a push to any branch", w.grace)
+		}
+		if waited >= w.timeout {
+			return nil, fmt.Errorf("CI still running after %s; nothing applied", w.timeout)
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(w.interval):
+		}
+	}
+}
+
+// report prints the runs whose state changed since the last poll.
+func (w *ciWaiter) report(runs []ciRun) {
+	if w.shown == nil {
+		w.shown = map[string]string{}
+	}
+	for _, r := range runs {
+		key := r.URL + "\x00" + r.Name
+		if w.shown[key] == r.state() {
+			continue
+		}
+		w.shown[key] = r.state()
+		fmt.Fprintf(w.out, "  %s: %s\n", r.Name, r.state()) //nolint:errcheck
+	}
+}
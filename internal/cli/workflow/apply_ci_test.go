@@ -0,0 +1,85 @@
+// ABOUTME: Tests for apply --ci-check's wait on CI: runs finishing, never
+// ABOUTME: starting, or outlasting the timeout, and which conclusions pass.
+package workflow
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kstenerud/yoloai/yoerrors"
+)
+
+// scriptedRuns returns a list function that answers each poll with the next
+// entry of polls, repeating the last one.
+func scriptedRuns(polls ...[]ciRun) func(context.Context) ([]ciRun, error) {
+	i := 0
+	return func(context.Context) ([]ciRun, error) {
+		runs := polls[min(i, len(polls)-1)]
+		i++
+		return runs, nil
+	}
+}
+
+func newTestWaiter(list func(context.Context) ([]ciRun, error)) (*ciWaiter, *bytes.Buffer) {
+	var out bytes.Buffer
+	return &ciWaiter{list: list, out: &out, interval: time.Millisecond, grace: time.Hour, timeout: time.Hour}, &out
+}
+
+func TestCIWaiter_WaitsForCompletion(t *testing.T) {
+	build := ciRun{Name: "build", URL: "u1"}
+	lint := ciRun{Name: "lint", URL: "u2"}
+	queued := func(r ciRun) ciRun { r.Status = "queued"; return r }
+	running := func(r ciRun) ciRun { r.Status = "in_progress"; return r }
+	done := func(r ciRun, c string) ciRun { r.Status = "completed"; r.Conclusion = c; return r }
+
+	w, out := newTestWaiter(scriptedRuns(
+		nil,
+		[]ciRun{queued(build), queued(lint)},
+		[]ciRun{running(build), queued(lint)},
+		[]ciRun{running(build), queued(lint)},
+		[]ciRun{done(build, "success"), done(lint, "failure")},
+	))
+	runs, err := w.wait(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []ciRun{done(build, "success"), done(lint, "failure")}, runs)
+	assert.Equal(t, "  build: queued\n  lint: queued\n  build: in_progress\n  build: success\n  lint: failure\n",
+		out.String(), "a line per state change, not per poll")
+}
+
+func TestCIWaiter_NoRuns(t *testing.T) {
+	w, _ := newTestWaiter(scriptedRuns(nil))
+	w.grace = 0
+	_, err := w.wait(context.Background())
+	var usage *yoerrors.UsageError
+	assert.ErrorAs(t, err, &usage, "a repository without CI is a usage error")
+}
+
+func TestCIWaiter_Timeout(t *testing.T) {
+	w, _ := newTestWaiter(scriptedRuns([]ciRun{{Name: "build", Status: "in_progress"}}))
+	w.timeout = 0
+	_, err := w.wait(context.Background())
+	assert.ErrorContains(t, err, "still running")
+}
+
+func TestCIWaiter_Cancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	w, _ := newTestWaiter(scriptedRuns([]ciRun{{Name: "build", Status: "queued"}}))
+	w.interval = time.Hour
+	_, err := w.wait(ctx)
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestCIRun_Passed(t *testing.T) {
+	for conclusion, want := range map[string]bool{
+		"success": true, "skipped": true, "neutral": true,
+		"failure": false, "cancelled": false, "timed_out": false, "action_required": false,
+	} {
+		assert.Equal(t, want, ciRun{Status: "completed", Conclusion: conclusion}.passed(), conclusion)
+	}
+}
@@ -36,6 +36,7 @@ func runExport(cmd *cobra.Command, name, hostPath string, _ yoloai.DirInfo, refs
 			Refs:               refs,
 			Paths:              paths,
 			IncludeUncommitted: includeUncommitted,
+			NoProvenance:       noProvenance(cmd),
 		})
 		return exportErr
 	})
@@ -164,7 +164,7 @@ func runApplyCommits(cmd *cobra.Command, name, hostPath, targetDir string, paths
 	}
 
 	if !yes {
-		prompt := fmt.Sprintf("Apply to %s? [y/N] ", targetDir)
+		prompt := fmt.Sprintf("Apply to %s?", targetDir)
 		confirmed, confirmErr := cliutil.Confirm(cmd.Context(), prompt, os.Stdin, cmd.ErrOrStderr())
 		if confirmErr != nil {
 			return confirmErr
@@ -179,6 +179,7 @@ func runApplyCommits(cmd *cobra.Command, name, hostPath, targetDir string, paths
 		var e error
 		result, e = wd.Apply(ctx, yoloai.WorkdirApplyOptions{
 			Mode: yoloai.ApplyModeCommits, IncludeUncommitted: includeUncommitted, Paths: paths,
+			NoProvenance: noProvenance(cmd), Changelog: changelogOption(cmd),
 		})
 		return e
 	})
@@ -193,6 +194,7 @@ func runApplyCommits(cmd *cobra.Command, name, hostPath, targetDir string, paths
 	if !cliutil.JSONEnabled(cmd) {
 		fmt.Fprintf(cmd.OutOrStdout(), "%d commit(s) applied to %s\n", commitsApplied, targetDir) //nolint:errcheck
 	}
+	reportChangelogFragment(cmd, result)
 
 	shaMap := make(map[string]string, len(result.Commits))
 	for _, c := range result.Commits {
@@ -211,6 +213,7 @@ func runApplyCommits(cmd *cobra.Command, name, hostPath, targetDir string, paths
 			TagsApplied:        tagsApplied,
 			TagsSkipped:        tagsSkipped,
 			Method:             "format-patch",
+			ChangelogFragment:  result.ChangelogFragment,
 		}); writeErr != nil {
 			return writeErr
 		}
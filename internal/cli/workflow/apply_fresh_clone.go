@@ -0,0 +1,106 @@
+// ABOUTME: --fresh-clone apply workflow — clones the source repo's origin into
+// ABOUTME: a new directory at the sandbox baseline and applies there instead.
+
+package workflow
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/kstenerud/yoloai/internal/cli/cliutil"
+
+	"github.com/kstenerud/yoloai"
+	"github.com/spf13/cobra"
+)
+
+// freshCloneResult is the JSON description of the clone a --fresh-clone apply
+// landed in.
+type freshCloneResult struct {
+	Dir        string `json:"dir"`
+	Remote     string `json:"remote"`
+	BaseSHA    string `json:"base_sha"`
+	AtBaseline bool   `json:"at_baseline"`
+}
+
+// applyFreshClone lands the changes in a new clone of the source's origin. The
+// original directory isn't touched, so there's no confirmation prompt and the
+// baseline stays put. Commits replay as a series unless --no-commit; a sandbox
+// with only uncommitted edits (and --include-uncommitted) lands them as one
+// unstaged patch, as the default flow does.
+func applyFreshClone(cmd *cobra.Command, name, hostPath string, refs, paths []string, flags applyFlags) error {
+	mode := yoloai.ApplyModeCommits
+	if flags.noCommit {
+		mode = yoloai.ApplyModeNoCommit
+	}
+
+	slog.Info("applying changes to a fresh clone", "event", "sandbox.apply.fresh_clone", "sandbox", name, "dir", flags.freshClone)
+
+	var result *yoloai.ApplyResult
+	applyErr := cliutil.WithTrackedDir(cmd, name, hostPath, func(ctx context.Context, wd *yoloai.Workdir) error {
+		opts := yoloai.WorkdirApplyOptions{
+			Mode: mode, Refs: refs, IncludeUncommitted: flags.includeUncommitted, Paths: paths,
+			NoProvenance: noProvenance(cmd), FreshClone: flags.freshClone,
+			Changelog: changelogOption(cmd),
+		}
+		var e error
+		result, e = wd.Apply(ctx, opts)
+		if result == nil && e == nil && mode == yoloai.ApplyModeCommits && len(refs) == 0 && flags.includeUncommitted {
+			opts.Mode = yoloai.ApplyModeNoCommit
+			result, e = wd.Apply(ctx, opts)
+		}
+		return e
+	})
+	// As in runApplyCommits: a result alongside an error means the changes
+	// landed but a follow-on step didn't.
+	if result == nil {
+		if applyErr != nil {
+			return applyErr
+		}
+		if cliutil.JSONEnabled(cmd) {
+			return cliutil.WriteJSON(cmd.OutOrStdout(), applyResult{Method: "fresh-clone"})
+		}
+		_, err := fmt.Fprintln(cmd.OutOrStdout(), "No changes to apply — no clone was made")
+		return err
+	}
+
+	fc := result.Clone
+	if cliutil.JSONEnabled(cmd) {
+		if err := cliutil.WriteJSON(cmd.OutOrStdout(), applyResult{
+			Target:             result.Dir,
+			CommitsApplied:     len(result.Commits),
+			UncommittedApplied: result.UncommittedApplied || len(result.Commits) == 0,
+			Method:             "fresh-clone",
+			ChangelogFragment:  result.ChangelogFragment,
+			FreshClone: &freshCloneResult{
+				Dir: fc.Dir, Remote: fc.Remote, BaseSHA: fc.BaseSHA, AtBaseline: fc.AtBaseline,
+			},
+		}); err != nil {
+			return err
+		}
+		return applyErr
+	}
+
+	out := cmd.OutOrStdout()
+	fmt.Fprintf(out, "Cloned %s into %s\n", fc.Remote, fc.Dir) //nolint:errcheck
+	if fc.AtBaseline {
+		fmt.Fprintf(out, "Base: %.12s (the sandbox baseline)\n\n", fc.BaseSHA) //nolint:errcheck
+	} else {
+		fmt.Fprintf(out, "Base: %.12s (origin's default branch — the sandbox baseline isn't on origin)\n\n", fc.BaseSHA) //nolint:errcheck
+	}
+	if len(result.Commits) > 0 {
+		for _, c := range result.Commits {
+			fmt.Fprintf(out, "  %.12s %s\n", c.SourceSHA, c.Subject) //nolint:errcheck
+		}
+		fmt.Fprintf(out, "%d commit(s) applied to %s\n", len(result.Commits), fc.Dir) //nolint:errcheck
+		if result.UncommittedApplied {
+			fmt.Fprintln(out, "Uncommitted changes applied as unstaged files") //nolint:errcheck
+		}
+	} else {
+		fmt.Fprintln(out, result.Stat)                                 //nolint:errcheck
+		fmt.Fprintf(out, "Changes applied to %s (unstaged)\n", fc.Dir) //nolint:errcheck
+	}
+	reportChangelogFragment(cmd, result)
+	fmt.Fprintln(out, "The original directory and the sandbox baseline are unchanged.") //nolint:errcheck
+	return applyErr
+}
@@ -0,0 +1,327 @@
+// ABOUTME: --interactive apply workflow — walks the net diff hunk by hunk (like
+// ABOUTME: `git add -p`) and lands only the accepted (or edited) hunks, unstaged.
+
+package workflow
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/kstenerud/yoloai/internal/cli/cliutil"
+	"github.com/kstenerud/yoloai/internal/sysexec"
+	"github.com/kstenerud/yoloai/yoerrors"
+
+	"github.com/kstenerud/yoloai"
+	"github.com/spf13/cobra"
+)
+
+// applyInteractive implements `apply --interactive`. The library generates the
+// net diff and hands it to the selector, which prompts per hunk; the accepted
+// subset lands unstaged, as with --no-commit. The baseline advances only when
+// every hunk was taken unedited; otherwise the whole diff stays pending.
+func applyInteractive(cmd *cobra.Command, name, hostPath string, paths []string, includeUncommitted bool) error {
+	sel := &hunkSelector{
+		prompter: prompter{in: bufio.NewReader(os.Stdin), out: cmd.OutOrStdout()},
+		edit:     editHunkInEditor,
+	}
+
+	var result *yoloai.ApplyResult
+	err := cliutil.WithTrackedDir(cmd, name, hostPath, func(ctx context.Context, wd *yoloai.Workdir) error {
+		sel.ctx = ctx
+		var e error
+		result, e = wd.Apply(ctx, yoloai.WorkdirApplyOptions{
+			Mode: yoloai.ApplyModeNoCommit, IncludeUncommitted: includeUncommitted, Paths: paths,
+			NoProvenance: noProvenance(cmd), SelectHunks: sel.selectHunks, Changelog: changelogOption(cmd),
+		})
+		return e
+	})
+	if err != nil {
+		return err
+	}
+
+	if sel.total == 0 {
+		_, err = fmt.Fprintln(cmd.OutOrStdout(), "No changes to apply")
+		return err
+	}
+	if result == nil {
+		_, err = fmt.Fprintln(cmd.OutOrStdout(), "\nNo changes applied")
+		return err
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "\nApplied %d of %d changes to %s\n", sel.accepted, sel.total, result.Dir) //nolint:errcheck
+	reportChangelogFragment(cmd, result)
+	if sel.accepted < sel.total || sel.edited > 0 {
+		_, err = fmt.Fprintln(cmd.OutOrStdout(), "The diff baseline was not advanced: to bring the rest across, run 'apply -i' again and skip the hunks already applied.")
+	}
+	return err
+}
+
+// hunkSelector drives the per-hunk prompt. Each file's hunks are offered in
+// turn; a file with no hunks (binary, rename-only, deletion) is offered whole.
+type hunkSelector struct {
+	prompter
+	edit func(hunk string) (string, error)
+
+	total    int // changes offered (hunks, plus whole-file changes)
+	accepted int
+	edited   int
+}
+
+const (
+	hunkHelp = `y - apply this hunk
+n - do not apply this hunk
+a - apply this hunk and all later hunks in the file
+d - do not apply this hunk or any later hunks in the file
+e - edit this hunk in $EDITOR, then apply the result
+q - quit; apply only the hunks already accepted
+? - print help
+`
+	fileHelp = `y - apply this change
+n - do not apply this change
+q - quit; apply only the changes already accepted
+? - print help
+`
+)
+
+// selectHunks is the WorkdirApplyOptions.SelectHunks callback.
+func (s *hunkSelector) selectHunks(files []yoloai.PatchFile) ([]yoloai.PatchFile, error) {
+	for _, f := range files {
+		s.total += max(len(f.Hunks), 1)
+	}
+	n := 0
+	var out []yoloai.PatchFile
+	for _, f := range files {
+		fmt.Fprint(s.out, "\n"+f.Header) //nolint:errcheck
+
+		if len(f.Hunks) == 0 {
+			n++
+			ans, err := s.ask(fmt.Sprintf("(%d/%d) Apply this change to %s [y,n,q,?]? ", n, s.total, f.Path), "ynq", fileHelp)
+			if err != nil {
+				return nil, err
+			}
+			switch ans {
+			case 'y':
+				s.accepted++
+				out = append(out, f)
+			case 'q':
+				return out, nil
+			}
+			continue
+		}
+
+		kept := f
+		kept.Hunks = nil
+		rest := byte(0) // 'a' or 'd' once the rest of the file is decided
+		quit := false
+		for _, h := range f.Hunks {
+			n++
+			if quit {
+				break
+			}
+			ans := rest
+			if ans == 0 {
+				fmt.Fprint(s.out, h.String()) //nolint:errcheck
+				var err error
+				ans, err = s.ask(fmt.Sprintf("(%d/%d) Apply this hunk to %s [y,n,a,d,e,q,?]? ", n, s.total, f.Path), "ynadeq", hunkHelp)
+				if err != nil {
+					return nil, err
+				}
+			}
+			switch ans {
+			case 'a':
+				rest = 'a'
+				fallthrough
+			case 'y':
+				s.accepted++
+				kept.Hunks = append(kept.Hunks, h)
+			case 'd':
+				rest = 'd'
+			case 'e':
+				edited, ok, err := s.editHunk(h)
+				if err != nil {
+					return nil, err
+				}
+				if ok {
+					s.accepted++
+					s.edited++
+					kept.Hunks = append(kept.Hunks, edited)
+				}
+			case 'q':
+				quit = true
+			}
+		}
+		if len(kept.Hunks) > 0 {
+			out = append(out, kept)
+		}
+		if quit {
+			return out, nil
+		}
+	}
+	return out, nil
+}
+
+// prompter reads single-letter answers from the terminal, for the
+// interactive apply flows.
+type prompter struct {
+	ctx context.Context //nolint:containedctx // set per Apply call; the prompt must honor Ctrl+C
+	in  *bufio.Reader
+	out io.Writer
+}
+
+// ask prompts until it reads one of the valid answer letters. '?' prints help.
+// EOF on the input counts as 'q', so a closed stdin never applies anything
+// that wasn't explicitly accepted.
+func (s *prompter) ask(prompt, valid, help string) (byte, error) {
+	for {
+		fmt.Fprint(s.out, prompt) //nolint:errcheck
+		line, err := s.readLine()
+		if err == io.EOF {
+			fmt.Fprintln(s.out) //nolint:errcheck
+			return 'q', nil
+		}
+		if err != nil {
+			return 0, err
+		}
+		line = strings.ToLower(strings.TrimSpace(line))
+		if len(line) == 1 && strings.IndexByte(valid, line[0]) >= 0 {
+			return line[0], nil
+		}
+		fmt.Fprint(s.out, help) //nolint:errcheck
+	}
+}
+
+// readLine reads one line, returning early if the context is cancelled
+// (Ctrl+C). The reading goroutine may outlive a cancelled call; the CLI is
+// about to exit then anyway.
+func (s *prompter) readLine() (string, error) {
+	type result struct {
+		line string
+		err  error
+	}
+	ch := make(chan result, 1)
+	go func() {
+		line, err := s.in.ReadString('\n')
+		if err == io.EOF && line != "" {
+			err = nil
+		}
+		ch <- result{line, err}
+	}()
+	ctx := s.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	select {
+	case <-ctx.Done():
+		return "", ctx.Err()
+	case r := <-ch:
+		return r.line, r.err
+	}
+}
+
+// editHunk opens the hunk in the editor and parses the result. ok is false
+// when the user emptied it (meaning: don't apply it). A malformed edit is
+// reported and re-offered rather than failing the whole apply.
+func (s *hunkSelector) editHunk(h yoloai.Hunk) (yoloai.Hunk, bool, error) {
+	text := h.String() + editHunkInstructions
+	for {
+		edited, err := s.edit(text)
+		if err != nil {
+			return yoloai.Hunk{}, false, err
+		}
+		out, ok, err := parseEditedHunk(h.Header, edited)
+		if err == nil {
+			return out, ok, nil
+		}
+		fmt.Fprintf(s.out, "%v\n", err) //nolint:errcheck
+		again, err := s.ask("Edit it again [y,n]? ", "yn", "y - reopen the editor\nn - do not apply this hunk\n")
+		if err != nil {
+			return yoloai.Hunk{}, false, err
+		}
+		if again != 'y' {
+			return yoloai.Hunk{}, false, nil
+		}
+		text = edited
+	}
+}
+
+const editHunkInstructions = `# ---
+# To drop a '-' line, make it a ' ' line (context).
+# To drop a '+' line, delete it.
+# Lines starting with # are removed.
+# Delete everything to not apply this hunk.
+`
+
+// parseEditedHunk turns the editor's output back into a hunk. Comment lines
+// are dropped; an "@@" line, if kept, replaces header (its counts are
+// recomputed on apply anyway). ok is false when no change lines remain.
+func parseEditedHunk(header, text string) (yoloai.Hunk, bool, error) {
+	h := yoloai.Hunk{Header: header}
+	for line := range strings.SplitSeq(text, "\n") {
+		switch {
+		case strings.HasPrefix(line, "#"):
+		case strings.HasPrefix(line, "@@"):
+			h.Header = line
+		case line == "" || strings.ContainsRune(" +-\\", rune(line[0])):
+			h.Lines = append(h.Lines, line)
+		default:
+			return yoloai.Hunk{}, false, yoerrors.NewUsageError("edited hunk line %q doesn't start with ' ', '+' or '-'", line)
+		}
+	}
+	// The trailing newline (and editor-added blank lines) aren't context.
+	for len(h.Lines) > 0 && h.Lines[len(h.Lines)-1] == "" {
+		h.Lines = h.Lines[:len(h.Lines)-1]
+	}
+	for _, l := range h.Lines {
+		if l != "" && (l[0] == '+' || l[0] == '-') {
+			return h, true, nil
+		}
+	}
+	return yoloai.Hunk{}, false, nil
+}
+
+// editHunkInEditor writes text to a temp file, opens it in the user's editor
+// on the terminal, and returns the result.
+func editHunkInEditor(text string) (string, error) {
+	f, err := os.CreateTemp("", "yoloai-hunk-*.diff")
+	if err != nil {
+		return "", fmt.Errorf("create hunk file: %w", err)
+	}
+	path := f.Name()
+	defer os.Remove(path) //nolint:errcheck // best-effort cleanup
+	if _, err := f.WriteString(text); err != nil {
+		f.Close() //nolint:errcheck,gosec // already failing
+		return "", fmt.Errorf("write hunk file: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return "", fmt.Errorf("write hunk file: %w", err)
+	}
+
+	if err := runEditor(path); err != nil {
+		return "", err
+	}
+	data, err := os.ReadFile(path) //nolint:gosec // G304: our own temp file
+	if err != nil {
+		return "", fmt.Errorf("read edited hunk: %w", err)
+	}
+	return string(data), nil
+}
+
+// runEditor opens files in the user's editor ($VISUAL, then $EDITOR, else vi)
+// on the terminal and waits for it to exit. The editor string runs through sh
+// so it may carry arguments ("code -w").
+func runEditor(files ...string) error {
+	hostEnv := cliutil.Layout().Env()
+	editor, ok := hostEnv.Editor()
+	if !ok {
+		editor = "vi"
+	}
+	ed := sysexec.Command(hostEnv.EnvForEditor(), "sh", append([]string{"-c", editor + ` "$@"`, "sh"}, files...)...)
+	ed.Stdin, ed.Stdout, ed.Stderr = os.Stdin, os.Stdout, os.Stderr
+	if err := ed.Run(); err != nil {
+		return fmt.Errorf("editor %q: %w", editor, err)
+	}
+	return nil
+}
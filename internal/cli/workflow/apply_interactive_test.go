@@ -0,0 +1,111 @@
+// ABOUTME: Tests for `apply --interactive`'s hunk selector: y/n/a/d/q answers,
+// ABOUTME: whole-file changes, EOF-as-quit, and parsing an edited hunk.
+package workflow
+
+import (
+	"bufio"
+	"bytes"
+	"strings"
+	"testing"
+
+	yoloai "github.com/kstenerud/yoloai"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testPatchFiles() []yoloai.PatchFile {
+	hunk := func(n string) yoloai.Hunk {
+		return yoloai.Hunk{Header: "@@ -1 +1 @@", Lines: []string{"-old " + n, "+new " + n}}
+	}
+	return []yoloai.PatchFile{
+		{Path: "a.go", Header: "diff --git a/a.go b/a.go\n", Hunks: []yoloai.Hunk{hunk("a1"), hunk("a2"), hunk("a3")}},
+		{Path: "logo.png", Header: "diff --git a/logo.png b/logo.png\nBinary files differ\n"},
+		{Path: "b.go", Header: "diff --git a/b.go b/b.go\n", Hunks: []yoloai.Hunk{hunk("b1"), hunk("b2")}},
+	}
+}
+
+func newTestSelector(input string) (*hunkSelector, *bytes.Buffer) {
+	var out bytes.Buffer
+	return &hunkSelector{prompter: prompter{in: bufio.NewReader(strings.NewReader(input)), out: &out}}, &out
+}
+
+// hunkNames flattens a selection to "path:firstline" for easy comparison.
+func hunkNames(files []yoloai.PatchFile) []string {
+	var names []string
+	for _, f := range files {
+		if len(f.Hunks) == 0 {
+			names = append(names, f.Path)
+		}
+		for _, h := range f.Hunks {
+			names = append(names, f.Path+":"+h.Lines[1])
+		}
+	}
+	return names
+}
+
+func TestHunkSelector_YesNo(t *testing.T) {
+	s, _ := newTestSelector("y\nn\ny\nn\nn\ny\n")
+	got, err := s.selectHunks(testPatchFiles())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a.go:+new a1", "a.go:+new a3", "b.go:+new b2"}, hunkNames(got))
+	assert.Equal(t, 6, s.total)
+	assert.Equal(t, 3, s.accepted)
+}
+
+func TestHunkSelector_AllAndDoneRestOfFile(t *testing.T) {
+	// a: take the first, then "a" takes the rest; binary: yes; b: "d" skips both.
+	s, out := newTestSelector("n\na\ny\nd\n")
+	got, err := s.selectHunks(testPatchFiles())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a.go:+new a2", "a.go:+new a3", "logo.png"}, hunkNames(got))
+	assert.NotContains(t, out.String(), "+new b2", "hunks decided by 'd' aren't shown")
+}
+
+func TestHunkSelector_QuitKeepsEarlierChoices(t *testing.T) {
+	s, _ := newTestSelector("y\nq\n")
+	got, err := s.selectHunks(testPatchFiles())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a.go:+new a1"}, hunkNames(got))
+}
+
+func TestHunkSelector_EOFQuits(t *testing.T) {
+	s, _ := newTestSelector("y\n")
+	got, err := s.selectHunks(testPatchFiles())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a.go:+new a1"}, hunkNames(got))
+}
+
+func TestHunkSelector_UnknownAnswerShowsHelp(t *testing.T) {
+	s, out := newTestSelector("x\nq\n")
+	_, err := s.selectHunks(testPatchFiles())
+	require.NoError(t, err)
+	assert.Contains(t, out.String(), "e - edit this hunk")
+}
+
+func TestHunkSelector_Edit(t *testing.T) {
+	s, _ := newTestSelector("e\nq\n")
+	s.edit = func(text string) (string, error) {
+		assert.Contains(t, text, "+new a1")
+		return "@@ -1 +1 @@\n-old a1\n+edited a1\n+extra\n# comment\n", nil
+	}
+	got, err := s.selectHunks(testPatchFiles())
+	require.NoError(t, err)
+	require.Len(t, got, 1)
+	assert.Equal(t, []string{"-old a1", "+edited a1", "+extra"}, got[0].Hunks[0].Lines)
+	assert.Equal(t, 1, s.edited)
+}
+
+func TestParseEditedHunk(t *testing.T) {
+	h, ok, err := parseEditedHunk("@@ -1,2 +1,2 @@", " ctx\n\n-gone\n# note\n\n\n")
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "@@ -1,2 +1,2 @@", h.Header)
+	assert.Equal(t, []string{" ctx", "", "-gone"}, h.Lines)
+
+	_, ok, err = parseEditedHunk("@@ -1 +1 @@", "# everything deleted\n")
+	require.NoError(t, err)
+	assert.False(t, ok, "an emptied hunk isn't applied")
+
+	_, _, err = parseEditedHunk("@@ -1 +1 @@", "+ok\nnot a diff line\n")
+	require.Error(t, err)
+}
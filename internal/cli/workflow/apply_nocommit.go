@@ -5,6 +5,7 @@ package workflow
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 
@@ -18,7 +19,8 @@ import (
 // (Workdir().Apply) owns generate/validate/apply/advance-baseline; this
 // function owns the CLI preview + confirmation + output. It previews via
 // DryRun (so the stat is exact, matching what the real apply lands), then —
-// after confirmation — applies for real.
+// after confirmation — applies for real. A preview that doesn't apply cleanly
+// turns into the offer to resolve the conflicts (see resolveConflicts).
 func applyNoCommit(cmd *cobra.Command, name, hostPath, targetDir string, paths []string, yes, dryRun, includeUncommitted bool) error {
 	backend := cliutil.ResolveBackendForSandbox(name)
 
@@ -30,6 +32,10 @@ func applyNoCommit(cmd *cobra.Command, name, hostPath, targetDir string, paths [
 		})
 		return e
 	})
+	var conflict *yoloai.ApplyConflictError
+	if errors.As(err, &conflict) && !yes && !dryRun && !cliutil.JSONEnabled(cmd) {
+		return resolveConflicts(cmd, name, hostPath, paths, includeUncommitted, conflict)
+	}
 	if err != nil {
 		return err
 	}
@@ -63,7 +69,7 @@ func applyNoCommit(cmd *cobra.Command, name, hostPath, targetDir string, paths [
 	}
 
 	if !yes {
-		prompt := fmt.Sprintf("Apply these changes to %s? [y/N] ", applyTarget)
+		prompt := fmt.Sprintf("Apply these changes to %s?", applyTarget)
 		confirmed, confirmErr := cliutil.Confirm(cmd.Context(), prompt, os.Stdin, cmd.ErrOrStderr())
 		if confirmErr != nil {
 			return confirmErr
@@ -73,9 +79,12 @@ func applyNoCommit(cmd *cobra.Command, name, hostPath, targetDir string, paths [
 		}
 	}
 
+	var result *yoloai.ApplyResult
 	err = cliutil.WithTrackedDir(cmd, name, hostPath, func(ctx context.Context, wd *yoloai.Workdir) error {
-		_, e := wd.Apply(ctx, yoloai.WorkdirApplyOptions{
+		var e error
+		result, e = wd.Apply(ctx, yoloai.WorkdirApplyOptions{
 			Mode: yoloai.ApplyModeNoCommit, IncludeUncommitted: includeUncommitted, Paths: paths, DryRun: false,
+			NoProvenance: noProvenance(cmd), Changelog: changelogOption(cmd),
 		})
 		return e
 	})
@@ -84,14 +93,22 @@ func applyNoCommit(cmd *cobra.Command, name, hostPath, targetDir string, paths [
 	}
 
 	if cliutil.JSONEnabled(cmd) {
+		fragment := ""
+		if result != nil {
+			fragment = result.ChangelogFragment
+		}
 		return cliutil.WriteJSON(cmd.OutOrStdout(), applyResult{
 			Target:             applyTarget,
 			UncommittedApplied: true,
 			Method:             "no-commit",
+			ChangelogFragment:  fragment,
 		})
 	}
-	_, err = fmt.Fprintf(cmd.OutOrStdout(), "Changes applied to %s\n", applyTarget)
-	return err
+	if _, err = fmt.Fprintf(cmd.OutOrStdout(), "Changes applied to %s\n", applyTarget); err != nil {
+		return err
+	}
+	reportChangelogFragment(cmd, result)
+	return nil
 }
 
 // warnNoCommitSkippedUncommitted prints the --include-uncommitted hint when
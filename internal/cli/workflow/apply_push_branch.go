@@ -0,0 +1,123 @@
+// ABOUTME: --push-branch apply workflow — replays the commits into a checkout
+// ABOUTME: yoloai owns (a --repo sandbox's, or a fresh clone) and pushes a branch.
+
+package workflow
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+
+	"github.com/kstenerud/yoloai/internal/cli/cliutil"
+
+	"github.com/kstenerud/yoloai"
+	"github.com/kstenerud/yoloai/yoerrors"
+	"github.com/spf13/cobra"
+)
+
+// checkRepoApply enforces where each apply can land for a sandbox created with
+// --repo, whose "host directory" is a checkout yoloai made: the work goes back
+// as a branch of the remote (or exported as patches), never into that
+// checkout alone where nobody would see it. --push-branch, in turn, only
+// pushes from a checkout yoloai owns — a --repo one or a --fresh-clone — so it
+// never publishes whatever else sits in the user's own repo.
+func checkRepoApply(env *yoloai.Environment, name string, flags applyFlags) error {
+	if env.RepoURL == "" {
+		if flags.pushBranch != "" && flags.freshClone == "" {
+			return yoerrors.NewUsageError("--push-branch pushes from a checkout yoloai owns: use it with --fresh-clone <dir>, or on a sandbox created with --repo")
+		}
+		return nil
+	}
+	if flags.pushBranch == "" && flags.patchesDir == "" && flags.freshClone == "" && !flags.dryRun {
+		return yoerrors.NewUsageError("sandbox %q was cloned from %s and has no host directory to apply to: "+
+			"push its commits with: yoloai apply %s --push-branch <branch>   (or export them with --patches <dir>)",
+			name, env.RepoURL, name)
+	}
+	return nil
+}
+
+// applyPushBranch replays the sandbox's commits and pushes them to a branch of
+// origin. For a --repo sandbox they land in its own checkout, so the baseline
+// advances and a later push to the same branch fast-forwards; with
+// --fresh-clone they land in the new clone instead. Only commits are pushed.
+func applyPushBranch(cmd *cobra.Command, name, hostPath string, refs, paths []string, flags applyFlags) error {
+	var preview *yoloai.ApplyResult
+	err := cliutil.WithTrackedDir(cmd, name, hostPath, func(ctx context.Context, wd *yoloai.Workdir) error {
+		var e error
+		preview, e = wd.Apply(ctx, yoloai.WorkdirApplyOptions{
+			Mode: yoloai.ApplyModeCommits, Refs: refs, Paths: paths, DryRun: true,
+		})
+		return e
+	})
+	if err != nil {
+		return err
+	}
+	if preview == nil {
+		if cliutil.JSONEnabled(cmd) {
+			return cliutil.WriteJSON(cmd.OutOrStdout(), applyResult{Method: "push-branch"})
+		}
+		_, err := fmt.Fprintln(cmd.OutOrStdout(), "No commits to push")
+		return err
+	}
+
+	if !cliutil.JSONEnabled(cmd) {
+		out := cmd.OutOrStdout()
+		fmt.Fprintf(out, "Commits to push to branch %s (%d):\n", flags.pushBranch, len(preview.Commits)) //nolint:errcheck
+		for _, c := range preview.Commits {
+			fmt.Fprintf(out, "  %.12s %s\n", c.SourceSHA, c.Subject) //nolint:errcheck
+		}
+		fmt.Fprintln(out) //nolint:errcheck
+	}
+	if flags.dryRun {
+		if !cliutil.JSONEnabled(cmd) {
+			fmt.Fprintln(cmd.OutOrStdout(), "(dry run)") //nolint:errcheck
+		}
+		return nil
+	}
+	if !flags.yes {
+		prompt := fmt.Sprintf("Push to branch %s of origin?", flags.pushBranch)
+		confirmed, confirmErr := cliutil.Confirm(cmd.Context(), prompt, os.Stdin, cmd.ErrOrStderr())
+		if confirmErr != nil {
+			return confirmErr
+		}
+		if !confirmed {
+			return nil
+		}
+	}
+
+	slog.Info("applying changes to a branch", "event", "sandbox.apply.push_branch", "sandbox", name, "branch", flags.pushBranch)
+
+	var result *yoloai.ApplyResult
+	applyErr := cliutil.WithTrackedDir(cmd, name, hostPath, func(ctx context.Context, wd *yoloai.Workdir) error {
+		var e error
+		result, e = wd.Apply(ctx, yoloai.WorkdirApplyOptions{
+			Mode: yoloai.ApplyModeCommits, Refs: refs, Paths: paths,
+			NoProvenance: noProvenance(cmd), FreshClone: flags.freshClone, PushBranch: flags.pushBranch,
+		})
+		return e
+	})
+	// As in runApplyCommits: a result alongside an error means the commits
+	// landed locally but a follow-on step (here, typically the push) didn't.
+	if result == nil {
+		return applyErr
+	}
+
+	if cliutil.JSONEnabled(cmd) {
+		if err := cliutil.WriteJSON(cmd.OutOrStdout(), applyResult{
+			Target:         result.Dir,
+			CommitsApplied: len(result.Commits),
+			Method:         "push-branch",
+			PushedBranch:   result.PushedBranch,
+		}); err != nil {
+			return err
+		}
+		return applyErr
+	}
+	if result.PushedBranch != "" {
+		fmt.Fprintf(cmd.OutOrStdout(), "%d commit(s) pushed to branch %s\n", len(result.Commits), result.PushedBranch) //nolint:errcheck
+	} else {
+		fmt.Fprintf(cmd.OutOrStdout(), "%d commit(s) applied to %s but not pushed\n", len(result.Commits), result.Dir) //nolint:errcheck
+	}
+	return applyErr
+}
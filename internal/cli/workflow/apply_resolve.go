@@ -0,0 +1,187 @@
+// ABOUTME: Conflict resolution for a --no-commit apply that doesn't fit: lands
+// ABOUTME: what does, then walks each .rej with its file in $EDITOR until resolved.
+
+package workflow
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/kstenerud/yoloai/internal/cli/cliutil"
+
+	"github.com/kstenerud/yoloai"
+	"github.com/spf13/cobra"
+)
+
+// resolveConflicts is offered when a --no-commit apply's net diff doesn't
+// apply cleanly. On a yes it applies the hunks that fit and leaves the rest in
+// .rej files (git apply --reject), then opens each .rej beside its file in the
+// editor until the user marks it resolved, deleting the .rej as they go. The
+// baseline advances once nothing is left; .rej files still open are listed,
+// and the apply fails so a script notices.
+func resolveConflicts(cmd *cobra.Command, name, hostPath string, paths []string, includeUncommitted bool, conflict *yoloai.ApplyConflictError) error {
+	out := cmd.OutOrStdout()
+	fmt.Fprintf(out, "%v\n\n", conflict) //nolint:errcheck
+	confirmed, err := cliutil.Confirm(cmd.Context(), "Apply the changes that fit and resolve the rest in your editor?", os.Stdin, cmd.ErrOrStderr())
+	if err != nil {
+		return err
+	}
+	if !confirmed {
+		return conflict
+	}
+
+	env, err := cliutil.SandboxMetadata(cmd, name)
+	if err != nil {
+		return err
+	}
+	expected := trackedDirBaseline(env, hostPath)
+
+	var result *yoloai.ApplyResult
+	err = cliutil.WithTrackedDir(cmd, name, hostPath, func(ctx context.Context, wd *yoloai.Workdir) error {
+		var e error
+		result, e = wd.Apply(ctx, yoloai.WorkdirApplyOptions{
+			Mode: yoloai.ApplyModeNoCommit, IncludeUncommitted: includeUncommitted, Paths: paths,
+			NoProvenance: noProvenance(cmd), Reject: true, Changelog: changelogOption(cmd),
+		})
+		return e
+	})
+	if err != nil {
+		return err
+	}
+	if result == nil {
+		_, err = fmt.Fprintln(out, "No changes to apply")
+		return err
+	}
+	if len(result.Rejects) == 0 {
+		if _, err = fmt.Fprintf(out, "Changes applied to %s\n", result.Dir); err != nil {
+			return err
+		}
+		reportChangelogFragment(cmd, result)
+		return nil
+	}
+
+	fmt.Fprintf(out, "Applied what fit to %s; %d file(s) have conflicts.\n", result.Dir, len(result.Rejects)) //nolint:errcheck
+	r := &rejectResolver{
+		prompter: prompter{ctx: cmd.Context(), in: bufio.NewReader(os.Stdin), out: out},
+		edit:     runEditor,
+	}
+	left, err := r.resolve(result.Rejects)
+	if err != nil {
+		return err
+	}
+	if len(left) > 0 {
+		fmt.Fprintf(out, "\n%d of %d conflict(s) left:\n", len(left), len(result.Rejects)) //nolint:errcheck
+		for _, rej := range left {
+			fmt.Fprintf(out, "  %s\n", rej) //nolint:errcheck
+		}
+		fmt.Fprintf(out, "Resolve each against the file beside it and delete the .rej; then run 'yoloai baseline advance %s'.\n", name) //nolint:errcheck
+		return fmt.Errorf("%d conflict(s) left unresolved", len(left))
+	}
+
+	fmt.Fprintf(out, "\nAll conflicts resolved; changes applied to %s\n", result.Dir) //nolint:errcheck
+	// A path-filtered apply leaves the baseline alone, as a clean one would.
+	if len(paths) > 0 {
+		return nil
+	}
+	return cliutil.WithTrackedDir(cmd, name, hostPath, func(ctx context.Context, wd *yoloai.Workdir) error {
+		_, e := wd.AdvanceBaseline(ctx, expected)
+		return e
+	})
+}
+
+// trackedDirBaseline returns the baseline of the tracked dir at hostPath (""
+// for the workdir): the compare-and-swap token for advancing it afterwards.
+func trackedDirBaseline(env *yoloai.Environment, hostPath string) string {
+	if hostPath == "" {
+		return env.Workdir().BaselineSHA
+	}
+	for _, d := range env.Dirs {
+		if d.HostPath == hostPath {
+			return d.BaselineSHA
+		}
+	}
+	return ""
+}
+
+// rejectResolver walks the .rej files a conflicted apply left, one at a time.
+type rejectResolver struct {
+	prompter
+	edit func(files ...string) error
+
+	resolved int
+}
+
+const rejectHelp = `y - resolved: delete the .rej
+n - not yet: keep the .rej and move on
+e - open the editor again
+q - quit; keep this and the remaining .rej files
+? - print help
+`
+
+// resolve opens each .rej with its target in the editor, then asks whether it
+// is resolved. It returns the .rej files still left. Deleting a .rej in the
+// editor counts as resolving it.
+func (r *rejectResolver) resolve(rejects []string) ([]string, error) {
+	var left []string
+	for i, rej := range rejects {
+		target := strings.TrimSuffix(rej, ".rej")
+		fmt.Fprintf(r.out, "\n(%d/%d) %s: %s\n", i+1, len(rejects), target, describeReject(rej)) //nolint:errcheck
+		files := []string{target, rej}
+		if _, err := os.Stat(target); err != nil {
+			files = files[1:]
+		}
+		for done := false; !done; {
+			if err := r.edit(files...); err != nil {
+				return nil, err
+			}
+			if _, err := os.Stat(rej); errors.Is(err, os.ErrNotExist) {
+				r.resolved++
+				break
+			}
+			ans, err := r.ask(fmt.Sprintf("(%d/%d) Is %s resolved [y,n,e,q,?]? ", i+1, len(rejects), target), "yneq", rejectHelp)
+			if err != nil {
+				return nil, err
+			}
+			switch ans {
+			case 'y':
+				if err := os.Remove(rej); err != nil && !errors.Is(err, os.ErrNotExist) {
+					return nil, fmt.Errorf("remove %s: %w", rej, err)
+				}
+				r.resolved++
+				done = true
+			case 'n':
+				left = append(left, rej)
+				done = true
+			case 'q':
+				return append(left, rejects[i:]...), nil
+			}
+		}
+	}
+	return left, nil
+}
+
+// describeReject summarizes what a .rej holds: git's rejected hunks, or, for
+// a file git couldn't patch at all, its whole diff.
+func describeReject(rej string) string {
+	data, err := os.ReadFile(rej) //nolint:gosec // G304: a .rej the apply just wrote
+	if err != nil {
+		return "conflicted"
+	}
+	if strings.HasPrefix(string(data), "diff --git ") {
+		return "none of its changes applied"
+	}
+	n := 0
+	for line := range strings.SplitSeq(string(data), "\n") {
+		if strings.HasPrefix(line, "@@") {
+			n++
+		}
+	}
+	if n == 1 {
+		return "1 hunk didn't apply"
+	}
+	return fmt.Sprintf("%d hunks didn't apply", n)
+}
@@ -0,0 +1,113 @@
+// ABOUTME: Tests for resolving a conflicted apply's .rej files: y/n/e/q answers,
+// ABOUTME: a .rej deleted in the editor, and describing what a .rej holds.
+package workflow
+
+import (
+	"bufio"
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testRej = "diff a/f.txt b/f.txt\t(rejected hunks)\n@@ -1 +1 @@\n-a\n+b\n@@ -9 +9 @@\n-c\n+d\n"
+
+// rejectFixture writes n target files, each with a .rej, and returns the .rej paths.
+func rejectFixture(t *testing.T, n int) []string {
+	t.Helper()
+	dir := t.TempDir()
+	var rejects []string
+	for i := range n {
+		target := filepath.Join(dir, string(rune('a'+i))+".txt")
+		require.NoError(t, os.WriteFile(target, []byte("x\n"), 0o600))
+		require.NoError(t, os.WriteFile(target+".rej", []byte(testRej), 0o600))
+		rejects = append(rejects, target+".rej")
+	}
+	return rejects
+}
+
+func newTestResolver(input string, edit func(files ...string) error) (*rejectResolver, *bytes.Buffer) {
+	var out bytes.Buffer
+	return &rejectResolver{prompter: prompter{in: bufio.NewReader(strings.NewReader(input)), out: &out}, edit: edit}, &out
+}
+
+func TestRejectResolver_YesNo(t *testing.T) {
+	rejects := rejectFixture(t, 3)
+	var opened [][]string
+	r, _ := newTestResolver("y\nn\ny\n", func(files ...string) error {
+		opened = append(opened, files)
+		return nil
+	})
+	left, err := r.resolve(rejects)
+	require.NoError(t, err)
+	assert.Equal(t, []string{rejects[1]}, left)
+	assert.Equal(t, 2, r.resolved)
+	assert.NoFileExists(t, rejects[0])
+	assert.FileExists(t, rejects[1])
+	assert.NoFileExists(t, rejects[2])
+	assert.Equal(t, []string{strings.TrimSuffix(rejects[0], ".rej"), rejects[0]}, opened[0], "the file opens beside its .rej")
+}
+
+func TestRejectResolver_EditAgainThenQuit(t *testing.T) {
+	rejects := rejectFixture(t, 3)
+	edits := 0
+	r, _ := newTestResolver("e\ny\nq\n", func(...string) error {
+		edits++
+		return nil
+	})
+	left, err := r.resolve(rejects)
+	require.NoError(t, err)
+	assert.Equal(t, 3, edits, "e reopens the editor")
+	assert.Equal(t, rejects[1:], left, "quitting keeps the current and remaining .rej files")
+	for _, rej := range left {
+		assert.FileExists(t, rej)
+	}
+}
+
+func TestRejectResolver_DeletedInEditor(t *testing.T) {
+	rejects := rejectFixture(t, 1)
+	r, out := newTestResolver("", func(files ...string) error {
+		return os.Remove(files[len(files)-1])
+	})
+	left, err := r.resolve(rejects)
+	require.NoError(t, err)
+	assert.Empty(t, left)
+	assert.Equal(t, 1, r.resolved)
+	assert.NotContains(t, out.String(), "resolved [y", "no question once the .rej is gone")
+}
+
+func TestRejectResolver_MissingTargetOpensRejOnly(t *testing.T) {
+	rejects := rejectFixture(t, 1)
+	require.NoError(t, os.Remove(strings.TrimSuffix(rejects[0], ".rej")))
+	var opened []string
+	r, _ := newTestResolver("n\n", func(files ...string) error {
+		opened = files
+		return nil
+	})
+	_, err := r.resolve(rejects)
+	require.NoError(t, err)
+	assert.Equal(t, rejects, opened)
+}
+
+func TestRejectResolver_EOFQuits(t *testing.T) {
+	rejects := rejectFixture(t, 2)
+	r, _ := newTestResolver("", func(...string) error { return nil })
+	left, err := r.resolve(rejects)
+	require.NoError(t, err)
+	assert.Equal(t, rejects, left, "a closed stdin resolves nothing")
+}
+
+func TestDescribeReject(t *testing.T) {
+	dir := t.TempDir()
+	hunks := filepath.Join(dir, "hunks.rej")
+	require.NoError(t, os.WriteFile(hunks, []byte(testRej), 0o600))
+	assert.Equal(t, "2 hunks didn't apply", describeReject(hunks))
+
+	whole := filepath.Join(dir, "whole.rej")
+	require.NoError(t, os.WriteFile(whole, []byte("diff --git a/f b/f\ndeleted file mode 100644\n"), 0o600))
+	assert.Equal(t, "none of its changes applied", describeReject(whole))
+}
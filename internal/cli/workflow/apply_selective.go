@@ -73,13 +73,14 @@ func applySelectedCommits(cmd *cobra.Command, name, hostPath, targetDir string,
 	if !cliutil.JSONEnabled(cmd) {
 		fmt.Fprintf(cmd.OutOrStdout(), "%d commit(s) applied to %s\n", len(result.Commits), targetDir) //nolint:errcheck
 	}
+	reportChangelogFragment(cmd, result)
 
 	shaMap := make(map[string]string, len(result.Commits))
 	for _, c := range result.Commits {
 		shaMap[strings.ToLower(c.SourceSHA)] = c.HostSHA
 	}
 
-	return finishSelectiveApply(cmd, name, hostPath, len(result.Commits), shaMap, applyErr, selectedTags, targetDir, withTags)
+	return finishSelectiveApply(cmd, name, hostPath, len(result.Commits), shaMap, applyErr, selectedTags, targetDir, withTags, result.ChangelogFragment)
 }
 
 // runSeriesApply runs a commit-series apply through the workdir handle — dryRun
@@ -99,10 +100,12 @@ func runSeriesApply(cmd *cobra.Command, name, hostPath string, backend yoloai.Ba
 			return wdErr
 		}
 		result, applyErr = wd.Apply(ctx, yoloai.WorkdirApplyOptions{
-			Mode:   yoloai.ApplyModeCommits,
-			Refs:   refs,
-			Paths:  paths,
-			DryRun: dryRun,
+			Mode:         yoloai.ApplyModeCommits,
+			Refs:         refs,
+			Paths:        paths,
+			DryRun:       dryRun,
+			NoProvenance: noProvenance(cmd),
+			Changelog:    changelogOption(cmd),
 		})
 		return applyErr
 	})
@@ -125,13 +128,13 @@ func confirmSelectiveApply(cmd *cobra.Command, yes bool, targetDir string) (bool
 	if yes {
 		return true, nil
 	}
-	prompt := fmt.Sprintf("Apply to %s? [y/N] ", targetDir)
+	prompt := fmt.Sprintf("Apply to %s?", targetDir)
 	confirmed, confirmErr := cliutil.Confirm(cmd.Context(), prompt, os.Stdin, cmd.ErrOrStderr())
 	return confirmed, confirmErr
 }
 
 // finishSelectiveApply prints results, handles tags, and returns any follow-on error.
-func finishSelectiveApply(cmd *cobra.Command, name, hostPath string, commitsApplied int, shaMap map[string]string, applyErr error, selectedTags []yoloai.TagInfo, targetDir string, withTags bool) error {
+func finishSelectiveApply(cmd *cobra.Command, name, hostPath string, commitsApplied int, shaMap map[string]string, applyErr error, selectedTags []yoloai.TagInfo, targetDir string, withTags bool, changelogFragment string) error {
 	tagsApplied, tagsSkipped := applyTags(cmd, name, hostPath, selectedTags, shaMap, withTags)
 
 	if !cliutil.JSONEnabled(cmd) && !withTags {
@@ -143,11 +146,12 @@ func finishSelectiveApply(cmd *cobra.Command, name, hostPath string, commitsAppl
 
 	if cliutil.JSONEnabled(cmd) {
 		return cliutil.WriteJSON(cmd.OutOrStdout(), applyResult{
-			Target:         targetDir,
-			CommitsApplied: commitsApplied,
-			TagsApplied:    tagsApplied,
-			TagsSkipped:    tagsSkipped,
-			Method:         "selective",
+			Target:            targetDir,
+			CommitsApplied:    commitsApplied,
+			TagsApplied:       tagsApplied,
+			TagsSkipped:       tagsSkipped,
+			Method:            "selective",
+			ChangelogFragment: changelogFragment,
 		})
 	}
 
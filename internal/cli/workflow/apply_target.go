@@ -0,0 +1,104 @@
+// ABOUTME: --target apply workflow — lands the changes in another existing
+// ABOUTME: checkout (a clean clone or worktree) instead of the original directory.
+
+package workflow
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+
+	"github.com/kstenerud/yoloai/internal/cli/cliutil"
+
+	"github.com/kstenerud/yoloai"
+	"github.com/kstenerud/yoloai/yoerrors"
+	"github.com/spf13/cobra"
+)
+
+// applyToTarget lands the changes in flags.target rather than the directory the
+// sandbox copied from. Neither that directory nor the baseline changes, so
+// there's no confirmation prompt. Commits replay as a series when the target is
+// a git repository and --no-commit isn't given; otherwise the net diff lands as
+// one unstaged patch, as the default flow does for a non-git directory.
+func applyToTarget(cmd *cobra.Command, name, hostPath string, refs, paths []string, flags applyFlags) error {
+	mode := yoloai.ApplyModeCommits
+	if flags.noCommit {
+		mode = yoloai.ApplyModeNoCommit
+	} else if _, err := os.Stat(filepath.Join(flags.target, ".git")); err != nil {
+		if len(refs) > 0 {
+			return yoerrors.NewUsageError("cannot apply specific commits to %s: not a git repository — use --no-commit to land the net changes", flags.target)
+		}
+		mode = yoloai.ApplyModeNoCommit
+		if !cliutil.JSONEnabled(cmd) {
+			fmt.Fprintf(cmd.OutOrStdout(), "%s is not a git repository — applying the changes as unstaged files\n\n", flags.target) //nolint:errcheck
+		}
+	}
+
+	slog.Info("applying changes to another directory", "event", "sandbox.apply.target", "sandbox", name, "dir", flags.target)
+
+	var result *yoloai.ApplyResult
+	applyErr := cliutil.WithTrackedDir(cmd, name, hostPath, func(ctx context.Context, wd *yoloai.Workdir) error {
+		opts := yoloai.WorkdirApplyOptions{
+			Mode: mode, Refs: refs, IncludeUncommitted: flags.includeUncommitted, Paths: paths,
+			DryRun: flags.dryRun, NoProvenance: noProvenance(cmd), Target: flags.target,
+			Changelog: changelogOption(cmd),
+		}
+		var e error
+		result, e = wd.Apply(ctx, opts)
+		if result == nil && e == nil && mode == yoloai.ApplyModeCommits && len(refs) == 0 && flags.includeUncommitted {
+			opts.Mode = yoloai.ApplyModeNoCommit
+			result, e = wd.Apply(ctx, opts)
+		}
+		return e
+	})
+	// As in runApplyCommits: a result alongside an error means the changes
+	// landed but a follow-on step didn't.
+	if result == nil {
+		if applyErr != nil {
+			return applyErr
+		}
+		if cliutil.JSONEnabled(cmd) {
+			return cliutil.WriteJSON(cmd.OutOrStdout(), applyResult{Target: flags.target, Method: "target"})
+		}
+		_, err := fmt.Fprintln(cmd.OutOrStdout(), "No changes to apply")
+		return err
+	}
+
+	if cliutil.JSONEnabled(cmd) {
+		if err := cliutil.WriteJSON(cmd.OutOrStdout(), applyResult{
+			Target:             result.Dir,
+			CommitsApplied:     len(result.Commits),
+			UncommittedApplied: result.UncommittedApplied || len(result.Commits) == 0,
+			Method:             "target",
+			ChangelogFragment:  result.ChangelogFragment,
+		}); err != nil {
+			return err
+		}
+		return applyErr
+	}
+
+	out := cmd.OutOrStdout()
+	verb := "applied to"
+	if flags.dryRun {
+		verb = "would be applied to"
+	}
+	if len(result.Commits) > 0 {
+		for _, c := range result.Commits {
+			fmt.Fprintf(out, "  %.12s %s\n", c.SourceSHA, c.Subject) //nolint:errcheck
+		}
+		fmt.Fprintf(out, "%d commit(s) %s %s\n", len(result.Commits), verb, result.Dir) //nolint:errcheck
+		if result.UncommittedApplied {
+			fmt.Fprintln(out, "Uncommitted changes applied as unstaged files") //nolint:errcheck
+		}
+	} else {
+		fmt.Fprintln(out, result.Stat)                                   //nolint:errcheck
+		fmt.Fprintf(out, "Changes %s %s (unstaged)\n", verb, result.Dir) //nolint:errcheck
+	}
+	reportChangelogFragment(cmd, result)
+	if !flags.dryRun {
+		fmt.Fprintln(out, "The original directory and the sandbox baseline are unchanged.") //nolint:errcheck
+	}
+	return applyErr
+}
@@ -1,6 +1,7 @@
 // ABOUTME: Tests for `apply` argument parsing: refs-vs-paths disambiguation
 // ABOUTME: (with/without --), dispatchApply's mutually-exclusive-flag and
-// ABOUTME: :rw-dir guards, and buildTagsByCommit's SHA normalization.
+// ABOUTME: :rw-dir guards, --repo/--push-branch targeting, and buildTagsByCommit's
+// ABOUTME: SHA normalization.
 package workflow
 
 import (
@@ -114,6 +115,57 @@ func TestApplyAll_WithPatches_ReturnsUsageError(t *testing.T) {
 	assert.Error(t, err)
 }
 
+func TestApply_FreshCloneExclusiveFlags(t *testing.T) {
+	for _, other := range [][]string{{"--dry-run"}, {"--tags"}, {"--all"}, {"--patches", "/tmp/p"}} {
+		cmd := NewApplyCmd()
+		cmd.SetArgs(append([]string{"mybox", "--fresh-clone", "/tmp/c"}, other...))
+		err := cmd.Execute()
+		require.Error(t, err, other[0])
+		assert.Contains(t, err.Error(), "fresh-clone", other[0])
+	}
+}
+
+func TestApply_BranchExclusiveFlags(t *testing.T) {
+	for _, other := range [][]string{{"--dry-run"}, {"--tags"}, {"--all"}, {"-i"}, {"--fresh-clone", "/tmp/c"}, {"--push-branch", "p"}} {
+		cmd := NewApplyCmd()
+		cmd.SetArgs(append([]string{"mybox", "--branch", "review"}, other...))
+		err := cmd.Execute()
+		require.Error(t, err, other[0])
+		assert.Contains(t, err.Error(), "branch", other[0])
+	}
+}
+
+func TestApply_VerifyExclusiveFlags(t *testing.T) {
+	for _, other := range [][]string{{"--no-verify"}, {"--dry-run"}, {"--patches", "/tmp/p"}} {
+		cmd := NewApplyCmd()
+		cmd.SetArgs(append([]string{"mybox", "--verify", "make test"}, other...))
+		err := cmd.Execute()
+		require.Error(t, err, other[0])
+		assert.Contains(t, err.Error(), "verify", other[0])
+	}
+}
+
+func TestApply_ChangelogExclusiveFlags(t *testing.T) {
+	for _, other := range [][]string{{"--branch", "b"}, {"--push-branch", "b"}, {"--patches", "/tmp/p"}} {
+		cmd := NewApplyCmd()
+		cmd.SetArgs(append([]string{"mybox", "--changelog-type", "bugfix"}, other...))
+		err := cmd.Execute()
+		require.Error(t, err, other[0])
+		assert.Contains(t, err.Error(), "changelog", other[0])
+	}
+}
+
+func TestChangelogOption(t *testing.T) {
+	cmd := NewApplyCmd()
+	assert.Nil(t, changelogOption(cmd))
+	require.NoError(t, cmd.Flags().Set("changelog", "true"))
+	assert.Equal(t, &yoloai.ApplyChangelog{}, changelogOption(cmd))
+
+	cmd = NewApplyCmd()
+	require.NoError(t, cmd.Flags().Set("changelog-type", "bugfix"))
+	assert.Equal(t, &yoloai.ApplyChangelog{Type: "bugfix"}, changelogOption(cmd), "--changelog-type implies --changelog")
+}
+
 // --- dispatchApply guard-clause tests ---
 
 func TestDispatchApply_RefsAndNoCommit_UsageError(t *testing.T) {
@@ -171,3 +223,21 @@ func TestBuildTagsByCommit_DistinctSHAs(t *testing.T) {
 	assert.Equal(t, []string{"v1.0"}, m["aaaa1111"])
 	assert.Equal(t, []string{"v2.0"}, m["bbbb2222"])
 }
+
+func TestCheckRepoApply(t *testing.T) {
+	repo := &yoloai.Environment{RepoURL: "https://example.com/org/repo"}
+	host := &yoloai.Environment{}
+	var ue *yoerrors.UsageError
+
+	err := checkRepoApply(repo, "box", applyFlags{})
+	require.ErrorAs(t, err, &ue, "a --repo sandbox has no host dir to apply to")
+	assert.Contains(t, err.Error(), "--push-branch")
+	require.NoError(t, checkRepoApply(repo, "box", applyFlags{pushBranch: "fix"}))
+	require.NoError(t, checkRepoApply(repo, "box", applyFlags{patchesDir: "/tmp/p"}))
+	require.NoError(t, checkRepoApply(repo, "box", applyFlags{dryRun: true}))
+
+	require.NoError(t, checkRepoApply(host, "box", applyFlags{}))
+	require.ErrorAs(t, checkRepoApply(host, "box", applyFlags{pushBranch: "fix"}), &ue,
+		"never push from the user's own checkout")
+	require.NoError(t, checkRepoApply(host, "box", applyFlags{pushBranch: "fix", freshClone: "/tmp/c"}))
+}
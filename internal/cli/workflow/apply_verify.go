@@ -0,0 +1,57 @@
+// ABOUTME: apply --verify — runs a check (the project's tests) in the sandbox
+// ABOUTME: against the work copy and gates the apply on it exiting zero.
+package workflow
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kstenerud/yoloai/internal/cli/cliutil"
+
+	yoloai "github.com/kstenerud/yoloai"
+	"github.com/spf13/cobra"
+)
+
+// maybeVerify runs the verify gate for one tracked dir unless the apply
+// won't change anything (--dry-run) or the user opted out (--no-verify).
+func maybeVerify(cmd *cobra.Command, name, hostPath string, flags applyFlags) error {
+	if flags.dryRun || flags.noVerify {
+		return nil
+	}
+	return runVerify(cmd, name, hostPath, flags.verify)
+}
+
+// runVerify is the gate behind apply --verify and the .yoloai.yaml verify key.
+// command is the --verify value; empty falls back to the project's verify key,
+// and with neither there is nothing to check. The command's output streams to
+// stdout (stderr under --json). It returns nil when the command exited zero.
+func runVerify(cmd *cobra.Command, name, hostPath, command string) error {
+	out := cmd.OutOrStdout()
+	if cliutil.JSONEnabled(cmd) {
+		out = cmd.ErrOrStderr()
+	}
+
+	var result *yoloai.VerifyResult
+	err := cliutil.WithTrackedDir(cmd, name, hostPath, func(ctx context.Context, wd *yoloai.Workdir) error {
+		source := ""
+		if command == "" {
+			var e error
+			if command, e = wd.VerifyCommand(); e != nil || command == "" {
+				return e
+			}
+			source = " (from .yoloai.yaml)"
+		}
+		fmt.Fprintf(out, "Verifying%s: %s\n", source, command) //nolint:errcheck
+		var e error
+		result, e = wd.Verify(ctx, yoloai.WorkdirVerifyOptions{Command: command, Output: out})
+		return e
+	})
+	if err != nil || result == nil {
+		return err
+	}
+	if !result.Passed {
+		return fmt.Errorf("verify command %q exited %d: nothing applied", result.Command, result.ExitCode)
+	}
+	fmt.Fprintf(out, "Verify passed.\n\n") //nolint:errcheck
+	return nil
+}
@@ -0,0 +1,154 @@
+package workflow
+
+// ABOUTME: CLI command for the artifact outbox: list what the agent left in
+// ABOUTME: /yoloai/outbox and collect it to a host directory.
+// ABOUTME: Uses name-first dispatch: `yoloai artifacts <sandbox> [collect <dest>]`.
+
+import (
+	"fmt"
+	"path/filepath"
+	"time"
+
+	yoloai "github.com/kstenerud/yoloai"
+	"github.com/kstenerud/yoloai/internal/cli/cliutil"
+	"github.com/kstenerud/yoloai/yoerrors"
+	"github.com/spf13/cobra"
+)
+
+// artifactJSON is the --json shape of one outbox artifact.
+type artifactJSON struct {
+	Path     string `json:"path"`
+	Size     int64  `json:"size"`
+	Modified string `json:"modified"`
+}
+
+func NewArtifactsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "artifacts <sandbox> [collect <dest> [pattern]...]",
+		Short: "List or collect files the agent left in its outbox",
+		Long: `List or collect the artifacts a sandbox's agent handed back.
+
+The agent is told to write non-code deliverables — reports, generated
+assets, exported data — to /yoloai/outbox. Those files land in the
+sandbox's outbox directory on the host and never show up in diff or apply.
+
+With no subcommand, lists the artifacts with their size and age.
+
+Subcommands:
+  collect <dest> [pattern]...  Copy artifacts into the host directory <dest>,
+                               keeping their paths. Patterns match an
+                               artifact's path or base name; default all.
+  path                         Print host path to the outbox
+
+Examples:
+  yoloai artifacts mybox
+  yoloai artifacts mybox collect ./out
+  yoloai artifacts mybox collect ./out '*.pdf' report.md`,
+		GroupID: cliutil.GroupWorkflow,
+		Args:    cobra.ArbitraryArgs,
+		RunE:    artifactsDispatch,
+	}
+	cmd.Flags().Bool("overwrite", false, "Overwrite existing files when collecting")
+	return cmd
+}
+
+func artifactsDispatch(cmd *cobra.Command, args []string) error {
+	var name string
+	var rest []string
+	if len(args) == 0 || args[0] == "collect" || args[0] == "path" {
+		envName := cliutil.SandboxNameFromEnv()
+		if envName == "" {
+			return yoerrors.NewUsageError("sandbox name required (or set YOLOAI_SANDBOX)")
+		}
+		name, rest = envName, args
+	} else {
+		name, rest = args[0], args[1:]
+	}
+	if err := cliutil.ValidateName(name); err != nil {
+		return err
+	}
+
+	c, err := cliutil.Client(cmd)
+	if err != nil {
+		return err
+	}
+	defer c.Close() //nolint:errcheck // best-effort cleanup
+	sb, err := c.Sandbox(name)
+	if err != nil {
+		return cliutil.SandboxErrorHint(name, err)
+	}
+	artifacts := sb.Artifacts()
+
+	if len(rest) == 0 {
+		return runArtifactsList(cmd, artifacts)
+	}
+	switch rest[0] {
+	case "collect":
+		return runArtifactsCollect(cmd, artifacts, rest[1:])
+	case "path":
+		fmt.Fprintln(cmd.OutOrStdout(), artifacts.Path()) //nolint:errcheck // best-effort output
+		return nil
+	default:
+		return yoerrors.NewUsageError("unknown subcommand %q: valid subcommands are collect, path", rest[0])
+	}
+}
+
+func runArtifactsList(cmd *cobra.Command, artifacts *yoloai.Artifacts) error {
+	items, err := artifacts.List()
+	if err != nil {
+		return err
+	}
+
+	if cliutil.JSONEnabled(cmd) {
+		out := make([]artifactJSON, 0, len(items))
+		for _, a := range items {
+			out = append(out, artifactJSON{Path: a.Path, Size: a.Size, Modified: a.ModTime.UTC().Format(time.RFC3339)})
+		}
+		return cliutil.WriteJSONList(cmd.OutOrStdout(), "artifacts", out)
+	}
+
+	w := cmd.OutOrStdout()
+	if len(items) == 0 {
+		_, err := fmt.Fprintf(w, "No artifacts in %s\n", artifacts.Path())
+		return err
+	}
+	for _, a := range items {
+		fmt.Fprintf(w, "%8s  %-8s  %s\n", cliutil.FormatSize(a.Size), cliutil.FormatAge(a.ModTime), a.Path) //nolint:errcheck // best-effort output
+	}
+	return nil
+}
+
+func runArtifactsCollect(cmd *cobra.Command, artifacts *yoloai.Artifacts, args []string) error {
+	if len(args) == 0 {
+		return yoerrors.NewUsageError("destination directory is required: artifacts <sandbox> collect <dest> [pattern]...")
+	}
+	dest, err := filepath.Abs(args[0])
+	if err != nil {
+		return fmt.Errorf("resolve destination: %w", err)
+	}
+	overwrite, _ := cmd.Flags().GetBool("overwrite")
+
+	copied, err := artifacts.Collect(cmd.Context(), dest, args[1:], overwrite)
+	if err != nil {
+		return err
+	}
+	if len(copied) == 0 && len(args) > 1 {
+		return fmt.Errorf("no artifacts match pattern: %v", args[1:])
+	}
+
+	if cliutil.JSONEnabled(cmd) {
+		return cliutil.WriteJSON(cmd.OutOrStdout(), map[string]any{
+			"destination": dest,
+			"collected":   cliutil.EmptyIfNil(copied),
+		})
+	}
+	w := cmd.OutOrStdout()
+	if len(copied) == 0 {
+		_, err := fmt.Fprintln(w, "No artifacts to collect")
+		return err
+	}
+	for _, rel := range copied {
+		fmt.Fprintln(w, filepath.Join(dest, filepath.FromSlash(rel))) //nolint:errcheck // best-effort output
+	}
+	return nil
+}
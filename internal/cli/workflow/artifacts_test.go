@@ -0,0 +1,73 @@
+package workflow
+
+// ABOUTME: Tests for the artifacts CLI command (list, collect, path).
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/kstenerud/yoloai/internal/cli/clitest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupArtifactsTest creates a fake sandbox with one artifact in its outbox.
+// Returns the sandbox name and the outbox path.
+func setupArtifactsTest(t *testing.T) (string, string) {
+	t.Helper()
+	tmpDir := clitest.Home(t)
+
+	name := "testbox"
+	outbox := filepath.Join(tmpDir, ".yoloai", "library", "sandboxes", name, "outbox")
+	require.NoError(t, os.MkdirAll(filepath.Join(outbox, "img"), 0750))
+	require.NoError(t, os.WriteFile(filepath.Join(outbox, "img", "chart.png"), []byte("png"), 0600))
+	return name, outbox
+}
+
+func TestArtifacts_Lists(t *testing.T) {
+	name, _ := setupArtifactsTest(t)
+
+	cmd := NewArtifactsCmd()
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{name})
+	require.NoError(t, cmd.Execute())
+	assert.Contains(t, out.String(), "img/chart.png")
+}
+
+func TestArtifacts_Collect(t *testing.T) {
+	name, _ := setupArtifactsTest(t)
+	dest := filepath.Join(t.TempDir(), "out")
+
+	cmd := NewArtifactsCmd()
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{name, "collect", dest})
+	require.NoError(t, cmd.Execute())
+	assert.FileExists(t, filepath.Join(dest, "img", "chart.png"))
+	assert.Contains(t, out.String(), filepath.Join(dest, "img", "chart.png"))
+}
+
+func TestArtifacts_CollectNoMatch(t *testing.T) {
+	name, _ := setupArtifactsTest(t)
+
+	cmd := NewArtifactsCmd()
+	cmd.SetOut(new(bytes.Buffer))
+	cmd.SetArgs([]string{name, "collect", t.TempDir(), "*.pdf"})
+	err := cmd.Execute()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "no artifacts match")
+}
+
+func TestArtifacts_Path(t *testing.T) {
+	name, outbox := setupArtifactsTest(t)
+
+	cmd := NewArtifactsCmd()
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{name, "path"})
+	require.NoError(t, cmd.Execute())
+	assert.Equal(t, outbox+"\n", out.String())
+}
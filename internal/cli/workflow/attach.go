@@ -4,16 +4,22 @@ package workflow
 
 import (
 	"context"
+	"fmt"
 	"log/slog"
+	"strings"
 
 	"github.com/kstenerud/yoloai/internal/cli/cliutil"
 
 	yoloai "github.com/kstenerud/yoloai"
+	"github.com/kstenerud/yoloai/yoerrors"
 	"github.com/spf13/cobra"
 )
 
 type attachOpts struct {
-	resume bool
+	resume   bool
+	force    bool
+	readOnly bool
+	window   string
 }
 
 func NewAttachCmd() *cobra.Command {
@@ -21,12 +27,37 @@ func NewAttachCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:     "attach <name>",
 		Short:   "Attach to a sandbox's session (tmux)",
+		Example: cliutil.CommandExamples("attach"),
+		Long: `Attach to a sandbox's session (tmux).
+
+If another terminal is already attached, attach refuses: two terminals typing
+into the same agent interleave their keystrokes. Pass --read-only to watch
+alongside it without sending input, or --force to attach anyway. Read-only
+watchers never block an attach. 'yoloai sandbox <name> info' shows who is
+attached.
+
+A split-role sandbox ('new --role') runs one agent per role, each in its own
+tmux window. --window opens the named role's window; switch windows once
+attached with Ctrl-b n / Ctrl-b p. Every sandbox also has a "shell" window
+with a plain shell in the workdir ('yoloai shell' opens it).
+
+Ctrl-b ? opens a menu of yoloai actions: detach, view the agent's
+uncommitted changes, watch its changes against the baseline live, or follow
+the sandbox's logs in a split, and send the agent a notice that its files
+changed.
+
+If the connection drops while attached (the docker daemon restarts, the VM
+reboots), attach waits for the sandbox to come back and re-attaches to the
+same session instead of exiting.`,
 		GroupID: cliutil.GroupWorkflow,
 		Args:    cobra.ArbitraryArgs,
 		RunE:    func(cmd *cobra.Command, args []string) error { return runAttach(cmd, args, opts) },
 	}
 
-	cmd.Flags().BoolVar(&opts.resume, "resume", false, "Restart agent with resume prompt before attaching")
+	cmd.Flags().BoolVar(&opts.resume, "resume", false, "Restart agent with resume prompt before attaching (a stopped sandbox is started either way)")
+	cmd.Flags().BoolVarP(&opts.readOnly, "read-only", "r", false, "Watch the session without sending keystrokes to the agent")
+	cmd.Flags().BoolVar(&opts.force, "force", false, "Attach even if another terminal is already attached")
+	cmd.Flags().StringVarP(&opts.window, "window", "w", "", "Open this tmux window: a role of a split-role sandbox, shell, or vscode-tunnel")
 
 	return cmd
 }
@@ -36,7 +67,7 @@ func NewAttachCmd() *cobra.Command {
 // handles terminal title + IOStreams wiring.
 func runAttach(cmd *cobra.Command, args []string, opts *attachOpts) error {
 	if cliutil.JSONEnabled(cmd) {
-		return cliutil.ErrJSONNotSupported("attach")
+		return cliutil.ErrJSONNotSupported(cmd.Name())
 	}
 
 	name, _, err := cliutil.ResolveName(cmd, args)
@@ -51,26 +82,66 @@ func runAttach(cmd *cobra.Command, args []string, opts *attachOpts) error {
 	return cliutil.WithSandbox(cmd, name, func(ctx context.Context, sb *yoloai.Sandbox) error {
 		// Bring a crashed credential injector back before attaching (D106).
 		cliutil.ReconcileInjectorBestEffort(ctx, sb)
-		// --resume restarts the agent before attaching when the sandbox is
-		// stopped or in a terminal state. Active/Idle sandboxes get an
-		// in-place attach.
-		if opts.resume {
-			info, err := sb.Inspect(ctx)
-			if err != nil {
-				return cliutil.SandboxErrorHint(name, err)
-			}
-			if info.Status != yoloai.StatusActive && info.Status != yoloai.StatusIdle {
-				// Auto-start before attaching; the interactive session takes over
-				// immediately, so start notices would be noise — discard them.
-				if _, err := sb.Start(ctx, yoloai.SandboxStartOptions{Resume: true}); err != nil {
-					return err
-				}
+		if err := ensureStartedForAttach(ctx, cmd, sb, opts.resume); err != nil {
+			return cliutil.SandboxErrorHint(name, err)
+		}
+
+		if !opts.force && !opts.readOnly {
+			if err := refuseIfAttached(ctx, sb); err != nil {
+				return err
 			}
 		}
 
-		slog.Debug("attaching to sandbox", "event", "sandbox.attach", "sandbox", name)
+		slog.Debug("attaching to sandbox", "event", "sandbox.attach", "sandbox", name, "read_only", opts.readOnly, "window", opts.window)
 		return cliutil.WithTerminal(func(io yoloai.IOStreams) error {
-			return sb.Agent().Attach(ctx, io)
+			return sb.Agent().AttachWith(ctx, io, yoloai.AgentAttachOptions{ReadOnly: opts.readOnly, Window: opts.window})
 		})
 	})
 }
+
+// ensureStartedForAttach brings the sandbox up before attaching when it is not
+// running. A stopped sandbox, or one whose container is gone (typically after a
+// host reboot), is started transparently — recreating the container as needed —
+// so attach never fails just because the host restarted. --resume additionally
+// restarts a finished (done/failed) agent with the resume preamble. Active/Idle
+// sandboxes get an in-place attach. The interactive session takes over
+// immediately, so start notices would be noise — they are discarded.
+func ensureStartedForAttach(ctx context.Context, cmd *cobra.Command, sb *yoloai.Sandbox, resume bool) error {
+	info, err := sb.Inspect(ctx)
+	if err != nil {
+		return err
+	}
+	switch info.Status {
+	case yoloai.StatusActive, yoloai.StatusIdle:
+		return nil
+	case yoloai.StatusStopped, yoloai.StatusRemoved:
+		fmt.Fprintf(cmd.ErrOrStderr(), "Sandbox %s is not running; starting it...\n", sb.Name()) //nolint:errcheck // best-effort output
+	default:
+		if !resume {
+			return nil // done/failed attach in place; other states surface from Attach
+		}
+	}
+	_, err = sb.Start(ctx, yoloai.SandboxStartOptions{Resume: resume})
+	return err
+}
+
+// refuseIfAttached returns a *UsageError when another terminal that can type
+// is already attached to the sandbox's session. A failed lookup doesn't block
+// the attach: the check is a courtesy, not a lock.
+func refuseIfAttached(ctx context.Context, sb *yoloai.Sandbox) error {
+	clients, err := sb.Agent().AttachedClients(ctx)
+	if err != nil {
+		slog.Debug("could not list attached clients", "event", "sandbox.attach.clients_fail", "sandbox", sb.Name(), "err", err)
+		return nil
+	}
+	var ttys []string
+	for _, c := range clients {
+		if !c.ReadOnly {
+			ttys = append(ttys, c.TTY)
+		}
+	}
+	if len(ttys) == 0 {
+		return nil
+	}
+	return yoerrors.NewUsageError("sandbox %s is already attached by %s — use --read-only to watch, or --force to attach anyway", sb.Name(), strings.Join(ttys, ", "))
+}
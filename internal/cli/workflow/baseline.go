@@ -36,7 +36,7 @@ func newBaselineAdvanceCmd() *cobra.Command {
 				if err != nil {
 					return err
 				}
-				return printBaselineChange(cmd, name, expected, change)
+				return printBaselineChange(cmd, name, "baseline-advanced", expected, change)
 			})
 		},
 	}
@@ -56,7 +56,7 @@ func newBaselineSetCmd() *cobra.Command {
 				if err != nil {
 					return err
 				}
-				return printBaselineChange(cmd, name, expected, change)
+				return printBaselineChange(cmd, name, "baseline-set", expected, change)
 			})
 		},
 	}
@@ -80,12 +80,34 @@ func withBaselineSandbox(cmd *cobra.Command, name string, fn func(ctx context.Co
 	})
 }
 
-// printBaselineChange prints the confirmation line for an advance/set:
+// baselineChangeJSON is the --json result of an advance/set.
+type baselineChangeJSON struct {
+	Name     string `json:"name"`
+	Action   string `json:"action"`
+	Baseline string `json:"baseline"`
+	Subject  string `json:"subject,omitempty"`
+	Previous string `json:"previous,omitempty"`
+}
+
+// baselineLogJSON is one --json entry of `baseline log`, newest first.
+type baselineLogJSON struct {
+	SHA        string `json:"sha"`
+	Subject    string `json:"subject"`
+	IsBaseline bool   `json:"is_baseline"`
+}
+
+// printBaselineChange prints the confirmation line for an advance/set
+// (action names which, for --json):
 // "Baseline advanced to <short-sha> (<subject>)". When oldSHA is non-empty it
 // also prints a "Previous baseline" undo hint pointing at the value the caller
 // swapped away from.
-func printBaselineChange(cmd *cobra.Command, name, oldSHA string, change *yoloai.BaselineChange) error {
+func printBaselineChange(cmd *cobra.Command, name, action, oldSHA string, change *yoloai.BaselineChange) error {
 	w := cmd.OutOrStdout()
+	if cliutil.JSONEnabled(cmd) {
+		return cliutil.WriteJSON(w, baselineChangeJSON{
+			Name: name, Action: action, Baseline: change.NewSHA, Subject: change.Subject, Previous: oldSHA,
+		})
+	}
 	if change.Subject != "" {
 		if _, err := fmt.Fprintf(w, "Baseline advanced to %s (%s)\n", short8(change.NewSHA), change.Subject); err != nil {
 			return err
@@ -119,6 +141,13 @@ func newBaselineLogCmd() *cobra.Command {
 					return cliutil.SandboxErrorHint(name, err)
 				}
 				w := cmd.OutOrStdout()
+				if cliutil.JSONEnabled(cmd) {
+					out := make([]baselineLogJSON, len(entries))
+					for i, e := range entries {
+						out[i] = baselineLogJSON{SHA: e.SHA, Subject: e.Subject, IsBaseline: e.IsBaseline}
+					}
+					return cliutil.WriteJSONList(w, "commits", out)
+				}
 				for _, e := range entries {
 					marker := ""
 					if e.IsBaseline {
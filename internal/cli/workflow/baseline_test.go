@@ -4,6 +4,7 @@ package workflow
 
 import (
 	"bytes"
+	"encoding/json"
 	"testing"
 
 	yoloai "github.com/kstenerud/yoloai"
@@ -27,7 +28,7 @@ func TestPrintBaselineChange_SubjectPresent(t *testing.T) {
 	cmd, buf := newBaselineCmd(t)
 	change := &yoloai.BaselineChange{NewSHA: "abcdef1234567890", Subject: "fix: something"}
 
-	require.NoError(t, printBaselineChange(cmd, "mybox", "", change))
+	require.NoError(t, printBaselineChange(cmd, "mybox", "baseline-advanced", "", change))
 
 	out := buf.String()
 	assert.Contains(t, out, "Baseline advanced to")
@@ -41,7 +42,7 @@ func TestPrintBaselineChange_NoSubject(t *testing.T) {
 	cmd, buf := newBaselineCmd(t)
 	change := &yoloai.BaselineChange{NewSHA: "abcdef1234567890", Subject: ""}
 
-	require.NoError(t, printBaselineChange(cmd, "mybox", "", change))
+	require.NoError(t, printBaselineChange(cmd, "mybox", "baseline-advanced", "", change))
 
 	out := buf.String()
 	assert.Contains(t, out, "Baseline advanced to")
@@ -55,7 +56,7 @@ func TestPrintBaselineChange_WithOldSHA(t *testing.T) {
 	cmd, buf := newBaselineCmd(t)
 	change := &yoloai.BaselineChange{NewSHA: "abcdef1234567890", Subject: "fix: something"}
 
-	require.NoError(t, printBaselineChange(cmd, "mybox", "oldsha1234567890", change))
+	require.NoError(t, printBaselineChange(cmd, "mybox", "baseline-advanced", "oldsha1234567890", change))
 
 	out := buf.String()
 	assert.Contains(t, out, "oldsha12") // short8 of oldSHA
@@ -69,9 +70,27 @@ func TestPrintBaselineChange_NoOldSHA(t *testing.T) {
 	cmd, buf := newBaselineCmd(t)
 	change := &yoloai.BaselineChange{NewSHA: "abcdef1234567890", Subject: "fix: something"}
 
-	require.NoError(t, printBaselineChange(cmd, "mybox", "", change))
+	require.NoError(t, printBaselineChange(cmd, "mybox", "baseline-advanced", "", change))
 
 	out := buf.String()
 	assert.NotContains(t, out, "Previous baseline")
 	assert.NotContains(t, out, "undo")
 }
+
+// TestPrintBaselineChange_JSON verifies the --json result carries the name,
+// action, new baseline, and the previous baseline for undo.
+func TestPrintBaselineChange_JSON(t *testing.T) {
+	cmd, buf := newBaselineCmd(t)
+	cmd.PersistentFlags().Bool("json", false, "")
+	require.NoError(t, cmd.PersistentFlags().Set("json", "true"))
+	change := &yoloai.BaselineChange{NewSHA: "abcdef1234567890", Subject: "fix: something"}
+
+	require.NoError(t, printBaselineChange(cmd, "mybox", "baseline-set", "oldsha1234567890", change))
+
+	var got baselineChangeJSON
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &got), "output must be valid JSON: %q", buf.String())
+	assert.Equal(t, baselineChangeJSON{
+		Name: "mybox", Action: "baseline-set", Baseline: "abcdef1234567890",
+		Subject: "fix: something", Previous: "oldsha1234567890",
+	}, got)
+}
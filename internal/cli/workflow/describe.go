@@ -0,0 +1,241 @@
+// ABOUTME: `yoloai describe`: drafts a PR/commit description (what, why,
+// ABOUTME: testing) from a sandbox's prompt, agent result, transcript and diff.
+package workflow
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/kstenerud/yoloai/internal/cli/cliutil"
+
+	yoloai "github.com/kstenerud/yoloai"
+	"github.com/spf13/cobra"
+)
+
+// describeTitleMax caps the title at a conventional commit-subject width.
+const describeTitleMax = 72
+
+// describeMaxTestRuns caps how many test invocations the Testing section lists.
+const describeMaxTestRuns = 10
+
+// testRunPattern finds test-runner invocations in the agent transcript. The
+// match runs to the end of the line, so the command's arguments come along.
+var testRunPattern = regexp.MustCompile(`\b(?:go test|(?:npm|pnpm|yarn|bun) (?:run )?test|npx (?:jest|vitest)|pytest|python3? -m (?:pytest|unittest)|cargo (?:test|nextest)|make (?:test|check)|swift test|xcodebuild test|mvn test|gradle test|\./gradlew test|bundle exec rspec|rspec|mix test|dotnet test|ctest)\b.*$`)
+
+// describeInput is everything a description is drafted from. Gathered once so
+// the drafting itself is a pure function.
+type describeInput struct {
+	Prompt     string
+	Result     *yoloai.AgentResult
+	Changes    *yoloai.Changes
+	Commits    []yoloai.CommitInfo
+	Transcript string // ANSI-stripped agent terminal log
+}
+
+// description is a drafted change description.
+type description struct {
+	Title string `json:"title"`
+	Body  string `json:"body"`
+}
+
+func NewDescribeCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "describe <name>",
+		Short: "Draft a PR/commit description for the agent's changes",
+		Long: `Draft a change description — a title plus What, Why and Testing
+sections — for pasting into a pull request or commit message.
+
+It is assembled from what the sandbox already knows: the agent's
+result.json summary and follow-ups, the original prompt, the changed
+files and commits in the workdir, and the test commands found in the
+agent's transcript. Nothing is sent anywhere; review and edit the draft
+before using it.
+
+Examples:
+  yoloai describe mybox
+  yoloai describe mybox | pbcopy
+  yoloai describe mybox --json`,
+		GroupID: cliutil.GroupWorkflow,
+		Args:    cobra.MaximumNArgs(1),
+		RunE:    runDescribeCmd,
+	}
+}
+
+func runDescribeCmd(cmd *cobra.Command, args []string) error {
+	name, _, err := cliutil.ResolveName(cmd, args)
+	if err != nil {
+		return err
+	}
+	return cliutil.WithSandbox(cmd, name, func(ctx context.Context, sb *yoloai.Sandbox) error {
+		in, err := gatherDescribeInput(ctx, sb)
+		if err != nil {
+			return err
+		}
+		d := draftDescription(in)
+		if cliutil.JSONEnabled(cmd) {
+			return cliutil.WriteJSON(cmd.OutOrStdout(), d)
+		}
+		_, err = fmt.Fprintf(cmd.OutOrStdout(), "%s\n\n%s", d.Title, d.Body)
+		return err
+	})
+}
+
+// gatherDescribeInput reads the pieces a description is drafted from. Only the
+// change summary is required; the prompt, result, commits and transcript are
+// optional and simply leave their part of the draft out when unavailable.
+func gatherDescribeInput(ctx context.Context, sb *yoloai.Sandbox) (describeInput, error) {
+	var in describeInput
+	changes, err := sb.Workdir().Changes(ctx)
+	if err != nil {
+		return in, err
+	}
+	in.Changes = changes
+
+	if prompt, ok, err := sb.Agent().Prompt(); err == nil && ok {
+		in.Prompt = prompt
+	}
+	if res, err := sb.Result(); err == nil {
+		in.Result = res
+	}
+	if commits, err := sb.Workdir().Commits(ctx, yoloai.WorkdirCommitsOptions{}); err == nil {
+		in.Commits = commits
+	}
+	if raw, err := sb.Agent().TerminalLog(0); err == nil && raw != "" {
+		var clean strings.Builder
+		if cliutil.StripANSI(&clean, strings.NewReader(raw)) == nil {
+			in.Transcript = clean.String()
+		}
+	}
+	return in, nil
+}
+
+// draftDescription assembles the title and markdown body from in.
+func draftDescription(in describeInput) description {
+	var b strings.Builder
+
+	b.WriteString("## What\n\n")
+	if in.Result != nil && strings.TrimSpace(in.Result.Summary) != "" {
+		b.WriteString(strings.TrimSpace(in.Result.Summary))
+		b.WriteString("\n\n")
+	}
+	writeChangeSummary(&b, in.Changes)
+	if len(in.Commits) > 0 {
+		b.WriteString("\nCommits:\n")
+		for _, c := range in.Commits {
+			fmt.Fprintf(&b, "- %s (%s)\n", c.Subject, shortSHA(c.SHA))
+		}
+	}
+
+	if prompt := strings.TrimSpace(in.Prompt); prompt != "" {
+		b.WriteString("\n## Why\n\n")
+		for line := range strings.SplitSeq(prompt, "\n") {
+			b.WriteString(strings.TrimRight("> "+line, " "))
+			b.WriteString("\n")
+		}
+	}
+
+	b.WriteString("\n## Testing\n\n")
+	if runs := findTestRuns(in.Transcript); len(runs) > 0 {
+		b.WriteString("The agent ran:\n")
+		for _, r := range runs {
+			fmt.Fprintf(&b, "- `%s`\n", r)
+		}
+	} else {
+		b.WriteString("No test runs were found in the agent's transcript.\n")
+	}
+
+	if in.Result != nil && len(in.Result.FollowUps) > 0 {
+		b.WriteString("\n## Follow-ups\n\n")
+		for _, f := range in.Result.FollowUps {
+			fmt.Fprintf(&b, "- %s\n", f)
+		}
+	}
+
+	return description{Title: draftTitle(in), Body: b.String()}
+}
+
+// writeChangeSummary lists the changed files with their line counts.
+func writeChangeSummary(b *strings.Builder, ch *yoloai.Changes) {
+	if ch == nil || len(ch.Files) == 0 {
+		b.WriteString("No file changes.\n")
+		return
+	}
+	fmt.Fprintf(b, "%d file(s) changed (+%d −%d):\n", len(ch.Files), ch.Additions, ch.Deletions)
+	for _, f := range ch.Files {
+		if f.Binary {
+			fmt.Fprintf(b, "- `%s` (binary)\n", f.Path)
+			continue
+		}
+		fmt.Fprintf(b, "- `%s` (+%d −%d)\n", f.Path, f.Additions, f.Deletions)
+	}
+}
+
+// draftTitle picks the title: the first line of the agent's summary, else of
+// the prompt, else the subject of a lone commit.
+func draftTitle(in describeInput) string {
+	var candidates []string
+	if in.Result != nil {
+		candidates = append(candidates, in.Result.Summary)
+	}
+	candidates = append(candidates, in.Prompt)
+	if len(in.Commits) == 1 {
+		candidates = append(candidates, in.Commits[0].Subject)
+	}
+	for _, c := range candidates {
+		line, _, _ := strings.Cut(strings.TrimSpace(c), "\n")
+		if line = strings.TrimSpace(line); line != "" {
+			return truncateTitle(line)
+		}
+	}
+	return "Changes from the sandbox agent"
+}
+
+// truncateTitle shortens s to describeTitleMax runes, breaking at a word.
+func truncateTitle(s string) string {
+	runes := []rune(s)
+	if len(runes) <= describeTitleMax {
+		return s
+	}
+	cut := string(runes[:describeTitleMax-1])
+	if i := strings.LastIndex(cut, " "); i > describeTitleMax/2 {
+		cut = cut[:i]
+	}
+	return strings.TrimRight(cut, " ,.;:") + "…"
+}
+
+// findTestRuns returns the distinct test-runner invocations in transcript, in
+// first-seen order, capped at describeMaxTestRuns. A TUI that echoes a tool
+// call as "Bash(go test ./...)" leaves an unbalanced ")" behind; it is dropped.
+func findTestRuns(transcript string) []string {
+	seen := make(map[string]bool)
+	var runs []string
+	for line := range strings.SplitSeq(transcript, "\n") {
+		m := testRunPattern.FindString(line)
+		if m == "" {
+			continue
+		}
+		m = strings.TrimSpace(m)
+		for strings.HasSuffix(m, ")") && strings.Count(m, ")") > strings.Count(m, "(") {
+			m = strings.TrimSpace(strings.TrimSuffix(m, ")"))
+		}
+		if m == "" || seen[m] {
+			continue
+		}
+		seen[m] = true
+		runs = append(runs, m)
+		if len(runs) == describeMaxTestRuns {
+			break
+		}
+	}
+	return runs
+}
+
+// shortSHA abbreviates a commit SHA for display.
+func shortSHA(sha string) string {
+	if len(sha) > 8 {
+		return sha[:8]
+	}
+	return sha
+}
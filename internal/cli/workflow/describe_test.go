@@ -0,0 +1,105 @@
+// ABOUTME: Tests for `describe` drafting: title selection and truncation, the
+// ABOUTME: What/Why/Testing/Follow-ups sections, and test-run extraction.
+package workflow
+
+import (
+	"strings"
+	"testing"
+
+	yoloai "github.com/kstenerud/yoloai"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDraftDescription_AllSections(t *testing.T) {
+	d := draftDescription(describeInput{
+		Prompt: "Fix the login timeout.\nIt should be 30s, not 5s.",
+		Result: &yoloai.AgentResult{
+			Status:    yoloai.ResultStatusSuccess,
+			Summary:   "Raised the login timeout to 30s",
+			FollowUps: []string{"Make the timeout configurable"},
+		},
+		Changes: &yoloai.Changes{
+			Files: []yoloai.FileChange{
+				{Path: "auth/login.go", Additions: 3, Deletions: 1},
+				{Path: "logo.png", Binary: true},
+			},
+			Additions: 3,
+			Deletions: 1,
+		},
+		Commits:    []yoloai.CommitInfo{{SHA: "0123456789abcdef", Subject: "Raise login timeout"}},
+		Transcript: "$ go test ./auth/...\nok  auth 0.2s\n",
+	})
+
+	assert.Equal(t, "Raised the login timeout to 30s", d.Title)
+	assert.Contains(t, d.Body, "## What\n\nRaised the login timeout to 30s\n")
+	assert.Contains(t, d.Body, "2 file(s) changed (+3 −1):")
+	assert.Contains(t, d.Body, "- `auth/login.go` (+3 −1)")
+	assert.Contains(t, d.Body, "- `logo.png` (binary)")
+	assert.Contains(t, d.Body, "- Raise login timeout (01234567)")
+	assert.Contains(t, d.Body, "## Why\n\n> Fix the login timeout.\n> It should be 30s, not 5s.\n")
+	assert.Contains(t, d.Body, "- `go test ./auth/...`")
+	assert.Contains(t, d.Body, "## Follow-ups\n\n- Make the timeout configurable\n")
+}
+
+func TestDraftDescription_Minimal(t *testing.T) {
+	d := draftDescription(describeInput{})
+
+	assert.Equal(t, "Changes from the sandbox agent", d.Title)
+	assert.Contains(t, d.Body, "No file changes.")
+	assert.Contains(t, d.Body, "No test runs were found")
+	assert.NotContains(t, d.Body, "## Why")
+	assert.NotContains(t, d.Body, "## Follow-ups")
+}
+
+func TestDraftTitle_FallsBackToPromptThenCommit(t *testing.T) {
+	assert.Equal(t, "Add retries", draftTitle(describeInput{
+		Result: &yoloai.AgentResult{Status: yoloai.ResultStatusSuccess},
+		Prompt: "\n  Add retries\nto the client",
+	}))
+	assert.Equal(t, "Only commit", draftTitle(describeInput{
+		Commits: []yoloai.CommitInfo{{SHA: "abc", Subject: "Only commit"}},
+	}))
+	// Several commits have no single subject to stand for the change.
+	assert.Equal(t, "Changes from the sandbox agent", draftTitle(describeInput{
+		Commits: []yoloai.CommitInfo{{Subject: "one"}, {Subject: "two"}},
+	}))
+}
+
+func TestTruncateTitle(t *testing.T) {
+	short := "Short title"
+	assert.Equal(t, short, truncateTitle(short))
+
+	long := strings.Repeat("word ", 30)
+	got := truncateTitle(long)
+	assert.LessOrEqual(t, len([]rune(got)), describeTitleMax)
+	assert.True(t, strings.HasSuffix(got, "word…"), got)
+}
+
+func TestFindTestRuns(t *testing.T) {
+	transcript := strings.Join([]string{
+		"⏺ Bash(go test ./...)",
+		"  ⎿  ok   example.com/pkg  0.1s",
+		"$ npm run test -- --watch=false",
+		"$ go test ./...",
+		"Running pytest -q tests/",
+		"I'll now edit main.go",
+	}, "\n")
+
+	assert.Equal(t, []string{
+		"go test ./...",
+		"npm run test -- --watch=false",
+		"pytest -q tests/",
+	}, findTestRuns(transcript))
+}
+
+func TestFindTestRuns_Capped(t *testing.T) {
+	var lines []string
+	for i := range describeMaxTestRuns + 5 {
+		lines = append(lines, "go test -run Test"+strings.Repeat("X", i+1))
+	}
+	assert.Len(t, findTestRuns(strings.Join(lines, "\n")), describeMaxTestRuns)
+}
+
+func TestFindTestRuns_KeepsBalancedParens(t *testing.T) {
+	assert.Equal(t, []string{"go test -run '(Foo|Bar)'"}, findTestRuns("go test -run '(Foo|Bar)'"))
+}
@@ -9,6 +9,7 @@ import (
 	"log/slog"
 	"regexp"
 	"strings"
+	"time"
 
 	"github.com/kstenerud/yoloai/internal/cli/cliutil"
 
@@ -45,7 +46,17 @@ Examples:
   yoloai diff mybox -- src/          # full diff filtered to path
   yoloai diff mybox web              # diff of "web" dir (multi-dir sandbox)
   yoloai diff mybox web abc123       # single commit diff in "web" dir
-  yoloai diff mybox --all                # diff of all tracked dirs`,
+  yoloai diff mybox --all                # diff of all tracked dirs
+  yoloai diff mybox --stat --watch   # follow the agent's changes live
+  yoloai diff mybox --format patch -o changes.patch   # patch file for review tools
+
+--watch re-runs the diff every --interval (default 2s) until interrupted,
+showing it again whenever it changes: redrawn in place on a terminal,
+appended under a timestamp when the output is redirected.
+
+--format patch writes the changes as a binary patch that git apply
+accepts, with nothing else in the output: to stdout, or to the file
+named by -o (- is stdout). -o alone implies --format patch.`,
 		GroupID: cliutil.GroupWorkflow,
 		Args:    cobra.ArbitraryArgs,
 		RunE:    runDiffCmd,
@@ -55,6 +66,10 @@ Examples:
 	cmd.Flags().Bool("name-only", false, "List changed files without content")
 	cmd.Flags().Bool("log", false, "List agent commits beyond baseline")
 	cmd.Flags().Bool("all", false, "operate on all tracked directories")
+	cmd.Flags().BoolP("watch", "w", false, "Re-run the diff every --interval and show it whenever it changes, until interrupted")
+	cmd.Flags().Duration("interval", 2*time.Second, "With --watch: how often to check for changes")
+	cmd.Flags().String("format", "text", "Output format: text, or patch (a binary patch for git apply and review tools)")
+	cmd.Flags().StringP("output", "o", "", "With --format patch: write the patch to this `file` (- for stdout)")
 
 	cmd.MarkFlagsMutuallyExclusive("stat", "name-only")
 
@@ -72,6 +87,14 @@ func runDiffCmd(cmd *cobra.Command, args []string) error {
 	nameOnly, _ := cmd.Flags().GetBool("name-only")
 	logFlag, _ := cmd.Flags().GetBool("log")
 	allFlag, _ := cmd.Flags().GetBool("all")
+	watch, _ := cmd.Flags().GetBool("watch")
+	interval, _ := cmd.Flags().GetDuration("interval")
+	if watch && cliutil.JSONEnabled(cmd) {
+		return yoerrors.NewUsageError("--watch runs until interrupted and can't be combined with --json")
+	}
+	if interval <= 0 {
+		return yoerrors.NewUsageError("--interval must be positive: %s", interval)
+	}
 
 	// Load meta early to select the target dir.
 	env, metaErr := cliutil.SandboxMetadata(cmd, name)
@@ -83,7 +106,20 @@ func runDiffCmd(cmd *cobra.Command, args []string) error {
 	// consumed a dir specifier. parseDiffArgs needs this to adjust ArgsLenAtDash.
 	argsConsumedBeforeRest := 1
 
+	patch, err := diffPatchRequested(cmd)
+	if err != nil {
+		return err
+	}
+	if patch && (allFlag || logFlag || stat || nameOnly || watch) {
+		return yoerrors.NewUsageError("--format patch writes one directory's full patch and can't be combined with --all, --log, --stat, --name-only or --watch")
+	}
+
 	if allFlag {
+		if watch {
+			return watchDiff(cmd, name, interval, func() error {
+				return diffAll(cmd, name, rest, logFlag, stat, nameOnly)
+			})
+		}
 		return diffAll(cmd, name, rest, logFlag, stat, nameOnly)
 	}
 
@@ -96,11 +132,34 @@ func runDiffCmd(cmd *cobra.Command, args []string) error {
 	}
 	slog.Debug("generating diff", "event", "sandbox.diff", "sandbox", name, "workdir_mode", selectedDir.Mode)
 
+	// Parse ref vs paths: split on "--" if present, otherwise
+	// try to detect ref from the first positional arg.
+	ref, paths := parseDiffArgs(rest, cmd, argsConsumedBeforeRest)
+
+	if patch {
+		return diffPatch(cmd, name, hostPath, ref, paths)
+	}
+
+	// --watch: the agent is expected to be running, so no warning about it.
+	if watch {
+		if ref != "" && !logFlag {
+			return yoerrors.NewUsageError("a commit doesn't change: --watch follows the diff since baseline or --log")
+		}
+		return watchDiff(cmd, name, interval, func() error {
+			return diffSelected(cmd, name, hostPath, "", paths, logFlag, stat, nameOnly)
+		})
+	}
+
 	// Skip agent warning in JSON mode
 	if !cliutil.JSONEnabled(cmd) {
 		agentRunningWarning(cmd, name)
 	}
+	return diffSelected(cmd, name, hostPath, ref, paths, logFlag, stat, nameOnly)
+}
 
+// diffSelected shows the diff of one tracked directory in the form the flags
+// and arguments ask for.
+func diffSelected(cmd *cobra.Command, name, hostPath, ref string, paths []string, logFlag, stat, nameOnly bool) error {
 	// --log: list commits
 	if logFlag {
 		if cliutil.JSONEnabled(cmd) {
@@ -109,10 +168,6 @@ func runDiffCmd(cmd *cobra.Command, args []string) error {
 		return diffLog(cmd, name, hostPath, stat)
 	}
 
-	// Parse ref vs paths: split on "--" if present, otherwise
-	// try to detect ref from the first positional arg.
-	ref, paths := parseDiffArgs(rest, cmd, argsConsumedBeforeRest)
-
 	// If ref is set, show that specific commit/range
 	if ref != "" {
 		return diffRef(cmd, name, hostPath, ref, stat)
@@ -136,18 +191,13 @@ func diffSingle(cmd *cobra.Command, name, hostPath string, paths []string, stat,
 		if err != nil {
 			return err
 		}
-		// For a full diff in JSON mode, enrich the output with structured per-file
-		// change counts. Stat and NameOnly remain plain (they're already structured
-		// summaries and the caller picked them explicitly).
-		if cliutil.JSONEnabled(cmd) && !stat && !nameOnly {
-			changes, changesErr := wd.Changes(ctx)
+		// In JSON mode, enrich the output with structured per-file change
+		// counts — for --stat and --name-only too, so scripts never parse the
+		// human summary.
+		if cliutil.JSONEnabled(cmd) {
+			changes, changesErr := wd.ChangesIn(ctx, paths)
 			if changesErr == nil {
-				return cliutil.WriteJSON(cmd.OutOrStdout(), map[string]any{
-					"diff":      out,
-					"files":     changes.Files,
-					"additions": changes.Additions,
-					"deletions": changes.Deletions,
-				})
+				return writeDiffChangesJSON(cmd, out, changes)
 			}
 			// Fall back to the plain diff envelope if changes fetch fails.
 		}
@@ -155,6 +205,20 @@ func diffSingle(cmd *cobra.Command, name, hostPath string, paths []string, stat,
 	})
 }
 
+// writeDiffChangesJSON writes the structured working-diff envelope. The text
+// output stays under "diff" (the patch, the --stat summary, or the name list)
+// so existing consumers keep working; "files" and the totals carry the same
+// information in parseable form whichever text form was asked for.
+func writeDiffChangesJSON(cmd *cobra.Command, out string, changes *yoloai.Changes) error {
+	return cliutil.WriteJSON(cmd.OutOrStdout(), map[string]any{
+		"diff":          out,
+		"files":         cliutil.EmptyIfNil(changes.Files),
+		"files_changed": len(changes.Files),
+		"additions":     changes.Additions,
+		"deletions":     changes.Deletions,
+	})
+}
+
 // writeDiffOutput emits a diff string to stdout, normalizing the
 // "no changes" case (empty string → "No changes" in human mode, an
 // empty JSON object in --json mode) so every diff entry point handles
@@ -0,0 +1,89 @@
+// ABOUTME: `yoloai diff --format patch [-o file]` — writes the agent's changes as
+// ABOUTME: a bare binary patch for git apply and review tools, to stdout or a file.
+
+package workflow
+
+import (
+	"context"
+
+	"github.com/kstenerud/yoloai/internal/cli/cliutil"
+	"github.com/kstenerud/yoloai/internal/fileutil"
+
+	yoloai "github.com/kstenerud/yoloai"
+	"github.com/kstenerud/yoloai/yoerrors"
+	"github.com/spf13/cobra"
+)
+
+// diffPatchRequested reports whether the diff is to be written as a patch:
+// --format patch, or -o on its own.
+func diffPatchRequested(cmd *cobra.Command) (bool, error) {
+	format, _ := cmd.Flags().GetString("format")
+	output, _ := cmd.Flags().GetString("output")
+	switch format {
+	case "patch":
+		return true, nil
+	case "text":
+		if output == "" {
+			return false, nil
+		}
+		if cmd.Flags().Changed("format") {
+			return false, yoerrors.NewUsageError("-o writes a patch file: use it with --format patch")
+		}
+		return true, nil
+	default:
+		return false, yoerrors.NewUsageError("unknown --format %q: want text or patch", format)
+	}
+}
+
+// diffPatch writes one tracked directory's changes as a binary patch: the
+// commit or range ref when given, otherwise everything since the baseline,
+// uncommitted edits included, as the text diff shows. Unlike the text output,
+// no changes is an empty patch rather than a "No changes" line, so the output
+// can go straight into git apply. With --json, the patch must go to a file and
+// the JSON reports it.
+func diffPatch(cmd *cobra.Command, name, hostPath, ref string, paths []string) error {
+	output, _ := cmd.Flags().GetString("output")
+	toStdout := output == "" || output == "-"
+	if toStdout && cliutil.JSONEnabled(cmd) {
+		return yoerrors.NewUsageError("--json needs the patch written to a file: yoloai diff %s --format patch -o <file> --json", name)
+	}
+	if !toStdout {
+		var err error
+		if output, err = cliutil.ExpandPath(output, cliutil.Layout().HomeDir, cliutil.Layout().Env().EnvForConfigInterpolation()); err != nil {
+			return err
+		}
+	}
+
+	var patch []byte
+	err := cliutil.WithTrackedDir(cmd, name, hostPath, func(ctx context.Context, wd *yoloai.Workdir) error {
+		if ref != "" {
+			out, err := wd.Diff(ctx, yoloai.WorkdirDiffOptions{Ref: ref})
+			if out != "" {
+				patch = []byte(out + "\n")
+			}
+			return err
+		}
+		var err error
+		patch, err = wd.Patch(ctx, yoloai.WorkdirPatchOptions{Paths: paths, IncludeUncommitted: true})
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	if toStdout {
+		_, err = cmd.OutOrStdout().Write(patch)
+		return err
+	}
+	if err := fileutil.WriteFile(output, patch, 0o600); err != nil {
+		return err
+	}
+	if cliutil.JSONEnabled(cmd) {
+		return cliutil.WriteJSON(cmd.OutOrStdout(), map[string]any{"file": output, "bytes": len(patch), "empty": len(patch) == 0})
+	}
+	if len(patch) == 0 {
+		_, err = cmd.ErrOrStderr().Write([]byte("No changes — wrote an empty patch to " + output + "\n"))
+		return err
+	}
+	return nil
+}
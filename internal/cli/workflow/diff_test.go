@@ -1,6 +1,6 @@
 // ABOUTME: Tests for `diff` argument/ref parsing (looksLikeRef, ref-vs-
 // ABOUTME: path split, range refs), diffAll's usage-error guards, diff
-// ABOUTME: output rendering (human/JSON), and commit-line/tag formatting.
+// ABOUTME: output rendering (human/JSON/--format patch), and commit-line/tag formatting.
 package workflow
 
 import (
@@ -8,6 +8,7 @@ import (
 	"encoding/json"
 	"testing"
 
+	"github.com/kstenerud/yoloai"
 	"github.com/spf13/cobra"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -163,6 +164,31 @@ func TestDiffAll_WithPositionalArgs_ReturnsUsageError(t *testing.T) {
 	assert.Error(t, err)
 }
 
+func TestDiffPatchRequested(t *testing.T) {
+	for _, tc := range []struct {
+		flags   []string
+		want    bool
+		wantErr bool
+	}{
+		{nil, false, false},
+		{[]string{"--format", "patch"}, true, false},
+		{[]string{"-o", "out.patch"}, true, false},
+		{[]string{"--format", "patch", "-o", "-"}, true, false},
+		{[]string{"--format", "text", "-o", "out.patch"}, false, true},
+		{[]string{"--format", "json"}, false, true},
+	} {
+		cmd := NewDiffCmd()
+		require.NoError(t, cmd.ParseFlags(tc.flags))
+		got, err := diffPatchRequested(cmd)
+		if tc.wantErr {
+			assert.Error(t, err, "%v", tc.flags)
+			continue
+		}
+		require.NoError(t, err, "%v", tc.flags)
+		assert.Equal(t, tc.want, got, "%v", tc.flags)
+	}
+}
+
 // --- writeDiffOutput tests ---
 
 func newCmdWithBuf(t *testing.T) (*cobra.Command, *bytes.Buffer) {
@@ -211,6 +237,36 @@ func TestWriteDiffOutput_JSONNonEmptyDiff(t *testing.T) {
 	assert.Equal(t, diff, result["diff"])
 }
 
+func TestWriteDiffChangesJSON_Stat(t *testing.T) {
+	cmd, buf := newCmdWithBuf(t)
+	changes := &yoloai.Changes{
+		Files:     []yoloai.FileChange{{Path: "a.go", Additions: 3, Deletions: 1}, {Path: "img.png", Binary: true}},
+		Additions: 3,
+		Deletions: 1,
+	}
+	require.NoError(t, writeDiffChangesJSON(cmd, " a.go | 4 +++-\n 2 files changed", changes))
+
+	var result struct {
+		Diff         string              `json:"diff"`
+		Files        []yoloai.FileChange `json:"files"`
+		FilesChanged int                 `json:"files_changed"`
+		Additions    int                 `json:"additions"`
+		Deletions    int                 `json:"deletions"`
+	}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &result), "output must be valid JSON: %q", buf.String())
+	assert.Contains(t, result.Diff, "2 files changed")
+	assert.Equal(t, changes.Files, result.Files)
+	assert.Equal(t, 2, result.FilesChanged)
+	assert.Equal(t, 3, result.Additions)
+	assert.Equal(t, 1, result.Deletions)
+}
+
+func TestWriteDiffChangesJSON_NoChangesIsEmptyList(t *testing.T) {
+	cmd, buf := newCmdWithBuf(t)
+	require.NoError(t, writeDiffChangesJSON(cmd, "", &yoloai.Changes{}))
+	assert.Contains(t, buf.String(), `"files": []`)
+}
+
 // --- formatCommitLine tests ---
 
 func TestFormatCommitLine_TruncatesSHATo12(t *testing.T) {
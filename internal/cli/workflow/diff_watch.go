@@ -0,0 +1,96 @@
+// ABOUTME: diff --watch — re-renders a sandbox's diff every few seconds while
+// ABOUTME: the agent works, redrawing in place on a terminal and appending otherwise.
+package workflow
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"golang.org/x/term"
+
+	yoloai "github.com/kstenerud/yoloai"
+	"github.com/spf13/cobra"
+)
+
+// clearScreen homes the cursor and clears the terminal, as watch(1) does.
+const clearScreen = "\033[H\033[2J"
+
+// watchDiff runs render every interval until interrupted, printing its output
+// whenever it changes. render is one of the ordinary diff handlers; its output
+// is captured by pointing the command's stdout at a buffer for the call.
+func watchDiff(cmd *cobra.Command, name string, interval time.Duration, render func() error) error {
+	out := cmd.OutOrStdout()
+	f, ok := out.(*os.File)
+	w := &diffWatcher{
+		render: func(buf io.Writer) error {
+			cmd.SetOut(buf)
+			defer cmd.SetOut(out)
+			return render()
+		},
+		out:      out,
+		errOut:   cmd.ErrOrStderr(),
+		redraw:   ok && term.IsTerminal(int(f.Fd())), //nolint:gosec // G115: fd is a small int
+		title:    fmt.Sprintf("Every %s: yoloai diff %s", interval, name),
+		interval: interval,
+		now:      time.Now,
+	}
+	return w.run(cmd.Context())
+}
+
+// diffWatcher polls a diff and prints it when it changes. On a terminal each
+// change redraws the screen under a title line; otherwise each is appended
+// under a timestamped separator, so the output can be logged.
+type diffWatcher struct {
+	render   func(io.Writer) error
+	out      io.Writer
+	errOut   io.Writer
+	redraw   bool
+	title    string
+	interval time.Duration
+	now      func() time.Time
+}
+
+// run polls until ctx is cancelled (Ctrl-C), which ends it cleanly. A failed
+// render is reported and retried on the next poll; a sandbox that has gone
+// away ends the watch.
+func (w *diffWatcher) run(ctx context.Context) error {
+	var last []byte
+	first := true
+	for {
+		var buf bytes.Buffer
+		err := w.render(&buf)
+		switch {
+		case ctx.Err() != nil:
+			return nil
+		case errors.Is(err, yoloai.ErrSandboxNotFound):
+			return err
+		case err != nil:
+			fmt.Fprintf(w.errOut, "diff: %v (retrying in %s)\n", err, w.interval) //nolint:errcheck
+		case first || !bytes.Equal(buf.Bytes(), last):
+			w.show(buf.Bytes())
+			last, first = buf.Bytes(), false
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(w.interval):
+		}
+	}
+}
+
+// show prints one rendering of the diff.
+func (w *diffWatcher) show(diff []byte) {
+	stamp := w.now().Format("15:04:05")
+	if w.redraw {
+		fmt.Fprintf(w.out, "%s%s  (updated %s, Ctrl-C to stop)\n\n", clearScreen, w.title, stamp) //nolint:errcheck
+	} else {
+		fmt.Fprintf(w.out, "--- %s ---\n", stamp) //nolint:errcheck
+	}
+	w.out.Write(diff) //nolint:errcheck
+}
@@ -0,0 +1,78 @@
+// ABOUTME: Tests for diff --watch: output only when the diff changes, redraw
+// ABOUTME: vs append framing, retried errors, and a vanished sandbox ending it.
+package workflow
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	yoloai "github.com/kstenerud/yoloai"
+)
+
+// scriptedDiffs returns a render function that writes each entry of diffs in
+// turn (an error entry fails that poll), then interrupts the watch.
+func scriptedDiffs(cancel context.CancelFunc, diffs ...any) func(io.Writer) error {
+	i := 0
+	return func(w io.Writer) error {
+		if i == len(diffs) {
+			cancel()
+			return context.Canceled
+		}
+		d := diffs[i]
+		i++
+		if err, ok := d.(error); ok {
+			return err
+		}
+		_, err := fmt.Fprint(w, d)
+		return err
+	}
+}
+
+func newTestDiffWatcher(render func(io.Writer) error, redraw bool) (*diffWatcher, *bytes.Buffer, *bytes.Buffer) {
+	var out, errOut bytes.Buffer
+	return &diffWatcher{
+		render:   render,
+		out:      &out,
+		errOut:   &errOut,
+		redraw:   redraw,
+		title:    "Every 1ms: yoloai diff box",
+		interval: time.Millisecond,
+		now:      func() time.Time { return time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC) },
+	}, &out, &errOut
+}
+
+func TestDiffWatcher_AppendsOnChange(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	w, out, _ := newTestDiffWatcher(scriptedDiffs(cancel, "a\n", "a\n", "b\n", "b\n"), false)
+	require.NoError(t, w.run(ctx))
+	assert.Equal(t, "--- 03:04:05 ---\na\n--- 03:04:05 ---\nb\n", out.String())
+}
+
+func TestDiffWatcher_Redraws(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	w, out, _ := newTestDiffWatcher(scriptedDiffs(cancel, "a\n", "b\n"), true)
+	require.NoError(t, w.run(ctx))
+	frame := clearScreen + "Every 1ms: yoloai diff box  (updated 03:04:05, Ctrl-C to stop)\n\n"
+	assert.Equal(t, frame+"a\n"+frame+"b\n", out.String())
+}
+
+func TestDiffWatcher_RetriesErrors(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	w, out, errOut := newTestDiffWatcher(scriptedDiffs(cancel, errors.New("boom"), "a\n", "a\n"), false)
+	require.NoError(t, w.run(ctx))
+	assert.Contains(t, errOut.String(), "diff: boom (retrying in 1ms)")
+	assert.Equal(t, "--- 03:04:05 ---\na\n", out.String())
+}
+
+func TestDiffWatcher_SandboxGone(t *testing.T) {
+	w, _, _ := newTestDiffWatcher(func(io.Writer) error { return yoloai.ErrSandboxNotFound }, false)
+	assert.ErrorIs(t, w.run(context.Background()), yoloai.ErrSandboxNotFound)
+}
@@ -88,13 +88,13 @@ func filesDispatch(cmd *cobra.Command, args []string) error {
 
 	switch subcmd {
 	case "put":
-		return runFilesPut(cmd, files, rest)
+		return runFilesPut(cmd, name, files, rest)
 	case "get":
-		return runFilesGet(cmd, files, rest)
+		return runFilesGet(cmd, name, files, rest)
 	case "ls":
-		return runFilesLs(cmd, files, rest)
+		return runFilesLs(cmd, name, files, rest)
 	case "rm":
-		return runFilesRm(cmd, files, rest)
+		return runFilesRm(cmd, name, files, rest)
 	case "path":
 		return runFilesPath(cmd, files)
 	default:
@@ -102,7 +102,7 @@ func filesDispatch(cmd *cobra.Command, args []string) error {
 	}
 }
 
-func runFilesPut(cmd *cobra.Command, files *yoloai.Files, args []string) error {
+func runFilesPut(cmd *cobra.Command, name string, files *yoloai.Files, args []string) error {
 	if len(args) == 0 {
 		return yoerrors.NewUsageError("at least one file is required")
 	}
@@ -113,17 +113,18 @@ func runFilesPut(cmd *cobra.Command, files *yoloai.Files, args []string) error {
 		return err
 	}
 
+	var placed []string
 	for _, src := range expanded {
-		placed, err := files.Import(cmd.Context(), src, overwrite)
+		dst, err := files.Import(cmd.Context(), src, overwrite)
 		if err != nil {
 			return err
 		}
-		fmt.Fprintln(cmd.OutOrStdout(), placed) //nolint:errcheck // best-effort output
+		placed = append(placed, dst)
 	}
-	return nil
+	return writeFileList(cmd, name, "put", placed)
 }
 
-func runFilesGet(cmd *cobra.Command, files *yoloai.Files, args []string) error {
+func runFilesGet(cmd *cobra.Command, name string, files *yoloai.Files, args []string) error {
 	if len(args) == 0 {
 		return yoerrors.NewUsageError("file name is required")
 	}
@@ -155,6 +156,7 @@ func runFilesGet(cmd *cobra.Command, files *yoloai.Files, args []string) error {
 		}
 	}
 
+	written := make([]string, 0, len(matches))
 	for _, rel := range matches {
 		// Compute final destination for this file
 		fileDst := absDst
@@ -164,12 +166,12 @@ func runFilesGet(cmd *cobra.Command, files *yoloai.Files, args []string) error {
 		if err := files.Export(cmd.Context(), rel, fileDst, overwrite); err != nil {
 			return err
 		}
-		fmt.Fprintln(cmd.OutOrStdout(), fileDst) //nolint:errcheck // best-effort output
+		written = append(written, fileDst)
 	}
-	return nil
+	return writeFileList(cmd, name, "get", written)
 }
 
-func runFilesLs(cmd *cobra.Command, files *yoloai.Files, args []string) error {
+func runFilesLs(cmd *cobra.Command, name string, files *yoloai.Files, args []string) error {
 	patterns := args
 	if len(patterns) == 0 {
 		patterns = []string{"*"}
@@ -180,13 +182,10 @@ func runFilesLs(cmd *cobra.Command, files *yoloai.Files, args []string) error {
 		return err
 	}
 
-	for _, n := range names {
-		fmt.Fprintln(cmd.OutOrStdout(), n) //nolint:errcheck // best-effort output
-	}
-	return nil
+	return writeFileList(cmd, name, "", names)
 }
 
-func runFilesRm(cmd *cobra.Command, files *yoloai.Files, args []string) error {
+func runFilesRm(cmd *cobra.Command, name string, files *yoloai.Files, args []string) error {
 	if len(args) == 0 {
 		return yoerrors.NewUsageError("glob pattern is required")
 	}
@@ -203,16 +202,38 @@ func runFilesRm(cmd *cobra.Command, files *yoloai.Files, args []string) error {
 		if err := files.Remove(rel); err != nil {
 			return err
 		}
-		fmt.Fprintln(cmd.OutOrStdout(), rel) //nolint:errcheck // best-effort output
 	}
-	return nil
+	return writeFileList(cmd, name, "rm", matches)
 }
 
 func runFilesPath(cmd *cobra.Command, files *yoloai.Files) error {
+	if cliutil.JSONEnabled(cmd) {
+		return cliutil.WriteJSON(cmd.OutOrStdout(), map[string]string{"path": files.Path()})
+	}
 	fmt.Fprintln(cmd.OutOrStdout(), files.Path()) //nolint:errcheck // best-effort output
 	return nil
 }
 
+// filesResultJSON is the --json result of the files subcommands.
+type filesResultJSON struct {
+	Name   string   `json:"name"`
+	Action string   `json:"action,omitempty"`
+	Files  []string `json:"files"`
+}
+
+// writeFileList prints the files a subcommand placed, wrote, listed, or
+// removed: one per line, or with --json an object carrying the sandbox name,
+// the action (absent for ls), and the files.
+func writeFileList(cmd *cobra.Command, name, action string, paths []string) error {
+	if cliutil.JSONEnabled(cmd) {
+		return cliutil.WriteJSON(cmd.OutOrStdout(), filesResultJSON{Name: name, Action: action, Files: cliutil.EmptyIfNil(paths)})
+	}
+	for _, p := range paths {
+		fmt.Fprintln(cmd.OutOrStdout(), p) //nolint:errcheck // best-effort output
+	}
+	return nil
+}
+
 // hasGlobMeta reports whether s contains glob metacharacters.
 func hasGlobMeta(s string) bool {
 	return strings.ContainsAny(s, "*?[")
@@ -0,0 +1,165 @@
+// ABOUTME: Cobra "pr" command: pushes a sandbox's commits to a branch of origin
+// ABOUTME: and opens a GitHub pull request for it with the gh CLI.
+package workflow
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/kstenerud/yoloai/internal/cli/cliutil"
+
+	yoloai "github.com/kstenerud/yoloai"
+	"github.com/kstenerud/yoloai/internal/sysexec"
+	"github.com/kstenerud/yoloai/yoerrors"
+	"github.com/spf13/cobra"
+)
+
+// prBranchPrefix namespaces the default PR branch so it can't collide with a
+// branch someone already works on.
+const prBranchPrefix = "yoloai/"
+
+type prOpts struct {
+	branch string
+	base   string
+	title  string
+	draft  bool
+}
+
+// prJSON is the --json shape of a pr.
+type prJSON struct {
+	Name    string `json:"name"`
+	Remote  string `json:"remote"`
+	Branch  string `json:"branch"`
+	Commits int    `json:"commits"`
+	URL     string `json:"url"`
+	Created bool   `json:"created"`
+}
+
+func NewPRCmd() *cobra.Command {
+	opts := &prOpts{}
+	cmd := &cobra.Command{
+		Use:   "pr <name>",
+		Short: "Push a sandbox's commits to a branch and open a pull request",
+		Long: `Push a sandbox's commits to a branch of the workdir's origin and open a
+GitHub pull request for it.
+
+The commits are replayed onto the sandbox's baseline and force-pushed to
+yoloai/<name> (--branch changes it); your checkout and its branches are not
+touched, and uncommitted work stays behind. The pull request's title and
+description are drafted as 'yoloai describe' would, so the description
+carries the sandbox's prompt.
+
+The pull request is opened with the GitHub CLI (gh), using its login or
+GH_TOKEN/GITHUB_TOKEN. When a pull request for the branch is already open,
+the push updates it and no new one is created.`,
+		Example: `  yoloai pr mybox
+  yoloai pr mybox --draft --base develop
+  yoloai pr mybox --branch fix/login-timeout --title "Fix login timeout"`,
+		GroupID: cliutil.GroupWorkflow,
+		Args:    cobra.ExactArgs(1),
+		RunE:    func(cmd *cobra.Command, args []string) error { return runPR(cmd, args[0], opts) },
+	}
+
+	cmd.Flags().StringVar(&opts.branch, "branch", "", "Branch to push to (default: yoloai/<name>)")
+	cmd.Flags().StringVar(&opts.base, "base", "", "Branch the pull request merges into (default: the repository's default branch)")
+	cmd.Flags().StringVar(&opts.title, "title", "", "Pull request title (default: drafted from the agent's result or prompt)")
+	cmd.Flags().BoolVar(&opts.draft, "draft", false, "Open the pull request as a draft")
+
+	return cmd
+}
+
+func runPR(cmd *cobra.Command, name string, opts *prOpts) error {
+	if err := cliutil.ValidateName(name); err != nil {
+		return err
+	}
+	branch := opts.branch
+	if branch == "" {
+		branch = prBranchPrefix + name
+	}
+	if _, err := exec.LookPath("gh"); err != nil {
+		return yoerrors.NewUsageError("yoloai pr needs the GitHub CLI (gh): install it from https://cli.github.com and run 'gh auth login'")
+	}
+
+	return cliutil.WithSandbox(cmd, name, func(ctx context.Context, sb *yoloai.Sandbox) error {
+		in, err := gatherDescribeInput(ctx, sb)
+		if err != nil {
+			return err
+		}
+		d := draftDescription(in)
+		if opts.title != "" {
+			d.Title = opts.title
+		}
+
+		result, err := sb.Workdir().Publish(ctx, yoloai.WorkdirPublishOptions{Ref: "refs/heads/" + branch})
+		if err != nil {
+			return err
+		}
+		if result == nil {
+			return yoerrors.NewUsageError("sandbox %q has no commits to open a pull request for; ask the agent to commit its work first", name)
+		}
+		if dirty, err := sb.Workdir().HasUncommittedChanges(ctx); err == nil && dirty {
+			fmt.Fprintf(cmd.ErrOrStderr(), "Note: %s has uncommitted changes; they are not part of the pull request\n", name) //nolint:errcheck
+		}
+
+		// gh acts with the user's own login; it gets what it needs to find that
+		// login and nothing else from the host.
+		env := cliutil.Layout().Env().EnvForGitHubCLI()
+		url, err := runGH(ctx, env, "pr", "list", "--repo", result.Remote, "--head", branch, "--state", "open", "--json", "url", "--jq", ".[0].url // empty")
+		if err != nil {
+			return err
+		}
+		created := url == ""
+		if created {
+			if url, err = runGH(ctx, env, prCreateArgs(result.Remote, branch, opts.base, d, opts.draft)...); err != nil {
+				return fmt.Errorf("%w\nthe commits are on branch %s of %s; open the pull request by hand", err, branch, result.Remote)
+			}
+		}
+
+		if cliutil.JSONEnabled(cmd) {
+			return cliutil.WriteJSON(cmd.OutOrStdout(), prJSON{
+				Name:    name,
+				Remote:  result.Remote,
+				Branch:  branch,
+				Commits: result.Commits,
+				URL:     url,
+				Created: created,
+			})
+		}
+		verb := "Opened"
+		if !created {
+			verb = "Updated"
+		}
+		_, err = fmt.Fprintf(cmd.OutOrStdout(), "%s pull request with %d commit(s) from %s: %s\n", verb, result.Commits, branch, url)
+		return err
+	})
+}
+
+// prCreateArgs is the gh command line that opens the pull request.
+func prCreateArgs(repo, branch, base string, d description, draft bool) []string {
+	args := []string{"pr", "create", "--repo", repo, "--head", branch, "--title", d.Title, "--body", d.Body}
+	if base != "" {
+		args = append(args, "--base", base)
+	}
+	if draft {
+		args = append(args, "--draft")
+	}
+	return args
+}
+
+// runGH runs gh and returns its trimmed stdout; a failure carries gh's stderr.
+func runGH(ctx context.Context, env []string, args ...string) (string, error) {
+	var stdout, stderr bytes.Buffer
+	c := sysexec.CommandContext(ctx, env, "gh", args...)
+	c.Stdout = &stdout
+	c.Stderr = &stderr
+	if err := c.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("gh %s %s: %s", args[0], args[1], msg)
+		}
+		return "", fmt.Errorf("gh %s %s: %w", args[0], args[1], err)
+	}
+	return strings.TrimSpace(stdout.String()), nil
+}
@@ -0,0 +1,40 @@
+// ABOUTME: Tests for the pr command's gh invocation. The push itself is
+// ABOUTME: Publish, tested against real git in copyflow.
+
+package workflow
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPRCreateArgs(t *testing.T) {
+	d := description{Title: "Fix login", Body: "## Why\n\nfix it\n"}
+	assert.Equal(t,
+		[]string{"pr", "create", "--repo", "git@github.com:org/repo.git", "--head", "yoloai/box", "--title", "Fix login", "--body", d.Body},
+		prCreateArgs("git@github.com:org/repo.git", "yoloai/box", "", d, false))
+	assert.Equal(t,
+		[]string{"pr", "create", "--repo", "r", "--head", "b", "--title", "Fix login", "--body", d.Body, "--base", "develop", "--draft"},
+		prCreateArgs("r", "b", "develop", d, true))
+}
+
+func TestRunGH(t *testing.T) {
+	bin := t.TempDir()
+	script := "#!/bin/sh\nif [ \"$2\" = fail ]; then echo 'no such repo' >&2; exit 1; fi\necho \"  https://github.com/org/repo/pull/$2  \"\n"
+	require.NoError(t, os.WriteFile(filepath.Join(bin, "gh"), []byte(script), 0o755)) //nolint:gosec // test stub must be executable
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+	env := []string{"PATH=" + os.Getenv("PATH")}
+
+	out, err := runGH(context.Background(), env, "pr", "7")
+	require.NoError(t, err)
+	assert.Equal(t, "https://github.com/org/repo/pull/7", out)
+
+	_, err = runGH(context.Background(), env, "pr", "fail")
+	require.Error(t, err)
+	assert.Equal(t, "gh pr fail: no such repo", err.Error(), "gh's own message is the error")
+}
@@ -0,0 +1,149 @@
+// ABOUTME: Cobra "publish" command: mirrors a sandbox's commits to a scratch
+// ABOUTME: ref of a remote, once or (--watch) each time the agent commits.
+package workflow
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/kstenerud/yoloai/internal/cli/cliutil"
+
+	yoloai "github.com/kstenerud/yoloai"
+	"github.com/kstenerud/yoloai/yoerrors"
+	"github.com/spf13/cobra"
+)
+
+type publishOpts struct {
+	remote   string
+	ref      string
+	watch    bool
+	interval time.Duration
+}
+
+// publishJSON is the --json shape of a publish.
+type publishJSON struct {
+	Name         string `json:"name"`
+	Published    bool   `json:"published"`
+	Remote       string `json:"remote,omitempty"`
+	Ref          string `json:"ref,omitempty"`
+	Commits      int    `json:"commits"`
+	SourceHead   string `json:"source_head,omitempty"`
+	PublishedSHA string `json:"published_sha,omitempty"`
+}
+
+func NewPublishCmd() *cobra.Command {
+	opts := &publishOpts{}
+	cmd := &cobra.Command{
+		Use:   "publish <name>",
+		Short: "Push a sandbox's commits to a scratch ref of a remote",
+		Long: `Push a sandbox's commits to a scratch ref of a remote, as an off-machine
+backup of the agent's progress that teammates can fetch and inspect.
+
+The commits go to refs/yoloai/<name> of the workdir's origin (--remote and
+--ref change both), outside refs/heads so they never show up as a branch.
+Each publish replaces the ref with the sandbox's current commits, replayed onto
+its baseline; your checkout, its branches and the baseline are not touched, and
+uncommitted work stays behind. Your own git credentials do the push.
+
+--watch keeps publishing whenever the agent commits, until interrupted.
+
+Fetch a published sandbox with:
+  git fetch origin refs/yoloai/<name>:refs/remotes/yoloai/<name>`,
+		Example: `  yoloai publish mybox
+  yoloai publish mybox --watch --interval 5m
+  yoloai publish mybox --remote backup --ref refs/yoloai/alice/mybox`,
+		GroupID: cliutil.GroupWorkflow,
+		Args:    cobra.ExactArgs(1),
+		RunE:    func(cmd *cobra.Command, args []string) error { return runPublish(cmd, args[0], opts) },
+	}
+
+	cmd.Flags().StringVar(&opts.remote, "remote", "", "Remote to push to: a remote name of the workdir's repository or a URL (default: its origin)")
+	cmd.Flags().StringVar(&opts.ref, "ref", "", "Full ref to push to (default: refs/yoloai/<name>)")
+	cmd.Flags().BoolVarP(&opts.watch, "watch", "w", false, "Keep publishing each time the agent commits, until interrupted")
+	cmd.Flags().DurationVar(&opts.interval, "interval", time.Minute, "With --watch: how often to check for new commits")
+
+	return cmd
+}
+
+func runPublish(cmd *cobra.Command, name string, opts *publishOpts) error {
+	if err := cliutil.ValidateName(name); err != nil {
+		return err
+	}
+	if opts.watch && cliutil.JSONEnabled(cmd) {
+		return yoerrors.NewUsageError("--watch runs until interrupted and can't be combined with --json")
+	}
+	if opts.interval <= 0 {
+		return yoerrors.NewUsageError("--interval must be positive: %s", opts.interval)
+	}
+	pubOpts := yoloai.WorkdirPublishOptions{Remote: opts.remote, Ref: opts.ref}
+
+	return cliutil.WithWorkdir(cmd, name, func(ctx context.Context, wd *yoloai.Workdir) error {
+		if !opts.watch {
+			result, err := wd.Publish(ctx, pubOpts)
+			if err != nil {
+				return err
+			}
+			return writePublishResult(cmd, name, result)
+		}
+		return watchPublish(ctx, cmd, name, wd, pubOpts, opts.interval)
+	})
+}
+
+// watchPublish publishes whenever the sandbox's newest commit changes, checking
+// every interval until ctx is cancelled (Ctrl-C). A failed publish — the remote
+// briefly unreachable, say — is reported and retried on the next check; a
+// sandbox that has gone away ends the watch.
+func watchPublish(ctx context.Context, cmd *cobra.Command, name string, wd *yoloai.Workdir, opts yoloai.WorkdirPublishOptions, interval time.Duration) error {
+	fmt.Fprintf(cmd.ErrOrStderr(), "Publishing %s every %s as the agent commits (Ctrl-C to stop)\n", name, interval) //nolint:errcheck
+	published := ""
+	for {
+		commits, err := wd.Commits(ctx, yoloai.WorkdirCommitsOptions{})
+		if err == nil && len(commits) > 0 && commits[len(commits)-1].SHA != published {
+			var result *yoloai.PublishResult
+			if result, err = wd.Publish(ctx, opts); err == nil && result != nil {
+				published = result.SourceHead
+				err = writePublishResult(cmd, name, result)
+			}
+		}
+		switch {
+		case ctx.Err() != nil:
+			return nil
+		case errors.Is(err, yoloai.ErrSandboxNotFound):
+			return err
+		case err != nil:
+			fmt.Fprintf(cmd.ErrOrStderr(), "publish %s: %v (retrying in %s)\n", name, err, interval) //nolint:errcheck
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(interval):
+		}
+	}
+}
+
+func writePublishResult(cmd *cobra.Command, name string, result *yoloai.PublishResult) error {
+	if cliutil.JSONEnabled(cmd) {
+		out := publishJSON{Name: name}
+		if result != nil {
+			out = publishJSON{
+				Name:         name,
+				Published:    true,
+				Remote:       result.Remote,
+				Ref:          result.Ref,
+				Commits:      result.Commits,
+				SourceHead:   result.SourceHead,
+				PublishedSHA: result.PublishedSHA,
+			}
+		}
+		return cliutil.WriteJSON(cmd.OutOrStdout(), out)
+	}
+	if result == nil {
+		_, err := fmt.Fprintln(cmd.OutOrStdout(), "No commits to publish")
+		return err
+	}
+	_, err := fmt.Fprintf(cmd.OutOrStdout(), "%d commit(s) published to %s %s (%.12s)\n", result.Commits, result.Remote, result.Ref, result.PublishedSHA)
+	return err
+}
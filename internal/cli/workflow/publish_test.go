@@ -0,0 +1,51 @@
+// ABOUTME: Tests for the publish command's flag checks and result rendering.
+// ABOUTME: Publish itself is tested against real git in copyflow.
+
+package workflow
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	yoloai "github.com/kstenerud/yoloai"
+	"github.com/kstenerud/yoloai/yoerrors"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWritePublishResult(t *testing.T) {
+	result := &yoloai.PublishResult{
+		Remote: "git@github.com:org/repo.git", Ref: "refs/yoloai/box", Commits: 2,
+		SourceHead: "aaaa", PublishedSHA: "0123456789abcdef",
+	}
+
+	cmd := &cobra.Command{}
+	var buf bytes.Buffer
+	cmd.SetOut(&buf)
+	require.NoError(t, writePublishResult(cmd, "box", result))
+	assert.Equal(t, "2 commit(s) published to git@github.com:org/repo.git refs/yoloai/box (0123456789ab)\n", buf.String())
+
+	buf.Reset()
+	require.NoError(t, writePublishResult(cmd, "box", nil))
+	assert.Equal(t, "No commits to publish\n", buf.String())
+}
+
+func TestWritePublishResult_JSON(t *testing.T) {
+	cmd := &cobra.Command{}
+	cmd.Flags().Bool("json", true, "")
+	var buf bytes.Buffer
+	cmd.SetOut(&buf)
+
+	require.NoError(t, writePublishResult(cmd, "box", nil))
+	var got publishJSON
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &got))
+	assert.Equal(t, publishJSON{Name: "box"}, got, "nothing to publish is published:false, not an error")
+}
+
+func TestRunPublish_FlagChecks(t *testing.T) {
+	var usage *yoerrors.UsageError
+	err := runPublish(NewPublishCmd(), "box", &publishOpts{interval: 0})
+	assert.ErrorAs(t, err, &usage)
+}
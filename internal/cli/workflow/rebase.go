@@ -0,0 +1,94 @@
+// ABOUTME: `yoloai rebase`: catches a sandbox's :copy dir up with its host directory,
+// ABOUTME: replaying the agent's commits and edits onto the host's current state.
+package workflow
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/kstenerud/yoloai/internal/cli/cliutil"
+
+	yoloai "github.com/kstenerud/yoloai"
+	"github.com/kstenerud/yoloai/yoerrors"
+	"github.com/spf13/cobra"
+)
+
+// rebaseJSON is the --json result of a rebase.
+type rebaseJSON struct {
+	Name             string `json:"name"`
+	Action           string `json:"action"`
+	Dir              string `json:"dir"`
+	Baseline         string `json:"baseline"`
+	PreviousBaseline string `json:"previous_baseline"`
+	Commits          int    `json:"commits"`
+	Uncommitted      bool   `json:"uncommitted"`
+}
+
+func NewRebaseCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "rebase <name> [<dir>]",
+		Short: "Replay the agent's work onto the host directory's current state",
+		Long: `Catch a long-running sandbox up with its host directory without losing
+the agent's work. The work copy's baseline moves to the host directory as
+it is now, and the agent's commits beyond the old baseline, then its
+uncommitted changes, are replayed on top. The commits get new SHAs. The
+running agent is told, as it is after a reset.
+
+The replay is rehearsed on the host first. If any commit or the
+uncommitted changes conflict with the host's changes, the rebase stops
+with git's report and the sandbox is left as it was; apply the work or
+reset instead. Like a reset, the work copy ends up holding exactly the
+copied files: gitignored files the agent made, its other branches, stashes
+and tags are not kept.
+
+<dir> is required when the sandbox tracks 2+ directories, as for diff.
+The sandbox must be running.`,
+		Example: cliutil.CommandExamples("rebase"),
+		GroupID: cliutil.GroupWorkflow,
+		Args:    cobra.RangeArgs(1, 2),
+		RunE:    runRebase,
+	}
+}
+
+func runRebase(cmd *cobra.Command, args []string) error {
+	name, rest, err := cliutil.ResolveName(cmd, args)
+	if err != nil {
+		return err
+	}
+	env, err := cliutil.SandboxMetadata(cmd, name)
+	if err != nil {
+		return err
+	}
+	hostPath, selected, rest, err := cliutil.SelectTrackedDir(env, rest)
+	if err != nil {
+		return err
+	}
+	if len(rest) > 0 {
+		return yoerrors.NewUsageError("unexpected argument %q", rest[0])
+	}
+
+	return cliutil.WithTrackedDir(cmd, name, hostPath, func(ctx context.Context, wd *yoloai.Workdir) error {
+		slog.Info("rebasing sandbox", "event", "sandbox.rebase", "sandbox", name, "dir", selected.HostPath)
+		res, err := wd.Rebase(ctx)
+		if err != nil {
+			return cliutil.SandboxErrorHint(name, err)
+		}
+
+		w := cmd.OutOrStdout()
+		if cliutil.JSONEnabled(cmd) {
+			return cliutil.WriteJSON(w, rebaseJSON{
+				Name: name, Action: "rebase", Dir: selected.HostPath,
+				Baseline: res.Baseline, PreviousBaseline: res.PreviousBaseline,
+				Commits: res.Commits, Uncommitted: res.Uncommitted,
+			})
+		}
+		carried := fmt.Sprintf("%d commit(s)", res.Commits)
+		if res.Uncommitted {
+			carried += " and uncommitted changes"
+		}
+		_, err = fmt.Fprintf(w, "Rebased %s onto the host's current state (baseline %s, was %s): replayed %s\n",
+			name, short8(res.Baseline), short8(res.PreviousBaseline), carried)
+		return err
+	})
+}
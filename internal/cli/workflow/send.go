@@ -0,0 +1,99 @@
+// ABOUTME: Cobra "send" command: submits a follow-up prompt to a running
+// ABOUTME: sandbox's agent without attaching, for scripted multi-turn runs.
+package workflow
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/kstenerud/yoloai/internal/cli/cliutil"
+
+	yoloai "github.com/kstenerud/yoloai"
+	"github.com/kstenerud/yoloai/yoerrors"
+	"github.com/spf13/cobra"
+)
+
+func NewSendCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "send <name> <prompt>...",
+		Short: "Send a follow-up prompt to a sandbox's agent",
+		Long: `Send a follow-up prompt to a sandbox's agent without attaching.
+
+The prompt is typed into the agent's session and submitted the way that agent
+expects, exactly as if you had attached and entered it yourself. The words
+after the name are joined with spaces; use --prompt-file for a longer prompt.
+
+send waits for nothing: pair it with 'yoloai wait' to script a multi-turn run.
+If the agent is still working on its last prompt, send refuses rather than
+interrupt it; --force sends anyway (most agents queue or act on the text
+mid-task).`,
+		Example: `  yoloai wait mybox && yoloai send mybox "now add tests" && yoloai wait mybox
+  yoloai send mybox -f review-notes.md`,
+		GroupID: cliutil.GroupWorkflow,
+		Args:    cobra.MinimumNArgs(1),
+		RunE:    runSendCmd,
+	}
+
+	cmd.Flags().StringP("prompt-file", "f", "", "File containing the prompt")
+	cmd.Flags().Bool("force", false, "Send even if the agent is still working")
+
+	return cmd
+}
+
+func runSendCmd(cmd *cobra.Command, args []string) error {
+	name := args[0]
+	if err := cliutil.ValidateName(name); err != nil {
+		return err
+	}
+	text, err := sendText(args[1:], cliutil.FlagStr(cmd, "prompt-file"))
+	if err != nil {
+		return err
+	}
+	force, _ := cmd.Flags().GetBool("force")
+
+	return cliutil.WithSandbox(cmd, name, func(ctx context.Context, sb *yoloai.Sandbox) error {
+		if !force {
+			info, err := sb.Inspect(ctx)
+			if err != nil {
+				return cliutil.SandboxErrorHint(name, err)
+			}
+			if info.Status == yoloai.StatusActive {
+				return yoerrors.NewUsageError("the agent in sandbox %s is still working: wait for it with 'yoloai wait %s', or pass --force to send anyway", name, name)
+			}
+		}
+		if err := sb.Agent().SendInput(ctx, text); err != nil {
+			return cliutil.SandboxErrorHint(name, err)
+		}
+		if cliutil.JSONEnabled(cmd) {
+			return cliutil.WriteJSON(cmd.OutOrStdout(), map[string]string{
+				"name":   name,
+				"action": "sent",
+			})
+		}
+		_, err := fmt.Fprintf(cmd.OutOrStdout(), "Sent prompt to %s\n", name)
+		return err
+	})
+}
+
+// sendText resolves the prompt from the words after the name or from
+// --prompt-file, which are mutually exclusive; an empty prompt is refused
+// since submitting it would only press Enter at the agent.
+func sendText(words []string, promptFile string) (string, error) {
+	if promptFile != "" {
+		if len(words) > 0 {
+			return "", yoerrors.NewUsageError("give the prompt as arguments or with --prompt-file, not both")
+		}
+		data, err := os.ReadFile(promptFile) //nolint:gosec // G304: user-named prompt file
+		if err != nil {
+			return "", yoerrors.NewUsageError("read prompt file: %s", err)
+		}
+		words = []string{string(data)}
+	}
+	text := strings.TrimSpace(strings.Join(words, " "))
+	if text == "" {
+		return "", yoerrors.NewUsageError("prompt required: yoloai send <name> <prompt>... (or --prompt-file)")
+	}
+	return text, nil
+}
@@ -0,0 +1,34 @@
+// ABOUTME: Tests for the send command's prompt resolution (arguments vs
+// ABOUTME: --prompt-file). Delivery itself is tested in the orchestrator.
+
+package workflow
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/kstenerud/yoloai/yoerrors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSendText(t *testing.T) {
+	text, err := sendText([]string{"now", "add", "tests"}, "")
+	require.NoError(t, err)
+	assert.Equal(t, "now add tests", text, "the words after the name are joined with spaces")
+
+	file := filepath.Join(t.TempDir(), "notes.md")
+	require.NoError(t, os.WriteFile(file, []byte("line one\nline two\n"), 0o600))
+	text, err = sendText(nil, file)
+	require.NoError(t, err)
+	assert.Equal(t, "line one\nline two", text, "a prompt file keeps its lines")
+
+	var usage *yoerrors.UsageError
+	_, err = sendText([]string{"words"}, file)
+	assert.ErrorAs(t, err, &usage, "arguments and --prompt-file are mutually exclusive")
+	_, err = sendText([]string{"  "}, "")
+	assert.ErrorAs(t, err, &usage, "an empty prompt is refused")
+	_, err = sendText(nil, filepath.Join(t.TempDir(), "missing.md"))
+	assert.ErrorAs(t, err, &usage)
+}
@@ -0,0 +1,209 @@
+// ABOUTME: Cobra "serve" command: runs the local web dashboard (internal/dashboard)
+// ABOUTME: over the CLI's sandbox operations until interrupted.
+package workflow
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kstenerud/yoloai/internal/cli/cliutil"
+
+	yoloai "github.com/kstenerud/yoloai"
+	"github.com/kstenerud/yoloai/internal/dashboard"
+	"github.com/kstenerud/yoloai/internal/events"
+	"github.com/kstenerud/yoloai/yoerrors"
+	"github.com/spf13/cobra"
+)
+
+// serveReadHeaderTimeout bounds the header read so a stuck client can't pin a
+// connection open.
+const serveReadHeaderTimeout = 30 * time.Second
+
+// serveEventsInterval is how often serve lists sandboxes to feed the
+// dashboard's event stream.
+const serveEventsInterval = 5 * time.Second
+
+func NewServeCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "serve",
+		Short: "Run a local web dashboard of all sandboxes",
+		Long: `Run a local web dashboard of all sandboxes, until interrupted.
+
+The page lists every sandbox with its status, tails the selected sandbox's
+agent output live, shows its diff, and has buttons to apply its changes (all
+of them, committed and uncommitted, as 'yoloai apply --include-uncommitted'
+would) and to destroy it. The list updates as sandboxes change, pushed to
+the page as server-sent events from /api/events; other tools holding the
+page's token can subscribe to the same stream.
+
+By default the dashboard listens on 127.0.0.1 only. Anyone who can load the
+page can apply and destroy sandboxes, so think twice before --host exposes it
+beyond this machine.`,
+		Example: `  yoloai serve
+  yoloai serve --port 8080`,
+		GroupID: cliutil.GroupWorkflow,
+		Args:    cobra.NoArgs,
+		RunE:    runServe,
+	}
+
+	cmd.Flags().Int("port", 7777, "Port to listen on")
+	cmd.Flags().String("host", "127.0.0.1", "Address to listen on")
+
+	return cmd
+}
+
+func runServe(cmd *cobra.Command, _ []string) error {
+	port, _ := cmd.Flags().GetInt("port")
+	host := cliutil.FlagStr(cmd, "host")
+	if port < 0 || port > 65535 {
+		return yoerrors.NewUsageError("--port must be between 0 and 65535: %d", port)
+	}
+
+	sys, err := cliutil.System()
+	if err != nil {
+		return err
+	}
+	// The page and the event stream both list every few seconds; a lister
+	// keeps the runtimes open between them and caches what they inspect.
+	lister := sys.NewSandboxLister()
+	defer lister.Close() //nolint:errcheck // best-effort cleanup
+	svc := &dashboardService{cmd: cmd, lister: lister}
+	hub := events.NewHub()
+	dash, err := dashboard.New(svc, hub, host)
+	if err != nil {
+		return err
+	}
+	ln, err := net.Listen("tcp", net.JoinHostPort(host, strconv.Itoa(port)))
+	if err != nil {
+		return fmt.Errorf("listen on %s: %w", net.JoinHostPort(host, strconv.Itoa(port)), err)
+	}
+	if ip := net.ParseIP(host); ip == nil || !ip.IsLoopback() {
+		fmt.Fprintf(cmd.ErrOrStderr(), "Warning: the dashboard is reachable beyond this machine at %s, and can apply and destroy sandboxes\n", host) //nolint:errcheck
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "yoloAI dashboard at http://%s/ (Ctrl-C to stop)\n", ln.Addr()) //nolint:errcheck
+
+	ctx := cmd.Context()
+	srv := &http.Server{
+		Handler:           dash,
+		ReadHeaderTimeout: serveReadHeaderTimeout,
+		// Event streams never finish on their own; they end with ctx, which
+		// lets Shutdown complete.
+		BaseContext: func(net.Listener) context.Context { return ctx },
+	}
+	go hub.Watch(ctx, serveEventsInterval, svc.List, nil)
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = srv.Shutdown(shutdownCtx) //nolint:errcheck // best-effort on the way out
+	}()
+	if err := srv.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// dashboardService implements dashboard.SandboxService with the same library
+// calls the list, log, diff, apply and destroy commands make.
+type dashboardService struct {
+	cmd    *cobra.Command
+	lister *yoloai.SandboxLister
+}
+
+var _ dashboard.SandboxService = (*dashboardService)(nil)
+
+func (s *dashboardService) List(ctx context.Context) ([]*yoloai.SandboxInfo, []yoloai.BackendType, error) {
+	return s.lister.List(ctx)
+}
+
+func (s *dashboardService) Log(_ context.Context, name string, lines int) (string, error) {
+	c, err := cliutil.Client(s.cmd)
+	if err != nil {
+		return "", err
+	}
+	defer c.Close() //nolint:errcheck // backend-less close is a no-op
+	sb, err := c.Sandbox(name)
+	if err != nil {
+		return "", err
+	}
+	raw, err := sb.Agent().TerminalLog(lines)
+	if err != nil {
+		return "", err
+	}
+	var out bytes.Buffer
+	if err := cliutil.StripANSI(&out, strings.NewReader(raw)); err != nil {
+		return "", err
+	}
+	return out.String(), nil
+}
+
+func (s *dashboardService) Diff(_ context.Context, name string) (string, error) {
+	var out string
+	err := cliutil.WithWorkdir(s.cmd, name, func(ctx context.Context, wd *yoloai.Workdir) error {
+		var diffErr error
+		out, diffErr = wd.Diff(ctx, yoloai.WorkdirDiffOptions{})
+		return diffErr
+	})
+	return out, err
+}
+
+// Apply lands everything the diff shows: the commits replayed as a series
+// when the host directory is a git repository and there are commits, plus
+// the uncommitted edits; otherwise the net diff as unstaged changes.
+func (s *dashboardService) Apply(_ context.Context, name string) (string, error) {
+	var msg string
+	err := cliutil.WithSandbox(s.cmd, name, func(ctx context.Context, sb *yoloai.Sandbox) error {
+		env, err := sb.Metadata()
+		if err != nil {
+			return err
+		}
+		if env.RepoURL != "" {
+			return yoerrors.NewUsageError("sandbox %q was cloned from %s: push its commits with: yoloai apply %s --push-branch <branch>", name, env.RepoURL, name)
+		}
+		wd := sb.Workdir()
+		commits, err := wd.Commits(ctx, yoloai.WorkdirCommitsOptions{})
+		if err != nil {
+			return err
+		}
+		isGit, err := wd.TargetIsGitRepo(ctx)
+		if err != nil {
+			return err
+		}
+		mode := yoloai.ApplyModeNoCommit
+		if isGit && len(commits) > 0 {
+			mode = yoloai.ApplyModeCommits
+		}
+		result, err := wd.Apply(ctx, yoloai.WorkdirApplyOptions{Mode: mode, IncludeUncommitted: true})
+		msg = applySummary(result)
+		return err
+	})
+	return msg, err
+}
+
+// applySummary is the dashboard's one-line account of an apply.
+func applySummary(result *yoloai.ApplyResult) string {
+	switch {
+	case result == nil:
+		return "No changes to apply"
+	case len(result.Commits) > 0 && result.UncommittedApplied:
+		return fmt.Sprintf("%d commit(s) and the uncommitted changes applied to %s", len(result.Commits), result.Dir)
+	case len(result.Commits) > 0:
+		return fmt.Sprintf("%d commit(s) applied to %s", len(result.Commits), result.Dir)
+	default:
+		return fmt.Sprintf("Changes applied to %s (unstaged)", result.Dir)
+	}
+}
+
+func (s *dashboardService) Destroy(_ context.Context, name string, abandonUnapplied bool) error {
+	return cliutil.WithSandbox(s.cmd, name, func(ctx context.Context, sb *yoloai.Sandbox) error {
+		_, err := sb.Destroy(ctx, yoloai.SandboxDestroyOptions{AbandonUnappliedWork: abandonUnapplied})
+		return err
+	})
+}
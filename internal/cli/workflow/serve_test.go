@@ -0,0 +1,21 @@
+// ABOUTME: Tests for the serve command's apply summary. The dashboard handler
+// ABOUTME: itself is tested in internal/dashboard.
+
+package workflow
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	yoloai "github.com/kstenerud/yoloai"
+)
+
+func TestApplySummary(t *testing.T) {
+	commits := []yoloai.AppliedCommit{{Subject: "a"}, {Subject: "b"}}
+	assert.Equal(t, "No changes to apply", applySummary(nil))
+	assert.Equal(t, "2 commit(s) applied to /p", applySummary(&yoloai.ApplyResult{Dir: "/p", Commits: commits}))
+	assert.Equal(t, "2 commit(s) and the uncommitted changes applied to /p",
+		applySummary(&yoloai.ApplyResult{Dir: "/p", Commits: commits, UncommittedApplied: true}))
+	assert.Equal(t, "Changes applied to /p (unstaged)", applySummary(&yoloai.ApplyResult{Dir: "/p", Stat: "1 file changed"}))
+}
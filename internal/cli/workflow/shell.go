@@ -0,0 +1,40 @@
+// ABOUTME: Cobra "shell" command: attaches to the plain shell window the
+// ABOUTME: sandbox's session keeps in the workdir, beside the agent's window.
+package workflow
+
+import (
+	"github.com/kstenerud/yoloai/internal/cli/cliutil"
+
+	yoloai "github.com/kstenerud/yoloai"
+	"github.com/spf13/cobra"
+)
+
+// NewShellCmd creates the shell command.
+func NewShellCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:     "shell <name>",
+		Short:   "Open a shell in the sandbox's workdir, beside the agent",
+		Example: cliutil.CommandExamples("shell"),
+		Long: `Open a shell in the sandbox's workdir, beside the agent.
+
+Every sandbox's tmux session has a second window, "shell", with a plain
+shell started in the workdir: look around the agent's work copy, run the
+tests, try its build, without typing into the agent's pane. shell attaches
+straight to it; once attached to either window, Ctrl-b n / Ctrl-b p switch
+between them.
+
+Exiting the shell closes the window; the next 'yoloai shell' opens a fresh
+one. A stopped sandbox is started first, as for attach. Detach with
+Ctrl-b d, after which the agent's window is the current one again.
+
+The session shows one window at a time to everyone attached, so a terminal
+attached to the agent follows you to the shell while you're in it.`,
+		GroupID: cliutil.GroupWorkflow,
+		Args:    cobra.ArbitraryArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			// The shell is yours, not the agent's: nothing to interleave
+			// with, so there is no already-attached refusal.
+			return runAttach(cmd, args, &attachOpts{window: yoloai.ShellWindow, force: true})
+		},
+	}
+}
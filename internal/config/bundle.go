@@ -0,0 +1,388 @@
+package config
+
+// ABOUTME: Config bundles: a tar of the user's setup (global and defaults config,
+// ABOUTME: profiles, agent definitions) with secrets left out, and its restore.
+
+import (
+	"archive/tar"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/kstenerud/yoloai/internal/fileutil"
+	"github.com/kstenerud/yoloai/yoerrors"
+	"gopkg.in/yaml.v3"
+)
+
+// BundleManifestName is the first entry of a bundle, identifying it as one.
+const BundleManifestName = "yoloai-bundle.json"
+
+// bundleFormat is the bundle layout version; bump it when the layout changes
+// incompatibly.
+const bundleFormat = 1
+
+// Limits on what import reads, so a bogus archive can't exhaust memory.
+const (
+	bundleMaxFileBytes  = 16 << 20
+	bundleMaxTotalBytes = 256 << 20
+)
+
+// bundleSkipNames are files in a profile or defaults dir that describe this
+// machine's state rather than the user's setup.
+var bundleSkipNames = map[string]bool{".last-build-checksum": true, ".DS_Store": true, ProfileOriginFileName: true}
+
+// bundleSecretFileNames are credential files never exported, wherever they sit.
+var bundleSecretFileNames = map[string]bool{
+	".npmrc": true, ".netrc": true, ".pypirc": true, ".env": true, ".git-credentials": true,
+	"credentials": true, "credentials.json": true, "id_rsa": true, "id_ecdsa": true, "id_ed25519": true,
+}
+
+// bundleSecretKeyWords: a YAML key whose last word ends in one of these holds
+// a secret (ANTHROPIC_API_KEY, GH_TOKEN, db_password, private_key). Only the
+// last word counts, so token_env (a variable name) and key_file (a path) don't.
+var bundleSecretKeyWords = []string{"key", "token", "secret", "password", "passwd", "credential", "credentials"}
+
+// bundleSecretKeyWord: a key with this word anywhere in it holds a secret. A
+// webhook URL is a bearer credential, whoever has it can post as the user
+// (notifications.webhook_url, notifications.slack_webhook).
+const bundleSecretKeyWord = "webhook"
+
+// bundleSecretValue matches values that are secrets whatever their key is
+// called: well-known API key prefixes, private keys, and incoming-webhook URLs.
+var bundleSecretValue = regexp.MustCompile(`(?:sk-ant-|sk-proj-|ghp_|gho_|ghu_|ghs_|github_pat_|xox[abposr]-|sk_live_|AIzaSy|AKIA[A-Z0-9]{16}|-----BEGIN [A-Z ]*PRIVATE KEY-----|hooks\.slack\.com/|discord(?:app)?\.com/api/webhooks/)`)
+
+// bundleEnvRef matches a value that only references a host variable, ${NAME}:
+// the secret stays on the host, so the value is safe to carry.
+var bundleEnvRef = regexp.MustCompile(`^\$\{[A-Za-z_][A-Za-z0-9_]*\}$`)
+
+// bundleManifest is the content of BundleManifestName.
+type bundleManifest struct {
+	Format        int       `json:"format"`
+	SchemaVersion int       `json:"schema_version"`
+	Created       time.Time `json:"created"`
+}
+
+// BundleOmission is something an export left out because it is, or holds, a
+// secret. Key is the dotted YAML key when a single value was dropped, and
+// empty when the whole file was.
+type BundleOmission struct {
+	Path   string `json:"path"`
+	Key    string `json:"key,omitempty"`
+	Reason string `json:"reason"`
+}
+
+// BundleExport reports what ExportBundle wrote.
+type BundleExport struct {
+	Files   []string         // bundle paths, relative to the data dir
+	Omitted []BundleOmission // secrets left out
+}
+
+// BundleImport reports what ImportBundle restored.
+type BundleImport struct {
+	Written   []string // files created or replaced
+	Unchanged []string // files already identical
+}
+
+// bundleRoots are the parts of the data dir a bundle carries: files at the
+// top level and directories walked whole.
+func bundleRoots(layout Layout) (files, dirs []string) {
+	return []string{layout.GlobalConfigPath()}, []string{layout.DefaultsDir(), layout.ProfilesDir(), layout.AgentsDir()}
+}
+
+// ExportBundle writes a tar of the user's setup to w: the global config, the
+// defaults dir, every profile and the user-defined agents. Secrets are left
+// out and reported: credential files whole, and single values in YAML files
+// (a secret-named key, or a value that looks like an API key) with the rest
+// of the file kept. A ${VAR} reference is not a secret and is kept.
+func ExportBundle(layout Layout, w io.Writer) (*BundleExport, error) {
+	var paths []string
+	files, dirs := bundleRoots(layout)
+	for _, f := range files {
+		if info, err := os.Lstat(f); err == nil && info.Mode().IsRegular() {
+			paths = append(paths, f)
+		}
+	}
+	for _, d := range dirs {
+		err := filepath.WalkDir(d, func(p string, e fs.DirEntry, err error) error {
+			switch {
+			case errors.Is(err, fs.ErrNotExist) && p == d:
+				return nil
+			case err != nil:
+				return err
+			case e.IsDir() || !e.Type().IsRegular() || bundleSkipNames[e.Name()]:
+				return nil
+			}
+			paths = append(paths, p)
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("export %s: %w", d, err)
+		}
+	}
+	sort.Strings(paths)
+
+	result := &BundleExport{}
+	tw := tar.NewWriter(w)
+	manifest, err := json.Marshal(bundleManifest{Format: bundleFormat, SchemaVersion: LibrarySchemaVersion, Created: time.Now().UTC()})
+	if err != nil {
+		return nil, err
+	}
+	if err := writeBundleEntry(tw, BundleManifestName, manifest, 0o644); err != nil {
+		return nil, err
+	}
+	for _, p := range paths {
+		rel, err := filepath.Rel(layout.DataDir, p)
+		if err != nil {
+			return nil, err
+		}
+		rel = filepath.ToSlash(rel)
+		data, err := os.ReadFile(p) //nolint:gosec // G304: walking the user's own data dir
+		if err != nil {
+			return nil, fmt.Errorf("export %s: %w", p, err)
+		}
+		data, omitted, keep := scrubBundleFile(rel, data)
+		result.Omitted = append(result.Omitted, omitted...)
+		if !keep {
+			continue
+		}
+		info, err := os.Stat(p)
+		if err != nil {
+			return nil, err
+		}
+		if err := writeBundleEntry(tw, rel, data, info.Mode().Perm()); err != nil {
+			return nil, err
+		}
+		result.Files = append(result.Files, rel)
+	}
+	if err := tw.Close(); err != nil {
+		return nil, fmt.Errorf("write bundle: %w", err)
+	}
+	return result, nil
+}
+
+func writeBundleEntry(tw *tar.Writer, name string, data []byte, perm fs.FileMode) error {
+	hdr := &tar.Header{Name: name, Mode: int64(perm), Size: int64(len(data)), Typeflag: tar.TypeReg, ModTime: time.Now()}
+	if err := tw.WriteHeader(hdr); err != nil {
+		return fmt.Errorf("write bundle: %w", err)
+	}
+	if _, err := tw.Write(data); err != nil {
+		return fmt.Errorf("write bundle: %w", err)
+	}
+	return nil
+}
+
+// scrubBundleFile decides what of one file goes into a bundle. keep is false
+// when the whole file is a secret; otherwise data is what to write, with any
+// secret YAML values removed.
+func scrubBundleFile(rel string, data []byte) (_ []byte, omitted []BundleOmission, keep bool) {
+	base := path.Base(rel)
+	ext := path.Ext(base)
+	if bundleSecretFileNames[base] || ext == ".pem" || ext == ".key" {
+		return nil, []BundleOmission{{Path: rel, Reason: "credential file"}}, false
+	}
+	if ext == ".yaml" || ext == ".yml" {
+		var doc yaml.Node
+		if err := yaml.Unmarshal(data, &doc); err == nil {
+			omitted = scrubYAMLSecrets(&doc, "", rel)
+			if len(omitted) == 0 {
+				return data, nil, true
+			}
+			var buf bytes.Buffer
+			enc := yaml.NewEncoder(&buf)
+			enc.SetIndent(2)
+			if err := enc.Encode(&doc); err != nil || enc.Close() != nil {
+				return nil, []BundleOmission{{Path: rel, Reason: "holds secrets that couldn't be cut out"}}, false
+			}
+			return buf.Bytes(), omitted, true
+		}
+		// Unparseable YAML gets the whole-file check below.
+	}
+	if bundleSecretValue.Match(data) {
+		return nil, []BundleOmission{{Path: rel, Reason: "contains what looks like an API key or private key"}}, false
+	}
+	return data, nil, true
+}
+
+// scrubYAMLSecrets removes secret scalar values from the mappings under n and
+// returns what it removed. prefix is n's dotted key.
+func scrubYAMLSecrets(n *yaml.Node, prefix, rel string) []BundleOmission {
+	var omitted []BundleOmission
+	switch n.Kind {
+	case yaml.DocumentNode, yaml.SequenceNode:
+		for _, c := range n.Content {
+			omitted = append(omitted, scrubYAMLSecrets(c, prefix, rel)...)
+		}
+	case yaml.MappingNode:
+		kept := n.Content[:0]
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			k, v := n.Content[i], n.Content[i+1]
+			key := k.Value
+			if prefix != "" {
+				key = prefix + "." + k.Value
+			}
+			if v.Kind == yaml.ScalarNode && isSecretYAMLValue(k.Value, v.Value) {
+				omitted = append(omitted, BundleOmission{Path: rel, Key: key, Reason: "secret value"})
+				continue
+			}
+			omitted = append(omitted, scrubYAMLSecrets(v, key, rel)...)
+			kept = append(kept, k, v)
+		}
+		n.Content = kept
+	}
+	return omitted
+}
+
+func isSecretYAMLValue(key, value string) bool {
+	if value == "" || bundleEnvRef.MatchString(value) {
+		return false
+	}
+	if bundleSecretValue.MatchString(value) {
+		return true
+	}
+	words := strings.FieldsFunc(strings.ToLower(key), func(r rune) bool {
+		return (r < 'a' || r > 'z') && (r < '0' || r > '9')
+	})
+	if len(words) == 0 {
+		return false
+	}
+	for _, w := range words {
+		if w == bundleSecretKeyWord {
+			return true
+		}
+	}
+	last := words[len(words)-1]
+	for _, s := range bundleSecretKeyWords {
+		if strings.HasSuffix(last, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// ImportBundle restores a bundle written by ExportBundle into layout's data
+// dir. Every entry is read and checked before anything is written: only the
+// paths an export produces are accepted, and a file that already exists with
+// different content is a conflict unless overwrite is set. Files the bundle
+// doesn't mention are left alone.
+func ImportBundle(layout Layout, r io.Reader, overwrite bool) (*BundleImport, error) {
+	type entry struct {
+		rel  string
+		data []byte
+		perm fs.FileMode
+	}
+	var entries []entry
+	total := int64(0)
+	sawManifest := false
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, yoerrors.NewUsageError("not a yoloai config bundle: %v", err)
+		}
+		if !sawManifest {
+			if hdr.Name != BundleManifestName {
+				return nil, yoerrors.NewUsageError("not a yoloai config bundle: it doesn't start with %s", BundleManifestName)
+			}
+			if err := checkBundleManifest(tr); err != nil {
+				return nil, err
+			}
+			sawManifest = true
+			continue
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			return nil, yoerrors.NewUsageError("bundle entry %s is not a regular file", hdr.Name)
+		}
+		if !validBundlePath(hdr.Name) {
+			return nil, yoerrors.NewUsageError("bundle entry %s is outside the config a bundle carries", hdr.Name)
+		}
+		total += hdr.Size
+		if hdr.Size > bundleMaxFileBytes || total > bundleMaxTotalBytes {
+			return nil, yoerrors.NewUsageError("bundle entry %s is too large", hdr.Name)
+		}
+		data, err := io.ReadAll(io.LimitReader(tr, bundleMaxFileBytes+1))
+		if err != nil {
+			return nil, fmt.Errorf("read bundle entry %s: %w", hdr.Name, err)
+		}
+		entries = append(entries, entry{rel: hdr.Name, data: data, perm: fs.FileMode(hdr.Mode).Perm() & 0o755}) //nolint:gosec // G115: tar modes fit
+	}
+	if !sawManifest {
+		return nil, yoerrors.NewUsageError("not a yoloai config bundle: it is empty")
+	}
+
+	result := &BundleImport{}
+	var conflicts []string
+	var write []entry
+	for _, e := range entries {
+		dest := filepath.Join(layout.DataDir, filepath.FromSlash(e.rel))
+		existing, err := os.ReadFile(dest) //nolint:gosec // G304: validated bundle path under the data dir
+		switch {
+		case err == nil && bytes.Equal(existing, e.data):
+			result.Unchanged = append(result.Unchanged, e.rel)
+			continue
+		case err == nil && !overwrite:
+			conflicts = append(conflicts, e.rel)
+		case err != nil && !errors.Is(err, fs.ErrNotExist):
+			return nil, err
+		}
+		write = append(write, e)
+	}
+	if len(conflicts) > 0 {
+		return nil, yoerrors.NewUsageError("these files already exist with different content: %s; re-run with --force to replace them", strings.Join(conflicts, ", "))
+	}
+	for _, e := range write {
+		dest := filepath.Join(layout.DataDir, filepath.FromSlash(e.rel))
+		if err := fileutil.MkdirAll(filepath.Dir(dest), 0o750); err != nil {
+			return result, err
+		}
+		if err := fileutil.AtomicWriteFile(dest, e.data, e.perm); err != nil {
+			return result, fmt.Errorf("restore %s: %w", e.rel, err)
+		}
+		result.Written = append(result.Written, e.rel)
+	}
+	return result, nil
+}
+
+func checkBundleManifest(r io.Reader) error {
+	var m bundleManifest
+	if err := json.NewDecoder(io.LimitReader(r, 1<<16)).Decode(&m); err != nil {
+		return yoerrors.NewUsageError("not a yoloai config bundle: unreadable %s: %v", BundleManifestName, err)
+	}
+	if m.Format != bundleFormat {
+		return yoerrors.NewUsageError("config bundle format %d is not supported (this yoloai reads format %d)", m.Format, bundleFormat)
+	}
+	if m.SchemaVersion > LibrarySchemaVersion {
+		return yoerrors.NewUsageError("the config bundle comes from a newer yoloai (schema %d, this one is %d); upgrade yoloai first", m.SchemaVersion, LibrarySchemaVersion)
+	}
+	return nil
+}
+
+// validBundlePath accepts exactly the paths ExportBundle writes: config.yaml,
+// and files under defaults/, profiles/ and agents/. A name that isn't already
+// clean (holding "..", ".", or doubled slashes) is refused outright.
+func validBundlePath(name string) bool {
+	if name != path.Clean(name) || path.IsAbs(name) || strings.Contains(name, "\\") {
+		return false
+	}
+	if name == "config.yaml" {
+		return true
+	}
+	for _, dir := range []string{"defaults/", "profiles/", "agents/"} {
+		if rest, ok := strings.CutPrefix(name, dir); ok && rest != "" {
+			return true
+		}
+	}
+	return false
+}
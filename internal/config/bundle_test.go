@@ -0,0 +1,143 @@
+package config
+
+// ABOUTME: Tests for config bundles: secrets left out of an export, a round
+// ABOUTME: trip through import, and import's path and conflict checks.
+
+import (
+	"archive/tar"
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kstenerud/yoloai/yoerrors"
+)
+
+func writeLayoutFile(t *testing.T, layout Layout, rel, content string) {
+	t.Helper()
+	p := filepath.Join(layout.DataDir, rel)
+	require.NoError(t, os.MkdirAll(filepath.Dir(p), 0o750))
+	require.NoError(t, os.WriteFile(p, []byte(content), 0o600))
+}
+
+func readLayoutFile(t *testing.T, layout Layout, rel string) string {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join(layout.DataDir, rel))
+	require.NoError(t, err)
+	return string(data)
+}
+
+func TestBundle_RoundTripLeavesSecretsOut(t *testing.T) {
+	src := NewLayout(filepath.Join(t.TempDir(), ".yoloai"))
+	writeLayoutFile(t, src, "config.yaml", "tmux_conf: host\ngithub:\n  token_env: GH_RO_TOKEN\n")
+	writeLayoutFile(t, src, "defaults/config.yaml", "agent: codex\nenv:\n  ANTHROPIC_API_KEY: sk-ant-abc\n  OPENAI_API_KEY: ${OPENAI_API_KEY}\n  GIT_AUTHOR_NAME: Ann\n  SOME_URL: ghp_tokenlookingvalue\n")
+	writeLayoutFile(t, src, "profiles/go/config.yaml", "extends: base\n")
+	writeLayoutFile(t, src, "profiles/go/Dockerfile", "FROM yoloai-base\n")
+	writeLayoutFile(t, src, "profiles/go/.npmrc", "//registry/:_authToken=x\n")
+	writeLayoutFile(t, src, "profiles/go/.last-build-checksum", "abc\n")
+	writeLayoutFile(t, src, "agents/mine.yaml", "name: mine\n")
+	writeLayoutFile(t, src, "sandboxes/box/prompt.txt", "not config\n")
+
+	var buf bytes.Buffer
+	exp, err := ExportBundle(src, &buf)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"agents/mine.yaml", "config.yaml", "defaults/config.yaml", "profiles/go/Dockerfile", "profiles/go/config.yaml"}, exp.Files)
+	assert.ElementsMatch(t, []BundleOmission{
+		{Path: "defaults/config.yaml", Key: "env.ANTHROPIC_API_KEY", Reason: "secret value"},
+		{Path: "defaults/config.yaml", Key: "env.SOME_URL", Reason: "secret value"},
+		{Path: "profiles/go/.npmrc", Reason: "credential file"},
+	}, exp.Omitted)
+	assert.NotContains(t, buf.String(), "sk-ant-abc")
+
+	dst := NewLayout(filepath.Join(t.TempDir(), ".yoloai"))
+	imp, err := ImportBundle(dst, &buf, false)
+	require.NoError(t, err)
+	assert.Len(t, imp.Written, 5)
+	assert.Equal(t, "tmux_conf: host\ngithub:\n  token_env: GH_RO_TOKEN\n", readLayoutFile(t, dst, "config.yaml"), "files without secrets travel byte for byte")
+	defaults := readLayoutFile(t, dst, "defaults/config.yaml")
+	assert.Contains(t, defaults, "OPENAI_API_KEY: ${OPENAI_API_KEY}", "a reference to a host variable is not a secret")
+	assert.Contains(t, defaults, "GIT_AUTHOR_NAME: Ann")
+	assert.NotContains(t, defaults, "ANTHROPIC_API_KEY")
+	assert.Equal(t, "FROM yoloai-base\n", readLayoutFile(t, dst, "profiles/go/Dockerfile"))
+	assert.NoFileExists(t, filepath.Join(dst.DataDir, "profiles/go/.npmrc"))
+}
+
+func TestBundle_LeavesWebhooksOut(t *testing.T) {
+	src := NewLayout(filepath.Join(t.TempDir(), ".yoloai"))
+	writeLayoutFile(t, src, "config.yaml", "notifications:\n  desktop: true\n"+
+		"  webhook_url: https://example.com/hook/abc123\n"+
+		"  slack_webhook: https://hooks.slack.com/services/T0/B0/xyz\n"+
+		"env:\n  CHAT_URL: https://hooks.slack.com/services/T1/B1/qrs\n")
+
+	var buf bytes.Buffer
+	exp, err := ExportBundle(src, &buf)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []BundleOmission{
+		{Path: "config.yaml", Key: "notifications.webhook_url", Reason: "secret value"},
+		{Path: "config.yaml", Key: "notifications.slack_webhook", Reason: "secret value"},
+		{Path: "config.yaml", Key: "env.CHAT_URL", Reason: "secret value"},
+	}, exp.Omitted)
+	assert.NotContains(t, buf.String(), "abc123")
+	assert.NotContains(t, buf.String(), "hooks.slack.com")
+	assert.Contains(t, buf.String(), "desktop: true")
+}
+
+func TestImportBundle_Conflicts(t *testing.T) {
+	src := NewLayout(filepath.Join(t.TempDir(), ".yoloai"))
+	writeLayoutFile(t, src, "config.yaml", "tmux_conf: host\n")
+	writeLayoutFile(t, src, "agents/mine.yaml", "name: mine\n")
+	var bundle bytes.Buffer
+	_, err := ExportBundle(src, &bundle)
+	require.NoError(t, err)
+
+	dst := NewLayout(filepath.Join(t.TempDir(), ".yoloai"))
+	writeLayoutFile(t, dst, "config.yaml", "tmux_conf: default+host\n")
+	var usage *yoerrors.UsageError
+	_, err = ImportBundle(dst, bytes.NewReader(bundle.Bytes()), false)
+	require.ErrorAs(t, err, &usage)
+	assert.Contains(t, err.Error(), "config.yaml")
+	assert.NoFileExists(t, filepath.Join(dst.DataDir, "agents/mine.yaml"), "a conflict stops the import before anything is written")
+
+	imp, err := ImportBundle(dst, bytes.NewReader(bundle.Bytes()), true)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"config.yaml", "agents/mine.yaml"}, imp.Written)
+	assert.Equal(t, "tmux_conf: host\n", readLayoutFile(t, dst, "config.yaml"))
+
+	imp, err = ImportBundle(dst, bytes.NewReader(bundle.Bytes()), false)
+	require.NoError(t, err)
+	assert.Empty(t, imp.Written)
+	assert.Len(t, imp.Unchanged, 2, "re-importing the same bundle is a no-op")
+}
+
+func TestImportBundle_RejectsForeignArchives(t *testing.T) {
+	dst := NewLayout(filepath.Join(t.TempDir(), ".yoloai"))
+	tarOf := func(names ...string) *bytes.Buffer {
+		var buf bytes.Buffer
+		tw := tar.NewWriter(&buf)
+		for _, n := range names {
+			data := []byte("x")
+			if n == BundleManifestName {
+				data = []byte(`{"format":1,"schema_version":1}`)
+			}
+			require.NoError(t, writeBundleEntry(tw, n, data, 0o644))
+		}
+		require.NoError(t, tw.Close())
+		return &buf
+	}
+
+	var usage *yoerrors.UsageError
+	for _, names := range [][]string{
+		{"config.yaml"},
+		{BundleManifestName, "../escape"},
+		{BundleManifestName, "profiles/../sandboxes/x/prompt.txt"},
+		{BundleManifestName, "sandboxes/x/environment.json"},
+		{BundleManifestName, "/etc/passwd"},
+	} {
+		_, err := ImportBundle(dst, tarOf(names...), false)
+		assert.ErrorAs(t, err, &usage, "%v", names)
+	}
+	assert.NoDirExists(t, dst.DataDir)
+}
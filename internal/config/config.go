@@ -7,9 +7,10 @@ import (
 	"fmt"
 	"maps"
 	"os"
-	"sort"
+	"regexp"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/kstenerud/yoloai/yoerrors"
 	"gopkg.in/yaml.v3"
@@ -28,30 +29,48 @@ func (c *AgentFilesConfig) IsStringForm() bool {
 }
 
 // YoloaiConfig holds the subset of config.yaml fields that the Go code reads.
+// The yaml tags name each field's top-level key; the handlers below do the
+// parsing, and the JSON Schema is generated from the tags (jsonschema.go).
 type YoloaiConfig struct {
 	OS                 string            `yaml:"os"`                   // os — guest OS: linux, mac
 	ContainerBackend   string            `yaml:"container_backend"`    // container_backend — runtime backend: docker, podman, containerd
-	TartImage          string            `yaml:"tart_image"`           // tart.image — custom base VM image for tart backend
+	BackendFallback    []string          `yaml:"backend_fallback"`     // backend_fallback — backends new/run try, in order, when the resolved one is unavailable
+	TartImage          string            `yaml:"tart"`                 // tart.image — custom base VM image for tart backend
 	Agent              string            `yaml:"agent"`                // agent
 	Model              string            `yaml:"model"`                // model
 	Env                map[string]string `yaml:"env"`                  // env — environment variables passed to container
 	Resources          *ResourceLimits   `yaml:"resources"`            // resources — container resource limits
 	Network            *NetworkConfig    `yaml:"network"`              // network — network isolation settings
+	Guard              *GuardConfig      `yaml:"guard"`                // guard — log or block destructive shell commands run in the sandbox
 	Mounts             []string          `yaml:"mounts"`               // mounts — extra bind mounts (host:container[:ro])
 	Ports              []string          `yaml:"ports"`                // ports — default port mappings (host:container)
 	AgentArgs          map[string]string `yaml:"agent_args"`           // agent_args — per-agent default CLI args
-	AgentFiles         *AgentFilesConfig `yaml:"-"`                    // agent_files — extra files to seed into agent-state
+	AgentFiles         *AgentFilesConfig `yaml:"agent_files"`          // agent_files — extra files to seed into agent-state
 	CapAdd             []string          `yaml:"cap_add"`              // cap_add — Linux capabilities to add (Docker only)
 	Devices            []string          `yaml:"devices"`              // devices — host devices to expose (Docker only)
 	Setup              []string          `yaml:"setup"`                // setup — commands to run before agent launch (Docker only)
 	AutoCommitInterval int               `yaml:"auto_commit_interval"` // auto_commit_interval — seconds between auto-commits in :copy dirs; 0 = disabled
 	Isolation          string            `yaml:"isolation"`            // isolation — sandbox isolation mode: container, container-enhanced, vm, vm-enhanced
+	ProvenanceHeaders  *bool             `yaml:"provenance_headers"`   // provenance_headers — mark agent-created files with a header comment on apply; nil = unset
+	Timezone           string            `yaml:"timezone"`             // timezone — TZ inside the sandbox (e.g. Europe/Berlin, UTC); "" = the host's
+	Locale             string            `yaml:"locale"`               // locale — LANG inside the sandbox (e.g. de_DE.UTF-8); "" = the host's
+	TTL                string            `yaml:"ttl"`                  // ttl — lifetime after which `yoloai gc` destroys the sandbox (e.g. 4h, 7d); "" = never
+	FakeTime           string            `yaml:"faketime"`             // faketime — libfaketime spec for the sandbox's clock (e.g. "-3d", "2024-02-29 12:00:00"); "" = real time
+	PreLaunch          string            `yaml:"pre_launch"`           // pre_launch — bash snippet run before tmux and the agent start; what it exports reaches the agent
 }
 
-// ResourceLimits holds container resource constraints (CPU, memory).
+// ResourceLimits holds container resource constraints (CPU, memory, disk,
+// agent state).
 type ResourceLimits struct {
 	CPUs   string `yaml:"cpus" json:"cpus,omitempty"`
 	Memory string `yaml:"memory" json:"memory,omitempty"`
+	// Disk caps the sandbox directory (work copies, agent state, logs): create
+	// and start refuse a sandbox over it. Docker also applies it as the
+	// container's writable-layer quota where the storage driver supports one.
+	Disk string `yaml:"disk" json:"disk,omitempty"`
+	// AgentState caps the agent's state directory (agent-runtime/): start
+	// trims the agent's prunable state, oldest first, down to it.
+	AgentState string `yaml:"agent_state" json:"agent_state,omitempty"`
 }
 
 // NetworkConfig holds network isolation settings.
@@ -60,11 +79,87 @@ type NetworkConfig struct {
 	Allow    []string `yaml:"allow" json:"allow,omitempty"`
 }
 
+// GuardConfig holds the destructive-command guard settings.
+type GuardConfig struct {
+	Mode     string   `yaml:"mode" json:"mode,omitempty"`         // off (default), log, block
+	Commands []string `yaml:"commands" json:"commands,omitempty"` // rules like "rm -rf"; empty = DefaultGuardCommands
+}
+
+// DefaultGuardCommands are the rules a guard uses when none are configured.
+// A rule's first word is the command; the remaining words must all appear
+// in its arguments (short flags may be clustered, e.g. "rm -fr").
+var DefaultGuardCommands = []string{
+	"rm -rf",
+	"git push",
+	"git reset --hard",
+	"git clean -f",
+	"git branch -D",
+}
+
 // GlobalConfig holds user preferences from ~/.yoloai/config.yaml.
 // These settings apply to all sandboxes regardless of profile.
 type GlobalConfig struct {
 	TmuxConf     string            `yaml:"tmux_conf"`
 	ModelAliases map[string]string `yaml:"model_aliases"`
+	GitHub       *GitHubConfig     `yaml:"github"` // github — read-only GitHub API token for sandboxes; nil = none
+	// RetentionDays is how long the prompts, logs and transcripts of sandboxes
+	// in the trash are kept before `yoloai gc` (or `yoloai scrub`) removes them;
+	// 0 = forever.
+	RetentionDays int `yaml:"retention_days"`
+	// MaxSandboxesPerWorkdir caps how many sandboxes may use one workdir at
+	// once: creating one more is refused; 0 = no limit.
+	MaxSandboxesPerWorkdir int `yaml:"max_sandboxes_per_workdir"`
+	// OrgConfigURL is where `yoloai config pull` fetches the org-wide config
+	// layered beneath the user's own; "" = none.
+	OrgConfigURL string `yaml:"org_config_url"`
+	// Notifications says how the daemon tells the user what their sandboxes
+	// are doing; nil = it doesn't.
+	Notifications *NotificationsConfig `yaml:"notifications"`
+}
+
+// NotificationsConfig says where `yoloai daemon` reports that a sandbox was
+// created, its agent needs input, or its agent has exited (finished or failed).
+type NotificationsConfig struct {
+	Desktop      bool   `yaml:"desktop"`       // a desktop notification (osascript / notify-send)
+	WebhookURL   string `yaml:"webhook_url"`   // POST a JSON event here; "" = none
+	SlackWebhook string `yaml:"slack_webhook"` // Slack incoming-webhook URL; "" = none
+}
+
+// Enabled reports whether any notification is configured.
+func (n *NotificationsConfig) Enabled() bool {
+	return n != nil && (n.Desktop || n.WebhookURL != "" || n.SlackWebhook != "")
+}
+
+// GitHubConfig says where a sandbox's read-only GitHub token comes from:
+// either a GitHub App installation that yoloai mints a read-only token from
+// at each launch, or a host environment variable holding a token the user
+// scoped read-only themselves.
+type GitHubConfig struct {
+	AppID          string `yaml:"app_id"`
+	InstallationID string `yaml:"installation_id"`
+	PrivateKey     string `yaml:"private_key"` // path to the app's PEM private key; ~ expanded
+	TokenEnv       string `yaml:"token_env"`   // host env var holding a read-only token
+	APIURL         string `yaml:"api_url"`     // "" = https://api.github.com (set for GitHub Enterprise)
+}
+
+// UsesApp reports whether the token is minted from a GitHub App.
+func (g *GitHubConfig) UsesApp() bool {
+	return g.AppID != "" || g.InstallationID != "" || g.PrivateKey != ""
+}
+
+// Validate checks that exactly one token source is fully configured. It is
+// checked where the token is needed, not at load, so setting the keys one at
+// a time with `config set` doesn't break every command in between.
+func (g *GitHubConfig) Validate() error {
+	switch {
+	case g.UsesApp() && g.TokenEnv != "":
+		return yoerrors.NewUsageError("github: set either app_id/installation_id/private_key or token_env, not both")
+	case g.UsesApp() && (g.AppID == "" || g.InstallationID == "" || g.PrivateKey == ""):
+		return yoerrors.NewUsageError("github: a GitHub App needs all of app_id, installation_id and private_key")
+	case !g.UsesApp() && g.TokenEnv == "":
+		return yoerrors.NewUsageError("github: set app_id/installation_id/private_key or token_env")
+	}
+	return nil
 }
 
 // knownSetting defines a config key with its default value.
@@ -83,9 +178,18 @@ var knownSettings = []knownSetting{
 	{"model", ""},
 	{"resources.cpus", ""},
 	{"resources.memory", ""},
+	{"resources.disk", ""},
+	{"resources.agent_state", ""},
 	{"network.isolated", "false"},
+	{"guard.mode", "off"},
 	{"auto_commit_interval", "0"},
 	{"isolation", ""},
+	{"provenance_headers", "false"},
+	{"timezone", ""},
+	{"locale", ""},
+	{"ttl", ""},
+	{"faketime", ""},
+	{"pre_launch", ""},
 }
 
 // ValidateIsolationMode returns an error if mode is not a known isolation mode.
@@ -99,6 +203,17 @@ func ValidateIsolationMode(mode string) error {
 	}
 }
 
+// ValidateGuardMode returns an error if mode is not a known guard mode.
+// Empty string is allowed (means "off").
+func ValidateGuardMode(mode string) error {
+	switch mode {
+	case "", "off", "log", "block":
+		return nil
+	default:
+		return yoerrors.NewUsageError("unknown guard mode %q: valid values are off, log, block", mode)
+	}
+}
+
 // knownCollectionSetting defines a non-scalar config key (map or list)
 // with its default YAML node kind.
 type knownCollectionSetting struct {
@@ -113,7 +228,9 @@ var knownCollectionSettings = []knownCollectionSetting{
 	{"env", yaml.MappingNode},
 	{"mounts", yaml.SequenceNode},
 	{"ports", yaml.SequenceNode},
+	{"backend_fallback", yaml.SequenceNode},
 	{"network.allow", yaml.SequenceNode},
+	{"guard.commands", yaml.SequenceNode},
 	{"cap_add", yaml.SequenceNode},
 	{"devices", yaml.SequenceNode},
 	{"setup", yaml.SequenceNode},
@@ -125,6 +242,17 @@ var knownCollectionSettings = []knownCollectionSetting{
 // with no imperative first-run write, so no setup-ceremony state is needed.
 var globalKnownSettings = []knownSetting{
 	{"tmux_conf", "default+host"},
+	{"github.app_id", ""},
+	{"github.installation_id", ""},
+	{"github.private_key", ""},
+	{"github.token_env", ""},
+	{"github.api_url", ""},
+	{"retention_days", "0"},
+	{"max_sandboxes_per_workdir", "0"},
+	{"org_config_url", ""},
+	{"notifications.desktop", "false"},
+	{"notifications.webhook_url", ""},
+	{"notifications.slack_webhook", ""},
 }
 
 // globalKnownCollectionSettings lists non-scalar config keys belonging to global config.
@@ -132,15 +260,6 @@ var globalKnownCollectionSettings = []knownCollectionSetting{
 	{"model_aliases", yaml.MappingNode},
 }
 
-// knownDefaultsKeys: valid top-level keys in defaults/config.yaml.
-var knownDefaultsKeys = map[string]bool{
-	"os": true, "agent": true, "model": true, "container_backend": true,
-	"isolation": true, "tart": true, "network": true, "agent_files": true,
-	"mounts": true, "ports": true, "resources": true, "agent_args": true,
-	"env": true, "auto_commit_interval": true, "cap_add": true,
-	"devices": true, "setup": true,
-}
-
 // yoloaiConfigHandler is a function that handles a single YAML key in a YoloaiConfig.
 type yoloaiConfigHandler func(cfg *YoloaiConfig, val *yaml.Node, env map[string]string) error
 
@@ -150,6 +269,7 @@ var yoloaiConfigHandlers = map[string]yoloaiConfigHandler{
 	"model":                yoloaiScalarHandler(func(c *YoloaiConfig) *string { return &c.Model }),
 	"os":                   yoloaiScalarHandler(func(c *YoloaiConfig) *string { return &c.OS }),
 	"container_backend":    yoloaiScalarHandler(func(c *YoloaiConfig) *string { return &c.ContainerBackend }),
+	"backend_fallback":     yoloaiRawSeqHandler(func(c *YoloaiConfig) *[]string { return &c.BackendFallback }),
 	"mounts":               yoloaiExpandedSeqHandler(func(c *YoloaiConfig) *[]string { return &c.Mounts }, "mounts[]"),
 	"ports":                yoloaiRawSeqHandler(func(c *YoloaiConfig) *[]string { return &c.Ports }),
 	"cap_add":              yoloaiExpandedSeqHandler(func(c *YoloaiConfig) *[]string { return &c.CapAdd }, "cap_add[]"),
@@ -160,9 +280,16 @@ var yoloaiConfigHandlers = map[string]yoloaiConfigHandler{
 	"tart":                 handleYoloaiTart,
 	"resources":            handleYoloaiResources,
 	"network":              handleYoloaiNetwork,
+	"guard":                handleYoloaiGuard,
 	"agent_files":          handleYoloaiAgentFiles,
 	"auto_commit_interval": handleYoloaiAutoCommitInterval,
 	"isolation":            handleYoloaiIsolation,
+	"provenance_headers":   handleYoloaiProvenanceHeaders,
+	"timezone":             yoloaiScalarHandler(func(c *YoloaiConfig) *string { return &c.Timezone }),
+	"locale":               yoloaiScalarHandler(func(c *YoloaiConfig) *string { return &c.Locale }),
+	"ttl":                  handleYoloaiTTL,
+	"faketime":             handleYoloaiFakeTime,
+	"pre_launch":           handleYoloaiPreLaunch,
 }
 
 // yoloaiScalarHandler returns a handler that expands env vars and stores the result in the field pointed to by ptr.
@@ -260,6 +387,10 @@ func handleYoloaiResources(cfg *YoloaiConfig, val *yaml.Node, env map[string]str
 			cfg.Resources.CPUs = subExpanded
 		case "memory":
 			cfg.Resources.Memory = subExpanded
+		case "disk":
+			cfg.Resources.Disk = subExpanded
+		case "agent_state":
+			cfg.Resources.AgentState = subExpanded
 		}
 	}
 	return nil
@@ -286,6 +417,34 @@ func handleYoloaiNetwork(cfg *YoloaiConfig, val *yaml.Node, _ map[string]string)
 	return nil
 }
 
+func handleYoloaiGuard(cfg *YoloaiConfig, val *yaml.Node, _ map[string]string) error {
+	if val.Kind != yaml.MappingNode {
+		return nil
+	}
+	cfg.Guard = &GuardConfig{}
+	for k := 0; k < len(val.Content)-1; k += 2 {
+		subKey := val.Content[k].Value
+		switch subKey {
+		case "mode":
+			if err := ValidateGuardMode(val.Content[k+1].Value); err != nil {
+				return err
+			}
+			cfg.Guard.Mode = val.Content[k+1].Value
+		case "commands":
+			if val.Content[k+1].Kind == yaml.SequenceNode {
+				for _, item := range val.Content[k+1].Content {
+					words := strings.Fields(item.Value)
+					if len(words) == 0 || strings.Contains(words[0], "/") {
+						return yoerrors.NewUsageError("guard.commands: rule %q must start with a command name (no path)", item.Value)
+					}
+					cfg.Guard.Commands = append(cfg.Guard.Commands, item.Value)
+				}
+			}
+		}
+	}
+	return nil
+}
+
 func handleYoloaiAgentFiles(cfg *YoloaiConfig, val *yaml.Node, _ map[string]string) error {
 	af, err := parseAgentFilesNode(val)
 	if err != nil {
@@ -316,12 +475,85 @@ func handleYoloaiIsolation(cfg *YoloaiConfig, val *yaml.Node, env map[string]str
 	return nil
 }
 
+func handleYoloaiProvenanceHeaders(cfg *YoloaiConfig, val *yaml.Node, _ map[string]string) error {
+	b, err := strconv.ParseBool(val.Value)
+	if err != nil {
+		return fmt.Errorf("provenance_headers: %w", err)
+	}
+	cfg.ProvenanceHeaders = &b
+	return nil
+}
+
+func handleYoloaiTTL(cfg *YoloaiConfig, val *yaml.Node, _ map[string]string) error {
+	if _, err := ParseTTL(val.Value); err != nil {
+		return err
+	}
+	cfg.TTL = val.Value
+	return nil
+}
+
+// ParseTTL parses a sandbox lifetime: a Go duration ("90m", "4h") or a whole
+// number of days ("7d"). "" and "0" mean no expiry and return 0.
+func ParseTTL(s string) (time.Duration, error) {
+	if s == "" {
+		return 0, nil
+	}
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil || n < 0 {
+			return 0, yoerrors.NewUsageError("invalid ttl %q: use a duration like 4h or 90m, or days like 7d", s)
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil || d < 0 {
+		return 0, yoerrors.NewUsageError("invalid ttl %q: use a duration like 4h or 90m, or days like 7d", s)
+	}
+	return d, nil
+}
+
+func handleYoloaiFakeTime(cfg *YoloaiConfig, val *yaml.Node, _ map[string]string) error {
+	if err := ValidateFakeTime(val.Value); err != nil {
+		return err
+	}
+	cfg.FakeTime = val.Value
+	return nil
+}
+
+// fakeTimeRE matches the libfaketime FAKETIME forms yoloai accepts: an offset
+// ("+2h", "-30d"), an absolute time that stays frozen ("2024-02-29 12:00:00"),
+// or one that starts there and runs on ("@2024-02-29 12:00:00"). Either may
+// end in a speed factor (" x10"). "none" disables a configured faketime.
+var fakeTimeRE = regexp.MustCompile(`^(none|[+-]\d+(\.\d+)?[smhdy]?|@?\d{4}-\d{2}-\d{2} \d{2}:\d{2}:\d{2})( x\d+(\.\d+)?)?$`)
+
+// ValidateFakeTime returns a usage error unless s is "" or a faketime spec
+// fakeTimeRE accepts.
+func ValidateFakeTime(s string) error {
+	if s == "" || fakeTimeRE.MatchString(s) {
+		return nil
+	}
+	return yoerrors.NewUsageError("invalid faketime %q: use an offset like -3d or +2h, a frozen time like \"2024-02-29 12:00:00\", or a start time like \"@2024-02-29 12:00:00\"", s)
+}
+
+// handleYoloaiPreLaunch stores pre_launch verbatim: it is shell source, so
+// ${VAR} belongs to the sandbox's shell, not to config expansion.
+func handleYoloaiPreLaunch(cfg *YoloaiConfig, val *yaml.Node, _ map[string]string) error {
+	if val.Kind != yaml.ScalarNode {
+		return yoerrors.NewUsageError("pre_launch must be a string (use a YAML block scalar for several lines)")
+	}
+	if strings.ContainsRune(val.Value, 0) {
+		return yoerrors.NewUsageError("pre_launch contains a NUL byte")
+	}
+	cfg.PreLaunch = val.Value
+	return nil
+}
+
 // parseConfigYAML parses a config YAML document into a YoloaiConfig.
-// source is used in error messages. knownKeys is the set of allowed top-level keys;
-// if nil, no unknown-key validation is performed.
+// source is used in error messages. schema, when non-nil, is checked first:
+// unknown keys and wrong types are errors (see parseYAMLRoot).
 // env is the curated interpolation map for ${VAR} expansion; nil means any ${VAR} errors as "not set".
-func parseConfigYAML(data []byte, source string, knownKeys map[string]bool, env map[string]string) (*YoloaiConfig, error) {
-	root, err := parseYAMLRoot(data, source, knownKeys)
+func parseConfigYAML(data []byte, source string, schema *Schema, env map[string]string) (*YoloaiConfig, error) {
+	root, err := parseYAMLRoot(data, source, schema)
 	if err != nil {
 		return nil, err
 	}
@@ -345,9 +577,10 @@ func parseConfigYAML(data []byte, source string, knownKeys map[string]bool, env
 }
 
 // parseYAMLRoot parses data into a yaml.Node and returns the root mapping node.
-// Returns nil if the document is empty or not a mapping. Validates unknown keys
-// against knownKeys when non-nil.
-func parseYAMLRoot(data []byte, source string, knownKeys map[string]bool) (*yaml.Node, error) {
+// Returns nil if the document is empty or not a mapping. When schema is
+// non-nil the document is checked against it, unknown keys included, and
+// every problem is reported with its line and column.
+func parseYAMLRoot(data []byte, source string, schema *Schema) (*yaml.Node, error) {
 	var doc yaml.Node
 	if err := yaml.Unmarshal(data, &doc); err != nil {
 		return nil, fmt.Errorf("parse %s: %w", source, err)
@@ -359,44 +592,33 @@ func parseYAMLRoot(data []byte, source string, knownKeys map[string]bool) (*yaml
 	if root.Kind != yaml.MappingNode {
 		return nil, nil
 	}
-	if knownKeys != nil {
-		if err := validateKnownKeys(root, source, knownKeys); err != nil {
+	if schema != nil {
+		if err := validateConfigNode(root, schema, source, true); err != nil {
 			return nil, err
 		}
 	}
 	return root, nil
 }
 
-// validateKnownKeys checks that all top-level keys in root are present in knownKeys.
-func validateKnownKeys(root *yaml.Node, source string, knownKeys map[string]bool) error {
-	var unknown []string
-	for i := 0; i < len(root.Content)-1; i += 2 {
-		key := root.Content[i].Value
-		if !knownKeys[key] {
-			unknown = append(unknown, key)
-		}
-	}
-	if len(unknown) > 0 {
-		sort.Strings(unknown)
-		return fmt.Errorf("%s: unknown config field(s): %s", source, strings.Join(unknown, ", "))
-	}
-	return nil
-}
-
 // LoadBakedInDefaults parses the embedded defaults YAML into a YoloaiConfig.
 // Returns a fully-populated config with every field at its baked-in default.
 func LoadBakedInDefaults() (*YoloaiConfig, error) {
-	return parseConfigYAML([]byte(DefaultConfigYAML), "<baked-in>", knownDefaultsKeys, nil)
+	return parseConfigYAML([]byte(DefaultConfigYAML), "<baked-in>", defaultsSchema, nil)
 }
 
 // LoadDefaultsConfig loads the effective config for the no-profile path:
-// baked-in defaults merged with DataDir/defaults/config.yaml.
+// baked-in defaults merged with the system layers, then with
+// DataDir/defaults/config.yaml.
 // Used by sandbox.Create() when no --profile is given.
 func LoadDefaultsConfig(layout Layout) (*YoloaiConfig, error) {
 	base, err := LoadBakedInDefaults()
 	if err != nil {
 		return nil, err
 	}
+	base, err = applySystemConfigLayers(layout, base)
+	if err != nil {
+		return nil, err
+	}
 
 	cfgPath := layout.DefaultsConfigPath()
 	data, err := os.ReadFile(cfgPath) //nolint:gosec // G304: path is DataDir/defaults/config.yaml
@@ -407,7 +629,7 @@ func LoadDefaultsConfig(layout Layout) (*YoloaiConfig, error) {
 		return nil, fmt.Errorf("read defaults/config.yaml: %w", err)
 	}
 
-	override, err := parseConfigYAML(data, cfgPath, knownDefaultsKeys, layout.Env().EnvForConfigInterpolation())
+	override, err := parseConfigYAML(data, cfgPath, defaultsSchema, layout.Env().EnvForConfigInterpolation())
 	if err != nil {
 		return nil, err
 	}
@@ -453,10 +675,14 @@ func mergeResources(base, override *ResourceLimits) *ResourceLimits {
 	if base != nil {
 		result.CPUs = base.CPUs
 		result.Memory = base.Memory
+		result.Disk = base.Disk
+		result.AgentState = base.AgentState
 	}
 	if override != nil {
 		result.CPUs = mergeStringField(result.CPUs, override.CPUs)
 		result.Memory = mergeStringField(result.Memory, override.Memory)
+		result.Disk = mergeStringField(result.Disk, override.Disk)
+		result.AgentState = mergeStringField(result.AgentState, override.AgentState)
 	}
 	return result
 }
@@ -482,15 +708,39 @@ func mergeNetwork(base, override *NetworkConfig) *NetworkConfig {
 	return result
 }
 
+// mergeGuard merges two GuardConfig values: Mode is last non-empty wins,
+// Commands is additive. Returns nil if both are nil.
+func mergeGuard(base, override *GuardConfig) *GuardConfig {
+	if base == nil && override == nil {
+		return nil
+	}
+	result := &GuardConfig{}
+	if base != nil {
+		result.Mode = base.Mode
+		result.Commands = append(result.Commands, base.Commands...)
+	}
+	if override != nil {
+		result.Mode = mergeStringField(result.Mode, override.Mode)
+		result.Commands = append(result.Commands, override.Commands...)
+	}
+	if len(result.Commands) == 0 {
+		result.Commands = nil
+	}
+	return result
+}
+
 // mergeConfigs merges override into base, returning a new YoloaiConfig.
 // Merge semantics:
-//   - Scalars (OS, Agent, Model, ContainerBackend, TartImage, Isolation): non-empty overrides
+//   - Scalars (OS, Agent, Model, ContainerBackend, TartImage, Isolation, Timezone, Locale, TTL, FakeTime, PreLaunch): non-empty overrides
 //   - Maps (Env, AgentArgs): map merge, override wins on conflict
 //   - Lists (Mounts, Ports, CapAdd, Devices, Setup): additive
+//   - BackendFallback: replacement semantics (a non-empty order replaces), since it is an order
 //   - Resources: per-field override (non-empty override wins)
 //   - Network: Isolated overrides (last wins), Allow is additive
+//   - Guard: Mode overrides (non-empty wins), Commands is additive
 //   - AgentFiles: replacement semantics (non-nil replaces)
 //   - AutoCommitInterval: non-zero override wins
+//   - ProvenanceHeaders: non-nil override wins
 func mergeConfigs(base, override *YoloaiConfig) *YoloaiConfig {
 	agentFiles := base.AgentFiles
 	if override.AgentFiles != nil {
@@ -500,14 +750,29 @@ func mergeConfigs(base, override *YoloaiConfig) *YoloaiConfig {
 	if override.AutoCommitInterval > 0 {
 		autoCommit = override.AutoCommitInterval
 	}
+	provenance := base.ProvenanceHeaders
+	if override.ProvenanceHeaders != nil {
+		provenance = override.ProvenanceHeaders
+	}
+	fallback := base.BackendFallback
+	if len(override.BackendFallback) > 0 {
+		fallback = override.BackendFallback
+	}
 	return &YoloaiConfig{
 		OS:                 mergeStringField(base.OS, override.OS),
 		ContainerBackend:   mergeStringField(base.ContainerBackend, override.ContainerBackend),
+		BackendFallback:    fallback,
 		TartImage:          mergeStringField(base.TartImage, override.TartImage),
 		Agent:              mergeStringField(base.Agent, override.Agent),
 		Model:              mergeStringField(base.Model, override.Model),
 		Isolation:          mergeStringField(base.Isolation, override.Isolation),
+		Timezone:           mergeStringField(base.Timezone, override.Timezone),
+		Locale:             mergeStringField(base.Locale, override.Locale),
+		TTL:                mergeStringField(base.TTL, override.TTL),
+		FakeTime:           mergeStringField(base.FakeTime, override.FakeTime),
+		PreLaunch:          mergeStringField(base.PreLaunch, override.PreLaunch),
 		AutoCommitInterval: autoCommit,
+		ProvenanceHeaders:  provenance,
 		AgentFiles:         agentFiles,
 		Env:                mergeMapFields(base.Env, override.Env),
 		AgentArgs:          mergeMapFields(base.AgentArgs, override.AgentArgs),
@@ -518,30 +783,56 @@ func mergeConfigs(base, override *YoloaiConfig) *YoloaiConfig {
 		Setup:              mergeSlices(base.Setup, override.Setup),
 		Resources:          mergeResources(base.Resources, override.Resources),
 		Network:            mergeNetwork(base.Network, override.Network),
+		Guard:              mergeGuard(base.Guard, override.Guard),
 	}
 }
 
-// LoadConfig reads DataDir/defaults/config.yaml and extracts known fields.
+// LoadConfig reads DataDir/defaults/config.yaml and extracts known fields,
+// merged over the system layers beneath it.
 func LoadConfig(layout Layout) (*YoloaiConfig, error) {
-	configPath := layout.DefaultsConfigPath()
+	base, err := applySystemConfigLayers(layout, &YoloaiConfig{})
+	if err != nil {
+		return nil, err
+	}
 
+	configPath := layout.DefaultsConfigPath()
 	data, err := os.ReadFile(configPath) //nolint:gosec // G304: path is DataDir/defaults/config.yaml
 	if err != nil {
 		if os.IsNotExist(err) {
-			return &YoloaiConfig{}, nil
+			return base, nil
 		}
 		return nil, fmt.Errorf("read config.yaml: %w", err)
 	}
 
-	return parseConfigYAML(data, configPath, nil, layout.Env().EnvForConfigInterpolation())
+	cfg, err := parseConfigYAML(data, configPath, nil, layout.Env().EnvForConfigInterpolation())
+	if err != nil {
+		return nil, err
+	}
+	return mergeConfigs(base, cfg), nil
 }
 
-// LoadGlobalConfig reads DataDir/config.yaml and extracts global settings.
+// LoadGlobalConfig reads DataDir/config.yaml and extracts global settings,
+// over those of the system layers beneath it. A key set in a higher layer
+// replaces the lower one's, except model_aliases, which merge.
 func LoadGlobalConfig(layout Layout) (*GlobalConfig, error) {
-	configPath := layout.GlobalConfigPath()
-
 	cfg := &GlobalConfig{}
+	interpEnv := layout.Env().EnvForConfigInterpolation()
+
+	layers, err := systemConfigLayers(layout)
+	if err != nil {
+		return nil, err
+	}
+	for _, l := range layers {
+		root, err := parseYAMLRoot(l.data, l.path, nil)
+		if err != nil {
+			return nil, err
+		}
+		if err := applyGlobalConfigRoot(cfg, root, interpEnv); err != nil {
+			return nil, err
+		}
+	}
 
+	configPath := layout.GlobalConfigPath()
 	data, err := os.ReadFile(configPath) //nolint:gosec // G304: path is DataDir/config.yaml
 	if err != nil {
 		if os.IsNotExist(err) {
@@ -558,21 +849,29 @@ func LoadGlobalConfig(layout Layout) (*GlobalConfig, error) {
 	if doc.Kind != yaml.DocumentNode || len(doc.Content) == 0 {
 		return applyGlobalDefaults(cfg), nil
 	}
-	root := doc.Content[0]
-	if root.Kind != yaml.MappingNode {
-		return applyGlobalDefaults(cfg), nil
+	// Keys it doesn't know are still passed over here, as they always were;
+	// a known key with the wrong type is an error.
+	if err := validateConfigNode(doc.Content[0], globalSchema, configPath, false); err != nil {
+		return nil, err
 	}
+	if err := applyGlobalConfigRoot(cfg, doc.Content[0], interpEnv); err != nil {
+		return nil, err
+	}
+	return applyGlobalDefaults(cfg), nil
+}
 
-	interpEnv := layout.Env().EnvForConfigInterpolation()
+// applyGlobalConfigRoot applies each top-level key of one config file's root
+// mapping to cfg. A nil or non-mapping root sets nothing.
+func applyGlobalConfigRoot(cfg *GlobalConfig, root *yaml.Node, env map[string]string) error {
+	if root == nil || root.Kind != yaml.MappingNode {
+		return nil
+	}
 	for i := 0; i < len(root.Content)-1; i += 2 {
-		key := root.Content[i]
-		val := root.Content[i+1]
-		if err := applyGlobalConfigField(cfg, key.Value, val, interpEnv); err != nil {
-			return nil, err
+		if err := applyGlobalConfigField(cfg, root.Content[i].Value, root.Content[i+1], env); err != nil {
+			return err
 		}
 	}
-
-	return applyGlobalDefaults(cfg), nil
+	return nil
 }
 
 // applyGlobalDefaults fills any unset scalar global setting with its declared
@@ -605,11 +904,183 @@ func applyGlobalConfigField(cfg *GlobalConfig, key string, val *yaml.Node, env m
 		if err != nil {
 			return err
 		}
-		cfg.ModelAliases = aliases
+		cfg.ModelAliases = mergeMapFields(cfg.ModelAliases, aliases)
+	case "github":
+		gh, err := parseGitHubConfig(val, env)
+		if err != nil {
+			return err
+		}
+		cfg.GitHub = gh
+	case "retention_days":
+		expanded, err := expandEnvBraced(val.Value, env)
+		if err != nil {
+			return fmt.Errorf("retention_days: %w", err)
+		}
+		days, err := ParseRetentionDays(expanded)
+		if err != nil {
+			return err
+		}
+		cfg.RetentionDays = days
+	case "max_sandboxes_per_workdir":
+		expanded, err := expandEnvBraced(val.Value, env)
+		if err != nil {
+			return fmt.Errorf("max_sandboxes_per_workdir: %w", err)
+		}
+		limit, err := ParseMaxSandboxesPerWorkdir(expanded)
+		if err != nil {
+			return err
+		}
+		cfg.MaxSandboxesPerWorkdir = limit
+	case "org_config_url":
+		expanded, err := expandEnvBraced(val.Value, env)
+		if err != nil {
+			return fmt.Errorf("org_config_url: %w", err)
+		}
+		cfg.OrgConfigURL = expanded
+	case "notifications":
+		n, err := parseNotificationsConfig(val, env)
+		if err != nil {
+			return err
+		}
+		cfg.Notifications = n
 	}
 	return nil
 }
 
+// ParseRetentionDays parses the retention_days value: a whole number of days,
+// 0 (or "") meaning keep forever.
+func ParseRetentionDays(s string) (int, error) {
+	if s == "" {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil || n < 0 {
+		return 0, yoerrors.NewUsageError("invalid retention_days %q: use a whole number of days, or 0 to keep forever", s)
+	}
+	return n, nil
+}
+
+// ParseMaxSandboxesPerWorkdir parses the max_sandboxes_per_workdir value: a
+// whole number of sandboxes, 0 (or "") meaning no limit.
+func ParseMaxSandboxesPerWorkdir(s string) (int, error) {
+	if s == "" {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil || n < 0 {
+		return 0, yoerrors.NewUsageError("invalid max_sandboxes_per_workdir %q: use a whole number of sandboxes, or 0 for no limit", s)
+	}
+	return n, nil
+}
+
+// ParseDiskSize parses the resources.disk value into bytes: a positive
+// number with an optional b, k, m, g or t suffix (powers of 1024), the same
+// shape as resources.memory. "" means no limit and parses as 0.
+func ParseDiskSize(s string) (int64, error) {
+	return ParseSize("resources.disk", s)
+}
+
+// ParseAgentStateSize parses the resources.agent_state value into bytes, the
+// same shape as resources.disk. "" means no cap and parses as 0.
+func ParseAgentStateSize(s string) (int64, error) {
+	return ParseSize("resources.agent_state", s)
+}
+
+// ParseSize parses a size like resources.disk's: a positive number with an
+// optional b, k, m, g or t suffix, "" parsing as 0. what names the setting or
+// flag the value came from, for the error.
+func ParseSize(what, s string) (int64, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, nil
+	}
+	multiplier := int64(1)
+	numStr := s
+	switch strings.ToLower(s[len(s)-1:]) {
+	case "b":
+		numStr = s[:len(s)-1]
+	case "k":
+		multiplier, numStr = 1<<10, s[:len(s)-1]
+	case "m":
+		multiplier, numStr = 1<<20, s[:len(s)-1]
+	case "g":
+		multiplier, numStr = 1<<30, s[:len(s)-1]
+	case "t":
+		multiplier, numStr = 1<<40, s[:len(s)-1]
+	}
+	val, err := strconv.ParseFloat(numStr, 64)
+	if err != nil || val <= 0 {
+		return 0, yoerrors.NewUsageError("invalid %s value %q: must be a positive size with optional suffix (b, k, m, g, t)", what, s)
+	}
+	return int64(val * float64(multiplier)), nil
+}
+
+// parseGitHubConfig reads the github mapping. An empty mapping means no token.
+func parseGitHubConfig(val *yaml.Node, env map[string]string) (*GitHubConfig, error) {
+	if val.Kind != yaml.MappingNode || len(val.Content) == 0 {
+		return nil, nil
+	}
+	gh := &GitHubConfig{}
+	for k := 0; k < len(val.Content)-1; k += 2 {
+		subKey := val.Content[k].Value
+		expanded, err := expandEnvBraced(val.Content[k+1].Value, env)
+		if err != nil {
+			return nil, fmt.Errorf("github.%s: %w", subKey, err)
+		}
+		switch subKey {
+		case "app_id":
+			gh.AppID = expanded
+		case "installation_id":
+			gh.InstallationID = expanded
+		case "private_key":
+			gh.PrivateKey = expanded
+		case "token_env":
+			gh.TokenEnv = expanded
+		case "api_url":
+			gh.APIURL = expanded
+		}
+	}
+	if *gh == (GitHubConfig{}) {
+		return nil, nil
+	}
+	return gh, nil
+}
+
+// parseNotificationsConfig reads the notifications mapping. An empty mapping
+// means no notifications.
+func parseNotificationsConfig(val *yaml.Node, env map[string]string) (*NotificationsConfig, error) {
+	if val.Kind != yaml.MappingNode || len(val.Content) == 0 {
+		return nil, nil
+	}
+	n := &NotificationsConfig{}
+	for k := 0; k < len(val.Content)-1; k += 2 {
+		subKey := val.Content[k].Value
+		expanded, err := expandEnvBraced(val.Content[k+1].Value, env)
+		if err != nil {
+			return nil, fmt.Errorf("notifications.%s: %w", subKey, err)
+		}
+		switch subKey {
+		case "desktop":
+			if expanded == "" {
+				continue
+			}
+			b, err := strconv.ParseBool(expanded)
+			if err != nil {
+				return nil, fmt.Errorf("notifications.desktop: %w", err)
+			}
+			n.Desktop = b
+		case "webhook_url":
+			n.WebhookURL = expanded
+		case "slack_webhook":
+			n.SlackWebhook = expanded
+		}
+	}
+	if *n == (NotificationsConfig{}) {
+		return nil, nil
+	}
+	return n, nil
+}
+
 // parseModelAliases expands env vars in each alias value and returns the map.
 func parseModelAliases(val *yaml.Node, env map[string]string) (map[string]string, error) {
 	aliases := make(map[string]string, len(val.Content)/2)
@@ -7,6 +7,7 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -73,6 +74,27 @@ func TestLoadConfig_AgentOverride(t *testing.T) {
 	assert.Equal(t, "gemini", cfg.Agent)
 }
 
+func TestLoadConfig_BackendFallback(t *testing.T) {
+	dir, layout := configDir(t)
+
+	content := "backend_fallback:\n  - podman\n  - seatbelt\n"
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "config.yaml"), []byte(content), 0600))
+
+	cfg, err := LoadConfig(layout)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"podman", "seatbelt"}, cfg.BackendFallback)
+}
+
+func TestMergeConfigs_BackendFallbackReplaces(t *testing.T) {
+	base := &YoloaiConfig{BackendFallback: []string{"podman"}}
+
+	merged := mergeConfigs(base, &YoloaiConfig{})
+	assert.Equal(t, []string{"podman"}, merged.BackendFallback, "an unset override keeps the base order")
+
+	merged = mergeConfigs(base, &YoloaiConfig{BackendFallback: []string{"seatbelt", "podman"}})
+	assert.Equal(t, []string{"seatbelt", "podman"}, merged.BackendFallback, "an order is replaced, not appended to")
+}
+
 func TestLoadConfig_ModelDefault(t *testing.T) {
 	dir, layout := configDir(t)
 	require.NoError(t, os.WriteFile(filepath.Join(dir, "config.yaml"), []byte(DefaultConfigYAML), 0600))
@@ -179,6 +201,44 @@ func TestLoadGlobalConfig_ModelAliasesEmpty(t *testing.T) {
 	assert.Nil(t, cfg.ModelAliases)
 }
 
+func TestLoadGlobalConfig_Notifications(t *testing.T) {
+	dir, layout := globalConfigDir(t)
+	content := "notifications:\n  desktop: true\n  webhook_url: https://hooks.example.com/yoloai\n"
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "config.yaml"), []byte(content), 0600))
+
+	cfg, err := LoadGlobalConfig(layout)
+	require.NoError(t, err)
+	require.NotNil(t, cfg.Notifications)
+	assert.True(t, cfg.Notifications.Desktop)
+	assert.Equal(t, "https://hooks.example.com/yoloai", cfg.Notifications.WebhookURL)
+	assert.True(t, cfg.Notifications.Enabled())
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "config.yaml"), []byte("notifications:\n  desktop: false\n"), 0600))
+	cfg, err = LoadGlobalConfig(layout)
+	require.NoError(t, err)
+	assert.False(t, cfg.Notifications.Enabled(), "all off is no notifications")
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "config.yaml"), []byte("notifications:\n  slack_webhook: https://hooks.slack.com/services/T/B/x\n"), 0600))
+	cfg, err = LoadGlobalConfig(layout)
+	require.NoError(t, err)
+	assert.Equal(t, "https://hooks.slack.com/services/T/B/x", cfg.Notifications.SlackWebhook)
+	assert.True(t, cfg.Notifications.Enabled(), "Slack alone is enough")
+}
+
+func TestLoadGlobalConfig_MaxSandboxesPerWorkdir(t *testing.T) {
+	dir, layout := globalConfigDir(t)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "config.yaml"), []byte("max_sandboxes_per_workdir: 3\n"), 0600))
+
+	cfg, err := LoadGlobalConfig(layout)
+	require.NoError(t, err)
+	assert.Equal(t, 3, cfg.MaxSandboxesPerWorkdir)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "config.yaml"), []byte("max_sandboxes_per_workdir: -1\n"), 0600))
+	_, err = LoadGlobalConfig(layout)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "max_sandboxes_per_workdir")
+}
+
 func TestLoadConfig_MissingFile(t *testing.T) {
 	tmpDir := t.TempDir()
 	layout := NewLayout(filepath.Join(tmpDir, ".yoloai"))
@@ -842,6 +902,25 @@ func TestLoadConfig_AutoCommitIntervalInvalid(t *testing.T) {
 	assert.Contains(t, err.Error(), "auto_commit_interval")
 }
 
+func TestLoadConfig_ProvenanceHeaders(t *testing.T) {
+	dir, layout := configDir(t)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "config.yaml"), []byte("provenance_headers: true\n"), 0600))
+
+	cfg, err := LoadConfig(layout)
+	require.NoError(t, err)
+	require.NotNil(t, cfg.ProvenanceHeaders)
+	assert.True(t, *cfg.ProvenanceHeaders)
+}
+
+func TestLoadConfig_ProvenanceHeadersInvalid(t *testing.T) {
+	dir, layout := configDir(t)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "config.yaml"), []byte("provenance_headers: maybe\n"), 0600))
+
+	_, err := LoadConfig(layout)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "provenance_headers")
+}
+
 func TestGetConfigValue_AutoCommitInterval(t *testing.T) {
 	tmpDir := t.TempDir()
 	layout := NewLayout(filepath.Join(tmpDir, ".yoloai"))
@@ -852,3 +931,108 @@ func TestGetConfigValue_AutoCommitInterval(t *testing.T) {
 	assert.True(t, found)
 	assert.Equal(t, "0", val)
 }
+
+func TestParseTTL(t *testing.T) {
+	for in, want := range map[string]time.Duration{
+		"":    0,
+		"0":   0,
+		"90m": 90 * time.Minute,
+		"4h":  4 * time.Hour,
+		"7d":  7 * 24 * time.Hour,
+	} {
+		got, err := ParseTTL(in)
+		require.NoError(t, err, in)
+		assert.Equal(t, want, got, in)
+	}
+	for _, bad := range []string{"soon", "1.5d", "-4h", "d"} {
+		_, err := ParseTTL(bad)
+		assert.Error(t, err, bad)
+	}
+}
+
+func TestParseDiskSize(t *testing.T) {
+	for in, want := range map[string]int64{
+		"":     0,
+		"512":  512,
+		"10k":  10 << 10,
+		"20g":  20 << 30,
+		"1.5G": 3 << 29,
+		"1t":   1 << 40,
+	} {
+		got, err := ParseDiskSize(in)
+		require.NoError(t, err, in)
+		assert.Equal(t, want, got, in)
+	}
+	for _, bad := range []string{"big", "0", "-5g", "g"} {
+		_, err := ParseDiskSize(bad)
+		assert.Error(t, err, bad)
+	}
+}
+
+func TestParseAgentStateSize(t *testing.T) {
+	got, err := ParseAgentStateSize("2g")
+	require.NoError(t, err)
+	assert.Equal(t, int64(2<<30), got)
+	_, err = ParseAgentStateSize("lots")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "resources.agent_state", "the error names the setting")
+}
+
+func TestLoadConfig_ResourcesDisk(t *testing.T) {
+	dir, layout := configDir(t)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "config.yaml"), []byte("resources:\n  memory: 8g\n  disk: 20g\n  agent_state: 2g\n"), 0600))
+
+	cfg, err := LoadConfig(layout)
+	require.NoError(t, err)
+	require.NotNil(t, cfg.Resources)
+	assert.Equal(t, "20g", cfg.Resources.Disk)
+	assert.Equal(t, "20g", mergeResources(cfg.Resources, &ResourceLimits{CPUs: "2"}).Disk)
+	assert.Equal(t, "2g", cfg.Resources.AgentState)
+	assert.Equal(t, "1g", mergeResources(cfg.Resources, &ResourceLimits{AgentState: "1g"}).AgentState)
+}
+
+func TestLoadConfig_TTLInvalid(t *testing.T) {
+	dir, layout := configDir(t)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "config.yaml"), []byte("ttl: forever\n"), 0600))
+
+	_, err := LoadConfig(layout)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "ttl")
+}
+
+func TestValidateFakeTime(t *testing.T) {
+	for _, ok := range []string{"", "none", "-3d", "+2h", "+1.5y", "2024-02-29 12:00:00", "@2024-02-29 12:00:00", "@2024-02-29 12:00:00 x10"} {
+		assert.NoError(t, ValidateFakeTime(ok), ok)
+	}
+	for _, bad := range []string{"yesterday", "3d", "2024-02-29", "@2024-02-29T12:00:00", "-3d;rm"} {
+		assert.Error(t, ValidateFakeTime(bad), bad)
+	}
+}
+
+func TestLoadConfig_PreLaunchVerbatim(t *testing.T) {
+	dir, layout := configDir(t)
+	yaml := "pre_launch: |\n  . /etc/corp/proxy.sh\n  export PATH=\"${HOME}/.local/bin:$PATH\"\n"
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "config.yaml"), []byte(yaml), 0600))
+
+	cfg, err := LoadConfig(layout)
+	require.NoError(t, err)
+	assert.Equal(t, ". /etc/corp/proxy.sh\nexport PATH=\"${HOME}/.local/bin:$PATH\"\n", cfg.PreLaunch, "shell source is not config-expanded")
+}
+
+func TestLoadConfig_Guard(t *testing.T) {
+	dir, layout := configDir(t)
+	yaml := "guard:\n  mode: block\n  commands:\n    - terraform destroy\n"
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "config.yaml"), []byte(yaml), 0600))
+
+	cfg, err := LoadConfig(layout)
+	require.NoError(t, err)
+	require.NotNil(t, cfg.Guard)
+	assert.Equal(t, "block", cfg.Guard.Mode)
+	assert.Equal(t, []string{"terraform destroy"}, cfg.Guard.Commands, "the baked-in list is empty; rules are additive")
+
+	for _, bad := range []string{"guard:\n  mode: ask\n", "guard:\n  commands:\n    - /bin/rm -rf\n"} {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "config.yaml"), []byte(bad), 0600))
+		_, err := LoadConfig(layout)
+		assert.Error(t, err, bad)
+	}
+}
@@ -32,6 +32,12 @@ os: linux
 # Empty string (default): auto-detect — prefers docker over podman if both are present.
 container_backend: ""
 
+# Backends 'new' and 'run' fall back to, in order, when the one resolved above
+# is unavailable (daemon down, tool not installed), e.g. [podman, seatbelt].
+# The sandbox records the backend it was created on. Ignored when --backend
+# is given. Empty list (default): an unavailable backend fails the create.
+backend_fallback: []
+
 # Isolation level for the sandbox.
 # Valid values: container, container-enhanced, vm, vm-enhanced
 #   container:          os=linux: Docker or Podman; os=mac: Seatbelt
@@ -56,6 +62,18 @@ network:
   # Additional domains to allow when isolation is active (additive with agent defaults).
   allow: []
 
+# --- Guard ---
+
+# Shims in front of destructive commands the agent runs (rm -rf, git push, ...).
+# Defense in depth, not a boundary: calling the real binary by path skips it.
+guard:
+  # off: no shims. log: record matches in logs/sandbox.jsonl. block: refuse them.
+  mode: "off"
+  # Rules: the command, then words that must all appear in its arguments.
+  # Empty uses the built-in list: rm -rf, git push, git reset --hard,
+  # git clean -f, git branch -D.
+  commands: []
+
 # --- Files and mounts ---
 
 # Files copied into the sandbox's agent-state directory on first run.
@@ -75,10 +93,14 @@ mounts: []
 # Default port mappings. Format: host-port:container-port
 ports: []
 
-# Container resource limits.
+# Container resource limits. disk caps the sandbox directory (e.g. 20g):
+# create and start refuse a sandbox over it. agent_state caps the agent's
+# state directory (e.g. 2g): start trims old session history down to it.
 resources:
   cpus: ""
   memory: ""
+  disk: ""
+  agent_state: ""
 
 # --- Agent behaviour ---
 
@@ -93,6 +115,29 @@ env: {}
 # Seconds between automatic git commits in :copy directories. 0 = disabled.
 auto_commit_interval: 0
 
+# Mark files the agent created with a provenance header comment (tool, model,
+# sandbox, date) when they are applied. Skip per apply with --no-provenance.
+provenance_headers: false
+
+# Timezone (TZ) and locale (LANG) inside the sandbox, e.g. Europe/Berlin and
+# de_DE.UTF-8. Empty = the host's, recorded when the sandbox is created.
+timezone: ""
+locale: ""
+
+# Lifetime of a new sandbox, e.g. 4h or 7d. Once it passes, 'yoloai gc'
+# destroys the sandbox. Empty = never expires. Override per sandbox with --ttl.
+ttl: ""
+
+# Run the sandbox's clock through libfaketime, for date-dependent code: an
+# offset (-3d, +2h), a frozen time ("2024-02-29 12:00:00"), or a start time
+# that then runs on ("@2024-02-29 12:00:00"). Empty = real time.
+faketime: ""
+
+# Bash run as the sandbox user just before tmux and the agent start, e.g.
+# ". /etc/corp/proxy.sh" or a toolchain activation. Variables it exports
+# reach the agent and every tmux window. Empty = none.
+pre_launch: ""
+
 # --- Advanced ---
 
 # Linux capabilities to add (Docker/Podman only).
@@ -113,6 +158,11 @@ const DefaultGlobalConfigYAML = `# yoloai global configuration
 # Available settings:
 #   tmux_conf                Tmux configuration: default, default+host
 #   model_aliases.<alias>    Custom model alias (overrides agent built-in aliases)
+#   github.app_id            GitHub App to mint read-only sandbox tokens from,
+#   github.installation_id     with its installation and PEM private key path
+#   github.private_key
+#   github.token_env         Or: host env var holding a read-only GitHub token
+#   github.api_url           GitHub Enterprise API URL (default api.github.com)
 
 {}
 `
@@ -10,4 +10,5 @@ const (
 	BinDirName          = "bin"
 	TmuxDirName         = "tmux"
 	AgentRuntimeDirName = "agent-runtime"
+	TmpDirName          = "tmp"
 )
@@ -143,6 +143,39 @@ var daemonEnvAllowlist = []string{
 // binary; HOME and TMPDIR keep them operating under the user's locations.
 var hostToolAllowlist = []string{"PATH", "HOME", "TMPDIR"}
 
+// desktopNotifyAllowlist: what a desktop notifier (osascript, notify-send)
+// needs to reach the user's session: the hostTool set plus the display and
+// session-bus addresses notify-send talks to.
+var desktopNotifyAllowlist = []string{
+	"PATH", "HOME", "TMPDIR",
+	"DISPLAY", "WAYLAND_DISPLAY", "DBUS_SESSION_BUS_ADDRESS", "XDG_RUNTIME_DIR",
+}
+
+// editorAllowlist: the user's editor, run on the terminal by `apply
+// --interactive`. PATH and SHELL find it and whatever it spawns; HOME and
+// XDG_CONFIG_HOME find its configuration; TERM, COLORTERM, TERMINFO and the
+// locale vars let a terminal editor draw; DISPLAY, WAYLAND_DISPLAY and the
+// session bus let a graphical one ("code -w") open a window.
+var editorAllowlist = []string{
+	"PATH", "HOME", "TMPDIR", "USER", "SHELL", "XDG_CONFIG_HOME",
+	"TERM", "COLORTERM", "TERMINFO", "LANG", "LC_ALL", "LC_CTYPE",
+	"DISPLAY", "WAYLAND_DISPLAY", "DBUS_SESSION_BUS_ADDRESS", "XDG_RUNTIME_DIR",
+}
+
+// githubCLIAllowlist: the GitHub CLI (gh), which `yoloai pr` and `apply --ci`
+// run with the user's own login. PATH resolves the binary; HOME, GH_CONFIG_DIR
+// and XDG_CONFIG_HOME locate its hosts.yml; the GH_*/GITHUB_* token and host vars
+// carry an explicit login; DBUS_SESSION_BUS_ADDRESS and XDG_RUNTIME_DIR reach the
+// Linux keyring gh stores its token in by default; proxy and SSL vars let it
+// reach the API from behind a corporate proxy.
+var githubCLIAllowlist = []string{
+	"PATH", "HOME", "TMPDIR", "XDG_CONFIG_HOME",
+	"GH_TOKEN", "GITHUB_TOKEN", "GH_ENTERPRISE_TOKEN", "GITHUB_ENTERPRISE_TOKEN", "GH_HOST", "GH_CONFIG_DIR",
+	"DBUS_SESSION_BUS_ADDRESS", "XDG_RUNTIME_DIR",
+	"HTTP_PROXY", "HTTPS_PROXY", "NO_PROXY", "http_proxy", "https_proxy", "no_proxy",
+	"SSL_CERT_FILE", "SSL_CERT_DIR",
+}
+
 // diagnosticEnvAllowlist: the host-networking and yoloai-context vars a bug
 // report captures — enough to explain most backend-connectivity issues, nothing
 // sensitive.
@@ -232,10 +265,30 @@ func (h HostEnv) EnvForHostTool() []string {
 	return sysexec.Curated(h.vars, hostToolAllowlist, nil)
 }
 
+// EnvForDesktopNotify is the environment for a desktop notifier subprocess
+// (osascript, notify-send): enough to find the user's graphical session.
+func (h HostEnv) EnvForDesktopNotify() []string {
+	return sysexec.Curated(h.vars, desktopNotifyAllowlist, nil)
+}
+
+// EnvForEditor is the environment for the user's editor: enough to draw on
+// the terminal or open a window, and to find the editor's own configuration.
+func (h HostEnv) EnvForEditor() []string {
+	return sysexec.Curated(h.vars, editorAllowlist, nil)
+}
+
+// EnvForGitHubCLI is the environment for the GitHub CLI (gh): enough to find
+// the user's gh login, and nothing else from the host.
+func (h HostEnv) EnvForGitHubCLI() []string {
+	return sysexec.Curated(h.vars, githubCLIAllowlist, nil)
+}
+
 // PassthroughEnv returns the entire snapshot as a sorted KEY=VALUE slice. It is
-// the ONE sanctioned full-passthrough: `yoloai x` runs user-authored extension
-// scripts via `sh -c`, and those scripts get the user's full edge-resolved
-// environment by design. Library code that shells out must use a curated
+// the sanctioned full-passthrough for programs the user chose, not yoloAI:
+// `yoloai x` runs user-authored extension scripts via `sh -c`, and lifecycle
+// hooks run the user's hook scripts; both get the user's full edge-resolved
+// environment by design. The daemon's sweeps pass it on to the yoloai child
+// they run, which curates at its own edge. Library code that shells out must use a curated
 // EnvFor… accessor instead, never this.
 func (h HostEnv) PassthroughEnv() []string {
 	out := make([]string, 0, len(h.vars))
@@ -314,6 +367,36 @@ func (h HostEnv) TerminalColumns() (int, bool) {
 	return n, true
 }
 
+// Timezone returns the host's TZ variable and whether it was set. Most hosts
+// leave TZ unset and configure the zone via /etc/localtime instead. Not a
+// subprocess env — a plain query.
+func (h HostEnv) Timezone() (string, bool) {
+	tz := strings.TrimSpace(h.vars["TZ"])
+	return tz, tz != ""
+}
+
+// Locale returns the host's effective locale — LC_ALL, then LANG — and whether
+// either was set. Not a subprocess env — a plain query.
+func (h HostEnv) Locale() (string, bool) {
+	for _, k := range []string{"LC_ALL", "LANG"} {
+		if v := strings.TrimSpace(h.vars[k]); v != "" {
+			return v, true
+		}
+	}
+	return "", false
+}
+
+// Editor returns the user's editor command — VISUAL, then EDITOR — and whether
+// either was set. Not a subprocess env — a plain query.
+func (h HostEnv) Editor() (string, bool) {
+	for _, k := range []string{"VISUAL", "EDITOR"} {
+		if v := strings.TrimSpace(h.vars[k]); v != "" {
+			return v, true
+		}
+	}
+	return "", false
+}
+
 // curatedMap returns the allowlisted subset of vars as a new (always non-nil)
 // map. Only exact keys named in allow are carried.
 func curatedMap(vars map[string]string, allow []string) map[string]string {
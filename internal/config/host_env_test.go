@@ -62,3 +62,46 @@ func TestEnvForDaemonDiscovery_CarriesTMPDIR(t *testing.T) {
 	assert.Equal(t, "unix:///var/run/docker.sock", env["DOCKER_HOST"])
 	assert.NotContains(t, env, "SECRET_KEY", "non-allowlisted vars must not leak into daemon discovery")
 }
+
+// gh acts with the user's own login, so it must see the token and config-dir
+// vars that carry it — but a yoloai pr run must not hand gh the rest of the
+// host environment (cloud credentials, agent API keys).
+func TestEnvForGitHubCLI_CarriesLoginAndDropsTheRest(t *testing.T) {
+	layout := Layout{}.WithEnv(map[string]string{
+		"PATH":              "/usr/bin",
+		"HOME":              "/home/tester",
+		"GH_TOKEN":          "gho_abc",
+		"GH_CONFIG_DIR":     "/home/tester/.gh",
+		"ANTHROPIC_API_KEY": "should-not-pass",
+		"AWS_SECRET_KEY":    "should-not-pass",
+	})
+
+	env := envSliceToMap(layout.Env().EnvForGitHubCLI())
+
+	assert.Equal(t, "/usr/bin", env["PATH"])
+	assert.Equal(t, "/home/tester", env["HOME"])
+	assert.Equal(t, "gho_abc", env["GH_TOKEN"])
+	assert.Equal(t, "/home/tester/.gh", env["GH_CONFIG_DIR"])
+	assert.NotContains(t, env, "ANTHROPIC_API_KEY", "agent credentials must not reach gh")
+	assert.NotContains(t, env, "AWS_SECRET_KEY", "non-allowlisted vars must not reach gh")
+}
+
+// The editor must be able to draw on the terminal (TERM) and find its own
+// config, but it is not handed the host's secrets.
+func TestEnvForEditor_CarriesTerminalAndDropsSecrets(t *testing.T) {
+	layout := Layout{}.WithEnv(map[string]string{
+		"PATH":              "/usr/bin",
+		"HOME":              "/home/tester",
+		"TERM":              "xterm-256color",
+		"DISPLAY":           ":0",
+		"ANTHROPIC_API_KEY": "should-not-pass",
+	})
+
+	env := envSliceToMap(layout.Env().EnvForEditor())
+
+	assert.Equal(t, "/usr/bin", env["PATH"])
+	assert.Equal(t, "/home/tester", env["HOME"])
+	assert.Equal(t, "xterm-256color", env["TERM"])
+	assert.Equal(t, ":0", env["DISPLAY"])
+	assert.NotContains(t, env, "ANTHROPIC_API_KEY", "agent credentials must not reach the editor")
+}
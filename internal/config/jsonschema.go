@@ -0,0 +1,365 @@
+package config
+
+// ABOUTME: JSON Schemas for the config files, generated from the Go structs
+// ABOUTME: that hold them, and the check of a parsed file against one.
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/kstenerud/yoloai/yoerrors"
+	"gopkg.in/yaml.v3"
+)
+
+// Schema is the subset of JSON Schema (draft 2020-12) the config files need.
+type Schema struct {
+	Schema      string             `json:"$schema,omitempty"`
+	ID          string             `json:"$id,omitempty"`
+	Title       string             `json:"title,omitempty"`
+	Description string             `json:"description,omitempty"`
+	Type        string             `json:"type,omitempty"`
+	Enum        []string           `json:"enum,omitempty"`
+	Properties  map[string]*Schema `json:"properties,omitempty"`
+	// AdditionalProperties is false for a struct-backed mapping, whose keys
+	// are all known, or the value schema of a free-form map such as env.
+	AdditionalProperties any       `json:"additionalProperties,omitempty"`
+	Items                *Schema   `json:"items,omitempty"`
+	AnyOf                []*Schema `json:"anyOf,omitempty"`
+}
+
+// SchemaBaseURL is where the schemas are published, as <name>.json: the
+// repository's docs/schemas, kept current by a test.
+const SchemaBaseURL = "https://raw.githubusercontent.com/kstenerud/yoloai/main/docs/schemas/"
+
+// SchemaInfo names one config file's schema.
+type SchemaInfo struct {
+	Name string `json:"name"`
+	File string `json:"file"` // where the file lives, relative to the data dir
+}
+
+// schemaSources are the config files a schema is published for. The system
+// layers (the org config and SystemConfigDir/config.yaml) take the keys of
+// both the user's files together.
+var schemaSources = []struct {
+	SchemaInfo
+	title string
+	types []reflect.Type
+}{
+	{SchemaInfo{"config", "config.yaml"}, "yoloai global config", []reflect.Type{reflect.TypeFor[GlobalConfig]()}},
+	{SchemaInfo{"defaults", "defaults/config.yaml"}, "yoloai sandbox defaults", []reflect.Type{reflect.TypeFor[YoloaiConfig]()}},
+	{SchemaInfo{"profile", "profiles/<name>/config.yaml"}, "yoloai profile", []reflect.Type{reflect.TypeFor[ProfileConfig]()}},
+	{SchemaInfo{"system", "<system config dir>/config.yaml"}, "yoloai system config", []reflect.Type{reflect.TypeFor[YoloaiConfig](), reflect.TypeFor[GlobalConfig]()}},
+}
+
+// schemaHint adds what a Go field's type can't say about its YAML key, by
+// dotted path ("network.isolated", "directories[].mode").
+type schemaHint struct {
+	desc  string
+	enum  []string
+	shape *Schema // replaces the shape reflected from the field's type
+}
+
+var schemaHints = map[string]schemaHint{
+	"agent":                       {desc: "Agent to launch inside the sandbox: aider, claude, codex, gemini, opencode."},
+	"model":                       {desc: "Model name or alias passed to the agent. Empty = the agent's own default."},
+	"os":                          {desc: "Guest OS for the sandbox: linux (default) or mac."},
+	"container_backend":           {desc: "Preferred container backend, e.g. docker or podman. Empty = auto-detect."},
+	"backend_fallback":            {desc: "Backends new and run try, in order, when the resolved backend is unavailable, e.g. [podman, seatbelt]. Empty = fail instead."},
+	"tart":                        {desc: "Tart (macOS VM backend) settings.", shape: &Schema{Type: "object", Properties: map[string]*Schema{"image": {Type: "string", Description: "Custom base VM image for the Tart backend."}}, AdditionalProperties: false}},
+	"env":                         {desc: "Environment variables forwarded to the sandbox. Supports ${VAR} expansion."},
+	"resources":                   {desc: "Resource limits for the sandbox."},
+	"resources.cpus":              {desc: "CPU limit, e.g. 2 or 1.5."},
+	"resources.memory":            {desc: "Memory limit, e.g. 4g."},
+	"resources.disk":              {desc: "Cap on the sandbox directory, e.g. 20g; create and start refuse a sandbox over it."},
+	"resources.agent_state":       {desc: "Cap on the agent's state directory, e.g. 2g; start trims the agent's prunable state, oldest first, down to it."},
+	"network":                     {desc: "Network isolation settings."},
+	"network.isolated":            {desc: "Allow only the agent's API and network.allow."},
+	"network.allow":               {desc: "Extra domains to allow when isolated (additive with the agent's defaults)."},
+	"guard":                       {desc: "Shims in front of destructive commands the agent runs."},
+	"guard.mode":                  {desc: "off: no shims. log: record matches. block: refuse them.", enum: []string{"", "off", "log", "block"}},
+	"guard.commands":              {desc: "Rules: the command, then words that must all appear in its arguments. Empty = the built-in list."},
+	"mounts":                      {desc: "Extra bind mounts: host-path:container-path[:ro]."},
+	"ports":                       {desc: "Port mappings: host-port:container-port."},
+	"agent_args":                  {desc: "Default CLI args per agent, keyed by agent name."},
+	"agent_files":                 {desc: "Files seeded into the agent's state on first run: a base directory, or a list of files and directories.", shape: &Schema{AnyOf: []*Schema{{Type: "string"}, {Type: "array", Items: &Schema{Type: "string"}}}}},
+	"cap_add":                     {desc: "Linux capabilities to add (Docker/Podman only)."},
+	"devices":                     {desc: "Host devices to expose (Docker/Podman only)."},
+	"setup":                       {desc: "Commands run at container start, before the agent launches."},
+	"auto_commit_interval":        {desc: "Seconds between automatic git commits in :copy directories. 0 = disabled."},
+	"isolation":                   {desc: "Isolation level for the sandbox.", enum: []string{"", "container", "container-enhanced", "container-privileged", "vm", "vm-enhanced"}},
+	"provenance_headers":          {desc: "Mark files the agent created with a provenance header comment when they are applied."},
+	"timezone":                    {desc: "TZ inside the sandbox, e.g. Europe/Berlin. Empty = the host's."},
+	"locale":                      {desc: "LANG inside the sandbox, e.g. de_DE.UTF-8. Empty = the host's."},
+	"ttl":                         {desc: "Lifetime of a new sandbox, e.g. 4h or 7d, after which 'yoloai gc' destroys it. Empty = never."},
+	"faketime":                    {desc: "libfaketime spec for the sandbox's clock: an offset (-3d), a frozen time, or @ a start time. Empty = real time."},
+	"pre_launch":                  {desc: "Bash run just before tmux and the agent start; what it exports reaches the agent."},
+	"backend":                     {desc: "Backend this profile requires; creating a sandbox with another fails."},
+	"workdir":                     {desc: "The sandbox's working directory, when the command line doesn't name one."},
+	"workdir.path":                {desc: "Host path."},
+	"workdir.mode":                {desc: "copy (default) or rw."},
+	"workdir.mount":               {desc: "Mount point inside the sandbox. Empty = the host path."},
+	"directories":                 {desc: "Auxiliary directories, like -d on the command line."},
+	"directories[].path":          {desc: "Host path."},
+	"directories[].mode":          {desc: "rw, copy, or empty for read-only."},
+	"directories[].mount":         {desc: "Mount point inside the sandbox. Empty = the host path."},
+	"tmux_conf":                   {desc: "Tmux configuration: default, or default+host to add the host's ~/.tmux.conf."},
+	"model_aliases":               {desc: "Custom model aliases, overriding the agents' built-in ones. A profile's are merged over the global ones."},
+	"github":                      {desc: "Where a sandbox's read-only GitHub token comes from: a GitHub App, or token_env."},
+	"github.app_id":               {desc: "GitHub App to mint read-only tokens from."},
+	"github.installation_id":      {desc: "The app's installation ID."},
+	"github.private_key":          {desc: "Path to the app's PEM private key."},
+	"github.token_env":            {desc: "Host environment variable holding a read-only token."},
+	"github.api_url":              {desc: "GitHub Enterprise API URL. Empty = https://api.github.com."},
+	"retention_days":              {desc: "Days to keep the prompts and logs of trashed sandboxes. 0 = forever."},
+	"max_sandboxes_per_workdir":   {desc: "Most sandboxes one workdir may have at once; creating another is refused. 0 = no limit."},
+	"org_config_url":              {desc: "https URL 'yoloai config pull' fetches the org-wide config from."},
+	"notifications":               {desc: "How 'yoloai daemon' reports sandbox events (created, needs input, finished, failed)."},
+	"notifications.desktop":       {desc: "Show a desktop notification (osascript on macOS, notify-send on Linux)."},
+	"notifications.webhook_url":   {desc: "http(s) URL to POST a JSON event to. Empty = none."},
+	"notifications.slack_webhook": {desc: "Slack incoming-webhook URL to post a one-line message to. Empty = none."},
+}
+
+// Schemas built once for the loaders; ConfigSchema builds a fresh copy.
+var (
+	globalSchema   = mustBuildSchema("config")
+	defaultsSchema = mustBuildSchema("defaults")
+	profileSchema  = mustBuildSchema("profile")
+	systemSchema   = mustBuildSchema("system")
+)
+
+// ConfigSchemas lists the config files a schema is published for.
+func ConfigSchemas() []SchemaInfo {
+	infos := make([]SchemaInfo, len(schemaSources))
+	for i, src := range schemaSources {
+		infos[i] = src.SchemaInfo
+	}
+	return infos
+}
+
+// ConfigSchema returns the JSON Schema of the named config file (see
+// ConfigSchemas), generated from the struct that holds it.
+func ConfigSchema(name string) (*Schema, error) {
+	for _, src := range schemaSources {
+		if src.Name != name {
+			continue
+		}
+		s := &Schema{
+			Schema:               "https://json-schema.org/draft/2020-12/schema",
+			ID:                   SchemaBaseURL + name + ".json",
+			Title:                src.title,
+			Description:          "yoloai " + src.File,
+			Type:                 "object",
+			Properties:           map[string]*Schema{},
+			AdditionalProperties: false,
+		}
+		for _, t := range src.types {
+			addStructProperties(s, t, "")
+		}
+		return s, nil
+	}
+	names := make([]string, len(schemaSources))
+	for i, src := range schemaSources {
+		names[i] = src.Name
+	}
+	return nil, yoerrors.NewUsageError("unknown schema %q (one of: %s)", name, strings.Join(names, ", "))
+}
+
+// MarshalSchema renders s as indented JSON, the form it is published in.
+func MarshalSchema(s *Schema) ([]byte, error) {
+	var b bytes.Buffer
+	enc := json.NewEncoder(&b)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(s); err != nil {
+		return nil, fmt.Errorf("marshal schema: %w", err)
+	}
+	return b.Bytes(), nil
+}
+
+func mustBuildSchema(name string) *Schema {
+	s, err := ConfigSchema(name)
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+// addStructProperties adds a property to s for each yaml-tagged field of the
+// struct t, folding in ",inline" embedded structs.
+func addStructProperties(s *Schema, t reflect.Type, prefix string) {
+	for f := range t.Fields() {
+		name, opts, _ := strings.Cut(f.Tag.Get("yaml"), ",")
+		if opts == "inline" {
+			addStructProperties(s, f.Type, prefix)
+			continue
+		}
+		if name == "" || name == "-" {
+			continue
+		}
+		s.Properties[name] = fieldSchema(f.Type, prefix+name)
+	}
+}
+
+// fieldSchema returns the schema of a value of type t at path, with its hint.
+func fieldSchema(t reflect.Type, path string) *Schema {
+	hint := schemaHints[path]
+	var s *Schema
+	switch {
+	case hint.shape != nil:
+		shape := *hint.shape
+		s = &shape
+	case t.Kind() == reflect.Pointer:
+		s = fieldSchema(t.Elem(), path)
+	case t.Kind() == reflect.String:
+		s = &Schema{Type: "string"}
+	case t.Kind() == reflect.Bool:
+		s = &Schema{Type: "boolean"}
+	case t.Kind() == reflect.Int:
+		s = &Schema{Type: "integer"}
+	case t.Kind() == reflect.Slice:
+		s = &Schema{Type: "array", Items: fieldSchema(t.Elem(), path+"[]")}
+	case t.Kind() == reflect.Map:
+		s = &Schema{Type: "object", AdditionalProperties: fieldSchema(t.Elem(), path+".*")}
+	case t.Kind() == reflect.Struct:
+		s = &Schema{Type: "object", Properties: map[string]*Schema{}, AdditionalProperties: false}
+		addStructProperties(s, t, path+".")
+	default:
+		panic(fmt.Sprintf("config schema: no JSON type for %s (%s)", path, t))
+	}
+	if hint.desc != "" {
+		s.Description = hint.desc
+	}
+	if hint.enum != nil {
+		s.Enum = hint.enum
+	}
+	return s
+}
+
+// validateConfigNode checks root, parsed from source, against s and returns
+// every problem found, each prefixed with its file, line and column. Keys the
+// schema doesn't know are problems only when strict. A value still holding a
+// ${VAR} is checked once expanded, by the handler that reads it.
+func validateConfigNode(root *yaml.Node, s *Schema, source string, strict bool) error {
+	v := &nodeValidator{source: source, strict: strict}
+	v.check(root, s, "")
+	return errors.Join(v.problems...)
+}
+
+type nodeValidator struct {
+	source   string
+	strict   bool
+	problems []error
+}
+
+func (v *nodeValidator) fail(n *yaml.Node, path, format string, args ...any) {
+	v.problems = append(v.problems, fmt.Errorf("%s:%d:%d: %s: %s", v.source, n.Line, n.Column, path, fmt.Sprintf(format, args...)))
+}
+
+func (v *nodeValidator) check(n *yaml.Node, s *Schema, path string) {
+	if n.Kind == yaml.AliasNode {
+		n = n.Alias
+	}
+	if n.Tag == "!!null" {
+		return // an empty value sets nothing
+	}
+	if len(s.AnyOf) > 0 {
+		var types []string
+		for _, alt := range s.AnyOf {
+			if nodeKindFor(alt.Type) == n.Kind {
+				v.check(n, alt, path)
+				return
+			}
+			types = append(types, typeNoun(alt.Type))
+		}
+		v.fail(n, path, "expected %s, got %s", strings.Join(types, " or "), nodeNoun(n))
+		return
+	}
+	if want := nodeKindFor(s.Type); n.Kind != want {
+		v.fail(n, path, "expected %s, got %s", typeNoun(s.Type), nodeNoun(n))
+		return
+	}
+	switch n.Kind {
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			key, val := n.Content[i], n.Content[i+1]
+			child := key.Value
+			if path != "" {
+				child = path + "." + key.Value
+			}
+			if ps, ok := s.Properties[key.Value]; ok {
+				v.check(val, ps, child)
+			} else if as, ok := s.AdditionalProperties.(*Schema); ok {
+				v.check(val, as, child)
+			} else if v.strict {
+				v.fail(key, child, "unknown key")
+			}
+		}
+	case yaml.SequenceNode:
+		for i, item := range n.Content {
+			v.check(item, s.Items, fmt.Sprintf("%s[%d]", path, i))
+		}
+	default:
+		if strings.Contains(n.Value, "${") {
+			return
+		}
+		switch s.Type {
+		case "boolean":
+			if _, err := strconv.ParseBool(n.Value); err != nil {
+				v.fail(n, path, "expected true or false, got %q", n.Value)
+				return
+			}
+		case "integer":
+			if _, err := strconv.Atoi(n.Value); err != nil {
+				v.fail(n, path, "expected a whole number, got %q", n.Value)
+				return
+			}
+		}
+		if s.Enum != nil && !slices.Contains(s.Enum, n.Value) {
+			v.fail(n, path, "%q is not one of: %s", n.Value, strings.Join(slices.DeleteFunc(slices.Clone(s.Enum), func(e string) bool { return e == "" }), ", "))
+		}
+	}
+}
+
+// nodeKindFor is the YAML node kind a JSON Schema type is written as.
+func nodeKindFor(jsonType string) yaml.Kind {
+	switch jsonType {
+	case "object":
+		return yaml.MappingNode
+	case "array":
+		return yaml.SequenceNode
+	default:
+		return yaml.ScalarNode
+	}
+}
+
+func typeNoun(jsonType string) string {
+	switch jsonType {
+	case "object":
+		return "a mapping"
+	case "array":
+		return "a list"
+	case "boolean":
+		return "true or false"
+	case "integer":
+		return "a whole number"
+	default:
+		return "a " + jsonType
+	}
+}
+
+func nodeNoun(n *yaml.Node) string {
+	switch n.Kind {
+	case yaml.MappingNode:
+		return "a mapping"
+	case yaml.SequenceNode:
+		return "a list"
+	default:
+		return strconv.Quote(n.Value)
+	}
+}
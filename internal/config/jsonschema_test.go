@@ -0,0 +1,132 @@
+// ABOUTME: Tests for the config JSON Schemas: coverage of every parsed key,
+// ABOUTME: the published copies, and line-numbered errors from the loaders.
+
+package config
+
+import (
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/kstenerud/yoloai/yoerrors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+// TestConfigSchema_CoversParsedKeys keeps the structs' yaml tags, which the
+// schemas come from, in step with the handlers that parse each file.
+func TestConfigSchema_CoversParsedKeys(t *testing.T) {
+	handled := slices.Sorted(maps.Keys(yoloaiConfigHandlers))
+	assert.Equal(t, handled, slices.Sorted(maps.Keys(defaultsSchema.Properties)))
+
+	profileKeys := append(slices.Clone(handled), slices.Collect(maps.Keys(profileOnlyHandlers))...)
+	assert.ElementsMatch(t, profileKeys, slices.Collect(maps.Keys(profileSchema.Properties)))
+
+	globalKeys := []string{"tmux_conf", "model_aliases", "github", "retention_days", "max_sandboxes_per_workdir", "org_config_url", "notifications"}
+	assert.ElementsMatch(t, globalKeys, slices.Collect(maps.Keys(globalSchema.Properties)))
+	assert.ElementsMatch(t, append(handled, globalKeys...), slices.Collect(maps.Keys(systemSchema.Properties)))
+}
+
+// TestConfigSchema_Published checks docs/schemas holds the current schemas.
+// Run `make schemas` (YOLOAI_UPDATE_SCHEMAS=1) to rewrite them.
+func TestConfigSchema_Published(t *testing.T) {
+	dir := filepath.Join("..", "..", "docs", "schemas")
+	for _, info := range ConfigSchemas() {
+		s, err := ConfigSchema(info.Name)
+		require.NoError(t, err)
+		want, err := MarshalSchema(s)
+		require.NoError(t, err)
+		path := filepath.Join(dir, info.Name+".json")
+		if os.Getenv("YOLOAI_UPDATE_SCHEMAS") != "" {
+			require.NoError(t, os.WriteFile(path, want, 0600))
+			continue
+		}
+		got, err := os.ReadFile(path) //nolint:gosec // G304: test fixture path
+		require.NoError(t, err)
+		assert.Equal(t, string(want), string(got), "%s is stale: run `make schemas`", path)
+	}
+
+	_, err := ConfigSchema("nope")
+	var ue *yoerrors.UsageError
+	assert.ErrorAs(t, err, &ue)
+}
+
+func TestConfigSchema_Shapes(t *testing.T) {
+	assert.Equal(t, "boolean", defaultsSchema.Properties["network"].Properties["isolated"].Type)
+	assert.Equal(t, "integer", defaultsSchema.Properties["auto_commit_interval"].Type)
+	assert.Equal(t, "string", defaultsSchema.Properties["env"].AdditionalProperties.(*Schema).Type)
+	assert.Equal(t, "string", defaultsSchema.Properties["tart"].Properties["image"].Type)
+	assert.Len(t, defaultsSchema.Properties["agent_files"].AnyOf, 2)
+	assert.Contains(t, defaultsSchema.Properties["isolation"].Enum, "vm")
+	assert.Equal(t, "string", profileSchema.Properties["directories"].Items.Properties["mode"].Type)
+	assert.NotContains(t, profileSchema.Properties["workdir"].Properties, "copy_strict", "not read from YAML")
+}
+
+func TestValidateConfigNode(t *testing.T) {
+	parse := func(src string) *yaml.Node {
+		var doc yaml.Node
+		require.NoError(t, yaml.Unmarshal([]byte(src), &doc))
+		return doc.Content[0]
+	}
+	src := `agent: claude
+network:
+  isolated: yes
+  alow: [a.com]
+auto_commit_interval: ${INTERVAL}
+guard:
+  mode: loud
+ports: 8080
+env:
+`
+	err := validateConfigNode(parse(src), defaultsSchema, "cfg.yaml", true)
+	require.Error(t, err)
+	msg := err.Error()
+	assert.Contains(t, msg, `cfg.yaml:3:13: network.isolated: expected true or false, got "yes"`)
+	assert.Contains(t, msg, "cfg.yaml:4:3: network.alow: unknown key")
+	assert.Contains(t, msg, `cfg.yaml:7:9: guard.mode: "loud" is not one of: off, log, block`)
+	assert.Contains(t, msg, `cfg.yaml:8:8: ports: expected a list, got "8080"`)
+	assert.NotContains(t, msg, "auto_commit_interval", "checked once expanded")
+	assert.NotContains(t, msg, "env", "an empty value sets nothing")
+
+	err = validateConfigNode(parse(src), defaultsSchema, "cfg.yaml", false)
+	require.Error(t, err)
+	assert.NotContains(t, err.Error(), "unknown key")
+
+	require.NoError(t, validateConfigNode(parse("agent_files: [a, b]\n"), defaultsSchema, "cfg.yaml", true))
+	require.NoError(t, validateConfigNode(parse("agent_files: ~/x\n"), defaultsSchema, "cfg.yaml", true))
+	err = validateConfigNode(parse("agent_files: {a: b}\n"), defaultsSchema, "cfg.yaml", true)
+	assert.ErrorContains(t, err, "agent_files: expected a string or a list, got a mapping")
+}
+
+func TestLoadProfile_WrongTypeReportsLine(t *testing.T) {
+	home, layout := setupProfileDir(t, "typed", "agent: claude\ndirectories:\n  - path: /a\n    mode: [rw]\n")
+
+	_, err := LoadProfile(layout, "typed")
+	require.Error(t, err)
+	path := filepath.Join(home, ".yoloai", "profiles", "typed", "config.yaml")
+	assert.ErrorContains(t, err, path+":4:11: directories[0].mode: expected a string, got a list")
+}
+
+func TestLoadDefaultsConfig_ReportsLine(t *testing.T) {
+	dir, layout := configDir(t)
+	path := filepath.Join(dir, "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("agent: claude\nbogus: 1\n"), 0600))
+
+	_, err := LoadDefaultsConfig(layout)
+	assert.ErrorContains(t, err, path+":2:1: bogus: unknown key")
+}
+
+func TestUpdateConfigFields_RefusesWrongType(t *testing.T) {
+	dir, layout := configDir(t)
+	path := filepath.Join(dir, "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("agent: claude\n"), 0600))
+
+	err := UpdateConfigFields(layout, map[string]string{"auto_commit_interval": "often"})
+	assert.ErrorContains(t, err, "auto_commit_interval: expected a whole number")
+	data, readErr := os.ReadFile(path) //nolint:gosec // G304: test path
+	require.NoError(t, readErr)
+	assert.Equal(t, "agent: claude\n", string(data), "nothing written")
+}
@@ -99,6 +99,13 @@ type Layout struct {
 	// *where* becomes caller-supplied" refinement of D59 — the library still
 	// decides what to stage and when to delete it; the embedder decides where.
 	SecretsStagingDir string
+
+	// SystemConfigDir is the machine-wide configuration directory a platform
+	// team manages (the CLI uses /etc/yoloai): its config.yaml is layered
+	// beneath the user's own config, and its profiles/ are offered alongside
+	// the user's. The zero value "" means no system layer, so an embedder or
+	// test sees only its own DataDir unless it opts in.
+	SystemConfigDir string
 }
 
 // TempDir returns DataDir/tmp — the yoloai-owned scratch root. Temp files live
@@ -195,6 +202,18 @@ func (l Layout) SandboxesDir() string {
 	return filepath.Join(l.DataDir, "sandboxes")
 }
 
+// ReposDir returns DataDir/repos/, where sandboxes created from a remote
+// repository (new --repo) keep the checkout that stands in for a host workdir.
+func (l Layout) ReposDir() string {
+	return filepath.Join(l.DataDir, "repos")
+}
+
+// ObjectsDir returns DataDir/objects/, where :copy work copies of one host
+// directory share the git packs they would otherwise each hold a copy of.
+func (l Layout) ObjectsDir() string {
+	return filepath.Join(l.DataDir, "objects")
+}
+
 // ProfilesDir returns DataDir/profiles/.
 func (l Layout) ProfilesDir() string {
 	return filepath.Join(l.DataDir, "profiles")
@@ -293,6 +312,37 @@ func (l Layout) GlobalConfigPath() string {
 	return filepath.Join(l.DataDir, "config.yaml")
 }
 
+// SystemConfigPath returns SystemConfigDir/config.yaml, or "" when the
+// Layout has no system layer.
+func (l Layout) SystemConfigPath() string {
+	if l.SystemConfigDir == "" {
+		return ""
+	}
+	return filepath.Join(l.SystemConfigDir, "config.yaml")
+}
+
+// SystemProfilesDir returns SystemConfigDir/profiles/, or "" when the Layout
+// has no system layer.
+func (l Layout) SystemProfilesDir() string {
+	if l.SystemConfigDir == "" {
+		return ""
+	}
+	return filepath.Join(l.SystemConfigDir, "profiles")
+}
+
+// OrgConfigCachePath returns DataDir/cache/org-config.yaml — where `config
+// pull` keeps the config fetched from org_config_url.
+func (l Layout) OrgConfigCachePath() string {
+	return filepath.Join(l.CacheDir(), "org-config.yaml")
+}
+
+// SystemProfileBuildDir returns DataDir/cache/system-profiles/<name>/ — the
+// writable copy of a system-wide profile its image is built from, so the
+// build checksum has somewhere to live.
+func (l Layout) SystemProfileBuildDir(name string) string {
+	return filepath.Join(l.CacheDir(), "system-profiles", name)
+}
+
 // ProfileDir returns DataDir/profiles/<name>/. Migration target for
 // the package-level ProfileDirPath(name) helper.
 func (l Layout) ProfileDir(name string) string {
@@ -66,9 +66,34 @@ func expandEnvBraced(s string, env map[string]string) (string, error) {
 // homeDir is the caller-supplied home directory; callers derive it from
 // layout.HomeDir (the conventional $HOME/.yoloai DataDir)
 // or pass an explicit home for testing.
+// Windows-style home- and dot-relative paths are accepted too (see
+// normalizeWindowsRelative).
 func ExpandTilde(path, homeDir string) string {
+	path = normalizeWindowsRelative(path)
 	if !strings.HasPrefix(path, "~") {
 		return path
 	}
 	return filepath.Join(homeDir, path[1:])
 }
+
+// windowsRelativePrefixes are the path prefixes that unambiguously mark a
+// Windows-style relative path: on POSIX nobody names a directory "~\foo".
+var windowsRelativePrefixes = []string{`~\`, `.\`, `..\`}
+
+// normalizeWindowsRelative rewrites backslash separators to the host's in a
+// path that starts with a Windows-style home- or dot-relative prefix (`~\src`,
+// `.\lib`, `..\shared`), as found in config files edited on
+// Windows. Other paths are returned unchanged: a backslash is a legal filename
+// character on POSIX, so it is only treated as a separator when the prefix
+// makes the intent unambiguous. On Windows the separator is already native.
+func normalizeWindowsRelative(path string) string {
+	if filepath.Separator == '\\' {
+		return path
+	}
+	for _, prefix := range windowsRelativePrefixes {
+		if strings.HasPrefix(path, prefix) {
+			return strings.ReplaceAll(path, `\`, "/")
+		}
+	}
+	return path
+}
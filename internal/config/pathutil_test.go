@@ -129,3 +129,14 @@ func TestExpandTilde_Relative(t *testing.T) {
 	// No tilde → returned unchanged
 	assert.Equal(t, "relative/path", ExpandTilde("relative/path", "/home/user"))
 }
+
+func TestExpandTilde_WindowsStyle(t *testing.T) {
+	if filepath.Separator == '\\' {
+		t.Skip("backslash is the native separator on Windows")
+	}
+	assert.Equal(t, "/home/user/src/app", ExpandTilde(`~\src\app`, "/home/user"))
+	assert.Equal(t, "./lib/x", ExpandTilde(`.\lib\x`, "/home/user"))
+	assert.Equal(t, "../shared", ExpandTilde(`..\shared`, "/home/user"))
+	// A backslash elsewhere is a legal filename character on POSIX.
+	assert.Equal(t, `/data/odd\name`, ExpandTilde(`/data/odd\name`, "/home/user"))
+}
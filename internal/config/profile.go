@@ -9,7 +9,6 @@ import (
 	"os"
 	"path/filepath"
 	"slices"
-	"sort"
 	"strings"
 
 	"gopkg.in/yaml.v3"
@@ -18,30 +17,33 @@ import (
 )
 
 // ProfileConfig holds the parsed fields from a profile's config.yaml file.
-// A profile is the YoloaiConfig superset plus three profile-only keys (a backend
-// constraint, workdir, and directories). The common fields are shared via the
+// A profile is the YoloaiConfig superset plus four profile-only keys (a backend
+// constraint, workdir, directories, and model aliases). The common fields are shared via the
 // embedded YoloaiConfig and parsed by the same yoloaiConfigHandlers, so the
 // profile parser only adds handlers for the profile-only keys (IC2 fold).
 type ProfileConfig struct {
-	YoloaiConfig
-	Backend     string          // optional backend constraint (different from container_backend)
-	Workdir     *ProfileWorkdir // nil if not specified
-	Directories []ProfileDir    // empty if not specified
+	YoloaiConfig `yaml:",inline"`
+	Backend      string          `yaml:"backend"`     // optional backend constraint (different from container_backend)
+	Workdir      *ProfileWorkdir `yaml:"workdir"`     // nil if not specified
+	Directories  []ProfileDir    `yaml:"directories"` // empty if not specified
+	// ModelAliases are merged over the global config's model_aliases for
+	// sandboxes of this profile.
+	ModelAliases map[string]string `yaml:"model_aliases"`
 }
 
 // ProfileWorkdir defines a workdir from a profile.
 type ProfileWorkdir struct {
-	Path       string `json:"path"`                  // host path
-	Mode       string `json:"mode,omitempty"`        // "copy" or "rw"
-	Mount      string `json:"mount,omitempty"`       // optional custom mount point
-	CopyStrict bool   `json:"copy_strict,omitempty"` // strip git history on :copy (fresh baseline) instead of preserving it
+	Path       string `yaml:"path" json:"path"`               // host path
+	Mode       string `yaml:"mode" json:"mode,omitempty"`     // "copy" or "rw"
+	Mount      string `yaml:"mount" json:"mount,omitempty"`   // optional custom mount point
+	CopyStrict bool   `yaml:"-" json:"copy_strict,omitempty"` // strip git history on :copy (fresh baseline) instead of preserving it
 }
 
 // ProfileDir defines an auxiliary directory from a profile.
 type ProfileDir struct {
-	Path  string `json:"path"`            // host path
-	Mode  string `json:"mode,omitempty"`  // "rw", "copy", or "" (read-only)
-	Mount string `json:"mount,omitempty"` // optional custom mount point
+	Path  string `yaml:"path" json:"path"`             // host path
+	Mode  string `yaml:"mode" json:"mode,omitempty"`   // "rw", "copy", or "" (read-only)
+	Mount string `yaml:"mount" json:"mount,omitempty"` // optional custom mount point
 }
 
 // MergedConfig holds the result of merging baked-in defaults with a profile.
@@ -58,6 +60,7 @@ type MergedConfig struct {
 	Directories        []ProfileDir      `json:"directories,omitempty"`          // additive across chain
 	Resources          *ResourceLimits   `json:"resources,omitempty"`            // from per-field merge across chain
 	Network            *NetworkConfig    `json:"network,omitempty"`              // isolated overrides (last wins), allow additive
+	Guard              *GuardConfig      `json:"guard,omitempty"`                // mode overrides (non-empty wins), commands additive
 	Mounts             []string          `json:"mounts,omitempty"`               // additive across chain (host:container[:ro])
 	AgentArgs          map[string]string `json:"agent_args,omitempty"`           // merged across chain (map merge, later wins)
 	AgentFiles         *AgentFilesConfig `json:"agent_files,omitempty"`          // replacement semantics (child replaces parent)
@@ -66,6 +69,13 @@ type MergedConfig struct {
 	Setup              []string          `json:"setup,omitempty"`                // additive across chain (Docker only)
 	AutoCommitInterval int               `json:"auto_commit_interval,omitempty"` // profile overrides default
 	Isolation          string            `json:"isolation,omitempty"`            // last non-empty wins across chain
+	ProvenanceHeaders  bool              `json:"provenance_headers,omitempty"`   // last explicit setting wins across chain
+	Timezone           string            `json:"timezone,omitempty"`             // last non-empty wins across chain
+	Locale             string            `json:"locale,omitempty"`               // last non-empty wins across chain
+	TTL                string            `json:"ttl,omitempty"`                  // last non-empty wins across chain
+	FakeTime           string            `json:"faketime,omitempty"`             // last non-empty wins across chain
+	PreLaunch          string            `json:"pre_launch,omitempty"`           // last non-empty wins across chain
+	ModelAliases       map[string]string `json:"model_aliases,omitempty"`        // merged across chain (map merge, later wins)
 }
 
 // ValidateProfileName validates a profile name.
@@ -86,59 +96,59 @@ func ValidateProfileName(name string) error {
 	return nil
 }
 
-// ProfileExists checks whether a profile directory with a config.yaml exists.
+// ProfileExists checks whether a profile directory with a config.yaml exists,
+// the user's own or a system-wide one.
 func ProfileExists(layout Layout, name string) bool {
-	dir := layout.ProfileDir(name)
-	info, err := os.Stat(dir)
-	if err != nil || !info.IsDir() {
-		return false
-	}
-	_, err = os.Stat(filepath.Join(dir, "config.yaml"))
-	return err == nil
+	return hasProfileConfig(ProfileSourceDir(layout, name))
 }
 
 // ProfileHasDockerfile checks whether a profile has a Dockerfile.
 func ProfileHasDockerfile(layout Layout, name string) bool {
-	_, err := os.Stat(filepath.Join(layout.ProfileDir(name), "Dockerfile"))
+	_, err := os.Stat(filepath.Join(ProfileSourceDir(layout, name), "Dockerfile"))
 	return err == nil
 }
 
-// ListProfiles returns the names of all user profiles.
+// ListProfiles returns the names of all profiles: the user's own and the
+// system-wide ones.
 func ListProfiles(layout Layout) ([]string, error) {
-	profilesDir := layout.ProfilesDir()
-
-	entries, err := os.ReadDir(profilesDir)
-	if err != nil {
-		if os.IsNotExist(err) {
-			return nil, nil
-		}
-		return nil, fmt.Errorf("read profiles directory: %w", err)
-	}
-
-	var names []string
-	for _, e := range entries {
-		if !e.IsDir() {
+	seen := map[string]bool{}
+	for _, profilesDir := range []string{layout.ProfilesDir(), layout.SystemProfilesDir()} {
+		if profilesDir == "" {
 			continue
 		}
-		// Only include directories that have a config.yaml
-		if _, statErr := os.Stat(filepath.Join(profilesDir, e.Name(), "config.yaml")); statErr == nil {
-			names = append(names, e.Name())
+		entries, err := os.ReadDir(profilesDir)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, fmt.Errorf("read profiles directory: %w", err)
+		}
+		for _, e := range entries {
+			// Only include directories that have a config.yaml
+			if e.IsDir() && hasProfileConfig(filepath.Join(profilesDir, e.Name())) {
+				seen[e.Name()] = true
+			}
 		}
 	}
-	sort.Strings(names)
+
+	names := slices.Sorted(maps.Keys(seen))
+	if len(names) == 0 {
+		return nil, nil
+	}
 	return names, nil
 }
 
-// profileOnlyHandler handles a profile-only YAML key (backend/workdir/directories)
+// profileOnlyHandler handles a profile-only YAML key (backend/workdir/directories/model_aliases)
 // — the keys ProfileConfig adds on top of the embedded YoloaiConfig. The common
 // keys are dispatched through yoloaiConfigHandlers in LoadProfile (IC2 fold).
 type profileOnlyHandler func(cfg *ProfileConfig, val *yaml.Node, env map[string]string) error
 
-// profileOnlyHandlers maps the three profile-only top-level keys to their handlers.
+// profileOnlyHandlers maps the profile-only top-level keys to their handlers.
 var profileOnlyHandlers = map[string]profileOnlyHandler{
-	"backend":     handleProfileBackend,
-	"workdir":     handleProfileWorkdir,
-	"directories": handleProfileDirectories,
+	"backend":       handleProfileBackend,
+	"workdir":       handleProfileWorkdir,
+	"directories":   handleProfileDirectories,
+	"model_aliases": handleProfileModelAliases,
 }
 
 func handleProfileBackend(cfg *ProfileConfig, val *yaml.Node, env map[string]string) error {
@@ -203,15 +213,29 @@ func handleProfileDirectories(cfg *ProfileConfig, val *yaml.Node, env map[string
 	return nil
 }
 
+func handleProfileModelAliases(cfg *ProfileConfig, val *yaml.Node, env map[string]string) error {
+	if val.Kind != yaml.MappingNode {
+		return nil
+	}
+	aliases, err := parseModelAliases(val, env)
+	if err != nil {
+		return err
+	}
+	cfg.ModelAliases = mergeMapFields(cfg.ModelAliases, aliases)
+	return nil
+}
+
 // LoadProfile reads and parses a profile's config.yaml file.
 // The layout's threaded env snapshot is used for ${VAR} expansion in config values.
 //
 // Common keys are dispatched through the shared yoloaiConfigHandlers (onto the
-// embedded YoloaiConfig); the three profile-only keys go through
-// profileOnlyHandlers (IC2 fold). Unknown keys are silently ignored — LoadProfile
-// does not validate top-level keys.
+// embedded YoloaiConfig); the profile-only keys go through
+// profileOnlyHandlers (IC2 fold). The known keys are checked against
+// profileSchema first, so a wrong type is an error with its line and column;
+// unknown keys are silently ignored, so a profile written for a newer yoloai
+// still loads.
 func LoadProfile(layout Layout, name string) (*ProfileConfig, error) {
-	dir := layout.ProfileDir(name)
+	dir := ProfileSourceDir(layout, name)
 	path := filepath.Join(dir, "config.yaml")
 
 	data, err := os.ReadFile(path) //nolint:gosec // G304: path is from profile directory
@@ -238,6 +262,10 @@ func LoadProfile(layout Layout, name string) (*ProfileConfig, error) {
 		return cfg, nil
 	}
 
+	if err := validateConfigNode(root, profileSchema, path, false); err != nil {
+		return nil, err
+	}
+
 	interpEnv := layout.Env().EnvForConfigInterpolation()
 	for i := 0; i < len(root.Content)-1; i += 2 {
 		key := root.Content[i].Value
@@ -265,6 +293,23 @@ func LoadProfile(layout Layout, name string) (*ProfileConfig, error) {
 // profile image, which ProfileImageTag scopes.
 const BaseImage = "yoloai-base"
 
+// ValidateImageRef checks an image name a user picks for `yoloai commit` or
+// `new --image`. The bare-local "yoloai-" names are refused: they belong to
+// BaseImage and the profile images, which a rebuild overwrites and
+// `prune --images` deletes.
+func ValidateImageRef(imageRef string) error {
+	if imageRef == "" {
+		return yoerrors.NewUsageError("an image name is required")
+	}
+	if strings.ContainsAny(imageRef, " \t\n") {
+		return yoerrors.NewUsageError("invalid image name %q", imageRef)
+	}
+	if !strings.Contains(imageRef, "/") && strings.HasPrefix(imageRef, "yoloai-") {
+		return yoerrors.NewUsageError("image name %q is reserved for yoloai's own images; pick another name", imageRef)
+	}
+	return nil
+}
+
 // ProfileImageTag returns the principal-scoped Docker image tag for a
 // principal-authored profile image: "yoloai-<principal>-<profileName>". A
 // principal-authored build artifact needs a principal-scoped tag (see
@@ -347,7 +392,7 @@ type legacyProfileConfig struct {
 // that exists yet fails to parse is a hard error: silently treating the user's malformed profile
 // as "extends base" would discard what they configured without telling them.
 func loadProfileLegacy(layout Layout, name string) (legacyProfileConfig, error) {
-	path := filepath.Join(layout.ProfileDir(name), "config.yaml")
+	path := filepath.Join(ProfileSourceDir(layout, name), "config.yaml")
 	data, err := os.ReadFile(path) //nolint:gosec // G304: path is from profile directory
 	if err != nil {
 		if os.IsNotExist(err) {
@@ -397,9 +442,17 @@ func mergedConfigFromBase(base *YoloaiConfig) *MergedConfig {
 		ContainerBackend:   base.ContainerBackend,
 		TartImage:          base.TartImage,
 		Isolation:          base.Isolation,
+		Timezone:           base.Timezone,
+		Locale:             base.Locale,
+		TTL:                base.TTL,
+		FakeTime:           base.FakeTime,
+		PreLaunch:          base.PreLaunch,
 		AgentFiles:         base.AgentFiles,
 		AutoCommitInterval: base.AutoCommitInterval,
 	}
+	if base.ProvenanceHeaders != nil {
+		merged.ProvenanceHeaders = *base.ProvenanceHeaders
+	}
 	if len(base.Env) > 0 {
 		merged.Env = make(map[string]string, len(base.Env))
 		maps.Copy(merged.Env, base.Env)
@@ -410,8 +463,10 @@ func mergedConfigFromBase(base *YoloaiConfig) *MergedConfig {
 	}
 	if base.Resources != nil {
 		merged.Resources = &ResourceLimits{
-			CPUs:   base.Resources.CPUs,
-			Memory: base.Resources.Memory,
+			CPUs:       base.Resources.CPUs,
+			Memory:     base.Resources.Memory,
+			Disk:       base.Resources.Disk,
+			AgentState: base.Resources.AgentState,
 		}
 	}
 	if base.Network != nil {
@@ -421,6 +476,13 @@ func mergedConfigFromBase(base *YoloaiConfig) *MergedConfig {
 			copy(merged.Network.Allow, base.Network.Allow)
 		}
 	}
+	if base.Guard != nil {
+		merged.Guard = &GuardConfig{Mode: base.Guard.Mode}
+		if len(base.Guard.Commands) > 0 {
+			merged.Guard.Commands = make([]string, len(base.Guard.Commands))
+			copy(merged.Guard.Commands, base.Guard.Commands)
+		}
+	}
 	if len(base.Mounts) > 0 {
 		merged.Mounts = make([]string, len(base.Mounts))
 		copy(merged.Mounts, base.Mounts)
@@ -451,6 +513,11 @@ func applyProfileToMerged(merged *MergedConfig, profile *ProfileConfig) {
 	merged.ContainerBackend = mergeStringField(merged.ContainerBackend, profile.ContainerBackend)
 	merged.TartImage = mergeStringField(merged.TartImage, profile.TartImage)
 	merged.Isolation = mergeStringField(merged.Isolation, profile.Isolation)
+	merged.Timezone = mergeStringField(merged.Timezone, profile.Timezone)
+	merged.Locale = mergeStringField(merged.Locale, profile.Locale)
+	merged.TTL = mergeStringField(merged.TTL, profile.TTL)
+	merged.FakeTime = mergeStringField(merged.FakeTime, profile.FakeTime)
+	merged.PreLaunch = mergeStringField(merged.PreLaunch, profile.PreLaunch)
 
 	// AgentFiles: replacement semantics
 	if profile.AgentFiles != nil {
@@ -460,6 +527,10 @@ func applyProfileToMerged(merged *MergedConfig, profile *ProfileConfig) {
 	if profile.AutoCommitInterval > 0 {
 		merged.AutoCommitInterval = profile.AutoCommitInterval
 	}
+	// ProvenanceHeaders: an explicit setting (true or false) wins
+	if profile.ProvenanceHeaders != nil {
+		merged.ProvenanceHeaders = *profile.ProvenanceHeaders
+	}
 	// Workdir: child wins over parent
 	if profile.Workdir != nil {
 		merged.Workdir = profile.Workdir
@@ -483,6 +554,8 @@ func applyProfileToMerged(merged *MergedConfig, profile *ProfileConfig) {
 		}
 		merged.Resources.CPUs = mergeStringField(merged.Resources.CPUs, profile.Resources.CPUs)
 		merged.Resources.Memory = mergeStringField(merged.Resources.Memory, profile.Resources.Memory)
+		merged.Resources.Disk = mergeStringField(merged.Resources.Disk, profile.Resources.Disk)
+		merged.Resources.AgentState = mergeStringField(merged.Resources.AgentState, profile.Resources.AgentState)
 	}
 
 	// Network: isolated overrides (last wins), allow is additive
@@ -493,9 +566,18 @@ func applyProfileToMerged(merged *MergedConfig, profile *ProfileConfig) {
 		merged.Network.Isolated = profile.Network.Isolated
 		merged.Network.Allow = append(merged.Network.Allow, profile.Network.Allow...)
 	}
+
+	// Guard: mode overrides (non-empty wins), commands are additive
+	if profile.Guard != nil {
+		if merged.Guard == nil {
+			merged.Guard = &GuardConfig{}
+		}
+		merged.Guard.Mode = mergeStringField(merged.Guard.Mode, profile.Guard.Mode)
+		merged.Guard.Commands = append(merged.Guard.Commands, profile.Guard.Commands...)
+	}
 }
 
-// applyProfileMaps merges profile map fields (Env, AgentArgs) into merged.
+// applyProfileMaps merges profile map fields (Env, AgentArgs, ModelAliases) into merged.
 func applyProfileMaps(merged *MergedConfig, profile *ProfileConfig) {
 	if len(profile.Env) > 0 {
 		if merged.Env == nil {
@@ -509,6 +591,12 @@ func applyProfileMaps(merged *MergedConfig, profile *ProfileConfig) {
 		}
 		maps.Copy(merged.AgentArgs, profile.AgentArgs)
 	}
+	if len(profile.ModelAliases) > 0 {
+		if merged.ModelAliases == nil {
+			merged.ModelAliases = make(map[string]string)
+		}
+		maps.Copy(merged.ModelAliases, profile.ModelAliases)
+	}
 }
 
 // MergeProfileChain merges base config with each profile in the chain.
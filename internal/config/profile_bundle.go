@@ -0,0 +1,290 @@
+package config
+
+// ABOUTME: Profile archives: one profile directory (config, Dockerfile and any
+// ABOUTME: files it builds from) as a gzipped tar, with secrets left out, and its import.
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/kstenerud/yoloai/internal/fileutil"
+	"github.com/kstenerud/yoloai/yoerrors"
+)
+
+// ProfileArchiveManifestName is the first entry of a profile archive,
+// identifying it as one and naming the profile.
+const ProfileArchiveManifestName = "yoloai-profile.json"
+
+// profileArchiveFormat is the archive layout version; bump it when the layout
+// changes incompatibly.
+const profileArchiveFormat = 1
+
+// profileArchiveManifest is the content of ProfileArchiveManifestName.
+type profileArchiveManifest struct {
+	Format        int       `json:"format"`
+	SchemaVersion int       `json:"schema_version"`
+	Name          string    `json:"name"`
+	Created       time.Time `json:"created"`
+}
+
+// ProfileImport reports what ImportProfile installed.
+type ProfileImport struct {
+	Name     string   // the profile's name
+	Files    []string // files written, relative to the profile directory
+	Replaced bool     // a profile of that name was replaced
+}
+
+// ExportProfile writes the named profile's directory to w as a gzipped tar:
+// config.yaml, the Dockerfile, and every other file in it the Dockerfile may
+// COPY. A system-wide profile exports like the user's own. Secrets are left
+// out and reported as ExportBundle does; files describing this machine's
+// build state are skipped, so the importer's first use builds the image.
+func ExportProfile(layout Layout, name string, w io.Writer) (*BundleExport, error) {
+	if err := ValidateProfileName(name); err != nil {
+		return nil, err
+	}
+	if !ProfileExists(layout, name) {
+		return nil, yoerrors.NewUsageError("profile %q does not exist", name)
+	}
+	dir := ProfileSourceDir(layout, name)
+
+	var paths []string
+	err := filepath.WalkDir(dir, func(p string, e fs.DirEntry, err error) error {
+		switch {
+		case err != nil:
+			return err
+		case e.IsDir() || !e.Type().IsRegular() || bundleSkipNames[e.Name()]:
+			return nil
+		}
+		paths = append(paths, p)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("export profile %s: %w", name, err)
+	}
+	sort.Strings(paths)
+
+	result := &BundleExport{}
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+	manifest, err := json.Marshal(profileArchiveManifest{
+		Format: profileArchiveFormat, SchemaVersion: LibrarySchemaVersion, Name: name, Created: time.Now().UTC(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := writeBundleEntry(tw, ProfileArchiveManifestName, manifest, 0o644); err != nil {
+		return nil, err
+	}
+	for _, p := range paths {
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return nil, err
+		}
+		rel = filepath.ToSlash(rel)
+		data, err := os.ReadFile(p) //nolint:gosec // G304: walking the profile's own directory
+		if err != nil {
+			return nil, fmt.Errorf("export %s: %w", p, err)
+		}
+		data, omitted, keep := scrubBundleFile(rel, data)
+		result.Omitted = append(result.Omitted, omitted...)
+		if !keep {
+			continue
+		}
+		info, err := os.Stat(p)
+		if err != nil {
+			return nil, err
+		}
+		if err := writeBundleEntry(tw, rel, data, info.Mode().Perm()); err != nil {
+			return nil, err
+		}
+		result.Files = append(result.Files, rel)
+	}
+	if err := tw.Close(); err != nil {
+		return nil, fmt.Errorf("write profile archive: %w", err)
+	}
+	if err := gz.Close(); err != nil {
+		return nil, fmt.Errorf("write profile archive: %w", err)
+	}
+	return result, nil
+}
+
+// ImportProfile installs a profile archive written by ExportProfile, under
+// name, or under the name it was exported with when name is "". The whole
+// archive is read and checked before anything is written. A profile of the
+// same name is a conflict unless overwrite is set, in which case it is
+// replaced whole; a system-wide profile of that name is shadowed, as Create
+// does.
+func ImportProfile(layout Layout, r io.Reader, name string, overwrite bool) (*ProfileImport, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, yoerrors.NewUsageError("not a yoloai profile archive: %v", err)
+	}
+	var (
+		files    []profileFile
+		manifest *profileArchiveManifest
+		total    int64
+	)
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, yoerrors.NewUsageError("not a yoloai profile archive: %v", err)
+		}
+		if manifest == nil {
+			if hdr.Name != ProfileArchiveManifestName {
+				return nil, yoerrors.NewUsageError("not a yoloai profile archive: it doesn't start with %s", ProfileArchiveManifestName)
+			}
+			if manifest, err = readProfileArchiveManifest(tr); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			return nil, yoerrors.NewUsageError("profile archive entry %s is not a regular file", hdr.Name)
+		}
+		if !validProfileArchivePath(hdr.Name) {
+			return nil, yoerrors.NewUsageError("profile archive entry %s is not a path inside a profile", hdr.Name)
+		}
+		total += hdr.Size
+		if hdr.Size > bundleMaxFileBytes || total > bundleMaxTotalBytes {
+			return nil, yoerrors.NewUsageError("profile archive entry %s is too large", hdr.Name)
+		}
+		data, err := io.ReadAll(io.LimitReader(tr, bundleMaxFileBytes+1))
+		if err != nil {
+			return nil, fmt.Errorf("read profile archive entry %s: %w", hdr.Name, err)
+		}
+		files = append(files, profileFile{rel: hdr.Name, data: data, perm: fs.FileMode(hdr.Mode).Perm() & 0o755}) //nolint:gosec // G115: tar modes fit
+	}
+	if manifest == nil {
+		return nil, yoerrors.NewUsageError("not a yoloai profile archive: it is empty")
+	}
+	if !hasProfileFile(files, "config.yaml") {
+		return nil, yoerrors.NewUsageError("profile archive has no config.yaml")
+	}
+
+	if name == "" {
+		name = manifest.Name
+	}
+	return installProfile(layout, name, files, overwrite)
+}
+
+// profileFile is one file of a profile about to be installed.
+type profileFile struct {
+	rel  string // slash-separated, relative to the profile directory
+	data []byte
+	perm fs.FileMode
+}
+
+func hasProfileFile(files []profileFile, rel string) bool {
+	return slices.ContainsFunc(files, func(f profileFile) bool { return f.rel == rel })
+}
+
+// InstallProfileFiles installs a generated profile: files maps each path,
+// relative to the profile directory, to its content. It is staged and swapped
+// in whole like an import, and an existing profile of that name is replaced
+// only with overwrite.
+func InstallProfileFiles(layout Layout, name string, files map[string][]byte, overwrite bool) (*ProfileImport, error) {
+	list := make([]profileFile, 0, len(files))
+	for rel, data := range files {
+		list = append(list, profileFile{rel: rel, data: data, perm: 0o600})
+	}
+	slices.SortFunc(list, func(a, b profileFile) int { return strings.Compare(a.rel, b.rel) })
+	return installProfile(layout, name, list, overwrite)
+}
+
+// installProfile writes files as profile name. A profile of the same name is
+// a conflict unless overwrite is set, in which case it is replaced whole.
+func installProfile(layout Layout, name string, files []profileFile, overwrite bool) (*ProfileImport, error) {
+	if err := ValidateProfileName(name); err != nil {
+		return nil, err
+	}
+	dest := layout.ProfileDir(name)
+	replaced := hasProfileConfig(dest)
+	if replaced && !overwrite {
+		return nil, yoerrors.NewUsageError("profile %q already exists; re-run with --force to replace it, or install it under another name with --name", name)
+	}
+
+	// Stage the profile beside its destination and swap it in, so a failed
+	// install never leaves a half-written profile behind.
+	if err := fileutil.MkdirAll(layout.ProfilesDir(), 0o750); err != nil {
+		return nil, err
+	}
+	staging, err := os.MkdirTemp(layout.ProfilesDir(), "."+name+".import-")
+	if err != nil {
+		return nil, fmt.Errorf("stage profile %s: %w", name, err)
+	}
+	defer os.RemoveAll(staging) //nolint:errcheck // best-effort cleanup; empty once swapped in
+	result := &ProfileImport{Name: name, Replaced: replaced}
+	for _, f := range files {
+		p := filepath.Join(staging, filepath.FromSlash(f.rel))
+		if err := fileutil.MkdirAll(filepath.Dir(p), 0o750); err != nil {
+			return nil, err
+		}
+		if err := fileutil.WriteFile(p, f.data, f.perm); err != nil {
+			return nil, fmt.Errorf("write %s: %w", f.rel, err)
+		}
+		if f.rel != ProfileOriginFileName {
+			result.Files = append(result.Files, f.rel)
+		}
+	}
+	if err := os.Chmod(staging, 0o750); err != nil { //nolint:gosec // G302: profile dirs are 0750 like Create makes them
+		return nil, err
+	}
+	old := ""
+	if _, err := os.Stat(dest); err == nil {
+		old = staging + ".old"
+		if err := os.Rename(dest, old); err != nil {
+			return nil, fmt.Errorf("replace profile %s: %w", name, err)
+		}
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+	if err := os.Rename(staging, dest); err != nil {
+		if old != "" {
+			os.Rename(old, dest) //nolint:errcheck,gosec // best-effort restore of the replaced profile
+		}
+		return nil, fmt.Errorf("install profile %s: %w", name, err)
+	}
+	if old != "" {
+		os.RemoveAll(old) //nolint:errcheck,gosec // best-effort cleanup
+	}
+	return result, nil
+}
+
+func readProfileArchiveManifest(r io.Reader) (*profileArchiveManifest, error) {
+	var m profileArchiveManifest
+	if err := json.NewDecoder(io.LimitReader(r, 1<<16)).Decode(&m); err != nil {
+		return nil, yoerrors.NewUsageError("not a yoloai profile archive: unreadable %s: %v", ProfileArchiveManifestName, err)
+	}
+	if m.Format != profileArchiveFormat {
+		return nil, yoerrors.NewUsageError("profile archive format %d is not supported (this yoloai reads format %d)", m.Format, profileArchiveFormat)
+	}
+	if m.SchemaVersion > LibrarySchemaVersion {
+		return nil, yoerrors.NewUsageError("the profile archive comes from a newer yoloai (schema %d, this one is %d); upgrade yoloai first", m.SchemaVersion, LibrarySchemaVersion)
+	}
+	return &m, nil
+}
+
+// validProfileArchivePath accepts a clean relative path inside the profile
+// directory. A name that isn't already clean (holding "..", ".", or doubled
+// slashes) is refused outright.
+func validProfileArchivePath(name string) bool {
+	return name != "" && name == path.Clean(name) && !path.IsAbs(name) &&
+		!strings.Contains(name, "\\") && name != ".." && !strings.HasPrefix(name, "../")
+}
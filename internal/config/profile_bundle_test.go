@@ -0,0 +1,128 @@
+package config
+
+// ABOUTME: Tests for profile archives: a round trip with secrets left out,
+// ABOUTME: renaming and replacing on import, and import's path checks.
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kstenerud/yoloai/yoerrors"
+)
+
+func TestProfileArchive_RoundTrip(t *testing.T) {
+	src := NewLayout(filepath.Join(t.TempDir(), ".yoloai"))
+	writeLayoutFile(t, src, "profiles/go/config.yaml", "agent: codex\nenv:\n  GH_TOKEN: ghp_tokenlookingvalue\n  GOFLAGS: -mod=mod\n")
+	writeLayoutFile(t, src, "profiles/go/Dockerfile", "FROM yoloai-base\nCOPY scripts/setup.sh /tmp/\n")
+	writeLayoutFile(t, src, "profiles/go/scripts/setup.sh", "#!/bin/sh\n")
+	writeLayoutFile(t, src, "profiles/go/.npmrc", "//registry/:_authToken=x\n")
+	writeLayoutFile(t, src, "profiles/go/.last-build-checksum", "abc\n")
+
+	var buf bytes.Buffer
+	exp, err := ExportProfile(src, "go", &buf)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Dockerfile", "config.yaml", "scripts/setup.sh"}, exp.Files)
+	assert.ElementsMatch(t, []BundleOmission{
+		{Path: "config.yaml", Key: "env.GH_TOKEN", Reason: "secret value"},
+		{Path: ".npmrc", Reason: "credential file"},
+	}, exp.Omitted)
+
+	dst := NewLayout(filepath.Join(t.TempDir(), ".yoloai"))
+	imp, err := ImportProfile(dst, bytes.NewReader(buf.Bytes()), "", false)
+	require.NoError(t, err)
+	assert.Equal(t, "go", imp.Name)
+	assert.False(t, imp.Replaced)
+	assert.Len(t, imp.Files, 3)
+	assert.Equal(t, "#!/bin/sh\n", readLayoutFile(t, dst, "profiles/go/scripts/setup.sh"))
+	assert.NotContains(t, readLayoutFile(t, dst, "profiles/go/config.yaml"), "ghp_")
+	p, err := LoadProfile(dst, "go")
+	require.NoError(t, err)
+	assert.Equal(t, "codex", p.Agent)
+	assert.True(t, ProfileHasDockerfile(dst, "go"))
+
+	names, err := ListProfiles(dst)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"go"}, names, "no staging directory is left behind")
+
+	// Under another name.
+	imp, err = ImportProfile(dst, bytes.NewReader(buf.Bytes()), "go-copy", false)
+	require.NoError(t, err)
+	assert.Equal(t, "go-copy", imp.Name)
+	assert.True(t, ProfileExists(dst, "go-copy"))
+}
+
+func TestImportProfile_Conflict(t *testing.T) {
+	src := NewLayout(filepath.Join(t.TempDir(), ".yoloai"))
+	writeLayoutFile(t, src, "profiles/go/config.yaml", "agent: codex\n")
+	var buf bytes.Buffer
+	_, err := ExportProfile(src, "go", &buf)
+	require.NoError(t, err)
+
+	dst := NewLayout(filepath.Join(t.TempDir(), ".yoloai"))
+	writeLayoutFile(t, dst, "profiles/go/config.yaml", "agent: gemini\n")
+	writeLayoutFile(t, dst, "profiles/go/stale.txt", "old\n")
+
+	var usage *yoerrors.UsageError
+	_, err = ImportProfile(dst, bytes.NewReader(buf.Bytes()), "", false)
+	require.ErrorAs(t, err, &usage)
+	assert.Equal(t, "agent: gemini\n", readLayoutFile(t, dst, "profiles/go/config.yaml"))
+
+	imp, err := ImportProfile(dst, bytes.NewReader(buf.Bytes()), "", true)
+	require.NoError(t, err)
+	assert.True(t, imp.Replaced)
+	assert.Equal(t, "agent: codex\n", readLayoutFile(t, dst, "profiles/go/config.yaml"))
+	assert.NoFileExists(t, filepath.Join(dst.ProfileDir("go"), "stale.txt"), "a replaced profile is replaced whole")
+	entries, err := os.ReadDir(dst.ProfilesDir())
+	require.NoError(t, err)
+	assert.Len(t, entries, 1)
+}
+
+func TestExportProfile_Missing(t *testing.T) {
+	layout := NewLayout(filepath.Join(t.TempDir(), ".yoloai"))
+	var usage *yoerrors.UsageError
+	_, err := ExportProfile(layout, "nope", &bytes.Buffer{})
+	assert.ErrorAs(t, err, &usage)
+}
+
+func TestImportProfile_RejectsForeignArchives(t *testing.T) {
+	dst := NewLayout(filepath.Join(t.TempDir(), ".yoloai"))
+	archiveOf := func(names ...string) *bytes.Buffer {
+		var buf bytes.Buffer
+		gz := gzip.NewWriter(&buf)
+		tw := tar.NewWriter(gz)
+		for _, n := range names {
+			data := []byte("x")
+			if n == ProfileArchiveManifestName {
+				data = []byte(`{"format":1,"schema_version":1,"name":"p"}`)
+			}
+			require.NoError(t, writeBundleEntry(tw, n, data, 0o644))
+		}
+		require.NoError(t, tw.Close())
+		require.NoError(t, gz.Close())
+		return &buf
+	}
+
+	var usage *yoerrors.UsageError
+	for _, names := range [][]string{
+		{"config.yaml"},
+		{ProfileArchiveManifestName},
+		{ProfileArchiveManifestName, "config.yaml", "../escape"},
+		{ProfileArchiveManifestName, "config.yaml", "a/../../escape"},
+		{ProfileArchiveManifestName, "config.yaml", "/etc/passwd"},
+	} {
+		_, err := ImportProfile(dst, archiveOf(names...), "", false)
+		assert.ErrorAs(t, err, &usage, "%v", names)
+	}
+	_, err := ImportProfile(dst, bytes.NewReader([]byte("not gzip")), "", false)
+	assert.ErrorAs(t, err, &usage)
+	_, err = ImportProfile(dst, archiveOf(ProfileArchiveManifestName, "config.yaml"), "../x", false)
+	assert.ErrorAs(t, err, &usage, "the name is validated")
+	assert.NoDirExists(t, dst.DataDir)
+}
@@ -0,0 +1,163 @@
+package config
+
+// ABOUTME: Profiles installed from a git repository: the origin record kept in
+// ABOUTME: the profile directory, installing from a checkout, and local-edit checks.
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/kstenerud/yoloai/yoerrors"
+)
+
+// ProfileOriginFileName records, inside a profile directory, where a profile
+// added from a git repository came from. It is machine state, so exports skip
+// it (see bundleSkipNames).
+const ProfileOriginFileName = ".yoloai-origin.json"
+
+// ProfileOrigin is where a profile was added from and what it held then.
+type ProfileOrigin struct {
+	URL     string    `json:"url"`              // repository, as given to git clone
+	Subdir  string    `json:"subdir,omitempty"` // profile directory in the repository; "" = its root
+	Ref     string    `json:"ref,omitempty"`    // branch, tag or commit followed; "" = the default branch
+	Commit  string    `json:"commit"`           // commit installed
+	Updated time.Time `json:"updated"`          // when it was installed
+	// Files maps each installed file, relative to the profile directory, to
+	// the SHA-256 of its content, so later edits can be detected.
+	Files map[string]string `json:"files"`
+}
+
+// Source is the origin in the form `profile add` takes: URL//subdir.
+func (o *ProfileOrigin) Source() string {
+	if o.Subdir == "" {
+		return o.URL
+	}
+	return o.URL + "//" + o.Subdir
+}
+
+// LoadProfileOrigin returns the origin of the user's profile name, or nil when
+// it wasn't added from a repository.
+func LoadProfileOrigin(layout Layout, name string) (*ProfileOrigin, error) {
+	data, err := os.ReadFile(filepath.Join(layout.ProfileDir(name), ProfileOriginFileName)) //nolint:gosec // G304: path under the profiles dir
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var o ProfileOrigin
+	if err := json.Unmarshal(data, &o); err != nil {
+		return nil, fmt.Errorf("read origin of profile %s: %w", name, err)
+	}
+	return &o, nil
+}
+
+// InstallProfileFromDir installs the profile directory dir (a subdirectory of
+// a checkout) as profile name, recording origin with the installed files'
+// hashes. Version-control metadata, symlinks and build state are left out;
+// dir must hold a config.yaml. An existing profile of the same name is
+// replaced whole only with overwrite.
+func InstallProfileFromDir(layout Layout, name, dir string, origin ProfileOrigin, overwrite bool) (*ProfileImport, error) {
+	var (
+		files []profileFile
+		total int64
+	)
+	err := filepath.WalkDir(dir, func(p string, e fs.DirEntry, err error) error {
+		switch {
+		case err != nil:
+			return err
+		case e.IsDir() && e.Name() == ".git" && p != dir:
+			return filepath.SkipDir
+		case e.IsDir() || !e.Type().IsRegular() || bundleSkipNames[e.Name()]:
+			return nil
+		}
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		info, err := e.Info()
+		if err != nil {
+			return err
+		}
+		total += info.Size()
+		if info.Size() > bundleMaxFileBytes || total > bundleMaxTotalBytes {
+			return yoerrors.NewUsageError("profile file %s is too large", rel)
+		}
+		data, err := os.ReadFile(p) //nolint:gosec // G304: walking the checkout
+		if err != nil {
+			return err
+		}
+		files = append(files, profileFile{rel: filepath.ToSlash(rel), data: data, perm: info.Mode().Perm() & 0o755})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !hasProfileFile(files, "config.yaml") {
+		return nil, yoerrors.NewUsageError("%s is not a yoloai profile: it has no config.yaml", origin.Source())
+	}
+
+	origin.Files = make(map[string]string, len(files))
+	for _, f := range files {
+		origin.Files[f.rel] = fileHash(f.data)
+	}
+	data, err := json.MarshalIndent(origin, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	files = append(files, profileFile{rel: ProfileOriginFileName, data: append(data, '\n'), perm: 0o644})
+	return installProfile(layout, name, files, overwrite)
+}
+
+// ProfileLocalChanges lists the files of profile name that differ from what
+// origin installed: edited, added or removed. Sorted; empty when none.
+func ProfileLocalChanges(layout Layout, name string, origin *ProfileOrigin) ([]string, error) {
+	dir := layout.ProfileDir(name)
+	seen := map[string]bool{}
+	var changed []string
+	err := filepath.WalkDir(dir, func(p string, e fs.DirEntry, err error) error {
+		switch {
+		case err != nil:
+			return err
+		case e.IsDir() || !e.Type().IsRegular() || bundleSkipNames[e.Name()]:
+			return nil
+		}
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		seen[rel] = true
+		data, err := os.ReadFile(p) //nolint:gosec // G304: walking the profile's own directory
+		if err != nil {
+			return err
+		}
+		if want, ok := origin.Files[rel]; !ok || want != fileHash(data) {
+			changed = append(changed, rel)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	for rel := range origin.Files {
+		if !seen[rel] {
+			changed = append(changed, rel)
+		}
+	}
+	sort.Strings(changed)
+	return changed, nil
+}
+
+func fileHash(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
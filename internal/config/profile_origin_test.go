@@ -0,0 +1,55 @@
+package config
+
+// ABOUTME: Tests for profiles installed from a checkout: the origin record,
+// ABOUTME: local-edit detection, and exports leaving the record out.
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInstallProfileFromDir(t *testing.T) {
+	checkout := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(checkout, ".git"), 0o750))
+	require.NoError(t, os.WriteFile(filepath.Join(checkout, ".git", "HEAD"), []byte("ref\n"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(checkout, "config.yaml"), []byte("agent: codex\n"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(checkout, "setup.sh"), []byte("#!/bin/sh\n"), 0o700))
+	require.NoError(t, os.Symlink("/etc/passwd", filepath.Join(checkout, "link")))
+
+	layout := NewLayout(filepath.Join(t.TempDir(), ".yoloai"))
+	imp, err := InstallProfileFromDir(layout, "go", checkout, ProfileOrigin{URL: "https://x/p", Subdir: "go", Commit: "abc"}, false)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"config.yaml", "setup.sh"}, imp.Files, "no .git, no symlinks, no origin record")
+
+	origin, err := LoadProfileOrigin(layout, "go")
+	require.NoError(t, err)
+	require.NotNil(t, origin)
+	assert.Equal(t, "https://x/p//go", origin.Source())
+	assert.Equal(t, "abc", origin.Commit)
+	assert.Len(t, origin.Files, 2)
+
+	changed, err := ProfileLocalChanges(layout, "go", origin)
+	require.NoError(t, err)
+	assert.Empty(t, changed)
+
+	writeLayoutFile(t, layout, "profiles/go/config.yaml", "agent: gemini\n")
+	writeLayoutFile(t, layout, "profiles/go/extra.txt", "x\n")
+	require.NoError(t, os.Remove(filepath.Join(layout.ProfileDir("go"), "setup.sh")))
+	changed, err = ProfileLocalChanges(layout, "go", origin)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"config.yaml", "extra.txt", "setup.sh"}, changed)
+
+	var buf bytes.Buffer
+	exp, err := ExportProfile(layout, "go", &buf)
+	require.NoError(t, err)
+	assert.NotContains(t, exp.Files, ProfileOriginFileName)
+
+	none, err := LoadProfileOrigin(layout, "other")
+	require.NoError(t, err)
+	assert.Nil(t, none)
+}
@@ -513,6 +513,34 @@ func TestMergeProfileChain_ScalarOverrideCascading(t *testing.T) {
 	}
 }
 
+func TestMergeProfileChain_ProvenanceHeadersChildCanDisable(t *testing.T) {
+	home, layout := setupProfileDir(t, "org", "provenance_headers: true\n")
+
+	childDir := filepath.Join(home, ".yoloai", "profiles", "scratch")
+	if err := os.MkdirAll(childDir, 0750); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(childDir, "config.yaml"), []byte("extends: org\nprovenance_headers: false\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	merged, err := MergeProfileChain(layout, &YoloaiConfig{}, []string{"base", "org"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !merged.ProvenanceHeaders {
+		t.Error("ProvenanceHeaders = false, want true from org profile")
+	}
+
+	merged, err = MergeProfileChain(layout, &YoloaiConfig{}, []string{"base", "org", "scratch"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if merged.ProvenanceHeaders {
+		t.Error("ProvenanceHeaders = true, want false from child override")
+	}
+}
+
 func TestMergeProfileChain_EnvMerge(t *testing.T) {
 	home, layout := setupProfileDir(t, "env-parent", "env:\n  GO: \"1\"\n  SHARED: parent\n")
 
@@ -547,6 +575,31 @@ func TestMergeProfileChain_EnvMerge(t *testing.T) {
 	}
 }
 
+func TestMergeProfileChain_ModelAliasesMerge(t *testing.T) {
+	home, layout := setupProfileDir(t, "alias-parent", "model_aliases:\n  fast: parent-fast\n  smart: parent-smart\n")
+
+	childDir := filepath.Join(home, ".yoloai", "profiles", "alias-child")
+	if err := os.MkdirAll(childDir, 0750); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(childDir, "config.yaml"),
+		[]byte("extends: alias-parent\nmodel_aliases:\n  fast: child-fast\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	merged, err := MergeProfileChain(layout, &YoloaiConfig{}, []string{"base", "alias-parent", "alias-child"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if merged.ModelAliases["fast"] != "child-fast" {
+		t.Errorf("ModelAliases[fast] = %q, want %q (child should win)", merged.ModelAliases["fast"], "child-fast")
+	}
+	if merged.ModelAliases["smart"] != "parent-smart" {
+		t.Errorf("ModelAliases[smart] = %q, want %q", merged.ModelAliases["smart"], "parent-smart")
+	}
+}
+
 func TestMergeProfileChain_PortsAdditive(t *testing.T) {
 	home, layout := setupProfileDir(t, "ports-parent", "ports:\n  - \"8080:8080\"\n")
 
@@ -1224,3 +1277,16 @@ func TestMergeProfileChain_AgentFilesFromBaseConfig(t *testing.T) {
 		t.Errorf("BaseDir = %q, want %q", merged.AgentFiles.BaseDir, "/base/config/dir")
 	}
 }
+
+func TestValidateImageRef(t *testing.T) {
+	for _, ok := range []string{"my-env", "my-env:v1", "ghcr.io/me/yoloai-env:latest", "localhost:5000/env"} {
+		if err := ValidateImageRef(ok); err != nil {
+			t.Errorf("ValidateImageRef(%q) = %v, want nil", ok, err)
+		}
+	}
+	for _, bad := range []string{"", "my env", "yoloai-base", "yoloai-go:latest"} {
+		if ValidateImageRef(bad) == nil {
+			t.Errorf("ValidateImageRef(%q) = nil, want an error", bad)
+		}
+	}
+}
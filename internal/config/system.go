@@ -0,0 +1,407 @@
+package config
+
+// ABOUTME: System-level config beneath the user's own: the org config that
+// ABOUTME: `config pull` caches from org_config_url, SystemConfigDir/config.yaml,
+// ABOUTME: system-wide profiles, and which layer each effective value came from.
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/kstenerud/yoloai/internal/fileutil"
+	"github.com/kstenerud/yoloai/yoerrors"
+	"gopkg.in/yaml.v3"
+)
+
+// Origins of a config value, as GetConfigOrigins reports them. File layers
+// append ":" and the file's path (the org layer: its URL).
+const (
+	OriginDefault = "default"
+	OriginOrg     = "org"
+	OriginSystem  = "system"
+	OriginUser    = "user"
+)
+
+// orgConfigHeader is the first line of the cached org config, followed by
+// the URL it came from, so the origin survives without a sidecar file.
+const orgConfigHeader = "# yoloai org config, fetched by 'yoloai config pull' from "
+
+// maxOrgConfigSize bounds the org config fetched from a URL.
+const maxOrgConfigSize = 1 << 20
+
+// configLayer is one config file in the stack the effective config is built
+// from, lowest first: the baked-in defaults, then the system layers, then the
+// user's config.yaml and defaults/config.yaml.
+type configLayer struct {
+	origin string
+	path   string
+	data   []byte
+}
+
+// systemConfigLayers reads the config files beneath the user's own that
+// exist, lowest first: the cached org config, then SystemConfigDir/config.yaml.
+// They are checked against systemSchema — the keys of the user's
+// defaults/config.yaml and config.yaml together, since one system file carries
+// both — so a typo in a config shipped to everyone fails loudly rather than
+// silently doing nothing.
+func systemConfigLayers(layout Layout) ([]configLayer, error) {
+	var layers []configLayer
+	orgPath := layout.OrgConfigCachePath()
+	data, err := readOptionalFile(orgPath)
+	if err != nil {
+		return nil, err
+	}
+	if data != nil {
+		layers = append(layers, configLayer{origin: OriginOrg + ":" + orgConfigSource(data, orgPath), path: orgPath, data: data})
+	}
+	if sysPath := layout.SystemConfigPath(); sysPath != "" {
+		data, err := readOptionalFile(sysPath)
+		if err != nil {
+			return nil, err
+		}
+		if data != nil {
+			layers = append(layers, configLayer{origin: OriginSystem + ":" + sysPath, path: sysPath, data: data})
+		}
+	}
+	for _, l := range layers {
+		if _, err := parseYAMLRoot(l.data, l.path, systemSchema); err != nil {
+			return nil, err
+		}
+	}
+	return layers, nil
+}
+
+// configLayers is every file layer, lowest first: the system layers, then the
+// user's config.yaml and defaults/config.yaml.
+func configLayers(layout Layout) ([]configLayer, error) {
+	layers, err := systemConfigLayers(layout)
+	if err != nil {
+		return nil, err
+	}
+	for _, path := range []string{layout.GlobalConfigPath(), layout.DefaultsConfigPath()} {
+		data, err := readOptionalFile(path)
+		if err != nil {
+			return nil, err
+		}
+		if data != nil {
+			layers = append(layers, configLayer{origin: OriginUser + ":" + path, path: path, data: data})
+		}
+	}
+	return layers, nil
+}
+
+// applySystemConfigLayers merges the system layers' sandbox settings over base.
+func applySystemConfigLayers(layout Layout, base *YoloaiConfig) (*YoloaiConfig, error) {
+	layers, err := systemConfigLayers(layout)
+	if err != nil {
+		return nil, err
+	}
+	for _, l := range layers {
+		cfg, err := parseConfigYAML(l.data, l.path, nil, layout.Env().EnvForConfigInterpolation())
+		if err != nil {
+			return nil, err
+		}
+		base = mergeConfigs(base, cfg)
+	}
+	return base, nil
+}
+
+// readOptionalFile reads path, returning nil, nil when it doesn't exist.
+func readOptionalFile(path string) ([]byte, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // G304: a config path from Layout
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("read %s: %w", path, err)
+	}
+	return data, nil
+}
+
+// orgConfigSource returns the URL the cached org config at path came from,
+// or path itself when the header is missing.
+func orgConfigSource(data []byte, path string) string {
+	line, _, _ := bytes.Cut(data, []byte("\n"))
+	if src, ok := strings.CutPrefix(string(line), orgConfigHeader); ok && src != "" {
+		return src
+	}
+	return path
+}
+
+// ConfigOrigin is one effective config value and the layer that set it:
+// OriginDefault, or a file layer's origin and path.
+type ConfigOrigin struct {
+	Key    string
+	Value  string // a scalar as is; a list or empty map in YAML flow style
+	Origin string
+}
+
+// GetConfigOrigins returns every effective config value, sorted by key, with
+// the layer it came from; a list more than one layer added to names each of
+// them, joined by " + ". It is the effective config GetEffectiveConfig
+// prints, one leaf at a time.
+func GetConfigOrigins(layout Layout) ([]ConfigOrigin, error) {
+	root, origins, err := effectiveConfigTree(layout)
+	if err != nil {
+		return nil, err
+	}
+	var out []ConfigOrigin
+	err = walkLeaves(root, "", func(path string, n *yaml.Node) error {
+		value, err := leafValue(n)
+		if err != nil {
+			return err
+		}
+		origin := OriginDefault
+		if o := origins[path]; len(o) > 0 {
+			origin = strings.Join(o, " + ")
+		}
+		out = append(out, ConfigOrigin{Key: path, Value: value, Origin: origin})
+		return nil
+	})
+	return out, err
+}
+
+// effectiveConfigTree merges every config layer over the defaults into one
+// sorted tree, noting for each leaf path the layers that set it.
+func effectiveConfigTree(layout Layout) (*yaml.Node, map[string][]string, error) {
+	layers, err := configLayers(layout)
+	if err != nil {
+		return nil, nil, err
+	}
+	root := buildEffectiveConfigDefaults()
+	origins := map[string][]string{}
+	for _, l := range layers {
+		// A config file the user wrote but that fails to parse must surface,
+		// not be silently dropped from the effective view.
+		var doc yaml.Node
+		if err := yaml.Unmarshal(l.data, &doc); err != nil {
+			return nil, nil, fmt.Errorf("parse config YAML %s: %w", l.path, err)
+		}
+		if doc.Kind != yaml.DocumentNode || len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+			continue
+		}
+		mergeLayerNodes(root, doc.Content[0], "", func(path string, added bool) {
+			if added {
+				origins[path] = append(origins[path], l.origin)
+			} else {
+				origins[path] = []string{l.origin}
+			}
+		})
+	}
+	sortMappingNodesRecursive(root)
+	return root, origins, nil
+}
+
+// walkLeaves calls fn for each leaf under a mapping node — a scalar, a list,
+// or an empty map — with its dotted path.
+func walkLeaves(node *yaml.Node, prefix string, fn func(path string, n *yaml.Node) error) error {
+	for i := 0; i < len(node.Content)-1; i += 2 {
+		path := node.Content[i].Value
+		if prefix != "" {
+			path = prefix + "." + path
+		}
+		val := node.Content[i+1]
+		if val.Kind == yaml.MappingNode && len(val.Content) > 0 {
+			if err := walkLeaves(val, path, fn); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := fn(path, val); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// leafValue renders a leaf for one line of output.
+func leafValue(n *yaml.Node) (string, error) {
+	if n.Kind == yaml.ScalarNode {
+		return n.Value, nil
+	}
+	flow := *n
+	flow.Style = yaml.FlowStyle
+	out, err := yaml.Marshal(&flow)
+	if err != nil {
+		return "", fmt.Errorf("marshal config value: %w", err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// ProfileSourceDir returns the directory profile name is read from: the
+// user's DataDir/profiles/<name> when it holds a config.yaml, else the
+// system-wide SystemConfigDir/profiles/<name> when that does. A name in
+// neither resolves to the user's directory, where a new profile is created.
+func ProfileSourceDir(layout Layout, name string) string {
+	userDir := layout.ProfileDir(name)
+	if hasProfileConfig(userDir) {
+		return userDir
+	}
+	if sysDir := layout.SystemProfilesDir(); sysDir != "" && hasProfileConfig(filepath.Join(sysDir, name)) {
+		return filepath.Join(sysDir, name)
+	}
+	return userDir
+}
+
+// IsSystemProfile reports whether profile name is a system-wide one the user
+// hasn't shadowed with a profile of their own.
+func IsSystemProfile(layout Layout, name string) bool {
+	return ProfileSourceDir(layout, name) != layout.ProfileDir(name)
+}
+
+// hasProfileConfig reports whether dir is a profile directory with a config.yaml.
+func hasProfileConfig(dir string) bool {
+	info, err := os.Stat(dir)
+	if err != nil || !info.IsDir() {
+		return false
+	}
+	_, err = os.Stat(filepath.Join(dir, "config.yaml"))
+	return err == nil
+}
+
+// OrgConfigPull reports what PullOrgConfig did.
+type OrgConfigPull struct {
+	// URL is org_config_url; "" when it isn't set.
+	URL string
+	// Path is the cache the org config is kept in.
+	Path string
+	// Changed is true when the cache was written with new content, or removed
+	// because org_config_url is no longer set.
+	Changed bool
+}
+
+// PullOrgConfig fetches the config org_config_url names (set in the user's
+// config.yaml or the system one) into OrgConfigCachePath, where it is layered
+// beneath SystemConfigDir/config.yaml. The URL must be https. The document is
+// checked before it replaces the cache, so a bad push upstream leaves the last
+// good copy in place. With org_config_url unset, a cache left from before is
+// removed. client nil means http.DefaultClient.
+func PullOrgConfig(ctx context.Context, layout Layout, client *http.Client) (*OrgConfigPull, error) {
+	result := &OrgConfigPull{Path: layout.OrgConfigCachePath()}
+	src, err := orgConfigURL(layout)
+	if err != nil {
+		return nil, err
+	}
+	result.URL = src
+	if src == "" {
+		err := os.Remove(result.Path)
+		if err == nil {
+			result.Changed = true
+		} else if !errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("remove cached org config: %w", err)
+		}
+		return result, nil
+	}
+	if u, err := url.Parse(src); err != nil || u.Scheme != "https" || u.Host == "" {
+		return nil, yoerrors.NewUsageError("org_config_url %q must be an https URL", src)
+	}
+
+	body, err := fetchOrgConfig(ctx, client, src)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := parseYAMLRoot(body, src, systemSchema); err != nil {
+		return nil, err
+	}
+	if _, err := parseConfigYAML(body, src, nil, layout.Env().EnvForConfigInterpolation()); err != nil {
+		return nil, err
+	}
+
+	data := append([]byte(orgConfigHeader+src+"\n"), body...)
+	if old, err := readOptionalFile(result.Path); err != nil {
+		return nil, err
+	} else if bytes.Equal(old, data) {
+		return result, nil
+	}
+	if err := fileutil.MkdirAll(filepath.Dir(result.Path), 0o750); err != nil {
+		return nil, fmt.Errorf("create cache directory: %w", err)
+	}
+	if err := fileutil.AtomicWriteFile(result.Path, data, 0o600); err != nil {
+		return nil, err
+	}
+	result.Changed = true
+	return result, nil
+}
+
+// orgConfigURL reads org_config_url from the user's config.yaml, else from
+// the system one. The cached org config itself is not consulted, so a broken
+// cache can always be replaced.
+func orgConfigURL(layout Layout) (string, error) {
+	paths := []string{layout.GlobalConfigPath()}
+	if sysPath := layout.SystemConfigPath(); sysPath != "" {
+		paths = append(paths, sysPath)
+	}
+	for _, path := range paths {
+		data, err := readOptionalFile(path)
+		if err != nil {
+			return "", err
+		}
+		root, err := parseYAMLRoot(data, path, nil)
+		if err != nil {
+			return "", err
+		}
+		if root == nil {
+			continue
+		}
+		if n := findDottedNode(root, []string{"org_config_url"}); n != nil && n.Kind == yaml.ScalarNode && n.Value != "" {
+			return expandEnvBraced(n.Value, layout.Env().EnvForConfigInterpolation())
+		}
+	}
+	return "", nil
+}
+
+// fetchOrgConfig GETs src and returns its body.
+func fetchOrgConfig(ctx context.Context, client *http.Client, src string) ([]byte, error) {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, src, nil)
+	if err != nil {
+		return nil, fmt.Errorf("fetch org config: %w", err)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("fetch org config: %w", err)
+	}
+	defer resp.Body.Close() //nolint:errcheck // read-only body
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetch org config from %s: %s", src, resp.Status)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxOrgConfigSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("fetch org config from %s: %w", src, err)
+	}
+	if len(body) > maxOrgConfigSize {
+		return nil, fmt.Errorf("org config at %s is over %d bytes", src, maxOrgConfigSize)
+	}
+	return body, nil
+}
+
+// findDottedNode returns the node at parts under a mapping, or nil.
+func findDottedNode(node *yaml.Node, parts []string) *yaml.Node {
+	for _, part := range parts {
+		if node.Kind != yaml.MappingNode {
+			return nil
+		}
+		var next *yaml.Node
+		for i := 0; i < len(node.Content)-1; i += 2 {
+			if node.Content[i].Value == part {
+				next = node.Content[i+1]
+				break
+			}
+		}
+		if next == nil {
+			return nil
+		}
+		node = next
+	}
+	return node
+}
@@ -0,0 +1,218 @@
+// ABOUTME: Tests for the system config layers: layering beneath the user's
+// ABOUTME: config, value origins, system-wide profiles, and pulling the org config.
+
+package config
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// systemConfigDir returns a Layout with a system layer and the system dir,
+// writing content (when non-empty) to its config.yaml.
+func systemConfigDir(t *testing.T, content string) (Layout, string) {
+	t.Helper()
+	_, layout := configDir(t)
+	sysDir := filepath.Join(t.TempDir(), "etc-yoloai")
+	require.NoError(t, os.MkdirAll(sysDir, 0750))
+	if content != "" {
+		require.NoError(t, os.WriteFile(filepath.Join(sysDir, "config.yaml"), []byte(content), 0600))
+	}
+	layout.SystemConfigDir = sysDir
+	return layout, sysDir
+}
+
+func TestLoadDefaultsConfig_SystemLayerBeneathUser(t *testing.T) {
+	layout, _ := systemConfigDir(t, "agent: codex\nmodel: sys-model\nnetwork:\n  allow: [corp.example.com]\n")
+	require.NoError(t, os.WriteFile(layout.DefaultsConfigPath(), []byte("model: my-model\nnetwork:\n  allow: [api.example.com]\n"), 0600))
+
+	cfg, err := LoadDefaultsConfig(layout)
+	require.NoError(t, err)
+	assert.Equal(t, "codex", cfg.Agent)
+	assert.Equal(t, "my-model", cfg.Model)
+	assert.Equal(t, []string{"corp.example.com", "api.example.com"}, cfg.Network.Allow)
+
+	cfg, err = LoadConfig(layout)
+	require.NoError(t, err)
+	assert.Equal(t, "codex", cfg.Agent)
+	assert.Equal(t, "my-model", cfg.Model)
+}
+
+func TestLoadConfig_NoSystemLayer(t *testing.T) {
+	layout, _ := systemConfigDir(t, "agent: codex\n")
+	layout.SystemConfigDir = ""
+
+	cfg, err := LoadConfig(layout)
+	require.NoError(t, err)
+	assert.Empty(t, cfg.Agent)
+}
+
+func TestLoadConfig_SystemUnknownKeyIsError(t *testing.T) {
+	layout, sysDir := systemConfigDir(t, "agnet: codex\n")
+
+	_, err := LoadConfig(layout)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), filepath.Join(sysDir, "config.yaml"))
+	assert.Contains(t, err.Error(), "agnet")
+}
+
+func TestLoadGlobalConfig_SystemLayer(t *testing.T) {
+	layout, _ := systemConfigDir(t, "retention_days: 30\nmodel_aliases:\n  fast: sys-fast\n  big: sys-big\n")
+	require.NoError(t, os.WriteFile(layout.GlobalConfigPath(), []byte("model_aliases:\n  fast: my-fast\n"), 0600))
+
+	cfg, err := LoadGlobalConfig(layout)
+	require.NoError(t, err)
+	assert.Equal(t, 30, cfg.RetentionDays)
+	assert.Equal(t, map[string]string{"fast": "my-fast", "big": "sys-big"}, cfg.ModelAliases)
+}
+
+func TestGetConfigValue_SystemLayer(t *testing.T) {
+	layout, _ := systemConfigDir(t, "agent: codex\nretention_days: 30\n")
+
+	val, found, err := GetConfigValue(layout, "agent")
+	require.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, "codex", val)
+
+	val, _, err = GetConfigValue(layout, "retention_days")
+	require.NoError(t, err)
+	assert.Equal(t, "30", val)
+
+	require.NoError(t, os.WriteFile(layout.DefaultsConfigPath(), []byte("agent: gemini\n"), 0600))
+	val, _, err = GetConfigValue(layout, "agent")
+	require.NoError(t, err)
+	assert.Equal(t, "gemini", val)
+}
+
+func TestGetConfigOrigins(t *testing.T) {
+	layout, sysDir := systemConfigDir(t, "agent: codex\nnetwork:\n  allow: [corp.example.com]\n")
+	require.NoError(t, os.WriteFile(layout.DefaultsConfigPath(), []byte("model: my-model\nnetwork:\n  allow: [api.example.com]\n"), 0600))
+
+	origins, err := GetConfigOrigins(layout)
+	require.NoError(t, err)
+	byKey := map[string]ConfigOrigin{}
+	for _, o := range origins {
+		byKey[o.Key] = o
+	}
+
+	system := OriginSystem + ":" + filepath.Join(sysDir, "config.yaml")
+	user := OriginUser + ":" + layout.DefaultsConfigPath()
+	assert.Equal(t, ConfigOrigin{Key: "agent", Value: "codex", Origin: system}, byKey["agent"])
+	assert.Equal(t, ConfigOrigin{Key: "model", Value: "my-model", Origin: user}, byKey["model"])
+	assert.Equal(t, ConfigOrigin{Key: "os", Value: "linux", Origin: OriginDefault}, byKey["os"])
+	assert.Equal(t, ConfigOrigin{Key: "env", Value: "{}", Origin: OriginDefault}, byKey["env"])
+	assert.Equal(t, "[corp.example.com, api.example.com]", byKey["network.allow"].Value)
+	assert.Equal(t, system+" + "+user, byKey["network.allow"].Origin)
+}
+
+func TestGetEffectiveConfig_SystemLayer(t *testing.T) {
+	layout, _ := systemConfigDir(t, "agent: codex\n")
+
+	out, err := GetEffectiveConfig(layout)
+	require.NoError(t, err)
+	assert.Contains(t, out, "agent: codex")
+}
+
+func TestSystemProfiles(t *testing.T) {
+	layout, sysDir := systemConfigDir(t, "")
+	sysProfile := filepath.Join(sysDir, "profiles", "corp")
+	require.NoError(t, os.MkdirAll(sysProfile, 0750))
+	require.NoError(t, os.WriteFile(filepath.Join(sysProfile, "config.yaml"), []byte("agent: codex\n"), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(sysProfile, "Dockerfile"), []byte("FROM yoloai-base\n"), 0600))
+	userProfile := layout.ProfileDir("mine")
+	require.NoError(t, os.MkdirAll(userProfile, 0750))
+	require.NoError(t, os.WriteFile(filepath.Join(userProfile, "config.yaml"), []byte("agent: gemini\n"), 0600))
+
+	assert.True(t, ProfileExists(layout, "corp"))
+	assert.True(t, IsSystemProfile(layout, "corp"))
+	assert.True(t, ProfileHasDockerfile(layout, "corp"))
+	assert.False(t, IsSystemProfile(layout, "mine"))
+	assert.False(t, ProfileExists(layout, "nope"))
+
+	names, err := ListProfiles(layout)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"corp", "mine"}, names)
+
+	p, err := LoadProfile(layout, "corp")
+	require.NoError(t, err)
+	assert.Equal(t, "codex", p.Agent)
+
+	// The user's own profile of the same name shadows the system one.
+	require.NoError(t, os.MkdirAll(layout.ProfileDir("corp"), 0750))
+	require.NoError(t, os.WriteFile(filepath.Join(layout.ProfileDir("corp"), "config.yaml"), []byte("agent: aider\n"), 0600))
+	assert.False(t, IsSystemProfile(layout, "corp"))
+	p, err = LoadProfile(layout, "corp")
+	require.NoError(t, err)
+	assert.Equal(t, "aider", p.Agent)
+}
+
+func TestPullOrgConfig(t *testing.T) {
+	body := "agent: codex\nnetwork:\n  allow: [corp.example.com]\n"
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(body))
+	}))
+	defer srv.Close()
+
+	layout, _ := systemConfigDir(t, "org_config_url: "+srv.URL+"/config.yaml\n")
+	ctx := context.Background()
+
+	res, err := PullOrgConfig(ctx, layout, srv.Client())
+	require.NoError(t, err)
+	assert.True(t, res.Changed)
+	assert.Equal(t, srv.URL+"/config.yaml", res.URL)
+
+	cfg, err := LoadConfig(layout)
+	require.NoError(t, err)
+	assert.Equal(t, "codex", cfg.Agent)
+
+	origins, err := GetConfigOrigins(layout)
+	require.NoError(t, err)
+	for _, o := range origins {
+		if o.Key == "agent" {
+			assert.Equal(t, OriginOrg+":"+srv.URL+"/config.yaml", o.Origin)
+		}
+	}
+
+	res, err = PullOrgConfig(ctx, layout, srv.Client())
+	require.NoError(t, err)
+	assert.False(t, res.Changed)
+
+	// A bad document leaves the last good copy in place.
+	body = "agnet: codex\n"
+	_, err = PullOrgConfig(ctx, layout, srv.Client())
+	require.Error(t, err)
+	cfg, err = LoadConfig(layout)
+	require.NoError(t, err)
+	assert.Equal(t, "codex", cfg.Agent)
+}
+
+func TestPullOrgConfig_Unset(t *testing.T) {
+	layout, _ := systemConfigDir(t, "")
+	require.NoError(t, os.MkdirAll(filepath.Dir(layout.OrgConfigCachePath()), 0750))
+	require.NoError(t, os.WriteFile(layout.OrgConfigCachePath(), []byte("agent: codex\n"), 0600))
+
+	res, err := PullOrgConfig(context.Background(), layout, nil)
+	require.NoError(t, err)
+	assert.True(t, res.Changed)
+	assert.NoFileExists(t, layout.OrgConfigCachePath())
+
+	res, err = PullOrgConfig(context.Background(), layout, nil)
+	require.NoError(t, err)
+	assert.False(t, res.Changed)
+}
+
+func TestPullOrgConfig_RequiresHTTPS(t *testing.T) {
+	layout, _ := systemConfigDir(t, "")
+	require.NoError(t, os.WriteFile(layout.GlobalConfigPath(), []byte("org_config_url: http://example.com/config.yaml\n"), 0600))
+
+	_, err := PullOrgConfig(context.Background(), layout, nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "https")
+}
@@ -6,6 +6,7 @@ package config
 import (
 	"fmt"
 	"os"
+	"slices"
 	"sort"
 	"strings"
 
@@ -36,7 +37,8 @@ func IsKnownConfigPath(path string) bool {
 	}
 
 	if len(parts) == 1 {
-		return knownDefaultsKeys[path] || path == "model_aliases" || path == "tmux_conf"
+		_, ok := systemSchema.Properties[path]
+		return ok
 	}
 
 	if len(parts) == 2 {
@@ -125,51 +127,15 @@ func setCollectionDefault(root *yaml.Node, cs knownCollectionSetting) {
 	setNodeValue(parent, parts[len(parts)-1], &yaml.Node{Kind: cs.Kind})
 }
 
-// overlayConfigFile reads raw YAML from readFn and merges it into root.
-// Errors from readFn and YAML parse errors are both returned — a config file
-// the user wrote but that fails to parse must surface, not be silently dropped
-// from the effective view.
-func overlayConfigFile(root *yaml.Node, readFn func() ([]byte, error)) error {
-	data, err := readFn()
-	if err != nil {
-		return err
-	}
-	if data == nil {
-		return nil
-	}
-	return mergeRawYAMLInto(root, data)
-}
-
-// mergeRawYAMLInto parses data as YAML and merges its top-level mapping into root.
-// A parse error is returned so the caller can report the malformed file.
-func mergeRawYAMLInto(root *yaml.Node, data []byte) error {
-	var doc yaml.Node
-	if err := yaml.Unmarshal(data, &doc); err != nil {
-		return fmt.Errorf("parse config YAML: %w", err)
-	}
-	if doc.Kind == yaml.DocumentNode && len(doc.Content) > 0 {
-		if src := doc.Content[0]; src.Kind == yaml.MappingNode {
-			mergeNodes(root, src)
-		}
-	}
-	return nil
-}
-
 // GetEffectiveConfig returns YAML showing all known settings with their
-// effective values (file overrides + defaults), plus any extra keys from the
-// files that aren't in the known settings list.
+// effective values (the config layers over the defaults), plus any extra keys
+// from the files that aren't in the known settings list.
 func GetEffectiveConfig(layout Layout) (string, error) {
-	root := buildEffectiveConfigDefaults()
-
-	if err := overlayConfigFile(root, func() ([]byte, error) { return ReadGlobalConfigRaw(layout) }); err != nil {
-		return "", err
-	}
-	if err := overlayConfigFile(root, func() ([]byte, error) { return ReadConfigRaw(layout) }); err != nil {
+	root, _, err := effectiveConfigTree(layout)
+	if err != nil {
 		return "", err
 	}
 
-	sortMappingNodesRecursive(root)
-
 	doc := yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{root}}
 	out, err := yaml.Marshal(&doc)
 	if err != nil {
@@ -178,18 +144,46 @@ func GetEffectiveConfig(layout Layout) (string, error) {
 	return string(out), nil
 }
 
-// mergeNodes recursively copies values from src into dst, overwriting
-// existing values and adding keys that don't exist in dst.
-func mergeNodes(dst, src *yaml.Node) {
+// mergeLayerNodes merges one config layer's mapping src into dst the way the
+// loaders merge layers: mappings merge, lists add up, scalars replace, and
+// agent_files, which a layer sets wholesale, replaces. onLeaf is called for
+// each leaf src sets, with added true when it was appended to a list a lower
+// layer had already started.
+func mergeLayerNodes(dst, src *yaml.Node, prefix string, onLeaf func(path string, added bool)) {
 	for i := 0; i < len(src.Content)-1; i += 2 {
 		key := src.Content[i].Value
 		val := src.Content[i+1]
+		path := key
+		if prefix != "" {
+			path = prefix + "." + key
+		}
+		existing := findDottedNode(dst, []string{key})
 
-		if val.Kind == yaml.MappingNode {
+		switch {
+		case path == "agent_files":
+			setNodeValue(dst, key, val)
+			if val.Kind == yaml.MappingNode && len(val.Content) > 0 {
+				_ = walkLeaves(val, path, func(p string, _ *yaml.Node) error {
+					onLeaf(p, false)
+					return nil
+				})
+			} else {
+				onLeaf(path, false)
+			}
+		case val.Kind == yaml.MappingNode:
 			dstChild := getOrCreateMapping(dst, key)
-			mergeNodes(dstChild, val)
-		} else {
+			if len(val.Content) == 0 && len(dstChild.Content) == 0 {
+				onLeaf(path, false)
+			}
+			mergeLayerNodes(dstChild, val, path, onLeaf)
+		case val.Kind == yaml.SequenceNode && existing != nil && existing.Kind == yaml.SequenceNode:
+			merged := *existing
+			merged.Content = append(slices.Clone(existing.Content), val.Content...)
+			setNodeValue(dst, key, &merged)
+			onLeaf(path, len(existing.Content) > 0)
+		default:
 			setNodeValue(dst, key, val)
+			onLeaf(path, false)
 		}
 	}
 }
@@ -208,65 +202,52 @@ func setNodeValue(parent *yaml.Node, key string, val *yaml.Node) {
 	parent.Content = append(parent.Content, keyNode, val)
 }
 
-// GetConfigValue reads a value at the given dotted path from the appropriate
-// config file. Global keys (tmux_conf, model_aliases) are read from
-// layout.GlobalConfigPath(); profile keys from layout.DefaultsConfigPath().
+// GetConfigValue reads a value at the given dotted path from the config
+// layers that hold it: the system layers, then, for global keys (tmux_conf,
+// model_aliases), layout.GlobalConfigPath() and, for profile keys,
+// layout.DefaultsConfigPath(). Layers combine as the loaders combine them.
 // Returns the raw string value for scalars, or marshaled YAML for
 // mappings/sequences. Falls back to the default for known settings.
-// The bool return indicates whether the key was found (in file or defaults).
+// The bool return indicates whether the key was found (in a file or defaults).
 func GetConfigValue(layout Layout, path string) (string, bool, error) {
 	if !IsKnownConfigPath(path) {
 		return "", false, nil
 	}
 
-	var configPath string
-	var defaults []knownSetting
-
+	userPath := layout.DefaultsConfigPath()
+	defaults := knownSettings
 	if isGlobalKey(path) {
-		configPath = layout.GlobalConfigPath()
+		userPath = layout.GlobalConfigPath()
 		defaults = globalKnownSettings
-	} else {
-		configPath = layout.DefaultsConfigPath()
-		defaults = knownSettings
 	}
 
-	data, err := os.ReadFile(configPath) //nolint:gosec // G304: path from GlobalConfigPath/ConfigPath
+	layers, err := systemConfigLayers(layout)
 	if err != nil {
-		if os.IsNotExist(err) {
-			return knownDefaultFrom(path, defaults)
-		}
-		return "", false, fmt.Errorf("read config: %w", err)
+		return "", false, err
 	}
-
-	var doc yaml.Node
-	if err := yaml.Unmarshal(data, &doc); err != nil {
-		return "", false, fmt.Errorf("parse config: %w", err)
-	}
-
-	if doc.Kind != yaml.DocumentNode || len(doc.Content) == 0 {
-		return knownDefaultFrom(path, defaults)
+	data, err := readOptionalFile(userPath)
+	if err != nil {
+		return "", false, fmt.Errorf("read config: %w", err)
 	}
-	root := doc.Content[0]
-	if root.Kind != yaml.MappingNode {
-		return knownDefaultFrom(path, defaults)
+	if data != nil {
+		layers = append(layers, configLayer{path: userPath, data: data})
 	}
 
-	parts := splitDottedPath(path)
-	node := root
-	for _, part := range parts {
-		found := false
-		for i := 0; i < len(node.Content)-1; i += 2 {
-			if node.Content[i].Value == part {
-				node = node.Content[i+1]
-				found = true
-				break
-			}
+	root := &yaml.Node{Kind: yaml.MappingNode}
+	for _, l := range layers {
+		src, err := parseYAMLRoot(l.data, l.path, nil)
+		if err != nil {
+			return "", false, fmt.Errorf("parse config: %w", err)
 		}
-		if !found {
-			return knownDefaultFrom(path, defaults)
+		if src != nil {
+			mergeLayerNodes(root, src, "", func(string, bool) {})
 		}
 	}
 
+	node := findDottedNode(root, splitDottedPath(path))
+	if node == nil {
+		return knownDefaultFrom(path, defaults)
+	}
 	if node.Kind == yaml.ScalarNode {
 		return node.Value, true, nil
 	}
@@ -319,6 +300,9 @@ func UpdateConfigFields(layout Layout, fields map[string]string) error {
 	for fieldPath, value := range fields {
 		setYAMLField(root, fieldPath, value)
 	}
+	if err := validateConfigNode(root, defaultsSchema, configPath, true); err != nil {
+		return err
+	}
 
 	out, err := yaml.Marshal(&doc)
 	if err != nil {
@@ -398,6 +382,9 @@ func UpdateGlobalConfigFields(layout Layout, fields map[string]string) error {
 	for fieldPath, value := range fields {
 		setYAMLField(root, fieldPath, value)
 	}
+	if err := validateConfigNode(root, globalSchema, configPath, false); err != nil {
+		return err
+	}
 
 	out, err := yaml.Marshal(&doc)
 	if err != nil {
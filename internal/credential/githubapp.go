@@ -0,0 +1,158 @@
+// ABOUTME: GitHub App token exchange: signs an app JWT and trades it for a
+// ABOUTME: short-lived installation token scoped to the permissions asked for.
+package credential
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// DefaultGitHubAPIURL is the API root of github.com; GitHub Enterprise
+// installations have their own.
+const DefaultGitHubAPIURL = "https://api.github.com"
+
+// GitHubReadOnlyPermissions is what a sandbox's token may do: read code,
+// issues and pull requests (metadata is implied by any of them), nothing else.
+var GitHubReadOnlyPermissions = map[string]string{
+	"contents":      "read",
+	"issues":        "read",
+	"pull_requests": "read",
+	"metadata":      "read",
+}
+
+// GitHubApp mints installation tokens for one GitHub App installation. The
+// caller reads the private key (yoloai's credential layer does no ambient
+// reads, D63). Its Fetch method is a FetchFunc, so it can back a
+// RefreshingSource as well as a one-off token.
+type GitHubApp struct {
+	AppID          string
+	InstallationID string
+	Key            *rsa.PrivateKey
+	// APIURL is the API root; "" = DefaultGitHubAPIURL.
+	APIURL string
+	// Permissions scopes the minted token; nil = the installation's full set.
+	Permissions map[string]string
+	// Client performs the exchange; nil = http.DefaultClient.
+	Client *http.Client
+	// Now is the clock the JWT is stamped with; nil = time.Now.
+	Now func() time.Time
+}
+
+// ParseGitHubAppKey parses the PEM private key GitHub issues for an app
+// (PKCS#1), also accepting PKCS#8.
+func ParseGitHubAppKey(data []byte) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("credential: github app key: no PEM block")
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("credential: github app key: %w", err)
+	}
+	key, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("credential: github app key: not an RSA key")
+	}
+	return key, nil
+}
+
+// Fetch exchanges an app JWT for an installation token and returns it with
+// its expiry (an hour after minting, per GitHub).
+func (a GitHubApp) Fetch(ctx context.Context) (string, time.Time, error) {
+	jwt, err := a.jwt()
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	payload := map[string]any{}
+	if a.Permissions != nil {
+		payload["permissions"] = a.Permissions
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("credential: github app: %w", err)
+	}
+
+	apiURL := strings.TrimSuffix(a.APIURL, "/")
+	if apiURL == "" {
+		apiURL = DefaultGitHubAPIURL
+	}
+	url := fmt.Sprintf("%s/app/installations/%s/access_tokens", apiURL, a.InstallationID)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("credential: github app: %w", err)
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("Authorization", "Bearer "+jwt)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+
+	client := a.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("credential: github app: %w", err)
+	}
+	defer resp.Body.Close() //nolint:errcheck // read-only body
+
+	var out struct {
+		Token     string    `json:"token"`
+		ExpiresAt time.Time `json:"expires_at"`
+		Message   string    `json:"message"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return "", time.Time{}, fmt.Errorf("credential: github app: %s: decode response: %w", resp.Status, err)
+	}
+	if resp.StatusCode != http.StatusCreated || out.Token == "" {
+		return "", time.Time{}, fmt.Errorf("credential: github app: installation %s: %s: %s", a.InstallationID, resp.Status, out.Message)
+	}
+	return out.Token, out.ExpiresAt, nil
+}
+
+// jwt builds the RS256 token that authenticates as the app. It is backdated
+// a minute against clock drift and lives the 10-minute maximum GitHub allows,
+// less a minute.
+func (a GitHubApp) jwt() (string, error) {
+	if a.Key == nil {
+		return "", errors.New("credential: github app: no private key")
+	}
+	now := time.Now
+	if a.Now != nil {
+		now = a.Now
+	}
+	t := now()
+	enc := base64.RawURLEncoding
+	header := enc.EncodeToString([]byte(`{"alg":"RS256","typ":"JWT"}`))
+	claims, err := json.Marshal(map[string]any{
+		"iat": t.Add(-time.Minute).Unix(),
+		"exp": t.Add(9 * time.Minute).Unix(),
+		"iss": a.AppID,
+	})
+	if err != nil {
+		return "", fmt.Errorf("credential: github app: %w", err)
+	}
+	signed := header + "." + enc.EncodeToString(claims)
+	digest := sha256.Sum256([]byte(signed))
+	sig, err := rsa.SignPKCS1v15(rand.Reader, a.Key, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", fmt.Errorf("credential: github app: sign JWT: %w", err)
+	}
+	return signed + "." + enc.EncodeToString(sig), nil
+}
@@ -0,0 +1,105 @@
+// ABOUTME: Tests for the GitHub App token exchange against a fake API: the JWT
+// ABOUTME: it signs, the permissions it asks for, and how a refusal surfaces.
+package credential_test
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kstenerud/yoloai/internal/credential"
+)
+
+func testAppKey(t *testing.T) *rsa.PrivateKey {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	return key
+}
+
+func TestParseGitHubAppKey(t *testing.T) {
+	key := testAppKey(t)
+	pkcs1 := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+	got, err := credential.ParseGitHubAppKey(pkcs1)
+	require.NoError(t, err)
+	assert.True(t, key.Equal(got))
+
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+	got, err = credential.ParseGitHubAppKey(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}))
+	require.NoError(t, err)
+	assert.True(t, key.Equal(got))
+
+	_, err = credential.ParseGitHubAppKey([]byte("not a key"))
+	assert.Error(t, err)
+}
+
+func TestGitHubApp_Fetch(t *testing.T) {
+	key := testAppKey(t)
+	now := time.Unix(1_700_000_000, 0)
+	expires := now.Add(time.Hour).UTC()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/app/installations/42/access_tokens", r.URL.Path)
+
+		// The bearer is an RS256 JWT issued by the app, verifiable with its key.
+		jwt := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		parts := strings.Split(jwt, ".")
+		require.Len(t, parts, 3)
+		sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+		require.NoError(t, err)
+		digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+		assert.NoError(t, rsa.VerifyPKCS1v15(&key.PublicKey, crypto.SHA256, digest[:], sig))
+		claims, err := base64.RawURLEncoding.DecodeString(parts[1])
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"iss":"7","iat":1699999940,"exp":1700000540}`, string(claims))
+
+		var body struct {
+			Permissions map[string]string `json:"permissions"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.Equal(t, credential.GitHubReadOnlyPermissions, body.Permissions)
+
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(map[string]any{"token": "ghs_minted", "expires_at": expires})
+	}))
+	defer srv.Close()
+
+	app := credential.GitHubApp{
+		AppID: "7", InstallationID: "42", Key: key, APIURL: srv.URL + "/",
+		Permissions: credential.GitHubReadOnlyPermissions,
+		Now:         func() time.Time { return now },
+	}
+	token, exp, err := app.Fetch(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "ghs_minted", token)
+	assert.True(t, expires.Equal(exp))
+}
+
+func TestGitHubApp_FetchRefused(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"message":"Not Found"}`))
+	}))
+	defer srv.Close()
+
+	app := credential.GitHubApp{AppID: "7", InstallationID: "42", Key: testAppKey(t), APIURL: srv.URL}
+	_, _, err := app.Fetch(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "404")
+	assert.Contains(t, err.Error(), "Not Found")
+}
@@ -0,0 +1,183 @@
+// ABOUTME: The daemon's local API on a unix socket (status, sandbox listing) and
+// ABOUTME: the client `yoloai ls` uses to list through the running daemon.
+
+// Package daemonapi is the local HTTP API `yoloai daemon run` serves on a
+// unix socket, and the client the CLI reaches it with. The daemon keeps one
+// SandboxLister open for its whole life, so a listing it answers costs a few
+// cached inspects, where a standalone `yoloai ls` opens every backend and
+// inspects every instance again.
+package daemonapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/kstenerud/yoloai"
+	"github.com/kstenerud/yoloai/internal/buildinfo"
+)
+
+// VersionHeader carries the daemon's yoloai version on every response, so a
+// client can tell it is talking to a daemon started from another build.
+const VersionHeader = "X-Yoloai-Version"
+
+// readHeaderTimeout bounds the header read so a stuck client can't pin a
+// connection open.
+const readHeaderTimeout = 10 * time.Second
+
+// requestTimeout bounds one client request. A first listing has to open the
+// backends, so it is generous; a daemon that takes longer is treated as gone.
+const requestTimeout = 30 * time.Second
+
+// ErrNoDaemon is returned by the Client when nothing is listening on the socket.
+var ErrNoDaemon = errors.New("no daemon is running")
+
+// ErrVersionMismatch is returned by the Client when the daemon runs a
+// different yoloai version, whose answers this one may not read right.
+var ErrVersionMismatch = errors.New("the daemon runs a different yoloai version")
+
+// Lister lists sandboxes the way System.AllSandboxes does.
+type Lister func(context.Context) ([]*yoloai.SandboxInfo, []yoloai.BackendType, error)
+
+// Status describes the running daemon.
+type Status struct {
+	PID     int       `json:"pid"`
+	Version string    `json:"version"`
+	Started time.Time `json:"started"`
+}
+
+// Listing is the answer to a sandbox listing, in the shape of `yoloai ls --json`.
+type Listing struct {
+	Sandboxes           []*yoloai.SandboxInfo `json:"sandboxes"`
+	UnavailableBackends []yoloai.BackendType  `json:"unavailable_backends"`
+}
+
+// NewHandler returns the API over list:
+//
+//	GET /v1/status     the daemon's Status
+//	GET /v1/sandboxes  a Listing
+//
+// Errors come back as {"error": "..."} with status 500.
+func NewHandler(list Lister) http.Handler {
+	status := Status{PID: os.Getpid(), Version: buildinfo.Version, Started: time.Now().UTC()}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /v1/status", func(w http.ResponseWriter, _ *http.Request) {
+		writeJSON(w, http.StatusOK, status)
+	})
+	mux.HandleFunc("GET /v1/sandboxes", func(w http.ResponseWriter, r *http.Request) {
+		infos, unavailable, err := list(r.Context())
+		if err != nil {
+			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+			return
+		}
+		if infos == nil {
+			infos = []*yoloai.SandboxInfo{}
+		}
+		if unavailable == nil {
+			unavailable = []yoloai.BackendType{}
+		}
+		writeJSON(w, http.StatusOK, Listing{Sandboxes: infos, UnavailableBackends: unavailable})
+	})
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(VersionHeader, status.Version)
+		mux.ServeHTTP(w, r)
+	})
+}
+
+// Serve answers API requests on ln until ctx is cancelled, then shuts the
+// server down and returns nil.
+func Serve(ctx context.Context, ln net.Listener, h http.Handler) error {
+	srv := &http.Server{
+		Handler:           h,
+		ReadHeaderTimeout: readHeaderTimeout,
+		BaseContext:       func(net.Listener) context.Context { return ctx },
+	}
+	stop := context.AfterFunc(ctx, func() {
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = srv.Shutdown(shutdownCtx) //nolint:errcheck // best-effort on the way out
+	})
+	defer stop()
+	if err := srv.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v) //nolint:errcheck // client gone; nothing to report to
+}
+
+// Client talks to the daemon's API socket.
+type Client struct {
+	http *http.Client
+}
+
+// NewClient returns a Client for the socket at path. Nothing is dialled
+// until the first request.
+func NewClient(path string) *Client {
+	var d net.Dialer
+	return &Client{http: &http.Client{
+		Timeout: requestTimeout,
+		Transport: &http.Transport{
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				return d.DialContext(ctx, "unix", path)
+			},
+		},
+	}}
+}
+
+// Status asks the daemon about itself.
+func (c *Client) Status(ctx context.Context) (Status, error) {
+	var st Status
+	err := c.get(ctx, "/v1/status", false, &st)
+	return st, err
+}
+
+// Sandboxes lists sandboxes through the daemon. It refuses the answer of a
+// daemon running another version with ErrVersionMismatch.
+func (c *Client) Sandboxes(ctx context.Context) (*Listing, error) {
+	var l Listing
+	if err := c.get(ctx, "/v1/sandboxes", true, &l); err != nil {
+		return nil, err
+	}
+	return &l, nil
+}
+
+func (c *Client) get(ctx context.Context, path string, sameVersion bool, v any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "http://yoloai"+path, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := c.http.Do(req)
+	if err != nil {
+		if _, ok := errors.AsType[*net.OpError](err); ok && ctx.Err() == nil {
+			return fmt.Errorf("%w: %w", ErrNoDaemon, err)
+		}
+		return err
+	}
+	defer resp.Body.Close() //nolint:errcheck // read-only
+	if sameVersion && resp.Header.Get(VersionHeader) != buildinfo.Version {
+		return fmt.Errorf("%w (%q, this is %q)", ErrVersionMismatch, resp.Header.Get(VersionHeader), buildinfo.Version)
+	}
+	if resp.StatusCode != http.StatusOK {
+		var e struct {
+			Error string `json:"error"`
+		}
+		if json.NewDecoder(resp.Body).Decode(&e) == nil && e.Error != "" {
+			return fmt.Errorf("daemon: %s", e.Error)
+		}
+		return fmt.Errorf("daemon: %s", resp.Status)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("unreadable answer from the daemon: %w", err)
+	}
+	return nil
+}
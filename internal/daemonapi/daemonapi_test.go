@@ -0,0 +1,101 @@
+package daemonapi
+
+// ABOUTME: Tests for the daemon API over a real unix socket: status, listings,
+// ABOUTME: list errors, a missing daemon, and a daemon from another version.
+
+import (
+	"context"
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kstenerud/yoloai"
+	"github.com/kstenerud/yoloai/internal/buildinfo"
+)
+
+// serve starts the API over list on a fresh socket and returns its path.
+func serve(t *testing.T, list Lister) string {
+	t.Helper()
+	// Unix socket paths are short on macOS; t.TempDir can be too long.
+	dir, err := os.MkdirTemp("", "yapi")
+	require.NoError(t, err)
+	t.Cleanup(func() { os.RemoveAll(dir) }) //nolint:errcheck,gosec // test cleanup
+	path := filepath.Join(dir, "daemon.sock")
+	ln, err := net.Listen("unix", path)
+	require.NoError(t, err)
+	ctx, cancel := context.WithCancel(t.Context())
+	done := make(chan error, 1)
+	h := NewHandler(list)
+	go func() { done <- Serve(ctx, ln, h) }()
+	t.Cleanup(func() {
+		cancel()
+		assert.NoError(t, <-done)
+	})
+	return path
+}
+
+func setVersion(t *testing.T, v string) {
+	t.Helper()
+	old := buildinfo.Version
+	buildinfo.Version = v
+	t.Cleanup(func() { buildinfo.Version = old })
+}
+
+func TestClient_Sandboxes(t *testing.T) {
+	setVersion(t, "1.2.3")
+	path := serve(t, func(context.Context) ([]*yoloai.SandboxInfo, []yoloai.BackendType, error) {
+		return []*yoloai.SandboxInfo{{Environment: &yoloai.Environment{Name: "one"}, Status: yoloai.StatusIdle}}, []yoloai.BackendType{"tart"}, nil
+	})
+
+	l, err := NewClient(path).Sandboxes(t.Context())
+	require.NoError(t, err)
+	require.Len(t, l.Sandboxes, 1)
+	assert.Equal(t, "one", l.Sandboxes[0].Environment.Name)
+	assert.Equal(t, yoloai.StatusIdle, l.Sandboxes[0].Status)
+	assert.Equal(t, []yoloai.BackendType{"tart"}, l.UnavailableBackends)
+}
+
+func TestClient_Status(t *testing.T) {
+	setVersion(t, "1.2.3")
+	path := serve(t, nil)
+
+	st, err := NewClient(path).Status(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, os.Getpid(), st.PID)
+	assert.Equal(t, "1.2.3", st.Version)
+	assert.False(t, st.Started.IsZero())
+}
+
+func TestClient_ListErrorComesBack(t *testing.T) {
+	path := serve(t, func(context.Context) ([]*yoloai.SandboxInfo, []yoloai.BackendType, error) {
+		return nil, nil, errors.New("docker is asleep")
+	})
+
+	_, err := NewClient(path).Sandboxes(t.Context())
+	require.ErrorContains(t, err, "daemon: docker is asleep")
+	assert.NotErrorIs(t, err, ErrNoDaemon)
+}
+
+func TestClient_NoDaemon(t *testing.T) {
+	_, err := NewClient(filepath.Join(t.TempDir(), "daemon.sock")).Sandboxes(t.Context())
+	require.ErrorIs(t, err, ErrNoDaemon)
+}
+
+func TestClient_RefusesAnotherVersionsListing(t *testing.T) {
+	setVersion(t, "1.2.3")
+	path := serve(t, func(context.Context) ([]*yoloai.SandboxInfo, []yoloai.BackendType, error) {
+		return nil, nil, nil
+	})
+	c := NewClient(path)
+	buildinfo.Version = "1.2.4" // this CLI was upgraded; the daemon wasn't restarted
+
+	_, err := c.Sandboxes(t.Context())
+	require.ErrorIs(t, err, ErrVersionMismatch)
+	_, err = c.Status(t.Context())
+	assert.NoError(t, err, "status is readable across versions")
+}
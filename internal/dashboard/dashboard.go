@@ -0,0 +1,275 @@
+// ABOUTME: Local web dashboard behind `yoloai serve`: an HTTP handler serving
+// ABOUTME: one page plus a small JSON API (list, log tail, diff, apply, destroy)
+// ABOUTME: and a server-sent event stream of sandbox changes.
+
+// Package dashboard implements the local web dashboard started by
+// `yoloai serve`. It is an http.Handler over a SandboxService, so the CLI
+// supplies the real sandbox operations and tests a fake.
+package dashboard
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	_ "embed"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html/template"
+	"net"
+	"net/http"
+	"strconv"
+
+	"github.com/kstenerud/yoloai"
+	"github.com/kstenerud/yoloai/internal/config"
+	"github.com/kstenerud/yoloai/internal/events"
+	"github.com/kstenerud/yoloai/yoerrors"
+)
+
+// TokenHeader carries the per-server token on every API request. The page
+// gets the token embedded when it is served; another site open in the same
+// browser can't read it, so it can't drive the API (CSRF).
+const TokenHeader = "X-Yoloai-Token"
+
+// DefaultLogLines is how much of an agent's terminal log a tail returns when
+// the request doesn't say.
+const DefaultLogLines = 200
+
+// maxLogLines caps ?lines= so a stray request can't pull a huge log into the page.
+const maxLogLines = 5000
+
+//go:embed index.html
+var indexHTML string
+
+var indexTmpl = template.Must(template.New("index").Parse(indexHTML))
+
+// SandboxService is the set of sandbox operations the dashboard drives.
+type SandboxService interface {
+	// List returns every sandbox across backends, plus the backends that
+	// couldn't be reached.
+	List(ctx context.Context) ([]*yoloai.SandboxInfo, []yoloai.BackendType, error)
+	// Log returns the last lines of the agent's terminal log, ANSI stripped.
+	Log(ctx context.Context, name string, lines int) (string, error)
+	// Diff returns the sandbox's changes against its baseline.
+	Diff(ctx context.Context, name string) (string, error)
+	// Apply lands the sandbox's changes on the host the way a plain
+	// `yoloai apply --yes` would, returning a one-line summary.
+	Apply(ctx context.Context, name string) (string, error)
+	// Destroy removes the sandbox. Without abandonUnapplied it refuses a
+	// sandbox holding unapplied work with a *yoerrors.ActiveWorkError.
+	Destroy(ctx context.Context, name string, abandonUnapplied bool) error
+}
+
+// Server is the dashboard's http.Handler.
+type Server struct {
+	svc   SandboxService
+	hub   *events.Hub
+	token string
+	hosts map[string]bool
+	mux   *http.ServeMux
+}
+
+// New returns a dashboard over svc, streaming hub's events at /api/events
+// (hub may be nil, which turns the stream off). Requests must name one of
+// hosts (or a loopback name) in their Host header, which shuts out
+// DNS-rebinding pages that resolve their own domain to this server.
+func New(svc SandboxService, hub *events.Hub, hosts ...string) (*Server, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return nil, err
+	}
+	s := &Server{
+		svc:   svc,
+		hub:   hub,
+		token: hex.EncodeToString(buf),
+		hosts: map[string]bool{"localhost": true, "127.0.0.1": true, "::1": true},
+		mux:   http.NewServeMux(),
+	}
+	for _, h := range hosts {
+		s.hosts[h] = true
+	}
+	s.mux.HandleFunc("GET /{$}", s.handleIndex)
+	s.mux.HandleFunc("GET /api/sandboxes", s.api(s.handleList))
+	s.mux.HandleFunc("GET /api/sandboxes/{name}/log", s.api(s.handleLog))
+	s.mux.HandleFunc("GET /api/sandboxes/{name}/diff", s.api(s.handleDiff))
+	s.mux.HandleFunc("POST /api/sandboxes/{name}/apply", s.api(s.handleApply))
+	s.mux.HandleFunc("POST /api/sandboxes/{name}/destroy", s.api(s.handleDestroy))
+	if hub != nil {
+		s.mux.HandleFunc("GET /api/events", s.handleEvents)
+	}
+	return s, nil
+}
+
+// Token returns the token API requests must carry in TokenHeader.
+func (s *Server) Token() string { return s.token }
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	host := r.Host
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	if !s.hosts[host] {
+		http.Error(w, "unexpected Host header", http.StatusForbidden)
+		return
+	}
+	w.Header().Set("X-Frame-Options", "DENY")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	s.mux.ServeHTTP(w, r)
+}
+
+func (s *Server) handleIndex(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	_ = indexTmpl.Execute(w, struct{ Token string }{s.token}) //nolint:errcheck // client gone; nothing to report to
+}
+
+// api wraps an API handler: it checks the token, and writes the handler's
+// result as JSON or its error with a status that fits it.
+func (s *Server) api(fn func(*http.Request) (any, error)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !s.tokenOK(r.Header.Get(TokenHeader)) {
+			writeJSON(w, http.StatusForbidden, map[string]string{"error": "missing or wrong " + TokenHeader})
+			return
+		}
+		if name := r.PathValue("name"); name != "" {
+			if _, err := config.ParseSandboxName(name); err != nil {
+				writeError(w, err)
+				return
+			}
+		}
+		result, err := fn(r)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, result)
+	}
+}
+
+func (s *Server) tokenOK(got string) bool {
+	return subtle.ConstantTimeCompare([]byte(got), []byte(s.token)) == 1
+}
+
+// handleEvents streams the hub's events as server-sent events, one JSON
+// event per message, until the client goes away or the server shuts down.
+// A browser's EventSource can't set headers, so the token may come as
+// ?token= instead; the stream only reads, so the URL holding it is no worse
+// than the page that embeds it.
+func (s *Server) handleEvents(w http.ResponseWriter, r *http.Request) {
+	token := r.Header.Get(TokenHeader)
+	if token == "" {
+		token = r.URL.Query().Get("token")
+	}
+	if !s.tokenOK(token) {
+		writeJSON(w, http.StatusForbidden, map[string]string{"error": "missing or wrong " + TokenHeader})
+		return
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "streaming unsupported"})
+		return
+	}
+	evs, cancel := s.hub.Subscribe()
+	defer cancel()
+	stop := context.AfterFunc(r.Context(), cancel)
+	defer stop()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+	for ev := range evs {
+		data, err := json.Marshal(ev)
+		if err != nil {
+			return
+		}
+		if _, err := fmt.Fprintf(w, "data: %s\n\n", data); err != nil {
+			return
+		}
+		flusher.Flush()
+	}
+}
+
+type listResponse struct {
+	Sandboxes           []*yoloai.SandboxInfo `json:"sandboxes"`
+	UnavailableBackends []yoloai.BackendType  `json:"unavailable_backends"`
+}
+
+func (s *Server) handleList(r *http.Request) (any, error) {
+	infos, unavailable, err := s.svc.List(r.Context())
+	if err != nil {
+		return nil, err
+	}
+	if infos == nil {
+		infos = []*yoloai.SandboxInfo{}
+	}
+	if unavailable == nil {
+		unavailable = []yoloai.BackendType{}
+	}
+	return listResponse{Sandboxes: infos, UnavailableBackends: unavailable}, nil
+}
+
+func (s *Server) handleLog(r *http.Request) (any, error) {
+	lines := DefaultLogLines
+	if v := r.URL.Query().Get("lines"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 {
+			return nil, yoerrors.NewUsageError("lines must be a positive number: %q", v)
+		}
+		lines = min(n, maxLogLines)
+	}
+	out, err := s.svc.Log(r.Context(), r.PathValue("name"), lines)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]string{"log": out}, nil
+}
+
+func (s *Server) handleDiff(r *http.Request) (any, error) {
+	out, err := s.svc.Diff(r.Context(), r.PathValue("name"))
+	if err != nil {
+		return nil, err
+	}
+	return map[string]string{"diff": out}, nil
+}
+
+func (s *Server) handleApply(r *http.Request) (any, error) {
+	msg, err := s.svc.Apply(r.Context(), r.PathValue("name"))
+	if err != nil {
+		return nil, err
+	}
+	return map[string]string{"message": msg}, nil
+}
+
+func (s *Server) handleDestroy(r *http.Request) (any, error) {
+	abandon := r.URL.Query().Get("abandon") == "true"
+	if err := s.svc.Destroy(r.Context(), r.PathValue("name"), abandon); err != nil {
+		return nil, err
+	}
+	return map[string]string{"message": "destroyed " + r.PathValue("name")}, nil
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	var (
+		usage  *yoerrors.UsageError
+		active *yoerrors.ActiveWorkError
+	)
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, yoloai.ErrSandboxNotFound):
+		status = http.StatusNotFound
+	case errors.As(err, &active):
+		status = http.StatusConflict
+	case errors.As(err, &usage):
+		status = http.StatusBadRequest
+	}
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v) //nolint:errcheck // client gone; nothing to report to
+}
@@ -0,0 +1,215 @@
+// ABOUTME: Tests for the dashboard handler: the token and Host checks, the
+// ABOUTME: JSON API over a fake SandboxService, error-to-status mapping, and the
+// ABOUTME: event stream.
+package dashboard
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kstenerud/yoloai"
+	"github.com/kstenerud/yoloai/internal/events"
+	"github.com/kstenerud/yoloai/yoerrors"
+)
+
+type fakeService struct {
+	infos     []*yoloai.SandboxInfo
+	logLines  int
+	applied   []string
+	destroyed map[string]bool
+	destroyFn func(name string, abandon bool) error
+}
+
+func (f *fakeService) List(context.Context) ([]*yoloai.SandboxInfo, []yoloai.BackendType, error) {
+	return f.infos, []yoloai.BackendType{"tart"}, nil
+}
+
+func (f *fakeService) Log(_ context.Context, name string, lines int) (string, error) {
+	f.logLines = lines
+	if name == "missing" {
+		return "", yoloai.ErrSandboxNotFound
+	}
+	return "log of " + name, nil
+}
+
+func (f *fakeService) Diff(_ context.Context, name string) (string, error) {
+	return "diff of " + name, nil
+}
+
+func (f *fakeService) Apply(_ context.Context, name string) (string, error) {
+	f.applied = append(f.applied, name)
+	return "applied " + name, nil
+}
+
+func (f *fakeService) Destroy(_ context.Context, name string, abandon bool) error {
+	if f.destroyFn != nil {
+		if err := f.destroyFn(name, abandon); err != nil {
+			return err
+		}
+	}
+	f.destroyed[name] = abandon
+	return nil
+}
+
+func newTestServer(t *testing.T) (*Server, *fakeService) {
+	t.Helper()
+	svc := &fakeService{
+		infos:     []*yoloai.SandboxInfo{{Environment: &yoloai.Environment{Name: "box"}, Status: yoloai.StatusActive}},
+		destroyed: map[string]bool{},
+	}
+	s, err := New(svc, nil, "127.0.0.1")
+	require.NoError(t, err)
+	return s, svc
+}
+
+func do(s *Server, method, target, token string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, target, nil)
+	req.Host = "127.0.0.1:7777"
+	if token != "" {
+		req.Header.Set(TokenHeader, token)
+	}
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, req)
+	return rec
+}
+
+func decode(t *testing.T, rec *httptest.ResponseRecorder) map[string]any {
+	t.Helper()
+	var out map[string]any
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &out), rec.Body.String())
+	return out
+}
+
+func TestIndex_EmbedsToken(t *testing.T) {
+	s, _ := newTestServer(t)
+	rec := do(s, http.MethodGet, "/", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `content="`+s.Token()+`"`)
+	assert.Equal(t, "DENY", rec.Header().Get("X-Frame-Options"))
+}
+
+func TestAPI_RequiresToken(t *testing.T) {
+	s, svc := newTestServer(t)
+	assert.Equal(t, http.StatusForbidden, do(s, http.MethodGet, "/api/sandboxes", "").Code)
+	assert.Equal(t, http.StatusForbidden, do(s, http.MethodPost, "/api/sandboxes/box/destroy", "wrong").Code)
+	assert.Empty(t, svc.destroyed, "a request without the token never reaches the service")
+}
+
+func TestAPI_RejectsForeignHost(t *testing.T) {
+	s, _ := newTestServer(t)
+	req := httptest.NewRequest(http.MethodGet, "/api/sandboxes", nil)
+	req.Host = "attacker.example:7777"
+	req.Header.Set(TokenHeader, s.Token())
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+}
+
+func TestAPI_List(t *testing.T) {
+	s, _ := newTestServer(t)
+	rec := do(s, http.MethodGet, "/api/sandboxes", s.Token())
+	require.Equal(t, http.StatusOK, rec.Code)
+	out := decode(t, rec)
+	sandboxes := out["sandboxes"].([]any)
+	require.Len(t, sandboxes, 1)
+	assert.Equal(t, "active", sandboxes[0].(map[string]any)["status"])
+	assert.Equal(t, []any{"tart"}, out["unavailable_backends"])
+}
+
+func TestAPI_Log(t *testing.T) {
+	s, svc := newTestServer(t)
+	rec := do(s, http.MethodGet, "/api/sandboxes/box/log", s.Token())
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "log of box", decode(t, rec)["log"])
+	assert.Equal(t, DefaultLogLines, svc.logLines)
+
+	do(s, http.MethodGet, "/api/sandboxes/box/log?lines=999999", s.Token())
+	assert.Equal(t, maxLogLines, svc.logLines, "lines is capped")
+
+	assert.Equal(t, http.StatusBadRequest, do(s, http.MethodGet, "/api/sandboxes/box/log?lines=x", s.Token()).Code)
+	assert.Equal(t, http.StatusNotFound, do(s, http.MethodGet, "/api/sandboxes/missing/log", s.Token()).Code)
+	assert.Equal(t, http.StatusBadRequest, do(s, http.MethodGet, "/api/sandboxes/..bad/log", s.Token()).Code)
+}
+
+func TestAPI_DiffAndApply(t *testing.T) {
+	s, svc := newTestServer(t)
+	rec := do(s, http.MethodGet, "/api/sandboxes/box/diff", s.Token())
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "diff of box", decode(t, rec)["diff"])
+
+	assert.Equal(t, http.StatusMethodNotAllowed, do(s, http.MethodGet, "/api/sandboxes/box/apply", s.Token()).Code)
+	assert.Empty(t, svc.applied, "apply is POST only")
+	rec = do(s, http.MethodPost, "/api/sandboxes/box/apply", s.Token())
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "applied box", decode(t, rec)["message"])
+	assert.Equal(t, []string{"box"}, svc.applied)
+}
+
+func TestAPI_DestroyRefusesUnappliedWork(t *testing.T) {
+	s, svc := newTestServer(t)
+	svc.destroyFn = func(_ string, abandon bool) error {
+		if !abandon {
+			return yoerrors.NewActiveWorkError("sandbox box has unapplied commits")
+		}
+		return nil
+	}
+
+	rec := do(s, http.MethodPost, "/api/sandboxes/box/destroy", s.Token())
+	assert.Equal(t, http.StatusConflict, rec.Code)
+	assert.Equal(t, "sandbox box has unapplied commits", decode(t, rec)["error"])
+	assert.Empty(t, svc.destroyed)
+
+	rec = do(s, http.MethodPost, "/api/sandboxes/box/destroy?abandon=true", s.Token())
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, map[string]bool{"box": true}, svc.destroyed)
+}
+
+func TestEvents_Stream(t *testing.T) {
+	hub := events.NewHub()
+	hub.Update([]*yoloai.SandboxInfo{{Environment: &yoloai.Environment{Name: "box"}, Status: yoloai.StatusActive}}, nil)
+	s, err := New(&fakeService{}, hub)
+	require.NoError(t, err)
+	srv := httptest.NewServer(s)
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "/api/events")
+	require.NoError(t, err)
+	resp.Body.Close() //nolint:errcheck,gosec // test
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+
+	ctx, cancel := context.WithCancel(t.Context())
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL+"/api/events?token="+s.Token(), nil)
+	require.NoError(t, err)
+	resp, err = http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close() //nolint:errcheck // test
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+
+	r := bufio.NewReader(resp.Body)
+	line, err := r.ReadString('\n')
+	require.NoError(t, err)
+	assert.Contains(t, line, `"type":"snapshot"`)
+	assert.True(t, strings.HasPrefix(line, "data: "))
+
+	hub.Update(nil, nil)
+	for line == "\n" || strings.Contains(line, "snapshot") {
+		line, err = r.ReadString('\n')
+		require.NoError(t, err)
+	}
+	assert.Contains(t, line, `"type":"destroyed"`)
+}
+
+func TestEvents_OffWithoutHub(t *testing.T) {
+	s, _ := newTestServer(t)
+	assert.Equal(t, http.StatusNotFound, do(s, http.MethodGet, "/api/events", s.Token()).Code)
+}
@@ -68,6 +68,24 @@ func GenerateContext(sandboxDir string, meta *store.Environment) string {
 	fmt.Fprintf(&b, "Files shared via `yoloai files put` appear here, and anything you write here can be retrieved by the user with `yoloai files get`.\n")
 	fmt.Fprintf(&b, "Use this for artifacts the user needs to see — generated reports, exported files, etc.\n")
 
+	// Outbox section
+	outboxPath := rtDir + "/outbox/"
+	if meta.HostFilesystem {
+		outboxPath = filepath.Join(sandboxDir, "outbox") + "/"
+	}
+	b.WriteString("\n## Outbox\n\n")
+	fmt.Fprintf(&b, "The **outbox** is at `%s`.\n", outboxPath)
+	b.WriteString("When your task produces a deliverable that is not a code change — a report, a generated image, exported data — write it here. ")
+	b.WriteString("The user lists and collects these with `yoloai artifacts`; they never appear in the diff of your work.\n")
+
+	// Result section
+	b.WriteString("\n## Result\n\n")
+	fmt.Fprintf(&b, "When you finish a task, write `%sresult.json` so tools can act on the outcome:\n\n", outboxPath)
+	b.WriteString("```json\n")
+	b.WriteString("{\"status\": \"success\", \"summary\": \"one paragraph\", \"follow_ups\": [\"...\"], \"files\": [\"path/of/interest\"]}\n")
+	b.WriteString("```\n\n")
+	b.WriteString("`status` is one of `success`, `partial`, `failed`, `blocked`. Overwrite the file after each task.\n")
+
 	// Cache section
 	b.WriteString("\n## Cache\n\n")
 	fmt.Fprintf(&b, "The **cache directory** is at `%s`.\n", cachePath)
@@ -81,6 +99,7 @@ func GenerateContext(sandboxDir string, meta *store.Environment) string {
 	b.WriteString("When the user says:\n\n")
 	fmt.Fprintf(&b, "- \"the cache\" — they mean the cache directory (`%s`)\n", cachePath)
 	fmt.Fprintf(&b, "- \"the files dir\" or \"shared files\" — they mean the shared files directory (`%s`)\n", filesPath)
+	fmt.Fprintf(&b, "- \"the outbox\" or \"artifacts\" — they mean the outbox (`%s`)\n", outboxPath)
 
 	// Resources section (only when resources are set)
 	if meta.Resources != nil {
@@ -162,7 +181,9 @@ func writeDir(b *strings.Builder, mountPath, hostPath string, mode store.DirMode
 // WriteContextFiles writes the sandbox context file and optional per-agent
 // instruction file into the sandbox directory. The agent's native context
 // filename comes from EnvSpec.ContextFile (compiled at the orchestrator
-// boundary), keeping this assembler agent-agnostic.
+// boundary), keeping this assembler agent-agnostic. meta.Context other than
+// ContextModeAgent leaves the instruction file alone: the context goes to the
+// work copy (WriteWorktreeContext) or nowhere.
 func WriteContextFiles(sandboxDir string, meta *store.Environment, spec EnvSpec) error {
 	content := GenerateContext(sandboxDir, meta)
 
@@ -173,7 +194,7 @@ func WriteContextFiles(sandboxDir string, meta *store.Environment, spec EnvSpec)
 	}
 
 	// Write full context inline into the agent's native instruction file.
-	if spec.ContextFile != "" && spec.HasStateDir {
+	if meta.Context == store.ContextModeAgent && spec.ContextFile != "" && spec.HasStateDir {
 		refPath := filepath.Join(sandboxDir, store.AgentRuntimeDir, spec.ContextFile)
 
 		// Append, don't clobber (D92): the seed/agent_files stage runs BEFORE this
@@ -182,6 +203,11 @@ func WriteContextFiles(sandboxDir string, meta *store.Environment, spec EnvSpec)
 		// the user's instructions; instead the yoloAI orientation is appended after
 		// it (separated by a blank line when the seeded file is non-empty). When no
 		// file was seeded, this creates it fresh — byte-identical to the old write.
+		// A seeded file saved by a Windows editor is normalized to LF first, so
+		// the appended LF sections don't leave it with mixed line endings.
+		if err := normalizeSeededFile(refPath); err != nil {
+			return fmt.Errorf("normalize agent context file %s: %w", spec.ContextFile, err)
+		}
 		sep := ""
 		if info, statErr := os.Stat(refPath); statErr == nil && info.Size() > 0 {
 			sep = "\n\n"
@@ -197,21 +223,7 @@ func WriteContextFiles(sandboxDir string, meta *store.Environment, spec EnvSpec)
 		// declared ContextFile, so every agent that reads a native context file
 		// gets it (not just Claude's CLAUDE.md). The fuller DEF-fan-in delivered
 		// per the agent's injection method is the envsetup re-homing (D91/D92).
-		filesDir := runtimeDir(sandboxDir, meta) + "/files"
-		qa := "\n## yoloAI File Exchange Protocol\n\n" +
-			"You are running inside a yoloAI sandbox. A file exchange directory is\n" +
-			"available at `" + filesDir + "/` — readable and writable from both inside\n" +
-			"and outside the sandbox.\n\n" +
-			"**When you need to ask a question or need input to continue:**\n\n" +
-			"1. Write your question to `" + filesDir + "/question.json`:\n" +
-			"   ```json\n" +
-			"   {\"question\": \"your question here\", \"context\": \"optional context\"}\n" +
-			"   ```\n" +
-			"2. Poll `" + filesDir + "/answer.json` every 5 seconds until it appears.\n" +
-			"3. Read the answer and continue your task.\n\n" +
-			"Do not make assumptions about blocking decisions. Write the question file\n" +
-			"and wait. The question will be seen and answered by an external agent or user.\n"
-		if err := appendToFile(refPath, qa); err != nil {
+		if err := appendToFile(refPath, fileExchangeProtocol(sandboxDir, meta)); err != nil {
 			return fmt.Errorf("append Q&A protocol to %s: %w", spec.ContextFile, err)
 		}
 	}
@@ -219,6 +231,81 @@ func WriteContextFiles(sandboxDir string, meta *store.Environment, spec EnvSpec)
 	return nil
 }
 
+// WriteWorktreeContext writes the sandbox context and the Q&A protocol to
+// meta.ContextFile at the root of the workdir's work copy, and lists it in the
+// copy's .git/info/exclude so diff and apply never carry it to the host. Only
+// for ContextModeWorktree, whose :copy requirement keeps it out of the live
+// directory. Create calls it once the work copy exists; reset calls it again
+// after re-copying, which drops the file.
+func WriteWorktreeContext(sandboxDir string, meta *store.Environment) error {
+	if meta.Context != store.ContextModeWorktree || meta.ContextFile == "" {
+		return nil
+	}
+	workCopy := store.WorkDir(sandboxDir, meta.Workdir().HostPath)
+	content := GenerateContext(sandboxDir, meta) + fileExchangeProtocol(sandboxDir, meta)
+	if err := fileutil.WriteFile(filepath.Join(workCopy, meta.ContextFile), []byte(content), 0600); err != nil {
+		return fmt.Errorf("write %s: %w", meta.ContextFile, err)
+	}
+
+	excludePath := filepath.Join(workCopy, ".git", "info", "exclude")
+	pattern := "/" + meta.ContextFile
+	existing, err := os.ReadFile(excludePath) //nolint:gosec // G304: path within the sandbox's work copy
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("read %s: %w", excludePath, err)
+	}
+	for _, line := range strings.Split(string(existing), "\n") {
+		if strings.TrimSpace(line) == pattern {
+			return nil
+		}
+	}
+	if err := fileutil.MkdirAll(filepath.Dir(excludePath), 0750); err != nil {
+		return fmt.Errorf("create %s: %w", filepath.Dir(excludePath), err)
+	}
+	sep := ""
+	if len(existing) > 0 && !strings.HasSuffix(string(existing), "\n") {
+		sep = "\n"
+	}
+	if err := appendToFile(excludePath, sep+"# yoloai sandbox context (--context worktree)\n"+pattern+"\n"); err != nil {
+		return fmt.Errorf("exclude %s from the work copy: %w", meta.ContextFile, err)
+	}
+	return nil
+}
+
+// fileExchangeProtocol is the Q&A protocol section appended after the context.
+func fileExchangeProtocol(sandboxDir string, meta *store.Environment) string {
+	filesDir := runtimeDir(sandboxDir, meta) + "/files"
+	return "\n## yoloAI File Exchange Protocol\n\n" +
+		"You are running inside a yoloAI sandbox. A file exchange directory is\n" +
+		"available at `" + filesDir + "/` — readable and writable from both inside\n" +
+		"and outside the sandbox.\n\n" +
+		"**When you need to ask a question or need input to continue:**\n\n" +
+		"1. Write your question to `" + filesDir + "/question.json`:\n" +
+		"   ```json\n" +
+		"   {\"question\": \"your question here\", \"context\": \"optional context\"}\n" +
+		"   ```\n" +
+		"2. Poll `" + filesDir + "/answer.json` every 5 seconds until it appears.\n" +
+		"3. Read the answer and continue your task.\n\n" +
+		"Do not make assumptions about blocking decisions. Write the question file\n" +
+		"and wait. The question will be seen and answered by an external agent or user.\n"
+}
+
+// normalizeSeededFile rewrites the file at path with LF line endings when it
+// contains carriage returns. A missing file is not an error.
+func normalizeSeededFile(path string) error {
+	data, err := os.ReadFile(path) //nolint:gosec // G304: path within sandbox dir
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	normalized := fileutil.NormalizeNewlines(string(data))
+	if normalized == string(data) {
+		return nil
+	}
+	return fileutil.WriteFile(path, []byte(normalized), 0600)
+}
+
 // appendToFile appends s to the file at path, creating it (0600) if absent.
 func appendToFile(path, s string) error {
 	f, err := fileutil.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
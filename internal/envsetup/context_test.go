@@ -50,6 +50,9 @@ func assertContextContains(t *testing.T, result string) {
 		{"/data/shared (rw)", "missing rw aux dir"},
 		{"## Files", "missing Files section"},
 		{"/yoloai/files/", "missing files exchange path"},
+		{"## Outbox", "missing Outbox section"},
+		{"/yoloai/outbox/", "missing outbox path"},
+		{"/yoloai/outbox/result.json", "missing result contract path"},
 		{"## Cache", "missing Cache section"},
 		{"/yoloai/cache/", "missing cache path"},
 		{"## Terminology", "missing Terminology section"},
@@ -349,3 +352,108 @@ func TestWriteContextFiles_AppendsNotClobbers(t *testing.T) {
 		t.Error("the yoloAI orientation must be appended after the user's content")
 	}
 }
+
+// TestWriteContextFiles_NormalizesSeededCRLF verifies that a seeded context
+// file saved with Windows line endings ends up uniformly LF after the yoloAI
+// sections are appended, rather than mixing CRLF and LF.
+func TestWriteContextFiles_NormalizesSeededCRLF(t *testing.T) {
+	sandboxDir := t.TempDir()
+	runtimeDir := filepath.Join(sandboxDir, store.AgentRuntimeDir)
+	if err := os.MkdirAll(runtimeDir, 0750); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(runtimeDir, "CLAUDE.md"), []byte("# Mine\r\nuse tabs\r\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	meta := &store.Environment{
+		Dirs: []store.DirEnvironment{{HostPath: "/project", MountPath: "/project", Mode: "copy"}},
+	}
+	spec := EnvSpec{ContextFile: "CLAUDE.md", HasStateDir: true}
+	if err := WriteContextFiles(sandboxDir, meta, spec); err != nil {
+		t.Fatalf("WriteContextFiles: %v", err)
+	}
+
+	refData, err := os.ReadFile(filepath.Join(runtimeDir, "CLAUDE.md")) //nolint:gosec // G304: test helper path
+	if err != nil {
+		t.Fatalf("read CLAUDE.md: %v", err)
+	}
+	got := string(refData)
+	if strings.Contains(got, "\r") {
+		t.Error("the context file still contains carriage returns")
+	}
+	if !strings.HasPrefix(got, "# Mine\nuse tabs\n") {
+		t.Errorf("the seeded content was not preserved, got prefix %q", got[:min(len(got), 40)])
+	}
+}
+
+// TestWriteContextFiles_ElsewhereLeavesAgentFile verifies that --context
+// worktree and none don't touch the agent's instruction file.
+func TestWriteContextFiles_ElsewhereLeavesAgentFile(t *testing.T) {
+	for _, mode := range []store.ContextMode{store.ContextModeWorktree, store.ContextModeNone} {
+		sandboxDir := t.TempDir()
+		if err := os.MkdirAll(filepath.Join(sandboxDir, store.AgentRuntimeDir), 0750); err != nil {
+			t.Fatal(err)
+		}
+		meta := &store.Environment{
+			Dirs:    []store.DirEnvironment{{HostPath: "/project", MountPath: "/project", Mode: "copy"}},
+			Context: mode,
+		}
+		if err := WriteContextFiles(sandboxDir, meta, EnvSpec{ContextFile: "CLAUDE.md", HasStateDir: true}); err != nil {
+			t.Fatalf("%s: WriteContextFiles: %v", mode, err)
+		}
+		if _, err := os.Stat(filepath.Join(sandboxDir, store.AgentRuntimeDir, "CLAUDE.md")); !os.IsNotExist(err) {
+			t.Errorf("%s: the agent instruction file was written", mode)
+		}
+		if _, err := os.Stat(filepath.Join(sandboxDir, "context.md")); err != nil {
+			t.Errorf("%s: the reference copy is missing: %v", mode, err)
+		}
+	}
+}
+
+func TestWriteWorktreeContext(t *testing.T) {
+	sandboxDir := t.TempDir()
+	meta := &store.Environment{
+		Dirs:        []store.DirEnvironment{{HostPath: "/project", MountPath: "/project", Mode: "copy"}},
+		Context:     store.ContextModeWorktree,
+		ContextFile: "AGENTS.md",
+	}
+	workCopy := store.WorkDir(sandboxDir, "/project")
+	if err := os.MkdirAll(filepath.Join(workCopy, ".git", "info"), 0750); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(workCopy, ".git", "info", "exclude"), []byte("*.log"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	// Twice, as create and then reset would: one exclude entry.
+	for range 2 {
+		if err := WriteWorktreeContext(sandboxDir, meta); err != nil {
+			t.Fatalf("WriteWorktreeContext: %v", err)
+		}
+	}
+	data, err := os.ReadFile(filepath.Join(workCopy, "AGENTS.md")) //nolint:gosec // G304: test helper path
+	if err != nil {
+		t.Fatalf("read AGENTS.md: %v", err)
+	}
+	if !strings.Contains(string(data), "# Sandbox Environment") || !strings.Contains(string(data), "yoloAI File Exchange Protocol") {
+		t.Error("AGENTS.md is missing the context or the Q&A protocol")
+	}
+	exclude, err := os.ReadFile(filepath.Join(workCopy, ".git", "info", "exclude")) //nolint:gosec // G304: test helper path
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := string(exclude), "*.log\n# yoloai sandbox context (--context worktree)\n/AGENTS.md\n"; got != want {
+		t.Errorf("exclude = %q, want %q", got, want)
+	}
+
+	// Any other mode writes nothing.
+	other := t.TempDir()
+	meta.Context = store.ContextModeAgent
+	if err := WriteWorktreeContext(other, meta); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(store.WorkDir(other, "/project")); !os.IsNotExist(err) {
+		t.Error("the default mode wrote into the work copy")
+	}
+}
@@ -0,0 +1,207 @@
+// ABOUTME: Sandbox lifecycle events for subscribers that would otherwise poll
+// ABOUTME: `list`: a Hub that turns successive listings into events and fans them out.
+
+// Package events turns successive sandbox listings into lifecycle and
+// status-change events and fans them out to subscribers. `yoloai daemon run`
+// serves them on a unix socket (ServeSocket) and `yoloai serve` as
+// server-sent events, so status-bar widgets and scripts can subscribe
+// instead of polling `yoloai ls`.
+package events
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/kstenerud/yoloai"
+)
+
+// Event types.
+const (
+	// TypeSnapshot describes a sandbox that already existed when the
+	// subscriber joined; a subscriber gets one per sandbox before any other.
+	TypeSnapshot = "snapshot"
+	// TypeCreated is a sandbox that wasn't in the previous listing.
+	TypeCreated = "created"
+	// TypeStatus is a sandbox whose status changed.
+	TypeStatus = "status"
+	// TypeChanges is a sandbox whose unapplied-changes state changed.
+	TypeChanges = "changes"
+	// TypeDestroyed is a sandbox that is no longer listed.
+	TypeDestroyed = "destroyed"
+)
+
+// subscriberBuffer is how many events a subscriber may fall behind by before
+// it is dropped. A dropped subscriber's channel is closed; it can reconnect
+// and start again from a fresh snapshot.
+const subscriberBuffer = 256
+
+// Event is one change to one sandbox. Status, Changes and Backend describe
+// the sandbox after the change (a destroyed sandbox keeps its last ones).
+type Event struct {
+	Time           time.Time          `json:"time"`
+	Type           string             `json:"type"`
+	Sandbox        string             `json:"sandbox"`
+	Status         yoloai.Status      `json:"status"`
+	PreviousStatus yoloai.Status      `json:"previous_status,omitempty"`
+	Changes        yoloai.ChangeState `json:"changes"`
+	Backend        string             `json:"backend,omitempty"`
+}
+
+// sandboxState is what the Hub remembers of a sandbox between listings.
+type sandboxState struct {
+	status  yoloai.Status
+	changes yoloai.ChangeState
+	backend string
+}
+
+// Hub holds the last listing and the subscribers. Update feeds it listings;
+// Subscribe hands out event streams. The zero value is not usable; use NewHub.
+type Hub struct {
+	mutex  sync.Mutex
+	state  map[string]sandboxState
+	primed bool
+	subs   map[chan Event]struct{}
+}
+
+// NewHub returns a Hub that has seen no listing yet.
+func NewHub() *Hub {
+	return &Hub{state: map[string]sandboxState{}, subs: map[chan Event]struct{}{}}
+}
+
+// Subscribe returns a stream of events, starting with a TypeSnapshot event per
+// sandbox in the last listing, and a func that ends the subscription. The
+// channel is closed when the subscription ends, including when the Hub drops
+// a subscriber that stopped reading.
+func (h *Hub) Subscribe() (<-chan Event, func()) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	ch := make(chan Event, max(subscriberBuffer, len(h.state)))
+	now := time.Now().UTC()
+	for _, name := range sortedNames(h.state) {
+		ch <- newEvent(now, TypeSnapshot, name, h.state[name])
+	}
+	h.subs[ch] = struct{}{}
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			h.mutex.Lock()
+			defer h.mutex.Unlock()
+			if _, ok := h.subs[ch]; ok {
+				delete(h.subs, ch)
+				close(ch)
+			}
+		})
+	}
+}
+
+// Update compares a listing with the previous one, sends the resulting events
+// to every subscriber, and returns them. Sandboxes on an unavailable backend
+// (as System.AllSandboxes reports them) keep their last known state rather
+// than being reported destroyed. The first listing only primes the Hub:
+// sandboxes that exist when watching starts weren't just created.
+func (h *Hub) Update(infos []*yoloai.SandboxInfo, unavailable []yoloai.BackendType) []Event {
+	next := make(map[string]sandboxState, len(infos))
+	for _, info := range infos {
+		if info.Environment == nil || info.Environment.Name == "" {
+			continue
+		}
+		next[info.Environment.Name] = sandboxState{
+			status:  info.Status,
+			changes: info.Changes,
+			backend: string(info.Environment.BackendType),
+		}
+	}
+
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	for name, old := range h.state {
+		if _, listed := next[name]; !listed && slices.Contains(unavailable, yoloai.BackendType(old.backend)) {
+			next[name] = old
+		}
+	}
+	prev, primed := h.state, h.primed
+	h.state, h.primed = next, true
+	if !primed {
+		return nil
+	}
+
+	now := time.Now().UTC()
+	var evs []Event
+	for _, name := range sortedNames(next) {
+		cur := next[name]
+		old, existed := prev[name]
+		switch {
+		case !existed:
+			evs = append(evs, newEvent(now, TypeCreated, name, cur))
+		case old.status != cur.status:
+			ev := newEvent(now, TypeStatus, name, cur)
+			ev.PreviousStatus = old.status
+			evs = append(evs, ev)
+		case old.changes != cur.changes:
+			evs = append(evs, newEvent(now, TypeChanges, name, cur))
+		}
+	}
+	for _, name := range sortedNames(prev) {
+		if _, ok := next[name]; !ok {
+			evs = append(evs, newEvent(now, TypeDestroyed, name, prev[name]))
+		}
+	}
+
+	for ch := range h.subs {
+		for _, ev := range evs {
+			select {
+			case ch <- ev:
+			default:
+				delete(h.subs, ch)
+				close(ch)
+			}
+			if _, ok := h.subs[ch]; !ok {
+				break
+			}
+		}
+	}
+	return evs
+}
+
+// Lister lists sandboxes the way System.AllSandboxes does.
+type Lister func(context.Context) ([]*yoloai.SandboxInfo, []yoloai.BackendType, error)
+
+// Watch lists sandboxes every interval and feeds the listings to the Hub until
+// ctx is cancelled. A failed listing is passed to onErr (when set) and
+// skipped, so a failure doesn't look like every sandbox being destroyed.
+func (h *Hub) Watch(ctx context.Context, interval time.Duration, list Lister, onErr func(error)) {
+	for {
+		infos, unavailable, err := list(ctx)
+		switch {
+		case ctx.Err() != nil:
+			return
+		case err != nil:
+			if onErr != nil {
+				onErr(err)
+			}
+		default:
+			h.Update(infos, unavailable)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(interval):
+		}
+	}
+}
+
+func newEvent(now time.Time, typ, name string, s sandboxState) Event {
+	return Event{Time: now, Type: typ, Sandbox: name, Status: s.status, Changes: s.changes, Backend: s.backend}
+}
+
+func sortedNames(m map[string]sandboxState) []string {
+	names := make([]string, 0, len(m))
+	for name := range m {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,184 @@
+package events
+
+// ABOUTME: Tests for the events Hub (listing diffs, snapshots, slow
+// ABOUTME: subscribers), Watch, and the unix socket.
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kstenerud/yoloai"
+)
+
+func info(name string, status yoloai.Status, changes string) *yoloai.SandboxInfo {
+	return &yoloai.SandboxInfo{
+		Environment: &yoloai.Environment{Name: name, BackendType: "docker"},
+		Status:      status,
+		Changes:     yoloai.ChangeState(changes),
+	}
+}
+
+func types(evs []Event) []string {
+	out := make([]string, len(evs))
+	for i, ev := range evs {
+		out[i] = ev.Type + ":" + ev.Sandbox
+	}
+	return out
+}
+
+func TestHub_Update(t *testing.T) {
+	h := NewHub()
+	assert.Empty(t, h.Update([]*yoloai.SandboxInfo{info("a", yoloai.StatusActive, "no")}, nil),
+		"the first listing only primes the hub")
+
+	evs := h.Update([]*yoloai.SandboxInfo{
+		info("a", yoloai.StatusIdle, "no"),
+		info("b", yoloai.StatusActive, "no"),
+	}, nil)
+	assert.Equal(t, []string{"status:a", "created:b"}, types(evs))
+	assert.Equal(t, yoloai.StatusIdle, evs[0].Status)
+	assert.Equal(t, yoloai.StatusActive, evs[0].PreviousStatus)
+	assert.Equal(t, "docker", evs[1].Backend)
+
+	evs = h.Update([]*yoloai.SandboxInfo{info("a", yoloai.StatusIdle, "yes")}, nil)
+	assert.Equal(t, []string{"changes:a", "destroyed:b"}, types(evs))
+	assert.Equal(t, yoloai.ChangeState("yes"), evs[0].Changes)
+	assert.Equal(t, yoloai.StatusActive, evs[1].Status, "a destroyed sandbox keeps its last status")
+
+	gone := info("c", yoloai.StatusActive, "no")
+	gone.Environment.BackendType = "tart"
+	h.Update([]*yoloai.SandboxInfo{info("a", yoloai.StatusIdle, "yes"), gone}, nil)
+	assert.Empty(t, h.Update([]*yoloai.SandboxInfo{info("a", yoloai.StatusIdle, "yes")}, []yoloai.BackendType{"tart"}),
+		"a sandbox on an unreachable backend isn't destroyed")
+	assert.Equal(t, []string{"destroyed:c"}, types(h.Update([]*yoloai.SandboxInfo{info("a", yoloai.StatusIdle, "yes")}, nil)))
+
+	assert.Empty(t, h.Update([]*yoloai.SandboxInfo{info("a", yoloai.StatusIdle, "yes"), {}}, nil),
+		"nothing changed, and an entry without an environment is skipped")
+}
+
+func TestHub_Subscribe(t *testing.T) {
+	h := NewHub()
+	h.Update([]*yoloai.SandboxInfo{info("b", yoloai.StatusIdle, "no"), info("a", yoloai.StatusActive, "no")}, nil)
+
+	evs, cancel := h.Subscribe()
+	assert.Equal(t, []string{"snapshot:a", "snapshot:b"}, types([]Event{<-evs, <-evs}))
+
+	h.Update([]*yoloai.SandboxInfo{info("a", yoloai.StatusActive, "no")}, nil)
+	assert.Equal(t, Event{Type: TypeDestroyed, Sandbox: "b", Status: yoloai.StatusIdle, Changes: "no", Backend: "docker"},
+		withoutTime(<-evs))
+
+	cancel()
+	cancel()
+	_, open := <-evs
+	assert.False(t, open, "cancel closes the stream")
+}
+
+func TestHub_DropsSlowSubscriber(t *testing.T) {
+	h := NewHub()
+	h.Update(nil, nil)
+	evs, cancel := h.Subscribe()
+	defer cancel()
+	for i := range subscriberBuffer + 1 {
+		status := yoloai.StatusActive
+		if i%2 == 1 {
+			status = yoloai.StatusIdle
+		}
+		h.Update([]*yoloai.SandboxInfo{info("a", status, "no")}, nil)
+	}
+	n := 0
+	for range evs {
+		n++
+	}
+	assert.Equal(t, subscriberBuffer, n, "the stream is closed once the buffer overflows")
+}
+
+func TestHub_Watch(t *testing.T) {
+	h := NewHub()
+	ctx, cancel := context.WithCancel(t.Context())
+	listings := [][]*yoloai.SandboxInfo{
+		{info("a", yoloai.StatusActive, "no")},
+		nil, // a failed listing
+		{info("a", yoloai.StatusDone, "no")},
+	}
+	var errs []error
+	calls := 0
+	evs, unsubscribe := h.Subscribe()
+	defer unsubscribe()
+	h.Watch(ctx, time.Millisecond, func(context.Context) ([]*yoloai.SandboxInfo, []yoloai.BackendType, error) {
+		calls++
+		if calls > len(listings) {
+			cancel()
+			return nil, nil, ctx.Err()
+		}
+		if listings[calls-1] == nil {
+			return nil, nil, errors.New("backend down")
+		}
+		return listings[calls-1], nil, nil
+	}, func(err error) { errs = append(errs, err) })
+
+	require.Len(t, errs, 1)
+	ev := <-evs
+	assert.Equal(t, TypeStatus, ev.Type, "a failed listing doesn't look like a destroy")
+	assert.Equal(t, yoloai.StatusDone, ev.Status)
+}
+
+func TestServeSocket(t *testing.T) {
+	// Unix socket paths are short on macOS; t.TempDir can be too long.
+	dir, err := os.MkdirTemp("", "yev")
+	require.NoError(t, err)
+	t.Cleanup(func() { os.RemoveAll(dir) }) //nolint:errcheck,gosec // test cleanup
+	path := filepath.Join(dir, "events.sock")
+
+	h := NewHub()
+	h.Update([]*yoloai.SandboxInfo{info("a", yoloai.StatusActive, "no")}, nil)
+	ln, err := ListenSocket(path)
+	require.NoError(t, err)
+	fi, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o600), fi.Mode().Perm())
+
+	_, err = ListenSocket(path)
+	require.ErrorIs(t, err, ErrSocketInUse)
+
+	ctx, cancel := context.WithCancel(t.Context())
+	done := make(chan error, 1)
+	go func() { done <- ServeSocket(ctx, ln, h) }()
+
+	conn, err := net.Dial("unix", path)
+	require.NoError(t, err)
+	defer conn.Close() //nolint:errcheck // test
+	r := bufio.NewScanner(conn)
+	var ev Event
+	require.True(t, r.Scan())
+	require.NoError(t, json.Unmarshal(r.Bytes(), &ev))
+	assert.Equal(t, TypeSnapshot, ev.Type)
+
+	h.Update([]*yoloai.SandboxInfo{info("a", yoloai.StatusIdle, "no")}, nil)
+	require.True(t, r.Scan())
+	assert.Contains(t, r.Text(), `"type":"status"`)
+	assert.Contains(t, r.Text(), `"previous_status":"active"`)
+
+	cancel()
+	require.NoError(t, <-done)
+	assert.False(t, r.Scan(), "connections are closed on shutdown")
+
+	// Once the server is gone the socket can be listened on again.
+	ln, err = ListenSocket(path)
+	require.NoError(t, err)
+	ln.Close() //nolint:errcheck,gosec // test
+}
+
+func withoutTime(ev Event) Event {
+	ev.Time = time.Time{}
+	return ev
+}
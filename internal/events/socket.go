@@ -0,0 +1,98 @@
+// ABOUTME: The events unix socket: every connection gets the Hub's stream as
+// ABOUTME: JSON lines until either end closes it.
+
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"os"
+	"sync"
+	"time"
+)
+
+// ErrSocketInUse is returned by ListenSocket when another process is already
+// serving on the socket.
+var ErrSocketInUse = errors.New("socket is in use by another process")
+
+// ListenSocket listens on the unix socket at path, readable only by the user.
+// The daemon's API socket (internal/daemonapi) is opened the same way.
+// A socket file left behind by a process that is gone is replaced; one that
+// still answers is ErrSocketInUse.
+func ListenSocket(path string) (net.Listener, error) {
+	if _, err := os.Stat(path); err == nil {
+		if conn, err := net.DialTimeout("unix", path, time.Second); err == nil {
+			conn.Close() //nolint:errcheck,gosec // only probing
+			return nil, ErrSocketInUse
+		}
+		if err := os.Remove(path); err != nil {
+			return nil, fmt.Errorf("remove stale socket %s: %w", path, err)
+		}
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+	ln, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, fmt.Errorf("listen on %s: %w", path, err)
+	}
+	if err := os.Chmod(path, 0o600); err != nil {
+		ln.Close() //nolint:errcheck,gosec // already failing
+		return nil, err
+	}
+	return ln, nil
+}
+
+// writeTimeout bounds one write to a subscriber that stopped reading.
+const writeTimeout = 10 * time.Second
+
+// ServeSocket accepts connections on ln and writes each one the Hub's event
+// stream, one JSON object per line, until ctx is cancelled. It closes ln and
+// every connection before returning. What a subscriber writes is ignored; a
+// subscriber that falls too far behind is disconnected.
+func ServeSocket(ctx context.Context, ln net.Listener, hub *Hub) error {
+	var wg sync.WaitGroup
+	defer wg.Wait()
+	stop := context.AfterFunc(ctx, func() { ln.Close() }) //nolint:errcheck,gosec // unblocks Accept
+	defer stop()
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			ln.Close() //nolint:errcheck,gosec // already failing
+			return fmt.Errorf("accept on events socket: %w", err)
+		}
+		wg.Go(func() { serveConn(ctx, conn, hub) })
+	}
+}
+
+func serveConn(ctx context.Context, conn net.Conn, hub *Hub) {
+	defer conn.Close() //nolint:errcheck // nothing to report to
+	evs, cancel := hub.Subscribe()
+	defer cancel()
+	// A subscriber hanging up is noticed by the read failing; it unblocks the
+	// write loop by ending the subscription.
+	go func() {
+		buf := make([]byte, 512)
+		for {
+			if _, err := conn.Read(buf); err != nil {
+				cancel()
+				return
+			}
+		}
+	}()
+	stop := context.AfterFunc(ctx, cancel)
+	defer stop()
+	enc := json.NewEncoder(conn)
+	for ev := range evs {
+		conn.SetWriteDeadline(time.Now().Add(writeTimeout)) //nolint:errcheck,gosec // a failed write ends the loop anyway
+		if err := enc.Encode(ev); err != nil {
+			return
+		}
+	}
+}
@@ -0,0 +1,31 @@
+// ABOUTME: Line-ending normalization for user-authored text files (prompts,
+// ABOUTME: seeded context files) that may have been saved on Windows.
+package fileutil
+
+import (
+	"os"
+	"strings"
+)
+
+// NormalizeNewlines converts CRLF and lone CR line endings to LF. Text that
+// reaches a shell command line or an agent prompt must not carry stray
+// carriage returns: a trailing \r on a line is passed through as part of the
+// argument and breaks the command.
+func NormalizeNewlines(s string) string {
+	if !strings.Contains(s, "\r") {
+		return s
+	}
+	s = strings.ReplaceAll(s, "\r\n", "\n")
+	return strings.ReplaceAll(s, "\r", "\n")
+}
+
+// ReadTextFile reads a user-authored text file with its line endings
+// normalized to LF and a leading UTF-8 byte-order mark (as written by some
+// Windows editors) removed.
+func ReadTextFile(path string) (string, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // G304: caller-supplied path by design
+	if err != nil {
+		return "", err
+	}
+	return NormalizeNewlines(strings.TrimPrefix(string(data), "\ufeff")), nil
+}
@@ -0,0 +1,37 @@
+// ABOUTME: Unit tests for line-ending normalization of user-authored text.
+// ABOUTME: Covers CRLF, lone CR, mixed endings, and the UTF-8 BOM.
+package fileutil
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeNewlines(t *testing.T) {
+	tests := []struct{ in, want string }{
+		{"plain\ntext\n", "plain\ntext\n"},
+		{"windows\r\ntext\r\n", "windows\ntext\n"},
+		{"old mac\rtext\r", "old mac\ntext\n"},
+		{"mixed\r\nand\nlone\rend", "mixed\nand\nlone\nend"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, NormalizeNewlines(tt.in), "input %q", tt.in)
+	}
+}
+
+func TestReadTextFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "prompt.txt")
+	require.NoError(t, os.WriteFile(path, []byte("\ufefffix the bug\r\nthen test\r\n"), 0o600))
+
+	got, err := ReadTextFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "fix the bug\nthen test\n", got)
+
+	_, err = ReadTextFile(filepath.Join(t.TempDir(), "missing"))
+	assert.Error(t, err)
+}
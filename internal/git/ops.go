@@ -10,6 +10,7 @@ import (
 	"os"
 	"path/filepath"
 	"regexp"
+	"slices"
 	"strings"
 	"time"
 
@@ -34,15 +35,50 @@ func (g *Git) IsEmptyRepo(ctx context.Context, dir string) bool {
 	return err != nil
 }
 
+// workCopyScanArgs are the `git -c` settings for the commands that scan a
+// whole work copy (staging and status).
+//
+//   - core.untrackedCache keeps each directory's untracked listing in the index
+//     and re-reads only the directories whose mtime changed, so a big copy isn't
+//     walked from scratch every time. Baseline also writes it into the copy's
+//     config; passing it here covers work copies baselined before it did.
+//   - core.checkStat=minimal and core.trustctime=false compare a file with its
+//     index entry by size and mtime only. A work copy keeps the source's .git,
+//     index included, and every copy preserves mtimes, but inode numbers and
+//     ctimes always differ from the source's. With the full check, git would
+//     re-read and re-hash every file of a fresh copy the first time it looks.
+//
+// Unlike core.fsmonitor (turned off by runtime.GitHardeningArgs), none of
+// these runs anything.
+var workCopyScanArgs = []string{
+	"-c", "core.untrackedCache=true",
+	"-c", "core.checkStat=minimal",
+	"-c", "core.trustctime=false",
+}
+
+// Subjects of the commits Baseline and BaselineUncommittedChanges make, which
+// RecoverBaseline looks for.
+const (
+	baselineSubject   = "yoloai baseline"
+	preSessionSubject = "yoloai: pre-session state"
+)
+
 // Baseline creates a fresh git baseline for the work copy.
 // Assumes all .git entries have already been removed by RemoveGitDirs.
+//
+// The repo is set up for large trees: the untracked cache (see
+// workCopyScanArgs) and index version 4, which prefix-compresses paths and so
+// shrinks the index that every later stage rewrites. The stat data the index
+// records is what lets later diff and status runs skip unchanged files.
 func (g *Git) Baseline(ctx context.Context, workDir string) (string, error) {
 	cmds := [][]string{
 		{"init"},
 		{"config", "user.email", "yoloai@localhost"},
 		{"config", "user.name", "yoloai"},
+		{"config", "core.untrackedCache", "true"},
+		{"config", "index.version", "4"},
 		{"add", "-A"},
-		{"commit", "-m", "yoloai baseline", "--allow-empty"},
+		{"commit", "-m", baselineSubject, "--allow-empty"},
 	}
 	for _, args := range cmds {
 		if err := g.RunCmd(ctx, workDir, args...); err != nil {
@@ -58,17 +94,17 @@ func (g *Git) Baseline(ctx context.Context, workDir string) (string, error) {
 // BaselineUncommittedChanges commits any pre-existing uncommitted changes in
 // workDir as "yoloai: pre-session state".
 func (g *Git) BaselineUncommittedChanges(ctx context.Context, workDir string) (string, error) {
-	out, err := g.Run(ctx, workDir, "status", "--porcelain")
+	out, err := g.WorkCopyStatus(ctx, workDir)
 	if err != nil || len(strings.TrimSpace(out)) == 0 {
 		return g.HeadSHA(ctx, workDir)
 	}
-	if err := g.RunCmd(ctx, workDir, "add", "-A"); err != nil {
+	if err := g.StageUntracked(ctx, workDir); err != nil {
 		return "", fmt.Errorf("stage pre-session changes: %w", err)
 	}
 	if err := g.RunCmd(ctx, workDir,
 		"-c", "user.email=yoloai@localhost",
 		"-c", "user.name=yoloai",
-		"commit", "-m", "yoloai: pre-session state",
+		"commit", "-m", preSessionSubject,
 	); err != nil {
 		return "", fmt.Errorf("commit pre-session state: %w", err)
 	}
@@ -78,21 +114,71 @@ func (g *Git) BaselineUncommittedChanges(ctx context.Context, workDir string) (s
 	return g.HeadSHA(ctx, workDir)
 }
 
+// RecoverBaseline finds the diff baseline of the work copy at workDir when its
+// record has lost it: the newest commit on HEAD that Baseline or
+// BaselineUncommittedChanges made. A copy of a clean repo got no such commit
+// (its baseline was the HEAD it was copied at), so then HEAD is returned and
+// marked is false — anything the agent committed before the loss counts as
+// baseline.
+func (g *Git) RecoverBaseline(ctx context.Context, workDir string) (sha string, marked bool, err error) {
+	out, err := g.Run(ctx, workDir, "log", "--format=%H %s", "--fixed-strings",
+		"--grep="+baselineSubject, "--grep="+preSessionSubject, "HEAD")
+	if err != nil {
+		return "", false, fmt.Errorf("git log: %w", err)
+	}
+	for _, line := range strings.Split(out, "\n") {
+		sha, subject, _ := strings.Cut(line, " ")
+		if subject == baselineSubject || subject == preSessionSubject {
+			return sha, true, nil
+		}
+	}
+	sha, err = g.HeadSHA(ctx, workDir)
+	return sha, false, err
+}
+
 // StageUntracked runs `git add -A` in the work directory to capture files
-// created by the agent that are not yet tracked. Retries on index.lock
-// contention (the in-container agent's git can briefly hold the lock).
-func (g *Git) StageUntracked(ctx context.Context, workDir string) error {
+// created by the agent that are not yet tracked. With paths, only those
+// pathspecs are staged, so a diff narrowed to part of a big copy doesn't walk
+// the rest of it; a pathspec matching nothing (a path that never existed)
+// falls back to staging everything. Retries on index.lock contention (the
+// in-container agent's git can briefly hold the lock).
+func (g *Git) StageUntracked(ctx context.Context, workDir string, paths ...string) error {
+	args := append(slices.Clone(workCopyScanArgs), "add", "-A")
+	if len(paths) > 0 {
+		args = append(append(args, "--"), paths...)
+	}
 	var err error
 	for range 5 {
-		err = g.RunCmd(ctx, workDir, "add", "-A")
+		_, err = g.Run(ctx, workDir, args...)
+		if err != nil && len(paths) > 0 && isPathspecMismatch(err) {
+			return g.StageUntracked(ctx, workDir)
+		}
 		if err == nil || !IsIndexLocked(err) {
 			return err
 		}
-		time.Sleep(100 * time.Millisecond)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(100 * time.Millisecond):
+		}
 	}
 	return err
 }
 
+// WorkCopyStatus returns `git status --porcelain` for a work copy. Rename
+// detection is off: the callers only ask whether anything changed, and pairing
+// up renames is the costly part of status on a large change set.
+func (g *Git) WorkCopyStatus(ctx context.Context, workDir string) (string, error) {
+	args := append(slices.Clone(workCopyScanArgs), "status", "--porcelain", "--no-renames")
+	return g.Run(ctx, workDir, args...)
+}
+
+// isPathspecMismatch reports whether err is git refusing a pathspec that
+// matches no file.
+func isPathspecMismatch(err error) bool {
+	return strings.Contains(err.Error(), "did not match any files")
+}
+
 // ─── diff ops ────────────────────────────────────────────────────────────────
 
 // RWDiff generates a diff for a :rw mode directory. Returns an empty string
@@ -166,8 +252,21 @@ func (g *Git) CheckPatch(ctx context.Context, patch []byte, targetDir string, is
 // For git repos: runs `git apply` from within the repo.
 // For non-git dirs: runs `git apply` confined to the target (see applyNonRepo).
 func (g *Git) ApplyPatch(ctx context.Context, patch []byte, targetDir string, isGit bool) error {
+	return g.applyPatch(ctx, patch, targetDir, isGit)
+}
+
+// ApplyPatchReject applies the patch with `git apply --reject`: the hunks
+// that fit land, and each file's hunks that don't are left in <file>.rej
+// beside it. Returns an error whenever anything was rejected; the caller
+// looks for the .rej files to tell what. A file git can't patch at all (a
+// missing target, a failed deletion) gets no .rej and is left untouched.
+func (g *Git) ApplyPatchReject(ctx context.Context, patch []byte, targetDir string, isGit bool) error {
+	return g.applyPatch(ctx, patch, targetDir, isGit, "--reject")
+}
+
+func (g *Git) applyPatch(ctx context.Context, patch []byte, targetDir string, isGit bool, extraArgs ...string) error {
 	if isGit {
-		if err := g.runGitApply(ctx, targetDir, patch); err != nil {
+		if err := g.runGitApply(ctx, targetDir, patch, extraArgs...); err != nil {
 			return formatApplyError(err, targetDir)
 		}
 		return nil
@@ -180,7 +279,7 @@ func (g *Git) ApplyPatch(ctx context.Context, patch []byte, targetDir string, is
 		return fmt.Errorf("resolve target dir: %w", err)
 	}
 
-	return g.applyNonRepo(ctx, patch, realTarget, targetDir)
+	return g.applyNonRepo(ctx, patch, realTarget, targetDir, extraArgs...)
 }
 
 // applyNonRepo runs `git apply` against realTarget, a :copy work dir that is
@@ -199,8 +298,8 @@ func (g *Git) ApplyPatch(ctx context.Context, patch []byte, targetDir string, is
 // — deliberately disabled exactly that guard, letting a crafted patch write
 // anywhere the host user could (DF70). git's separate "beyond a symbolic link"
 // check still fires here and blocks the symlink-then-write-through escape.
-// extraArgs carries "--check" for the dry-run path; realTarget must already be
-// symlink-resolved.
+// extraArgs carries "--check" for the dry-run path, or "--reject"; realTarget
+// must already be symlink-resolved.
 func (g *Git) applyNonRepo(ctx context.Context, patch []byte, realTarget, targetDir string, extraArgs ...string) error {
 	return g.withTempGitDir(ctx, func(tmpDir string) error {
 		args := append([]string{"--git-dir=" + filepath.Join(tmpDir, ".git"), "apply"}, extraArgs...)
@@ -288,6 +387,147 @@ func (g *Git) ApplyFormatPatch(ctx context.Context, patchDir string, files []str
 	return shaMap, nil
 }
 
+// ReplayPatches commits the format-patch files in patchDir onto workDir's HEAD
+// with `git am --3way`, as yoloai. It's for a scratch repo yoloai owns, so
+// unlike ApplyFormatPatch there's no stash to manage and no SHA map to build. On
+// a conflict the am is aborted, leaving workDir at the commit it started from,
+// and the error carries git's account of the patch that failed.
+func (g *Git) ReplayPatches(ctx context.Context, workDir, patchDir string, files []string) error {
+	if len(files) == 0 {
+		return nil
+	}
+	args := []string{"-c", "user.email=yoloai@localhost", "-c", "user.name=yoloai", "am", "--3way", "--quiet"}
+	for _, f := range files {
+		args = append(args, filepath.Join(patchDir, f))
+	}
+	stdout, err := g.Run(ctx, workDir, args...)
+	if err == nil {
+		return nil
+	}
+	_ = g.RunCmd(ctx, workDir, "am", "--abort")
+	var ee *runtime.ExecError
+	if errors.As(err, &ee) {
+		return errors.New(amFailureReport(stdout + "\n" + ee.Stderr))
+	}
+	return err
+}
+
+// amFailureReport keeps the lines of git am's output that say what failed —
+// which patch, and the conflicting paths — and drops its how-to-continue
+// advice, which doesn't apply to a repo that is about to be thrown away.
+func amFailureReport(output string) string {
+	var kept []string
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "Patch failed at ") || strings.HasPrefix(line, "CONFLICT ") ||
+			strings.HasPrefix(line, "error: patch failed") {
+			kept = append(kept, line)
+		}
+	}
+	if len(kept) == 0 {
+		return strings.TrimSpace(output)
+	}
+	return strings.Join(kept, "\n")
+}
+
+// RootCommit returns the repository's root commit — the lineage fingerprint a
+// repo keeps through renames, remote changes, and new commits. With several
+// roots (merged histories) the lexically smallest is returned so the answer is
+// stable. Errors for a non-repo or a repo with no commits.
+func (g *Git) RootCommit(ctx context.Context, dir string) (string, error) {
+	out, err := g.Run(ctx, dir, "rev-list", "--max-parents=0", "HEAD")
+	if err != nil {
+		return "", fmt.Errorf("git rev-list --max-parents=0: %w", err)
+	}
+	roots := strings.Fields(out)
+	if len(roots) == 0 {
+		return "", errors.New("git rev-list --max-parents=0: no root commit")
+	}
+	slices.Sort(roots)
+	return roots[0], nil
+}
+
+// HasCommit reports whether the repository at dir holds commit sha.
+func (g *Git) HasCommit(ctx context.Context, dir, sha string) bool {
+	_, err := g.Run(ctx, dir, "cat-file", "-e", sha+"^{commit}")
+	return err == nil
+}
+
+// CheckIgnored returns which of paths (relative to dir, a directory named with
+// a trailing slash) dir's ignore rules ignore, as git check-ignore reports them.
+func (g *Git) CheckIgnored(ctx context.Context, dir string, paths []string) (map[string]bool, error) {
+	ignored := map[string]bool{}
+	if len(paths) == 0 {
+		return ignored, nil
+	}
+	out, err := g.RunInput(ctx, dir, []byte(strings.Join(paths, "\n")+"\n"), "check-ignore", "--stdin")
+	if err != nil {
+		// Exit 1 is check-ignore's "none of them are ignored".
+		var ee *runtime.ExecError
+		if !errors.As(err, &ee) || ee.ExitCode != 1 {
+			return nil, fmt.Errorf("git check-ignore: %w", err)
+		}
+	}
+	for _, line := range strings.Split(out, "\n") {
+		if line != "" {
+			ignored[line] = true
+		}
+	}
+	return ignored, nil
+}
+
+// RemoteURL returns the fetch URL of the named remote, or "" when the remote is
+// not configured (or dir is not a repo).
+func (g *Git) RemoteURL(ctx context.Context, dir, remote string) string {
+	out, err := g.Run(ctx, dir, "remote", "get-url", remote)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(out)
+}
+
+// PushBlockedURL is where BlockPush points every push: a path that is never a
+// repository, named so git's "does not appear to be a git repository" error
+// explains itself. sandbox-setup.py's PUSH_BLOCKED_URL is the same string.
+const PushBlockedURL = "/git-push-is-disabled-in-this-sandbox (create it with --allow-push)/"
+
+// BlockPush sets every remote of the repository at dir to push to
+// PushBlockedURL, replacing any pushurl it had; fetch URLs are untouched. Only
+// dir/.git/config is edited (git config --file), so none of the repository's
+// other config is read.
+func (g *Git) BlockPush(ctx context.Context, dir string) error {
+	cfg := filepath.Join(dir, ".git", "config")
+	if _, err := os.Stat(cfg); err != nil {
+		return nil // no repository config, so no remotes
+	}
+	out, err := g.Run(ctx, dir, "config", "--file", cfg, "--name-only", "--get-regexp", `^remote\..*\.url$`)
+	if err != nil {
+		// Exit 1 is git config's "no such key": there are no remotes.
+		var ee *runtime.ExecError
+		if errors.As(err, &ee) && ee.ExitCode == 1 {
+			return nil
+		}
+		return fmt.Errorf("list remotes: %w", err)
+	}
+	for _, key := range strings.Split(strings.TrimSpace(out), "\n") {
+		if key == "" {
+			continue
+		}
+		pushKey := strings.TrimSuffix(key, ".url") + ".pushurl"
+		if err := g.RunCmd(ctx, dir, "config", "--file", cfg, "--replace-all", pushKey, PushBlockedURL); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Clone clones url into dest, which must not exist or be empty. It runs from
+// fromDir so a relative remote URL (one recorded as "../upstream.git")
+// resolves the same way it does for the repo it came from.
+func (g *Git) Clone(ctx context.Context, fromDir, url, dest string) error {
+	return g.RunCmd(ctx, fromDir, "clone", "--quiet", url, dest)
+}
+
 // ─── safety ops ──────────────────────────────────────────────────────────────
 
 // CheckDirtyRepo checks if the given path is a git repository with
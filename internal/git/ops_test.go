@@ -528,6 +528,97 @@ func TestHeadSHA_NotGitRepo(t *testing.T) {
 	assert.Error(t, err)
 }
 
+// ─── RootCommit, HasCommit, RemoteURL ────────────────────────────────────────
+
+func TestRootCommit_IdentifiesLineage(t *testing.T) {
+	g := NewTestHostWithEnv(testEnv())
+	dir := t.TempDir()
+	initGitRepo(t, dir)
+	writeTestFile(t, dir, "a.txt", "a")
+	gitAdd(t, dir, ".")
+	gitCommit(t, dir, "first")
+	root, err := g.RootCommit(ctx, dir)
+	require.NoError(t, err)
+
+	writeTestFile(t, dir, "b.txt", "b")
+	gitAdd(t, dir, ".")
+	gitCommit(t, dir, "second")
+	again, err := g.RootCommit(ctx, dir)
+	require.NoError(t, err)
+	assert.Equal(t, root, again, "new commits do not change the root")
+	assert.True(t, g.HasCommit(ctx, dir, root))
+
+	other := t.TempDir()
+	initGitRepo(t, other)
+	writeTestFile(t, other, "a.txt", "other")
+	gitAdd(t, other, ".")
+	gitCommit(t, other, "unrelated")
+	assert.False(t, g.HasCommit(ctx, other, root), "an unrelated repo lacks the root")
+}
+
+func TestRootCommit_NoCommits(t *testing.T) {
+	dir := t.TempDir()
+	initGitRepo(t, dir)
+	_, err := NewTestHostWithEnv(testEnv()).RootCommit(ctx, dir)
+	assert.Error(t, err)
+}
+
+func TestRemoteURL(t *testing.T) {
+	g := NewTestHostWithEnv(testEnv())
+	dir := t.TempDir()
+	initGitRepo(t, dir)
+	assert.Empty(t, g.RemoteURL(ctx, dir, "origin"), "unset remote reads as empty")
+	runGit(t, dir, "remote", "add", "origin", "https://example.com/repo.git")
+	assert.Equal(t, "https://example.com/repo.git", g.RemoteURL(ctx, dir, "origin"))
+}
+
+func TestBlockPush(t *testing.T) {
+	g := NewTestHostWithEnv(testEnv())
+	dir := t.TempDir()
+	initGitRepo(t, dir)
+	require.NoError(t, g.BlockPush(ctx, dir), "a repo without remotes is left alone")
+
+	runGit(t, dir, "remote", "add", "origin", "https://example.com/repo.git")
+	runGit(t, dir, "remote", "add", "fork", "https://example.com/fork.git")
+	runGit(t, dir, "remote", "set-url", "--push", "fork", "git@example.com:fork.git")
+	require.NoError(t, g.BlockPush(ctx, dir))
+
+	for _, remote := range []string{"origin", "fork"} {
+		out, err := g.Run(ctx, dir, "remote", "get-url", "--push", "--all", remote)
+		require.NoError(t, err)
+		assert.Equal(t, PushBlockedURL, strings.TrimSpace(out), "%s pushes nowhere, an explicit pushurl included", remote)
+	}
+	assert.Equal(t, "https://example.com/repo.git", g.RemoteURL(ctx, dir, "origin"), "fetching is unchanged")
+}
+
+// TestPushBlockedURL_GoPythonAgreement fences the two copies of the blocked
+// push URL: sandbox-setup's environment rule and BlockPush must fail a push the
+// same way, with the same message naming --allow-push.
+func TestPushBlockedURL_GoPythonAgreement(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join("..", "..", "runtime", "monitor", "setup_helpers.py"))
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `PUSH_BLOCKED_URL = "`+PushBlockedURL+`"`)
+}
+
+func TestClone_ResolvesRelativeURLFromDir(t *testing.T) {
+	g := NewTestHostWithEnv(testEnv())
+	root := t.TempDir()
+	upstream := filepath.Join(root, "upstream")
+	require.NoError(t, os.MkdirAll(upstream, 0750))
+	initGitRepo(t, upstream)
+	writeTestFile(t, upstream, "a.txt", "a")
+	gitAdd(t, upstream, ".")
+	gitCommit(t, upstream, "first")
+
+	work := filepath.Join(root, "work")
+	require.NoError(t, os.MkdirAll(work, 0750))
+	dest := filepath.Join(root, "clone")
+	require.NoError(t, g.Clone(ctx, work, "../upstream", dest))
+	assert.FileExists(t, filepath.Join(dest, "a.txt"))
+
+	assert.Error(t, g.Clone(ctx, work, "../upstream", dest), "a non-empty destination is refused")
+}
+
 // TestRun_ExitOneReturnsExecError verifies that a non-zero git exit returns
 // *runtime.ExecError so callers can match exit codes via errors.As. Regression
 // guard: copyflow/apply.go treats `git diff --quiet HEAD` exit 1 as "diffs
@@ -605,6 +696,20 @@ func TestBaseline_SetsUserConfig(t *testing.T) {
 	assert.Equal(t, "yoloai@localhost", strings.TrimSpace(string(output)))
 }
 
+func TestBaseline_TunesForLargeTrees(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, dir, "a.txt", "a")
+
+	_, err := NewTestHostWithEnv(testEnv()).Baseline(ctx, dir)
+	require.NoError(t, err)
+
+	for key, want := range map[string]string{"core.untrackedCache": "true", "index.version": "4"} {
+		output, err := sysexec.Command(testutil.GitEnv(), "git", "-C", dir, "config", key).Output()
+		require.NoError(t, err, key)
+		assert.Equal(t, want, strings.TrimSpace(string(output)), key)
+	}
+}
+
 // ─── BaselineUncommittedChanges ──────────────────────────────────────────────
 
 func TestBaselineUncommittedChanges_DirtyTree(t *testing.T) {
@@ -644,6 +749,45 @@ func TestBaselineUncommittedChanges_CleanTree(t *testing.T) {
 	assert.Equal(t, originalSHA, newSHA, "clean tree should not create a new commit")
 }
 
+// ─── RecoverBaseline ─────────────────────────────────────────────────────────
+
+func TestRecoverBaseline_FindsNewestMarkedCommit(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, dir, "file.txt", "original\n")
+	g := NewTestHostWithEnv(testEnv())
+	_, err := g.Baseline(ctx, dir)
+	require.NoError(t, err)
+	writeTestFile(t, dir, "file.txt", "user edit\n")
+	preSession, err := g.BaselineUncommittedChanges(ctx, dir)
+	require.NoError(t, err)
+	writeTestFile(t, dir, "file.txt", "agent edit\n")
+	gitAdd(t, dir, ".")
+	gitCommit(t, dir, "agent: mentions yoloai baseline in passing")
+
+	sha, marked, err := g.RecoverBaseline(ctx, dir)
+	require.NoError(t, err)
+	assert.True(t, marked)
+	assert.Equal(t, preSession, sha)
+}
+
+func TestRecoverBaseline_FallsBackToHead(t *testing.T) {
+	dir := t.TempDir()
+	initGitRepo(t, dir)
+	writeTestFile(t, dir, "file.txt", "content\n")
+	gitAdd(t, dir, ".")
+	gitCommit(t, dir, "initial")
+
+	sha, marked, err := NewTestHostWithEnv(testEnv()).RecoverBaseline(ctx, dir)
+	require.NoError(t, err)
+	assert.False(t, marked)
+	assert.Equal(t, headSHA(t, dir), sha)
+}
+
+func TestRecoverBaseline_NotARepo(t *testing.T) {
+	_, _, err := NewTestHostWithEnv(testEnv()).RecoverBaseline(ctx, t.TempDir())
+	assert.Error(t, err)
+}
+
 // ─── StageUntracked ──────────────────────────────────────────────────────────
 
 func TestStageUntracked_NewFiles(t *testing.T) {
@@ -663,6 +807,51 @@ func TestStageUntracked_NewFiles(t *testing.T) {
 	assert.Contains(t, string(output), "b.txt")
 }
 
+func TestStageUntracked_Paths(t *testing.T) {
+	dir := t.TempDir()
+	initGitRepo(t, dir)
+	writeTestFile(t, dir, "a.txt", "a")
+	gitAdd(t, dir, ".")
+	gitCommit(t, dir, "initial")
+
+	for _, sub := range []string{"src", "other"} {
+		require.NoError(t, os.Mkdir(filepath.Join(dir, sub), 0750))
+	}
+	writeTestFile(t, dir, "src/b.txt", "b")
+	writeTestFile(t, dir, "other/c.txt", "c")
+
+	g := NewTestHostWithEnv(testEnv())
+	require.NoError(t, g.StageUntracked(ctx, dir, "src"))
+	staged, err := g.Run(ctx, dir, "diff", "--cached", "--name-only")
+	require.NoError(t, err)
+	assert.Equal(t, "src/b.txt", strings.TrimSpace(staged), "only the named path is staged")
+
+	// A pathspec that matches nothing stages everything rather than failing.
+	require.NoError(t, g.StageUntracked(ctx, dir, "never-existed"))
+	staged, err = g.Run(ctx, dir, "diff", "--cached", "--name-only")
+	require.NoError(t, err)
+	assert.Contains(t, staged, "other/c.txt")
+}
+
+func TestWorkCopyStatus(t *testing.T) {
+	dir := t.TempDir()
+	initGitRepo(t, dir)
+	writeTestFile(t, dir, "a.txt", "a")
+	gitAdd(t, dir, ".")
+	gitCommit(t, dir, "initial")
+
+	g := NewTestHostWithEnv(testEnv())
+	out, err := g.WorkCopyStatus(ctx, dir)
+	require.NoError(t, err)
+	assert.Empty(t, strings.TrimSpace(out))
+
+	runGit(t, dir, "mv", "a.txt", "b.txt")
+	out, err = g.WorkCopyStatus(ctx, dir)
+	require.NoError(t, err)
+	assert.Contains(t, out, "D  a.txt", "renames are reported as a delete and an add")
+	assert.Contains(t, out, "A  b.txt")
+}
+
 func TestStageUntracked_EmptyRepo(t *testing.T) {
 	dir := t.TempDir()
 	initGitRepo(t, dir)
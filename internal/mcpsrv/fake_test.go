@@ -21,6 +21,8 @@ type fakeService struct {
 	HasActiveWorkFn func(ctx context.Context, name string) (bool, string, error)
 	DestroyFn       func(ctx context.Context, name string, opts yoloai.SandboxDestroyOptions) error
 	DiffFn          func(ctx context.Context, name string, opts yoloai.WorkdirDiffOptions) (string, error)
+	ApplyFn         func(ctx context.Context, name string, opts yoloai.WorkdirApplyOptions) (*yoloai.ApplyResult, error)
+	TargetIsGitFn   func(ctx context.Context, name string) (bool, error)
 	TerminalLogFn   func(ctx context.Context, name string, lines int) (string, error)
 	SendInputFn     func(ctx context.Context, name, text string) error
 	ListFilesFn     func(ctx context.Context, name string) ([]string, error)
@@ -103,6 +105,20 @@ func (f *fakeService) Diff(ctx context.Context, name string, opts yoloai.Workdir
 	return "", nil
 }
 
+func (f *fakeService) Apply(ctx context.Context, name string, opts yoloai.WorkdirApplyOptions) (*yoloai.ApplyResult, error) {
+	if f.ApplyFn != nil {
+		return f.ApplyFn(ctx, name, opts)
+	}
+	return nil, nil
+}
+
+func (f *fakeService) TargetIsGitRepo(ctx context.Context, name string) (bool, error) {
+	if f.TargetIsGitFn != nil {
+		return f.TargetIsGitFn(ctx, name)
+	}
+	return false, nil
+}
+
 func (f *fakeService) TerminalLog(ctx context.Context, name string, lines int) (string, error) {
 	if f.TerminalLogFn != nil {
 		return f.TerminalLogFn(ctx, name, lines)
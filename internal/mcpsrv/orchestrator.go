@@ -22,6 +22,8 @@ type SandboxService interface {
 	HasActiveWork(ctx context.Context, name string) (active bool, reason string, err error)
 	Destroy(ctx context.Context, name string, opts yoloai.SandboxDestroyOptions) error
 	Diff(ctx context.Context, name string, opts yoloai.WorkdirDiffOptions) (string, error)
+	Apply(ctx context.Context, name string, opts yoloai.WorkdirApplyOptions) (*yoloai.ApplyResult, error)
+	TargetIsGitRepo(ctx context.Context, name string) (bool, error)
 	TerminalLog(ctx context.Context, name string, lines int) (string, error)
 	SendInput(ctx context.Context, name, text string) error
 	ListFiles(ctx context.Context, name string) ([]string, error)
@@ -115,6 +117,22 @@ func (cs *clientService) Diff(ctx context.Context, name string, opts yoloai.Work
 	return sb.Workdir().Diff(ctx, opts)
 }
 
+func (cs *clientService) Apply(ctx context.Context, name string, opts yoloai.WorkdirApplyOptions) (*yoloai.ApplyResult, error) {
+	sb, err := cs.client.Sandbox(name)
+	if err != nil {
+		return nil, err
+	}
+	return sb.Workdir().Apply(ctx, opts)
+}
+
+func (cs *clientService) TargetIsGitRepo(ctx context.Context, name string) (bool, error) {
+	sb, err := cs.client.Sandbox(name)
+	if err != nil {
+		return false, err
+	}
+	return sb.Workdir().TargetIsGitRepo(ctx)
+}
+
 // TerminalLog reads the last lines of the agent's terminal log. The ctx param
 // satisfies the interface but is not forwarded — Agent.TerminalLog takes no ctx.
 func (cs *clientService) TerminalLog(_ context.Context, name string, lines int) (string, error) {
@@ -24,6 +24,7 @@ func TestSandboxToolNames(t *testing.T) {
 		{"sandbox_log", func() string { return sandboxLogTool().Name }},
 		{"sandbox_input", func() string { return sandboxInputTool().Name }},
 		{"sandbox_reset", func() string { return sandboxResetTool().Name }},
+		{"sandbox_apply", func() string { return sandboxApplyTool().Name }},
 		{"sandbox_files_list", func() string { return sandboxFilesListTool().Name }},
 		{"sandbox_files_read", func() string { return sandboxFilesReadTool().Name }},
 		{"sandbox_files_write", func() string { return sandboxFilesWriteTool().Name }},
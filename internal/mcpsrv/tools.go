@@ -1,4 +1,4 @@
-// ABOUTME: MCP tool definitions and handlers for sandbox lifecycle, diff, log,
+// ABOUTME: MCP tool definitions and handlers for sandbox lifecycle, diff, apply, log,
 // ABOUTME: input, reset, and file-exchange operations exposed to outer agents.
 package mcpsrv
 
@@ -35,6 +35,9 @@ func (s *Server) registerTools() {
 	s.srv.AddTool(sandboxInputTool(), s.handleSandboxInput)
 	s.srv.AddTool(sandboxResetTool(), s.handleSandboxReset)
 
+	// Landing changes
+	s.srv.AddTool(sandboxApplyTool(), s.handleSandboxApply)
+
 	// Files (Q&A channel)
 	s.srv.AddTool(sandboxFilesListTool(), s.handleSandboxFilesList)
 	s.srv.AddTool(sandboxFilesReadTool(), s.handleSandboxFilesRead)
@@ -137,6 +140,17 @@ func sandboxResetTool() mcp.Tool {
 	)
 }
 
+func sandboxApplyTool() mcp.Tool {
+	return mcp.NewTool("sandbox_apply",
+		mcp.WithDescription("Apply the sandbox's changes to the host working directory — the agent's commits as a series, then its uncommitted edits. Only call this after the user approved the diff. Call with dry_run=true first to see what would land. Pass branch to land the changes on a new branch instead of the user's checkout."),
+		mcp.WithString("name", mcp.Required(), mcp.Description("Sandbox name")),
+		mcp.WithBoolean("dry_run", mcp.Description("Report what would be applied without changing anything (default false)")),
+		mcp.WithString("branch", mcp.Description("Create this branch in the host repository and commit the changes there, leaving the user's checkout alone. The branch must not exist.")),
+		mcp.WithBoolean("no_commit", mcp.Description("Apply the net diff as unstaged changes instead of replaying commits (default false)")),
+		mcp.WithBoolean("include_uncommitted", mcp.Description("Also apply the agent's uncommitted edits (default true)")),
+	)
+}
+
 func sandboxFilesListTool() mcp.Tool {
 	return mcp.NewTool("sandbox_files_list",
 		mcp.WithDescription("List files in the sandbox file exchange directory (/yoloai/files/). Check here for question.json when the agent needs input."),
@@ -482,6 +496,85 @@ func (s *Server) handleSandboxReset(ctx context.Context, req mcp.CallToolRequest
 	return textResult(fmt.Sprintf("Sandbox %q reset. Poll sandbox_status.", name)), nil
 }
 
+func (s *Server) handleSandboxApply(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	name := req.GetString("name", "")
+	dryRun := req.GetBool("dry_run", false)
+	branch := req.GetString("branch", "")
+
+	if name == "" {
+		return textResult(errorf("name is required")), nil
+	}
+	if dryRun && branch != "" {
+		return textResult(errorf("dry_run and branch cannot be combined")), nil
+	}
+
+	// As 'yoloai apply' does: replay commits unless asked not to or the host
+	// directory isn't a git repository.
+	mode := yoloai.ApplyModeCommits
+	if req.GetBool("no_commit", false) {
+		mode = yoloai.ApplyModeNoCommit
+	} else if isGit, err := s.svc.TargetIsGitRepo(ctx, name); err == nil && !isGit {
+		mode = yoloai.ApplyModeNoCommit
+	}
+	opts := yoloai.WorkdirApplyOptions{
+		Mode:               mode,
+		IncludeUncommitted: req.GetBool("include_uncommitted", true),
+		DryRun:             dryRun,
+		Branch:             branch,
+	}
+	result, err := s.svc.Apply(ctx, name, opts)
+	if result == nil && err == nil && mode == yoloai.ApplyModeCommits && opts.IncludeUncommitted {
+		// No commits: the uncommitted edits land as one patch (or one commit on branch).
+		opts.Mode = yoloai.ApplyModeNoCommit
+		result, err = s.svc.Apply(ctx, name, opts)
+	}
+	if result == nil {
+		if err != nil {
+			return textResult(errorf("apply sandbox %q: %v", name, err)), nil
+		}
+		return textResult("No changes to apply"), nil
+	}
+
+	text := describeApply(result, dryRun)
+	if err != nil {
+		// The changes landed; a follow-on step (uncommitted edits, a post-apply hook) failed.
+		text += "\n" + errorf("after applying: %v", err)
+	}
+	return textResult(text), nil
+}
+
+// describeApply renders an apply result for the outer agent.
+func describeApply(r *yoloai.ApplyResult, dryRun bool) string {
+	var b strings.Builder
+	verb := "Applied"
+	if dryRun {
+		verb = "Would apply"
+	}
+	switch {
+	case len(r.Commits) > 0:
+		fmt.Fprintf(&b, "%s %d commit(s) to %s", verb, len(r.Commits), r.Dir)
+	default:
+		fmt.Fprintf(&b, "%s changes to %s", verb, r.Dir)
+	}
+	if r.Branch != "" {
+		fmt.Fprintf(&b, " on new branch %s", r.Branch)
+	}
+	b.WriteString(":\n")
+	for _, c := range r.Commits {
+		fmt.Fprintf(&b, "  %s\n", c.Subject)
+	}
+	if r.Stat != "" {
+		b.WriteString(r.Stat)
+		if !strings.HasSuffix(r.Stat, "\n") {
+			b.WriteString("\n")
+		}
+	}
+	if r.UncommittedApplied {
+		b.WriteString("Uncommitted edits were applied as unstaged changes.\n")
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
 func (s *Server) handleSandboxFilesList(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	name := req.GetString("name", "")
 	if name == "" {
@@ -578,7 +671,8 @@ request revisions, or discard the sandbox entirely.
 3. sandbox_diff(stat=true) — cheap summary of what changed.
 4. sandbox_diff / sandbox_diff_file — full diff when needed.
 5. Surface the diff to the user. Get approval.
-6. User runs 'yoloai apply <name>' from their terminal to apply changes.
+6. sandbox_apply — land the approved changes on the host (or the user runs
+   'yoloai apply <name>' from their terminal).
 7. sandbox_destroy — clean up.
 
 ## agent_status values
@@ -635,6 +729,19 @@ Large diffs can be expensive to include in your context window:
 Always call stat=true first. Only fetch the full diff or per-file diffs when
 you need to reason about specific changes.
 
+## Applying
+
+Apply only what the user approved:
+
+  sandbox_apply(name, dry_run=true)     — what would land, changing nothing
+  sandbox_apply(name, branch="ai/fix")  — commit it on a new branch; the
+                                          user's checkout is not touched
+  sandbox_apply(name)                   — into the user's working tree
+
+The agent's commits replay as a series; its uncommitted edits follow as
+unstaged changes (include_uncommitted=false leaves them out). no_commit=true
+applies the whole thing as one unstaged diff instead.
+
 ## Logs
 
   sandbox_log(name)          — last 100 lines of the inner agent's output
@@ -673,9 +780,9 @@ Example:
 
 ## What NOT to do
 
-- Do not apply changes yourself via file tools. The diff/apply workflow
-  exists so the user can review and approve changes. Surface the diff and
-  let the user run 'yoloai apply'.
+- Do not apply changes yourself via file tools, and do not call
+  sandbox_apply before the user approved the diff. The diff/apply workflow
+  exists so the user can review and approve changes.
 - Do not poll faster than 5 seconds — the inner agent needs time to work.
 - Do not call sandbox_input while agent_status is active unless you intend
   to interrupt the current task.
@@ -388,6 +388,93 @@ func TestHandleSandboxReset_Success(t *testing.T) {
 	assert.NotContains(t, text, "[ERROR]")
 }
 
+// ── sandbox_apply ─────────────────────────────────────────────────────────────
+
+func TestHandleSandboxApply_Commits(t *testing.T) {
+	var got yoloai.WorkdirApplyOptions
+	svc := &fakeService{
+		TargetIsGitFn: func(_ context.Context, _ string) (bool, error) { return true, nil },
+		ApplyFn: func(_ context.Context, name string, opts yoloai.WorkdirApplyOptions) (*yoloai.ApplyResult, error) {
+			assert.Equal(t, "mybox", name)
+			got = opts
+			return &yoloai.ApplyResult{Dir: "/src", Branch: "ai/fix", Commits: []yoloai.AppliedCommit{{Subject: "add tests"}}}, nil
+		},
+	}
+	s := &Server{svc: svc}
+	req := newRunRequest(map[string]any{"name": "mybox", "branch": "ai/fix"})
+
+	result, err := s.handleSandboxApply(context.Background(), req)
+	require.NoError(t, err)
+	assert.Equal(t, yoloai.ApplyModeCommits, got.Mode)
+	assert.True(t, got.IncludeUncommitted, "uncommitted edits are included by default")
+	assert.Equal(t, "ai/fix", got.Branch)
+	assert.Equal(t, "Applied 1 commit(s) to /src on new branch ai/fix:\n  add tests", resultText(t, result))
+}
+
+func TestHandleSandboxApply_NoCommitsFallsBackToNetDiff(t *testing.T) {
+	var modes []yoloai.ApplyMode
+	svc := &fakeService{
+		TargetIsGitFn: func(_ context.Context, _ string) (bool, error) { return true, nil },
+		ApplyFn: func(_ context.Context, _ string, opts yoloai.WorkdirApplyOptions) (*yoloai.ApplyResult, error) {
+			modes = append(modes, opts.Mode)
+			if opts.Mode == yoloai.ApplyModeCommits {
+				return nil, nil
+			}
+			return &yoloai.ApplyResult{Dir: "/src", Stat: " a.go | 2 +-"}, nil
+		},
+	}
+	s := &Server{svc: svc}
+	req := newRunRequest(map[string]any{"name": "mybox", "dry_run": true})
+
+	result, err := s.handleSandboxApply(context.Background(), req)
+	require.NoError(t, err)
+	assert.Equal(t, []yoloai.ApplyMode{yoloai.ApplyModeCommits, yoloai.ApplyModeNoCommit}, modes)
+	assert.Equal(t, "Would apply changes to /src:\n a.go | 2 +-", resultText(t, result))
+}
+
+func TestHandleSandboxApply_NonGitTarget(t *testing.T) {
+	var got yoloai.ApplyMode
+	svc := &fakeService{
+		ApplyFn: func(_ context.Context, _ string, opts yoloai.WorkdirApplyOptions) (*yoloai.ApplyResult, error) {
+			got = opts.Mode
+			return nil, nil
+		},
+	}
+	s := &Server{svc: svc}
+	req := newRunRequest(map[string]any{"name": "mybox"})
+
+	result, err := s.handleSandboxApply(context.Background(), req)
+	require.NoError(t, err)
+	assert.Equal(t, yoloai.ApplyModeNoCommit, got)
+	assert.Equal(t, "No changes to apply", resultText(t, result))
+}
+
+func TestHandleSandboxApply_LandedWithError(t *testing.T) {
+	svc := &fakeService{
+		TargetIsGitFn: func(_ context.Context, _ string) (bool, error) { return true, nil },
+		ApplyFn: func(_ context.Context, _ string, _ yoloai.WorkdirApplyOptions) (*yoloai.ApplyResult, error) {
+			return &yoloai.ApplyResult{Dir: "/src", Commits: []yoloai.AppliedCommit{{Subject: "fix"}}}, fmt.Errorf("post-apply hook failed")
+		},
+	}
+	s := &Server{svc: svc}
+	req := newRunRequest(map[string]any{"name": "mybox"})
+
+	result, err := s.handleSandboxApply(context.Background(), req)
+	require.NoError(t, err)
+	text := resultText(t, result)
+	assert.Contains(t, text, "Applied 1 commit(s) to /src")
+	assert.Contains(t, text, "[ERROR] after applying: post-apply hook failed")
+}
+
+func TestHandleSandboxApply_DryRunWithBranch(t *testing.T) {
+	s := &Server{svc: &fakeService{}}
+	req := newRunRequest(map[string]any{"name": "mybox", "dry_run": true, "branch": "ai/fix"})
+
+	result, err := s.handleSandboxApply(context.Background(), req)
+	require.NoError(t, err)
+	assert.Contains(t, resultText(t, result), "[ERROR]")
+}
+
 // ── sandbox_files_list ────────────────────────────────────────────────────────
 
 func TestHandleSandboxFilesList_Empty(t *testing.T) {
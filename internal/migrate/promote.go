@@ -9,6 +9,7 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/kstenerud/yoloai/internal/fileutil"
 	"github.com/kstenerud/yoloai/internal/workspace"
@@ -382,6 +383,9 @@ func TrashDisposer(trashDir string) DisposeFunc {
 		if err := os.Rename(origPath, dest); err != nil {
 			return fmt.Errorf("move orig to trash: %w", err)
 		}
+		// Stamp the entry with when it was trashed, for the retention scrub.
+		now := time.Now()
+		_ = os.Chtimes(dest, now, now) //nolint:errcheck // best-effort
 		return fileutil.FsyncDir(trashDir)
 	}
 }
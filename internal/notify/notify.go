@@ -0,0 +1,54 @@
+// ABOUTME: Desktop notifications for the host user: osascript on macOS,
+// ABOUTME: notify-send on Linux.
+package notify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	goruntime "runtime"
+	"strings"
+
+	"github.com/kstenerud/yoloai/internal/sysexec"
+)
+
+// ErrUnsupported is returned by Desktop on a platform yoloai has no desktop
+// notifier for.
+var ErrUnsupported = errors.New("desktop notifications are not supported on this platform")
+
+// Desktop shows a desktop notification with title and message. env is the
+// notifier's environment (config.HostEnv.EnvForDesktopNotify): notify-send
+// finds the session through DBUS_SESSION_BUS_ADDRESS and DISPLAY.
+func Desktop(ctx context.Context, env []string, title, message string) error {
+	argv, err := desktopCommand(goruntime.GOOS, title, message)
+	if err != nil {
+		return err
+	}
+	_, err = sysexec.CommandContext(ctx, env, argv[0], argv[1:]...).Output()
+	if err != nil {
+		return fmt.Errorf("%s: %w", argv[0], sysexec.EnrichExitError(err))
+	}
+	return nil
+}
+
+// desktopCommand returns the command that shows a notification on goos.
+func desktopCommand(goos, title, message string) ([]string, error) {
+	switch goos {
+	case "darwin":
+		script := fmt.Sprintf("display notification %s with title %s", appleScriptString(message), appleScriptString(title))
+		return []string{"osascript", "-e", script}, nil
+	case "linux":
+		return []string{"notify-send", "--app-name=yoloai", "--", title, message}, nil
+	default:
+		return nil, ErrUnsupported
+	}
+}
+
+// appleScriptString quotes s as an AppleScript string literal. Sandbox names
+// and agent output end up in notifications, so a quote or backslash in them
+// must not end the literal early.
+func appleScriptString(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	return `"` + s + `"`
+}
@@ -0,0 +1,28 @@
+// ABOUTME: Tests for the desktop notifier command line per platform and the
+// ABOUTME: AppleScript quoting that keeps a title or message inside its literal.
+package notify
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDesktopCommand_Darwin(t *testing.T) {
+	argv, err := desktopCommand("darwin", "yoloai: fix-bug", `said "hi" \ bye`)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"osascript", "-e",
+		`display notification "said \"hi\" \\ bye" with title "yoloai: fix-bug"`}, argv)
+}
+
+func TestDesktopCommand_Linux(t *testing.T) {
+	argv, err := desktopCommand("linux", "yoloai: fix-bug", "-looks like a flag")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"notify-send", "--app-name=yoloai", "--", "yoloai: fix-bug", "-looks like a flag"}, argv)
+}
+
+func TestDesktopCommand_Unsupported(t *testing.T) {
+	_, err := desktopCommand("windows", "t", "m")
+	assert.ErrorIs(t, err, ErrUnsupported)
+}
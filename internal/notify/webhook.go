@@ -0,0 +1,102 @@
+// ABOUTME: Webhook notifications: one JSON event per thing that happened to a sandbox,
+// ABOUTME: POSTed to notifications.webhook_url, or as a message to a Slack webhook.
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// Event names a webhook is sent.
+const (
+	// EventCreated is a sandbox that has just appeared.
+	EventCreated = "created"
+	// EventNeedsInput is an agent that was working and is now waiting for
+	// input.
+	EventNeedsInput = "needs_input"
+	// EventFinished is an agent that exited with status 0.
+	EventFinished = "finished"
+	// EventFailed is an agent that exited with a non-zero status.
+	EventFailed = "failed"
+)
+
+// webhookTimeout bounds one delivery, so a receiver that hangs can't hold up
+// the daemon's next check.
+const webhookTimeout = 10 * time.Second
+
+// Event is the JSON body a webhook receives: one thing that happened to one
+// sandbox, with Message the line a person would be shown.
+type Event struct {
+	Time     time.Time `json:"time"`
+	Event    string    `json:"event"`
+	Sandbox  string    `json:"sandbox"`
+	Status   string    `json:"status"`
+	ExitCode *int      `json:"exit_code,omitempty"`
+	Message  string    `json:"message"`
+}
+
+// Webhook POSTs ev as JSON to target, which must be an http or https URL.
+// Any 2xx response is success; the body is ignored. client nil means
+// http.DefaultClient.
+func Webhook(ctx context.Context, client *http.Client, target string, ev Event) error {
+	return postJSON(ctx, client, "webhook_url", target, ev)
+}
+
+// slackMessage is the body of a Slack incoming-webhook message.
+type slackMessage struct {
+	Text string `json:"text"`
+}
+
+// Slack posts ev as a one-line message to target, a Slack incoming-webhook
+// URL (or anything that accepts the same {"text": ...} body, as Mattermost
+// and Discord's /slack endpoint do).
+func Slack(ctx context.Context, client *http.Client, target string, ev Event) error {
+	return postJSON(ctx, client, "slack_webhook", target, slackMessage{Text: slackText(ev)})
+}
+
+// slackText is the message Slack shows for ev. The sandbox name is set as
+// code, so one with an underscore or asterisk isn't taken for formatting.
+func slackText(ev Event) string {
+	return fmt.Sprintf("yoloai `%s`: %s", ev.Sandbox, ev.Message)
+}
+
+// postJSON POSTs body as JSON to target, which must be an http or https URL;
+// key names the setting target came from, for the error. Any 2xx response is
+// success and its body is ignored.
+func postJSON(ctx context.Context, client *http.Client, key, target string, body any) error {
+	if u, err := url.Parse(target); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("%s %q must be an http or https URL", key, target)
+	}
+	data, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("webhook: %w", err)
+	}
+	ctx, cancel := context.WithTimeout(ctx, webhookTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, target, bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("webhook: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "yoloai")
+
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("webhook: %w", err)
+	}
+	defer resp.Body.Close()                                       //nolint:errcheck // read-only body
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10)) //nolint:errcheck // drained so the connection can be reused
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook %s: %s", target, resp.Status)
+	}
+	return nil
+}
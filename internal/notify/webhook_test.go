@@ -0,0 +1,67 @@
+// ABOUTME: Tests for Webhook and Slack: the JSON body and headers a receiver gets, a
+// ABOUTME: non-2xx answer as an error, and refusing a URL that isn't http(s).
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhook_PostsEvent(t *testing.T) {
+	var got Event
+	var contentType string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		contentType = r.Header.Get("Content-Type")
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&got))
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	code := 2
+	ev := Event{Time: time.Unix(1_700_000_000, 0).UTC(), Event: EventFailed, Sandbox: "fix-bug", Status: "failed", ExitCode: &code, Message: "Failed (exit 2)"}
+	require.NoError(t, Webhook(context.Background(), srv.Client(), srv.URL, ev))
+	assert.Equal(t, "application/json", contentType)
+	assert.Equal(t, ev, got)
+}
+
+func TestWebhook_ErrorStatus(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		http.Error(w, "nope", http.StatusForbidden)
+	}))
+	defer srv.Close()
+
+	err := Webhook(context.Background(), srv.Client(), srv.URL, Event{Event: EventFinished})
+	assert.ErrorContains(t, err, "403 Forbidden")
+}
+
+func TestWebhook_RejectsNonHTTPURL(t *testing.T) {
+	for _, target := range []string{"file:///etc/passwd", "hooks.example.com/x", ""} {
+		assert.ErrorContains(t, Webhook(context.Background(), nil, target, Event{}), "must be an http or https URL", target)
+	}
+}
+
+func TestSlack_PostsText(t *testing.T) {
+	var got map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&got))
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer srv.Close()
+
+	ev := Event{Event: EventNeedsInput, Sandbox: "fix_bug", Status: "idle", Message: "Waiting for input"}
+	require.NoError(t, Slack(context.Background(), srv.Client(), srv.URL, ev))
+	assert.Equal(t, map[string]any{"text": "yoloai `fix_bug`: Waiting for input"}, got)
+}
+
+func TestSlack_RejectsNonHTTPURL(t *testing.T) {
+	assert.ErrorContains(t, Slack(context.Background(), nil, "hooks.slack.com/x", Event{}), "slack_webhook")
+}
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"time"
 
 	"github.com/kstenerud/yoloai/internal/fileutil"
 )
@@ -23,6 +24,16 @@ type AgentConfig struct {
 	Version   int    `json:"version"`
 	AgentType string `json:"agent"`
 	Model     string `json:"model,omitempty"`
+	// Upgrades records each in-place agent upgrade (`yoloai upgrade`), oldest
+	// first. The last entry's To is the version the sandbox now runs.
+	Upgrades []AgentUpgrade `json:"upgrades,omitempty"`
+}
+
+// AgentUpgrade is one in-place upgrade of the agent CLI inside a sandbox.
+type AgentUpgrade struct {
+	From string    `json:"from"`
+	To   string    `json:"to"`
+	At   time.Time `json:"at"`
 }
 
 // Save writes agent.json to the given sandbox directory.
@@ -1,5 +1,5 @@
-// ABOUTME: Host-side read of a sandbox's raw agent terminal output
-// ABOUTME: (logs/agent.log) — full or tail-N, ANSI bytes left intact.
+// ABOUTME: Host-side read of a sandbox's raw agent terminal output (logs/agent.log,
+// ABOUTME: full or tail-N, ANSI bytes left intact) and its timed recording (agent.cast).
 package orchestrator
 
 import (
@@ -49,3 +49,22 @@ func ReadAgentLog(layout config.Layout, name string, tailLines int) (string, err
 	}
 	return strings.Join(lines, "\n"), nil
 }
+
+// OpenAgentRecording opens the sandbox's timed terminal recording
+// (logs/agent.cast, asciicast v2) for reading. A sandbox with no recording —
+// one created before recording existed, or whose agent has not started — is
+// not an error: it returns (nil, nil).
+func OpenAgentRecording(layout config.Layout, name string) (io.ReadCloser, error) {
+	sandboxDir := layout.SandboxDir(name)
+	if err := store.RequireSandboxDir(sandboxDir); err != nil {
+		return nil, err
+	}
+	f, err := os.Open(store.AgentCastPath(sandboxDir)) //nolint:gosec // G304: path is store.AgentCastPath(name) — yoloAI-owned
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("open agent recording: %w", err)
+	}
+	return f, nil
+}
@@ -23,6 +23,18 @@ const (
 	NetworkModeIsolated NetworkMode = create.NetworkModeIsolated // allowlist only
 )
 
+// ContextMode re-exports store.ContextMode for callers referencing
+// sandbox.ContextMode.
+type ContextMode = store.ContextMode
+
+// Re-exported ContextMode constants. Canonical definitions in
+// store/contextmode.go.
+const (
+	ContextModeAgent    = store.ContextModeAgent
+	ContextModeWorktree = store.ContextModeWorktree
+	ContextModeNone     = store.ContextModeNone
+)
+
 // State re-exports state.State for callers referencing sandbox.State.
 type State = state.State
 
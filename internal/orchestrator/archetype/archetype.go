@@ -53,15 +53,9 @@ func ValidArchetypes() []string {
 //  4. Nothing → simple
 func DetectArchetype(workdir string) (Archetype, []string) {
 	// 1. devcontainer
-	devcontainerPaths := []string{
-		filepath.Join(workdir, ".devcontainer", "devcontainer.json"),
-		filepath.Join(workdir, "devcontainer.json"),
-	}
-	for _, p := range devcontainerPaths {
-		if fileExists(p) {
-			rel, _ := filepath.Rel(workdir, p)
-			return ArchetypeDevcontainer, []string{fmt.Sprintf("found %s", rel)}
-		}
+	if p := FindDevcontainer(workdir); p != "" {
+		rel, _ := filepath.Rel(workdir, p)
+		return ArchetypeDevcontainer, []string{fmt.Sprintf("found %s", rel)}
 	}
 
 	// 2. docker-compose
@@ -4,11 +4,13 @@
 package archetype
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"io"
 	"maps"
 	"os"
+	"path/filepath"
 	"strings"
 )
 
@@ -101,13 +103,29 @@ type DevcontainerConfig struct {
 	ShutdownAction   string `json:"shutdownAction,omitempty"`
 }
 
-// LoadDevcontainer reads and JSON-decodes the devcontainer.json at path.
+// FindDevcontainer returns the path of dir's devcontainer.json, or "" if it
+// has none: .devcontainer/devcontainer.json, else devcontainer.json at the root.
+func FindDevcontainer(dir string) string {
+	for _, candidate := range []string{
+		filepath.Join(dir, ".devcontainer", "devcontainer.json"),
+		filepath.Join(dir, "devcontainer.json"),
+	} {
+		if fileExists(candidate) {
+			return candidate
+		}
+	}
+	return ""
+}
+
+// LoadDevcontainer reads and JSON-decodes the devcontainer.json at path,
+// accepting the comments and trailing commas the spec allows.
 // Sets BuildPresent=true if a "build" key is present without fully parsing it.
 func LoadDevcontainer(path string) (*DevcontainerConfig, error) {
 	data, err := os.ReadFile(path) //nolint:gosec // G304: path is derived from user workdir
 	if err != nil {
 		return nil, fmt.Errorf("read devcontainer.json: %w", err)
 	}
+	data = stripJSONC(data)
 
 	// Detect "build" key presence before unmarshaling to the typed struct.
 	var rawMap map[string]json.RawMessage
@@ -125,6 +143,83 @@ func LoadDevcontainer(path string) (*DevcontainerConfig, error) {
 	return &dc, nil
 }
 
+// stripJSONC turns JSON with comments, the dialect devcontainer.json is
+// written in, into plain JSON: comments outside strings become spaces
+// (newlines kept, so decode errors still point at the right line), and a comma
+// followed only by whitespace before a closing } or ] is dropped.
+func stripJSONC(data []byte) []byte {
+	out := make([]byte, 0, len(data))
+	inString := false
+	for i := 0; i < len(data); i++ {
+		c := data[i]
+		switch {
+		case inString:
+			out = append(out, c)
+			if c == '\\' && i+1 < len(data) {
+				i++
+				out = append(out, data[i])
+			} else if c == '"' {
+				inString = false
+			}
+		case c == '"':
+			inString = true
+			out = append(out, c)
+		case c == '/' && i+1 < len(data) && data[i+1] == '/':
+			for i < len(data) && data[i] != '\n' {
+				out = append(out, ' ')
+				i++
+			}
+			i--
+		case c == '/' && i+1 < len(data) && data[i+1] == '*':
+			end := bytes.Index(data[i+2:], []byte("*/"))
+			if end < 0 {
+				end = len(data) - i - 2
+			} else {
+				end += 2
+			}
+			for _, b := range data[i : i+2+end] {
+				if b == '\n' {
+					out = append(out, '\n')
+				} else {
+					out = append(out, ' ')
+				}
+			}
+			i += 1 + end
+		default:
+			out = append(out, c)
+		}
+	}
+
+	// Trailing commas, now that no comment can sit between one and its bracket.
+	inString = false
+	result := out[:0:0]
+	for i := 0; i < len(out); i++ {
+		c := out[i]
+		switch {
+		case inString:
+			if c == '\\' && i+1 < len(out) {
+				result = append(result, c, out[i+1])
+				i++
+				continue
+			}
+			inString = c != '"'
+		case c == '"':
+			inString = true
+		case c == ',':
+			j := i + 1
+			for j < len(out) && (out[j] == ' ' || out[j] == '\t' || out[j] == '\n' || out[j] == '\r') {
+				j++
+			}
+			if j < len(out) && (out[j] == '}' || out[j] == ']') {
+				result = append(result, ' ')
+				continue
+			}
+		}
+		result = append(result, c)
+	}
+	return result
+}
+
 // ExtractPorts returns the union of forwardPorts and appPort as "port:port" strings
 // compatible with parsePortBindings.
 func (dc *DevcontainerConfig) ExtractPorts() []string {
@@ -0,0 +1,431 @@
+// ABOUTME: Translates a devcontainer.json into a yoloai profile: a config.yaml
+// ABOUTME: (ports, env, resources, caps) and a Dockerfile on yoloai-base for image and features.
+
+package archetype
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DevcontainerProfile is a yoloai profile generated from a devcontainer.json.
+type DevcontainerProfile struct {
+	Config     []byte   // config.yaml
+	Dockerfile []byte   // nil when yoloai-base already has everything the devcontainer asks for
+	Notes      []string // what could not be carried over, to finish by hand
+}
+
+// devcontainerProfileYAML is the part of a profile's config.yaml a
+// devcontainer.json can fill in, in the order it is written.
+type devcontainerProfileYAML struct {
+	Env       map[string]string         `yaml:"env,omitempty"`
+	Ports     []string                  `yaml:"ports,omitempty"`
+	Resources *devcontainerProfileLimit `yaml:"resources,omitempty"`
+	CapAdd    []string                  `yaml:"cap_add,omitempty"`
+}
+
+type devcontainerProfileLimit struct {
+	CPUs   string `yaml:"cpus,omitempty"`
+	Memory string `yaml:"memory,omitempty"`
+}
+
+// toolchainVersionPattern matches the versions the Dockerfile steps accept:
+// a major, major.minor or major.minor.patch.
+var toolchainVersionPattern = regexp.MustCompile(`^[0-9]+(\.[0-9]+){0,2}$`)
+
+// devcontainerImagePrefixes are the registries whose images are the
+// devcontainer project's own, tagged [imageVersion-]toolVersion[-distro].
+var devcontainerImagePrefixes = []string{
+	"mcr.microsoft.com/devcontainers/",
+	"mcr.microsoft.com/vscode/devcontainers/",
+}
+
+// imageToolchains maps an image name (its last path element) to the
+// toolchain it provides; "" for a plain OS image yoloai-base stands in for.
+var imageToolchains = map[string]string{
+	"go": "go", "golang": "go",
+	"javascript-node": "node", "typescript-node": "node", "node": "node",
+	"python": "python",
+	"rust":   "rust",
+	"java":   "java", "openjdk": "java", "eclipse-temurin": "java",
+	"base": "", "debian": "", "ubuntu": "", "buildpack-deps": "", "cpp": "",
+}
+
+// featureToolchains maps a devcontainer feature ID to the toolchain it
+// installs.
+var featureToolchains = map[string]string{
+	"go": "go", "golang": "go",
+	"node": "node", "nodejs": "node",
+	"python": "python",
+	"rust":   "rust",
+	"java":   "java",
+}
+
+// featurePackages maps a devcontainer feature ID to the Debian packages that
+// stand in for it; an empty list means yoloai-base already has it.
+var featurePackages = map[string][]string{
+	"common-utils": nil,
+	"git":          nil,
+	"github-cli":   {"gh"},
+	"git-lfs":      {"git-lfs"},
+}
+
+// imageTagSuffixes are tag parts naming a distribution or variant rather than
+// a version.
+var imageTagSuffixes = map[string]bool{
+	"bookworm": true, "bullseye": true, "buster": true, "trixie": true,
+	"jammy": true, "focal": true, "noble": true,
+	"slim": true, "alpine": true, "windowsservercore": true,
+}
+
+// ToProfile generates the yoloai profile equivalent to dc; source names the
+// devcontainer.json in the generated files' header comments.
+//
+// Profile images must build FROM yoloai-base, so dc's image can't be used as
+// is: the toolchain it provides (Go, Node.js, Python, Rust, Java) and the
+// toolchains and tools its features add are installed on top of yoloai-base
+// instead, at the versions asked for. Lifecycle commands aren't copied: they
+// already run from the devcontainer.json when its repository is a sandbox's
+// workdir. What can't be translated is left in Notes.
+func (dc *DevcontainerConfig) ToProfile(source string) (*DevcontainerProfile, error) {
+	if dc.DockerComposeFilePresent() {
+		return nil, fmt.Errorf("docker Compose devcontainers are not supported")
+	}
+	g := &profileGen{toolchains: map[string]toolchainReq{}}
+	g.image(dc)
+	g.features(dc.Features)
+
+	cfg := devcontainerProfileYAML{Ports: dc.ExtractPorts()}
+	cfg.Env = g.env(dc.MergedEnv())
+	cpus, memory, capAdd, warnings := dc.ParsedRunArgs()
+	for _, w := range warnings {
+		g.note("%s", strings.TrimPrefix(w, "Warning: "))
+	}
+	if cpus != "" || memory != "" {
+		cfg.Resources = &devcontainerProfileLimit{CPUs: cpus, Memory: memory}
+	}
+	cfg.CapAdd = capAdd
+	if len(dc.Mounts) > 0 {
+		g.note("mounts are not copied: they name paths on the machine they were written for; add the ones you need under mounts: in config.yaml")
+	}
+	if !dc.OnCreateCommand.IsZero() || !dc.UpdateContentCommand.IsZero() || !dc.PostCreateCommand.IsZero() || !dc.PostStartCommand.IsZero() {
+		g.note("lifecycle commands are not copied: they run from the repository's devcontainer.json when it is a sandbox's workdir")
+	}
+
+	configYAML, err := g.config(source, cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &DevcontainerProfile{Config: configYAML, Dockerfile: g.dockerfile(source), Notes: g.notes}, nil
+}
+
+// toolchainReq is a toolchain to install and what asked for it.
+type toolchainReq struct {
+	version string // "" = the newest the step knows
+	from    string // the image or feature it came from
+}
+
+type profileGen struct {
+	toolchains map[string]toolchainReq
+	packages   []string
+	unknown    []string // features with no translation, as Dockerfile comments
+	nestedDock bool     // a feature runs Docker in the container
+	notes      []string
+}
+
+func (g *profileGen) note(format string, args ...any) {
+	g.notes = append(g.notes, fmt.Sprintf(format, args...))
+}
+
+// image records the toolchain dc's image provides, or notes a build or image
+// it can't follow.
+func (g *profileGen) image(dc *DevcontainerConfig) {
+	if dc.BuildPresent {
+		g.note("the devcontainer builds its own Dockerfile: port its steps into the profile's Dockerfile, on FROM yoloai-base")
+	}
+	if dc.Image == "" {
+		return
+	}
+	name, tag := splitImageRef(dc.Image)
+	lang, ok := imageToolchains[name[strings.LastIndex(name, "/")+1:]]
+	switch {
+	case !ok:
+		g.note("image %s can't be used: profile images build on yoloai-base; add what it provides to the Dockerfile", dc.Image)
+		return
+	case lang == "":
+		return
+	}
+	version := ""
+	parts := imageTagParts(tag)
+	if slices.ContainsFunc(devcontainerImagePrefixes, func(p string) bool { return strings.HasPrefix(name, p) }) {
+		// [imageVersion-]toolVersion: a lone part is the tool's version only
+		// when it can't be the image's own major.
+		switch {
+		case len(parts) >= 2:
+			version = parts[1]
+		case len(parts) == 1 && (strings.Contains(parts[0], ".") || lang == "node" || lang == "java"):
+			version = parts[0]
+		}
+	} else if len(parts) > 0 {
+		version = parts[0]
+	}
+	g.toolchain(lang, version, "image "+dc.Image)
+}
+
+// features records what each feature installs, in ID order.
+func (g *profileGen) features(features map[string]any) {
+	ids := make([]string, 0, len(features))
+	for id := range features {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		short := featureID(id)
+		version := featureVersion(features[id])
+		if version == "none" {
+			continue
+		}
+		if lang, ok := featureToolchains[short]; ok {
+			g.toolchain(lang, version, "feature "+id)
+			continue
+		}
+		if pkgs, ok := featurePackages[short]; ok {
+			for _, p := range pkgs {
+				if !slices.Contains(g.packages, p) {
+					g.packages = append(g.packages, p)
+				}
+			}
+			continue
+		}
+		if short == "docker-in-docker" || short == "docker-outside-of-docker" {
+			g.nestedDock = true
+			continue
+		}
+		g.unknown = append(g.unknown, id)
+		g.note("feature %s has no translation: install what it provides in the Dockerfile", id)
+	}
+}
+
+// toolchain records lang at version, a later request replacing an earlier
+// one (features override the image, as they install on top of it).
+func (g *profileGen) toolchain(lang, version, from string) {
+	switch version {
+	case "latest", "lts", "stable", "os-provided":
+		version = ""
+	}
+	if version != "" && !toolchainVersionPattern.MatchString(version) {
+		g.note("%s asks for %s version %q, which isn't understood; the newest is installed instead", from, lang, version)
+		version = ""
+	}
+	g.toolchains[lang] = toolchainReq{version: version, from: from}
+}
+
+// env keeps the variables whose values are literal. devcontainer.json's
+// ${localEnv:…} and ${containerEnv:…} have no counterpart a profile could
+// resolve the same way, so those are noted instead.
+func (g *profileGen) env(vars map[string]string) map[string]string {
+	out := map[string]string{}
+	var skipped []string
+	for k, v := range vars {
+		if strings.Contains(v, "${") {
+			skipped = append(skipped, k)
+			continue
+		}
+		out[k] = v
+	}
+	if len(skipped) > 0 {
+		sort.Strings(skipped)
+		g.note("env %s refer to ${...} variables and are not copied; set them under env: in config.yaml", strings.Join(skipped, ", "))
+	}
+	if len(out) == 0 {
+		return nil
+	}
+	return out
+}
+
+func (g *profileGen) config(source string, cfg devcontainerProfileYAML) ([]byte, error) {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "# Generated by 'yoloai profile from-devcontainer' from %s.\n", source)
+	if cfg.Env != nil || cfg.Ports != nil || cfg.Resources != nil || cfg.CapAdd != nil {
+		enc := yaml.NewEncoder(&b)
+		enc.SetIndent(2)
+		if err := enc.Encode(cfg); err != nil {
+			return nil, err
+		}
+		if err := enc.Close(); err != nil {
+			return nil, err
+		}
+	}
+	if g.nestedDock {
+		b.WriteString(`# The devcontainer runs Docker inside the container, which needs nested
+# Docker. That gives the sandbox full access to the host, so it is left to you:
+# isolation: container-privileged
+`)
+		g.note("the devcontainer runs Docker inside the container: nested Docker needs isolation: container-privileged, which gives full host access; it is left commented out in config.yaml")
+	}
+	return b.Bytes(), nil
+}
+
+// dockerfile writes the install steps on yoloai-base, or nil when there are
+// none.
+func (g *profileGen) dockerfile(source string) []byte {
+	var steps []string
+	for _, lang := range []string{"go", "node", "python", "rust", "java"} {
+		if req, ok := g.toolchains[lang]; ok {
+			if step := toolchainStep(lang, req.version); step != "" {
+				steps = append(steps, fmt.Sprintf("\n# %s (%s)\n%s", toolchainTitle(lang, req.version), req.from, step))
+			}
+		}
+	}
+	if len(steps) == 0 && len(g.packages) == 0 && len(g.unknown) == 0 {
+		return nil
+	}
+	var b bytes.Buffer
+	fmt.Fprintf(&b, `# Generated by 'yoloai profile from-devcontainer' from %s.
+# Profile images build on yoloai-base, which already has Go, Node.js, Python,
+# Rust, Docker and the usual build tools; these steps add what the
+# devcontainer asks for on top.
+FROM yoloai-base
+`, source)
+	for _, step := range steps {
+		b.WriteString(step)
+	}
+	if len(g.packages) > 0 {
+		fmt.Fprintf(&b, `
+# Tools from the devcontainer's features
+RUN apt-get update \
+    && apt-get install -y --no-install-recommends %s \
+    && rm -rf /var/lib/apt/lists/*
+`, strings.Join(g.packages, " "))
+	}
+	if len(g.unknown) > 0 {
+		b.WriteString("\n# Features with no translation; install what they provide here:\n")
+		for _, id := range g.unknown {
+			fmt.Fprintf(&b, "#   %s\n", id)
+		}
+	}
+	return b.Bytes()
+}
+
+func toolchainTitle(lang, version string) string {
+	name := map[string]string{"go": "Go", "node": "Node.js", "python": "Python", "rust": "Rust", "java": "Java"}[lang]
+	if version == "" {
+		return name
+	}
+	return name + " " + version
+}
+
+// toolchainStep is the Dockerfile step installing lang at version, "" when
+// yoloai-base's own will do.
+func toolchainStep(lang, version string) string {
+	switch lang {
+	case "go":
+		if version == "" {
+			return ""
+		}
+		// The newest release of that line: go.dev lists releases newest first.
+		return fmt.Sprintf(`RUN v="$(curl --retry 5 --retry-delay 2 --retry-all-errors -fsSL 'https://go.dev/dl/?mode=json&include=all' \
+      | jq -r --arg v go%s '[.[].version | select(. == $v or startswith($v + "."))][0] // empty')" \
+    && test -n "$v" \
+    && rm -rf /usr/local/go \
+    && curl --retry 5 --retry-delay 2 --retry-all-errors -fsSL "https://go.dev/dl/${v}.linux-$(dpkg --print-architecture).tar.gz" \
+      | tar -C /usr/local -xz
+`, version)
+	case "node":
+		if version == "" {
+			return ""
+		}
+		major, _, _ := strings.Cut(version, ".")
+		// The agents are installed under yoloai-base's Node.js; switching the
+		// NodeSource line keeps them, and may be a downgrade.
+		return fmt.Sprintf(`RUN echo "deb [signed-by=/etc/apt/keyrings/nodesource.gpg] https://deb.nodesource.com/node_%s.x nodistro main" \
+       > /etc/apt/sources.list.d/nodesource.list \
+    && apt-get update \
+    && apt-get install -y --no-install-recommends --allow-downgrades \
+       "nodejs=$(apt-cache madison nodejs | awk '/nodesource/ {print $3; exit}')" \
+    && rm -rf /var/lib/apt/lists/*
+`, major)
+	case "python":
+		if version == "" {
+			return ""
+		}
+		return fmt.Sprintf(`RUN UV_PYTHON_INSTALL_DIR=/opt/uv/python UV_PYTHON_BIN_DIR=/usr/local/bin \
+       uv python install --preview --default %s
+`, version)
+	case "rust":
+		if version == "" {
+			return ""
+		}
+		return fmt.Sprintf(`RUN rustup toolchain install %[1]s --profile minimal \
+    && rustup default %[1]s \
+    && chmod -R a+rw "$RUSTUP_HOME" "$CARGO_HOME"
+`, version)
+	case "java":
+		pkg := "default-jdk-headless"
+		if version != "" {
+			major, _, _ := strings.Cut(version, ".")
+			pkg = "openjdk-" + major + "-jdk-headless"
+		}
+		return fmt.Sprintf(`RUN apt-get update \
+    && apt-get install -y --no-install-recommends %s \
+    && rm -rf /var/lib/apt/lists/*
+`, pkg)
+	}
+	return ""
+}
+
+// splitImageRef splits an image reference into its name and tag, dropping a
+// digest.
+func splitImageRef(ref string) (name, tag string) {
+	ref, _, _ = strings.Cut(ref, "@")
+	if i := strings.LastIndex(ref, ":"); i > strings.LastIndex(ref, "/") {
+		return ref[:i], ref[i+1:]
+	}
+	return ref, ""
+}
+
+// imageTagParts splits a tag on "-", leaving out distribution and variant
+// names.
+func imageTagParts(tag string) []string {
+	var parts []string
+	for p := range strings.SplitSeq(tag, "-") {
+		if p != "" && !imageTagSuffixes[p] {
+			parts = append(parts, p)
+		}
+	}
+	return parts
+}
+
+// featureID reduces a feature reference to its short ID:
+// ghcr.io/devcontainers/features/go:1 → go. A feature from another
+// registry keeps its full reference, so it isn't mistaken for an official one.
+func featureID(ref string) string {
+	ref, _, _ = strings.Cut(ref, "@")
+	if i := strings.LastIndex(ref, ":"); i > strings.LastIndex(ref, "/") {
+		ref = ref[:i]
+	}
+	if rest, ok := strings.CutPrefix(ref, "ghcr.io/devcontainers/features/"); ok {
+		return rest
+	}
+	return ref
+}
+
+// featureVersion is the version a feature's options ask for: the value
+// itself in the legacy string form, else its "version" option.
+func featureVersion(opts any) string {
+	switch v := opts.(type) {
+	case string:
+		return v
+	case map[string]any:
+		if s, ok := v["version"].(string); ok {
+			return s
+		}
+	}
+	return ""
+}
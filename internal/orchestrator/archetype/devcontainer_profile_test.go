@@ -0,0 +1,134 @@
+// ABOUTME: Tests for generating a yoloai profile from devcontainer.json: image and
+// ABOUTME: feature translation, config.yaml contents, notes, and JSON-with-comments parsing.
+
+package archetype
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestLoadDevcontainer_JSONC(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "devcontainer.json")
+	require.NoError(t, os.WriteFile(path, []byte(`// For format details, see https://aka.ms/devcontainer.json
+{
+	"name": "api", /* the service */
+	"image": "mcr.microsoft.com/devcontainers/go:1-1.22-bookworm",
+	"forwardPorts": [8080, 9090,],
+	"containerEnv": {
+		"URL": "http://x//y", // not a comment inside a string
+		"QUOTE": "a \"/* b */\" c",
+	},
+}
+`), 0o600))
+	dc, err := LoadDevcontainer(path)
+	require.NoError(t, err)
+	assert.Equal(t, "mcr.microsoft.com/devcontainers/go:1-1.22-bookworm", dc.Image)
+	assert.Equal(t, []int{8080, 9090}, dc.ForwardPorts)
+	assert.Equal(t, "http://x//y", dc.ContainerEnv["URL"])
+	assert.Equal(t, `a "/* b */" c`, dc.ContainerEnv["QUOTE"])
+}
+
+func TestToProfile(t *testing.T) {
+	dc := &DevcontainerConfig{
+		Image:        "mcr.microsoft.com/devcontainers/go:1-1.22-bookworm",
+		ForwardPorts: []int{8080},
+		AppPort:      []int{3000},
+		ContainerEnv: map[string]string{"GOFLAGS": "-mod=mod", "PATH": "${containerEnv:PATH}:/x"},
+		RunArgs:      []string{"--cpus=2", "--memory", "4g", "--cap-add=SYS_ADMIN", "--cap-add", "NET_RAW"},
+		Features: map[string]any{
+			"ghcr.io/devcontainers/features/node:1":             map[string]any{"version": "18"},
+			"ghcr.io/devcontainers/features/github-cli:1":       map[string]any{},
+			"ghcr.io/devcontainers/features/docker-in-docker:2": map[string]any{},
+			"ghcr.io/devcontainers/features/python:1":           map[string]any{"version": "none"},
+			"ghcr.io/example/features/terraform:1":              map[string]any{},
+		},
+		Mounts: []string{"source=/tmp,target=/tmp,type=bind"},
+	}
+	dc.PostCreateCommand.raw = "go mod download"
+
+	p, err := dc.ToProfile("/src/api/.devcontainer/devcontainer.json")
+	require.NoError(t, err)
+
+	var cfg struct {
+		Env       map[string]string `yaml:"env"`
+		Ports     []string          `yaml:"ports"`
+		Resources map[string]string `yaml:"resources"`
+		CapAdd    []string          `yaml:"cap_add"`
+	}
+	require.NoError(t, yaml.Unmarshal(p.Config, &cfg))
+	assert.Equal(t, map[string]string{"GOFLAGS": "-mod=mod"}, cfg.Env, "values referring to ${...} are left out")
+	assert.Equal(t, []string{"8080:8080", "3000:3000"}, cfg.Ports)
+	assert.Equal(t, map[string]string{"cpus": "2", "memory": "4g"}, cfg.Resources)
+	assert.Equal(t, []string{"NET_RAW"}, cfg.CapAdd, "dangerous capabilities are refused")
+	assert.Contains(t, string(p.Config), "# isolation: container-privileged", "nested Docker is left to the user")
+
+	df := string(p.Dockerfile)
+	assert.Contains(t, df, "FROM yoloai-base\n")
+	assert.Contains(t, df, "# Go 1.22 (image mcr.microsoft.com/devcontainers/go:1-1.22-bookworm)")
+	assert.Contains(t, df, "--arg v go1.22 ")
+	assert.Contains(t, df, "https://deb.nodesource.com/node_18.x")
+	assert.Contains(t, df, "install -y --no-install-recommends gh")
+	assert.Contains(t, df, "#   ghcr.io/example/features/terraform:1")
+	assert.NotContains(t, df, "uv python install", `version "none" asks for nothing`)
+
+	joined := func() string {
+		s := ""
+		for _, n := range p.Notes {
+			s += n + "\n"
+		}
+		return s
+	}()
+	for _, want := range []string{"SYS_ADMIN", "PATH", "terraform", "mounts", "lifecycle", "container-privileged"} {
+		assert.Contains(t, joined, want)
+	}
+}
+
+func TestToProfile_NothingToBuild(t *testing.T) {
+	dc := &DevcontainerConfig{
+		Image:    "mcr.microsoft.com/devcontainers/base:bookworm",
+		Features: map[string]any{"ghcr.io/devcontainers/features/common-utils:2": map[string]any{}},
+	}
+	p, err := dc.ToProfile("devcontainer.json")
+	require.NoError(t, err)
+	assert.Nil(t, p.Dockerfile, "yoloai-base already covers it")
+	assert.Empty(t, p.Notes)
+	assert.Equal(t, "# Generated by 'yoloai profile from-devcontainer' from devcontainer.json.\n", string(p.Config))
+
+	_, err = (&DevcontainerConfig{DockerComposeFile: "compose.yaml"}).ToProfile("x")
+	assert.Error(t, err)
+}
+
+func TestToProfile_ImageVersions(t *testing.T) {
+	for _, tc := range []struct{ image, want string }{
+		{"golang:1.23-bookworm", "# Go 1.23 ("},
+		{"mcr.microsoft.com/devcontainers/javascript-node:1-20-bookworm", "# Node.js 20 ("},
+		{"mcr.microsoft.com/devcontainers/typescript-node:22", "# Node.js 22 ("},
+		{"mcr.microsoft.com/devcontainers/python:3.12", "# Python 3.12 ("},
+		{"python:3.11-slim@sha256:abcd", "# Python 3.11 ("},
+		{"mcr.microsoft.com/devcontainers/rust:1-1.80", "# Rust 1.80 ("},
+		{"eclipse-temurin:21-jdk", "openjdk-21-jdk-headless"},
+		{"mcr.microsoft.com/devcontainers/java:17", "openjdk-17-jdk-headless"},
+	} {
+		p, err := (&DevcontainerConfig{Image: tc.image}).ToProfile("x")
+		require.NoError(t, err, tc.image)
+		assert.Contains(t, string(p.Dockerfile), tc.want, tc.image)
+	}
+
+	// The image's own major alone, or a moving tag, leaves yoloai-base's toolchain.
+	for _, image := range []string{"mcr.microsoft.com/devcontainers/go:1", "golang:latest", "rust"} {
+		p, err := (&DevcontainerConfig{Image: image}).ToProfile("x")
+		require.NoError(t, err, image)
+		assert.Nil(t, p.Dockerfile, image)
+	}
+
+	p, err := (&DevcontainerConfig{Image: "ghcr.io/acme/toolbox:3"}).ToProfile("x")
+	require.NoError(t, err)
+	require.Len(t, p.Notes, 1)
+	assert.Contains(t, p.Notes[0], "ghcr.io/acme/toolbox:3")
+}
@@ -1,5 +1,7 @@
 // ABOUTME: Loads and validates .yoloai.yaml project configuration files.
-// ABOUTME: Provides archetype declaration, extra mounts, and version requirements for projects.
+// ABOUTME: Provides archetype declaration, extra mounts, version requirements, and the
+// ABOUTME: project's sandbox defaults (profile, agent, model, model aliases, ports,
+// ABOUTME: env, network) and what apply does around the change (verify, changelog).
 
 package archetype
 
@@ -9,15 +11,36 @@ import (
 	"path/filepath"
 
 	"github.com/kstenerud/yoloai/internal/config"
+	"github.com/kstenerud/yoloai/internal/orchestrator/changelog"
 	"gopkg.in/yaml.v3"
 )
 
 // YoloAIProjectConfig holds the parsed contents of a project's .yoloai.yaml file.
 // This file is checked into the project repo and expresses project-level environment requirements.
+// Profile through Network are defaults for sandboxes of the project: the
+// create pipeline applies them below CLI flags (see create.applyProjectDefaults).
+// ModelAliases are merged over the global and profile aliases.
 type YoloAIProjectConfig struct {
 	Archetype string            `yaml:"archetype,omitempty"`
 	Mounts    []string          `yaml:"mounts,omitempty"`
 	Requires  map[string]string `yaml:"requires,omitempty"`
+
+	Profile string                `yaml:"profile,omitempty"`
+	Agent   string                `yaml:"agent,omitempty"`
+	Model   string                `yaml:"model,omitempty"`
+	Ports   []string              `yaml:"ports,omitempty"`
+	Env     map[string]string     `yaml:"env,omitempty"`
+	Network *config.NetworkConfig `yaml:"network,omitempty"`
+
+	ModelAliases map[string]string `yaml:"model_aliases,omitempty"`
+
+	// Verify is the shell command `yoloai apply` runs in the sandbox against
+	// the work copy before applying; a non-zero exit stops the apply.
+	Verify string `yaml:"verify,omitempty"`
+
+	// Changelog is where `yoloai apply --changelog` writes a changelog
+	// fragment for the change, and in which format.
+	Changelog *changelog.Config `yaml:"changelog,omitempty"`
 }
 
 // LoadYoloAIYaml looks for .yoloai.yaml in workdir.
@@ -49,6 +72,18 @@ func LoadYoloAIYaml(workdir, homeDir string, env map[string]string) (*YoloAIProj
 		}
 	}
 
+	if cfg.Profile != "" {
+		if err := config.ValidateProfileName(cfg.Profile); err != nil {
+			return nil, false, fmt.Errorf(".yoloai.yaml: profile: %w", err)
+		}
+	}
+
+	if cfg.Changelog != nil {
+		if err := cfg.Changelog.Validate(); err != nil {
+			return nil, false, fmt.Errorf(".yoloai.yaml: %w", err)
+		}
+	}
+
 	// Expand tilde in mounts
 	for i, m := range cfg.Mounts {
 		// Only expand the host part (before the first colon that isn't part of a Windows path)
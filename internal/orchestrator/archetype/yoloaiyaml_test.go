@@ -40,6 +40,36 @@ requires:
 	assert.Equal(t, map[string]string{"yoloai": ">=1.0"}, cfg.Requires)
 }
 
+func TestLoadYoloAIYaml_Verify(t *testing.T) {
+	dir := t.TempDir()
+	content := "verify: make test && go vet ./...\n"
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".yoloai.yaml"), []byte(content), 0600))
+
+	cfg, found, err := LoadYoloAIYaml(dir, "/home/user", nil)
+	require.NoError(t, err)
+	assert.True(t, found)
+	require.NotNil(t, cfg)
+	assert.Equal(t, "make test && go vet ./...", cfg.Verify)
+}
+
+func TestLoadYoloAIYaml_Changelog(t *testing.T) {
+	dir := t.TempDir()
+	content := "changelog:\n  dir: changelog.d\n  format: keep-a-changelog\n  type: Fixed\n"
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".yoloai.yaml"), []byte(content), 0600))
+
+	cfg, _, err := LoadYoloAIYaml(dir, "/home/user", nil)
+	require.NoError(t, err)
+	require.NotNil(t, cfg.Changelog)
+	assert.Equal(t, "changelog.d", cfg.Changelog.Dir)
+	assert.Equal(t, "keep-a-changelog", cfg.Changelog.Format)
+	assert.Equal(t, "Fixed", cfg.Changelog.Type)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".yoloai.yaml"), []byte("changelog:\n  dir: ../outside\n"), 0600))
+	_, _, err = LoadYoloAIYaml(dir, "/home/user", nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "inside the repository")
+}
+
 func TestLoadYoloAIYaml_UnknownArchetype(t *testing.T) {
 	dir := t.TempDir()
 	content := "archetype: invalid-archetype\n"
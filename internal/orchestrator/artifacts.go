@@ -0,0 +1,136 @@
+// ABOUTME: Host-side view of a sandbox's outbox/ — the artifacts the agent hands
+// ABOUTME: back (reports, generated assets): listing and collecting to the host.
+package orchestrator
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/kstenerud/yoloai/internal/config"
+	"github.com/kstenerud/yoloai/internal/fileutil"
+	"github.com/kstenerud/yoloai/store"
+)
+
+// Artifact is one file the agent left in the sandbox's outbox.
+type Artifact struct {
+	Path    string    // relative to the outbox, slash-separated
+	Size    int64     // bytes
+	ModTime time.Time // last modification
+}
+
+// OutboxDir returns the host path of the sandbox's outbox. It does not create
+// the directory or check that the sandbox exists.
+func OutboxDir(layout config.Layout, name string) string {
+	return store.OutboxDir(layout.SandboxDir(name))
+}
+
+// ListArtifacts returns every regular file under the sandbox's outbox, sorted by
+// path. Symlinks are skipped rather than followed: the outbox is writable from
+// inside the sandbox, so a link could point anywhere on the host. A missing
+// outbox (a sandbox that predates it, or one the agent never wrote to) yields
+// an empty list.
+func ListArtifacts(layout config.Layout, name string) ([]Artifact, error) {
+	outbox := OutboxDir(layout, name)
+	artifacts := make([]Artifact, 0)
+	err := filepath.WalkDir(outbox, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if path == outbox && os.IsNotExist(err) {
+				return fs.SkipAll
+			}
+			return err
+		}
+		if !d.Type().IsRegular() {
+			return nil // directories are walked; links and specials are skipped
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(outbox, path)
+		if err != nil {
+			return err
+		}
+		artifacts = append(artifacts, Artifact{
+			Path:    filepath.ToSlash(rel),
+			Size:    info.Size(),
+			ModTime: info.ModTime(),
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list outbox: %w", err)
+	}
+	sort.Slice(artifacts, func(i, j int) bool { return artifacts[i].Path < artifacts[j].Path })
+	return artifacts, nil
+}
+
+// CollectArtifacts copies the outbox artifacts matching patterns (all of them
+// when patterns is empty) into destDir on the host, keeping their paths
+// relative to the outbox. A pattern matches an artifact's relative path or its
+// base name. Without force, an existing destination file is an error and
+// nothing after it is copied. Returns the relative paths copied.
+func CollectArtifacts(ctx context.Context, layout config.Layout, name, destDir string, patterns []string, force bool) ([]string, error) {
+	artifacts, err := ListArtifacts(layout, name)
+	if err != nil {
+		return nil, err
+	}
+	selected, err := matchArtifacts(artifacts, patterns)
+	if err != nil {
+		return nil, err
+	}
+	if len(selected) == 0 {
+		return nil, nil
+	}
+
+	outbox := OutboxDir(layout, name)
+	cpEnv := layout.Env().EnvForHostTool()
+	copied := make([]string, 0, len(selected))
+	for _, a := range selected {
+		src, err := resolveExchangePath(outbox, filepath.FromSlash(a.Path))
+		if err != nil {
+			return copied, err
+		}
+		dst := filepath.Join(destDir, filepath.FromSlash(a.Path))
+		if !force {
+			if _, err := os.Stat(dst); err == nil {
+				return copied, fmt.Errorf("destination already exists: %s (use --overwrite to replace it)", dst)
+			}
+		}
+		if err := fileutil.MkdirAll(filepath.Dir(dst), 0750); err != nil {
+			return copied, fmt.Errorf("create %s: %w", filepath.Dir(dst), err)
+		}
+		if err := copyTree(ctx, cpEnv, src, dst); err != nil {
+			return copied, err
+		}
+		copied = append(copied, a.Path)
+	}
+	return copied, nil
+}
+
+// matchArtifacts filters artifacts to those matching any of patterns, keeping
+// their order. An empty pattern list selects everything.
+func matchArtifacts(artifacts []Artifact, patterns []string) ([]Artifact, error) {
+	if len(patterns) == 0 {
+		return artifacts, nil
+	}
+	var selected []Artifact
+	for _, a := range artifacts {
+		for _, pat := range patterns {
+			full, err := filepath.Match(pat, a.Path)
+			if err != nil {
+				return nil, fmt.Errorf("invalid pattern %q: %w", pat, err)
+			}
+			base, _ := filepath.Match(pat, filepath.Base(filepath.FromSlash(a.Path)))
+			if full || base {
+				selected = append(selected, a)
+				break
+			}
+		}
+	}
+	return selected, nil
+}
@@ -0,0 +1,76 @@
+// ABOUTME: Tests for the outbox artifact view — listing (symlinks skipped) and
+// ABOUTME: collecting to the host with patterns and overwrite protection.
+package orchestrator
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeArtifact places a file at rel inside the sandbox's outbox.
+func writeArtifact(t *testing.T, outbox, rel, content string) {
+	t.Helper()
+	path := filepath.Join(outbox, filepath.FromSlash(rel))
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0750))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0600))
+}
+
+func TestListArtifacts_MissingOutboxIsEmpty(t *testing.T) {
+	layout, name := filesTestLayout(t)
+	items, err := ListArtifacts(layout, name)
+	require.NoError(t, err)
+	assert.Empty(t, items)
+}
+
+func TestListArtifacts_WalksTreeAndSkipsSymlinks(t *testing.T) {
+	layout, name := filesTestLayout(t)
+	outbox := OutboxDir(layout, name)
+	writeArtifact(t, outbox, "report.md", "# done")
+	writeArtifact(t, outbox, "img/chart.png", "png")
+	require.NoError(t, os.Symlink("/etc/passwd", filepath.Join(outbox, "leak")))
+
+	items, err := ListArtifacts(layout, name)
+	require.NoError(t, err)
+	require.Len(t, items, 2)
+	assert.Equal(t, "img/chart.png", items[0].Path)
+	assert.Equal(t, "report.md", items[1].Path)
+	assert.Equal(t, int64(len("# done")), items[1].Size)
+}
+
+func TestCollectArtifacts_CopiesMatchesKeepingPaths(t *testing.T) {
+	layout, name := filesTestLayout(t)
+	outbox := OutboxDir(layout, name)
+	writeArtifact(t, outbox, "report.md", "# done")
+	writeArtifact(t, outbox, "img/chart.png", "png")
+	writeArtifact(t, outbox, "notes.txt", "n")
+	dest := filepath.Join(t.TempDir(), "out")
+
+	copied, err := CollectArtifacts(context.Background(), layout, name, dest, []string{"*.png", "report.md"}, false)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"img/chart.png", "report.md"}, copied)
+	assert.FileExists(t, filepath.Join(dest, "img", "chart.png"))
+	assert.FileExists(t, filepath.Join(dest, "report.md"))
+	assert.NoFileExists(t, filepath.Join(dest, "notes.txt"))
+}
+
+func TestCollectArtifacts_RefusesOverwriteWithoutForce(t *testing.T) {
+	layout, name := filesTestLayout(t)
+	writeArtifact(t, OutboxDir(layout, name), "report.md", "new")
+	dest := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dest, "report.md"), []byte("old"), 0600))
+
+	_, err := CollectArtifacts(context.Background(), layout, name, dest, nil, false)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "already exists")
+
+	_, err = CollectArtifacts(context.Background(), layout, name, dest, nil, true)
+	require.NoError(t, err)
+	got, err := os.ReadFile(filepath.Join(dest, "report.md")) //nolint:gosec // test path
+	require.NoError(t, err)
+	assert.Equal(t, "new", string(got))
+}
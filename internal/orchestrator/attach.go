@@ -1,19 +1,24 @@
 // ABOUTME: Library-side attach-readiness helpers. Polls sandbox.jsonl / tmux
-// ABOUTME: has-session to know when a started sandbox is ready for tmux attach.
+// ABOUTME: has-session to know when a started sandbox is ready for tmux attach,
+// ABOUTME: and re-attaches when a runtime hiccup drops the attach exec.
 
 package orchestrator
 
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"log/slog"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/kstenerud/yoloai/internal/config"
 	"github.com/kstenerud/yoloai/runtime"
 	"github.com/kstenerud/yoloai/store"
+	"github.com/kstenerud/yoloai/yoerrors"
 )
 
 // attachReadyTimeout bounds how long Attach waits for the agent's tmux session
@@ -21,14 +26,44 @@ import (
 // its session within seconds; 5 minutes covers a cold image pull/build.
 const attachReadyTimeout = 300 * time.Second
 
+const (
+	// attachReconnectTimeout bounds how long a dropped attach waits for the
+	// runtime to answer again — a docker daemon restart, a VM coming back.
+	attachReconnectTimeout = 2 * time.Minute
+	// attachReconnectPoll is how often the sandbox is probed meanwhile.
+	attachReconnectPoll = time.Second
+	// attachStableAfter is how long an attach must have lasted for its drop
+	// to start a fresh run of reconnects rather than count against the last.
+	attachStableAfter = 30 * time.Second
+	// attachMaxReconnects caps back-to-back reconnects of attaches that keep
+	// dropping straight away, so a broken exec path fails instead of looping.
+	attachMaxReconnects = 5
+)
+
+// ShellWindow is the tmux window the entrypoint opens beside the agent's: a
+// plain shell in the workdir, for poking around the work copy.
+const ShellWindow = "shell"
+
+// AttachOptions configures Engine.Attach.
+type AttachOptions struct {
+	// ReadOnly attaches with tmux's -r: the client sees the session but its
+	// keystrokes don't reach the agent.
+	ReadOnly bool
+	// Window selects the tmux window to show: a split-role sandbox's role
+	// name, ShellWindow, or "vscode-tunnel". "" = the session's current window.
+	Window string
+}
+
 // Attach connects io to the sandbox's tmux session and blocks until the user
 // detaches (Ctrl-B d) or the agent exits. It owns the full interactive-attach
 // orchestration — status gate, container/user resolution, attach-readiness
 // poll, and the runtime attach exec — so the public Agent.Attach reduces to a
 // TTY check plus this one call (mirroring CaptureTerminal/SendInput). The
 // sandbox must be running (Active/Idle/Done/Failed); stopped sandboxes return
-// ErrContainerNotRunning.
-func (e *Engine) Attach(ctx context.Context, name string, io runtime.IOStreams) error {
+// ErrContainerNotRunning. When the attach exec drops while the session lives
+// on — a docker daemon restart, a VM reboot — it is re-established with a
+// banner on io.Err (see awaitReattach) instead of returning.
+func (e *Engine) Attach(ctx context.Context, name string, opts AttachOptions, io runtime.IOStreams) error {
 	if err := e.ensure(ctx); err != nil {
 		return err
 	}
@@ -39,16 +74,284 @@ func (e *Engine) Attach(ctx context.Context, name string, io runtime.IOStreams)
 	if err := attachStatusOK(info.Status, name); err != nil {
 		return err
 	}
+	window, err := attachWindow(info.Environment, name, opts.Window)
+	if err != nil {
+		return err
+	}
 	user := ContainerUser(info.Environment, e.layout.HostUID)
 	if err := WaitForAttachReady(ctx, e.runtime, e.layout, name, user, attachReadyTimeout); err != nil {
 		return fmt.Errorf("waiting for tmux session: %w", err)
 	}
 	socket := runtime.TmuxSocketFor(e.runtime, e.layout.SandboxDir(name))
+	if window == ShellWindow {
+		if err := e.ensureShellWindow(ctx, name, user, socket); err != nil {
+			return err
+		}
+		// Every client of the session shows its current window, so put the
+		// agent's back once the shell is left: a plain attach lands there.
+		defer e.selectAgentWindow(name, user, socket)
+	}
 	cmd, ok := runtime.AttachCommandFor(e.runtime, socket, io.Rows, io.Cols, info.Environment.Isolation)
 	if !ok {
 		return fmt.Errorf("backend %s does not support interactive attach", e.runtime.Descriptor().Type)
 	}
-	return e.runtime.InteractiveExec(ctx, store.InstanceName(e.layout.Principal, name), cmd, user, "", io)
+	if window != "" {
+		cmd = windowAttachCommand(cmd, window)
+	}
+	if opts.ReadOnly {
+		cmd = readOnlyAttachCommand(cmd)
+	}
+	probe := func(ctx context.Context) attachState { return e.attachSessionState(ctx, name, user, socket) }
+	banner := io.Err
+	if banner == nil {
+		banner = io.Out
+	}
+	quickDrops := 0
+	for {
+		started := time.Now()
+		err := e.runtime.InteractiveExec(ctx, store.InstanceName(e.layout.Principal, name), cmd, user, "", io)
+		if err == nil || ctx.Err() != nil {
+			return err
+		}
+		if time.Since(started) >= attachStableAfter {
+			quickDrops = 0
+		}
+		if quickDrops >= attachMaxReconnects {
+			return err
+		}
+		quickDrops++
+		slog.Debug("attach exec ended with an error", "event", "sandbox.attach.dropped", "sandbox", name, "err", err)
+		if !awaitReattach(ctx, probe, banner, name, err, attachReconnectTimeout, attachReconnectPoll) {
+			return err
+		}
+	}
+}
+
+// attachState is what a probe found of a sandbox's tmux session after its
+// attach exec ended with an error.
+type attachState int
+
+const (
+	// attachUnknown: the runtime isn't answering (yet); keep probing.
+	attachUnknown attachState = iota
+	// attachLive: the session is there, so only the attach exec dropped.
+	attachLive
+	// attachGone: the session or the sandbox is gone; nothing to re-attach to.
+	attachGone
+)
+
+// attachSessionState probes whether the sandbox's tmux session can be
+// re-attached. A stopped, paused or removed sandbox, and a has-session that
+// ran and failed, are gone for good; runtime errors are unknown, since the
+// daemon or VM may just be coming back.
+func (e *Engine) attachSessionState(ctx context.Context, name, user, socket string) attachState {
+	info, err := e.Inspect(ctx, name)
+	if err != nil {
+		return attachUnknown
+	}
+	switch info.Status {
+	case StatusActive, StatusIdle, StatusDone, StatusFailed:
+	case StatusUnavailable:
+		return attachUnknown
+	default:
+		return attachGone
+	}
+	_, err = e.runtime.Exec(ctx, store.InstanceName(e.layout.Principal, name), buildTmuxHasSessionArgs(socket), user)
+	var ee *runtime.ExecError
+	switch {
+	case err == nil:
+		return attachLive
+	case errors.As(err, &ee):
+		return attachGone
+	default:
+		return attachUnknown
+	}
+}
+
+// awaitReattach decides whether an attach whose exec ended with cause should
+// be re-established. A session that's gone ends the attach quietly, as a
+// clean exit would. Otherwise the user is told the connection dropped, and
+// the session is probed every poll until it's reachable (true) or gone, the
+// timeout passes or ctx ends (false). The banner is written to out, which
+// the caller's terminal has in raw mode, hence the explicit CRs.
+func awaitReattach(ctx context.Context, probe func(context.Context) attachState, out io.Writer, name string, cause error, timeout, poll time.Duration) bool {
+	state := probe(ctx)
+	if state == attachGone {
+		return false
+	}
+	fmt.Fprintf(out, "\r\n[yoloai] lost the connection to sandbox %s (%v); reconnecting...\r\n", name, cause) //nolint:errcheck // best-effort banner
+	deadline := time.Now().Add(timeout)
+	for state == attachUnknown {
+		if time.Now().After(deadline) {
+			fmt.Fprintf(out, "[yoloai] sandbox %s didn't come back within %s; giving up\r\n", name, timeout) //nolint:errcheck // best-effort banner
+			return false
+		}
+		if sleepOrCancel(ctx, poll) != nil {
+			return false
+		}
+		state = probe(ctx)
+	}
+	if state == attachGone {
+		fmt.Fprintf(out, "[yoloai] sandbox %s stopped; run 'yoloai attach %s' to start it again\r\n", name, name) //nolint:errcheck // best-effort banner
+		return false
+	}
+	fmt.Fprintf(out, "[yoloai] reconnected to sandbox %s\r\n", name) //nolint:errcheck // best-effort banner
+	slog.Info("re-attached after a dropped attach", "event", "sandbox.attach.reconnected", "sandbox", name)
+	return true
+}
+
+// readOnlyAttachCommand adds tmux's -r (read-only) to a backend's attach
+// command. Backends spell the command either as argv ("attach", "-t", "main")
+// or as a shell string wrapped by script/sh ("... attach -t main"), so both
+// forms are rewritten; the backends' own AttachCommand stays option-free.
+func readOnlyAttachCommand(cmd []string) []string {
+	out := make([]string, 0, len(cmd)+1)
+	for _, arg := range cmd {
+		switch {
+		case arg == "attach":
+			out = append(out, arg, "-r")
+		case strings.Contains(arg, " attach -t "):
+			out = append(out, strings.Replace(arg, " attach -t ", " attach -r -t ", 1))
+		default:
+			out = append(out, arg)
+		}
+	}
+	return out
+}
+
+// attachWindow resolves --window to a tmux target window. A role's window is
+// named after it, except the first role's: that is the main window, whose
+// name the status monitor keeps rewriting, so it is addressed as the lowest
+// window instead. Returns a *UsageError for a window the sandbox doesn't have.
+func attachWindow(env *store.Environment, name, window string) (string, error) {
+	if window == "" || env == nil {
+		return "", nil
+	}
+	if window == "vscode-tunnel" && env.VscodeTunnel {
+		return window, nil
+	}
+	if window == ShellWindow {
+		return window, nil
+	}
+	for i, r := range env.Roles {
+		if r == window {
+			if i == 0 {
+				return "{start}", nil
+			}
+			return window, nil
+		}
+	}
+	if len(env.Roles) == 0 {
+		return "", yoerrors.NewUsageError("sandbox %s has no window %q: it runs a single agent (see 'new --role')", name, window)
+	}
+	return "", yoerrors.NewUsageError("sandbox %s has no window %q; its roles are: %s", name, window, strings.Join(env.Roles, ", "))
+}
+
+// ensureShellWindow opens the shell window when the session doesn't have it:
+// the sandbox predates it, or its shell was exited, which closes the window.
+// The new window starts in the session's directory, the workdir.
+func (e *Engine) ensureShellWindow(ctx context.Context, name, user, socket string) error {
+	instance := store.InstanceName(e.layout.Principal, name)
+	res, err := e.runtime.Exec(ctx, instance, tmuxArgs(socket, "list-windows", "-t", "main", "-F", "#{window_name}"), user)
+	if err != nil {
+		return fmt.Errorf("list windows of sandbox %q: %w", name, err)
+	}
+	for line := range strings.SplitSeq(res.Stdout, "\n") {
+		if strings.TrimSpace(line) == ShellWindow {
+			return nil
+		}
+	}
+	if _, err := e.runtime.Exec(ctx, instance, tmuxArgs(socket, "new-window", "-d", "-t", "main", "-n", ShellWindow), user); err != nil {
+		return fmt.Errorf("open shell window in sandbox %q: %w", name, err)
+	}
+	_, err = e.runtime.Exec(ctx, instance, tmuxArgs(socket, "set-window-option", "-t", "main:"+ShellWindow, "remain-on-exit", "off"), user)
+	return err
+}
+
+// selectAgentWindow makes the agent's window the session's current one again,
+// best effort: the shell attach it follows has already ended.
+func (e *Engine) selectAgentWindow(name, user, socket string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if _, err := e.runtime.Exec(ctx, store.InstanceName(e.layout.Principal, name), tmuxArgs(socket, "select-window", "-t", "main:{start}"), user); err != nil {
+		slog.Debug("could not select the agent window", "event", "sandbox.attach.select_window_fail", "sandbox", name, "err", err)
+	}
+}
+
+// tmuxArgs is a tmux command line on the sandbox's socket ("" = the default).
+func tmuxArgs(socket string, args ...string) []string {
+	cmd := []string{"tmux"}
+	if socket != "" {
+		cmd = append(cmd, "-S", socket)
+	}
+	return append(cmd, args...)
+}
+
+// windowAttachCommand points a backend's attach command at one window of the
+// session, rewriting both command forms as readOnlyAttachCommand does.
+func windowAttachCommand(cmd []string, window string) []string {
+	out := make([]string, 0, len(cmd))
+	for i, arg := range cmd {
+		switch {
+		case arg == "main" && i > 0 && cmd[i-1] == "-t":
+			out = append(out, "main:"+window)
+		case strings.HasSuffix(arg, " attach -t main"):
+			out = append(out, arg+":"+window)
+		default:
+			out = append(out, arg)
+		}
+	}
+	return out
+}
+
+// AttachedClient is a terminal attached to a sandbox's tmux session.
+type AttachedClient struct {
+	// TTY is the client's terminal inside the sandbox (e.g. "/dev/pts/3").
+	TTY string `json:"tty"`
+	// ReadOnly reports a client attached with --read-only, whose keystrokes
+	// don't reach the agent.
+	ReadOnly bool `json:"read_only,omitempty"`
+}
+
+// AttachedClients lists the terminals currently attached to the sandbox's
+// tmux session. A sandbox that isn't running has none.
+func (e *Engine) AttachedClients(ctx context.Context, name string) ([]AttachedClient, error) {
+	if err := e.ensure(ctx); err != nil {
+		return nil, err
+	}
+	info, err := e.Inspect(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if attachStatusOK(info.Status, name) != nil {
+		return nil, nil
+	}
+	args := []string{"tmux"}
+	if socket := runtime.TmuxSocketFor(e.runtime, e.layout.SandboxDir(name)); socket != "" {
+		args = append(args, "-S", socket)
+	}
+	args = append(args, "list-clients", "-t", "main", "-F", "#{client_tty} #{client_readonly}")
+	res, err := e.runtime.Exec(ctx, store.InstanceName(e.layout.Principal, name), args, ContainerUser(info.Environment, e.layout.HostUID))
+	if err != nil {
+		return nil, fmt.Errorf("list attached clients of sandbox %q: %w", name, err)
+	}
+	return parseTmuxClients(res.Stdout), nil
+}
+
+// parseTmuxClients parses `tmux list-clients -F "#{client_tty} #{client_readonly}"`.
+func parseTmuxClients(out string) []AttachedClient {
+	var clients []AttachedClient
+	for line := range strings.SplitSeq(strings.TrimSpace(out), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		clients = append(clients, AttachedClient{
+			TTY:      fields[0],
+			ReadOnly: len(fields) > 1 && fields[1] == "1",
+		})
+	}
+	return clients
 }
 
 // attachStatusOK returns nil if the sandbox status permits attach, otherwise a
@@ -57,6 +360,8 @@ func attachStatusOK(status Status, name string) error {
 	switch status {
 	case StatusActive, StatusIdle, StatusDone, StatusFailed:
 		return nil
+	case StatusPaused:
+		return yoerrors.NewUsageError("sandbox %s is paused — run 'yoloai unpause %s' first", name, name)
 	default:
 		// StatusStopped, StatusRemoved, StatusBroken, StatusUnavailable
 		return fmt.Errorf("sandbox %q: %w", name, ErrContainerNotRunning)
@@ -150,11 +455,7 @@ func pollAttachReady(
 
 // buildTmuxHasSessionArgs constructs the tmux has-session argument list.
 func buildTmuxHasSessionArgs(tmuxSocket string) []string {
-	args := []string{"tmux"}
-	if tmuxSocket != "" {
-		args = append(args, "-S", tmuxSocket)
-	}
-	return append(args, "has-session", "-t", "main")
+	return tmuxArgs(tmuxSocket, "has-session", "-t", "main")
 }
 
 // sleepOrCancel waits for the given duration or returns ctx.Err() if cancelled.
@@ -0,0 +1,138 @@
+package orchestrator
+
+// ABOUTME: Unit tests for attach helpers — the read-only and window rewrites of
+// ABOUTME: each backend's attach command, --window lookup, list-clients parsing,
+// ABOUTME: and the reconnect decision after a dropped attach.
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kstenerud/yoloai/runtime"
+	"github.com/kstenerud/yoloai/store"
+	"github.com/kstenerud/yoloai/yoerrors"
+)
+
+func TestReadOnlyAttachCommand(t *testing.T) {
+	// argv form (seatbelt, tart, gVisor arm64 docker)
+	assert.Equal(t,
+		[]string{"tmux", "-S", "/s", "attach", "-r", "-t", "main"},
+		readOnlyAttachCommand([]string{"tmux", "-S", "/s", "attach", "-t", "main"}))
+	// shell-string form (docker/apple via script, containerd via sh -c)
+	assert.Equal(t,
+		[]string{"/usr/bin/script", "-q", "-e", "-c", "exec tmux -S /s attach -r -t main", "/dev/null"},
+		readOnlyAttachCommand([]string{"/usr/bin/script", "-q", "-e", "-c", "exec tmux -S /s attach -t main", "/dev/null"}))
+}
+
+func TestParseTmuxClients(t *testing.T) {
+	assert.Equal(t, []AttachedClient{
+		{TTY: "/dev/pts/3"},
+		{TTY: "/dev/pts/5", ReadOnly: true},
+	}, parseTmuxClients("/dev/pts/3 0\n/dev/pts/5 1\n"))
+	assert.Empty(t, parseTmuxClients(""))
+}
+
+func TestWindowAttachCommand(t *testing.T) {
+	assert.Equal(t,
+		[]string{"tmux", "-S", "/s", "attach", "-r", "-t", "main:tester"},
+		readOnlyAttachCommand(windowAttachCommand([]string{"tmux", "-S", "/s", "attach", "-t", "main"}, "tester")))
+	assert.Equal(t,
+		[]string{"/usr/bin/script", "-q", "-e", "-c", "exec tmux -S /s attach -t main:{start}", "/dev/null"},
+		windowAttachCommand([]string{"/usr/bin/script", "-q", "-e", "-c", "exec tmux -S /s attach -t main", "/dev/null"}, "{start}"))
+}
+
+func TestAttachWindow(t *testing.T) {
+	env := &store.Environment{Roles: []string{"implementer", "tester"}}
+	w, err := attachWindow(env, "sb", "implementer")
+	require.NoError(t, err)
+	assert.Equal(t, "{start}", w, "the first role is the main window")
+	w, err = attachWindow(env, "sb", "tester")
+	require.NoError(t, err)
+	assert.Equal(t, "tester", w)
+	w, err = attachWindow(env, "sb", "")
+	require.NoError(t, err)
+	assert.Empty(t, w)
+
+	_, err = attachWindow(env, "sb", "reviewer")
+	var ue *yoerrors.UsageError
+	require.ErrorAs(t, err, &ue)
+	assert.Contains(t, err.Error(), "implementer, tester")
+	_, err = attachWindow(&store.Environment{}, "sb", "tester")
+	require.ErrorAs(t, err, &ue)
+
+	w, err = attachWindow(&store.Environment{}, "sb", ShellWindow)
+	require.NoError(t, err)
+	assert.Equal(t, ShellWindow, w, "every sandbox has a shell window")
+}
+
+func TestEnsureShellWindow(t *testing.T) {
+	mock := &terminalMockRuntime{plainResult: runtime.ExecResult{Stdout: "claude\nshell\n"}}
+	mgr := newTerminalMgr(mock, t.TempDir())
+	require.NoError(t, mgr.ensureShellWindow(context.Background(), "sb", "yoloai", "/s"))
+	assert.Equal(t, [][]string{{"tmux", "-S", "/s", "list-windows", "-t", "main", "-F", "#{window_name}"}}, mock.execCalls,
+		"an open shell window is left alone")
+
+	mock = &terminalMockRuntime{plainResult: runtime.ExecResult{Stdout: "claude\n"}}
+	mgr = newTerminalMgr(mock, t.TempDir())
+	require.NoError(t, mgr.ensureShellWindow(context.Background(), "sb", "yoloai", ""))
+	require.Len(t, mock.execCalls, 3)
+	assert.Equal(t, []string{"tmux", "new-window", "-d", "-t", "main", "-n", "shell"}, mock.execCalls[1])
+	assert.Equal(t, []string{"tmux", "set-window-option", "-t", "main:shell", "remain-on-exit", "off"}, mock.execCalls[2])
+}
+
+// probeSequence returns a probe that reports states in order, repeating the
+// last one, and counts its calls.
+func probeSequence(calls *int, states ...attachState) func(context.Context) attachState {
+	return func(context.Context) attachState {
+		st := states[min(*calls, len(states)-1)]
+		*calls++
+		return st
+	}
+}
+
+func TestAwaitReattach(t *testing.T) {
+	dropped := errors.New("connection reset")
+
+	t.Run("session gone ends quietly", func(t *testing.T) {
+		var out bytes.Buffer
+		calls := 0
+		assert.False(t, awaitReattach(context.Background(), probeSequence(&calls, attachGone), &out, "sb", dropped, time.Second, time.Millisecond))
+		assert.Empty(t, out.String())
+	})
+
+	t.Run("live session reconnects at once", func(t *testing.T) {
+		var out bytes.Buffer
+		calls := 0
+		assert.True(t, awaitReattach(context.Background(), probeSequence(&calls, attachLive), &out, "sb", dropped, time.Second, time.Millisecond))
+		assert.Equal(t, 1, calls)
+		assert.Contains(t, out.String(), "lost the connection to sandbox sb (connection reset)")
+		assert.Contains(t, out.String(), "reconnected")
+	})
+
+	t.Run("waits out an unreachable runtime", func(t *testing.T) {
+		var out bytes.Buffer
+		calls := 0
+		assert.True(t, awaitReattach(context.Background(), probeSequence(&calls, attachUnknown, attachUnknown, attachLive), &out, "sb", dropped, time.Second, time.Millisecond))
+		assert.Equal(t, 3, calls)
+	})
+
+	t.Run("sandbox stops while waiting", func(t *testing.T) {
+		var out bytes.Buffer
+		calls := 0
+		assert.False(t, awaitReattach(context.Background(), probeSequence(&calls, attachUnknown, attachGone), &out, "sb", dropped, time.Second, time.Millisecond))
+		assert.Contains(t, out.String(), "yoloai attach sb")
+	})
+
+	t.Run("gives up after the timeout", func(t *testing.T) {
+		var out bytes.Buffer
+		calls := 0
+		assert.False(t, awaitReattach(context.Background(), probeSequence(&calls, attachUnknown), &out, "sb", dropped, 20*time.Millisecond, time.Millisecond))
+		assert.Contains(t, out.String(), "giving up")
+	})
+}
@@ -0,0 +1,152 @@
+// ABOUTME: Changelog fragments written at apply: a towncrier or keep-a-changelog
+// ABOUTME: entry for the agent's change, in the fragments directory the project names.
+
+// Package changelog writes the changelog fragment `yoloai apply --changelog`
+// leaves in the repository the changes landed in. Where fragments go comes
+// from the project's .yoloai.yaml (the changelog key) or, without one, from
+// the repository's towncrier configuration.
+package changelog
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	toml "github.com/pelletier/go-toml/v2"
+
+	"github.com/kstenerud/yoloai/internal/fileutil"
+)
+
+// Fragment formats.
+const (
+	// FormatTowncrier writes "+<name>.<type>.md" holding the entry, the
+	// orphan-fragment form towncrier collects into its next release notes.
+	FormatTowncrier = "towncrier"
+	// FormatKeepAChangelog writes "<timestamp>_<name>.md" holding the entry
+	// under a "### <Type>" heading, the fragment form scriv and similar
+	// tools merge into a Keep a Changelog file.
+	FormatKeepAChangelog = "keep-a-changelog"
+)
+
+// Config says where a project's fragments go and what they look like.
+type Config struct {
+	// Dir is the fragments directory, relative to the repository root.
+	Dir string `yaml:"dir"`
+	// Format is FormatTowncrier (the default) or FormatKeepAChangelog.
+	Format string `yaml:"format,omitempty"`
+	// Type is the towncrier fragment type or Keep a Changelog section a
+	// fragment gets when the apply doesn't name one; "" = DefaultType.
+	Type string `yaml:"type,omitempty"`
+}
+
+// Validate checks that Dir stays inside the repository and Format is known.
+func (c *Config) Validate() error {
+	if c.Dir == "" {
+		return errors.New("changelog: dir is required")
+	}
+	if !filepath.IsLocal(c.Dir) {
+		return fmt.Errorf("changelog: dir %q must be a relative path inside the repository", c.Dir)
+	}
+	switch c.Format {
+	case "", FormatTowncrier, FormatKeepAChangelog:
+	default:
+		return fmt.Errorf("changelog: unknown format %q (use %s or %s)", c.Format, FormatTowncrier, FormatKeepAChangelog)
+	}
+	if strings.ContainsAny(c.Type, "/\\.\n") {
+		return fmt.Errorf("changelog: invalid type %q", c.Type)
+	}
+	return nil
+}
+
+// DefaultType is the fragment type used when neither the apply nor the
+// project names one: towncrier's "feature", or Keep a Changelog's "Changed".
+func DefaultType(format string) string {
+	if format == FormatKeepAChangelog {
+		return "Changed"
+	}
+	return "feature"
+}
+
+// Detect reads the towncrier configuration of the repository at repoDir
+// (towncrier.toml, else the [tool.towncrier] table of pyproject.toml) and
+// returns its fragments directory as a Config. It returns nil when there is
+// no towncrier configuration or it doesn't name a directory.
+func Detect(repoDir string) (*Config, error) {
+	for _, file := range []string{"towncrier.toml", "pyproject.toml"} {
+		data, err := os.ReadFile(filepath.Join(repoDir, file)) //nolint:gosec // G304: repoDir + fixed filename
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		var doc struct {
+			Tool struct {
+				Towncrier *struct {
+					Directory string `toml:"directory"`
+				} `toml:"towncrier"`
+			} `toml:"tool"`
+		}
+		if err := toml.Unmarshal(data, &doc); err != nil {
+			return nil, fmt.Errorf("parse %s: %w", file, err)
+		}
+		if tc := doc.Tool.Towncrier; tc != nil && tc.Directory != "" {
+			cfg := &Config{Dir: filepath.FromSlash(tc.Directory), Format: FormatTowncrier}
+			if err := cfg.Validate(); err != nil {
+				return nil, fmt.Errorf("%s: %w", file, err)
+			}
+			return cfg, nil
+		}
+	}
+	return nil, nil
+}
+
+// Write writes a fragment holding entry into the fragments directory of the
+// repository at repoDir, creating the directory if needed, and returns the
+// fragment's path. name identifies the change in the file name (the sandbox
+// name); kind is the fragment type, "" for the configured or default one. An
+// existing fragment is never overwritten: a taken name gets a numeric suffix.
+func (c *Config) Write(repoDir, name, entry, kind string, now time.Time) (string, error) {
+	if kind == "" {
+		kind = c.Type
+	}
+	if kind == "" {
+		kind = DefaultType(c.Format)
+	}
+	dir := filepath.Join(repoDir, c.Dir)
+	if err := fileutil.MkdirAll(dir, 0o750); err != nil {
+		return "", err
+	}
+	entry = strings.Join(strings.Fields(entry), " ")
+	for n := 1; ; n++ {
+		stem := name
+		if n > 1 {
+			stem = fmt.Sprintf("%s-%d", name, n)
+		}
+		var file, content string
+		if c.Format == FormatKeepAChangelog {
+			file = now.Format("20060102_150405") + "_" + stem + ".md"
+			content = fmt.Sprintf("### %s\n\n- %s\n", kind, entry)
+		} else {
+			file = "+" + stem + "." + kind + ".md"
+			content = entry + "\n"
+		}
+		path := filepath.Join(dir, file)
+		f, err := fileutil.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+		if errors.Is(err, fs.ErrExist) {
+			continue
+		}
+		if err != nil {
+			return "", err
+		}
+		if _, err := f.WriteString(content); err != nil {
+			_ = f.Close()
+			return "", err
+		}
+		return path, f.Close()
+	}
+}
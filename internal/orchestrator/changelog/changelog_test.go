@@ -0,0 +1,81 @@
+// ABOUTME: Tests for fragment writing in both formats, config validation, and
+// ABOUTME: reading the fragments directory from towncrier.toml / pyproject.toml.
+
+package changelog
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfigValidate(t *testing.T) {
+	require.NoError(t, (&Config{Dir: "changelog.d"}).Validate())
+	require.NoError(t, (&Config{Dir: "docs/changes", Format: FormatKeepAChangelog, Type: "Fixed"}).Validate())
+	assert.Error(t, (&Config{}).Validate(), "dir is required")
+	assert.Error(t, (&Config{Dir: "../elsewhere"}).Validate(), "dir must stay in the repository")
+	assert.Error(t, (&Config{Dir: "/abs"}).Validate())
+	assert.Error(t, (&Config{Dir: "c", Format: "markdown"}).Validate())
+	assert.Error(t, (&Config{Dir: "c", Type: "../x"}).Validate())
+}
+
+func TestWrite_Towncrier(t *testing.T) {
+	repo := t.TempDir()
+	cfg := &Config{Dir: "changes"}
+
+	path, err := cfg.Write(repo, "fix-login", "Fix the login\n  redirect loop", "", time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(repo, "changes", "+fix-login.feature.md"), path)
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "Fix the login redirect loop\n", string(data))
+
+	again, err := cfg.Write(repo, "fix-login", "Again", "", time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(repo, "changes", "+fix-login-2.feature.md"), again, "an existing fragment is kept")
+
+	typed, err := cfg.Write(repo, "fix-login", "Typed", "bugfix", time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(repo, "changes", "+fix-login.bugfix.md"), typed)
+}
+
+func TestWrite_KeepAChangelog(t *testing.T) {
+	repo := t.TempDir()
+	cfg := &Config{Dir: "changelog.d", Format: FormatKeepAChangelog, Type: "Fixed"}
+	now := time.Date(2026, 3, 4, 5, 6, 7, 0, time.UTC)
+
+	path, err := cfg.Write(repo, "box", "Fix the parser", "", now)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(repo, "changelog.d", "20260304_050607_box.md"), path)
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "### Fixed\n\n- Fix the parser\n", string(data))
+}
+
+func TestDetect(t *testing.T) {
+	repo := t.TempDir()
+	cfg, err := Detect(repo)
+	require.NoError(t, err)
+	assert.Nil(t, cfg, "no towncrier configuration")
+
+	require.NoError(t, os.WriteFile(filepath.Join(repo, "pyproject.toml"), []byte("[project]\nname = \"x\"\n"), 0o600))
+	cfg, err = Detect(repo)
+	require.NoError(t, err)
+	assert.Nil(t, cfg, "pyproject.toml without [tool.towncrier]")
+
+	require.NoError(t, os.WriteFile(filepath.Join(repo, "pyproject.toml"), []byte("[tool.towncrier]\ndirectory = \"newsfragments\"\n"), 0o600))
+	cfg, err = Detect(repo)
+	require.NoError(t, err)
+	require.NotNil(t, cfg)
+	assert.Equal(t, "newsfragments", cfg.Dir)
+	assert.Equal(t, FormatTowncrier, cfg.Format)
+
+	require.NoError(t, os.WriteFile(filepath.Join(repo, "towncrier.toml"), []byte("[tool.towncrier]\ndirectory = \"changes\"\n"), 0o600))
+	cfg, err = Detect(repo)
+	require.NoError(t, err)
+	assert.Equal(t, "changes", cfg.Dir, "towncrier.toml wins over pyproject.toml")
+}
@@ -6,8 +6,10 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"path/filepath"
 	"time"
 
+	"github.com/kstenerud/yoloai/internal/orchestrator/lifecycle"
 	"github.com/kstenerud/yoloai/internal/workspace"
 	"github.com/kstenerud/yoloai/store"
 )
@@ -21,8 +23,8 @@ type CloneOptions struct {
 }
 
 // Clone creates a new stopped sandbox by deep-copying an existing one's
-// state directory. The clone gets a fresh name and creation timestamp;
-// everything else (agent, model, profile, workdir, config, work copies,
+// state directory. The clone gets a fresh name and creation timestamp (and
+// the runtime config follows the name); everything else (agent, model, profile, workdir, config, work copies,
 // agent state, prompt) is preserved.
 func (e *Engine) Clone(ctx context.Context, opts CloneOptions) error {
 	// Clone's copy is disk-only, but it is a backend-bound verb by contract
@@ -66,14 +68,55 @@ func (e *Engine) Clone(ctx context.Context, opts CloneOptions) error {
 		return fmt.Errorf("load cloned meta: %w", err)
 	}
 
+	if meta.WorkRoot != "" {
+		if err := cloneWorkRoot(dstDir, meta.WorkRoot, opts.Source, opts.Dest); err != nil {
+			os.RemoveAll(dstDir) //nolint:errcheck,gosec // best-effort cleanup
+			return err
+		}
+	}
+
+	// The clone gets the source's TTL afresh, counted from its own creation.
+	now := time.Now()
+	if meta.ExpiresAt != nil {
+		expires := now.Add(meta.ExpiresAt.Sub(meta.CreatedAt))
+		meta.ExpiresAt = &expires
+	}
 	meta.Name = opts.Dest
-	meta.CreatedAt = time.Now()
+	meta.CreatedAt = now
 
 	if err := store.SaveEnvironment(dstDir, meta); err != nil {
 		os.RemoveAll(dstDir) //nolint:errcheck,gosec // best-effort cleanup
 		return fmt.Errorf("update cloned meta: %w", err)
 	}
+	if err := lifecycle.PatchConfigForClone(dstDir, srcDir, opts.Dest); err != nil {
+		os.RemoveAll(dstDir) //nolint:errcheck,gosec // best-effort cleanup
+		return fmt.Errorf("update cloned runtime config: %w", err)
+	}
 
 	e.logger.Info("cloned sandbox", "source", opts.Source, "dest", opts.Dest)
 	return nil
 }
+
+// cloneWorkRoot gives a clone of a --work-root sandbox its own work copies. The
+// directory copy carried the source's work/ symlink across verbatim, which would
+// leave both sandboxes editing one tree; replace it with a link to a fresh copy
+// under the same work root, keeping the clone on the disk the user chose.
+func cloneWorkRoot(dstDir, workRoot, source, dest string) error {
+	srcWork := store.WorkRootDir(workRoot, source)
+	dstWork := store.WorkRootDir(workRoot, dest)
+	if _, err := os.Stat(dstWork); err == nil {
+		return fmt.Errorf("work root %s already exists; remove it before cloning", dstWork)
+	}
+	if err := os.Remove(filepath.Join(dstDir, "work")); err != nil {
+		return fmt.Errorf("unlink cloned work root: %w", err)
+	}
+	if err := workspace.CopyDir(srcWork, dstWork); err != nil {
+		os.RemoveAll(dstWork) //nolint:errcheck,gosec // best-effort cleanup
+		return fmt.Errorf("copy work root: %w", err)
+	}
+	if err := os.Symlink(dstWork, filepath.Join(dstDir, "work")); err != nil {
+		os.RemoveAll(dstWork) //nolint:errcheck,gosec // best-effort cleanup
+		return fmt.Errorf("link cloned work root: %w", err)
+	}
+	return nil
+}
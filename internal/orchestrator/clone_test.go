@@ -1,10 +1,11 @@
 // ABOUTME: Tests Engine.Clone: success (meta/agent-config copy, refreshed
-// ABOUTME: CreatedAt), destination-name validation, missing-source and
-// ABOUTME: already-exists errors, and cleanup on a corrupt cloned meta.
+// ABOUTME: CreatedAt, retargeted runtime config), destination-name validation,
+// ABOUTME: missing-source and already-exists errors, and cleanup on a corrupt cloned meta.
 package orchestrator
 
 import (
 	"context"
+	"encoding/json"
 	"log/slog"
 	"os"
 	"path/filepath"
@@ -18,6 +19,7 @@ import (
 	"github.com/kstenerud/yoloai/internal/agent"
 	"github.com/kstenerud/yoloai/internal/config"
 	"github.com/kstenerud/yoloai/internal/orchestrator/agentcfg"
+	"github.com/kstenerud/yoloai/internal/orchestrator/runtimeconfig"
 	"github.com/kstenerud/yoloai/store"
 	"github.com/kstenerud/yoloai/yoerrors"
 )
@@ -147,3 +149,49 @@ func TestClone_CleansUpOnMetaLoadFailure(t *testing.T) {
 	dstDir := filepath.Join(tmpDir, ".yoloai", "sandboxes", "baddst")
 	assert.NoDirExists(t, dstDir)
 }
+
+func TestClone_RestartsTTL(t *testing.T) {
+	tmpDir := t.TempDir()
+	createCloneSource(t, tmpDir, "source")
+	srcDir := filepath.Join(tmpDir, ".yoloai", "sandboxes", "source")
+	src, err := store.LoadEnvironment(srcDir)
+	require.NoError(t, err)
+	expires := src.CreatedAt.Add(2 * time.Hour) // one hour left
+	src.ExpiresAt = &expires
+	require.NoError(t, store.SaveEnvironment(srcDir, src))
+
+	require.NoError(t, newCloneMgr(tmpDir).Clone(context.Background(), CloneOptions{Source: "source", Dest: "dest"}))
+
+	meta, err := store.LoadEnvironment(filepath.Join(tmpDir, ".yoloai", "sandboxes", "dest"))
+	require.NoError(t, err)
+	require.NotNil(t, meta.ExpiresAt)
+	assert.Equal(t, 2*time.Hour, meta.ExpiresAt.Sub(meta.CreatedAt), "the clone gets the full TTL from its own creation")
+}
+
+func TestClone_RetargetsRuntimeConfig(t *testing.T) {
+	tmpDir := t.TempDir()
+	createCloneSource(t, tmpDir, "source")
+	srcDir := filepath.Join(tmpDir, ".yoloai", "sandboxes", "source")
+	rc := runtimeconfig.ContainerConfig{
+		SandboxName:      "source",
+		TmuxSocket:       filepath.Join(srcDir, "tmux", "tmux.sock"),
+		VscodeTunnel:     true,
+		VscodeTunnelName: "source",
+		AgentCommand:     "claude",
+	}
+	data, err := json.Marshal(rc)
+	require.NoError(t, err)
+	writeTestFile(t, srcDir, store.RuntimeConfigFile, string(data))
+
+	require.NoError(t, newCloneMgr(tmpDir).Clone(context.Background(), CloneOptions{Source: "source", Dest: "dest"}))
+
+	dstDir := filepath.Join(tmpDir, ".yoloai", "sandboxes", "dest")
+	data, err = os.ReadFile(filepath.Join(dstDir, store.RuntimeConfigFile)) //nolint:gosec // test path
+	require.NoError(t, err)
+	var got runtimeconfig.ContainerConfig
+	require.NoError(t, json.Unmarshal(data, &got))
+	assert.Equal(t, "dest", got.SandboxName)
+	assert.Equal(t, "dest", got.VscodeTunnelName)
+	assert.Equal(t, filepath.Join(dstDir, "tmux", "tmux.sock"), got.TmuxSocket, "a socket in the sandbox dir follows the clone")
+	assert.Equal(t, "claude", got.AgentCommand)
+}
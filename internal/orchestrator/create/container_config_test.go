@@ -26,7 +26,7 @@ func TestBuildContainerConfig_LaunchPrefixStored(t *testing.T) {
 	// constant is launch.AgentLaunchPrefix (no longer the runtime descriptor).
 	agentDef := agent.GetAgent("claude")
 	prefix := `PATH="/opt/homebrew/opt/node/bin:$PATH" `
-	data, err := buildContainerConfig(config.NewLayout(t.TempDir()), agentDef, "claude", prefix, "default", "/tmp", false, false, nil, nil, nil, 0, nil, "test", "", "", false, "", nil, false)
+	data, err := buildContainerConfig(config.NewLayout(t.TempDir()), agentDef, "claude", prefix, "default", "/tmp", false, false, nil, nil, nil, 0, nil, "test", "", "", false, "", nil, false, "", "", nil, nil, false)
 	require.NoError(t, err)
 	var cfg runtimeconfig.ContainerConfig
 	require.NoError(t, json.Unmarshal(data, &cfg))
@@ -36,7 +36,7 @@ func TestBuildContainerConfig_LaunchPrefixStored(t *testing.T) {
 func TestBuildContainerConfig_ValidJSON(t *testing.T) {
 	agentDef := agent.GetAgent("claude")
 	layout := config.NewLayout(t.TempDir())
-	data, err := buildContainerConfig(layout, agentDef, "claude --dangerously-skip-permissions", "", "default+host", "/Users/test/project", false, false, nil, nil, nil, 0, nil, "test", "", "", false, "", nil, false)
+	data, err := buildContainerConfig(layout, agentDef, "claude --dangerously-skip-permissions", "", "default+host", "/Users/test/project", false, false, nil, nil, nil, 0, nil, "test", "", "", false, "", nil, false, "", "", nil, nil, false)
 	require.NoError(t, err)
 
 	var cfg runtimeconfig.ContainerConfig
@@ -64,14 +64,14 @@ func TestBuildContainerConfig_Headless(t *testing.T) {
 	// fall-to-shell on.
 	agentDef := agent.GetAgent("claude")
 
-	headlessData, err := buildContainerConfig(config.NewLayout(t.TempDir()), agentDef, `claude -p "x"`, "", "default", "/tmp", false, false, nil, nil, nil, 0, nil, "test", "", "", false, "", nil, true)
+	headlessData, err := buildContainerConfig(config.NewLayout(t.TempDir()), agentDef, `claude -p "x"`, "", "default", "/tmp", false, false, nil, nil, nil, 0, nil, "test", "", "", false, "", nil, true, "", "", nil, nil, false)
 	require.NoError(t, err)
 	var headless runtimeconfig.ContainerConfig
 	require.NoError(t, json.Unmarshal(headlessData, &headless))
 	assert.True(t, headless.Headless)
 	assert.False(t, headless.FallToShell, "headless must not fall to shell")
 
-	interactiveData, err := buildContainerConfig(config.NewLayout(t.TempDir()), agentDef, "claude", "", "default", "/tmp", false, false, nil, nil, nil, 0, nil, "test", "", "", false, "", nil, false)
+	interactiveData, err := buildContainerConfig(config.NewLayout(t.TempDir()), agentDef, "claude", "", "default", "/tmp", false, false, nil, nil, nil, 0, nil, "test", "", "", false, "", nil, false, "", "", nil, nil, false)
 	require.NoError(t, err)
 	var interactive runtimeconfig.ContainerConfig
 	require.NoError(t, json.Unmarshal(interactiveData, &interactive))
@@ -155,7 +155,7 @@ func TestBuildContainerConfig_StateDirName(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.agent, func(t *testing.T) {
 			agentDef := agent.GetAgent(tt.agent)
-			data, err := buildContainerConfig(config.NewLayout(t.TempDir()), agentDef, "cmd", "", "default", "/tmp", false, false, nil, nil, nil, 0, nil, "test", "", "", false, "", nil, false)
+			data, err := buildContainerConfig(config.NewLayout(t.TempDir()), agentDef, "cmd", "", "default", "/tmp", false, false, nil, nil, nil, 0, nil, "test", "", "", false, "", nil, false, "", "", nil, nil, false)
 			require.NoError(t, err)
 			var cfg runtimeconfig.ContainerConfig
 			require.NoError(t, json.Unmarshal(data, &cfg))
@@ -167,7 +167,7 @@ func TestBuildContainerConfig_StateDirName(t *testing.T) {
 func TestBuildContainerConfig_NetworkIsolated(t *testing.T) {
 	agentDef := agent.GetAgent("claude")
 	domains := []string{"api.anthropic.com", "sentry.io"}
-	data, err := buildContainerConfig(config.NewLayout(t.TempDir()), agentDef, "claude", "", "default", "/tmp", false, true, domains, nil, nil, 0, nil, "test", "", "", false, "", nil, false)
+	data, err := buildContainerConfig(config.NewLayout(t.TempDir()), agentDef, "claude", "", "default", "/tmp", false, true, domains, nil, nil, 0, nil, "test", "", "", false, "", nil, false, "", "", nil, nil, false)
 	require.NoError(t, err)
 
 	var cfg runtimeconfig.ContainerConfig
@@ -180,7 +180,7 @@ func TestBuildContainerConfig_NetworkIsolated(t *testing.T) {
 func TestBuildContainerConfig_AutoCommitInterval(t *testing.T) {
 	agentDef := agent.GetAgent("claude")
 	copyDirs := []string{"/home/user/project", "/home/user/lib"}
-	data, err := buildContainerConfig(config.NewLayout(t.TempDir()), agentDef, "claude", "", "default", "/tmp", false, false, nil, nil, nil, 60, copyDirs, "test", "", "", false, "", nil, false)
+	data, err := buildContainerConfig(config.NewLayout(t.TempDir()), agentDef, "claude", "", "default", "/tmp", false, false, nil, nil, nil, 60, copyDirs, "test", "", "", false, "", nil, false, "", "", nil, nil, false)
 	require.NoError(t, err)
 
 	var cfg runtimeconfig.ContainerConfig
@@ -192,7 +192,7 @@ func TestBuildContainerConfig_AutoCommitInterval(t *testing.T) {
 
 func TestBuildContainerConfig_AutoCommitIntervalZero(t *testing.T) {
 	agentDef := agent.GetAgent("claude")
-	data, err := buildContainerConfig(config.NewLayout(t.TempDir()), agentDef, "claude", "", "default", "/tmp", false, false, nil, nil, nil, 0, nil, "test", "", "", false, "", nil, false)
+	data, err := buildContainerConfig(config.NewLayout(t.TempDir()), agentDef, "claude", "", "default", "/tmp", false, false, nil, nil, nil, 0, nil, "test", "", "", false, "", nil, false, "", "", nil, nil, false)
 	require.NoError(t, err)
 
 	var cfg runtimeconfig.ContainerConfig
@@ -233,7 +233,7 @@ func TestGitBaseline_EmptyGitRepo(t *testing.T) {
 	sandboxDir := filepath.Join(t.TempDir(), "test-sandbox")
 	workdir := &DirSpec{Path: dir, Mode: DirMode("copy")}
 	rt := &mockDockerRuntime{} // Docker-like backend: creates baseline on host
-	_, sha, err := setupWorkdir(context.Background(), git.NewTestHostWithEnv(testutil.GitEnv()), sandboxDir, workdir, rt)
+	_, sha, err := setupWorkdir(context.Background(), git.NewTestHostWithEnv(testutil.GitEnv()), sandboxDir, "", workdir, rt)
 	require.NoError(t, err)
 	assert.Len(t, sha, 40)
 }
@@ -292,3 +292,24 @@ func TestRemoveGitDirs_NoGit(t *testing.T) {
 	require.NoError(t, workspace.RemoveGitDirs(dir))
 	assert.FileExists(t, filepath.Join(dir, "file.txt"))
 }
+
+func TestBuildContainerConfig_PreLaunch(t *testing.T) {
+	agentDef := agent.GetAgent("claude")
+	data, err := buildContainerConfig(config.NewLayout(t.TempDir()), agentDef, "claude", "", "default", "/tmp", false, false, nil, nil, nil, 0, nil, "test", "", "", false, "", nil, false, ". /etc/corp/proxy.sh", "", nil, nil, false)
+	require.NoError(t, err)
+
+	var cfg runtimeconfig.ContainerConfig
+	require.NoError(t, json.Unmarshal(data, &cfg))
+	assert.Equal(t, ". /etc/corp/proxy.sh", cfg.PreLaunch)
+}
+
+func TestBuildContainerConfig_Roles(t *testing.T) {
+	agentDef := agent.GetAgent("claude")
+	roles := []runtimeconfig.Role{{Name: "implementer", Prompt: "build it"}, {Name: "tester", Prompt: "test it"}}
+	data, err := buildContainerConfig(config.NewLayout(t.TempDir()), agentDef, "claude", "", "default", "/tmp", false, false, nil, nil, nil, 0, nil, "test", "", "", false, "", nil, false, "", "", roles, nil, false)
+	require.NoError(t, err)
+
+	var cfg runtimeconfig.ContainerConfig
+	require.NoError(t, json.Unmarshal(data, &cfg))
+	assert.Equal(t, roles, cfg.Roles)
+}
@@ -11,6 +11,7 @@ import (
 	"io/fs"
 	"log/slog"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"slices"
 	"strings"
@@ -25,11 +26,14 @@ import (
 	"github.com/kstenerud/yoloai/internal/orchestrator/agentcfg"
 	"github.com/kstenerud/yoloai/internal/orchestrator/archetype"
 	"github.com/kstenerud/yoloai/internal/orchestrator/envspec"
+	"github.com/kstenerud/yoloai/internal/orchestrator/hooks"
 	"github.com/kstenerud/yoloai/internal/orchestrator/invocation"
 	"github.com/kstenerud/yoloai/internal/orchestrator/launch"
 	"github.com/kstenerud/yoloai/internal/orchestrator/runtimeconfig"
 	"github.com/kstenerud/yoloai/internal/orchestrator/state"
 	"github.com/kstenerud/yoloai/internal/orchestrator/workprobe"
+	"github.com/kstenerud/yoloai/internal/sysexec"
+	"github.com/kstenerud/yoloai/internal/timing"
 	"github.com/kstenerud/yoloai/runtime"
 	"github.com/kstenerud/yoloai/store"
 	"github.com/kstenerud/yoloai/yoerrors"
@@ -77,6 +81,18 @@ const (
 	DirModeRO   = store.DirModeRO
 )
 
+// ContextMode is re-exported from store, where the persisted Environment
+// records it.
+type ContextMode = store.ContextMode
+
+// Re-exported ContextMode constants. Canonical definitions in
+// store/contextmode.go.
+const (
+	ContextModeAgent    = store.ContextModeAgent
+	ContextModeWorktree = store.ContextModeWorktree
+	ContextModeNone     = store.ContextModeNone
+)
+
 // DirSpec describes a directory to mount in the sandbox. The canonical
 // definition lives in the state leaf package (so create/mounts/lifecycle can
 // share it without importing this façade); aliased here to keep the public
@@ -91,6 +107,8 @@ type Options struct {
 	Agent                string                // agent name (e.g., "claude", "test")
 	Model                string                // model name or alias (e.g., "sonnet", "claude-sonnet-4-latest")
 	Profile              string                // profile name (from --profile flag)
+	NoProfile            bool                  // --no-profile flag: ignore a profile named by the project's .yoloai.yaml
+	Image                string                // --image flag: start from an image saved by `yoloai commit` instead of the base image
 	Prompt               string                // prompt text (from --prompt)
 	PromptFile           string                // prompt file path (from --prompt-file)
 	Headless             bool                  // launch the agent in its own headless mode (yoloai run); requires a prompt (D100)
@@ -109,6 +127,16 @@ type Options struct {
 	Runtimes             []string              // --runtime flags (Apple simulator runtimes, e.g., ["ios", "tvos:26.1"])
 	VscodeTunnel         bool                  // --vscode-tunnel flag
 	Archetype            string                // --archetype flag (empty = auto-detect)
+	WorkRoot             string                // --work-root flag: absolute dir to hold this sandbox's work copies (empty = inside the sandbox dir)
+	TTL                  string                // --ttl flag: lifetime before gc may destroy it, e.g. "4h", "7d" (empty = ttl config; "0" = never)
+	FakeTime             string                // --faketime flag: libfaketime spec for the sandbox's clock (empty = faketime config; "none" = real time)
+	AgentWorkdir         string                // --agent-workdir flag: subdirectory of the workdir the agent starts in (empty = the workdir itself)
+	Roles                []runtimeconfig.Role  // --role flags: split-role session, one agent per role (empty = a single agent)
+	AllowPush            bool                  // --allow-push flag: let the agent's git push (default: pushes are rewritten to fail)
+	Context              ContextMode           // --context flag: where the sandbox context goes (default: the agent's instruction file)
+	Repo                 string                // --repo flag: "URL[@ref]" to clone as the workdir instead of a host directory (exclusive with Workdir)
+	RepoDepth            int                   // --depth flag: shallow-clone Repo to this many commits (0 = full history)
+	RepoSparse           []string              // --sparse flag: cone-mode sparse checkout of these directories of Repo (empty = the whole tree)
 
 	// Output receives the create pipeline's human-readable progress (profile
 	// image build stream, advisory warnings). Per-call so concurrent Creates on
@@ -154,6 +182,7 @@ func Run(ctx context.Context, d state.Deps, opts Options) (name string, err erro
 
 	backend := d.Runtime.Descriptor().Type
 	slog.Info("creating sandbox", "event", "sandbox.create", "sandbox", opts.Name, "agent", opts.Agent, "backend", backend)
+	timing.SetSandbox(ctx, opts.Name)
 	// Validate isolation prerequisites before the potentially expensive image build.
 	if opts.Isolation != "" {
 		if err := launch.CheckIsolationPrerequisites(ctx, d.Runtime, opts.Isolation); err != nil {
@@ -165,6 +194,7 @@ func Run(ctx context.Context, d state.Deps, opts Options) (name string, err erro
 	if err != nil {
 		return "", err
 	}
+	runPostCreateHooks(ctx, d, sandboxState, outputFor(opts.Output))
 
 	// Create provisions only — it does not launch the container. The caller
 	// starts the sandbox explicitly via Sandbox.Start, whose first-launch path
@@ -174,6 +204,30 @@ func Run(ctx context.Context, d state.Deps, opts Options) (name string, err erro
 	return sandboxState.Name, nil
 }
 
+// runPostCreateHooks runs the post-create hooks in the new sandbox's work copy
+// (the workdir itself when it isn't :copy). The sandbox is already complete, so a failing
+// hook is reported on output rather than failing the create.
+func runPostCreateHooks(ctx context.Context, d state.Deps, st *state.State, output io.Writer) {
+	sb := hooks.Sandbox{
+		Name:    st.Name,
+		Dir:     st.SandboxDir,
+		Profile: st.Profile,
+		Agent:   string(st.Agent.Type),
+		Model:   st.Model,
+		Backend: string(d.Runtime.Descriptor().Type),
+		Workdir: st.Workdir.Path,
+	}
+	dir := st.Workdir.Path
+	if st.Workdir.Mode == DirModeCopy {
+		// For :rw and :ro, WorkCopyDir is only a placeholder.
+		sb.WorkCopy = st.WorkCopyDir
+		dir = st.WorkCopyDir
+	}
+	if err := hooks.Run(ctx, d.Layout, hooks.PostCreate, st.Profile, dir, sb.Vars(), output); err != nil {
+		fmt.Fprintf(output, "Warning: %v\n", err) //nolint:errcheck // best-effort output
+	}
+}
+
 // checkUnappliedWork checks if the named sandbox has any unapplied work
 // (uncommitted changes or commits beyond the baseline). Returns an error if
 // work would be lost, or if a present-but-unreadable environment.json means
@@ -225,6 +279,13 @@ func unappliedWorkError(ctx context.Context, g *git.Git, name, workDir, baseline
 	return nil
 }
 
+// prevEnvironmentExists reports whether the sandbox dir still holds the
+// environment.json.prev that SaveEnvironment retains.
+func prevEnvironmentExists(sandboxDir string) bool {
+	_, err := os.Stat(filepath.Join(sandboxDir, store.EnvironmentPrevFile))
+	return err == nil
+}
+
 // prepareSandboxState handles validation, safety checks, directory
 // creation, workdir copy, git baseline, and meta/config writing.
 func prepareSandboxState(ctx context.Context, d state.Deps, opts Options) (*state.State, error) {
@@ -233,11 +294,67 @@ func prepareSandboxState(ctx context.Context, d state.Deps, opts Options) (*stat
 		return nil, err
 	}
 
+	// Cleanup on failure: the --repo checkout from here, the sandbox directory
+	// (and any out-of-tree work root) once Phase 2 creates it.
+	success := false
+	if opts.Repo != "" {
+		repoDir, err := cloneRepo(ctx, d.Layout, git.NewHost(d.Layout), opts.Name, opts.Repo, opts.RepoDepth, opts.RepoSparse)
+		if err != nil {
+			return nil, err
+		}
+		defer func() {
+			if !success {
+				_ = os.RemoveAll(repoDir)
+			}
+		}()
+		opts.Workdir = DirSpec{Path: repoDir, Mode: DirModeCopy, StripHistory: opts.Workdir.StripHistory}
+	}
+	if len(opts.Roles) > 0 {
+		// The first role runs in the main window, on the normal prompt path.
+		opts.Prompt = opts.Roles[0].Prompt
+	}
+
 	// Phase 1: Resolve profile, runtime base, archetype, and mounts.
 	ri, err := resolveProfileAndArchetype(ctx, d, &opts, agentDef, ycfg, gcfg)
 	if err != nil {
 		return nil, err
 	}
+	agentDef = ri.agentDef // a profile or .yoloai.yaml may have chosen the agent
+
+	ri.profile.fakeTime, err = resolveFakeTime(d.Runtime.Descriptor(), opts.FakeTime, ri.profile.fakeTime)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkPreLaunch(ctx, ri.profile.preLaunch); err != nil {
+		return nil, err
+	}
+	if ri.profile.resources != nil {
+		// Caught before the work copy, which may be large.
+		if _, err := config.ParseDiskSize(ri.profile.resources.Disk); err != nil {
+			return nil, err
+		}
+		if _, err := config.ParseAgentStateSize(ri.profile.resources.AgentState); err != nil {
+			return nil, err
+		}
+	}
+
+	// After profile resolution, which may supply the workdir.
+	if err := checkWorkdirQuota(d.Layout, opts, gcfg.MaxSandboxesPerWorkdir); err != nil {
+		return nil, err
+	}
+
+	// Before anything of the sandbox exists (or, with --replace, is torn down),
+	// so a failing pre-create hook leaves everything as it was.
+	preCreate := hooks.Sandbox{
+		Name:    opts.Name,
+		Profile: ri.profile.name,
+		Agent:   string(agentDef.Type),
+		Backend: string(d.Runtime.Descriptor().Type),
+		Workdir: opts.Workdir.Path,
+	}
+	if err := hooks.Run(ctx, d.Layout, hooks.PreCreate, ri.profile.name, opts.Workdir.Path, preCreate.Vars(), outputFor(opts.Output)); err != nil {
+		return nil, err
+	}
 
 	if err := replaceSandboxIfNeeded(ctx, d, opts, sandboxDir); err != nil {
 		return nil, err
@@ -247,19 +364,27 @@ func prepareSandboxState(ctx context.Context, d state.Deps, opts Options) (*stat
 	if err != nil {
 		return nil, err
 	}
+	opts.AgentWorkdir, err = resolveAgentWorkdir(workdir.Path, opts.AgentWorkdir)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkWorktreeContext(d, opts, workdir, agentDef); err != nil {
+		return nil, err
+	}
 
 	// Phase 2: Create directory structure and seed sandbox.
 	perms := store.Perms()
-	agentFilesInitialized, err := createAndSeedSandbox(ctx, d, sandboxDir, agentDef, ri.profile, perms, agentDirMountPaths(workdir, auxDirs), outputFor(opts.Output))
+	agentFilesInitialized, err := createAndSeedSandbox(ctx, d, sandboxDir, opts.WorkRoot, opts.Name, agentDef, ri.profile, perms, agentDirMountPaths(workdir, auxDirs), outputFor(opts.Output))
 	if err != nil {
 		return nil, err
 	}
 
-	// Cleanup sandbox directory on failure
-	success := false
 	defer func() {
 		if !success {
 			_ = os.RemoveAll(sandboxDir)
+			if opts.WorkRoot != "" {
+				_ = os.RemoveAll(store.WorkRootDir(opts.WorkRoot, opts.Name))
+			}
 		}
 	}()
 
@@ -267,6 +392,11 @@ func prepareSandboxState(ctx context.Context, d state.Deps, opts Options) (*stat
 	if err != nil {
 		return nil, err
 	}
+	// The work copies are the bulk of a sandbox, so this is the first point
+	// the resources.disk limit can be measured against; failure cleans up.
+	if err := launch.CheckDiskLimit(sandboxDir, opts.Name, ri.profile.resources); err != nil {
+		return nil, err
+	}
 
 	// Phase 3: Build config, meta, and state files.
 	configData, meta, model, tmuxConf, promptText, networkMode, networkAllow, err := buildConfigAndEnvironment(ctx, d, opts, ri, agentDef, workdir, auxDirs, gcfg, dirEnvs, baselineSHA, sandboxDir)
@@ -286,6 +416,7 @@ func prepareSandboxState(ctx context.Context, d state.Deps, opts Options) (*stat
 // devcontainer config, mounts, lifecycle state) threaded into the later config/meta/
 // state build phases, so those builders take one struct instead of long scalar lists.
 type resolvedCreateInputs struct {
+	agentDef        *agent.Definition
 	profile         *profileResult
 	archetype       archetype.Archetype
 	devcontainerCfg *archetype.DevcontainerConfig
@@ -297,18 +428,37 @@ type resolvedCreateInputs struct {
 
 // resolveProfileAndArchetype resolves profile config, runtime base, archetype, mounts, and lifecycle state.
 func resolveProfileAndArchetype(ctx context.Context, d state.Deps, opts *Options, agentDef *agent.Definition, ycfg *config.YoloaiConfig, gcfg *config.GlobalConfig) (*resolvedCreateInputs, error) {
+	project, err := loadProjectConfig(d, opts.Workdir.Path)
+	if err != nil {
+		return nil, err
+	}
+	if err := applyProjectSelection(opts, &agentDef, project, ycfg); err != nil {
+		return nil, err
+	}
+
 	pr, err := resolveProfileConfig(ctx, d, opts, &agentDef, ycfg, gcfg)
 	if err != nil {
 		return nil, err
 	}
+	if project == nil && opts.Workdir.Path != "" {
+		// The profile supplied the workdir; its profile/agent/model choice is
+		// made, but the rest of the project's defaults still apply.
+		if project, err = loadProjectConfig(d, opts.Workdir.Path); err != nil {
+			return nil, err
+		}
+	}
 
 	if err := resolveRuntimeBase(ctx, d, opts, pr); err != nil {
 		return nil, err
 	}
+	if err := resolveSavedImage(ctx, d, opts, pr); err != nil {
+		return nil, err
+	}
 
 	if err := applyConfigDefaults(opts, ycfg, pr); err != nil {
 		return nil, err
 	}
+	applyProjectDefaults(opts, pr, project)
 
 	resolvedArchetype, devcontainerCfg, dcMounts, dcMountWarnings, err := resolveAndApplyArchetype(ctx, d, opts, pr)
 	if err != nil {
@@ -326,6 +476,7 @@ func resolveProfileAndArchetype(ctx context.Context, d state.Deps, opts *Options
 	}
 
 	return &resolvedCreateInputs{
+		agentDef:        agentDef,
 		profile:         pr,
 		archetype:       resolvedArchetype,
 		devcontainerCfg: devcontainerCfg,
@@ -337,9 +488,9 @@ func resolveProfileAndArchetype(ctx context.Context, d state.Deps, opts *Options
 }
 
 // createAndSeedSandbox creates directory structure and seeds the sandbox with agent files.
-func createAndSeedSandbox(ctx context.Context, d state.Deps, sandboxDir string, agentDef *agent.Definition, pr *profileResult, perms store.IsolationPerms, trustPaths []string, output io.Writer) (bool, error) {
+func createAndSeedSandbox(ctx context.Context, d state.Deps, sandboxDir, workRoot, name string, agentDef *agent.Definition, pr *profileResult, perms store.IsolationPerms, trustPaths []string, output io.Writer) (bool, error) {
 	_ = ctx // reserved for future use
-	if err := createSandboxDirs(sandboxDir, perms); err != nil {
+	if err := createSandboxDirs(sandboxDir, workRoot, name, perms); err != nil {
 		return false, err
 	}
 	spec := envspec.BuildEnvSpec(agentDef)
@@ -363,7 +514,6 @@ func agentDirMountPaths(workdir *state.DirSpec, auxDirs []*state.DirSpec) []stri
 // substrate record no longer carries them (Q104/D90) — the caller needs them for
 // agent.json, netpolicy.json, and the launch state.
 func buildConfigAndEnvironment(ctx context.Context, d state.Deps, opts Options, ri *resolvedCreateInputs, agentDef *agent.Definition, workdir *DirSpec, auxDirs []*DirSpec, gcfg *config.GlobalConfig, dirEnvs []store.DirEnvironment, baselineSHA string, sandboxDir string) ([]byte, *store.Environment, string, string, string, string, []string, error) {
-	_ = ctx // reserved for future use
 	pr := ri.profile
 	promptText, hasPrompt, model, agentCommand, tmuxConf, headless, err := resolveAgentParams(agentDef, opts, pr, gcfg, d.Layout.HomeDir, d.Layout, d.Input)
 	if err != nil {
@@ -376,7 +526,7 @@ func buildConfigAndEnvironment(ctx context.Context, d state.Deps, opts Options,
 	lifecycleCfg := buildLifecycleConfig(ri.archetype, pr.archetypeDockerDRequired, ri.onCreateDone, ri.devcontainerCfg)
 
 	backend := d.Runtime.Descriptor().Type
-	configData, err := buildContainerConfig(d.Layout, agentDef, agentCommand, launch.AgentLaunchPrefix(backend), tmuxConf, launch.WorkdirMountPath(workdir), opts.Debug, networkMode == "isolated", networkAllow, opts.Passthrough, pr.setup, pr.autoCommitInterval, collectCopyDirs(workdir, auxDirs), opts.Name, runtime.TmuxSocketFor(d.Runtime, sandboxDir), pr.isolation, opts.VscodeTunnel, invocation.SanitizeTunnelName(opts.Name), lifecycleCfg, headless)
+	configData, err := buildContainerConfig(d.Layout, agentDef, agentCommand, launch.AgentLaunchPrefix(backend), tmuxConf, launch.WorkdirMountPath(workdir), opts.Debug, networkMode == "isolated", networkAllow, opts.Passthrough, pr.setup, pr.autoCommitInterval, collectCopyDirs(workdir, auxDirs), opts.Name, runtime.TmuxSocketFor(d.Runtime, sandboxDir), pr.isolation, opts.VscodeTunnel, invocation.SanitizeTunnelName(opts.Name), lifecycleCfg, headless, pr.preLaunch, opts.AgentWorkdir, opts.Roles, resolveGuard(pr.guard), opts.AllowPush)
 	if err != nil {
 		return nil, nil, "", "", "", "", nil, fmt.Errorf("build %s: %w", store.RuntimeConfigFile, err)
 	}
@@ -385,10 +535,119 @@ func buildConfigAndEnvironment(ctx context.Context, d state.Deps, opts Options,
 	meta := buildEnvironment(opts, pr, workdir, baselineSHA, dirEnvs, hasPrompt, usernsMode, d.Runtime.Descriptor().Capabilities.HostFilesystem, string(ri.archetype), backend, ri.mergedMounts)
 	meta.Principal = d.Layout.Principal // record the owning principal for attribution + runtime namespace (D62)
 	meta.Headless = headless            // effective headless mode (may be a D101 downgrade of opts.Headless)
+	meta.Timezone = resolveTimezone(pr.timezone, d.Layout.Env())
+	meta.Locale = resolveLocale(pr.locale, d.Layout.Env())
+	meta.ExpiresAt = expiresAt(meta.CreatedAt, opts.TTL, pr.ttl)
+	meta.FakeTime = pr.fakeTime
+	meta.AgentWorkdir = opts.AgentWorkdir
+	for _, r := range opts.Roles {
+		meta.Roles = append(meta.Roles, r.Name)
+	}
+	meta.AllowPush = opts.AllowPush
+	meta.Context = opts.Context
+	if opts.Context == ContextModeWorktree {
+		meta.ContextFile = worktreeContextFile(agentDef)
+	}
+	meta.RepoURL, meta.RepoRef = splitRepoRef(opts.Repo)
+	meta.RepoDepth, meta.RepoSparse = opts.RepoDepth, opts.RepoSparse
+	stampSourceIdentity(ctx, git.NewHost(d.Layout), meta.Dirs)
 
 	return configData, meta, model, tmuxConf, promptText, string(networkMode), networkAllow, nil
 }
 
+// expiresAt returns when a sandbox created at created expires: the --ttl flag
+// if given, else the configured ttl. nil when neither sets a positive TTL.
+// Both were validated before creation started.
+func expiresAt(created time.Time, flagTTL, configTTL string) *time.Time {
+	ttl := configTTL
+	if flagTTL != "" {
+		ttl = flagTTL
+	}
+	d, _ := config.ParseTTL(ttl)
+	if d <= 0 {
+		return nil
+	}
+	t := created.Add(d)
+	return &t
+}
+
+// resolveFakeTime returns the sandbox's faketime spec: --faketime if given,
+// else the configured one, with "none" meaning real time. libfaketime is
+// preloaded from the Linux base image, so a backend without it refuses a spec
+// rather than silently running on the real clock.
+func resolveFakeTime(desc runtime.BackendDescriptor, flagSpec, configured string) (string, error) {
+	spec := configured
+	if flagSpec != "" {
+		spec = flagSpec
+	}
+	if spec == "none" {
+		return "", nil
+	}
+	if spec != "" && !desc.Capabilities.FakeTime {
+		return "", yoerrors.NewUsageError("faketime needs a Linux container backend (docker, podman, containerd or apple), not %s", desc.Type)
+	}
+	return spec, nil
+}
+
+// resolveAgentWorkdir validates --agent-workdir against the workdir's host
+// path and returns it cleaned, slash-separated. It must name an existing
+// directory inside the workdir: the agent starts there, while the whole
+// workdir stays mounted and diffed.
+func resolveAgentWorkdir(workdirPath, rel string) (string, error) {
+	if rel == "" {
+		return "", nil
+	}
+	clean := filepath.Clean(rel)
+	if filepath.IsAbs(clean) || clean == ".." || strings.HasPrefix(clean, ".."+string(filepath.Separator)) {
+		return "", yoerrors.NewUsageError("--agent-workdir must be a path inside the workdir, relative to it: %s", rel)
+	}
+	if clean == "." {
+		return "", nil
+	}
+	info, err := os.Stat(filepath.Join(workdirPath, clean))
+	if err != nil || !info.IsDir() {
+		return "", yoerrors.NewUsageError("--agent-workdir %s is not a directory in %s", rel, workdirPath)
+	}
+	return filepath.ToSlash(clean), nil
+}
+
+// checkPreLaunch syntax-checks the pre_launch snippet with the host's bash, so
+// a typo fails `new` instead of surfacing in the sandbox log after launch. A
+// host without bash skips the check; the sandbox still reports a failing
+// snippet when it runs.
+func checkPreLaunch(ctx context.Context, snippet string) error {
+	if snippet == "" {
+		return nil
+	}
+	bash, err := exec.LookPath("bash")
+	if err != nil {
+		return nil //nolint:nilerr // no host bash: leave the check to the sandbox
+	}
+	out, err := sysexec.CommandContext(ctx, []string{}, bash, "-n", "-c", snippet).CombinedOutput()
+	if err != nil {
+		return yoerrors.NewUsageError("pre_launch: %s", strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// stampSourceIdentity records, for each copy-mode dir whose host source is a git
+// repo with history, the source's root commit and origin URL, so apply can later
+// tell whether HostPath still holds the same repository. Best-effort: a source
+// with no commits or no origin simply records less, and the apply-time check
+// skips what was not recorded.
+func stampSourceIdentity(ctx context.Context, g *git.Git, dirs []store.DirEnvironment) {
+	for i := range dirs {
+		d := &dirs[i]
+		if d.Mode != DirModeCopy || !git.IsGitRepo(d.HostPath) {
+			continue
+		}
+		if root, err := g.RootCommit(ctx, d.HostPath); err == nil {
+			d.SourceRootSHA = root
+		}
+		d.SourceRemote = g.RemoteURL(ctx, d.HostPath, "origin")
+	}
+}
+
 // buildSandboxStateResult constructs the State from all resolved values.
 // networkMode and networkAllow are passed explicitly because the substrate
 // record (meta) no longer carries them (D90); they live in netpolicy.json.
@@ -456,6 +715,14 @@ func validateAndLoadConfig(d state.Deps, opts Options) (*agent.Definition, strin
 		switch {
 		case metaErr == nil:
 			return nil, "", nil, nil, fmt.Errorf("sandbox %q already exists (use --replace to recreate): %w", opts.Name, ErrSandboxExists)
+		case errors.Is(metaErr, fs.ErrNotExist) && prevEnvironmentExists(sandboxDir):
+			// The record is gone but its previous copy is not — teardown removes
+			// .prev before the record, so this is lost metadata on a real
+			// sandbox, not an interrupted create or destroy. Never wipe it.
+			return nil, "", nil, nil, fmt.Errorf(
+				"sandbox %q exists but its metadata is missing — it was left untouched; "+
+					"run `yoloai system recover %s` to rebuild it, or pass --replace to discard and recreate it",
+				opts.Name, opts.Name)
 		case errors.Is(metaErr, fs.ErrNotExist):
 			// environment.json is absent: an earlier create was interrupted
 			// before it wrote the record. create copies the work tree (Phase 2)
@@ -473,8 +740,9 @@ func validateAndLoadConfig(d state.Deps, opts Options) (*agent.Definition, strin
 			// never be wiped — the old code did, on any load error at all.
 			return nil, "", nil, nil, fmt.Errorf(
 				"sandbox %q exists but its metadata cannot be read: %w — it was left untouched; "+
-					"run `yoloai system migrate` if it predates an upgrade, or pass --replace to discard and recreate it",
-				opts.Name, metaErr)
+					"run `yoloai system migrate` if it predates an upgrade, `yoloai system recover %s` if it is damaged, "+
+					"or pass --replace to discard and recreate it",
+				opts.Name, metaErr, opts.Name)
 		}
 	}
 
@@ -482,6 +750,43 @@ func validateAndLoadConfig(d state.Deps, opts Options) (*agent.Definition, strin
 		return nil, "", nil, nil, yoerrors.NewUsageError("--prompt and --prompt-file are mutually exclusive")
 	}
 
+	if err := validateRoles(opts); err != nil {
+		return nil, "", nil, nil, err
+	}
+
+	if opts.Repo != "" && opts.Workdir.Path != "" {
+		return nil, "", nil, nil, yoerrors.NewUsageError("--repo clones the workdir, so it can't be combined with a workdir argument")
+	}
+	if opts.Repo == "" && (opts.RepoDepth != 0 || len(opts.RepoSparse) > 0) {
+		return nil, "", nil, nil, yoerrors.NewUsageError("--depth and --sparse shape the --repo clone: use them with --repo")
+	}
+	if opts.RepoDepth < 0 {
+		return nil, "", nil, nil, yoerrors.NewUsageError("--depth must be a positive number of commits (0 = full history): %d", opts.RepoDepth)
+	}
+	for _, dir := range opts.RepoSparse {
+		if dir == "" || filepath.IsAbs(dir) || strings.HasPrefix(filepath.Clean(dir), "..") {
+			return nil, "", nil, nil, yoerrors.NewUsageError("--sparse takes directories inside the repository: %q", dir)
+		}
+	}
+
+	if opts.WorkRoot != "" && !filepath.IsAbs(opts.WorkRoot) {
+		return nil, "", nil, nil, yoerrors.NewUsageError("--work-root must be an absolute path: %s", opts.WorkRoot)
+	}
+
+	if _, err := config.ParseTTL(opts.TTL); err != nil {
+		return nil, "", nil, nil, fmt.Errorf("--ttl: %w", err)
+	}
+
+	if err := config.ValidateFakeTime(opts.FakeTime); err != nil {
+		return nil, "", nil, nil, fmt.Errorf("--faketime: %w", err)
+	}
+
+	switch opts.Context {
+	case ContextModeAgent, ContextModeWorktree, ContextModeNone:
+	default:
+		return nil, "", nil, nil, yoerrors.NewUsageError("--context must be agent, worktree or none: %q", opts.Context)
+	}
+
 	ycfg, err := config.LoadConfig(d.Layout)
 	if err != nil {
 		return nil, "", nil, nil, fmt.Errorf("load config: %w", err)
@@ -515,6 +820,40 @@ func resolveRuntimeBase(ctx context.Context, d state.Deps, opts *Options, pr *pr
 	return nil
 }
 
+// resolveSavedImage swaps the base image for the one --image names, saved
+// from another sandbox by `yoloai commit`. A profile or --runtime picks its
+// own image, so either one alongside --image is refused rather than silently
+// losing to it. Dispatches via the ImageCommitter optional interface: only a
+// backend that can save images can start from one.
+func resolveSavedImage(ctx context.Context, d state.Deps, opts *Options, pr *profileResult) error {
+	if opts.Image == "" {
+		return nil
+	}
+	if err := config.ValidateImageRef(opts.Image); err != nil {
+		return err
+	}
+	if pr.name != "" {
+		return yoerrors.NewUsageError("--image can't be combined with profile %q, which picks its own image (--no-profile skips a project's profile)", pr.name)
+	}
+	if len(opts.Runtimes) > 0 {
+		return yoerrors.NewUsageError("--image can't be combined with --runtime")
+	}
+	ic, ok := d.Runtime.(runtime.ImageCommitter)
+	if !ok {
+		return yoerrors.NewUsageError("--image is only supported on backends that can save sandbox images (currently: docker, podman)")
+	}
+	exists, err := ic.HasImage(ctx, opts.Image)
+	if err != nil {
+		return fmt.Errorf("check image %s: %w", opts.Image, err)
+	}
+	if !exists {
+		return yoerrors.NewUsageError("image %s not found — save one with 'yoloai commit <sandbox> --image %s'", opts.Image, opts.Image)
+	}
+	_, _ = fmt.Fprintf(outputFor(opts.Output), "Using image %s\n", opts.Image)
+	pr.imageRef = opts.Image
+	return nil
+}
+
 // mergeDcMounts merges devcontainer mounts into pr.mounts (dedup).
 func mergeDcMounts(pr *profileResult, dcMounts []string) {
 	seen := make(map[string]bool)
@@ -558,8 +897,11 @@ func replaceSandboxIfNeeded(ctx context.Context, d state.Deps, opts Options, san
 	return nil
 }
 
-// createSandboxDirs creates the directory structure for a new sandbox.
-func createSandboxDirs(sandboxDir string, perms store.IsolationPerms) error {
+// createSandboxDirs creates the directory structure for a new sandbox. With a
+// workRoot, work/ is a symlink to the sandbox's directory under it rather than
+// a real directory, so the work copies land on the chosen disk while every
+// store.WorkDir path stays unchanged.
+func createSandboxDirs(sandboxDir, workRoot, name string, perms store.IsolationPerms) error {
 	for _, dir := range []string{
 		sandboxDir,
 		filepath.Join(sandboxDir, "home-seed"),
@@ -571,12 +913,22 @@ func createSandboxDirs(sandboxDir string, perms store.IsolationPerms) error {
 			return fmt.Errorf("create directory %s: %w", dir, err)
 		}
 	}
-	for _, dir := range []string{
-		filepath.Join(sandboxDir, "work"),
+	if workRoot != "" {
+		if err := store.LinkWorkRoot(sandboxDir, workRoot, name, perms.Dir); err != nil {
+			return err
+		}
+	}
+	dirs := []string{
 		filepath.Join(sandboxDir, store.AgentRuntimeDir),
 		filepath.Join(sandboxDir, "files"),
 		filepath.Join(sandboxDir, "cache"),
-	} {
+		store.InboxDir(sandboxDir),
+		store.OutboxDir(sandboxDir),
+	}
+	if workRoot == "" {
+		dirs = append(dirs, filepath.Join(sandboxDir, "work"))
+	}
+	for _, dir := range dirs {
 		if err := fileutil.MkdirAllPerm(dir, perms.Dir); err != nil {
 			return fmt.Errorf("create directory %s: %w", dir, err)
 		}
@@ -588,7 +940,7 @@ func createSandboxDirs(sandboxDir string, perms store.IsolationPerms) error {
 func setupAllWorkdirs(ctx context.Context, d state.Deps, opts Options, workdir *DirSpec, auxDirs []*DirSpec, resolvedArchetype archetype.Archetype, devcontainerCfg *archetype.DevcontainerConfig) (string, string, []store.DirEnvironment, error) {
 	slog.Debug("setting up workdir", "event", "sandbox.create.workdir", "mode", string(workdir.Mode))
 	sandboxDir := d.Layout.SandboxDir(opts.Name)
-	workCopyDir, baselineSHA, err := setupWorkdir(ctx, git.NewHost(d.Layout), sandboxDir, workdir, d.Runtime)
+	workCopyDir, baselineSHA, err := setupWorkdir(ctx, git.NewHost(d.Layout), sandboxDir, d.Layout.ObjectsDir(), workdir, d.Runtime)
 	if err != nil {
 		return "", "", nil, err
 	}
@@ -613,11 +965,25 @@ func setupAllWorkdirs(ctx context.Context, d state.Deps, opts Options, workdir *
 	}
 
 	slog.Debug("setting up aux dirs", "event", "sandbox.create.aux_dirs", "count", len(auxDirs))
-	dirEnvs, err := setupAuxDirs(ctx, git.NewHost(d.Layout), sandboxDir, d.Runtime, auxDirs)
+	dirEnvs, err := setupAuxDirs(ctx, git.NewHost(d.Layout), sandboxDir, d.Layout.ObjectsDir(), d.Runtime, auxDirs)
 	if err != nil {
 		return "", "", nil, err
 	}
 
+	// The copies' remotes are the host's, push URLs included; unless
+	// --allow-push, point them nowhere. Reset and rebase redo this as they
+	// re-copy (workcopy.Spec.BlockPush).
+	if !opts.AllowPush {
+		for _, dir := range append([]*DirSpec{workdir}, auxDirs...) {
+			if dir.Mode != DirModeCopy || dir.StripHistory {
+				continue
+			}
+			if err := git.NewHost(d.Layout).BlockPush(ctx, store.WorkDir(sandboxDir, dir.Path)); err != nil {
+				return "", "", nil, fmt.Errorf("block pushes from %s: %w", dir.Path, err)
+			}
+		}
+	}
+
 	return workCopyDir, baselineSHA, dirEnvs, nil
 }
 
@@ -738,12 +1104,14 @@ func buildEnvironment(opts Options, pr *profileResult, workdir *DirSpec, baselin
 		Devices:            pr.devices,
 		Setup:              pr.setup,
 		AutoCommitInterval: pr.autoCommitInterval,
+		ProvenanceHeaders:  pr.provenanceHeaders,
 		Debug:              opts.Debug,
 		UsernsMode:         usernsMode,
 		Isolation:          pr.isolation,
 		HostFilesystem:     hostFilesystem,
 		VscodeTunnel:       opts.VscodeTunnel,
 		Archetype:          archetypeStr,
+		WorkRoot:           opts.WorkRoot,
 	}
 }
 
@@ -752,6 +1120,16 @@ func buildEnvironment(opts Options, pr *profileResult, workdir *DirSpec, baselin
 // networkMode and networkAllow are passed explicitly because meta no longer
 // carries them (D90); they go to netpolicy.json. agentType/model go to agent.json.
 func writeStatFiles(sandboxDir string, meta *store.Environment, agentDef *agent.Definition, agentType, model string, networkMode string, networkAllow []string, agentFilesInitialized bool, hasPrompt bool, promptText string, configData []byte, perms store.IsolationPerms) error {
+	// logs/ first: SaveEnvironment publishes the baselines there.
+	if err := fileutil.MkdirAllPerm(filepath.Join(sandboxDir, store.LogsDir), perms.Dir); err != nil {
+		return fmt.Errorf("create logs dir: %w", err)
+	}
+	for _, logFile := range []string{store.SandboxJSONLFile, store.MonitorJSONLFile, store.HooksJSONLFile} {
+		p := filepath.Join(sandboxDir, logFile)
+		if err := fileutil.WriteFilePerm(p, nil, perms.File); err != nil {
+			return fmt.Errorf("create log file %s: %w", logFile, err)
+		}
+	}
 	if err := store.SaveEnvironment(sandboxDir, meta); err != nil {
 		return err
 	}
@@ -778,16 +1156,6 @@ func writeStatFiles(sandboxDir string, meta *store.Environment, agentDef *agent.
 	}
 
 	configPerm := os.FileMode(0644) // always 0644 (no secrets, read-only in container)
-
-	if err := fileutil.MkdirAllPerm(filepath.Join(sandboxDir, store.LogsDir), perms.Dir); err != nil {
-		return fmt.Errorf("create logs dir: %w", err)
-	}
-	for _, logFile := range []string{store.SandboxJSONLFile, store.MonitorJSONLFile, store.HooksJSONLFile} {
-		p := filepath.Join(sandboxDir, logFile)
-		if err := fileutil.WriteFilePerm(p, nil, perms.File); err != nil {
-			return fmt.Errorf("create log file %s: %w", logFile, err)
-		}
-	}
 	if err := fileutil.WriteFilePerm(filepath.Join(sandboxDir, store.AgentStatusFile), []byte("{}\n"), perms.File); err != nil {
 		return fmt.Errorf("write %s: %w", store.AgentStatusFile, err)
 	}
@@ -797,6 +1165,9 @@ func writeStatFiles(sandboxDir string, meta *store.Environment, agentDef *agent.
 	if err := envsetup.WriteContextFiles(sandboxDir, meta, envspec.BuildEnvSpec(agentDef)); err != nil {
 		return fmt.Errorf("write context files: %w", err)
 	}
+	if err := envsetup.WriteWorktreeContext(sandboxDir, meta); err != nil {
+		return fmt.Errorf("write context into the work copy: %w", err)
+	}
 	return nil
 }
 
@@ -804,7 +1175,7 @@ func writeStatFiles(sandboxDir string, meta *store.Environment, agentDef *agent.
 // agentLaunchPrefix is the backend's constant launch wrap (launch.AgentLaunchPrefix;
 // e.g. a 'PATH=...' prefix for Tart), computed once by the caller and stored here as the
 // single source of truth for the agent-command wrap (W1a of the architecture remediation plan).
-func buildContainerConfig(layout config.Layout, agentDef *agent.Definition, agentCommand string, agentLaunchPrefix string, tmuxConf string, workingDir string, debug bool, networkIsolated bool, allowedDomains []string, passthrough []string, setupCommands []string, autoCommitInterval int, copyDirs []string, sandboxName string, tmuxSocket string, isolation runtime.IsolationMode, vscodeTunnel bool, vscodeTunnelName string, lifecycle *runtimeconfig.LifecycleConfig, headless bool) ([]byte, error) {
+func buildContainerConfig(layout config.Layout, agentDef *agent.Definition, agentCommand string, agentLaunchPrefix string, tmuxConf string, workingDir string, debug bool, networkIsolated bool, allowedDomains []string, passthrough []string, setupCommands []string, autoCommitInterval int, copyDirs []string, sandboxName string, tmuxSocket string, isolation runtime.IsolationMode, vscodeTunnel bool, vscodeTunnelName string, lifecycle *runtimeconfig.LifecycleConfig, headless bool, preLaunch string, agentWorkdir string, roles []runtimeconfig.Role, guard *runtimeconfig.Guard, allowPush bool) ([]byte, error) {
 	var stateDirName string
 	if agentDef.StateDir != "" {
 		stateDirName = filepath.Base(agentDef.StateDir)
@@ -822,12 +1193,14 @@ func buildContainerConfig(layout config.Layout, agentDef *agent.Definition, agen
 		SubmitSequence:     agentDef.SubmitSequence,
 		TmuxConf:           tmuxConf,
 		WorkingDir:         workingDir,
+		AgentWorkdir:       agentWorkdir,
 		StateDirName:       stateDirName,
 		Debug:              debug,
 		NetworkIsolated:    networkIsolated,
 		AllowedDomains:     allowedDomains,
 		Passthrough:        passthrough,
 		SetupCommands:      setupCommands,
+		PreLaunch:          preLaunch,
 		AutoCommitInterval: autoCommitInterval,
 		CopyDirs:           copyDirs,
 		HookIdle:           agentDef.Idle.Hook,
@@ -847,6 +1220,9 @@ func buildContainerConfig(layout config.Layout, agentDef *agent.Definition, agen
 		VscodeTunnel:     vscodeTunnel,
 		VscodeTunnelName: vscodeTunnelName,
 		Lifecycle:        lifecycle,
+		Roles:            roles,
+		Guard:            guard,
+		AllowPush:        allowPush,
 	}
 	return json.MarshalIndent(cfg, "", "  ")
 }
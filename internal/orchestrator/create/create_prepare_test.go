@@ -9,9 +9,11 @@ import (
 	"io"
 	"log/slog"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/kstenerud/yoloai/internal/agent"
 	"github.com/kstenerud/yoloai/internal/config"
@@ -124,7 +126,7 @@ func TestSetupAuxDirs_GuestMountTranslation(t *testing.T) {
 		{Path: "/Users/karl/lib", Mode: DirModeRW},
 	}
 
-	dirEnvs, err := setupAuxDirs(context.Background(), git.NewTestHostWithEnv(testutil.GitEnv()), t.TempDir(), &fakeGuestMountRuntime{}, auxDirs)
+	dirEnvs, err := setupAuxDirs(context.Background(), git.NewTestHostWithEnv(testutil.GitEnv()), t.TempDir(), "", &fakeGuestMountRuntime{}, auxDirs)
 	require.NoError(t, err)
 	require.Len(t, dirEnvs, 2)
 
@@ -138,7 +140,7 @@ func TestSetupAuxDirs_GuestMountTranslation(t *testing.T) {
 func TestSetupAuxDirs_NoTranslationIsIdentity(t *testing.T) {
 	auxDirs := []*DirSpec{{Path: "/Users/karl/work/embrace", Mode: "ro"}}
 
-	dirEnvs, err := setupAuxDirs(context.Background(), git.NewTestHostWithEnv(testutil.GitEnv()), t.TempDir(), &fakeRuntime{}, auxDirs)
+	dirEnvs, err := setupAuxDirs(context.Background(), git.NewTestHostWithEnv(testutil.GitEnv()), t.TempDir(), "", &fakeRuntime{}, auxDirs)
 	require.NoError(t, err)
 	require.Len(t, dirEnvs, 1)
 	assert.Equal(t, dirEnvs[0].HostPath, dirEnvs[0].MountPath)
@@ -162,7 +164,7 @@ func TestSetupAuxDir_CopyMode_CreatesBaselineAndCopy(t *testing.T) {
 	rt := &mockDockerRuntime{}
 	g := git.NewTestHostWithEnv(testutil.GitEnv())
 
-	dirEnvs, err := setupAuxDirs(context.Background(), g, sandboxDir, rt, []*DirSpec{auxCopy})
+	dirEnvs, err := setupAuxDirs(context.Background(), g, sandboxDir, "", rt, []*DirSpec{auxCopy})
 	require.NoError(t, err)
 	require.Len(t, dirEnvs, 1)
 
@@ -507,7 +509,7 @@ func TestSetupWorkdir_DefersBaselineForWorkDirSetupBackends(t *testing.T) {
 	rt := &mockTartRuntime{}
 
 	// setupWorkdir should return empty SHA for WorkDirSetup backends
-	_, baselineSHA, err := setupWorkdir(context.Background(), git.NewTestHostWithEnv(testutil.GitEnv()), sandboxDir, workdir, rt)
+	_, baselineSHA, err := setupWorkdir(context.Background(), git.NewTestHostWithEnv(testutil.GitEnv()), sandboxDir, "", workdir, rt)
 	require.NoError(t, err)
 	assert.Empty(t, baselineSHA, "baseline SHA should be empty for WorkDirSetup backends (baseline deferred to VM)")
 }
@@ -533,7 +535,7 @@ func TestSetupWorkdir_CreatesBaselineForDockerBackends(t *testing.T) {
 	rt := &mockDockerRuntime{}
 
 	// setupWorkdir should create baseline and return non-empty SHA for Docker
-	_, baselineSHA, err := setupWorkdir(context.Background(), git.NewTestHostWithEnv(testutil.GitEnv()), sandboxDir, workdir, rt)
+	_, baselineSHA, err := setupWorkdir(context.Background(), git.NewTestHostWithEnv(testutil.GitEnv()), sandboxDir, "", workdir, rt)
 	require.NoError(t, err)
 	assert.NotEmpty(t, baselineSHA, "baseline SHA should be non-empty for Docker backends (immediate baseline)")
 	assert.Len(t, baselineSHA, 40, "SHA should be 40 characters (git SHA-1)")
@@ -931,3 +933,152 @@ func TestPrepareSandboxState_AbsentMetadataIsNotRefused(t *testing.T) {
 			"an absent record is an interrupted creation, not an unreadable sandbox")
 	}
 }
+
+func TestExpiresAt(t *testing.T) {
+	created := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	assert.Nil(t, expiresAt(created, "", ""), "no TTL anywhere: never expires")
+	require.NotNil(t, expiresAt(created, "", "7d"))
+	assert.Equal(t, created.Add(7*24*time.Hour), *expiresAt(created, "", "7d"), "config ttl")
+	assert.Equal(t, created.Add(4*time.Hour), *expiresAt(created, "4h", "7d"), "--ttl wins")
+	assert.Nil(t, expiresAt(created, "0", "7d"), "--ttl 0 opts out of the config ttl")
+}
+
+func TestResolveFakeTime(t *testing.T) {
+	docker := runtime.BackendDescriptor{Type: runtime.BackendDocker, Capabilities: runtime.BackendCaps{FakeTime: true}}
+	tart := runtime.BackendDescriptor{Type: runtime.BackendTart}
+
+	spec, err := resolveFakeTime(docker, "", "-3d")
+	require.NoError(t, err)
+	assert.Equal(t, "-3d", spec, "configured spec")
+
+	spec, err = resolveFakeTime(docker, "+2h", "-3d")
+	require.NoError(t, err)
+	assert.Equal(t, "+2h", spec, "--faketime wins")
+
+	spec, err = resolveFakeTime(tart, "none", "-3d")
+	require.NoError(t, err)
+	assert.Empty(t, spec, "none turns a configured spec off, on any backend")
+
+	_, err = resolveFakeTime(tart, "-3d", "")
+	require.Error(t, err, "no libfaketime outside the Linux image")
+	var ue *yoerrors.UsageError
+	assert.ErrorAs(t, err, &ue)
+}
+
+func TestCheckPreLaunch(t *testing.T) {
+	if _, err := exec.LookPath("bash"); err != nil {
+		t.Skip("bash not available")
+	}
+	ctx := context.Background()
+	require.NoError(t, checkPreLaunch(ctx, ""))
+	require.NoError(t, checkPreLaunch(ctx, ". /does/not/exist.sh\nexport A=1"), "only syntax is checked")
+
+	err := checkPreLaunch(ctx, "if true; then export A=1")
+	require.Error(t, err)
+	var ue *yoerrors.UsageError
+	assert.ErrorAs(t, err, &ue)
+	assert.Contains(t, err.Error(), "pre_launch")
+}
+
+func TestResolveAgentWorkdir(t *testing.T) {
+	wd := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(wd, "packages", "api"), 0o750))
+	require.NoError(t, os.WriteFile(filepath.Join(wd, "README"), nil, 0o600))
+
+	for in, want := range map[string]string{
+		"":                         "",
+		".":                        "",
+		"packages/api":             "packages/api",
+		"./packages/api/":          "packages/api",
+		"packages/../packages/api": "packages/api",
+	} {
+		got, err := resolveAgentWorkdir(wd, in)
+		require.NoError(t, err, in)
+		assert.Equal(t, want, got, in)
+	}
+	for _, bad := range []string{"/abs", "..", "../sibling", "packages/missing", "README"} {
+		_, err := resolveAgentWorkdir(wd, bad)
+		var ue *yoerrors.UsageError
+		assert.ErrorAs(t, err, &ue, bad)
+	}
+}
+
+func TestCheckWorktreeContext(t *testing.T) {
+	d := state.Deps{Runtime: &fakeRuntime{}}
+	claude := agent.GetAgent("claude")
+	dir := t.TempDir()
+
+	require.NoError(t, checkWorktreeContext(d, Options{}, &DirSpec{Path: dir, Mode: DirModeRW}, claude), "the default mode needs nothing")
+	require.NoError(t, checkWorktreeContext(d, Options{Context: ContextModeWorktree}, &DirSpec{Path: dir, Mode: DirModeCopy}, claude))
+
+	var usage *yoerrors.UsageError
+	err := checkWorktreeContext(d, Options{Context: ContextModeWorktree}, &DirSpec{Path: dir, Mode: DirModeRW}, claude)
+	require.ErrorAs(t, err, &usage, "never write into a live directory")
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "CLAUDE.md"), []byte("mine"), 0600))
+	err = checkWorktreeContext(d, Options{Context: ContextModeWorktree}, &DirSpec{Path: dir, Mode: DirModeCopy}, claude)
+	require.ErrorAs(t, err, &usage, "don't shadow the project's own file")
+}
+
+func TestPrepareSandboxState_RWWorkdirStaysUntouched(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Setenv("ANTHROPIC_API_KEY", "sk-test")
+
+	workDir := filepath.Join(tmpDir, "project")
+	require.NoError(t, os.MkdirAll(workDir, 0750))
+	require.NoError(t, os.WriteFile(filepath.Join(workDir, "main.go"), []byte("package main\n"), 0600))
+
+	d := state.Deps{
+		Runtime: &fakeRuntime{},
+		Layout:  layoutForTmpDir(tmpDir),
+		Input:   strings.NewReader("y\n"),
+	}
+	_, err := prepareSandboxState(context.TODO(), d, Options{
+		Name:    "test",
+		Workdir: DirSpec{Path: workDir, Mode: DirModeRW},
+		Agent:   "claude",
+		Version: "test",
+	})
+	require.NoError(t, err)
+
+	entries, err := os.ReadDir(workDir)
+	require.NoError(t, err)
+	require.Len(t, entries, 1, "yoloai wrote into the :rw workdir")
+	assert.Equal(t, "main.go", entries[0].Name())
+}
+
+// fakeCommitterRuntime is a fakeRuntime that can save images and has the ones
+// in images.
+type fakeCommitterRuntime struct {
+	fakeRuntime
+	images map[string]bool
+}
+
+func (f *fakeCommitterRuntime) CommitImage(_ context.Context, _, _ string) error { return nil }
+func (f *fakeCommitterRuntime) HasImage(_ context.Context, ref string) (bool, error) {
+	return f.images[ref], nil
+}
+
+func TestResolveSavedImage(t *testing.T) {
+	rt := &fakeCommitterRuntime{images: map[string]bool{"my-env:v1": true}}
+	d := state.Deps{Runtime: rt, Layout: config.NewLayout(t.TempDir())}
+	var ue *yoerrors.UsageError
+
+	pr := &profileResult{imageRef: config.BaseImage}
+	require.NoError(t, resolveSavedImage(context.Background(), d, &Options{}, pr))
+	assert.Equal(t, config.BaseImage, pr.imageRef, "no --image keeps the base image")
+
+	require.NoError(t, resolveSavedImage(context.Background(), d, &Options{Image: "my-env:v1"}, pr))
+	assert.Equal(t, "my-env:v1", pr.imageRef)
+
+	err := resolveSavedImage(context.Background(), d, &Options{Image: "my-env:v2"}, &profileResult{})
+	require.ErrorAs(t, err, &ue)
+	assert.Contains(t, err.Error(), "yoloai commit")
+
+	err = resolveSavedImage(context.Background(), d, &Options{Image: "my-env:v1"}, &profileResult{name: "go-dev"})
+	require.ErrorAs(t, err, &ue, "a profile picks its own image")
+
+	noCommit := state.Deps{Runtime: &fakeRuntime{}, Layout: d.Layout}
+	require.ErrorAs(t, resolveSavedImage(context.Background(), noCommit, &Options{Image: "my-env:v1"}, &profileResult{}), &ue)
+}
@@ -0,0 +1,23 @@
+// ABOUTME: Resolves the guard config key into the runtime-config guard: the
+// ABOUTME: mode and rules the in-sandbox shims apply to destructive commands.
+
+package create
+
+import (
+	"github.com/kstenerud/yoloai/internal/config"
+	"github.com/kstenerud/yoloai/internal/orchestrator/runtimeconfig"
+)
+
+// resolveGuard turns the merged guard config into what the sandbox setup
+// installs. Off (or unset) yields nil — no shims; an empty rule list falls
+// back to config.DefaultGuardCommands.
+func resolveGuard(g *config.GuardConfig) *runtimeconfig.Guard {
+	if g == nil || g.Mode == "" || g.Mode == "off" {
+		return nil
+	}
+	commands := g.Commands
+	if len(commands) == 0 {
+		commands = config.DefaultGuardCommands
+	}
+	return &runtimeconfig.Guard{Mode: g.Mode, Commands: commands}
+}
@@ -0,0 +1,23 @@
+// ABOUTME: Tests for resolveGuard: off means no shims, and an empty rule list
+// ABOUTME: falls back to the built-in destructive-command rules.
+package create
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kstenerud/yoloai/internal/config"
+	"github.com/kstenerud/yoloai/internal/orchestrator/runtimeconfig"
+)
+
+func TestResolveGuard(t *testing.T) {
+	assert.Nil(t, resolveGuard(nil))
+	assert.Nil(t, resolveGuard(&config.GuardConfig{Mode: "off", Commands: []string{"rm -rf"}}))
+	assert.Nil(t, resolveGuard(&config.GuardConfig{}), "unset mode is off")
+
+	assert.Equal(t, &runtimeconfig.Guard{Mode: "log", Commands: config.DefaultGuardCommands},
+		resolveGuard(&config.GuardConfig{Mode: "log"}), "no rules: the defaults")
+	assert.Equal(t, &runtimeconfig.Guard{Mode: "block", Commands: []string{"terraform destroy"}},
+		resolveGuard(&config.GuardConfig{Mode: "block", Commands: []string{"terraform destroy"}}))
+}
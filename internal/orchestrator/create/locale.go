@@ -0,0 +1,78 @@
+// ABOUTME: Resolves the sandbox's timezone and locale at create time — the
+// ABOUTME: configured value, else the host's — for recording in environment.json.
+
+package create
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/kstenerud/yoloai/internal/config"
+)
+
+// hostLocaltime and hostTimezoneFile are where the host's zone is configured
+// when TZ is unset. Vars so tests can point them at fixtures.
+var (
+	hostLocaltime    = "/etc/localtime"
+	hostTimezoneFile = "/etc/timezone"
+)
+
+// resolveTimezone returns the TZ for a new sandbox: configured if set, else
+// the host's zone name ("" when it can't be determined, leaving the image's
+// UTC). A zone name rather than a path is required — the host's zoneinfo
+// path means nothing inside the container.
+func resolveTimezone(configured string, hostEnv config.HostEnv) string {
+	if configured != "" {
+		return configured
+	}
+	if tz, ok := hostEnv.Timezone(); ok {
+		// TZ may name a file (":/etc/localtime", "/usr/share/zoneinfo/X").
+		tz = strings.TrimPrefix(tz, ":")
+		if !filepath.IsAbs(tz) {
+			return tz
+		}
+		if zone := zoneFromPath(tz); zone != "" {
+			return zone
+		}
+	}
+	if zone := zoneFromPath(hostLocaltime); zone != "" {
+		return zone
+	}
+	// Debian-family hosts may copy the zone file instead of linking it.
+	if data, err := os.ReadFile(hostTimezoneFile); err == nil { //nolint:gosec // G304: fixed system path
+		return strings.TrimSpace(string(data))
+	}
+	return ""
+}
+
+// zoneFromPath returns the zone name a zoneinfo path (or a symlink to one)
+// refers to — "America/New_York" for
+// /usr/share/zoneinfo/America/New_York, or macOS's
+// /var/db/timezone/zoneinfo/America/New_York. "" if it isn't one.
+func zoneFromPath(path string) string {
+	if target, err := os.Readlink(path); err == nil {
+		path = target
+	}
+	_, zone, ok := strings.Cut(filepath.ToSlash(path), "zoneinfo/")
+	if !ok {
+		return ""
+	}
+	// posix/ and right/ are alternate trees of the same zones.
+	zone = strings.TrimPrefix(strings.TrimPrefix(zone, "posix/"), "right/")
+	return zone
+}
+
+// resolveLocale returns the LANG for a new sandbox: configured if set, else
+// the host's. The C/POSIX locale is dropped so the container keeps its
+// C.UTF-8 default — plain C would render agents' TUIs as ASCII.
+func resolveLocale(configured string, hostEnv config.HostEnv) string {
+	if configured != "" {
+		return configured
+	}
+	loc, ok := hostEnv.Locale()
+	if !ok || loc == "C" || loc == "POSIX" {
+		return ""
+	}
+	return loc
+}
@@ -0,0 +1,65 @@
+// ABOUTME: Tests for create-time timezone/locale resolution: config over host
+// ABOUTME: TZ/LANG, zone names from /etc/localtime links and /etc/timezone.
+package create
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kstenerud/yoloai/internal/config"
+)
+
+// fakeHostZone points the host zone lookups at fixtures for the test.
+func fakeHostZone(t *testing.T, localtimeTarget, timezoneFile string) {
+	t.Helper()
+	dir := t.TempDir()
+	oldLocaltime, oldTimezone := hostLocaltime, hostTimezoneFile
+	t.Cleanup(func() { hostLocaltime, hostTimezoneFile = oldLocaltime, oldTimezone })
+
+	hostLocaltime = filepath.Join(dir, "localtime")
+	if localtimeTarget != "" {
+		require.NoError(t, os.Symlink(localtimeTarget, hostLocaltime))
+	}
+	hostTimezoneFile = filepath.Join(dir, "timezone")
+	if timezoneFile != "" {
+		require.NoError(t, os.WriteFile(hostTimezoneFile, []byte(timezoneFile), 0600))
+	}
+}
+
+func hostEnvWith(vars map[string]string) config.HostEnv {
+	return config.Layout{}.WithEnv(vars).Env()
+}
+
+func TestResolveTimezone(t *testing.T) {
+	fakeHostZone(t, "/usr/share/zoneinfo/America/New_York", "")
+
+	assert.Equal(t, "UTC", resolveTimezone("UTC", hostEnvWith(map[string]string{"TZ": "Asia/Tokyo"})), "config wins")
+	assert.Equal(t, "Asia/Tokyo", resolveTimezone("", hostEnvWith(map[string]string{"TZ": "Asia/Tokyo"})), "host TZ next")
+	assert.Equal(t, "Europe/Paris", resolveTimezone("", hostEnvWith(map[string]string{"TZ": ":/usr/share/zoneinfo/posix/Europe/Paris"})), "TZ naming a zone file")
+	assert.Equal(t, "America/New_York", resolveTimezone("", hostEnvWith(nil)), "then /etc/localtime")
+}
+
+func TestResolveTimezone_MacOSLocaltime(t *testing.T) {
+	fakeHostZone(t, "/var/db/timezone/zoneinfo/Australia/Sydney", "")
+	assert.Equal(t, "Australia/Sydney", resolveTimezone("", hostEnvWith(nil)))
+}
+
+func TestResolveTimezone_TimezoneFileFallback(t *testing.T) {
+	fakeHostZone(t, "", "Europe/Berlin\n")
+	assert.Equal(t, "Europe/Berlin", resolveTimezone("", hostEnvWith(nil)))
+
+	fakeHostZone(t, "", "")
+	assert.Equal(t, "", resolveTimezone("", hostEnvWith(nil)), "undeterminable: leave the image default")
+}
+
+func TestResolveLocale(t *testing.T) {
+	assert.Equal(t, "fr_FR.UTF-8", resolveLocale("fr_FR.UTF-8", hostEnvWith(map[string]string{"LANG": "en_US.UTF-8"})), "config wins")
+	assert.Equal(t, "de_DE.UTF-8", resolveLocale("", hostEnvWith(map[string]string{"LC_ALL": "de_DE.UTF-8", "LANG": "en_US.UTF-8"})), "LC_ALL over LANG")
+	assert.Equal(t, "en_US.UTF-8", resolveLocale("", hostEnvWith(map[string]string{"LANG": "en_US.UTF-8"})))
+	assert.Equal(t, "", resolveLocale("", hostEnvWith(map[string]string{"LANG": "C"})), "plain C keeps the C.UTF-8 default")
+	assert.Equal(t, "", resolveLocale("", hostEnvWith(nil)))
+}
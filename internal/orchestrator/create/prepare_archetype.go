@@ -7,8 +7,6 @@ import (
 	"context"
 	"fmt"
 	"io"
-	"os"
-	"path/filepath"
 	goruntime "runtime"
 
 	"github.com/kstenerud/yoloai/internal/config"
@@ -160,7 +158,7 @@ func applyDevcontainerArchetype(ctx context.Context, d state.Deps, opts *Options
 	workdir := opts.Workdir.Path
 	var bullets []string
 
-	dcPath := findDevcontainerPath(workdir)
+	dcPath := archetype.FindDevcontainer(workdir)
 	if dcPath == "" {
 		return nil, nil, nil, bullets, nil
 	}
@@ -198,19 +196,6 @@ func applyDevcontainerArchetype(ctx context.Context, d state.Deps, opts *Options
 	return dc, dcMounts, dcMountWarnings, bullets, nil
 }
 
-// findDevcontainerPath returns the path to devcontainer.json, or empty string if not found.
-func findDevcontainerPath(workdir string) string {
-	for _, candidate := range []string{
-		filepath.Join(workdir, ".devcontainer", "devcontainer.json"),
-		filepath.Join(workdir, "devcontainer.json"),
-	} {
-		if _, err := os.Stat(candidate); err == nil {
-			return candidate
-		}
-	}
-	return ""
-}
-
 // applyDevcontainerRunArgs applies runArgs (cpus, memory, capAdd) from devcontainer.json.
 func applyDevcontainerRunArgs(dc *archetype.DevcontainerConfig, pr *profileResult, bullets []string, output io.Writer) []string {
 	cpus, memory, capAdd, unknownWarnings := dc.ParsedRunArgs()
@@ -9,6 +9,7 @@ import (
 	"io"
 	"log/slog"
 	"os"
+	"path/filepath"
 	"strings"
 
 	"github.com/kstenerud/yoloai/internal/agent"
@@ -243,12 +244,14 @@ func checkDirtyRepos(ctx context.Context, g *git.Git, workdir *DirSpec, auxDirs
 // setupWorkdir copies the workdir, strips git metadata, and creates
 // the git baseline. Returns the work copy directory path and baseline SHA.
 // For backends implementing WorkDirSetup (e.g., Tart), baseline creation is
-// deferred until the VM starts, and this function returns empty SHA.
-func setupWorkdir(ctx context.Context, g *git.Git, sandboxDir string, workdir *DirSpec, rt runtime.Backend) (string, string, error) {
+// deferred until the VM starts, and this function returns empty SHA. A :copy
+// work copy shares its git packs with other copies of the same directory under
+// objectsDir ("" for none; see workcopy.ObjectStoreRoot).
+func setupWorkdir(ctx context.Context, g *git.Git, sandboxDir, objectsDir string, workdir *DirSpec, rt runtime.Backend) (string, string, error) {
 	workCopyDir := store.WorkDir(sandboxDir, workdir.Path)
 
 	if workdir.Mode == DirModeCopy {
-		sha, err := materializeCopyDir(ctx, g, workdir, workCopyDir, rt)
+		sha, err := materializeCopyDir(ctx, g, workdir, workCopyDir, objectsDir, rt)
 		if err != nil {
 			return "", "", err
 		}
@@ -268,11 +271,12 @@ func setupWorkdir(ctx context.Context, g *git.Git, sandboxDir string, workdir *D
 // their callers dispatch on mode first. This is the single create-side seam onto
 // workcopy.Materialize, so the workdir and each aux :copy dir go through the same
 // sequence create and reset share (the archived workdir-materialization plan).
-func materializeCopyDir(ctx context.Context, g *git.Git, dir *DirSpec, workCopyDir string, rt runtime.Backend) (string, error) {
+func materializeCopyDir(ctx context.Context, g *git.Git, dir *DirSpec, workCopyDir, objectsDir string, rt runtime.Backend) (string, error) {
 	sha, notice, err := workcopy.Materialize(ctx, workcopy.Spec{
 		Src:            dir.Path,
 		IncludeIgnored: dir.IncludeIgnored,
 		StripHistory:   dir.StripHistory,
+		ObjectStores:   workcopy.ObjectStoreRoot(objectsDir, dir.Path),
 	}, workCopyDir, workcopy.WipeAndCopy, g, rt)
 	if err != nil {
 		return "", fmt.Errorf("materialize %s: %w", dir.Path, err)
@@ -288,10 +292,10 @@ func materializeCopyDir(ctx context.Context, g *git.Git, dir *DirSpec, workCopyD
 // slice. :copy dirs get host-side content setup and a git baseline
 // (same pipeline as the workdir). :rw and :ro dirs are pure reference mounts
 // with no host-side preparation.
-func setupAuxDirs(ctx context.Context, g *git.Git, sandboxDir string, rt runtime.Backend, auxDirs []*DirSpec) ([]store.DirEnvironment, error) {
+func setupAuxDirs(ctx context.Context, g *git.Git, sandboxDir, objectsDir string, rt runtime.Backend, auxDirs []*DirSpec) ([]store.DirEnvironment, error) {
 	var dirEnvs []store.DirEnvironment
 	for _, ad := range auxDirs {
-		dm, err := setupAuxDir(ctx, g, sandboxDir, rt, ad)
+		dm, err := setupAuxDir(ctx, g, sandboxDir, objectsDir, rt, ad)
 		if err != nil {
 			return nil, fmt.Errorf("setup aux dir %s: %w", ad.Path, err)
 		}
@@ -310,11 +314,11 @@ func setupAuxDirs(ctx context.Context, g *git.Git, sandboxDir string, rt runtime
 // must advertise where the mount is actually reachable, so the generated
 // CLAUDE.md, `info`, and MCP {dir:N} placeholders don't point at a path that
 // doesn't exist in the guest. Identity for backends without translation.
-func setupAuxDir(ctx context.Context, g *git.Git, sandboxDir string, rt runtime.Backend, ad *DirSpec) (store.DirEnvironment, error) {
+func setupAuxDir(ctx context.Context, g *git.Git, sandboxDir, objectsDir string, rt runtime.Backend, ad *DirSpec) (store.DirEnvironment, error) {
 	switch ad.Mode {
 	case DirModeCopy:
 		workCopyDir := store.WorkDir(sandboxDir, ad.Path)
-		baselineSHA, err := materializeCopyDir(ctx, g, ad, workCopyDir, rt)
+		baselineSHA, err := materializeCopyDir(ctx, g, ad, workCopyDir, objectsDir, rt)
 		if err != nil {
 			return store.DirEnvironment{}, err
 		}
@@ -355,3 +359,34 @@ func collectCopyDirs(workdir *DirSpec, auxDirs []*DirSpec) []string {
 	}
 	return dirs
 }
+
+// worktreeContextFile is the file --context worktree writes at the root of the
+// work copy: the agent's own instruction file, or AGENTS.md, the convention
+// agents without one read from a project.
+func worktreeContextFile(agentDef *agent.Definition) string {
+	if agentDef.ContextFile != "" {
+		return agentDef.ContextFile
+	}
+	return "AGENTS.md"
+}
+
+// checkWorktreeContext refuses --context worktree where it would touch the
+// user's files: a :rw workdir is the live host directory, a work copy kept
+// inside the sandbox (SandboxSide) can't be written from here, and a context
+// file the project already has would show up in the diff.
+func checkWorktreeContext(d state.Deps, opts Options, workdir *DirSpec, agentDef *agent.Definition) error {
+	if opts.Context != ContextModeWorktree {
+		return nil
+	}
+	if workdir.Mode != DirModeCopy {
+		return yoerrors.NewUsageError("--context worktree writes into the work copy, so it needs a :copy workdir; %s is :%s and yoloai never writes to a live directory", workdir.Path, workdir.Mode)
+	}
+	if runtime.LocalityOf(d.Runtime) == runtime.LocalitySandboxSide {
+		return yoerrors.NewUsageError("--context worktree isn't supported by the %s backend, which keeps the work copy inside the sandbox", d.Runtime.Descriptor().Type)
+	}
+	name := worktreeContextFile(agentDef)
+	if _, err := os.Lstat(filepath.Join(workdir.Path, name)); err == nil {
+		return yoerrors.NewUsageError("--context worktree would write %s, but the workdir already has one; use the default --context agent", name)
+	}
+	return nil
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/kstenerud/yoloai/internal/config"
 	"github.com/kstenerud/yoloai/internal/orchestrator/profiles"
 	"github.com/kstenerud/yoloai/internal/orchestrator/state"
+	"github.com/kstenerud/yoloai/internal/timing"
 	"github.com/kstenerud/yoloai/runtime"
 	"github.com/kstenerud/yoloai/yoerrors"
 )
@@ -31,9 +32,16 @@ type profileResult struct {
 	devices            []string
 	setup              []string
 	autoCommitInterval int
+	provenanceHeaders  bool
+	timezone           string // configured TZ; "" = the host's (resolveTimezone)
+	locale             string // configured LANG; "" = the host's (resolveLocale)
+	ttl                string // configured ttl; --ttl overrides (expiresAt)
+	fakeTime           string // configured faketime, then the effective one (resolveFakeTime)
+	preLaunch          string // configured pre_launch snippet (checkPreLaunch)
+	guard              *config.GuardConfig
 	isolation          runtime.IsolationMode
-	isolationExplicit  bool // true when isolation was set via --isolation flag (not config/profile default)
-	userAliases        map[string]string
+	isolationExplicit  bool              // true when isolation was set via --isolation flag (not config/profile default)
+	userAliases        map[string]string // global model_aliases, then the profile's and the project's over them
 	// Archetype-specific resolved fields
 	archetypeDockerDRequired bool // true when archetype requires dockerd auto-start
 }
@@ -46,8 +54,17 @@ func resolveProfileConfig(ctx context.Context, d state.Deps, opts *Options, agen
 		agentArgs:          ycfg.AgentArgs,
 		agentFiles:         ycfg.AgentFiles,
 		autoCommitInterval: ycfg.AutoCommitInterval,
+		timezone:           ycfg.Timezone,
+		locale:             ycfg.Locale,
+		ttl:                ycfg.TTL,
+		fakeTime:           ycfg.FakeTime,
+		preLaunch:          ycfg.PreLaunch,
+		guard:              ycfg.Guard,
 		userAliases:        gcfg.ModelAliases,
 	}
+	if ycfg.ProvenanceHeaders != nil {
+		pr.provenanceHeaders = *ycfg.ProvenanceHeaders
+	}
 
 	if opts.Profile == "" {
 		// No profile specified: use base image
@@ -81,7 +98,10 @@ func resolveProfileConfig(ctx context.Context, d state.Deps, opts *Options, agen
 
 	// Build profile image if needed (Docker only)
 	logger := slog.Default()
-	if err := profiles.EnsureProfileImage(ctx, d.Runtime, d.Layout, opts.Profile, profiles.AutoBuildSecrets(d.Layout.HomeDir), outputFor(opts.Output), logger, false); err != nil {
+	stop := timing.Track(ctx, timing.PhaseImage)
+	err = profiles.EnsureProfileImage(ctx, d.Runtime, d.Layout, opts.Profile, profiles.AutoBuildSecrets(d.Layout.HomeDir), outputFor(opts.Output), logger, false)
+	stop()
+	if err != nil {
 		return nil, fmt.Errorf("build profile image: %w", err)
 	}
 
@@ -153,11 +173,33 @@ func applyMergedProfileToOpts(opts *Options, agentDef **agent.Definition, merged
 	pr.devices = merged.Devices
 	pr.setup = merged.Setup
 	pr.autoCommitInterval = merged.AutoCommitInterval
+	pr.provenanceHeaders = merged.ProvenanceHeaders
+	pr.timezone = merged.Timezone
+	pr.locale = merged.Locale
+	pr.ttl = merged.TTL
+	pr.fakeTime = merged.FakeTime
+	pr.preLaunch = merged.PreLaunch
+	pr.guard = merged.Guard
 	pr.isolation = runtime.IsolationMode(merged.Isolation)
+	pr.userAliases = overlayAliases(pr.userAliases, merged.ModelAliases)
 
 	return nil
 }
 
+// overlayAliases returns base with over's entries added, over winning. base
+// may be the global config's own map, so it is copied rather than modified.
+func overlayAliases(base, over map[string]string) map[string]string {
+	if len(over) == 0 {
+		return base
+	}
+	out := maps.Clone(base)
+	if out == nil {
+		out = make(map[string]string, len(over))
+	}
+	maps.Copy(out, over)
+	return out
+}
+
 // prependProfileDirs prepends profile directory specs before the CLI aux dirs.
 // homeDir is used for ~ expansion in profile directory paths.
 // env is the curated interpolation map for ${VAR} expansion; pass
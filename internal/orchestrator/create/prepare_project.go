@@ -0,0 +1,190 @@
+// ABOUTME: Project defaults from the workdir's .yoloai.yaml — profile, agent,
+// ABOUTME: model, model aliases, ports, env and network — applied below CLI flags.
+package create
+
+import (
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/kstenerud/yoloai/internal/agent"
+	"github.com/kstenerud/yoloai/internal/config"
+	"github.com/kstenerud/yoloai/internal/orchestrator/archetype"
+	"github.com/kstenerud/yoloai/internal/orchestrator/state"
+	"github.com/kstenerud/yoloai/yoerrors"
+)
+
+// loadProjectConfig loads workdir's .yoloai.yaml. Returns nil when there is
+// no workdir yet (a profile may supply it) or no file.
+func loadProjectConfig(d state.Deps, workdir string) (*archetype.YoloAIProjectConfig, error) {
+	if workdir == "" {
+		return nil, nil
+	}
+	cfg, _, err := archetype.LoadYoloAIYaml(workdir, d.Layout.HomeDir, d.Layout.Env().EnvForConfigInterpolation())
+	if err != nil {
+		return nil, fmt.Errorf("load .yoloai.yaml: %w", err)
+	}
+	return cfg, nil
+}
+
+// applyProjectSelection applies the project's profile, agent and model where
+// the caller didn't choose them. It runs before profile resolution so the
+// project's profile is the one resolved, and so its agent and model win over
+// the profile's. Like applyMergedProfileToOpts, an agent or model equal to
+// the base config's counts as not chosen: the CLI fills them from config.
+func applyProjectSelection(opts *Options, agentDef **agent.Definition, project *archetype.YoloAIProjectConfig, ycfg *config.YoloaiConfig) error {
+	if project == nil {
+		return nil
+	}
+	var applied []string
+	if project.Profile != "" && opts.Profile == "" && !opts.NoProfile {
+		opts.Profile = project.Profile
+		applied = append(applied, "profile "+project.Profile)
+	}
+	if project.Agent != "" && opts.Agent == ycfg.Agent && project.Agent != opts.Agent {
+		def := agent.GetAgent(project.Agent)
+		if def == nil {
+			return yoerrors.NewUsageError("unknown agent in .yoloai.yaml: %s", project.Agent)
+		}
+		opts.Agent = project.Agent
+		*agentDef = def
+		applied = append(applied, "agent "+project.Agent)
+	}
+	if project.Model != "" && (opts.Model == "" || opts.Model == ycfg.Model) && project.Model != opts.Model {
+		opts.Model = project.Model
+		applied = append(applied, "model "+project.Model)
+	}
+	printProjectDefaults(outputFor(opts.Output), applied)
+	return nil
+}
+
+// applyProjectDefaults merges the project's ports, env and network under the
+// CLI's, and its model aliases over the global and profile ones (the most
+// specific scope wins). Ports and allowed domains are additive. Env only adds: a variable the
+// user's config or profile sets keeps its value, and one that steers where the
+// agent's credentials go or what code it loads is refused outright (see
+// projectEnvRefused). The project can turn network isolation on but never off,
+// and yields to --network-none.
+func applyProjectDefaults(opts *Options, pr *profileResult, project *archetype.YoloAIProjectConfig) {
+	if project == nil {
+		return
+	}
+	var applied []string
+
+	var ports []string
+	for _, p := range project.Ports {
+		if !slices.Contains(opts.Ports, p) {
+			opts.Ports = append(opts.Ports, p)
+			ports = append(ports, p)
+		}
+	}
+	if len(ports) > 0 {
+		applied = append(applied, "ports "+strings.Join(ports, " "))
+	}
+
+	var keys, refused []string
+	for k, v := range project.Env {
+		if _, cli := opts.Env[k]; cli {
+			continue
+		}
+		if _, own := pr.env[k]; own {
+			continue
+		}
+		if projectEnvRefused(k) {
+			refused = append(refused, k)
+			continue
+		}
+		if pr.env == nil {
+			pr.env = make(map[string]string)
+		}
+		pr.env[k] = v
+		keys = append(keys, k)
+	}
+	if len(keys) > 0 {
+		sort.Strings(keys)
+		applied = append(applied, "env "+strings.Join(keys, " "))
+	}
+
+	if len(project.ModelAliases) > 0 {
+		pr.userAliases = overlayAliases(pr.userAliases, project.ModelAliases)
+		aliases := slices.Sorted(maps.Keys(project.ModelAliases))
+		applied = append(applied, "model aliases "+strings.Join(aliases, " "))
+	}
+
+	if n := project.Network; n != nil && (n.Isolated || len(n.Allow) > 0) && opts.Network != NetworkModeNone {
+		opts.Network = NetworkModeIsolated
+		added := 0
+		for _, domain := range n.Allow {
+			if !slices.Contains(opts.NetworkAllow, domain) {
+				opts.NetworkAllow = append(opts.NetworkAllow, domain)
+				added++
+			}
+		}
+		applied = append(applied, fmt.Sprintf("network isolated (+%d allowed domains)", added))
+	}
+
+	printProjectDefaults(outputFor(opts.Output), applied)
+	if len(refused) > 0 {
+		sort.Strings(refused)
+		fmt.Fprintf(outputFor(opts.Output), "→ .yoloai.yaml env ignored: %s (credentials, endpoints, proxies and loader settings come only from your own config or --env)\n", strings.Join(refused, " ")) //nolint:errcheck // best-effort output
+	}
+}
+
+// projectEnvRefusedNames and the patterns below are the variables a checked-in
+// .yoloai.yaml may not set: ones that would send the agent's API traffic and
+// credentials somewhere else (base URLs, proxies, CA bundles), carry
+// credentials themselves, or run code the repository chose in every process
+// the agent starts (loader and interpreter startup settings, git's config and
+// helpers).
+var projectEnvRefusedNames = map[string]bool{
+	"PATH": true, "NODE_OPTIONS": true, "BASH_ENV": true, "ENV": true,
+	"PYTHONPATH": true, "PYTHONSTARTUP": true, "PYTHONHOME": true,
+	"PERL5OPT": true, "PERL5LIB": true, "RUBYOPT": true, "RUBYLIB": true,
+	"HTTP_PROXY": true, "HTTPS_PROXY": true, "ALL_PROXY": true, "NO_PROXY": true,
+	"SSL_CERT_FILE": true, "SSL_CERT_DIR": true, "NODE_EXTRA_CA_CERTS": true,
+	"REQUESTS_CA_BUNDLE": true, "CURL_CA_BUNDLE": true,
+	"OLLAMA_HOST": true,
+}
+
+var (
+	projectEnvRefusedPrefixes = []string{"LD_", "DYLD_", "GIT_"}
+	projectEnvRefusedSuffixes = []string{"_BASE_URL", "_API_BASE", "_API_URL", "_ENDPOINT", "_ENDPOINT_URL"}
+	projectEnvRefusedWords    = []string{"API_KEY", "TOKEN", "SECRET", "PASSWORD", "CREDENTIAL"}
+)
+
+// projectEnvRefused reports whether a .yoloai.yaml may not set key. Names are
+// compared uppercased, since proxy variables are honored in either case.
+func projectEnvRefused(key string) bool {
+	k := strings.ToUpper(key)
+	if projectEnvRefusedNames[k] {
+		return true
+	}
+	for _, p := range projectEnvRefusedPrefixes {
+		if strings.HasPrefix(k, p) {
+			return true
+		}
+	}
+	for _, s := range projectEnvRefusedSuffixes {
+		if strings.HasSuffix(k, s) {
+			return true
+		}
+	}
+	for _, w := range projectEnvRefusedWords {
+		if strings.Contains(k, w) {
+			return true
+		}
+	}
+	return false
+}
+
+// printProjectDefaults says which settings came from the project's file, which
+// is checked into a repository the user may not have written.
+func printProjectDefaults(output io.Writer, applied []string) {
+	if len(applied) == 0 {
+		return
+	}
+	fmt.Fprintf(output, "→ .yoloai.yaml sets %s\n", strings.Join(applied, ", ")) //nolint:errcheck // best-effort output
+}
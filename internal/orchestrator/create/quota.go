@@ -0,0 +1,54 @@
+// ABOUTME: The max_sandboxes_per_workdir quota — refuses a create that would
+// ABOUTME: give a workdir one sandbox too many, naming the existing ones oldest first.
+package create
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/kstenerud/yoloai/internal/config"
+	"github.com/kstenerud/yoloai/store"
+	"github.com/kstenerud/yoloai/yoerrors"
+)
+
+// checkWorkdirQuota refuses to create another sandbox on opts.Workdir when
+// limit sandboxes already use it as their workdir; limit 0 means no limit.
+// With --replace the sandbox being replaced doesn't count, since it goes
+// away. A sandbox whose environment.json won't load is skipped: it is
+// neither counted nor offered for destruction.
+func checkWorkdirQuota(layout config.Layout, opts Options, limit int) error {
+	if limit <= 0 || opts.Workdir.Path == "" {
+		return nil
+	}
+	workdir := filepath.Clean(opts.Workdir.Path)
+	entries, err := os.ReadDir(layout.SandboxesDir())
+	if err != nil {
+		return nil // no sandboxes yet
+	}
+	type existing struct {
+		name    string
+		created time.Time
+	}
+	var same []existing
+	for _, entry := range entries {
+		if !entry.IsDir() || (opts.Replace && entry.Name() == opts.Name) {
+			continue
+		}
+		env, err := store.LoadEnvironment(filepath.Join(layout.SandboxesDir(), entry.Name()))
+		if err != nil || env.Workdir() == nil || filepath.Clean(env.Workdir().HostPath) != workdir {
+			continue
+		}
+		same = append(same, existing{name: entry.Name(), created: env.CreatedAt})
+	}
+	if len(same) < limit {
+		return nil
+	}
+	sort.SliceStable(same, func(i, j int) bool { return same[i].created.Before(same[j].created) })
+	names := make([]string, len(same))
+	for i, s := range same {
+		names[i] = s.name
+	}
+	return &yoerrors.SandboxQuotaError{Workdir: workdir, Limit: limit, Sandboxes: names}
+}
@@ -0,0 +1,55 @@
+// ABOUTME: Tests for the max_sandboxes_per_workdir quota check.
+package create
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/kstenerud/yoloai/internal/config"
+	"github.com/kstenerud/yoloai/store"
+	"github.com/kstenerud/yoloai/yoerrors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// saveQuotaSandbox writes a sandbox on workdir created at created.
+func saveQuotaSandbox(t *testing.T, layout config.Layout, name, workdir string, created time.Time) {
+	t.Helper()
+	dir := layout.SandboxDir(name)
+	require.NoError(t, os.MkdirAll(dir, 0o750))
+	require.NoError(t, store.SaveEnvironment(dir, &store.Environment{
+		Name:      name,
+		CreatedAt: created,
+		Dirs:      []store.DirEnvironment{{HostPath: workdir, Mode: store.DirModeCopy}},
+	}))
+}
+
+func TestCheckWorkdirQuota(t *testing.T) {
+	layout := config.NewLayout(filepath.Join(t.TempDir(), ".yoloai"))
+	now := time.Now()
+	saveQuotaSandbox(t, layout, "newer", "/src/app", now)
+	saveQuotaSandbox(t, layout, "older", "/src/app/", now.Add(-time.Hour))
+	saveQuotaSandbox(t, layout, "other", "/src/lib", now.Add(-2*time.Hour))
+	opts := Options{Name: "next", Workdir: DirSpec{Path: "/src/app"}}
+
+	require.NoError(t, checkWorkdirQuota(layout, opts, 0), "0 is no limit")
+	require.NoError(t, checkWorkdirQuota(layout, opts, 3))
+
+	err := checkWorkdirQuota(layout, opts, 2)
+	quota, ok := errors.AsType[*yoerrors.SandboxQuotaError](err)
+	require.True(t, ok, "got %v", err)
+	assert.Equal(t, "/src/app", quota.Workdir)
+	assert.Equal(t, 2, quota.Limit)
+	assert.Equal(t, []string{"older", "newer"}, quota.Sandboxes, "oldest first; other workdirs don't count")
+
+	opts.Name, opts.Replace = "older", true
+	require.NoError(t, checkWorkdirQuota(layout, opts, 2), "the sandbox being replaced doesn't count")
+}
+
+func TestCheckWorkdirQuota_NoSandboxesDir(t *testing.T) {
+	layout := config.NewLayout(filepath.Join(t.TempDir(), ".yoloai"))
+	require.NoError(t, checkWorkdirQuota(layout, Options{Name: "a", Workdir: DirSpec{Path: "/src/app"}}, 1))
+}
@@ -0,0 +1,115 @@
+// ABOUTME: --repo workdirs: splits URL[@ref] and clones the repository into a
+// ABOUTME: yoloai-owned checkout that stands in for a host workdir.
+package create
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/kstenerud/yoloai/internal/config"
+	"github.com/kstenerud/yoloai/internal/fileutil"
+	"github.com/kstenerud/yoloai/internal/git"
+	"github.com/kstenerud/yoloai/yoerrors"
+)
+
+// splitRepoRef splits a --repo spec into its clone URL and optional ref. The
+// ref follows an "@" in the path part, so the user@ of an ssh URL
+// (git@github.com:org/repo, ssh://git@host/org/repo) is never mistaken for one.
+func splitRepoRef(spec string) (url, ref string) {
+	pathStart := 0
+	if i := strings.Index(spec, "://"); i >= 0 {
+		if j := strings.Index(spec[i+3:], "/"); j >= 0 {
+			pathStart = i + 3 + j
+		}
+	} else if i := strings.Index(spec, ":"); i >= 0 {
+		pathStart = i + 1
+	}
+	if at := strings.Index(spec[pathStart:], "@"); at >= 0 {
+		return spec[:pathStart+at], spec[pathStart+at+1:]
+	}
+	return spec, ""
+}
+
+// commitSHARe matches a full commit ID (SHA-1 or SHA-256), the only kind of
+// commit ref a server will hand out on its own, with no branch or tag naming it.
+var commitSHARe = regexp.MustCompile(`^([0-9a-f]{40}|[0-9a-f]{64})$`)
+
+// cloneRepo clones a --repo spec into a new directory under the layout's
+// repos dir and checks out its ref, returning the checkout. The directory name
+// is unique, so a --replace of a sandbox with the same name never collides
+// with the checkout it is replacing. The host's git (and so its credential
+// helpers) does the clone, which is what lets a private repository work.
+//
+// depth > 0 makes the clone shallow: only the ref's last depth commits are
+// fetched, so a branch or tag ref is cloned directly and a commit ref (a full
+// SHA) is fetched on its own. sparse, when set, is a cone-mode sparse checkout
+// of those directories over a blobless clone, so file contents outside them
+// are never downloaded. Full history and a full tree stay the default: the
+// sandbox's copy of the checkout has no route back to the remote, so anything
+// left out is out of reach of the agent's git too.
+func cloneRepo(ctx context.Context, layout config.Layout, g *git.Git, name, spec string, depth int, sparse []string) (string, error) {
+	url, ref := splitRepoRef(spec)
+	if url == "" {
+		return "", yoerrors.NewUsageError("--repo needs a repository URL: %q", spec)
+	}
+	if strings.HasSuffix(spec, "@") {
+		return "", yoerrors.NewUsageError("--repo %s: empty ref after @", spec)
+	}
+	if err := fileutil.MkdirAll(layout.ReposDir(), 0750); err != nil {
+		return "", fmt.Errorf("create repos dir: %w", err)
+	}
+	dir, err := os.MkdirTemp(layout.ReposDir(), name+"-")
+	if err != nil {
+		return "", fmt.Errorf("create checkout dir: %w", err)
+	}
+	if err := cloneInto(ctx, g, url, ref, dir, depth, sparse); err != nil {
+		_ = os.RemoveAll(dir)
+		return "", err
+	}
+	return dir, nil
+}
+
+// cloneInto runs the clone and checkout steps of cloneRepo in dir.
+func cloneInto(ctx context.Context, g *git.Git, url, ref, dir string, depth int, sparse []string) error {
+	fetchSHA := depth > 0 && commitSHARe.MatchString(ref)
+	args := []string{"clone", "--quiet"}
+	if depth > 0 {
+		args = append(args, "--depth", strconv.Itoa(depth))
+		if ref != "" && !fetchSHA {
+			args = append(args, "--branch", ref)
+		}
+	}
+	if len(sparse) > 0 {
+		args = append(args, "--filter=blob:none", "--sparse")
+	}
+	if fetchSHA {
+		args = append(args, "--no-checkout")
+	}
+	args = append(args, url, dir)
+	if err := g.RunCmd(ctx, filepath.Dir(dir), args...); err != nil {
+		return fmt.Errorf("clone %s: %w", url, err)
+	}
+
+	if len(sparse) > 0 {
+		if err := g.RunCmd(ctx, dir, append([]string{"sparse-checkout", "set", "--cone", "--"}, sparse...)...); err != nil {
+			return fmt.Errorf("sparse checkout of %s: %w", url, err)
+		}
+	}
+	switch {
+	case fetchSHA:
+		if err := g.RunCmd(ctx, dir, "fetch", "--quiet", "--depth", strconv.Itoa(depth), "origin", ref); err != nil {
+			return fmt.Errorf("fetch %s of %s: %w", ref, url, err)
+		}
+		fallthrough
+	case ref != "" && depth == 0:
+		if err := g.RunCmd(ctx, dir, "checkout", "--quiet", ref); err != nil {
+			return fmt.Errorf("check out %s of %s: %w", ref, url, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,140 @@
+// ABOUTME: --repo spec parsing and the clone that stands in for a host workdir.
+package create
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kstenerud/yoloai/internal/config"
+	"github.com/kstenerud/yoloai/internal/git"
+	"github.com/kstenerud/yoloai/internal/testutil"
+)
+
+func TestSplitRepoRef(t *testing.T) {
+	tests := []struct {
+		spec, url, ref string
+	}{
+		{"https://github.com/org/repo", "https://github.com/org/repo", ""},
+		{"https://github.com/org/repo@main", "https://github.com/org/repo", "main"},
+		{"https://github.com/org/repo@feature/x", "https://github.com/org/repo", "feature/x"},
+		{"https://user@github.com/org/repo", "https://user@github.com/org/repo", ""},
+		{"ssh://git@host/org/repo@v1.2", "ssh://git@host/org/repo", "v1.2"},
+		{"git@github.com:org/repo.git", "git@github.com:org/repo.git", ""},
+		{"git@github.com:org/repo.git@abc123", "git@github.com:org/repo.git", "abc123"},
+		{"/srv/git/repo@dev", "/srv/git/repo", "dev"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			url, ref := splitRepoRef(tt.spec)
+			assert.Equal(t, tt.url, url)
+			assert.Equal(t, tt.ref, ref)
+		})
+	}
+}
+
+// repoFixture builds an upstream repo with a commit on main and a second on
+// branch `dev`, and returns its path and the two heads.
+func repoFixture(t *testing.T) (upstream, mainSHA, devSHA string) {
+	t.Helper()
+	upstream = filepath.Join(t.TempDir(), "upstream")
+	require.NoError(t, os.MkdirAll(upstream, 0o750))
+	testutil.InitGitRepo(t, upstream)
+	writeTestFile(t, upstream, "app.js", "v1")
+	testutil.RunGit(t, upstream, "add", "-A")
+	gitCommit(t, upstream, "initial")
+	mainSHA = headOf(t, upstream)
+	testutil.RunGit(t, upstream, "checkout", "-q", "-b", "dev")
+	writeTestFile(t, upstream, "app.js", "v2")
+	testutil.RunGit(t, upstream, "add", "-A")
+	gitCommit(t, upstream, "dev work")
+	devSHA = headOf(t, upstream)
+	testutil.RunGit(t, upstream, "checkout", "-q", "-")
+	return upstream, mainSHA, devSHA
+}
+
+func TestCloneRepo_DefaultBranchAndRef(t *testing.T) {
+	upstream, mainSHA, devSHA := repoFixture(t)
+	layout := config.NewLayout(t.TempDir())
+	g := git.NewTestHostWithEnv(testutil.GitEnv())
+
+	dir, err := cloneRepo(context.Background(), layout, g, "box", upstream, 0, nil)
+	require.NoError(t, err)
+	assert.Equal(t, layout.ReposDir(), filepath.Dir(dir))
+	assert.Equal(t, mainSHA, headOf(t, dir))
+	assert.Equal(t, upstream, testutil.RunGitOutput(t, dir, "remote", "get-url", "origin"))
+
+	again, err := cloneRepo(context.Background(), layout, g, "box", upstream+"@dev", 0, nil)
+	require.NoError(t, err)
+	assert.NotEqual(t, dir, again, "each clone gets its own checkout")
+	assert.Equal(t, devSHA, headOf(t, again))
+}
+
+func TestCloneRepo_FailureLeavesNothing(t *testing.T) {
+	upstream, _, _ := repoFixture(t)
+	layout := config.NewLayout(t.TempDir())
+	g := git.NewTestHostWithEnv(testutil.GitEnv())
+
+	_, err := cloneRepo(context.Background(), layout, g, "box", upstream+"@no-such-ref", 0, nil)
+	require.Error(t, err)
+	_, err = cloneRepo(context.Background(), layout, g, "box", filepath.Join(t.TempDir(), "missing"), 0, nil)
+	require.Error(t, err)
+
+	entries, err := os.ReadDir(layout.ReposDir())
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
+
+func TestCloneRepo_Shallow(t *testing.T) {
+	upstream, mainSHA, devSHA := repoFixture(t)
+	url := "file://" + upstream // a plain path is cloned locally, ignoring --depth
+	layout := config.NewLayout(t.TempDir())
+	g := git.NewTestHostWithEnv(testutil.GitEnv())
+
+	tests := []struct {
+		name, spec, want string
+	}{
+		{"default branch", url, mainSHA},
+		{"branch", url + "@dev", devSHA},
+		{"commit", url + "@" + devSHA, devSHA},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir, err := cloneRepo(context.Background(), layout, g, "box", tt.spec, 1, nil)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, headOf(t, dir))
+			assert.Equal(t, "1", testutil.RunGitOutput(t, dir, "rev-list", "--count", "HEAD"), "only the ref's last commit is fetched")
+		})
+	}
+}
+
+func TestCloneRepo_Sparse(t *testing.T) {
+	upstream := filepath.Join(t.TempDir(), "upstream")
+	require.NoError(t, os.MkdirAll(upstream, 0o750))
+	testutil.InitGitRepo(t, upstream)
+	for _, d := range []string{"services/api", "services/web", "docs"} {
+		require.NoError(t, os.MkdirAll(filepath.Join(upstream, d), 0o750))
+	}
+	writeTestFile(t, upstream, "README.md", "top")
+	writeTestFile(t, upstream, "services/api/main.go", "package main")
+	writeTestFile(t, upstream, "services/web/index.js", "web")
+	writeTestFile(t, upstream, "docs/guide.md", "guide")
+	testutil.RunGit(t, upstream, "add", "-A")
+	gitCommit(t, upstream, "initial")
+	testutil.RunGit(t, upstream, "config", "uploadpack.allowFilter", "true")
+
+	layout := config.NewLayout(t.TempDir())
+	g := git.NewTestHostWithEnv(testutil.GitEnv())
+	dir, err := cloneRepo(context.Background(), layout, g, "box", "file://"+upstream, 0, []string{"services/api"})
+	require.NoError(t, err)
+
+	assert.FileExists(t, filepath.Join(dir, "README.md"), "cone mode keeps top-level files")
+	assert.FileExists(t, filepath.Join(dir, "services", "api", "main.go"))
+	assert.NoFileExists(t, filepath.Join(dir, "services", "web", "index.js"))
+	assert.NoDirExists(t, filepath.Join(dir, "docs"))
+	assert.Equal(t, "blob:none", testutil.RunGitOutput(t, dir, "config", "remote.origin.partialclonefilter"))
+}
@@ -0,0 +1,46 @@
+// ABOUTME: Validates `new --role`: the split-role session where several agents
+// ABOUTME: with their own prompts share one work copy, one tmux window each.
+
+package create
+
+import (
+	"regexp"
+
+	"github.com/kstenerud/yoloai/yoerrors"
+)
+
+// roleNameRe is what a role name may look like. It names a tmux window and a
+// log file, so it stays shell- and tmux-target-safe.
+var roleNameRe = regexp.MustCompile(`^[a-z][a-z0-9-]{0,31}$`)
+
+// validateRoles checks opts.Roles. A split-role session needs at least two
+// uniquely named roles, each with a prompt, and replaces --prompt: the first
+// role's prompt is the sandbox's prompt.
+func validateRoles(opts Options) error {
+	if len(opts.Roles) == 0 {
+		return nil
+	}
+	if opts.Prompt != "" || opts.PromptFile != "" {
+		return yoerrors.NewUsageError("--role and --prompt/--prompt-file are mutually exclusive: each role carries its own prompt")
+	}
+	if opts.Headless {
+		return yoerrors.NewUsageError("--role needs an interactive session; it can't be used with a headless run")
+	}
+	if len(opts.Roles) < 2 {
+		return yoerrors.NewUsageError("--role needs at least two roles; for a single agent use --prompt")
+	}
+	seen := make(map[string]bool, len(opts.Roles))
+	for _, r := range opts.Roles {
+		if !roleNameRe.MatchString(r.Name) || r.Name == "vscode-tunnel" || r.Name == "shell" {
+			return yoerrors.NewUsageError("invalid role name %q: use up to 32 lowercase letters, digits and dashes, starting with a letter", r.Name)
+		}
+		if seen[r.Name] {
+			return yoerrors.NewUsageError("role %q is given twice", r.Name)
+		}
+		seen[r.Name] = true
+		if r.Prompt == "" {
+			return yoerrors.NewUsageError("role %q has an empty prompt", r.Name)
+		}
+	}
+	return nil
+}
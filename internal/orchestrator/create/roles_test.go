@@ -0,0 +1,34 @@
+// ABOUTME: Tests for `new --role` validation: names, duplicates, the two-role
+// ABOUTME: minimum, and exclusivity with --prompt and headless runs.
+package create
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kstenerud/yoloai/internal/orchestrator/runtimeconfig"
+	"github.com/kstenerud/yoloai/yoerrors"
+)
+
+func TestValidateRoles(t *testing.T) {
+	two := []runtimeconfig.Role{{Name: "implementer", Prompt: "build it"}, {Name: "tester", Prompt: "test it"}}
+	assert.NoError(t, validateRoles(Options{}))
+	assert.NoError(t, validateRoles(Options{Roles: two}))
+
+	bad := map[string]Options{
+		"with --prompt":  {Roles: two, Prompt: "x"},
+		"headless":       {Roles: two, Headless: true},
+		"single role":    {Roles: two[:1]},
+		"bad name":       {Roles: []runtimeconfig.Role{two[0], {Name: "Tester", Prompt: "x"}}},
+		"reserved name":  {Roles: []runtimeconfig.Role{two[0], {Name: "vscode-tunnel", Prompt: "x"}}},
+		"shell window":   {Roles: []runtimeconfig.Role{two[0], {Name: "shell", Prompt: "x"}}},
+		"duplicate name": {Roles: []runtimeconfig.Role{two[0], two[0]}},
+		"empty prompt":   {Roles: []runtimeconfig.Role{two[0], {Name: "tester"}}},
+	}
+	for name, opts := range bad {
+		err := validateRoles(opts)
+		var ue *yoerrors.UsageError
+		assert.ErrorAs(t, err, &ue, name)
+	}
+}
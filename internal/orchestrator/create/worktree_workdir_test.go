@@ -43,7 +43,7 @@ func setupCopyAllWorkdir(t *testing.T, path string) (string, error) {
 	t.Helper()
 	sandboxDir := filepath.Join(t.TempDir(), "sandbox")
 	workdir := &DirSpec{Path: path, Mode: DirMode("copy"), IncludeIgnored: true}
-	_, sha, err := setupWorkdir(context.Background(), git.NewTestHostWithEnv(testutil.GitEnv()), sandboxDir, workdir, &mockDockerRuntime{})
+	_, sha, err := setupWorkdir(context.Background(), git.NewTestHostWithEnv(testutil.GitEnv()), sandboxDir, "", workdir, &mockDockerRuntime{})
 	return sha, err
 }
 
@@ -103,7 +103,7 @@ func TestSetupWorkdir_CopyAllFromWorktree_ProducesStandaloneBaseline(t *testing.
 
 	sandboxDir := filepath.Join(t.TempDir(), "sandbox")
 	workdir := &DirSpec{Path: wt, Mode: DirMode("copy"), IncludeIgnored: true}
-	workCopyDir, sha, err := setupWorkdir(context.Background(), git.NewTestHostWithEnv(testutil.GitEnv()), sandboxDir, workdir, &mockDockerRuntime{})
+	workCopyDir, sha, err := setupWorkdir(context.Background(), git.NewTestHostWithEnv(testutil.GitEnv()), sandboxDir, "", workdir, &mockDockerRuntime{})
 	require.NoError(t, err)
 
 	assert.Len(t, sha, 40, "the work copy needs a baseline of its own")
@@ -132,7 +132,7 @@ func TestSetupWorkdir_CopyFromWorktree_LeavesHostRepoAlone(t *testing.T) {
 
 	sandboxDir := filepath.Join(t.TempDir(), "sandbox")
 	workdir := &DirSpec{Path: wt, Mode: DirMode("copy")}
-	_, sha, err := setupWorkdir(context.Background(), git.NewTestHostWithEnv(testutil.GitEnv()), sandboxDir, workdir, &mockDockerRuntime{})
+	_, sha, err := setupWorkdir(context.Background(), git.NewTestHostWithEnv(testutil.GitEnv()), sandboxDir, "", workdir, &mockDockerRuntime{})
 	require.NoError(t, err)
 
 	assert.Len(t, sha, 40)
@@ -150,7 +150,7 @@ func TestSetupWorkdir_CopyAllFromNormalRepo_PreservesHistory(t *testing.T) {
 
 	sandboxDir := filepath.Join(t.TempDir(), "sandbox")
 	workdir := &DirSpec{Path: dir, Mode: DirMode("copy"), IncludeIgnored: true}
-	workCopyDir, sha, err := setupWorkdir(context.Background(), git.NewTestHostWithEnv(testutil.GitEnv()), sandboxDir, workdir, &mockDockerRuntime{})
+	workCopyDir, sha, err := setupWorkdir(context.Background(), git.NewTestHostWithEnv(testutil.GitEnv()), sandboxDir, "", workdir, &mockDockerRuntime{})
 	require.NoError(t, err)
 
 	assert.Equal(t, headOf(t, dir), sha, "a real repo's history survives, so its HEAD is the baseline")
@@ -180,7 +180,7 @@ func assertAuxDirHasNoBaseline(t *testing.T, mode string) {
 
 	dirEnv, err := setupAuxDir(context.Background(),
 		git.NewTestHostWithEnv(testutil.GitEnv()),
-		filepath.Join(t.TempDir(), "sandbox"),
+		filepath.Join(t.TempDir(), "sandbox"), "",
 		&mockDockerRuntime{},
 		&DirSpec{Path: dir, Mode: DirMode(mode)})
 	require.NoError(t, err)
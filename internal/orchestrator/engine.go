@@ -16,6 +16,7 @@ import (
 	"github.com/kstenerud/yoloai/internal/fileutil"
 	"github.com/kstenerud/yoloai/internal/orchestrator/state"
 	tmuxres "github.com/kstenerud/yoloai/internal/resources/tmux"
+	"github.com/kstenerud/yoloai/internal/timing"
 	"github.com/kstenerud/yoloai/runtime"
 	"github.com/kstenerud/yoloai/store"
 	"github.com/kstenerud/yoloai/yoerrors"
@@ -214,6 +215,10 @@ func (e *Engine) deps() state.Deps {
 // WithLayout.
 func (e *Engine) Layout() config.Layout { return e.layout }
 
+// Logger returns the Engine's structured logger, for admin handles that run
+// backend work outside the Engine.
+func (e *Engine) Logger() *slog.Logger { return e.logger }
+
 // EnsureSetup performs first-run auto-setup. Idempotent — safe to call
 // before every sandbox operation. Non-interactive: scaffolds the data
 // dir, materializes declarative safe defaults, runs the library schema
@@ -236,6 +241,7 @@ func (e *Engine) EnsureSetup(ctx context.Context, out io.Writer) error {
 		return err
 	}
 	baseProfileDir := e.layout.ProfileDir("base")
+	defer timing.Track(ctx, timing.PhaseImage)()
 	return e.runtime.Setup(ctx, e.layout, baseProfileDir, out, e.logger, false)
 }
 
@@ -326,34 +332,3 @@ func (e *Engine) Runtime() runtime.Backend {
 func (e *Engine) SandboxFiles(name string) string {
 	return store.FilesDir(e.layout.SandboxDir(name))
 }
-
-// SendInput sends text to the sandbox agent's terminal via tmux send-keys.
-// If the agent is running, this interrupts it mid-task. If the agent is idle
-// at its prompt, this sends a follow-up message. The caller should check
-// the sandbox status (via Inspect) before calling to know which case applies.
-//
-// Acquires the per-sandbox lock (Q-T): SendInput mutates sandbox state
-// (injects keystrokes into the running agent's tmux session), so it
-// serialises against concurrent Stop / Destroy / Reset / Apply for the
-// same sandbox. Each call is brief (one exec), so the lock-hold time
-// is small even under interactive use.
-func (e *Engine) SendInput(ctx context.Context, name string, text string) error {
-	if err := e.ensure(ctx); err != nil {
-		return err
-	}
-	unlock, err := store.AcquireLock(e.layout, name)
-	if err != nil {
-		return err
-	}
-	defer unlock()
-
-	containerName := store.InstanceName(e.layout.Principal, name)
-	_, err = e.runtime.Exec(ctx, containerName,
-		[]string{"tmux", "send-keys", "-t", "main", text, "Enter"},
-		"yoloai",
-	)
-	if err != nil {
-		return fmt.Errorf("send input to sandbox %q: %w", name, err)
-	}
-	return nil
-}
@@ -1,5 +1,5 @@
 // ABOUTME: Engine-level file-exchange verbs — list/import/export/remove on a
-// ABOUTME: sandbox's files/ dir, so the Files sub-handle never threads layout.
+// ABOUTME: sandbox's files/ dir and outbox/ — so the public handles never thread layout.
 
 package orchestrator
 
@@ -32,6 +32,16 @@ func (e *Engine) ReadFile(name, rel string) ([]byte, error) {
 	return ReadExchangeFile(e.layout, name, rel)
 }
 
+// ListArtifacts returns the regular files in the sandbox's outbox.
+func (e *Engine) ListArtifacts(name string) ([]Artifact, error) {
+	return ListArtifacts(e.layout, name)
+}
+
+// CollectArtifacts copies the outbox artifacts matching patterns into destDir.
+func (e *Engine) CollectArtifacts(ctx context.Context, name, destDir string, patterns []string, force bool) ([]string, error) {
+	return CollectArtifacts(ctx, e.layout, name, destDir, patterns, force)
+}
+
 // WriteFile writes data to one exchange entry (rel), creating parent dirs.
 func (e *Engine) WriteFile(name, rel string, data []byte) error {
 	return WriteExchangeFile(e.layout, name, rel, data)
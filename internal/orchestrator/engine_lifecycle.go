@@ -44,6 +44,31 @@ func (e *Engine) Stop(ctx context.Context, name string) error {
 	return lifecycle.Stop(ctx, e.deps(), name)
 }
 
+// Pause freezes the sandbox's processes in place (runtime.Pauser).
+func (e *Engine) Pause(ctx context.Context, name string) error {
+	if err := e.ensure(ctx); err != nil {
+		return err
+	}
+	return lifecycle.Pause(ctx, e.deps(), name)
+}
+
+// Unpause thaws a sandbox frozen by Pause.
+func (e *Engine) Unpause(ctx context.Context, name string) error {
+	if err := e.ensure(ctx); err != nil {
+		return err
+	}
+	return lifecycle.Unpause(ctx, e.deps(), name)
+}
+
+// CommitImage saves the sandbox's container filesystem as imageRef
+// (runtime.ImageCommitter), for creating other sandboxes from it.
+func (e *Engine) CommitImage(ctx context.Context, name, imageRef string) error {
+	if err := e.ensure(ctx); err != nil {
+		return err
+	}
+	return lifecycle.CommitImage(ctx, e.deps(), name, imageRef)
+}
+
 // Restart stops then starts the sandbox under a single backend open, applying
 // opts on the way back up.
 func (e *Engine) Restart(ctx context.Context, name string, opts StartOptions) (*StartResult, error) {
@@ -56,6 +81,16 @@ func (e *Engine) Restart(ctx context.Context, name string, opts StartOptions) (*
 	return lifecycle.Start(ctx, e.deps(), name, opts)
 }
 
+// UpdatePorts adds and removes the sandbox's port forwards, restarting it
+// (under a single backend open) when it's running so the container picks
+// them up.
+func (e *Engine) UpdatePorts(ctx context.Context, opts PortsOptions) (*PortsResult, error) {
+	if err := e.ensure(ctx); err != nil {
+		return nil, err
+	}
+	return lifecycle.UpdatePorts(ctx, e.deps(), opts)
+}
+
 // Reset re-copies the workdir, resets the diff baseline, and (per opts)
 // optionally restarts the container and wipes agent state.
 func (e *Engine) Reset(ctx context.Context, opts ResetOptions) (*ResetResult, error) {
@@ -65,6 +100,39 @@ func (e *Engine) Reset(ctx context.Context, opts ResetOptions) (*ResetResult, er
 	return lifecycle.Reset(ctx, e.deps(), opts)
 }
 
+// Rebase moves a :copy directory's baseline to its host directory as it is now
+// and replays the agent's work on top, in the running sandbox.
+func (e *Engine) Rebase(ctx context.Context, opts RebaseOptions) (*RebaseResult, error) {
+	if err := e.ensure(ctx); err != nil {
+		return nil, err
+	}
+	return lifecycle.Rebase(ctx, e.deps(), opts)
+}
+
+// UpgradeAgent reinstalls the sandbox's agent CLI at a newer version inside the
+// running container and relaunches it.
+func (e *Engine) UpgradeAgent(ctx context.Context, opts UpgradeOptions) (*UpgradeResult, error) {
+	if err := e.ensure(ctx); err != nil {
+		return nil, err
+	}
+	return lifecycle.UpgradeAgent(ctx, e.deps(), opts)
+}
+
+// CleanAgentState deletes the prunable part of a stopped sandbox's agent state.
+// Status is read through the backend recorded in the sandbox's own
+// environment.json, so a sandbox running elsewhere isn't mistaken for stopped.
+func (e *Engine) CleanAgentState(ctx context.Context, opts CleanStateOptions) (*CleanStateResult, error) {
+	if err := e.ensure(ctx); err != nil {
+		return nil, err
+	}
+	deps, cleanup, err := e.depsForSandbox(ctx, opts.Name)
+	if err != nil {
+		return nil, err
+	}
+	defer cleanup()
+	return lifecycle.CleanAgentState(ctx, deps, opts)
+}
+
 // Destroy removes the sandbox and its container. The active-work guard is the
 // caller's policy (the library boundary turns it into a typed *ActiveWorkError);
 // this is the unconditional teardown. Tears down through the backend recorded
@@ -107,6 +175,18 @@ func (e *Engine) NeedsConfirmation(ctx context.Context, name string) (bool, stri
 	return lifecycle.NeedsConfirmation(ctx, deps, name)
 }
 
+// NeedsConfirmationFor is NeedsConfirmation limited to the tracked directories
+// with the given host paths, for a directory-scoped reset.
+func (e *Engine) NeedsConfirmationFor(ctx context.Context, name string, dirs []string) (bool, string) {
+	e.TryEnsure(ctx)
+	deps, cleanup, err := e.depsForSandbox(ctx, name)
+	if err != nil {
+		return true, "backend for this sandbox is unavailable, so unapplied changes can't be verified (use --abandon-unapplied)"
+	}
+	defer cleanup()
+	return lifecycle.NeedsConfirmationFor(ctx, deps, name, dirs)
+}
+
 // depsForSandbox resolves the state.Deps to operate on the named sandbox through
 // the backend recorded in its environment.json, opening a per-sandbox runtime
 // when that backend differs from the Engine's own. A --all/wildcard destroy
@@ -6,10 +6,15 @@ package orchestrator
 import (
 	"context"
 	"fmt"
+	"maps"
+	"strings"
 
 	"github.com/kstenerud/yoloai/copyflow"
 	"github.com/kstenerud/yoloai/internal/git"
 	"github.com/kstenerud/yoloai/internal/orchestrator/agentcfg"
+	"github.com/kstenerud/yoloai/internal/orchestrator/archetype"
+	"github.com/kstenerud/yoloai/internal/orchestrator/changelog"
+	"github.com/kstenerud/yoloai/internal/orchestrator/hooks"
 	"github.com/kstenerud/yoloai/store"
 )
 
@@ -69,6 +74,14 @@ func (e *Engine) GenerateWorkingChanges(ctx context.Context, name string, dirHos
 	})
 }
 
+// GeneratePatch returns the copy-mode binary patch from baseline — to HEAD, or
+// to the live work copy with includeUncommitted. Best-effort backend open.
+func (e *Engine) GeneratePatch(ctx context.Context, name string, dirHostPath string, paths []string, includeUncommitted bool) ([]byte, error) {
+	e.TryEnsure(ctx)
+	patch, _, err := copyflow.GeneratePatch(ctx, e.layout, e.runtime, name, dirHostPath, paths, includeUncommitted)
+	return patch, err
+}
+
 // GenerateCommitDiff returns the diff for a specific commit or commit range from
 // the sandbox work copy (copy-mode only). The runtime dispatches git to where
 // the work copy lives — on the host for bind-mount backends, in-VM for Tart.
@@ -88,6 +101,113 @@ func (e *Engine) GenerateCommitDiff(ctx context.Context, name string, dirHostPat
 	})
 }
 
+// ProjectVerifyCommand returns the verify command from the .yoloai.yaml in
+// the host directory a sandbox dir was copied from ("" = workdir), or "" when
+// the file or the key is absent. The host's file is read, not the work copy's,
+// so the agent can't loosen its own gate.
+func (e *Engine) ProjectVerifyCommand(name, dirHostPath string) (string, error) {
+	meta, err := e.LoadEnvironment(name)
+	if err != nil {
+		return "", err
+	}
+	dir := meta.Dir(dirHostPath)
+	if dir == nil {
+		return "", fmt.Errorf("directory %q not found in sandbox %q", dirHostPath, name)
+	}
+	cfg, _, err := archetype.LoadYoloAIYaml(dir.HostPath, e.layout.HomeDir, e.layout.Env().EnvForConfigInterpolation())
+	if err != nil || cfg == nil {
+		return "", err
+	}
+	return cfg.Verify, nil
+}
+
+// ProjectChangelog returns where `apply --changelog` writes fragments for a
+// sandbox dir ("" = workdir): the changelog key of the .yoloai.yaml in the
+// host directory it was copied from, else the repository's towncrier
+// configuration, else nil. Like ProjectVerifyCommand it reads the host's
+// files, not the work copy's.
+func (e *Engine) ProjectChangelog(name, dirHostPath string) (*changelog.Config, error) {
+	meta, err := e.LoadEnvironment(name)
+	if err != nil {
+		return nil, err
+	}
+	dir := meta.Dir(dirHostPath)
+	if dir == nil {
+		return nil, fmt.Errorf("directory %q not found in sandbox %q", dirHostPath, name)
+	}
+	cfg, _, err := archetype.LoadYoloAIYaml(dir.HostPath, e.layout.HomeDir, e.layout.Env().EnvForConfigInterpolation())
+	if err != nil {
+		return nil, err
+	}
+	if cfg != nil && cfg.Changelog != nil {
+		return cfg.Changelog, nil
+	}
+	return changelog.Detect(dir.HostPath)
+}
+
+// ChangelogEntry is the one-line changelog entry for a sandbox's change: the
+// first line of the agent's result summary, else of the prompt, else the
+// subject of the one commit applied.
+func (e *Engine) ChangelogEntry(name string, commits []copyflow.AppliedCommit) string {
+	var candidates []string
+	if res, err := store.LoadAgentResult(e.layout.SandboxDir(name)); err == nil && res != nil {
+		candidates = append(candidates, res.Summary)
+	}
+	if prompt, ok, err := ReadStoredPrompt(e.layout, name); err == nil && ok {
+		candidates = append(candidates, prompt)
+	}
+	if len(commits) == 1 {
+		candidates = append(candidates, commits[0].Subject)
+	}
+	for _, c := range candidates {
+		line, _, _ := strings.Cut(strings.TrimSpace(c), "\n")
+		if line = strings.TrimSpace(line); line != "" {
+			return line
+		}
+	}
+	return fmt.Sprintf("Changes from sandbox %s.", name)
+}
+
+// RunApplyHooks runs the sandbox's pre-apply or post-apply hooks (event) in
+// target, the directory the changes land in, with the sandbox's metadata and
+// extra as variables. The hooks' output is dropped; a failure's error carries
+// its tail.
+func (e *Engine) RunApplyHooks(ctx context.Context, event hooks.Event, name, dirHostPath, target string, extra map[string]string) error {
+	meta, err := e.LoadEnvironment(name)
+	if err != nil {
+		return err
+	}
+	dir := meta.Dir(dirHostPath)
+	if dir == nil {
+		return fmt.Errorf("directory %q not found in sandbox %q", dirHostPath, name)
+	}
+	ac, err := e.LoadAgentConfig(name)
+	if err != nil {
+		return err
+	}
+	sandboxDir := e.layout.SandboxDir(name)
+	sb := hooks.Sandbox{
+		Name:     name,
+		Dir:      sandboxDir,
+		Profile:  meta.Profile,
+		Agent:    ac.AgentType,
+		Model:    ac.Model,
+		Backend:  string(meta.BackendType),
+		Workdir:  dir.HostPath,
+		WorkCopy: store.WorkDir(sandboxDir, dir.HostPath),
+	}
+	vars := sb.Vars()
+	maps.Copy(vars, extra)
+	return hooks.Run(ctx, e.layout, event, meta.Profile, target, vars, nil)
+}
+
+// CompareWorkCopies returns the diff between two sandboxes' :copy work copies.
+// Best-effort backend open so each side is read where it lives.
+func (e *Engine) CompareWorkCopies(ctx context.Context, opts copyflow.CompareOptions) (string, error) {
+	e.TryEnsure(ctx)
+	return copyflow.Compare(ctx, e.layout, e.runtime, opts)
+}
+
 // ExportPatches writes the sandbox's changes as patch files (the apply
 // --patches flow). Best-effort backend open.
 func (e *Engine) ExportPatches(ctx context.Context, name string, opts copyflow.ExportOptions) (*copyflow.ExportResult, error) {
@@ -95,6 +215,17 @@ func (e *Engine) ExportPatches(ctx context.Context, name string, opts copyflow.E
 	return copyflow.Export(ctx, e.layout, e.runtime, name, opts)
 }
 
+// Publish mirrors the sandbox's beyond-baseline commits to a remote ref.
+func (e *Engine) Publish(ctx context.Context, name string, opts copyflow.PublishOptions) (*copyflow.PublishResult, error) {
+	e.TryEnsure(ctx)
+	return copyflow.Publish(ctx, e.layout, e.runtime, name, opts)
+}
+
+// Unpublish deletes a ref Publish pushed from its remote.
+func (e *Engine) Unpublish(ctx context.Context, name string, opts copyflow.PublishOptions) error {
+	return copyflow.Unpublish(ctx, e.layout, name, opts)
+}
+
 // ApplySeries replays the sandbox's beyond-baseline commits onto the host.
 func (e *Engine) ApplySeries(ctx context.Context, name string, opts copyflow.ApplySeriesOptions) (*copyflow.ApplyResult, error) {
 	e.TryEnsure(ctx)
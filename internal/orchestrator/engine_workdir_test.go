@@ -0,0 +1,149 @@
+// ABOUTME: Tests for what apply resolves around the change: the verify command and
+// ABOUTME: where it runs, the changelog fragments directory, and the entry text.
+package orchestrator
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kstenerud/yoloai/copyflow"
+	"github.com/kstenerud/yoloai/internal/config"
+	"github.com/kstenerud/yoloai/runtime"
+	"github.com/kstenerud/yoloai/store"
+	"github.com/kstenerud/yoloai/yoerrors"
+)
+
+func changelogEngine(t *testing.T) (*Engine, string) {
+	t.Helper()
+	tmp := t.TempDir()
+	host := filepath.Join(tmp, "host")
+	require.NoError(t, os.MkdirAll(host, 0o750))
+	createTestSandbox(t, tmp, "box", host, store.DirModeCopy)
+	layout := config.NewLayout(filepath.Join(tmp, ".yoloai"))
+	return NewEngine("", slog.Default(), strings.NewReader(""), WithLayout(layout)), host
+}
+
+func TestProjectVerifyCommand(t *testing.T) {
+	e, host := changelogEngine(t)
+
+	cmd, err := e.ProjectVerifyCommand("box", "")
+	require.NoError(t, err)
+	assert.Empty(t, cmd, "no .yoloai.yaml")
+
+	require.NoError(t, os.WriteFile(filepath.Join(host, ".yoloai.yaml"), []byte("verify: make test\n"), 0o600))
+	cmd, err = e.ProjectVerifyCommand("box", host)
+	require.NoError(t, err)
+	assert.Equal(t, "make test", cmd, "the dir resolves by its host path as well as by \"\"")
+
+	workCopy := store.WorkDir(e.layout.SandboxDir("box"), host)
+	require.NoError(t, os.MkdirAll(workCopy, 0o750))
+	require.NoError(t, os.WriteFile(filepath.Join(workCopy, ".yoloai.yaml"), []byte("verify: \"true\"\n"), 0o600))
+	cmd, err = e.ProjectVerifyCommand("box", "")
+	require.NoError(t, err)
+	assert.Equal(t, "make test", cmd, "the work copy's file can't loosen the gate")
+
+	_, err = e.ProjectVerifyCommand("box", "/not/tracked")
+	assert.ErrorContains(t, err, "not found in sandbox")
+	_, err = e.ProjectVerifyCommand("missing", "")
+	assert.Error(t, err)
+}
+
+// workCopyExecRuntime records the command RunInWorkCopy hands the backend.
+type workCopyExecRuntime struct {
+	lifecycleMockRuntime
+	running bool
+	cmd     []string
+	workDir string
+	err     error
+}
+
+func (m *workCopyExecRuntime) Inspect(_ context.Context, _ string) (runtime.InstanceInfo, error) {
+	return runtime.InstanceInfo{Running: m.running}, nil
+}
+
+func (m *workCopyExecRuntime) InteractiveExec(_ context.Context, _ string, cmd []string, _ string, workDir string, streams runtime.IOStreams) error {
+	m.cmd, m.workDir = cmd, workDir
+	if _, err := io.ReadAll(streams.In); err != nil {
+		return err
+	}
+	fmt.Fprint(streams.Out, "ran\n") //nolint:errcheck // test writer
+	return m.err
+}
+
+func TestRunInWorkCopy(t *testing.T) {
+	tmpDir := t.TempDir()
+	createTestSandbox(t, tmpDir, "box", "/tmp/project", store.DirModeCopy)
+	rt := &workCopyExecRuntime{running: true}
+	e := newLifecycleMgr(&rt.lifecycleMockRuntime, tmpDir).WithRuntime(rt)
+
+	var out bytes.Buffer
+	require.NoError(t, e.RunInWorkCopy(context.Background(), "box", "", "make test", &out))
+	assert.Equal(t, []string{"sh", "-c", "make test"}, rt.cmd)
+	assert.Equal(t, "/tmp/project", rt.workDir, "the command runs in the dir's mount path")
+	assert.Equal(t, "ran\n", out.String())
+
+	rt.err = &runtime.ExecError{ExitCode: 2}
+	var ee *runtime.ExecError
+	require.ErrorAs(t, e.RunInWorkCopy(context.Background(), "box", "", "make test", io.Discard), &ee)
+	assert.Equal(t, 2, ee.ExitCode)
+
+	err := e.RunInWorkCopy(context.Background(), "box", "/not/tracked", "make test", io.Discard)
+	var usage *yoerrors.UsageError
+	assert.ErrorAs(t, err, &usage)
+}
+
+func TestRunInWorkCopy_NotRunning(t *testing.T) {
+	tmpDir := t.TempDir()
+	createTestSandbox(t, tmpDir, "box", "/tmp/project", store.DirModeCopy)
+	rt := &workCopyExecRuntime{}
+	e := newLifecycleMgr(&rt.lifecycleMockRuntime, tmpDir).WithRuntime(rt)
+
+	err := e.RunInWorkCopy(context.Background(), "box", "", "make test", io.Discard)
+	require.ErrorIs(t, err, ErrContainerNotRunning)
+	assert.Nil(t, rt.cmd, "nothing runs in a stopped sandbox")
+}
+
+func TestProjectChangelog(t *testing.T) {
+	e, host := changelogEngine(t)
+
+	cfg, err := e.ProjectChangelog("box", "")
+	require.NoError(t, err)
+	assert.Nil(t, cfg, "nothing configured")
+
+	require.NoError(t, os.WriteFile(filepath.Join(host, "towncrier.toml"), []byte("[tool.towncrier]\ndirectory = \"changes\"\n"), 0o600))
+	cfg, err = e.ProjectChangelog("box", "")
+	require.NoError(t, err)
+	require.NotNil(t, cfg)
+	assert.Equal(t, "changes", cfg.Dir, "towncrier's directory")
+
+	require.NoError(t, os.WriteFile(filepath.Join(host, ".yoloai.yaml"), []byte("changelog:\n  dir: changelog.d\n"), 0o600))
+	cfg, err = e.ProjectChangelog("box", "")
+	require.NoError(t, err)
+	assert.Equal(t, "changelog.d", cfg.Dir, ".yoloai.yaml wins")
+}
+
+func TestChangelogEntry(t *testing.T) {
+	e, _ := changelogEngine(t)
+	sandboxDir := e.layout.SandboxDir("box")
+	one := []copyflow.AppliedCommit{{Subject: "Fix the parser"}}
+
+	assert.Equal(t, "Changes from sandbox box.", e.ChangelogEntry("box", nil))
+	assert.Equal(t, "Fix the parser", e.ChangelogEntry("box", one))
+
+	require.NoError(t, os.WriteFile(store.PromptFilePath(sandboxDir), []byte("\nMake the parser accept tabs\n\nDetails follow.\n"), 0o600))
+	assert.Equal(t, "Make the parser accept tabs", e.ChangelogEntry("box", one), "the prompt beats a commit subject")
+
+	require.NoError(t, os.MkdirAll(filepath.Dir(store.ResultFilePath(sandboxDir)), 0o750))
+	require.NoError(t, os.WriteFile(store.ResultFilePath(sandboxDir), []byte(`{"status":"success","summary":"Parser accepts tabs as indentation"}`), 0o600))
+	assert.Equal(t, "Parser accepts tabs as indentation", e.ChangelogEntry("box", one), "the agent's summary comes first")
+}
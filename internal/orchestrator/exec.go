@@ -1,12 +1,17 @@
 // ABOUTME: Engine-level exec verbs — interactive (PTY) and stdio-piped command
-// ABOUTME: execution inside a sandbox, plus the raw container-log tail.
+// ABOUTME: execution inside a sandbox, a work-copy command for apply's verify gate,
+// ABOUTME: the raw container-log tail, and the confinement's refused-write report
+// ABOUTME: and CPU/memory use.
 
 package orchestrator
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
+	"strings"
+	"time"
 
 	"github.com/kstenerud/yoloai/runtime"
 	"github.com/kstenerud/yoloai/store"
@@ -34,6 +39,30 @@ func (e *Engine) InteractiveExec(ctx context.Context, name string, cmd []string,
 		user, info.Environment.Workdir().MountPath, io)
 }
 
+// RunInWorkCopy runs a shell command inside the sandbox with the tracked dir's
+// work copy (dirHostPath, "" = workdir) as its working directory, with no
+// stdin and stdout and stderr both going to out. The sandbox must be Active or
+// Idle. A non-zero exit surfaces as the runtime's *ExecError.
+func (e *Engine) RunInWorkCopy(ctx context.Context, name, dirHostPath, command string, out io.Writer) error {
+	if err := e.ensure(ctx); err != nil {
+		return err
+	}
+	info, err := e.Inspect(ctx, name)
+	if err != nil {
+		return err
+	}
+	if info.Status != StatusActive && info.Status != StatusIdle {
+		return fmt.Errorf("sandbox %q: %w", name, ErrContainerNotRunning)
+	}
+	dir := info.Environment.Dir(dirHostPath)
+	if dir == nil {
+		return yoerrors.NewUsageError("no tracked directory %q in sandbox %q", dirHostPath, name)
+	}
+	user := ContainerUser(info.Environment, e.layout.HostUID)
+	return e.runtime.InteractiveExec(ctx, store.InstanceName(e.layout.Principal, name), []string{"sh", "-c", command},
+		user, dir.MountPath, runtime.IOStreams{In: strings.NewReader(""), Out: out, Err: out})
+}
+
 // StdioExec runs cmd inside the sandbox's container with raw stdio piped to the
 // supplied reader/writers (no PTY) — the line-oriented shape the MCP proxy
 // bridges JSON-RPC over. Returns a *UsageError when the backend doesn't
@@ -60,3 +89,28 @@ func (e *Engine) ContainerLogs(ctx context.Context, name string, tailLines int)
 	}
 	return runtime.LogsFor(ctx, e.runtime, store.InstanceName(e.layout.Principal, name), tailLines)
 }
+
+// DeniedWrites returns the writes the sandbox's confinement refused since the
+// given time. supported is false when the backend does not report refused
+// writes (only seatbelt does; container and VM backends confine by giving the
+// agent its own filesystem).
+func (e *Engine) DeniedWrites(ctx context.Context, name string, since time.Time) (denied []runtime.DeniedWrite, supported bool, err error) {
+	if err := e.ensure(ctx); err != nil {
+		return nil, false, err
+	}
+	return runtime.DeniedWritesFor(ctx, e.runtime, store.InstanceName(e.layout.Principal, name), since)
+}
+
+// Usage returns the running sandbox's CPU and memory use. supported is false
+// when the backend can't measure it (see runtime.UsageReporter). A sandbox
+// whose instance is stopped or gone reports ErrContainerNotRunning.
+func (e *Engine) Usage(ctx context.Context, name string) (usage runtime.ResourceUsage, supported bool, err error) {
+	if err := e.ensure(ctx); err != nil {
+		return runtime.ResourceUsage{}, false, err
+	}
+	usage, supported, err = runtime.UsageFor(ctx, e.runtime, store.InstanceName(e.layout.Principal, name))
+	if errors.Is(err, runtime.ErrNotRunning) || errors.Is(err, runtime.ErrNotFound) {
+		return runtime.ResourceUsage{}, supported, ErrContainerNotRunning
+	}
+	return usage, supported, err
+}
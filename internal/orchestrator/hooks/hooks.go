@@ -0,0 +1,125 @@
+// ABOUTME: User hook scripts run at sandbox lifecycle points (pre/post-create,
+// ABOUTME: pre/post-apply): <data dir>/hooks/<event>, then the profile's hooks/<event>.
+
+package hooks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log/slog"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/kstenerud/yoloai/internal/config"
+	"github.com/kstenerud/yoloai/internal/sysexec"
+)
+
+// Event is a lifecycle point a hook can run at. The hook script is a file
+// named after the event.
+type Event string
+
+const (
+	// PreCreate runs before a sandbox is created; a failure stops the create.
+	PreCreate Event = "pre-create"
+	// PostCreate runs once the sandbox and its work copies exist, before the
+	// agent starts.
+	PostCreate Event = "post-create"
+	// PreApply runs before changes land on the host; a failure stops the apply.
+	PreApply Event = "pre-apply"
+	// PostApply runs in the directory the changes landed in.
+	PostApply Event = "post-apply"
+)
+
+// dirName is the hooks directory under the data dir and under a profile.
+const dirName = "hooks"
+
+// outputTailLines is how much of a failed hook's output its error carries.
+const outputTailLines = 20
+
+// Sandbox is the sandbox metadata a hook receives, as YOLOAI_* variables.
+type Sandbox struct {
+	Name     string // YOLOAI_SANDBOX
+	Dir      string // YOLOAI_SANDBOX_DIR: its state directory
+	Profile  string // YOLOAI_PROFILE
+	Agent    string // YOLOAI_AGENT
+	Model    string // YOLOAI_MODEL
+	Backend  string // YOLOAI_BACKEND
+	Workdir  string // YOLOAI_WORKDIR: the host directory the sandbox works on
+	WorkCopy string // YOLOAI_WORK_COPY: the sandbox's :copy of it on the host
+}
+
+// Vars returns s as hook variables, leaving out the empty ones.
+func (s Sandbox) Vars() map[string]string {
+	vars := map[string]string{}
+	for k, v := range map[string]string{
+		"YOLOAI_SANDBOX":     s.Name,
+		"YOLOAI_SANDBOX_DIR": s.Dir,
+		"YOLOAI_PROFILE":     s.Profile,
+		"YOLOAI_AGENT":       s.Agent,
+		"YOLOAI_MODEL":       s.Model,
+		"YOLOAI_BACKEND":     s.Backend,
+		"YOLOAI_WORKDIR":     s.Workdir,
+		"YOLOAI_WORK_COPY":   s.WorkCopy,
+	} {
+		if v != "" {
+			vars[k] = v
+		}
+	}
+	return vars
+}
+
+// Dirs returns the directories searched for hooks, in run order: the global
+// <data dir>/hooks, then the hooks directory of profile ("" = no profile).
+func Dirs(layout config.Layout, profile string) []string {
+	dirs := []string{filepath.Join(layout.DataDir, dirName)}
+	if profile != "" && profile != "base" {
+		dirs = append(dirs, filepath.Join(config.ProfileSourceDir(layout, profile), dirName))
+	}
+	return dirs
+}
+
+// Run runs event's hooks from Dirs(layout, profile), one after another, with
+// workDir as the working directory. Each gets the user's environment plus
+// YOLOAI_HOOK=<event> and vars, and writes its output to out (nil discards
+// it). A missing hook is skipped; one that isn't executable is an error, as
+// is a non-zero exit, which stops the remaining hooks. A failure's error
+// carries the tail of the hook's output.
+func Run(ctx context.Context, layout config.Layout, event Event, profile, workDir string, vars map[string]string, out io.Writer) error {
+	if out == nil {
+		out = io.Discard
+	}
+	env := append(layout.Env().PassthroughEnv(), "YOLOAI_HOOK="+string(event))
+	for _, k := range slices.Sorted(maps.Keys(vars)) {
+		env = append(env, k+"="+vars[k])
+	}
+	for _, dir := range Dirs(layout, profile) {
+		path := filepath.Join(dir, string(event))
+		info, err := os.Stat(path)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("%s hook: %w", event, err)
+		}
+		if info.IsDir() || info.Mode()&0o111 == 0 {
+			return fmt.Errorf("%s hook %s is not an executable file (chmod +x it)", event, path)
+		}
+
+		slog.Info("running hook", "event", "hook.run", "hook", string(event), "path", path)
+		tail := sysexec.NewTailBuffer(outputTailLines)
+		w := io.MultiWriter(out, tail)
+		cmd := sysexec.CommandContext(ctx, env, path)
+		cmd.Dir = workDir
+		cmd.Stdout = w
+		cmd.Stderr = w
+		if err := cmd.Run(); err != nil {
+			return fmt.Errorf("%s hook %s failed: %w%s", event, path, err, tail.ErrorSuffix())
+		}
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+// ABOUTME: Tests for Run: global-then-profile order, the YOLOAI_* variables and
+// ABOUTME: working directory a hook gets, and missing, failing and non-executable hooks.
+
+package hooks
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kstenerud/yoloai/internal/config"
+)
+
+func testLayout(t *testing.T) config.Layout {
+	t.Helper()
+	return config.Layout{DataDir: t.TempDir()}.WithEnv(map[string]string{"PATH": "/bin:/usr/bin"})
+}
+
+func writeHook(t *testing.T, dir string, event Event, body string) {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(dir, 0o750))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, string(event)), []byte("#!/bin/sh\n"+body), 0o755)) //nolint:gosec // G306: a hook must be executable
+}
+
+func TestRun_GlobalThenProfile(t *testing.T) {
+	layout := testLayout(t)
+	writeHook(t, filepath.Join(layout.DataDir, "hooks"), PostApply, "echo global $YOLOAI_HOOK $YOLOAI_SANDBOX $(pwd)\n")
+	writeHook(t, filepath.Join(layout.ProfileDir("go-dev"), "hooks"), PostApply, "echo profile $YOLOAI_APPLY_TARGET\n")
+	workDir := t.TempDir()
+
+	var out strings.Builder
+	vars := Sandbox{Name: "task"}.Vars()
+	vars["YOLOAI_APPLY_TARGET"] = "/src"
+	require.NoError(t, Run(context.Background(), layout, PostApply, "go-dev", workDir, vars, &out))
+
+	realWorkDir, err := filepath.EvalSymlinks(workDir)
+	require.NoError(t, err)
+	assert.Equal(t, "global post-apply task "+realWorkDir+"\nprofile /src\n", out.String())
+}
+
+func TestRun_NoHooks(t *testing.T) {
+	layout := testLayout(t)
+	require.NoError(t, Run(context.Background(), layout, PreCreate, "go-dev", t.TempDir(), nil, nil))
+}
+
+func TestRun_FailureStopsAndCarriesOutput(t *testing.T) {
+	layout := testLayout(t)
+	writeHook(t, filepath.Join(layout.DataDir, "hooks"), PreApply, "echo not today\nexit 3\n")
+	writeHook(t, filepath.Join(layout.ProfileDir("go-dev"), "hooks"), PreApply, "echo ran\n")
+
+	var out strings.Builder
+	err := Run(context.Background(), layout, PreApply, "go-dev", t.TempDir(), nil, &out)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "pre-apply hook")
+	assert.Contains(t, err.Error(), "exit status 3")
+	assert.Contains(t, err.Error(), "not today")
+	assert.NotContains(t, out.String(), "ran", "a failed hook stops the ones after it")
+}
+
+func TestRun_NotExecutable(t *testing.T) {
+	layout := testLayout(t)
+	dir := filepath.Join(layout.DataDir, "hooks")
+	require.NoError(t, os.MkdirAll(dir, 0o750))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, string(PostCreate)), []byte("#!/bin/sh\n"), 0o600))
+
+	err := Run(context.Background(), layout, PostCreate, "", t.TempDir(), nil, nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "chmod +x")
+}
+
+func TestSandboxVars_OmitsEmpty(t *testing.T) {
+	assert.Equal(t, map[string]string{
+		"YOLOAI_SANDBOX": "task",
+		"YOLOAI_AGENT":   "claude",
+	}, Sandbox{Name: "task", Agent: "claude"}.Vars())
+}
@@ -18,6 +18,7 @@ const (
 	StatusFailed      = status.StatusFailed      // agent exited with error (non-zero)
 	StatusStopped     = status.StatusStopped     // container stopped
 	StatusSuspended   = status.StatusSuspended   // VM suspended (Tart only)
+	StatusPaused      = status.StatusPaused      // container running but frozen in memory
 	StatusRemoved     = status.StatusRemoved     // container removed but sandbox dir exists
 	StatusBroken      = status.StatusBroken      // sandbox dir exists but environment.json missing/invalid
 	StatusUnavailable = status.StatusUnavailable // backend not running
@@ -76,6 +77,13 @@ var ListSandboxes = status.ListSandboxes
 // ListSandboxesMultiBackend inspects sandboxes per their backends. See status.ListSandboxesMultiBackend.
 var ListSandboxesMultiBackend = status.ListSandboxesMultiBackend
 
+// ListSandboxesMultiBackendComplete is ListSandboxesMultiBackend without the
+// per-sandbox timeout. See status.ListSandboxesMultiBackendComplete.
+var ListSandboxesMultiBackendComplete = status.ListSandboxesMultiBackendComplete
+
+// ListSandboxesWithRuntimes lists across backends with caller-owned runtimes. See status.ListSandboxesWithRuntimes.
+var ListSandboxesWithRuntimes = status.ListSandboxesWithRuntimes
+
 // IsolationPerms is re-exported from store. See store.IsolationPerms.
 type IsolationPerms = store.IsolationPerms
 
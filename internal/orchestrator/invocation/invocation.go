@@ -5,16 +5,17 @@ package invocation
 import (
 	"fmt"
 	"io"
-	"os"
 	"strings"
 
 	"github.com/kstenerud/yoloai/internal/agent"
 	"github.com/kstenerud/yoloai/internal/config"
+	"github.com/kstenerud/yoloai/internal/fileutil"
 	"github.com/kstenerud/yoloai/yoerrors"
 )
 
-// ResolveModel expands a model alias. User-configured aliases (from
-// config.yaml model_aliases) take priority over agent built-in aliases.
+// ResolveModel expands a model alias. User-configured aliases (config.yaml
+// model_aliases, with a profile's and the project's .yoloai.yaml merged over
+// them) take priority over agent built-in aliases.
 func ResolveModel(agentDef *agent.Definition, model string, userAliases map[string]string) string {
 	if model == "" {
 		return ""
@@ -240,6 +241,7 @@ func ResolveFallToShell(_ agent.IdleSupport, headless bool) bool {
 }
 
 // ReadPrompt reads the prompt from --prompt, --prompt-file, or stdin ("-").
+// Line endings are normalized to LF whatever the source (see cleanPrompt).
 // homeDir is used to expand leading "~" in the promptFile path. stdin is the
 // reader the "-" sentinel pulls from — threaded from the Engine's input
 // (the CLI wires os.Stdin there; embedders supply their own), so the library
@@ -256,11 +258,11 @@ func ReadPrompt(prompt, promptFile, homeDir string, env map[string]string, stdin
 		if err != nil {
 			return "", fmt.Errorf("read prompt from stdin: %w", err)
 		}
-		return strings.TrimSpace(string(data)), nil
+		return cleanPrompt(string(data)), nil
 	}
 
 	if prompt != "" {
-		return prompt, nil
+		return fileutil.NormalizeNewlines(prompt), nil
 	}
 
 	if promptFile == "-" {
@@ -268,7 +270,7 @@ func ReadPrompt(prompt, promptFile, homeDir string, env map[string]string, stdin
 		if err != nil {
 			return "", fmt.Errorf("read prompt from stdin: %w", err)
 		}
-		return strings.TrimSpace(string(data)), nil
+		return cleanPrompt(string(data)), nil
 	}
 
 	if promptFile != "" {
@@ -276,16 +278,24 @@ func ReadPrompt(prompt, promptFile, homeDir string, env map[string]string, stdin
 		if err != nil {
 			return "", fmt.Errorf("expand prompt file path: %w", err)
 		}
-		data, err := os.ReadFile(promptFile) //nolint:gosec // G304: path is from user-provided --prompt-file flag
+		data, err := fileutil.ReadTextFile(promptFile)
 		if err != nil {
 			return "", fmt.Errorf("read prompt file: %w", err)
 		}
-		return strings.TrimSpace(string(data)), nil
+		return cleanPrompt(data), nil
 	}
 
 	return "", nil
 }
 
+// cleanPrompt normalizes prompt text read from a file or stdin: CRLF and
+// lone-CR line endings become LF (a prompt saved by a Windows editor must not
+// carry carriage returns into the agent command or prompt.txt), a leading
+// UTF-8 byte-order mark is dropped, and surrounding whitespace is trimmed.
+func cleanPrompt(s string) string {
+	return strings.TrimSpace(fileutil.NormalizeNewlines(strings.TrimPrefix(s, "\ufeff")))
+}
+
 // shellEscapeForDoubleQuotes escapes a string for embedding inside
 // double quotes in a shell command.
 func shellEscapeForDoubleQuotes(s string) string {
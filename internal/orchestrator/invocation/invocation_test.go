@@ -68,13 +68,20 @@ func TestBuildAgentCommand_InteractiveWithPassthrough(t *testing.T) {
 func TestBuildAgentCommand_HeadlessWithPrompt(t *testing.T) {
 	agentDef := agent.GetAgent("test")
 	result := BuildAgentCommand(agentDef, "", "echo hello", "", nil, false)
-	assert.Equal(t, `sh -c "echo hello"`, result)
+	assert.Equal(t, `python3 "${YOLOAI_DIR:-/yoloai}/bin/test-agent.py" -c "echo hello"`, result)
 }
 
 func TestBuildAgentCommand_InteractiveFallback(t *testing.T) {
 	agentDef := agent.GetAgent("test")
 	result := BuildAgentCommand(agentDef, "", "", "", nil, false)
-	assert.Equal(t, "bash", result)
+	assert.Equal(t, `python3 "${YOLOAI_DIR:-/yoloai}/bin/test-agent.py"`, result)
+}
+
+func TestBuildAgentCommand_TestAgentScenarioPassthrough(t *testing.T) {
+	// `yoloai new --agent test … -- --scenario s.json` reaches the script as an arg.
+	agentDef := agent.GetAgent("test")
+	result := BuildAgentCommand(agentDef, "", "go", "", []string{"--scenario", "s.json"}, false)
+	assert.Equal(t, `python3 "${YOLOAI_DIR:-/yoloai}/bin/test-agent.py" -c "go" --scenario s.json`, result)
 }
 
 func TestBuildAgentCommand_WithAgentArgs(t *testing.T) {
@@ -143,6 +150,23 @@ func TestReadPrompt_File(t *testing.T) {
 	assert.Equal(t, "prompt from file", result)
 }
 
+func TestReadPrompt_WindowsLineEndings(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "prompt.txt")
+	require.NoError(t, os.WriteFile(path, []byte("\ufefffix the bug\r\nthen run \"make test\"\r\n"), 0600))
+
+	result, err := ReadPrompt("", path, "/home/user", nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "fix the bug\nthen run \"make test\"", result)
+
+	result, err = ReadPrompt("-", "", "/home/user", nil, strings.NewReader("from stdin\r\nline two\r\n"))
+	require.NoError(t, err)
+	assert.Equal(t, "from stdin\nline two", result)
+
+	result, err = ReadPrompt("inline\r\ntext", "", "/home/user", nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "inline\ntext", result)
+}
+
 func TestReadPrompt_MutualExclusion(t *testing.T) {
 	_, err := ReadPrompt("hello", "/some/file", "/home/user", nil, nil)
 	require.Error(t, err)
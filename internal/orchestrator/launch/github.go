@@ -0,0 +1,89 @@
+// ABOUTME: The read-only GitHub token a sandbox gets from the github global
+// ABOUTME: config key: minted from a GitHub App, or taken from a host env var.
+package launch
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/kstenerud/yoloai/internal/config"
+	"github.com/kstenerud/yoloai/internal/credential"
+)
+
+// githubTokenVars are where the read-only token is delivered: gh reads
+// GH_TOKEN first, most other tooling GITHUB_TOKEN.
+var githubTokenVars = []string{"GH_TOKEN", "GITHUB_TOKEN"}
+
+// githubTokenEnv resolves the github config key into the env vars that carry
+// its token into the sandbox, or nil when the key is unset. An app token is
+// minted here, at every launch, with GitHubReadOnlyPermissions; it lasts an
+// hour, so a restart is what renews it. A token_env token is passed as is —
+// its scope is whatever its owner gave it.
+func githubTokenEnv(ctx context.Context, layout config.Layout) (map[string]string, error) {
+	gcfg, err := config.LoadGlobalConfig(layout)
+	if err != nil {
+		return nil, err
+	}
+	gh := gcfg.GitHub
+	if gh == nil {
+		return nil, nil
+	}
+	if err := gh.Validate(); err != nil {
+		return nil, err
+	}
+
+	var token string
+	if gh.UsesApp() {
+		keyPath, err := config.ExpandPath(gh.PrivateKey, layout.HomeDir, layout.Env().EnvForConfigInterpolation())
+		if err != nil {
+			return nil, fmt.Errorf("github.private_key: %w", err)
+		}
+		pemData, err := os.ReadFile(keyPath) //nolint:gosec // G304: user-configured key path
+		if err != nil {
+			return nil, fmt.Errorf("github.private_key: %w", err)
+		}
+		key, err := credential.ParseGitHubAppKey(pemData)
+		if err != nil {
+			return nil, err
+		}
+		app := credential.GitHubApp{
+			AppID:          gh.AppID,
+			InstallationID: gh.InstallationID,
+			Key:            key,
+			APIURL:         gh.APIURL,
+			Permissions:    credential.GitHubReadOnlyPermissions,
+		}
+		if token, _, err = app.Fetch(ctx); err != nil {
+			return nil, err
+		}
+	} else {
+		token = layout.Env().EnvForAgentCredentials([]string{gh.TokenEnv})[gh.TokenEnv]
+		if token == "" {
+			return nil, fmt.Errorf("github.token_env: $%s is not set", gh.TokenEnv)
+		}
+	}
+
+	env := make(map[string]string, len(githubTokenVars))
+	for _, name := range githubTokenVars {
+		env[name] = token
+	}
+	return env, nil
+}
+
+// addGitHubToken merges the read-only GitHub token into secretEnv without
+// replacing a GH_TOKEN/GITHUB_TOKEN the sandbox already gets (an env config
+// entry, or an agent's forwarded credential). Failing to get the token warns
+// and launches without it: a GitHub outage shouldn't keep sandboxes down.
+func addGitHubToken(ctx context.Context, layout config.Layout, secretEnv map[string]string, warn func(string)) {
+	env, err := githubTokenEnv(ctx, layout)
+	if err != nil {
+		warn(fmt.Sprintf("no read-only GitHub token for this sandbox: %v", err))
+		return
+	}
+	for k, v := range env {
+		if _, taken := secretEnv[k]; !taken {
+			secretEnv[k] = v
+		}
+	}
+}
@@ -0,0 +1,85 @@
+// ABOUTME: Tests for the read-only GitHub token: minted from an app against a
+// ABOUTME: fake API, read from a host env var, and never clobbering a set var.
+package launch
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kstenerud/yoloai/internal/config"
+	"github.com/kstenerud/yoloai/internal/credential"
+)
+
+func githubLayout(t *testing.T, globalYAML string, env map[string]string) config.Layout {
+	t.Helper()
+	layout := config.NewLayout(t.TempDir()).WithEnv(env)
+	require.NoError(t, os.MkdirAll(filepath.Dir(layout.GlobalConfigPath()), 0750))
+	require.NoError(t, os.WriteFile(layout.GlobalConfigPath(), []byte(globalYAML), 0600))
+	return layout
+}
+
+func TestGitHubTokenEnv_Unset(t *testing.T) {
+	env, err := githubTokenEnv(context.Background(), githubLayout(t, "tmux_conf: host\n", nil))
+	require.NoError(t, err)
+	assert.Nil(t, env)
+}
+
+func TestGitHubTokenEnv_MintsReadOnlyAppToken(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	keyPath := filepath.Join(t.TempDir(), "app.pem")
+	require.NoError(t, os.WriteFile(keyPath, pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)}), 0600))
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/app/installations/99/access_tokens", r.URL.Path)
+		var body struct {
+			Permissions map[string]string `json:"permissions"`
+		}
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.Equal(t, credential.GitHubReadOnlyPermissions, body.Permissions)
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"token":"ghs_readonly","expires_at":"2030-01-01T00:00:00Z"}`))
+	}))
+	defer srv.Close()
+
+	layout := githubLayout(t, "github:\n  app_id: \"7\"\n  installation_id: \"99\"\n  private_key: "+keyPath+"\n  api_url: "+srv.URL+"\n", nil)
+	env, err := githubTokenEnv(context.Background(), layout)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"GH_TOKEN": "ghs_readonly", "GITHUB_TOKEN": "ghs_readonly"}, env)
+}
+
+func TestGitHubTokenEnv_TokenEnv(t *testing.T) {
+	layout := githubLayout(t, "github:\n  token_env: MY_RO_TOKEN\n", map[string]string{"MY_RO_TOKEN": "github_pat_ro"})
+	env, err := githubTokenEnv(context.Background(), layout)
+	require.NoError(t, err)
+	assert.Equal(t, "github_pat_ro", env["GH_TOKEN"])
+
+	_, err = githubTokenEnv(context.Background(), githubLayout(t, "github:\n  token_env: MY_RO_TOKEN\n", nil))
+	assert.ErrorContains(t, err, "MY_RO_TOKEN")
+
+	_, err = githubTokenEnv(context.Background(), githubLayout(t, "github:\n  app_id: \"7\"\n", nil))
+	assert.ErrorContains(t, err, "installation_id", "an incomplete app is reported when the token is needed")
+}
+
+func TestAddGitHubToken_KeepsExistingVars(t *testing.T) {
+	layout := githubLayout(t, "github:\n  token_env: MY_RO_TOKEN\n", map[string]string{"MY_RO_TOKEN": "ro"})
+	secretEnv := map[string]string{"GITHUB_TOKEN": "opencode-forwarded"}
+	addGitHubToken(context.Background(), layout, secretEnv, func(msg string) { t.Errorf("unexpected warning: %s", msg) })
+	assert.Equal(t, map[string]string{"GH_TOKEN": "ro", "GITHUB_TOKEN": "opencode-forwarded"}, secretEnv)
+
+	var warned string
+	addGitHubToken(context.Background(), githubLayout(t, "github:\n  token_env: UNSET\n", nil), map[string]string{}, func(msg string) { warned = msg })
+	assert.Contains(t, warned, "UNSET")
+}
@@ -11,6 +11,7 @@ import (
 	"fmt"
 	"io"
 	"log/slog"
+	"maps"
 	"net"
 	"os"
 	"path/filepath"
@@ -30,6 +31,7 @@ import (
 	mountspkg "github.com/kstenerud/yoloai/internal/orchestrator/mounts"
 	"github.com/kstenerud/yoloai/internal/orchestrator/runtimeconfig"
 	"github.com/kstenerud/yoloai/internal/orchestrator/state"
+	"github.com/kstenerud/yoloai/internal/timing"
 	"github.com/kstenerud/yoloai/runtime"
 	"github.com/kstenerud/yoloai/runtime/caps"
 	"github.com/kstenerud/yoloai/store"
@@ -47,6 +49,7 @@ const secretsConsumedTimeout = 30 * time.Second
 // recreation from environment.json.
 func LaunchContainer(ctx context.Context, d state.Deps, st *state.State) (err error) {
 	slog.Info("launching container", "event", "sandbox.create.container.launch", "sandbox", st.Name, "image", st.ImageRef)
+	timing.SetSandbox(ctx, st.Name)
 	// Use pre-merged env from state if available, otherwise load from config.
 	envVars := st.Env
 	if envVars == nil {
@@ -66,6 +69,9 @@ func LaunchContainer(ctx context.Context, d state.Deps, st *state.State) (err er
 	// — so both paths broker identically (D105/D106).
 	spec := envspec.BuildEnvSpec(st.Agent)
 	secretEnv := envsetup.ResolveSecretEnv(spec, envVars, st.Layout)
+	addGitHubToken(ctx, st.Layout, secretEnv, func(msg string) {
+		fmt.Fprintf(outputOr(st.Output), "Warning: %s\n", msg) //nolint:errcheck // best-effort output
+	})
 	bro, err := brokerCredentials(ctx, d.Runtime, st, secretEnv)
 	if err != nil {
 		return err
@@ -113,6 +119,14 @@ func LaunchContainer(ctx context.Context, d state.Deps, st *state.State) (err er
 		secretEnv = nil // legacy delivers via the bind-mounted files, not ProcSpec.Env
 	}
 
+	// Sandboxes created before the prompt inbox and artifact outbox existed lack
+	// their directories, and the bind mounts need a source.
+	for _, dir := range []string{store.InboxDir(st.SandboxDir), store.OutboxDir(st.SandboxDir)} {
+		if err = fileutil.MkdirAll(dir, 0750); err != nil {
+			return fmt.Errorf("create %s: %w", dir, err)
+		}
+	}
+
 	mnts := mountspkg.Build(st, secretsDir) // secretsDir=="" on the launch path -> no /run/secrets mount
 
 	ports, err := parsePortBindings(st.Ports)
@@ -265,18 +279,18 @@ func startViaLaunch(ctx context.Context, rt runtime.Backend, launcher runtime.Pr
 	readyPath := filepath.Join(st.SandboxDir, store.SubstrateReadyMarker)
 	_ = os.Remove(readyPath)
 
-	if err := rt.Create(ctx, instanceCfg); err != nil {
-		return gvisorStartHint(st.Isolation, err)
-	}
-	if err := rt.Start(ctx, cname); err != nil {
-		return fmt.Errorf("start instance: %w", gvisorStartHint(st.Isolation, err))
+	if err := createAndStart(ctx, rt, st, cname, instanceCfg); err != nil {
+		return err
 	}
 
 	// The box must finish root provisioning before we launch the session-runner
 	// over it; otherwise the runner is killed mid-setup (DF44 readiness race).
 	// The substrate owns the readiness signal (launcher.Ready); we own the wait
 	// policy.
-	if err := waitForReady(ctx, launcher, cname, effectiveSecretsConsumedTimeout(rt.Descriptor())); err != nil {
+	stopBoot := timing.Track(ctx, timing.PhaseBoot)
+	err := waitForReady(ctx, launcher, cname, effectiveSecretsConsumedTimeout(rt.Descriptor()))
+	stopBoot()
+	if err != nil {
 		return err
 	}
 
@@ -308,7 +322,7 @@ func startViaLaunch(ctx context.Context, rt runtime.Backend, launcher runtime.Pr
 		env = append(env, "YOLOAI_SECRET_KEYS="+strings.Join(keys, ","))
 	}
 
-	_, err := launcher.Launch(ctx, cname, runtime.ProcSpec{
+	_, err = launcher.Launch(ctx, cname, runtime.ProcSpec{
 		Argv:     []string{"sh", "-c", "exec python3 /yoloai/bin/sandbox-setup.py docker >> /yoloai/logs/session-runner.log 2>&1"},
 		User:     "yoloai",
 		Cwd:      WorkdirMountPath(st.Workdir),
@@ -322,7 +336,9 @@ func startViaLaunch(ctx context.Context, rt runtime.Backend, launcher runtime.Pr
 	// The secrets marker is now written by the launched runner, not the
 	// entrypoint — so we wait here, after Launch, not after Start.
 	if hasSecrets {
+		stopBoot := timing.Track(ctx, timing.PhaseBoot)
 		waitForSecretsConsumed(markerPath, effectiveSecretsConsumedTimeout(rt.Descriptor()))
+		stopBoot()
 	}
 	return nil
 }
@@ -785,11 +801,8 @@ func applyBrokerEnv(secretEnv map[string]string, bc *agent.BrokerConfig, reach r
 // entrypoint (which runs sandbox-setup.py inline) to consume secrets.
 // No keepalive_only patch; the agent is welded into the entrypoint as before.
 func startLegacy(ctx context.Context, rt runtime.Backend, st *state.State, cname string, instanceCfg runtime.InstanceConfig, markerPath string, hasSecrets bool) error {
-	if err := rt.Create(ctx, instanceCfg); err != nil {
-		return gvisorStartHint(st.Isolation, err)
-	}
-	if err := rt.Start(ctx, cname); err != nil {
-		return fmt.Errorf("start instance: %w", gvisorStartHint(st.Isolation, err))
+	if err := createAndStart(ctx, rt, st, cname, instanceCfg); err != nil {
+		return err
 	}
 	// Wait for the entrypoint to signal it has read /run/secrets before the
 	// caller removes the host-side secrets temp dir. A fixed sleep used to
@@ -800,7 +813,22 @@ func startLegacy(ctx context.Context, rt runtime.Backend, st *state.State, cname
 	// the host observes the marker before removing the dir, rather than timing
 	// out mid-boot and relying on VirtioFS deletion lag to dodge the race.
 	if hasSecrets {
+		stopBoot := timing.Track(ctx, timing.PhaseBoot)
 		waitForSecretsConsumed(markerPath, effectiveSecretsConsumedTimeout(rt.Descriptor()))
+		stopBoot()
+	}
+	return nil
+}
+
+// createAndStart creates the instance and starts it, the "create" phase of
+// --timings on both bring-up paths.
+func createAndStart(ctx context.Context, rt runtime.Backend, st *state.State, cname string, instanceCfg runtime.InstanceConfig) error {
+	defer timing.Track(ctx, timing.PhaseCreate)()
+	if err := rt.Create(ctx, instanceCfg); err != nil {
+		return gvisorStartHint(st.Isolation, err)
+	}
+	if err := rt.Start(ctx, cname); err != nil {
+		return fmt.Errorf("start instance: %w", gvisorStartHint(st.Isolation, err))
 	}
 	return nil
 }
@@ -829,6 +857,41 @@ func patchKeepaliveOnly(sandboxDir string, keepalive bool) error {
 	return nil
 }
 
+// localeEnv returns the container's LANG and TZ: the values resolved at create
+// (config, else the host's), recorded in environment.json so a recreated
+// container keeps them. LANG falls back to C.UTF-8, which is always present
+// without locale-gen; without a UTF-8 locale apps like Claude Code render
+// ASCII-only. With no TZ the container runs in UTC.
+func localeEnv(meta *store.Environment) []string {
+	lang := "C.UTF-8"
+	if meta != nil && meta.Locale != "" {
+		lang = meta.Locale
+	}
+	env := []string{"LANG=" + lang}
+	if meta != nil && meta.Timezone != "" {
+		env = append(env, "TZ="+meta.Timezone)
+	}
+	return env
+}
+
+// fakeTimeLib is where the base image links libfaketime (see its Dockerfile).
+const fakeTimeLib = "/usr/local/lib/libfaketime.so.1"
+
+// fakeTimeEnv returns the env that puts every process in the container on the
+// sandbox's faked clock, or nil when it has none. Monotonic clocks are left
+// real: faking them stalls event loops and tmux's timers, and nothing
+// date-dependent reads them.
+func fakeTimeEnv(meta *store.Environment) []string {
+	if meta == nil || meta.FakeTime == "" {
+		return nil
+	}
+	return []string{
+		"LD_PRELOAD=" + fakeTimeLib,
+		"FAKETIME=" + meta.FakeTime,
+		"FAKETIME_DONT_FAKE_MONOTONIC=1",
+	}
+}
+
 // buildInstanceConfig constructs the runtime.InstanceConfig from sandbox state.
 // bro carries the broker's network-mode override (rootless podman → slirp; only
 // set on open networking) and the injector endpoint, which is published to the
@@ -856,8 +919,7 @@ func buildInstanceConfig(desc runtime.BackendDescriptor, st *state.State, mnts [
 		networkMode = bro.NetworkMode
 	}
 
-	// C.UTF-8 is always present without locale-gen; without it apps like Claude Code render ASCII-only.
-	containerEnv := []string{"LANG=C.UTF-8"}
+	containerEnv := append(localeEnv(st.Environment), fakeTimeEnv(st.Environment)...)
 	// Publish the injector endpoint so the entrypoint can allowlist it under
 	// network isolation (the agent's LLM egress collapses to the injector). Only
 	// consumed when network_isolated; harmless on open networking.
@@ -881,7 +943,7 @@ func buildInstanceConfig(desc runtime.BackendDescriptor, st *state.State, mnts [
 		Ports:        ports,
 		NetworkMode:  networkMode,
 		UseInit:      true,
-		Labels:       instanceLabels(st.Layout.Principal, st.Name),
+		Labels:       instanceLabels(st.Layout.Principal, st.Name, descriptiveLabels(st)),
 		ContainerEnv: containerEnv,
 	}
 
@@ -914,14 +976,36 @@ func buildInstanceConfig(desc runtime.BackendDescriptor, st *state.State, mnts [
 }
 
 // instanceLabels builds the runtime instance labels recording sandbox identity
-// and the owning principal. Both are always set: every principal is non-empty
-// (D126), so there is no default to elide and no unlabelled instance for a sweep
-// to have to guess about (runtime.IsOrphanCandidate, D62).
-func instanceLabels(principal config.PrincipalSegment, name string) map[string]string {
-	return map[string]string{
-		runtime.LabelSandbox:   name,
-		runtime.LabelPrincipal: string(principal),
+// and the owning principal, plus any extra descriptive labels. Both identity
+// labels are always set: every principal is non-empty (D126), so there is no
+// default to elide and no unlabelled instance for a sweep to have to guess about
+// (runtime.IsOrphanCandidate, D62). Identity wins over a colliding extra key.
+func instanceLabels(principal config.PrincipalSegment, name string, extra map[string]string) map[string]string {
+	labels := make(map[string]string, len(extra)+2)
+	maps.Copy(labels, extra)
+	labels[runtime.LabelSandbox] = name
+	labels[runtime.LabelPrincipal] = string(principal)
+	return labels
+}
+
+// descriptiveLabels builds the labels that describe what a sandbox is — the
+// yoloai version that created it, its profile, and a hash of its workdir — so
+// the runtime side can be reconciled with the sandbox dir (or inspected by
+// third-party tooling) without reading the sandbox dir. Prune describes
+// orphans with them and `system recover` reads them back. Empty values are
+// omitted rather than stamped as "".
+func descriptiveLabels(st *state.State) map[string]string {
+	labels := map[string]string{}
+	if st.Environment != nil && st.Environment.YoloaiVersion != "" {
+		labels[runtime.LabelVersion] = st.Environment.YoloaiVersion
+	}
+	if st.Profile != "" {
+		labels[runtime.LabelProfile] = st.Profile
 	}
+	if st.Workdir != nil && st.Workdir.Path != "" {
+		labels[runtime.LabelWorkdir] = runtime.WorkdirHash(st.Workdir.Path)
+	}
+	return labels
 }
 
 // effectiveSecretsConsumedTimeout is the host's cap on waiting for the
@@ -1192,12 +1276,42 @@ func parseResourceLimits(rl *config.ResourceLimits) (*runtime.ResourceLimits, er
 		result.Memory = mem
 	}
 
-	if result.NanoCPUs == 0 && result.Memory == 0 {
+	disk, err := config.ParseDiskSize(rl.Disk)
+	if err != nil {
+		return nil, err
+	}
+	result.DiskBytes = disk
+
+	if result.NanoCPUs == 0 && result.Memory == 0 && result.DiskBytes == 0 {
 		return nil, nil
 	}
 	return result, nil
 }
 
+// CheckDiskLimit refuses a sandbox whose directory (work copies included,
+// wherever --work-root put them) is over its resources.disk limit. No limit,
+// or a directory that can't be measured, passes: the check guards against a
+// runaway sandbox and must not itself keep one from starting.
+func CheckDiskLimit(sandboxDir, name string, rl *config.ResourceLimits) error {
+	if rl == nil || rl.Disk == "" {
+		return nil
+	}
+	limit, err := config.ParseDiskSize(rl.Disk)
+	if err != nil {
+		return err
+	}
+	usage, err := store.MeasureSandbox(sandboxDir)
+	if err != nil {
+		slog.Warn("could not measure sandbox for resources.disk", "event", "sandbox.disk.measure_failed", "sandbox", name, "error", err)
+		return nil
+	}
+	if used := usage.Total(); used > limit {
+		return yoerrors.NewUsageError("sandbox %s uses %s, over its resources.disk limit of %s (see 'yoloai du %s' for what is using it)",
+			name, runtime.FormatBytes(used), runtime.FormatBytes(limit), name)
+	}
+	return nil
+}
+
 // parseMemoryString parses a Docker-style memory string (e.g., "512m", "8g")
 // into bytes. Supported suffixes: b, k, m, g (case-insensitive).
 func parseMemoryString(s string) (int64, error) {
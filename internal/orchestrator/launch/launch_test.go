@@ -1,5 +1,6 @@
 // ABOUTME: Tests for the launch package: secrets-consumed wait, port binding
-// ABOUTME: parsing, resource limit parsing, and instance config construction.
+// ABOUTME: parsing, resource limit parsing and the disk-limit check, and
+// ABOUTME: instance config construction.
 package launch
 
 import (
@@ -168,6 +169,11 @@ func TestParseResourceLimits(t *testing.T) {
 			input:   &config.ResourceLimits{Memory: "xyz"},
 			wantErr: true,
 		},
+		{
+			name:    "invalid disk",
+			input:   &config.ResourceLimits{Disk: "huge"},
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -195,6 +201,31 @@ func checkResourceLimits(t *testing.T, input *config.ResourceLimits, wantCPU, wa
 	require.Equal(t, wantMem, result.Memory, "Memory")
 }
 
+func TestParseResourceLimits_DiskOnly(t *testing.T) {
+	result, err := parseResourceLimits(&config.ResourceLimits{Disk: "20g"})
+	require.NoError(t, err)
+	require.NotNil(t, result)
+	assert.Equal(t, int64(20<<30), result.DiskBytes)
+}
+
+func TestCheckDiskLimit(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "work"), 0o750))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "work", "big"), make([]byte, 2048), 0o600))
+
+	require.NoError(t, CheckDiskLimit(dir, "sb", nil))
+	require.NoError(t, CheckDiskLimit(dir, "sb", &config.ResourceLimits{Memory: "1g"}))
+	require.NoError(t, CheckDiskLimit(dir, "sb", &config.ResourceLimits{Disk: "4k"}))
+
+	err := CheckDiskLimit(dir, "sb", &config.ResourceLimits{Disk: "1k"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "resources.disk limit")
+	assert.Contains(t, err.Error(), "yoloai du sb")
+
+	// Unmeasurable passes: the limit must not itself keep a sandbox down.
+	require.NoError(t, CheckDiskLimit(filepath.Join(dir, "gone"), "sb", &config.ResourceLimits{Disk: "1k"}))
+}
+
 func TestParseMemoryString(t *testing.T) {
 	tests := []struct {
 		input   string
@@ -286,6 +317,60 @@ func TestBuildInstanceConfig_BrokerOutcome(t *testing.T) {
 	assert.Contains(t, cfg.ContainerEnv, "YOLOAI_BROKER_INJECTOR_ENDPOINT=172.17.0.1:44115", "injector endpoint published")
 }
 
+// TestBuildInstanceConfig_LocaleEnv verifies the recorded timezone and locale
+// reach the container env, and that LANG defaults to C.UTF-8 without one.
+func TestBuildInstanceConfig_LocaleEnv(t *testing.T) {
+	st := &state.State{
+		Name:        "test",
+		Workdir:     &state.DirSpec{Path: "/project", Mode: store.DirMode("copy")},
+		Agent:       agent.GetAgent("test"),
+		Layout:      config.Layout{Principal: config.CLIPrincipal},
+		Environment: &store.Environment{},
+	}
+	desc := runtime.BackendDescriptor{Type: "mock"}
+
+	cfg, err := buildInstanceConfig(desc, st, nil, nil, brokerOutcome{}, false)
+	require.NoError(t, err)
+	assert.Contains(t, cfg.ContainerEnv, "LANG=C.UTF-8")
+	for _, e := range cfg.ContainerEnv {
+		assert.NotContains(t, e, "TZ=", "no timezone recorded: the image default (UTC) stands")
+	}
+
+	st.Environment = &store.Environment{Timezone: "Europe/Berlin", Locale: "de_DE.UTF-8"}
+	cfg, err = buildInstanceConfig(desc, st, nil, nil, brokerOutcome{}, false)
+	require.NoError(t, err)
+	assert.Contains(t, cfg.ContainerEnv, "LANG=de_DE.UTF-8")
+	assert.Contains(t, cfg.ContainerEnv, "TZ=Europe/Berlin")
+	assert.NotContains(t, cfg.ContainerEnv, "LANG=C.UTF-8")
+}
+
+// TestBuildInstanceConfig_FakeTimeEnv verifies a recorded faketime preloads
+// libfaketime into the container, and that none leaves the env alone.
+func TestBuildInstanceConfig_FakeTimeEnv(t *testing.T) {
+	st := &state.State{
+		Name:        "test",
+		Workdir:     &state.DirSpec{Path: "/project", Mode: store.DirMode("copy")},
+		Agent:       agent.GetAgent("test"),
+		Layout:      config.Layout{Principal: config.CLIPrincipal},
+		Environment: &store.Environment{},
+	}
+	desc := runtime.BackendDescriptor{Type: "mock"}
+
+	cfg, err := buildInstanceConfig(desc, st, nil, nil, brokerOutcome{}, false)
+	require.NoError(t, err)
+	for _, e := range cfg.ContainerEnv {
+		assert.NotContains(t, e, "LD_PRELOAD=")
+		assert.NotContains(t, e, "FAKETIME")
+	}
+
+	st.Environment = &store.Environment{FakeTime: "-3d"}
+	cfg, err = buildInstanceConfig(desc, st, nil, nil, brokerOutcome{}, false)
+	require.NoError(t, err)
+	assert.Contains(t, cfg.ContainerEnv, "LD_PRELOAD="+fakeTimeLib)
+	assert.Contains(t, cfg.ContainerEnv, "FAKETIME=-3d")
+	assert.Contains(t, cfg.ContainerEnv, "FAKETIME_DONT_FAKE_MONOTONIC=1")
+}
+
 // TestBuildInstanceConfig_AllowsNetworkIsolatedOnSupportedModes is the
 // counterpart: every isolation mode that yoloai claims to support with
 // --network-isolated must build a config without error. If a future change
@@ -318,10 +403,33 @@ func TestBuildInstanceConfig_AllowsNetworkIsolatedOnSupportedModes(t *testing.T)
 func TestInstanceLabels(t *testing.T) {
 	for _, principal := range []config.PrincipalSegment{config.CLIPrincipal, "acme"} {
 		t.Run("principal="+string(principal), func(t *testing.T) {
-			labels := instanceLabels(principal, "mybox")
+			labels := instanceLabels(principal, "mybox", nil)
 			assert.Equal(t, "mybox", labels[runtime.LabelSandbox])
 			assert.Equal(t, string(principal), labels[runtime.LabelPrincipal],
 				"every instance carries its owner, so a sweep never has to infer one")
 		})
 	}
 }
+
+// TestInstanceLabels_Descriptive: version, profile, and workdir hash ride along
+// with the identity pair, empty values are omitted, and an extra label can never
+// override identity.
+func TestInstanceLabels_Descriptive(t *testing.T) {
+	st := &state.State{
+		Name:        "mybox",
+		Profile:     "go",
+		Workdir:     &state.DirSpec{Path: "/home/user/proj"},
+		Environment: &store.Environment{YoloaiVersion: "1.2.3"},
+	}
+	labels := instanceLabels(config.CLIPrincipal, "mybox", descriptiveLabels(st))
+	assert.Equal(t, "1.2.3", labels[runtime.LabelVersion])
+	assert.Equal(t, "go", labels[runtime.LabelProfile])
+	assert.Equal(t, runtime.WorkdirHash("/home/user/proj"), labels[runtime.LabelWorkdir])
+	assert.Equal(t, "mybox", labels[runtime.LabelSandbox])
+
+	bare := descriptiveLabels(&state.State{Name: "mybox"})
+	assert.Empty(t, bare, "unset fields are omitted, not stamped as empty")
+
+	spoofed := instanceLabels(config.CLIPrincipal, "mybox", map[string]string{runtime.LabelSandbox: "other"})
+	assert.Equal(t, "mybox", spoofed[runtime.LabelSandbox])
+}
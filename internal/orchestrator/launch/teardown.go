@@ -51,10 +51,28 @@ func Teardown(ctx context.Context, d state.Deps, name string) (warnings []string
 	// Remove instance (ignore errors — may not exist)
 	_ = d.Runtime.Remove(ctx, cname)
 
+	// Read the work-root override before the metadata goes: with --work-root the
+	// work copies live outside the sandbox dir, and removing the dir only drops
+	// the work/ symlink. Best-effort — unreadable metadata leaves the external
+	// tree behind, which is recoverable, rather than guessing at a path to delete.
+	// A --repo sandbox's checkout lives outside the sandbox dir the same way.
+	var workRoot, repoDir string
+	if meta, merr := store.LoadEnvironment(sandboxDir); merr == nil {
+		if meta.WorkRoot != "" {
+			workRoot = store.WorkRootDir(meta.WorkRoot, name)
+		}
+		if meta.RepoURL != "" && meta.Workdir() != nil {
+			repoDir = meta.Workdir().HostPath
+		}
+	}
+
 	// Remove the metadata file first so a partial directory removal still frees
 	// the name for reuse: Create keys "already exists" off the metadata, not the
 	// directory, so a leftover (e.g. root-owned overlay/VM state we can't delete)
-	// won't block re-creating with the same name.
+	// won't block re-creating with the same name. The retained previous copy
+	// goes first: a .prev beside a missing record means "metadata lost", which
+	// create refuses to wipe, so it must not outlive an intentional destroy.
+	_ = os.Remove(filepath.Join(sandboxDir, store.EnvironmentPrevFile))
 	_ = os.Remove(filepath.Join(sandboxDir, store.EnvironmentFile))
 
 	// Remove sandbox directory. Some files (e.g. Go module cache) are
@@ -62,10 +80,56 @@ func Teardown(ctx context.Context, d state.Deps, name string) (warnings []string
 	if rerr := forceRemoveAll(sandboxDir); rerr != nil {
 		warnings = append(warnings, fmt.Sprintf("sandbox %s removed, but some files could not be deleted (likely root-owned overlay/VM state from the backend): %v\n  reclaim the leftover disk with: sudo rm -rf %s   (or run 'yoloai system prune')", name, rerr, sandboxDir))
 	}
+	if workRoot != "" {
+		if rerr := forceRemoveAll(workRoot); rerr != nil {
+			warnings = append(warnings, fmt.Sprintf("sandbox %s removed, but its work copies under %s could not be fully deleted: %v", name, workRoot, rerr))
+		}
+	}
+
+	if repoDir != "" && ownedRepoCheckout(d.Layout.ReposDir(), repoDir) && !repoCheckoutInUse(d.Layout.SandboxesDir(), repoDir) {
+		if rerr := forceRemoveAll(repoDir); rerr != nil {
+			warnings = append(warnings, fmt.Sprintf("sandbox %s removed, but its repository checkout %s could not be fully deleted: %v", name, repoDir, rerr))
+		}
+	}
 
 	return warnings, nil
 }
 
+// ownedRepoCheckout reports whether dir is a checkout yoloai made for --repo:
+// directly under the repos dir. Anything else in the metadata is never deleted.
+func ownedRepoCheckout(reposDir, dir string) bool {
+	return filepath.Dir(filepath.Clean(dir)) == filepath.Clean(reposDir)
+}
+
+// repoCheckoutInUse reports whether any remaining sandbox still tracks dir — a
+// clone of a --repo sandbox shares its checkout, just as two sandboxes of one
+// host directory share that. Unreadable metadata counts as a user, so a
+// checkout is only ever deleted when nothing could still need it.
+func repoCheckoutInUse(sandboxesDir, dir string) bool {
+	entries, err := os.ReadDir(sandboxesDir)
+	if err != nil {
+		return !errors.Is(err, os.ErrNotExist)
+	}
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+		meta, err := store.LoadEnvironment(filepath.Join(sandboxesDir, e.Name()))
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return true
+		}
+		for _, d := range meta.Dirs {
+			if d.HostPath == dir {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 // forceRemoveAll removes a directory tree, making read-only entries writable
 // first and retrying briefly to absorb macOS system services (Spotlight,
 // FSEvents) momentarily recreating files mid-removal.
@@ -1,5 +1,5 @@
-// ABOUTME: Tests for forceRemoveAll — verifies read-only nested trees are made
-// ABOUTME: writable and removed, and that a missing path is a no-op.
+// ABOUTME: Tests for Teardown and forceRemoveAll — injector reaping, external
+// ABOUTME: work-root removal, read-only trees, and missing paths.
 package launch
 
 import (
@@ -18,6 +18,7 @@ import (
 	"github.com/kstenerud/yoloai/internal/fileutil"
 	"github.com/kstenerud/yoloai/internal/orchestrator/state"
 	"github.com/kstenerud/yoloai/internal/sysexec"
+	"github.com/kstenerud/yoloai/store"
 )
 
 // TestTeardown_ReapsInjectorBeforeDeletingDir guards DF71: Teardown must kill the
@@ -77,3 +78,75 @@ func TestForceRemoveAll_NonExistentPath(t *testing.T) {
 	err := forceRemoveAll("/tmp/nonexistent-path-" + t.Name())
 	assert.NoError(t, err)
 }
+
+// TestTeardown_RemovesExternalWorkRoot verifies a --work-root sandbox's work
+// copies are deleted with it: removing the sandbox dir alone only drops the
+// work/ symlink and would strand the copies on the other disk.
+func TestTeardown_RemovesExternalWorkRoot(t *testing.T) {
+	layout := config.NewLayout(t.TempDir()).WithPrincipal(config.CLIPrincipal)
+	d := state.Deps{Runtime: &fakeRuntime{}, Layout: layout}
+	workRoot := t.TempDir()
+
+	dir := layout.SandboxDir("box")
+	require.NoError(t, fileutil.MkdirAll(dir, 0o755))
+	require.NoError(t, store.LinkWorkRoot(dir, workRoot, "box", 0o750))
+	require.NoError(t, os.WriteFile(filepath.Join(store.WorkRootDir(workRoot, "box"), "f"), []byte("x"), 0o600))
+	require.NoError(t, store.SaveEnvironment(dir, &store.Environment{Name: "box", WorkRoot: workRoot}))
+
+	_, err := Teardown(context.Background(), d, "box")
+	require.NoError(t, err)
+
+	assert.NoDirExists(t, dir)
+	assert.NoDirExists(t, store.WorkRootDir(workRoot, "box"))
+	assert.DirExists(t, workRoot, "only the sandbox's own subdirectory is removed")
+}
+
+// TestTeardown_RemovesRepoCheckout verifies a --repo sandbox's checkout goes
+// with it, but only once no other sandbox (a clone) still tracks it.
+func TestTeardown_RemovesRepoCheckout(t *testing.T) {
+	layout := config.NewLayout(t.TempDir()).WithPrincipal(config.CLIPrincipal)
+	d := state.Deps{Runtime: &fakeRuntime{}, Layout: layout}
+
+	checkout := filepath.Join(layout.ReposDir(), "box-123")
+	require.NoError(t, fileutil.MkdirAll(checkout, 0o750))
+	env := func(name string) *store.Environment {
+		return &store.Environment{
+			Name:    name,
+			RepoURL: "https://example.com/org/repo",
+			Dirs:    []store.DirEnvironment{{HostPath: checkout, Mode: store.DirModeCopy}},
+		}
+	}
+	for _, name := range []string{"box", "copy"} {
+		require.NoError(t, fileutil.MkdirAll(layout.SandboxDir(name), 0o755))
+		require.NoError(t, store.SaveEnvironment(layout.SandboxDir(name), env(name)))
+	}
+
+	_, err := Teardown(context.Background(), d, "box")
+	require.NoError(t, err)
+	assert.DirExists(t, checkout, "a clone still tracks the checkout")
+
+	_, err = Teardown(context.Background(), d, "copy")
+	require.NoError(t, err)
+	assert.NoDirExists(t, checkout, "the last sandbox using it takes the checkout along")
+}
+
+// TestTeardown_KeepsForeignRepoPath guards against metadata pointing a --repo
+// sandbox's workdir somewhere yoloai didn't create: only checkouts directly
+// under the repos dir are ever deleted.
+func TestTeardown_KeepsForeignRepoPath(t *testing.T) {
+	layout := config.NewLayout(t.TempDir()).WithPrincipal(config.CLIPrincipal)
+	d := state.Deps{Runtime: &fakeRuntime{}, Layout: layout}
+	foreign := t.TempDir()
+
+	dir := layout.SandboxDir("box")
+	require.NoError(t, fileutil.MkdirAll(dir, 0o755))
+	require.NoError(t, store.SaveEnvironment(dir, &store.Environment{
+		Name:    "box",
+		RepoURL: "https://example.com/org/repo",
+		Dirs:    []store.DirEnvironment{{HostPath: foreign, Mode: store.DirModeCopy}},
+	}))
+
+	_, err := Teardown(context.Background(), d, "box")
+	require.NoError(t, err)
+	assert.DirExists(t, foreign)
+}
@@ -11,6 +11,7 @@ import (
 	"time"
 
 	"github.com/kstenerud/yoloai/internal/config"
+	"github.com/kstenerud/yoloai/internal/timing"
 	"github.com/kstenerud/yoloai/runtime"
 	"github.com/kstenerud/yoloai/store"
 )
@@ -46,6 +47,7 @@ func ExecuteVMWorkDirSetup(ctx context.Context, rt runtime.Backend, name, sandbo
 
 	instance := store.InstanceName(meta.Principal, name)
 
+	defer timing.Track(ctx, timing.PhaseBaseline)()
 	for i := range meta.Dirs {
 		if meta.Dirs[i].Mode != store.DirModeCopy {
 			continue
@@ -10,5 +10,17 @@ type StartOptions = lifecycle.StartOptions
 // ResetOptions configures Reset. See lifecycle.ResetOptions.
 type ResetOptions = lifecycle.ResetOptions
 
+// RebaseOptions configures Rebase. See lifecycle.RebaseOptions.
+type RebaseOptions = lifecycle.RebaseOptions
+
+// UpgradeOptions configures UpgradeAgent. See lifecycle.UpgradeOptions.
+type UpgradeOptions = lifecycle.UpgradeOptions
+
+// CleanStateOptions configures CleanAgentState. See lifecycle.CleanStateOptions.
+type CleanStateOptions = lifecycle.CleanStateOptions
+
+// PortsOptions configures UpdatePorts. See lifecycle.PortsOptions.
+type PortsOptions = lifecycle.PortsOptions
+
 // PatchConfigAllowedDomains rewrites a sandbox's allowed-domains list. See lifecycle.PatchConfigAllowedDomains.
 var PatchConfigAllowedDomains = lifecycle.PatchConfigAllowedDomains
@@ -0,0 +1,205 @@
+// ABOUTME: Agent-state trimming: deletes what an agent declares prunable in its
+// ABOUTME: state dir (session transcripts, caches), oldest first, down to a size cap.
+package lifecycle
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/kstenerud/yoloai/internal/config"
+	"github.com/kstenerud/yoloai/internal/orchestrator/state"
+	"github.com/kstenerud/yoloai/internal/orchestrator/status"
+	"github.com/kstenerud/yoloai/store"
+	"github.com/kstenerud/yoloai/yoerrors"
+)
+
+// CleanStateOptions configures CleanAgentState.
+type CleanStateOptions struct {
+	Name string
+	// MaxSize trims the agent's prunable state, oldest first, until its state
+	// directory is at most this many bytes. 0 removes all of it.
+	MaxSize int64
+	// DryRun reports what would be removed without removing it.
+	DryRun bool
+}
+
+// CleanStateResult reports the outcome of CleanAgentState.
+type CleanStateResult struct {
+	Notices []Notice
+	// Removed lists the files removed (under DryRun, that would be), relative
+	// to the agent's state directory, oldest first.
+	Removed []string
+	// Freed is the size of the Removed files.
+	Freed int64
+	// Before and After are the size of the agent's state directory before and
+	// after the trim (under DryRun, what it would be).
+	Before int64
+	After  int64
+}
+
+// CleanAgentState deletes the prunable part of a sandbox's agent state — what
+// the agent's definition lists as safe to lose, its session transcripts among
+// it — to reclaim space. The agent must not be running: it holds its current
+// transcript open and would keep writing to a deleted file. Removing the
+// transcripts means the agent's native resume starts a fresh conversation.
+func CleanAgentState(ctx context.Context, d state.Deps, opts CleanStateOptions) (*CleanStateResult, error) {
+	unlock, err := store.AcquireLock(d.Layout, opts.Name)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	sandboxDir := d.Layout.SandboxDir(opts.Name)
+	if err := store.RequireSandboxDir(sandboxDir); err != nil {
+		return nil, err
+	}
+	agentDef, acfg, err := requireAgent(d, opts.Name)
+	if err != nil {
+		return nil, err
+	}
+	st, err := status.DetectStatus(ctx, d.Runtime, store.InstanceName(d.Layout.Principal, opts.Name), sandboxDir)
+	if err != nil {
+		return nil, fmt.Errorf("detect status: %w", err)
+	}
+	if agentMayBeWriting(st) {
+		return nil, yoerrors.NewUsageError("sandbox %q's agent is running — stop it first with 'yoloai stop %s'", opts.Name, opts.Name)
+	}
+
+	var n notices
+	if agentDef.StateDir == "" || len(agentDef.PrunableState) == 0 {
+		n.infof("the %s agent declares no prunable state", acfg.AgentType)
+		return &CleanStateResult{Notices: n.list}, nil
+	}
+	res, err := trimAgentState(filepath.Join(sandboxDir, store.AgentRuntimeDir), agentDef.PrunableState, opts.MaxSize, opts.DryRun)
+	if err != nil {
+		return nil, err
+	}
+	slog.Info("cleaned agent state", "event", "sandbox.clean_state", "sandbox", opts.Name,
+		"removed", len(res.Removed), "freed", res.Freed, "dry_run", opts.DryRun)
+	res.Notices = n.list
+	return res, nil
+}
+
+// agentMayBeWriting reports whether a sandbox in status st may have an agent
+// process with its state files open: running, or frozen mid-write by a pause.
+func agentMayBeWriting(st status.Status) bool {
+	return st == status.StatusActive || st == status.StatusIdle || st == status.StatusPaused
+}
+
+// trimStateForStart applies the sandbox's resources.agent_state cap before its
+// agent launches. Best-effort: a trim that fails is logged and the start goes
+// on, as an unmeasurable directory passes the resources.disk check.
+func trimStateForStart(d state.Deps, name, sandboxDir string, meta *store.Environment) {
+	if meta.Resources == nil || meta.Resources.AgentState == "" {
+		return
+	}
+	limit, err := config.ParseAgentStateSize(meta.Resources.AgentState)
+	if err != nil || limit == 0 {
+		return // validated at create
+	}
+	agentDef, _, err := requireAgent(d, name)
+	if err != nil || agentDef.StateDir == "" || len(agentDef.PrunableState) == 0 {
+		return
+	}
+	res, err := trimAgentState(filepath.Join(sandboxDir, store.AgentRuntimeDir), agentDef.PrunableState, limit, false)
+	if err != nil {
+		slog.Warn("could not trim agent state", "event", "sandbox.agent_state.trim_failed", "sandbox", name, "error", err)
+		return
+	}
+	if len(res.Removed) > 0 {
+		slog.Info("trimmed agent state", "event", "sandbox.agent_state.trimmed", "sandbox", name,
+			"removed", len(res.Removed), "freed", res.Freed, "size", res.After, "limit", limit)
+	}
+}
+
+// stateFile is one regular file in an agent's state directory.
+type stateFile struct {
+	rel     string // slash-separated, relative to the state directory
+	size    int64
+	modTime int64
+}
+
+// trimAgentState removes the files under stateDir that match prunable (see
+// agent.Definition.PrunableState), oldest first, until what is left is at
+// most maxSize bytes; maxSize 0 removes every match. Only regular files are
+// considered and symlinks are never followed: the agent writes this directory,
+// and a link it planted must not turn the trim on the host's own files.
+func trimAgentState(stateDir string, prunable []string, maxSize int64, dryRun bool) (*CleanStateResult, error) {
+	var total int64
+	var candidates []stateFile
+	err := filepath.WalkDir(stateDir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		total += info.Size()
+		rel, err := filepath.Rel(stateDir, p)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if matchesPrunable(rel, prunable) {
+			candidates = append(candidates, stateFile{rel: rel, size: info.Size(), modTime: info.ModTime().UnixNano()})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("read agent state: %w", err)
+	}
+
+	res := &CleanStateResult{Before: total, After: total}
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].modTime < candidates[j].modTime })
+	for _, f := range candidates {
+		if maxSize > 0 && res.After <= maxSize {
+			break
+		}
+		if !dryRun {
+			if err := os.Remove(filepath.Join(stateDir, filepath.FromSlash(f.rel))); err != nil && !os.IsNotExist(err) {
+				return res, fmt.Errorf("remove agent state: %w", err)
+			}
+		}
+		res.Removed = append(res.Removed, f.rel)
+		res.Freed += f.size
+		res.After -= f.size
+	}
+	return res, nil
+}
+
+// matchesPrunable reports whether the state file at rel is covered by one of
+// the prunable entries: under a "dir/" entry, or matched by a pattern entry
+// itself or through one of its parent dirs.
+func matchesPrunable(rel string, prunable []string) bool {
+	for _, p := range prunable {
+		if dir, ok := strings.CutSuffix(p, "/"); ok {
+			if strings.HasPrefix(rel, dir+"/") {
+				return true
+			}
+			continue
+		}
+		for q := rel; q != "." && q != "/"; q = path.Dir(q) {
+			if ok, _ := path.Match(p, q); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,92 @@
+// ABOUTME: Agent-state trimming: only prunable files go, oldest first down to a
+// ABOUTME: size cap, never through a symlink the agent planted, and dry runs keep all.
+package lifecycle
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeStateFile writes size bytes to rel under dir, last modified age ago.
+func writeStateFile(t *testing.T, dir, rel string, size int, age time.Duration) {
+	t.Helper()
+	p := filepath.Join(dir, filepath.FromSlash(rel))
+	require.NoError(t, os.MkdirAll(filepath.Dir(p), 0o750))
+	require.NoError(t, os.WriteFile(p, []byte(strings.Repeat("x", size)), 0o600))
+	when := time.Now().Add(-age)
+	require.NoError(t, os.Chtimes(p, when, when))
+}
+
+var claudeLikePrunable = []string{"projects/", "todos/", "*.log"}
+
+func TestTrimAgentState_RemovesAllPrunableWithoutCap(t *testing.T) {
+	dir := t.TempDir()
+	writeStateFile(t, dir, "settings.json", 10, time.Hour)
+	writeStateFile(t, dir, "projects/-src/a.jsonl", 100, time.Hour)
+	writeStateFile(t, dir, "todos/t.json", 20, time.Hour)
+	writeStateFile(t, dir, "debug.log", 5, time.Hour)
+	writeStateFile(t, dir, "sub/keep.log", 7, time.Hour)
+
+	res, err := trimAgentState(dir, claudeLikePrunable, 0, false)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"projects/-src/a.jsonl", "todos/t.json", "debug.log"}, res.Removed)
+	assert.Equal(t, int64(125), res.Freed)
+	assert.Equal(t, int64(142), res.Before)
+	assert.Equal(t, int64(17), res.After)
+	assert.FileExists(t, filepath.Join(dir, "settings.json"))
+	assert.FileExists(t, filepath.Join(dir, "sub", "keep.log"), "a root-level pattern doesn't reach into subdirs")
+	assert.NoFileExists(t, filepath.Join(dir, "projects", "-src", "a.jsonl"))
+}
+
+func TestTrimAgentState_OldestFirstDownToCap(t *testing.T) {
+	dir := t.TempDir()
+	writeStateFile(t, dir, "settings.json", 50, 0)
+	writeStateFile(t, dir, "projects/-src/old.jsonl", 100, 3*time.Hour)
+	writeStateFile(t, dir, "projects/-src/mid.jsonl", 100, 2*time.Hour)
+	writeStateFile(t, dir, "projects/-src/new.jsonl", 100, time.Hour)
+
+	res, err := trimAgentState(dir, claudeLikePrunable, 200, false)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"projects/-src/old.jsonl", "projects/-src/mid.jsonl"}, res.Removed)
+	assert.Equal(t, int64(150), res.After)
+	assert.FileExists(t, filepath.Join(dir, "projects", "-src", "new.jsonl"), "the latest transcript survives")
+
+	res, err = trimAgentState(dir, claudeLikePrunable, 200, false)
+	require.NoError(t, err)
+	assert.Empty(t, res.Removed, "already under the cap")
+}
+
+func TestTrimAgentState_DryRunRemovesNothing(t *testing.T) {
+	dir := t.TempDir()
+	writeStateFile(t, dir, "projects/-src/a.jsonl", 100, time.Hour)
+
+	res, err := trimAgentState(dir, claudeLikePrunable, 0, true)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"projects/-src/a.jsonl"}, res.Removed)
+	assert.Equal(t, int64(0), res.After)
+	assert.FileExists(t, filepath.Join(dir, "projects", "-src", "a.jsonl"))
+}
+
+func TestTrimAgentState_DoesNotFollowSymlinks(t *testing.T) {
+	dir := t.TempDir()
+	outside := t.TempDir()
+	writeStateFile(t, outside, "precious.jsonl", 100, time.Hour)
+	require.NoError(t, os.Symlink(outside, filepath.Join(dir, "projects")))
+
+	res, err := trimAgentState(dir, claudeLikePrunable, 0, false)
+	require.NoError(t, err)
+	assert.Empty(t, res.Removed)
+	assert.FileExists(t, filepath.Join(outside, "precious.jsonl"))
+}
+
+func TestTrimAgentState_MissingDir(t *testing.T) {
+	res, err := trimAgentState(filepath.Join(t.TempDir(), "absent"), claudeLikePrunable, 0, false)
+	require.NoError(t, err)
+	assert.Empty(t, res.Removed)
+}
@@ -0,0 +1,48 @@
+// ABOUTME: CommitImage: save a sandbox's container filesystem as an image that new
+// ABOUTME: sandboxes start from (--image), through the backend's runtime.ImageCommitter.
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"github.com/kstenerud/yoloai/internal/config"
+	"github.com/kstenerud/yoloai/internal/orchestrator/state"
+	"github.com/kstenerud/yoloai/runtime"
+	"github.com/kstenerud/yoloai/store"
+	"github.com/kstenerud/yoloai/yoerrors"
+)
+
+// CommitImage saves the sandbox's container filesystem as imageRef: whatever
+// was installed or configured by hand inside it, without the mounted
+// directories (the work copies, the sandbox state, secrets). A running
+// sandbox is frozen for the moment of the commit and keeps running.
+func CommitImage(ctx context.Context, d state.Deps, name, imageRef string) error {
+	if err := config.ValidateImageRef(imageRef); err != nil {
+		return err
+	}
+	unlock, err := store.AcquireLock(d.Layout, name)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	if err := store.RequireSandboxDir(d.Layout.SandboxDir(name)); err != nil {
+		return err
+	}
+	committer, ok := d.Runtime.(runtime.ImageCommitter)
+	if !ok {
+		return yoerrors.NewUsageError("the %s backend can't save sandboxes as images (supported: docker, podman)", d.Runtime.Descriptor().Type)
+	}
+
+	cname := store.InstanceName(d.Layout.Principal, name)
+	slog.Info("committing sandbox image", "event", "sandbox.commit", "container", cname, "image", imageRef)
+	if err := committer.CommitImage(ctx, cname, imageRef); err != nil {
+		if errors.Is(err, runtime.ErrNotFound) {
+			return yoerrors.NewUsageError("sandbox %s has no container to save — run 'yoloai start %s' first", name, name)
+		}
+		return err
+	}
+	return nil
+}
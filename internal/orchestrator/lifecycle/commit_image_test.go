@@ -0,0 +1,65 @@
+// ABOUTME: CommitImage: the backend commit under the sandbox's instance name, and the
+// ABOUTME: refusals for reserved names, a missing container and a backend without it.
+package lifecycle
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kstenerud/yoloai/runtime"
+	"github.com/kstenerud/yoloai/yoerrors"
+)
+
+// committerMockRuntime adds runtime.ImageCommitter to the lifecycle mock.
+type committerMockRuntime struct {
+	*lifecycleMockRuntime
+	missing bool
+	commits []string
+}
+
+func (m *committerMockRuntime) CommitImage(_ context.Context, name, imageRef string) error {
+	if m.missing {
+		return runtime.ErrNotFound
+	}
+	m.commits = append(m.commits, name+" -> "+imageRef)
+	return nil
+}
+
+func (m *committerMockRuntime) HasImage(_ context.Context, _ string) (bool, error) {
+	return true, nil
+}
+
+func TestCommitImage(t *testing.T) {
+	tmpDir := t.TempDir()
+	name := "setup"
+	createTestSandbox(t, tmpDir, name, "/tmp/project", "copy")
+	rt := &committerMockRuntime{lifecycleMockRuntime: &lifecycleMockRuntime{}}
+	d := newLifecycleDeps(rt, tmpDir)
+
+	require.NoError(t, CommitImage(context.Background(), d, name, "my-env:v1"))
+	require.Len(t, rt.commits, 1)
+	assert.Contains(t, rt.commits[0], name+" -> my-env:v1")
+}
+
+func TestCommitImage_Refusals(t *testing.T) {
+	tmpDir := t.TempDir()
+	name := "setup"
+	createTestSandbox(t, tmpDir, name, "/tmp/project", "copy")
+	var ue *yoerrors.UsageError
+
+	rt := &committerMockRuntime{lifecycleMockRuntime: &lifecycleMockRuntime{}}
+	require.ErrorAs(t, CommitImage(context.Background(), newLifecycleDeps(rt, tmpDir), name, "yoloai-base"), &ue)
+	assert.Empty(t, rt.commits, "a reserved name never reaches the backend")
+
+	rt.missing = true
+	err := CommitImage(context.Background(), newLifecycleDeps(rt, tmpDir), name, "my-env")
+	require.ErrorAs(t, err, &ue)
+	assert.Contains(t, err.Error(), "no container")
+
+	err = CommitImage(context.Background(), newLifecycleDeps(&lifecycleMockRuntime{}, tmpDir), name, "my-env")
+	require.ErrorAs(t, err, &ue)
+	assert.Contains(t, err.Error(), "can't save")
+}
@@ -43,6 +43,10 @@ type lifecycleMockRuntime struct {
 	// optional (DF119) — so a mock that stands in for one sets this true. Zero value
 	// suits tests that never construct a sandbox-git and so never read it.
 	gitExecInConfinement bool
+	// agentProvisioned sets the descriptor's AgentProvisionedByBackend: true
+	// models an image-shipped agent (docker), false a host-provided one
+	// (seatbelt).
+	agentProvisioned bool
 }
 
 func (m *lifecycleMockRuntime) Stop(ctx context.Context, name string) error {
@@ -130,8 +134,9 @@ func (m *lifecycleMockRuntime) AttachCommand(_ string, _, _ int, _ runtime.Isola
 }
 func (m *lifecycleMockRuntime) Descriptor() runtime.BackendDescriptor {
 	return runtime.BackendDescriptor{
-		Type:         "mock",
-		BaseModeName: runtime.IsolationModeContainer,
+		Type:                      "mock",
+		BaseModeName:              runtime.IsolationModeContainer,
+		AgentProvisionedByBackend: m.agentProvisioned,
 		Capabilities: runtime.BackendCaps{
 			NetworkIsolation:     true,
 			CapAdd:               true,
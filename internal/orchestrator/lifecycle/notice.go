@@ -8,6 +8,8 @@ import (
 	"bytes"
 	"fmt"
 	"strings"
+
+	"github.com/kstenerud/yoloai/copyflow"
 )
 
 // NoticeLevel classifies a Notice for rendering — informational status vs. a
@@ -95,4 +97,7 @@ type StartResult struct {
 // emitted (e.g. "upgrading to restart", plus the restart's own start notices).
 type ResetResult struct {
 	Notices []Notice
+	// Rewind is set by a reset to a commit (ResetOptions.To): where the work
+	// copy now sits and which commits were dropped.
+	Rewind *copyflow.RewindResult
 }
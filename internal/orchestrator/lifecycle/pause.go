@@ -0,0 +1,66 @@
+// ABOUTME: Pause and Unpause: freeze a running sandbox's processes in place and
+// ABOUTME: thaw them, through the backend's optional runtime.Pauser.
+package lifecycle
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/kstenerud/yoloai/internal/orchestrator/state"
+	"github.com/kstenerud/yoloai/internal/orchestrator/status"
+	"github.com/kstenerud/yoloai/runtime"
+	"github.com/kstenerud/yoloai/store"
+	"github.com/kstenerud/yoloai/yoerrors"
+)
+
+// Pause freezes every process in a running sandbox. The agent keeps its
+// in-memory state and carries on from the same instruction on Unpause; unlike
+// Stop, nothing is torn down.
+func Pause(ctx context.Context, d state.Deps, name string) error {
+	return setPaused(ctx, d, name, true)
+}
+
+// Unpause thaws a sandbox frozen by Pause.
+func Unpause(ctx context.Context, d state.Deps, name string) error {
+	return setPaused(ctx, d, name, false)
+}
+
+func setPaused(ctx context.Context, d state.Deps, name string, pause bool) error {
+	unlock, err := store.AcquireLock(d.Layout, name)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	sandboxDir := d.Layout.SandboxDir(name)
+	if err := store.RequireSandboxDir(sandboxDir); err != nil {
+		return err
+	}
+	pauser, ok := d.Runtime.(runtime.Pauser)
+	if !ok {
+		return yoerrors.NewUsageError("the %s backend can't pause sandboxes — use 'yoloai stop' instead", d.Runtime.Descriptor().Type)
+	}
+
+	cname := store.InstanceName(d.Layout.Principal, name)
+	st, err := status.DetectStatus(ctx, d.Runtime, cname, sandboxDir)
+	if err != nil {
+		return fmt.Errorf("detect status: %w", err)
+	}
+	if pause {
+		switch st {
+		case status.StatusActive, status.StatusIdle, status.StatusDone, status.StatusFailed:
+		case status.StatusPaused:
+			return yoerrors.NewUsageError("sandbox %s is already paused", name)
+		default:
+			return yoerrors.NewUsageError("sandbox %s is not running (%s)", name, st)
+		}
+		slog.Info("pausing sandbox", "event", "sandbox.pause", "container", cname)
+		return pauser.Pause(ctx, cname)
+	}
+	if st != status.StatusPaused {
+		return yoerrors.NewUsageError("sandbox %s is not paused (%s)", name, st)
+	}
+	slog.Info("unpausing sandbox", "event", "sandbox.unpause", "container", cname)
+	return pauser.Unpause(ctx, cname)
+}
@@ -0,0 +1,82 @@
+// ABOUTME: Pause/Unpause: state checks around the backend's freeze, and the
+// ABOUTME: refusal on a backend without runtime.Pauser.
+package lifecycle
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kstenerud/yoloai/runtime"
+	"github.com/kstenerud/yoloai/yoerrors"
+)
+
+// pauserMockRuntime adds runtime.Pauser to the lifecycle mock; Inspect reports
+// a running instance whose Paused flag follows Pause/Unpause.
+type pauserMockRuntime struct {
+	*lifecycleMockRuntime
+	paused bool
+	calls  []string
+}
+
+func (m *pauserMockRuntime) Pause(_ context.Context, name string) error {
+	m.paused = true
+	m.calls = append(m.calls, "pause "+name)
+	return nil
+}
+
+func (m *pauserMockRuntime) Unpause(_ context.Context, name string) error {
+	m.paused = false
+	m.calls = append(m.calls, "unpause "+name)
+	return nil
+}
+
+func newPauserMock() *pauserMockRuntime {
+	m := &pauserMockRuntime{lifecycleMockRuntime: &lifecycleMockRuntime{}}
+	m.inspectFn = func(_ context.Context, _ string) (runtime.InstanceInfo, error) {
+		return runtime.InstanceInfo{Running: true, Paused: m.paused}, nil
+	}
+	return m
+}
+
+func TestPauseUnpause(t *testing.T) {
+	tmpDir := t.TempDir()
+	name := "noisy"
+	createTestSandbox(t, tmpDir, name, "/tmp/project", "copy")
+	rt := newPauserMock()
+	d := newLifecycleDeps(rt, tmpDir)
+	var ue *yoerrors.UsageError
+
+	require.ErrorAs(t, Unpause(context.Background(), d, name), &ue, "not paused yet")
+
+	require.NoError(t, Pause(context.Background(), d, name))
+	assert.True(t, rt.paused)
+	require.ErrorAs(t, Pause(context.Background(), d, name), &ue, "already paused")
+
+	require.NoError(t, Unpause(context.Background(), d, name))
+	assert.False(t, rt.paused)
+	assert.Len(t, rt.calls, 2, "refusals never reach the backend")
+}
+
+func TestPause_Refusals(t *testing.T) {
+	tmpDir := t.TempDir()
+	name := "frozen"
+	createTestSandbox(t, tmpDir, name, "/tmp/project", "copy")
+	var ue *yoerrors.UsageError
+
+	noPauser := &lifecycleMockRuntime{inspectFn: func(_ context.Context, _ string) (runtime.InstanceInfo, error) {
+		return runtime.InstanceInfo{Running: true}, nil
+	}}
+	err := Pause(context.Background(), newLifecycleDeps(noPauser, tmpDir), name)
+	require.ErrorAs(t, err, &ue)
+	assert.Contains(t, err.Error(), "can't pause")
+
+	stopped := newPauserMock()
+	stopped.inspectFn = func(_ context.Context, _ string) (runtime.InstanceInfo, error) {
+		return runtime.InstanceInfo{}, nil
+	}
+	require.ErrorAs(t, Pause(context.Background(), newLifecycleDeps(stopped, tmpDir), name), &ue)
+	assert.Empty(t, stopped.calls)
+}
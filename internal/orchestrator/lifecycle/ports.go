@@ -0,0 +1,199 @@
+// ABOUTME: Port forwarding after creation: adds and removes host:container
+// ABOUTME: mappings in the sandbox's record and restarts it so the container gets them.
+package lifecycle
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/kstenerud/yoloai/internal/netpolicycfg"
+	"github.com/kstenerud/yoloai/internal/orchestrator/state"
+	"github.com/kstenerud/yoloai/internal/orchestrator/status"
+	"github.com/kstenerud/yoloai/store"
+	"github.com/kstenerud/yoloai/yoerrors"
+)
+
+// PortsOptions configures UpdatePorts.
+type PortsOptions struct {
+	Name string
+	// Add lists "host:container" mappings to forward.
+	Add []string
+	// Remove lists host ports whose forwarding goes.
+	Remove []int
+	// NoRestart records the change without restarting a running sandbox;
+	// it takes effect on the sandbox's next start.
+	NoRestart bool
+}
+
+// PortsResult reports the outcome of UpdatePorts.
+type PortsResult struct {
+	Notices []Notice
+	// Ports is the sandbox's forwarding after the change.
+	Ports []string
+	// Added and Removed are the mappings that changed. An Add that was
+	// already forwarded is not in Added.
+	Added   []string
+	Removed []string
+	// Restarted reports whether the running sandbox was restarted to pick
+	// the change up.
+	Restarted bool
+}
+
+// UpdatePorts changes which ports the sandbox forwards. Mappings are a
+// creation-time property of the container, so a running sandbox is
+// restarted: its container is recreated with the new mappings, while the
+// work copy and the agent's state directory, both on the host, carry over.
+// A sandbox that isn't running picks the change up on its next start.
+// Returns a *UsageError for a malformed mapping, a host port that is already
+// forwarded elsewhere, a host port that isn't forwarded, or a port added to
+// a --network-none sandbox.
+func UpdatePorts(ctx context.Context, d state.Deps, opts PortsOptions) (*PortsResult, error) {
+	result, running, err := updatePortsRecord(ctx, d, opts)
+	if err != nil || (len(result.Added) == 0 && len(result.Removed) == 0) {
+		return result, err
+	}
+	var n notices
+	switch {
+	case !running:
+		n.infof("The new port forwarding takes effect when sandbox %s next starts", opts.Name)
+	case opts.NoRestart:
+		n.infof("The new port forwarding takes effect when sandbox %s is restarted", opts.Name)
+	default:
+		// Stop and Start each take the sandbox lock, which updatePortsRecord
+		// has released by now. Start finds the container stopped and
+		// recreates it from the updated record.
+		if err := Stop(ctx, d, opts.Name); err != nil {
+			return result, err
+		}
+		started, err := Start(ctx, d, opts.Name, StartOptions{Recreating: true})
+		if started != nil {
+			n.list = append(n.list, started.Notices...)
+		}
+		result.Notices = n.list
+		if err != nil {
+			return result, err
+		}
+		result.Restarted = true
+	}
+	result.Notices = n.list
+	return result, nil
+}
+
+// updatePortsRecord applies opts to the sandbox's saved port list under the
+// sandbox lock and reports whether the sandbox is running.
+func updatePortsRecord(ctx context.Context, d state.Deps, opts PortsOptions) (*PortsResult, bool, error) {
+	unlock, err := store.AcquireLock(d.Layout, opts.Name)
+	if err != nil {
+		return nil, false, err
+	}
+	defer unlock()
+
+	sandboxDir := d.Layout.SandboxDir(opts.Name)
+	if err := store.RequireSandboxDir(sandboxDir); err != nil {
+		return nil, false, err
+	}
+	meta, err := store.LoadEnvironment(sandboxDir)
+	if err != nil {
+		return nil, false, err
+	}
+	if len(opts.Add) > 0 {
+		np, err := netpolicycfg.Load(sandboxDir)
+		if err != nil {
+			return nil, false, fmt.Errorf("load netpolicy: %w", err)
+		}
+		if np.Mode == "none" {
+			return nil, false, yoerrors.NewUsageError("sandbox %s uses --network-none; it can't forward ports", opts.Name)
+		}
+	}
+
+	ports, removed, err := removePorts(meta.Ports, opts.Remove)
+	if err != nil {
+		return nil, false, err
+	}
+	ports, added, err := addPorts(ports, opts.Add)
+	if err != nil {
+		return nil, false, err
+	}
+	result := &PortsResult{Ports: ports, Added: added, Removed: removed}
+	if len(added) == 0 && len(removed) == 0 {
+		return result, false, nil
+	}
+	meta.Ports = ports
+	if err := store.SaveEnvironment(sandboxDir, meta); err != nil {
+		return nil, false, fmt.Errorf("save meta: %w", err)
+	}
+
+	st, err := status.DetectStatus(ctx, d.Runtime, store.InstanceName(d.Layout.Principal, opts.Name), sandboxDir)
+	if err != nil {
+		return nil, false, fmt.Errorf("detect status: %w", err)
+	}
+	switch st {
+	case status.StatusActive, status.StatusIdle, status.StatusDone, status.StatusFailed:
+		return result, true, nil
+	default:
+		return result, false, nil
+	}
+}
+
+// removePorts drops the mappings of hostPorts from ports, returning what is
+// left and what went.
+func removePorts(ports []string, hostPorts []int) (kept, removed []string, err error) {
+	kept = slices.Clone(ports)
+	for _, hp := range hostPorts {
+		i := slices.IndexFunc(kept, func(p string) bool { return portHost(p) == hp })
+		if i < 0 {
+			return nil, nil, yoerrors.NewUsageError("host port %d is not forwarded", hp)
+		}
+		removed = append(removed, kept[i])
+		kept = slices.Delete(kept, i, i+1)
+	}
+	return kept, removed, nil
+}
+
+// addPorts appends the mappings of add that ports doesn't have yet. A host
+// port can only be forwarded to one sandbox port.
+func addPorts(ports, add []string) (out, added []string, err error) {
+	out = ports
+	for _, p := range add {
+		host, container, err := parsePort(p)
+		if err != nil {
+			return nil, nil, err
+		}
+		mapping := fmt.Sprintf("%d:%d", host, container)
+		i := slices.IndexFunc(out, func(q string) bool { return portHost(q) == host })
+		switch {
+		case i < 0:
+			out = append(out, mapping)
+			added = append(added, mapping)
+		case out[i] != mapping:
+			return nil, nil, yoerrors.NewUsageError("host port %d is already forwarded (%s); remove it first", host, out[i])
+		}
+	}
+	return out, added, nil
+}
+
+// parsePort parses a "host:container" mapping.
+func parsePort(p string) (host, container int, err error) {
+	h, c, ok := strings.Cut(p, ":")
+	if !ok {
+		return 0, 0, yoerrors.NewUsageError("invalid port format %q (expected host:container)", p)
+	}
+	if host, err = strconv.Atoi(h); err != nil || host < 1 || host > 65535 {
+		return 0, 0, yoerrors.NewUsageError("invalid host port %q in mapping %q", h, p)
+	}
+	if container, err = strconv.Atoi(c); err != nil || container < 1 || container > 65535 {
+		return 0, 0, yoerrors.NewUsageError("invalid container port %q in mapping %q", c, p)
+	}
+	return host, container, nil
+}
+
+// portHost is the host port of a saved "host:container" mapping, 0 if it
+// doesn't parse.
+func portHost(p string) int {
+	h, _, _ := strings.Cut(p, ":")
+	n, _ := strconv.Atoi(h)
+	return n
+}
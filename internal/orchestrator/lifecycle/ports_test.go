@@ -0,0 +1,75 @@
+// ABOUTME: UpdatePorts: mapping bookkeeping in environment.json, the conflict
+// ABOUTME: and --network-none refusals, and deferral for a stopped sandbox.
+package lifecycle
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kstenerud/yoloai/internal/netpolicycfg"
+	"github.com/kstenerud/yoloai/runtime"
+	"github.com/kstenerud/yoloai/store"
+	"github.com/kstenerud/yoloai/yoerrors"
+)
+
+func TestUpdatePorts_StoppedSandbox(t *testing.T) {
+	tmpDir := t.TempDir()
+	name := "web"
+	createTestSandbox(t, tmpDir, name, "/tmp/project", "copy")
+	rt := &lifecycleMockRuntime{
+		inspectFn: func(_ context.Context, _ string) (runtime.InstanceInfo, error) {
+			return runtime.InstanceInfo{Running: false}, nil
+		},
+	}
+	d := newLifecycleDeps(rt, tmpDir)
+	sandboxDir := filepath.Join(tmpDir, ".yoloai", "sandboxes", name)
+
+	res, err := UpdatePorts(context.Background(), d, PortsOptions{Name: name, Add: []string{"3000:3000", "8080:80"}})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"3000:3000", "8080:80"}, res.Added)
+	assert.False(t, res.Restarted, "a stopped sandbox isn't started")
+	require.Len(t, res.Notices, 1)
+	assert.Contains(t, res.Notices[0].Message, "next starts")
+	meta, err := store.LoadEnvironment(sandboxDir)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"3000:3000", "8080:80"}, meta.Ports)
+
+	res, err = UpdatePorts(context.Background(), d, PortsOptions{Name: name, Add: []string{"3000:3000"}})
+	require.NoError(t, err)
+	assert.Empty(t, res.Added, "an existing mapping is a no-op")
+	assert.Empty(t, res.Notices)
+
+	var ue *yoerrors.UsageError
+	_, err = UpdatePorts(context.Background(), d, PortsOptions{Name: name, Add: []string{"3000:4000"}})
+	require.ErrorAs(t, err, &ue, "host port already forwarded elsewhere")
+	_, err = UpdatePorts(context.Background(), d, PortsOptions{Name: name, Add: []string{"70000:80"}})
+	require.ErrorAs(t, err, &ue, "out of range")
+	_, err = UpdatePorts(context.Background(), d, PortsOptions{Name: name, Remove: []int{9999}})
+	require.ErrorAs(t, err, &ue, "not forwarded")
+
+	res, err = UpdatePorts(context.Background(), d, PortsOptions{Name: name, Remove: []int{3000}})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"3000:3000"}, res.Removed)
+	assert.Equal(t, []string{"8080:80"}, res.Ports)
+	meta, err = store.LoadEnvironment(sandboxDir)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"8080:80"}, meta.Ports)
+}
+
+func TestUpdatePorts_NetworkNone(t *testing.T) {
+	tmpDir := t.TempDir()
+	name := "offline"
+	createTestSandbox(t, tmpDir, name, "/tmp/project", "copy")
+	sandboxDir := filepath.Join(tmpDir, ".yoloai", "sandboxes", name)
+	require.NoError(t, netpolicycfg.Save(sandboxDir, &netpolicycfg.Netpolicy{Mode: "none"}))
+	d := newLifecycleDeps(&lifecycleMockRuntime{}, tmpDir)
+
+	_, err := UpdatePorts(context.Background(), d, PortsOptions{Name: name, Add: []string{"3000:3000"}})
+	var ue *yoerrors.UsageError
+	require.ErrorAs(t, err, &ue)
+	assert.Contains(t, err.Error(), "--network-none")
+}
@@ -0,0 +1,264 @@
+// ABOUTME: Rebase: moves a :copy dir's baseline to the host directory as it is now
+// ABOUTME: and replays the agent's commits and edits on top, keeping its work.
+package lifecycle
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+
+	"github.com/kstenerud/yoloai/copyflow"
+	"github.com/kstenerud/yoloai/internal/envsetup"
+	"github.com/kstenerud/yoloai/internal/git"
+	"github.com/kstenerud/yoloai/internal/orchestrator/state"
+	"github.com/kstenerud/yoloai/internal/orchestrator/status"
+	"github.com/kstenerud/yoloai/internal/orchestrator/workcopy"
+	"github.com/kstenerud/yoloai/runtime"
+	"github.com/kstenerud/yoloai/store"
+	"github.com/kstenerud/yoloai/yoerrors"
+)
+
+// RebaseOptions configures Rebase.
+type RebaseOptions struct {
+	Name string
+	// Dir is the host path of the tracked :copy directory to rebase; "" is
+	// the workdir.
+	Dir string
+}
+
+// RebaseResult reports the outcome of Rebase.
+type RebaseResult struct {
+	// Baseline is the new diff baseline — the host directory as it is now —
+	// and PreviousBaseline the one the agent's work sat on before.
+	Baseline         string
+	PreviousBaseline string
+	// Commits is how many of the agent's commits were replayed onto it.
+	Commits int
+	// Uncommitted reports whether uncommitted edits were carried over too.
+	Uncommitted bool
+}
+
+// rebaseNotification is pasted into the agent's session after a rebase; it's
+// filled in with the directory's mount path and the number of commits replayed.
+const rebaseNotification = "[yoloai] %s has been rebased onto the current host directory. " +
+	"It now has the host's latest changes, with your %d commit(s) and any uncommitted changes replayed on top; " +
+	"the commit SHAs have changed. Re-read files before assuming their contents."
+
+// Rebase moves a :copy directory's baseline to the host directory as it is now
+// and replays the agent's work on top: its commits beyond the baseline, then
+// its uncommitted edits, left uncommitted. A long-running sandbox drifts from
+// the host, and until now reset was the only way to catch up — at the cost of
+// the agent's work.
+//
+// It rehearses before it touches anything. The host directory is materialized
+// into a scratch repo exactly as create would copy it, baseline and all, and the
+// agent's work is replayed there on the host (git am --3way, then git apply).
+// A conflict fails the rebase with git's report and leaves the sandbox as it
+// was. Only a clean replay is mirrored into the work copy, in place, as an
+// in-place reset syncs it — so the container's bind-mount survives, and like a
+// reset the copy ends up with exactly the rehearsal's files, .git included.
+// Commits on other branches, stashes and tags don't come along. Gitignored
+// files the agent made (node_modules, build output) are left where they are.
+//
+// The agent's work is read through the sandbox's confinement like every other
+// work-copy git operation (audit C1), and the agent is told in-session, so the
+// sandbox must be running. The instance is paused for the mirror when the
+// backend can pause; otherwise a working agent is refused. Either way the
+// rebase aborts, changing nothing, if the work copy changed after it was read.
+func Rebase(ctx context.Context, d state.Deps, opts RebaseOptions) (*RebaseResult, error) {
+	unlock, err := store.AcquireLock(d.Layout, opts.Name)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	sandboxDir := d.Layout.SandboxDir(opts.Name)
+	if err := store.RequireSandboxDir(sandboxDir); err != nil {
+		return nil, err
+	}
+	meta, err := store.LoadEnvironment(sandboxDir)
+	if err != nil {
+		return nil, err
+	}
+	dir := meta.Dir(opts.Dir)
+	if dir == nil {
+		return nil, yoerrors.NewUsageError("no directory %q in sandbox %q", opts.Dir, opts.Name)
+	}
+	if dir.Mode != store.DirModeCopy {
+		return nil, yoerrors.NewUsageError("%s is :%s; only :copy directories have a baseline to rebase", dir.HostPath, dir.Mode)
+	}
+	if runtime.LocalityOf(d.Runtime) == runtime.LocalitySandboxSide {
+		return nil, fmt.Errorf("rebase not supported when the work copy is inside the sandbox (SandboxSide backend, e.g. Tart)")
+	}
+	if dir.BaselineSHA == "" {
+		return nil, fmt.Errorf("%s has no baseline yet; start the sandbox first", dir.HostPath)
+	}
+	if _, err := os.Stat(dir.HostPath); err != nil {
+		return nil, fmt.Errorf("original directory no longer exists: %s", dir.HostPath)
+	}
+	if err := requireRunning(ctx, d, opts.Name, sandboxDir, "rebase"); err != nil {
+		return nil, err
+	}
+	cname := store.InstanceName(d.Layout.Principal, opts.Name)
+	pauser, canPause := d.Runtime.(runtime.Pauser)
+	if !canPause {
+		// Nothing can hold the agent still for the mirror, so it must not be
+		// in the middle of a turn.
+		if st, err := status.DetectStatus(ctx, d.Runtime, cname, sandboxDir); err == nil && st == status.StatusActive {
+			return nil, yoerrors.NewUsageError("the agent in %q is working; rebase when it is idle, so none of its edits are lost", opts.Name)
+		}
+	}
+	workDir := store.WorkDir(sandboxDir, dir.HostPath)
+	before, err := workCopyFingerprint(workDir)
+	if err != nil {
+		return nil, err
+	}
+
+	scratch, err := d.Layout.MkdirTemp("yoloai-rebase-*")
+	if err != nil {
+		return nil, fmt.Errorf("create temp dir: %w", err)
+	}
+	defer os.RemoveAll(scratch) //nolint:errcheck // best-effort cleanup
+	rehearsal := filepath.Join(scratch, "work")
+
+	slog.Info("rebasing work copy", "event", "sandbox.rebase", "sandbox", opts.Name, "host_path", dir.HostPath)
+	res, err := rehearseRebase(ctx, d, opts.Name, *dir, rehearsal)
+	if err != nil {
+		return nil, err
+	}
+
+	// The agent's work was read while it ran. Freeze it for the mirror and
+	// make sure it hasn't touched the work copy since, or the mirror would
+	// silently discard what it wrote in between.
+	paused := false
+	if canPause {
+		if err := pauser.Pause(ctx, cname); err != nil {
+			return nil, fmt.Errorf("pause sandbox for the rebase: %w", err)
+		}
+		paused = true
+		defer func() {
+			if !paused {
+				return
+			}
+			if err := pauser.Unpause(context.WithoutCancel(ctx), cname); err != nil {
+				slog.Warn("unpause after rebase failed", "event", "sandbox.rebase", "sandbox", opts.Name, "error", err)
+			}
+		}()
+	}
+	after, err := workCopyFingerprint(workDir)
+	if err != nil {
+		return nil, err
+	}
+	if after != before {
+		return nil, yoerrors.NewUsageError("the agent in %q changed files while the rebase was being prepared, so nothing was changed; try again when it is idle", opts.Name)
+	}
+
+	hostGit := git.NewHost(d.Layout)
+	spec := specOf(d.Layout.ObjectsDir(), *dir, meta.AllowPush)
+	spec.Src = rehearsal
+	if err := workcopy.Mirror(ctx, spec, workDir, hostGit); err != nil {
+		return nil, fmt.Errorf("update work copy: %w", err)
+	}
+	if dir == meta.Workdir() {
+		// The mirror has pruned a --context worktree file, as a reset's sync does.
+		if err := envsetup.WriteWorktreeContext(sandboxDir, meta); err != nil {
+			return nil, err
+		}
+	}
+	dir.BaselineSHA = res.Baseline
+	if err := store.SaveEnvironment(sandboxDir, meta); err != nil {
+		return nil, err
+	}
+
+	if paused {
+		paused = false
+		if err := pauser.Unpause(ctx, cname); err != nil {
+			return res, fmt.Errorf("unpause sandbox after the rebase: %w", err)
+		}
+	}
+
+	text := fmt.Sprintf(rebaseNotification, dir.MountPath, res.Commits)
+	return res, sendResetNotification(ctx, d, opts.Name, sandboxDir, text, false, meta)
+}
+
+// workCopyFingerprint summarizes the entries under workDir by path, type and,
+// for files, size and modification time, so two calls differ when anything was written
+// in between. .git counts too, except its index and object store: reading the
+// agent's uncommitted work stages it, rewriting both, while a commit the agent
+// makes still shows in its refs and logs.
+func workCopyFingerprint(workDir string) (string, error) {
+	h := sha256.New()
+	skip := map[string]bool{
+		filepath.Join(workDir, ".git", "index"):   true,
+		filepath.Join(workDir, ".git", "objects"): true,
+	}
+	err := filepath.WalkDir(workDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if skip[path] {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			// A directory's own mtime moves with lock files git creates and
+			// removes; what it holds is covered by its entries.
+			fmt.Fprintf(h, "%s\x00%v\n", path, info.Mode()) //nolint:errcheck // hash writes don't fail
+			return nil
+		}
+		fmt.Fprintf(h, "%s\x00%v\x00%d\x00%d\n", path, info.Mode(), info.Size(), info.ModTime().UnixNano()) //nolint:errcheck // hash writes don't fail
+		return nil
+	})
+	if err != nil {
+		return "", fmt.Errorf("scan work copy: %w", err)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// rehearseRebase materializes dir's host directory at rehearsal, as create
+// would, and replays the agent's work from the sandbox onto it. On success
+// rehearsal holds the rebased repo and the result names its baseline.
+func rehearseRebase(ctx context.Context, d state.Deps, name string, dir store.DirEnvironment, rehearsal string) (*RebaseResult, error) {
+	hostGit := git.NewHost(d.Layout)
+	// The rehearsal keeps packs of its own: a work copy sharing an object store
+	// goes on sharing only that one, which a fresh copy could name differently.
+	// Its pushes are blocked when it is mirrored into the work copy, not here.
+	baselineSHA, _, err := workcopy.Materialize(ctx, specOf("", dir, true), rehearsal, workcopy.WipeAndCopy, hostGit, d.Runtime)
+	if err != nil {
+		return nil, fmt.Errorf("copy %s: %w", dir.HostPath, err)
+	}
+	res := &RebaseResult{Baseline: baselineSHA, PreviousBaseline: dir.BaselineSHA}
+
+	patchDir, files, err := copyflow.GenerateFormatPatch(ctx, d.Layout, d.Runtime, name, dir.HostPath, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(patchDir) //nolint:errcheck // best-effort cleanup
+	uncommitted, _, err := copyflow.GenerateUncommittedDiff(ctx, d.Layout, d.Runtime, name, dir.HostPath, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := hostGit.ReplayPatches(ctx, rehearsal, patchDir, files); err != nil {
+		return nil, fmt.Errorf("the agent's commits don't apply on top of the host's changes, so nothing was changed:\n%w", err)
+	}
+	res.Commits = len(files)
+	if len(uncommitted) > 0 {
+		if err := hostGit.ApplyPatch(ctx, uncommitted, rehearsal, true); err != nil {
+			return nil, fmt.Errorf("the agent's uncommitted changes don't apply on top of the host's changes, so nothing was changed: %w", err)
+		}
+		res.Uncommitted = true
+	}
+	return res, nil
+}
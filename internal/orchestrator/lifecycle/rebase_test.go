@@ -0,0 +1,174 @@
+// ABOUTME: Rebase replays the agent's commits and edits onto the host's current
+// ABOUTME: state and moves the baseline; conflicts and a busy agent leave it untouched.
+package lifecycle
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kstenerud/yoloai/internal/config"
+	"github.com/kstenerud/yoloai/internal/git"
+	"github.com/kstenerud/yoloai/internal/orchestrator/agentcfg"
+	"github.com/kstenerud/yoloai/internal/orchestrator/state"
+	"github.com/kstenerud/yoloai/internal/orchestrator/workcopy"
+	"github.com/kstenerud/yoloai/internal/testutil"
+	"github.com/kstenerud/yoloai/store"
+	"github.com/kstenerud/yoloai/yoerrors"
+)
+
+// rebaseFixture creates a host repo and a running sandbox named name, on a
+// backend that can pause, whose work copy was made from it the way create
+// makes one. It returns the deps,
+// the host repo, the work copy and its baseline.
+func rebaseFixture(t *testing.T, name string) (d state.Deps, origDir, workDir, baseline string) {
+	t.Helper()
+	tmpDir := t.TempDir()
+	origDir = filepath.Join(tmpDir, "original")
+	require.NoError(t, os.MkdirAll(origDir, 0750))
+	testutil.InitGitRepo(t, origDir)
+	testutil.WriteFile(t, origDir, "file.txt", "one\ntwo\nthree\n")
+	testutil.WriteFile(t, origDir, "other.txt", "other v1\n")
+	testutil.GitAdd(t, origDir, ".")
+	testutil.GitCommit(t, origDir, "initial")
+
+	mock := newPauserMock()
+	d = newLifecycleDeps(mock, tmpDir)
+	sandboxDir := d.Layout.SandboxDir(name)
+	workDir = store.WorkDir(sandboxDir, origDir)
+	baseline, _, err := workcopy.Materialize(context.Background(), workcopy.Spec{Src: origDir}, workDir, workcopy.WipeAndCopy, git.NewHost(d.Layout), mock)
+	require.NoError(t, err)
+	require.NoError(t, store.SaveEnvironment(sandboxDir, &store.Environment{
+		Name:      name,
+		Principal: config.CLIPrincipal,
+		CreatedAt: time.Now(),
+		Dirs:      []store.DirEnvironment{{HostPath: origDir, MountPath: origDir, Mode: "copy", BaselineSHA: baseline}},
+	}))
+	require.NoError(t, agentcfg.Save(sandboxDir, &agentcfg.AgentConfig{AgentType: "claude"}))
+	return d, origDir, workDir, baseline
+}
+
+func TestRebase_ReplaysAgentWorkOntoHost(t *testing.T) {
+	name := "test-rebase"
+	d, origDir, workDir, oldBaseline := rebaseFixture(t, name)
+
+	// The agent commits an edit and leaves a new file uncommitted; meanwhile
+	// the host commits a change to another file.
+	testutil.WriteFile(t, workDir, "file.txt", "one\ntwo by agent\nthree\n")
+	testutil.GitAdd(t, workDir, ".")
+	testutil.GitCommit(t, workDir, "agent edit")
+	testutil.WriteFile(t, workDir, "scratch.txt", "not committed\n")
+	testutil.WriteFile(t, origDir, "other.txt", "other v2\n")
+	testutil.GitAdd(t, origDir, ".")
+	testutil.GitCommit(t, origDir, "upstream")
+
+	// The notification fails (no runtime-config.json), after the rebase is done.
+	res, err := Rebase(context.Background(), d, RebaseOptions{Name: name})
+	require.Error(t, err, "Rebase must surface the notification failure, not swallow it")
+	require.NotNil(t, res, "%v", err)
+
+	assert.Equal(t, oldBaseline, res.PreviousBaseline)
+	assert.Equal(t, 1, res.Commits)
+	assert.True(t, res.Uncommitted)
+	assert.Equal(t, "other v2\n", readFile(t, workDir, "other.txt"), "the host's change is in")
+	assert.Equal(t, "one\ntwo by agent\nthree\n", readFile(t, workDir, "file.txt"), "the agent's commit is kept")
+	assert.Equal(t, "not committed\n", readFile(t, workDir, "scratch.txt"), "and so is its uncommitted file")
+
+	assert.Equal(t, "agent edit", testutil.RunGitOutput(t, workDir, "log", "-1", "--format=%s"))
+	assert.Equal(t, res.Baseline, testutil.RunGitOutput(t, workDir, "rev-parse", "HEAD^"),
+		"the agent's commit sits directly on the new baseline")
+	assert.Equal(t, gitHEAD(t, origDir), res.Baseline, "a clean host's baseline is its HEAD")
+	assert.Equal(t, "?? scratch.txt", testutil.RunGitOutput(t, workDir, "status", "--porcelain"))
+
+	meta, err := store.LoadEnvironment(d.Layout.SandboxDir(name))
+	require.NoError(t, err)
+	assert.Equal(t, res.Baseline, meta.Workdir().BaselineSHA)
+
+	cname := store.InstanceName(d.Layout.Principal, name)
+	assert.Equal(t, []string{"pause " + cname, "unpause " + cname}, d.Runtime.(*pauserMockRuntime).calls,
+		"the agent is held still for the mirror and let go before it is told")
+}
+
+func TestRebase_KeepsIgnoredFiles(t *testing.T) {
+	name := "test-rebase-ignored"
+	d, origDir, workDir, _ := rebaseFixture(t, name)
+
+	testutil.WriteFile(t, workDir, ".gitignore", "build/\n")
+	require.NoError(t, os.MkdirAll(filepath.Join(workDir, "build"), 0750))
+	testutil.WriteFile(t, workDir, "build/out.txt", "built\n")
+	testutil.WriteFile(t, origDir, "other.txt", "other v2\n")
+	testutil.GitAdd(t, origDir, ".")
+	testutil.GitCommit(t, origDir, "upstream")
+
+	_, err := Rebase(context.Background(), d, RebaseOptions{Name: name})
+	require.Error(t, err, "the notification fails after the rebase is done")
+	assert.Equal(t, "other v2\n", readFile(t, workDir, "other.txt"))
+	assert.Equal(t, "built\n", readFile(t, workDir, "build/out.txt"), "ignored build output survives")
+}
+
+func TestRebase_RefusesWorkingAgentWithoutPause(t *testing.T) {
+	name := "test-rebase-busy"
+	d, origDir, workDir, oldBaseline := rebaseFixture(t, name)
+	// A backend that can't pause; the mock's status probe reports the agent
+	// as working.
+	d.Runtime = d.Runtime.(*pauserMockRuntime).lifecycleMockRuntime
+
+	testutil.WriteFile(t, origDir, "other.txt", "other v2\n")
+	testutil.GitAdd(t, origDir, ".")
+	testutil.GitCommit(t, origDir, "upstream")
+
+	_, err := Rebase(context.Background(), d, RebaseOptions{Name: name})
+	var ue *yoerrors.UsageError
+	require.ErrorAs(t, err, &ue)
+	assert.Contains(t, err.Error(), "is working")
+	assert.Equal(t, "other v1\n", readFile(t, workDir, "other.txt"), "the work copy is untouched")
+	meta, err := store.LoadEnvironment(d.Layout.SandboxDir(name))
+	require.NoError(t, err)
+	assert.Equal(t, oldBaseline, meta.Workdir().BaselineSHA)
+}
+
+func TestRebase_ConflictLeavesSandboxAlone(t *testing.T) {
+	name := "test-rebase-conflict"
+	d, origDir, workDir, oldBaseline := rebaseFixture(t, name)
+
+	testutil.WriteFile(t, workDir, "file.txt", "one\ntwo by agent\nthree\n")
+	testutil.GitAdd(t, workDir, ".")
+	testutil.GitCommit(t, workDir, "agent edit")
+	agentHead := gitHEAD(t, workDir)
+	testutil.WriteFile(t, origDir, "file.txt", "one\ntwo by host\nthree\n")
+	testutil.GitAdd(t, origDir, ".")
+	testutil.GitCommit(t, origDir, "upstream")
+
+	_, err := Rebase(context.Background(), d, RebaseOptions{Name: name})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "don't apply on top of the host's changes")
+	assert.Contains(t, err.Error(), "Patch failed at 0001 agent edit")
+	assert.NotContains(t, err.Error(), "git am --continue", "no advice about a scratch repo")
+
+	assert.Equal(t, agentHead, gitHEAD(t, workDir), "the work copy is untouched")
+	assert.Equal(t, "one\ntwo by agent\nthree\n", readFile(t, workDir, "file.txt"))
+	meta, err := store.LoadEnvironment(d.Layout.SandboxDir(name))
+	require.NoError(t, err)
+	assert.Equal(t, oldBaseline, meta.Workdir().BaselineSHA)
+}
+
+func TestWorkCopyFingerprint(t *testing.T) {
+	_, _, workDir, _ := rebaseFixture(t, "test-rebase-fingerprint")
+	before, err := workCopyFingerprint(workDir)
+	require.NoError(t, err)
+
+	testutil.GitAdd(t, workDir, ".")
+	same, err := workCopyFingerprint(workDir)
+	require.NoError(t, err)
+	assert.Equal(t, before, same, "staging, as reading the agent's work does, is not a change")
+
+	testutil.WriteFile(t, workDir, "new.txt", "late write\n")
+	after, err := workCopyFingerprint(workDir)
+	require.NoError(t, err)
+	assert.NotEqual(t, before, after, "a file the agent writes is")
+}
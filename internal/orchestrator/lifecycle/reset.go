@@ -9,7 +9,9 @@ import (
 	"log/slog"
 	"os"
 	"path/filepath"
+	"strings"
 
+	"github.com/kstenerud/yoloai/internal/envsetup"
 	"github.com/kstenerud/yoloai/internal/fileutil"
 	"github.com/kstenerud/yoloai/internal/git"
 	"github.com/kstenerud/yoloai/internal/orchestrator/launch"
@@ -19,6 +21,7 @@ import (
 	"github.com/kstenerud/yoloai/internal/orchestrator/workprobe"
 	"github.com/kstenerud/yoloai/runtime"
 	"github.com/kstenerud/yoloai/store"
+	"github.com/kstenerud/yoloai/yoerrors"
 )
 
 // ResetOptions holds parameters for the reset command.
@@ -35,6 +38,20 @@ type ResetOptions struct {
 	// recreated (Restart). Merged over the resolved config+profile env, never
 	// persisted — the caller re-supplies it (secrets are the caller's concern).
 	Env map[string]string
+	// Paths, when set, restores only these workdir-relative paths to their
+	// baseline contents (see resetPaths) instead of re-copying everything.
+	// Incompatible with Restart and ClearState.
+	Paths []string
+	// To, when set, rewinds the work copy to this commit — the baseline or one
+	// of the agent's unapplied commits — discarding the commits after it (see
+	// resetTo). Incompatible with Paths, Restart and ClearState.
+	To string
+	// Dirs, when set, limits the reset to these tracked :copy directories
+	// (host paths; the workdir, aux dirs, or both). Each is re-copied and
+	// re-baselined, in place or on restart as usual; the others keep their
+	// work, and the cache and files directories are left alone. Empty resets
+	// every tracked directory.
+	Dirs []string
 }
 
 // Reset re-copies the workdir from the original host directory and resets
@@ -58,10 +75,21 @@ func Reset(ctx context.Context, d state.Deps, opts ResetOptions) (*ResetResult,
 		return nil, err
 	}
 
-	if meta.Workdir().Mode == "rw" {
+	sel, err := selectResetDirs(meta, opts.Dirs)
+	if err != nil {
+		return nil, err
+	}
+	if sel == nil && meta.Workdir().Mode == "rw" {
 		return nil, fmt.Errorf("reset is not applicable for :rw directories — changes are already in the original")
 	}
 
+	if opts.To != "" {
+		return resetTo(ctx, d, opts, meta, sandboxDir)
+	}
+	if len(opts.Paths) > 0 {
+		return &ResetResult{}, resetPaths(ctx, d, opts, meta, sandboxDir)
+	}
+
 	if opts.Prompt != "" {
 		promptPath := filepath.Join(sandboxDir, "prompt.txt")
 		if err := fileutil.WriteFile(promptPath, []byte(opts.Prompt), 0600); err != nil {
@@ -93,14 +121,41 @@ func Reset(ctx context.Context, d state.Deps, opts ResetOptions) (*ResetResult,
 	}
 
 	if !opts.Restart {
-		err := resetInPlace(ctx, d, opts, meta, sandboxDir)
+		err := resetInPlace(ctx, d, opts, sel, meta, sandboxDir)
 		return &ResetResult{Notices: n.list}, err
 	}
 
-	err = prepareResetRestart(ctx, d, opts, sandboxDir, meta, &n)
+	err = prepareResetRestart(ctx, d, opts, sel, sandboxDir, meta, &n)
 	return &ResetResult{Notices: n.list}, err
 }
 
+// dirSelection is the set of tracked directories (by host path) a reset
+// touches. nil selects every tracked directory.
+type dirSelection map[string]bool
+
+func (s dirSelection) has(hostPath string) bool { return s == nil || s[hostPath] }
+
+// selectResetDirs resolves ResetOptions.Dirs against the sandbox's directories.
+// Each must name a :copy directory — the only kind with a copy to reset. No
+// dirs returns a nil (select-all) selection.
+func selectResetDirs(meta *store.Environment, dirs []string) (dirSelection, error) {
+	if len(dirs) == 0 {
+		return nil, nil
+	}
+	sel := make(dirSelection, len(dirs))
+	for _, p := range dirs {
+		dir := meta.Dir(p)
+		if p == "" || dir == nil {
+			return nil, yoerrors.NewUsageError("no directory %q in sandbox %q", p, meta.Name)
+		}
+		if dir.Mode != store.DirModeCopy {
+			return nil, yoerrors.NewUsageError("directory %q is :%s; only :copy directories have a copy to reset", p, dir.Mode)
+		}
+		sel[dir.HostPath] = true
+	}
+	return sel, nil
+}
+
 // NeedsConfirmation checks if a sandbox requires confirmation before
 // destruction. Returns true when destruction would lose unapplied work —
 // uncommitted changes or commits beyond the baseline — OR when that can't be
@@ -110,17 +165,31 @@ func Reset(ctx context.Context, d state.Deps, opts ResetOptions) (*ResetResult,
 // every routine destroy. The work signal is read via the backend's git context
 // (in-VM for Tart), so callers must open the runtime first.
 func NeedsConfirmation(ctx context.Context, d state.Deps, name string) (bool, string) {
+	return NeedsConfirmationFor(ctx, d, name, nil)
+}
+
+// NeedsConfirmationFor is NeedsConfirmation limited to the tracked directories
+// with the given host paths — what a directory-scoped reset would discard.
+// Empty dirs checks every directory.
+func NeedsConfirmationFor(ctx context.Context, d state.Deps, name string, dirs []string) (bool, string) {
 	hostGit := git.NewHost(d.Layout)
 	sandboxGit := git.NewSandbox(d.Layout, d.Runtime, name)
-	return unappliedWorkReason(ctx, hostGit, sandboxGit, d.Layout.SandboxDir(name))
+	var sel dirSelection
+	if len(dirs) > 0 {
+		sel = make(dirSelection, len(dirs))
+		for _, p := range dirs {
+			sel[p] = true
+		}
+	}
+	return unappliedWorkReason(ctx, hostGit, sandboxGit, d.Layout.SandboxDir(name), sel)
 }
 
 // unappliedWorkReason reports whether a sandbox holds work that destruction
 // would lose — uncommitted/beyond-baseline changes in the workdir or any aux
-// directory — independent of whether the agent is running. A WorkUnknown probe
+// directory (those in sel; nil is all of them) — independent of whether the agent is running. A WorkUnknown probe
 // (a VM-local backend that is not running, so the in-VM working copy can't be
 // read) fails safe: it blocks destroy with a reason that points to the cause.
-func unappliedWorkReason(ctx context.Context, hostGit, sandboxGit *git.Git, sandboxDir string) (bool, string) {
+func unappliedWorkReason(ctx context.Context, hostGit, sandboxGit *git.Git, sandboxDir string, sel dirSelection) (bool, string) {
 	meta, err := store.LoadEnvironment(sandboxDir)
 	if err != nil {
 		// Environment is unreadable (a broken sandbox). Don't assume it's empty —
@@ -135,10 +204,10 @@ func unappliedWorkReason(ctx context.Context, hostGit, sandboxGit *git.Git, sand
 		return false, ""
 	}
 
-	if blocked, reason := dirWorkReason(ctx, sandboxGit, sandboxDir, meta.Workdir().Mode, meta.Workdir().HostPath, meta.Workdir().BaselineSHA); blocked {
-		return true, reason
-	}
-	for _, dirEnv := range meta.AuxDirs() {
+	for _, dirEnv := range meta.Dirs {
+		if !sel.has(dirEnv.HostPath) {
+			continue
+		}
 		if blocked, reason := dirWorkReason(ctx, sandboxGit, sandboxDir, dirEnv.Mode, dirEnv.HostPath, dirEnv.BaselineSHA); blocked {
 			return true, reason
 		}
@@ -166,26 +235,38 @@ func dirWorkReason(ctx context.Context, sandboxGit *git.Git, sandboxDir string,
 
 // specOf adapts a stored DirEnvironment to the materialization inputs. Reset's
 // counterpart to create building the Spec from a DirSpec — the two carry the same
-// three fields under different names.
-func specOf(d store.DirEnvironment) workcopy.Spec {
-	return workcopy.Spec{Src: d.HostPath, IncludeIgnored: d.IncludeIgnored, StripHistory: d.StripHistory}
+// three fields under different names. objectsDir is where the work copy may
+// share git packs (Layout.ObjectsDir; "" for nowhere); allowPush is the
+// sandbox's --allow-push.
+func specOf(objectsDir string, d store.DirEnvironment, allowPush bool) workcopy.Spec {
+	return workcopy.Spec{
+		Src:            d.HostPath,
+		IncludeIgnored: d.IncludeIgnored,
+		StripHistory:   d.StripHistory,
+		ObjectStores:   workcopy.ObjectStoreRoot(objectsDir, d.HostPath),
+		BlockPush:      !allowPush,
+	}
 }
 
-// resetCopyWorkdir re-copies the workdir from its host path and records the new
-// baseline SHA (empty if deferred to the VM). WipeAndCopy — the same
-// materialization create runs, so a reset reproduces the copy create would have
-// made. Reset drops the history notice: it does not warn today, and keeping that
-// is behaviour-preserving.
+// resetCopyWorkdir re-syncs the workdir from its host path and records the new
+// baseline SHA (empty if deferred to the VM). InPlaceAndPrune, as in an in-place
+// reset: nothing is watching dst here, but syncing rewrites only what changed,
+// and it lands on the same file set as create's copy. Reset drops the history
+// notice: it does not warn today, and keeping that is behaviour-preserving.
 func resetCopyWorkdir(ctx context.Context, d state.Deps, sandboxName, sandboxDir string, meta *store.Environment) (string, error) {
 	workDir := store.WorkDir(sandboxDir, meta.Workdir().HostPath)
 	if _, err := os.Stat(meta.Workdir().HostPath); err != nil {
 		return "", fmt.Errorf("original directory no longer exists: %s", meta.Workdir().HostPath)
 	}
 	slog.Debug("re-copying workdir", "event", "sandbox.reset.workdir", "sandbox", sandboxName, "host_path", meta.Workdir().HostPath)
-	sha, _, err := workcopy.Materialize(ctx, specOf(*meta.Workdir()), workDir, workcopy.WipeAndCopy, git.NewHost(d.Layout), d.Runtime)
+	sha, _, err := workcopy.Materialize(ctx, specOf(d.Layout.ObjectsDir(), *meta.Workdir(), meta.AllowPush), workDir, workcopy.InPlaceAndPrune, git.NewHost(d.Layout), d.Runtime)
 	if err != nil {
 		return "", fmt.Errorf("re-copy workdir: %w", err)
 	}
+	// The fresh copy has lost a --context worktree file.
+	if err := envsetup.WriteWorktreeContext(sandboxDir, meta); err != nil {
+		return "", err
+	}
 	return sha, nil
 }
 
@@ -193,25 +274,28 @@ func resetCopyWorkdir(ctx context.Context, d state.Deps, sandboxName, sandboxDir
 // Same materialization as the workdir — which also gives aux dirs the SandboxSide
 // baseline deferral this path used to omit (masked before by the recreate's
 // unconditional VM setup, so no observable change; the divergence is simply gone).
-func resetAuxCopyDir(ctx context.Context, g *git.Git, sandboxDir string, d store.DirEnvironment, rt runtime.Backend) (string, error) {
+func resetAuxCopyDir(ctx context.Context, g *git.Git, sandboxDir, objectsDir string, d store.DirEnvironment, allowPush bool, rt runtime.Backend) (string, error) {
 	auxWorkDir := store.WorkDir(sandboxDir, d.HostPath)
 	if _, err := os.Stat(d.HostPath); err != nil {
 		return "", fmt.Errorf("original aux directory no longer exists: %s", d.HostPath)
 	}
-	sha, _, err := workcopy.Materialize(ctx, specOf(d), auxWorkDir, workcopy.WipeAndCopy, g, rt)
+	sha, _, err := workcopy.Materialize(ctx, specOf(objectsDir, d, allowPush), auxWorkDir, workcopy.InPlaceAndPrune, g, rt)
 	if err != nil {
 		return "", fmt.Errorf("re-copy aux dir %s: %w", d.HostPath, err)
 	}
 	return sha, nil
 }
 
-// resetAuxDirs resets all aux :copy directories in meta, updating BaselineSHA
-// in-place.
-func resetAuxDirs(ctx context.Context, g *git.Git, sandboxDir string, meta *store.Environment, rt runtime.Backend) error {
+// resetAuxDirs resets the selected aux :copy directories in meta, updating
+// BaselineSHA in-place.
+func resetAuxDirs(ctx context.Context, g *git.Git, sandboxDir, objectsDir string, meta *store.Environment, sel dirSelection, rt runtime.Backend) error {
 	for i, d := range meta.AuxDirs() {
+		if !sel.has(d.HostPath) {
+			continue
+		}
 		switch d.Mode {
 		case store.DirModeCopy:
-			sha, err := resetAuxCopyDir(ctx, g, sandboxDir, d, rt)
+			sha, err := resetAuxCopyDir(ctx, g, sandboxDir, objectsDir, d, meta.AllowPush, rt)
 			if err != nil {
 				return err
 			}
@@ -266,8 +350,10 @@ func applyPostResetOptions(d state.Deps, opts ResetOptions, sandboxDir string, p
 			return nil, err
 		}
 	}
-	if err := clearCacheAndFiles(d, opts); err != nil {
-		return nil, err
+	if len(opts.Dirs) == 0 {
+		if err := clearCacheAndFiles(d, opts); err != nil {
+			return nil, err
+		}
 	}
 	if opts.Debug {
 		if err := patchConfigDebug(sandboxDir, true); err != nil {
@@ -292,7 +378,7 @@ func applyPostResetOptions(d state.Deps, opts ResetOptions, sandboxDir string, p
 
 // prepareResetRestart performs the full stop → wipe → recopy → start flow for
 // reset --restart. Extracted from Reset to reduce its cyclomatic complexity.
-func prepareResetRestart(ctx context.Context, d state.Deps, opts ResetOptions, sandboxDir string, meta *store.Environment, n *notices) error {
+func prepareResetRestart(ctx context.Context, d state.Deps, opts ResetOptions, sel dirSelection, sandboxDir string, meta *store.Environment, n *notices) error {
 	// Destroy the container so start() sees StatusRemoved and does a clean
 	// recreate. Using Remove (not Stop) avoids suspending a VM we're about
 	// to rebuild — the suspend state would be stale after the host workdir
@@ -307,18 +393,20 @@ func prepareResetRestart(ctx context.Context, d state.Deps, opts ResetOptions, s
 	reinitLogs(sandboxDir, perms)
 
 	// Reset main workdir
-	newSHA, err := resetWorkdir(ctx, d, opts.Name, sandboxDir, meta)
-	if err != nil {
-		return err
+	if sel.has(meta.Workdir().HostPath) {
+		newSHA, err := resetWorkdir(ctx, d, opts.Name, sandboxDir, meta)
+		if err != nil {
+			return err
+		}
+		meta.Workdir().BaselineSHA = newSHA
 	}
 
 	// Reset aux :copy dirs
-	if err := resetAuxDirs(ctx, git.NewHost(d.Layout), sandboxDir, meta, d.Runtime); err != nil {
+	if err := resetAuxDirs(ctx, git.NewHost(d.Layout), sandboxDir, d.Layout.ObjectsDir(), meta, sel, d.Runtime); err != nil {
 		return err
 	}
 
 	// Update environment.json
-	meta.Workdir().BaselineSHA = newSHA
 	if err := store.SaveEnvironment(sandboxDir, meta); err != nil {
 		return err
 	}
@@ -347,21 +435,21 @@ func prepareResetRestart(ctx context.Context, d state.Deps, opts ResetOptions, s
 	return nil
 }
 
-// resyncWorkCopy re-copies dir's host directory into the work copy at workDir and
-// returns the new baseline SHA. InPlaceAndPrune — the strategy that overwrites a
+// resyncWorkCopy re-syncs dir's host directory into the work copy at workDir and
+// returns the new baseline SHA. InPlaceAndPrune — the strategy that updates a
 // live bind-mounted work copy without replacing the directory the container is
 // watching, then prunes what the source dropped. Shared with create/restart via
 // workcopy.Materialize, so an in-place reset reproduces the copy create would
 // have made rather than approximating it (the DF117/DF118 fix), and the two
 // cannot drift.
-func resyncWorkCopy(ctx context.Context, g *git.Git, dir store.DirEnvironment, workDir string, rt runtime.Backend) (string, error) {
-	sha, _, err := workcopy.Materialize(ctx, specOf(dir), workDir, workcopy.InPlaceAndPrune, g, rt)
+func resyncWorkCopy(ctx context.Context, g *git.Git, objectsDir string, dir store.DirEnvironment, allowPush bool, workDir string, rt runtime.Backend) (string, error) {
+	sha, _, err := workcopy.Materialize(ctx, specOf(objectsDir, dir, allowPush), workDir, workcopy.InPlaceAndPrune, g, rt)
 	return sha, err
 }
 
 // resetInPlace resets the workspace while the agent is still running.
 // Syncs files from host, recreates git baseline, and notifies the agent via tmux.
-func resetInPlace(ctx context.Context, d state.Deps, opts ResetOptions, meta *store.Environment, sandboxDir string) error {
+func resetInPlace(ctx context.Context, d state.Deps, opts ResetOptions, sel dirSelection, meta *store.Environment, sandboxDir string) error {
 	// In-place reset requires direct host access to the work copy; a SandboxSide
 	// backend (e.g. Tart) keeps it inside the sandbox, so it is unsupported there.
 	if runtime.LocalityOf(d.Runtime) == runtime.LocalitySandboxSide {
@@ -370,23 +458,28 @@ func resetInPlace(ctx context.Context, d state.Deps, opts ResetOptions, meta *st
 
 	g := git.NewHost(d.Layout)
 
-	workDir := store.WorkDir(sandboxDir, meta.Workdir().HostPath)
-	newSHA, err := resyncWorkCopy(ctx, g, *meta.Workdir(), workDir, d.Runtime)
-	if err != nil {
-		return err
+	if sel.has(meta.Workdir().HostPath) {
+		workDir := store.WorkDir(sandboxDir, meta.Workdir().HostPath)
+		newSHA, err := resyncWorkCopy(ctx, g, d.Layout.ObjectsDir(), *meta.Workdir(), meta.AllowPush, workDir, d.Runtime)
+		if err != nil {
+			return err
+		}
+		if err := envsetup.WriteWorktreeContext(sandboxDir, meta); err != nil {
+			return err
+		}
+		meta.Workdir().BaselineSHA = newSHA
 	}
-	meta.Workdir().BaselineSHA = newSHA
 
 	// Aux :copy dirs get the same in-place resync. Omitting them was DF123: the
 	// default reset refreshed only the workdir and left aux dirs holding the
 	// agent's changes, while telling the user everything was reverted. The
 	// restart path already resets aux dirs; the in-place path now matches it.
 	for i, aux := range meta.AuxDirs() {
-		if aux.Mode != store.DirModeCopy {
+		if aux.Mode != store.DirModeCopy || !sel.has(aux.HostPath) {
 			continue
 		}
 		auxWorkDir := store.WorkDir(sandboxDir, aux.HostPath)
-		sha, err := resyncWorkCopy(ctx, g, aux, auxWorkDir, d.Runtime)
+		sha, err := resyncWorkCopy(ctx, g, d.Layout.ObjectsDir(), aux, meta.AllowPush, auxWorkDir, d.Runtime)
 		if err != nil {
 			return fmt.Errorf("reset aux dir %s: %w", aux.HostPath, err)
 		}
@@ -397,13 +490,19 @@ func resetInPlace(ctx context.Context, d state.Deps, opts ResetOptions, meta *st
 		return err
 	}
 
+	if sel != nil {
+		// A directory-scoped reset leaves the rest of the sandbox alone: no
+		// cache/files wipe, and no prompt re-send for a task still under way.
+		return sendResetNotification(ctx, d, opts.Name, sandboxDir, resetDirsText(meta, sel), false, meta)
+	}
+
 	// Clear cache and files directories (unless --keep-X)
 	if err := clearCacheAndFiles(d, opts); err != nil {
 		return err
 	}
 
 	// Notify agent via tmux
-	return sendResetNotification(ctx, d, opts.Name, sandboxDir, opts.NoPrompt, meta.HasPrompt, meta)
+	return sendResetNotification(ctx, d, opts.Name, sandboxDir, resetNotification, !opts.NoPrompt && meta.HasPrompt, meta)
 }
 
 // clearCacheAndFiles clears the cache and files directories unless --keep-X flags are set.
@@ -436,9 +535,27 @@ const resetNotification = "[yoloai] Workspace has been reset to match the curren
 	"All previous changes have been reverted and any new upstream changes are now present. " +
 	"Re-read files before assuming their contents."
 
-// sendResetNotification delivers a notification (and optionally the prompt)
+// resetDirsNotification is pasted into the agent's session after a
+// directory-scoped reset; %s is the list of reset directories as the agent
+// sees them (their mount paths).
+const resetDirsNotification = "[yoloai] These directories have been reset to match the host: %s. " +
+	"All previous changes in them have been reverted; your work elsewhere is untouched. " +
+	"Re-read files there before assuming their contents."
+
+// resetDirsText renders resetDirsNotification for the selected directories.
+func resetDirsText(meta *store.Environment, sel dirSelection) string {
+	var mounts []string
+	for _, dir := range meta.Dirs {
+		if dir.Mode == store.DirModeCopy && sel.has(dir.HostPath) {
+			mounts = append(mounts, dir.MountPath)
+		}
+	}
+	return fmt.Sprintf(resetDirsNotification, strings.Join(mounts, ", "))
+}
+
+// sendResetNotification delivers text (followed by the prompt when withPrompt)
 // to the running agent via tmux load-buffer + paste-buffer + send-keys.
-func sendResetNotification(ctx context.Context, d state.Deps, name, sandboxDir string, noPrompt, hasPrompt bool, meta *store.Environment) error {
+func sendResetNotification(ctx context.Context, d state.Deps, name, sandboxDir, text string, withPrompt bool, meta *store.Environment) error {
 	// Read runtime-config.json for submit_sequence
 	cfg, err := loadContainerConfig(sandboxDir)
 	if err != nil {
@@ -448,7 +565,7 @@ func sendResetNotification(ctx context.Context, d state.Deps, name, sandboxDir s
 	// Build script to deliver notification via tmux.
 	// $1 carries the notification text (positional arg avoids shell injection).
 	appendPrompt := ":"
-	if !noPrompt && hasPrompt {
+	if withPrompt {
 		appendPrompt = `printf '\n\n' >> /tmp/yoloai-reset.txt; cat /yoloai/prompt.txt >> /tmp/yoloai-reset.txt`
 	}
 
@@ -459,16 +576,16 @@ func sendResetNotification(ctx context.Context, d state.Deps, name, sandboxDir s
 	script := fmt.Sprintf(`printf '%%s' "$1" > /tmp/yoloai-reset.txt
 %s
 tmux load-buffer /tmp/yoloai-reset.txt
-tmux paste-buffer -p -t main
+tmux paste-buffer -p -t main:{start}
 sleep 0.5
 for key in %s; do
-    tmux send-keys -t main "$key"
+    tmux send-keys -t main:{start} "$key"
     sleep 0.2
 done
 rm -f /tmp/yoloai-reset.txt`, appendPrompt, cfg.SubmitSequence)
 
 	_, err = status.ExecInContainer(ctx, d.Runtime, name, meta, d.Layout.HostUID, []string{
-		"bash", "-c", script, "_", resetNotification,
+		"bash", "-c", script, "_", text,
 	})
 	return err
 }
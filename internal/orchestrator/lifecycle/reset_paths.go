@@ -0,0 +1,119 @@
+// ABOUTME: Partial reset: restores selected workdir paths to their baseline
+// ABOUTME: contents, leaving the rest of the agent's work, and tells the agent.
+package lifecycle
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"path/filepath"
+	"strings"
+
+	"github.com/kstenerud/yoloai/internal/git"
+	"github.com/kstenerud/yoloai/internal/orchestrator/state"
+	"github.com/kstenerud/yoloai/internal/orchestrator/status"
+	"github.com/kstenerud/yoloai/store"
+	"github.com/kstenerud/yoloai/yoerrors"
+)
+
+// resetPathsNotification is pasted into the agent's session after a partial
+// reset; %s is the comma-separated list of restored paths.
+const resetPathsNotification = "[yoloai] These paths have been restored to their original contents: %s. " +
+	"Your changes everywhere else are untouched. Re-read those files before assuming their contents."
+
+// resetPaths restores opts.Paths in the workdir's work copy to the baseline —
+// the original as copied, or as of the last apply — and leaves everything
+// else alone. The agent keeps running and is told which paths changed under
+// it. Its commits stay in history; the restore shows up as uncommitted edits
+// that undo them within the paths, so the paths drop out of the diff.
+//
+// Git runs through the sandbox's confinement like every other work-copy git
+// operation (audit C1), so the sandbox must be running.
+func resetPaths(ctx context.Context, d state.Deps, opts ResetOptions, meta *store.Environment, sandboxDir string) error {
+	if opts.Restart || opts.ClearState || len(opts.Dirs) > 0 {
+		return yoerrors.NewUsageError("a path reset restores workdir files in place; it can't be combined with restart, clear-state, or dirs")
+	}
+	paths, err := cleanResetPaths(opts.Paths)
+	if err != nil {
+		return err
+	}
+	dir := meta.Workdir()
+	if dir.BaselineSHA == "" {
+		return fmt.Errorf("workdir has no baseline yet; start the sandbox first")
+	}
+	if err := requireRunning(ctx, d, opts.Name, sandboxDir, "restore paths"); err != nil {
+		return err
+	}
+
+	slog.Info("restoring paths", "event", "sandbox.reset.paths", "sandbox", opts.Name, "paths", paths)
+	workDir := store.WorkDir(sandboxDir, dir.HostPath)
+	if err := restorePathsFromBaseline(ctx, git.NewSandbox(d.Layout, d.Runtime, opts.Name), workDir, dir.BaselineSHA, paths); err != nil {
+		return err
+	}
+	return sendResetNotification(ctx, d, opts.Name, sandboxDir, fmt.Sprintf(resetPathsNotification, strings.Join(paths, ", ")), false, meta)
+}
+
+// requireRunning refuses an operation that only works on a live sandbox — a
+// targeted reset (paths or commit) runs git through the sandbox's confinement
+// and notifies the agent in-session, and an agent upgrade installs inside the
+// container — so none of them has a stopped fallback. what completes "must be
+// running to ...".
+func requireRunning(ctx context.Context, d state.Deps, name, sandboxDir, what string) error {
+	st, err := status.DetectStatus(ctx, d.Runtime, store.InstanceName(d.Layout.Principal, name), sandboxDir)
+	if err == nil && st == status.StatusPaused {
+		return yoerrors.NewUsageError("sandbox %q is paused — run 'yoloai unpause %s' to %s", name, name, what)
+	}
+	if err != nil || (st != status.StatusActive && st != status.StatusIdle) {
+		return yoerrors.NewUsageError("sandbox %q must be running to %s — start it with 'yoloai start %s'", name, what, name)
+	}
+	return nil
+}
+
+// cleanResetPaths normalizes the requested paths to slash-separated,
+// workdir-relative form and refuses any that are absolute or climb out of the
+// workdir.
+func cleanResetPaths(paths []string) ([]string, error) {
+	out := make([]string, 0, len(paths))
+	for _, p := range paths {
+		p = filepath.Clean(strings.TrimSpace(p))
+		if !filepath.IsLocal(p) {
+			return nil, yoerrors.NewUsageError("reset path %q must be relative to the workdir and stay inside it", p)
+		}
+		out = append(out, filepath.ToSlash(p))
+	}
+	return out, nil
+}
+
+// restorePathsFromBaseline makes each path in workDir match baselineSHA: files
+// the agent added there are removed (tracked or not; ignored files stay), and
+// whatever the baseline had is checked back out. Paths are literal pathspecs,
+// so a name containing glob characters means itself.
+func restorePathsFromBaseline(ctx context.Context, g *git.Git, workDir, baselineSHA string, paths []string) error {
+	specs := make([]string, len(paths))
+	for i, p := range paths {
+		specs[i] = ":(literal)" + p
+	}
+	if err := g.RunCmd(ctx, workDir, append([]string{"rm", "-r", "-q", "-f", "--ignore-unmatch", "--"}, specs...)...); err != nil {
+		return err
+	}
+	if err := g.RunCmd(ctx, workDir, append([]string{"clean", "-f", "-d", "-q", "--"}, specs...)...); err != nil {
+		return err
+	}
+
+	// Only paths the baseline has can be checked out; one the agent created
+	// is fully restored by the removal above.
+	var inBaseline []string
+	for i, p := range paths {
+		obj := baselineSHA + ":" + p
+		if p == "." {
+			obj = baselineSHA + ":"
+		}
+		if _, err := g.Run(ctx, workDir, "cat-file", "-e", obj); err == nil {
+			inBaseline = append(inBaseline, specs[i])
+		}
+	}
+	if len(inBaseline) == 0 {
+		return nil
+	}
+	return g.RunCmd(ctx, workDir, append([]string{"checkout", baselineSHA, "--"}, inBaseline...)...)
+}
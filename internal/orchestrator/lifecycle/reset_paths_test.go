@@ -0,0 +1,116 @@
+// ABOUTME: Partial reset: restoring selected paths to the baseline while the
+// ABOUTME: rest of the agent's work survives, path validation, and refusals of
+// ABOUTME: targeted (paths / commit) resets.
+package lifecycle
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kstenerud/yoloai/internal/git"
+	"github.com/kstenerud/yoloai/internal/testutil"
+	"github.com/kstenerud/yoloai/runtime"
+	"github.com/kstenerud/yoloai/store"
+	"github.com/kstenerud/yoloai/yoerrors"
+)
+
+func readFile(t *testing.T, dir, name string) string {
+	t.Helper()
+	b, err := os.ReadFile(filepath.Join(dir, name)) //nolint:gosec // test path
+	require.NoError(t, err)
+	return string(b)
+}
+
+// writeNested writes name inside dir, creating its parent directories.
+func writeNested(t *testing.T, dir, name, content string) {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(dir, name)), 0o750))
+	testutil.WriteFile(t, dir, name, content)
+}
+
+func TestRestorePathsFromBaseline(t *testing.T) {
+	workDir := newWorkCopy(t, "top v1\n")
+	writeNested(t, workDir, "src/a.txt", "a v1\n")
+	writeNested(t, workDir, "src/gone.txt", "gone v1\n")
+	testutil.GitAdd(t, workDir, ".")
+	testutil.GitCommit(t, workDir, "more baseline")
+	baseline := gitHEAD(t, workDir)
+
+	// The agent edits, deletes and adds under src/ — committing some of it —
+	// and also edits a file outside it.
+	writeNested(t, workDir, "src/a.txt", "a by agent\n")
+	require.NoError(t, os.Remove(filepath.Join(workDir, "src/gone.txt")))
+	writeNested(t, workDir, "src/committed.txt", "new\n")
+	testutil.GitAdd(t, workDir, ".")
+	testutil.GitCommit(t, workDir, "agent work")
+	writeNested(t, workDir, "src/untracked.txt", "scratch\n")
+	writeNested(t, workDir, "newdir/x.txt", "x\n")
+	testutil.WriteFile(t, workDir, "file.txt", "top by agent\n")
+
+	g := git.NewTestHostWithEnv(testutil.GitEnv())
+	require.NoError(t, restorePathsFromBaseline(context.Background(), g, workDir, baseline, []string{"src", "newdir"}))
+
+	assert.Equal(t, "a v1\n", readFile(t, workDir, "src/a.txt"))
+	assert.Equal(t, "gone v1\n", readFile(t, workDir, "src/gone.txt"))
+	assert.NoFileExists(t, filepath.Join(workDir, "src/committed.txt"))
+	assert.NoFileExists(t, filepath.Join(workDir, "src/untracked.txt"))
+	assert.NoDirExists(t, filepath.Join(workDir, "newdir"))
+	assert.Equal(t, "top by agent\n", readFile(t, workDir, "file.txt"), "work outside the paths is kept")
+}
+
+func TestCleanResetPaths(t *testing.T) {
+	got, err := cleanResetPaths([]string{"src/foo/", " ./docs ", "."})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"src/foo", "docs", "."}, got)
+
+	for _, bad := range []string{"../outside", "/etc", "src/../../x"} {
+		_, err := cleanResetPaths([]string{bad})
+		var ue *yoerrors.UsageError
+		assert.ErrorAs(t, err, &ue, bad)
+	}
+}
+
+func TestReset_TargetedRefusals(t *testing.T) {
+	tmpDir := t.TempDir()
+	name := "test-reset-paths"
+	sandboxDir := filepath.Join(tmpDir, ".yoloai", "sandboxes", name)
+	workDir := filepath.Join(sandboxDir, "work", store.EncodePath("/tmp/project"))
+	require.NoError(t, os.MkdirAll(workDir, 0750))
+	testutil.InitGitRepo(t, workDir)
+	testutil.WriteFile(t, workDir, "file.txt", "v1\n")
+	testutil.GitAdd(t, workDir, ".")
+	testutil.GitCommit(t, workDir, "yoloai baseline")
+	require.NoError(t, store.SaveEnvironment(sandboxDir, &store.Environment{
+		Name: name,
+		Dirs: []store.DirEnvironment{{HostPath: "/tmp/project", MountPath: "/tmp/project", Mode: "copy", BaselineSHA: gitHEAD(t, workDir)}},
+	}))
+
+	stopped := &lifecycleMockRuntime{
+		inspectFn: func(_ context.Context, _ string) (runtime.InstanceInfo, error) {
+			return runtime.InstanceInfo{}, fmt.Errorf("not found: %w", runtime.ErrNotFound)
+		},
+	}
+	d := newLifecycleDeps(stopped, tmpDir)
+	var ue *yoerrors.UsageError
+
+	_, err := Reset(context.Background(), d, ResetOptions{Name: name, Paths: []string{"file.txt"}, Restart: true})
+	require.ErrorAs(t, err, &ue)
+	assert.Contains(t, err.Error(), "restart")
+
+	_, err = Reset(context.Background(), d, ResetOptions{Name: name, Paths: []string{"file.txt"}})
+	require.ErrorAs(t, err, &ue)
+	assert.Contains(t, err.Error(), "must be running")
+
+	_, err = Reset(context.Background(), d, ResetOptions{Name: name, To: "HEAD", Paths: []string{"file.txt"}})
+	require.ErrorAs(t, err, &ue)
+
+	_, err = Reset(context.Background(), d, ResetOptions{Name: name, To: "HEAD"})
+	require.ErrorAs(t, err, &ue)
+	assert.Contains(t, err.Error(), "must be running")
+}
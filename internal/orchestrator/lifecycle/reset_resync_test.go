@@ -19,6 +19,7 @@ import (
 	"github.com/kstenerud/yoloai/internal/testutil"
 	"github.com/kstenerud/yoloai/runtime"
 	"github.com/kstenerud/yoloai/store"
+	"github.com/kstenerud/yoloai/yoerrors"
 )
 
 // confinedBackend models a real backend: every one of them confines work-copy
@@ -29,7 +30,7 @@ func confinedBackend() *lifecycleMockRuntime {
 
 func resync(t *testing.T, dir store.DirEnvironment, workDir string) (string, error) {
 	t.Helper()
-	return resyncWorkCopy(context.Background(), git.NewTestHostWithEnv(testutil.GitEnv()), dir, workDir, confinedBackend())
+	return resyncWorkCopy(context.Background(), git.NewTestHostWithEnv(testutil.GitEnv()), "", dir, false, workDir, confinedBackend())
 }
 
 // makeWorktreeRepo builds a real repo with a linked worktree checked out on
@@ -154,6 +155,26 @@ func TestResyncWorkCopy_DiscardsAgentChangesAndPicksUpUpstream(t *testing.T) {
 // The work copy's directory is bind-mounted into a live container, so the sync
 // must overwrite in place. Replacing the directory would leave the agent looking
 // at a deleted inode for the rest of the session.
+// The reset copy brings the host's remotes back with its .git, so it must block
+// pushes to them again — and only when the sandbox was not made --allow-push.
+func TestResyncWorkCopy_BlocksPushUnlessAllowed(t *testing.T) {
+	src := secretRepo(t)
+	testutil.RunGit(t, src, "remote", "add", "origin", "https://example.com/repo.git")
+	g := git.NewTestHostWithEnv(testutil.GitEnv())
+	dir := store.DirEnvironment{HostPath: src, Mode: "copy"}
+
+	workDir := newWorkCopy(t, "v1\n")
+	_, err := resync(t, dir, workDir)
+	require.NoError(t, err)
+	assert.Equal(t, git.PushBlockedURL, testutil.RunGitOutput(t, workDir, "remote", "get-url", "--push", "origin"))
+	assert.Equal(t, "https://example.com/repo.git", testutil.RunGitOutput(t, workDir, "remote", "get-url", "origin"))
+
+	allowed := newWorkCopy(t, "v1\n")
+	_, err = resyncWorkCopy(context.Background(), g, "", dir, true, allowed, confinedBackend())
+	require.NoError(t, err)
+	assert.Equal(t, "https://example.com/repo.git", testutil.RunGitOutput(t, allowed, "remote", "get-url", "--push", "origin"))
+}
+
 func TestResyncWorkCopy_PreservesWorkDirInode(t *testing.T) {
 	src := secretRepo(t)
 	workDir := newWorkCopy(t, "v1\n")
@@ -343,7 +364,7 @@ func TestResetCopyWorkdir_SandboxSide_DefersBaseline(t *testing.T) {
 // resetAuxCopyDir (the restart-path aux dir) had no test before it moved onto
 // workcopy.Materialize. It must re-copy the aux dir from its host source,
 // discard the agent's leftovers, and record a fresh baseline — the same
-// WipeAndCopy the workdir gets.
+// InPlaceAndPrune sync the workdir gets.
 func TestResetAuxCopyDir_RebuildsFromHost(t *testing.T) {
 	auxSrc := filepath.Join(t.TempDir(), "auxsrc")
 	require.NoError(t, os.MkdirAll(auxSrc, 0o750))
@@ -363,8 +384,8 @@ func TestResetAuxCopyDir_RebuildsFromHost(t *testing.T) {
 	testutil.GitCommit(t, auxSrc, "aux upstream")
 
 	sha, err := resetAuxCopyDir(context.Background(),
-		git.NewTestHostWithEnv(testutil.GitEnv()), sandboxDir,
-		store.DirEnvironment{HostPath: auxSrc, Mode: "copy"}, confinedBackend())
+		git.NewTestHostWithEnv(testutil.GitEnv()), sandboxDir, "",
+		store.DirEnvironment{HostPath: auxSrc, Mode: "copy"}, false, confinedBackend())
 	require.NoError(t, err)
 
 	assert.Len(t, sha, 40, "HostSide backend records a baseline")
@@ -376,16 +397,24 @@ func TestResetAuxCopyDir_RebuildsFromHost(t *testing.T) {
 // must be reported clearly rather than surfacing as a copy failure.
 func TestResetAuxCopyDir_OriginalMissing(t *testing.T) {
 	_, err := resetAuxCopyDir(context.Background(),
-		git.NewTestHostWithEnv(testutil.GitEnv()), t.TempDir(),
-		store.DirEnvironment{HostPath: filepath.Join(t.TempDir(), "gone"), Mode: "copy"}, confinedBackend())
+		git.NewTestHostWithEnv(testutil.GitEnv()), t.TempDir(), "",
+		store.DirEnvironment{HostPath: filepath.Join(t.TempDir(), "gone"), Mode: "copy"}, false, confinedBackend())
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "original aux directory no longer exists")
 }
 
-// DF123: the default in-place reset used to refresh only the workdir, silently
-// skipping aux :copy dirs. It now resets them the way the restart path does —
-// this asserts both the workdir and the aux dir come back clean and current.
-func TestReset_InPlace_ResetsAuxCopyDir(t *testing.T) {
+// twoDirSandbox is a copy-mode sandbox with a workdir and one aux :copy dir,
+// each with an agent marker in its work copy and a new upstream file in its
+// source, plus a running-container mock so reset takes the in-place path.
+type twoDirSandbox struct {
+	tmp, name           string
+	workSrc, auxSrc     string
+	workDir, auxWorkDir string
+	mock                *lifecycleMockRuntime
+}
+
+func newTwoDirSandbox(t *testing.T, name string) twoDirSandbox {
+	t.Helper()
 	tmp := t.TempDir()
 	workSrc := secretRepo(t)
 	auxSrc := filepath.Join(t.TempDir(), "auxsrc")
@@ -395,7 +424,6 @@ func TestReset_InPlace_ResetsAuxCopyDir(t *testing.T) {
 	testutil.GitAdd(t, auxSrc, ".")
 	testutil.GitCommit(t, auxSrc, "aux initial")
 
-	name := "df123-fixed"
 	sandboxDir := filepath.Join(tmp, ".yoloai", "sandboxes", name)
 	workDir := filepath.Join(sandboxDir, "work", store.EncodePath(workSrc))
 	auxWorkDir := filepath.Join(sandboxDir, "work", store.EncodePath(auxSrc))
@@ -428,10 +456,53 @@ func TestReset_InPlace_ResetsAuxCopyDir(t *testing.T) {
 			return runtime.InstanceInfo{Running: true}, nil // in-place path
 		},
 	}
-	_, _ = Reset(context.Background(), newLifecycleDeps(mock, tmp), ResetOptions{Name: name})
+	return twoDirSandbox{tmp, name, workSrc, auxSrc, workDir, auxWorkDir, mock}
+}
+
+// DF123: the default in-place reset used to refresh only the workdir, silently
+// skipping aux :copy dirs. It now resets them the way the restart path does —
+// this asserts both the workdir and the aux dir come back clean and current.
+func TestReset_InPlace_ResetsAuxCopyDir(t *testing.T) {
+	sb := newTwoDirSandbox(t, "df123-fixed")
+	_, _ = Reset(context.Background(), newLifecycleDeps(sb.mock, sb.tmp), ResetOptions{Name: sb.name})
+
+	assert.False(t, exists(filepath.Join(sb.auxWorkDir, "agent.txt")), "aux agent change is reverted (DF123)")
+	assert.True(t, exists(filepath.Join(sb.auxWorkDir, "upstream.txt")), "aux upstream arrives (DF123)")
+	assert.False(t, exists(filepath.Join(sb.workDir, "agent.txt")), "workdir still reset too")
+	assert.True(t, exists(filepath.Join(sb.workDir, "upstream.txt")))
+}
+
+// A directory-scoped reset re-copies and re-baselines only the named dir; the
+// workdir keeps the agent's work and its baseline.
+func TestReset_InPlace_SelectedDirOnly(t *testing.T) {
+	sb := newTwoDirSandbox(t, "reset-one-dir")
+	d := newLifecycleDeps(sb.mock, sb.tmp)
+	sandboxDir := d.Layout.SandboxDir(sb.name)
+	before, err := store.LoadEnvironment(sandboxDir)
+	require.NoError(t, err)
+
+	_, _ = Reset(context.Background(), d, ResetOptions{Name: sb.name, Dirs: []string{sb.auxSrc}})
+
+	assert.False(t, exists(filepath.Join(sb.auxWorkDir, "agent.txt")), "selected aux dir is reset")
+	assert.True(t, exists(filepath.Join(sb.auxWorkDir, "upstream.txt")))
+	assert.True(t, exists(filepath.Join(sb.workDir, "agent.txt")), "unselected workdir keeps the agent's work")
+	assert.False(t, exists(filepath.Join(sb.workDir, "upstream.txt")))
+
+	after, err := store.LoadEnvironment(sandboxDir)
+	require.NoError(t, err)
+	assert.Equal(t, before.Workdir().BaselineSHA, after.Workdir().BaselineSHA)
+	assert.NotEqual(t, before.Dirs[1].BaselineSHA, after.Dirs[1].BaselineSHA, "aux dir is re-baselined")
+}
+
+func TestReset_DirsRefusals(t *testing.T) {
+	sb := newTwoDirSandbox(t, "reset-dir-refuse")
+	d := newLifecycleDeps(sb.mock, sb.tmp)
+	var ue *yoerrors.UsageError
+
+	_, err := Reset(context.Background(), d, ResetOptions{Name: sb.name, Dirs: []string{"/not/tracked"}})
+	require.ErrorAs(t, err, &ue)
 
-	assert.False(t, exists(filepath.Join(auxWorkDir, "agent.txt")), "aux agent change is reverted (DF123)")
-	assert.True(t, exists(filepath.Join(auxWorkDir, "upstream.txt")), "aux upstream arrives (DF123)")
-	assert.False(t, exists(filepath.Join(workDir, "agent.txt")), "workdir still reset too")
-	assert.True(t, exists(filepath.Join(workDir, "upstream.txt")))
+	_, err = Reset(context.Background(), d, ResetOptions{Name: sb.name, Dirs: []string{sb.auxSrc}, Paths: []string{"x"}})
+	require.ErrorAs(t, err, &ue)
+	assert.True(t, exists(filepath.Join(sb.auxWorkDir, "agent.txt")), "a refused reset touches nothing")
 }
@@ -0,0 +1,44 @@
+// ABOUTME: Reset to a commit: rewinds the work copy to one of the agent's own
+// ABOUTME: commits, dropping the bad ones after it, and tells the agent.
+package lifecycle
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/kstenerud/yoloai/copyflow"
+	"github.com/kstenerud/yoloai/internal/orchestrator/state"
+	"github.com/kstenerud/yoloai/store"
+	"github.com/kstenerud/yoloai/yoerrors"
+)
+
+// resetToNotification is pasted into the agent's session after a reset to a
+// commit; it's filled in with the target SHA, its subject, and the number of
+// commits dropped.
+const resetToNotification = "[yoloai] The workspace has been reset to commit %.12s (%s). " +
+	"The %d commit(s) after it and any uncommitted changes were discarded. " +
+	"Re-read files before assuming their contents."
+
+// resetTo rewinds the workdir's work copy to opts.To — the baseline or one of
+// the agent's unapplied commits — and tells the running agent. The baseline
+// doesn't move (see copyflow.RewindToCommit), and the cache and files
+// directories are untouched: this discards the tail of a session, not the
+// session.
+func resetTo(ctx context.Context, d state.Deps, opts ResetOptions, meta *store.Environment, sandboxDir string) (*ResetResult, error) {
+	if opts.Restart || opts.ClearState || len(opts.Paths) > 0 || len(opts.Dirs) > 0 {
+		return nil, yoerrors.NewUsageError("a reset to a commit can't be combined with restart, clear-state, paths, or dirs")
+	}
+	if err := requireRunning(ctx, d, opts.Name, sandboxDir, "reset to a commit"); err != nil {
+		return nil, err
+	}
+
+	slog.Info("rewinding work copy", "event", "sandbox.reset.to", "sandbox", opts.Name, "ref", opts.To)
+	rw, err := copyflow.RewindToCommit(ctx, d.Layout, d.Runtime, opts.Name, meta.Workdir().HostPath, opts.To)
+	if err != nil {
+		return nil, err
+	}
+	res := &ResetResult{Rewind: rw}
+	text := fmt.Sprintf(resetToNotification, rw.SHA, rw.Subject, len(rw.Dropped))
+	return res, sendResetNotification(ctx, d, opts.Name, sandboxDir, text, false, meta)
+}
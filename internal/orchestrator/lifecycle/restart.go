@@ -214,6 +214,7 @@ func recreateContainer(ctx context.Context, d state.Deps, name string, meta *sto
 		// reverting a brokered sandbox to direct key delivery (D106).
 		BrokerCredentials: meta.BrokerCredentials,
 		BrokerDisabled:    meta.BrokerDisabled,
+		Environment:       meta,
 		ConfigJSON:        configData,
 		Layout:            d.Layout,
 		HomeDir:           d.Layout.HomeDir,
@@ -298,7 +299,7 @@ func relaunchAgent(ctx context.Context, d state.Deps, name string, meta *store.E
 
 	socket := runtime.TmuxSocketFor(d.Runtime, d.Layout.SandboxDir(name))
 	if _, err := status.ExecInContainer(ctx, d.Runtime, name, meta, d.Layout.HostUID,
-		tmuxCmd(socket, "respawn-pane", "-t", "main", "-k", cfg.AgentCommand),
+		tmuxCmd(socket, "respawn-pane", "-t", "main:{start}", "-k", cfg.AgentCommand),
 	); err != nil {
 		return fmt.Errorf("relaunch agent: %w", err)
 	}
@@ -322,7 +323,7 @@ func relaunchAgentWithResume(ctx context.Context, d state.Deps, name string, met
 	interactiveCmd := invocation.BuildAgentCommand(agentDef, acfg.Model, "", agentArgs, cfg.Passthrough, false)
 	socket := runtime.TmuxSocketFor(d.Runtime, sandboxDir)
 	if _, err := status.ExecInContainer(ctx, d.Runtime, name, meta, d.Layout.HostUID,
-		tmuxCmd(socket, "respawn-pane", "-t", "main", "-k", interactiveCmd),
+		tmuxCmd(socket, "respawn-pane", "-t", "main:{start}", "-k", interactiveCmd),
 	); err != nil {
 		return fmt.Errorf("relaunch agent: %w", err)
 	}
@@ -355,7 +356,7 @@ func relaunchAgentWithCustomPrompt(ctx context.Context, d state.Deps, name strin
 	interactiveCmd = cfg.AgentLaunchPrefix + interactiveCmd
 	socket := runtime.TmuxSocketFor(d.Runtime, d.Layout.SandboxDir(name))
 	if _, err := status.ExecInContainer(ctx, d.Runtime, name, meta, d.Layout.HostUID,
-		tmuxCmd(socket, "respawn-pane", "-t", "main", "-k", interactiveCmd),
+		tmuxCmd(socket, "respawn-pane", "-t", "main:{start}", "-k", interactiveCmd),
 	); err != nil {
 		return fmt.Errorf("relaunch agent: %w", err)
 	}
@@ -371,7 +372,7 @@ func buildReadyWaitScript(cfg runtimeconfig.ContainerConfig) string {
 	case cfg.ReadyPattern != "":
 		// Poll tmux capture-pane output for the ready pattern.
 		return fmt.Sprintf(`for i in $(seq 1 60); do
-    if _tmux capture-pane -t main -p 2>/dev/null | grep -q '%s'; then
+    if _tmux capture-pane -t main:{start} -p 2>/dev/null | grep -q '%s'; then
         break
     fi
     sleep 1
@@ -408,10 +409,10 @@ func deliverPromptViaTmux(ctx context.Context, d state.Deps, name string, cfg ru
 %s
 printf '%%s' "$1" > %s
 _tmux load-buffer %s
-_tmux paste-buffer -p -t main
+_tmux paste-buffer -p -t main:{start}
 sleep 0.5
 for key in %s; do
-    _tmux send-keys -t main "$key"
+    _tmux send-keys -t main:{start} "$key"
     sleep 0.2
 done
 rm -f %s`, tmuxShellPrefix(socket), buildReadyWaitScript(cfg), tmpFile, tmpFile, cfg.SubmitSequence, tmpFile)
@@ -1,13 +1,16 @@
 // ABOUTME: Helpers for patching runtime-config.json in-place. A single shared
 // ABOUTME: read-unmarshal-mutate-marshal-write helper, with thin wrappers for
-// ABOUTME: the three fields that need patching (vscode tunnel, debug, domains).
+// ABOUTME: the fields that need patching (vscode tunnel, debug, domains, clone identity).
 package lifecycle
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io/fs"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/kstenerud/yoloai/internal/fileutil"
 	"github.com/kstenerud/yoloai/internal/orchestrator/invocation"
@@ -71,3 +74,22 @@ func patchConfigDebug(sandboxDir string, debug bool) error {
 func PatchConfigAllowedDomains(sandboxDir string, domains []string) error {
 	return patchRuntimeConfig(sandboxDir, func(cfg *runtimeconfig.ContainerConfig) { cfg.AllowedDomains = domains })
 }
+
+// PatchConfigForClone points a cloned sandbox's runtime-config.json at its own
+// identity: the sandbox name (terminal title), the VS Code tunnel name (two
+// tunnels can't share one), and a tmux socket that lived in the source's
+// sandbox dir. A sandbox without runtime-config.json is left alone.
+func PatchConfigForClone(sandboxDir, srcDir, name string) error {
+	if _, err := os.Stat(filepath.Join(sandboxDir, store.RuntimeConfigFile)); errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	return patchRuntimeConfig(sandboxDir, func(cfg *runtimeconfig.ContainerConfig) {
+		cfg.SandboxName = name
+		if cfg.VscodeTunnelName != "" {
+			cfg.VscodeTunnelName = invocation.SanitizeTunnelName(name)
+		}
+		if rel, ok := strings.CutPrefix(cfg.TmuxSocket, srcDir+string(filepath.Separator)); ok {
+			cfg.TmuxSocket = filepath.Join(sandboxDir, rel)
+		}
+	})
+}
@@ -361,6 +361,20 @@ func start(ctx context.Context, d state.Deps, name string, opts StartOptions, n
 	}
 	slog.Debug("container status", "event", "sandbox.start.status", "sandbox", name, "status", string(st))
 
+	// Trim the agent's state to its resources.agent_state cap while nothing is
+	// writing it, before the disk check it may bring the sandbox under.
+	if !agentMayBeWriting(st) {
+		trimStateForStart(d, name, sandboxDir, meta)
+	}
+
+	// A sandbox over its resources.disk limit doesn't start; one already running
+	// is left alone.
+	if st != status.StatusActive && st != status.StatusIdle {
+		if err := launch.CheckDiskLimit(sandboxDir, name, meta.Resources); err != nil {
+			return err
+		}
+	}
+
 	promptText, customPrompt, err := preparePromptForStart(opts, sandboxDir, meta, d.Layout.HomeDir, d.Layout.Env().EnvForConfigInterpolation(), d.Input)
 	if err != nil {
 		return err
@@ -374,6 +388,9 @@ func start(ctx context.Context, d state.Deps, name string, opts StartOptions, n
 	case status.StatusDone, status.StatusFailed:
 		return handleTerminalStatus(ctx, d, name, meta, opts, promptText, customPrompt, n)
 
+	case status.StatusPaused:
+		return yoerrors.NewUsageError("sandbox %s is paused — run 'yoloai unpause %s' to resume it", name, name)
+
 	case status.StatusSuspended:
 		return handleSuspendedResume(ctx, d, cname, name, meta, opts, promptText, customPrompt, n)
 
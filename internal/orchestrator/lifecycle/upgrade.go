@@ -0,0 +1,180 @@
+// ABOUTME: In-place agent upgrade: reinstalls the agent's npm package inside a
+// ABOUTME: running sandbox, records old/new versions in agent.json, and relaunches it.
+package lifecycle
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"time"
+
+	"github.com/kstenerud/yoloai/internal/agent"
+	"github.com/kstenerud/yoloai/internal/orchestrator/agentcfg"
+	"github.com/kstenerud/yoloai/internal/orchestrator/invocation"
+	"github.com/kstenerud/yoloai/internal/orchestrator/state"
+	"github.com/kstenerud/yoloai/internal/orchestrator/status"
+	"github.com/kstenerud/yoloai/runtime"
+	"github.com/kstenerud/yoloai/store"
+	"github.com/kstenerud/yoloai/yoerrors"
+)
+
+// UpgradeOptions configures UpgradeAgent.
+type UpgradeOptions struct {
+	Name string
+	// Version is the npm version or dist-tag to install ("2.1.30", "next").
+	// "" means "latest".
+	Version string
+}
+
+// UpgradeResult reports the outcome of UpgradeAgent.
+type UpgradeResult struct {
+	Notices []Notice
+	// From and To are the agent versions before and after the install.
+	From string
+	To   string
+	// Relaunched reports whether the agent was restarted on the new version;
+	// false when the install left the version unchanged.
+	Relaunched bool
+}
+
+// npmVersionRe bounds what UpgradeOptions.Version may be: an npm version,
+// range-free, or a dist-tag. It keeps the argument from being read as a URL,
+// path, or git spec by `npm install`.
+var npmVersionRe = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._+-]*$`)
+
+// UpgradeAgent reinstalls the sandbox's agent CLI at opts.Version inside the
+// running container and relaunches it in its tmux pane, continuing the prior
+// conversation when the agent has a resume flag. The agent's state directory
+// is a host mount, so it survives. The install lives in the container: a
+// recreate (stop/start, reset --restart) goes back to the image's version.
+func UpgradeAgent(ctx context.Context, d state.Deps, opts UpgradeOptions) (*UpgradeResult, error) {
+	unlock, err := store.AcquireLock(d.Layout, opts.Name)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	sandboxDir := d.Layout.SandboxDir(opts.Name)
+	if err := store.RequireSandboxDir(sandboxDir); err != nil {
+		return nil, err
+	}
+	meta, err := store.LoadEnvironment(sandboxDir)
+	if err != nil {
+		return nil, err
+	}
+	agentDef, acfg, err := requireAgent(d, opts.Name)
+	if err != nil {
+		return nil, err
+	}
+	if agentDef.NPMPackage == "" {
+		return nil, yoerrors.NewUsageError("the %s agent isn't installed from npm and can't be upgraded in place", acfg.AgentType)
+	}
+	if !d.Runtime.Descriptor().AgentProvisionedByBackend {
+		return nil, yoerrors.NewUsageError("the %s backend runs the host's %s — upgrade it on the host", meta.BackendType, acfg.AgentType)
+	}
+	version := opts.Version
+	if version == "" {
+		version = "latest"
+	}
+	if !npmVersionRe.MatchString(version) {
+		return nil, yoerrors.NewUsageError("invalid agent version %q", version)
+	}
+	if err := requireRunning(ctx, d, opts.Name, sandboxDir, "upgrade its agent"); err != nil {
+		return nil, err
+	}
+
+	from, err := npmInstalledVersion(ctx, d, opts.Name, meta, agentDef.NPMPackage)
+	if err != nil {
+		return nil, err
+	}
+	slog.Info("upgrading agent", "event", "sandbox.upgrade", "sandbox", opts.Name, "package", agentDef.NPMPackage, "from", from, "version", version)
+	if _, err := d.Runtime.Exec(ctx, store.InstanceName(meta.Principal, opts.Name),
+		[]string{"npm", "install", "-g", agentDef.NPMPackage + "@" + version}, "root"); err != nil {
+		return nil, fmt.Errorf("install %s@%s: %w", agentDef.NPMPackage, version, err)
+	}
+	to, err := npmInstalledVersion(ctx, d, opts.Name, meta, agentDef.NPMPackage)
+	if err != nil {
+		return nil, err
+	}
+
+	var n notices
+	res := &UpgradeResult{From: from, To: to}
+	if to == from {
+		n.infof("%s is already at %s", acfg.AgentType, to)
+		res.Notices = n.list
+		return res, nil
+	}
+
+	acfg.Upgrades = append(acfg.Upgrades, agentcfg.AgentUpgrade{From: from, To: to, At: time.Now().UTC()})
+	if err := agentcfg.Save(sandboxDir, acfg); err != nil {
+		return nil, err
+	}
+	resumed, err := relaunchAgentContinuing(ctx, d, opts.Name, meta, agentDef, acfg)
+	if err != nil {
+		return nil, err
+	}
+	res.Relaunched = true
+	n.infof("Upgraded %s %s → %s and relaunched it", acfg.AgentType, from, to)
+	if !resumed {
+		n.warnf("%s has no resume flag, so it started a fresh conversation (its state directory is kept)", acfg.AgentType)
+	}
+	n.warnf("The upgrade lasts until the container is recreated (stop/start or reset --restart); run 'yoloai upgrade %s' again after that", opts.Name)
+	res.Notices = n.list
+	return res, nil
+}
+
+// npmInstalledVersion reads the globally installed version of pkg inside the
+// sandbox. A package npm doesn't know about means the image provisioned the
+// agent some other way (e.g. Tart's native installer).
+func npmInstalledVersion(ctx context.Context, d state.Deps, name string, meta *store.Environment, pkg string) (string, error) {
+	res, err := d.Runtime.Exec(ctx, store.InstanceName(meta.Principal, name),
+		[]string{"npm", "ls", "-g", "--depth=0", "--json", pkg}, "root")
+	if err != nil {
+		return "", yoerrors.NewUsageError("%s isn't installed through npm in sandbox %q, so it can't be upgraded in place (%v)", pkg, name, err)
+	}
+	return parseNPMListVersion(res.Stdout, pkg)
+}
+
+// parseNPMListVersion extracts pkg's version from `npm ls --json` output.
+func parseNPMListVersion(out, pkg string) (string, error) {
+	var listing struct {
+		Dependencies map[string]struct {
+			Version string `json:"version"`
+		} `json:"dependencies"`
+	}
+	if err := json.Unmarshal([]byte(out), &listing); err != nil {
+		return "", fmt.Errorf("parse npm ls output: %w", err)
+	}
+	dep, ok := listing.Dependencies[pkg]
+	if !ok || dep.Version == "" {
+		return "", fmt.Errorf("npm ls did not report a version for %s", pkg)
+	}
+	return dep.Version, nil
+}
+
+// relaunchAgentContinuing respawns the agent's tmux pane in interactive mode
+// with its native resume flag, so the relaunched agent picks up the prior
+// conversation instead of re-running the prompt. It reports whether a resume
+// flag was used; an agent without one starts a fresh session.
+func relaunchAgentContinuing(ctx context.Context, d state.Deps, name string, meta *store.Environment, agentDef *agent.Definition, acfg *agentcfg.AgentConfig) (bool, error) {
+	sandboxDir := d.Layout.SandboxDir(name)
+	cfg, err := loadContainerConfig(sandboxDir)
+	if err != nil {
+		return false, err
+	}
+	agentArgs := resolveAgentArgs(d.Layout, acfg.AgentType, meta.Profile)
+	cmd := invocation.BuildAgentCommand(agentDef, acfg.Model, "", agentArgs, cfg.Passthrough, false)
+	resumed := false
+	if resumeCmd := invocation.ResolveResumeCommand(cmd, agentDef.ResumeFlag); resumeCmd != "" {
+		cmd, resumed = resumeCmd, true
+	}
+	socket := runtime.TmuxSocketFor(d.Runtime, sandboxDir)
+	if _, err := status.ExecInContainer(ctx, d.Runtime, name, meta, d.Layout.HostUID,
+		tmuxCmd(socket, "respawn-pane", "-t", "main:{start}", "-k", cmd),
+	); err != nil {
+		return false, fmt.Errorf("relaunch agent: %w", err)
+	}
+	return resumed, nil
+}
@@ -0,0 +1,119 @@
+// ABOUTME: In-place agent upgrade: npm version parsing, the install-then-relaunch
+// ABOUTME: flow with its agent.json record, and refusals.
+package lifecycle
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kstenerud/yoloai/internal/orchestrator/agentcfg"
+	"github.com/kstenerud/yoloai/runtime"
+	"github.com/kstenerud/yoloai/store"
+	"github.com/kstenerud/yoloai/yoerrors"
+)
+
+func TestParseNPMListVersion(t *testing.T) {
+	out := `{"name":"lib","dependencies":{"@anthropic-ai/claude-code":{"version":"2.1.3","overridden":false}}}`
+	v, err := parseNPMListVersion(out, "@anthropic-ai/claude-code")
+	require.NoError(t, err)
+	assert.Equal(t, "2.1.3", v)
+
+	_, err = parseNPMListVersion(`{"name":"lib"}`, "@anthropic-ai/claude-code")
+	assert.Error(t, err)
+}
+
+// upgradeRuntime is a running container whose npm reports installed, moving to
+// next on `npm install`; every exec is recorded.
+func upgradeRuntime(installed, next string, execs *[][]string) *lifecycleMockRuntime {
+	return &lifecycleMockRuntime{
+		agentProvisioned: true,
+		inspectFn: func(_ context.Context, _ string) (runtime.InstanceInfo, error) {
+			return runtime.InstanceInfo{Running: true}, nil
+		},
+		execFn: func(_ context.Context, _ string, cmd []string, _ string) (runtime.ExecResult, error) {
+			*execs = append(*execs, cmd)
+			switch {
+			case len(cmd) > 1 && cmd[0] == "npm" && cmd[1] == "ls":
+				return runtime.ExecResult{Stdout: `{"dependencies":{"@anthropic-ai/claude-code":{"version":"` + installed + `"}}}`}, nil
+			case len(cmd) > 1 && cmd[0] == "npm" && cmd[1] == "install":
+				installed = next
+			}
+			return runtime.ExecResult{}, nil
+		},
+	}
+}
+
+func TestUpgradeAgent_InstallsRecordsAndRelaunches(t *testing.T) {
+	tmpDir := t.TempDir()
+	name := "upgrade-me"
+	createTestSandbox(t, tmpDir, name, "/tmp/project", "copy")
+	sandboxDir := filepath.Join(tmpDir, ".yoloai", "sandboxes", name)
+	require.NoError(t, os.WriteFile(filepath.Join(sandboxDir, store.RuntimeConfigFile), []byte(`{}`), 0600))
+
+	var execs [][]string
+	d := newLifecycleDeps(upgradeRuntime("2.0.0", "2.1.0", &execs), tmpDir)
+	res, err := UpgradeAgent(context.Background(), d, UpgradeOptions{Name: name, Version: "2.1.0"})
+	require.NoError(t, err)
+	assert.Equal(t, "2.0.0", res.From)
+	assert.Equal(t, "2.1.0", res.To)
+	assert.True(t, res.Relaunched)
+
+	assert.Contains(t, execs, []string{"npm", "install", "-g", "@anthropic-ai/claude-code@2.1.0"})
+	last := strings.Join(execs[len(execs)-1], " ")
+	assert.Contains(t, last, "respawn-pane")
+	assert.Contains(t, last, "--continue", "claude resumes its conversation")
+
+	acfg, err := agentcfg.Load(sandboxDir)
+	require.NoError(t, err)
+	require.Len(t, acfg.Upgrades, 1)
+	assert.Equal(t, "2.0.0", acfg.Upgrades[0].From)
+	assert.Equal(t, "2.1.0", acfg.Upgrades[0].To)
+}
+
+func TestUpgradeAgent_AlreadyCurrent(t *testing.T) {
+	tmpDir := t.TempDir()
+	name := "current"
+	createTestSandbox(t, tmpDir, name, "/tmp/project", "copy")
+
+	var execs [][]string
+	d := newLifecycleDeps(upgradeRuntime("2.1.0", "2.1.0", &execs), tmpDir)
+	res, err := UpgradeAgent(context.Background(), d, UpgradeOptions{Name: name})
+	require.NoError(t, err)
+	assert.False(t, res.Relaunched)
+	assert.Contains(t, execs, []string{"npm", "install", "-g", "@anthropic-ai/claude-code@latest"})
+
+	acfg, err := agentcfg.Load(filepath.Join(tmpDir, ".yoloai", "sandboxes", name))
+	require.NoError(t, err)
+	assert.Empty(t, acfg.Upgrades, "no version change, nothing recorded")
+}
+
+func TestUpgradeAgent_Refusals(t *testing.T) {
+	tmpDir := t.TempDir()
+	name := "refuse"
+	createTestSandbox(t, tmpDir, name, "/tmp/project", "copy")
+	sandboxDir := filepath.Join(tmpDir, ".yoloai", "sandboxes", name)
+	var execs [][]string
+	var ue *yoerrors.UsageError
+
+	_, err := UpgradeAgent(context.Background(), newLifecycleDeps(upgradeRuntime("1", "2", &execs), tmpDir), UpgradeOptions{Name: name, Version: "https://evil/x.tgz"})
+	require.ErrorAs(t, err, &ue)
+
+	hostAgent := upgradeRuntime("1", "2", &execs)
+	hostAgent.agentProvisioned = false
+	_, err = UpgradeAgent(context.Background(), newLifecycleDeps(hostAgent, tmpDir), UpgradeOptions{Name: name})
+	require.ErrorAs(t, err, &ue)
+	assert.Contains(t, err.Error(), "on the host")
+
+	require.NoError(t, agentcfg.Save(sandboxDir, &agentcfg.AgentConfig{AgentType: "aider"}))
+	_, err = UpgradeAgent(context.Background(), newLifecycleDeps(upgradeRuntime("1", "2", &execs), tmpDir), UpgradeOptions{Name: name})
+	require.ErrorAs(t, err, &ue)
+	assert.Contains(t, err.Error(), "npm")
+
+	assert.Empty(t, execs, "refusals happen before anything runs in the container")
+}
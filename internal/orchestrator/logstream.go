@@ -1,6 +1,6 @@
 // ABOUTME: Host-side activity-stream transport — merges the per-sandbox JSONL
-// ABOUTME: log sources (cli/sandbox/monitor/hooks) into a time-ordered frame
-// ABOUTME: stream, with optional follow (tail-poll) and terminal done-detection.
+// ABOUTME: log sources (cli/sandbox/monitor/hooks, plus runtime output on request)
+// ABOUTME: into a time-ordered frame stream, with optional follow and done-detection.
 package orchestrator
 
 import (
@@ -9,12 +9,14 @@ import (
 	"encoding/json"
 	"io"
 	"os"
+	"slices"
 	"sort"
 	"strings"
 	"sync"
 	"time"
 
 	"github.com/kstenerud/yoloai/internal/config"
+	"github.com/kstenerud/yoloai/runtime"
 	"github.com/kstenerud/yoloai/store"
 	"github.com/kstenerud/yoloai/yoerrors"
 )
@@ -33,7 +35,9 @@ type LogFrame struct {
 
 // LogStreamOptions selects and filters the frames StreamLogs emits.
 type LogStreamOptions struct {
-	// Sources limits the streamed sources; empty means all four.
+	// Sources limits the streamed sources; empty means the four JSONL files.
+	// store.LogSourceRuntime is only streamed when named here, and only through
+	// Engine.StreamLogs, which can reach the backend.
 	Sources []store.LogSource
 	// MinLevel drops frames below this level ("debug" < "info" < "warn" <
 	// "error"). Empty means no level filter. An unknown value is a *UsageError.
@@ -43,6 +47,10 @@ type LogStreamOptions struct {
 	// Follow keeps the stream open after the backlog, tailing each source for
 	// new lines until the agent reaches a terminal state or ctx is cancelled.
 	Follow bool
+
+	// runtimeLines fetches the instance's runtime output after a given time.
+	// Set by Engine.StreamLogs when LogSourceRuntime is requested.
+	runtimeLines func(ctx context.Context, since time.Time) ([]runtime.LogLine, error)
 }
 
 // allLogSources is the canonical source order for backlog merging and the
@@ -104,11 +112,22 @@ func StreamLogs(ctx context.Context, layout config.Layout, name string, opts Log
 		}
 	}
 
+	var rt *runtimeSource
+	if opts.runtimeLines != nil && slices.Contains(srcSet, store.LogSourceRuntime) {
+		rt = &runtimeSource{fetch: opts.runtimeLines}
+	}
+
 	out := make(chan LogFrame, 64)
 	go func() {
 		defer close(out)
 
 		backlog := readBacklog(srcs, minLevel, opts.Since)
+		if rt != nil {
+			backlog = append(backlog, rt.poll(ctx, minLevel, opts.Since)...)
+			sort.SliceStable(backlog, func(i, j int) bool {
+				return backlog[i].Time.Before(backlog[j].Time)
+			})
+		}
 		for _, f := range backlog {
 			select {
 			case out <- f:
@@ -118,12 +137,77 @@ func StreamLogs(ctx context.Context, layout config.Layout, name string, opts Log
 		}
 
 		if opts.Follow {
-			followLogs(ctx, sandboxDir, srcs, minLevel, opts.Since, out)
+			followLogs(ctx, sandboxDir, srcs, rt, minLevel, opts.Since, out)
 		}
 	}()
 	return out, nil
 }
 
+// StreamLogs is the package StreamLogs with store.LogSourceRuntime wired to
+// this Engine's backend. The backend is opened only when the runtime source is
+// requested, and best-effort: without one (or on a backend that keeps no
+// runtime log) that source is simply empty.
+func (e *Engine) StreamLogs(ctx context.Context, name string, opts LogStreamOptions) (<-chan LogFrame, error) {
+	if slices.Contains(opts.Sources, store.LogSourceRuntime) {
+		e.TryEnsure(ctx)
+		if rt := e.runtime; rt != nil {
+			instance := store.InstanceName(e.layout.Principal, name)
+			opts.runtimeLines = func(ctx context.Context, since time.Time) ([]runtime.LogLine, error) {
+				lines, _, err := runtime.LogLinesFor(ctx, rt, instance, since)
+				return lines, err
+			}
+		}
+	}
+	return StreamLogs(ctx, e.layout, name, opts)
+}
+
+// runtimeSource turns the backend's runtime output into frames. last is the
+// newest line already emitted, so each poll asks only for what came after.
+type runtimeSource struct {
+	fetch func(ctx context.Context, since time.Time) ([]runtime.LogLine, error)
+	last  time.Time
+}
+
+// poll fetches the runtime lines newer than both since and the last poll and
+// returns them as frames. A fetch error (the container is gone, the daemon is
+// unreachable) yields no frames: runtime output is a best-effort addition to
+// the sandbox's own logs.
+func (r *runtimeSource) poll(ctx context.Context, minLevel int, since time.Time) []LogFrame {
+	after := since
+	if r.last.After(after) {
+		after = r.last
+	}
+	lines, err := r.fetch(ctx, after)
+	if err != nil {
+		return nil
+	}
+	var frames []LogFrame
+	for _, l := range lines {
+		if l.Time.After(r.last) {
+			r.last = l.Time
+		}
+		if f, ok := runtimeFrame(l, minLevel, since); ok {
+			frames = append(frames, f)
+		}
+	}
+	return frames
+}
+
+// runtimeFrame wraps one runtime output line in the same JSONL shape the
+// sandbox's own logs use, so consumers render every source alike.
+func runtimeFrame(l runtime.LogLine, minLevel int, since time.Time) (LogFrame, bool) {
+	raw, err := json.Marshal(struct {
+		Ts    string `json:"ts"`
+		Level string `json:"level"`
+		Event string `json:"event"`
+		Msg   string `json:"msg"`
+	}{l.Time.UTC().Format("2006-01-02T15:04:05.000Z"), "info", "runtime.output", l.Text})
+	if err != nil {
+		return LogFrame{}, false
+	}
+	return frameFromLine(raw, store.LogSourceRuntime, minLevel, since)
+}
+
 // readBacklog reads every source fully, applies the level/since filters, and
 // returns the frames merged into a single time-ordered slice.
 func readBacklog(srcs []sourcePath, minLevel int, since time.Time) []LogFrame {
@@ -235,11 +319,11 @@ func parseMinLevel(s string) (int, error) {
 
 // followLogs tails each source concurrently after the backlog, forwarding new
 // frames to out until the agent reaches a terminal state or ctx is cancelled.
-func followLogs(ctx context.Context, sandboxDir string, srcs []sourcePath, minLevel int, since time.Time, out chan<- LogFrame) {
+func followLogs(ctx context.Context, sandboxDir string, srcs []sourcePath, rt *runtimeSource, minLevel int, since time.Time, out chan<- LogFrame) {
 	tctx, cancel := context.WithCancel(ctx)
 	defer cancel()
 
-	frames := startTailers(tctx, srcs, minLevel, since)
+	frames := startTailers(tctx, srcs, rt, minLevel, since)
 
 	doneCheck := time.NewTicker(2 * time.Second)
 	defer doneCheck.Stop()
@@ -262,9 +346,10 @@ func followLogs(ctx context.Context, sandboxDir string, srcs []sourcePath, minLe
 	}
 }
 
-// startTailers launches one tail goroutine per source and returns a fan-in
-// channel that is closed once every tailer has stopped (tctx cancelled).
-func startTailers(tctx context.Context, srcs []sourcePath, minLevel int, since time.Time) <-chan LogFrame {
+// startTailers launches one tail goroutine per source (and one for the runtime
+// output when rt is set) and returns a fan-in channel that is closed once every
+// tailer has stopped (tctx cancelled).
+func startTailers(tctx context.Context, srcs []sourcePath, rt *runtimeSource, minLevel int, since time.Time) <-chan LogFrame {
 	frames := make(chan LogFrame, 64)
 	var wg sync.WaitGroup
 	for _, sp := range srcs {
@@ -274,6 +359,13 @@ func startTailers(tctx context.Context, srcs []sourcePath, minLevel int, since t
 			tailSource(tctx, sp, initialOffset(sp.path), minLevel, since, frames)
 		}(sp)
 	}
+	if rt != nil {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			tailRuntime(tctx, rt, minLevel, since, frames)
+		}()
+	}
 	go func() { wg.Wait(); close(frames) }()
 	return frames
 }
@@ -321,6 +413,28 @@ func tailSource(ctx context.Context, sp sourcePath, offset int64, minLevel int,
 	}
 }
 
+// tailRuntime polls the runtime output every second, forwarding new frames to
+// ch until ctx is cancelled. Slower than the file tailers: each poll is a round
+// trip to the backend.
+func tailRuntime(ctx context.Context, rt *runtimeSource, minLevel int, since time.Time, ch chan<- LogFrame) {
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		for _, f := range rt.poll(ctx, minLevel, since) {
+			select {
+			case ch <- f:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}
+}
+
 // pollSource reads new lines from offset, forwarding matching frames to ch, and
 // returns the offset past the last consumed line.
 func pollSource(ctx context.Context, sp sourcePath, offset int64, minLevel int, since time.Time, ch chan<- LogFrame) int64 {
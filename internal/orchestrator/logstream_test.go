@@ -1,5 +1,5 @@
 // ABOUTME: Tests for StreamLogs — backlog merge-sort across sources, level and
-// ABOUTME: since filtering, missing-sandbox/invalid-level errors, channel close.
+// ABOUTME: since filtering, missing-sandbox/invalid-level errors, channel close, runtime output.
 package orchestrator
 
 import (
@@ -13,6 +13,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/kstenerud/yoloai/internal/config"
+	"github.com/kstenerud/yoloai/runtime"
 	"github.com/kstenerud/yoloai/store"
 	"github.com/kstenerud/yoloai/yoerrors"
 )
@@ -187,3 +188,52 @@ func TestStreamLogs_InvalidLinesSkipped(t *testing.T) {
 	require.Len(t, frames, 1)
 	assert.Contains(t, string(frames[0].Raw), `"event":"ok"`)
 }
+
+func TestStreamLogs_RuntimeMergedWhenRequested(t *testing.T) {
+	layout, name := logStreamLayout(t)
+	writeJSONL(t, store.CLIJSONLPath(layout.SandboxDir(name)),
+		`{"ts":"2026-03-15T10:00:00.000Z","level":"info","event":"c0"}`+"\n"+
+			`{"ts":"2026-03-15T10:00:02.000Z","level":"info","event":"c2"}`+"\n")
+	lines := func(_ context.Context, _ time.Time) ([]runtime.LogLine, error) {
+		return []runtime.LogLine{{Time: time.Date(2026, 3, 15, 10, 0, 1, 0, time.UTC), Text: `entrypoint "ready"`}}, nil
+	}
+
+	// Without the runtime source named, the fetcher is never used.
+	ch, err := StreamLogs(context.Background(), layout, name, LogStreamOptions{runtimeLines: lines})
+	require.NoError(t, err)
+	assert.Len(t, drain(t, ch), 2)
+
+	ch, err = StreamLogs(context.Background(), layout, name, LogStreamOptions{
+		Sources:      []store.LogSource{store.LogSourceCLI, store.LogSourceRuntime},
+		runtimeLines: lines,
+	})
+	require.NoError(t, err)
+	frames := drain(t, ch)
+	require.Len(t, frames, 3)
+	assert.Equal(t, store.LogSourceRuntime, frames[1].Source)
+	assert.JSONEq(t, `{"ts":"2026-03-15T10:00:01.000Z","level":"info","event":"runtime.output","msg":"entrypoint \"ready\""}`,
+		string(frames[1].Raw))
+}
+
+func TestRuntimeSource_PollsOnlyNewLines(t *testing.T) {
+	t0 := time.Date(2026, 3, 15, 10, 0, 0, 0, time.UTC)
+	var asked []time.Time
+	all := []runtime.LogLine{{Time: t0, Text: "a"}, {Time: t0.Add(time.Second), Text: "b"}}
+	rt := &runtimeSource{fetch: func(_ context.Context, since time.Time) ([]runtime.LogLine, error) {
+		asked = append(asked, since)
+		var out []runtime.LogLine
+		for _, l := range all {
+			if since.IsZero() || l.Time.After(since) {
+				out = append(out, l)
+			}
+		}
+		return out, nil
+	}}
+
+	assert.Len(t, rt.poll(context.Background(), 0, time.Time{}), 2)
+	all = append(all, runtime.LogLine{Time: t0.Add(2 * time.Second), Text: "c"})
+	frames := rt.poll(context.Background(), 0, time.Time{})
+	require.Len(t, frames, 1)
+	assert.Contains(t, string(frames[0].Raw), `"msg":"c"`)
+	assert.Equal(t, []time.Time{{}, t0.Add(time.Second)}, asked)
+}
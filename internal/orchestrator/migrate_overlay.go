@@ -171,7 +171,7 @@ func classifyOverlay(name string, st status.Status, goos string) migrate.Op {
 		return migrate.Op{Description: "abandon stopped overlay sandbox " + name + " (Linux: recoverable — downgrade + start it first, or proceed to abandon its overlay changes)", Auth: migrate.AuthAbandonOverlay, Sandbox: name}
 	case status.StatusRemoved:
 		return migrate.Op{Description: "abandon overlay sandbox " + name + " (container removed; overlay changes unrecoverable)", Auth: migrate.AuthAbandonOverlay, Sandbox: name}
-	default: // Broken, Unavailable, Suspended
+	default: // Broken, Unavailable, Suspended, Paused (frozen: can't capture)
 		return migrate.Op{Description: "quarantine sandbox " + name + " (cannot audit — repair it or start the backend, then re-run migrate)", Auth: migrate.AuthConfirm, Sandbox: name}
 	}
 }
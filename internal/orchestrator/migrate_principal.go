@@ -352,7 +352,7 @@ func backendHasInstance(rt runtime.Backend) bool {
 // state that a recreate would destroy.
 func isInstanceUp(st status.Status) bool {
 	switch st {
-	case status.StatusActive, status.StatusIdle, status.StatusDone, status.StatusFailed, status.StatusSuspended:
+	case status.StatusActive, status.StatusIdle, status.StatusDone, status.StatusFailed, status.StatusSuspended, status.StatusPaused:
 		return true
 	default:
 		return false
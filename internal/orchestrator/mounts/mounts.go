@@ -16,6 +16,8 @@ import (
 	"github.com/kstenerud/yoloai/internal/config"
 	"github.com/kstenerud/yoloai/internal/fileutil"
 	"github.com/kstenerud/yoloai/internal/orchestrator/state"
+	"github.com/kstenerud/yoloai/internal/orchestrator/workcopy"
+	"github.com/kstenerud/yoloai/internal/workspace"
 	"github.com/kstenerud/yoloai/runtime"
 	"github.com/kstenerud/yoloai/store"
 )
@@ -36,10 +38,10 @@ func Build(st *state.State, secretsDir string) []runtime.MountSpec {
 func buildWorkdirMounts(st *state.State) []runtime.MountSpec {
 	switch st.Workdir.Mode {
 	case "copy":
-		return []runtime.MountSpec{{
+		return append([]runtime.MountSpec{{
 			HostPath:      st.WorkCopyDir,
 			ContainerPath: st.Workdir.ResolvedMountPath(),
-		}}
+		}}, buildObjectStoreMounts(st, st.WorkCopyDir, st.Workdir.Path)...)
 	default:
 		return []runtime.MountSpec{{
 			HostPath:      st.Workdir.Path,
@@ -53,20 +55,21 @@ func buildWorkdirMounts(st *state.State) []runtime.MountSpec {
 func buildAuxDirMounts(st *state.State) []runtime.MountSpec {
 	var mounts []runtime.MountSpec
 	for _, ad := range st.AuxDirs {
-		mounts = append(mounts, buildSingleAuxDirMount(st.SandboxDir, ad)...)
+		mounts = append(mounts, buildSingleAuxDirMount(st, ad)...)
 	}
 	return mounts
 }
 
 // buildSingleAuxDirMount returns mount specs for one auxiliary directory.
-func buildSingleAuxDirMount(sandboxDir string, ad *state.DirSpec) []runtime.MountSpec {
+func buildSingleAuxDirMount(st *state.State, ad *state.DirSpec) []runtime.MountSpec {
 	mountTarget := ad.ResolvedMountPath()
 	switch ad.Mode {
 	case "copy":
-		return []runtime.MountSpec{{
-			HostPath:      store.WorkDir(sandboxDir, ad.Path),
+		workCopy := store.WorkDir(st.SandboxDir, ad.Path)
+		return append([]runtime.MountSpec{{
+			HostPath:      workCopy,
 			ContainerPath: mountTarget,
-		}}
+		}}, buildObjectStoreMounts(st, workCopy, ad.Path)...)
 	case "rw":
 		return []runtime.MountSpec{{
 			HostPath:      ad.Path,
@@ -81,6 +84,22 @@ func buildSingleAuxDirMount(sandboxDir string, ad *state.DirSpec) []runtime.Moun
 	}
 }
 
+// buildObjectStoreMounts returns read-only mounts for the shared git object
+// stores the work copy at workCopy borrows packs from. Each is mounted at its
+// host path, which is what the copy's alternates file names; read-only so no
+// agent can change what another sandbox's copy reads.
+func buildObjectStoreMounts(st *state.State, workCopy, src string) []runtime.MountSpec {
+	root := workcopy.ObjectStoreRoot(st.Layout.ObjectsDir(), src)
+	if st.Layout.DataDir == "" || root == "" {
+		return nil
+	}
+	var mounts []runtime.MountSpec
+	for _, s := range workspace.SharedObjectStores(workCopy, root) {
+		mounts = append(mounts, runtime.MountSpec{HostPath: s, ContainerPath: s, ReadOnly: true})
+	}
+	return mounts
+}
+
 // buildAgentMounts returns mount specs for the agent runtime dir, VS Code CLI, and home-seed files.
 func buildAgentMounts(st *state.State) []runtime.MountSpec {
 	var mounts []runtime.MountSpec
@@ -182,7 +201,8 @@ func buildHomeSeedMounts(st *state.State) []runtime.MountSpec {
 	return mounts
 }
 
-// buildSystemMounts returns mount specs for logs, status, prompt, config, files, and cache.
+// buildSystemMounts returns mount specs for logs, status, prompt, config, files,
+// cache, the prompt inbox, and the artifact outbox.
 func buildSystemMounts(st *state.State) []runtime.MountSpec {
 	mounts := []runtime.MountSpec{
 		// Structured log directory
@@ -229,6 +249,18 @@ func buildSystemMounts(st *state.State) []runtime.MountSpec {
 		},
 	)
 
+	// Prompt inbox (watched by sandbox-setup.py)
+	mounts = append(mounts, runtime.MountSpec{
+		HostPath:      store.InboxDir(st.SandboxDir),
+		ContainerPath: "/yoloai/inbox",
+	})
+
+	// Artifact outbox (listed and collected by `yoloai artifacts`)
+	mounts = append(mounts, runtime.MountSpec{
+		HostPath:      store.OutboxDir(st.SandboxDir),
+		ContainerPath: "/yoloai/outbox",
+	})
+
 	return mounts
 }
 
@@ -11,6 +11,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/kstenerud/yoloai/internal/agent"
+	"github.com/kstenerud/yoloai/internal/config"
 	"github.com/kstenerud/yoloai/internal/orchestrator/state"
 	"github.com/kstenerud/yoloai/runtime"
 	"github.com/kstenerud/yoloai/store"
@@ -40,6 +41,38 @@ func TestBuild_CopyMode(t *testing.T) {
 	assert.Equal(t, st.WorkCopyDir, workMount.HostPath)
 }
 
+func TestBuild_CopyModeMountsSharedObjectStores(t *testing.T) {
+	layout := config.NewLayout(t.TempDir())
+	src := "/home/user/project"
+	root := filepath.Join(layout.ObjectsDir(), config.EncodePath(src))
+	shared := filepath.Join(root, "0123abcd")
+	require.NoError(t, os.MkdirAll(shared, 0o750))
+	workCopy := t.TempDir()
+	infoDir := filepath.Join(workCopy, ".git", "objects", "info")
+	require.NoError(t, os.MkdirAll(infoDir, 0o750))
+	// An entry outside the source's store root is the agent's to write, and
+	// must not become a mount.
+	elsewhere := t.TempDir()
+	alternates := shared + "\n" + elsewhere + "\n"
+	require.NoError(t, os.WriteFile(filepath.Join(infoDir, "alternates"), []byte(alternates), 0o600))
+
+	st := &state.State{
+		SandboxDir:  "/home/user/.yoloai/sandboxes/test",
+		Workdir:     &state.DirSpec{Path: src, Mode: store.DirMode("copy")},
+		WorkCopyDir: workCopy,
+		Agent:       agent.GetAgent("test"),
+		Layout:      layout,
+	}
+
+	var storeMounts []runtime.MountSpec
+	for _, m := range Build(st, "") {
+		if m.HostPath == shared || m.HostPath == elsewhere {
+			storeMounts = append(storeMounts, m)
+		}
+	}
+	assert.Equal(t, []runtime.MountSpec{{HostPath: shared, ContainerPath: shared, ReadOnly: true}}, storeMounts)
+}
+
 func TestBuild_RWMode(t *testing.T) {
 	agentDef := agent.GetAgent("test")
 	st := &state.State{
@@ -142,3 +175,45 @@ func TestBuild_IncludesSecrets(t *testing.T) {
 	}
 	assert.True(t, found, "should include secrets mount")
 }
+
+func TestBuild_IncludesInbox(t *testing.T) {
+	agentDef := agent.GetAgent("test")
+	st := &state.State{
+		SandboxDir: "/sandbox",
+		Workdir:    &state.DirSpec{Path: "/project", Mode: store.DirMode("copy")},
+		Agent:      agentDef,
+	}
+
+	mounts := Build(st, "")
+
+	var found bool
+	for _, m := range mounts {
+		if m.ContainerPath == "/yoloai/inbox" {
+			found = true
+			assert.Equal(t, "/sandbox/inbox", m.HostPath)
+			assert.False(t, m.ReadOnly, "the agent side moves delivered files out of the inbox")
+		}
+	}
+	assert.True(t, found, "should include the prompt inbox mount")
+}
+
+func TestBuild_IncludesOutbox(t *testing.T) {
+	agentDef := agent.GetAgent("test")
+	st := &state.State{
+		SandboxDir: "/sandbox",
+		Workdir:    &state.DirSpec{Path: "/project", Mode: store.DirMode("copy")},
+		Agent:      agentDef,
+	}
+
+	mounts := Build(st, "")
+
+	var found bool
+	for _, m := range mounts {
+		if m.ContainerPath == "/yoloai/outbox" {
+			found = true
+			assert.Equal(t, "/sandbox/outbox", m.HostPath)
+			assert.False(t, m.ReadOnly, "the agent writes its artifacts here")
+		}
+	}
+	assert.True(t, found, "should include the artifact outbox mount")
+}
@@ -24,3 +24,15 @@ type StartResult = lifecycle.StartResult
 
 // ResetResult reports the outcome of a Reset. See lifecycle.ResetResult.
 type ResetResult = lifecycle.ResetResult
+
+// RebaseResult reports the outcome of a Rebase. See lifecycle.RebaseResult.
+type RebaseResult = lifecycle.RebaseResult
+
+// UpgradeResult reports the outcome of UpgradeAgent. See lifecycle.UpgradeResult.
+type UpgradeResult = lifecycle.UpgradeResult
+
+// CleanStateResult reports the outcome of CleanAgentState. See lifecycle.CleanStateResult.
+type CleanStateResult = lifecycle.CleanStateResult
+
+// PortsResult reports the outcome of UpdatePorts. See lifecycle.PortsResult.
+type PortsResult = lifecycle.PortsResult
@@ -6,12 +6,14 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"io/fs"
 	"log/slog"
 	"os"
 	"path/filepath"
 	"strings"
 
 	"github.com/kstenerud/yoloai/internal/config"
+	"github.com/kstenerud/yoloai/internal/fileutil"
 	"github.com/kstenerud/yoloai/runtime"
 )
 
@@ -65,11 +67,17 @@ func EnsureProfileImage(ctx context.Context, rt runtime.Backend, layout config.L
 			continue
 		}
 
-		profileDir := layout.ProfileDir(name)
 		if !config.ProfileHasDockerfile(layout, name) {
 			// No Dockerfile — skip, but pass along prevDir unchanged
 			continue
 		}
+		profileDir := layout.ProfileDir(name)
+		if config.IsSystemProfile(layout, name) {
+			profileDir = layout.SystemProfileBuildDir(name)
+			if err := stageSystemProfile(config.ProfileSourceDir(layout, name), profileDir); err != nil {
+				return fmt.Errorf("stage system profile %s: %w", name, err)
+			}
+		}
 
 		tag := config.ProfileImageTag(layout, name)
 		if force || builder.ProfileImageNeedsBuild(profileDir, prevDir) {
@@ -86,6 +94,55 @@ func EnsureProfileImage(ctx context.Context, rt runtime.Backend, layout config.L
 	return nil
 }
 
+// stageSystemProfile mirrors a system-wide profile (read-only to the user)
+// into dst, the writable directory its image is built from. Files gone from
+// src are removed from dst, except hidden files at its top, where the builder
+// keeps its record of the last build.
+func stageSystemProfile(src, dst string) error {
+	err := filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+		if d.IsDir() {
+			return fileutil.MkdirAll(target, 0o750)
+		}
+		data, err := os.ReadFile(path) //nolint:gosec // G304: path is under the system profile directory
+		if err != nil {
+			return err
+		}
+		return fileutil.WriteFile(target, data, 0o600)
+	})
+	if err != nil {
+		return err
+	}
+	return filepath.WalkDir(dst, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || path == dst {
+			return err
+		}
+		rel, err := filepath.Rel(dst, path)
+		if err != nil {
+			return err
+		}
+		if !strings.Contains(rel, string(filepath.Separator)) && strings.HasPrefix(rel, ".") {
+			return nil
+		}
+		if _, statErr := os.Lstat(filepath.Join(src, rel)); os.IsNotExist(statErr) {
+			if err := os.RemoveAll(path); err != nil {
+				return err
+			}
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+		}
+		return nil
+	})
+}
+
 // AutoBuildSecrets detects well-known credential files on the host and
 // returns Docker BuildKit --secret specs for them. Returns nil if nothing
 // is detected.
@@ -1,6 +1,7 @@
 // ABOUTME: Docker build-secret handling for profile builds: auto-detecting a
 // ABOUTME: host ~/.npmrc, and parsing/validating id=,src= specs (order, missing
-// ABOUTME: fields, tilde expansion, source-file existence).
+// ABOUTME: fields, tilde expansion, source-file existence), and staging a
+// ABOUTME: system-wide profile into a writable build directory.
 package profiles
 
 import (
@@ -90,3 +91,26 @@ func TestValidateBuildSecret_TildeExpansion(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, "id=npmrc,src="+npmrcPath, got)
 }
+
+func TestStageSystemProfile(t *testing.T) {
+	src := filepath.Join(t.TempDir(), "corp")
+	dst := filepath.Join(t.TempDir(), "corp")
+	require.NoError(t, os.MkdirAll(filepath.Join(src, "files"), 0750))
+	require.NoError(t, os.WriteFile(filepath.Join(src, "Dockerfile"), []byte("FROM base\n"), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(src, "files", "a.sh"), []byte("a"), 0600))
+
+	require.NoError(t, stageSystemProfile(src, dst))
+	assert.FileExists(t, filepath.Join(dst, "files", "a.sh"))
+
+	// The builder's record survives a restage; files gone upstream don't.
+	require.NoError(t, os.WriteFile(filepath.Join(dst, ".last-build-checksum"), []byte("abc"), 0600))
+	require.NoError(t, os.RemoveAll(filepath.Join(src, "files")))
+	require.NoError(t, os.WriteFile(filepath.Join(src, "Dockerfile"), []byte("FROM base2\n"), 0600))
+	require.NoError(t, stageSystemProfile(src, dst))
+
+	data, err := os.ReadFile(filepath.Join(dst, "Dockerfile"))
+	require.NoError(t, err)
+	assert.Equal(t, "FROM base2\n", string(data))
+	assert.NoDirExists(t, filepath.Join(dst, "files"))
+	assert.FileExists(t, filepath.Join(dst, ".last-build-checksum"))
+}
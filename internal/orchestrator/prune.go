@@ -1,6 +1,7 @@
 package orchestrator
 
-// ABOUTME: Cleans up stale yoloai-* temporary directories under the yoloai data dir.
+// ABOUTME: Cleans up stale yoloai-* temporary directories under the yoloai data dir,
+// ABOUTME: and the shared git object stores no work copy borrows from any more.
 
 import (
 	"fmt"
@@ -10,6 +11,7 @@ import (
 	"time"
 
 	"github.com/kstenerud/yoloai/internal/config"
+	"github.com/kstenerud/yoloai/internal/workspace"
 )
 
 // TempPruneFailure is a stale temp dir that matched but could not be removed —
@@ -76,3 +78,95 @@ func pruneTempDir(root string, dryRun bool, maxAge time.Duration) (pruned []stri
 
 	return pruned, failed, nil
 }
+
+// PruneObjectStores removes the shared git object stores under
+// layout.ObjectsDir() that no work copy names any more, along with store
+// builds left half-done. A store is in use while any work copy of a sandbox —
+// live or in the trash, where it can still be restored — has it in its
+// alternates file. One taken up less than maxAge ago is kept either way, since
+// the copy about to name it may not have yet. Results are reported as for
+// PruneTempFiles.
+//
+// A sandbox whose work copies can't be read could be borrowing from any store,
+// so none is removed then.
+func PruneObjectStores(layout config.Layout, dryRun bool, maxAge time.Duration) (pruned []string, failed []TempPruneFailure, err error) {
+	root := layout.ObjectsDir()
+	sources, readErr := os.ReadDir(root)
+	if readErr != nil {
+		if os.IsNotExist(readErr) {
+			return nil, nil, nil // nothing to prune
+		}
+		return nil, nil, fmt.Errorf("read %s: %w", root, readErr)
+	}
+	inUse, err := objectStoresInUse(layout)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	cutoff := time.Now().Add(-maxAge)
+	for _, source := range sources {
+		if !source.IsDir() {
+			continue
+		}
+		sourceDir := filepath.Join(root, source.Name())
+		stores, readErr := os.ReadDir(sourceDir)
+		if readErr != nil {
+			continue // skip entries we can't read
+		}
+		kept := 0
+		for _, entry := range stores {
+			path := filepath.Join(sourceDir, entry.Name())
+			info, statErr := entry.Info()
+			if statErr != nil || inUse[path] || info.ModTime().After(cutoff) {
+				kept++
+				continue
+			}
+			if !dryRun {
+				if rmErr := os.RemoveAll(path); rmErr != nil {
+					failed = append(failed, TempPruneFailure{Path: path, Err: rmErr})
+					kept++
+					continue
+				}
+			}
+			pruned = append(pruned, path)
+		}
+		if kept == 0 && !dryRun {
+			_ = os.Remove(sourceDir) // best-effort; a store made meanwhile keeps it
+		}
+	}
+	return pruned, failed, nil
+}
+
+// objectStoresInUse returns every object directory named by the alternates of
+// a work copy under the sandboxes or trash dir.
+func objectStoresInUse(layout config.Layout) (map[string]bool, error) {
+	inUse := map[string]bool{}
+	for _, parent := range []string{layout.SandboxesDir(), layout.TrashDir()} {
+		sandboxes, err := os.ReadDir(parent)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, fmt.Errorf("read %s: %w", parent, err)
+		}
+		for _, sb := range sandboxes {
+			if !sb.IsDir() {
+				continue
+			}
+			workRoot := filepath.Join(parent, sb.Name(), "work")
+			copies, err := os.ReadDir(workRoot)
+			if err != nil {
+				if os.IsNotExist(err) {
+					continue // not a copy-mode sandbox
+				}
+				return nil, fmt.Errorf("read %s: %w", workRoot, err)
+			}
+			for _, c := range copies {
+				for _, path := range workspace.Alternates(filepath.Join(workRoot, c.Name())) {
+					inUse[path] = true
+				}
+			}
+		}
+	}
+	return inUse, nil
+}
@@ -1,6 +1,7 @@
 // ABOUTME: PruneTempFiles age-based sweeping of ~/.yoloai temp dirs: dry-run
 // ABOUTME: vs real removal, non-dir entries skipped, and unremovable dirs
-// ABOUTME: reported as failed rather than falsely claimed as pruned.
+// ABOUTME: reported as failed rather than falsely claimed as pruned; and
+// ABOUTME: PruneObjectStores keeping the stores a live or trashed copy names.
 package orchestrator
 
 import (
@@ -129,3 +130,51 @@ func TestPruneTempFiles_UnremovableReportedAsFailed(t *testing.T) {
 	_, statErr := os.Stat(staleDir)
 	assert.NoError(t, statErr, "the dir should still exist since removal failed")
 }
+
+func TestPruneObjectStores(t *testing.T) {
+	layout := testLayout(t)
+	sourceDir := filepath.Join(layout.ObjectsDir(), "^2Fsrc")
+	past := time.Now().Add(-2 * time.Hour)
+	mkStore := func(name string) string {
+		path := filepath.Join(sourceDir, name)
+		require.NoError(t, os.MkdirAll(filepath.Join(path, "pack"), 0o750))
+		require.NoError(t, os.Chtimes(path, past, past))
+		return path
+	}
+	named := mkStore("named")
+	trashed := mkStore("trashed")
+	unused := mkStore("unused")
+	halfBuilt := mkStore(".new-123")
+	fresh := filepath.Join(sourceDir, "fresh")
+	require.NoError(t, os.MkdirAll(fresh, 0o750))
+
+	nameStore := func(workCopy, store string) {
+		info := filepath.Join(workCopy, ".git", "objects", "info")
+		require.NoError(t, os.MkdirAll(info, 0o750))
+		require.NoError(t, os.WriteFile(filepath.Join(info, "alternates"), []byte(store+"\n"), 0o600))
+	}
+	nameStore(filepath.Join(layout.SandboxesDir(), "live", "work", "^2Fsrc"), named)
+	nameStore(filepath.Join(layout.TrashDir(), "gone-1", "work", "^2Fsrc"), trashed)
+
+	pruned, failed, err := PruneObjectStores(layout, true, time.Hour)
+	require.NoError(t, err)
+	assert.Empty(t, failed)
+	assert.ElementsMatch(t, []string{unused, halfBuilt}, pruned)
+	assert.DirExists(t, unused, "dry run removes nothing")
+
+	pruned, _, err = PruneObjectStores(layout, false, time.Hour)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{unused, halfBuilt}, pruned)
+	assert.NoDirExists(t, unused)
+	assert.NoDirExists(t, halfBuilt)
+	for _, kept := range []string{named, trashed, fresh} {
+		assert.DirExists(t, kept)
+	}
+}
+
+func TestPruneObjectStores_NoObjectsDir(t *testing.T) {
+	pruned, failed, err := PruneObjectStores(testLayout(t), false, time.Hour)
+	require.NoError(t, err)
+	assert.Empty(t, pruned)
+	assert.Empty(t, failed)
+}
@@ -56,12 +56,14 @@ type ContainerConfig struct {
 	SubmitSequence     string      `json:"submit_sequence"`
 	TmuxConf           string      `json:"tmux_conf"`
 	WorkingDir         string      `json:"working_dir"`
+	AgentWorkdir       string      `json:"agent_workdir,omitempty"`
 	StateDirName       string      `json:"state_dir_name"`
 	Debug              bool        `json:"debug,omitempty"`
 	NetworkIsolated    bool        `json:"network_isolated,omitempty"`
 	AllowedDomains     []string    `json:"allowed_domains,omitempty"`
 	Passthrough        []string    `json:"passthrough,omitempty"`
 	SetupCommands      []string    `json:"setup_commands,omitempty"`
+	PreLaunch          string      `json:"pre_launch,omitempty"`
 	AutoCommitInterval int         `json:"auto_commit_interval,omitempty"`
 	CopyDirs           []string    `json:"copy_dirs,omitempty"`
 	HookIdle           bool        `json:"hook_idle,omitempty"`
@@ -103,4 +105,33 @@ type ContainerConfig struct {
 	// keepalive box (launch.startViaLaunch → patchKeepaliveOnly); the entrypoint
 	// honors it.
 	KeepaliveOnly bool `json:"keepalive_only,omitempty"`
+	// Roles, when set, splits the session between agents with different
+	// jobs (`new --role`): the first role runs in the main window with its
+	// prompt in prompt.txt, each later one in a window named after it with
+	// the prompt given here. Absent → one agent. Additive optional field → no
+	// SchemaVersion bump.
+	Roles []Role `json:"roles,omitempty"`
+	// Guard, when set, puts shims in front of the commands its rules name
+	// (the guard config key); the shims log or refuse matching invocations.
+	// Absent → no shims. Additive optional field → no SchemaVersion bump.
+	Guard *Guard `json:"guard,omitempty"`
+	// AllowPush lets the agent's git push reach real remotes (`new
+	// --allow-push`). Unset, sandbox-setup rewrites every push URL in the
+	// agent's environment to a path that fails with a message naming the flag
+	// (the :copy work copies' own config is rewritten on the host as well).
+	// Additive optional field → no SchemaVersion bump.
+	AllowPush bool `json:"allow_push,omitempty"`
+}
+
+// Guard is the resolved destructive-command guard: a mode of "log" or
+// "block" and the rules it applies.
+type Guard struct {
+	Mode     string   `json:"mode"`
+	Commands []string `json:"commands"`
+}
+
+// Role is one agent of a split-role session.
+type Role struct {
+	Name   string `json:"name"`
+	Prompt string `json:"prompt"`
 }
@@ -0,0 +1,110 @@
+package orchestrator
+
+// ABOUTME: Follow-up prompt injection — pastes text into the running agent's
+// ABOUTME: tmux pane and submits it with the agent's own submit sequence.
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/kstenerud/yoloai/internal/orchestrator/runtimeconfig"
+	"github.com/kstenerud/yoloai/runtime"
+	"github.com/kstenerud/yoloai/store"
+	"github.com/kstenerud/yoloai/yoerrors"
+)
+
+// SendInput delivers text to the sandbox agent as a follow-up prompt: it is
+// pasted into the agent's tmux pane and submitted with the agent's
+// SubmitSequence (some agents need more than one Enter), the same way the
+// initial prompt is delivered. If the agent is idle at its prompt this starts
+// its next turn; if it is mid-task the text reaches it the way typing would,
+// which for most agents queues it or interrupts. Returns
+// ErrContainerNotRunning when the sandbox is stopped, and a *UsageError for a
+// headless agent, which has no prompt to type into.
+//
+// The agent status is flipped to active in the same exec, before the paste,
+// so a `yoloai wait` that follows never sees the idle state the send is about
+// to end.
+//
+// Acquires the per-sandbox lock (Q-T): SendInput mutates sandbox state
+// (injects keystrokes into the running agent's tmux session), so it
+// serialises against concurrent Stop / Destroy / Reset / Apply for the
+// same sandbox. Each call is brief (one exec), so the lock-hold time
+// is small even under interactive use.
+func (e *Engine) SendInput(ctx context.Context, name string, text string) error {
+	info, err := e.Inspect(ctx, name)
+	if err != nil {
+		return err
+	}
+	if info.Status != StatusActive && info.Status != StatusIdle {
+		return fmt.Errorf("sandbox %q: %w", name, ErrContainerNotRunning)
+	}
+	cfg, err := readSendConfig(e.layout.SandboxDir(name))
+	if err != nil {
+		return err
+	}
+	if cfg.Headless {
+		return yoerrors.NewUsageError("sandbox %q runs its agent headless: there is no prompt to send to", name)
+	}
+
+	unlock, err := store.AcquireLock(e.layout, name)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	socket := runtime.TmuxSocketFor(e.runtime, e.layout.SandboxDir(name))
+	containerName := store.InstanceName(e.layout.Principal, name)
+	result, err := e.runtime.Exec(ctx, containerName,
+		[]string{"bash", "-c", sendInputScript(socket, cfg.SubmitSequence), "_", text},
+		ContainerUser(info.Environment, e.layout.HostUID),
+	)
+	if err == nil && result.ExitCode != 0 {
+		err = fmt.Errorf("exit %d", result.ExitCode)
+	}
+	if err != nil {
+		return fmt.Errorf("send input to sandbox %q: %w", name, err)
+	}
+	return nil
+}
+
+// readSendConfig loads the parts of runtime-config.json SendInput needs.
+func readSendConfig(sandboxDir string) (runtimeconfig.ContainerConfig, error) {
+	var cfg runtimeconfig.ContainerConfig
+	data, err := os.ReadFile(filepath.Join(sandboxDir, store.RuntimeConfigFile)) //nolint:gosec // path is sandbox-controlled
+	if err != nil {
+		return cfg, fmt.Errorf("read runtime-config.json: %w", err)
+	}
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return cfg, fmt.Errorf("parse runtime-config.json: %w", err)
+	}
+	return cfg, nil
+}
+
+// sendInputScript is the bash run inside the sandbox to deliver "$1". The text
+// goes through a tmux buffer rather than send-keys so it arrives verbatim, and
+// paste-buffer -p brackets it so a multi-line prompt isn't submitted line by
+// line (see deliverPromptViaTmux). An empty submit sequence falls back to
+// Enter.
+func sendInputScript(socket, submit string) string {
+	tmux := "tmux"
+	if socket != "" {
+		tmux = fmt.Sprintf("tmux -S %q", socket)
+	}
+	if strings.TrimSpace(submit) == "" {
+		submit = "Enter"
+	}
+	return fmt.Sprintf(`set -e
+printf '{"schema_version":1,"status":"active","exit_code":null,"timestamp":%%d}' "$(date +%%s)" > "${YOLOAI_DIR:-/yoloai}/agent-status.json"
+%[1]s set-buffer -b yoloai-send -- "$1"
+%[1]s paste-buffer -p -d -b yoloai-send -t main:{start}
+sleep 0.5
+for key in %[2]s; do
+    %[1]s send-keys -t main:{start} "$key"
+    sleep 0.2
+done`, tmux, submit)
+}
@@ -0,0 +1,120 @@
+package orchestrator
+
+// ABOUTME: Unit tests for SendInput — the submit sequence it uses, the
+// ABOUTME: not-running and headless refusals, and the delivery script itself.
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/kstenerud/yoloai/internal/orchestrator/runtimeconfig"
+	"github.com/kstenerud/yoloai/internal/sysexec"
+	"github.com/kstenerud/yoloai/runtime"
+	"github.com/kstenerud/yoloai/store"
+	"github.com/kstenerud/yoloai/yoerrors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeSendConfig(t *testing.T, tmpDir, name string, cfg runtimeconfig.ContainerConfig) {
+	t.Helper()
+	data, err := json.Marshal(cfg)
+	require.NoError(t, err)
+	writeTestFile(t, filepath.Join(tmpDir, ".yoloai", "sandboxes", name), store.RuntimeConfigFile, string(data))
+}
+
+func runningTerminalMock() *terminalMockRuntime {
+	return &terminalMockRuntime{
+		tmuxSocket: "/tmp/yoloai-tmux.sock",
+		inspectFn: func(_ context.Context, _ string) (runtime.InstanceInfo, error) {
+			return runtime.InstanceInfo{Running: true}, nil
+		},
+	}
+}
+
+func TestSendInput_UsesSubmitSequence(t *testing.T) {
+	tmpDir := t.TempDir()
+	createTestSandbox(t, tmpDir, "send-box", "/tmp/project", "copy")
+	writeSendConfig(t, tmpDir, "send-box", runtimeconfig.ContainerConfig{SubmitSequence: "Enter Enter"})
+
+	mock := runningTerminalMock()
+	mgr := newTerminalMgr(mock, tmpDir)
+
+	require.NoError(t, mgr.SendInput(context.Background(), "send-box", "now add tests"))
+	require.Len(t, mock.execCalls, 1)
+	call := mock.execCalls[0]
+	require.Len(t, call, 5)
+	assert.Equal(t, []string{"bash", "-c"}, call[:2])
+	assert.Contains(t, call[2], `for key in Enter Enter; do`)
+	assert.Contains(t, call[2], `tmux -S "/tmp/yoloai-tmux.sock" paste-buffer`)
+	assert.Equal(t, "now add tests", call[4], "the text travels as an argument, never spliced into the script")
+}
+
+func TestSendInput_NotRunning(t *testing.T) {
+	tmpDir := t.TempDir()
+	createTestSandbox(t, tmpDir, "send-stopped", "/tmp/project", "copy")
+
+	mock := &terminalMockRuntime{
+		inspectFn: func(_ context.Context, _ string) (runtime.InstanceInfo, error) {
+			return runtime.InstanceInfo{Running: false}, nil
+		},
+	}
+	mgr := newTerminalMgr(mock, tmpDir)
+
+	err := mgr.SendInput(context.Background(), "send-stopped", "hello")
+	assert.ErrorIs(t, err, ErrContainerNotRunning)
+	assert.Empty(t, mock.execCalls)
+}
+
+func TestSendInput_HeadlessRefused(t *testing.T) {
+	tmpDir := t.TempDir()
+	createTestSandbox(t, tmpDir, "send-headless", "/tmp/project", "copy")
+	writeSendConfig(t, tmpDir, "send-headless", runtimeconfig.ContainerConfig{Headless: true, SubmitSequence: "Enter"})
+
+	mock := runningTerminalMock()
+	mgr := newTerminalMgr(mock, tmpDir)
+
+	err := mgr.SendInput(context.Background(), "send-headless", "hello")
+	var usage *yoerrors.UsageError
+	assert.ErrorAs(t, err, &usage)
+	assert.Empty(t, mock.execCalls)
+}
+
+// TestSendInputScript_Delivery runs the script against a stub tmux that logs
+// its arguments: the text goes through a buffer verbatim, the paste is
+// bracketed, each submit key is its own send-keys, and the status is active.
+func TestSendInputScript_Delivery(t *testing.T) {
+	if _, err := exec.LookPath("bash"); err != nil {
+		t.Skip("bash not available")
+	}
+	dir := t.TempDir()
+	logPath := filepath.Join(dir, "tmux.log")
+	stub := "#!/bin/sh\nfor a in \"$@\"; do printf '[%s]' \"$a\"; done >> " + logPath + "\necho >> " + logPath + "\n"
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "tmux"), []byte(stub), 0o755)) //nolint:gosec // test stub must be executable
+
+	text := "it's \"quoted\" $HOME\nand two lines"
+	env := []string{"PATH=" + dir + ":" + os.Getenv("PATH"), "YOLOAI_DIR=" + dir}
+	cmd := sysexec.Command(env, "bash", "-c", sendInputScript("/run/tmux.sock", "Enter Enter"), "_", text)
+	out, err := cmd.CombinedOutput()
+	require.NoError(t, err, string(out))
+
+	logData, err := os.ReadFile(logPath) //nolint:gosec // test path
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSuffix(string(logData), "\n"), "\n")
+	assert.Equal(t, []string{
+		"[-S][/run/tmux.sock][set-buffer][-b][yoloai-send][--][it's \"quoted\" $HOME",
+		"and two lines]",
+		"[-S][/run/tmux.sock][paste-buffer][-p][-d][-b][yoloai-send][-t][main:{start}]",
+		"[-S][/run/tmux.sock][send-keys][-t][main:{start}][Enter]",
+		"[-S][/run/tmux.sock][send-keys][-t][main:{start}][Enter]",
+	}, lines)
+
+	status, err := os.ReadFile(filepath.Join(dir, "agent-status.json")) //nolint:gosec // test path
+	require.NoError(t, err)
+	assert.Contains(t, string(status), `"status":"active"`)
+}
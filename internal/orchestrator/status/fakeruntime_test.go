@@ -20,6 +20,7 @@ var _ runtime.Backend = (*fakeRuntime)(nil)
 type fakeRuntime struct {
 	inspectFn func(ctx context.Context, name string) (runtime.InstanceInfo, error)
 	execFn    func(ctx context.Context, name string, cmd []string, user string) (runtime.ExecResult, error)
+	closes    int // how many times Close was called
 }
 
 func (f *fakeRuntime) Inspect(ctx context.Context, name string) (runtime.InstanceInfo, error) {
@@ -52,7 +53,10 @@ func (f *fakeRuntime) GitExec(_ context.Context, _ string, _ string, _ string, _
 func (f *fakeRuntime) InteractiveExec(_ context.Context, _ string, _ []string, _ string, _ string, _ runtime.IOStreams) error {
 	return errFakeNotImplemented
 }
-func (f *fakeRuntime) Close() error { return nil }
+func (f *fakeRuntime) Close() error {
+	f.closes++
+	return nil
+}
 func (f *fakeRuntime) Prune(_ context.Context, _ []string, _ bool, _ io.Writer) (runtime.PruneResult, error) {
 	return runtime.PruneResult{}, errFakeNotImplemented
 }
@@ -9,8 +9,12 @@ import (
 	"fmt"
 	"io/fs"
 	"log/slog"
+	"maps"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/kstenerud/yoloai/internal/config"
@@ -33,6 +37,7 @@ const (
 	StatusFailed      Status = "failed"      // container running, agent exited with error (non-zero)
 	StatusStopped     Status = "stopped"     // container stopped (docker stop)
 	StatusSuspended   Status = "suspended"   // VM suspended (state on disk, quota slot free; Tart only)
+	StatusPaused      Status = "paused"      // container running but frozen in memory (yoloai pause)
 	StatusRemoved     Status = "removed"     // container removed but sandbox dir exists
 	StatusBroken      Status = "broken"      // sandbox dir exists but environment.json missing/invalid
 	StatusUnavailable Status = "unavailable" // backend not running (container state unknown)
@@ -72,7 +77,12 @@ type Info struct {
 	// failure never fails the surrounding inspect/list (see probeNetHealth).
 	NetHealth       string `json:"net_health,omitempty"`
 	NetHealthDetail string `json:"net_health_detail,omitempty"`
-	HasChanges      string `json:"has_changes"` // "yes", "no", "unknown" (stopped VM-local backend), or "-" (not applicable)
+	// LastOutput is when the agent's tmux window last printed anything, from
+	// the status monitor's heartbeat (store.AgentActivityFile); zero when there
+	// is none. It goes stale once the sandbox stops, so it only says how long a
+	// running agent has been quiet.
+	LastOutput time.Time `json:"last_output,omitzero"`
+	HasChanges string    `json:"has_changes"` // "yes", "no", "unknown" (stopped VM-local backend), or "-" (not applicable)
 	// DiskUsageBytes is the total size of the sandbox directory in bytes, or
 	// -1 when it could not be measured. Rendering to a human-readable string
 	// is the CLI's responsibility (see cliutil.FormatSize).
@@ -80,6 +90,11 @@ type Info struct {
 	// ExitCode is the agent's process exit code when Status is Done (0) or
 	// Failed (non-zero); nil for every non-terminal or non-agent-exit state.
 	ExitCode *int `json:"exit_code,omitempty"`
+	// Result is the agent's structured account of its run (outbox/result.json),
+	// nil when it wrote none. ResultError explains why a result.json that is
+	// present could not be used.
+	Result      *store.AgentResult `json:"result,omitempty"`
+	ResultError string             `json:"result_error,omitempty"`
 }
 
 // DirSize recursively calculates the total size of all files under path.
@@ -245,6 +260,11 @@ func detectStatusWithExit(ctx context.Context, rt runtime.Backend, containerName
 		}
 		return StatusStopped, nil, nil
 	}
+	// A frozen agent's status file still says what it was doing when paused;
+	// report the freeze itself, which is what gates attach/exec/start.
+	if info.Paused {
+		return StatusPaused, nil, nil
+	}
 
 	// Try agent-status.json (fast path — no exec)
 	if sandboxDir != "" {
@@ -342,6 +362,7 @@ func InspectSandbox(ctx context.Context, layout config.Layout, rt runtime.Backen
 	agentType, model := loadAgentIdentity(sandboxDir)
 	networkMode, networkAllow := loadNetworkPolicy(sandboxDir)
 	netHealth, netHealthDetail := probeNetHealth(ctx, rt, name, status)
+	result, resultErr := loadAgentResult(sandboxDir)
 	return &Info{
 		Environment:     meta,
 		AgentType:       agentType,
@@ -351,9 +372,12 @@ func InspectSandbox(ctx context.Context, layout config.Layout, rt runtime.Backen
 		Status:          status,
 		NetHealth:       netHealth,
 		NetHealthDetail: netHealthDetail,
+		LastOutput:      loadLastOutput(sandboxDir),
 		HasChanges:      detectWorkdirChanges(ctx, git.NewSandbox(layout, rt, name), sandboxDir, meta),
 		DiskUsageBytes:  diskUsageBytes,
 		ExitCode:        exitCode,
+		Result:          result,
+		ResultError:     resultErr,
 	}, nil
 }
 
@@ -409,33 +433,47 @@ func loadNetworkPolicy(sandboxDir string) (mode string, allow []string) {
 	return np.Mode, np.Allow
 }
 
+// loadLastOutput reads the agent's output heartbeat for the read-model.
+// Best-effort: a missing or unusable agent-activity.json yields the zero time,
+// and the listing just doesn't say how long the agent has been quiet.
+func loadLastOutput(sandboxDir string) time.Time {
+	last, err := store.LoadLastOutput(sandboxDir)
+	if err != nil {
+		return time.Time{}
+	}
+	return last
+}
+
+// loadAgentResult reads the agent's result.json for the read-model. A missing
+// file yields (nil, ""); an unusable one yields its error as text, so a listing
+// still succeeds and the agent's mistake stays visible.
+func loadAgentResult(sandboxDir string) (*store.AgentResult, string) {
+	res, err := store.LoadAgentResult(sandboxDir)
+	if err != nil {
+		return nil, err.Error()
+	}
+	return res, ""
+}
+
 // detectWorkdirChanges returns "yes", "no", "unknown", or "-" for a sandbox's
-// workdir and aux dirs. "unknown" means the working copy lives in a VM-local
-// backend (Tart) that is not running, so the probe can't reach it — the change
-// state genuinely can't be read from the host (see workprobe.HasUnappliedWorkVia).
+// tracked (:copy) dirs — the workdir and any aux :copy dirs. "-" means none is
+// tracked; a :rw workdir doesn't hide an aux :copy dir's work. "unknown" means
+// the working copy lives in a VM-local backend (Tart) that is not running, so
+// the probe can't reach it — the change state genuinely can't be read from the
+// host (see workprobe.HasUnappliedWorkVia) — or that the probe ran out of time.
 func detectWorkdirChanges(ctx context.Context, g *git.Git, sandboxDir string, meta *store.Environment) string {
-	if meta.Workdir().Mode != "copy" && meta.Workdir().Mode != "overlay" {
+	tracked := meta.TrackedDirs()
+	if len(tracked) == 0 {
 		return "-"
 	}
-	workDir := store.WorkDir(sandboxDir, meta.Workdir().HostPath)
-	switch workprobe.HasUnappliedWorkVia(ctx, g, workDir, meta.Workdir().BaselineSHA) {
-	case workprobe.WorkDirty:
-		return "yes"
-	case workprobe.WorkUnknown:
-		return "unknown"
-	case workprobe.WorkClean:
-	}
-	// workdir has no unapplied work — check aux dirs before reporting "no"
-	for _, d := range meta.AuxDirs() {
-		if d.Mode == "copy" {
-			auxWorkDir := store.WorkDir(sandboxDir, d.HostPath)
-			switch workprobe.HasUnappliedWorkVia(ctx, g, auxWorkDir, d.BaselineSHA) {
-			case workprobe.WorkDirty:
-				return "yes"
-			case workprobe.WorkUnknown:
-				return "unknown"
-			case workprobe.WorkClean:
-			}
+	for _, i := range tracked {
+		d := meta.Dirs[i]
+		switch workprobe.HasUnappliedWorkVia(ctx, g, store.WorkDir(sandboxDir, d.HostPath), d.BaselineSHA) {
+		case workprobe.WorkDirty:
+			return "yes"
+		case workprobe.WorkUnknown:
+			return "unknown"
+		case workprobe.WorkClean:
 		}
 	}
 	return "no"
@@ -462,6 +500,7 @@ func InspectSandboxWithBackend(ctx context.Context, layout config.Layout, rt run
 
 	agentType, model := loadAgentIdentity(sandboxDir)
 	networkMode, networkAllow := loadNetworkPolicy(sandboxDir)
+	result, resultErr := loadAgentResult(sandboxDir)
 
 	// If runtime is nil, return basic info with unavailable status
 	if rt == nil {
@@ -472,8 +511,11 @@ func InspectSandboxWithBackend(ctx context.Context, layout config.Layout, rt run
 			NetworkMode:    networkMode,
 			NetworkAllow:   networkAllow,
 			Status:         StatusUnavailable,
+			LastOutput:     loadLastOutput(sandboxDir),
 			HasChanges:     "-",
 			DiskUsageBytes: diskUsageBytes,
+			Result:         result,
+			ResultError:    resultErr,
 		}, nil
 	}
 
@@ -493,13 +535,31 @@ func InspectSandboxWithBackend(ctx context.Context, layout config.Layout, rt run
 		Status:          status,
 		NetHealth:       netHealth,
 		NetHealthDetail: netHealthDetail,
+		LastOutput:      loadLastOutput(sandboxDir),
 		HasChanges:      detectWorkdirChanges(ctx, git.NewSandbox(layout, rt, name), sandboxDir, meta),
 		DiskUsageBytes:  diskUsageBytes,
 		ExitCode:        exitCode,
+		Result:          result,
+		ResultError:     resultErr,
 	}, nil
 }
 
-// ListSandboxes scans ~/.yoloai/sandboxes/ and returns info for all sandboxes.
+// listJobs bounds how many sandboxes a listing inspects at once. An inspection
+// mostly waits on the backend and on git, so the pool can be wider than batch
+// create's; bounding it still keeps a host with many sandboxes from starting
+// dozens of backend calls and git processes together.
+const listJobs = 8
+
+// listInspectTimeout is how long ListSandboxesMultiBackend gives each sandbox's
+// inspection. It keeps one hung backend call from stalling a whole listing:
+// with everything else done in parallel, 30 sandboxes list in about this long
+// even when one of them never answers. A var so tests can shorten it.
+var listInspectTimeout = 750 * time.Millisecond
+
+// ListSandboxes scans ~/.yoloai/sandboxes/ and returns info for all sandboxes,
+// inspected concurrently, in name order. Unlike ListSandboxesMultiBackend it
+// waits for every inspection to finish, so callers that pick sandboxes by
+// status (stop --all, destroy's wildcards) never miss a slow one.
 func ListSandboxes(ctx context.Context, layout config.Layout, rt runtime.Backend) ([]*Info, error) {
 	sandboxesDir := layout.SandboxesDir()
 
@@ -511,33 +571,44 @@ func ListSandboxes(ctx context.Context, layout config.Layout, rt runtime.Backend
 		return nil, fmt.Errorf("read sandboxes directory: %w", err)
 	}
 
-	var result []*Info
+	var targets []listTarget
 	for _, entry := range entries {
-		if !entry.IsDir() {
-			continue
-		}
-		info, err := InspectSandbox(ctx, layout, rt, entry.Name())
-		if err != nil {
-			// Include broken sandboxes with minimal info
-			result = append(result, &Info{
-				Environment:    &store.Environment{Name: entry.Name()},
-				Status:         StatusBroken,
-				HasChanges:     "-",
-				DiskUsageBytes: -1,
-			})
-			continue
+		if entry.IsDir() {
+			targets = append(targets, listTarget{name: entry.Name(), rt: rt})
 		}
-		result = append(result, info)
 	}
-
-	return result, nil
+	return inspectAll(ctx, layout, targets, 0), nil
 }
 
 // ListSandboxesMultiBackend scans sandboxes and inspects them using their respective backends.
 // Takes a newRuntimeFunc parameter for creating runtimes (enables testing).
-// Returns (infos, unavailableBackends, error).
-// Sandboxes whose backends are unavailable get StatusUnavailable.
+// Returns (infos, unavailableBackends, error), infos in name order.
+// Sandboxes whose backends are unavailable get StatusUnavailable. The sandboxes
+// are inspected concurrently, each within listInspectTimeout; one that runs
+// out of time is reported as inspectForList describes rather than holding up
+// the rest.
 func ListSandboxesMultiBackend(ctx context.Context, layout config.Layout, newRuntimeFunc func(context.Context, runtime.BackendType) (runtime.Backend, error)) ([]*Info, []string, error) {
+	return listMultiBackend(ctx, layout, newRuntimeFunc, true, listInspectTimeout)
+}
+
+// ListSandboxesMultiBackendComplete lists like ListSandboxesMultiBackend but
+// waits for every inspection to finish, like ListSandboxes, so callers that
+// pick sandboxes by status (up after a reboot, when cold backends answer
+// slowly) never skip a slow one as unavailable.
+func ListSandboxesMultiBackendComplete(ctx context.Context, layout config.Layout, newRuntimeFunc func(context.Context, runtime.BackendType) (runtime.Backend, error)) ([]*Info, []string, error) {
+	return listMultiBackend(ctx, layout, newRuntimeFunc, true, 0)
+}
+
+// ListSandboxesWithRuntimes lists like ListSandboxesMultiBackend, except the
+// runtimes runtimeFor returns stay open: they are the caller's, kept across
+// listings so their connections and inspect caches are reused.
+func ListSandboxesWithRuntimes(ctx context.Context, layout config.Layout, runtimeFor func(context.Context, runtime.BackendType) (runtime.Backend, error)) ([]*Info, []string, error) {
+	return listMultiBackend(ctx, layout, runtimeFor, false, listInspectTimeout)
+}
+
+// listMultiBackend is ListSandboxesMultiBackend, closing the runtimes it got
+// from open when owned, with each inspection bounded by timeout (0: none).
+func listMultiBackend(ctx context.Context, layout config.Layout, open func(context.Context, runtime.BackendType) (runtime.Backend, error), owned bool, timeout time.Duration) ([]*Info, []string, error) {
 	sandboxesDir := layout.SandboxesDir()
 
 	entries, err := os.ReadDir(sandboxesDir)
@@ -552,17 +623,28 @@ func ListSandboxesMultiBackend(ctx context.Context, layout config.Layout, newRun
 
 	var result []*Info
 	var unavailableBackends []string
-	unavailableSet := make(map[runtime.BackendType]bool)
-
-	for backend, names := range backendSandboxes {
+	var targets []listTarget
+	for _, backend := range slices.Sorted(maps.Keys(backendSandboxes)) {
+		names := backendSandboxes[backend]
 		if backend == "" {
 			result = append(result, brokenInfos(names)...)
 			continue
 		}
-		infos, unavail := inspectBackendGroup(ctx, layout, newRuntimeFunc, backend, names, unavailableSet)
-		result = append(result, infos...)
-		unavailableBackends = append(unavailableBackends, unavail...)
+		rt, err := open(ctx, backend)
+		if err != nil {
+			// rt stays nil: its sandboxes are listed from disk as unavailable.
+			unavailableBackends = append(unavailableBackends, string(backend))
+		} else if owned {
+			defer rt.Close() //nolint:errcheck // best-effort cleanup
+		}
+		for _, name := range names {
+			targets = append(targets, listTarget{name: name, rt: rt})
+		}
 	}
+	result = append(result, inspectAll(ctx, layout, targets, timeout)...)
+	slices.SortFunc(result, func(a, b *Info) int {
+		return strings.Compare(a.Environment.Name, b.Environment.Name)
+	})
 
 	return result, unavailableBackends, nil
 }
@@ -594,43 +676,69 @@ func groupSandboxesByBackend(entries []os.DirEntry, sandboxesDir string) map[run
 func brokenInfos(names []string) []*Info {
 	infos := make([]*Info, len(names))
 	for i, name := range names {
-		infos[i] = &Info{
-			Environment:    &store.Environment{Name: name},
-			Status:         StatusBroken,
-			HasChanges:     "-",
-			DiskUsageBytes: -1,
-		}
+		infos[i] = brokenInfo(name)
 	}
 	return infos
 }
 
-// inspectBackendGroup inspects all sandboxes for a single backend, returning
-// their Info entries and any newly discovered unavailable backend names.
-func inspectBackendGroup(ctx context.Context, layout config.Layout, newRuntimeFunc func(context.Context, runtime.BackendType) (runtime.Backend, error), backend runtime.BackendType, names []string, unavailableSet map[runtime.BackendType]bool) ([]*Info, []string) {
-	var unavailableBackends []string
-	rt, err := newRuntimeFunc(ctx, backend)
-	var effectiveRT runtime.Backend
-	if err == nil {
-		effectiveRT = rt
-		defer rt.Close() //nolint:errcheck // best-effort cleanup
-	} else if !unavailableSet[backend] {
-		unavailableBackends = append(unavailableBackends, string(backend))
-		unavailableSet[backend] = true
+// brokenInfo is the minimal StatusBroken Info a listing shows for a sandbox
+// it couldn't inspect.
+func brokenInfo(name string) *Info {
+	return &Info{
+		Environment:    &store.Environment{Name: name},
+		Status:         StatusBroken,
+		HasChanges:     "-",
+		DiskUsageBytes: -1,
 	}
+}
 
-	var result []*Info
-	for _, name := range names {
-		info, inspectErr := InspectSandboxWithBackend(ctx, layout, effectiveRT, name)
-		if inspectErr != nil {
-			result = append(result, &Info{
-				Environment:    &store.Environment{Name: name},
-				Status:         StatusBroken,
-				HasChanges:     "-",
-				DiskUsageBytes: -1,
-			})
-			continue
+// listTarget is one sandbox a listing inspects, with the runtime of its
+// backend (nil when that backend is unavailable).
+type listTarget struct {
+	name string
+	rt   runtime.Backend
+}
+
+// inspectAll inspects targets concurrently, at most listJobs at a time, and
+// returns their Info in targets' order. A non-zero timeout bounds each
+// inspection (see inspectForList); it starts when the inspection does, not
+// while the sandbox waits for a slot.
+func inspectAll(ctx context.Context, layout config.Layout, targets []listTarget, timeout time.Duration) []*Info {
+	infos := make([]*Info, len(targets))
+	sem := make(chan struct{}, listJobs)
+	var wg sync.WaitGroup
+	for i, t := range targets {
+		wg.Go(func() {
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			infos[i] = inspectForList(ctx, layout, t, timeout)
+		})
+	}
+	wg.Wait()
+	return infos
+}
+
+// inspectForList inspects one sandbox for a listing; one that can't be
+// inspected is reported broken. With a non-zero timeout, a backend that doesn't
+// answer in time gets the sandbox reported as StatusUnavailable, from what its
+// directory says, as if its backend were down. A change probe that runs out of
+// time reports HasChanges "unknown" (workprobe.WorkUnknown).
+func inspectForList(ctx context.Context, layout config.Layout, t listTarget, timeout time.Duration) *Info {
+	inspectCtx := ctx
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		inspectCtx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	info, err := InspectSandboxWithBackend(inspectCtx, layout, t.rt, t.name)
+	if err == nil {
+		return info
+	}
+	if inspectCtx.Err() != nil && ctx.Err() == nil {
+		slog.Warn("sandbox inspection timed out", "event", "sandbox.inspect.timeout", "sandbox", t.name, "timeout", timeout)
+		if info, err := InspectSandboxWithBackend(ctx, layout, nil, t.name); err == nil {
+			return info
 		}
-		result = append(result, info)
 	}
-	return result, unavailableBackends
+	return brokenInfo(t.name)
 }
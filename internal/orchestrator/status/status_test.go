@@ -10,6 +10,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
@@ -208,6 +209,137 @@ func TestListSandboxesMultiBackend_GroupsAvailableUnavailableAndBroken(t *testin
 	assert.Equal(t, 0, requested[""], "the broken group must not open a runtime")
 }
 
+// A listing inspects its sandboxes concurrently, each within
+// listInspectTimeout, so one backend call that never returns costs the listing
+// one timeout rather than stalling it. The hung sandbox is listed as
+// unavailable from its directory, the others normally, all in name order.
+func TestListSandboxesMultiBackend_HungInspectDoesNotStallListing(t *testing.T) {
+	old := listInspectTimeout
+	listInspectTimeout = 100 * time.Millisecond
+	t.Cleanup(func() { listInspectTimeout = old })
+
+	tmpDir := t.TempDir()
+	sandboxesDir := filepath.Join(tmpDir, ".yoloai", "sandboxes")
+	names := []string{"hung", "s01", "s02", "s03", "s04", "s05", "s06", "s07", "s08", "s09", "s10"}
+	for _, name := range names {
+		dir := filepath.Join(sandboxesDir, name)
+		require.NoError(t, os.MkdirAll(dir, 0750))
+		require.NoError(t, store.SaveEnvironment(dir, &store.Environment{
+			Name:        name,
+			Principal:   config.CLIPrincipal,
+			BackendType: "backend-a",
+			CreatedAt:   time.Now(),
+		}))
+	}
+
+	newRT := func(_ context.Context, _ runtime.BackendType) (runtime.Backend, error) {
+		return &fakeRuntime{
+			inspectFn: func(ctx context.Context, name string) (runtime.InstanceInfo, error) {
+				if strings.HasSuffix(name, "hung") {
+					<-ctx.Done()
+					return runtime.InstanceInfo{}, ctx.Err()
+				}
+				// Slow enough that a serial listing would take longer than
+				// the whole budget below.
+				time.Sleep(50 * time.Millisecond)
+				return runtime.InstanceInfo{}, nil
+			},
+		}, nil
+	}
+
+	layout := config.NewLayout(filepath.Join(tmpDir, ".yoloai")).WithPrincipal(config.CLIPrincipal)
+	start := time.Now()
+	result, _, err := ListSandboxesMultiBackend(context.Background(), layout, newRT)
+	elapsed := time.Since(start)
+	require.NoError(t, err)
+	assert.Less(t, elapsed, 400*time.Millisecond, "the hung inspect must cost one timeout, and the rest run in parallel")
+
+	require.Len(t, result, len(names))
+	for i, info := range result {
+		assert.Equal(t, names[i], info.Environment.Name, "results are in name order")
+	}
+	assert.Equal(t, StatusUnavailable, result[0].Status, "a sandbox whose backend doesn't answer is unavailable, not broken")
+	assert.Equal(t, config.CLIPrincipal, result[0].Environment.Principal, "and still listed from its directory")
+	for _, info := range result[1:] {
+		assert.Equal(t, StatusStopped, info.Status)
+	}
+}
+
+// The complete listing, which up selects from, waits out a slow inspection
+// instead of reporting the sandbox unavailable.
+func TestListSandboxesMultiBackendComplete_WaitsForSlowInspect(t *testing.T) {
+	old := listInspectTimeout
+	listInspectTimeout = 20 * time.Millisecond
+	t.Cleanup(func() { listInspectTimeout = old })
+
+	tmpDir := t.TempDir()
+	dir := filepath.Join(tmpDir, ".yoloai", "sandboxes", "slow")
+	require.NoError(t, os.MkdirAll(dir, 0750))
+	require.NoError(t, store.SaveEnvironment(dir, &store.Environment{
+		Name:        "slow",
+		Principal:   config.CLIPrincipal,
+		BackendType: "backend-a",
+		CreatedAt:   time.Now(),
+	}))
+	newRT := func(_ context.Context, _ runtime.BackendType) (runtime.Backend, error) {
+		return &fakeRuntime{
+			inspectFn: func(ctx context.Context, _ string) (runtime.InstanceInfo, error) {
+				select {
+				case <-ctx.Done():
+					return runtime.InstanceInfo{}, ctx.Err()
+				case <-time.After(100 * time.Millisecond):
+					return runtime.InstanceInfo{}, nil
+				}
+			},
+		}, nil
+	}
+	layout := config.NewLayout(filepath.Join(tmpDir, ".yoloai")).WithPrincipal(config.CLIPrincipal)
+
+	result, _, err := ListSandboxesMultiBackend(context.Background(), layout, newRT)
+	require.NoError(t, err)
+	require.Len(t, result, 1)
+	assert.Equal(t, StatusUnavailable, result[0].Status, "the bounded listing gives up on it")
+
+	result, _, err = ListSandboxesMultiBackendComplete(context.Background(), layout, newRT)
+	require.NoError(t, err)
+	require.Len(t, result, 1)
+	assert.Equal(t, StatusStopped, result[0].Status)
+}
+
+// ListSandboxesMultiBackend closes the runtimes it opened; with
+// ListSandboxesWithRuntimes they are the caller's, so a long-running caller
+// can keep one per backend, and its inspect cache, across listings.
+func TestListSandboxesWithRuntimes_LeavesRuntimesOpen(t *testing.T) {
+	tmpDir := t.TempDir()
+	dir := filepath.Join(tmpDir, ".yoloai", "sandboxes", "alpha")
+	require.NoError(t, os.MkdirAll(dir, 0750))
+	require.NoError(t, store.SaveEnvironment(dir, &store.Environment{
+		Name:        "alpha",
+		Principal:   config.CLIPrincipal,
+		BackendType: "backend-a",
+		CreatedAt:   time.Now(),
+	}))
+	layout := config.NewLayout(filepath.Join(tmpDir, ".yoloai")).WithPrincipal(config.CLIPrincipal)
+	rt := &fakeRuntime{
+		inspectFn: func(_ context.Context, _ string) (runtime.InstanceInfo, error) {
+			return runtime.InstanceInfo{}, nil
+		},
+	}
+	open := func(_ context.Context, _ runtime.BackendType) (runtime.Backend, error) { return rt, nil }
+
+	for range 2 {
+		result, _, err := ListSandboxesWithRuntimes(context.Background(), layout, open)
+		require.NoError(t, err)
+		require.Len(t, result, 1)
+		assert.Equal(t, StatusStopped, result[0].Status)
+	}
+	assert.Equal(t, 0, rt.closes, "the caller's runtime stays open")
+
+	_, _, err := ListSandboxesMultiBackend(context.Background(), layout, open)
+	require.NoError(t, err)
+	assert.Equal(t, 1, rt.closes, "a runtime opened for one listing is closed after it")
+}
+
 // DetectStatus tests (exec fallback — empty sandboxDir)
 
 func TestDetectStatus_Running(t *testing.T) {
@@ -290,6 +422,21 @@ func TestDetectStatus_Stopped(t *testing.T) {
 	assert.Equal(t, StatusStopped, status)
 }
 
+func TestDetectStatus_PausedOverridesStatusFile(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, store.AgentStatusFile),
+		statusJSONBytes("active", nil, time.Now().Unix()), 0600))
+
+	mock := &fakeRuntime{
+		inspectFn: func(_ context.Context, _ string) (runtime.InstanceInfo, error) {
+			return runtime.InstanceInfo{Running: true, Paused: true}, nil
+		},
+	}
+	status, err := DetectStatus(context.Background(), mock, "test", dir)
+	require.NoError(t, err)
+	assert.Equal(t, StatusPaused, status)
+}
+
 // DetectStatus tests (agent-status.json)
 
 func statusJSONBytes(status string, exitCode *int, ts int64) []byte {
@@ -626,3 +773,27 @@ func TestInspectSandboxWithBackend_NetHealth_ProbedWhenActive(t *testing.T) {
 	assert.Equal(t, "ok", info.NetHealth)
 	assert.Equal(t, "192.168.64.12", info.NetHealthDetail)
 }
+
+// A :rw workdir has nothing to diff, but an aux :copy dir beside it does: its
+// changes must still show.
+func TestDetectWorkdirChanges_AuxCopyBesideRWWorkdir(t *testing.T) {
+	dir := t.TempDir()
+	work := store.WorkDir(dir, "/home/u/backend")
+	require.NoError(t, os.MkdirAll(work, 0o750))
+	testutil.InitGitRepo(t, work)
+	testutil.WriteFile(t, work, "file.txt", "hello")
+	testutil.GitAdd(t, work, ".")
+	testutil.GitCommit(t, work, "initial")
+	meta := &store.Environment{Dirs: []store.DirEnvironment{
+		{HostPath: "/home/u/frontend", Mode: store.DirModeRW},
+		{HostPath: "/home/u/backend", Mode: store.DirModeCopy, BaselineSHA: testutil.GitRevParse(t, work)},
+	}}
+	g := git.NewTestHostWithEnv(testutil.GitEnv())
+
+	assert.Equal(t, "no", detectWorkdirChanges(context.Background(), g, dir, meta))
+	testutil.WriteFile(t, work, "file.txt", "modified")
+	assert.Equal(t, "yes", detectWorkdirChanges(context.Background(), g, dir, meta))
+
+	meta.Dirs = meta.Dirs[:1]
+	assert.Equal(t, "-", detectWorkdirChanges(context.Background(), g, dir, meta), "nothing tracked")
+}
@@ -74,7 +74,7 @@ func (e *Engine) capturePane(ctx context.Context, containerName, socket, user st
 	if socket != "" {
 		args = append(args, "-S", socket)
 	}
-	args = append(args, "capture-pane", "-p", "-t", "main")
+	args = append(args, "capture-pane", "-p", "-t", "main:{start}")
 	if scrollback != 0 {
 		args = append(args, "-S", fmt.Sprintf("%d", -scrollback))
 	}
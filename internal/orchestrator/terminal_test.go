@@ -93,18 +93,18 @@ func TestCaptureTerminal_BuildsTmuxCommand(t *testing.T) {
 	assert.Equal(t, "\x1b[31magent screen\x1b[0m\n", string(ansi))
 
 	// Two Exec calls — one plain, one ANSI. Both share the same prefix
-	// (tmux -S <socket> capture-pane -p -t main -S -200), the ANSI one
+	// (tmux -S <socket> capture-pane -p -t main:{start} -S -200), the ANSI one
 	// also has the trailing -e flag.
 	require.Len(t, mock.execCalls, 2)
 	plainArgs := mock.execCalls[0]
 	ansiArgs := mock.execCalls[1]
 	assert.Equal(t, []string{
 		"tmux", "-S", "/tmp/yoloai-tmux.sock",
-		"capture-pane", "-p", "-t", "main", "-S", "-200",
+		"capture-pane", "-p", "-t", "main:{start}", "-S", "-200",
 	}, plainArgs)
 	assert.Equal(t, []string{
 		"tmux", "-S", "/tmp/yoloai-tmux.sock",
-		"capture-pane", "-p", "-t", "main", "-S", "-200", "-e",
+		"capture-pane", "-p", "-t", "main:{start}", "-S", "-200", "-e",
 	}, ansiArgs)
 
 	// User propagation: tmux server runs as the sandbox's container user
@@ -157,10 +157,10 @@ func TestCaptureTerminal_ZeroScrollbackOmitsHistoryFlag(t *testing.T) {
 	// Each call should have exactly one "-S" (for the socket).
 	require.Len(t, mock.execCalls, 2)
 	assert.Equal(t, []string{
-		"tmux", "-S", "/tmp/yoloai-tmux.sock", "capture-pane", "-p", "-t", "main",
+		"tmux", "-S", "/tmp/yoloai-tmux.sock", "capture-pane", "-p", "-t", "main:{start}",
 	}, mock.execCalls[0])
 	assert.Equal(t, []string{
-		"tmux", "-S", "/tmp/yoloai-tmux.sock", "capture-pane", "-p", "-t", "main", "-e",
+		"tmux", "-S", "/tmp/yoloai-tmux.sock", "capture-pane", "-p", "-t", "main:{start}", "-e",
 	}, mock.execCalls[1])
 }
 
@@ -0,0 +1,36 @@
+package orchestrator
+
+// ABOUTME: MeasureTokenUsage — a sandbox's agent token usage and estimated cost,
+// ABOUTME: read from the records the agent keeps, for `yoloai cost` and sandbox info.
+
+import (
+	"github.com/kstenerud/yoloai/internal/agent"
+	"github.com/kstenerud/yoloai/internal/config"
+	"github.com/kstenerud/yoloai/internal/orchestrator/agentcfg"
+	"github.com/kstenerud/yoloai/internal/tokenusage"
+	"github.com/kstenerud/yoloai/store"
+)
+
+// TokenUsage is what a sandbox's agent has spent; see tokenusage.Totals.
+type TokenUsage = tokenusage.Totals
+
+// MeasureTokenUsage totals the tokens the named sandbox's agent has recorded
+// and their cost. supported is false, with a zero usage, when the agent keeps
+// no usage record yoloai can read (agent.Definition.UsageSource). A pure host
+// read: it works on stopped sandboxes and needs no backend.
+func MeasureTokenUsage(layout config.Layout, name string) (usage TokenUsage, agentType string, supported bool, err error) {
+	sandboxDir := layout.SandboxDir(name)
+	if err := store.RequireSandboxDir(sandboxDir); err != nil {
+		return TokenUsage{}, "", false, err
+	}
+	acfg, err := agentcfg.Load(sandboxDir)
+	if err != nil {
+		return TokenUsage{}, "", false, err
+	}
+	def := agent.GetAgent(acfg.AgentType)
+	if def == nil || def.UsageSource == "" {
+		return TokenUsage{}, acfg.AgentType, false, nil
+	}
+	usage, err = tokenusage.Measure(def.UsageSource, sandboxDir)
+	return usage, acfg.AgentType, true, err
+}
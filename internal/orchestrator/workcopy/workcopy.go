@@ -9,9 +9,12 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	goruntime "runtime"
 
+	"github.com/kstenerud/yoloai/internal/config"
 	"github.com/kstenerud/yoloai/internal/git"
 	"github.com/kstenerud/yoloai/internal/orchestrator/baseline"
+	"github.com/kstenerud/yoloai/internal/timing"
 	"github.com/kstenerud/yoloai/internal/workspace"
 	"github.com/kstenerud/yoloai/runtime"
 )
@@ -23,14 +26,17 @@ type Strategy int
 
 const (
 	// WipeAndCopy rebuilds dst from scratch: remove it, then copy. For create's
-	// not-yet-existing destination and reset --restart's stale one — both cases
-	// where nothing is watching dst. After the copy dst holds exactly the source
+	// not-yet-existing destination. After the copy dst holds exactly the source
 	// file set, so no prune is needed.
 	WipeAndCopy Strategy = iota
-	// InPlaceAndPrune overwrites dst without replacing the directory, then prunes
-	// what the source no longer has. For in-place reset, whose dst is bind-mounted
-	// into a live container: RemoveAll would strand the container on a deleted
-	// inode. Only .git is removed-and-replaced, as a unit, never merged (DF118).
+	// InPlaceAndPrune syncs dst without replacing the directory, rewriting only
+	// what changed, then prunes what the source no longer has. For every reset:
+	// an in-place reset's dst is bind-mounted into a live container, where
+	// RemoveAll would strand the container on a deleted inode, and a large copy
+	// resets in seconds instead of being copied again in full. The source's .git
+	// is mirrored rather than merged, so the object store survives but the result
+	// is exactly the source's repo (DF118); a .git yoloai made itself (no .git in
+	// the source, or :copy-strict) is replaced as a unit.
 	InPlaceAndPrune
 )
 
@@ -42,6 +48,26 @@ type Spec struct {
 	Src            string // absolute host path of the source directory
 	IncludeIgnored bool   // :copy-all — copy gitignored files too
 	StripHistory   bool   // :copy-strict — fresh baseline instead of preserving .git
+	// ObjectStores is where work copies of Src share their git packs (see
+	// ObjectStoreRoot); "" gives this copy packs of its own.
+	ObjectStores string
+	// BlockPush points every remote the copy's .git carries over from Src at
+	// git.PushBlockedURL, so the agent can't push to them (unless the sandbox
+	// was made with --allow-push). The copy is yoloai's, so its config is
+	// rewritten; the agent's environment alone can be unset.
+	BlockPush bool
+}
+
+// ObjectStoreRoot returns where work copies of src share their git packs:
+// <objectsDir>/<caret-encoded src>/, holding one store per set of packs, named
+// for the set. Keeping each source's stores apart is what lets a sandbox be
+// handed its stores without being handed another directory's history. An
+// empty objectsDir means no sharing, and returns "".
+func ObjectStoreRoot(objectsDir, src string) string {
+	if objectsDir == "" {
+		return ""
+	}
+	return filepath.Join(objectsDir, config.EncodePath(src))
 }
 
 // HistoryNotice reports why the source's git history did not come along, if it
@@ -78,27 +104,116 @@ func Materialize(ctx context.Context, spec Spec, dst string, strategy Strategy,
 	// second `git ls-files`).
 	listProjectFiles := memoizeProjectFiles(ctx, g, spec.Src)
 
-	if err := bringDestinationInLine(ctx, spec, dst, strategy, preserveGit, listProjectFiles); err != nil {
+	objects := ""
+	if preserveGit {
+		objects = objectStore(spec, dst, strategy, backend)
+	}
+	stopCopy := timing.Track(ctx, timing.PhaseCopy)
+	err := bringDestinationInLine(ctx, spec, dst, strategy, preserveGit, objects, listProjectFiles, workspace.PruneToFileSet)
+	stopCopy()
+	if err != nil {
 		return "", notice, err
 	}
+	if spec.BlockPush && preserveGit {
+		if err := g.BlockPush(ctx, dst); err != nil {
+			return "", notice, fmt.Errorf("block pushes from %s: %w", dst, err)
+		}
+	}
 
 	if runtime.LocalityOf(backend) == runtime.LocalitySandboxSide {
 		return "", notice, nil
 	}
+	stopBaseline := timing.Track(ctx, timing.PhaseBaseline)
 	sha, err := baseline.WorkCopy(ctx, g, dst)
+	stopBaseline()
 	if err != nil {
 		return "", notice, err
 	}
 	return sha, notice, nil
 }
 
-func bringDestinationInLine(ctx context.Context, spec Spec, dst string, strategy Strategy, preserveGit bool, listProjectFiles func() ([]string, bool, error)) error {
+// Mirror brings dst in line with spec.Src the way InPlaceAndPrune does, .git
+// included, but establishes no baseline. spec.Src must already be the repo dst
+// is to become: rebase's rehearsal, whose history holds the new baseline with
+// the agent's commits replayed on top and whose worktree holds the agent's
+// uncommitted edits. Baselining it would commit those edits as the starting
+// point. spec.StripHistory is ignored, because here the .git is the point.
+// A dst that shares its packs with an object store keeps sharing them, so
+// spec.Src should hold its own.
+//
+// Unlike a reset, the prune spares what spec.Src's ignore rules ignore: the
+// agent's node_modules, build output and caches are its working state, not
+// a stray copy of the source, and rebuilding them is not the rebase's call.
+func Mirror(ctx context.Context, spec Spec, dst string, g *git.Git) error {
+	objects := objectStore(spec, dst, InPlaceAndPrune, nil)
+	prune := func(dst string, files []string) error {
+		extra, err := workspace.ExtraPaths(dst, files)
+		if err != nil {
+			return err
+		}
+		ignored, err := g.CheckIgnored(ctx, spec.Src, extra)
+		if err != nil {
+			return err
+		}
+		var doomed []string
+		for _, rel := range extra {
+			if !ignored[rel] {
+				doomed = append(doomed, rel)
+			}
+		}
+		return workspace.RemovePaths(dst, doomed)
+	}
+	if err := bringDestinationInLine(ctx, spec, dst, InPlaceAndPrune, true, objects, memoizeProjectFiles(ctx, g, spec.Src), prune); err != nil {
+		return err
+	}
+	if spec.BlockPush {
+		if err := g.BlockPush(ctx, dst); err != nil {
+			return fmt.Errorf("block pushes from %s: %w", dst, err)
+		}
+	}
+	return nil
+}
+
+// objectStore returns the object store a work copy at dst is to share its git
+// packs with, or "" for none.
+//
+// A new copy shares the store named for the source's current packs — one
+// other copies of it may already have made — but only on a Linux host and a
+// backend that mounts the work copy from the host. Elsewhere a copy is either
+// a copy-on-write clone already (APFS), or not on the host at all (Tart).
+//
+// A copy being brought in line keeps the store it already shares and never
+// takes one up: a running sandbox only sees the stores mounted when it
+// started, and the source having moved on just means a new pack in the copy.
+func objectStore(spec Spec, dst string, strategy Strategy, backend runtime.Backend) string {
+	if spec.ObjectStores == "" {
+		return ""
+	}
+	if strategy == InPlaceAndPrune {
+		if stores := workspace.SharedObjectStores(dst, spec.ObjectStores); len(stores) > 0 {
+			return stores[0]
+		}
+		return ""
+	}
+	if goruntime.GOOS != "linux" || backend == nil || runtime.LocalityOf(backend) != runtime.LocalityHostSide {
+		return ""
+	}
+	key, err := workspace.PackSetKey(filepath.Join(spec.Src, ".git"))
+	if err != nil || key == "" {
+		return "" // nothing to share, or nothing readable: copy what's there
+	}
+	return filepath.Join(spec.ObjectStores, key)
+}
+
+// bringDestinationInLine copies spec.Src to dst by strategy. InPlaceAndPrune
+// removes what the copy doesn't account for with prune.
+func bringDestinationInLine(ctx context.Context, spec Spec, dst string, strategy Strategy, preserveGit bool, objects string, listProjectFiles func() ([]string, bool, error), prune func(dst string, files []string) error) error {
 	switch strategy {
 	case WipeAndCopy:
 		if err := os.RemoveAll(dst); err != nil {
 			return fmt.Errorf("clear work copy %s: %w", dst, err)
 		}
-		if err := workspace.CopyProjectDir(spec.Src, dst, spec.IncludeIgnored, preserveGit, listProjectFiles); err != nil {
+		if err := workspace.CopyProjectDir(spec.Src, dst, spec.IncludeIgnored, preserveGit, objects, listProjectFiles); err != nil {
 			return fmt.Errorf("copy %s: %w", spec.Src, err)
 		}
 		return nil
@@ -108,18 +223,19 @@ func bringDestinationInLine(ctx context.Context, spec Spec, dst string, strategy
 		if err != nil {
 			return fmt.Errorf("enumerate project files of %s: %w", spec.Src, err)
 		}
-		// Replace .git as a unit rather than let CopyProjectDir merge it over the
-		// existing repo — a merge can leave a ref pointing at a deleted object
-		// (DF118). The live-mounted dst itself is never removed.
-		if err := os.RemoveAll(filepath.Join(dst, ".git")); err != nil {
-			return fmt.Errorf("remove work copy .git: %w", err)
+		// A .git the sync won't mirror from the source is the baseline yoloai
+		// built over the last copy, agent commits and all: replace it whole.
+		if !workspace.WantsGitDir(spec.IncludeIgnored, preserveGit) || !workspace.HasGitDir(spec.Src) {
+			if err := os.RemoveAll(filepath.Join(dst, ".git")); err != nil {
+				return fmt.Errorf("remove work copy .git: %w", err)
+			}
 		}
-		if err := workspace.CopyProjectDir(spec.Src, dst, spec.IncludeIgnored, preserveGit, listProjectFiles); err != nil {
-			return fmt.Errorf("copy %s: %w", spec.Src, err)
+		if err := workspace.SyncProjectDir(spec.Src, dst, spec.IncludeIgnored, preserveGit, objects, listProjectFiles); err != nil {
+			return fmt.Errorf("sync %s: %w", spec.Src, err)
 		}
-		// Copying refreshes what the source still has; pruning removes what the
+		// Syncing refreshes what the source still has; pruning removes what the
 		// agent added and what the source dropped — a leaked secret included (DF117).
-		if err := workspace.PruneToFileSet(dst, files); err != nil {
+		if err := prune(dst, files); err != nil {
 			return fmt.Errorf("prune work copy: %w", err)
 		}
 		return nil
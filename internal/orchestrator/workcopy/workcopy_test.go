@@ -154,7 +154,7 @@ func TestMaterialize_SandboxSide_DefersBaseline(t *testing.T) {
 	assert.True(t, exists(filepath.Join(dst, "app.js")), "but the copy is staged on the host")
 }
 
-// WipeAndCopy over a stale destination (the reset --restart case) leaves exactly
+// WipeAndCopy over a stale destination (a leftover the create reuses) leaves exactly
 // the source's files — the agent's leftovers are gone.
 func TestMaterialize_RebuildsStaleDestination(t *testing.T) {
 	src := repo(t)
@@ -24,7 +24,8 @@ const (
 	WorkDirty
 	// WorkUnknown: the state could not be determined because the working copy
 	// lives in a backend whose execution context is unavailable (e.g. a Tart VM
-	// that is not running). Callers must fail safe and treat it as possibly dirty.
+	// that is not running), or the probe ran out of time. Callers must fail safe
+	// and treat it as possibly dirty.
 	WorkUnknown
 )
 
@@ -41,7 +42,7 @@ func DetectChanges(ctx context.Context, g *git.Git, workDir string) string {
 	if _, err := os.Stat(filepath.Join(workDir, ".git")); err != nil {
 		return "-"
 	}
-	output, err := g.Run(ctx, workDir, "status", "--porcelain")
+	output, err := g.WorkCopyStatus(ctx, workDir)
 	if err != nil {
 		return "-"
 	}
@@ -75,11 +76,13 @@ func porcelainHasChange(output string) bool {
 // unavailable — the backend reports the instance is not running — it returns WorkUnknown so
 // callers fail safe rather than read a stale host seed copy the VM never wrote
 // back to (see backend-idiosyncrasies.md "VirtioFS corrupts git repositories").
+// A probe cut short by ctx is WorkUnknown too, not the "no work" a failing git
+// otherwise reports: a listing's deadline must not make a dirty copy look clean.
 // g is a sandbox-scoped git runner derived from the caller's layout (DEV §12).
 func HasUnappliedWorkVia(ctx context.Context, g *git.Git, workDir, baselineSHA string) WorkProbe {
-	out, err := g.Run(ctx, workDir, "status", "--porcelain")
+	out, err := g.WorkCopyStatus(ctx, workDir)
 	if err != nil {
-		if errors.Is(err, runtime.ErrNotRunning) {
+		if errors.Is(err, runtime.ErrNotRunning) || ctx.Err() != nil {
 			return WorkUnknown
 		}
 		// A non-repo or otherwise unreadable workdir mirrors the historical host
@@ -94,7 +97,7 @@ func HasUnappliedWorkVia(ctx context.Context, g *git.Git, workDir, baselineSHA s
 	}
 	out, err = g.Run(ctx, workDir, "rev-list", "--count", baselineSHA+"..HEAD")
 	if err != nil {
-		if errors.Is(err, runtime.ErrNotRunning) {
+		if errors.Is(err, runtime.ErrNotRunning) || ctx.Err() != nil {
 			return WorkUnknown
 		}
 		return WorkClean
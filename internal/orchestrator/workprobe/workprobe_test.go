@@ -87,6 +87,21 @@ func TestHasUnappliedWorkVia_DirtyWorkingTree(t *testing.T) {
 	assert.Equal(t, WorkDirty, HasUnappliedWorkVia(context.Background(), git.NewTestHostWithEnv(testutil.GitEnv()), dir, sha))
 }
 
+// A probe cut short by its context can't have read the copy, so it must not
+// report the copy clean the way an unreadable workdir does.
+func TestHasUnappliedWorkVia_CancelledIsUnknown(t *testing.T) {
+	dir := t.TempDir()
+	testutil.InitGitRepo(t, dir)
+	testutil.WriteFile(t, dir, "file.txt", "hello")
+	testutil.GitAdd(t, dir, ".")
+	testutil.GitCommit(t, dir, "initial")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	sha := testutil.GitRevParse(t, dir)
+	assert.Equal(t, WorkUnknown, HasUnappliedWorkVia(ctx, git.NewTestHostWithEnv(testutil.GitEnv()), dir, sha))
+}
+
 func TestHasUnappliedWorkVia_CommitsBeyondBaseline(t *testing.T) {
 	dir := t.TempDir()
 	testutil.InitGitRepo(t, dir)
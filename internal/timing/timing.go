@@ -0,0 +1,121 @@
+// ABOUTME: Per-command phase timings (image ensure, copy, baseline, container
+// ABOUTME: create, boot wait) carried on the context, for `--timings` and cli.jsonl.
+
+// Package timing records how long the slow phases of a command took. The
+// CLI puts a Recorder on the command's context; the orchestrator brackets
+// each phase with Track, which is a no-op when no Recorder is present, so
+// library embedders pay nothing for it.
+package timing
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// Phase names, in the order a create-and-start usually runs them.
+const (
+	// PhaseImage is ensuring the base and profile images are built.
+	PhaseImage = "image"
+	// PhaseCopy is copying a :copy directory into the sandbox.
+	PhaseCopy = "copy"
+	// PhaseBaseline is committing a work copy's diff baseline (in the VM
+	// for backends that baseline there).
+	PhaseBaseline = "baseline"
+	// PhaseCreate is creating and starting the container or VM.
+	PhaseCreate = "create"
+	// PhaseBoot is waiting for the started instance to signal it is ready.
+	PhaseBoot = "boot"
+)
+
+// Span is the accumulated time of one phase. A phase that runs more than
+// once (a copy per :copy directory) is summed, and Count says how often.
+type Span struct {
+	Phase    string
+	Duration time.Duration
+	Count    int
+}
+
+// Recorder collects phase durations for one command. Safe for concurrent use.
+type Recorder struct {
+	mutex   sync.Mutex
+	start   time.Time
+	sandbox string
+	spans   []Span
+}
+
+// NewRecorder returns a Recorder whose total is measured from now.
+func NewRecorder() *Recorder {
+	return &Recorder{start: time.Now()}
+}
+
+type recorderKey struct{}
+
+// WithRecorder returns ctx carrying r.
+func WithRecorder(ctx context.Context, r *Recorder) context.Context {
+	return context.WithValue(ctx, recorderKey{}, r)
+}
+
+// FromContext returns the Recorder on ctx, or nil.
+func FromContext(ctx context.Context) *Recorder {
+	r, _ := ctx.Value(recorderKey{}).(*Recorder)
+	return r
+}
+
+// Track starts timing phase and returns the func that ends it. Without a
+// Recorder on ctx it costs nothing and the returned func does nothing.
+//
+//	defer timing.Track(ctx, timing.PhaseCopy)()
+func Track(ctx context.Context, phase string) func() {
+	r := FromContext(ctx)
+	if r == nil {
+		return func() {}
+	}
+	began := time.Now()
+	return func() { r.Add(phase, time.Since(began)) }
+}
+
+// SetSandbox names the sandbox the command worked on, so the summary can be
+// written to that sandbox's cli.jsonl. A no-op without a Recorder on ctx.
+func SetSandbox(ctx context.Context, name string) {
+	if r := FromContext(ctx); r != nil {
+		r.mutex.Lock()
+		r.sandbox = name
+		r.mutex.Unlock()
+	}
+}
+
+// Add records d against phase, and logs it at debug level.
+func (r *Recorder) Add(phase string, d time.Duration) {
+	slog.Debug("phase finished", "event", "timing.phase", "phase", phase, "duration_ms", d.Milliseconds())
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	for i := range r.spans {
+		if r.spans[i].Phase == phase {
+			r.spans[i].Duration += d
+			r.spans[i].Count++
+			return
+		}
+	}
+	r.spans = append(r.spans, Span{Phase: phase, Duration: d, Count: 1})
+}
+
+// Spans returns the recorded phases in the order each first ran.
+func (r *Recorder) Spans() []Span {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return append([]Span(nil), r.spans...)
+}
+
+// Sandbox returns the name passed to SetSandbox, or "".
+func (r *Recorder) Sandbox() string {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return r.sandbox
+}
+
+// Total returns the time since NewRecorder.
+func (r *Recorder) Total() time.Duration {
+	return time.Since(r.start)
+}
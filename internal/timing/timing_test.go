@@ -0,0 +1,47 @@
+package timing
+
+// ABOUTME: Tests for the phase Recorder: summing repeated phases in first-run
+// ABOUTME: order, and Track/SetSandbox being no-ops without a Recorder.
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecorder_SumsRepeatedPhasesInFirstRunOrder(t *testing.T) {
+	r := NewRecorder()
+	r.Add(PhaseCopy, 2*time.Second)
+	r.Add(PhaseBaseline, time.Second)
+	r.Add(PhaseCopy, 3*time.Second)
+
+	assert.Equal(t, []Span{
+		{Phase: PhaseCopy, Duration: 5 * time.Second, Count: 2},
+		{Phase: PhaseBaseline, Duration: time.Second, Count: 1},
+	}, r.Spans())
+}
+
+func TestTrack_RecordsOnTheContextsRecorder(t *testing.T) {
+	r := NewRecorder()
+	ctx := WithRecorder(context.Background(), r)
+
+	Track(ctx, PhaseBoot)()
+	SetSandbox(ctx, "demo")
+
+	spans := r.Spans()
+	require.Len(t, spans, 1)
+	assert.Equal(t, PhaseBoot, spans[0].Phase)
+	assert.Equal(t, 1, spans[0].Count)
+	assert.Equal(t, "demo", r.Sandbox())
+	assert.GreaterOrEqual(t, r.Total(), spans[0].Duration)
+}
+
+func TestTrack_NoRecorderIsANoOp(t *testing.T) {
+	ctx := context.Background()
+	assert.Nil(t, FromContext(ctx))
+	Track(ctx, PhaseCopy)()
+	SetSandbox(ctx, "demo") // must not panic
+}
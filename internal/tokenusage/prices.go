@@ -0,0 +1,68 @@
+// ABOUTME: List prices per million tokens for the models the built-in agents use,
+// ABOUTME: which the cost estimate for Claude and Codex usage is computed from.
+package tokenusage
+
+import "strings"
+
+// modelPrice is a model's list price in US dollars per million tokens.
+type modelPrice struct {
+	input, output, cacheWrite, cacheRead float64
+}
+
+// cost is what u costs at p.
+func (p modelPrice) cost(u tokens) float64 {
+	return (float64(u.input)*p.input + float64(u.output)*p.output +
+		float64(u.cacheWrite)*p.cacheWrite + float64(u.cacheRead)*p.cacheRead) / 1e6
+}
+
+// anthropic is an Anthropic price: a 5-minute cache write costs 1.25x input
+// and a cache read 0.1x.
+func anthropic(input, output float64) modelPrice {
+	return modelPrice{input: input, output: output, cacheWrite: input * 1.25, cacheRead: input / 10}
+}
+
+// openai is an OpenAI price: cache writes are free, cached input is billed at
+// cachedInput.
+func openai(input, output, cachedInput float64) modelPrice {
+	return modelPrice{input: input, output: output, cacheRead: cachedInput}
+}
+
+// prices maps a model name, or the prefix of a dated or suffixed variant
+// (claude-sonnet-4-5-20250929, gpt-5-codex), to its list price. A model
+// missing here is reported as unpriced rather than guessed at; update the
+// table when the agents' default models move.
+var prices = map[string]modelPrice{
+	"claude-opus-4-6":   anthropic(5, 25),
+	"claude-opus-4-5":   anthropic(5, 25),
+	"claude-opus-4":     anthropic(15, 75),
+	"claude-sonnet-4":   anthropic(3, 15),
+	"claude-3-7-sonnet": anthropic(3, 15),
+	"claude-3-5-sonnet": anthropic(3, 15),
+	"claude-haiku-4-5":  anthropic(1, 5),
+	"claude-3-5-haiku":  anthropic(0.8, 4),
+	"claude-3-haiku":    anthropic(0.25, 1.25),
+
+	"gpt-5":             openai(1.25, 10, 0.125),
+	"gpt-5.1":           openai(1.25, 10, 0.125),
+	"gpt-5-mini":        openai(0.25, 2, 0.025),
+	"gpt-5-nano":        openai(0.05, 0.4, 0.005),
+	"gpt-4.1":           openai(2, 8, 0.5),
+	"gpt-4o":            openai(2.5, 10, 1.25),
+	"o3":                openai(2, 8, 0.5),
+	"o4-mini":           openai(1.1, 4.4, 0.275),
+	"codex-mini-latest": openai(1.5, 6, 0.375),
+}
+
+// lookupPrice finds model's price: the longest table entry that is model
+// itself or a prefix of it ending at a "-", so "claude-opus-4-5-20251101"
+// is priced as claude-opus-4-5, not claude-opus-4, and "gpt-5.3-codex" is
+// not taken for gpt-5.
+func lookupPrice(model string) (modelPrice, bool) {
+	best, found := "", false
+	for name := range prices {
+		if (model == name || strings.HasPrefix(model, name+"-")) && len(name) > len(best) {
+			best, found = name, true
+		}
+	}
+	return prices[best], found
+}
@@ -0,0 +1,230 @@
+// ABOUTME: The per-agent usage readers: Claude transcript usage blocks, Codex
+// ABOUTME: token_count events, and aider's "Tokens: ... Cost: ..." terminal lines.
+package tokenusage
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// eachLine calls fn with each line of the file at path. Lines are read whole
+// however long they are: a transcript line carries a full tool result.
+func eachLine(path string, fn func(line []byte)) error {
+	f, err := os.Open(path) //nolint:gosec // path is under the sandbox dir
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	defer f.Close() //nolint:errcheck // read-only
+	r := bufio.NewReader(f)
+	for {
+		line, err := r.ReadBytes('\n')
+		if len(line) > 0 {
+			fn(line)
+		}
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("read %s: %w", path, err)
+		}
+	}
+}
+
+// claudeEntry is the part of a Claude Code transcript line that carries
+// usage: an assistant message, written once per content block with the same
+// message id.
+type claudeEntry struct {
+	Message struct {
+		ID    string `json:"id"`
+		Model string `json:"model"`
+		Usage *struct {
+			InputTokens              int64 `json:"input_tokens"`
+			OutputTokens             int64 `json:"output_tokens"`
+			CacheCreationInputTokens int64 `json:"cache_creation_input_tokens"`
+			CacheReadInputTokens     int64 `json:"cache_read_input_tokens"`
+		} `json:"usage"`
+	} `json:"message"`
+}
+
+// claudeTranscripts reads the usage of every assistant message in the
+// transcripts under projectsDir, by model. A message is counted once however
+// many lines and files repeat it (a resumed session copies its history into
+// the new transcript), with the usage of its last line, which is final.
+func claudeTranscripts(projectsDir string) (map[string]tokens, error) {
+	type message struct {
+		model string
+		usage tokens
+	}
+	messages := make(map[string]message)
+	err := walkFiles(projectsDir, ".jsonl", func(path string) error {
+		return eachLine(path, func(line []byte) {
+			if !bytes.Contains(line, []byte(`"usage"`)) {
+				return
+			}
+			var e claudeEntry
+			if json.Unmarshal(line, &e) != nil || e.Message.Usage == nil || e.Message.ID == "" {
+				return
+			}
+			u := e.Message.Usage
+			messages[e.Message.ID] = message{model: e.Message.Model, usage: tokens{
+				input:      u.InputTokens,
+				output:     u.OutputTokens,
+				cacheWrite: u.CacheCreationInputTokens,
+				cacheRead:  u.CacheReadInputTokens,
+			}}
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	byModel := make(map[string]tokens)
+	for _, m := range messages {
+		if m.usage == (tokens{}) {
+			continue // e.g. "<synthetic>" messages Claude Code makes up itself
+		}
+		u := byModel[m.model]
+		u.add(m.usage)
+		byModel[m.model] = u
+	}
+	return byModel, nil
+}
+
+// codexLine is the part of a Codex session rollout line that usage needs:
+// the turn context naming the model, or a token_count event with the
+// session's running totals.
+type codexLine struct {
+	Type    string `json:"type"`
+	Payload struct {
+		Type  string `json:"type"`
+		Model string `json:"model"`
+		Info  *struct {
+			Total codexUsage `json:"total_token_usage"`
+		} `json:"info"`
+	} `json:"payload"`
+}
+
+type codexUsage struct {
+	InputTokens       int64 `json:"input_tokens"`
+	CachedInputTokens int64 `json:"cached_input_tokens"`
+	OutputTokens      int64 `json:"output_tokens"`
+}
+
+// codexSessions reads the session rollouts under sessionsDir, by model. Each
+// token_count event carries the session's running total; the growth since
+// the previous one goes to the model the current turn runs on, so repeated
+// events count once and a mid-session model switch is priced right. Codex
+// counts cached tokens inside input_tokens.
+func codexSessions(sessionsDir string) (map[string]tokens, error) {
+	byModel := make(map[string]tokens)
+	err := walkFiles(sessionsDir, ".jsonl", func(path string) error {
+		model := "codex"
+		var prev codexUsage
+		return eachLine(path, func(line []byte) {
+			if !bytes.Contains(line, []byte(`"turn_context"`)) && !bytes.Contains(line, []byte(`"token_count"`)) {
+				return
+			}
+			var l codexLine
+			if json.Unmarshal(line, &l) != nil {
+				return
+			}
+			switch {
+			case l.Type == "turn_context" && l.Payload.Model != "":
+				model = l.Payload.Model
+			case l.Payload.Type == "token_count" && l.Payload.Info != nil:
+				cur := l.Payload.Info.Total
+				if cur.InputTokens < prev.InputTokens || cur.OutputTokens < prev.OutputTokens {
+					prev = codexUsage{} // a restarted count
+				}
+				cached := cur.CachedInputTokens - prev.CachedInputTokens
+				u := byModel[model]
+				u.add(tokens{
+					input:     cur.InputTokens - prev.InputTokens - cached,
+					output:    cur.OutputTokens - prev.OutputTokens,
+					cacheRead: cached,
+				})
+				byModel[model] = u
+				prev = cur
+			}
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return byModel, nil
+}
+
+// ansiEscape matches the terminal escape sequences in the agent log.
+var ansiEscape = regexp.MustCompile(`\x1b(?:\[[0-?]*[ -/]*[@-~]|\][^\x07\x1b]*(?:\x07|\x1b\\)|[ -/]*[0-~])`)
+
+// aiderTokenPart is one "<count> <kind>" in aider's Tokens line, the count
+// as aider formats it: 950, 2.5k, 12k, 1.2M.
+var aiderTokenPart = regexp.MustCompile(`([0-9]+(?:\.[0-9]+)?)([kKmM]?) (sent|received|cache write|cache hit)`)
+
+// aiderMessageCost is the cost of one reply in aider's Cost line.
+var aiderMessageCost = regexp.MustCompile(`Cost: \$([0-9]+(?:\.[0-9]+)?) message`)
+
+// aiderLog reads the usage line aider prints after each reply —
+// "Tokens: 2.5k sent, 1.1k cache hit, 119 received. Cost: $0.02 message,
+// $0.05 session." — from the agent's terminal log. The cost is aider's own,
+// summed per message, since its session total restarts with aider. Aider
+// rounds the counts it prints, so the token totals are approximate.
+func aiderLog(logPath string) (Totals, error) {
+	var t Totals
+	err := eachLine(logPath, func(raw []byte) {
+		// A pane redraw can put several screen lines on one log line.
+		for _, line := range bytes.Split(ansiEscape.ReplaceAll(raw, nil), []byte("\r")) {
+			s := string(line)
+			i := strings.Index(s, "Tokens: ")
+			if i < 0 {
+				continue
+			}
+			s = s[i:]
+			tokensPart, _, _ := strings.Cut(s, "Cost: ")
+			for _, m := range aiderTokenPart.FindAllStringSubmatch(tokensPart, -1) {
+				n := aiderCount(m[1], m[2])
+				switch m[3] {
+				case "sent":
+					t.Input += n
+				case "received":
+					t.Output += n
+				case "cache write":
+					t.CacheWrite += n
+				case "cache hit":
+					t.CacheRead += n
+				}
+			}
+			if m := aiderMessageCost.FindStringSubmatch(s); m != nil {
+				if c, err := strconv.ParseFloat(m[1], 64); err == nil {
+					t.CostUSD += c
+				}
+			}
+		}
+	})
+	return t, err
+}
+
+// aiderCount turns an aider-formatted count back into a number.
+func aiderCount(num, suffix string) int64 {
+	f, err := strconv.ParseFloat(num, 64)
+	if err != nil {
+		return 0
+	}
+	switch strings.ToLower(suffix) {
+	case "k":
+		f *= 1e3
+	case "m":
+		f *= 1e6
+	}
+	return int64(f + 0.5)
+}
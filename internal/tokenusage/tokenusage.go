@@ -0,0 +1,124 @@
+// ABOUTME: Token usage and estimated cost of a sandbox's agent, read back from the
+// ABOUTME: records the agent keeps: Claude/Codex session files, aider's terminal log.
+
+// Package tokenusage totals what an agent has spent in a sandbox. Each agent
+// records its usage in its own format (agent.Definition.UsageSource); this
+// package reads them from the sandbox directory on the host, so it needs no
+// backend and works on stopped sandboxes.
+package tokenusage
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/kstenerud/yoloai/internal/agent"
+	"github.com/kstenerud/yoloai/store"
+)
+
+// Totals is what an agent has spent, summed over every session it recorded.
+type Totals struct {
+	// Input is the uncached input tokens; CacheWrite and CacheRead are the
+	// input tokens written to and read from the prompt cache.
+	Input      int64
+	Output     int64
+	CacheWrite int64
+	CacheRead  int64
+	// CostUSD is the cost in US dollars of the tokens that have a price.
+	CostUSD float64
+	// Estimated means CostUSD was computed from list prices (see prices.go);
+	// otherwise it is the figure the agent itself reported.
+	Estimated bool
+	// Models lists the models the tokens were spent on, sorted.
+	Models []string
+	// Unpriced lists the models without a known price, sorted. Their tokens
+	// are counted but not in CostUSD.
+	Unpriced []string
+}
+
+// Total is every token counted, cached or not.
+func (t Totals) Total() int64 {
+	return t.Input + t.Output + t.CacheWrite + t.CacheRead
+}
+
+// tokens is one model's share of the usage.
+type tokens struct {
+	input, output, cacheWrite, cacheRead int64
+}
+
+func (t *tokens) add(o tokens) {
+	t.input += o.input
+	t.output += o.output
+	t.cacheWrite += o.cacheWrite
+	t.cacheRead += o.cacheRead
+}
+
+// Measure totals the usage src records in the sandbox at sandboxDir. A
+// sandbox whose agent hasn't recorded anything yet has zero Totals. src ""
+// is an error: the agent records nothing to read.
+func Measure(src agent.UsageSource, sandboxDir string) (Totals, error) {
+	stateDir := filepath.Join(sandboxDir, store.AgentRuntimeDir)
+	switch src {
+	case agent.UsageClaudeTranscripts:
+		byModel, err := claudeTranscripts(filepath.Join(stateDir, "projects"))
+		if err != nil {
+			return Totals{}, err
+		}
+		return price(byModel), nil
+	case agent.UsageCodexSessions:
+		byModel, err := codexSessions(filepath.Join(stateDir, "sessions"))
+		if err != nil {
+			return Totals{}, err
+		}
+		return price(byModel), nil
+	case agent.UsageAiderLog:
+		return aiderLog(store.AgentLogPath(sandboxDir))
+	default:
+		return Totals{}, fmt.Errorf("no readable token usage (usage source %q)", src)
+	}
+}
+
+// price sums per-model usage and estimates its cost from list prices.
+func price(byModel map[string]tokens) Totals {
+	var t Totals
+	for model, u := range byModel {
+		t.Input += u.input
+		t.Output += u.output
+		t.CacheWrite += u.cacheWrite
+		t.CacheRead += u.cacheRead
+		t.Models = append(t.Models, model)
+		p, ok := lookupPrice(model)
+		if !ok {
+			t.Unpriced = append(t.Unpriced, model)
+			continue
+		}
+		t.CostUSD += p.cost(u)
+		t.Estimated = true
+	}
+	sort.Strings(t.Models)
+	sort.Strings(t.Unpriced)
+	return t
+}
+
+// walkFiles calls fn for each regular file under root with the given
+// extension. A missing root is no files. Symlinks are not followed: the agent
+// writes these directories.
+func walkFiles(root, ext string, fn func(path string) error) error {
+	err := filepath.WalkDir(root, func(p string, d os.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		if !d.Type().IsRegular() || filepath.Ext(p) != ext {
+			return nil
+		}
+		return fn(p)
+	})
+	if err != nil {
+		return fmt.Errorf("read agent usage: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,130 @@
+// ABOUTME: Tests for reading agent usage back: Claude messages counted once, Codex
+// ABOUTME: running totals as deltas, aider's terminal lines, and list-price lookup.
+package tokenusage
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kstenerud/yoloai/internal/agent"
+	"github.com/kstenerud/yoloai/store"
+)
+
+func writeLines(t *testing.T, path string, lines ...string) {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o750))
+	require.NoError(t, os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0o600))
+}
+
+func TestMeasure_ClaudeCountsEachMessageOnce(t *testing.T) {
+	dir := t.TempDir()
+	projects := filepath.Join(dir, store.AgentRuntimeDir, "projects", "-home-yoloai-src")
+	msg := func(id, model string, in, out, cw, cr int) string {
+		return `{"type":"assistant","message":{"id":"` + id + `","model":"` + model + `","usage":{"input_tokens":` +
+			strconv.Itoa(in) + `,"output_tokens":` + strconv.Itoa(out) + `,"cache_creation_input_tokens":` + strconv.Itoa(cw) +
+			`,"cache_read_input_tokens":` + strconv.Itoa(cr) + `}}}`
+	}
+	writeLines(t, filepath.Join(projects, "a.jsonl"),
+		`{"type":"user","message":{"role":"user","content":"hi"}}`,
+		// One message, one line per content block; the last has the final count.
+		msg("msg_1", "claude-sonnet-4-5-20250929", 100, 1, 1000, 0),
+		msg("msg_1", "claude-sonnet-4-5-20250929", 100, 50, 1000, 0),
+		msg("msg_2", "claude-sonnet-4-5-20250929", 10, 20, 0, 1000),
+		msg("msg_3", "<synthetic>", 0, 0, 0, 0),
+	)
+	// A resumed session repeats msg_1, and adds one on an unknown model.
+	writeLines(t, filepath.Join(projects, "b.jsonl"),
+		msg("msg_1", "claude-sonnet-4-5-20250929", 100, 50, 1000, 0),
+		msg("msg_4", "claude-next", 1, 1, 0, 0),
+	)
+
+	got, err := Measure(agent.UsageClaudeTranscripts, dir)
+	require.NoError(t, err)
+	assert.Equal(t, int64(111), got.Input)
+	assert.Equal(t, int64(71), got.Output)
+	assert.Equal(t, int64(1000), got.CacheWrite)
+	assert.Equal(t, int64(1000), got.CacheRead)
+	assert.Equal(t, []string{"claude-next", "claude-sonnet-4-5-20250929"}, got.Models)
+	assert.Equal(t, []string{"claude-next"}, got.Unpriced)
+	assert.True(t, got.Estimated)
+	// 110 in × $3 + 70 out × $15 + 1000 cache write × $3.75 + 1000 cache read × $0.30, per million.
+	assert.InDelta(t, 0.00543, got.CostUSD, 1e-9)
+}
+
+func TestMeasure_CodexRunningTotals(t *testing.T) {
+	dir := t.TempDir()
+	count := func(in, cached, out int) string {
+		return `{"type":"event_msg","payload":{"type":"token_count","info":{"total_token_usage":{"input_tokens":` +
+			strconv.Itoa(in) + `,"cached_input_tokens":` + strconv.Itoa(cached) + `,"output_tokens":` + strconv.Itoa(out) + `}}}}`
+	}
+	writeLines(t, filepath.Join(dir, store.AgentRuntimeDir, "sessions", "2026", "10", "01", "rollout-1.jsonl"),
+		`{"type":"turn_context","payload":{"model":"gpt-5-codex"}}`,
+		`{"type":"event_msg","payload":{"type":"token_count","info":null}}`,
+		count(1000, 0, 100),
+		count(1000, 0, 100), // repeated: counts once
+		`{"type":"turn_context","payload":{"model":"gpt-5-mini"}}`,
+		count(3000, 1500, 300),
+	)
+
+	got, err := Measure(agent.UsageCodexSessions, dir)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1500), got.Input)
+	assert.Equal(t, int64(1500), got.CacheRead)
+	assert.Equal(t, int64(300), got.Output)
+	assert.Equal(t, []string{"gpt-5-codex", "gpt-5-mini"}, got.Models)
+	assert.Empty(t, got.Unpriced)
+	// gpt-5: 1000 × $1.25 + 100 × $10; gpt-5-mini: 500 × $0.25 + 1500 × $0.025 + 200 × $2.
+	assert.InDelta(t, (1250+1000+125+37.5+400)/1e6, got.CostUSD, 1e-12)
+}
+
+func TestMeasure_AiderLog(t *testing.T) {
+	dir := t.TempDir()
+	writeLines(t, store.AgentLogPath(dir),
+		"\x1b[32mTokens: 2.5k sent, 1.1k cache hit, 119 received. Cost: $0.02 message, $0.02 session.\x1b[0m",
+		"some reply text mentioning Tokens: nothing",
+		"Tokens: 12k sent, 1.2k received. Cost: $0.05 message, $0.07 session.",
+	)
+
+	got, err := Measure(agent.UsageAiderLog, dir)
+	require.NoError(t, err)
+	assert.Equal(t, int64(14500), got.Input)
+	assert.Equal(t, int64(1100), got.CacheRead)
+	assert.Equal(t, int64(1319), got.Output)
+	assert.InDelta(t, 0.07, got.CostUSD, 1e-9)
+	assert.False(t, got.Estimated, "aider reports its own cost")
+}
+
+func TestMeasure_NothingRecordedYet(t *testing.T) {
+	for _, src := range []agent.UsageSource{agent.UsageClaudeTranscripts, agent.UsageCodexSessions, agent.UsageAiderLog} {
+		got, err := Measure(src, t.TempDir())
+		require.NoError(t, err, src)
+		assert.Zero(t, got.Total(), src)
+	}
+	_, err := Measure("", t.TempDir())
+	assert.Error(t, err)
+}
+
+func TestLookupPrice(t *testing.T) {
+	for model, want := range map[string]string{
+		"claude-opus-4-5-20251101": "claude-opus-4-5",
+		"claude-opus-4-20250514":   "claude-opus-4",
+		"claude-opus-4-1-20250805": "claude-opus-4",
+		"gpt-5-codex":              "gpt-5",
+		"gpt-5.1-codex":            "gpt-5.1",
+		"gpt-5":                    "gpt-5",
+	} {
+		got, ok := lookupPrice(model)
+		assert.True(t, ok, model)
+		assert.Equal(t, prices[want], got, model)
+	}
+	for _, model := range []string{"gpt-5.3-codex", "claude-opus", "gpt-50"} {
+		_, ok := lookupPrice(model)
+		assert.False(t, ok, model)
+	}
+}
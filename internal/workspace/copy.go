@@ -42,13 +42,15 @@ func CopyDir(src, dst string) error {
 	}
 
 	// Regular file-by-file copy.
-	return copyDirWalk(src, dst, srcInfo)
+	return copyDirWalk(src, dst, srcInfo, false, nil)
 }
 
 // copyDirWalk copies a directory tree by walking the source and recreating
 // each entry in the destination, preserving symlinks, permissions, and
-// modification times.
-func copyDirWalk(src, dst string, srcInfo os.FileInfo) error {
+// modification times. With sync, entries dst already holds are left alone (see
+// syncTarget). A file whose src-relative path skip reports is left out; nil
+// skips nothing.
+func copyDirWalk(src, dst string, srcInfo os.FileInfo, sync bool, skip func(rel string) bool) error {
 	if err := fileutil.MkdirAll(dst, srcInfo.Mode().Perm()); err != nil {
 		return fmt.Errorf("create destination: %w", err)
 	}
@@ -56,19 +58,19 @@ func copyDirWalk(src, dst string, srcInfo os.FileInfo) error {
 		if err != nil {
 			return err
 		}
-		return copyDirEntry(src, dst, path, d)
+		return copyDirEntry(src, dst, path, d, sync, skip)
 	})
 }
 
 // copyDirEntry handles a single entry produced by filepath.WalkDir, skipping
 // unwanted files and recreating the entry (symlink, directory, or file) under dst.
-func copyDirEntry(src, dst, path string, d fs.DirEntry) error {
+func copyDirEntry(src, dst, path string, d fs.DirEntry, sync bool, skip func(rel string) bool) error {
 	rel, err := filepath.Rel(src, path)
 	if err != nil {
 		return fmt.Errorf("rel path: %w", err)
 	}
 
-	if !d.IsDir() && isBugreportFile(d.Name()) {
+	if !d.IsDir() && (isBugreportFile(d.Name()) || (skip != nil && skip(rel))) {
 		return nil
 	}
 	if isBuildArtifact(rel, d.IsDir()) {
@@ -79,6 +81,15 @@ func copyDirEntry(src, dst, path string, d fs.DirEntry) error {
 	}
 
 	target := filepath.Join(dst, rel)
+	if sync && rel != "." {
+		info, err := d.Info()
+		if err != nil {
+			return fmt.Errorf("file info %s: %w", path, err)
+		}
+		if upToDate, err := syncTarget(path, target, info); err != nil || upToDate {
+			return err
+		}
+	}
 
 	if d.Type()&fs.ModeSymlink != 0 {
 		return copySymlink(path, target)
@@ -120,7 +131,15 @@ func copyEntryFile(path, target string, d fs.DirEntry) error {
 }
 
 // copyFile copies a regular file preserving permissions and modification time.
+// It tries a copy-on-write clone first (clonefile on APFS, FICLONE on btrfs and
+// XFS), which shares the source's blocks instead of copying them, and falls
+// back to a byte copy when the filesystem can't clone or src and dst are on
+// different filesystems.
 func copyFile(src, dst string, srcInfo fs.FileInfo) error {
+	if err := cloneFile(src, dst, srcInfo.Mode().Perm()); err == nil {
+		return os.Chtimes(dst, srcInfo.ModTime(), srcInfo.ModTime())
+	}
+
 	in, err := os.Open(src) //nolint:gosec // G304: paths come from WalkDir of validated sandbox paths
 	if err != nil {
 		return fmt.Errorf("open %s: %w", src, err)
@@ -133,9 +152,8 @@ func copyFile(src, dst string, srcInfo fs.FileInfo) error {
 	}
 
 	// io.Copy between two bare *os.File values engages os.(*File).ReadFrom,
-	// which on Linux uses copy_file_range(2): a reflink (CoW, no data copied)
-	// on filesystems that support it (btrfs, XFS) when src and dst are on the
-	// same filesystem. Wrapping either file in a buffered reader/writer would
+	// which on Linux uses copy_file_range(2): an in-kernel copy with no trip
+	// through userspace. Wrapping either file in a buffered reader/writer would
 	// silently downgrade every copy to a userspace read/write loop.
 	if _, err := io.Copy(out, in); err != nil {
 		out.Close() //nolint:errcheck,gosec // best-effort cleanup on copy failure
@@ -1,10 +1,15 @@
 //go:build darwin
 
-// ABOUTME: macOS APFS clonefile(2) fast-path for CopyDir; falls through to the
-// ABOUTME: walk-based copy in copy.go when the filesystem doesn't support it.
+// ABOUTME: macOS APFS clonefile(2) fast-paths for CopyDir and copyFile; both fall
+// ABOUTME: through to the byte copy in copy.go when the filesystem doesn't support it.
 package workspace
 
-import "golang.org/x/sys/unix"
+import (
+	"io/fs"
+
+	"github.com/kstenerud/yoloai/internal/fileutil"
+	"golang.org/x/sys/unix"
+)
 
 // cloneDir uses macOS clonefile(2) to create a copy-on-write clone of the
 // entire directory tree. This is near-instant on APFS. Any error (unsupported
@@ -13,3 +18,14 @@ import "golang.org/x/sys/unix"
 func cloneDir(src, dst string) error {
 	return unix.Clonefile(src, dst, 0)
 }
+
+// cloneFile clones a single file with clonefile(2). The .gitignore-honoring
+// copy goes file by file, so this is what makes the default :copy fast on
+// APFS. dst must not exist; the mode comes from src, the owner from the caller
+// (CLONE_NOOWNERCOPY), so a sudo run still hands the file to the real user.
+func cloneFile(src, dst string, _ fs.FileMode) error {
+	if err := unix.Clonefile(src, dst, unix.CLONE_NOFOLLOW|unix.CLONE_NOOWNERCOPY); err != nil {
+		return err
+	}
+	return fileutil.ChownIfSudo(dst)
+}
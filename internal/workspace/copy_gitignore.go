@@ -36,34 +36,41 @@ import (
 // Only the :copy-all branch can produce one, since the gitignore-honoring branch
 // never copies .git at all. The sever is unconditional anyway, so the invariant
 // holds for the function rather than for one branch of it (DF116).
-func CopyProjectDir(src, dst string, includeIgnored, preserveGit bool, listProjectFiles func() (files []string, isRepo bool, err error)) error {
-	if err := copyProjectContent(src, dst, includeIgnored, preserveGit, listProjectFiles); err != nil {
+//
+// objects, when not "", is an object store the copied .git is to share its
+// packs with instead of holding them (see shareGitObjects): the packs it
+// already has are not copied, and when it doesn't exist yet it is made from
+// this copy's. "" copies the packs like everything else.
+func CopyProjectDir(src, dst string, includeIgnored, preserveGit bool, objects string, listProjectFiles func() (files []string, isRepo bool, err error)) error {
+	if err := copyProjectContent(src, dst, includeIgnored, preserveGit, false, objects, listProjectFiles); err != nil {
 		return err
 	}
 	if _, err := RemoveGitLink(dst); err != nil {
 		return err
 	}
-	return nil
+	return shareGitObjects(dst, objects)
 }
 
 // copyProjectContent performs CopyProjectDir's mode dispatch, leaving the
-// work copy's .git invariant to its caller.
-func copyProjectContent(src, dst string, includeIgnored, preserveGit bool, listProjectFiles func() (files []string, isRepo bool, err error)) error {
+// work copy's .git invariant to its caller. With sync, dst may hold an earlier
+// copy, and only what changed is written (see SyncProjectDir). The packs
+// objects holds are left out of the copied .git.
+func copyProjectContent(src, dst string, includeIgnored, preserveGit, sync bool, objects string, listProjectFiles func() (files []string, isRepo bool, err error)) error {
 	if includeIgnored {
-		return CopyDir(src, dst)
+		return copyTree(src, dst, sync, sharedPackFilter(objects, filepath.Join(".git", "objects", "pack")))
 	}
 	files, isRepo, err := listProjectFiles()
 	if err != nil {
 		return err
 	}
 	if !isRepo {
-		return CopyDir(src, dst)
+		return copyTree(src, dst, sync, nil)
 	}
-	if err := copyFileList(src, dst, files); err != nil {
+	if err := copyFileList(src, dst, files, sync); err != nil {
 		return err
 	}
 	if preserveGit {
-		return copyGitDir(src, dst)
+		return copyGitDir(src, dst, sync, objects)
 	}
 	return nil
 }
@@ -72,8 +79,9 @@ func copyProjectContent(src, dst string, includeIgnored, preserveGit bool, listP
 // repo's history (log/blame/bisect) and filter config. Only a real .git
 // *directory* is copied; a gitlink file (linked worktree / submodule) is
 // skipped — its objects live in a shared common dir outside src, out of scope
-// (see copy-mode-history.md). A missing .git is a no-op.
-func copyGitDir(src, dst string) error {
+// (see copy-mode-history.md). A missing .git is a no-op. The packs objects
+// holds are left out.
+func copyGitDir(src, dst string, sync bool, objects string) error {
 	gitPath := filepath.Join(src, ".git")
 	info, err := os.Lstat(gitPath)
 	if err != nil {
@@ -85,7 +93,7 @@ func copyGitDir(src, dst string) error {
 	if !info.IsDir() {
 		return nil // gitlink file (worktree/submodule) — history lives elsewhere
 	}
-	if err := CopyDir(gitPath, filepath.Join(dst, ".git")); err != nil {
+	if err := copyTree(gitPath, filepath.Join(dst, ".git"), sync, sharedPackFilter(objects, filepath.Join("objects", "pack"))); err != nil {
 		return fmt.Errorf("copy .git: %w", err)
 	}
 	return nil
@@ -96,7 +104,8 @@ func copyGitDir(src, dst string) error {
 // times, and symlinks. Paths that no longer exist on disk (tracked-but-deleted),
 // submodule gitlink directories, build artifacts, and bugreport files are
 // skipped — so the result matches CopyDir's exclusions plus the gitignore set.
-func copyFileList(src, dst string, files []string) error {
+// With sync, files dst already holds are left alone (see syncTarget).
+func copyFileList(src, dst string, files []string, sync bool) error {
 	if err := fileutil.MkdirAll(dst, 0o750); err != nil {
 		return fmt.Errorf("create destination: %w", err)
 	}
@@ -113,6 +122,15 @@ func copyFileList(src, dst string, files []string) error {
 			return fmt.Errorf("stat %s: %w", srcPath, err)
 		}
 		target := filepath.Join(dst, rel)
+		if sync {
+			upToDate, err := syncTarget(srcPath, target, info)
+			if err != nil {
+				return err
+			}
+			if upToDate {
+				continue
+			}
+		}
 		if err := fileutil.MkdirAll(filepath.Dir(target), 0o750); err != nil {
 			return fmt.Errorf("create parent of %s: %w", rel, err)
 		}
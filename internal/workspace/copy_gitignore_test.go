@@ -37,7 +37,7 @@ func TestCopyProjectDir_HonorsListedFilesOnly(t *testing.T) {
 	write(t, filepath.Join(src, "secret.env"), "TOKEN") // present on disk but NOT in the list
 
 	dst := filepath.Join(t.TempDir(), "out")
-	require.NoError(t, CopyProjectDir(src, dst, false, false, listFn("a.txt", "sub/b.txt")))
+	require.NoError(t, CopyProjectDir(src, dst, false, false, "", listFn("a.txt", "sub/b.txt")))
 
 	assert.True(t, exists(filepath.Join(dst, "a.txt")))
 	assert.True(t, exists(filepath.Join(dst, "sub", "b.txt")), "nested listed file copied")
@@ -55,7 +55,7 @@ func TestCopyProjectDir_IncludeIgnoredCopiesEverything(t *testing.T) {
 		return nil, false, nil
 	}
 	dst := filepath.Join(t.TempDir(), "out")
-	require.NoError(t, CopyProjectDir(src, dst, true, false, enum))
+	require.NoError(t, CopyProjectDir(src, dst, true, false, "", enum))
 
 	assert.True(t, exists(filepath.Join(dst, "data.txt")))
 	assert.True(t, exists(filepath.Join(dst, "secret.env")), ":copy-all includes gitignored files")
@@ -68,7 +68,7 @@ func TestCopyProjectDir_NonRepoFallsBackToFullCopy(t *testing.T) {
 
 	notRepo := func() ([]string, bool, error) { return nil, false, nil }
 	dst := filepath.Join(t.TempDir(), "out")
-	require.NoError(t, CopyProjectDir(src, dst, false, false, notRepo))
+	require.NoError(t, CopyProjectDir(src, dst, false, false, "", notRepo))
 
 	assert.True(t, exists(filepath.Join(dst, "config.env")), "non-repo copies everything")
 	assert.True(t, exists(filepath.Join(dst, "data.txt")))
@@ -81,7 +81,7 @@ func TestCopyProjectDir_SkipsDeletedAndPreservesSymlink(t *testing.T) {
 
 	// "gone.txt" is listed (tracked) but not on disk (deleted) — must be skipped.
 	dst := filepath.Join(t.TempDir(), "out")
-	require.NoError(t, CopyProjectDir(src, dst, false, false, listFn("real.txt", "link", "gone.txt")))
+	require.NoError(t, CopyProjectDir(src, dst, false, false, "", listFn("real.txt", "link", "gone.txt")))
 
 	assert.True(t, exists(filepath.Join(dst, "real.txt")))
 	assert.False(t, exists(filepath.Join(dst, "gone.txt")), "deleted-but-listed file skipped")
@@ -94,7 +94,7 @@ func TestCopyProjectDir_EnumeratorErrorPropagates(t *testing.T) {
 	src := t.TempDir()
 	write(t, filepath.Join(src, "a.txt"), "a")
 	boom := func() ([]string, bool, error) { return nil, false, errors.New("git exploded") }
-	err := CopyProjectDir(src, filepath.Join(t.TempDir(), "out"), false, false, boom)
+	err := CopyProjectDir(src, filepath.Join(t.TempDir(), "out"), false, false, "", boom)
 	require.Error(t, err, "a genuine enumeration error must not silently full-copy")
 }
 
@@ -127,7 +127,7 @@ func TestCopyProjectDir_PreserveGitKeepsHistory(t *testing.T) {
 	makeRepo(t, src)
 
 	dst := filepath.Join(t.TempDir(), "out")
-	require.NoError(t, CopyProjectDir(src, dst, false, true, listFn("a.txt", ".gitignore")))
+	require.NoError(t, CopyProjectDir(src, dst, false, true, "", listFn("a.txt", ".gitignore")))
 
 	assert.True(t, exists(filepath.Join(dst, ".git")), "preserveGit clones the source .git")
 	assert.True(t, exists(filepath.Join(dst, "a.txt")))
@@ -144,7 +144,7 @@ func TestCopyProjectDir_StripHistoryDropsGitDir(t *testing.T) {
 	makeRepo(t, src)
 
 	dst := filepath.Join(t.TempDir(), "out")
-	require.NoError(t, CopyProjectDir(src, dst, false, false, listFn("a.txt", ".gitignore")))
+	require.NoError(t, CopyProjectDir(src, dst, false, false, "", listFn("a.txt", ".gitignore")))
 
 	assert.False(t, exists(filepath.Join(dst, ".git")), "preserveGit=false leaves no .git (fresh-baseline path)")
 	assert.True(t, exists(filepath.Join(dst, "a.txt")))
@@ -0,0 +1,15 @@
+//go:build !darwin && !linux
+
+// ABOUTME: Stub cloneFile for platforms without a copy-on-write file clone,
+// ABOUTME: causing copyFile to fall through to its byte copy.
+package workspace
+
+import (
+	"errors"
+	"io/fs"
+)
+
+// cloneFile always fails here, so copyFile does a regular byte copy.
+func cloneFile(_, _ string, _ fs.FileMode) error {
+	return errors.New("file clone not supported")
+}
@@ -174,6 +174,23 @@ func TestCopyDir_CloneIsolation(t *testing.T) {
 	assert.Equal(t, "nested-original", string(content))
 }
 
+func TestCopyFile_OverwritesExisting(t *testing.T) {
+	src := t.TempDir()
+	writeTestFile(t, src, "f.txt", "new")
+	dst := t.TempDir()
+	writeTestFile(t, dst, "f.txt", "stale and longer")
+
+	info, err := os.Stat(filepath.Join(src, "f.txt"))
+	require.NoError(t, err)
+	// clonefile refuses an existing destination, so on macOS this exercises the
+	// byte-copy fallback; either path must leave an exact copy.
+	require.NoError(t, copyFile(filepath.Join(src, "f.txt"), filepath.Join(dst, "f.txt"), info))
+
+	content, err := os.ReadFile(filepath.Join(dst, "f.txt")) //nolint:gosec
+	require.NoError(t, err)
+	assert.Equal(t, "new", string(content))
+}
+
 func TestCopyDir_SkipsBugreportFiles(t *testing.T) {
 	src := t.TempDir()
 	writeTestFile(t, src, "file.txt", "hello")
@@ -94,7 +94,7 @@ func TestCopyProjectDir_CopyAllFromWorktree_SeversGitLink(t *testing.T) {
 	write(t, filepath.Join(wt, "ignored.log"), "SECRET")
 
 	dst := filepath.Join(t.TempDir(), "out")
-	require.NoError(t, CopyProjectDir(wt, dst, true, true, listFn()))
+	require.NoError(t, CopyProjectDir(wt, dst, true, true, "", listFn()))
 
 	assert.False(t, exists(filepath.Join(dst, ".git")), "work copy must not point back at the source repo")
 	assert.True(t, exists(filepath.Join(dst, "a.txt")), ":copy-all still copies the files")
@@ -105,7 +105,7 @@ func TestCopyProjectDir_CopyFromWorktree_HasNoGitLink(t *testing.T) {
 	_, wt := makeWorktree(t, t.TempDir())
 
 	dst := filepath.Join(t.TempDir(), "out")
-	require.NoError(t, CopyProjectDir(wt, dst, false, true, listFn("a.txt")))
+	require.NoError(t, CopyProjectDir(wt, dst, false, true, "", listFn("a.txt")))
 
 	assert.False(t, exists(filepath.Join(dst, ".git")))
 	assert.True(t, exists(filepath.Join(dst, "a.txt")))
@@ -117,7 +117,7 @@ func TestCopyProjectDir_NormalRepoKeepsGitDir(t *testing.T) {
 	makeRepo(t, src)
 
 	dst := filepath.Join(t.TempDir(), "out")
-	require.NoError(t, CopyProjectDir(src, dst, false, true, listFn("a.txt", ".gitignore")))
+	require.NoError(t, CopyProjectDir(src, dst, false, true, "", listFn("a.txt", ".gitignore")))
 
 	info, err := os.Lstat(filepath.Join(dst, ".git"))
 	require.NoError(t, err, "a real repo's history must survive the copy")
@@ -130,7 +130,7 @@ func TestCopyProjectDir_CopyAllFromNormalRepo_KeepsGitDir(t *testing.T) {
 	makeRepo(t, src)
 
 	dst := filepath.Join(t.TempDir(), "out")
-	require.NoError(t, CopyProjectDir(src, dst, true, true, listFn()))
+	require.NoError(t, CopyProjectDir(src, dst, true, true, "", listFn()))
 
 	info, err := os.Lstat(filepath.Join(dst, ".git"))
 	require.NoError(t, err)
@@ -155,7 +155,7 @@ func TestCopyProjectDir_CopyAllFromSubmodule_SeversGitLink(t *testing.T) {
 	require.True(t, IsGitLink(subdir), "a submodule keeps its git dir in the superproject")
 
 	dst := filepath.Join(t.TempDir(), "out")
-	require.NoError(t, CopyProjectDir(subdir, dst, true, true, listFn()))
+	require.NoError(t, CopyProjectDir(subdir, dst, true, true, "", listFn()))
 
 	assert.False(t, exists(filepath.Join(dst, ".git")))
 	assert.True(t, exists(filepath.Join(dst, "a.txt")))
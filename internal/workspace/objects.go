@@ -0,0 +1,284 @@
+// ABOUTME: Git object stores shared by work copies of one source: a copy's packs
+// ABOUTME: move once into a store named for the pack set, and each copy names the
+// ABOUTME: store in its alternates file instead of holding the packs itself.
+package workspace
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/kstenerud/yoloai/internal/fileutil"
+)
+
+// packFileSuffixes are the files that make up one pack: the pack itself and the
+// indexes git derives from it. They are written once and never changed, which
+// is what makes them safe to share. A .keep or .promisor marker stays with the
+// copy.
+var packFileSuffixes = []string{".pack", ".idx", ".rev", ".bitmap", ".mtimes"}
+
+// multiPackIndexPrefix names the multi-pack-index files, which list the packs
+// beside them. A sharing copy leaves them out: its packs are in the store, and
+// git reads each pack's own .idx without one.
+const multiPackIndexPrefix = "multi-pack-index"
+
+// isPackFile reports whether name is one of the files of a pack.
+func isPackFile(name string) bool {
+	if !strings.HasPrefix(name, "pack-") {
+		return false
+	}
+	return slices.Contains(packFileSuffixes, filepath.Ext(name))
+}
+
+// PackSetKey names the set of packs in the object store of the git dir at
+// gitDir: a hash of the pack names, each of which already names its pack's
+// content. Two copies with the same key hold the same packs. It returns "" when
+// there are no packs (or no object store) to share.
+func PackSetKey(gitDir string) (string, error) {
+	packDir := filepath.Join(gitDir, "objects", "pack")
+	entries, err := os.ReadDir(packDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", nil
+		}
+		return "", fmt.Errorf("read %s: %w", packDir, err)
+	}
+	var packs []string
+	for _, e := range entries {
+		name, ok := strings.CutSuffix(e.Name(), ".idx")
+		if !ok || !isPackFile(e.Name()) {
+			continue
+		}
+		// An index without its pack is one git is still writing, or one it
+		// failed to; neither is a pack to share.
+		if _, err := os.Lstat(filepath.Join(packDir, name+".pack")); err == nil {
+			packs = append(packs, name)
+		}
+	}
+	if len(packs) == 0 {
+		return "", nil
+	}
+	slices.Sort(packs)
+	sum := sha256.Sum256([]byte(strings.Join(packs, "\n")))
+	return hex.EncodeToString(sum[:16]), nil
+}
+
+// SharedObjectStores returns the object stores under root that the work copy at
+// dir names in its alternates file. An alternates file belongs to whoever
+// controls the .git, the agent included, so an entry is only trusted when it is
+// an existing directory directly under root: anything else is left for git to
+// resolve or not, and never mounted or reused.
+func SharedObjectStores(dir, root string) []string {
+	root = filepath.Clean(root)
+	var stores []string
+	for _, path := range Alternates(dir) {
+		if filepath.Dir(path) != root {
+			continue
+		}
+		if info, err := os.Lstat(path); err == nil && info.IsDir() {
+			stores = append(stores, path)
+		}
+	}
+	return stores
+}
+
+// Alternates returns the absolute object directories the work copy at dir names
+// in its alternates file, cleaned and deduplicated, whether or not they exist.
+// nil when the copy has no alternates file.
+func Alternates(dir string) []string {
+	data, err := os.ReadFile(alternatesPath(dir)) //nolint:gosec // G304: a work copy's alternates file, only read
+	if err != nil {
+		return nil
+	}
+	var paths []string
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || !filepath.IsAbs(line) {
+			continue
+		}
+		if path := filepath.Clean(line); !slices.Contains(paths, path) {
+			paths = append(paths, path)
+		}
+	}
+	return paths
+}
+
+// alternatesPath is where git looks for the object stores a repo borrows from.
+func alternatesPath(dir string) string {
+	return filepath.Join(dir, ".git", "objects", "info", "alternates")
+}
+
+// sharedPackFilter returns a copy filter that leaves out what a copy sharing
+// store should not hold itself: the pack files store has, and the
+// multi-pack-index, both under relPackDir (the pack directory relative to the
+// copy's root). nil when store is "", so nothing is left out.
+func sharedPackFilter(store, relPackDir string) func(rel string) bool {
+	if store == "" {
+		return nil
+	}
+	shared := map[string]bool{}
+	if entries, err := os.ReadDir(filepath.Join(store, "pack")); err == nil {
+		for _, e := range entries {
+			shared[e.Name()] = true
+		}
+	}
+	return func(rel string) bool {
+		if filepath.Dir(rel) != relPackDir {
+			return false
+		}
+		name := filepath.Base(rel)
+		return shared[name] || strings.HasPrefix(name, multiPackIndexPrefix)
+	}
+}
+
+// shareGitObjects makes the work copy at dir borrow its packs from store. When
+// store doesn't exist yet it is made from dir's own packs, moved rather than
+// copied, but only if they are the set store is named for (see PackSetKey): a
+// source that changed mid-copy just isn't shared this time. Packs dir still
+// holds that store has are removed, and store is added to dir's alternates.
+// A copy that has no .git, or whose packs can't be moved (the work copy is on
+// another filesystem), keeps them and is left as it is.
+//
+// A store is never written after it is made, and a sandbox only ever sees it
+// through a read-only mount, so no agent can change what another one reads.
+func shareGitObjects(dir, store string) error {
+	if store == "" || !HasGitDir(dir) {
+		return nil
+	}
+	packDir := filepath.Join(dir, ".git", "objects", "pack")
+	if _, err := os.Lstat(store); os.IsNotExist(err) {
+		made, err := makeObjectStore(dir, packDir, store)
+		if err != nil || !made {
+			return err
+		}
+	}
+	entries, err := os.ReadDir(packDir)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("read %s: %w", packDir, err)
+	}
+	for _, e := range entries {
+		switch {
+		case strings.HasPrefix(e.Name(), multiPackIndexPrefix):
+		case isPackFile(e.Name()):
+			if _, err := os.Lstat(filepath.Join(store, "pack", e.Name())); err != nil {
+				continue // a pack the store doesn't have stays with the copy
+			}
+		default:
+			continue
+		}
+		if err := os.Remove(filepath.Join(packDir, e.Name())); err != nil {
+			return fmt.Errorf("remove shared pack file: %w", err)
+		}
+	}
+	if err := addAlternate(dir, store); err != nil {
+		return err
+	}
+	// A store's mtime is when a copy last took it up, so a prune can tell one
+	// that is about to be named from one nothing names any more.
+	now := time.Now()
+	if err := os.Chtimes(store, now, now); err != nil {
+		return fmt.Errorf("touch object store: %w", err)
+	}
+	return nil
+}
+
+// makeObjectStore moves the pack files in packDir into a new store at store,
+// reporting whether it did. It builds the store beside its final path and
+// renames it into place, so a store that exists is complete; losing that
+// rename to another copy of the same packs is as good as winning it.
+func makeObjectStore(dir, packDir, store string) (bool, error) {
+	key, err := PackSetKey(filepath.Join(dir, ".git"))
+	if err != nil || key == "" || key != filepath.Base(store) {
+		return false, err
+	}
+	info, err := os.Stat(packDir)
+	if err != nil {
+		return false, fmt.Errorf("stat %s: %w", packDir, err)
+	}
+	if err := fileutil.MkdirAll(filepath.Dir(store), 0o750); err != nil {
+		return false, fmt.Errorf("create object store dir: %w", err)
+	}
+	tmp, err := os.MkdirTemp(filepath.Dir(store), ".new-")
+	if err != nil {
+		return false, fmt.Errorf("create object store: %w", err)
+	}
+	defer os.RemoveAll(tmp) //nolint:errcheck // best-effort; empty once renamed into place
+	if err := fileutil.ChownIfSudo(tmp); err != nil {
+		return false, fmt.Errorf("create object store: %w", err)
+	}
+	// The store is read through the same mounts as the work copy, so it gets
+	// the work copy's permissions rather than MkdirTemp's owner-only ones.
+	if err := os.Chmod(tmp, info.Mode().Perm()); err != nil { //nolint:gosec // G302: the work copy's own permissions
+		return false, fmt.Errorf("create object store: %w", err)
+	}
+	tmpPack := filepath.Join(tmp, "pack")
+	if err := fileutil.MkdirAll(tmpPack, info.Mode().Perm()); err != nil {
+		return false, fmt.Errorf("create object store: %w", err)
+	}
+
+	entries, err := os.ReadDir(packDir)
+	if err != nil {
+		return false, fmt.Errorf("read %s: %w", packDir, err)
+	}
+	var moved []string
+	putBack := func() {
+		for _, name := range moved {
+			_ = os.Rename(filepath.Join(tmpPack, name), filepath.Join(packDir, name))
+		}
+	}
+	for _, e := range entries {
+		if !isPackFile(e.Name()) {
+			continue
+		}
+		if err := os.Rename(filepath.Join(packDir, e.Name()), filepath.Join(tmpPack, e.Name())); err != nil {
+			// Most likely a work root on another filesystem, where a store
+			// would save nothing: keep the packs with the copy.
+			putBack()
+			return false, nil
+		}
+		moved = append(moved, e.Name())
+	}
+	if err := os.Rename(tmp, store); err != nil {
+		if _, statErr := os.Lstat(store); statErr == nil {
+			return true, nil // another copy of the same packs got there first
+		}
+		putBack()
+		return false, fmt.Errorf("create object store: %w", err)
+	}
+	return true, nil
+}
+
+// addAlternate adds store to the alternates of the work copy at dir, keeping
+// any entries the copy came with.
+func addAlternate(dir, store string) error {
+	path := alternatesPath(dir)
+	data, err := os.ReadFile(path) //nolint:gosec // G304: the work copy's alternates file
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("read alternates: %w", err)
+	}
+	for line := range strings.SplitSeq(string(data), "\n") {
+		if filepath.Clean(strings.TrimSpace(line)) == store {
+			return nil
+		}
+	}
+	if len(data) > 0 && !bytes.HasSuffix(data, []byte("\n")) {
+		data = append(data, '\n')
+	}
+	data = append(data, store+"\n"...)
+	if err := fileutil.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return fmt.Errorf("create objects/info: %w", err)
+	}
+	if err := fileutil.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("write alternates: %w", err)
+	}
+	return nil
+}
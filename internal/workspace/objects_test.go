@@ -0,0 +1,124 @@
+// ABOUTME: Work copies sharing an object store: the packs live once in the store,
+// ABOUTME: each copy still reads its full history, and a sync keeps the sharing.
+package workspace
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// makePackedRepo is makeRepo with its objects packed, as a repo that has been
+// cloned, fetched into or gc'd has them.
+func makePackedRepo(t *testing.T, dir string) {
+	t.Helper()
+	makeRepo(t, dir)
+	runGit(t, dir, "repack", "-adq")
+}
+
+func packFiles(t *testing.T, dir string) []string {
+	t.Helper()
+	entries, err := os.ReadDir(filepath.Join(dir, ".git", "objects", "pack"))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	require.NoError(t, err)
+	var names []string
+	for _, e := range entries {
+		if isPackFile(e.Name()) {
+			names = append(names, e.Name())
+		}
+	}
+	return names
+}
+
+func TestCopyProjectDir_SharesPacksThroughStore(t *testing.T) {
+	src := t.TempDir()
+	makePackedRepo(t, src)
+	key, err := PackSetKey(filepath.Join(src, ".git"))
+	require.NoError(t, err)
+	require.NotEmpty(t, key)
+	store := filepath.Join(t.TempDir(), "objects", key)
+
+	first := filepath.Join(t.TempDir(), "first")
+	require.NoError(t, CopyProjectDir(src, first, false, true, store, listFn("a.txt", ".gitignore")))
+	assert.Empty(t, packFiles(t, first), "the packs moved into the store")
+	storePacks, err := os.ReadDir(filepath.Join(store, "pack"))
+	require.NoError(t, err)
+	assert.NotEmpty(t, storePacks)
+
+	second := filepath.Join(t.TempDir(), "second")
+	require.NoError(t, CopyProjectDir(src, second, false, true, store, listFn("a.txt", ".gitignore")))
+	assert.Empty(t, packFiles(t, second), "a copy of the same packs borrows them")
+
+	for _, dir := range []string{first, second} {
+		assert.Equal(t, []string{store}, SharedObjectStores(dir, filepath.Dir(store)))
+		assert.Equal(t, "initial commit", runGit(t, dir, "log", "-1", "--format=%s"), "history reads through the store")
+		runGit(t, dir, "fsck", "--no-progress")
+	}
+}
+
+func TestCopyProjectDir_UnsharedWhenStoreIsForOtherPacks(t *testing.T) {
+	src := t.TempDir()
+	makePackedRepo(t, src)
+	store := filepath.Join(t.TempDir(), "objects", "not-this-pack-set")
+
+	dst := filepath.Join(t.TempDir(), "out")
+	require.NoError(t, CopyProjectDir(src, dst, false, true, store, listFn("a.txt", ".gitignore")))
+	assert.NotEmpty(t, packFiles(t, dst), "packs that aren't the store's set stay with the copy")
+	assert.False(t, exists(store))
+	assert.False(t, exists(alternatesPath(dst)))
+}
+
+func TestSyncProjectDir_KeepsSharing(t *testing.T) {
+	src := t.TempDir()
+	makePackedRepo(t, src)
+	key, err := PackSetKey(filepath.Join(src, ".git"))
+	require.NoError(t, err)
+	store := filepath.Join(t.TempDir(), "objects", key)
+	files := listFn("a.txt", ".gitignore")
+
+	dst := filepath.Join(t.TempDir(), "out")
+	require.NoError(t, CopyProjectDir(src, dst, false, true, store, files))
+
+	// The source moves on by a pack the store doesn't have.
+	write(t, filepath.Join(src, "a.txt"), "a2")
+	runGit(t, src, "commit", "-qam", "second commit")
+	runGit(t, src, "repack", "-dq")
+
+	require.NoError(t, SyncProjectDir(src, dst, false, true, store, files))
+	local := packFiles(t, dst)
+	assert.NotEmpty(t, local, "the new pack is copied")
+	for _, name := range local {
+		assert.NotContains(t, packFilesIn(t, store), name, "the store's packs are not")
+	}
+	assert.Equal(t, []string{store}, SharedObjectStores(dst, filepath.Dir(store)))
+	assert.Equal(t, "second commit", runGit(t, dst, "log", "-1", "--format=%s"))
+	runGit(t, dst, "fsck", "--no-progress")
+}
+
+func packFilesIn(t *testing.T, store string) []string {
+	t.Helper()
+	entries, err := os.ReadDir(filepath.Join(store, "pack"))
+	require.NoError(t, err)
+	names := make([]string, 0, len(entries))
+	for _, e := range entries {
+		names = append(names, e.Name())
+	}
+	return names
+}
+
+func TestSharedObjectStores_TrustsOnlyStoresUnderRoot(t *testing.T) {
+	root := t.TempDir()
+	store := filepath.Join(root, "abc")
+	require.NoError(t, os.MkdirAll(store, 0o750))
+	elsewhere := t.TempDir()
+
+	dir := t.TempDir()
+	write(t, alternatesPath(dir), "relative/path\n"+elsewhere+"\n"+store+"\n"+filepath.Join(root, "missing")+"\n"+store+"/\n"+root+"\n")
+	assert.Equal(t, []string{store}, SharedObjectStores(dir, root))
+	assert.Nil(t, SharedObjectStores(t.TempDir(), root), "no alternates, no stores")
+}
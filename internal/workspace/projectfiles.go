@@ -9,6 +9,7 @@ import (
 	"io/fs"
 	"os"
 	"path/filepath"
+	"strings"
 )
 
 // ProjectFileSet returns the src-relative paths that belong in a work copy of
@@ -49,8 +50,20 @@ func ProjectFileSet(src string, includeIgnored bool, listProjectFiles func() (fi
 // agent added, what the source no longer has, and what a laxer sync should never
 // have put there in the first place — a `.gitignore`d secret, say (DF117).
 func PruneToFileSet(dst string, files []string) error {
+	extra, err := ExtraPaths(dst, files)
+	if err != nil {
+		return err
+	}
+	return RemovePaths(dst, extra)
+}
+
+// ExtraPaths returns the dst-relative paths PruneToFileSet would remove: each
+// entry under dst that files does not account for, outermost only (a
+// directory stands for everything in it), .git excepted. A directory's path
+// ends in a slash.
+func ExtraPaths(dst string, files []string) ([]string, error) {
 	keep := pathsWithAncestors(files)
-	var toRemove []string
+	var extra []string
 	err := filepath.WalkDir(dst, func(path string, d fs.DirEntry, err error) error {
 		if err != nil {
 			return err
@@ -71,16 +84,23 @@ func PruneToFileSet(dst string, files []string) error {
 		if keep[rel] {
 			return nil
 		}
-		toRemove = append(toRemove, path)
 		if d.IsDir() {
+			extra = append(extra, filepath.ToSlash(rel)+"/")
 			return filepath.SkipDir // nothing inside a doomed directory needs visiting
 		}
+		extra = append(extra, filepath.ToSlash(rel))
 		return nil
 	})
 	if err != nil {
-		return fmt.Errorf("walk %s: %w", dst, err)
+		return nil, fmt.Errorf("walk %s: %w", dst, err)
 	}
-	for _, path := range toRemove {
+	return extra, nil
+}
+
+// RemovePaths removes each dst-relative path in rels, as ExtraPaths names them.
+func RemovePaths(dst string, rels []string) error {
+	for _, rel := range rels {
+		path := filepath.Join(dst, filepath.FromSlash(strings.TrimSuffix(rel, "/")))
 		if err := os.RemoveAll(path); err != nil {
 			return fmt.Errorf("prune %s: %w", path, err)
 		}
@@ -82,7 +82,7 @@ func TestProjectFileSet_MatchesCopyProjectDir(t *testing.T) {
 			}
 
 			dst := filepath.Join(t.TempDir(), "out")
-			require.NoError(t, CopyProjectDir(src, dst, tc.includeIgnored, true, listFn))
+			require.NoError(t, CopyProjectDir(src, dst, tc.includeIgnored, true, "", listFn))
 
 			want, err := ProjectFileSet(src, tc.includeIgnored, listFn)
 			require.NoError(t, err)
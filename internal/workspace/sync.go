@@ -0,0 +1,174 @@
+// ABOUTME: SyncProjectDir — CopyProjectDir over an earlier copy that rewrites only
+// ABOUTME: what changed (rsync's size+mtime check) and mirrors .git, for fast resets.
+
+package workspace
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/kstenerud/yoloai/internal/fileutil"
+)
+
+// SyncProjectDir brings dst, which may hold an earlier copy of src, in line
+// with src the way CopyProjectDir would copy it, rewriting only what differs.
+// A file dst already holds with the same size, modification time and
+// permissions is left alone — rsync's quick check. Every copy preserves the
+// source's mtime, so an untouched file passes and anything the agent edited
+// fails. What does need writing goes through copyFile, so it's still a
+// copy-on-write clone where the filesystem can do one.
+//
+// The source's .git, when it is copied, is mirrored rather than replaced, so a
+// large object store stays where it is. Inside .git the quick check applies only
+// under objects/, where a file's name is its content's hash: a same-named object
+// is the same object whichever repo it came from. Refs, the index and the rest
+// are always rewritten — a ref is the same size in every repo, and skipping one
+// on a coincident mtime is what left DF118's ref naming a deleted object. Then
+// anything under dst/.git that src/.git doesn't have is removed, so the result
+// is exactly the source's .git, never a union with the old one.
+//
+// Deliberately never a hardlink: the work copy is the agent's to write, and a
+// write through a link would land in the user's file.
+//
+// Sharing objects with a store works as in CopyProjectDir, and the mirror
+// keeps it: the packs the store holds are the source's whether or not they sit
+// in dst.
+//
+// Removing project files src doesn't have is left to PruneToFileSet, as after
+// CopyProjectDir. The .git link invariant is the same as CopyProjectDir's.
+func SyncProjectDir(src, dst string, includeIgnored, preserveGit bool, objects string, listProjectFiles func() (files []string, isRepo bool, err error)) error {
+	if err := copyProjectContent(src, dst, includeIgnored, preserveGit, true, objects, listProjectFiles); err != nil {
+		return err
+	}
+	if _, err := RemoveGitLink(dst); err != nil {
+		return err
+	}
+	if !WantsGitDir(includeIgnored, preserveGit) || !HasGitDir(src) || !HasGitDir(dst) {
+		return nil
+	}
+	if err := pruneToSource(filepath.Join(src, ".git"), filepath.Join(dst, ".git")); err != nil {
+		return fmt.Errorf("mirror .git: %w", err)
+	}
+	return shareGitObjects(dst, objects)
+}
+
+// HasGitDir reports whether src has a real .git directory — the only kind
+// CopyProjectDir and SyncProjectDir copy (a gitlink is severed).
+func HasGitDir(src string) bool {
+	info, err := os.Lstat(filepath.Join(src, ".git"))
+	return err == nil && info.IsDir()
+}
+
+// copyTree copies the tree at src to dst: CopyDir, or with sync the same walk
+// over an earlier copy, skipping what is already there. The whole-tree clone
+// CopyDir tries first needs a missing dst, so sync never attempts it, and
+// neither does a copy that leaves files out (skip; nil leaves out nothing).
+func copyTree(src, dst string, sync bool, skip func(rel string) bool) error {
+	if !sync && skip == nil {
+		return CopyDir(src, dst)
+	}
+	srcInfo, err := os.Lstat(src)
+	if err != nil {
+		return fmt.Errorf("stat source: %w", err)
+	}
+	if !srcInfo.IsDir() {
+		return fmt.Errorf("source is not a directory: %s", src)
+	}
+	if !sync {
+		if err := fileutil.MkdirAll(filepath.Dir(dst), 0o750); err != nil {
+			return fmt.Errorf("create parent: %w", err)
+		}
+	}
+	return copyDirWalk(src, dst, srcInfo, sync, skip)
+}
+
+// syncTarget reports whether target already holds what copying src (described
+// by srcInfo) would write there, so the copy can skip it: for a regular file,
+// the same size, mtime and permissions; for a symlink, the same target. Nothing
+// in .git outside objects/ is ever up to date (see SyncProjectDir). A directory
+// is never "up to date": its contents still need visiting, and creating it again
+// is a no-op. An entry that isn't up to date is removed first when it is in the way:
+// an entry of another kind, or a stale file or symlink — so the copy recreates
+// it with the source's permissions and can clone into the fresh path.
+func syncTarget(src, target string, srcInfo fs.FileInfo) (bool, error) {
+	dstInfo, err := os.Lstat(target)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("stat %s: %w", target, err)
+	}
+	srcType, dstType := srcInfo.Mode().Type(), dstInfo.Mode().Type()
+	switch {
+	case srcType != dstType:
+	case !srcInfo.IsDir() && inGitMetadata(target):
+	case srcInfo.IsDir():
+		return false, nil
+	case srcType&fs.ModeSymlink != 0:
+		srcLink, srcErr := os.Readlink(src)
+		dstLink, dstErr := os.Readlink(target)
+		if srcErr == nil && dstErr == nil && srcLink == dstLink {
+			return true, nil
+		}
+	case srcType.IsRegular():
+		if dstInfo.Size() == srcInfo.Size() && dstInfo.ModTime().Equal(srcInfo.ModTime()) &&
+			dstInfo.Mode().Perm() == srcInfo.Mode().Perm() {
+			return true, nil
+		}
+	}
+	if err := os.RemoveAll(target); err != nil {
+		return false, fmt.Errorf("replace %s: %w", target, err)
+	}
+	return false, nil
+}
+
+// inGitMetadata reports whether path lies inside a .git directory but outside
+// its objects/ — where no quick check may skip a file (see SyncProjectDir). A
+// .git component anywhere in the path counts, which errs toward rewriting.
+func inGitMetadata(path string) bool {
+	parts := strings.Split(filepath.ToSlash(path), "/")
+	for i, part := range parts {
+		if part == ".git" {
+			return i+1 >= len(parts) || parts[i+1] != "objects"
+		}
+	}
+	return false
+}
+
+// pruneToSource removes everything under dst that has no counterpart under src,
+// so a synced tree holds only what the source does.
+func pruneToSource(src, dst string) error {
+	var toRemove []string
+	err := filepath.WalkDir(dst, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, relErr := filepath.Rel(dst, path)
+		if relErr != nil {
+			return fmt.Errorf("rel path: %w", relErr)
+		}
+		if rel == "." {
+			return nil
+		}
+		if _, statErr := os.Lstat(filepath.Join(src, rel)); statErr == nil {
+			return nil
+		}
+		toRemove = append(toRemove, path)
+		if d.IsDir() {
+			return filepath.SkipDir
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("walk %s: %w", dst, err)
+	}
+	for _, path := range toRemove {
+		if err := os.RemoveAll(path); err != nil {
+			return fmt.Errorf("prune %s: %w", path, err)
+		}
+	}
+	return nil
+}
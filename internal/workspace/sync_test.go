@@ -0,0 +1,105 @@
+// ABOUTME: SyncProjectDir rewrites only what changed since the last copy — an
+// ABOUTME: untouched file keeps its inode, an edited one is restored — and mirrors .git.
+package workspace
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSyncProjectDir_RewritesOnlyWhatChanged(t *testing.T) {
+	src := t.TempDir()
+	write(t, filepath.Join(src, "same.txt"), "same")
+	write(t, filepath.Join(src, "edited.txt"), "original")
+	write(t, filepath.Join(src, "upstream.txt"), "v1")
+	require.NoError(t, os.Symlink("same.txt", filepath.Join(src, "link")))
+	write(t, filepath.Join(src, "was-dir"), "a file in the source")
+	files := listFn("same.txt", "edited.txt", "upstream.txt", "link", "was-dir")
+
+	dst := filepath.Join(t.TempDir(), "out")
+	require.NoError(t, CopyProjectDir(src, dst, false, false, "", files))
+	before, err := os.Stat(filepath.Join(dst, "same.txt"))
+	require.NoError(t, err)
+
+	// The agent edits one file without changing its size, repoints the
+	// symlink and puts a directory where a file was; the user edits another.
+	write(t, filepath.Join(dst, "edited.txt"), "ORIGINAL")
+	require.NoError(t, os.Remove(filepath.Join(dst, "link")))
+	require.NoError(t, os.Symlink("edited.txt", filepath.Join(dst, "link")))
+	require.NoError(t, os.Remove(filepath.Join(dst, "was-dir")))
+	write(t, filepath.Join(dst, "was-dir", "inside.txt"), "agent")
+	write(t, filepath.Join(src, "upstream.txt"), "v2")
+	future := time.Now().Add(time.Minute)
+	require.NoError(t, os.Chtimes(filepath.Join(src, "upstream.txt"), future, future))
+
+	require.NoError(t, SyncProjectDir(src, dst, false, false, "", files))
+
+	after, err := os.Stat(filepath.Join(dst, "same.txt"))
+	require.NoError(t, err)
+	assert.True(t, os.SameFile(before, after), "an unchanged file is left as it is")
+	for name, want := range map[string]string{"edited.txt": "original", "upstream.txt": "v2", "was-dir": "a file in the source"} {
+		got, err := os.ReadFile(filepath.Join(dst, name)) //nolint:gosec // G304: test temp dir
+		require.NoError(t, err, name)
+		assert.Equal(t, want, string(got), name)
+	}
+	link, err := os.Readlink(filepath.Join(dst, "link"))
+	require.NoError(t, err)
+	assert.Equal(t, "same.txt", link)
+}
+
+// The source's .git is mirrored: objects the copy already has stay put, and
+// what the source doesn't have — the objects of a baseline commit made in the
+// work copy — is removed, leaving exactly the source's repo.
+func TestSyncProjectDir_MirrorsGitDir(t *testing.T) {
+	src := t.TempDir()
+	makeRepo(t, src)
+	files := listFn("a.txt", ".gitignore")
+
+	dst := filepath.Join(t.TempDir(), "out")
+	require.NoError(t, CopyProjectDir(src, dst, false, true, "", files))
+	commit := runGit(t, src, "rev-parse", "HEAD")
+	object := filepath.Join(".git", "objects", commit[:2], commit[2:])
+	before, err := os.Stat(filepath.Join(dst, object))
+	require.NoError(t, err)
+
+	write(t, filepath.Join(dst, "a.txt"), "the agent's change")
+	runGit(t, dst, "commit", "-qam", "agent commit")
+
+	require.NoError(t, SyncProjectDir(src, dst, false, true, "", files))
+
+	after, err := os.Stat(filepath.Join(dst, object))
+	require.NoError(t, err)
+	assert.True(t, os.SameFile(before, after), "an object the copy already has is left as it is")
+	assert.Equal(t, commit, runGit(t, dst, "rev-parse", "HEAD"))
+	assert.Empty(t, runGit(t, dst, "status", "--porcelain"))
+	assert.Equal(t, runGit(t, src, "count-objects"), runGit(t, dst, "count-objects"),
+		"no object survives that the source doesn't have")
+	assert.NotContains(t, runGit(t, dst, "log", "--oneline", "--all"), "agent commit")
+}
+
+// A ref is the same size in every repo, so outside objects/ the quick check
+// never applies: a ref the agent moved is rewritten even when its size and
+// mtime happen to match the source's (DF118).
+func TestSyncProjectDir_AlwaysRewritesGitRefs(t *testing.T) {
+	src := t.TempDir()
+	makeRepo(t, src)
+	files := listFn("a.txt", ".gitignore")
+	dst := filepath.Join(t.TempDir(), "out")
+	require.NoError(t, CopyProjectDir(src, dst, false, true, "", files))
+
+	write(t, filepath.Join(dst, "a.txt"), "the agent's change")
+	runGit(t, dst, "commit", "-qam", "agent commit")
+	ref := filepath.Join(".git", "refs", "heads", runGit(t, src, "branch", "--show-current"))
+	srcRef, err := os.Stat(filepath.Join(src, ref))
+	require.NoError(t, err)
+	require.NoError(t, os.Chtimes(filepath.Join(dst, ref), srcRef.ModTime(), srcRef.ModTime()))
+
+	require.NoError(t, SyncProjectDir(src, dst, false, true, "", files))
+
+	assert.Equal(t, runGit(t, src, "rev-parse", "HEAD"), runGit(t, dst, "rev-parse", "HEAD"))
+}
@@ -5,11 +5,13 @@ package yoloai
 
 import (
 	"context"
+	"time"
 
 	"github.com/kstenerud/yoloai/internal/agent"
 	"github.com/kstenerud/yoloai/internal/netpolicy"
 	"github.com/kstenerud/yoloai/internal/netpolicycfg"
 	"github.com/kstenerud/yoloai/internal/orchestrator"
+	"github.com/kstenerud/yoloai/store"
 	"github.com/kstenerud/yoloai/yoerrors"
 )
 
@@ -31,6 +33,17 @@ const (
 	AllowedFromUser DomainSource = netpolicy.AllowedFromUser
 )
 
+// EgressEvent is one entry in Network.Egress().
+// See store.EgressEvent for the authoritative definition.
+type EgressEvent = store.EgressEvent
+
+// Egress verdicts (EgressEvent.Verdict).
+const (
+	EgressAllowed = store.EgressAllowed
+	EgressBlocked = store.EgressBlocked
+	EgressFailed  = store.EgressFailed
+)
+
 // Network is the per-sandbox network-allowlist sub-handle.
 //
 // Q-V resolution (2026-05-25): provenance is RECOVERABLE at read
@@ -199,6 +212,50 @@ func (n *Network) Deny(ctx context.Context, domains ...string) (*DenyResult, err
 	return &DenyResult{Removed: removed, Live: live}, nil
 }
 
+// Refresh re-resolves every allowlisted domain in the running sandbox and
+// replaces the IPs its firewall admits with the fresh answers. Isolation
+// resolves the allowlist once, when the sandbox starts, and CDN-hosted APIs
+// rotate their addresses within hours, so a long-lived sandbox slowly loses
+// the endpoints it was allowed; the daemon calls this on every sweep.
+//
+// The new set is built beside the live one and swapped in atomically, and
+// only when at least one domain resolved, so a DNS outage never empties the
+// allowlist. RefreshResult.Live is false when the sandbox isn't running.
+// Returns a *UsageError if the sandbox isn't using :isolated network mode,
+// and the exec error if the firewall couldn't be updated (e.g. a backend
+// that fell back to per-IP iptables rules, which have no set to swap).
+func (n *Network) Refresh(ctx context.Context) (*RefreshResult, error) {
+	np, err := n.requireIsolated()
+	if err != nil {
+		return nil, err
+	}
+	domains := append([]string{}, np.Allow...)
+	if len(domains) == 0 {
+		return &RefreshResult{Domains: domains}, nil
+	}
+	live, err := n.engine.LivePatchNetwork(ctx, n.name, ipsetRefreshScript, domains)
+	if err != nil {
+		return nil, err
+	}
+	return &RefreshResult{Domains: domains, Live: live}, nil
+}
+
+// Egress returns the HTTP(S) requests the sandbox's egress audit proxy
+// recorded at or after since, oldest first: each destination the agent asked
+// for and whether the firewall let it through. Only programs that honour
+// HTTP(S)_PROXY are recorded; the firewall holds the rest regardless.
+// Returns a *UsageError if the sandbox isn't using :isolated network mode.
+func (n *Network) Egress(since time.Time) ([]EgressEvent, error) {
+	np, err := n.loadNetpolicy()
+	if err != nil {
+		return nil, err
+	}
+	if np.Mode != "isolated" {
+		return nil, yoerrors.NewUsageError("sandbox %q is not using network isolation; only isolated sandboxes record egress", n.name)
+	}
+	return store.LoadEgress(n.engine.Layout().SandboxDir(n.name), since)
+}
+
 // AllowResult is returned by Network.Allow.
 type AllowResult struct {
 	// Added lists the domains that were newly added (input
@@ -221,6 +278,16 @@ type DenyResult struct {
 	Live bool `json:"live"`
 }
 
+// RefreshResult is returned by Network.Refresh.
+type RefreshResult struct {
+	// Domains lists the allowlisted domains that were re-resolved.
+	// Always non-nil.
+	Domains []string `json:"domains"`
+	// Live is true if the sandbox's ipset was replaced; false if the
+	// sandbox isn't running (or the allowlist is empty).
+	Live bool `json:"live"`
+}
+
 // --- helpers ---
 
 // loadNetpolicy reads the sandbox's netpolicy.json.
@@ -275,3 +342,31 @@ const ipsetResolveDomainsScript = `for domain in "$@"; do
       ipset add allowed-domains "$ip" 2>/dev/null || true
   done
 done`
+
+// ipsetRefreshScript re-resolves domains into a scratch ipset and swaps it
+// for allowed-domains, so connections to an address that is still allowed
+// never see a gap. An IPv4 literal in the allowlist is kept as is (startup
+// admits it too). When nothing resolves the live set is kept and the script
+// exits non-zero.
+//
+// Args are positional: $1 onward are domain names.
+const ipsetRefreshScript = `ipset create -exist allowed-domains-refresh hash:net || exit 1
+ipset flush allowed-domains-refresh
+n=0
+for domain in "$@"; do
+  ips=$domain
+  echo "$domain" | grep -qE "^[0-9]+\.[0-9]+\.[0-9]+\.[0-9]+$" || ips=$(dig +short A "$domain" 2>/dev/null)
+  for ip in $ips; do
+    if echo "$ip" | grep -qE "^[0-9]+\.[0-9]+\.[0-9]+\.[0-9]+$" && \
+      ipset add -exist allowed-domains-refresh "$ip" 2>/dev/null; then
+      n=$((n+1))
+    fi
+  done
+done
+if [ "$n" -eq 0 ]; then
+  ipset destroy allowed-domains-refresh
+  echo "no allowlisted domain resolved; keeping the current rules" >&2
+  exit 1
+fi
+ipset swap allowed-domains-refresh allowed-domains || { ipset destroy allowed-domains-refresh; exit 1; }
+ipset destroy allowed-domains-refresh`
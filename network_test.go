@@ -9,12 +9,14 @@ import (
 	"log/slog"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/kstenerud/yoloai/internal/netpolicycfg"
 	"github.com/kstenerud/yoloai/internal/orchestrator"
 	"github.com/kstenerud/yoloai/internal/orchestrator/agentcfg"
+	"github.com/kstenerud/yoloai/internal/sysexec"
 	"github.com/kstenerud/yoloai/store"
 	"github.com/kstenerud/yoloai/yoerrors"
 	"github.com/stretchr/testify/assert"
@@ -296,6 +298,67 @@ func TestNetwork_Allow_NotIsolated_UsageError(t *testing.T) {
 	assert.Contains(t, err.Error(), "not using network isolation")
 }
 
+// --- Refresh ---
+
+func TestNetwork_Refresh_NotRunning(t *testing.T) {
+	c, sys := clientWithSandbox(t)
+	writeIsolatedSandbox(t, sys, "box", "claude", []string{"api.anthropic.com", "extra.example"})
+
+	result, err := mustSandbox(t, c, "box").Network().Refresh(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"api.anthropic.com", "extra.example"}, result.Domains)
+	assert.False(t, result.Live, "nothing to patch in a sandbox that isn't running")
+}
+
+func TestNetwork_Refresh_NoneNetworkMode_UsageError(t *testing.T) {
+	c, sys := clientWithSandbox(t)
+	writeNoNetworkSandbox(t, sys, "box")
+
+	_, err := mustSandbox(t, c, "box").Network().Refresh(context.Background())
+	var usage *yoerrors.UsageError
+	require.ErrorAs(t, err, &usage)
+}
+
+// TestIpsetRefreshScript runs the refresh script against stub ipset and dig
+// binaries: resolved addresses and IPv4 literals go into the scratch set,
+// which replaces the live one, and a run where nothing resolves leaves the
+// live set alone.
+func TestIpsetRefreshScript(t *testing.T) {
+	bin := t.TempDir()
+	calls := filepath.Join(bin, "calls")
+	stubs := map[string]string{
+		"ipset": `echo "ipset $*" >> "` + calls + `"`,
+		"dig":   `[ "$3" = "cdn.example" ] && printf '1.2.3.4\ncname.example.\n5.6.7.8\n'; true`,
+	}
+	for name, body := range stubs {
+		require.NoError(t, os.WriteFile(filepath.Join(bin, name), []byte("#!/bin/sh\n"+body+"\n"), 0o755)) //nolint:gosec // G306: test stub must be executable
+	}
+	run := func(domains ...string) ([]string, error) {
+		_ = os.Remove(calls)
+		env := []string{"PATH=" + bin + ":" + os.Getenv("PATH")}
+		cmd := sysexec.Command(env, "sh", append([]string{"-c", ipsetRefreshScript, "_"}, domains...)...)
+		err := cmd.Run()
+		data, _ := os.ReadFile(calls) //nolint:gosec // test path
+		return strings.Split(strings.TrimSpace(string(data)), "\n"), err
+	}
+
+	got, err := run("cdn.example", "9.9.9.9", "gone.example")
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"ipset create -exist allowed-domains-refresh hash:net",
+		"ipset flush allowed-domains-refresh",
+		"ipset add -exist allowed-domains-refresh 1.2.3.4",
+		"ipset add -exist allowed-domains-refresh 5.6.7.8",
+		"ipset add -exist allowed-domains-refresh 9.9.9.9",
+		"ipset swap allowed-domains-refresh allowed-domains",
+		"ipset destroy allowed-domains-refresh",
+	}, got)
+
+	got, err = run("gone.example")
+	require.Error(t, err, "nothing resolved")
+	assert.NotContains(t, got, "ipset swap allowed-domains-refresh allowed-domains")
+}
+
 // --- Deny ---
 
 func TestNetwork_Deny_RemovesAndTagsSource(t *testing.T) {
@@ -387,3 +450,29 @@ func TestNetworkTest_Layout(t *testing.T) {
 	// Walk up: parent must exist.
 	require.DirExists(t, filepath.Dir(c.layout.SandboxesDir()))
 }
+
+// --- Egress ---
+
+func TestNetwork_Egress(t *testing.T) {
+	c, sys := clientWithSandbox(t)
+	writeIsolatedSandbox(t, sys, "box", "claude", nil)
+
+	events, err := mustSandbox(t, c, "box").Network().Egress(time.Time{})
+	require.NoError(t, err)
+	assert.Empty(t, events, "nothing recorded yet")
+
+	logPath := filepath.Join(sys.layout.SandboxDir("box"), store.EgressJSONLFile)
+	require.NoError(t, os.MkdirAll(filepath.Dir(logPath), 0750))
+	require.NoError(t, os.WriteFile(logPath, []byte(
+		`{"ts": "2026-03-01T12:00:00.000Z", "method": "CONNECT", "host": "evil.example", "port": 443, "verdict": "blocked"}`+"\n"), 0600))
+	events, err = mustSandbox(t, c, "box").Network().Egress(time.Time{})
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, "evil.example", events[0].Host)
+	assert.Equal(t, EgressBlocked, events[0].Verdict)
+
+	writeNoNetworkSandbox(t, sys, "nonet")
+	_, err = mustSandbox(t, c, "nonet").Network().Egress(time.Time{})
+	var usage *yoerrors.UsageError
+	assert.ErrorAs(t, err, &usage)
+}
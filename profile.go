@@ -1,5 +1,5 @@
-// ABOUTME: System.Profiles() sub-handle: profile create/list/info/delete
-// ABOUTME: as library orchestration; CLI consumes the typed results.
+// ABOUTME: System.Profiles() sub-handle: profile create/list/info/delete and
+// ABOUTME: export/import as library orchestration; CLI consumes the typed results.
 
 package yoloai
 
@@ -7,6 +7,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 
@@ -63,7 +64,8 @@ func (a *ProfileAdmin) Create(_ context.Context, name string) error {
 	if err := config.ValidateProfileName(name); err != nil {
 		return err
 	}
-	if config.ProfileExists(a.layout, name) {
+	// A system-wide profile of the same name may be shadowed by the user's own.
+	if config.ProfileExists(a.layout, name) && !config.IsSystemProfile(a.layout, name) {
 		return yoerrors.NewUsageError("profile %q already exists", name)
 	}
 
@@ -83,6 +85,7 @@ type ProfileSummary struct {
 	Name          string    // profile name
 	AgentType     AgentType // configured agent, empty if not set
 	HasDockerfile bool      // profile carries its own Dockerfile
+	Source        string    // repository it was added from (URL//dir), "" if not added by Add
 }
 
 // List returns one ProfileSummary per profile under ~/.yoloai/profiles/.
@@ -103,6 +106,9 @@ func (a *ProfileAdmin) List(_ context.Context) ([]ProfileSummary, error) {
 		if profile, loadErr := config.LoadProfile(a.layout, name); loadErr == nil {
 			summary.AgentType = AgentType(profile.Agent)
 		}
+		if origin, _ := config.LoadProfileOrigin(a.layout, name); origin != nil { //nolint:errcheck // an unreadable origin just isn't shown
+			summary.Source = origin.Source()
+		}
 		out = append(out, summary)
 	}
 	return out, nil
@@ -265,6 +271,9 @@ func (a *ProfileAdmin) Delete(_ context.Context, name string) (*ProfileDeleteRes
 	if !config.ProfileExists(a.layout, name) {
 		return nil, yoerrors.NewUsageError("profile %q does not exist", name)
 	}
+	if config.IsSystemProfile(a.layout, name) {
+		return nil, yoerrors.NewUsageError("profile %q is a system-wide profile in %s and can't be deleted here", name, a.layout.SystemProfilesDir())
+	}
 
 	dir := a.layout.ProfileDir(name)
 	if err := os.RemoveAll(dir); err != nil {
@@ -285,3 +294,61 @@ func (a *ProfileAdmin) Delete(_ context.Context, name string) (*ProfileDeleteRes
 	}
 	return &ProfileDeleteResult{ImageCleanupHints: hints}, nil
 }
+
+// ProfileExportResult reports what ProfileAdmin.Export put in the archive.
+type ProfileExportResult struct {
+	// Files are the archived paths, relative to the profile directory.
+	Files []string
+	// Omitted are the secrets left out, as for ConfigAdmin.Export.
+	Omitted []ConfigBundleOmission
+}
+
+// ProfileImportOptions configures ProfileAdmin.Import.
+type ProfileImportOptions struct {
+	// Name installs the profile under this name instead of the one it was
+	// exported with.
+	Name string
+	// Overwrite replaces an existing profile of that name; without it Import
+	// refuses.
+	Overwrite bool
+}
+
+// ProfileImportResult reports what ProfileAdmin.Import installed.
+type ProfileImportResult struct {
+	Name     string   // the installed profile's name
+	Files    []string // files written, relative to the profile directory
+	Replaced bool     // an existing profile of that name was replaced
+}
+
+// Export writes the named profile — config.yaml, the Dockerfile and every
+// other file in its directory — to w as a gzipped tar, for Import on another
+// machine or by a teammate. Secrets are left out and reported the way
+// ConfigAdmin.Export does; the image itself isn't included, and is built on
+// the importer's first use.
+//
+// Returns a *UsageError if the name is invalid or does not exist.
+func (a *ProfileAdmin) Export(_ context.Context, name string, w io.Writer) (*ProfileExportResult, error) {
+	b, err := config.ExportProfile(a.layout, name, w)
+	if err != nil {
+		return nil, err
+	}
+	result := &ProfileExportResult{Files: b.Files}
+	for _, o := range b.Omitted {
+		result.Omitted = append(result.Omitted, ConfigBundleOmission(o))
+	}
+	return result, nil
+}
+
+// Import installs a profile archive written by Export. The archive is checked
+// whole before anything is written, and an existing profile of the same name
+// is only replaced with opts.Overwrite.
+//
+// Returns a *UsageError for an archive that isn't a profile archive, an
+// invalid name, or a name already taken.
+func (a *ProfileAdmin) Import(_ context.Context, r io.Reader, opts ProfileImportOptions) (*ProfileImportResult, error) {
+	p, err := config.ImportProfile(a.layout, r, opts.Name, opts.Overwrite)
+	if err != nil {
+		return nil, err
+	}
+	return &ProfileImportResult{Name: p.Name, Files: p.Files, Replaced: p.Replaced}, nil
+}
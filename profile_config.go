@@ -26,6 +26,7 @@ type ResolvedProfileConfig struct {
 	Directories        []ProfileAuxDir    `json:"directories,omitempty"`
 	Resources          *ProfileResources  `json:"resources,omitempty"`
 	Network            *ProfileNetwork    `json:"network,omitempty"`
+	Guard              *ProfileGuard      `json:"guard,omitempty"`
 	Mounts             []string           `json:"mounts,omitempty"`
 	AgentArgs          map[string]string  `json:"agent_args,omitempty"`
 	AgentFiles         *ProfileAgentFiles `json:"agent_files,omitempty"`
@@ -34,6 +35,12 @@ type ResolvedProfileConfig struct {
 	Setup              []string           `json:"setup,omitempty"`
 	AutoCommitInterval int                `json:"auto_commit_interval,omitempty"`
 	Isolation          string             `json:"isolation,omitempty"`
+	ProvenanceHeaders  bool               `json:"provenance_headers,omitempty"`
+	Timezone           string             `json:"timezone,omitempty"`
+	Locale             string             `json:"locale,omitempty"`
+	TTL                string             `json:"ttl,omitempty"`
+	FakeTime           string             `json:"faketime,omitempty"`
+	PreLaunch          string             `json:"pre_launch,omitempty"`
 }
 
 // ProfileWorkdir is the resolved primary working directory of a profile.
@@ -54,6 +61,9 @@ type ProfileAuxDir struct {
 type ProfileResources struct {
 	CPULimit    string `json:"cpus,omitempty"`
 	MemoryLimit string `json:"memory,omitempty"`
+	DiskLimit   string `json:"disk,omitempty"`
+	// AgentStateLimit caps the agent's state directory (resources.agent_state).
+	AgentStateLimit string `json:"agent_state,omitempty"`
 }
 
 // ProfileNetwork holds a profile's network isolation settings.
@@ -62,6 +72,12 @@ type ProfileNetwork struct {
 	Allow    []string `json:"allow,omitempty"`
 }
 
+// ProfileGuard holds a profile's destructive-command guard settings.
+type ProfileGuard struct {
+	Mode     string   `json:"mode,omitempty"` // "off", "log", or "block"
+	Commands []string `json:"commands,omitempty"`
+}
+
 // ProfileAgentFiles holds a profile's agent_files setting. Exactly one form
 // is populated: BaseDir for the string (base directory) form, or Files for
 // the explicit-list form.
@@ -93,6 +109,12 @@ func resolvedProfileConfigFromMerged(m *config.MergedConfig) *ResolvedProfileCon
 		Setup:              m.Setup,
 		AutoCommitInterval: m.AutoCommitInterval,
 		Isolation:          m.Isolation,
+		ProvenanceHeaders:  m.ProvenanceHeaders,
+		Timezone:           m.Timezone,
+		Locale:             m.Locale,
+		TTL:                m.TTL,
+		FakeTime:           m.FakeTime,
+		PreLaunch:          m.PreLaunch,
 	}
 	if m.Workdir != nil {
 		pc.Workdir = &ProfileWorkdir{
@@ -109,8 +131,10 @@ func resolvedProfileConfigFromMerged(m *config.MergedConfig) *ResolvedProfileCon
 	}
 	if m.Resources != nil {
 		pc.Resources = &ProfileResources{
-			CPULimit:    m.Resources.CPUs,
-			MemoryLimit: m.Resources.Memory,
+			CPULimit:        m.Resources.CPUs,
+			MemoryLimit:     m.Resources.Memory,
+			DiskLimit:       m.Resources.Disk,
+			AgentStateLimit: m.Resources.AgentState,
 		}
 	}
 	if m.Network != nil {
@@ -119,6 +143,12 @@ func resolvedProfileConfigFromMerged(m *config.MergedConfig) *ResolvedProfileCon
 			Allow:    m.Network.Allow,
 		}
 	}
+	if m.Guard != nil {
+		pc.Guard = &ProfileGuard{
+			Mode:     m.Guard.Mode,
+			Commands: m.Guard.Commands,
+		}
+	}
 	if m.AgentFiles != nil {
 		pc.AgentFiles = &ProfileAgentFiles{
 			BaseDir: m.AgentFiles.BaseDir,
@@ -0,0 +1,98 @@
+// ABOUTME: ProfileAdmin.FromDevcontainer: generates a profile (config.yaml and a
+// ABOUTME: Dockerfile on yoloai-base) from a repository's devcontainer.json.
+
+package yoloai
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+
+	"github.com/kstenerud/yoloai/internal/config"
+	"github.com/kstenerud/yoloai/internal/orchestrator/archetype"
+	"github.com/kstenerud/yoloai/yoerrors"
+)
+
+// ProfileFromDevcontainerOptions configures ProfileAdmin.FromDevcontainer.
+type ProfileFromDevcontainerOptions struct {
+	// Name names the profile; "" uses the repository directory's name.
+	Name string
+	// Overwrite replaces an existing profile of that name; without it the
+	// name must be free.
+	Overwrite bool
+}
+
+// ProfileFromDevcontainerResult reports what ProfileAdmin.FromDevcontainer
+// generated.
+type ProfileFromDevcontainerResult struct {
+	Name     string   // the profile's name
+	Source   string   // the devcontainer.json it was generated from
+	Files    []string // files written, relative to the profile directory
+	Replaced bool     // an existing profile of that name was replaced
+	Notes    []string // what couldn't be carried over, to finish by hand
+}
+
+// FromDevcontainer generates a profile from the devcontainer.json of the
+// repository at path (.devcontainer/devcontainer.json, else devcontainer.json
+// at its root), or from the devcontainer.json that path names. Its forwarded
+// ports, environment and run limits go into config.yaml; the toolchains its
+// image and features provide become install steps in a Dockerfile on
+// yoloai-base. Anything that can't be translated is listed in the result's
+// Notes.
+//
+// Returns a *UsageError when there is no devcontainer.json, it can't be
+// translated, or the name is invalid or already taken.
+func (a *ProfileAdmin) FromDevcontainer(_ context.Context, path string, opts ProfileFromDevcontainerOptions) (*ProfileFromDevcontainerResult, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+	info, err := os.Stat(abs)
+	if err != nil {
+		return nil, yoerrors.NewUsageError("%s: %v", path, err)
+	}
+	dcPath, repo := abs, filepath.Dir(abs)
+	if info.IsDir() {
+		repo = abs
+		if dcPath = archetype.FindDevcontainer(abs); dcPath == "" {
+			return nil, yoerrors.NewUsageError("%s has no .devcontainer/devcontainer.json or devcontainer.json", path)
+		}
+	} else if filepath.Base(repo) == ".devcontainer" {
+		repo = filepath.Dir(repo)
+	}
+
+	name := opts.Name
+	if name == "" {
+		name = filepath.Base(repo)
+	}
+	if err := config.ValidateProfileName(name); err != nil {
+		if opts.Name == "" {
+			return nil, yoerrors.NewUsageError("can't name the profile after %q; pick a name with --name", filepath.Base(repo))
+		}
+		return nil, err
+	}
+	if !opts.Overwrite && config.ProfileExists(a.layout, name) && !config.IsSystemProfile(a.layout, name) {
+		return nil, yoerrors.NewUsageError("profile %q already exists; re-run with --force to replace it, or pick another name with --name", name)
+	}
+
+	dc, err := archetype.LoadDevcontainer(dcPath)
+	if err != nil {
+		return nil, yoerrors.NewUsageError("%s: %v", dcPath, err)
+	}
+	gen, err := dc.ToProfile(dcPath)
+	if err != nil {
+		return nil, yoerrors.NewUsageError("%s: %v", dcPath, err)
+	}
+	files := map[string][]byte{"config.yaml": gen.Config}
+	if gen.Dockerfile != nil {
+		files["Dockerfile"] = gen.Dockerfile
+	}
+	installed, err := config.InstallProfileFiles(a.layout, name, files, opts.Overwrite)
+	if err != nil {
+		return nil, err
+	}
+	return &ProfileFromDevcontainerResult{
+		Name: name, Source: dcPath, Files: installed.Files,
+		Replaced: installed.Replaced, Notes: gen.Notes,
+	}, nil
+}
@@ -0,0 +1,86 @@
+// ABOUTME: Tests for ProfileAdmin.FromDevcontainer: the generated profile loads
+// ABOUTME: and builds on yoloai-base, naming, and the refusals.
+
+package yoloai
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/kstenerud/yoloai/internal/config"
+	"github.com/kstenerud/yoloai/yoerrors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProfileAdmin_FromDevcontainer(t *testing.T) {
+	sys := newTestClient(t)
+	ctx := context.Background()
+	repo := filepath.Join(t.TempDir(), "api")
+	require.NoError(t, os.MkdirAll(filepath.Join(repo, ".devcontainer"), 0o750))
+	dcPath := filepath.Join(repo, ".devcontainer", "devcontainer.json")
+	require.NoError(t, os.WriteFile(dcPath, []byte(`{
+	// Go service
+	"image": "mcr.microsoft.com/devcontainers/go:1-1.22-bookworm",
+	"features": {"ghcr.io/devcontainers/features/node:1": {"version": "18"}},
+	"forwardPorts": [8080],
+	"containerEnv": {"APP_ENV": "dev"},
+	"runArgs": ["--memory=4g"],
+	"postCreateCommand": "go mod download",
+}
+`), 0o600))
+
+	result, err := sys.Profiles().FromDevcontainer(ctx, repo, ProfileFromDevcontainerOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "api", result.Name, "named after the repository")
+	assert.Equal(t, dcPath, result.Source)
+	assert.Equal(t, []string{"Dockerfile", "config.yaml"}, result.Files)
+	assert.False(t, result.Replaced)
+	require.Len(t, result.Notes, 1)
+	assert.Contains(t, result.Notes[0], "lifecycle")
+
+	p, err := config.LoadProfile(sys.layout, "api")
+	require.NoError(t, err, "the generated config.yaml is a valid profile")
+	assert.Equal(t, []string{"8080:8080"}, p.Ports)
+	assert.Equal(t, "dev", p.Env["APP_ENV"])
+	require.NotNil(t, p.Resources)
+	assert.Equal(t, "4g", p.Resources.Memory)
+	df, err := os.ReadFile(filepath.Join(sys.layout.ProfileDir("api"), "Dockerfile"))
+	require.NoError(t, err)
+	assert.Contains(t, string(df), "\nFROM yoloai-base\n")
+
+	var usage *yoerrors.UsageError
+	_, err = sys.Profiles().FromDevcontainer(ctx, repo, ProfileFromDevcontainerOptions{})
+	require.ErrorAs(t, err, &usage, "the name is taken")
+
+	// Pointing at the file itself finds the same repository name.
+	result, err = sys.Profiles().FromDevcontainer(ctx, dcPath, ProfileFromDevcontainerOptions{Overwrite: true})
+	require.NoError(t, err)
+	assert.Equal(t, "api", result.Name)
+	assert.True(t, result.Replaced)
+
+	result, err = sys.Profiles().FromDevcontainer(ctx, repo, ProfileFromDevcontainerOptions{Name: "api-dev"})
+	require.NoError(t, err)
+	assert.Equal(t, "api-dev", result.Name)
+}
+
+func TestProfileAdmin_FromDevcontainerErrors(t *testing.T) {
+	sys := newTestClient(t)
+	ctx := context.Background()
+	var usage *yoerrors.UsageError
+
+	_, err := sys.Profiles().FromDevcontainer(ctx, t.TempDir(), ProfileFromDevcontainerOptions{})
+	require.ErrorAs(t, err, &usage, "no devcontainer.json")
+
+	_, err = sys.Profiles().FromDevcontainer(ctx, filepath.Join(t.TempDir(), "missing"), ProfileFromDevcontainerOptions{})
+	require.ErrorAs(t, err, &usage)
+
+	repo := filepath.Join(t.TempDir(), "svc")
+	require.NoError(t, os.Mkdir(repo, 0o750))
+	require.NoError(t, os.WriteFile(filepath.Join(repo, "devcontainer.json"), []byte(`{"dockerComposeFile": "compose.yaml"}`), 0o600))
+	_, err = sys.Profiles().FromDevcontainer(ctx, repo, ProfileFromDevcontainerOptions{})
+	require.ErrorAs(t, err, &usage, "compose devcontainers can't be translated")
+	assert.False(t, config.ProfileExists(sys.layout, "svc"))
+}
@@ -0,0 +1,274 @@
+// ABOUTME: ProfileAdmin.Add / Update: profiles installed from a directory of a
+// ABOUTME: git repository, with their origin recorded so they can be refreshed.
+
+package yoloai
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/kstenerud/yoloai/internal/config"
+	"github.com/kstenerud/yoloai/internal/git"
+	"github.com/kstenerud/yoloai/yoerrors"
+)
+
+// ProfileAddOptions configures ProfileAdmin.Add.
+type ProfileAddOptions struct {
+	// Name installs the profile under this name instead of the last element
+	// of its directory in the repository.
+	Name string
+	// Ref is the branch, tag or full commit SHA to install and, for a branch,
+	// to follow on Update. "" is the repository's default branch.
+	Ref string
+	// Overwrite replaces an existing profile of that name; without it Add
+	// refuses.
+	Overwrite bool
+}
+
+// ProfileAddResult reports what ProfileAdmin.Add installed.
+type ProfileAddResult struct {
+	Name     string   // the installed profile's name
+	Source   string   // where it came from, as URL//dir
+	Commit   string   // the commit installed
+	Files    []string // files written, relative to the profile directory
+	Replaced bool     // an existing profile of that name was replaced
+}
+
+// ProfileUpdateOptions configures ProfileAdmin.Update.
+type ProfileUpdateOptions struct {
+	// Force discards local edits to the profile's files; without it Update
+	// refuses a profile that has any.
+	Force bool
+}
+
+// ProfileUpdateResult reports what ProfileAdmin.Update did.
+type ProfileUpdateResult struct {
+	Name      string   // the profile
+	Source    string   // where it came from, as URL//dir
+	OldCommit string   // the commit installed before
+	Commit    string   // the commit installed now
+	Updated   bool     // false when the profile was already at Commit
+	Files     []string // files written when Updated
+}
+
+// commitSHAPattern matches a full commit ID (SHA-1 or SHA-256).
+var commitSHAPattern = regexp.MustCompile(`^([0-9a-f]{40}|[0-9a-f]{64})$`)
+
+// Add installs a profile from a directory of a git repository, named as
+// URL//dir (github.com/org/profiles//go-dev), or as a bare URL when the
+// repository's root is the profile. A URL without a scheme that starts with a
+// host name is fetched over https. The host's git does the clone, so its
+// credential helpers reach private repositories.
+//
+// The origin is recorded in the profile directory for Update. Returns a
+// *UsageError for a malformed source, a directory that isn't a profile, or a
+// name already taken.
+func (a *ProfileAdmin) Add(ctx context.Context, source string, opts ProfileAddOptions) (*ProfileAddResult, error) {
+	url, subdir, err := parseProfileSource(source)
+	if err != nil {
+		return nil, err
+	}
+	name := opts.Name
+	if name == "" {
+		name = defaultProfileName(url, subdir)
+	}
+	if err := config.ValidateProfileName(name); err != nil {
+		return nil, err
+	}
+	if !opts.Overwrite && config.ProfileExists(a.layout, name) && !config.IsSystemProfile(a.layout, name) {
+		return nil, yoerrors.NewUsageError("profile %q already exists; re-run with --force to replace it, or add it under another name with --name", name)
+	}
+
+	origin := config.ProfileOrigin{URL: url, Subdir: subdir, Ref: opts.Ref}
+	checkout, err := a.fetchProfile(ctx, &origin)
+	if err != nil {
+		return nil, err
+	}
+	defer checkout.remove()
+	installed, err := config.InstallProfileFromDir(a.layout, name, checkout.dir, origin, opts.Overwrite)
+	if err != nil {
+		return nil, err
+	}
+	return &ProfileAddResult{
+		Name: name, Source: origin.Source(), Commit: origin.Commit,
+		Files: installed.Files, Replaced: installed.Replaced,
+	}, nil
+}
+
+// Update refreshes a profile installed by Add from its repository, following
+// the branch it was added from. A profile whose files were edited since is
+// refused unless opts.Force, which discards the edits.
+//
+// Returns a *UsageError if the profile doesn't exist or wasn't added from a
+// repository.
+func (a *ProfileAdmin) Update(ctx context.Context, name string, opts ProfileUpdateOptions) (*ProfileUpdateResult, error) {
+	if err := config.ValidateProfileName(name); err != nil {
+		return nil, err
+	}
+	origin, err := config.LoadProfileOrigin(a.layout, name)
+	if err != nil {
+		return nil, err
+	}
+	if origin == nil {
+		if !config.ProfileExists(a.layout, name) {
+			return nil, yoerrors.NewUsageError("profile %q does not exist", name)
+		}
+		return nil, yoerrors.NewUsageError("profile %q wasn't added from a git repository; only profiles installed with 'yoloai profile add' can be updated", name)
+	}
+	changed, err := config.ProfileLocalChanges(a.layout, name, origin)
+	if err != nil {
+		return nil, err
+	}
+	if len(changed) > 0 && !opts.Force {
+		return nil, yoerrors.NewUsageError("profile %q has local changes (%s); re-run with --force to discard them", name, strings.Join(changed, ", "))
+	}
+
+	next := *origin
+	checkout, err := a.fetchProfile(ctx, &next)
+	if err != nil {
+		return nil, err
+	}
+	defer checkout.remove()
+	result := &ProfileUpdateResult{Name: name, Source: origin.Source(), OldCommit: origin.Commit, Commit: next.Commit}
+	// An unmoved commit leaves the profile alone, unless it is forced past
+	// local edits, which are then really discarded.
+	if next.Commit == origin.Commit && len(changed) == 0 {
+		return result, nil
+	}
+	installed, err := config.InstallProfileFromDir(a.layout, name, checkout.dir, next, true)
+	if err != nil {
+		return nil, err
+	}
+	result.Updated = true
+	result.Files = installed.Files
+	return result, nil
+}
+
+// profileCheckout is the profile's directory in a scratch clone.
+type profileCheckout struct {
+	dir     string
+	scratch string
+}
+
+func (c *profileCheckout) remove() {
+	os.RemoveAll(c.scratch) //nolint:errcheck,gosec // best-effort cleanup
+}
+
+// fetchProfile clones origin's repository into a scratch directory, sets
+// origin.Commit and Updated, and returns the profile's directory in the clone.
+// The caller removes it.
+func (a *ProfileAdmin) fetchProfile(ctx context.Context, origin *config.ProfileOrigin) (*profileCheckout, error) {
+	scratch, err := a.layout.MkdirTemp("profile-add-")
+	if err != nil {
+		return nil, err
+	}
+	c := &profileCheckout{scratch: scratch}
+	repo := filepath.Join(scratch, "repo")
+	g := git.NewHost(a.layout)
+	if err := cloneProfileRepo(ctx, g, origin.URL, origin.Ref, repo); err != nil {
+		c.remove()
+		return nil, err
+	}
+	if origin.Commit, err = g.HeadSHA(ctx, repo); err != nil {
+		c.remove()
+		return nil, err
+	}
+	origin.Updated = time.Now().UTC()
+	if c.dir, err = profileDirInCheckout(repo, origin.Subdir); err != nil {
+		c.remove()
+		return nil, yoerrors.NewUsageError("%s: %v", origin.Source(), err)
+	}
+	return c, nil
+}
+
+// cloneProfileRepo checks out ref of url into dest: a shallow clone of a
+// branch or tag, or a full one for a commit SHA, which a server won't serve
+// on its own.
+func cloneProfileRepo(ctx context.Context, g *git.Git, url, ref, dest string) error {
+	args := []string{"clone", "--quiet"}
+	isSHA := commitSHAPattern.MatchString(ref)
+	switch {
+	case isSHA:
+		args = append(args, "--no-checkout")
+	case ref != "":
+		args = append(args, "--depth", "1", "--branch", ref)
+	default:
+		args = append(args, "--depth", "1")
+	}
+	args = append(args, "--", url, dest)
+	if err := g.RunCmd(ctx, filepath.Dir(dest), args...); err != nil {
+		return fmt.Errorf("clone %s: %w", url, err)
+	}
+	if isSHA {
+		if err := g.RunCmd(ctx, dest, "checkout", "--quiet", ref); err != nil {
+			return fmt.Errorf("check out %s of %s: %w", ref, url, err)
+		}
+	}
+	return nil
+}
+
+// profileDirInCheckout resolves subdir inside checkout, refusing one that is
+// missing, isn't a directory, or leads out of the checkout through a symlink.
+func profileDirInCheckout(checkout, subdir string) (string, error) {
+	root, err := filepath.EvalSymlinks(checkout)
+	if err != nil {
+		return "", err
+	}
+	dir, err := filepath.EvalSymlinks(filepath.Join(root, filepath.FromSlash(subdir)))
+	if err != nil {
+		return "", fmt.Errorf("the repository has no directory %q", subdir)
+	}
+	if rel, err := filepath.Rel(root, dir); err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("%q leads outside the repository", subdir)
+	}
+	if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+		return "", fmt.Errorf("%q in the repository is not a directory", subdir)
+	}
+	return dir, nil
+}
+
+// parseProfileSource splits URL//dir into the repository URL and the
+// profile's directory in it. A scheme-less URL starting with a host name
+// (github.com/org/repo) gets https://; scp-style (git@host:org/repo) and
+// local paths are left for git.
+func parseProfileSource(source string) (url, subdir string, err error) {
+	source = strings.TrimSpace(source)
+	start := 0
+	if i := strings.Index(source, "://"); i >= 0 {
+		start = i + 3
+	}
+	url = source
+	if i := strings.Index(source[start:], "//"); i >= 0 {
+		url, subdir = source[:start+i], strings.TrimSuffix(source[start+i+2:], "/")
+		if subdir == "" || subdir != path.Clean(subdir) || path.IsAbs(subdir) || subdir == ".." || strings.HasPrefix(subdir, "../") {
+			return "", "", yoerrors.NewUsageError("profile source %q: %q is not a directory path in the repository", source, subdir)
+		}
+	}
+	url = strings.TrimSuffix(url, "/")
+	if url == "" {
+		return "", "", yoerrors.NewUsageError("profile source %q names no repository", source)
+	}
+	if start == 0 && !strings.HasPrefix(url, "/") && !strings.HasPrefix(url, ".") && !strings.HasPrefix(url, "~") {
+		host, _, _ := strings.Cut(url, "/")
+		if !strings.Contains(host, ":") && strings.Contains(host, ".") {
+			url = "https://" + url
+		}
+	}
+	return url, subdir, nil
+}
+
+// defaultProfileName is the last element of the profile's directory, or of
+// the repository when the profile is its root.
+func defaultProfileName(url, subdir string) string {
+	if subdir != "" {
+		return path.Base(subdir)
+	}
+	base := url[strings.LastIndexAny(url, "/:")+1:]
+	return strings.TrimSuffix(base, ".git")
+}
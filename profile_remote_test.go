@@ -0,0 +1,131 @@
+// ABOUTME: Tests for ProfileAdmin.Add / Update against a local git repository,
+// ABOUTME: and for parsing URL//dir profile sources.
+
+package yoloai
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/kstenerud/yoloai/internal/config"
+	"github.com/kstenerud/yoloai/internal/testutil"
+	"github.com/kstenerud/yoloai/yoerrors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// profileRepo returns a git repository holding a go-dev profile directory.
+func profileRepo(t *testing.T) string {
+	t.Helper()
+	repo := t.TempDir()
+	testutil.InitGitRepo(t, repo)
+	require.NoError(t, os.Mkdir(filepath.Join(repo, "go-dev"), 0o750))
+	testutil.WriteFile(t, repo, "go-dev/config.yaml", "agent: codex\n")
+	testutil.WriteFile(t, repo, "go-dev/Dockerfile", "FROM yoloai-base\n")
+	testutil.WriteFile(t, repo, "README.md", "team profiles\n")
+	testutil.GitAdd(t, repo, ".")
+	testutil.GitCommit(t, repo, "go-dev")
+	return repo
+}
+
+func TestProfileAdmin_AddUpdate(t *testing.T) {
+	sys := newTestClient(t)
+	ctx := context.Background()
+	repo := profileRepo(t)
+
+	added, err := sys.Profiles().Add(ctx, repo+"//go-dev", ProfileAddOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "go-dev", added.Name)
+	assert.Equal(t, repo+"//go-dev", added.Source)
+	assert.Equal(t, testutil.GitRevParse(t, repo), added.Commit)
+	assert.ElementsMatch(t, []string{"Dockerfile", "config.yaml"}, added.Files)
+	dir := sys.layout.ProfileDir("go-dev")
+	assert.NoFileExists(t, filepath.Join(dir, "README.md"), "only the profile's directory is installed")
+
+	summaries, err := sys.Profiles().List(ctx)
+	require.NoError(t, err)
+	require.Len(t, summaries, 1)
+	assert.Equal(t, repo+"//go-dev", summaries[0].Source)
+	assert.Equal(t, AgentType("codex"), summaries[0].AgentType)
+
+	var usage *yoerrors.UsageError
+	_, err = sys.Profiles().Add(ctx, repo+"//go-dev", ProfileAddOptions{})
+	require.ErrorAs(t, err, &usage, "the name is taken")
+
+	// Nothing new upstream.
+	up, err := sys.Profiles().Update(ctx, "go-dev", ProfileUpdateOptions{})
+	require.NoError(t, err)
+	assert.False(t, up.Updated)
+
+	// A new commit is picked up.
+	testutil.WriteFile(t, repo, "go-dev/config.yaml", "agent: gemini\n")
+	testutil.GitAdd(t, repo, ".")
+	testutil.GitCommit(t, repo, "switch agent")
+	up, err = sys.Profiles().Update(ctx, "go-dev", ProfileUpdateOptions{})
+	require.NoError(t, err)
+	assert.True(t, up.Updated)
+	assert.Equal(t, added.Commit, up.OldCommit)
+	assert.Equal(t, testutil.GitRevParse(t, repo), up.Commit)
+	p, err := config.LoadProfile(sys.layout, "go-dev")
+	require.NoError(t, err)
+	assert.Equal(t, "gemini", p.Agent)
+
+	// Local edits stop an update until forced.
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "Dockerfile"), []byte("FROM mine\n"), 0o600))
+	_, err = sys.Profiles().Update(ctx, "go-dev", ProfileUpdateOptions{})
+	require.ErrorAs(t, err, &usage)
+	assert.Contains(t, err.Error(), "Dockerfile")
+	up, err = sys.Profiles().Update(ctx, "go-dev", ProfileUpdateOptions{Force: true})
+	require.NoError(t, err)
+	assert.True(t, up.Updated, "forcing rewrites the profile even at the same commit")
+	data, err := os.ReadFile(filepath.Join(dir, "Dockerfile"))
+	require.NoError(t, err)
+	assert.Equal(t, "FROM yoloai-base\n", string(data))
+}
+
+func TestProfileAdmin_AddErrors(t *testing.T) {
+	sys := newTestClient(t)
+	ctx := context.Background()
+	repo := profileRepo(t)
+	var usage *yoerrors.UsageError
+
+	_, err := sys.Profiles().Add(ctx, repo+"//missing", ProfileAddOptions{})
+	assert.ErrorAs(t, err, &usage)
+	_, err = sys.Profiles().Add(ctx, repo, ProfileAddOptions{Name: "root"})
+	assert.ErrorAs(t, err, &usage, "the repository root has no config.yaml")
+	_, err = sys.Profiles().Add(ctx, repo+"//go-dev", ProfileAddOptions{Ref: "no-such-branch"})
+	assert.Error(t, err)
+
+	require.NoError(t, sys.Profiles().Create(ctx, "local"))
+	_, err = sys.Profiles().Update(ctx, "local", ProfileUpdateOptions{})
+	assert.ErrorAs(t, err, &usage, "a profile not added from git can't be updated")
+	_, err = sys.Profiles().Update(ctx, "nope", ProfileUpdateOptions{})
+	assert.ErrorAs(t, err, &usage)
+}
+
+func TestParseProfileSource(t *testing.T) {
+	for _, tc := range []struct{ in, url, subdir string }{
+		{"github.com/org/profiles//go-dev", "https://github.com/org/profiles", "go-dev"},
+		{"github.com/org/profiles//team/go-dev/", "https://github.com/org/profiles", "team/go-dev"},
+		{"https://git.example.com/org/profiles.git//go", "https://git.example.com/org/profiles.git", "go"},
+		{"git@github.com:org/profiles.git//rust", "git@github.com:org/profiles.git", "rust"},
+		{"ssh://git@host/org/p//x", "ssh://git@host/org/p", "x"},
+		{"/srv/profiles//go", "/srv/profiles", "go"},
+		{"github.com/org/go-profile", "https://github.com/org/go-profile", ""},
+	} {
+		url, subdir, err := parseProfileSource(tc.in)
+		require.NoError(t, err, tc.in)
+		assert.Equal(t, tc.url, url, tc.in)
+		assert.Equal(t, tc.subdir, subdir, tc.in)
+	}
+	var usage *yoerrors.UsageError
+	for _, bad := range []string{"", "//go", "github.com/org/p//../x", "github.com/org/p///abs", "github.com/org/p//a/./b"} {
+		_, _, err := parseProfileSource(bad)
+		assert.ErrorAs(t, err, &usage, bad)
+	}
+
+	assert.Equal(t, "go-dev", defaultProfileName("https://x/org/p", "team/go-dev"))
+	assert.Equal(t, "profiles", defaultProfileName("git@github.com:org/profiles.git", ""))
+}
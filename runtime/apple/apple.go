@@ -67,6 +67,7 @@ var descriptor = runtime.BackendDescriptor{
 		// hands the guest a ULA and no ip6tables rules exist (DF104).
 		NetworkIsolation:   true,
 		CapAdd:             true,
+		FakeTime:           true,
 		HostFilesystem:     false,
 		FilesystemLocality: runtime.LocalityHostSide,
 		KeepAliveModel:     runtime.KeepAliveGuestOSInit,
@@ -46,6 +46,7 @@ var descriptor = runtime.BackendDescriptor{
 		// IPv4 only — see the note in runtime/docker/docker.go (DF104).
 		NetworkIsolation:     true,
 		CapAdd:               true,
+		FakeTime:             true,
 		HostFilesystem:       false,
 		FilesystemLocality:   runtime.LocalityHostSide,
 		GitExecInConfinement: true, // copy-mode work-copy git runs in-container (audit C1)
@@ -109,6 +110,7 @@ var _ runtime.IsolationCapabilityProvider = (*Runtime)(nil)
 var _ runtime.CachePruner = (*Runtime)(nil)
 var _ runtime.InteractiveSession = (*Runtime)(nil)
 var _ runtime.DiskUsageReporter = (*Runtime)(nil)
+var _ runtime.Pauser = (*Runtime)(nil)
 
 // Descriptor returns a BackendDescriptor with the static facts for this backend.
 func (r *Runtime) Descriptor() runtime.BackendDescriptor {
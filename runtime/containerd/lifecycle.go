@@ -327,6 +327,11 @@ func buildContainerSpecOpts(img client.Image, netnsPath string, cfg runtime.Inst
 		specOpts = append(specOpts, oci.WithProcessCwd(cfg.WorkingDir))
 	}
 
+	// Layered over the image's env (WithImageConfig): same key, later wins.
+	if len(cfg.ContainerEnv) > 0 {
+		specOpts = append(specOpts, oci.WithEnv(cfg.ContainerEnv))
+	}
+
 	if cfg.Privileged {
 		specOpts = append(specOpts, oci.WithPrivileged)
 	} else {
@@ -753,6 +758,12 @@ func (r *Runtime) Stop(ctx context.Context, name string) error {
 		return fmt.Errorf("load task: %w", err)
 	}
 
+	// A frozen task can't act on SIGTERM; thaw it so the graceful stop works
+	// instead of always escalating to SIGKILL.
+	if st, err := task.Status(ctx); err == nil && st.Status == client.Paused {
+		_ = task.Resume(ctx)
+	}
+
 	if err := r.stopTaskWithEscalation(ctx, task, name); err != nil {
 		// stopTaskWithEscalation only returns on a non-recoverable
 		// failure (escalation itself failed); fall through to delete +
@@ -911,11 +922,62 @@ func (r *Runtime) Inspect(ctx context.Context, name string) (runtime.InstanceInf
 		return runtime.InstanceInfo{}, fmt.Errorf("task status: %w", err)
 	}
 
+	// A paused task is still up, just frozen; report it as running so
+	// lifecycle code doesn't mistake it for a stopped sandbox.
+	paused := status.Status == client.Paused || status.Status == client.Pausing
 	return runtime.InstanceInfo{
-		Running: status.Status == client.Running,
+		Running: status.Status == client.Running || paused,
+		Paused:  paused,
 	}, nil
 }
 
+// Pause freezes a running container's task (runtime.Pauser).
+func (r *Runtime) Pause(ctx context.Context, name string) error {
+	ctx = r.withNamespace(ctx)
+	task, err := r.loadTask(ctx, name)
+	if err != nil {
+		return err
+	}
+	if err := task.Pause(ctx); err != nil {
+		return fmt.Errorf("pause task: %w", err)
+	}
+	return nil
+}
+
+// Unpause resumes a task frozen by Pause (runtime.Pauser).
+func (r *Runtime) Unpause(ctx context.Context, name string) error {
+	ctx = r.withNamespace(ctx)
+	task, err := r.loadTask(ctx, name)
+	if err != nil {
+		return err
+	}
+	if err := task.Resume(ctx); err != nil {
+		return fmt.Errorf("resume task: %w", err)
+	}
+	return nil
+}
+
+// loadTask loads the task of a container, mapping a missing container to
+// ErrNotFound and a missing task to ErrNotRunning. ctx must already carry the
+// namespace.
+func (r *Runtime) loadTask(ctx context.Context, name string) (client.Task, error) {
+	ctr, err := r.client.LoadContainer(ctx, name)
+	if err != nil {
+		if errdefs.IsNotFound(err) {
+			return nil, runtime.ErrNotFound
+		}
+		return nil, fmt.Errorf("load container: %w", err)
+	}
+	task, err := ctr.Task(ctx, nil)
+	if err != nil {
+		if errdefs.IsNotFound(err) {
+			return nil, runtime.ErrNotRunning
+		}
+		return nil, fmt.Errorf("load task: %w", err)
+	}
+	return task, nil
+}
+
 // teardownCNIForSandbox is a helper that calls teardownCNI with the non-namespaced ctx.
 func (r *Runtime) teardownCNIForSandbox(ctx context.Context, sandboxDir string) error {
 	return teardownCNI(ctx, r.layout, sandboxDir)
@@ -61,8 +61,9 @@ func (r *Runtime) Prune(ctx context.Context, knownInstances []string, dryRun boo
 		}
 
 		item := runtime.PruneItem{
-			Kind: "container",
-			Name: name,
+			Kind:   "container",
+			Name:   name,
+			Detail: runtime.DescribeInstance(labels),
 		}
 
 		if !dryRun {
@@ -105,6 +105,7 @@ func buildInputsChecksum() string {
 		{"entrypoint.py", embeddedEntrypointPy},
 		{"firewall.py", embeddedFirewallPy},
 		{"install-firewall.py", embeddedInstallFirewallPy},
+		{"egress-proxy.py", embeddedEgressProxyPy},
 		{"sandbox-setup.py", embeddedSandboxSetup},
 		{"setup_helpers.py", embeddedSetupHelpers},
 		{"tmux_io.py", embeddedTmuxIO},
@@ -112,6 +113,8 @@ func buildInputsChecksum() string {
 		{"diagnose-idle.sh", embeddedDiagnoseIdle},
 		{"agent-run.sh", embeddedAgentRun},
 		{"yoloai-resume", embeddedYoloaiResume},
+		{"yoloai-diffwatch", embeddedYoloaiDiffwatch},
+		{"test-agent.py", embeddedTestAgent},
 		{"tmux.conf", embeddedTmuxConf},
 	}
 	for _, f := range files {
@@ -242,6 +245,7 @@ func createBuildContext() (io.Reader, error) {
 		{"entrypoint.py", embeddedEntrypointPy},
 		{"firewall.py", embeddedFirewallPy},
 		{"install-firewall.py", embeddedInstallFirewallPy},
+		{"egress-proxy.py", embeddedEgressProxyPy},
 		{"sandbox-setup.py", embeddedSandboxSetup},
 		{"setup_helpers.py", embeddedSetupHelpers},
 		{"tmux_io.py", embeddedTmuxIO},
@@ -249,6 +253,8 @@ func createBuildContext() (io.Reader, error) {
 		{"diagnose-idle.sh", embeddedDiagnoseIdle},
 		{"agent-run.sh", embeddedAgentRun},
 		{"yoloai-resume", embeddedYoloaiResume},
+		{"yoloai-diffwatch", embeddedYoloaiDiffwatch},
+		{"test-agent.py", embeddedTestAgent},
 		{"tmux.conf", embeddedTmuxConf},
 	}
 
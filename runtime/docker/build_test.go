@@ -65,6 +65,7 @@ func TestCreateBuildContext(t *testing.T) {
 	assert.Contains(t, found, "entrypoint.py")
 	assert.Contains(t, found, "firewall.py")
 	assert.Contains(t, found, "install-firewall.py")
+	assert.Contains(t, found, "egress-proxy.py")
 	assert.Contains(t, found, "sandbox-setup.py")
 	assert.Contains(t, found, "setup_helpers.py")
 	assert.Contains(t, found, "tmux_io.py")
@@ -72,8 +73,10 @@ func TestCreateBuildContext(t *testing.T) {
 	assert.Contains(t, found, "diagnose-idle.sh")
 	assert.Contains(t, found, "agent-run.sh")
 	assert.Contains(t, found, "yoloai-resume")
+	assert.Contains(t, found, "yoloai-diffwatch")
+	assert.Contains(t, found, "test-agent.py")
 	assert.Contains(t, found, "tmux.conf")
-	assert.Len(t, found, 13)
+	assert.Len(t, found, 16)
 }
 
 func TestCreateProfileBuildContext(t *testing.T) {
@@ -55,6 +55,7 @@ var descriptor = runtime.BackendDescriptor{
 		// because the guest gets no routable IPv6 — not a guarantee this makes.
 		NetworkIsolation:     true,
 		CapAdd:               true,
+		FakeTime:             true,
 		HostFilesystem:       false,
 		FilesystemLocality:   runtime.LocalityHostSide,
 		GitExecInConfinement: true, // copy-mode work-copy git runs in-container (audit C1)
@@ -135,6 +136,11 @@ type Runtime struct {
 	// construction (OrbStack, Docker Desktop, …). Used only for the "you may have
 	// switched Docker providers" hint on not-found; empty for podman.
 	providerNames []string
+
+	// inspects caches Inspect results once CacheInspects turns it on; nil
+	// (the default) inspects every time. stopInspects ends its event watch.
+	inspects     *inspectCache
+	stopInspects context.CancelFunc
 }
 
 // Compile-time check.
@@ -145,6 +151,11 @@ var _ runtime.CachePruner = (*Runtime)(nil)
 var _ runtime.InteractiveSession = (*Runtime)(nil)
 var _ runtime.DiskUsageReporter = (*Runtime)(nil)
 var _ runtime.RecreateAdvisor = (*Runtime)(nil)
+var _ runtime.Pauser = (*Runtime)(nil)
+var _ runtime.ImageCommitter = (*Runtime)(nil)
+var _ runtime.LabelReader = (*Runtime)(nil)
+var _ runtime.LogLineReader = (*Runtime)(nil)
+var _ runtime.UsageReporter = (*Runtime)(nil)
 
 // New creates a Runtime and verifies the Docker daemon is reachable. layout
 // carries the threaded environment snapshot; the daemon socket and TLS settings
@@ -575,11 +586,21 @@ func (r *Runtime) Create(ctx context.Context, cfg runtime.InstanceConfig) error
 			hostConfig.Memory = cfg.Resources.Memory
 		}
 	}
+	quota := cfg.Resources != nil && cfg.Resources.DiskBytes > 0 &&
+		r.applyDiskQuota(ctx, hostConfig, cfg.Resources.DiskBytes)
 
 	// Pre-clear any stale container with this name from a previous failed run.
 	_ = r.client.ContainerRemove(ctx, cfg.Name, container.RemoveOptions{Force: true})
 
 	_, err := r.client.ContainerCreate(ctx, containerConfig, hostConfig, &network.NetworkingConfig{}, nil, cfg.Name)
+	if err != nil && quota && isStorageOptRefusal(err) {
+		// overlay2 on xfs needs the pquota mount option, which docker info
+		// doesn't report: retry without the quota rather than fail the create.
+		slog.Warn("daemon refused the container disk quota; creating without it",
+			"event", "docker.disk_quota.refused", "container", cfg.Name, "error", err)
+		hostConfig.StorageOpt = nil
+		_, err = r.client.ContainerCreate(ctx, containerConfig, hostConfig, &network.NetworkingConfig{}, nil, cfg.Name)
+	}
 	if err != nil {
 		return fmt.Errorf("create container: %w", err)
 	}
@@ -634,6 +655,109 @@ func (r *Runtime) Rename(ctx context.Context, oldName, newName string) error {
 	return nil
 }
 
+// Pause freezes every process in a running container through the cgroup
+// freezer (runtime.Pauser). Memory stays resident, so the agent resumes
+// exactly where it was on Unpause. Podman inherits this by embedding.
+func (r *Runtime) Pause(ctx context.Context, name string) error {
+	if err := r.client.ContainerPause(ctx, name); err != nil {
+		if cerrdefs.IsNotFound(err) {
+			return runtime.ErrNotFound
+		}
+		if cerrdefs.IsConflict(err) {
+			return runtime.ErrNotRunning
+		}
+		return fmt.Errorf("pause container: %w", err)
+	}
+	return nil
+}
+
+// Unpause thaws a container frozen by Pause (runtime.Pauser).
+func (r *Runtime) Unpause(ctx context.Context, name string) error {
+	if err := r.client.ContainerUnpause(ctx, name); err != nil {
+		if cerrdefs.IsNotFound(err) {
+			return runtime.ErrNotFound
+		}
+		return fmt.Errorf("unpause container: %w", err)
+	}
+	return nil
+}
+
+// CommitImage saves a container's filesystem as imageRef (runtime.ImageCommitter),
+// pausing it for the commit so a running agent can't leave a half-written file
+// in the image. Mounts are not part of the container's filesystem and are left
+// out. The image is stamped with snapshotLabel: it inherits managedLabel from
+// the base image, and without the mark `prune --images` would take it for a
+// yoloai build and delete the user's saved environment. The sandbox's own
+// environment (firewall and broker wiring, faked clock, locale) is reset to
+// the image's, see committedEnvChanges. Podman inherits this by embedding.
+func (r *Runtime) CommitImage(ctx context.Context, name, imageRef string) error {
+	info, err := r.client.ContainerInspect(ctx, name)
+	if err != nil {
+		if cerrdefs.IsNotFound(err) {
+			return runtime.ErrNotFound
+		}
+		return fmt.Errorf("inspect container: %w", err)
+	}
+	var containerEnv, imageEnv []string
+	if info.Config != nil {
+		containerEnv = info.Config.Env
+	}
+	img, err := r.client.ImageInspect(ctx, info.Image)
+	if err != nil {
+		return fmt.Errorf("inspect image of %s: %w", name, err)
+	}
+	if img.Config != nil {
+		imageEnv = img.Config.Env
+	}
+
+	changes := []string{fmt.Sprintf("LABEL %s=%q", snapshotLabel, name)}
+	changes = append(changes, committedEnvChanges(containerEnv, imageEnv)...)
+	_, err = r.client.ContainerCommit(ctx, name, container.CommitOptions{
+		Reference: imageRef,
+		Comment:   "yoloai commit " + name,
+		Changes:   changes,
+		Pause:     true,
+	})
+	if err != nil {
+		if cerrdefs.IsNotFound(err) {
+			return runtime.ErrNotFound
+		}
+		return fmt.Errorf("commit container %s to %s: %w", name, imageRef, err)
+	}
+	return nil
+}
+
+// committedEnvChanges returns the ENV changes that give a committed image the
+// environment of the image its container was created from. A commit keeps the
+// container's environment, which holds what launch set for that one sandbox:
+// YOLOAI_FIREWALL_EXTERNAL would make a sandbox created from the image skip its
+// own firewall, and the broker endpoint, faked clock and locale belong to the
+// source sandbox. The daemon merges a commit's config into the container's by
+// key, so a variable can't be dropped; one the image lacks is set empty, which
+// every reader treats as unset.
+func committedEnvChanges(containerEnv, imageEnv []string) []string {
+	base := make(map[string]string, len(imageEnv))
+	for _, kv := range imageEnv {
+		k, v, _ := strings.Cut(kv, "=")
+		base[k] = v
+	}
+	var changes []string
+	for _, kv := range containerEnv {
+		k, v, _ := strings.Cut(kv, "=")
+		want, inImage := base[k]
+		if inImage && v == want {
+			continue
+		}
+		changes = append(changes, fmt.Sprintf("ENV %s=%s", k, strconv.Quote(want)))
+	}
+	return changes
+}
+
+// HasImage reports whether imageRef exists locally (runtime.ImageCommitter).
+func (r *Runtime) HasImage(ctx context.Context, imageRef string) (bool, error) {
+	return r.imageExists(ctx, imageRef)
+}
+
 // Remove removes a Docker container. Returns nil if already removed.
 func (r *Runtime) Remove(ctx context.Context, name string) error {
 	if err := r.client.ContainerRemove(ctx, name, container.RemoveOptions{Force: true}); err != nil {
@@ -674,8 +798,13 @@ func (r *Runtime) ensureDindVolumeMount(ctx context.Context, cfg runtime.Instanc
 	return mount.Mount{Type: mount.TypeVolume, Source: volName, Target: "/var/lib/docker"}, nil
 }
 
-// Inspect returns the state of a Docker container.
+// Inspect returns the state of a Docker container, from the inspect cache
+// when CacheInspects has turned it on and it holds a current answer.
 func (r *Runtime) Inspect(ctx context.Context, name string) (runtime.InstanceInfo, error) {
+	if cached, ok := r.inspects.get(name); ok {
+		return cached, nil
+	}
+	gen := r.inspects.generation()
 	info, err := r.client.ContainerInspect(ctx, name)
 	if err != nil {
 		if cerrdefs.IsNotFound(err) {
@@ -684,9 +813,27 @@ func (r *Runtime) Inspect(ctx context.Context, name string) (runtime.InstanceInf
 		return runtime.InstanceInfo{}, fmt.Errorf("inspect container: %w", err)
 	}
 
-	return runtime.InstanceInfo{
+	result := runtime.InstanceInfo{
 		Running: info.State.Running,
-	}, nil
+		Paused:  info.State.Paused,
+	}
+	r.inspects.put(name, gen, result)
+	return result, nil
+}
+
+// InstanceLabels returns the labels the container was created with.
+func (r *Runtime) InstanceLabels(ctx context.Context, name string) (map[string]string, error) {
+	info, err := r.client.ContainerInspect(ctx, name)
+	if err != nil {
+		if cerrdefs.IsNotFound(err) {
+			return nil, r.notFound()
+		}
+		return nil, fmt.Errorf("inspect container: %w", err)
+	}
+	if info.Config == nil {
+		return nil, nil
+	}
+	return info.Config.Labels, nil
 }
 
 // Exec runs a command inside a running Docker container and returns the result.
@@ -942,6 +1089,9 @@ func (r *Runtime) forwardExecResizes(ctx context.Context, execID string, resize
 
 // Close releases the Docker client connection.
 func (r *Runtime) Close() error {
+	if r.stopInspects != nil {
+		r.stopInspects()
+	}
 	return r.client.Close()
 }
 
@@ -962,6 +1112,54 @@ func (r *Runtime) Logs(ctx context.Context, name string, tail int) string {
 	return strings.TrimSpace(buf.String())
 }
 
+// LogLines returns the container's stdout+stderr lines recorded after since
+// (all of them when since is zero), each with the timestamp the daemon stamped
+// on it.
+func (r *Runtime) LogLines(ctx context.Context, name string, since time.Time) ([]runtime.LogLine, error) {
+	opts := container.LogsOptions{ShowStdout: true, ShowStderr: true, Timestamps: true}
+	if !since.IsZero() {
+		opts.Since = since.Format(time.RFC3339Nano)
+	}
+	out, err := r.client.ContainerLogs(ctx, name, opts)
+	if err != nil {
+		if cerrdefs.IsNotFound(err) {
+			return nil, r.notFound()
+		}
+		return nil, fmt.Errorf("container logs: %w", err)
+	}
+	defer out.Close() //nolint:errcheck // best-effort close
+	var buf bytes.Buffer
+	if _, err := stdcopy.StdCopy(&buf, &buf, out); err != nil {
+		return nil, fmt.Errorf("read container logs: %w", err)
+	}
+	return parseTimestampedLogs(buf.String(), since), nil
+}
+
+// parseTimestampedLogs splits `docker logs --timestamps` output into lines.
+// The daemon's since filter is inclusive and second-granular on some engines,
+// so lines not strictly after since are dropped here; a line without a
+// parseable stamp is kept with the previous line's time.
+func parseTimestampedLogs(s string, since time.Time) []runtime.LogLine {
+	var lines []runtime.LogLine
+	var last time.Time
+	for raw := range strings.SplitSeq(strings.TrimRight(s, "\n"), "\n") {
+		if raw == "" {
+			continue
+		}
+		text := raw
+		if stamp, rest, ok := strings.Cut(raw, " "); ok {
+			if t, err := time.Parse(time.RFC3339Nano, stamp); err == nil {
+				last, text = t, rest
+			}
+		}
+		if !since.IsZero() && !last.After(since) {
+			continue
+		}
+		lines = append(lines, runtime.LogLine{Time: last, Text: strings.TrimRight(text, "\r")})
+	}
+	return lines
+}
+
 // DiagHint returns a backend-specific hint for checking logs.
 func (r *Runtime) DiagHint(instanceName string) string {
 	return fmt.Sprintf("run '%s logs %s' to see what went wrong", r.binaryName, instanceName)
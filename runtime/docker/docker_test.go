@@ -1,10 +1,12 @@
 // ABOUTME: Docker Runtime unit tests: mount/port SDK conversion, per-mode
 // ABOUTME: RequiredCapabilities gating (runc floor, gVisor), descriptor/probe
-// ABOUTME: behavior, and the image-presence confirm-by-list retry/backoff.
+// ABOUTME: behavior, the image-presence confirm-by-list retry/backoff, the disk-quota
+// ABOUTME: storage-driver gate, and the env reset of a committed image.
 package docker
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	goruntime "runtime"
 	"testing"
@@ -287,3 +289,59 @@ func TestConfirmImagePresentByList_ContextCancelledDuringBackoff(t *testing.T) {
 	require.ErrorIs(t, err, context.Canceled)
 	assert.False(t, present)
 }
+
+func TestStorageQuotaSupported(t *testing.T) {
+	xfs := [][2]string{{"Backing Filesystem", "xfs"}, {"Supports d_type", "true"}}
+	ext4 := [][2]string{{"Backing Filesystem", "extfs"}}
+	assert.True(t, storageQuotaSupported("overlay2", xfs))
+	assert.False(t, storageQuotaSupported("overlay2", ext4))
+	assert.False(t, storageQuotaSupported("overlay2", nil))
+	assert.True(t, storageQuotaSupported("btrfs", nil))
+	assert.True(t, storageQuotaSupported("zfs", nil))
+	assert.False(t, storageQuotaSupported("vfs", nil))
+}
+
+func TestIsStorageOptRefusal(t *testing.T) {
+	assert.True(t, isStorageOptRefusal(errors.New("Error response from daemon: --storage-opt is supported only for overlay over xfs with 'pquota' mount option")))
+	assert.False(t, isStorageOptRefusal(errors.New("No such image: yoloai-base")))
+}
+
+func TestCommittedEnvChanges_ResetsSandboxEnv(t *testing.T) {
+	imageEnv := []string{"PATH=/usr/local/bin:/usr/bin", "LANG=C.UTF-8"}
+	containerEnv := []string{
+		"PATH=/usr/local/bin:/usr/bin",
+		"LANG=de_DE.UTF-8",
+		"TZ=Europe/Berlin",
+		"LD_PRELOAD=/usr/local/lib/libfaketime.so.1",
+		"FAKETIME=@2020-01-01 00:00:00",
+		"YOLOAI_BROKER_INJECTOR_ENDPOINT=host.docker.internal:41234",
+		"YOLOAI_FIREWALL_EXTERNAL=1",
+	}
+	assert.Equal(t, []string{
+		`ENV LANG="C.UTF-8"`,
+		`ENV TZ=""`,
+		`ENV LD_PRELOAD=""`,
+		`ENV FAKETIME=""`,
+		`ENV YOLOAI_BROKER_INJECTOR_ENDPOINT=""`,
+		`ENV YOLOAI_FIREWALL_EXTERNAL=""`,
+	}, committedEnvChanges(containerEnv, imageEnv))
+
+	assert.Empty(t, committedEnvChanges(imageEnv, imageEnv))
+}
+
+func TestParseTimestampedLogs(t *testing.T) {
+	out := "2026-03-15T10:00:00.5Z first\r\n" +
+		"2026-03-15T10:00:01Z second line\n" +
+		"continued without a stamp\n" +
+		"\n"
+	lines := parseTimestampedLogs(out, time.Time{})
+	require.Len(t, lines, 3)
+	assert.Equal(t, "first", lines[0].Text)
+	assert.Equal(t, time.Date(2026, 3, 15, 10, 0, 0, 5e8, time.UTC), lines[0].Time)
+	assert.Equal(t, "second line", lines[1].Text)
+	assert.Equal(t, lines[1].Time, lines[2].Time, "an unstamped line keeps the previous time")
+
+	// The daemon's since is inclusive; only lines strictly after it are kept.
+	since := time.Date(2026, 3, 15, 10, 0, 0, 5e8, time.UTC)
+	assert.Len(t, parseTimestampedLogs(out, since), 2)
+}
@@ -0,0 +1,165 @@
+// ABOUTME: Inspect cache (runtime.InspectCacher): short-lived Inspect results kept
+// ABOUTME: honest by the daemon's container events, for processes that list repeatedly.
+package docker
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/docker/docker/api/types/events"
+	"github.com/docker/docker/api/types/filters"
+
+	"github.com/kstenerud/yoloai/runtime"
+)
+
+// eventsRetryDelay is how long the event watch waits before subscribing
+// again after the stream ends (the daemon restarted, the socket went away).
+// Inspect goes to the daemon every time in between.
+const eventsRetryDelay = 5 * time.Second
+
+// stateActions are the container events that can change what Inspect
+// reports. Exec events, which every status probe causes, are left out so the
+// probes don't keep emptying the cache they are meant to benefit from.
+var stateActions = []events.Action{
+	events.ActionCreate, events.ActionStart, events.ActionRestart, events.ActionDie,
+	events.ActionStop, events.ActionPause, events.ActionUnPause, events.ActionDestroy,
+	events.ActionRename,
+}
+
+// CacheInspects turns on the inspect cache (runtime.InspectCacher): Inspect
+// answers from a result up to ttl old, and a watch on the daemon's container
+// events drops a container's entry when its state changes. Entries are kept
+// only while the event stream is up, so a missed event can't leave a stale
+// answer behind for longer than the ttl. Podman inherits this by embedding.
+func (r *Runtime) CacheInspects(ctx context.Context, ttl time.Duration) {
+	if r.inspects != nil || ttl <= 0 {
+		return
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	r.inspects = newInspectCache(ttl)
+	r.stopInspects = cancel
+	go r.watchStateEvents(ctx, r.inspects)
+}
+
+// watchStateEvents feeds container state events into c until ctx is
+// cancelled, subscribing again after eventsRetryDelay whenever the stream
+// ends.
+func (r *Runtime) watchStateEvents(ctx context.Context, c *inspectCache) {
+	args := filters.NewArgs(filters.Arg("type", string(events.ContainerEventType)))
+	for _, action := range stateActions {
+		args.Add("event", string(action))
+	}
+	for {
+		msgs, errs := r.client.Events(ctx, events.ListOptions{Filters: args})
+		c.setLive(true)
+		err := drainStateEvents(c, msgs, errs)
+		c.setLive(false)
+		if ctx.Err() != nil {
+			return
+		}
+		slog.Debug("docker event stream ended; inspecting uncached until it is back", "binary", r.binaryName, "error", err)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(eventsRetryDelay):
+		}
+	}
+}
+
+// drainStateEvents forgets the container each event names until the stream
+// reports why it ended.
+func drainStateEvents(c *inspectCache, msgs <-chan events.Message, errs <-chan error) error {
+	for {
+		select {
+		case msg := <-msgs:
+			c.forget(msg.Actor.ID, msg.Actor.Attributes["name"], msg.Actor.Attributes["oldName"])
+		case err := <-errs:
+			return err
+		}
+	}
+}
+
+// inspectCache holds Inspect results by the name they were asked for. Its
+// methods are safe on a nil cache, which caches nothing.
+type inspectCache struct {
+	ttl time.Duration
+	now func() time.Time
+
+	mu sync.Mutex
+	// live reports that the event stream is up; nothing is kept while it isn't.
+	live bool
+	// gen moves on every invalidation, so an Inspect that was in flight when a
+	// container changed doesn't store what it read before the change.
+	gen     uint64
+	entries map[string]inspectEntry
+}
+
+type inspectEntry struct {
+	info runtime.InstanceInfo
+	at   time.Time
+}
+
+func newInspectCache(ttl time.Duration) *inspectCache {
+	return &inspectCache{ttl: ttl, now: time.Now, entries: map[string]inspectEntry{}}
+}
+
+// get returns name's cached result if it is younger than the ttl.
+func (c *inspectCache) get(name string) (runtime.InstanceInfo, bool) {
+	if c == nil {
+		return runtime.InstanceInfo{}, false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.entries[name]
+	if !ok || c.now().Sub(e.at) >= c.ttl {
+		return runtime.InstanceInfo{}, false
+	}
+	return e.info, true
+}
+
+// generation is read before asking the daemon and handed back to put.
+func (c *inspectCache) generation() uint64 {
+	if c == nil {
+		return 0
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.gen
+}
+
+// put stores name's result unless the cache was invalidated since gen was
+// read or the event stream is down.
+func (c *inspectCache) put(name string, gen uint64, info runtime.InstanceInfo) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.live || gen != c.gen {
+		return
+	}
+	c.entries[name] = inspectEntry{info: info, at: c.now()}
+}
+
+// forget drops the entries for keys, a container's ID and names; an Inspect
+// may have asked by either.
+func (c *inspectCache) forget(keys ...string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.gen++
+	for _, k := range keys {
+		delete(c.entries, k)
+	}
+}
+
+// setLive records whether the event stream is up. Either way the cache
+// starts over: entries from before an outage may have missed their events.
+func (c *inspectCache) setLive(live bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.live = live
+	c.gen++
+	clear(c.entries)
+}
@@ -0,0 +1,90 @@
+// ABOUTME: Tests for inspectCache: ttl expiry, invalidation by container event,
+// ABOUTME: and refusing to keep anything while the event stream is down.
+package docker
+
+import (
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types/events"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kstenerud/yoloai/runtime"
+)
+
+// liveCache returns a cache whose event stream is up and whose clock the
+// test moves by hand.
+func liveCache(ttl time.Duration) (*inspectCache, *time.Time) {
+	now := time.Unix(1_700_000_000, 0)
+	c := newInspectCache(ttl)
+	c.now = func() time.Time { return now }
+	c.setLive(true)
+	return c, &now
+}
+
+func TestInspectCache_ServesUntilTTL(t *testing.T) {
+	c, now := liveCache(10 * time.Second)
+	running := runtime.InstanceInfo{Running: true}
+	c.put("yoloai-cli-a", c.generation(), running)
+
+	got, ok := c.get("yoloai-cli-a")
+	assert.True(t, ok)
+	assert.Equal(t, running, got)
+
+	*now = now.Add(10 * time.Second)
+	_, ok = c.get("yoloai-cli-a")
+	assert.False(t, ok, "an entry as old as the ttl is not served")
+}
+
+func TestInspectCache_EventForgetsContainer(t *testing.T) {
+	c, _ := liveCache(time.Minute)
+	c.put("yoloai-cli-a", c.generation(), runtime.InstanceInfo{Running: true})
+	c.put("yoloai-cli-b", c.generation(), runtime.InstanceInfo{Running: true})
+
+	msgs := make(chan events.Message, 1)
+	errs := make(chan error, 1)
+	msgs <- events.Message{Action: events.ActionDie, Actor: events.Actor{ID: "abc123", Attributes: map[string]string{"name": "yoloai-cli-a"}}}
+	go func() {
+		// Let the message through first, then end the stream.
+		for len(msgs) > 0 {
+			time.Sleep(time.Millisecond)
+		}
+		errs <- assert.AnError
+	}()
+	assert.ErrorIs(t, drainStateEvents(c, msgs, errs), assert.AnError)
+
+	_, ok := c.get("yoloai-cli-a")
+	assert.False(t, ok, "the container that died is inspected afresh")
+	_, ok = c.get("yoloai-cli-b")
+	assert.True(t, ok, "other containers keep their entries")
+}
+
+func TestInspectCache_InFlightInspectLosesToEvent(t *testing.T) {
+	c, _ := liveCache(time.Minute)
+	gen := c.generation()
+	c.forget("", "yoloai-cli-a") // the container stops while its Inspect is in flight
+	c.put("yoloai-cli-a", gen, runtime.InstanceInfo{Running: true})
+
+	_, ok := c.get("yoloai-cli-a")
+	assert.False(t, ok, "a result read before the change isn't stored")
+}
+
+func TestInspectCache_NothingKeptWhileStreamDown(t *testing.T) {
+	c, _ := liveCache(time.Minute)
+	c.put("yoloai-cli-a", c.generation(), runtime.InstanceInfo{Running: true})
+
+	c.setLive(false)
+	_, ok := c.get("yoloai-cli-a")
+	assert.False(t, ok, "losing the stream drops what was cached")
+
+	c.put("yoloai-cli-a", c.generation(), runtime.InstanceInfo{Running: true})
+	_, ok = c.get("yoloai-cli-a")
+	assert.False(t, ok, "and nothing is cached until it is back")
+}
+
+func TestInspectCache_NilCachesNothing(t *testing.T) {
+	var c *inspectCache
+	c.put("yoloai-cli-a", c.generation(), runtime.InstanceInfo{Running: true})
+	_, ok := c.get("yoloai-cli-a")
+	assert.False(t, ok)
+}
@@ -26,6 +26,11 @@ import (
 // does MUST stamp them with this label.
 const managedLabel = "com.yoloai.managed"
 
+// snapshotLabel marks an image saved from a sandbox by `yoloai commit`, naming
+// the container it came from. Such an image inherits managedLabel from the base
+// image but is the user's, so the --images sweep leaves it alone.
+const snapshotLabel = "com.yoloai.snapshot"
+
 // Prune implements runtime.Backend.
 func (r *Runtime) Prune(ctx context.Context, knownInstances []string, dryRun bool, output io.Writer) (runtime.PruneResult, error) {
 	known := make(map[string]bool, len(knownInstances))
@@ -75,7 +80,7 @@ func (r *Runtime) pruneContainers(ctx context.Context, known map[string]bool, dr
 		if !dryRun && !r.removeContainer(ctx, name, output) {
 			continue
 		}
-		items = append(items, runtime.PruneItem{Kind: "container", Name: name})
+		items = append(items, runtime.PruneItem{Kind: "container", Name: name, Detail: runtime.DescribeInstance(c.Labels)})
 	}
 	return items, nil
 }
@@ -188,8 +193,9 @@ type managedImageCandidate struct {
 }
 
 // managedImageCandidates selects the unused yoloai images from a full image
-// list: not referenced by any container, and carrying managedLabel or (bridge)
-// a bare-local yoloai- name. Pure so the selection is testable without a
+// list: not referenced by any container, not saved by `yoloai commit`
+// (snapshotLabel), and carrying managedLabel or (bridge) a bare-local yoloai-
+// name. Pure so the selection is testable without a
 // daemon.
 func managedImageCandidates(imgs []image.Summary, inUse map[string]bool) []managedImageCandidate {
 	var out []managedImageCandidate
@@ -197,6 +203,9 @@ func managedImageCandidates(imgs []image.Summary, inUse map[string]bool) []manag
 		if inUse[img.ID] {
 			continue
 		}
+		if _, saved := img.Labels[snapshotLabel]; saved {
+			continue
+		}
 		_, labeled := img.Labels[managedLabel]
 		var named []string
 		for _, t := range img.RepoTags {
@@ -257,8 +257,8 @@ func TestYoloaiImageName(t *testing.T) {
 
 // Selection contract for the scoped --images sweep: an unused image is a
 // candidate iff it carries the managed label OR (deprecated bridge) a
-// bare-local yoloai- name; anything in use, and every foreign image, is
-// spared. nameOnly marks bridge matches for the settling-period log line.
+// bare-local yoloai- name; anything in use, every foreign image, and every
+// image saved by `yoloai commit` is spared. nameOnly marks bridge matches for the settling-period log line.
 func TestManagedImageCandidates(t *testing.T) {
 	managed := map[string]string{managedLabel: "true"}
 	imgs := []image.Summary{
@@ -269,6 +269,8 @@ func TestManagedImageCandidates(t *testing.T) {
 		{ID: "sha256:eee", RepoTags: []string{"yoloai-base:old"}, Labels: managed},   // in use: spared
 		{ID: "sha256:fff", Labels: managed},                                          // labeled dangling: reclaimed
 		{ID: "sha256:ggg", RepoTags: []string{"alpine:edge", "yoloai-retag:latest"}}, // foreign re-tagged with our name
+		{ID: "sha256:hhh", RepoTags: []string{"my-env:v1"}, // saved by `yoloai commit`: spared
+			Labels: map[string]string{managedLabel: "true", snapshotLabel: "yoloai-cli-setup"}},
 	}
 	inUse := map[string]bool{"sha256:eee": true}
 
@@ -292,6 +294,7 @@ func TestManagedImageCandidates(t *testing.T) {
 		"a renamed derived image displays its own tag")
 	assert.NotContains(t, byID, "sha256:ddd", "foreign unused image must be spared")
 	assert.NotContains(t, byID, "sha256:eee", "in-use image must be spared")
+	assert.NotContains(t, byID, "sha256:hhh", "an image saved by yoloai commit is the user's")
 	assert.Equal(t, "fff", byID["sha256:fff"].display, "untagged image displays its short ID")
 	assert.Equal(t, []string{"yoloai-retag:latest"}, byID["sha256:ggg"].removeRefs,
 		"only the yoloai tag is removed from a re-tagged foreign image; its own tag and the image survive")
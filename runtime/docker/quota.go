@@ -0,0 +1,59 @@
+// ABOUTME: Container disk quota (resources.disk) via storage-opt size, applied
+// ABOUTME: only on storage drivers that can enforce a per-container quota.
+package docker
+
+import (
+	"context"
+	"log/slog"
+	"strconv"
+	"strings"
+
+	"github.com/docker/docker/api/types/container"
+)
+
+// storageQuotaSupported reports whether a daemon with this storage driver
+// (docker info's Driver and DriverStatus) can enforce storage-opt size.
+// overlay2 can only on an xfs backing filesystem, and even there only when it
+// is mounted with pquota — which docker info doesn't say, so Create still
+// falls back when the daemon refuses the option.
+func storageQuotaSupported(driver string, driverStatus [][2]string) bool {
+	switch driver {
+	case "btrfs", "zfs", "devicemapper", "windowsfilter":
+		return true
+	case "overlay2":
+		for _, kv := range driverStatus {
+			if kv[0] == "Backing Filesystem" {
+				return kv[1] == "xfs"
+			}
+		}
+	}
+	return false
+}
+
+// applyDiskQuota sets the container's writable-layer quota when the daemon's
+// storage driver supports one, and reports whether it did. Otherwise the
+// quota is skipped: the orchestrator's own check on the sandbox directory
+// still holds, and a create that fails over an unenforceable option would
+// make resources.disk unusable on most hosts.
+func (r *Runtime) applyDiskQuota(ctx context.Context, hostConfig *container.HostConfig, diskBytes int64) bool {
+	info, err := r.client.Info(ctx)
+	if err != nil {
+		slog.Debug("docker info failed; skipping container disk quota", "event", "docker.disk_quota.skip", "error", err)
+		return false
+	}
+	if !storageQuotaSupported(info.Driver, info.DriverStatus) {
+		slog.Info("storage driver has no per-container quota; resources.disk caps the sandbox directory only",
+			"event", "docker.disk_quota.unsupported", "driver", info.Driver)
+		return false
+	}
+	hostConfig.StorageOpt = map[string]string{"size": strconv.FormatInt(diskBytes, 10)}
+	return true
+}
+
+// isStorageOptRefusal reports whether a container-create error is the daemon
+// rejecting storage-opt, e.g. "--storage-opt is supported only for overlay over
+// xfs with 'pquota' mount option".
+func isStorageOptRefusal(err error) bool {
+	msg := strings.ToLower(err.Error())
+	return strings.Contains(msg, "storage-opt") || strings.Contains(msg, "storage opt") || strings.Contains(msg, "quota")
+}
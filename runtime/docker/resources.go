@@ -26,6 +26,9 @@ var embeddedFirewallPy []byte
 //go:embed resources/install-firewall.py
 var embeddedInstallFirewallPy []byte
 
+//go:embed resources/egress-proxy.py
+var embeddedEgressProxyPy []byte
+
 // embeddedTmuxConf is the shared default tmux.conf, sourced from the neutral
 // internal/resources/tmux package rather than re-embedded here.
 var embeddedTmuxConf = tmuxres.Embedded()
@@ -57,3 +60,12 @@ var embeddedAgentRun = monitor.AgentRunScript()
 // embeddedYoloaiResume provides the in-sandbox resume command (D96 DD4),
 // installed executable in /yoloai/bin as `yoloai-resume`.
 var embeddedYoloaiResume = monitor.YoloaiResumeScript()
+
+// embeddedYoloaiDiffwatch provides the in-sandbox live diff the tmux menu
+// opens in a side pane, installed executable in /yoloai/bin as
+// `yoloai-diffwatch`.
+var embeddedYoloaiDiffwatch = monitor.YoloaiDiffwatchScript()
+
+// embeddedTestAgent provides the built-in `test` agent (a shell, or a scripted
+// fake agent playing a scenario file), installed in /yoloai/bin.
+var embeddedTestAgent = monitor.TestAgentScript()
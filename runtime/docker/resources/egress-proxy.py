@@ -0,0 +1,199 @@
+#!/usr/bin/env python3
+# ABOUTME: Egress audit proxy for network-isolated sandboxes — a loopback HTTP(S)
+# ABOUTME: forward proxy that records each destination the agent asks for in logs/egress.jsonl.
+"""Egress audit proxy.
+
+sandbox-setup.py starts this in a network-isolated container and points the
+agent's HTTP_PROXY/HTTPS_PROXY at it. Each request is one line in the log:
+the destination, and whether the firewall let the connection through.
+
+The proxy is for evidence, not enforcement. Its own upstream connections go
+through the same iptables allowlist as everything else in the container, so
+it can't widen what the sandbox reaches: a destination the firewall rejects
+is logged as "blocked" and answered with 403. A program that ignores the
+proxy variables isn't recorded, but is still held by the firewall.
+
+Usage: egress-proxy.py <log-path> [<port>]
+"""
+
+from __future__ import annotations
+
+import asyncio
+import datetime
+import errno
+import json
+import sys
+from typing import TextIO
+
+LISTEN_HOST = "127.0.0.1"
+DEFAULT_PORT = 3128
+# How long an upstream connect may take before the request is logged as failed.
+CONNECT_TIMEOUT = 15.0
+# Cap on a request's head (request line plus headers).
+MAX_HEAD_BYTES = 64 * 1024
+PIPE_CHUNK = 64 * 1024
+
+# Verdicts, as written to the log. Must match store.EgressAllowed & co.
+ALLOWED = "allowed"
+BLOCKED = "blocked"
+FAILED = "failed"
+
+# Errors the firewall's REJECT (icmp-port-unreachable) or a policy drop surfaces as.
+_BLOCKED_ERRNOS = {errno.ECONNREFUSED, errno.EHOSTUNREACH, errno.ENETUNREACH,
+                   errno.EACCES, errno.EPERM}
+
+
+def split_host_port(authority: str, default_port: int) -> tuple[str, int]:
+    """Split "host", "host:port" or "[v6]:port"; raises ValueError if malformed."""
+    if authority.startswith("["):
+        end = authority.find("]")
+        if end < 0:
+            raise ValueError(f"malformed authority {authority!r}")
+        host, rest = authority[1:end], authority[end + 1:]
+        port = int(rest[1:]) if rest.startswith(":") else default_port
+    elif authority.count(":") == 1:
+        host, _, port_str = authority.partition(":")
+        port = int(port_str)
+    else:
+        host, port = authority, default_port
+    if not host or not 0 < port < 65536:
+        raise ValueError(f"malformed authority {authority!r}")
+    return host, port
+
+
+def parse_target(method: str, target: str) -> tuple[str, int, str]:
+    """Return (host, port, origin-form path) for a proxy request's target.
+
+    CONNECT takes an authority (the path is ""); anything else must be an
+    absolute http:// URI, since https goes through CONNECT.
+    """
+    if method == "CONNECT":
+        host, port = split_host_port(target, 443)
+        return host, port, ""
+    if not target.lower().startswith("http://"):
+        raise ValueError(f"not an absolute http URI: {target!r}")
+    authority, _, path = target[len("http://"):].partition("/")
+    host, port = split_host_port(authority, 80)
+    return host, port, "/" + path
+
+
+def verdict_for(err: BaseException | None) -> str:
+    """Classify an upstream connect outcome."""
+    if err is None:
+        return ALLOWED
+    if isinstance(err, OSError) and err.errno in _BLOCKED_ERRNOS:
+        return BLOCKED
+    return FAILED
+
+
+def event_line(now: datetime.datetime, method: str, host: str, port: int,
+               verdict: str, error: str = "") -> str:
+    """Render one log line."""
+    ts = now.strftime("%Y-%m-%dT%H:%M:%S.") + f"{now.microsecond // 1000:03d}Z"
+    entry: dict[str, object] = {"ts": ts, "method": method, "host": host,
+                                "port": port, "verdict": verdict}
+    if error:
+        entry["error"] = error
+    return json.dumps(entry) + "\n"
+
+
+def forward_head(method: str, path: str, version: str, headers: list[str]) -> bytes:
+    """Rewrite a plain-HTTP proxy request's head for the origin server.
+
+    The proxy handles one request per connection, so the upstream is asked to
+    close after answering; hop-by-hop proxy headers are dropped.
+    """
+    skip = ("proxy-connection:", "proxy-authorization:", "connection:", "keep-alive:")
+    kept = [h for h in headers if h and not h.lower().startswith(skip)]
+    lines = [f"{method} {path} {version}", *kept, "Connection: close", "", ""]
+    return "\r\n".join(lines).encode("latin-1")
+
+
+async def _pipe(src: asyncio.StreamReader, dst: asyncio.StreamWriter) -> None:
+    try:
+        while data := await src.read(PIPE_CHUNK):
+            dst.write(data)
+            await dst.drain()
+    except (ConnectionError, OSError):
+        pass
+
+
+async def _respond(writer: asyncio.StreamWriter, status: str) -> None:
+    writer.write(f"HTTP/1.1 {status}\r\nContent-Length: 0\r\nConnection: close\r\n\r\n".encode())
+    try:
+        await writer.drain()
+    except (ConnectionError, OSError):
+        pass
+    writer.close()
+
+
+def _record(log: TextIO, method: str, host: str, port: int, verdict: str, error: str = "") -> None:
+    try:
+        log.write(event_line(datetime.datetime.now(datetime.timezone.utc), method, host, port, verdict, error))
+    except OSError:
+        pass
+
+
+async def handle(reader: asyncio.StreamReader, writer: asyncio.StreamWriter, log: TextIO) -> None:
+    """Serve one proxy connection: log its destination, then tunnel it."""
+    try:
+        head = await reader.readuntil(b"\r\n\r\n")
+    except (asyncio.IncompleteReadError, asyncio.LimitOverrunError, ConnectionError, OSError):
+        writer.close()
+        return
+    lines = head.decode("latin-1").split("\r\n")
+    parts = lines[0].split(" ")
+    if len(parts) != 3:
+        await _respond(writer, "400 Bad Request")
+        return
+    method, target, version = parts
+    try:
+        host, port, path = parse_target(method, target)
+    except ValueError:
+        await _respond(writer, "400 Bad Request")
+        return
+
+    try:
+        up_reader, up_writer = await asyncio.wait_for(
+            asyncio.open_connection(host, port), CONNECT_TIMEOUT)
+    except (OSError, asyncio.TimeoutError) as e:
+        verdict = verdict_for(e)
+        _record(log, method, host, port, verdict, str(e) or type(e).__name__)
+        await _respond(writer, "403 Forbidden" if verdict == BLOCKED else "502 Bad Gateway")
+        return
+    _record(log, method, host, port, ALLOWED)
+
+    if method == "CONNECT":
+        writer.write(b"HTTP/1.1 200 Connection Established\r\n\r\n")
+    else:
+        up_writer.write(forward_head(method, path, version, lines[1:]))
+    tasks = [asyncio.create_task(_pipe(reader, up_writer)),
+             asyncio.create_task(_pipe(up_reader, writer))]
+    _, pending = await asyncio.wait(tasks, return_when=asyncio.FIRST_COMPLETED)
+    for t in pending:
+        t.cancel()
+    up_writer.close()
+    writer.close()
+
+
+async def serve(port: int, log: TextIO) -> None:
+    async def _handle(r: asyncio.StreamReader, w: asyncio.StreamWriter) -> None:
+        await handle(r, w, log)
+
+    server = await asyncio.start_server(_handle, LISTEN_HOST, port, limit=MAX_HEAD_BYTES)
+    async with server:
+        await server.serve_forever()
+
+
+def main(argv: list[str]) -> int:
+    if len(argv) < 2:
+        print(f"Usage: {argv[0]} <log-path> [<port>]", file=sys.stderr)
+        return 2
+    port = int(argv[2]) if len(argv) > 2 else DEFAULT_PORT
+    with open(argv[1], "a", buffering=1) as log:  # line-buffered: one event per line
+        asyncio.run(serve(port, log))
+    return 0
+
+
+if __name__ == "__main__":
+    sys.exit(main(sys.argv))
@@ -121,6 +121,51 @@ def remap_uid(cfg: dict[str, Any], running_as_root: bool) -> None:
              host_gid=int(host_gid) if host_gid else 0)
 
 
+def _normalize_locale(name: str) -> str:
+    """Fold a locale name for comparison: en_US.UTF-8 == en_US.utf8."""
+    return name.lower().replace("-", "")
+
+
+def ensure_locale(running_as_root: bool) -> None:
+    """Compile the sandbox's LANG with localedef if the image lacks it.
+
+    LANG is set on the container from the sandbox's configured (or
+    host-inherited) locale. The image ships only C.UTF-8, so any other locale
+    is generated here, once per container. Failure is logged, not fatal:
+    programs fall back to the C locale.
+    """
+    lang = os.environ.get("LANG", "")
+    if not lang or lang.split(".")[0] in ("C", "POSIX"):
+        return
+    try:
+        available = subprocess.run(["locale", "-a"], capture_output=True, text=True).stdout.split()
+    except OSError:
+        available = []
+    if _normalize_locale(lang) in {_normalize_locale(a) for a in available}:
+        return
+    if not running_as_root:
+        log_error("locale.skip", "cannot compile locale without root", locale=lang)
+        return
+
+    # de_DE.UTF-8@euro → input de_DE@euro, charmap UTF-8.
+    name, _, rest = lang.partition(".")
+    charmap, _, modifier = rest.partition("@")
+    if modifier:
+        name += "@" + modifier
+    if not charmap or _normalize_locale(charmap) == "utf8":
+        charmap = "UTF-8"
+    # -c: write the locale despite warnings (exit status 1), so judge by
+    # whether it is listed afterwards rather than by the exit status.
+    result = subprocess.run(["localedef", "-c", "-i", name, "-f", charmap, lang],
+                            capture_output=True, text=True)
+    after = subprocess.run(["locale", "-a"], capture_output=True, text=True).stdout.split()
+    if _normalize_locale(lang) in {_normalize_locale(a) for a in after}:
+        log_info("locale.generate", "compiled locale", locale=lang)
+    else:
+        log_error("locale.generate_error", "localedef failed", locale=lang,
+                  exit_code=result.returncode, stderr=result.stderr.strip())
+
+
 def read_secrets() -> None:
     """Read secret files from /run/secrets into os.environ."""
     secrets_dir = "/run/secrets"
@@ -273,6 +318,7 @@ def main() -> None:
         keepalive = True
 
     remap_uid(cfg, running_as_root)
+    ensure_locale(running_as_root)
     if not keepalive:
         # In keepalive_only mode the entrypoint must NOT read secrets or write the
         # consumed marker — the launched session-runner (sandbox-setup.py) does
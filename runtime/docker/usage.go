@@ -0,0 +1,68 @@
+// ABOUTME: Usage (runtime.UsageReporter): a running container's CPU and memory
+// ABOUTME: use from docker stats, computed the way the docker CLI computes it.
+package docker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	cerrdefs "github.com/containerd/errdefs"
+	"github.com/docker/docker/api/types/container"
+
+	"github.com/kstenerud/yoloai/runtime"
+)
+
+// Usage samples a container's stats once (runtime.UsageReporter). The daemon
+// takes two readings about a second apart so CPU use can be worked out from
+// the difference; podman's compat API does the same. Podman inherits this by
+// embedding.
+func (r *Runtime) Usage(ctx context.Context, name string) (runtime.ResourceUsage, error) {
+	resp, err := r.client.ContainerStats(ctx, name, false)
+	if err != nil {
+		if cerrdefs.IsNotFound(err) {
+			return runtime.ResourceUsage{}, runtime.ErrNotFound
+		}
+		return runtime.ResourceUsage{}, fmt.Errorf("container stats: %w", err)
+	}
+	defer resp.Body.Close() //nolint:errcheck // read-only body
+
+	var st container.StatsResponse
+	if err := json.NewDecoder(resp.Body).Decode(&st); err != nil {
+		return runtime.ResourceUsage{}, fmt.Errorf("decode container stats: %w", err)
+	}
+	// A stopped container answers with an empty reading.
+	if st.Read.IsZero() {
+		return runtime.ResourceUsage{}, runtime.ErrNotRunning
+	}
+	return usageFromStats(&st), nil
+}
+
+// usageFromStats mirrors the docker CLI's `docker stats`: CPU is the
+// container's share of the host's CPU time between the two readings, scaled by
+// the CPU count; memory leaves out the inactive page cache the kernel can
+// reclaim (the cgroup v2 and v1 names for it differ).
+func usageFromStats(st *container.StatsResponse) runtime.ResourceUsage {
+	var u runtime.ResourceUsage
+	cpuDelta := float64(st.CPUStats.CPUUsage.TotalUsage) - float64(st.PreCPUStats.CPUUsage.TotalUsage)
+	sysDelta := float64(st.CPUStats.SystemUsage) - float64(st.PreCPUStats.SystemUsage)
+	cpus := float64(st.CPUStats.OnlineCPUs)
+	if cpus == 0 {
+		cpus = float64(len(st.CPUStats.CPUUsage.PercpuUsage))
+	}
+	if cpuDelta > 0 && sysDelta > 0 {
+		u.CPUPercent = cpuDelta / sysDelta * cpus * 100
+	}
+
+	mem := st.MemoryStats.Usage
+	cache, ok := st.MemoryStats.Stats["inactive_file"]
+	if !ok {
+		cache = st.MemoryStats.Stats["total_inactive_file"]
+	}
+	if cache < mem {
+		mem -= cache
+	}
+	u.MemoryBytes = int64(mem)                       //nolint:gosec // G115: memory in use fits an int64
+	u.MemoryLimitBytes = int64(st.MemoryStats.Limit) //nolint:gosec // G115: likewise
+	return u
+}
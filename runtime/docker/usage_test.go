@@ -0,0 +1,43 @@
+// ABOUTME: Tests for usageFromStats: CPU share across readings and memory
+// ABOUTME: net of reclaimable cache, for both cgroup versions.
+package docker
+
+import (
+	"testing"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUsageFromStats(t *testing.T) {
+	st := &container.StatsResponse{
+		CPUStats: container.CPUStats{
+			CPUUsage:    container.CPUUsage{TotalUsage: 3_000},
+			SystemUsage: 20_000,
+			OnlineCPUs:  4,
+		},
+		PreCPUStats: container.CPUStats{
+			CPUUsage:    container.CPUUsage{TotalUsage: 1_000},
+			SystemUsage: 10_000,
+		},
+		MemoryStats: container.MemoryStats{
+			Usage: 500,
+			Limit: 2_000,
+			Stats: map[string]uint64{"inactive_file": 100},
+		},
+	}
+	u := usageFromStats(st)
+	assert.InDelta(t, 80.0, u.CPUPercent, 0.001)
+	assert.Equal(t, int64(400), u.MemoryBytes)
+	assert.Equal(t, int64(2_000), u.MemoryLimitBytes)
+
+	// cgroup v1 names the cache total_inactive_file; the first reading has no
+	// previous one, so no CPU figure yet.
+	st = &container.StatsResponse{
+		CPUStats:    container.CPUStats{CPUUsage: container.CPUUsage{TotalUsage: 3_000, PercpuUsage: []uint64{1, 2}}},
+		MemoryStats: container.MemoryStats{Usage: 500, Stats: map[string]uint64{"total_inactive_file": 200}},
+	}
+	u = usageFromStats(st)
+	assert.Zero(t, u.CPUPercent)
+	assert.Equal(t, int64(300), u.MemoryBytes)
+}
@@ -1,5 +1,5 @@
 // ABOUTME: Embeds Python scripts (status-monitor, sandbox-setup, setup_helpers,
-// ABOUTME: tmux_io, diagnose-idle) and exposes them for all runtime backends to install.
+// ABOUTME: tmux_io, diagnose-idle, test-agent) and exposes them for all backends to install.
 // Package monitor embeds the Python status monitor script and the
 // consolidated sandbox setup script shared across all runtime backends
 // (Docker, Tart, Seatbelt).
@@ -28,6 +28,12 @@ var embeddedAgentRun []byte
 //go:embed yoloai-resume.sh
 var embeddedYoloaiResume []byte
 
+//go:embed yoloai-diffwatch.sh
+var embeddedYoloaiDiffwatch []byte
+
+//go:embed test-agent.py
+var embeddedTestAgent []byte
+
 // Script returns the embedded status-monitor.py content.
 func Script() []byte {
 	return embeddedStatusMonitor
@@ -73,3 +79,18 @@ func AgentRunScript() []byte {
 func YoloaiResumeScript() []byte {
 	return embeddedYoloaiResume
 }
+
+// YoloaiDiffwatchScript returns the embedded yoloai-diffwatch script: a live
+// `git diff --stat` of a :copy work copy against the baseline published in
+// logs/baselines, run in a side pane from the tmux menu. Backends install it
+// executable in the sandbox bin dir as `yoloai-diffwatch` (no extension).
+func YoloaiDiffwatchScript() []byte {
+	return embeddedYoloaiDiffwatch
+}
+
+// TestAgentScript returns the embedded test-agent.py content. It is the launch
+// command of the built-in `test` agent: a plain shell, or a scripted fake agent
+// when given a scenario file. Backends install it in the sandbox bin dir.
+func TestAgentScript() []byte {
+	return embeddedTestAgent
+}
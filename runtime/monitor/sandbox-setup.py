@@ -19,6 +19,7 @@ import datetime
 import json
 import os
 import shutil
+import socket
 import subprocess
 import sys
 import tempfile
@@ -28,17 +29,30 @@ from pathlib import Path
 from typing import Any, Callable, TextIO, cast
 
 from setup_helpers import (
+    EGRESS_PROXY_PORT,
+    agent_working_dir,
+    block_push_env,
     build_agent_launch_command,
     compose_prompt_content,
     dockerd_storage_args,
+    egress_proxy_env,
     lifecycle_on_create_marker,
     lifecycle_preamble,
     load_secret_files,
+    pending_inbox_files,
+    pre_launch_env_changes,
+    read_agent_status,
+    read_inbox_prompt,
     read_runtime_config,
+    role_preamble,
     should_run_on_create,
 )
 import tmux_io
-from tmux_io import set_title, tmux, tmux_output
+from tmux_io import AGENT_WINDOW, set_title, tmux, tmux_output
+
+# The window of `yoloai shell`: a plain shell in the workdir beside the agent
+# (orchestrator.ShellWindow on the host side).
+SHELL_WINDOW = "shell"
 
 
 # --- JSONL logger ---
@@ -278,6 +292,45 @@ class Backend(ABC):
         pass
 
 
+def _port_open(port: int) -> bool:
+    try:
+        with socket.create_connection(("127.0.0.1", port), timeout=0.5):
+            return True
+    except OSError:
+        return False
+
+
+def start_egress_proxy(yoloai_dir: str, port: int = EGRESS_PROXY_PORT) -> None:
+    """Start egress-proxy.py and point this process's environment at it, so the
+    tmux server and the agent inherit HTTP(S)_PROXY.
+
+    The proxy records each destination in logs/egress.jsonl (read by `yoloai
+    network log`). It audits; the firewall still decides. A proxy left over
+    from an earlier setup run is reused. If it doesn't come up, the sandbox
+    runs without it — the agent connects directly, unrecorded.
+    """
+    if not _port_open(port):
+        script = os.path.join(yoloai_dir, "bin", "egress-proxy.py")
+        log_path = os.path.join(yoloai_dir, "logs", "egress.jsonl")
+        try:
+            subprocess.Popen(
+                [sys.executable, script, log_path, str(port)],
+                stdin=subprocess.DEVNULL, stdout=subprocess.DEVNULL,
+                stderr=subprocess.DEVNULL, start_new_session=True,
+            )
+        except OSError as e:
+            log_error("egress_proxy.error", f"cannot start egress proxy: {e}")
+            return
+        deadline = time.monotonic() + 5
+        while not _port_open(port):
+            if time.monotonic() > deadline:
+                log_error("egress_proxy.error", "egress proxy did not start listening", port=port)
+                return
+            time.sleep(0.1)
+    os.environ.update(egress_proxy_env(dict(os.environ), port))
+    log_info("egress_proxy.start", "egress audit proxy ready", port=port)
+
+
 class DockerBackend(Backend):
     """Backend for Docker and Podman containers."""
 
@@ -320,8 +373,10 @@ class DockerBackend(Backend):
         return None
 
     def prepare_environment(self) -> None:
-        """Docker environment is already prepared by entrypoint.py."""
-        pass
+        """Docker environment is otherwise prepared by entrypoint.py; an
+        isolated sandbox also gets the egress audit proxy."""
+        if self.cfg.get("network_isolated"):
+            start_egress_proxy(self.yoloai_dir)
 
     def read_secrets(self, socket: str | None) -> dict[str, str]:
         """Read secrets from env vars named in YOLOAI_SECRET_KEYS.
@@ -812,6 +867,114 @@ def get_backend(name: str, cfg: dict[str, Any], yoloai_dir: str) -> Backend:
 
 # --- Shared setup functions ---
 
+PRE_LAUNCH_TIMEOUT = 300  # seconds
+
+
+def run_pre_launch(cfg: dict[str, Any], working_dir: str | None) -> dict[str, str]:
+    """Run the pre_launch snippet and return the variables it exported.
+
+    The snippet is eval'd by bash, so sourcing a script or activating a
+    toolchain changes that shell; `env -0` then dumps the result. Changes are
+    applied to os.environ so the tmux server (and every window) inherits them,
+    and returned so launch_agent can export them inline past a login shell's
+    profile. A failing snippet is logged and the agent launches without it.
+    """
+    snippet = cfg.get("pre_launch", "")
+    if not snippet:
+        return {}
+    log_info("pre_launch.start", "running pre_launch", cmd=snippet)
+    start = time.monotonic()
+    try:
+        result = subprocess.run(
+            ["bash", "-c", 'eval "$1" >&2 && env -0', "pre_launch", snippet],
+            cwd=working_dir or None, stdin=subprocess.DEVNULL,
+            capture_output=True, timeout=PRE_LAUNCH_TIMEOUT)
+    except (OSError, subprocess.TimeoutExpired) as e:
+        log_error("pre_launch.error", "pre_launch could not run", error=str(e))
+        return {}
+    duration_ms = int((time.monotonic() - start) * 1000)
+    output = result.stderr.decode("utf-8", errors="replace").strip()[-2000:]
+    if result.returncode != 0:
+        log_error("pre_launch.error", "pre_launch failed; launching the agent without it",
+                  exit_code=result.returncode, duration_ms=duration_ms, output=output)
+        return {}
+    changes = pre_launch_env_changes(dict(os.environ), result.stdout)
+    os.environ.update(changes)
+    log_info("pre_launch.done", f"pre_launch succeeded, {len(changes)} variable(s) exported",
+             vars=sorted(changes), duration_ms=duration_ms, output=output)
+    return changes
+
+
+# The shim install_guard writes for each guarded command. It runs on every
+# invocation of that command, so it stays small: match, log, then refuse or
+# exec the real binary. @PYTHON@, @HELPERS@ and @CONF@ are substituted.
+GUARD_SHIM = """#!@PYTHON@
+# yoloai guard shim: written by install_guard in sandbox-setup.py.
+import datetime, json, os, shlex, sys
+sys.path.insert(0, @HELPERS@)
+from setup_helpers import guard_match, guard_real_binary
+
+with open(@CONF@) as f:
+    conf = json.load(f)
+name = os.path.basename(__file__)
+real = guard_real_binary(name, os.environ.get("PATH", ""), os.path.dirname(os.path.abspath(__file__)))
+rule = guard_match(conf["commands"], name, sys.argv[1:])
+if rule is not None:
+    blocked = conf["mode"] == "block"
+    cmd = shlex.join([name] + sys.argv[1:])
+    now = datetime.datetime.now(datetime.timezone.utc)
+    entry = {"ts": now.strftime("%Y-%m-%dT%H:%M:%S.") + f"{now.microsecond // 1000:03d}Z",
+             "level": "warn", "event": "guard.match", "msg": "guarded command " + ("blocked" if blocked else "run"),
+             "cmd": cmd, "rule": rule, "cwd": os.getcwd(), "blocked": blocked}
+    try:
+        with open(conf["log"], "a") as log:
+            log.write(json.dumps(entry) + "\\n")
+    except OSError:
+        pass
+    if blocked:
+        print(f"yoloai guard: refused {cmd!r} (rule {rule!r}); this sandbox blocks it", file=sys.stderr)
+        sys.exit(126)
+if real is None:
+    print(f"{name}: command not found", file=sys.stderr)
+    sys.exit(127)
+os.execv(real, [name] + sys.argv[1:])
+"""
+
+
+def install_guard(cfg: dict[str, Any], yoloai_dir: str, path: str) -> dict[str, str]:
+    """Put guard shims in front of the commands the guard config names.
+
+    Returns the agent's PATH with the shim directory first ({} when the guard
+    is off). The shims log each matching invocation to sandbox.jsonl and, in
+    block mode, refuse it. Defense in depth, not a boundary: the agent can
+    still run a binary by its full path.
+    """
+    guard = cfg.get("guard")
+    if not guard:
+        return {}
+    # Rewritten from runtime-config.json on every start, undoing any tampering.
+    guard_dir = os.path.join(yoloai_dir, "guard")
+    shutil.rmtree(guard_dir, ignore_errors=True)
+    bin_dir = os.path.join(guard_dir, "bin")
+    os.makedirs(bin_dir)
+    conf_path = os.path.join(guard_dir, "guard.json")
+    with open(conf_path, "w") as f:
+        json.dump({"mode": guard["mode"], "commands": guard["commands"],
+                   "log": os.path.join(yoloai_dir, "logs", "sandbox.jsonl")}, f)
+    shim = (GUARD_SHIM.replace("@PYTHON@", sys.executable)
+            .replace("@HELPERS@", repr(os.path.dirname(os.path.abspath(__file__))))
+            .replace("@CONF@", repr(conf_path)))
+    names = sorted({rule.split()[0] for rule in guard["commands"] if rule.split()})
+    for name in names:
+        shim_path = os.path.join(bin_dir, name)
+        with open(shim_path, "w") as f:
+            f.write(shim)
+        os.chmod(shim_path, 0o555)
+    log_info("guard.install", f"guard shims installed ({guard['mode']})",
+             mode=guard["mode"], commands=names, dir=bin_dir)
+    return {"PATH": bin_dir + os.pathsep + path}
+
+
 def setup_tmux_session(cfg: dict[str, Any], yoloai_dir: str, socket: str | None = None) -> None:
     """Start a tmux session with config based on tmux_conf setting."""
     tmux_conf = cfg.get("tmux_conf", "")
@@ -852,13 +1015,18 @@ def setup_tmux_session(cfg: dict[str, Any], yoloai_dir: str, socket: str | None
              alive=bool(_sessions_after_new.strip()),
              sessions=_sessions_after_new.strip())
 
+    # The yoloai menu (prefix + ?) in tmux.conf finds the logs through
+    # $YOLOAI_DIR, which only the Docker image sets; set it for every
+    # backend so panes the menu opens get it.
+    tmux("set-environment", "-g", "YOLOAI_DIR", yoloai_dir, socket=socket)
+
     # Source host tmux.conf on top of default if default+host
     if tmux_conf == "default+host" and host_tmux_conf and os.path.isfile(host_tmux_conf):
         tmux("source-file", host_tmux_conf, socket=socket)
 
     # remain-on-exit is also set in tmux.conf, but belt-and-suspenders here.
     # Use set-window-option (not set-option) — remain-on-exit is a window option.
-    r = tmux("set-window-option", "-t", "main", "remain-on-exit", "on", socket=socket)
+    r = tmux("set-window-option", "-t", AGENT_WINDOW, "remain-on-exit", "on", socket=socket)
     if r.returncode != 0:
         log_info("tmux.error", "set-window-option remain-on-exit failed",
                  exit_code=r.returncode, stderr=r.stderr.strip())
@@ -869,8 +1037,11 @@ def setup_tmux_session(cfg: dict[str, Any], yoloai_dir: str, socket: str | None
              alive=bool(_sessions_after_swo.strip()),
              sessions=_sessions_after_swo.strip())
 
-    # Pipe raw terminal stream to logs/agent.log for later inspection.
-    r = tmux("pipe-pane", "-t", "main", f"cat >> {yoloai_dir}/logs/agent.log", socket=socket)
+    # Pipe raw terminal stream to logs/agent.log for later inspection, and
+    # record it with its timing to logs/agent.cast for `yoloai replay`.
+    record_cmd = (f"python3 {yoloai_dir}/bin/status-monitor.py --record "
+                  f"{yoloai_dir}/logs/agent.log {yoloai_dir}/logs/agent.cast 200 50")
+    r = tmux("pipe-pane", "-t", AGENT_WINDOW, record_cmd, socket=socket)
     if r.returncode != 0:
         log_info("tmux.error", "pipe-pane failed",
                  exit_code=r.returncode, stderr=r.stderr.strip())
@@ -888,8 +1059,15 @@ def launch_agent(
     backend_inst: Backend | None = None,
     secrets: dict[str, str] | None = None,
     yoloai_dir: str | None = None,
+    extra_env: dict[str, str] | None = None,
+    target: str = AGENT_WINDOW,
 ) -> None:
-    """Launch the agent command inside the tmux session."""
+    """Launch the agent command inside the tmux session.
+
+    extra_env (the pre_launch exports) is exported inline with the secrets,
+    which win on a name clash. target is the window to launch in; a split-role
+    sandbox launches each role's agent in its own.
+    """
     agent_command = cfg.get("agent_command", "")
     agent = cfg.get("agent", "")
     model = cfg.get("model", "")
@@ -930,7 +1108,7 @@ def launch_agent(
                      python_path=os.environ.get("PATH", ""))
             backend_name = backend_inst.__class__.__name__.replace("Backend", "").lower() if backend_inst else ""
             rebuild_cmd = f"yoloai system build --backend {backend_name}" if backend_name else "yoloai system build"
-            tmux("send-keys", "-t", "main",
+            tmux("send-keys", "-t", target,
                  f"echo 'yoloai: {agent_bin} not found — run: {rebuild_cmd}'",
                  "Enter", socket=socket)
             return
@@ -956,19 +1134,19 @@ def launch_agent(
     bin_dir = yoloai_dir or os.environ.get("YOLOAI_DIR", "/yoloai")
     wrapper = os.path.join(bin_dir, "bin", "agent-run.sh") if cfg.get("fall_to_shell") else ""
     send_cmd = build_agent_launch_command(
-        agent_command, working_dir, secrets, cfg.get("agent_launch_prefix", ""),
+        agent_command, working_dir, {**(extra_env or {}), **(secrets or {})}, cfg.get("agent_launch_prefix", ""),
         wrapper=wrapper)
 
-    tmux("send-keys", "-t", "main", send_cmd, "Enter", socket=socket)
-    log_info("sandbox.agent_launch", "agent process started", agent=agent, model=model)
+    tmux("send-keys", "-t", target, send_cmd, "Enter", socket=socket)
+    log_info("sandbox.agent_launch", "agent process started", agent=agent, model=model, window=target)
 
     # Check session health shortly after launch to surface immediate crashes
     # (e.g. missing binary, auth error, gVisor syscall rejection) in
     # sandbox.jsonl before the host-side attach attempt.
     time.sleep(0.5)
     sessions = tmux_output("list-sessions", socket=socket)
-    pane = tmux_output("capture-pane", "-t", "main", "-p", socket=socket)
-    pane_dead = tmux_output("list-panes", "-t", "main", "-F", "#{pane_dead}", socket=socket)
+    pane = tmux_output("capture-pane", "-t", target, "-p", socket=socket)
+    pane_dead = tmux_output("list-panes", "-t", target, "-F", "#{pane_dead}", socket=socket)
     log_info("sandbox.post_launch", "post-launch check",
              sessions_alive=bool(sessions.strip()),
              pane_dead=(pane_dead.strip() == "1"),
@@ -1016,15 +1194,70 @@ def launch_vscode_tunnel(cfg: dict[str, Any], socket: str | None = None) -> None
     log_info("vscode_tunnel.launch", "VS Code tunnel started", tunnel_name=tunnel_name)
 
 
+def open_shell_window(working_dir: str | None, socket: str | None = None) -> None:
+    """Open a background window with a plain shell in the workdir.
+
+    It is `yoloai shell`'s window (Ctrl-b n from the agent), for looking
+    around the work copy without typing into the agent's pane. Exiting the
+    shell closes the window; `yoloai shell` opens it again.
+    """
+    args = ["new-window", "-d", "-t", "main", "-n", SHELL_WINDOW]
+    if working_dir:
+        args.extend(["-c", working_dir])
+    r = tmux(*args, socket=socket)
+    if r.returncode != 0:
+        log_info("sandbox.shell_window_error", "failed to create shell window",
+                 exit_code=r.returncode, stderr=r.stderr.strip())
+        return
+    # The global remain-on-exit keeps the agent's pane; an exited shell goes.
+    tmux("set-window-option", "-t", f"main:{SHELL_WINDOW}", "remain-on-exit", "off", socket=socket)
+    log_info("sandbox.shell_window", "shell window opened", path=working_dir or "")
+
+
+def launch_role_windows(
+    cfg: dict[str, Any],
+    yoloai_dir: str,
+    socket: str | None,
+    launch: Callable[[str], None],
+) -> None:
+    """Start each split-role agent after the first in a window of its own.
+
+    The first role runs in the main window and gets its prompt through the
+    normal delivery; every other role gets a background window named after
+    it, the same agent launched by ``launch(target)``, and its own prompt
+    opened by the role notice. The status monitor and inbox keep following
+    the main window.
+    """
+    roles = cfg.get("roles") or []
+    names = [r["name"] for r in roles]
+    notes = os.path.join(yoloai_dir, "files", "notes.md")
+    for role in roles[1:]:
+        name = role["name"]
+        r = tmux("new-window", "-d", "-t", "main", "-n", name, socket=socket)
+        if r.returncode != 0:
+            log_error("sandbox.role_window_error", "failed to create role window",
+                      role=name, exit_code=r.returncode, stderr=r.stderr.strip())
+            continue
+        target = f"main:{name}"
+        tmux("set-window-option", "-t", target, "remain-on-exit", "on", socket=socket)
+        tmux("pipe-pane", "-t", target, f"cat >> {yoloai_dir}/logs/agent-{name}.log", socket=socket)
+        launch(target)
+        wait_for_ready(cfg, socket=socket, target=target)
+        content = compose_prompt_content(role_preamble(name, names, notes), role.get("prompt")) or ""
+        confirmed = paste_and_submit(cfg, content, socket=socket, target=target)
+        log_info("sandbox.role_start", "role agent started", role=name,
+                 submit_confirmed=confirmed)
+
+
 def monitor_exit(socket: str | None = None) -> None:
     """Daemon thread: poll pane_dead and detach clients when agent exits."""
     def _monitor() -> None:
         while True:
-            output = tmux_output("list-panes", "-t", "main", "-F", "#{pane_dead}:#{pane_dead_status}", socket=socket)
+            output = tmux_output("list-panes", "-t", AGENT_WINDOW, "-F", "#{pane_dead}:#{pane_dead_status}", socket=socket)
             if ":" in (output or ""):
                 dead, status = output.strip().split(":", 1)
                 if dead == "1":
-                    pane = tmux_output("capture-pane", "-t", "main", "-p", socket=socket)
+                    pane = tmux_output("capture-pane", "-t", AGENT_WINDOW, "-p", socket=socket)
                     log_info("sandbox.agent_exit_detected", "agent pane exited",
                              exit_code=status.strip(),
                              pane_content=pane.strip()[:400] if pane else "")
@@ -1047,7 +1280,7 @@ def monitor_exit(socket: str | None = None) -> None:
     t.start()
 
 
-def wait_for_ready(cfg: dict[str, Any], socket: str | None = None) -> None:
+def wait_for_ready(cfg: dict[str, Any], socket: str | None = None, target: str = AGENT_WINDOW) -> None:
     """Wait for agent ready pattern, auto-accept trust/confirmation prompts."""
     ready_pattern = cfg.get("ready_pattern", "")
     startup_delay = cfg.get("startup_delay", 5)
@@ -1063,14 +1296,14 @@ def wait_for_ready(cfg: dict[str, Any], socket: str | None = None) -> None:
 
     found = False
     while waited < max_wait:
-        pane = tmux_output("capture-pane", "-t", "main", "-p", socket=socket)
+        pane = tmux_output("capture-pane", "-t", target, "-p", socket=socket)
 
         # Auto-accept confirmation prompts
         if "Enter to confirm" in pane:
             if "Yes, I accept" in pane:
-                tmux("send-keys", "-t", "main", "Down", socket=socket)
+                tmux("send-keys", "-t", target, "Down", socket=socket)
                 time.sleep(0.5)
-            tmux("send-keys", "-t", "main", "Enter", socket=socket)
+            tmux("send-keys", "-t", target, "Enter", socket=socket)
             time.sleep(2)
             waited += 2
             continue
@@ -1093,7 +1326,7 @@ def wait_for_ready(cfg: dict[str, Any], socket: str | None = None) -> None:
     while stable < 1 and waited < max_wait:
         time.sleep(0.5)
         waited += 1
-        curr = tmux_output("capture-pane", "-t", "main", "-p", socket=socket)
+        curr = tmux_output("capture-pane", "-t", target, "-p", socket=socket)
         if curr == prev:
             stable += 1
         else:
@@ -1110,15 +1343,15 @@ _SUBMIT_VERIFY_ATTEMPTS = 3
 _SUBMIT_VERIFY_DELAY_SECONDS = 0.75
 
 
-def _send_submit(submit_sequence: str, socket: str | None = None) -> None:
+def _send_submit(submit_sequence: str, socket: str | None = None, target: str = AGENT_WINDOW) -> None:
     """Send the agent's submit key sequence to the pane."""
     for key in submit_sequence.split():
-        tmux("send-keys", "-t", "main", key, socket=socket)
+        tmux("send-keys", "-t", target, key, socket=socket)
         time.sleep(0.2)
 
 
 def prompt_pending_in_input(
-    cfg: dict[str, Any], content: str, socket: str | None = None
+    cfg: dict[str, Any], content: str, socket: str | None = None, target: str = AGENT_WINDOW
 ) -> bool:
     """True when `content` still sits UNSENT in the agent's input box.
 
@@ -1139,7 +1372,7 @@ def prompt_pending_in_input(
     first_line = content.strip().split("\n", 1)[0].strip()
     if not first_line:
         return False
-    pane = tmux_output("capture-pane", "-t", "main", "-p", socket=socket)
+    pane = tmux_output("capture-pane", "-t", target, "-p", socket=socket)
     input_lines = [line for line in pane.split("\n") if ready_pattern in line]
     if not input_lines:
         return False
@@ -1187,10 +1420,26 @@ def deliver_prompt(
             prompt_text = f.read()
     content = compose_prompt_content(preamble, prompt_text) or ""
 
-    submit_sequence = cfg.get("submit_sequence", "")
     log_debug("prompt.deliver", "delivering prompt", has_preamble=bool(preamble),
               has_user_prompt=has_prompt)
+    confirmed = paste_and_submit(cfg, content, socket=socket)
+    if confirmed is None:
+        return False
 
+    log_info("sandbox.prompt_deliver", "prompt delivered", method="paste-buffer",
+             submit_confirmed=confirmed)
+    return has_prompt  # True only when a real user task was submitted
+
+
+def paste_and_submit(
+    cfg: dict[str, Any], content: str, socket: str | None = None, target: str = AGENT_WINDOW
+) -> bool | None:
+    """Paste content into the agent's pane and submit it.
+
+    Returns None when tmux could not paste at all, otherwise whether the submit
+    was confirmed to have left the input box.
+    """
+    submit_sequence = cfg.get("submit_sequence", "")
     with tempfile.NamedTemporaryFile(mode="w", suffix=".txt", delete=False) as tmp:
         tmp.write(content)
         tmpname = tmp.name
@@ -1199,7 +1448,7 @@ def deliver_prompt(
         if r.returncode != 0:
             log_error("prompt.load_buffer_failed", "tmux load-buffer failed",
                       exit_code=r.returncode, stderr=r.stderr.strip())
-            return False
+            return None
 
         # -p brackets the paste (ESC[200~ … ESC[201~) when the agent has asked
         # for bracketed paste mode. Without it tmux rewrites each LF to a CR
@@ -1207,14 +1456,14 @@ def deliver_prompt(
         # timing swallows those CRs, silently joining a multi-line prompt into a
         # single line. tmux emits the brackets only for an app that requested the
         # mode, so this stays inert for agents that did not.
-        r = tmux("paste-buffer", "-p", "-t", "main", socket=socket)
+        r = tmux("paste-buffer", "-p", "-t", target, socket=socket)
         if r.returncode != 0:
             log_error("prompt.paste_buffer_failed", "tmux paste-buffer failed",
                       exit_code=r.returncode, stderr=r.stderr.strip())
-            return False
+            return None
 
         time.sleep(0.5)
-        _send_submit(submit_sequence, socket=socket)
+        _send_submit(submit_sequence, socket=socket, target=target)
 
         # Confirm the submit actually took, and re-send if it didn't. Bounded,
         # and each retry is logged: a prompt that needs one is a real event, and
@@ -1222,14 +1471,14 @@ def deliver_prompt(
         confirmed = False
         for attempt in range(_SUBMIT_VERIFY_ATTEMPTS + 1):
             time.sleep(_SUBMIT_VERIFY_DELAY_SECONDS)
-            if not prompt_pending_in_input(cfg, content, socket=socket):
+            if not prompt_pending_in_input(cfg, content, socket=socket, target=target):
                 confirmed = True
                 break
             if attempt < _SUBMIT_VERIFY_ATTEMPTS:
                 log_info("prompt.submit_retry",
                          "prompt still composed in the input box; re-sending submit",
                          attempt=attempt + 1)
-                _send_submit(submit_sequence, socket=socket)
+                _send_submit(submit_sequence, socket=socket, target=target)
     finally:
         os.unlink(tmpname)
 
@@ -1241,9 +1490,71 @@ def deliver_prompt(
                   "the agent has the text but was never told to run it",
                   attempts=_SUBMIT_VERIFY_ATTEMPTS)
 
-    log_info("sandbox.prompt_deliver", "prompt delivered", method="paste-buffer",
-             submit_confirmed=confirmed)
-    return has_prompt  # True only when a real user task was submitted
+    return confirmed
+
+
+# Inbox poll interval. Delivery waits for an idle agent anyway, so this only
+# bounds how long a dropped file sits before an idle agent receives it.
+_INBOX_POLL_SECONDS = 2.0
+
+
+def _archive_inbox_file(path: str, outcome: str) -> None:
+    """Move a handled inbox file to inbox/<outcome>/ so it is never resent."""
+    dest_dir = os.path.join(os.path.dirname(path), outcome)
+    dest = os.path.join(dest_dir, f"{int(time.time())}-{os.path.basename(path)}")
+    try:
+        os.makedirs(dest_dir, exist_ok=True)
+        os.replace(path, dest)
+    except OSError as e:
+        # Leaving the file in place would resend it forever; drop it instead.
+        log_error("inbox.archive_failed", "could not archive inbox file; removing it",
+                  path=path, error=str(e))
+        try:
+            os.unlink(path)
+        except OSError:
+            pass
+
+
+def watch_inbox(cfg: dict[str, Any], yoloai_dir: str, socket: str | None = None) -> None:
+    """Submit prompt files dropped into <yoloai_dir>/inbox/ to the agent.
+
+    Lets tools that can write files but not run the CLI queue work for a
+    running agent. Each poll delivers at most one file, and only while the
+    agent is idle, so queued prompts run one after another instead of being
+    typed into a busy session. The file is then moved to inbox/delivered/, or
+    to inbox/failed/ when it was empty or tmux could not paste it.
+    """
+    inbox_dir = os.path.join(yoloai_dir, "inbox")
+    status_file = os.path.join(yoloai_dir, "agent-status.json")
+    while True:
+        time.sleep(_INBOX_POLL_SECONDS)
+        if read_agent_status(status_file) != "idle":
+            continue
+        ready = pending_inbox_files(inbox_dir, time.time())
+        if not ready:
+            continue
+        path = ready[0]
+        try:
+            content = read_inbox_prompt(path)
+        except OSError as e:
+            log_error("inbox.read_failed", "could not read inbox file", path=path, error=str(e))
+            _archive_inbox_file(path, "failed")
+            continue
+        if not content:
+            log_info("inbox.empty", "inbox file is empty; not delivered", path=path)
+            _archive_inbox_file(path, "failed")
+            continue
+
+        confirmed = paste_and_submit(cfg, content, socket=socket)
+        if confirmed is None:
+            _archive_inbox_file(path, "failed")
+            continue
+        # Mark the agent busy now rather than waiting for its hooks, so the
+        # next poll cannot stack a second prompt on top of this one.
+        write_status(status_file, "active")
+        _archive_inbox_file(path, "delivered")
+        log_info("inbox.deliver", "inbox prompt delivered", path=path,
+                 submit_confirmed=confirmed)
 
 
 def _var_lib_docker_fstype() -> str:
@@ -1409,11 +1720,11 @@ def run_lifecycle_background(
         tmux("load-buffer", tmpname, socket=socket)
         # -p for the same reason as deliver_prompt: keep the text's own line
         # structure instead of letting tmux's LF→CR rewrite reach the agent.
-        tmux("paste-buffer", "-p", "-t", "main", socket=socket)
+        tmux("paste-buffer", "-p", "-t", AGENT_WINDOW, socket=socket)
         time.sleep(0.3)
         submit_sequence = cfg.get("submit_sequence", "")
         for key in submit_sequence.split():
-            tmux("send-keys", "-t", "main", key, socket=socket)
+            tmux("send-keys", "-t", AGENT_WINDOW, key, socket=socket)
             time.sleep(0.2)
     finally:
         os.unlink(tmpname)
@@ -1503,6 +1814,16 @@ def main() -> None:
         signal_secrets_consumed(yoloai_dir)
 
     working_dir = backend.get_working_dir()
+    # --agent-workdir: the agent (and pre_launch) start in a subdirectory of
+    # the workdir; the whole workdir stays mounted and diffed.
+    agent_dir = agent_working_dir(working_dir, cfg.get("agent_workdir", ""))
+
+    # Before tmux starts, so the server and every window inherit what it sets.
+    pre_launch_env = run_pre_launch(cfg, agent_dir)
+    # After pre_launch, so the shims stay first on whatever PATH it exported.
+    pre_launch_env.update(install_guard(cfg, yoloai_dir, pre_launch_env.get("PATH", os.environ.get("PATH", ""))))
+    if not cfg.get("allow_push"):
+        pre_launch_env.update(block_push_env({**os.environ, **pre_launch_env}))
 
     setup_tmux_session(cfg, yoloai_dir, socket=socket)
 
@@ -1515,6 +1836,12 @@ def main() -> None:
     def _log_lifecycle(msg: str) -> None:
         log_info("lifecycle.event", msg)
     preamble = lifecycle_preamble(cfg, yoloai_dir) or None
+    roles = cfg.get("roles") or []
+    if roles:
+        # Split-role sandbox: the main window's agent is the first role.
+        notes = os.path.join(yoloai_dir, "files", "notes.md")
+        preamble = compose_prompt_content(
+            preamble, role_preamble(roles[0]["name"], [r["name"] for r in roles], notes))
     pane_ready = threading.Event()
     threading.Thread(
         target=run_lifecycle_background,
@@ -1533,7 +1860,10 @@ def main() -> None:
                  uid=stat_info.st_uid, gid=stat_info.st_gid)
         os.chmod(socket, 0o777)
 
-    launch_agent(cfg, socket=socket, working_dir=working_dir, backend_inst=backend, secrets=secrets, yoloai_dir=yoloai_dir)
+    def _launch(target: str) -> None:
+        launch_agent(cfg, socket=socket, working_dir=agent_dir, backend_inst=backend, secrets=secrets,
+                     yoloai_dir=yoloai_dir, extra_env=pre_launch_env, target=target)
+    _launch(AGENT_WINDOW)
 
     if cfg.get("vscode_tunnel"):
         launch_vscode_tunnel(cfg, socket=socket)
@@ -1548,6 +1878,11 @@ def main() -> None:
     else:
         wait_for_ready(cfg, socket=socket)
         prompt_delivered = deliver_prompt(cfg, yoloai_dir, socket=socket, preamble=preamble)
+        # Before pane_ready: the lifecycle banner and these prompts would
+        # otherwise share tmux's paste buffer.
+        launch_role_windows(cfg, yoloai_dir, socket, _launch)
+    # After the role windows, so Ctrl-b n walks the agents first.
+    open_shell_window(working_dir, socket=socket)
     # Main thread is done writing to the tmux pane; the lifecycle background
     # banner is now safe to deliver.
     pane_ready.set()
@@ -1566,6 +1901,14 @@ def main() -> None:
     # Launch status monitor
     launch_monitor(cfg_path, status_file, yoloai_dir, socket=socket)
 
+    # A headless agent has no input box to type into, so it gets no inbox.
+    if not cfg.get("headless"):
+        threading.Thread(
+            target=watch_inbox,
+            args=(cfg, yoloai_dir, socket),
+            daemon=True,
+        ).start()
+
     log_info("sandbox.ready", "sandbox fully initialized")
 
     # Block — process stops only on explicit stop/kill.
@@ -15,6 +15,7 @@ from __future__ import annotations
 
 import json
 import os
+import re
 from typing import Any
 
 
@@ -136,6 +137,62 @@ def compose_prompt_content(preamble: str | None, prompt_text: str | None) -> str
     return "\n\n".join(parts)
 
 
+# INBOX_SETTLE_SECONDS is how long an inbox file must sit unmodified before it
+# is picked up, so a writer that does not rename into place is not read
+# half-written.
+INBOX_SETTLE_SECONDS: float = 1.0
+
+
+def pending_inbox_files(inbox_dir: str, now: float, settle_seconds: float = INBOX_SETTLE_SECONDS) -> list[str]:
+    """List the inbox prompt files ready for delivery, in name order.
+
+    Only regular ``*.md`` files directly in inbox_dir count; dotfiles are
+    skipped so a writer can stage ``.name.md`` and rename it into place. A file
+    modified less than settle_seconds before now is still being written and
+    waits for a later poll. A missing inbox yields an empty list.
+    """
+    try:
+        names = sorted(os.listdir(inbox_dir))
+    except OSError:
+        return []
+    ready: list[str] = []
+    for name in names:
+        if name.startswith(".") or not name.endswith(".md"):
+            continue
+        path = os.path.join(inbox_dir, name)
+        try:
+            st = os.stat(path)
+        except OSError:
+            continue
+        if not os.path.isfile(path) or now - st.st_mtime < settle_seconds:
+            continue
+        ready.append(path)
+    return ready
+
+
+def read_agent_status(status_file: str) -> str:
+    """Return the status string recorded in agent-status.json ("" on any error)."""
+    try:
+        with open(status_file) as f:
+            data = json.load(f)
+    except (OSError, ValueError):
+        return ""
+    status = data.get("status", "") if isinstance(data, dict) else ""
+    return status if isinstance(status, str) else ""
+
+
+def read_inbox_prompt(path: str) -> str:
+    """Read an inbox prompt file as text with LF line endings and no BOM.
+
+    Files dropped by other tools often come from Windows editors; the agent
+    should see the same text the CLI's prompt reader would hand it.
+    """
+    with open(path, encoding="utf-8", errors="replace", newline="") as f:
+        text = f.read()
+    text = text.removeprefix("\ufeff")
+    return text.replace("\r\n", "\n").replace("\r", "\n").strip()
+
+
 def build_secret_exports(secrets: dict[str, str] | None) -> str:
     """Build a POSIX-sh ``export NAME='value'; `` prefix for the given secrets.
 
@@ -155,6 +212,164 @@ def build_secret_exports(secrets: dict[str, str] | None) -> str:
     return "".join(parts)
 
 
+def agent_working_dir(working_dir: str | None, agent_workdir: str) -> str | None:
+    """Return the directory the agent starts in.
+
+    ``agent_workdir`` is the sandbox's ``--agent-workdir``: a slash-separated
+    path relative to ``working_dir`` (the mounted workdir, after any backend
+    remapping). Empty, or no ``working_dir``, means the workdir itself.
+    """
+    if not working_dir or not agent_workdir:
+        return working_dir
+    return os.path.join(working_dir, *agent_workdir.split("/"))
+
+
+def role_preamble(name: str, roles: list[str], notes_path: str) -> str:
+    """Return the notice that opens a split-role agent's prompt.
+
+    ``roles`` is every role in the sandbox, in window order. The notice names
+    the agent's own role and the others, and points them all at the one notes
+    file they share, since they work on the same files without seeing each
+    other's sessions.
+    """
+    others = ", ".join(r for r in roles if r != name)
+    return (
+        f'You are the "{name}" agent. Other agents are working on the same files '
+        f"at the same time, each in its own session: {others}. Coordinate through "
+        f"the shared notes file {notes_path}: read it before you start and between "
+        "steps, and append what you have done, what you are waiting on, and "
+        "anything the others need to know."
+    )
+
+
+def _guard_split_args(args: list[str]) -> tuple[set[str], list[str], list[str]]:
+    """Split argv into short-flag letters, long options, and other words."""
+    short: set[str] = set()
+    long: list[str] = []
+    words: list[str] = []
+    options_done = False
+    for arg in args:
+        if options_done or arg == "-" or not arg.startswith("-"):
+            words.append(arg)
+        elif arg == "--":
+            options_done = True
+        elif arg.startswith("--"):
+            long.append(arg)
+        else:
+            short.update(arg[1:])
+    return short, long, words
+
+
+def guard_match(rules: list[str], name: str, args: list[str]) -> str | None:
+    """Return the first guard rule that ``name args`` matches, or None.
+
+    A rule's first word is the command; every later word must appear in the
+    arguments. A short-flag word matches when each of its letters is among
+    the short flags (so "rm -rf" catches "rm -fr" and "rm -r -f"); a long
+    option matches exactly or before an "="; any other word must appear in
+    the same order among the non-option words ("git push" catches
+    "git -C repo push origin").
+    """
+    short, long, words = _guard_split_args(args)
+    for rule in rules:
+        parts = rule.split()
+        if not parts or parts[0] != name:
+            continue
+        pos = 0
+        matched = True
+        for part in parts[1:]:
+            if part.startswith("--"):
+                matched = any(a == part or a.startswith(part + "=") for a in long)
+            elif part.startswith("-") and len(part) > 1:
+                matched = set(part[1:]) <= short
+            else:
+                try:
+                    pos = words.index(part, pos) + 1
+                except ValueError:
+                    matched = False
+            if not matched:
+                break
+        if matched:
+            return rule
+    return None
+
+
+def guard_real_binary(name: str, path: str, guard_dir: str) -> str | None:
+    """Return the binary ``name`` resolves to on ``path``, skipping ``guard_dir``.
+
+    The guard's shims sit first on the agent's PATH; this finds what the
+    command would have run without them.
+    """
+    skip = os.path.realpath(guard_dir)
+    for entry in path.split(os.pathsep):
+        if not entry or os.path.realpath(entry) == skip:
+            continue
+        candidate = os.path.join(entry, name)
+        if os.path.isfile(candidate) and os.access(candidate, os.X_OK):
+            return candidate
+    return None
+
+
+# Where a blocked push is sent: a path that is never a repository, named so
+# git's "does not appear to be a git repository" error explains itself. The Go
+# side's git.PushBlockedURL is the same string.
+PUSH_BLOCKED_URL = "/git-push-is-disabled-in-this-sandbox (create it with --allow-push)/"
+
+
+def block_push_env(env: dict[str, str]) -> dict[str, str]:
+    """Return the variables that make every git push in ``env`` fail.
+
+    An empty ``url.<base>.pushInsteadOf`` prefix matches every push URL, so
+    each one is rewritten to PUSH_BLOCKED_URL; fetches are untouched. It is
+    passed as one more GIT_CONFIG_KEY_n/VALUE_n pair after any the
+    environment already sets, so the repository's own config — a :rw
+    directory's included — is never modified. A remote with an explicit
+    pushurl is not rewritten (git applies pushInsteadOf only to url).
+
+    This is the block for :rw directories and repositories the agent
+    clones. :copy work copies don't rely on it: yoloai owns them, and
+    rewrites their remotes' push URLs in their own config on the host.
+    """
+    try:
+        n = int(env.get("GIT_CONFIG_COUNT", "0"))
+    except ValueError:
+        n = 0
+    return {
+        "GIT_CONFIG_COUNT": str(n + 1),
+        f"GIT_CONFIG_KEY_{n}": f"url.{PUSH_BLOCKED_URL}.pushInsteadOf",
+        f"GIT_CONFIG_VALUE_{n}": "",
+    }
+
+
+# The port egress-proxy.py listens on inside an isolated sandbox.
+EGRESS_PROXY_PORT = 3128
+
+_PROXY_VARS = ("HTTP_PROXY", "HTTPS_PROXY", "http_proxy", "https_proxy")
+
+
+def egress_proxy_env(env: dict[str, str], port: int = EGRESS_PROXY_PORT) -> dict[str, str]:
+    """Return the variables that send ``env``'s HTTP(S) traffic through the
+    egress audit proxy on ``port``.
+
+    Loopback stays direct: NO_PROXY keeps whatever ``env`` already lists and
+    adds the local names, plus the credential-broker injector's host when
+    brokering composes with isolation. When ``env`` already names a proxy (a
+    profile's or the user's own), nothing is returned — it is left in charge
+    rather than chained behind ours.
+    """
+    if any(env.get(v) for v in _PROXY_VARS):
+        return {}
+    url = f"http://127.0.0.1:{port}"
+    no_proxy = [h for h in (env.get("NO_PROXY") or env.get("no_proxy") or "").split(",") if h]
+    injector_host = env.get("YOLOAI_BROKER_INJECTOR_ENDPOINT", "").rpartition(":")[0]
+    for h in ("localhost", "127.0.0.1", "::1", injector_host):
+        if h and h not in no_proxy:
+            no_proxy.append(h)
+    out = {v: url for v in _PROXY_VARS}
+    out["NO_PROXY"] = out["no_proxy"] = ",".join(no_proxy)
+    return out
+
+
 def build_agent_launch_command(
     agent_command: str,
     working_dir: str | None,
@@ -187,6 +402,32 @@ def build_agent_launch_command(
     return launch_prefix + base
 
 
+# Variables bash maintains for itself; a pre_launch snippet leaving them
+# different from sandbox-setup.py's own environment is not an export.
+PRE_LAUNCH_SHELL_VARS: frozenset[str] = frozenset({"_", "SHLVL", "PWD", "OLDPWD"})
+
+_ENV_NAME_RE = re.compile(r"[A-Za-z_][A-Za-z0-9_]*\Z")
+
+
+def pre_launch_env_changes(before: dict[str, str], env_dump: bytes) -> dict[str, str]:
+    """Return the variables a pre_launch snippet set or changed.
+
+    ``env_dump`` is the ``env -0`` output captured after the snippet ran (NUL
+    separated ``NAME=value`` entries); ``before`` is the environment it started
+    from. Variables the snippet unset are not reported — the launch can only
+    export, not unset. Pure: it does not touch ``os.environ``.
+    """
+    changes: dict[str, str] = {}
+    for entry in env_dump.decode("utf-8", errors="replace").split("\0"):
+        name, sep, value = entry.partition("=")
+        # Exported bash functions (BASH_FUNC_f%%) aren't names export accepts.
+        if not sep or not _ENV_NAME_RE.match(name) or name in PRE_LAUNCH_SHELL_VARS:
+            continue
+        if before.get(name) != value:
+            changes[name] = value
+    return changes
+
+
 def load_secret_files(secrets_dir: str) -> dict[str, str]:
     """Read secret files from a directory into a {name: value} mapping.
 
@@ -8,10 +8,13 @@ order to determine agent idle/active status, writes results to status.json,
 and updates the tmux window title.
 
 Usage: status-monitor.py /path/to/config.json /path/to/status.json
+       status-monitor.py --write-status STATUS STATUS_FILE [EXIT_CODE]
+       status-monitor.py --record LOG_FILE CAST_FILE WIDTH HEIGHT
 """
 
 from __future__ import annotations
 
+import codecs
 import datetime
 import json
 import os
@@ -35,6 +38,7 @@ HOOK_IDLE_GRACE = 2  # seconds idle must persist before HookDetector reports idl
 # sequences. The Stop hook fires only once per turn (not between tool calls), so
 # a 2s grace period is sufficient to absorb any filesystem write latency.
 GLOBAL_HOLD_CYCLES = 2  # consecutive non-idle cycles needed to leave idle
+AGENT_WINDOW = "main:{start}"  # the session's first window; "main" alone follows the current one
 
 # Wait channels indicating terminal input wait (idle)
 IDLE_WCHANS = {"n_tty_read", "wait_woken", "ttyin"}
@@ -99,6 +103,61 @@ def write_status(status_file: str, status: str, exit_code: int | None = None) ->
         pass
 
 
+# The output heartbeat, relative to the yoloai dir. Must equal
+# store.AgentActivityFile: it lives under logs/ because that directory is
+# bind-mounted, so the file reaches the host.
+ACTIVITY_FILE = os.path.join("logs", "agent-activity.json")
+ACTIVITY_SCHEMA_VERSION = 1
+
+
+def write_activity(activity_file: str, last_output: int) -> None:
+    """Write the output heartbeat atomically.
+
+    Unlike status.json this file sits in the bind-mounted logs/ directory
+    rather than being a file-level mount, so a temp file plus os.replace()
+    works, and the host never reads a half-written stamp.
+    """
+    data = {"schema_version": ACTIVITY_SCHEMA_VERSION, "last_output": last_output}
+    tmp = activity_file + ".tmp"
+    try:
+        with open(tmp, "w") as f:
+            json.dump(data, f)
+            f.write("\n")
+        os.replace(tmp, activity_file)
+    except OSError:
+        pass
+
+
+class ActivityTracker:
+    """Records when the agent's window last printed anything.
+
+    tmux stamps #{window_activity} whenever a pane in the window writes to
+    the terminal, so it is a free heartbeat: no pane capture, no diffing. The
+    tracker copies it to the activity file when it moves, and the host reads
+    that to say how long an agent has been quiet ("idle for 12m") without an
+    exec into the sandbox. It runs in every idle mode: it reports output, not
+    whether the agent is working.
+    """
+
+    def __init__(self, activity_file: str, tmux_sock: str | None = None) -> None:
+        self.activity_file = activity_file
+        self.tmux_sock = tmux_sock
+        self.last: int | None = None
+
+    def poll(self) -> None:
+        out = tmux_cmd(
+            ["display-message", "-p", "-t", AGENT_WINDOW, "#{window_activity}"], self.tmux_sock
+        ).strip()
+        try:
+            stamp = int(out)
+        except ValueError:
+            return  # tmux unreachable or too old to know the format
+        if stamp <= 0 or stamp == self.last:
+            return
+        write_activity(self.activity_file, stamp)
+        self.last = stamp
+
+
 def read_status_value(status_file: str) -> str:
     """Read the current status string from status_file ("" on any error).
 
@@ -116,7 +175,7 @@ def read_status_value(status_file: str) -> str:
 
 def set_title(name: str, tmux_sock: str | None = None) -> None:
     """Set tmux window title."""
-    tmux_cmd(["rename-window", "-t", "main", name], tmux_sock)
+    tmux_cmd(["rename-window", "-t", AGENT_WINDOW, name], tmux_sock)
 
 
 # --- Wchan detector ---
@@ -372,7 +431,7 @@ class ReadyPatternDetector:
         self.tmux_sock = tmux_sock
 
     def check(self, _agent_pid: int | None) -> DetectorResult:
-        content = tmux_cmd(["capture-pane", "-t", "main", "-p"], self.tmux_sock)
+        content = tmux_cmd(["capture-pane", "-t", AGENT_WINDOW, "-p"], self.tmux_sock)
         if not content:
             return DetectorResult("unknown")
         # Check bottom 5 non-empty lines for the pattern. The agent's ready
@@ -441,7 +500,7 @@ class OutputStabilityDetector:
         self.prev_content: str | None = None
 
     def check(self, _agent_pid: int | None) -> DetectorResult:
-        content = tmux_cmd(["capture-pane", "-t", "main", "-p"], self.tmux_sock)
+        content = tmux_cmd(["capture-pane", "-t", AGENT_WINDOW, "-p"], self.tmux_sock)
         if not content:
             return DetectorResult("unknown")
         # Normalize: strip trailing whitespace per line and remove trailing
@@ -545,7 +604,7 @@ def get_agent_pid(tmux_sock: str | None = None) -> int | None:
     one level to the agent it launched as a child, so process-based detectors
     inspect the agent and not the wrapper's `wait()` (D96 Phase 3).
     """
-    output = tmux_cmd(["list-panes", "-t", "main", "-F", "#{pane_pid}"], tmux_sock)
+    output = tmux_cmd(["list-panes", "-t", AGENT_WINDOW, "-F", "#{pane_pid}"], tmux_sock)
     pid_str = output.strip()
     if not pid_str:
         return None
@@ -577,7 +636,7 @@ def check_pane_dead(tmux_sock: str | None = None) -> tuple[bool, int | None]:
     """
     global _tmux_fail_count
     output = tmux_cmd(
-        ["list-panes", "-t", "main", "-F", "#{pane_dead}|#{pane_dead_status}"],
+        ["list-panes", "-t", AGENT_WINDOW, "-F", "#{pane_dead}|#{pane_dead_status}"],
         tmux_sock,
     )
     if not output.strip():
@@ -714,7 +773,10 @@ def run_monitor(config_path: str, status_file: str, tmux_sock: str | None = None
     # re-detected and tracked without restarting the monitor. It exits only when
     # the box (and the session-runner that parents it) goes down.
     in_done = False  # latched while the pane is dead, cleared on respawn
+    activity = ActivityTracker(os.path.join(yoloai_dir, ACTIVITY_FILE), tmux_sock)
     while True:
+        activity.poll()
+
         # 1. Check pane death
         dead, exit_code = check_pane_dead(tmux_sock)
         if dead:
@@ -874,10 +936,103 @@ def write_status_cli(args: list[str]) -> None:
     write_status(status_file, status, exit_code)
 
 
+CAST_READ_SIZE = 65536  # bytes read from the pane stream per chunk
+CAST_RESUME_GAP = 1.0  # seconds of replay time between two recording sessions
+
+
+def cast_resume_time(cast_file: str) -> float | None:
+    """Return the time of the last event in an existing cast file, or None.
+
+    A restarted sandbox appends to the same recording; its events continue
+    after the previous session's last one (plus CAST_RESUME_GAP) so the file
+    stays a single valid asciicast v2 stream with one header.
+    """
+    try:
+        with open(cast_file, "rb") as f:
+            f.seek(0, os.SEEK_END)
+            size = f.tell()
+            if size == 0:
+                return None
+            f.seek(max(0, size - CAST_READ_SIZE))
+            tail = f.read().splitlines()
+    except OSError:
+        return None
+    for line in reversed(tail):
+        try:
+            event = json.loads(line)
+        except ValueError:
+            continue
+        if isinstance(event, list) and event and isinstance(event[0], (int, float)):
+            return float(event[0])
+        if isinstance(event, dict):
+            return 0.0  # header only: nothing recorded yet
+    return None
+
+
+def record_cli(args: list[str]) -> None:
+    """Handle `status-monitor.py --record LOG_FILE CAST_FILE WIDTH HEIGHT`.
+
+    tmux pipe-pane feeds the agent pane's output here. The raw bytes are
+    appended to LOG_FILE exactly as `cat >> LOG_FILE` used to, and also
+    recorded with their timing to CAST_FILE in asciicast v2 format, which
+    `yoloai replay` and asciinema play back. The log always comes first: a
+    failure writing the cast stops the recording but never the log.
+    """
+    if len(args) < 4:
+        print("Usage: status-monitor.py --record LOG_FILE CAST_FILE WIDTH HEIGHT", file=sys.stderr)
+        sys.exit(2)
+    log_file, cast_file = args[0], args[1]
+    try:
+        width, height = int(args[2]), int(args[3])
+    except ValueError:
+        width, height = 200, 50
+
+    decoder = codecs.getincrementaldecoder("utf-8")(errors="replace")
+    offset = 0.0
+    cast: TextIO | None = None
+    try:
+        resume = cast_resume_time(cast_file)
+        cast = open(cast_file, "a", encoding="utf-8")
+        if resume is None:
+            header = {"version": 2, "width": width, "height": height,
+                      "timestamp": int(time.time()), "env": {"TERM": "xterm-256color"}}
+            cast.write(json.dumps(header) + "\n")
+            cast.flush()
+        else:
+            offset = resume + CAST_RESUME_GAP
+    except OSError:
+        cast = None
+
+    start = time.monotonic()
+    with open(log_file, "ab") as log:
+        while True:
+            chunk = os.read(0, CAST_READ_SIZE)
+            if not chunk:
+                break
+            log.write(chunk)
+            log.flush()
+            if cast is None:
+                continue
+            text = decoder.decode(chunk)
+            if not text:
+                continue
+            try:
+                elapsed = round(offset + time.monotonic() - start, 6)
+                cast.write(json.dumps([elapsed, "o", text]) + "\n")
+                cast.flush()
+            except OSError:
+                cast = None
+    if cast is not None:
+        cast.close()
+
+
 def main() -> None:
     if len(sys.argv) >= 2 and sys.argv[1] == "--write-status":
         write_status_cli(sys.argv[2:])
         return
+    if len(sys.argv) >= 2 and sys.argv[1] == "--record":
+        record_cli(sys.argv[2:])
+        return
 
     if len(sys.argv) < 3:
         print(f"Usage: {sys.argv[0]} CONFIG_PATH STATUS_FILE [TMUX_SOCK]", file=sys.stderr)
@@ -0,0 +1,219 @@
+#!/usr/bin/env python3
+# ABOUTME: The built-in `test` agent — a plain shell, or a scripted fake agent that
+# ABOUTME: plays a scenario file (output, file edits, commits, prompts, exit code).
+"""Scriptable fake agent for the built-in `test` agent.
+
+Without a scenario this is the old `test` agent: it execs `bash` (interactive)
+or `sh -c PROMPT` (headless), so nothing changes for existing users.
+
+With a scenario (`--scenario FILE` or YOLOAI_TEST_SCENARIO=FILE, relative to the
+working directory) it plays the file's steps in order and exits with its exit
+code, so create→diff→apply flows can be exercised deterministically without an
+API key or a model. The scenario is JSON:
+
+    {
+      "steps": [
+        {"say": "Looking at the code..."},
+        {"write": "hello.txt", "content": "hi\\n"},
+        {"append": "notes.md", "content": "- done\\n"},
+        {"delete": "old.txt"},
+        {"run": "go test ./..."},
+        {"commit": "add hello"},
+        {"ask": "Continue? ", "expect": "y"},
+        {"sleep": 0.5}
+      ],
+      "exit": 0
+    }
+
+Each step has exactly one action key. `run` fails the scenario on a non-zero
+exit unless "check": false. `ask` reads one line from stdin; with "expect" a
+different answer fails the scenario. {"exit": N} as a step stops there. The
+whole file is validated before the first step runs, so a typo never leaves a
+half-played scenario behind; an invalid file exits 2.
+"""
+
+from __future__ import annotations
+
+import argparse
+import json
+import os
+import subprocess
+import sys
+import time
+from typing import Any
+
+SCENARIO_ENV = "YOLOAI_TEST_SCENARIO"
+
+# Exit code for a scenario that cannot be loaded or is malformed, distinct from
+# any exit code the scenario itself asks for by default.
+EXIT_INVALID = 2
+
+# action -> (required extra keys, optional extra keys)
+ACTIONS: dict[str, tuple[set[str], set[str]]] = {
+    "say": (set(), set()),
+    "write": ({"content"}, set()),
+    "append": ({"content"}, set()),
+    "delete": (set(), set()),
+    "run": (set(), {"check"}),
+    "commit": (set(), set()),
+    "ask": (set(), {"expect"}),
+    "sleep": (set(), set()),
+    "exit": (set(), set()),
+}
+
+# Identity used for `commit` steps only when the sandbox has none configured.
+FALLBACK_IDENTITY = ["-c", "user.name=yoloai test agent", "-c", "user.email=test-agent@yoloai.invalid"]
+
+
+class ScenarioError(Exception):
+    """A scenario file that cannot be played."""
+
+
+def load_scenario(path: str) -> dict[str, Any]:
+    """Read and validate a scenario file, returning {"steps": [...], "exit": int}."""
+    try:
+        with open(path, encoding="utf-8") as f:
+            data = json.load(f)
+    except OSError as e:
+        raise ScenarioError(f"read scenario: {e}") from e
+    except json.JSONDecodeError as e:
+        raise ScenarioError(f"parse scenario {path}: {e}") from e
+    return validate_scenario(data)
+
+
+def validate_scenario(data: Any) -> dict[str, Any]:
+    """Check a decoded scenario's shape; raise ScenarioError naming the bad step."""
+    if not isinstance(data, dict):
+        raise ScenarioError("scenario must be a JSON object")
+    unknown = set(data) - {"steps", "exit"}
+    if unknown:
+        raise ScenarioError(f"unknown scenario key(s): {', '.join(sorted(unknown))}")
+    steps = data.get("steps", [])
+    if not isinstance(steps, list):
+        raise ScenarioError('"steps" must be a list')
+    exit_code = data.get("exit", 0)
+    if not _is_int(exit_code):
+        raise ScenarioError('"exit" must be an integer')
+    for i, step in enumerate(steps, 1):
+        _validate_step(i, step)
+    return {"steps": steps, "exit": exit_code}
+
+
+def _is_int(v: Any) -> bool:
+    return isinstance(v, int) and not isinstance(v, bool)
+
+
+def _validate_step(i: int, step: Any) -> None:
+    if not isinstance(step, dict):
+        raise ScenarioError(f"step {i}: must be a JSON object")
+    actions = [k for k in step if k in ACTIONS]
+    if len(actions) != 1:
+        raise ScenarioError(f"step {i}: needs exactly one of {', '.join(sorted(ACTIONS))}")
+    action = actions[0]
+    required, optional = ACTIONS[action]
+    missing = required - set(step)
+    if missing:
+        raise ScenarioError(f"step {i} ({action}): missing {', '.join(sorted(missing))}")
+    extra = set(step) - required - optional - {action}
+    if extra:
+        raise ScenarioError(f"step {i} ({action}): unknown key(s) {', '.join(sorted(extra))}")
+    value = step[action]
+    if action == "sleep":
+        if isinstance(value, bool) or not isinstance(value, (int, float)) or value < 0:
+            raise ScenarioError(f"step {i} (sleep): must be a non-negative number of seconds")
+    elif action == "exit":
+        if not _is_int(value):
+            raise ScenarioError(f"step {i} (exit): must be an integer")
+    elif not isinstance(value, str):
+        raise ScenarioError(f"step {i} ({action}): must be a string")
+    if "content" in step and not isinstance(step["content"], str):
+        raise ScenarioError(f"step {i} ({action}): content must be a string")
+    if "expect" in step and not isinstance(step["expect"], str):
+        raise ScenarioError(f"step {i} (ask): expect must be a string")
+    if "check" in step and not isinstance(step["check"], bool):
+        raise ScenarioError(f"step {i} (run): check must be true or false")
+
+
+def play(scenario: dict[str, Any], stdin=None, stdout=None) -> int:
+    """Run a validated scenario's steps and return the exit code to use."""
+    stdin = stdin or sys.stdin
+    stdout = stdout or sys.stdout
+
+    def say(text: str) -> None:
+        stdout.write(text if text.endswith("\n") else text + "\n")
+        stdout.flush()
+
+    for i, step in enumerate(scenario["steps"], 1):
+        action = next(k for k in step if k in ACTIONS)
+        value = step[action]
+        if action == "say":
+            say(value)
+        elif action in ("write", "append"):
+            parent = os.path.dirname(value)
+            if parent:
+                os.makedirs(parent, exist_ok=True)
+            with open(value, "w" if action == "write" else "a", encoding="utf-8") as f:
+                f.write(step["content"])
+        elif action == "delete":
+            try:
+                os.remove(value)
+            except FileNotFoundError:
+                pass
+        elif action == "run":
+            stdout.flush()
+            rc = subprocess.call(value, shell=True)
+            if rc != 0 and step.get("check", True):
+                say(f"[test agent] step {i}: `{value}` exited {rc}")
+                return rc
+        elif action == "commit":
+            rc = _commit(value)
+            if rc != 0:
+                say(f"[test agent] step {i}: commit failed (exit {rc})")
+                return rc
+        elif action == "ask":
+            stdout.write(value)
+            stdout.flush()
+            answer = stdin.readline().rstrip("\r\n")
+            if "expect" in step and answer != step["expect"]:
+                say(f"[test agent] step {i}: expected {step['expect']!r}, got {answer!r}")
+                return 1
+        elif action == "sleep":
+            time.sleep(value)
+        elif action == "exit":
+            return value
+    return scenario["exit"]
+
+
+def _commit(message: str) -> int:
+    """Stage everything and commit it, supplying an identity only if none is set."""
+    if subprocess.call(["git", "add", "-A"]) != 0:
+        return 1
+    identity: list[str] = []
+    if subprocess.call(["git", "config", "user.email"], stdout=subprocess.DEVNULL) != 0:
+        identity = FALLBACK_IDENTITY
+    return subprocess.call(["git", *identity, "commit", "-q", "--allow-empty", "-m", message])
+
+
+def main(argv: list[str] | None = None) -> int:
+    parser = argparse.ArgumentParser(prog="test-agent", description=__doc__.splitlines()[0])
+    parser.add_argument("-c", dest="prompt", help="headless prompt (a shell command without a scenario)")
+    parser.add_argument("--scenario", default=os.environ.get(SCENARIO_ENV, ""), help=f"scenario file (default ${SCENARIO_ENV})")
+    args = parser.parse_args(argv)
+
+    if not args.scenario:
+        if args.prompt is not None:
+            os.execvp("sh", ["sh", "-c", args.prompt])
+        os.execvp("bash", ["bash"])
+
+    try:
+        scenario = load_scenario(args.scenario)
+    except ScenarioError as e:
+        print(f"[test agent] {e}", file=sys.stderr)
+        return EXIT_INVALID
+    if args.prompt:
+        print(f"[test agent] prompt: {args.prompt}", flush=True)
+    return play(scenario)
+
+
+if __name__ == "__main__":
+    sys.exit(main())
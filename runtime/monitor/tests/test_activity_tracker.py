@@ -0,0 +1,58 @@
+# ABOUTME: Unit tests for status-monitor.py's ActivityTracker, which copies tmux's
+# ABOUTME: #{window_activity} stamp to the host-visible logs/agent-activity.json.
+"""Tests for ActivityTracker.
+
+The tracker's only inputs are tmux's answer for #{window_activity} and the
+activity file. tmux_cmd is replaced with a canned answer so these run without
+tmux: a new stamp is written, an unchanged one is not rewritten, and an
+unusable answer (tmux unreachable) leaves the file alone.
+"""
+
+from __future__ import annotations
+
+import json
+from pathlib import Path
+from typing import Iterator
+
+import pytest
+
+from conftest import load_status_monitor
+
+sm = load_status_monitor()
+
+
+@pytest.fixture
+def tmux_answer(monkeypatch: pytest.MonkeyPatch) -> Iterator[list[str]]:
+    """Make tmux_cmd return holder[0]; yields the one-element holder."""
+    answer = [""]
+    monkeypatch.setattr(sm, "tmux_cmd", lambda _args, _sock=None: answer[0])
+    yield answer
+
+
+def test_writes_new_stamp(tmp_path: Path, tmux_answer: list[str]) -> None:
+    path = tmp_path / "agent-activity.json"
+    tmux_answer[0] = "1760000000\n"
+    sm.ActivityTracker(str(path)).poll()
+    assert json.loads(path.read_text()) == {"schema_version": 1, "last_output": 1760000000}
+    assert not (tmp_path / "agent-activity.json.tmp").exists()
+
+
+def test_unchanged_stamp_not_rewritten(tmp_path: Path, tmux_answer: list[str]) -> None:
+    path = tmp_path / "agent-activity.json"
+    tracker = sm.ActivityTracker(str(path))
+    tmux_answer[0] = "1760000000"
+    tracker.poll()
+    path.write_text("sentinel")
+    tracker.poll()
+    assert path.read_text() == "sentinel"
+    tmux_answer[0] = "1760000042"
+    tracker.poll()
+    assert json.loads(path.read_text())["last_output"] == 1760000042
+
+
+def test_unusable_answer_leaves_file_alone(tmp_path: Path, tmux_answer: list[str]) -> None:
+    path = tmp_path / "agent-activity.json"
+    for answer in ["", "#{window_activity}", "0"]:
+        tmux_answer[0] = answer
+        sm.ActivityTracker(str(path)).poll()
+    assert not path.exists()
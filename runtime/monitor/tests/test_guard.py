@@ -0,0 +1,65 @@
+# ABOUTME: Tests for the destructive-command guard: install_guard's shims run
+# ABOUTME: for real, logging or refusing a matching command and exec'ing the rest.
+"""Tests for install_guard and the shim it writes.
+
+The shim is a generated script, so these run it as a subprocess with the
+returned PATH — the same way the agent's shell would reach it — in front of
+a fake "rm" that records that it ran.
+"""
+
+from __future__ import annotations
+
+import json
+import os
+import subprocess
+from pathlib import Path
+
+from conftest import load_sandbox_setup
+
+sandbox_setup = load_sandbox_setup()
+
+
+def _install(tmp_path: Path, mode: str) -> tuple[dict[str, str], Path, Path]:
+    yoloai_dir = tmp_path / "yoloai"
+    (yoloai_dir / "logs").mkdir(parents=True)
+    real_bin = tmp_path / "bin"
+    real_bin.mkdir()
+    ran = tmp_path / "ran"
+    (real_bin / "rm").write_text(f"#!/bin/sh\necho \"$@\" > {ran}\n")
+    (real_bin / "rm").chmod(0o755)
+    cfg = {"guard": {"mode": mode, "commands": ["rm -rf"]}}
+    env = sandbox_setup.install_guard(cfg, str(yoloai_dir), str(real_bin) + os.pathsep + "/usr/bin:/bin")
+    return {**os.environ, **env}, ran, yoloai_dir / "logs" / "sandbox.jsonl"
+
+
+def _log_events(log: Path) -> list[dict[str, object]]:
+    if not log.exists():
+        return []
+    return [json.loads(line) for line in log.read_text().splitlines()]
+
+
+def test_guard_off_installs_nothing(tmp_path: Path) -> None:
+    assert sandbox_setup.install_guard({}, str(tmp_path), "/usr/bin") == {}
+
+
+def test_guard_log_mode_runs_the_command_and_logs_it(tmp_path: Path) -> None:
+    env, ran, log = _install(tmp_path, "log")
+    result = subprocess.run(["rm", "-fr", "build"], env=env, capture_output=True, text=True)
+    assert result.returncode == 0, result.stderr
+    assert ran.read_text() == "-fr build\n"
+    events = _log_events(log)
+    assert [e["rule"] for e in events] == ["rm -rf"]
+    assert events[0]["blocked"] is False
+
+
+def test_guard_block_mode_refuses_a_match_only(tmp_path: Path) -> None:
+    env, ran, log = _install(tmp_path, "block")
+    result = subprocess.run(["rm", "-rf", "build"], env=env, capture_output=True, text=True)
+    assert result.returncode == 126
+    assert "refused" in result.stderr
+    assert not ran.exists()
+    assert _log_events(log)[0]["blocked"] is True
+
+    result = subprocess.run(["rm", "file.txt"], env=env, capture_output=True, text=True)
+    assert result.returncode == 0, result.stderr
+    assert ran.read_text() == "file.txt\n"
@@ -0,0 +1,31 @@
+# ABOUTME: Tests block_push_env against real git: a push through the rewritten
+# ABOUTME: config fails with a message naming --allow-push; a fetch still works.
+"""Tests for the default push block.
+
+block_push_env itself is pure, but whether git honors the rewrite is the
+point, so this drives a real clone of a local bare repository.
+"""
+
+from __future__ import annotations
+
+import os
+import subprocess
+from pathlib import Path
+
+import setup_helpers
+
+
+def test_block_push_env_fails_a_push_but_not_a_fetch(tmp_path: Path) -> None:
+    def git(*args: str, cwd: Path = tmp_path / "work", env: dict[str, str] | None = None) -> subprocess.CompletedProcess[str]:
+        return subprocess.run(["git", *args], cwd=cwd, env=env, capture_output=True, text=True)
+
+    git("init", "-q", "--bare", str(tmp_path / "remote.git"), cwd=tmp_path)
+    git("clone", "-q", str(tmp_path / "remote.git"), str(tmp_path / "work"), cwd=tmp_path)
+    git("-c", "user.name=t", "-c", "user.email=t@t", "commit", "-q", "--allow-empty", "-m", "x")
+    env = {**os.environ, **setup_helpers.block_push_env(dict(os.environ))}
+
+    pushed = git("push", "origin", "HEAD", env=env)
+    assert pushed.returncode != 0
+    assert "--allow-push" in pushed.stderr
+    assert git("fetch", "origin", env=env).returncode == 0
+    assert git("push", "origin", "HEAD").returncode == 0, "the repo's own config is untouched"
@@ -172,6 +172,45 @@ def test_compose_prompt_content_none_when_nothing() -> None:
     assert setup_helpers.compose_prompt_content("", "") is None
 
 
+# --- inbox ---
+
+
+def test_pending_inbox_files_returns_settled_md_files_in_name_order(tmp_path: Path) -> None:
+    for name in ("b.md", "a.md", "notes.txt", ".staging.md"):
+        (tmp_path / name).write_text("x")
+    (tmp_path / "delivered").mkdir()
+    now = (tmp_path / "a.md").stat().st_mtime + 10
+    assert setup_helpers.pending_inbox_files(str(tmp_path), now) == [
+        str(tmp_path / "a.md"),
+        str(tmp_path / "b.md"),
+    ]
+
+
+def test_pending_inbox_files_skips_files_still_being_written(tmp_path: Path) -> None:
+    (tmp_path / "fresh.md").write_text("x")
+    now = (tmp_path / "fresh.md").stat().st_mtime
+    assert setup_helpers.pending_inbox_files(str(tmp_path), now) == []
+
+
+def test_pending_inbox_files_missing_dir_is_empty(tmp_path: Path) -> None:
+    assert setup_helpers.pending_inbox_files(str(tmp_path / "nope"), 0.0) == []
+
+
+def test_read_inbox_prompt_normalizes_windows_text(tmp_path: Path) -> None:
+    path = tmp_path / "p.md"
+    path.write_bytes(b"\xef\xbb\xbffix the bug\r\nthen test\r\n")
+    assert setup_helpers.read_inbox_prompt(str(path)) == "fix the bug\nthen test"
+
+
+def test_read_agent_status(tmp_path: Path) -> None:
+    path = tmp_path / "agent-status.json"
+    path.write_text(json.dumps({"status": "idle"}))
+    assert setup_helpers.read_agent_status(str(path)) == "idle"
+    path.write_text("not json")
+    assert setup_helpers.read_agent_status(str(path)) == ""
+    assert setup_helpers.read_agent_status(str(tmp_path / "missing.json")) == ""
+
+
 # --- load_secret_files ---
 
 
@@ -286,6 +325,108 @@ def test_build_agent_launch_command_applies_launch_prefix() -> None:
     assert out == "PATH=\"/opt/bin:$PATH\" export T='x'; cd '/w' && exec claude"
 
 
+# --- agent_working_dir ---
+
+
+def test_agent_working_dir_joins_subdir() -> None:
+    assert setup_helpers.agent_working_dir("/w", "packages/api") == os.path.join("/w", "packages", "api")
+    assert setup_helpers.agent_working_dir("/w", "") == "/w"
+    assert setup_helpers.agent_working_dir(None, "packages/api") is None
+
+
+# --- role_preamble ---
+
+
+def test_role_preamble_names_the_others_and_the_notes_file() -> None:
+    text = setup_helpers.role_preamble("tester", ["implementer", "tester", "reviewer"], "/yoloai/files/notes.md")
+    assert '"tester"' in text
+    assert "implementer, reviewer." in text
+    assert "/yoloai/files/notes.md" in text
+
+
+# --- guard_match / guard_real_binary ---
+
+
+RULES = ["rm -rf", "git push", "git reset --hard", "git branch -D"]
+
+
+@pytest.mark.parametrize("name,args,want", [
+    ("rm", ["-rf", "build"], "rm -rf"),
+    ("rm", ["-fr", "build"], "rm -rf"),
+    ("rm", ["-r", "-f", "build"], "rm -rf"),
+    ("rm", ["-r", "build"], None),
+    ("rm", ["--", "-rf"], None),
+    ("git", ["-C", "repo", "push", "origin"], "git push"),
+    ("git", ["reset", "--hard=HEAD~1"], "git reset --hard"),
+    ("git", ["reset", "--soft", "HEAD~1"], None),
+    ("git", ["branch", "-D", "topic"], "git branch -D"),
+    ("git", ["branch", "-d", "topic"], None),
+    ("git", ["pull", "origin"], None),
+    ("ls", ["-rf"], None),
+])
+def test_guard_match(name: str, args: list[str], want: str | None) -> None:
+    assert setup_helpers.guard_match(RULES, name, args) == want
+
+
+def test_guard_real_binary_skips_the_guard_dir(tmp_path: Path) -> None:
+    guard, real = tmp_path / "guard", tmp_path / "bin"
+    for d in (guard, real):
+        d.mkdir()
+        (d / "rm").write_text("#!/bin/sh\n")
+        (d / "rm").chmod(0o755)
+    path = os.pathsep.join([str(guard), str(real)])
+    assert setup_helpers.guard_real_binary("rm", path, str(guard)) == str(real / "rm")
+    assert setup_helpers.guard_real_binary("git", path, str(guard)) is None
+
+
+# --- block_push_env ---
+
+
+def test_block_push_env_appends_after_existing_config() -> None:
+    env = setup_helpers.block_push_env({"GIT_CONFIG_COUNT": "1"})
+    assert env["GIT_CONFIG_COUNT"] == "2"
+    assert env["GIT_CONFIG_KEY_1"] == f"url.{setup_helpers.PUSH_BLOCKED_URL}.pushInsteadOf"
+    assert env["GIT_CONFIG_VALUE_1"] == ""
+
+
+# --- egress_proxy_env ---
+
+
+def test_egress_proxy_env_points_at_loopback_proxy() -> None:
+    env = setup_helpers.egress_proxy_env({"NO_PROXY": "internal.example,localhost"}, port=3128)
+    assert env["HTTPS_PROXY"] == "http://127.0.0.1:3128"
+    assert env["http_proxy"] == "http://127.0.0.1:3128"
+    assert env["NO_PROXY"] == "internal.example,localhost,127.0.0.1,::1"
+    assert env["no_proxy"] == env["NO_PROXY"]
+
+
+def test_egress_proxy_env_keeps_the_broker_injector_direct() -> None:
+    env = setup_helpers.egress_proxy_env({"YOLOAI_BROKER_INJECTOR_ENDPOINT": "172.17.0.1:41234"})
+    assert env["NO_PROXY"] == "localhost,127.0.0.1,::1,172.17.0.1"
+
+
+def test_egress_proxy_env_leaves_an_existing_proxy_alone() -> None:
+    assert setup_helpers.egress_proxy_env({"https_proxy": "http://corp:8080"}) == {}
+
+
+# --- pre_launch_env_changes ---
+
+
+def test_pre_launch_env_changes_reports_set_and_changed_only() -> None:
+    before = {"PATH": "/usr/bin", "HOME": "/home/yoloai", "GONE": "x"}
+    dump = b"PATH=/opt/tool/bin:/usr/bin\0HOME=/home/yoloai\0HTTPS_PROXY=http://proxy:3128\0"
+    assert setup_helpers.pre_launch_env_changes(before, dump) == {
+        "PATH": "/opt/tool/bin:/usr/bin",
+        "HTTPS_PROXY": "http://proxy:3128",
+    }
+
+
+def test_pre_launch_env_changes_skips_shell_bookkeeping() -> None:
+    # bash's own variables and exported functions are not the snippet's exports.
+    dump = b"SHLVL=1\0_=/usr/bin/env\0PWD=/w\0BASH_FUNC_f%%=() {  true\n}\0A=a=b\0"
+    assert setup_helpers.pre_launch_env_changes({}, dump) == {"A": "a=b"}
+
+
 def test_dockerd_storage_args_overlay_backing_uses_fuse() -> None:
     # No real-fs volume (backing is the overlay rootfs): overlay2 can't nest, so
     # force fuse-overlayfs as the fallback driver.
@@ -0,0 +1,84 @@
+# ABOUTME: Tests test-agent.py's scenario player: validation rejects a bad file up
+# ABOUTME: front, and a played scenario edits, commits, reads input and exits as told.
+"""Tests for the scripted `test` agent.
+
+Validation is pure; play() is driven against a real git repository in a temp
+dir so `commit` steps are exercised for real. stdin/stdout are StringIO.
+"""
+
+from __future__ import annotations
+
+import importlib.util
+import io
+import subprocess
+from pathlib import Path
+from types import ModuleType
+
+import pytest
+
+
+def _load() -> ModuleType:
+    path = Path(__file__).resolve().parent.parent / "test-agent.py"
+    spec = importlib.util.spec_from_file_location("test_agent", str(path))
+    assert spec is not None and spec.loader is not None
+    mod = importlib.util.module_from_spec(spec)
+    spec.loader.exec_module(mod)
+    return mod
+
+
+ta = _load()
+
+
+@pytest.mark.parametrize(
+    "data, msg",
+    [
+        ([], "JSON object"),
+        ({"steps": [], "bogus": 1}, "unknown scenario key"),
+        ({"exit": "1"}, '"exit" must be an integer'),
+        ({"steps": [{"say": "a", "write": "b", "content": ""}]}, "exactly one"),
+        ({"steps": [{"write": "a"}]}, "missing content"),
+        ({"steps": [{"say": "a", "colour": "red"}]}, "unknown key"),
+        ({"steps": [{"sleep": -1}]}, "non-negative"),
+        ({"steps": [{"run": "true", "check": "no"}]}, "check must be"),
+    ],
+)
+def test_validate_rejects(data: object, msg: str) -> None:
+    with pytest.raises(ta.ScenarioError, match=msg):
+        ta.validate_scenario(data)
+
+
+def test_play_edits_commits_and_exits(tmp_path: Path, monkeypatch: pytest.MonkeyPatch) -> None:
+    subprocess.run(["git", "init", "-q"], cwd=tmp_path, check=True)
+    monkeypatch.chdir(tmp_path)
+    scenario = ta.validate_scenario(
+        {
+            "steps": [
+                {"say": "working"},
+                {"write": "src/a.txt", "content": "one\n"},
+                {"commit": "add a"},
+                {"ask": "go on? ", "expect": "y"},
+                {"append": "src/a.txt", "content": "two\n"},
+            ],
+            "exit": 3,
+        }
+    )
+    out = io.StringIO()
+    assert ta.play(scenario, stdin=io.StringIO("y\n"), stdout=out) == 3
+    assert "working\n" in out.getvalue()
+    assert (tmp_path / "src/a.txt").read_text() == "one\ntwo\n"
+    log = subprocess.run(["git", "log", "--format=%s"], cwd=tmp_path, capture_output=True, text=True)
+    assert log.stdout.strip() == "add a"
+
+
+def test_play_wrong_answer_fails(tmp_path: Path, monkeypatch: pytest.MonkeyPatch) -> None:
+    monkeypatch.chdir(tmp_path)
+    scenario = ta.validate_scenario({"steps": [{"ask": "? ", "expect": "y"}, {"write": "x", "content": ""}]})
+    assert ta.play(scenario, stdin=io.StringIO("n\n"), stdout=io.StringIO()) == 1
+    assert not (tmp_path / "x").exists()
+
+
+def test_exit_step_stops_early(tmp_path: Path, monkeypatch: pytest.MonkeyPatch) -> None:
+    monkeypatch.chdir(tmp_path)
+    scenario = ta.validate_scenario({"steps": [{"exit": 7}, {"write": "x", "content": ""}], "exit": 0})
+    assert ta.play(scenario, stdin=io.StringIO(), stdout=io.StringIO()) == 7
+    assert not (tmp_path / "x").exists()
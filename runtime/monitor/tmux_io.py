@@ -28,6 +28,11 @@ Runner = Callable[..., "subprocess.CompletedProcess[str]"]
 
 _runner: Runner = subprocess.run
 
+# AGENT_WINDOW targets the agent's window: the session's first. Plain "main"
+# would mean whichever window is current, and attaching to another one (a
+# split-role window, the VS Code tunnel) changes that.
+AGENT_WINDOW = "main:{start}"
+
 # Well-known locations checked when tmux is not found on PATH (Homebrew on
 # Apple Silicon, Homebrew on Intel, system).
 _TMUX_FALLBACK_PATHS = ("/opt/homebrew/bin/tmux", "/usr/local/bin/tmux", "/usr/bin/tmux")
@@ -177,4 +182,4 @@ def tmux_output(*args: str, socket: str | None = None) -> str:
 
 def set_title(title: str, socket: str | None = None) -> None:
     """Set tmux window title."""
-    tmux("rename-window", "-t", "main", title, socket=socket)
+    tmux("rename-window", "-t", AGENT_WINDOW, title, socket=socket)
@@ -0,0 +1,50 @@
+#!/bin/sh
+# ABOUTME: In-sandbox live diff — redraws `git diff --stat` of a :copy work copy
+# ABOUTME: against its yoloai baseline every few seconds, for a tmux side pane.
+
+# The baselines come from logs/baselines ("<sha> <mount path>" per :copy dir),
+# which the host rewrites whenever the baseline moves (create, reset, apply,
+# rebase), so this follows the same baseline `yoloai diff` uses. The directory
+# is the one given, else the deepest :copy dir containing $PWD, else the first.
+#
+# Untracked files count as changes, as in `yoloai diff`: they are staged into
+# a throwaway copy of the index, so the agent's own index is never touched.
+
+YOLOAI_DIR="${YOLOAI_DIR:-/yoloai}"
+baselines="${YOLOAI_DIR}/logs/baselines"
+interval="${YOLOAI_DIFFWATCH_INTERVAL:-2}"
+want="${1:-$PWD}"
+
+# pick prints "<sha> <dir>" for the :copy dir that holds $want.
+pick() {
+	awk -v want="$want" '
+		{ sha = $1; dir = substr($0, length($1) + 2) }
+		NR == 1 { first = sha " " dir }
+		want == dir || index(want, dir "/") == 1 {
+			if (length(dir) > best) { best = length(dir); hit = sha " " dir }
+		}
+		END { if (hit != "") print hit; else if (first != "") print first }
+	' "$baselines" 2>/dev/null
+}
+
+index=$(mktemp "${TMPDIR:-/tmp}/yoloai-diffwatch.XXXXXX") || exit 1
+trap 'rm -f "$index"' EXIT
+trap 'exit 0' INT TERM
+
+while :; do
+	entry=$(pick)
+	sha=${entry%% *}
+	dir=${entry#* }
+	out=$(
+		if [ -z "$entry" ]; then
+			printf 'No :copy directory with a baseline (%s).\n' "$baselines"
+		else
+			cp "$(git -C "$dir" rev-parse --path-format=absolute --git-path index 2>/dev/null)" "$index" 2>/dev/null || rm -f "$index"
+			GIT_INDEX_FILE="$index" git -C "$dir" add -A 2>/dev/null
+			stat=$(GIT_INDEX_FILE="$index" git -C "$dir" diff --cached --stat=72 "$sha" 2>&1)
+			printf '%s  (vs baseline %.12s)\n\n%s\n' "$dir" "$sha" "${stat:-No changes.}"
+		fi
+	)
+	printf '\033[H\033[2J%s\n\n[yoloai] refreshing every %ss — Ctrl-C closes\n' "$out" "$interval"
+	sleep "$interval"
+done
@@ -2,9 +2,14 @@
 // ABOUTME: The canonical identity is the com.yoloai.* label set (D62), not the
 // ABOUTME: yoloai-* name prefix — so prune reclaims exactly what yoloai created
 // ABOUTME: and never a foreign container that merely happens to be named yoloai-*.
+// ABOUTME: DescribeInstance reads the descriptive labels back for prune's report.
 package runtime
 
-import "github.com/kstenerud/yoloai/internal/config"
+import (
+	"strings"
+
+	"github.com/kstenerud/yoloai/internal/config"
+)
 
 // IsOrphanCandidate reports whether a backend container carrying these labels is
 // a yoloai-created instance owned by `principal` — i.e. a candidate for orphan
@@ -70,3 +75,22 @@ func IsOrphanCandidate(labels map[string]string, principal config.PrincipalSegme
 func legacyCLIInstance(labels map[string]string, principal config.PrincipalSegment) bool {
 	return principal == config.CLIPrincipal && labels[LabelPrincipal] == ""
 }
+
+// DescribeInstance summarizes what an instance's descriptive labels say about
+// it — the yoloai version that created it, its profile, its workdir hash — for
+// prune to show beside an orphan it found, so the operator can tell what it
+// was before removing it. "" when none are set (an instance from before the
+// labels existed).
+func DescribeInstance(labels map[string]string) string {
+	var parts []string
+	if v := labels[LabelVersion]; v != "" {
+		parts = append(parts, "yoloai "+v)
+	}
+	if p := labels[LabelProfile]; p != "" {
+		parts = append(parts, "profile "+p)
+	}
+	if w := labels[LabelWorkdir]; w != "" {
+		parts = append(parts, LabelWorkdir+"="+w)
+	}
+	return strings.Join(parts, ", ")
+}
@@ -1,6 +1,7 @@
 // ABOUTME: IsOrphanCandidate's principal-scoping rules for prune eligibility —
 // ABOUTME: a container is only a candidate if its principal label matches the
-// ABOUTME: caller's (DF19), never cross-principal or non-yoloai containers.
+// ABOUTME: caller's (DF19), never cross-principal or non-yoloai containers; and the
+// ABOUTME: orphan description prune shows from the descriptive labels.
 package runtime
 
 import (
@@ -91,3 +92,18 @@ func TestIsOrphanCandidate(t *testing.T) {
 		})
 	}
 }
+
+func TestDescribeInstance(t *testing.T) {
+	full := map[string]string{
+		LabelSandbox: "mybox",
+		LabelVersion: "1.4.0",
+		LabelProfile: "go",
+		LabelWorkdir: "0123456789abcdef",
+	}
+	if got, want := DescribeInstance(full), "yoloai 1.4.0, profile go, com.yoloai.workdir=0123456789abcdef"; got != want {
+		t.Errorf("DescribeInstance(full) = %q, want %q", got, want)
+	}
+	if got := DescribeInstance(map[string]string{LabelSandbox: "old"}); got != "" {
+		t.Errorf("DescribeInstance(unlabeled) = %q, want empty", got)
+	}
+}
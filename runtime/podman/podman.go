@@ -39,6 +39,7 @@ var descriptor = runtime.BackendDescriptor{
 		// IPv4 only — see the note in runtime/docker/docker.go (DF104).
 		NetworkIsolation:     true,
 		CapAdd:               true,
+		FakeTime:             true,
 		HostFilesystem:       false,
 		FilesystemLocality:   runtime.LocalityHostSide,
 		GitExecInConfinement: true, // copy-mode work-copy git runs in-container (audit C1); GitExec inherited from docker.Runtime
@@ -100,6 +101,8 @@ var _ runtime.IsolationCapabilityProvider = (*Runtime)(nil)
 var _ runtime.InteractiveSession = (*Runtime)(nil)
 var _ runtime.CachePruner = (*Runtime)(nil)       // inherited from embedded docker.Runtime
 var _ runtime.DiskUsageReporter = (*Runtime)(nil) // inherited; image bytes via podmanImageBytes (LayersSize=0 workaround)
+var _ runtime.LabelReader = (*Runtime)(nil)       // inherited from embedded docker.Runtime
+var _ runtime.LogLineReader = (*Runtime)(nil)     // inherited from embedded docker.Runtime
 
 // New creates a Podman Runtime by discovering the Podman socket and
 // connecting via the Docker SDK.
@@ -4,10 +4,13 @@ package runtime
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"io"
 	"io/fs"
 	"log/slog"
+	"path/filepath"
 	"strings"
 	"syscall"
 	"time"
@@ -25,6 +28,9 @@ var (
 type PruneItem struct {
 	Kind string // "container", "vm", "image"
 	Name string // instance name or short image ID
+	// Detail describes an orphaned instance from its descriptive labels
+	// (DescribeInstance); "" when the backend has none to read.
+	Detail string
 }
 
 // PruneResult summarizes orphaned resources found by a backend.
@@ -69,6 +75,11 @@ type PortMapping struct {
 type ResourceLimits struct {
 	NanoCPUs int64 // CPU limit in Docker NanoCPUs (cpus * 1e9)
 	Memory   int64 // Memory limit in bytes
+	// DiskBytes caps the instance's writable layer, where the backend can
+	// (Docker: storage-opt size, on a storage driver with quota support).
+	// Backends that can't enforce it ignore it; the orchestrator still caps
+	// the sandbox directory itself.
+	DiskBytes int64
 }
 
 // Instance label keys. Backends that support labels stamp these so an
@@ -79,6 +90,27 @@ const (
 	LabelSandbox   = "com.yoloai.sandbox"
 )
 
+// Descriptive instance label keys. Unlike the identity pair above, nothing in
+// yoloai keys ownership off these: they let an operator or third-party tool
+// (`docker ps --filter label=com.yoloai.workdir=<hash>`) say what an instance
+// is without reading the sandbox dir. yoloai reads them back where the sandbox
+// dir can't answer: prune describes an orphan with them (DescribeInstance),
+// and `system recover` rebuilds a lost record from them (LabelReader). Profile
+// is omitted when no profile is set.
+const (
+	LabelVersion = "com.yoloai.version"
+	LabelProfile = "com.yoloai.profile"
+	LabelWorkdir = "com.yoloai.workdir"
+)
+
+// WorkdirHash returns the LabelWorkdir value for a workdir host path: a short
+// sha256 prefix, so instances can be matched to a project without the label
+// publishing the host path to everything that can list containers.
+func WorkdirHash(hostPath string) string {
+	sum := sha256.Sum256([]byte(filepath.Clean(hostPath)))
+	return hex.EncodeToString(sum[:])[:16]
+}
+
 // InstanceConfig holds the parameters for creating a sandbox instance.
 // It is the substrate's agent-free provision config — the ProvisionSpec of
 // docs/contributors/design/substrate-interface.md. Agent-launch fields (agent
@@ -140,6 +172,7 @@ type InstanceConfig struct {
 type InstanceInfo struct {
 	Running   bool
 	Suspended bool // true if the instance is suspended (state saved to disk, not consuming CPU/RAM)
+	Paused    bool // true if the instance's processes are frozen in memory (Pauser); Running stays true
 }
 
 // ExecResult holds the output of a non-interactive command execution.
@@ -259,6 +292,7 @@ type BackendDescriptor struct {
 type BackendCaps struct {
 	NetworkIsolation   bool               // supports --network=isolated (iptables domain filtering)
 	CapAdd             bool               // supports cap_add, devices, and setup commands
+	FakeTime           bool               // runs the Linux base image, whose libfaketime ContainerEnv can preload (--faketime)
 	HostFilesystem     bool               // true when sandbox state lives on the host (seatbelt, future SSH)
 	ContainerAttach    bool               // exposes a docker-compatible container surface so VS Code's "Attach to Running Container" works
 	VMRuntimeDir       string             // path to yoloai state inside the VM; "" means /yoloai (docker default)
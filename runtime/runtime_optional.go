@@ -8,6 +8,7 @@ import (
 	"context"
 	"errors"
 	"io"
+	"time"
 
 	"github.com/kstenerud/yoloai/internal/config"
 	"github.com/kstenerud/yoloai/runtime/caps"
@@ -27,7 +28,8 @@ import (
 //     or report a backend-managed resource (user namespaces, isolation
 //     prerequisites, simulator images, VM-slot census, disk usage).
 //  3. Optional operations — extra verbs only some backends can perform (stdio
-//     exec, cache prune, log tail, agent-command wrapping).
+//     exec, cache prune, log tail, denied-write report, agent-command wrapping,
+//     inspect caching).
 
 // ===== 1. Path & exec translators =====
 
@@ -378,6 +380,56 @@ type Renamer interface {
 	Rename(ctx context.Context, oldName, newName string) error
 }
 
+// Pauser is an optional backend interface: freeze every process in a running
+// instance in place, and thaw it again, without losing in-memory state — a
+// frozen agent keeps its conversation, open files and child processes.
+// Implemented by docker and podman (docker pause, the cgroup freezer),
+// containerd (task pause/resume) and seatbelt (SIGSTOP/SIGCONT of the
+// instance's process groups). A paused instance still reports Running, with
+// InstanceInfo.Paused set.
+//
+// Tart does NOT implement it: its only freeze is `tart suspend`, and
+// Virtualization.framework can't restore a VM with VirtioFS mounts from a
+// suspend snapshot, so a suspended sandbox comes back with a fresh agent (see
+// tart.Runtime.Stop). apple's CLI has no pause verb.
+type Pauser interface {
+	// Pause freezes a running instance. Returns ErrNotRunning if it is not up.
+	Pause(ctx context.Context, name string) error
+	// Unpause thaws an instance frozen by Pause.
+	Unpause(ctx context.Context, name string) error
+}
+
+// ImageCommitter is an optional backend interface: save an instance's
+// filesystem as a new image, and check that one exists, so sandboxes can be
+// created from a hand-prepared environment (InstanceConfig.ImageRef) instead
+// of a Dockerfile. Bind mounts — the work copies, the sandbox's state dir,
+// secrets — are not part of the instance's own filesystem and are never
+// captured. Implemented by docker and podman (docker commit).
+//
+// containerd has no commit verb in yoloai's client, tart's VMs are cloned
+// from bases rather than images, and seatbelt has no image at all; none of
+// them implement it.
+type ImageCommitter interface {
+	// CommitImage saves instance name's filesystem as imageRef, replacing any
+	// image already tagged imageRef. Returns ErrNotFound if the instance does
+	// not exist.
+	CommitImage(ctx context.Context, name, imageRef string) error
+	// HasImage reports whether imageRef exists locally.
+	HasImage(ctx context.Context, imageRef string) (bool, error)
+}
+
+// LabelReader is an optional backend interface: read back the labels an
+// instance was created with (InstanceConfig.Labels). The descriptive labels —
+// LabelVersion, LabelProfile, LabelWorkdir — outlive a damaged sandbox dir
+// there, so `system recover` can rebuild what the work copies alone can't say.
+// Implemented by docker and podman (container inspect). The backends without
+// native labels (tart, seatbelt) can't, and containerd and apple don't yet.
+type LabelReader interface {
+	// InstanceLabels returns instance name's labels. Returns ErrNotFound if
+	// the instance does not exist.
+	InstanceLabels(ctx context.Context, name string) (map[string]string, error)
+}
+
 // ExitStatus is the result of a launched process exiting. Signaled and Signal
 // are populated when the backend can report signal death; docker exec cannot,
 // so it always reports Signaled=false.
@@ -520,6 +572,113 @@ func LogsFor(ctx context.Context, rt Backend, name string, tail int) string {
 	return ""
 }
 
+// LogLine is one line of an instance's runtime output with the time the
+// runtime recorded it.
+type LogLine struct {
+	Time time.Time
+	Text string
+}
+
+// LogLineReader is an optional interface for backends whose runtime keeps a
+// timestamped log of the instance's stdout/stderr (docker, podman). It returns
+// the lines recorded after since (all of them when since is zero), oldest first.
+// `yoloai log` merges them with the sandbox's own structured logs.
+type LogLineReader interface {
+	LogLines(ctx context.Context, name string, since time.Time) ([]LogLine, error)
+}
+
+// LogLinesFor returns the instance's runtime log lines after since. supported
+// is false when the backend does not implement LogLineReader.
+func LogLinesFor(ctx context.Context, rt Backend, name string, since time.Time) (lines []LogLine, supported bool, err error) {
+	r, ok := rt.(LogLineReader)
+	if !ok {
+		return nil, false, nil
+	}
+	lines, err = r.LogLines(ctx, name, since)
+	return lines, true, err
+}
+
+// DeniedWrite is one file write the backend's confinement refused.
+type DeniedWrite struct {
+	Time      time.Time // when the write was refused; zero when unknown
+	Process   string    // process name as logged, e.g. "npm"
+	Operation string    // the denied operation, e.g. "file-write-create"
+	Path      string    // the path the process tried to write
+}
+
+// DeniedWriteReporter is an optional interface for backends whose confinement
+// is a host-side policy that logs the writes it refuses (seatbelt). It returns
+// the refused writes for one instance since the given time, oldest first.
+// Container and VM backends confine by giving the agent its own filesystem, so
+// nothing outside it is ever "denied" and they do not implement this.
+type DeniedWriteReporter interface {
+	DeniedWrites(ctx context.Context, name string, since time.Time) ([]DeniedWrite, error)
+}
+
+// DeniedWritesFor returns the instance's refused writes. supported is false
+// when the backend does not implement DeniedWriteReporter.
+func DeniedWritesFor(ctx context.Context, rt Backend, name string, since time.Time) (denied []DeniedWrite, supported bool, err error) {
+	r, ok := rt.(DeniedWriteReporter)
+	if !ok {
+		return nil, false, nil
+	}
+	denied, err = r.DeniedWrites(ctx, name, since)
+	return denied, true, err
+}
+
+// ResourceUsage is a running instance's CPU and memory use at one moment.
+type ResourceUsage struct {
+	// CPUPercent is relative to one CPU, as docker stats and top report it:
+	// 200 means two CPUs' worth of work.
+	CPUPercent float64
+	// MemoryBytes is the memory in use, not counting reclaimable page cache.
+	MemoryBytes int64
+	// MemoryLimitBytes is the instance's memory cap; 0 when unknown.
+	MemoryLimitBytes int64
+}
+
+// UsageReporter is an optional interface for backends that can measure a
+// running instance's CPU and memory use. Implemented by docker and podman
+// (docker stats); `yoloai top` shows "-" for sandboxes on other backends.
+// Returns ErrNotFound or ErrNotRunning when there is nothing to measure.
+type UsageReporter interface {
+	Usage(ctx context.Context, name string) (ResourceUsage, error)
+}
+
+// UsageFor returns the instance's resource use. supported is false when the
+// backend does not implement UsageReporter.
+func UsageFor(ctx context.Context, rt Backend, name string) (usage ResourceUsage, supported bool, err error) {
+	r, ok := rt.(UsageReporter)
+	if !ok {
+		return ResourceUsage{}, false, nil
+	}
+	usage, err = r.Usage(ctx, name)
+	return usage, true, err
+}
+
+// InspectCacher is an optional interface for backends that can answer Inspect
+// from a cache kept current by the backend's own change events. Implemented by
+// docker and podman (the container event stream). A process that lists the
+// same sandboxes again and again while it runs (serve, the daemon, top) turns
+// it on for the runtimes it keeps open; a one-shot command never does.
+type InspectCacher interface {
+	// CacheInspects answers Inspect with a result up to ttl old until ctx is
+	// cancelled or the runtime is closed. An instance's entry is dropped as
+	// soon as the backend reports a change to it, and nothing is cached while
+	// the event stream is down. Call it before sharing the runtime.
+	CacheInspects(ctx context.Context, ttl time.Duration)
+}
+
+// CacheInspectsFor turns on rt's inspect cache and reports whether it has
+// one; it is a no-op for backends that don't implement InspectCacher.
+func CacheInspectsFor(ctx context.Context, rt Backend, ttl time.Duration) bool {
+	c, ok := rt.(InspectCacher)
+	if ok {
+		c.CacheInspects(ctx, ttl)
+	}
+	return ok
+}
+
 // ===== 4. Interactive session =====
 
 // InteractiveSession is implemented by backends that expose an interactive
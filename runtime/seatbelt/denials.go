@@ -0,0 +1,81 @@
+package seatbelt
+
+// ABOUTME: Reports writes the SBPL profile refused, read back from the macOS
+// ABOUTME: unified log by the per-instance marker the profile tags them with.
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/kstenerud/yoloai/internal/sysexec"
+	"github.com/kstenerud/yoloai/runtime"
+)
+
+var _ runtime.DeniedWriteReporter = (*Runtime)(nil)
+
+const (
+	// logBin is the macOS unified-log CLI.
+	logBin = "/usr/bin/log"
+
+	// denialMarkerPrefix prefixes the message the profile attaches to refused
+	// writes; the instance name follows it.
+	denialMarkerPrefix = "yoloai-seatbelt:"
+
+	// logTimeLayout is the timestamp `log show --style syslog` prints.
+	logTimeLayout = "2006-01-02 15:04:05.000000-0700"
+)
+
+// denialLineRE matches the kernel's sandbox violation text, e.g.
+// "Sandbox: npm(4242) deny(1) file-write-create /private/tmp/x".
+var denialLineRE = regexp.MustCompile(`Sandbox: (.+?)\(\d+\) deny\(\d+\) (file-write\S*) (.+)$`)
+
+// denialMarker is the message the profile attaches to this instance's refused
+// writes. It is unique per instance, so one sandbox's report never shows
+// another's denials.
+func denialMarker(instanceName string) string {
+	return denialMarkerPrefix + instanceName
+}
+
+// DeniedWrites returns the writes the instance's profile refused since the
+// given time, read from the unified log. The kernel rate-limits violation
+// logging, so a burst of identical denials may appear only once.
+func (r *Runtime) DeniedWrites(ctx context.Context, name string, since time.Time) ([]runtime.DeniedWrite, error) {
+	marker := denialMarker(name)
+	args := []string{
+		"show", "--style", "syslog",
+		"--start", since.Local().Format("2006-01-02 15:04:05"),
+		"--predicate", fmt.Sprintf("eventMessage CONTAINS %q", marker),
+	}
+	out, err := sysexec.CommandContext(ctx, r.execEnv, logBin, args...).Output()
+	if err != nil {
+		return nil, fmt.Errorf("read sandbox violations from the system log: %w", sysexec.EnrichExitError(err))
+	}
+	return parseDeniedWrites(string(out), marker), nil
+}
+
+// parseDeniedWrites extracts the refused writes carrying marker from `log show
+// --style syslog` output. Lines that are not write denials are skipped.
+func parseDeniedWrites(out, marker string) []runtime.DeniedWrite {
+	var denied []runtime.DeniedWrite
+	for line := range strings.SplitSeq(out, "\n") {
+		if !strings.Contains(line, marker) {
+			continue
+		}
+		m := denialLineRE.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		path := strings.TrimSpace(strings.Replace(m[3], marker, "", 1))
+		d := runtime.DeniedWrite{Process: m[1], Operation: m[2], Path: path}
+		if len(line) >= len(logTimeLayout) {
+			if t, err := time.Parse(logTimeLayout, line[:len(logTimeLayout)]); err == nil {
+				d.Time = t
+			}
+		}
+		denied = append(denied, d)
+	}
+	return denied
+}
@@ -0,0 +1,38 @@
+package seatbelt
+
+// ABOUTME: Unit tests for parsing refused writes out of `log show` output.
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseDeniedWrites(t *testing.T) {
+	marker := denialMarker("yoloai-cli-mybox")
+	out := `Timestamp                       (process)[PID]
+2026-10-18 09:15:02.123456-0700  localhost kernel[0]: (Sandbox) Sandbox: npm(4242) deny(1) file-write-create /private/tmp/npm-cache yoloai-seatbelt:yoloai-cli-mybox
+2026-10-18 09:15:03.000001-0700  localhost kernel[0]: (Sandbox) Sandbox: python3.12(77) deny(1) file-write-data /Users/test/.zshrc yoloai-seatbelt:yoloai-cli-mybox
+2026-10-18 09:15:04.000001-0700  localhost kernel[0]: (Sandbox) Sandbox: npm(4243) deny(1) file-write-create /private/tmp/other yoloai-seatbelt:yoloai-cli-otherbox
+2026-10-18 09:15:05.000001-0700  localhost kernel[0]: (Sandbox) Sandbox: curl(9) deny(1) network-outbound 1.2.3.4:443 yoloai-seatbelt:yoloai-cli-mybox
+`
+	got := parseDeniedWrites(out, marker)
+	if len(got) != 2 {
+		t.Fatalf("want 2 denials for this instance, got %d: %+v", len(got), got)
+	}
+	if got[0].Process != "npm" || got[0].Operation != "file-write-create" || got[0].Path != "/private/tmp/npm-cache" {
+		t.Errorf("first denial parsed wrong: %+v", got[0])
+	}
+	if got[1].Process != "python3.12" || got[1].Path != "/Users/test/.zshrc" {
+		t.Errorf("second denial parsed wrong: %+v", got[1])
+	}
+	want := time.Date(2026, 10, 18, 16, 15, 2, 123456000, time.UTC)
+	if !got[0].Time.Equal(want) {
+		t.Errorf("time = %v, want %v", got[0].Time, want)
+	}
+}
+
+func TestParseDeniedWrites_Empty(t *testing.T) {
+	if got := parseDeniedWrites("Timestamp (process)[PID]\n", denialMarker("x")); len(got) != 0 {
+		t.Errorf("want no denials, got %+v", got)
+	}
+}
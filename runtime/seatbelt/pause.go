@@ -0,0 +1,105 @@
+// ABOUTME: Pauser for seatbelt — freezes the sandbox's process groups with
+// ABOUTME: SIGSTOP and thaws them with SIGCONT; a marker file records the state.
+package seatbelt
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"github.com/kstenerud/yoloai/internal/fileutil"
+	"github.com/kstenerud/yoloai/internal/sysexec"
+	"github.com/kstenerud/yoloai/runtime"
+)
+
+// pausedMarkerName marks a sandbox whose processes are stopped by Pause.
+// There is no kernel state to query for "this process group is frozen" short
+// of scanning every process, so Inspect reads the marker instead.
+const pausedMarkerName = "paused"
+
+var _ runtime.Pauser = (*Runtime)(nil)
+
+// Pause sends SIGSTOP to the sandbox-exec process group and to every tmux
+// pane's process group (runtime.Pauser). The tmux server daemonizes into its
+// own group and each pane runs in a fresh session, so stopping only the
+// sandbox-exec group would leave the agent running. The tmux server itself is
+// left alone so an attached client still sees the (frozen) screen.
+func (r *Runtime) Pause(_ context.Context, name string) error {
+	sandboxPath := filepath.Join(r.layout.SandboxesDir(), r.sandboxName(name))
+	return r.signalSandbox(sandboxPath, syscall.SIGSTOP)
+}
+
+// Unpause sends SIGCONT to the groups stopped by Pause (runtime.Pauser).
+func (r *Runtime) Unpause(_ context.Context, name string) error {
+	sandboxPath := filepath.Join(r.layout.SandboxesDir(), r.sandboxName(name))
+	return r.signalSandbox(sandboxPath, syscall.SIGCONT)
+}
+
+// signalSandbox delivers sig to the sandbox's process groups and records the
+// resulting pause state in the marker file.
+func (r *Runtime) signalSandbox(sandboxPath string, sig syscall.Signal) error {
+	if !r.isRunning(sandboxPath) {
+		return runtime.ErrNotRunning
+	}
+	pids := r.panePIDs(sandboxPath)
+	if pid, err := readPIDFile(sandboxPath); err == nil {
+		pids = append(pids, pid)
+	}
+	for _, pid := range pids {
+		// A pane may have exited between listing and signalling; ESRCH is fine.
+		if err := syscall.Kill(-pid, sig); err != nil && err != syscall.ESRCH {
+			return fmt.Errorf("signal process group %d: %w", pid, err)
+		}
+	}
+
+	markerPath := filepath.Join(sandboxPath, backendDir, pausedMarkerName)
+	if sig == syscall.SIGSTOP {
+		if err := fileutil.WriteFile(markerPath, nil, 0600); err != nil {
+			return fmt.Errorf("write pause marker: %w", err)
+		}
+		return nil
+	}
+	if err := os.Remove(markerPath); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("remove pause marker: %w", err)
+	}
+	return nil
+}
+
+// isPaused reports whether Pause left the sandbox frozen.
+func isPaused(sandboxPath string) bool {
+	_, err := os.Stat(filepath.Join(sandboxPath, backendDir, pausedMarkerName))
+	return err == nil
+}
+
+// panePIDs lists the process IDs of every tmux pane in the sandbox's session.
+// Each pane leads its own process group. Returns nil if tmux isn't up.
+func (r *Runtime) panePIDs(sandboxPath string) []int {
+	tmuxSock := filepath.Join(sandboxPath, tmuxDir, tmuxSocketName)
+	if _, err := os.Stat(tmuxSock); err != nil {
+		return nil
+	}
+	out, err := sysexec.Command(r.execEnv, "tmux", "-S", tmuxSock, "list-panes", "-a", "-F", "#{pane_pid}").Output()
+	if err != nil {
+		return nil
+	}
+	var pids []int
+	for line := range strings.SplitSeq(strings.TrimSpace(string(out)), "\n") {
+		if pid, err := strconv.Atoi(strings.TrimSpace(line)); err == nil && pid > 1 {
+			pids = append(pids, pid)
+		}
+	}
+	return pids
+}
+
+// readPIDFile reads the sandbox-exec process ID written by Start.
+func readPIDFile(sandboxPath string) (int, error) {
+	data, err := os.ReadFile(filepath.Join(sandboxPath, backendDir, pidFileName)) //nolint:gosec // G304: path within sandbox dir
+	if err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(strings.TrimSpace(string(data)))
+}
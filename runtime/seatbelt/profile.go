@@ -22,6 +22,11 @@ func GenerateProfile(cfg runtime.InstanceConfig, sandboxDir, homeDir string) str
 	b.WriteString("(version 1)\n")
 	b.WriteString("(deny default)\n\n")
 
+	// Tag refused writes with this instance's marker so DeniedWrites can pick
+	// them out of the system log. The allow rules below take precedence.
+	b.WriteString("; Refused writes (reported by `yoloai sandbox <name> denials`)\n")
+	fmt.Fprintf(&b, "(deny file-write* (with message %q))\n\n", denialMarker(cfg.Name))
+
 	writeProfileHeader(&b)
 	writeProfileSystemPaths(&b)
 	writeProfileSandboxDir(&b, sandboxDir)
@@ -157,7 +162,8 @@ func writeProfileHeader(b *strings.Builder) {
 	b.WriteString("(allow ipc-posix-sem)\n\n")
 }
 
-// writeProfileSystemPaths writes rules for system libraries, toolchains, and temp dirs.
+// writeProfileSystemPaths writes rules for system libraries, toolchains, and
+// the shared temp dirs.
 func writeProfileSystemPaths(b *strings.Builder) {
 	b.WriteString("; System libraries, frameworks, and binaries\n")
 	for _, path := range systemReadPaths() {
@@ -183,9 +189,13 @@ func writeProfileSystemPaths(b *strings.Builder) {
 		b.WriteString("\n")
 	}
 
-	b.WriteString("; Temporary directories\n")
+	// The shared temp dirs are readable but not writable: a writable /tmp lets
+	// one sandbox leave state for another (or for the host) to pick up. Each
+	// sandbox writes temp files to its own tmp/ instead (TMPDIR, see
+	// sandboxEnv), which the sandbox-directory rule covers.
+	b.WriteString("; Shared temporary directories (read-only; TMPDIR is the sandbox's tmp/)\n")
 	for _, path := range tempPaths() {
-		fmt.Fprintf(b, "(allow file-read* file-write* (subpath %q))\n", path)
+		fmt.Fprintf(b, "(allow file-read* (subpath %q))\n", path)
 	}
 	b.WriteString("\n")
 }
@@ -82,6 +82,11 @@ const (
 	// tmuxDir holds tmux config and sockets within the sandbox directory.
 	tmuxDir = config.TmuxDirName
 
+	// tmpDir is the sandbox's private temp directory (its TMPDIR). It is the
+	// only temp location the profile lets the agent write, and is emptied on
+	// every Start and Stop.
+	tmpDir = config.TmpDirName
+
 	// pidFileName stores the sandbox-exec process ID.
 	pidFileName = "pid"
 
@@ -262,6 +267,14 @@ func writeSandboxScripts(sandboxPath string) error {
 	if err := fileutil.WriteFile(resumePath, monitor.YoloaiResumeScript(), 0755); err != nil {
 		return fmt.Errorf("write yoloai-resume: %w", err)
 	}
+	diffwatchPath := filepath.Join(sandboxPath, binDir, "yoloai-diffwatch")
+	if err := fileutil.WriteFile(diffwatchPath, monitor.YoloaiDiffwatchScript(), 0755); err != nil {
+		return fmt.Errorf("write yoloai-diffwatch: %w", err)
+	}
+	testAgentPath := filepath.Join(sandboxPath, binDir, "test-agent.py")
+	if err := fileutil.WriteFile(testAgentPath, monitor.TestAgentScript(), 0644); err != nil {
+		return fmt.Errorf("write test-agent.py: %w", err)
+	}
 	tmuxConfPath := filepath.Join(sandboxPath, tmuxDir, "tmux.conf")
 	if err := fileutil.WriteFile(tmuxConfPath, embeddedTmuxConf, 0600); err != nil {
 		return fmt.Errorf("write tmux.conf: %w", err)
@@ -329,7 +342,11 @@ func (r *Runtime) Start(ctx context.Context, name string) error {
 	} else {
 		sandboxArgs = append(sandboxArgs, "python3", setupScriptPath, "seatbelt", sandboxPath)
 	}
-	cmd := sysexec.Command(r.sandboxEnv(), r.sandboxExecBin, sandboxArgs...)
+	if err := resetTmpDir(sandboxPath); err != nil {
+		logFile.Close() //nolint:errcheck,gosec // best-effort
+		return err
+	}
+	cmd := sysexec.Command(r.sandboxEnv(sandboxPath), r.sandboxExecBin, sandboxArgs...)
 	cmd.Stderr = logFile
 	cmd.Stdout = logFile
 	// Setsid (not Setpgid): on seatbelt the agent + tmux run on the host, so a
@@ -403,6 +420,13 @@ func (r *Runtime) awaitInstanceReady(ctx context.Context, sandboxPath, logPath s
 func (r *Runtime) Stop(_ context.Context, name string) error {
 	sandboxPath := filepath.Join(r.layout.SandboxesDir(), r.sandboxName(name))
 
+	// Stopped processes can't act on SIGTERM; thaw a paused sandbox first so
+	// the shutdown below is graceful rather than a 5s wait and SIGKILL.
+	if isPaused(sandboxPath) {
+		_ = r.signalSandbox(sandboxPath, syscall.SIGCONT)
+		_ = os.Remove(filepath.Join(sandboxPath, backendDir, pausedMarkerName))
+	}
+
 	// Kill tmux server via socket
 	tmuxSock := filepath.Join(sandboxPath, tmuxDir, tmuxSocketName)
 	if _, err := os.Stat(tmuxSock); err == nil {
@@ -413,6 +437,9 @@ func (r *Runtime) Stop(_ context.Context, name string) error {
 	// Kill the sandbox-exec process
 	r.killByPID(sandboxPath)
 
+	// Nothing may carry over to the next run through the temp dir.
+	_ = resetTmpDir(sandboxPath)
+
 	return nil
 }
 
@@ -451,8 +478,10 @@ func (r *Runtime) Inspect(_ context.Context, name string) (runtime.InstanceInfo,
 		return runtime.InstanceInfo{}, runtime.ErrNotFound
 	}
 
+	running := r.isRunning(sandboxPath)
 	return runtime.InstanceInfo{
-		Running: r.isRunning(sandboxPath),
+		Running: running,
+		Paused:  running && isPaused(sandboxPath),
 	}, nil
 }
 
@@ -759,8 +788,31 @@ func (r *Runtime) killByPID(sandboxPath string) {
 // directory; users can opt in to additional env vars via the config env: section.
 // The curated subset is built from the threaded snapshot, never os.Environ (§12) —
 // the CLI captures the host env once at its boundary and threads it in.
-func (r *Runtime) sandboxEnv() []string {
-	return r.layout.Env().EnvForSeatbeltSandbox()
+// TMPDIR is replaced with the sandbox's private tmp/, the only temp location
+// its profile allows writes to.
+func (r *Runtime) sandboxEnv(sandboxPath string) []string {
+	env := r.layout.Env().EnvForSeatbeltSandbox()
+	tmp := "TMPDIR=" + filepath.Join(sandboxPath, tmpDir)
+	for i, kv := range env {
+		if strings.HasPrefix(kv, "TMPDIR=") {
+			env[i] = tmp
+			return env
+		}
+	}
+	return append(env, tmp)
+}
+
+// resetTmpDir empties the sandbox's private temp directory, creating it if
+// needed, so no temp state survives from one run to the next.
+func resetTmpDir(sandboxPath string) error {
+	dir := filepath.Join(sandboxPath, tmpDir)
+	if err := os.RemoveAll(dir); err != nil {
+		return fmt.Errorf("clear temp dir: %w", err)
+	}
+	if err := fileutil.MkdirAll(dir, 0700); err != nil {
+		return fmt.Errorf("create temp dir: %w", err)
+	}
+	return nil
 }
 
 // waitForTmux polls until the tmux session appears via the per-sandbox socket.
@@ -816,7 +868,7 @@ func (r *Runtime) buildExecCommand(sandboxPath string, cmd []string) *exec.Cmd {
 	profilePath := filepath.Join(sandboxPath, backendDir, profileFileName)
 	args := []string{"-f", profilePath}
 	args = append(args, cmd...)
-	c := sysexec.Command(r.execEnv, r.sandboxExecBin, args...)
+	c := sysexec.Command(r.sandboxEnv(sandboxPath), r.sandboxExecBin, args...)
 
 	// Read working directory from runtime-config.json, which is the source of truth
 	// for seatbelt. patchConfigWorkingDir (called during Start) rewrites it
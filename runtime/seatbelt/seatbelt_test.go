@@ -112,6 +112,36 @@ func TestGenerateProfile_SandboxDirAlwaysWritable(t *testing.T) {
 	}
 }
 
+func TestGenerateProfile_SharedTempReadOnly(t *testing.T) {
+	cfg := runtime.InstanceConfig{Name: "test"}
+	profile := GenerateProfile(cfg, "/Users/test/.yoloai/sandboxes/mybox", "/Users/test")
+
+	for _, p := range tempPaths() {
+		if strings.Contains(profile, fmt.Sprintf(`(allow file-read* file-write* (subpath %q))`, p)) {
+			t.Errorf("shared temp dir %s should not be writable, profile:\n%s", p, profile)
+		}
+		if !strings.Contains(profile, fmt.Sprintf(`(allow file-read* (subpath %q))`, p)) {
+			t.Errorf("shared temp dir %s should stay readable", p)
+		}
+	}
+}
+
+func TestGenerateProfile_TagsRefusedWrites(t *testing.T) {
+	cfg := runtime.InstanceConfig{Name: "yoloai-cli-mybox"}
+	profile := GenerateProfile(cfg, "/Users/test/.yoloai/sandboxes/mybox", "/Users/test")
+
+	deny := `(deny file-write* (with message "yoloai-seatbelt:yoloai-cli-mybox"))`
+	idx := strings.Index(profile, deny)
+	if idx < 0 {
+		t.Fatalf("profile should tag refused writes with the instance marker, profile:\n%s", profile)
+	}
+	// SBPL gives later rules precedence, so the tagging deny must come before
+	// every allow or it would override them.
+	if allow := strings.Index(profile, "(allow "); allow >= 0 && allow < idx {
+		t.Error("the tagging deny must precede the allow rules")
+	}
+}
+
 func TestGenerateProfile_HomeDirMinimalAccess(t *testing.T) {
 	cfg := runtime.InstanceConfig{Name: "test"}
 	homeDir := "/Users/testuser"
@@ -725,6 +755,42 @@ func TestKillByPID_WaitsForExit(t *testing.T) {
 	}
 }
 
+func TestSignalSandbox_PauseMarker(t *testing.T) {
+	sandboxPath := t.TempDir()
+	backendPath := filepath.Join(sandboxPath, backendDir)
+	if err := os.MkdirAll(backendPath, 0750); err != nil {
+		t.Fatal(err)
+	}
+
+	r := &Runtime{}
+	if err := r.signalSandbox(sandboxPath, syscall.SIGSTOP); err != runtime.ErrNotRunning {
+		t.Fatalf("pausing a sandbox with no process: got %v, want ErrNotRunning", err)
+	}
+
+	cmd := sysexec.Command([]string{"PATH=/usr/bin:/bin"}, "sleep", "60")
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	if err := cmd.Start(); err != nil {
+		t.Fatalf("failed to start sleep process: %v", err)
+	}
+	t.Cleanup(func() { _ = cmd.Process.Kill(); _ = cmd.Wait() })
+	if err := os.WriteFile(filepath.Join(backendPath, pidFileName), []byte(strconv.Itoa(cmd.Process.Pid)), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := r.signalSandbox(sandboxPath, syscall.SIGSTOP); err != nil {
+		t.Fatalf("pause: %v", err)
+	}
+	if !isPaused(sandboxPath) {
+		t.Error("pause should leave the marker")
+	}
+	if err := r.signalSandbox(sandboxPath, syscall.SIGCONT); err != nil {
+		t.Fatalf("unpause: %v", err)
+	}
+	if isPaused(sandboxPath) {
+		t.Error("unpause should remove the marker")
+	}
+}
+
 func TestToolchainReadPaths_DetectsPython(t *testing.T) {
 	pythonPath, err := exec.LookPath("python3")
 	if err != nil {
@@ -852,7 +918,7 @@ func TestSandboxEnv_Whitelist(t *testing.T) {
 		"GIT_AUTHOR_EMAIL":      "test@example.com",
 	})}
 
-	env := r.sandboxEnv()
+	env := r.sandboxEnv("/tmp/sandbox")
 	envMap := make(map[string]string)
 	for _, entry := range env {
 		k, v, _ := strings.Cut(entry, "=")
@@ -876,7 +942,7 @@ func TestSandboxEnv_PreservesWhitelisted(t *testing.T) {
 		"LC_CTYPE": "UTF-8",
 	})}
 
-	env := r.sandboxEnv()
+	env := r.sandboxEnv("/tmp/sandbox")
 	envMap := make(map[string]string)
 	for _, entry := range env {
 		k, v, _ := strings.Cut(entry, "=")
@@ -889,3 +955,42 @@ func TestSandboxEnv_PreservesWhitelisted(t *testing.T) {
 		}
 	}
 }
+
+func TestSandboxEnv_PrivateTmpdir(t *testing.T) {
+	r := &Runtime{layout: config.Layout{}.WithEnv(map[string]string{
+		"TMPDIR": "/var/folders/xy/T/",
+	})}
+
+	env := r.sandboxEnv("/Users/test/.yoloai/sandboxes/mybox")
+	var tmpdirs []string
+	for _, entry := range env {
+		if v, ok := strings.CutPrefix(entry, "TMPDIR="); ok {
+			tmpdirs = append(tmpdirs, v)
+		}
+	}
+	if len(tmpdirs) != 1 || tmpdirs[0] != "/Users/test/.yoloai/sandboxes/mybox/tmp" {
+		t.Errorf("TMPDIR should be the sandbox's private tmp dir exactly once, got %v", tmpdirs)
+	}
+}
+
+func TestResetTmpDir(t *testing.T) {
+	sandboxPath := t.TempDir()
+	stale := filepath.Join(sandboxPath, tmpDir, "leftover")
+	if err := os.MkdirAll(filepath.Dir(stale), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(stale, []byte("x"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := resetTmpDir(sandboxPath); err != nil {
+		t.Fatalf("resetTmpDir: %v", err)
+	}
+	entries, err := os.ReadDir(filepath.Join(sandboxPath, tmpDir))
+	if err != nil {
+		t.Fatalf("tmp dir should exist: %v", err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("tmp dir should be empty, has %d entries", len(entries))
+	}
+}
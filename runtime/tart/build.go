@@ -342,6 +342,125 @@ func (r *Runtime) recordBuildInfo(baseImage string) {
 	_ = fileutil.WriteFile(r.tartBaseInfoPath(), []byte(r.buildInfoContent(baseImage)), 0600)
 }
 
+// BaseImageStatus describes the provisioned yoloai-base VM: whether it exists,
+// which upstream image it is (or would be) provisioned from, and the provision
+// checksums that decide whether it is current.
+type BaseImageStatus struct {
+	Name             string            // local VM name (provisionedImageName)
+	Exists           bool              // the VM is present in tart's store
+	SourceImage      string            // upstream image resolved for the current config
+	Checksum         string            // provision checksum for the current inputs
+	RecordedChecksum string            // checksum recorded by the last successful build; "" if none
+	Info             map[string]string // host-side build-info sidecar; nil if absent
+}
+
+// Verify returns nil when the base exists, was built from the current inputs,
+// and its build-info sidecar agrees with the recorded checksum. Otherwise it
+// returns an error naming the first problem found. It reads host-side records
+// only; VerifyBaseImage checks the VM itself.
+func (s BaseImageStatus) Verify() error {
+	switch {
+	case !s.Exists:
+		return fmt.Errorf("base VM %s does not exist", s.Name)
+	case s.RecordedChecksum == "":
+		return fmt.Errorf("base VM %s has no recorded build checksum", s.Name)
+	case s.RecordedChecksum != s.Checksum:
+		return fmt.Errorf("base VM %s is stale: provision inputs or source image changed since it was built", s.Name)
+	case s.Info == nil:
+		return fmt.Errorf("base VM %s has no build info", s.Name)
+	case s.Info["provision_checksum"] != s.RecordedChecksum:
+		return fmt.Errorf("base VM %s build info does not match its recorded checksum", s.Name)
+	}
+	return nil
+}
+
+// BaseImageStatus reports the state of the provisioned base VM without
+// building anything. sourceDir is what you would pass Setup.
+func (r *Runtime) BaseImageStatus(ctx context.Context, sourceDir string) (BaseImageStatus, error) {
+	baseImage := r.resolveBaseImage(sourceDir)
+	exists, err := r.vmExistsNamed(ctx, provisionedImageName)
+	if err != nil {
+		return BaseImageStatus{}, err
+	}
+	st := BaseImageStatus{
+		Name:        provisionedImageName,
+		Exists:      exists,
+		SourceImage: baseImage,
+		Checksum:    r.provisionChecksum(baseImage),
+	}
+	if data, err := os.ReadFile(r.tartBaseChecksumPath()); err == nil {
+		st.RecordedChecksum = strings.TrimSpace(string(data))
+	}
+	if data, err := os.ReadFile(r.tartBaseInfoPath()); err == nil {
+		st.Info = parseBuildInfo(string(data))
+	}
+	return st, nil
+}
+
+// VerifyBaseImage checks the built base VM itself, where Verify checks only
+// its build record: it boots a throwaway clone, asserts every required tool
+// resolves on the login PATH, and compares the imprint the build wrote inside
+// the guest with the host-side build info. The base itself is never booted, so
+// sandboxes cloned from it are unaffected. Tool output is written to output.
+func (r *Runtime) VerifyBaseImage(ctx context.Context, output io.Writer) error {
+	data, err := os.ReadFile(r.tartBaseInfoPath())
+	if err != nil {
+		return fmt.Errorf("base VM %s has no build info: %w", provisionedImageName, err)
+	}
+	tempVM := generateTempVMName(provisionedImageName)
+	defer r.cleanupTempVM(ctx, tempVM)
+
+	fmt.Fprintln(output, "Booting a clone of the base VM to verify it...") //nolint:errcheck // best-effort
+	if _, err := r.runTart(ctx, "clone", provisionedImageName, tempVM); err != nil {
+		return fmt.Errorf("clone base VM: %w", err)
+	}
+	if err := r.startTempVM(ctx, tempVM); err != nil {
+		return fmt.Errorf("boot base VM clone: %w", err)
+	}
+	fmt.Fprintln(output, "Verifying provisioned tools...") //nolint:errcheck // best-effort
+	if err := r.verifyTools(ctx, tempVM, output); err != nil {
+		return err
+	}
+	imprint, err := r.runTart(ctx, execArgs(tempVM, "bash", "-c", "cat ~/.yoloai-base-info")...)
+	if err != nil {
+		return fmt.Errorf("read build imprint: %w", err)
+	}
+	return checkImprint(parseBuildInfo(imprint), parseBuildInfo(string(data)))
+}
+
+// imprintKeys are the build-info fields the in-guest imprint and the host-side
+// sidecar must agree on. built_at is left out: the two are stamped moments
+// apart by the same build.
+var imprintKeys = []string{"yoloai_version", "yoloai_commit", "provision_checksum", "base_image"}
+
+// checkImprint returns an error naming the first imprintKeys field on which
+// the guest's imprint disagrees with the host's build info — a base VM that
+// was replaced or rebuilt outside yoloai.
+func checkImprint(imprint, info map[string]string) error {
+	if len(imprint) == 0 {
+		return fmt.Errorf("base VM %s has no build imprint", provisionedImageName)
+	}
+	for _, k := range imprintKeys {
+		if imprint[k] != info[k] {
+			return fmt.Errorf("base VM %s does not match its build info: %s is %q in the guest, %q on the host", provisionedImageName, k, imprint[k], info[k])
+		}
+	}
+	return nil
+}
+
+// parseBuildInfo parses the key=value lines written by buildInfoContent.
+// Lines without '=' are ignored.
+func parseBuildInfo(content string) map[string]string {
+	info := map[string]string{}
+	for line := range strings.SplitSeq(content, "\n") {
+		k, v, ok := strings.Cut(strings.TrimSpace(line), "=")
+		if ok {
+			info[k] = v
+		}
+	}
+	return info
+}
+
 // verifyTools asserts every requiredTools binary resolves on the VM's login
 // shell PATH (zsh -l sources ~/.zprofile). Returns an error naming the first
 // missing tool — that is what the provisioned base must guarantee.
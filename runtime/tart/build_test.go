@@ -1,6 +1,6 @@
-// ABOUTME: Unit tests for build.go's DF145 error forwarding: a failed tart
-// ABOUTME: subprocess (pull, tool verification, imprint write) must carry the
-// ABOUTME: tail of its own stderr on the returned error, not a bare exit code.
+// ABOUTME: Unit tests for build.go: DF145 error forwarding on failed tart subprocesses
+// ABOUTME: (pull, tool verification, imprint write), the base-image status check,
+// ABOUTME: and the in-guest imprint comparison.
 
 package tart
 
@@ -52,3 +52,58 @@ func TestWriteImprint_ErrorCarriesStderrTail(t *testing.T) {
 	assert.Contains(t, err.Error(), cause,
 		"without a captured tail this exec's stderr was discarded entirely (DF145)")
 }
+
+func TestParseBuildInfo(t *testing.T) {
+	r := &Runtime{}
+	info := parseBuildInfo(r.buildInfoContent("ghcr.io/cirruslabs/macos-tahoe-base:latest"))
+	assert.Equal(t, "ghcr.io/cirruslabs/macos-tahoe-base:latest", info["base_image"])
+	assert.Equal(t, r.provisionChecksum("ghcr.io/cirruslabs/macos-tahoe-base:latest"), info["provision_checksum"])
+	assert.NotEmpty(t, info["built_at"])
+}
+
+func TestBaseImageStatus_Verify(t *testing.T) {
+	good := BaseImageStatus{
+		Name:             provisionedImageName,
+		Exists:           true,
+		Checksum:         "abc",
+		RecordedChecksum: "abc",
+		Info:             map[string]string{"provision_checksum": "abc"},
+	}
+	require.NoError(t, good.Verify())
+
+	tests := []struct {
+		name   string
+		mutate func(*BaseImageStatus)
+		want   string
+	}{
+		{"missing", func(s *BaseImageStatus) { s.Exists = false }, "does not exist"},
+		{"unrecorded", func(s *BaseImageStatus) { s.RecordedChecksum = "" }, "no recorded build checksum"},
+		{"stale", func(s *BaseImageStatus) { s.Checksum = "def" }, "is stale"},
+		{"no info", func(s *BaseImageStatus) { s.Info = nil }, "no build info"},
+		{"info mismatch", func(s *BaseImageStatus) { s.Info = map[string]string{"provision_checksum": "zzz"} }, "does not match"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := good
+			tt.mutate(&s)
+			err := s.Verify()
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.want)
+		})
+	}
+}
+
+func TestCheckImprint(t *testing.T) {
+	r := &Runtime{}
+	content := r.buildInfoContent("ghcr.io/cirruslabs/macos-tahoe-base:latest")
+	require.NoError(t, checkImprint(parseBuildInfo(content), parseBuildInfo(content)))
+
+	err := checkImprint(nil, parseBuildInfo(content))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "no build imprint")
+
+	other := parseBuildInfo(r.buildInfoContent("ghcr.io/cirruslabs/macos-sequoia-base:latest"))
+	err = checkImprint(other, parseBuildInfo(content))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "provision_checksum")
+}
@@ -227,6 +227,14 @@ func writeVMSetupScripts(sandboxPath string) error {
 	if err := fileutil.WriteFile(resumePath, monitor.YoloaiResumeScript(), 0755); err != nil {
 		return fmt.Errorf("write yoloai-resume: %w", err)
 	}
+	diffwatchPath := filepath.Join(sandboxPath, binDir, "yoloai-diffwatch")
+	if err := fileutil.WriteFile(diffwatchPath, monitor.YoloaiDiffwatchScript(), 0755); err != nil {
+		return fmt.Errorf("write yoloai-diffwatch: %w", err)
+	}
+	testAgentPath := filepath.Join(sandboxPath, binDir, "test-agent.py")
+	if err := fileutil.WriteFile(testAgentPath, monitor.TestAgentScript(), 0644); err != nil {
+		return fmt.Errorf("write test-agent.py: %w", err)
+	}
 	tmuxConfPath := filepath.Join(sandboxPath, tmuxDir, "tmux.conf")
 	if err := fileutil.WriteFile(tmuxConfPath, embeddedTmuxConf, 0600); err != nil {
 		return fmt.Errorf("write tmux.conf: %w", err)
@@ -499,13 +499,13 @@ func (r *Runtime) Inspect(ctx context.Context, name string) (runtime.InstanceInf
 }
 
 // Exec runs a command inside the VM via tart exec and returns the result.
-// The user parameter is ignored — tart exec runs as the VM's logged-in user.
-func (r *Runtime) Exec(ctx context.Context, name string, cmd []string, _ string) (runtime.ExecResult, error) {
+// It runs as user (see guestCommand).
+func (r *Runtime) Exec(ctx context.Context, name string, cmd []string, user string) (runtime.ExecResult, error) {
 	if !r.isRunning(ctx, name) {
 		return runtime.ExecResult{}, runtime.ErrNotRunning
 	}
 
-	args := execArgs(name, cmd...)
+	args := execArgs(name, guestCommand(cmd, user, "")...)
 
 	slog.Debug("tart Exec", "vm", name, "args", args)
 
@@ -516,12 +516,12 @@ func (r *Runtime) Exec(ctx context.Context, name string, cmd []string, _ string)
 
 // ExecRaw is like Exec but preserves exact stdout/stderr without trimming
 // whitespace. Use this for commands whose output is whitespace-sensitive.
-func (r *Runtime) ExecRaw(ctx context.Context, name string, cmd []string, _ string) (runtime.ExecResult, error) {
+func (r *Runtime) ExecRaw(ctx context.Context, name string, cmd []string, user string) (runtime.ExecResult, error) {
 	if !r.isRunning(ctx, name) {
 		return runtime.ExecResult{}, runtime.ErrNotRunning
 	}
 
-	args := execArgs(name, cmd...)
+	args := execArgs(name, guestCommand(cmd, user, "")...)
 
 	slog.Debug("tart ExecRaw", "vm", name, "args", args)
 
@@ -575,10 +575,9 @@ func (r *Runtime) translateWorkDirToVMPath(workDir string) string {
 // or a VM path (/Users/admin/yoloai-work/<encoded>). Host paths are translated
 // to VM paths automatically.
 // name may be a sandbox name or instance name; both are accepted. user is
-// ignored — `tart exec` runs as the VM's single logged-in user (the work copy
-// is owned by that user); the container backends use it to match the agent's
-// container user.
-func (r *Runtime) GitExec(ctx context.Context, name, _, workDir string, args ...string) (string, error) {
+// the agent's container user, as for the container backends; in the VM that
+// is the login user, which owns the work copy (see guestCommand).
+func (r *Runtime) GitExec(ctx context.Context, name, user, workDir string, args ...string) (string, error) {
 	// Callers in the sandbox package pass the sandbox name (e.g. "mybox").
 	// The Tart VM is named with the instance prefix (e.g. "yoloai-cli-mybox").
 	vmName := r.instanceName(name)
@@ -599,7 +598,7 @@ func (r *Runtime) GitExec(ctx context.Context, name, _, workDir string, args ...
 	cmd := append([]string{"git"}, gitArgs...)
 
 	// Use ExecRaw to preserve exact git output (patches are whitespace-sensitive)
-	result, err := r.ExecRaw(ctx, vmName, cmd, "")
+	result, err := r.ExecRaw(ctx, vmName, cmd, user)
 	if err != nil {
 		return "", err
 	}
@@ -608,9 +607,8 @@ func (r *Runtime) GitExec(ctx context.Context, name, _, workDir string, args ...
 
 // InteractiveExec runs a command interactively inside the VM by shelling
 // out to `tart exec`. IOStreams determines whether a PTY is allocated and
-// where stdio is wired. The user and workDir params are ignored — tart
-// exec runs as the VM's logged-in user in its default cwd.
-func (r *Runtime) InteractiveExec(ctx context.Context, name string, cmd []string, _ string, _ string, streams runtime.IOStreams) error {
+// where stdio is wired. It runs as user in workDir (see guestCommand).
+func (r *Runtime) InteractiveExec(ctx context.Context, name string, cmd []string, user string, workDir string, streams runtime.IOStreams) error {
 	args := []string{"exec"}
 	if streams.TTY {
 		// -i attaches stdin, -t allocates the VM-side PTY (like docker exec -it).
@@ -619,7 +617,7 @@ func (r *Runtime) InteractiveExec(ctx context.Context, name string, cmd []string
 		args = append(args, "-i")
 	}
 	args = append(args, name)
-	args = append(args, cmd...)
+	args = append(args, guestCommand(cmd, user, workDir)...)
 
 	c := sysexec.CommandContext(ctx, r.execEnv, r.tartBin, args...)
 	// ptybridge.Exec wraps the child in a local host PTY. tart already allocates a
@@ -783,6 +781,31 @@ func execArgs(vmName string, cmd ...string) []string {
 	return append(args, cmd...)
 }
 
+// vmUser is the login user of the Cirrus Labs base images, whom tart exec
+// always runs as.
+const vmUser = "admin"
+
+// guestCommand wraps cmd to run as user in workDir inside the VM, since tart
+// exec has no options for either: it runs as vmUser in its default directory.
+// The container backends' agent user ("yoloai") is vmUser in a VM, so it, ""
+// and vmUser run cmd as is; any other user, root included, goes through
+// `sudo -n -u` (the base images give vmUser passwordless sudo), with a
+// numeric one taken as a UID. workDir may be a container-side path; it is
+// remapped to where the VM has it.
+func guestCommand(cmd []string, user, workDir string) []string {
+	if workDir != "" {
+		cmd = append([]string{"sh", "-c", `cd "$1" && shift && exec "$@"`, "sh", remapTargetPath(workDir)}, cmd...)
+	}
+	switch user {
+	case "", vmUser, "yoloai":
+		return cmd
+	}
+	if _, err := strconv.Atoi(user); err == nil {
+		user = "#" + user
+	}
+	return append([]string{"sudo", "-n", "-u", user, "--"}, cmd...)
+}
+
 // runTart executes a tart command and returns stdout.
 func (r *Runtime) runTart(ctx context.Context, args ...string) (string, error) {
 	cmd := sysexec.CommandContext(ctx, r.execEnv, r.tartBin, args...)
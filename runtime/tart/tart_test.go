@@ -51,6 +51,29 @@ func TestExecArgs(t *testing.T) {
 	}, args)
 }
 
+func TestGuestCommand(t *testing.T) {
+	cmd := []string{"git", "status"}
+	tests := []struct {
+		name, user, workDir string
+		want                []string
+	}{
+		{"login user", "admin", "", cmd},
+		{"default", "", "", cmd},
+		{"container agent user is the login user", "yoloai", "", cmd},
+		{"root", "root", "", []string{"sudo", "-n", "-u", "root", "--", "git", "status"}},
+		{"uid", "0", "", []string{"sudo", "-n", "-u", "#0", "--", "git", "status"}},
+		{"workdir remapped", "", "/home/yoloai/project",
+			[]string{"sh", "-c", `cd "$1" && shift && exec "$@"`, "sh", "/Users/admin/project", "git", "status"}},
+		{"user and workdir", "root", "/tmp",
+			[]string{"sudo", "-n", "-u", "root", "--", "sh", "-c", `cd "$1" && shift && exec "$@"`, "sh", "/tmp", "git", "status"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, guestCommand(cmd, tt.user, tt.workDir))
+		})
+	}
+}
+
 func TestBuildRunArgs(t *testing.T) {
 	r := &Runtime{tartBin: "/usr/local/bin/tart", execEnv: []string{"PATH=/usr/bin:/bin"}}
 	sandboxPath := t.TempDir()
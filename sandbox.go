@@ -95,6 +95,11 @@ func (s *Sandbox) Files() *Files {
 	return &Files{engine: s.engine, name: s.name}
 }
 
+// Artifacts returns the handle for the sandbox's artifact outbox.
+func (s *Sandbox) Artifacts() *Artifacts {
+	return &Artifacts{engine: s.engine, name: s.name}
+}
+
 // Network returns the sandbox's network-management sub-handle.
 func (s *Sandbox) Network() *Network {
 	return &Network{engine: s.engine, name: s.name}
@@ -156,6 +161,38 @@ func (s *Sandbox) Stop(ctx context.Context) error {
 	return s.engine.Stop(ctx, s.name)
 }
 
+// Pause freezes every process in the running sandbox without stopping it: the
+// agent keeps its in-memory state and resumes where it was on Unpause. While
+// paused the sandbox reports StatusPaused. Returns a *UsageError when the
+// backend can't pause (Tart, Apple) or the sandbox isn't running.
+func (s *Sandbox) Pause(ctx context.Context) error {
+	if err := s.checkNotDestroyed(); err != nil {
+		return err
+	}
+	return s.engine.Pause(ctx, s.name)
+}
+
+// Unpause thaws a sandbox frozen by Pause.
+func (s *Sandbox) Unpause(ctx context.Context) error {
+	if err := s.checkNotDestroyed(); err != nil {
+		return err
+	}
+	return s.engine.Unpause(ctx, s.name)
+}
+
+// CommitImage saves the sandbox's container filesystem as the image imageRef,
+// so other sandboxes can start from it (SandboxCreateOptions.Image) with
+// whatever was installed or configured in this one by hand. The mounted
+// directories — work copies, agent state, secrets — are not part of it. A
+// running sandbox is frozen for the commit and keeps running. Backends that
+// can't save images (containerd, tart, seatbelt, apple) return a UsageError.
+func (s *Sandbox) CommitImage(ctx context.Context, imageRef string) error {
+	if err := s.checkNotDestroyed(); err != nil {
+		return err
+	}
+	return s.engine.CommitImage(ctx, s.name, imageRef)
+}
+
 // Clone copies this sandbox's state into a new sandbox named dest. Although the
 // copy itself is a disk-only deep-copy of the source sandbox dir under
 // DataDir/sandboxes/, Clone is backend-bound: it goes through the Engine (and,
@@ -203,6 +240,24 @@ func (s *Sandbox) Restart(ctx context.Context, opts SandboxStartOptions) (*Start
 	return s.engine.Restart(ctx, s.name, opts)
 }
 
+// UpdatePorts adds and removes the host ports the sandbox forwards. A running
+// sandbox is restarted so its container gets the new mappings (the work copy
+// and agent state carry over) unless opts.NoRestart; a stopped one picks them
+// up on its next start. Returns a *UsageError for a host port that is already
+// forwarded to a different port, a Remove that isn't forwarded, or an Add on
+// a sandbox created with network isolation "none".
+func (s *Sandbox) UpdatePorts(ctx context.Context, opts SandboxPortsOptions) (*PortsResult, error) {
+	if err := s.checkNotDestroyed(); err != nil {
+		return nil, err
+	}
+	return s.engine.UpdatePorts(ctx, orchestrator.PortsOptions{
+		Name:      s.name,
+		Add:       formatPorts(opts.Add),
+		Remove:    opts.Remove,
+		NoRestart: opts.NoRestart,
+	})
+}
+
 // waitPollInterval is how often Wait re-inspects the sandbox. A single
 // Inspect is one backend status query plus a host-side status-file read (no
 // container exec on the fast path), so a 1s cadence is cheap for a
@@ -295,12 +350,13 @@ func pollUntil(ctx context.Context, interval, timeout time.Duration, cond WaitCo
 }
 
 // waitConditionMet reports whether a status satisfies the wait condition.
-// Active never satisfies it (agent still working); Idle satisfies only
+// Active and Paused never satisfy it (agent mid-work, possibly frozen until an
+// Unpause); Idle satisfies only
 // WaitForIdle; every other status is terminal and always satisfies it (so an
 // unexpected/future status ends the wait rather than hanging forever).
 func waitConditionMet(st Status, cond WaitCondition) bool {
 	switch st {
-	case StatusActive:
+	case StatusActive, StatusPaused:
 		return false
 	case StatusIdle:
 		return cond == WaitForIdle
@@ -318,11 +374,28 @@ func waitConditionMet(st Status, cond WaitCondition) bool {
 // returning a typed *ActiveWorkError carrying the reason — the same contract
 // Destroy has, because both verbs destroy the same thing: work the host has
 // never seen. Atomic: no check-then-act gap.
+//
+// With opts.Paths set it restores only those workdir paths and leaves the rest
+// of the work alone; naming the paths is the consent, so the active-work gate
+// doesn't apply. With opts.To set it rewinds the workdir to that commit; the
+// commits after it are named by the target, so the gate covers only the
+// uncommitted edits the rewind would also discard. With opts.Dirs set only
+// those directories are reset, and only their work is gated.
 func (s *Sandbox) Reset(ctx context.Context, opts SandboxResetOptions) (*ResetResult, error) {
 	if err := s.checkNotDestroyed(); err != nil {
 		return nil, err
 	}
-	if !opts.AbandonUnappliedWork {
+	switch {
+	case opts.AbandonUnappliedWork || len(opts.Paths) > 0:
+	case opts.To != "":
+		if dirty, err := s.engine.HasUncommittedChanges(ctx, s.name, ""); err == nil && dirty {
+			return nil, yoerrors.NewActiveWorkError("workdir has uncommitted changes that a reset to a commit would discard")
+		}
+	case len(opts.Dirs) > 0:
+		if active, reason := s.engine.NeedsConfirmationFor(ctx, s.name, opts.Dirs); active {
+			return nil, yoerrors.NewActiveWorkError("%s", reason)
+		}
+	default:
 		if active, reason := s.HasActiveWork(ctx); active {
 			return nil, yoerrors.NewActiveWorkError("%s", reason)
 		}
@@ -362,7 +435,7 @@ func (s *Sandbox) Destroy(ctx context.Context, opts SandboxDestroyOptions) (*Des
 	if s.destroyed {
 		return &DestroyResult{}, nil
 	}
-	if !opts.AbandonUnappliedWork {
+	if !opts.AbandonUnappliedWork && len(opts.Paths) == 0 {
 		if active, reason := s.HasActiveWork(ctx); active {
 			return nil, yoerrors.NewActiveWorkError("%s", reason)
 		}
@@ -448,14 +521,56 @@ type SandboxInfo struct {
 	// backends that can probe it (Tart's vmnet-wedge detector). Both are ""
 	// when not probed: the backend has no prober, the sandbox isn't running,
 	// or the probe failed.
-	NetHealth       string      `json:"net_health,omitempty"`
-	NetHealthDetail string      `json:"net_health_detail,omitempty"`
-	Changes         ChangeState `json:"has_changes"`
-	DiskUsageBytes  int64       `json:"disk_usage_bytes"`
+	NetHealth       string `json:"net_health,omitempty"`
+	NetHealthDetail string `json:"net_health_detail,omitempty"`
+	// LastOutput is when the agent last printed anything to its terminal, as
+	// the in-sandbox status monitor saw it; zero when the sandbox has no
+	// heartbeat (it never started, or predates the heartbeat). It stops moving
+	// when the sandbox stops, so it only says how long a running agent
+	// (StatusActive or StatusIdle) has been quiet.
+	LastOutput     time.Time   `json:"last_output,omitzero"`
+	Changes        ChangeState `json:"has_changes"`
+	DiskUsageBytes int64       `json:"disk_usage_bytes"`
 	// ExitCode is the agent's process exit code once the agent has exited:
 	// 0 when Status is Done, the agent's non-zero code when Failed; nil while
 	// the agent is still running/idle or the sandbox never ran an agent.
 	ExitCode *int `json:"exit_code,omitempty"`
+	// Result is the agent's structured account of its run, nil until it writes
+	// one. ResultError is set instead when the file it wrote is unusable.
+	Result      *AgentResult `json:"result,omitempty"`
+	ResultError string       `json:"result_error,omitempty"`
+}
+
+// AgentResult is the optional result contract: a JSON file the agent writes to
+// /yoloai/outbox/result.json when it finishes, so automation can act on a
+// defined shape instead of scraping the agent's free-form output.
+type AgentResult struct {
+	// Status is one of the ResultStatus* values.
+	Status    string   `json:"status"`
+	Summary   string   `json:"summary,omitempty"`
+	FollowUps []string `json:"follow_ups,omitempty"`
+	// Files lists paths the agent considers worth a look — changed, created,
+	// or left in the outbox.
+	Files []string `json:"files,omitempty"`
+}
+
+// Values of AgentResult.Status.
+const (
+	ResultStatusSuccess = store.ResultSuccess
+	ResultStatusPartial = store.ResultPartial
+	ResultStatusFailed  = store.ResultFailed
+	ResultStatusBlocked = store.ResultBlocked
+)
+
+// Result reads the agent's result.json. It returns (nil, nil) when the agent
+// has not written one, and an error when the file is malformed. Pure host
+// read: no backend contact.
+func (s *Sandbox) Result() (*AgentResult, error) {
+	res, err := store.LoadAgentResult(s.engine.Layout().SandboxDir(s.name))
+	if err != nil || res == nil {
+		return nil, err
+	}
+	return agentResultFromStore(res), nil
 }
 
 // Status is a sandbox's lifecycle state. Re-exported (type alias) from
@@ -469,6 +584,7 @@ const (
 	StatusFailed      Status = orchestrator.StatusFailed      // agent exited non-zero
 	StatusStopped     Status = orchestrator.StatusStopped     // container stopped
 	StatusSuspended   Status = orchestrator.StatusSuspended   // VM suspended (Tart only)
+	StatusPaused      Status = orchestrator.StatusPaused      // container running but frozen (Sandbox.Pause)
 	StatusRemoved     Status = orchestrator.StatusRemoved     // container removed, sandbox dir remains
 	StatusBroken      Status = orchestrator.StatusBroken      // sandbox dir exists but environment.json missing/invalid
 	StatusUnavailable Status = orchestrator.StatusUnavailable // backend not running
@@ -494,6 +610,15 @@ const (
 // needed.
 type SandboxStartOptions = orchestrator.StartOptions
 
+// SandboxPortsOptions configures Sandbox.UpdatePorts.
+type SandboxPortsOptions struct {
+	Add    []PortMapping // forwards to add; one already in place is a no-op
+	Remove []int         // host ports to stop forwarding
+	// NoRestart records the change without restarting a running sandbox; it
+	// takes effect the next time the sandbox is restarted.
+	NoRestart bool
+}
+
 // SandboxResetOptions configures Sandbox.Reset. Hand-written rather than aliased: the
 // internal struct carries a Name field that the handle now supplies, so it's
 // dropped here.
@@ -524,6 +649,21 @@ type SandboxResetOptions struct {
 	// would silently take something no one has looked at.
 	// (The CLI's --abandon-unapplied flag maps onto this field at the boundary.)
 	AbandonUnappliedWork bool
+	// Paths, when set, restores only these workdir-relative paths to their
+	// baseline contents in the running sandbox and tells the agent, instead of
+	// re-copying everything. Can't be combined with RestartContainer or
+	// ClearState.
+	Paths []string
+	// To, when set, rewinds the workdir to this commit — the baseline or one of
+	// the agent's unapplied commits — discarding the commits after it and any
+	// uncommitted edits, in the running sandbox. The baseline doesn't move.
+	// Can't be combined with Paths, RestartContainer or ClearState.
+	To string
+	// Dirs, when set, resets only these tracked :copy directories (host
+	// paths — the workdir, aux dirs, or both), in place or with
+	// RestartContainer. The other directories keep their work, and the cache
+	// and files directories are left alone. Empty resets every directory.
+	Dirs []string
 }
 
 func (o SandboxResetOptions) toInternal(name string) orchestrator.ResetOptions {
@@ -537,6 +677,9 @@ func (o SandboxResetOptions) toInternal(name string) orchestrator.ResetOptions {
 		Prompt:     o.Prompt,
 		Debug:      o.Debug,
 		Env:        o.Env,
+		Paths:      o.Paths,
+		To:         o.To,
+		Dirs:       o.Dirs,
 	}
 }
 
@@ -549,6 +692,21 @@ type SandboxDestroyOptions struct {
 	// alone is not unapplied work and never triggers the refusal.
 	// (The CLI's --abandon-unapplied flag maps onto this field at the boundary.)
 	AbandonUnappliedWork bool
+	// Paths, when set, restores only these workdir-relative paths to their
+	// baseline contents in the running sandbox and tells the agent, instead of
+	// re-copying everything. Can't be combined with RestartContainer or
+	// ClearState.
+	Paths []string
+	// To, when set, rewinds the workdir to this commit — the baseline or one of
+	// the agent's unapplied commits — discarding the commits after it and any
+	// uncommitted edits, in the running sandbox. The baseline doesn't move.
+	// Can't be combined with Paths, RestartContainer or ClearState.
+	To string
+	// Dirs, when set, resets only these tracked :copy directories (host
+	// paths — the workdir, aux dirs, or both), in place or with
+	// RestartContainer. The other directories keep their work, and the cache
+	// and files directories are left alone. Empty resets every directory.
+	Dirs []string
 }
 
 // SandboxExecOptions configures Sandbox.Exec. PTY selects between an interactive
@@ -566,6 +724,15 @@ func (s *Sandbox) CacheDir() string {
 	return store.CacheDir(s.engine.Layout().SandboxDir(s.name))
 }
 
+// InboxDir returns the host path of the sandbox's prompt inbox
+// (<state>/inbox). A *.md file written there is submitted to the running agent
+// as its next prompt once the agent is idle, then moved to inbox/delivered/.
+// Write to a dotfile and rename it into place so a half-written prompt is never
+// picked up. Pure path computation: no backend contact.
+func (s *Sandbox) InboxDir() string {
+	return store.InboxDir(s.engine.Layout().SandboxDir(s.name))
+}
+
 // RuntimeConfigPath returns the host path of the sandbox's runtime-config.json
 // (<state>/runtime-config.json), the entrypoint/infrastructure config the
 // backend reads at launch. Pure path computation: no backend contact.
@@ -604,6 +771,46 @@ func (s *Sandbox) LogPaths() LogPaths {
 	}
 }
 
+// DeniedWrite is one file write the sandbox's confinement refused.
+type DeniedWrite = runtime.DeniedWrite
+
+// DeniedWrites returns the writes the sandbox's confinement refused since the
+// given time, oldest first. supported is false when the backend has no such
+// report: only seatbelt confines a host process by policy, while container and
+// VM backends give the agent its own filesystem, so nothing is refused there.
+func (s *Sandbox) DeniedWrites(ctx context.Context, since time.Time) (denied []DeniedWrite, supported bool, err error) {
+	if err := s.checkNotDestroyed(); err != nil {
+		return nil, false, err
+	}
+	return s.engine.DeniedWrites(ctx, s.name, since)
+}
+
+// ResourceUsage is a running sandbox's CPU and memory use at one moment.
+type ResourceUsage = runtime.ResourceUsage
+
+// Usage measures the running sandbox's CPU and memory use; it takes about a
+// second, since CPU use is worked out from two readings. supported is false
+// when the backend can't measure it (only docker and podman can). Returns
+// ErrContainerNotRunning when the sandbox isn't running.
+func (s *Sandbox) Usage(ctx context.Context) (usage ResourceUsage, supported bool, err error) {
+	if err := s.checkNotDestroyed(); err != nil {
+		return ResourceUsage{}, false, err
+	}
+	return s.engine.Usage(ctx, s.name)
+}
+
+// TokenUsage totals the tokens the sandbox's agent has spent and what they
+// cost, from the records the agent keeps. supported is false when the agent
+// keeps none yoloai can read (Claude Code, Codex and aider do). A pure host
+// read: no backend contact, and a stopped sandbox reports what it spent.
+func (s *Sandbox) TokenUsage() (usage TokenUsage, supported bool, err error) {
+	if err := s.checkNotDestroyed(); err != nil {
+		return TokenUsage{}, false, err
+	}
+	usage, _, supported, err = orchestrator.MeasureTokenUsage(s.engine.Layout(), s.name)
+	return usage, supported, err
+}
+
 // Unlock force-clears a stale lock file for the sandbox. It returns whether a
 // lock was actually cleared (false means there was no lock file present) and
 // surfaces a *UsageError when the recorded holder process is still alive. This
@@ -8,6 +8,7 @@ import (
 	"io"
 
 	"github.com/kstenerud/yoloai/internal/orchestrator"
+	"github.com/kstenerud/yoloai/internal/orchestrator/runtimeconfig"
 )
 
 // Option-mapping convention (IC7).
@@ -55,9 +56,19 @@ type SandboxCreateOptions struct {
 	// agent default.
 	Model string
 
-	// Profile applies a named profile (image, env, settings). Empty = none.
+	// Profile applies a named profile (image, env, settings). Empty = the
+	// profile named by the workdir's .yoloai.yaml, if any; otherwise none.
 	Profile string
 
+	// NoProfile ignores a profile named by the workdir's .yoloai.yaml, so an
+	// empty Profile means the base image.
+	NoProfile bool
+
+	// Image starts the sandbox from an image saved by Sandbox.CommitImage
+	// instead of the base image. It can't be combined with a profile or
+	// Runtimes, and needs a backend that can save images (docker, podman).
+	Image string
+
 	// Prompt is the task description sent to the agent. Empty = interactive.
 	Prompt string
 
@@ -127,6 +138,67 @@ type SandboxCreateOptions struct {
 	// Archetype forces a project archetype (empty = auto-detect).
 	Archetype string
 
+	// WorkRoot places this sandbox's work copies under an absolute host
+	// directory (e.g. a fast scratch disk) instead of inside the sandbox dir.
+	// Empty = the default location. Recorded in the sandbox's environment and
+	// honored by every diff/apply/reset path.
+	WorkRoot string
+
+	// TTL is the sandbox's lifetime — a Go duration ("4h") or days ("7d") —
+	// after which it is expired and `yoloai gc` destroys it. Empty = the ttl
+	// config key; "0" = never expires, overriding the config.
+	TTL string
+
+	// FakeTime runs the sandbox's clock through libfaketime: an offset ("-3d",
+	// "+2h"), a frozen time ("2024-02-29 12:00:00") or a start time that runs
+	// on ("@2024-02-29 12:00:00"). Empty = the faketime config key; "none" =
+	// real time. Linux container backends only.
+	FakeTime string
+
+	// AgentWorkdir starts the agent in this subdirectory of the workdir (e.g.
+	// one package of a monorepo) while the whole workdir stays mounted and
+	// diffed. Relative to the workdir; must exist. Empty = the workdir.
+	AgentWorkdir string
+
+	// Roles splits the session between agents with different jobs against the
+	// same work copy (e.g. "implementer" and "tester"), one tmux window each.
+	// The first role runs in the main window; each prompt is opened with a
+	// notice naming the other roles and the notes file they share. Needs at
+	// least two roles and replaces Prompt/PromptFile. Empty = a single agent.
+	Roles []SandboxRole
+
+	// AllowPush lets the agent push to the real git remotes of its
+	// directories. By default every push from inside the sandbox fails, so a
+	// :rw directory or a copied repo's origin can't be pushed to by accident.
+	AllowPush bool
+
+	// Context chooses where the sandbox context goes. The default
+	// (ContextModeAgent) appends it to the agent's own instruction file in
+	// its state directory. ContextModeWorktree writes it to the agent's
+	// instruction file (AGENTS.md for agents without one) at the root of the
+	// work copy, where agents that read project files find it; diff and apply
+	// never include it, and it needs a :copy workdir so the live directory is
+	// never written to. ContextModeNone gives the agent no context.
+	Context ContextMode
+
+	// Repo clones a remote repository as the workdir instead of copying a host
+	// directory: "URL[@ref]", where ref is a branch, tag or commit (default:
+	// the remote's default branch). The host's git does the clone, so its
+	// credentials apply. The checkout is yoloai's and goes with the sandbox;
+	// the work comes back with Workdir.Apply's PushBranch rather than an apply
+	// to a host path. Leave Workdir empty.
+	Repo string
+
+	// RepoDepth makes the Repo clone shallow, fetching only the ref's last
+	// RepoDepth commits; 0 keeps the full history. A commit ref must then be a
+	// full SHA.
+	RepoDepth int
+
+	// RepoSparse checks out only these directories of Repo (cone mode), over a
+	// blobless clone so nothing outside them is downloaded. Empty checks out
+	// the whole tree.
+	RepoSparse []string
+
 	// AllowDirtyWorkdir proceeds even when the workdir has uncommitted git
 	// changes, overriding *DirtyWorkdirError for the workdir. OR'd with
 	// Workdir.AllowDirty. Aux directories are acked individually via their own
@@ -159,6 +231,8 @@ func (o SandboxCreateOptions) toInternal() orchestrator.CreateOptions {
 		Agent:                string(o.AgentType),
 		Model:                o.Model,
 		Profile:              o.Profile,
+		NoProfile:            o.NoProfile,
+		Image:                o.Image,
 		Prompt:               o.Prompt,
 		PromptFile:           o.PromptFile,
 		Headless:             o.Headless,
@@ -176,10 +250,29 @@ func (o SandboxCreateOptions) toInternal() orchestrator.CreateOptions {
 		Runtimes:             o.Runtimes,
 		VscodeTunnel:         o.VscodeTunnel,
 		Archetype:            o.Archetype,
+		WorkRoot:             o.WorkRoot,
+		TTL:                  o.TTL,
+		FakeTime:             o.FakeTime,
+		AgentWorkdir:         o.AgentWorkdir,
+		Roles:                formatRoles(o.Roles),
+		AllowPush:            o.AllowPush,
+		Context:              o.Context,
+		Repo:                 o.Repo,
+		RepoDepth:            o.RepoDepth,
+		RepoSparse:           o.RepoSparse,
 		Output:               o.Output,
 	}
 }
 
+// SandboxRole is one agent of a split-role session (SandboxCreateOptions.Roles).
+type SandboxRole struct {
+	// Name names the role's tmux window ('attach --window'): lowercase
+	// letters, digits and dashes, starting with a letter.
+	Name string
+	// Prompt is the role's task.
+	Prompt string
+}
+
 // SandboxCloneOptions configures Sandbox.Clone. Source (the receiver sandbox)
 // and Dest (the Clone argument) are not fields here — only the optional
 // behavior knob is. Overwrite (not "Force") is the concern-specific name per
@@ -188,6 +281,18 @@ type SandboxCloneOptions struct {
 	Overwrite bool // destroy the destination first if it already exists
 }
 
+// formatRoles maps public SandboxRoles onto the runtime-config form.
+func formatRoles(roles []SandboxRole) []runtimeconfig.Role {
+	if len(roles) == 0 {
+		return nil
+	}
+	out := make([]runtimeconfig.Role, 0, len(roles))
+	for _, r := range roles {
+		out = append(out, runtimeconfig.Role{Name: r.Name, Prompt: r.Prompt})
+	}
+	return out
+}
+
 // formatPorts renders public PortMappings into the "host:container" strings the
 // internal create path parses (parsePortBindings; tcp-only).
 func formatPorts(ports []PortMapping) []string {
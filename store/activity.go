@@ -0,0 +1,55 @@
+// ABOUTME: The agent output heartbeat (logs/agent-activity.json): when the agent's
+// ABOUTME: tmux window last printed, written by the in-sandbox status monitor.
+package store
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// maxActivitySize bounds how much of agent-activity.json is read. The file is
+// written from inside the sandbox, so its size is not trusted.
+const maxActivitySize = 4 * 1024
+
+// agentActivity is the on-disk shape of agent-activity.json. LastOutput is
+// tmux's #{window_activity} for the agent's window, in unix seconds.
+type agentActivity struct {
+	SchemaVersion int   `json:"schema_version"`
+	LastOutput    int64 `json:"last_output"`
+}
+
+// LoadLastOutput reads when the agent last produced output, from the monitor's
+// heartbeat. Returns the zero time when there is no heartbeat yet (a sandbox
+// that hasn't started, or one whose monitor predates the heartbeat). A
+// symlink, an oversized file, or malformed JSON is an error.
+func LoadLastOutput(sandboxDir string) (time.Time, error) {
+	path := filepath.Join(sandboxDir, AgentActivityFile)
+	info, err := os.Lstat(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return time.Time{}, nil
+		}
+		return time.Time{}, fmt.Errorf("read %s: %w", AgentActivityFile, err)
+	}
+	if !info.Mode().IsRegular() {
+		return time.Time{}, fmt.Errorf("%s is not a regular file", AgentActivityFile)
+	}
+	if info.Size() > maxActivitySize {
+		return time.Time{}, fmt.Errorf("%s is too large (%d bytes, limit %d)", AgentActivityFile, info.Size(), maxActivitySize)
+	}
+	data, err := os.ReadFile(path) //nolint:gosec // path is constructed from sandbox dir
+	if err != nil {
+		return time.Time{}, fmt.Errorf("read %s: %w", AgentActivityFile, err)
+	}
+	var a agentActivity
+	if err := json.Unmarshal(data, &a); err != nil {
+		return time.Time{}, fmt.Errorf("parse %s: %w", AgentActivityFile, err)
+	}
+	if a.LastOutput <= 0 {
+		return time.Time{}, nil
+	}
+	return time.Unix(a.LastOutput, 0), nil
+}
@@ -0,0 +1,63 @@
+// ABOUTME: Agent output heartbeat (logs/agent-activity.json): absent file, a valid
+// ABOUTME: stamp, and rejection of symlinks and malformed content.
+package store
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeActivity writes content as the sandbox's agent-activity.json.
+func writeActivity(t *testing.T, dir, content string) {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, LogsDir), 0750))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, AgentActivityFile), []byte(content), 0600))
+}
+
+func TestLoadLastOutput_Missing(t *testing.T) {
+	last, err := LoadLastOutput(t.TempDir())
+	require.NoError(t, err)
+	assert.True(t, last.IsZero())
+}
+
+func TestLoadLastOutput_Valid(t *testing.T) {
+	dir := t.TempDir()
+	writeActivity(t, dir, `{"schema_version": 1, "last_output": 1760000000}`)
+
+	last, err := LoadLastOutput(dir)
+	require.NoError(t, err)
+	assert.Equal(t, time.Unix(1760000000, 0), last)
+}
+
+func TestLoadLastOutput_NoStampIsZero(t *testing.T) {
+	dir := t.TempDir()
+	writeActivity(t, dir, `{}`)
+
+	last, err := LoadLastOutput(dir)
+	require.NoError(t, err)
+	assert.True(t, last.IsZero())
+}
+
+func TestLoadLastOutput_Malformed(t *testing.T) {
+	dir := t.TempDir()
+	writeActivity(t, dir, `{"last_output": "soon"`)
+
+	_, err := LoadLastOutput(dir)
+	assert.Error(t, err)
+}
+
+func TestLoadLastOutput_RejectsSymlink(t *testing.T) {
+	dir := t.TempDir()
+	target := filepath.Join(dir, "elsewhere.json")
+	require.NoError(t, os.WriteFile(target, []byte(`{"last_output": 1760000000}`), 0600))
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, LogsDir), 0750))
+	require.NoError(t, os.Symlink(target, filepath.Join(dir, AgentActivityFile)))
+
+	_, err := LoadLastOutput(dir)
+	assert.ErrorContains(t, err, "not a regular file")
+}
@@ -0,0 +1,23 @@
+// ABOUTME: ContextMode — typed enum for where create delivers the sandbox
+// ABOUTME: context (the agent's own instruction file, the work copy, or nowhere).
+
+package store
+
+// ContextMode names where the sandbox context (the markdown describing the
+// sandbox to the agent) is written. Closed set: the three constants below.
+type ContextMode string
+
+const (
+	// ContextModeAgent appends the context to the agent's own instruction
+	// file in its state directory (e.g. ~/.claude/CLAUDE.md in the sandbox).
+	// The default; never touches a workdir.
+	ContextModeAgent ContextMode = ""
+	// ContextModeWorktree writes the context to a file at the root of the
+	// workdir's work copy (Environment.ContextFile), hidden from diff and
+	// apply. Needs a :copy workdir, so the live host directory is never
+	// written to.
+	ContextModeWorktree ContextMode = "worktree"
+	// ContextModeNone gives the agent no context. The reference copy at
+	// <sandbox>/context.md is still written.
+	ContextModeNone ContextMode = "none"
+)
@@ -0,0 +1,96 @@
+// ABOUTME: The egress audit log (logs/egress.jsonl): the HTTP(S) destinations an
+// ABOUTME: isolated sandbox's agent asked for, and whether the firewall let them through.
+package store
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Egress verdicts, as egress-proxy.py writes them.
+const (
+	// EgressAllowed means the connection was made.
+	EgressAllowed = "allowed"
+	// EgressBlocked means the firewall refused the connection.
+	EgressBlocked = "blocked"
+	// EgressFailed means the connection failed for another reason (DNS, timeout).
+	EgressFailed = "failed"
+)
+
+// maxEgressLine bounds one line of egress.jsonl. The file is written from
+// inside the sandbox, so its lines are not trusted; longer ones are skipped.
+const maxEgressLine = 16 * 1024
+
+// EgressEvent is one request recorded by the egress audit proxy.
+type EgressEvent struct {
+	Time    time.Time `json:"time"`
+	Method  string    `json:"method"` // CONNECT for HTTPS, else the HTTP method
+	Host    string    `json:"host"`
+	Port    int       `json:"port"`
+	Verdict string    `json:"verdict"`
+	Error   string    `json:"error,omitempty"`
+}
+
+// egressLine is the on-disk shape of one egress.jsonl line.
+type egressLine struct {
+	TS      string `json:"ts"`
+	Method  string `json:"method"`
+	Host    string `json:"host"`
+	Port    int    `json:"port"`
+	Verdict string `json:"verdict"`
+	Error   string `json:"error"`
+}
+
+// LoadEgress reads the egress events recorded at or after since, oldest
+// first. A sandbox with no log yet has no events. Malformed or oversized
+// lines are skipped; a symlink is an error.
+func LoadEgress(sandboxDir string, since time.Time) ([]EgressEvent, error) {
+	path := filepath.Join(sandboxDir, EgressJSONLFile)
+	info, err := os.Lstat(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("read %s: %w", EgressJSONLFile, err)
+	}
+	if !info.Mode().IsRegular() {
+		return nil, fmt.Errorf("%s is not a regular file", EgressJSONLFile)
+	}
+	f, err := os.Open(path) //nolint:gosec // path is constructed from sandbox dir
+	if err != nil {
+		return nil, fmt.Errorf("read %s: %w", EgressJSONLFile, err)
+	}
+	defer f.Close() //nolint:errcheck // read-only
+
+	var events []EgressEvent
+	r := bufio.NewReaderSize(f, maxEgressLine)
+	for {
+		line, isPrefix, err := r.ReadLine()
+		if err != nil {
+			break
+		}
+		if isPrefix {
+			// Oversized: drain the rest of it and move on.
+			for isPrefix && err == nil {
+				_, isPrefix, err = r.ReadLine()
+			}
+			continue
+		}
+		var l egressLine
+		if json.Unmarshal(line, &l) != nil || l.Host == "" {
+			continue
+		}
+		ts, err := time.Parse(time.RFC3339Nano, l.TS)
+		if err != nil || ts.Before(since) {
+			continue
+		}
+		events = append(events, EgressEvent{
+			Time: ts, Method: l.Method, Host: l.Host, Port: l.Port, Verdict: l.Verdict, Error: l.Error,
+		})
+	}
+	return events, nil
+}
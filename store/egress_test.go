@@ -0,0 +1,41 @@
+// ABOUTME: LoadEgress: parsing egress.jsonl, the since cutoff, and skipping
+// ABOUTME: malformed and oversized lines.
+package store
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadEgress(t *testing.T) {
+	dir := t.TempDir()
+	events, err := LoadEgress(dir, time.Time{})
+	require.NoError(t, err)
+	assert.Empty(t, events, "no log yet")
+
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, LogsDir), 0o750))
+	log := strings.Join([]string{
+		`{"ts": "2026-03-01T10:00:00.000Z", "method": "CONNECT", "host": "old.example", "port": 443, "verdict": "allowed"}`,
+		`{"ts": "2026-03-01T12:00:00.250Z", "method": "CONNECT", "host": "api.example", "port": 443, "verdict": "allowed"}`,
+		`not json`,
+		`{"ts": "2026-03-01T12:00:01.000Z", "host": "x", "pad": "` + strings.Repeat("x", maxEgressLine) + `"}`,
+		`{"ts": "2026-03-01T12:00:02.000Z", "method": "GET", "host": "evil.example", "port": 80, "verdict": "blocked", "error": "refused"}`,
+		``,
+	}, "\n")
+	require.NoError(t, os.WriteFile(filepath.Join(dir, EgressJSONLFile), []byte(log), 0o600))
+
+	events, err = LoadEgress(dir, time.Date(2026, 3, 1, 11, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+	assert.Equal(t, EgressEvent{
+		Time: time.Date(2026, 3, 1, 12, 0, 0, 250e6, time.UTC), Method: "CONNECT", Host: "api.example", Port: 443, Verdict: EgressAllowed,
+	}, events[0])
+	assert.Equal(t, EgressBlocked, events[1].Verdict)
+	assert.Equal(t, "refused", events[1].Error)
+}
@@ -4,9 +4,11 @@ package store
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/kstenerud/yoloai/internal/config"
@@ -67,6 +69,13 @@ type Environment struct {
 	Devices            []string               `json:"devices,omitempty"`
 	Setup              []string               `json:"setup,omitempty"`
 	AutoCommitInterval int                    `json:"auto_commit_interval,omitempty"`
+	ProvenanceHeaders  bool                   `json:"provenance_headers,omitempty"` // mark agent-created files with a provenance header on apply
+	Timezone           string                 `json:"timezone,omitempty"`           // TZ inside the sandbox, resolved at create (config, else host); "" = image default
+	Locale             string                 `json:"locale,omitempty"`             // LANG inside the sandbox, resolved at create (config, else host); "" = C.UTF-8
+	FakeTime           string                 `json:"faketime,omitempty"`           // libfaketime FAKETIME spec for the sandbox's clock; "" = real time
+	AgentWorkdir       string                 `json:"agent_workdir,omitempty"`      // --agent-workdir: workdir-relative, slash-separated dir the agent starts in; "" = the workdir
+	Roles              []string               `json:"roles,omitempty"`              // --role names in window order: the first runs in the main window; empty = a single agent
+	AllowPush          bool                   `json:"allow_push,omitempty"`         // --allow-push: the agent's git push reaches real remotes; false = pushes are blocked
 	Debug              bool                   `json:"debug,omitempty"`
 	UsernsMode         string                 `json:"userns_mode,omitempty"`        // "keep-id" for Podman rootless keep-id; "" otherwise
 	Isolation          runtime.IsolationMode  `json:"isolation,omitempty"`          // isolation mode: container, container-enhanced, vm, vm-enhanced
@@ -75,6 +84,35 @@ type Environment struct {
 	BrokerCredentials  bool                   `json:"broker_credentials,omitempty"` // forced-on: --broker (D106). Sticky across restart so the key isn't silently re-delivered direct
 	BrokerDisabled     bool                   `json:"broker_disabled,omitempty"`    // forced-off: --no-broker (D106). Sticky opt-out of the default-on brokering. At most one of these two is set
 	Archetype          string                 `json:"archetype,omitempty"`          // resolved environment archetype (simple, compose, devcontainer, apple)
+
+	// Context is where create delivered the sandbox context (--context), and
+	// ContextFile the work-copy-relative file it went to under
+	// ContextModeWorktree, which reset writes again after re-copying.
+	Context     ContextMode `json:"context,omitempty"`
+	ContextFile string      `json:"context_file,omitempty"`
+
+	// ExpiresAt is when the sandbox's TTL (--ttl / the ttl config key) runs
+	// out, after which `yoloai gc` destroys it. nil = never expires.
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+
+	// RepoURL / RepoRef record where a sandbox created with --repo was cloned
+	// from (the ref is "" for the remote's default branch). Its workdir host
+	// path is then a yoloai-owned checkout under Layout.ReposDir rather than a
+	// directory of the user's; apply pushes to a branch of RepoURL instead of
+	// landing there, and teardown removes the checkout once no sandbox uses it.
+	RepoURL string `json:"repo_url,omitempty"`
+	RepoRef string `json:"repo_ref,omitempty"`
+	// RepoDepth / RepoSparse record how that clone was trimmed: a shallow
+	// clone of RepoDepth commits (0 = full history) and a cone-mode sparse
+	// checkout of the RepoSparse directories (empty = the whole tree).
+	RepoDepth  int      `json:"repo_depth,omitempty"`
+	RepoSparse []string `json:"repo_sparse,omitempty"`
+
+	// WorkRoot is the --work-root override: when set, the work copies live
+	// under store.WorkRootDir(WorkRoot, Name) and <sandboxDir>/work is a
+	// symlink to it. Path consumers never read this (they follow the link);
+	// teardown and clone do, since they must handle the out-of-tree half.
+	WorkRoot string `json:"work_root,omitempty"`
 }
 
 // DirEnvironment stores resolved directory state at creation time, for every
@@ -98,6 +136,20 @@ type DirEnvironment struct {
 	// preserves history — but only where the backend confines work-copy git; the
 	// create/reset gate downgrades to strip on unconfined backends regardless.
 	StripHistory bool `json:"strip_history,omitempty"`
+	// SourceRootSHA / SourceRemote record the identity of the host repository
+	// the copy was taken from (its root commit and origin URL), captured at
+	// create for copy-mode dirs whose source is a git repo. Apply checks the
+	// root commit still exists at HostPath before landing anything, so a source
+	// that was moved, deleted, or replaced by a different repo yields a clear
+	// refusal instead of a nonsensical patch. Empty for non-git sources and for
+	// sandboxes created before these fields existed — the check is then skipped.
+	SourceRootSHA string `json:"source_root_sha,omitempty"`
+	SourceRemote  string `json:"source_remote,omitempty"`
+	// PublishedRefs maps "<remote URL> <ref>" to the commit Publish last
+	// pushed there. Publish overwrites a remote ref only while it still holds
+	// that commit (or, in yoloai's own refs/yoloai/ namespace, doesn't exist),
+	// so it never clobbers a branch someone else created or pushed to.
+	PublishedRefs map[string]string `json:"published_refs,omitempty"`
 }
 
 // Workdir returns the primary directory — Dirs[0], the agent's cwd. Returns nil
@@ -254,6 +306,12 @@ func MigrateEnvironment(meta *Environment) error {
 // before writing, so a crash mid-write leaves a torn record that no longer
 // parses. AtomicWriteFile closes both: temp + fsync + rename + dir fsync, so a
 // reader sees the old record or the new one, and never neither.
+//
+// The record being replaced is kept as environment.json.prev (only when it still
+// parses — a damaged record must never overwrite a good fallback). Atomicity
+// guards against our own torn writes; the previous copy guards against
+// everything else (a full disk, an editor, a bad sync) that can leave
+// environment.json unreadable. LoadPreviousEnvironment reads it.
 func SaveEnvironment(dir string, meta *Environment) error {
 	meta.Version = metaVersion
 	data, err := json.MarshalIndent(meta, "", "  ")
@@ -262,10 +320,34 @@ func SaveEnvironment(dir string, meta *Environment) error {
 	}
 
 	path := filepath.Join(dir, EnvironmentFile)
+	if old, readErr := os.ReadFile(path); readErr == nil && json.Valid(old) { //nolint:gosec // path is constructed from sandbox dir, not user input
+		if err := fileutil.AtomicWriteFile(filepath.Join(dir, EnvironmentPrevFile), old, 0600); err != nil {
+			return fmt.Errorf("write %s: %w", EnvironmentPrevFile, err)
+		}
+	}
 	if err := fileutil.AtomicWriteFile(path, data, 0600); err != nil {
 		return fmt.Errorf("write %s: %w", EnvironmentFile, err)
 	}
 
+	return writeBaselines(dir, meta)
+}
+
+// writeBaselines publishes meta's :copy baselines to BaselinesFile for the
+// sandbox side. A sandbox dir without logs/ (not yet laid out) has no reader.
+func writeBaselines(dir string, meta *Environment) error {
+	if _, err := os.Stat(LogsPath(dir)); err != nil {
+		return nil //nolint:nilerr // nothing inside the sandbox to publish to yet
+	}
+	var b strings.Builder
+	for _, d := range meta.Dirs {
+		if d.Mode == DirModeCopy && d.BaselineSHA != "" {
+			fmt.Fprintf(&b, "%s %s\n", d.BaselineSHA, d.MountPath)
+		}
+	}
+	// 0644: the sandbox user reads it, and a SHA is no secret.
+	if err := fileutil.AtomicWriteFile(filepath.Join(dir, BaselinesFile), []byte(b.String()), 0644); err != nil { //nolint:gosec // G306: see above
+		return fmt.Errorf("write %s: %w", BaselinesFile, err)
+	}
 	return nil
 }
 
@@ -297,19 +379,38 @@ func ContainerUser(meta *Environment, hostUID int) string {
 // is read from the raw bytes BEFORE unmarshalling into the slimmed struct —
 // otherwise the dropped agent/model keys would vanish silently before the
 // migration could relocate them.
+//
+// A record that exists but does not parse is an error, never quietly replaced
+// by environment.json.prev: the previous copy predates the last save, so
+// running on it would lose that save's changes (a moved baseline, a new dir)
+// without anyone noticing. LoadPreviousEnvironment, run by `yoloai system
+// recover`, is the one place it stands in, and the error says so.
 func LoadEnvironment(dir string) (*Environment, error) {
-	path := filepath.Join(dir, EnvironmentFile)
+	meta, err := loadEnvironmentFile(dir, EnvironmentFile)
+	if err != nil && errors.Is(err, errCorruptEnvironment) {
+		return nil, fmt.Errorf("%w (run `yoloai system recover %s` to rebuild it)", err, filepath.Base(dir))
+	}
+	return meta, err
+}
+
+// errCorruptEnvironment marks a record that was read but would not parse — the
+// one failure a previous copy can stand in for.
+var errCorruptEnvironment = errors.New("corrupt record")
+
+// loadEnvironmentFile reads and version-checks one environment record.
+func loadEnvironmentFile(dir, file string) (*Environment, error) {
+	path := filepath.Join(dir, file)
 
 	data, err := os.ReadFile(path) //nolint:gosec // path is constructed from sandbox dir, not user input
 	if err != nil {
-		return nil, fmt.Errorf("read %s: %w", EnvironmentFile, err)
+		return nil, fmt.Errorf("read %s: %w", file, err)
 	}
 
 	var probe struct {
 		Version int `json:"version"`
 	}
 	if err := json.Unmarshal(data, &probe); err != nil {
-		return nil, fmt.Errorf("parse %s: %w", EnvironmentFile, err)
+		return nil, fmt.Errorf("parse %s: %w: %w", file, errCorruptEnvironment, err)
 	}
 	switch {
 	case probe.Version > metaVersion:
@@ -317,13 +418,122 @@ func LoadEnvironment(dir string) (*Environment, error) {
 			"(meta version %d, this binary knows %d); upgrade yoloai to use it",
 			probe.Version, metaVersion)
 	case probe.Version < metaVersion:
-		return nil, fmt.Errorf("%s is at schema v%d: %w", EnvironmentFile, probe.Version, ErrNeedsMigration)
+		return nil, fmt.Errorf("%s is at schema v%d: %w", file, probe.Version, ErrNeedsMigration)
 	}
 
 	var meta Environment
 	if err := json.Unmarshal(data, &meta); err != nil {
-		return nil, fmt.Errorf("parse %s: %w", EnvironmentFile, err)
+		return nil, fmt.Errorf("parse %s: %w: %w", file, errCorruptEnvironment, err)
 	}
 
 	return &meta, nil
 }
+
+// LoadPreviousEnvironment loads the environment.json.prev that SaveEnvironment
+// keeps beside the record, for recovering a sandbox whose environment.json is
+// missing or unreadable. The copy can be older than the record it stands in
+// for, so it is used only when it still describes the work directory on disk:
+// every copy-mode dir it lists has its work copy, and every work copy holding
+// a .git belongs to a dir it lists. Otherwise it returns an error naming the
+// mismatch.
+func LoadPreviousEnvironment(dir string) (*Environment, error) {
+	prev, err := loadEnvironmentFile(dir, EnvironmentPrevFile)
+	if err != nil {
+		return nil, err
+	}
+	listed := make(map[string]bool, len(prev.Dirs))
+	for _, d := range prev.Dirs {
+		if d.Mode != DirModeCopy {
+			continue
+		}
+		listed[EncodePath(d.HostPath)] = true
+		if _, err := os.Stat(WorkDir(dir, d.HostPath)); err != nil {
+			return nil, fmt.Errorf("%s lists %s, which has no work copy", EnvironmentPrevFile, d.HostPath)
+		}
+	}
+	entries, err := os.ReadDir(filepath.Join(dir, "work"))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+	for _, entry := range entries {
+		if listed[entry.Name()] || !isWorkCopy(dir, entry) {
+			continue
+		}
+		hostPath, _ := DecodePath(entry.Name())
+		return nil, fmt.Errorf("%s does not list the work copy of %s", EnvironmentPrevFile, hostPath)
+	}
+	return prev, nil
+}
+
+// isWorkCopy reports whether a work/ entry is a work copy: a directory named
+// for a host path that holds a .git.
+func isWorkCopy(dir string, entry os.DirEntry) bool {
+	if !entry.IsDir() {
+		return false
+	}
+	if _, err := DecodePath(entry.Name()); err != nil {
+		return false
+	}
+	_, err := os.Stat(filepath.Join(dir, "work", entry.Name(), ".git"))
+	return err == nil
+}
+
+// ReconstructEnvironment rebuilds a minimal record for a sandbox whose
+// environment.json is missing or unreadable and whose environment.json.prev is
+// unusable, without writing anything (the caller saves it): one copy-mode dir
+// per work copy holding a .git. labels, the instance's labels when the backend
+// can read them back (nil otherwise), fill in what the work directory can't
+// say: the yoloai version and profile, and which copy is the workdir
+// (LabelWorkdir). Without them the workdir is the copy runtime-config.json's
+// working_dir names (else the first found). A work directory that is a link
+// into a --work-root restores WorkRoot. Baselines live in git, not here, so
+// BaselineSHA stays empty for the caller to find. backend is the runtime the
+// sandbox was created on (the work dir does not record it).
+func ReconstructEnvironment(dir, name string, backend runtime.BackendType, labels map[string]string) (*Environment, error) {
+	workRoot := filepath.Join(dir, "work")
+	entries, err := os.ReadDir(workRoot)
+	if err != nil {
+		return nil, fmt.Errorf("no work directory to reconstruct from: %w", err)
+	}
+	meta := &Environment{
+		Name:          name,
+		BackendType:   backend,
+		YoloaiVersion: labels[runtime.LabelVersion],
+		Profile:       labels[runtime.LabelProfile],
+	}
+	if info, statErr := os.Stat(workRoot); statErr == nil {
+		meta.CreatedAt = info.ModTime()
+	}
+	if target, linkErr := os.Readlink(workRoot); linkErr == nil && filepath.Base(target) == name {
+		meta.WorkRoot = filepath.Dir(target)
+	}
+	for _, entry := range entries {
+		if !isWorkCopy(dir, entry) {
+			continue
+		}
+		hostPath, _ := DecodePath(entry.Name())
+		meta.Dirs = append(meta.Dirs, DirEnvironment{HostPath: hostPath, MountPath: hostPath, Mode: DirModeCopy})
+	}
+	if len(meta.Dirs) == 0 {
+		return nil, fmt.Errorf("no recognizable work copies under %s", workRoot)
+	}
+
+	isWorkdir := func(DirEnvironment) bool { return false }
+	if hash := labels[runtime.LabelWorkdir]; hash != "" {
+		isWorkdir = func(d DirEnvironment) bool { return runtime.WorkdirHash(d.HostPath) == hash }
+	} else {
+		var rc struct {
+			WorkingDir string `json:"working_dir"`
+		}
+		if data, readErr := os.ReadFile(filepath.Join(dir, RuntimeConfigFile)); readErr == nil && json.Unmarshal(data, &rc) == nil { //nolint:gosec // path is constructed from sandbox dir, not user input
+			isWorkdir = func(d DirEnvironment) bool { return d.MountPath == rc.WorkingDir }
+		}
+	}
+	for i, d := range meta.Dirs {
+		if isWorkdir(d) {
+			meta.Dirs[0], meta.Dirs[i] = meta.Dirs[i], meta.Dirs[0]
+			break
+		}
+	}
+	return meta, nil
+}
@@ -1,6 +1,8 @@
 // ABOUTME: Environment (environment.json) save/load round-trip, omitempty
 // ABOUTME: field shaping, and the versioned migration ladder (v0->v1->v2) that
-// ABOUTME: balks below-current-version reads (M2/D61) rather than auto-migrate.
+// ABOUTME: balks below-current-version reads (M2/D61) rather than auto-migrate,
+// ABOUTME: plus the retained environment.json.prev, LoadPreviousEnvironment and
+// ABOUTME: ReconstructEnvironment.
 package store
 
 import (
@@ -321,3 +323,179 @@ func TestEnvironment_MountPaths(t *testing.T) {
 	// Empty environment yields an empty (non-nil is fine) slice.
 	assert.Empty(t, (&Environment{}).MountPaths())
 }
+
+func TestSaveEnvironment_RetainsPrevious(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, SaveEnvironment(dir, &Environment{Name: "first"}))
+	_, err := os.Stat(filepath.Join(dir, EnvironmentPrevFile))
+	assert.True(t, os.IsNotExist(err), "first save has nothing to retain")
+
+	require.NoError(t, SaveEnvironment(dir, &Environment{Name: "second"}))
+	prev, err := loadEnvironmentFile(dir, EnvironmentPrevFile)
+	require.NoError(t, err)
+	assert.Equal(t, "first", prev.Name)
+}
+
+func TestSaveEnvironment_CorruptRecordDoesNotClobberPrevious(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, SaveEnvironment(dir, &Environment{Name: "first"}))
+	require.NoError(t, SaveEnvironment(dir, &Environment{Name: "second"}))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, EnvironmentFile), []byte("{torn"), 0600))
+
+	require.NoError(t, SaveEnvironment(dir, &Environment{Name: "third"}))
+	prev, err := loadEnvironmentFile(dir, EnvironmentPrevFile)
+	require.NoError(t, err)
+	assert.Equal(t, "first", prev.Name)
+}
+
+func TestSaveEnvironment_PublishesBaselines(t *testing.T) {
+	dir := t.TempDir()
+	env := &Environment{Name: "box", Dirs: []DirEnvironment{
+		{HostPath: "/src/app", MountPath: "/src/app", Mode: DirModeCopy, BaselineSHA: "abc123"},
+		{HostPath: "/src/lib", MountPath: "/opt/my lib", Mode: DirModeCopy, BaselineSHA: "def456"},
+		{HostPath: "/src/ro", MountPath: "/src/ro", Mode: DirModeRO},
+	}}
+
+	// No logs/ yet: nothing inside the sandbox to publish to.
+	require.NoError(t, SaveEnvironment(dir, env))
+	_, err := os.Stat(filepath.Join(dir, BaselinesFile))
+	assert.True(t, os.IsNotExist(err))
+
+	require.NoError(t, os.Mkdir(LogsPath(dir), 0o750))
+	require.NoError(t, SaveEnvironment(dir, env))
+	data, err := os.ReadFile(filepath.Join(dir, BaselinesFile))
+	require.NoError(t, err)
+	assert.Equal(t, "abc123 /src/app\ndef456 /opt/my lib\n", string(data))
+}
+
+func TestLoadEnvironment_CorruptDoesNotFallBack(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "box")
+	require.NoError(t, os.MkdirAll(dir, 0750))
+	require.NoError(t, SaveEnvironment(dir, &Environment{Name: "first"}))
+	require.NoError(t, SaveEnvironment(dir, &Environment{Name: "second"}))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, EnvironmentFile), []byte("{torn"), 0600))
+
+	_, err := LoadEnvironment(dir)
+	require.Error(t, err, "the older record must not stand in unannounced")
+	assert.Contains(t, err.Error(), "yoloai system recover box")
+}
+
+func TestLoadEnvironment_MissingDoesNotFallBack(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, SaveEnvironment(dir, &Environment{Name: "first"}))
+	require.NoError(t, SaveEnvironment(dir, &Environment{Name: "second"}))
+	require.NoError(t, os.Remove(filepath.Join(dir, EnvironmentFile)))
+
+	_, err := LoadEnvironment(dir)
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestReconstructEnvironment_FromWorkDir(t *testing.T) {
+	dir := t.TempDir()
+	for _, p := range []string{"/src/aux", "/src/app"} {
+		require.NoError(t, os.MkdirAll(filepath.Join(WorkDir(dir, p), ".git"), 0750))
+	}
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "work", EncodePath("/src/empty")), 0750))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, RuntimeConfigFile), []byte(`{"working_dir":"/src/app"}`), 0600))
+
+	meta, err := ReconstructEnvironment(dir, "box", runtime.BackendType("docker"), nil)
+	require.NoError(t, err)
+	assert.Equal(t, "box", meta.Name)
+	require.Len(t, meta.Dirs, 2)
+	assert.Equal(t, "/src/app", meta.Workdir().HostPath)
+	assert.Equal(t, DirModeCopy, meta.Workdir().Mode)
+	assert.Equal(t, "/src/aux", meta.AuxDirs()[0].HostPath)
+}
+
+func TestReconstructEnvironment_FromLabels(t *testing.T) {
+	dir := t.TempDir()
+	for _, p := range []string{"/src/aux", "/src/app"} {
+		require.NoError(t, os.MkdirAll(filepath.Join(WorkDir(dir, p), ".git"), 0750))
+	}
+	// runtime-config.json disagrees; the instance's label wins.
+	require.NoError(t, os.WriteFile(filepath.Join(dir, RuntimeConfigFile), []byte(`{"working_dir":"/src/aux"}`), 0600))
+	labels := map[string]string{
+		runtime.LabelVersion: "1.2.3",
+		runtime.LabelProfile: "go",
+		runtime.LabelWorkdir: runtime.WorkdirHash("/src/app"),
+	}
+
+	meta, err := ReconstructEnvironment(dir, "box", runtime.BackendType("docker"), labels)
+	require.NoError(t, err)
+	assert.Equal(t, "/src/app", meta.Workdir().HostPath)
+	assert.Equal(t, "1.2.3", meta.YoloaiVersion)
+	assert.Equal(t, "go", meta.Profile)
+}
+
+func TestReconstructEnvironment_NothingToRecover(t *testing.T) {
+	_, err := ReconstructEnvironment(t.TempDir(), "box", runtime.BackendType("docker"), nil)
+	assert.Error(t, err)
+}
+
+func TestReconstructEnvironment_RestoresWorkRoot(t *testing.T) {
+	dir := t.TempDir()
+	workRoot := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(WorkRootDir(workRoot, "box"), EncodePath("/src/app"), ".git"), 0750))
+	require.NoError(t, os.Symlink(WorkRootDir(workRoot, "box"), filepath.Join(dir, "work")))
+
+	meta, err := ReconstructEnvironment(dir, "box", runtime.BackendType("docker"), nil)
+	require.NoError(t, err)
+	assert.Equal(t, workRoot, meta.WorkRoot)
+	assert.Equal(t, "/src/app", meta.Workdir().HostPath)
+}
+
+func TestLoadPreviousEnvironment_MatchesWorkDir(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(WorkDir(dir, "/src/app"), ".git"), 0750))
+	env := &Environment{Name: "box", Profile: "go", Dirs: []DirEnvironment{{HostPath: "/src/app", MountPath: "/src/app", Mode: DirModeCopy}}}
+	require.NoError(t, SaveEnvironment(dir, env))
+	require.NoError(t, SaveEnvironment(dir, env))
+	require.NoError(t, os.Remove(filepath.Join(dir, EnvironmentFile)))
+
+	meta, err := LoadPreviousEnvironment(dir)
+	require.NoError(t, err)
+	assert.Equal(t, "go", meta.Profile)
+}
+
+func TestLoadPreviousEnvironment_RejectsStaleRecord(t *testing.T) {
+	tests := []struct {
+		name  string
+		dirs  []DirEnvironment
+		work  []string
+		wants string
+	}{
+		{
+			name:  "listed copy missing",
+			dirs:  []DirEnvironment{{HostPath: "/src/app", MountPath: "/src/app", Mode: DirModeCopy}, {HostPath: "/src/gone", MountPath: "/src/gone", Mode: DirModeCopy}},
+			work:  []string{"/src/app"},
+			wants: "/src/gone",
+		},
+		{
+			name:  "work copy not listed",
+			dirs:  []DirEnvironment{{HostPath: "/src/app", MountPath: "/src/app", Mode: DirModeCopy}},
+			work:  []string{"/src/app", "/src/new"},
+			wants: "/src/new",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			for _, p := range tt.work {
+				require.NoError(t, os.MkdirAll(filepath.Join(WorkDir(dir, p), ".git"), 0750))
+			}
+			env := &Environment{Name: "box", Dirs: tt.dirs}
+			require.NoError(t, SaveEnvironment(dir, env))
+			require.NoError(t, SaveEnvironment(dir, env))
+			require.NoError(t, os.Remove(filepath.Join(dir, EnvironmentFile)))
+
+			_, err := LoadPreviousEnvironment(dir)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wants)
+		})
+	}
+}
+
+func TestLoadPreviousEnvironment_Missing(t *testing.T) {
+	_, err := LoadPreviousEnvironment(t.TempDir())
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
@@ -21,4 +21,9 @@ const (
 	LogSourceSandbox LogSource = "sandbox" // sandbox lifecycle events from the in-container entrypoint
 	LogSourceMonitor LogSource = "monitor" // agent idle/active detector emissions from the Python monitor
 	LogSourceHooks   LogSource = "hooks"   // hook-emitted events (Claude Code hooks; future agents may emit here too)
+
+	// LogSourceRuntime is the container/VM runtime's own log of the instance's
+	// stdout/stderr. Unlike the others it has no file in the logs directory —
+	// it is read from the backend — so it is only streamed when asked for.
+	LogSourceRuntime LogSource = "runtime"
 )
@@ -17,6 +17,7 @@ func TestLogSourceConstants(t *testing.T) {
 		{LogSourceSandbox, "sandbox"},
 		{LogSourceMonitor, "monitor"},
 		{LogSourceHooks, "hooks"},
+		{LogSourceRuntime, "runtime"},
 	}
 	for _, c := range cases {
 		if string(c.got) != c.want {
@@ -34,6 +34,10 @@ const (
 	// EnvironmentFile stores sandbox metadata captured at creation time.
 	EnvironmentFile = "environment.json"
 
+	// EnvironmentPrevFile retains the environment.json that the most recent
+	// SaveEnvironment replaced, so a damaged record has a fallback.
+	EnvironmentPrevFile = "environment.json.prev"
+
 	// SandboxStateFile stores per-sandbox persistent flags.
 	SandboxStateFile = "sandbox-state.json"
 
@@ -72,12 +76,29 @@ const (
 	// MonitorJSONLFile is the relative path to the status monitor structured log.
 	MonitorJSONLFile = "logs/monitor.jsonl"
 
+	// EgressJSONLFile is the relative path to the egress audit log: one line per
+	// HTTP(S) destination an isolated sandbox's agent asked for, written by the
+	// in-sandbox egress-proxy.py.
+	EgressJSONLFile = "logs/egress.jsonl"
+
 	// HooksJSONLFile is the relative path to the agent hooks structured log.
 	HooksJSONLFile = "logs/agent-hooks.jsonl"
 
 	// AgentLogFile is the relative path to the raw agent terminal output log.
 	AgentLogFile = "logs/agent.log"
 
+	// AgentCastFile is the relative path to the agent terminal recording: the
+	// same stream as AgentLogFile with its timing, in asciicast v2 format.
+	// status-monitor.py --record writes it.
+	AgentCastFile = "logs/agent.cast"
+
+	// AgentActivityFile is the relative path to the status monitor's output
+	// heartbeat: when the agent's tmux window last printed anything. It lives
+	// under logs/ because that directory is bind-mounted, so the monitor's
+	// writes reach the host (see SecretsConsumedMarker); status-monitor.py
+	// hard-codes the same relative path.
+	AgentActivityFile = "logs/agent-activity.json"
+
 	// SecretsConsumedMarker is a host-visible marker the in-sandbox
 	// entrypoint writes after it has read /run/secrets into the agent's
 	// environment. The host waits for this marker before removing the
@@ -104,6 +125,14 @@ const (
 	// writes it. Lives under logs/ for the same bind-mount reason as
 	// SecretsConsumedMarker; entrypoint.py hard-codes the same relative path.
 	SubstrateReadyMarker = "logs/.substrate-ready"
+
+	// BaselinesFile lists each :copy directory's diff baseline as
+	// "<sha> <mount path>" lines, rewritten by SaveEnvironment whenever the
+	// environment is. It lets the in-sandbox yoloai-diffwatch show changes
+	// against the same baseline `yoloai diff` uses; environment.json itself
+	// isn't mounted. Lives under logs/ for the same bind-mount reason as
+	// SecretsConsumedMarker.
+	BaselinesFile = "logs/baselines"
 )
 
 // EncodePath encodes a host path using the caret encoding spec for use as a
@@ -205,6 +234,11 @@ func AgentLogPath(sandboxDir string) string {
 	return filepath.Join(sandboxDir, AgentLogFile)
 }
 
+// AgentCastPath returns the path to logs/agent.cast within a sandbox.
+func AgentCastPath(sandboxDir string) string {
+	return filepath.Join(sandboxDir, AgentCastFile)
+}
+
 // PromptFilePath returns the path to prompt.txt within a sandbox.
 func PromptFilePath(sandboxDir string) string {
 	return filepath.Join(sandboxDir, "prompt.txt")
@@ -249,6 +283,10 @@ func QuarantineSandbox(layout config.Layout, name string) (string, error) {
 	if err := os.Rename(src, dest); err != nil {
 		return "", fmt.Errorf("quarantine sandbox %q to trash: %w", name, err)
 	}
+	// The entry's mtime records when it was trashed, which the retention
+	// scrub ages it by.
+	now := time.Now()
+	_ = os.Chtimes(dest, now, now) //nolint:errcheck // best-effort
 	return dest, nil
 }
 
@@ -260,6 +298,36 @@ func WorkDir(sandboxDir string, hostPath string) string {
 	return filepath.Join(sandboxDir, "work", EncodePath(hostPath))
 }
 
+// WorkRootDir returns where a sandbox created with a work-root override keeps
+// its work copies. <sandboxDir>/work is a symlink to this directory, so every
+// WorkDir caller (diff, apply, reset, mounts, status) resolves into it without
+// knowing the override exists; Environment.WorkRoot records it so teardown and
+// clone can find the out-of-tree half.
+//
+//	<workRoot>/<name>/
+func WorkRootDir(workRoot, name string) string {
+	return filepath.Join(workRoot, name)
+}
+
+// LinkWorkRoot creates the external work-copy directory for a sandbox and
+// points <sandboxDir>/work at it. The target must not already exist: an
+// existing directory is either another sandbox's work copies or leftovers the
+// user has not reviewed, and adopting either would be silent data mixing.
+func LinkWorkRoot(sandboxDir, workRoot, name string, perm os.FileMode) error {
+	target := WorkRootDir(workRoot, name)
+	if _, err := os.Stat(target); err == nil {
+		return fmt.Errorf("work root %s already exists; remove it or choose another --work-root", target)
+	}
+	if err := fileutil.MkdirAllPerm(target, perm); err != nil {
+		return fmt.Errorf("create work root %s: %w", target, err)
+	}
+	if err := os.Symlink(target, filepath.Join(sandboxDir, "work")); err != nil {
+		_ = os.Remove(target)
+		return fmt.Errorf("link work root %s: %w", target, err)
+	}
+	return nil
+}
+
 // OverlayLowerDir returns the mount-point directory, under a retired overlay
 // sandbox's work base (<sandboxDir>/work/<caret-encoded-path>/), where the
 // user's original workdir was bind-mounted read-only. :overlay is retired
@@ -278,6 +346,22 @@ func FilesDir(sandboxDir string) string {
 	return filepath.Join(sandboxDir, "files")
 }
 
+// InboxDir returns the prompt inbox within a sandbox. A *.md file dropped here
+// is submitted to the running agent as its next prompt once it is idle.
+//
+//	<sandboxDir>/inbox/
+func InboxDir(sandboxDir string) string {
+	return filepath.Join(sandboxDir, "inbox")
+}
+
+// OutboxDir returns the artifact outbox within a sandbox: files the agent hands
+// back to the user (reports, generated assets).
+//
+//	<sandboxDir>/outbox/
+func OutboxDir(sandboxDir string) string {
+	return filepath.Join(sandboxDir, "outbox")
+}
+
 // CacheDir returns the host-side cache directory within a sandbox.
 //
 //	<sandboxDir>/cache/
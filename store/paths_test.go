@@ -4,6 +4,7 @@
 package store
 
 import (
+	"os"
 	"path/filepath"
 	"testing"
 
@@ -288,3 +289,23 @@ func TestFilesDir(t *testing.T) {
 	expected := filepath.Join(sandboxDir, "files")
 	assert.Equal(t, expected, FilesDir(sandboxDir))
 }
+
+func TestLinkWorkRoot(t *testing.T) {
+	sandboxDir := t.TempDir()
+	workRoot := t.TempDir()
+
+	require.NoError(t, LinkWorkRoot(sandboxDir, workRoot, "box", 0o750))
+
+	target, err := os.Readlink(filepath.Join(sandboxDir, "work"))
+	require.NoError(t, err)
+	assert.Equal(t, WorkRootDir(workRoot, "box"), target)
+	info, err := os.Stat(filepath.Join(sandboxDir, "work"))
+	require.NoError(t, err)
+	assert.True(t, info.IsDir(), "work/ resolves through the link")
+
+	// A second sandbox claiming the same target is refused rather than sharing it.
+	other := t.TempDir()
+	err = LinkWorkRoot(other, workRoot, "box", 0o750)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "already exists")
+}
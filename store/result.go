@@ -0,0 +1,81 @@
+// ABOUTME: The agent result contract (outbox/result.json): an optional structured
+// ABOUTME: summary the agent writes when it finishes, parsed for status/list/run.
+package store
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// ResultFile is the name of the agent result file within the outbox.
+const ResultFile = "result.json"
+
+// maxResultSize bounds how much of result.json is read. The file is written
+// from inside the sandbox, so its size is not trusted.
+const maxResultSize = 256 * 1024
+
+// Agent result statuses. The agent reports one of these; anything else is
+// rejected so automation can switch on the value.
+const (
+	ResultSuccess = "success" // the task is complete
+	ResultPartial = "partial" // some of the task is done; see follow-ups
+	ResultFailed  = "failed"  // the task could not be done
+	ResultBlocked = "blocked" // the agent needs input or access it doesn't have
+)
+
+// ResultStatuses lists the valid AgentResult.Status values.
+var ResultStatuses = []string{ResultSuccess, ResultPartial, ResultFailed, ResultBlocked}
+
+// AgentResult is the agent's own structured account of its run, read from
+// <sandbox>/outbox/result.json (/yoloai/outbox/result.json inside the sandbox).
+type AgentResult struct {
+	Status    string   `json:"status"`
+	Summary   string   `json:"summary,omitempty"`
+	FollowUps []string `json:"follow_ups,omitempty"`
+	Files     []string `json:"files,omitempty"`
+}
+
+// ResultFilePath returns the path to the agent result file within a sandbox.
+//
+//	<sandboxDir>/outbox/result.json
+func ResultFilePath(sandboxDir string) string {
+	return filepath.Join(OutboxDir(sandboxDir), ResultFile)
+}
+
+// LoadAgentResult reads and validates the sandbox's result.json. Returns
+// (nil, nil) when the agent has not written one. A symlink, an oversized file,
+// malformed JSON, or an unknown status is an error.
+func LoadAgentResult(sandboxDir string) (*AgentResult, error) {
+	path := ResultFilePath(sandboxDir)
+	info, err := os.Lstat(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("read %s: %w", ResultFile, err)
+	}
+	if !info.Mode().IsRegular() {
+		return nil, fmt.Errorf("%s is not a regular file", ResultFile)
+	}
+	if info.Size() > maxResultSize {
+		return nil, fmt.Errorf("%s is too large (%d bytes, limit %d)", ResultFile, info.Size(), maxResultSize)
+	}
+
+	data, err := os.ReadFile(path) //nolint:gosec // path is constructed from sandbox dir
+	if err != nil {
+		return nil, fmt.Errorf("read %s: %w", ResultFile, err)
+	}
+	var res AgentResult
+	if err := json.Unmarshal(data, &res); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", ResultFile, err)
+	}
+	res.Status = strings.ToLower(strings.TrimSpace(res.Status))
+	if !slices.Contains(ResultStatuses, res.Status) {
+		return nil, fmt.Errorf("%s: invalid status %q (valid: %s)", ResultFile, res.Status, strings.Join(ResultStatuses, ", "))
+	}
+	return &res, nil
+}
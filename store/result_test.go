@@ -0,0 +1,74 @@
+// ABOUTME: Agent result contract (outbox/result.json): absent file, a valid
+// ABOUTME: result, and rejection of bad statuses, symlinks and oversized files.
+package store
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeResult writes content as the sandbox's result.json.
+func writeResult(t *testing.T, dir, content string) {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(OutboxDir(dir), 0750))
+	require.NoError(t, os.WriteFile(ResultFilePath(dir), []byte(content), 0600))
+}
+
+func TestLoadAgentResult_Missing(t *testing.T) {
+	res, err := LoadAgentResult(t.TempDir())
+	require.NoError(t, err)
+	assert.Nil(t, res)
+}
+
+func TestLoadAgentResult_Valid(t *testing.T) {
+	dir := t.TempDir()
+	writeResult(t, dir, `{"status": " Partial ", "summary": "half done", "follow_ups": ["finish"], "files": ["a.go"]}`)
+
+	res, err := LoadAgentResult(dir)
+	require.NoError(t, err)
+	assert.Equal(t, &AgentResult{Status: ResultPartial, Summary: "half done", FollowUps: []string{"finish"}, Files: []string{"a.go"}}, res)
+}
+
+func TestLoadAgentResult_InvalidStatus(t *testing.T) {
+	dir := t.TempDir()
+	writeResult(t, dir, `{"status": "done"}`)
+
+	_, err := LoadAgentResult(dir)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `invalid status "done"`)
+}
+
+func TestLoadAgentResult_Malformed(t *testing.T) {
+	dir := t.TempDir()
+	writeResult(t, dir, `{"status":`)
+
+	_, err := LoadAgentResult(dir)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "parse result.json")
+}
+
+func TestLoadAgentResult_RefusesSymlink(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(OutboxDir(dir), 0750))
+	target := filepath.Join(t.TempDir(), "elsewhere.json")
+	require.NoError(t, os.WriteFile(target, []byte(`{"status":"success"}`), 0600))
+	require.NoError(t, os.Symlink(target, ResultFilePath(dir)))
+
+	_, err := LoadAgentResult(dir)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "not a regular file")
+}
+
+func TestLoadAgentResult_TooLarge(t *testing.T) {
+	dir := t.TempDir()
+	writeResult(t, dir, `{"status":"success","summary":"`+strings.Repeat("x", maxResultSize)+`"}`)
+
+	_, err := LoadAgentResult(dir)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "too large")
+}
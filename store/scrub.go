@@ -0,0 +1,62 @@
+// ABOUTME: Retention scrub: removes prompts, logs and agent transcripts from
+// ABOUTME: sandbox directories that no longer belong to a live sandbox.
+
+package store
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// ScrubbedPaths are what a retention scrub removes from a sandbox directory,
+// relative to it: the prompts, the logs (raw agent terminal output included)
+// and the agent's own state, which holds its session transcripts.
+var ScrubbedPaths = []string{"prompt.txt", "resume-prompt.txt", LogsDir, AgentRuntimeDir}
+
+// sandboxDirMarkers identify a sandbox directory inside a trash entry, which
+// may be one quarantined sandbox or a whole displaced sandboxes tree. They are
+// files only yoloai writes; a bare logs/ or prompt.txt could be anybody's.
+var sandboxDirMarkers = []string{EnvironmentFile, RuntimeConfigFile, SandboxStateFile, AgentStatusFile}
+
+// ScrubTranscripts removes ScrubbedPaths from every sandbox directory found
+// under root (root itself included) and returns the paths removed. Work
+// copies and metadata are left alone, so what's left is still recoverable as
+// code. Under dryRun nothing is removed and the paths that would be are
+// returned. The walk never descends into a sandbox directory it found, so a
+// project's own logs/ directory inside a work copy is never touched.
+func ScrubTranscripts(root string, dryRun bool) ([]string, error) {
+	var removed []string
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() || !isSandboxDir(path) {
+			return nil
+		}
+		for _, rel := range ScrubbedPaths {
+			p := filepath.Join(path, rel)
+			if _, statErr := os.Lstat(p); statErr != nil {
+				continue
+			}
+			if !dryRun {
+				if rmErr := os.RemoveAll(p); rmErr != nil {
+					return fmt.Errorf("scrub %s: %w", p, rmErr)
+				}
+			}
+			removed = append(removed, p)
+		}
+		return filepath.SkipDir
+	})
+	return removed, err
+}
+
+func isSandboxDir(dir string) bool {
+	for _, m := range sandboxDirMarkers {
+		if _, err := os.Lstat(filepath.Join(dir, m)); err == nil {
+			return true
+		}
+	}
+	return false
+}
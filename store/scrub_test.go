@@ -0,0 +1,35 @@
+// ABOUTME: Tests for ScrubTranscripts over a displaced tree of sandbox dirs.
+
+package store
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScrubTranscripts_FindsNestedSandboxDirs(t *testing.T) {
+	root := t.TempDir()
+	for _, f := range []string{"sandboxes/a/environment.json", "sandboxes/a/resume-prompt.txt", "sandboxes/b/runtime-config.json", "sandboxes/b/logs/cli.jsonl", "notes/logs/keep.txt"} {
+		p := filepath.Join(root, f)
+		require.NoError(t, os.MkdirAll(filepath.Dir(p), 0o750))
+		require.NoError(t, os.WriteFile(p, []byte("x"), 0o600))
+	}
+
+	removed, err := ScrubTranscripts(root, false)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{
+		filepath.Join(root, "sandboxes/a/resume-prompt.txt"),
+		filepath.Join(root, "sandboxes/b/logs"),
+	}, removed)
+	assert.NoFileExists(t, filepath.Join(root, "sandboxes/a/resume-prompt.txt"))
+	assert.FileExists(t, filepath.Join(root, "sandboxes/a/environment.json"))
+	assert.NoDirExists(t, filepath.Join(root, "sandboxes/b/logs"))
+
+	removed, err = ScrubTranscripts(root, false)
+	require.NoError(t, err)
+	assert.Empty(t, removed, "a second scrub finds nothing")
+}
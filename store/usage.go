@@ -0,0 +1,90 @@
+// ABOUTME: Per-sandbox disk usage, split into work copies, agent state, logs and
+// ABOUTME: the rest; what `yoloai du` reports and resources.disk is checked against.
+
+package store
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// SandboxUsage is the on-disk size of one sandbox directory, in bytes.
+type SandboxUsage struct {
+	Work       int64 // work/ — the copy-mode work copies, followed when --work-root put them elsewhere
+	AgentState int64 // agent-runtime/ — the agent's own state, its session transcripts included
+	Logs       int64 // logs/
+	Other      int64 // everything else: metadata, seeded home files, scripts
+}
+
+// Total is the sum of every part.
+func (u SandboxUsage) Total() int64 {
+	return u.Work + u.AgentState + u.Logs + u.Other
+}
+
+// MeasureSandbox sums the sizes of the files under sandboxDir. Symlinks are
+// not followed, except that a work/ symlink (a --work-root sandbox) is
+// measured at its target, since those work copies belong to the sandbox too.
+// Files that vanish mid-walk are skipped: a running agent writes as we read.
+func MeasureSandbox(sandboxDir string) (SandboxUsage, error) {
+	if _, err := os.Stat(sandboxDir); err != nil {
+		return SandboxUsage{}, err
+	}
+	var u SandboxUsage
+	entries, err := os.ReadDir(sandboxDir)
+	if err != nil {
+		return u, err
+	}
+	for _, e := range entries {
+		path := filepath.Join(sandboxDir, e.Name())
+		var size int64
+		switch e.Name() {
+		case "work":
+			if target, linkErr := filepath.EvalSymlinks(path); linkErr == nil {
+				path = target
+			}
+			size, err = treeSize(path)
+			u.Work += size
+		case AgentRuntimeDir:
+			size, err = treeSize(path)
+			u.AgentState += size
+		case LogsDir:
+			size, err = treeSize(path)
+			u.Logs += size
+		default:
+			size, err = treeSize(path)
+			u.Other += size
+		}
+		if err != nil {
+			return u, err
+		}
+	}
+	return u, nil
+}
+
+// treeSize sums the sizes of the regular files under path (path itself when
+// it is a file). It does not follow symlinks.
+func treeSize(path string) (int64, error) {
+	var size int64
+	err := filepath.WalkDir(path, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		size += info.Size()
+		return nil
+	})
+	return size, err
+}
@@ -0,0 +1,52 @@
+// ABOUTME: Tests for MeasureSandbox's split of a sandbox dir into work copies,
+// ABOUTME: agent state, logs and the rest, including a --work-root symlink.
+
+package store
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeSized(t *testing.T, path string, n int) {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o750))
+	require.NoError(t, os.WriteFile(path, []byte(strings.Repeat("x", n)), 0o600))
+}
+
+func TestMeasureSandbox_SplitsByPart(t *testing.T) {
+	dir := t.TempDir()
+	writeSized(t, filepath.Join(dir, "work", "^2Fsrc", "main.go"), 100)
+	writeSized(t, filepath.Join(dir, AgentRuntimeDir, "session.jsonl"), 20)
+	writeSized(t, filepath.Join(dir, LogsDir, "agent.log"), 7)
+	writeSized(t, filepath.Join(dir, EnvironmentFile), 3)
+
+	u, err := MeasureSandbox(dir)
+	require.NoError(t, err)
+	assert.Equal(t, SandboxUsage{Work: 100, AgentState: 20, Logs: 7, Other: 3}, u)
+	assert.Equal(t, int64(130), u.Total())
+}
+
+func TestMeasureSandbox_FollowsWorkRootLink(t *testing.T) {
+	dir := t.TempDir()
+	root := t.TempDir()
+	require.NoError(t, LinkWorkRoot(dir, root, "sb", 0o750))
+	writeSized(t, filepath.Join(WorkRootDir(root, "sb"), "^2Fsrc", "main.go"), 50)
+	// Any other symlink is not followed.
+	require.NoError(t, os.Symlink(root, filepath.Join(dir, "elsewhere")))
+
+	u, err := MeasureSandbox(dir)
+	require.NoError(t, err)
+	assert.Equal(t, int64(50), u.Work)
+	assert.Equal(t, int64(0), u.Other)
+}
+
+func TestMeasureSandbox_Missing(t *testing.T) {
+	_, err := MeasureSandbox(filepath.Join(t.TempDir(), "gone"))
+	assert.True(t, os.IsNotExist(err))
+}
@@ -44,6 +44,7 @@ import (
 // acquire backend-internal locks where applicable.
 type System struct {
 	layout config.Layout
+	logger *slog.Logger // ClientCreateOptions.Logger, for backend builds run from admin handles
 }
 
 // Config returns the configuration-management sub-handle.
@@ -68,7 +69,7 @@ func (s *System) Profiles() *ProfileAdmin {
 // return the backend-construction error when it is unavailable. Call Available
 // to probe first, or inspect the returned error.
 func (s *System) TartBases() *TartBaseAdmin {
-	return &TartBaseAdmin{layout: s.layout}
+	return &TartBaseAdmin{layout: s.layout, logger: s.logger}
 }
 
 // LayoutStatus is the verdict of a realm status check — see DataDirStatus.
@@ -213,8 +214,23 @@ type SystemInfo struct {
 // has sandbox state, inspecting each via its own backend. Returns the sandbox
 // infos plus the names of backends that have sandbox dirs but couldn't be
 // reached (e.g. their daemon is down) so callers can warn without failing.
+//
+// Each sandbox's inspection is bounded, so one that hangs is listed as
+// unavailable rather than stalling the listing. Callers that pick sandboxes
+// by status use AllSandboxesComplete instead.
 func (s *System) AllSandboxes(ctx context.Context) ([]*SandboxInfo, []BackendType, error) {
-	infos, unavailable, err := orchestrator.ListSandboxesMultiBackend(ctx, s.layout,
+	return s.allSandboxes(ctx, orchestrator.ListSandboxesMultiBackend)
+}
+
+// AllSandboxesComplete is AllSandboxes waiting for every inspection to finish,
+// however slow its backend is to answer, so a status it selects on is never
+// missing for a sandbox that was merely slow.
+func (s *System) AllSandboxesComplete(ctx context.Context) ([]*SandboxInfo, []BackendType, error) {
+	return s.allSandboxes(ctx, orchestrator.ListSandboxesMultiBackendComplete)
+}
+
+func (s *System) allSandboxes(ctx context.Context, list func(context.Context, config.Layout, func(context.Context, runtime.BackendType) (runtime.Backend, error)) ([]*orchestrator.Info, []string, error)) ([]*SandboxInfo, []BackendType, error) {
+	infos, unavailable, err := list(ctx, s.layout,
 		func(ctx context.Context, backend runtime.BackendType) (runtime.Backend, error) {
 			return runtime.New(ctx, backend, s.layout)
 		})
@@ -235,6 +251,98 @@ func (s *System) ValidateSandboxName(name string) error {
 	return store.ValidateName(name)
 }
 
+// RecoverSandbox rebuilds the metadata of a sandbox whose environment.json is
+// missing or unreadable, so it can be listed, diffed, applied, and destroyed
+// again instead of sitting as StatusBroken. The retained environment.json.prev
+// is used when it still matches the work copies on disk; otherwise a minimal
+// record is reconstructed from them, with backend naming the runtime it was
+// created on (the work dir does not record it). When that backend can read its
+// instance's labels back, they supply the yoloai version, the profile and which
+// copy is the workdir. A copy whose baseline is not on record gets the one its
+// history marks (see git.RecoverBaseline); a copy of a clean repo has no mark,
+// so its HEAD is taken and the agent's commits so far count as baseline. A
+// sandbox whose metadata already loads is rejected with *UsageError — there is
+// nothing to recover.
+func (s *System) RecoverSandbox(ctx context.Context, name string, backend BackendType) (*Environment, error) {
+	if err := store.ValidateName(name); err != nil {
+		return nil, err
+	}
+	dir := s.layout.SandboxDir(name)
+	if _, err := os.Stat(dir); err != nil {
+		return nil, fmt.Errorf("sandbox %q: %w", name, ErrSandboxNotFound)
+	}
+	if _, err := store.LoadEnvironment(dir); err == nil {
+		return nil, yoerrors.NewUsageError("sandbox %q has readable metadata; nothing to recover", name)
+	}
+	meta, prevErr := store.LoadPreviousEnvironment(dir)
+	if prevErr != nil {
+		slog.Debug("previous metadata unusable for recovery", "event", "sandbox.recover", "sandbox", name, "error", prevErr)
+		var err error
+		meta, err = store.ReconstructEnvironment(dir, name, backend, s.instanceLabels(ctx, name, backend))
+		if err != nil {
+			return nil, fmt.Errorf("recover sandbox %q: %w", name, err)
+		}
+	}
+	if err := s.recoverBaselines(ctx, dir, meta); err != nil {
+		return nil, fmt.Errorf("recover sandbox %q: %w", name, err)
+	}
+	if meta.Principal == "" {
+		meta.Principal = s.layout.Principal
+	}
+	if err := store.SaveEnvironment(dir, meta); err != nil {
+		return nil, fmt.Errorf("recover sandbox %q: %w", name, err)
+	}
+	slog.Info("sandbox metadata recovered", "event", "sandbox.recover", "sandbox", name, "dirs", len(meta.Dirs))
+	return environmentFromStore(meta), nil
+}
+
+// recoverBaselines fills in the baseline of each copy-mode dir in meta that
+// has none on record, from its work copy's history. Without one, diff and apply
+// refuse the copy and reset treats it as never set up, so a copy whose history
+// can't be read fails recovery rather than being saved half-usable.
+func (s *System) recoverBaselines(ctx context.Context, dir string, meta *store.Environment) error {
+	g := git.NewHost(s.layout)
+	for i := range meta.Dirs {
+		d := &meta.Dirs[i]
+		if d.Mode != store.DirModeCopy || d.BaselineSHA != "" {
+			continue
+		}
+		sha, marked, err := g.RecoverBaseline(ctx, store.WorkDir(dir, d.HostPath))
+		if err != nil {
+			return fmt.Errorf("find baseline of %s: %w", d.HostPath, err)
+		}
+		if !marked {
+			slog.Warn("no baseline commit in work copy; using HEAD", "event", "sandbox.recover", "dir", d.HostPath, "sha", sha)
+		}
+		d.BaselineSHA = sha
+		if d.InceptionSHA == "" {
+			d.InceptionSHA = sha
+		}
+	}
+	return nil
+}
+
+// instanceLabels returns the labels of sandbox name's instance on backend, or
+// nil when the backend is unavailable, can't read labels back, or has no such
+// instance — recovery then works from the sandbox dir alone.
+func (s *System) instanceLabels(ctx context.Context, name string, backend BackendType) map[string]string {
+	rt, err := runtime.New(ctx, backend, s.layout)
+	if err != nil {
+		return nil
+	}
+	defer rt.Close() //nolint:errcheck // best-effort cleanup
+	reader, ok := rt.(runtime.LabelReader)
+	if !ok {
+		return nil
+	}
+	labels, err := reader.InstanceLabels(ctx, store.InstanceName(s.layout.Principal, name))
+	if err != nil {
+		slog.Debug("instance labels unavailable for recovery", "event", "sandbox.recover", "sandbox", name, "error", err)
+		return nil
+	}
+	return labels
+}
+
 // Info returns the installation's paths and per-backend availability in one
 // call. It never returns an error today (per-backend probe failures are
 // captured in BackendInfo.Note); the error return is kept for forward
@@ -466,9 +574,9 @@ func (s *System) buildOne(ctx context.Context, backend BackendType, opts BuildIm
 	}
 	defer rt.Close() //nolint:errcheck // best-effort
 	if opts.Profile != "" {
-		return orchestrator.EnsureProfileImage(ctx, rt, s.layout, opts.Profile, opts.Secrets, out, slog.Default(), opts.Rebuild)
+		return orchestrator.EnsureProfileImage(ctx, rt, s.layout, opts.Profile, opts.Secrets, out, s.logger, opts.Rebuild)
 	}
-	return rt.Setup(ctx, s.layout, s.layout.ProfileDir("base"), out, slog.Default(), opts.Rebuild)
+	return rt.Setup(ctx, s.layout, s.layout.ProfileDir("base"), out, s.logger, opts.Rebuild)
 }
 
 // CheckPrerequisitesOptions configures System.Check.
@@ -678,6 +786,10 @@ type PruneItem struct {
 	// BytesReclaimed is the space freed by removing this item; 0 when the
 	// backend can't report it.
 	BytesReclaimed int64
+	// Detail says what an orphaned instance was, from the labels it was
+	// created with: the yoloai version, profile and workdir hash. Empty when
+	// the backend keeps no labels or the instance predates them.
+	Detail string
 }
 
 // TrashedSandbox is a sandbox directory Prune quarantined to the trash
@@ -754,6 +866,8 @@ func (s *System) Prune(ctx context.Context, opts SystemPruneOptions) (*PruneResu
 	// reconciled here against the sandbox registry.
 	result.RemovedItems = append(result.RemovedItems, s.reapOrphanInjectors(opts.DryRun, out)...)
 
+	result.RemovedItems = append(result.RemovedItems, s.pruneObjectStores(opts.DryRun, out)...)
+
 	tempItems, err := s.pruneTempFiles(opts.DryRun, out)
 	result.RemovedItems = append(result.RemovedItems, tempItems...)
 	if err != nil {
@@ -832,6 +946,7 @@ func (s *System) pruneBackend(ctx context.Context, backend BackendType, known []
 				BackendType: backend,
 				Kind:        PruneItemKind(item.Kind),
 				Name:        item.Name,
+				Detail:      item.Detail,
 			})
 		}
 	} else {
@@ -840,6 +955,7 @@ func (s *System) pruneBackend(ctx context.Context, backend BackendType, known []
 				BackendType: backend,
 				Kind:        PruneItemKind(item.Kind),
 				Name:        item.Name,
+				Detail:      item.Detail,
 			})
 		}
 	}
@@ -888,6 +1004,26 @@ func (s *System) pruneTempFiles(dryRun bool, out io.Writer) ([]PruneItem, error)
 	return items, nil
 }
 
+// pruneObjectStores removes the shared git object stores no work copy borrows
+// from any more. Runs after the sandbox-dir classifications so a store only a
+// just-deleted dir named goes with it. Best-effort like the lock sweep: a
+// sandbox that can't be read keeps every store, and that is a warning, not fatal.
+func (s *System) pruneObjectStores(dryRun bool, out io.Writer) []PruneItem {
+	removed, failed, err := orchestrator.PruneObjectStores(s.layout, dryRun, staleTempFileAge)
+	if err != nil {
+		fmt.Fprintf(out, "Warning: object store sweep failed: %v\n", err) //nolint:errcheck // best-effort progress
+		return nil
+	}
+	items := make([]PruneItem, 0, len(removed))
+	for _, path := range removed {
+		items = append(items, PruneItem{Kind: PruneKindObjectStore, Name: path})
+	}
+	for _, f := range failed {
+		fmt.Fprintf(out, "Warning: could not remove object store %s: %v (try 'sudo yoloai system prune')\n", f.Path, f.Err) //nolint:errcheck // best-effort progress
+	}
+	return items
+}
+
 // reapOrphanInjectors kills leaked `__inject` broker processes whose sandbox is
 // gone (DF71). The keep-set is every live sandbox's recorded injector PID; the
 // broker sweep reaps any running injector not in it. Best-effort: an enumeration
@@ -7,6 +7,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 
@@ -27,14 +28,16 @@ var ErrConfigKeyNotFound = errors.New("config key not found")
 //   - everything else → ~/.yoloai/defaults/config.yaml
 //
 // Set and Reset hide this routing from callers; embedders pass a
-// dotted key and the handle picks the right file.
+// dotted key and the handle picks the right file. Beneath both sit the
+// read-only system layers (ClientCreateOptions.SystemConfigDir and the org
+// config Pull caches), which Effective, Get and Origins take into account.
 type ConfigAdmin struct {
 	layout config.Layout
 }
 
 // Effective returns the merged effective configuration as formatted
-// YAML text (baked-in defaults overlaid with the user's
-// ~/.yoloai/config.yaml and ~/.yoloai/defaults/config.yaml). This is
+// YAML text (baked-in defaults overlaid with the system layers, then the
+// user's ~/.yoloai/config.yaml and ~/.yoloai/defaults/config.yaml). This is
 // what `yoloai config get` (no key) prints.
 //
 // The return is YAML rather than map[string]any because the formatted
@@ -66,6 +69,77 @@ func (a *ConfigAdmin) Get(_ context.Context, key string) (string, error) {
 	return value, nil
 }
 
+// ConfigOrigin is one effective config value and the layer that set it:
+// "default" for the baked-in default, else "org:<url>", "system:<path>" or
+// "user:<path>". A list several layers added to names each, joined by " + ".
+type ConfigOrigin struct {
+	Key    string `json:"key"`
+	Value  string `json:"value"` // lists and empty maps in YAML flow style
+	Origin string `json:"origin"`
+}
+
+// Origins returns every effective config value, sorted by key, with the layer
+// it came from. This is what `yoloai config get --origin` prints.
+func (a *ConfigAdmin) Origins(_ context.Context) ([]ConfigOrigin, error) {
+	origins, err := config.GetConfigOrigins(a.layout)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]ConfigOrigin, 0, len(origins))
+	for _, o := range origins {
+		out = append(out, ConfigOrigin(o))
+	}
+	return out, nil
+}
+
+// ConfigPullResult reports what Pull did.
+type ConfigPullResult struct {
+	URL     string `json:"url"`     // org_config_url; "" when not set
+	Path    string `json:"path"`    // where the org config is cached
+	Changed bool   `json:"changed"` // cache written anew, or removed with the URL unset
+}
+
+// Pull fetches the org-wide config named by org_config_url (from the user's
+// config.yaml or the system one) and caches it beneath the system config. A
+// document that fails to parse leaves the cached copy in place; with
+// org_config_url unset, the cache is removed.
+func (a *ConfigAdmin) Pull(ctx context.Context) (*ConfigPullResult, error) {
+	p, err := config.PullOrgConfig(ctx, a.layout, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &ConfigPullResult{URL: p.URL, Path: p.Path, Changed: p.Changed}, nil
+}
+
+// ConfigSchemaInfo names a config file a JSON Schema is published for.
+type ConfigSchemaInfo struct {
+	Name string `json:"name"` // "config", "defaults", "profile" or "system"
+	File string `json:"file"` // where the file lives, relative to the data dir
+	URL  string `json:"url"`  // where the schema is published
+}
+
+// Schemas lists the config files a JSON Schema is published for, in the order
+// `yoloai schema` shows them.
+func (a *ConfigAdmin) Schemas() []ConfigSchemaInfo {
+	var out []ConfigSchemaInfo
+	for _, s := range config.ConfigSchemas() {
+		out = append(out, ConfigSchemaInfo{Name: s.Name, File: s.File, URL: config.SchemaBaseURL + s.Name + ".json"})
+	}
+	return out
+}
+
+// Schema returns the JSON Schema of the named config file (see Schemas) as
+// indented JSON, for editors to validate and complete the file with. It is
+// generated from the structs the file is parsed into, and the loaders check
+// each file against it. An unknown name is a *UsageError.
+func (a *ConfigAdmin) Schema(_ context.Context, name string) ([]byte, error) {
+	s, err := config.ConfigSchema(name)
+	if err != nil {
+		return nil, err
+	}
+	return config.MarshalSchema(s)
+}
+
 // Set writes a configuration value. The dotted key picks the storage
 // layer (global vs profile defaults); the target file is created
 // with a sensible scaffold if it doesn't yet exist.
@@ -101,6 +175,64 @@ func (a *ConfigAdmin) Reset(_ context.Context, key string) error {
 	return config.DeleteConfigField(a.layout, key)
 }
 
+// ConfigExportResult reports what Export put in the bundle.
+type ConfigExportResult struct {
+	// Files are the bundled paths, relative to the data dir.
+	Files []string
+	// Omitted are the secrets left out: credential files whole, or single
+	// values of YAML files (Key is then the dotted key).
+	Omitted []ConfigBundleOmission
+}
+
+// ConfigBundleOmission is one secret Export left out of a bundle.
+type ConfigBundleOmission struct {
+	Path   string
+	Key    string
+	Reason string
+}
+
+// ConfigImportOptions configures Import.
+type ConfigImportOptions struct {
+	// Overwrite replaces files that exist with different content; without it
+	// any such file makes Import refuse before writing anything.
+	Overwrite bool
+}
+
+// ConfigImportResult reports what Import restored.
+type ConfigImportResult struct {
+	Written   []string // created or replaced
+	Unchanged []string // already identical
+}
+
+// Export writes a tar bundle of the user's setup to w — the global config, the
+// defaults dir (config.yaml, tmux.conf, ...), every profile and the
+// user-defined agents — for Import on another machine. Secrets never go in:
+// credential files are skipped, and secret values (a key named like
+// *_API_KEY or *_TOKEN, or a value shaped like an API key) are cut out of YAML
+// files; both are reported so the user can set them up again.
+func (a *ConfigAdmin) Export(_ context.Context, w io.Writer) (*ConfigExportResult, error) {
+	b, err := config.ExportBundle(a.layout, w)
+	if err != nil {
+		return nil, err
+	}
+	result := &ConfigExportResult{Files: b.Files}
+	for _, o := range b.Omitted {
+		result.Omitted = append(result.Omitted, ConfigBundleOmission(o))
+	}
+	return result, nil
+}
+
+// Import restores a bundle written by Export. Only paths an export produces
+// are accepted, everything is checked before anything is written, and files
+// the bundle doesn't mention are left alone.
+func (a *ConfigAdmin) Import(_ context.Context, r io.Reader, opts ConfigImportOptions) (*ConfigImportResult, error) {
+	b, err := config.ImportBundle(a.layout, r, opts.Overwrite)
+	if b == nil {
+		return nil, err
+	}
+	return &ConfigImportResult{Written: b.Written, Unchanged: b.Unchanged}, err
+}
+
 func configKeyNotFound(key string) error {
 	return fmt.Errorf("%w: %s (run `yoloai config get` to list available keys)", ErrConfigKeyNotFound, key)
 }
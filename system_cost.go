@@ -0,0 +1,63 @@
+// ABOUTME: System.SandboxCosts — per-sandbox agent token usage and estimated cost,
+// ABOUTME: read from the agents' own records; what `yoloai cost` reports.
+package yoloai
+
+import (
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/kstenerud/yoloai/internal/orchestrator"
+	"github.com/kstenerud/yoloai/store"
+)
+
+// TokenUsage is what a sandbox's agent has spent: tokens by kind, and their
+// cost in US dollars, estimated from list prices for Claude Code and Codex
+// (Estimated) or as aider reported it. Tokens on a model without a known
+// price are counted but left out of the cost; Unpriced names those models.
+type TokenUsage = orchestrator.TokenUsage
+
+// SandboxCost is one sandbox's token usage.
+type SandboxCost struct {
+	Name  string
+	Agent string
+	Usage TokenUsage
+	// Supported is false when the sandbox's agent keeps no usage record
+	// yoloai can read; Usage is then zero.
+	Supported bool
+	// Err is why the sandbox's usage could not be read.
+	Err error
+}
+
+// SandboxCosts reads the token usage of the named sandboxes, or of every
+// sandbox when names is empty, sorted by name. A sandbox whose usage can't be
+// read carries its error in Err rather than failing the call; an unknown name
+// is an error. Trashed sandboxes are not included.
+func (s *System) SandboxCosts(names ...string) ([]SandboxCost, error) {
+	if len(names) == 0 {
+		entries, err := os.ReadDir(s.layout.SandboxesDir())
+		if err != nil && !os.IsNotExist(err) {
+			return nil, fmt.Errorf("read sandboxes dir: %w", err)
+		}
+		for _, e := range entries {
+			if e.IsDir() {
+				names = append(names, e.Name())
+			}
+		}
+	} else {
+		names = append([]string(nil), names...) // sorted below; the caller's slice stays as it was
+		for _, name := range names {
+			if err := store.RequireSandboxDir(s.layout.SandboxDir(name)); err != nil {
+				return nil, fmt.Errorf("sandbox %q: %w", name, err)
+			}
+		}
+	}
+	sort.Strings(names)
+
+	out := make([]SandboxCost, 0, len(names))
+	for _, name := range names {
+		usage, agentType, supported, err := orchestrator.MeasureTokenUsage(s.layout, name)
+		out = append(out, SandboxCost{Name: name, Agent: agentType, Usage: usage, Supported: supported, Err: err})
+	}
+	return out, nil
+}
@@ -0,0 +1,48 @@
+// ABOUTME: Tests for System.SandboxCosts: usage read per agent, agents that keep no
+// ABOUTME: record marked unsupported, and unknown names refused.
+
+package yoloai
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kstenerud/yoloai/internal/orchestrator/agentcfg"
+	"github.com/kstenerud/yoloai/store"
+)
+
+func TestSandboxCosts_PerAgent(t *testing.T) {
+	c := newTestClient(t)
+	claude := c.layout.SandboxDir("claude-box")
+	transcript := filepath.Join(claude, store.AgentRuntimeDir, "projects", "-home-yoloai-src", "s.jsonl")
+	require.NoError(t, os.MkdirAll(filepath.Dir(transcript), 0o750))
+	require.NoError(t, os.WriteFile(transcript, []byte(
+		`{"type":"assistant","message":{"id":"m1","model":"claude-haiku-4-5-20251001","usage":{"input_tokens":1000000,"output_tokens":100000}}}`+"\n"), 0o600))
+	require.NoError(t, agentcfg.Save(claude, &agentcfg.AgentConfig{AgentType: "claude"}))
+
+	shell := c.layout.SandboxDir("shell-box")
+	require.NoError(t, os.MkdirAll(shell, 0o750))
+	require.NoError(t, agentcfg.Save(shell, &agentcfg.AgentConfig{AgentType: "shell"}))
+
+	rows, err := c.SandboxCosts()
+	require.NoError(t, err)
+	require.Len(t, rows, 2)
+	assert.Equal(t, "claude-box", rows[0].Name)
+	assert.Equal(t, "claude", rows[0].Agent)
+	assert.True(t, rows[0].Supported)
+	require.NoError(t, rows[0].Err)
+	assert.Equal(t, int64(1_100_000), rows[0].Usage.Total())
+	assert.InDelta(t, 1.5, rows[0].Usage.CostUSD, 1e-9) // $1/M in, $5/M out
+	assert.Equal(t, "shell-box", rows[1].Name)
+	assert.False(t, rows[1].Supported)
+}
+
+func TestSandboxCosts_UnknownName(t *testing.T) {
+	c := newTestClient(t)
+	_, err := c.SandboxCosts("nope")
+	assert.ErrorIs(t, err, ErrSandboxNotFound)
+}
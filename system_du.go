@@ -0,0 +1,69 @@
+// ABOUTME: System.SandboxDiskUsage — per-sandbox disk usage split into work
+// ABOUTME: copies, agent state, logs and the rest, against any resources.disk limit.
+package yoloai
+
+import (
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/kstenerud/yoloai/internal/config"
+	"github.com/kstenerud/yoloai/store"
+)
+
+// SandboxDiskUsage is one sandbox's on-disk footprint, in bytes. Measured on
+// the host, so a VM backend's in-guest work copy is not included.
+type SandboxDiskUsage struct {
+	Name       string
+	Work       int64 // copy-mode work copies, wherever --work-root put them
+	AgentState int64 // agent-runtime/: the agent's state and session transcripts
+	Logs       int64 // logs/, the agent's terminal output included
+	Other      int64 // metadata, seeded home files, scripts
+	Total      int64
+	// Limit is the sandbox's resources.disk limit; 0 when it has none.
+	Limit int64
+	// Err is why the sandbox could not be measured; the sizes are then 0.
+	Err error
+}
+
+// SandboxDiskUsage measures the named sandboxes, or every sandbox when names
+// is empty, sorted by name. A sandbox that can't be measured carries its error
+// in Err rather than failing the call; an unknown name is an error.
+func (s *System) SandboxDiskUsage(names ...string) ([]SandboxDiskUsage, error) {
+	if len(names) == 0 {
+		entries, err := os.ReadDir(s.layout.SandboxesDir())
+		if err != nil && !os.IsNotExist(err) {
+			return nil, fmt.Errorf("read sandboxes dir: %w", err)
+		}
+		for _, e := range entries {
+			if e.IsDir() {
+				names = append(names, e.Name())
+			}
+		}
+	} else {
+		names = append([]string(nil), names...) // sorted below; the caller's slice stays as it was
+		for _, name := range names {
+			if err := store.RequireSandboxDir(s.layout.SandboxDir(name)); err != nil {
+				return nil, fmt.Errorf("sandbox %q: %w", name, err)
+			}
+		}
+	}
+	sort.Strings(names)
+
+	out := make([]SandboxDiskUsage, 0, len(names))
+	for _, name := range names {
+		dir := s.layout.SandboxDir(name)
+		row := SandboxDiskUsage{Name: name}
+		if meta, err := store.LoadEnvironment(dir); err == nil && meta.Resources != nil {
+			row.Limit, _ = config.ParseDiskSize(meta.Resources.Disk) //nolint:errcheck // validated at create; bad means none
+		}
+		u, err := store.MeasureSandbox(dir)
+		if err != nil {
+			row.Err = err
+		} else {
+			row.Work, row.AgentState, row.Logs, row.Other, row.Total = u.Work, u.AgentState, u.Logs, u.Other, u.Total()
+		}
+		out = append(out, row)
+	}
+	return out, nil
+}
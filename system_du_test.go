@@ -0,0 +1,63 @@
+// ABOUTME: Tests for System.SandboxDiskUsage: per-sandbox breakdown and limits.
+
+package yoloai
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kstenerud/yoloai/internal/config"
+	"github.com/kstenerud/yoloai/store"
+)
+
+func TestSandboxDiskUsage_AllSandboxes(t *testing.T) {
+	c := newTestClient(t)
+	for f, n := range map[string]int{
+		"b/work/^2Fsrc/main.go":         40,
+		"b/logs/agent.log":              5,
+		"a/environment.json":            0,
+		"a/agent-runtime/session.jsonl": 9,
+	} {
+		p := filepath.Join(c.layout.SandboxesDir(), f)
+		require.NoError(t, os.MkdirAll(filepath.Dir(p), 0o750))
+		require.NoError(t, os.WriteFile(p, []byte(strings.Repeat("x", n)), 0o600))
+	}
+	// The per-sandbox lock file beside the dirs is not a sandbox.
+	require.NoError(t, os.WriteFile(c.layout.SandboxLockPath("a"), nil, 0o600))
+
+	rows, err := c.SandboxDiskUsage()
+	require.NoError(t, err)
+	require.Len(t, rows, 2)
+	assert.Equal(t, "a", rows[0].Name)
+	assert.Equal(t, int64(9), rows[0].AgentState)
+	assert.Equal(t, "b", rows[1].Name)
+	assert.Equal(t, int64(40), rows[1].Work)
+	assert.Equal(t, int64(5), rows[1].Logs)
+	assert.Equal(t, int64(45), rows[1].Total)
+}
+
+func TestSandboxDiskUsage_Limit(t *testing.T) {
+	c := newTestClient(t)
+	dir := c.layout.SandboxDir("capped")
+	require.NoError(t, os.MkdirAll(dir, 0o750))
+	require.NoError(t, store.SaveEnvironment(dir, &store.Environment{
+		Name:      "capped",
+		Resources: &config.ResourceLimits{Disk: "1k"},
+	}))
+
+	rows, err := c.SandboxDiskUsage("capped")
+	require.NoError(t, err)
+	require.Len(t, rows, 1)
+	assert.Equal(t, int64(1024), rows[0].Limit)
+}
+
+func TestSandboxDiskUsage_UnknownName(t *testing.T) {
+	c := newTestClient(t)
+	_, err := c.SandboxDiskUsage("nope")
+	assert.ErrorIs(t, err, ErrSandboxNotFound)
+}
@@ -0,0 +1,98 @@
+// ABOUTME: SandboxLister — AllSandboxes for long-running callers: keeps one runtime
+// ABOUTME: per backend open between listings, with the backend's inspect cache on.
+package yoloai
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/kstenerud/yoloai/internal/config"
+	"github.com/kstenerud/yoloai/internal/orchestrator"
+	"github.com/kstenerud/yoloai/runtime"
+)
+
+// listerInspectTTL is how long a SandboxLister's runtimes may answer Inspect
+// from their cache. The backend's change events drop an entry as soon as the
+// instance changes; the ttl only bounds how long a missed event could go
+// unnoticed.
+const listerInspectTTL = 10 * time.Second
+
+// SandboxLister lists sandboxes as AllSandboxes does, for a process that
+// lists over and over while it runs: a dashboard, a daemon, a status view.
+// Rather than opening a runtime per backend for every listing, it keeps each
+// one open and has it cache Inspect results where the backend can (docker and
+// podman, from their container event streams), so frequent listings don't
+// hammer the runtime. Get one from System.NewSandboxLister and Close it when
+// done.
+//
+// Safe for concurrent use by multiple goroutines.
+type SandboxLister struct {
+	layout config.Layout
+	ctx    context.Context // scopes the runtimes' event watches
+	cancel context.CancelFunc
+
+	mu     sync.Mutex
+	rts    map[BackendType]runtime.Backend
+	closed bool
+}
+
+// NewSandboxLister returns a SandboxLister. It opens nothing until the first
+// List.
+func (s *System) NewSandboxLister() *SandboxLister {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &SandboxLister{layout: s.layout, ctx: ctx, cancel: cancel, rts: map[BackendType]runtime.Backend{}}
+}
+
+// List enumerates sandboxes across every backend, exactly as AllSandboxes
+// does. A backend that can't be reached is retried on the next List.
+func (l *SandboxLister) List(ctx context.Context) ([]*SandboxInfo, []BackendType, error) {
+	infos, unavailable, err := orchestrator.ListSandboxesWithRuntimes(ctx, l.layout, l.runtimeFor)
+	if err != nil {
+		return nil, nil, err
+	}
+	unavailableNames := make([]BackendType, len(unavailable))
+	for i, name := range unavailable {
+		unavailableNames[i] = BackendType(name)
+	}
+	return sandboxInfosFromStatus(infos), unavailableNames, nil
+}
+
+// runtimeFor returns the open runtime for backend, opening it (and turning on
+// its inspect cache) the first time.
+func (l *SandboxLister) runtimeFor(ctx context.Context, backend runtime.BackendType) (runtime.Backend, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.closed {
+		return nil, errors.New("sandbox lister is closed")
+	}
+	if rt, ok := l.rts[backend]; ok {
+		return rt, nil
+	}
+	rt, err := runtime.New(ctx, backend, l.layout)
+	if err != nil {
+		return nil, err
+	}
+	runtime.CacheInspectsFor(l.ctx, rt, listerInspectTTL)
+	l.rts[backend] = rt
+	return rt, nil
+}
+
+// Close closes the runtimes the lister opened. A List after Close reports
+// every backend unavailable.
+func (l *SandboxLister) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.closed {
+		return nil
+	}
+	l.closed = true
+	l.cancel()
+	var errs []error
+	for _, rt := range l.rts {
+		errs = append(errs, rt.Close())
+	}
+	clear(l.rts)
+	return errors.Join(errs...)
+}
@@ -0,0 +1,94 @@
+// ABOUTME: System.Scrub — the retention policy for prompts, logs and transcripts
+// ABOUTME: left in the trash dir by quarantined and migrated sandboxes.
+package yoloai
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/kstenerud/yoloai/internal/config"
+	"github.com/kstenerud/yoloai/store"
+)
+
+// SystemScrubOptions configures System.Scrub.
+type SystemScrubOptions struct {
+	// RetentionDays overrides the retention_days config key; nil uses the
+	// key. 0 here scrubs every trash entry regardless of age, where 0 in the
+	// config means keep forever.
+	RetentionDays *int
+	// DryRun reports what would be removed without removing it.
+	DryRun bool
+}
+
+// ScrubResult is what System.Scrub returns.
+type ScrubResult struct {
+	// RetentionDays is the retention applied; -1 when none is configured and
+	// nothing was scrubbed.
+	RetentionDays int
+	Entries       []ScrubbedEntry
+}
+
+// ScrubbedEntry is one trash entry Scrub removed (or, under DryRun, would
+// remove) prompts, logs and transcripts from.
+type ScrubbedEntry struct {
+	Path      string    // the trash entry
+	TrashedAt time.Time // when it was trashed (its mtime)
+	Removed   []string  // the paths removed under it
+}
+
+// Scrub applies the retention policy: every trash entry older than the
+// retention loses its sandboxes' prompts, logs and agent state (see
+// store.ScrubbedPaths). Work copies and metadata stay, so the entry is still
+// recoverable as code. Live sandboxes are never touched, and a destroyed
+// sandbox leaves nothing behind to scrub. Like Prune, it never prompts.
+func (s *System) Scrub(opts SystemScrubOptions) (*ScrubResult, error) {
+	days := -1
+	if opts.RetentionDays != nil {
+		days = *opts.RetentionDays
+	} else {
+		gcfg, err := config.LoadGlobalConfig(s.layout)
+		if err != nil {
+			return nil, err
+		}
+		if gcfg.RetentionDays > 0 {
+			days = gcfg.RetentionDays
+		}
+	}
+	result := &ScrubResult{RetentionDays: days}
+	if days < 0 {
+		return result, nil
+	}
+
+	dir := s.layout.TrashDir()
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return result, nil
+		}
+		return nil, fmt.Errorf("read trash dir: %w", err)
+	}
+	cutoff := time.Now().Add(-time.Duration(days) * 24 * time.Hour)
+	var errs error
+	for _, entry := range entries {
+		info, err := entry.Info()
+		if err != nil || info.ModTime().After(cutoff) {
+			continue
+		}
+		path := filepath.Join(dir, entry.Name())
+		removed, err := store.ScrubTranscripts(path, opts.DryRun)
+		if err != nil {
+			errs = errors.Join(errs, err)
+		}
+		if len(removed) > 0 {
+			if !opts.DryRun {
+				// Keep the entry aged by when it was trashed, not by the scrub.
+				_ = os.Chtimes(path, info.ModTime(), info.ModTime()) //nolint:errcheck // best-effort
+			}
+			result.Entries = append(result.Entries, ScrubbedEntry{Path: path, TrashedAt: info.ModTime(), Removed: removed})
+		}
+	}
+	return result, errs
+}
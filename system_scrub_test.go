@@ -0,0 +1,79 @@
+// ABOUTME: Tests for System.Scrub: the retention policy over trash entries.
+
+package yoloai
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// trashSandbox makes a trash entry shaped like a quarantined sandbox, aged by
+// age, and returns its path.
+func trashSandbox(t *testing.T, c *System, name string, age time.Duration) string {
+	t.Helper()
+	dir := filepath.Join(c.layout.TrashDir(), name)
+	for _, f := range []string{"environment.json", "prompt.txt", "logs/agent.log", "agent-runtime/session.jsonl", "work/^2Fsrc/logs/app.log"} {
+		p := filepath.Join(dir, f)
+		require.NoError(t, os.MkdirAll(filepath.Dir(p), 0o750))
+		require.NoError(t, os.WriteFile(p, []byte("x"), 0o600))
+	}
+	then := time.Now().Add(-age)
+	require.NoError(t, os.Chtimes(dir, then, then))
+	return dir
+}
+
+func TestScrub_RetentionFromConfig(t *testing.T) {
+	c := newTestClient(t)
+	require.NoError(t, os.WriteFile(c.layout.GlobalConfigPath(), []byte("retention_days: 30\n"), 0o600))
+	old := trashSandbox(t, c, "old", 40*24*time.Hour)
+	recent := trashSandbox(t, c, "recent", 24*time.Hour)
+
+	result, err := c.Scrub(SystemScrubOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, 30, result.RetentionDays)
+	require.Len(t, result.Entries, 1)
+	assert.Equal(t, old, result.Entries[0].Path)
+	assert.Len(t, result.Entries[0].Removed, 3)
+
+	assert.NoFileExists(t, filepath.Join(old, "prompt.txt"))
+	assert.NoDirExists(t, filepath.Join(old, "logs"))
+	assert.NoDirExists(t, filepath.Join(old, "agent-runtime"))
+	assert.FileExists(t, filepath.Join(old, "environment.json"), "metadata stays")
+	assert.FileExists(t, filepath.Join(old, "work/^2Fsrc/logs/app.log"), "a project's own logs in the work copy stay")
+	assert.FileExists(t, filepath.Join(recent, "prompt.txt"), "entries younger than the retention stay whole")
+
+	info, err := os.Stat(old)
+	require.NoError(t, err)
+	assert.WithinDuration(t, time.Now().Add(-40*24*time.Hour), info.ModTime(), time.Minute, "the entry keeps its trashed-at age")
+}
+
+func TestScrub_NoRetentionConfigured(t *testing.T) {
+	c := newTestClient(t)
+	old := trashSandbox(t, c, "old", 400*24*time.Hour)
+
+	result, err := c.Scrub(SystemScrubOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, -1, result.RetentionDays)
+	assert.Empty(t, result.Entries)
+	assert.FileExists(t, filepath.Join(old, "prompt.txt"))
+}
+
+func TestScrub_OverrideAndDryRun(t *testing.T) {
+	c := newTestClient(t)
+	recent := trashSandbox(t, c, "recent", time.Hour)
+	zero := 0
+
+	result, err := c.Scrub(SystemScrubOptions{RetentionDays: &zero, DryRun: true})
+	require.NoError(t, err)
+	require.Len(t, result.Entries, 1, "--days 0 takes every entry")
+	assert.FileExists(t, filepath.Join(recent, "prompt.txt"), "a dry run removes nothing")
+
+	_, err = c.Scrub(SystemScrubOptions{RetentionDays: &zero})
+	require.NoError(t, err)
+	assert.NoFileExists(t, filepath.Join(recent, "prompt.txt"))
+}
@@ -10,10 +10,13 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/kstenerud/yoloai/internal/config"
 	"github.com/kstenerud/yoloai/internal/envsetup"
+	"github.com/kstenerud/yoloai/internal/testutil"
 	"github.com/kstenerud/yoloai/runtime"
+	"github.com/kstenerud/yoloai/store"
 	"github.com/kstenerud/yoloai/yoerrors"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -82,6 +85,30 @@ func TestSystem_ListAcrossBackends_Empty(t *testing.T) {
 	assert.Empty(t, unavailable)
 }
 
+// TestSandboxLister_ListsLikeAllSandboxes checks the lister agrees with
+// AllSandboxes on a fresh install, and that once closed it opens no runtime:
+// a sandbox's backend is reported unavailable instead.
+func TestSandboxLister_ListsLikeAllSandboxes(t *testing.T) {
+	c := newTestClient(t)
+	l := c.NewSandboxLister()
+	infos, unavailable, err := l.List(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, infos)
+	assert.Empty(t, unavailable)
+
+	require.NoError(t, l.Close())
+	require.NoError(t, l.Close(), "Close is idempotent")
+	dir := mkSandboxDir(t, c, "box")
+	require.NoError(t, store.SaveEnvironment(dir, &store.Environment{
+		Name: "box", Principal: c.layout.Principal, BackendType: BackendDocker, CreatedAt: time.Now(),
+	}))
+	infos, unavailable, err = l.List(context.Background())
+	require.NoError(t, err)
+	require.Len(t, infos, 1)
+	assert.Equal(t, StatusUnavailable, infos[0].Status)
+	assert.Equal(t, []BackendType{BackendDocker}, unavailable)
+}
+
 // TestSystem_Doctor verifies every registered backend produces at least
 // one report row (base-mode or init-failure), and that a non-matching backend
 // filter yields nothing.
@@ -230,6 +257,41 @@ func TestPrune_ExecutesClassifications(t *testing.T) {
 	assert.Equal(t, 1, res.TrashContents.Count, "trash summary reflects the quarantined dir")
 }
 
+// TestRecoverSandbox_ReconstructsFromWorkCopies verifies a sandbox with no
+// usable metadata gets a record rebuilt from its work copies, baseline
+// included, after which it loads normally — and that a healthy sandbox is
+// refused.
+func TestRecoverSandbox_ReconstructsFromWorkCopies(t *testing.T) {
+	c := newTestClient(t)
+
+	lost := mkSandboxDir(t, c, "lost")
+	writeEnv(t, lost, `{torn`)
+	work := store.WorkDir(lost, "/src/app")
+	require.NoError(t, os.MkdirAll(work, 0o750))
+	testutil.InitGitRepo(t, work)
+	testutil.WriteFile(t, work, "main.go", "package main\n")
+	testutil.GitAdd(t, work, ".")
+	testutil.GitCommit(t, work, "yoloai: pre-session state")
+	baseline := testutil.GitRevParse(t, work)
+	testutil.WriteFile(t, work, "main.go", "package main // agent\n")
+	testutil.GitAdd(t, work, ".")
+	testutil.GitCommit(t, work, "agent work")
+
+	env, err := c.RecoverSandbox(context.Background(), "lost", BackendType("docker"))
+	require.NoError(t, err)
+	assert.Equal(t, "/src/app", env.Workdir().HostPath)
+	assert.Equal(t, DirModeCopy, env.Workdir().Mode)
+
+	loaded, err := store.LoadEnvironment(lost)
+	require.NoError(t, err)
+	assert.Equal(t, "lost", loaded.Name)
+	assert.Equal(t, baseline, loaded.Workdir().BaselineSHA, "the agent's commit stays in the diff")
+
+	_, err = c.RecoverSandbox(context.Background(), "lost", BackendType("docker"))
+	var usageErr *UsageError
+	assert.ErrorAs(t, err, &usageErr, "readable metadata has nothing to recover")
+}
+
 // TestEmptyTrash_RemovesAll verifies EmptyTrash deletes all trash entries.
 func TestEmptyTrash_RemovesAll(t *testing.T) {
 	c := newTestClient(t)
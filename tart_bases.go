@@ -1,11 +1,12 @@
 // ABOUTME: Public TartBases admin handle for managing Apple simulator runtime
-// ABOUTME: base images (iOS/tvOS/watchOS/visionOS) on the Tart backend.
+// ABOUTME: base images (iOS/tvOS/watchOS/visionOS) and the guest base VM on the Tart backend.
 package yoloai
 
 import (
 	"context"
 	"fmt"
 	"io"
+	"log/slog"
 	"strings"
 
 	"github.com/kstenerud/yoloai/internal/config"
@@ -62,6 +63,7 @@ func (e *TartBaseNotFoundError) Error() string {
 // appropriate is the caller's policy (development-principles.md §2).
 type TartBaseAdmin struct {
 	layout config.Layout
+	logger *slog.Logger
 }
 
 // Available reports whether the Tart backend can be constructed in this
@@ -180,6 +182,94 @@ func (a *TartBaseAdmin) Remove(ctx context.Context, name string) (freed int64, e
 	return size, nil
 }
 
+// TartGuestImage describes the provisioned guest base VM (yoloai-base) that
+// every Tart sandbox is cloned from. The guest is macOS: the Tart backend has
+// no Linux guest image, Linux sandboxes run on the container backends.
+type TartGuestImage struct {
+	Name        string            // local VM name
+	Exists      bool              // the VM is present
+	SourceImage string            // upstream image it is built from (tart.image or host-matched)
+	Checksum    string            // provision checksum for the current yoloai build and config
+	Info        map[string]string // build info recorded by the last build (yoloai_version, built_at, …)
+	// Problem is empty when the image exists, is current, and its build record
+	// is consistent (and, from VerifyGuestImage, the VM itself checks out);
+	// otherwise it names the first problem found.
+	Problem string
+}
+
+// GuestImage reports the state of the guest base VM from its host-side build
+// record, without building or booting it. VerifyGuestImage checks the VM.
+func (a *TartBaseAdmin) GuestImage(ctx context.Context) (TartGuestImage, error) {
+	r, closeRT, err := a.open(ctx)
+	if err != nil {
+		return TartGuestImage{}, err
+	}
+	defer closeRT()
+	return a.guestImage(ctx, r)
+}
+
+// BuildGuestImage provisions the guest base VM when it is missing or stale, or
+// unconditionally when force is set, then reports its state. The new image is
+// verified before it replaces the old one. Build progress is written to
+// progress; pass nil to discard it.
+func (a *TartBaseAdmin) BuildGuestImage(ctx context.Context, force bool, progress io.Writer) (TartGuestImage, error) {
+	if progress == nil {
+		progress = io.Discard
+	}
+	r, closeRT, err := a.open(ctx)
+	if err != nil {
+		return TartGuestImage{}, err
+	}
+	defer closeRT()
+	if err := r.Setup(ctx, a.layout, a.layout.ProfileDir("base"), progress, a.logger, force); err != nil {
+		return TartGuestImage{}, err
+	}
+	return a.guestImage(ctx, r)
+}
+
+// VerifyGuestImage reports the state of the guest base VM like GuestImage and,
+// when its build record is sound, also checks the VM itself: a throwaway clone
+// is booted, its agent tooling verified, and the build imprint inside it
+// compared with the host's record. The clone needs a free VM slot (Apple allows
+// two running macOS VMs). Progress is written to progress; pass nil to discard
+// it.
+func (a *TartBaseAdmin) VerifyGuestImage(ctx context.Context, progress io.Writer) (TartGuestImage, error) {
+	if progress == nil {
+		progress = io.Discard
+	}
+	r, closeRT, err := a.open(ctx)
+	if err != nil {
+		return TartGuestImage{}, err
+	}
+	defer closeRT()
+	img, err := a.guestImage(ctx, r)
+	if err != nil || img.Problem != "" {
+		return img, err
+	}
+	if err := r.VerifyBaseImage(ctx, progress); err != nil {
+		img.Problem = err.Error()
+	}
+	return img, nil
+}
+
+func (a *TartBaseAdmin) guestImage(ctx context.Context, r *tartrt.Runtime) (TartGuestImage, error) {
+	st, err := r.BaseImageStatus(ctx, a.layout.ProfileDir("base"))
+	if err != nil {
+		return TartGuestImage{}, err
+	}
+	img := TartGuestImage{
+		Name:        st.Name,
+		Exists:      st.Exists,
+		SourceImage: st.SourceImage,
+		Checksum:    st.Checksum,
+		Info:        st.Info,
+	}
+	if err := st.Verify(); err != nil {
+		img.Problem = err.Error()
+	}
+	return img, nil
+}
+
 // open constructs the Tart runtime and returns it with a close func.
 func (a *TartBaseAdmin) open(ctx context.Context) (*tartrt.Runtime, func(), error) {
 	r, err := runtime.New(ctx, runtime.BackendTart, a.layout)
@@ -76,15 +76,16 @@ const (
 type PruneItemKind string
 
 const (
-	PruneKindContainer  PruneItemKind = "container"   // docker / podman / containerd
-	PruneKindImage      PruneItemKind = "image"       // docker / podman / containerd
-	PruneKindVM         PruneItemKind = "vm"          // tart
-	PruneKindTempDir    PruneItemKind = "temp_dir"    // yoloai-side: stale ~/.yoloai temp dirs
-	PruneKindSandboxDir PruneItemKind = "sandbox_dir" // yoloai-side: never-initialized sandbox dir (no recoverable work)
-	PruneKindLockFile   PruneItemKind = "lock_file"   // yoloai-side: orphaned per-sandbox .lock file
-	PruneKindStaleBase  PruneItemKind = "stale_base"  // tart: superseded base image (prune --stale-bases)
-	PruneKindProcess    PruneItemKind = "process"     // yoloai-side: leaked host process (orphaned __inject broker)
-	PruneKindNetns      PruneItemKind = "netns"       // containerd: leaked network namespace with no owning sandbox
+	PruneKindContainer   PruneItemKind = "container"    // docker / podman / containerd
+	PruneKindImage       PruneItemKind = "image"        // docker / podman / containerd
+	PruneKindVM          PruneItemKind = "vm"           // tart
+	PruneKindTempDir     PruneItemKind = "temp_dir"     // yoloai-side: stale ~/.yoloai temp dirs
+	PruneKindSandboxDir  PruneItemKind = "sandbox_dir"  // yoloai-side: never-initialized sandbox dir (no recoverable work)
+	PruneKindLockFile    PruneItemKind = "lock_file"    // yoloai-side: orphaned per-sandbox .lock file
+	PruneKindStaleBase   PruneItemKind = "stale_base"   // tart: superseded base image (prune --stale-bases)
+	PruneKindProcess     PruneItemKind = "process"      // yoloai-side: leaked host process (orphaned __inject broker)
+	PruneKindNetns       PruneItemKind = "netns"        // containerd: leaked network namespace with no owning sandbox
+	PruneKindObjectStore PruneItemKind = "object_store" // yoloai-side: shared git object store no work copy borrows from
 )
 
 // LogSource names a structured-log stream emitted by one of yoloai's
@@ -102,6 +103,7 @@ const (
 	LogSourceSandbox LogSource = store.LogSourceSandbox // sandbox lifecycle events from the in-container entrypoint
 	LogSourceMonitor LogSource = store.LogSourceMonitor // agent idle/active detector emissions
 	LogSourceHooks   LogSource = store.LogSourceHooks   // hook-emitted events (Claude Code hooks; future agents too)
+	LogSourceRuntime LogSource = store.LogSourceRuntime // the container/VM runtime's log of the instance's output (opt-in)
 )
 
 // MountSpec describes a bind mount from the host filesystem into the
@@ -182,6 +184,17 @@ const (
 	NetworkModeIsolated NetworkMode = orchestrator.NetworkModeIsolated // allowlist only
 )
 
+// ContextMode names where a new sandbox's context (the description of the
+// sandbox given to the agent) is written. Closed set. Re-exported (type
+// alias) from internal/orchestrator.
+type ContextMode = orchestrator.ContextMode
+
+const (
+	ContextModeAgent    ContextMode = orchestrator.ContextModeAgent    // the agent's own instruction file (default)
+	ContextModeWorktree ContextMode = orchestrator.ContextModeWorktree // a file in the :copy work copy, hidden from diff
+	ContextModeNone     ContextMode = orchestrator.ContextModeNone     // no context for the agent
+)
+
 // Notice is a user-facing advisory message returned on an orchestration
 // result. Re-exported (type alias) from internal/orchestrator.
 type Notice = orchestrator.Notice
@@ -210,3 +223,28 @@ type StartResult = orchestrator.StartResult
 // ResetResult reports the outcome of a Reset — the advisory/status notices
 // emitted. Re-exported (type alias) from internal/orchestrator.
 type ResetResult = orchestrator.ResetResult
+
+// RebaseResult reports the outcome of Workdir.Rebase — the new and previous
+// baselines, how many commits were replayed, and whether uncommitted edits came
+// along. Re-exported (type alias) from internal/orchestrator.
+type RebaseResult = orchestrator.RebaseResult
+
+// AgentUpgradeResult reports the outcome of Agent.Upgrade — the versions before
+// and after, whether the agent was relaunched, and the notices emitted.
+// Re-exported (type alias) from internal/orchestrator.
+type AgentUpgradeResult = orchestrator.UpgradeResult
+
+// AgentCleanStateResult reports the outcome of Agent.CleanState — the files
+// removed, the space freed, and the state directory's size before and after.
+// Re-exported (type alias) from internal/orchestrator.
+type AgentCleanStateResult = orchestrator.CleanStateResult
+
+// PortsResult reports the outcome of Sandbox.UpdatePorts — the forwards in
+// place afterwards, the ones added and removed, whether the sandbox was
+// restarted, and the notices emitted. Re-exported (type alias) from
+// internal/orchestrator.
+type PortsResult = orchestrator.PortsResult
+
+// AttachedClient is a terminal attached to an agent's session, as reported by
+// Agent.AttachedClients.
+type AttachedClient = orchestrator.AttachedClient
@@ -7,10 +7,16 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
+	"strconv"
+	"time"
 
 	"github.com/kstenerud/yoloai/copyflow"
 	"github.com/kstenerud/yoloai/internal/orchestrator"
+	"github.com/kstenerud/yoloai/internal/orchestrator/changelog"
+	"github.com/kstenerud/yoloai/internal/orchestrator/hooks"
 	"github.com/kstenerud/yoloai/runtime"
+	"github.com/kstenerud/yoloai/store"
 	"github.com/kstenerud/yoloai/yoerrors"
 )
 
@@ -76,6 +82,113 @@ func (w *Workdir) Diff(ctx context.Context, opts WorkdirDiffOptions) (out string
 	return w.engine.GenerateWorkingDiff(ctx, w.name, w.dirHostPath, opts.Paths, opts.Stat, opts.NameOnly, opts.PathPrefix)
 }
 
+// WorkdirPatchOptions configures Workdir.Patch. The zero value is the patch of
+// the agent's commits.
+type WorkdirPatchOptions struct {
+	// Paths narrows the patch to specific files (relative to the workdir).
+	Paths []string
+	// IncludeUncommitted diffs the baseline against the live work copy, so the
+	// agent's uncommitted edits and new files are in the patch too.
+	IncludeUncommitted bool
+}
+
+// Patch returns the agent's changes as one binary patch against the baseline —
+// the patch `yoloai apply --no-commit` lands, for `git apply` or a review tool
+// instead. Empty means no changes. Copy-mode only: a :rw workdir's changes are
+// already in the directory.
+func (w *Workdir) Patch(ctx context.Context, opts WorkdirPatchOptions) (_ []byte, err error) {
+	defer func() { err = w.wrapNotRunning(err) }()
+	meta, err := w.engine.LoadEnvironment(w.name)
+	if err != nil {
+		return nil, err
+	}
+	dir := meta.Dir(w.dirHostPath)
+	if dir == nil {
+		return nil, yoerrors.NewUsageError("no tracked directory found")
+	}
+	if dir.Mode == store.DirModeRW {
+		return nil, yoerrors.NewUsageError("%s is mounted :rw — its changes are already in place, so there is no patch to make", dir.HostPath)
+	}
+	return w.engine.GeneratePatch(ctx, w.name, w.dirHostPath, opts.Paths, opts.IncludeUncommitted)
+}
+
+// WorkdirCompareOptions configures Workdir.Compare. The zero value is the full
+// binary diff.
+type WorkdirCompareOptions struct {
+	// Stat renders a `git diff --stat` summary instead of the full diff.
+	Stat bool
+}
+
+// Compare returns the diff from this work copy to other's — "" when both hold
+// the same files. It is meant for sandboxes given the same task on the same
+// source (`yoloai compare`): only files either agent changed since its
+// baseline are compared. Paths in the diff are prefixed with the sandbox
+// names. Both must be :copy work copies on this handle's backend, and both
+// sandboxes running.
+func (w *Workdir) Compare(ctx context.Context, other *Workdir, opts WorkdirCompareOptions) (string, error) {
+	if other == nil {
+		return "", yoerrors.NewUsageError("compare needs a second work copy")
+	}
+	out, err := w.engine.CompareWorkCopies(ctx, copyflow.CompareOptions{
+		A:    copyflow.CompareSide{Name: w.name, DirHostPath: w.dirHostPath},
+		B:    copyflow.CompareSide{Name: other.name, DirHostPath: other.dirHostPath},
+		Stat: opts.Stat,
+	})
+	if err != nil && errors.Is(err, runtime.ErrNotRunning) {
+		return "", fmt.Errorf("sandboxes %q and %q must both be running to compare their work copies: %w", w.name, other.name, err)
+	}
+	return out, err
+}
+
+// WorkdirVerifyOptions configures Workdir.Verify.
+type WorkdirVerifyOptions struct {
+	// Command is the shell command to run (required).
+	Command string
+	// Output receives the command's stdout and stderr; nil discards them.
+	Output io.Writer
+}
+
+// VerifyResult is the outcome of Workdir.Verify.
+type VerifyResult struct {
+	Command  string `json:"command"`
+	Passed   bool   `json:"passed"`
+	ExitCode int    `json:"exit_code"`
+}
+
+// VerifyCommand returns the verify key of the .yoloai.yaml in the host
+// directory this work copy was taken from, or "" when there is none. The host
+// file is read, not the work copy's, so the agent can't edit its own gate.
+func (w *Workdir) VerifyCommand() (string, error) {
+	return w.engine.ProjectVerifyCommand(w.name, w.dirHostPath)
+}
+
+// Verify runs a check — typically the project's tests — inside the sandbox,
+// in the work copy as it stands, uncommitted edits included. It is the gate
+// `yoloai apply --verify` puts in front of an apply. A command that runs and
+// exits non-zero is a result (Passed false), not an error. The sandbox must
+// be running.
+func (w *Workdir) Verify(ctx context.Context, opts WorkdirVerifyOptions) (*VerifyResult, error) {
+	if opts.Command == "" {
+		return nil, yoerrors.NewUsageError("verify needs a command to run")
+	}
+	out := opts.Output
+	if out == nil {
+		out = io.Discard
+	}
+	err := w.engine.RunInWorkCopy(ctx, w.name, w.dirHostPath, opts.Command, out)
+	var ee *runtime.ExecError
+	if errors.As(err, &ee) {
+		return &VerifyResult{Command: opts.Command, ExitCode: ee.ExitCode}, nil
+	}
+	if errors.Is(err, orchestrator.ErrContainerNotRunning) {
+		return nil, fmt.Errorf("sandbox %q must be running to verify its work copy — start it with 'yoloai start %s': %w", w.name, w.name, err)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &VerifyResult{Command: opts.Command, Passed: true}, nil
+}
+
 // FileChange is one file's line-count delta in a workdir diff. Additions and
 // Deletions are -1 for binary files.
 type FileChange struct {
@@ -95,7 +208,13 @@ type Changes struct {
 
 // Changes returns the structured change summary for the workdir. Like Diff, it
 // reflects copy/rw mode automatically.
-func (w *Workdir) Changes(ctx context.Context) (_ *Changes, err error) {
+func (w *Workdir) Changes(ctx context.Context) (*Changes, error) {
+	return w.ChangesIn(ctx, nil)
+}
+
+// ChangesIn is Changes limited to paths (workdir-relative pathspecs, as for
+// WorkdirDiffOptions.Paths); nil or empty means the whole workdir.
+func (w *Workdir) ChangesIn(ctx context.Context, paths []string) (_ *Changes, err error) {
 	defer func() { err = w.wrapNotRunning(err) }()
 	meta, err := w.engine.LoadEnvironment(w.name)
 	if err != nil {
@@ -107,7 +226,7 @@ func (w *Workdir) Changes(ctx context.Context) (_ *Changes, err error) {
 	}
 
 	var internal []copyflow.FileChange
-	internal, err = w.engine.GenerateWorkingChanges(ctx, w.name, w.dirHostPath, nil)
+	internal, err = w.engine.GenerateWorkingChanges(ctx, w.name, w.dirHostPath, paths)
 	if err != nil {
 		return nil, err
 	}
@@ -153,17 +272,21 @@ type WorkdirExportOptions struct {
 	// uncommitted.diff (copy-mode only). Mirrors `yoloai apply --patches
 	// --include-uncommitted`.
 	IncludeUncommitted bool
+	// NoProvenance skips the provenance headers a sandbox created with
+	// provenance_headers would otherwise stamp into new files.
+	NoProvenance bool
 }
 
 // toInternal maps the public WorkdirExportOptions onto copyflow.ExportOptions (IC7:
 // one internal counterpart, so a value→value method rather than inline mapping).
-func (o WorkdirExportOptions) toInternal(dirHostPath string) copyflow.ExportOptions {
+func (o WorkdirExportOptions) toInternal(dirHostPath string, prov *copyflow.Provenance) copyflow.ExportOptions {
 	return copyflow.ExportOptions{
 		Dir:                o.Dir,
 		Refs:               o.Refs,
 		Paths:              o.Paths,
 		IncludeUncommitted: o.IncludeUncommitted,
 		DirHostPath:        dirHostPath,
+		Provenance:         prov,
 	}
 }
 
@@ -182,7 +305,30 @@ func (w *Workdir) Export(ctx context.Context, opts WorkdirExportOptions) (*Expor
 	if opts.Dir == "" {
 		return nil, yoerrors.NewUsageError("export requires a destination directory: set WorkdirExportOptions.Dir")
 	}
-	return w.engine.ExportPatches(ctx, w.name, opts.toInternal(w.dirHostPath))
+	meta, err := w.engine.LoadEnvironment(w.name)
+	if err != nil {
+		return nil, err
+	}
+	return w.engine.ExportPatches(ctx, w.name, opts.toInternal(w.dirHostPath, w.provenance(meta, opts.NoProvenance)))
+}
+
+// provenance returns the header to stamp into files the agent created, or nil
+// when the sandbox wasn't created with provenance_headers. Opting out still
+// returns a Provenance (with Skip set) so edits to files an earlier apply
+// stamped keep landing past the header. The agent and model come from
+// agent.json; a missing one leaves them blank rather than failing the apply.
+func (w *Workdir) provenance(meta *store.Environment, optOut bool) *copyflow.Provenance {
+	if !meta.ProvenanceHeaders {
+		return nil
+	}
+	if optOut {
+		return &copyflow.Provenance{Skip: true}
+	}
+	p := &copyflow.Provenance{Tool: "an AI agent", Sandbox: w.name, Date: time.Now()}
+	if ac, err := w.engine.LoadAgentConfig(w.name); err == nil && ac.AgentType != "" {
+		p.Tool, p.Model = ac.AgentType, ac.Model
+	}
+	return p
 }
 
 // ApplyResult describes the outcome of an Apply: the host directory patched,
@@ -195,6 +341,29 @@ type ApplyResult = copyflow.ApplyResult
 // it. Re-exported (type alias) from internal/orchestrator/copyflow.
 type AppliedCommit = copyflow.AppliedCommit
 
+// FreshClone describes the clone a fresh-clone Apply landed its changes in:
+// the clone Dir, the origin Remote, and the BaseSHA applied onto — the
+// sandbox baseline when AtBaseline, else origin's default branch. Re-exported
+// (type alias) from internal/orchestrator/copyflow.
+type FreshClone = copyflow.FreshClone
+
+// PatchFile is one file's section of a net diff handed to
+// WorkdirApplyOptions.SelectHunks: its Header and its Hunks. A file with no
+// hunks (binary, rename-only, deletion) is taken or left whole. Re-exported
+// (type alias) from internal/orchestrator/copyflow.
+type PatchFile = copyflow.PatchFile
+
+// Hunk is one "@@" hunk of a PatchFile; its line counts are recomputed on
+// apply, so a caller may edit Lines. Re-exported (type alias) from
+// internal/orchestrator/copyflow.
+type Hunk = copyflow.Hunk
+
+// ApplyConflictError is returned by an ApplyModeNoCommit Apply whose net diff
+// doesn't apply cleanly to Dir; nothing was applied. Retry with
+// WorkdirApplyOptions.Reject to land what fits. Match it with errors.As.
+// Re-exported (type alias) from internal/orchestrator/copyflow.
+type ApplyConflictError = copyflow.ApplyConflictError
+
 // ApplyMode selects how Apply lands changes. Required — there is no default,
 // because the choice is consequential and mutually exclusive, and a movable
 // default would silently change behavior out from under callers (§4: empty
@@ -236,6 +405,67 @@ type WorkdirApplyOptions struct {
 	// returns the commits that would apply, ApplyModeNoCommit returns the stat.
 	// The library never prompts; the CLI uses this to render confirmation.
 	DryRun bool
+	// NoProvenance skips the provenance headers a sandbox created with
+	// provenance_headers would otherwise stamp into new files. Mirrors
+	// `yoloai apply --no-provenance`.
+	NoProvenance bool
+	// FreshClone, when set, clones the source repo's origin into this
+	// directory at the sandbox baseline and applies there instead of the
+	// original host directory, which is left alone; the baseline doesn't
+	// advance. Must not exist or be empty. Incompatible with DryRun. Mirrors
+	// `yoloai apply --fresh-clone`.
+	FreshClone string
+	// Target, when set, applies into this existing directory — another clone
+	// or a worktree of the same repository — instead of the original host
+	// directory, which is left alone; the baseline doesn't advance.
+	// ApplyModeCommits needs it to be a git repository. Incompatible with
+	// FreshClone and Branch. Mirrors `yoloai apply --target`.
+	Target string
+	// Branch, when set, creates this branch in the host repository and lands
+	// the changes on it instead of in the working tree: the commit series
+	// (ApplyModeCommits) or the net diff as one commit (ApplyModeNoCommit),
+	// with included uncommitted edits as a final commit. The user's checkout,
+	// its current branch and working tree are untouched; the branch must not
+	// exist. The baseline advances as for any apply. Incompatible with DryRun,
+	// FreshClone and PushBranch. Mirrors `yoloai apply --branch`.
+	Branch string
+	// PushBranch, when set, pushes the target's HEAD to this branch of its
+	// origin after the commits land, using the host's git credentials — how a
+	// sandbox created from a remote repository (SandboxCreateOptions.Repo)
+	// hands its work back. ApplyModeCommits only; skipped on DryRun. A push
+	// failure comes back alongside the result, as the commits did land.
+	// Mirrors `yoloai apply --push-branch`.
+	PushBranch string
+	// SelectHunks, when set, receives the net diff split into files and hunks
+	// and returns the subset (hunks may be edited) to apply; the baseline
+	// advances only if that's the whole diff unchanged. The library still
+	// never prompts — the callback is where a caller does. ApplyModeNoCommit
+	// only; incompatible with DryRun. Mirrors `yoloai apply --interactive`.
+	SelectHunks func([]PatchFile) ([]PatchFile, error)
+	// Reject handles a net diff that doesn't apply cleanly by applying the
+	// hunks that do and leaving the rest in .rej files beside their targets,
+	// listed in ApplyResult.Rejects, rather than failing with
+	// *ApplyConflictError. The baseline doesn't advance while any are left;
+	// once they are resolved the caller advances it. ApplyModeNoCommit only;
+	// incompatible with DryRun and Branch.
+	Reject bool
+	// Changelog, when set, writes a changelog fragment describing the change
+	// into the directory the changes landed in, untracked, for the user to
+	// commit with them; its path comes back in ApplyResult.ChangelogFragment.
+	// The fragments directory and format come from the changelog key of the
+	// host directory's .yoloai.yaml, else from the repository's towncrier
+	// configuration; with neither, Apply refuses before changing anything.
+	// Skipped on DryRun; incompatible with Branch and PushBranch, which leave
+	// the working tree alone. Mirrors `yoloai apply --changelog`.
+	Changelog *ApplyChangelog
+}
+
+// ApplyChangelog configures the changelog fragment Workdir.Apply writes.
+type ApplyChangelog struct {
+	// Type is the towncrier fragment type or Keep a Changelog section;
+	// "" = the project's configured type, else feature (towncrier) or
+	// Changed (keep-a-changelog).
+	Type string
 }
 
 // Apply lands the agent's changes back on the original host workdir, per
@@ -243,7 +473,7 @@ type WorkdirApplyOptions struct {
 // (preserving message/author), ApplyModeNoCommit applies the net diff unstaged.
 // Returns (nil, nil) when there's nothing to apply — branch on result == nil
 // rather than a sentinel error (Q-P). On success (and unless Paths filters the
-// apply) it advances the diff baseline.
+// apply or FreshClone or Target redirects it) it advances the diff baseline.
 //
 // Comply-or-complain (§2/§4): Mode is required — an unset mode is a *UsageError,
 // not a silent default. ApplyModeCommits refuses a non-git host target with a
@@ -253,6 +483,11 @@ type WorkdirApplyOptions struct {
 //
 // Mount mode is resolved internally (like Diff). An :overlay workdir must be
 // migrated before Apply can be used — run 'yoloai system migrate'.
+//
+// Unless DryRun, the user's pre-apply hooks run first (a failure stops the
+// apply), and once changes landed the changelog fragment (opts.Changelog) is
+// written and the post-apply hooks run, both in result.Dir; a failure of
+// either comes back alongside the result.
 func (w *Workdir) Apply(ctx context.Context, opts WorkdirApplyOptions) (_ *ApplyResult, err error) {
 	defer func() { err = w.wrapNotRunning(err) }()
 	if opts.Mode != ApplyModeCommits && opts.Mode != ApplyModeNoCommit {
@@ -263,25 +498,151 @@ func (w *Workdir) Apply(ctx context.Context, opts WorkdirApplyOptions) (_ *Apply
 	if err != nil {
 		return nil, err
 	}
-	if dir := meta.Dir(w.dirHostPath); dir == nil {
+	dir := meta.Dir(w.dirHostPath)
+	if dir == nil {
 		return nil, yoerrors.NewUsageError("no tracked directory found")
 	}
+	prov := w.provenance(meta, opts.NoProvenance)
+	if opts.DryRun {
+		return w.apply(ctx, opts, prov)
+	}
+	fragments, err := w.changelogConfig(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	target := dir.HostPath
+	if opts.Target != "" {
+		target = opts.Target
+	} else if opts.FreshClone != "" {
+		target = opts.FreshClone
+	}
+	pre := map[string]string{"YOLOAI_APPLY_TARGET": target, "YOLOAI_APPLY_MODE": string(opts.Mode), "YOLOAI_APPLY_BRANCH": opts.Branch}
+	if err := w.engine.RunApplyHooks(ctx, hooks.PreApply, w.name, w.dirHostPath, dir.HostPath, pre); err != nil {
+		return nil, err
+	}
+	result, err := w.apply(ctx, opts, prov)
+	if result == nil || len(result.Rejects) > 0 {
+		return result, err
+	}
+	if fragments != nil {
+		path, fragErr := fragments.Write(result.Dir, w.name, w.engine.ChangelogEntry(w.name, result.Commits), opts.Changelog.Type, time.Now())
+		if fragErr != nil {
+			// The changes landed: report the fragment's failure alongside the result.
+			return result, errors.Join(err, fmt.Errorf("write changelog fragment: %w", fragErr))
+		}
+		result.ChangelogFragment = path
+	}
+	post := map[string]string{
+		"YOLOAI_APPLY_TARGET":  result.Dir,
+		"YOLOAI_APPLY_MODE":    string(opts.Mode),
+		"YOLOAI_APPLY_BRANCH":  result.Branch,
+		"YOLOAI_APPLY_COMMITS": strconv.Itoa(len(result.Commits)),
+	}
+	if hookErr := w.engine.RunApplyHooks(ctx, hooks.PostApply, w.name, w.dirHostPath, result.Dir, post); hookErr != nil {
+		// The changes landed: report the hook's failure alongside the result.
+		return result, errors.Join(err, hookErr)
+	}
+	return result, err
+}
+
+// changelogConfig resolves where opts.Changelog's fragment goes, or returns
+// nil when none was asked for. It runs before anything is applied, so a
+// project without a fragments directory fails the apply cleanly.
+func (w *Workdir) changelogConfig(opts WorkdirApplyOptions) (*changelog.Config, error) {
+	if opts.Changelog == nil {
+		return nil, nil
+	}
+	if opts.Branch != "" || opts.PushBranch != "" {
+		return nil, yoerrors.NewUsageError("a changelog fragment is written into the working tree: it can't be combined with a branch or push-branch apply")
+	}
+	cfg, err := w.engine.ProjectChangelog(w.name, w.dirHostPath)
+	if err != nil {
+		return nil, err
+	}
+	if cfg == nil {
+		return nil, yoerrors.NewUsageError("no changelog fragments directory is configured: set changelog.dir in the project's .yoloai.yaml, or configure towncrier")
+	}
+	return cfg, nil
+}
 
+// apply dispatches Apply to the series or net-diff path per opts.Mode.
+func (w *Workdir) apply(ctx context.Context, opts WorkdirApplyOptions, prov *copyflow.Provenance) (*ApplyResult, error) {
 	if opts.Mode == ApplyModeCommits {
+		if opts.SelectHunks != nil {
+			return nil, yoerrors.NewUsageError("hunk selection applies a net diff: use ApplyModeNoCommit with SelectHunks")
+		}
+		if opts.Reject {
+			return nil, yoerrors.NewUsageError("leaving .rej files applies a net diff: use ApplyModeNoCommit with Reject")
+		}
 		return w.engine.ApplySeries(ctx, w.name, copyflow.ApplySeriesOptions{
 			Refs:               opts.Refs,
 			IncludeUncommitted: opts.IncludeUncommitted,
 			Paths:              opts.Paths,
 			DryRun:             opts.DryRun,
 			DirHostPath:        w.dirHostPath,
+			Provenance:         prov,
+			FreshClone:         opts.FreshClone,
+			Target:             opts.Target,
+			Branch:             opts.Branch,
+			PushBranch:         opts.PushBranch,
 		})
 	}
+	if opts.PushBranch != "" {
+		return nil, yoerrors.NewUsageError("pushing to a branch needs commits: use ApplyModeCommits with PushBranch")
+	}
 
 	return w.engine.ApplyAll(ctx, w.name, copyflow.ApplyAllOptions{
 		IncludeUncommitted: opts.IncludeUncommitted,
 		Paths:              opts.Paths,
 		DryRun:             opts.DryRun,
 		DirHostPath:        w.dirHostPath,
+		Provenance:         prov,
+		FreshClone:         opts.FreshClone,
+		Target:             opts.Target,
+		Branch:             opts.Branch,
+		SelectHunks:        opts.SelectHunks,
+		Reject:             opts.Reject,
+	})
+}
+
+// WorkdirPublishOptions configures Workdir.Publish.
+type WorkdirPublishOptions struct {
+	// Remote is where to push: a remote name of the host repository or a URL.
+	// "" is the host repository's origin.
+	Remote string
+	// Ref is the full ref to push to; "" is refs/yoloai/<sandbox name>.
+	Ref string
+}
+
+// PublishResult reports what Publish pushed: the remote URL and ref, how many
+// commits, the sandbox HEAD mirrored, and the SHA the ref now points at.
+// Re-exported (type alias) from internal/orchestrator/copyflow.
+type PublishResult = copyflow.PublishResult
+
+// Publish pushes the agent's beyond-baseline commits to a scratch ref of a
+// remote (refs/yoloai/<name> by default), as an off-machine backup that
+// teammates can fetch. The commits are replayed in a throwaway clone and
+// force-pushed, so the ref always mirrors the sandbox as it is now; the host
+// directory and the baseline are untouched and uncommitted work stays behind.
+// Returns (nil, nil) when there is nothing to publish.
+func (w *Workdir) Publish(ctx context.Context, opts WorkdirPublishOptions) (_ *PublishResult, err error) {
+	defer func() { err = w.wrapNotRunning(err) }()
+	return w.engine.Publish(ctx, w.name, copyflow.PublishOptions{
+		DirHostPath: w.dirHostPath,
+		Remote:      opts.Remote,
+		Ref:         opts.Ref,
+	})
+}
+
+// Unpublish deletes the ref a Publish with the same options pushed
+// (refs/yoloai/<name> of origin by default) from the remote. The sandbox and
+// the host checkout are untouched.
+func (w *Workdir) Unpublish(ctx context.Context, opts WorkdirPublishOptions) error {
+	return w.engine.Unpublish(ctx, w.name, copyflow.PublishOptions{
+		DirHostPath: w.dirHostPath,
+		Remote:      opts.Remote,
+		Ref:         opts.Ref,
 	})
 }
 
@@ -390,6 +751,17 @@ func (w *Workdir) BaselineLog(ctx context.Context) ([]BaselineLogEntry, error) {
 	return w.engine.BaselineLog(ctx, w.name, w.dirHostPath)
 }
 
+// Rebase moves the diff baseline to the host directory as it is now and replays
+// the agent's commits beyond the old baseline, then its uncommitted edits, on
+// top — catching a long-running sandbox up with the host without losing its
+// work, as Reset would. The replay is rehearsed on the host first, so a conflict
+// returns an error and changes nothing. The commits get new SHAs, and the running
+// agent is told. Copy-mode only (a *UsageError otherwise); the sandbox must be
+// running.
+func (w *Workdir) Rebase(ctx context.Context) (*RebaseResult, error) {
+	return w.engine.Rebase(ctx, orchestrator.RebaseOptions{Name: w.name, Dir: w.dirHostPath})
+}
+
 // TagInfo identifies a git tag in a sandbox's workdir (its Name and commit
 // SHA). Re-exported (type alias) from internal/orchestrator so embedders can hold
 // the tag-listing results without importing internal packages.
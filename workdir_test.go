@@ -46,3 +46,48 @@ func TestWorkdir_Apply_RequiresMode(t *testing.T) {
 	var ue *yoerrors.UsageError
 	require.ErrorAs(t, err, &ue, "unset apply mode must be a *UsageError")
 }
+
+// TestWorkdir_Patch_RefusesRW verifies a :rw workdir, whose changes are already
+// in place, has no patch and says so rather than failing deep in git.
+func TestWorkdir_Patch_RefusesRW(t *testing.T) {
+	sb := newSandboxHandle(t, &store.Environment{
+		Name: "box",
+		Dirs: []store.DirEnvironment{{HostPath: "/x", MountPath: "/x", Mode: store.DirModeRW}},
+	})
+	_, err := sb.Workdir().Patch(context.Background(), WorkdirPatchOptions{IncludeUncommitted: true})
+	var ue *yoerrors.UsageError
+	require.ErrorAs(t, err, &ue)
+}
+
+// TestWorkdir_Apply_ChangelogNeedsDirectory verifies a changelog fragment
+// asked for in a project that configures no fragments directory refuses the
+// apply up front, before anything lands, as it does next to a branch apply.
+func TestWorkdir_Apply_ChangelogNeedsDirectory(t *testing.T) {
+	host := t.TempDir()
+	sb := newSandboxHandle(t, &store.Environment{
+		Name: "box",
+		Dirs: []store.DirEnvironment{{HostPath: host, MountPath: host, Mode: store.DirModeCopy, BaselineSHA: "abc"}},
+	})
+	var ue *yoerrors.UsageError
+
+	_, err := sb.Workdir().Apply(context.Background(), WorkdirApplyOptions{Mode: ApplyModeCommits, Changelog: &ApplyChangelog{}})
+	require.ErrorAs(t, err, &ue)
+	require.Contains(t, err.Error(), "changelog.dir")
+
+	require.NoError(t, os.WriteFile(filepath.Join(host, ".yoloai.yaml"), []byte("changelog:\n  dir: changelog.d\n"), 0o600))
+	_, err = sb.Workdir().Apply(context.Background(), WorkdirApplyOptions{Mode: ApplyModeCommits, Branch: "b", Changelog: &ApplyChangelog{}})
+	require.ErrorAs(t, err, &ue)
+	require.Contains(t, err.Error(), "branch")
+}
+
+// TestWorkdir_Verify_RequiresCommand verifies Verify refuses to run nothing
+// rather than reporting an empty gate as passed.
+func TestWorkdir_Verify_RequiresCommand(t *testing.T) {
+	sb := newSandboxHandle(t, &store.Environment{
+		Name: "box",
+		Dirs: []store.DirEnvironment{{HostPath: "/x", MountPath: "/x", Mode: store.DirModeCopy}},
+	})
+	_, err := sb.Workdir().Verify(context.Background(), WorkdirVerifyOptions{})
+	var ue *yoerrors.UsageError
+	require.ErrorAs(t, err, &ue)
+}
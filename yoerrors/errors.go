@@ -42,6 +42,7 @@ const (
 
 	ExitMigrationRequired   = 13
 	ExitInconsistentDataDir = 14
+	ExitSandboxQuota        = 15
 )
 
 // ExitCoder is implemented by typed errors that map to a specific
@@ -304,6 +305,22 @@ func NewResourceLimitError(format string, args ...any) *ResourceLimitError {
 	return &ResourceLimitError{Err: fmt.Errorf(format, args...)}
 }
 
+// SandboxQuotaError indicates a create was refused because its workdir
+// already has as many sandboxes as max_sandboxes_per_workdir allows (exit code
+// 15). Sandboxes names them oldest first, so a caller
+// can offer to destroy the oldest and retry.
+type SandboxQuotaError struct {
+	Workdir   string
+	Limit     int
+	Sandboxes []string
+}
+
+func (e *SandboxQuotaError) Error() string {
+	return fmt.Sprintf("%s already has %d sandboxes (max_sandboxes_per_workdir is %d): %s",
+		e.Workdir, len(e.Sandboxes), e.Limit, strings.Join(e.Sandboxes, ", "))
+}
+func (e *SandboxQuotaError) ExitCode() int { return ExitSandboxQuota }
+
 // SandboxLockedError indicates a write operation couldn't acquire the
 // per-sandbox file lock within the brief retry window because another
 // holder is currently using it (exit code 9).
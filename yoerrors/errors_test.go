@@ -169,6 +169,7 @@ func TestExitCode_PerType(t *testing.T) {
 		{"sandbox-locked", &SandboxLockedError{Name: "s"}, ExitSandboxLocked},
 		{"disk-space", NewDiskSpaceError("op", syscall.ENOSPC), ExitDiskSpace},
 		{"resource-limit", NewResourceLimitError("x"), ExitResourceLimit},
+		{"sandbox-quota", &SandboxQuotaError{Workdir: "/w", Limit: 1, Sandboxes: []string{"a"}}, ExitSandboxQuota},
 	}
 	// Exit codes must be distinct — two errors sharing a code would make
 	// the status ambiguous for scripts branching on it.